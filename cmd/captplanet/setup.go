@@ -74,6 +74,14 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 	if err != nil {
 		return err
 	}
+	// the storage nodes only accept the order limits of the satellite
+	satellite, err := provider.IdentityConfig{
+		CertPath: setupCfg.HCIdentity.CertPath,
+		KeyPath:  setupCfg.HCIdentity.KeyPath,
+	}.Load()
+	if err != nil {
+		return err
+	}
 
 	for i := 0; i < len(runCfg.StorageNodes); i++ {
 		storagenodePath := filepath.Join(setupCfg.BasePath, fmt.Sprintf("f%d", i))
@@ -157,6 +165,7 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 		overrides[storagenode+"kademlia.bootstrap-addr"] = joinHostPort(
 			setupCfg.ListenHost, startingPort+1)
 		overrides[storagenode+"storage.path"] = filepath.Join(storagenodePath, "data")
+		overrides[storagenode+"storage.satellite-ids"] = satellite.ID.String()
 	}
 
 	return process.SaveConfig(runCmd.Flags(),
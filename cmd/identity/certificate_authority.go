@@ -4,13 +4,18 @@
 package main
 
 import (
+	"encoding/hex"
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
 
 	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/utils"
 )
 
 var (
@@ -30,6 +35,24 @@ var (
 		RunE:  cmdGetID,
 	}
 
+	signCACmd = &cobra.Command{
+		Use:   "sign",
+		Short: "Have a certificate authority signed by a certificate signing service",
+		Long: "Sends a certificate request for the certificate authority with an authorization token to the " +
+			"certificate signing service of a satellite, and replaces the certificate authority and the chain " +
+			"of the identity with the signed ones. Satellites may require signed identities for storing data.",
+		RunE: cmdSignCA,
+	}
+
+	signWalletCmd = &cobra.Command{
+		Use:   "sign-wallet WALLET",
+		Short: "Sign the payout wallet of a node with its certificate authority",
+		Long: "Prints the hex signature of the wallet by the key of the certificate authority, which proves to " +
+			"satellites that the owner of the node chose the wallet. Set it as the wallet signature of the node.",
+		Args: cobra.ExactArgs(1),
+		RunE: cmdSignWallet,
+	}
+
 	newCACfg struct {
 		CA provider.CASetupConfig
 	}
@@ -37,14 +60,29 @@ var (
 	getIDCfg struct {
 		CA provider.PeerCAConfig
 	}
+
+	signCACfg struct {
+		CA       provider.FullCAConfig
+		Identity provider.IdentitySetupConfig
+		Signer   string `help:"address of the certificate signing service" default:""`
+		Token    string `help:"authorization token issued by the operator of the signing service" default:""`
+	}
+
+	signWalletCfg struct {
+		CA provider.FullCAConfig
+	}
 )
 
 func init() {
 	rootCmd.AddCommand(caCmd)
 	caCmd.AddCommand(newCACmd)
 	caCmd.AddCommand(getIDCmd)
+	caCmd.AddCommand(signCACmd)
+	caCmd.AddCommand(signWalletCmd)
 	cfgstruct.Bind(newCACmd.Flags(), &newCACfg, cfgstruct.ConfDir(defaultConfDir))
 	cfgstruct.Bind(getIDCmd.Flags(), &getIDCfg, cfgstruct.ConfDir(defaultConfDir))
+	cfgstruct.Bind(signCACmd.Flags(), &signCACfg, cfgstruct.ConfDir(defaultConfDir))
+	cfgstruct.Bind(signWalletCmd.Flags(), &signWalletCfg, cfgstruct.ConfDir(defaultConfDir))
 }
 
 func cmdNewCA(cmd *cobra.Command, args []string) error {
@@ -61,3 +99,78 @@ func cmdGetID(cmd *cobra.Command, args []string) (err error) {
 	fmt.Println(p.ID.String())
 	return nil
 }
+
+func cmdSignCA(cmd *cobra.Command, args []string) (err error) {
+	if signCACfg.Signer == "" || signCACfg.Token == "" {
+		return provider.ErrSetup.New("--signer and --token are required")
+	}
+
+	ca, err := signCACfg.CA.Load()
+	if err != nil {
+		return err
+	}
+	ic := provider.IdentityConfig{
+		CertPath: signCACfg.Identity.CertPath,
+		KeyPath:  signCACfg.Identity.KeyPath,
+	}
+	fi, err := ic.Load()
+	if err != nil {
+		return err
+	}
+
+	csr, err := ca.CertificateRequest()
+	if err != nil {
+		return err
+	}
+
+	dialOpt, err := fi.DialOption()
+	if err != nil {
+		return err
+	}
+	conn, err := grpc.Dial(signCACfg.Signer, dialOpt)
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, conn.Close()) }()
+
+	resp, err := pb.NewCertificatesClient(conn).Sign(process.Ctx(cmd), &pb.SigningRequest{
+		AuthToken: signCACfg.Token,
+		Csr:       csr,
+	})
+	if err != nil {
+		return err
+	}
+	chain, err := provider.ParseCertChain(resp.GetChain())
+	if err != nil {
+		return err
+	}
+	if err := ca.SetSignedChain(chain); err != nil {
+		return err
+	}
+
+	if err := signCACfg.CA.Save(ca); err != nil {
+		return err
+	}
+	fi.CA, fi.RestChain = ca.Cert, ca.RestChain
+	if err := ic.Save(fi); err != nil {
+		return err
+	}
+
+	fmt.Printf("signed the certificate authority of %s\n", ca.ID)
+	return nil
+}
+
+func cmdSignWallet(cmd *cobra.Command, args []string) (err error) {
+	ca, err := signWalletCfg.CA.Load()
+	if err != nil {
+		return err
+	}
+
+	signature, err := overlay.SignWallet(ca.Key, args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(hex.EncodeToString(signature))
+	return nil
+}
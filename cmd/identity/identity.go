@@ -4,6 +4,9 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
 
 	"storj.io/storj/pkg/cfgstruct"
@@ -22,16 +25,32 @@ var (
 		RunE:  cmdNewID,
 	}
 
+	rotateIDCmd = &cobra.Command{
+		Use:   "rotate",
+		Short: "Replaces the leaf certificate of an identity, revoking the old one",
+		Long: "Creates a new leaf certificate and key signed by the identity's certificate authority. " +
+			"The new leaf carries the revocation of the old one, so peers reject the old leaf once they " +
+			"connected to the node with the new one. The old files are kept with an .old suffix.",
+		RunE: cmdRotateID,
+	}
+
 	newIDCfg struct {
 		CA       provider.FullCAConfig
 		Identity provider.IdentitySetupConfig
 	}
+
+	rotateIDCfg struct {
+		CA       provider.FullCAConfig
+		Identity provider.IdentitySetupConfig
+	}
 )
 
 func init() {
 	rootCmd.AddCommand(idCmd)
 	idCmd.AddCommand(newIDCmd)
+	idCmd.AddCommand(rotateIDCmd)
 	cfgstruct.Bind(newIDCmd.Flags(), &newIDCfg, cfgstruct.ConfDir(defaultConfDir))
+	cfgstruct.Bind(rotateIDCmd.Flags(), &rotateIDCfg, cfgstruct.ConfDir(defaultConfDir))
 }
 
 func cmdNewID(cmd *cobra.Command, args []string) (err error) {
@@ -47,3 +66,36 @@ func cmdNewID(cmd *cobra.Command, args []string) (err error) {
 	}
 	return provider.ErrSetup.New("identity file(s) exist: %s", s)
 }
+
+func cmdRotateID(cmd *cobra.Command, args []string) (err error) {
+	ca, err := rotateIDCfg.CA.Load()
+	if err != nil {
+		return err
+	}
+
+	ic := provider.IdentityConfig{
+		CertPath: rotateIDCfg.Identity.CertPath,
+		KeyPath:  rotateIDCfg.Identity.KeyPath,
+	}
+	old, err := ic.Load()
+	if err != nil {
+		return err
+	}
+
+	fi, err := ca.RotateIdentity(old)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range []string{ic.CertPath, ic.KeyPath} {
+		if err := os.Rename(path, path+".old"); err != nil {
+			return err
+		}
+	}
+	if err := ic.Save(fi); err != nil {
+		return err
+	}
+
+	fmt.Printf("rotated the leaf certificate of %s\n", fi.ID)
+	return nil
+}
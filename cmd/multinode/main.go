@@ -0,0 +1,136 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/multinode"
+	"storj.io/storj/pkg/process"
+)
+
+var (
+	rootCmd = &cobra.Command{
+		Use:   "multinode",
+		Short: "Dashboard of a fleet of storage nodes",
+	}
+	runCmd = &cobra.Command{
+		Use:   "run",
+		Short: "Serve the status of the managed nodes",
+		RunE:  cmdRun,
+	}
+	addCmd = &cobra.Command{
+		Use:   "add NAME URL TOKEN",
+		Short: "Manage a storage node",
+		Long: "Adds the storage node NAME, whose node API is served at URL, e.g. http://10.0.0.2:7778. " +
+			"TOKEN is the API token the node issued, found in the api-token file of its configuration.",
+		Args: cobra.ExactArgs(3),
+		RunE: cmdAdd,
+	}
+	removeCmd = &cobra.Command{
+		Use:   "remove NAME",
+		Short: "Stop managing a storage node",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdRemove,
+	}
+	listCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the managed storage nodes",
+		RunE:  cmdList,
+	}
+
+	runCfg multinode.Config
+	// nodesCfg configures the commands managing the list of nodes
+	nodesCfg struct {
+		NodesPath string `help:"path to the list of managed nodes and their API tokens" default:"$CONFDIR/nodes.json"`
+	}
+
+	defaultConfDir = "$HOME/.storj/multinode"
+)
+
+func init() {
+	rootCmd.AddCommand(runCmd)
+	rootCmd.AddCommand(addCmd)
+	rootCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(listCmd)
+	cfgstruct.Bind(runCmd.Flags(), &runCfg, cfgstruct.ConfDir(defaultConfDir))
+	for _, cmd := range []*cobra.Command{addCmd, removeCmd, listCmd} {
+		cfgstruct.Bind(cmd.Flags(), &nodesCfg, cfgstruct.ConfDir(defaultConfDir))
+	}
+}
+
+func cmdRun(cmd *cobra.Command, args []string) (err error) {
+	return runCfg.Run(process.Ctx(cmd))
+}
+
+func cmdAdd(cmd *cobra.Command, args []string) (err error) {
+	name, rawurl, token := args[0], args[1], args[2]
+	if u, err := url.Parse(rawurl); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("Invalid node API URL %q, expected e.g. http://10.0.0.2:7778", rawurl)
+	}
+
+	nodes, err := multinode.LoadNodes(nodesCfg.NodesPath)
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if node.Name == name {
+			return fmt.Errorf("Node %q already exists", name)
+		}
+	}
+	nodes = append(nodes, multinode.Node{Name: name, URL: rawurl, Token: token})
+
+	if err := os.MkdirAll(filepath.Dir(nodesCfg.NodesPath), 0700); err != nil {
+		return err
+	}
+	if err := multinode.SaveNodes(nodesCfg.NodesPath, nodes); err != nil {
+		return err
+	}
+	fmt.Printf("Added node %s\n", name)
+	return nil
+}
+
+func cmdRemove(cmd *cobra.Command, args []string) (err error) {
+	nodes, err := multinode.LoadNodes(nodesCfg.NodesPath)
+	if err != nil {
+		return err
+	}
+	for i, node := range nodes {
+		if node.Name == args[0] {
+			nodes = append(nodes[:i], nodes[i+1:]...)
+			if err := multinode.SaveNodes(nodesCfg.NodesPath, nodes); err != nil {
+				return err
+			}
+			fmt.Printf("Removed node %s\n", args[0])
+			return nil
+		}
+	}
+	return fmt.Errorf("No node named %q", args[0])
+}
+
+func cmdList(cmd *cobra.Command, args []string) (err error) {
+	nodes, err := multinode.LoadNodes(nodesCfg.NodesPath)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tURL")
+	for _, node := range nodes {
+		_, _ = fmt.Fprintf(w, "%s\t%s\n", node.Name, node.URL)
+	}
+	return w.Flush()
+}
+
+func main() {
+	runCmd.Flags().String("config",
+		filepath.Join(defaultConfDir, "config.yaml"), "path to configuration")
+	process.Exec(rootCmd)
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/certificates"
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	authorizeCmd = &cobra.Command{
+		Use:   "authorize COUNT",
+		Short: "Create tokens that authorize new nodes to have their identities signed",
+		Long: "Creates COUNT authorization tokens in the database of the certificate signing service and " +
+			"prints them. Each token can be exchanged once for a signature of a node's certificate authority.",
+		Args: cobra.ExactArgs(1),
+		RunE: cmdAuthorize,
+	}
+
+	authorizeCfg struct {
+		DatabaseURL string `help:"the database connection string of the authorization tokens, like certificates.database-url" default:"bolt://$CONFDIR/authorizations.db"`
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(authorizeCmd)
+	cfgstruct.Bind(authorizeCmd.Flags(), &authorizeCfg, cfgstruct.ConfDir(defaultConfDir))
+}
+
+func cmdAuthorize(cmd *cobra.Command, args []string) (err error) {
+	count, err := strconv.Atoi(args[0])
+	if err != nil || count < 1 {
+		return fmt.Errorf("invalid count %q", args[0])
+	}
+
+	auths, closeDB, err := certificates.Config{DatabaseURL: authorizeCfg.DatabaseURL}.OpenAuthorizationDB()
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, closeDB()) }()
+
+	tokens, err := auths.Create(count)
+	for _, token := range tokens {
+		fmt.Println(token)
+	}
+	return err
+}
@@ -9,12 +9,31 @@ import (
 	"path/filepath"
 
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/accounting/export"
+	"storj.io/storj/pkg/accounting/tally"
+	"storj.io/storj/pkg/analytics"
+	"storj.io/storj/pkg/backup"
+	"storj.io/storj/pkg/certificates"
 	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/chore"
+	"storj.io/storj/pkg/console"
+	"storj.io/storj/pkg/credentials"
+	"storj.io/storj/pkg/discovery"
+	"storj.io/storj/pkg/gc"
+	"storj.io/storj/pkg/gracefulexit"
+	"storj.io/storj/pkg/health"
 	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/lease"
+	"storj.io/storj/pkg/metainfo"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pointerdb"
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/proxy"
+	"storj.io/storj/pkg/verification"
+	"storj.io/storj/pkg/vetting"
 )
 
 var (
@@ -34,11 +53,29 @@ var (
 	}
 
 	runCfg struct {
-		Identity    provider.IdentityConfig
-		Kademlia    kademlia.Config
-		PointerDB   pointerdb.Config
-		Overlay     overlay.Config
-		MockOverlay overlay.MockConfig
+		Identity     provider.IdentityConfig
+		Lease        lease.Config
+		Chores       chore.Config
+		Kademlia     kademlia.Config
+		Certificates certificates.Config
+		Analytics    analytics.Config
+		Vetting      vetting.Config
+		PointerDB    pointerdb.Config
+		Metainfo     metainfo.Config
+		Backup       backup.Config
+		Overlay      overlay.Config
+		MockOverlay  overlay.MockConfig
+		GC           gc.Config
+		Verification verification.Config
+		Discovery    discovery.Config
+		Accounting   accounting.Config
+		Export       export.Config
+		Tally        tally.Config
+		Proxy        proxy.Config
+		Credentials  credentials.Config
+		GracefulExit gracefulexit.Config
+		Console      console.Config
+		Health       health.Config
 	}
 	setupCfg struct {
 		BasePath  string `default:"$CONFDIR" help:"base path for setup"`
@@ -58,12 +95,22 @@ func init() {
 }
 
 func cmdRun(cmd *cobra.Command, args []string) (err error) {
-	var o provider.Responsibility = runCfg.Overlay
 	if runCfg.MockOverlay.Nodes != "" {
-		o = runCfg.MockOverlay
+		// these responsibilities need the real overlay
+		zap.S().Warn("garbage collection, audit verification, node discovery, vetting and graceful exit are disabled with the mock overlay")
+		return runCfg.Identity.Run(process.Ctx(cmd),
+			runCfg.Lease, runCfg.Chores, runCfg.Kademlia, runCfg.Certificates, runCfg.Analytics, runCfg.Accounting, runCfg.PointerDB, runCfg.Metainfo, runCfg.Backup, runCfg.MockOverlay, runCfg.Export, runCfg.Tally,
+			runCfg.Proxy, runCfg.Credentials, runCfg.Console, runCfg.Health)
 	}
+	// garbage collection, discovery and graceful exit need the real overlay,
+	// which pointerdb uses to include node addresses in pointer lookups. the
+	// overlay vets nodes with the signing service, so it's started before it.
+	// pointerdb attributes new buckets and rolls up downloads in the accounting
+	// database.
 	return runCfg.Identity.Run(process.Ctx(cmd),
-		runCfg.Kademlia, runCfg.PointerDB, o)
+		runCfg.Lease, runCfg.Chores, runCfg.Kademlia, runCfg.Certificates, runCfg.Analytics, runCfg.Accounting, runCfg.Vetting, runCfg.Overlay, runCfg.PointerDB, runCfg.Metainfo, runCfg.Backup, runCfg.GC, runCfg.Verification,
+		runCfg.Discovery, runCfg.Export, runCfg.Tally, runCfg.Proxy, runCfg.Credentials,
+		runCfg.GracefulExit, runCfg.Console, runCfg.Health)
 }
 
 func cmdSetup(cmd *cobra.Command, args []string) (err error) {
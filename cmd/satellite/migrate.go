@@ -0,0 +1,129 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/migrate"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	migratePointersCmd = &cobra.Command{
+		Use:   "migrate-pointers",
+		Short: "Copy the pointers of a bolt pointerdb to a SQL database",
+		Long: "Streams the pointers of the source database to the destination, like postgres://, mysql:// " +
+			"or sqlite3:// URLs, in batches, recording the last copied pointer in a checkpoint from which an " +
+			"interrupted migration is resumed, and reads a sample of the copied pointers back to verify them. " +
+			"Pointers written during the migration may be missed, so with --read-only-url it checks that " +
+			"the satellite is in read-only mode. The bolt database is locked by a running satellite, so " +
+			"migrate a copy taken once the satellite is read-only.",
+		RunE: cmdMigratePointers,
+	}
+
+	migratePointersCfg struct {
+		Source      string  `help:"the pointerdb database to copy from" default:"bolt://$CONFDIR/pointerdb.db"`
+		Destination string  `help:"the pointerdb database to copy to" default:""`
+		Checkpoint  string  `help:"the file the progress of the migration is recorded in" default:"$CONFDIR/migrate-pointers.json"`
+		BatchSize   int     `help:"how many pointers are copied in each transaction" default:"1000"`
+		SampleRate  float64 `help:"the fraction of the copied pointers read back to verify them" default:"0.01"`
+		ReadOnlyURL string  `help:"the read-only mode admin API of the satellite, like http://localhost:10009/pointerdb/read-only, which must be enabled" default:""`
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(migratePointersCmd)
+	cfgstruct.Bind(migratePointersCmd.Flags(), &migratePointersCfg, cfgstruct.ConfDir(defaultConfDir))
+}
+
+func cmdMigratePointers(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	if migratePointersCfg.Destination == "" {
+		return migrate.Error.New("no destination")
+	}
+	if err := checkReadOnly(ctx); err != nil {
+		return err
+	}
+
+	checkpoint, err := migrate.LoadCheckpoint(migratePointersCfg.Checkpoint,
+		migratePointersCfg.Source, migratePointersCfg.Destination)
+	if err != nil {
+		return err
+	}
+
+	source, err := pointerdb.OpenStore(migratePointersCfg.Source, pointerdb.PointerBucket)
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, source.Close()) }()
+
+	dest, err := pointerdb.OpenStore(migratePointersCfg.Destination, pointerdb.PointerBucket)
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, dest.Close()) }()
+
+	migrator := migrate.NewPointerMigrator(zap.L(), source, dest, checkpoint,
+		migratePointersCfg.BatchSize, migratePointersCfg.SampleRate)
+	stats, err := migrator.Migrate(ctx)
+	fmt.Printf("copied %d pointers (%d bytes), %d in total, verified %d\n",
+		stats.Copied, stats.Bytes, checkpoint.Copied, stats.Sampled)
+	if err != nil {
+		return err
+	}
+
+	// the satellite must not have left read-only mode while the pointers
+	// were copied
+	if err := checkReadOnly(ctx); err != nil {
+		return err
+	}
+
+	for _, key := range stats.Mismatched {
+		fmt.Printf("mismatched\t%s\n", key)
+	}
+	if len(stats.Mismatched) > 0 {
+		return migrate.Error.New("%d verified pointers don't match the source", len(stats.Mismatched))
+	}
+	return nil
+}
+
+// checkReadOnly checks that the satellite is in read-only mode, if its
+// read-only mode admin API is configured
+func checkReadOnly(ctx context.Context) (err error) {
+	if migratePointersCfg.ReadOnlyURL == "" {
+		return nil
+	}
+	req, err := http.NewRequest(http.MethodGet, migratePointersCfg.ReadOnlyURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, resp.Body.Close()) }()
+	if resp.StatusCode != http.StatusOK {
+		return migrate.Error.New("read-only mode: %s", resp.Status)
+	}
+
+	var state pointerdb.ReadOnlyState
+	if err := json.NewDecoder(resp.Body).Decode(&state); err != nil {
+		return err
+	}
+	if !state.Enabled {
+		return migrate.Error.New("the satellite isn't in read-only mode")
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/notification"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	notifyCmd = &cobra.Command{
+		Use:   "notify-nodes NODEID...",
+		Short: "Send a notification to storage nodes",
+		Long: "Sends a notification to the inbox of the storage nodes NODEID..., e.g. to warn about the " +
+			"deprecation of a version, a suspension or a payout. The overlay bolt database is locked by " +
+			"a running satellite, so run it while the satellite is stopped or on a copy.",
+		Args: cobra.MinimumNArgs(1),
+		RunE: cmdNotify,
+	}
+
+	notifyCfg struct {
+		Identity provider.IdentityConfig
+		Overlay  string `help:"the overlay bolt database" default:"$CONFDIR/overlay.db"`
+		Type     string `help:"the type of the notification: info, version_deprecation, suspension_warning or payout" default:"info"`
+		Title    string `help:"the title of the notification" default:""`
+		Message  string `help:"the message of the notification" default:""`
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(notifyCmd)
+	cfgstruct.Bind(notifyCmd.Flags(), &notifyCfg, cfgstruct.ConfDir(defaultConfDir))
+}
+
+func cmdNotify(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	notificationType, ok := pb.Notification_Type_value[strings.ToUpper(notifyCfg.Type)]
+	if !ok {
+		return fmt.Errorf("unknown notification type %q", notifyCfg.Type)
+	}
+	if notifyCfg.Title == "" {
+		return fmt.Errorf("the notification has no title")
+	}
+
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return err
+	}
+	n := &pb.Notification{
+		Id:             hex.EncodeToString(id[:]),
+		Type:           pb.Notification_Type(notificationType),
+		Title:          notifyCfg.Title,
+		Message:        notifyCfg.Message,
+		CreatedUnixSec: time.Now().Unix(),
+	}
+
+	identity, err := notifyCfg.Identity.Load()
+	if err != nil {
+		return err
+	}
+
+	cache, err := overlay.NewBoltOverlayCache(notifyCfg.Overlay, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, cache.DB.Close()) }()
+
+	sender := notification.NewSender(transport.NewClient(identity), cache)
+
+	var failed int
+	for _, nodeID := range args {
+		if err := sender.Send(ctx, nodeID, n); err != nil {
+			fmt.Printf("%s\tfailed: %v\n", nodeID, err)
+			failed++
+			continue
+		}
+		fmt.Printf("%s\tnotified\n", nodeID)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d nodes weren't notified", failed, len(args))
+	}
+	return nil
+}
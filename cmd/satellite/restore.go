@@ -0,0 +1,101 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/backup"
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage/boltdb"
+)
+
+var (
+	restoreCmd = &cobra.Command{
+		Use:   "restore-metainfo",
+		Short: "Restore pointerdb from a snapshot, or verify it against one",
+		Long: "Writes the pointers of a snapshot taken by the backup responsibility to the pointerdb bolt " +
+			"database, or with --verify reports how the database differs from the snapshot. The database " +
+			"is locked by a running satellite, so run it while the satellite is stopped or on a copy.",
+		RunE: cmdRestore,
+	}
+
+	restoreCfg struct {
+		PointerDB string `help:"the pointerdb bolt database" default:"$CONFDIR/pointerdb.db"`
+		From      string `help:"where the snapshots are stored, like backup.url" default:""`
+		Snapshot  string `help:"the name of the snapshot, the latest if empty" default:""`
+		Verify    bool   `help:"compare the database with the snapshot instead of restoring it" default:"false"`
+		List      bool   `help:"list the snapshots instead of restoring one" default:"false"`
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	cfgstruct.Bind(restoreCmd.Flags(), &restoreCfg, cfgstruct.ConfDir(defaultConfDir))
+}
+
+func cmdRestore(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	dest, err := backup.OpenDestination(restoreCfg.From)
+	if err != nil {
+		return err
+	}
+
+	if restoreCfg.List {
+		snapshots, err := backup.Snapshots(dest)
+		if err != nil {
+			return err
+		}
+		for _, snapshot := range snapshots {
+			fmt.Printf("%s\t%d pointers\t%d bytes\n", snapshot.Name, snapshot.Count, snapshot.Size)
+		}
+		return nil
+	}
+
+	name := restoreCfg.Snapshot
+	if name == "" {
+		name, err = backup.Latest(dest)
+		if err != nil {
+			return err
+		}
+	}
+
+	pointers, err := boltdb.New(restoreCfg.PointerDB, pointerdb.PointerBucket)
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, pointers.Close()) }()
+
+	if !restoreCfg.Verify {
+		manifest, err := backup.Restore(ctx, dest, name, pointers)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("restored %d pointers of snapshot %s\n", manifest.Count, name)
+		return nil
+	}
+
+	report, err := backup.Verify(ctx, dest, name, pointers)
+	if err != nil {
+		return err
+	}
+	for _, path := range report.Missing {
+		fmt.Printf("missing\t%s\n", path)
+	}
+	for _, path := range report.Different {
+		fmt.Printf("different\t%s\n", path)
+	}
+	for _, path := range report.Extra {
+		fmt.Printf("extra\t%s\n", path)
+	}
+	fmt.Printf("\nchecked %d pointers of snapshot %s: %d missing, %d different, %d extra\n",
+		report.Checked, name, len(report.Missing), len(report.Different), len(report.Extra))
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/console"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	signupCmd = &cobra.Command{
+		Use:   "signup-token",
+		Short: "Manage the signup tokens of the console",
+	}
+	signupCreateCmd = &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create a signup token and print its signup link",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdSignupCreate,
+	}
+	signupListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the signup tokens and how many users registered with them",
+		RunE:  cmdSignupList,
+	}
+	signupRevokeCmd = &cobra.Command{
+		Use:   "revoke ID",
+		Short: "Stop users from registering with a signup token",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdSignupRevoke,
+	}
+
+	signupCfg struct {
+		Console         string        `help:"the console database" default:"$CONFDIR/console.db"`
+		ExternalAddress string        `help:"address of the console the signup links point to" default:"http://localhost:10100/"`
+		Uses            int           `help:"how many users may register with the token, 0 if unlimited" default:"0"`
+		Expiration      time.Duration `help:"how long the token is valid, 0 if forever" default:"0"`
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(signupCmd)
+	signupCmd.AddCommand(signupCreateCmd)
+	signupCmd.AddCommand(signupListCmd)
+	signupCmd.AddCommand(signupRevokeCmd)
+	cfgstruct.Bind(signupCmd.PersistentFlags(), &signupCfg, cfgstruct.ConfDir(defaultConfDir))
+}
+
+func cmdSignupCreate(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	db, err := console.Open(ctx, signupCfg.Console)
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, db.Close()) }()
+	db.ExternalAddress = signupCfg.ExternalAddress
+
+	token, err := db.CreateSignupToken(ctx, "", args[0], signupCfg.Uses, signupCfg.Expiration)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("created signup token %s: %s\n", token.ID, db.SignupLink(token.Token))
+	return nil
+}
+
+func cmdSignupList(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	db, err := console.Open(ctx, signupCfg.Console)
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, db.Close()) }()
+
+	tokens, err := db.SignupTokens(ctx)
+	if err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		uses := fmt.Sprintf("%d", token.Uses)
+		if token.MaxUses > 0 {
+			uses = fmt.Sprintf("%d/%d", token.Uses, token.MaxUses)
+		}
+		state := "valid"
+		switch {
+		case token.Revoked:
+			state = "revoked"
+		case !token.Expires.IsZero() && time.Now().After(token.Expires):
+			state = "expired"
+		}
+		fmt.Printf("%s\t%s\t%s uses\t%s\tcreated by %q\n", token.ID, token.Name, uses, state, token.CreatedBy)
+	}
+	return nil
+}
+
+func cmdSignupRevoke(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	db, err := console.Open(ctx, signupCfg.Console)
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, db.Close()) }()
+
+	if err := db.RevokeSignupToken(ctx, args[0]); err != nil {
+		return err
+	}
+	fmt.Printf("revoked signup token %s\n", args[0])
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/pkg/verification"
+	"storj.io/storj/storage/boltdb"
+)
+
+var (
+	verifyCmd = &cobra.Command{
+		Use:   "verify-segments",
+		Short: "Check that the pieces of segments are retrievable from the storage nodes",
+		Long: "Asks the storage nodes of every remote segment in the range whether they have their piece " +
+			"and reports the segments that are at risk or lost. The pointerdb and overlay bolt databases " +
+			"are locked by a running satellite, so run it while the satellite is stopped or on copies.",
+		RunE: cmdVerify,
+	}
+
+	verifyCfg struct {
+		Identity    provider.IdentityConfig
+		PointerDB   string `help:"the pointerdb bolt database" default:"$CONFDIR/pointerdb.db"`
+		Overlay     string `help:"the overlay bolt database" default:"$CONFDIR/overlay.db"`
+		First       string `help:"the path of the first segment to verify" default:""`
+		End         string `help:"the path to stop verification before, all segments if empty" default:""`
+		Limit       int    `help:"the maximum number of segments to verify, all if 0" default:"0"`
+		Concurrency int    `help:"how many storage nodes are asked in parallel" default:"10"`
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(verifyCmd)
+	cfgstruct.Bind(verifyCmd.Flags(), &verifyCfg, cfgstruct.ConfDir(defaultConfDir))
+}
+
+func cmdVerify(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	identity, err := verifyCfg.Identity.Load()
+	if err != nil {
+		return err
+	}
+
+	pointers, err := boltdb.New(verifyCfg.PointerDB, pointerdb.PointerBucket)
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, pointers.Close()) }()
+
+	cache, err := overlay.NewBoltOverlayCache(verifyCfg.Overlay, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, cache.DB.Close()) }()
+
+	checker := verification.NewNodeChecker(identity, transport.NewClient(identity), cache)
+	service := verification.NewService(zap.L(), pointers, checker, verifyCfg.Concurrency)
+
+	report, err := service.Verify(ctx, verification.Range{
+		First: verifyCfg.First,
+		End:   verifyCfg.End,
+		Limit: verifyCfg.Limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PATH\tHEALTH\tRETRIEVABLE\tREQUIRED\tREPAIR AT\tTOTAL\tMISSING\tUNREACHABLE")
+	for _, segment := range report.Unhealthy {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%s\t%s\n",
+			segment.Path, segment.Health, segment.Retrievable, segment.Required,
+			segment.RepairThreshold, segment.Total,
+			strings.Join(segment.Missing, ","), strings.Join(segment.Unreachable, ","))
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nverified %d remote and %d inline segments, %d unhealthy\n",
+		report.Checked, report.Inline, len(report.Unhealthy))
+	if report.Last != "" {
+		fmt.Printf("last segment: %s\n", report.Last)
+	}
+	return nil
+}
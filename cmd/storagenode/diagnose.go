@@ -0,0 +1,287 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/piecestore/rpc/server/psdb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/provider"
+)
+
+var (
+	diagnoseCmd = &cobra.Command{
+		Use:   "diagnose SATELLITE",
+		Short: "Find out why the node is offline",
+		Long: "Checks the identity of the node, that the node listens locally and is reachable from outside " +
+			"(by asking the satellite at address SATELLITE to dial it back), that the clock agrees with the " +
+			"satellite's, the integrity of the piece database and the write latency of the disk. Prints the " +
+			"most likely cause of the node being offline. The node must be running.",
+		Args: cobra.ExactArgs(1),
+		RunE: cmdDiagnose,
+	}
+
+	diagnoseCfg struct {
+		ExternalAddress string        `help:"the address the node is reachable at from outside. if empty, the port of identity.address on the host the satellite sees the node at" default:""`
+		Timeout         time.Duration `help:"how long to wait for each connection" default:"10s"`
+		MaxClockSkew    time.Duration `help:"how far the clock may be off the satellite's" default:"1m"`
+		MaxWriteLatency time.Duration `help:"how long a synced write to the disk may take on average" default:"100ms"`
+		WriteSamples    int           `help:"how many synced writes to time" default:"10"`
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(diagnoseCmd)
+	cfgstruct.Bind(diagnoseCmd.Flags(), &runCfg, cfgstruct.ConfDir(defaultConfDir))
+	cfgstruct.Bind(diagnoseCmd.Flags(), &diagnoseCfg, cfgstruct.ConfDir(defaultConfDir))
+}
+
+// finding is a check made by diagnose
+type finding struct {
+	check  string
+	failed bool
+	detail string
+	// cause is what the failure means for the node, if the check failed
+	cause string
+}
+
+// diagnosis collects the findings of diagnose, in the order of how likely
+// their failures are to keep the node offline
+type diagnosis struct {
+	findings []finding
+}
+
+// ok prints and records a check that passed
+func (d *diagnosis) ok(check, format string, args ...interface{}) {
+	d.report(finding{check: check, detail: fmt.Sprintf(format, args...)})
+}
+
+// fail prints and records a check that failed because of cause
+func (d *diagnosis) fail(check, cause, format string, args ...interface{}) {
+	d.report(finding{check: check, failed: true, detail: fmt.Sprintf(format, args...), cause: cause})
+}
+
+func (d *diagnosis) report(f finding) {
+	d.findings = append(d.findings, f)
+	result := "OK"
+	if f.failed {
+		result = "FAILED"
+	}
+	fmt.Printf("%-8s %-12s %s\n", result, f.check, f.detail)
+}
+
+// cause returns the most likely cause of the node being offline, the cause
+// of the first failed check
+func (d *diagnosis) cause() (string, bool) {
+	for _, f := range d.findings {
+		if f.failed {
+			return f.cause, true
+		}
+	}
+	return "", false
+}
+
+func cmdDiagnose(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+	d := &diagnosis{}
+
+	identity, err := runCfg.Identity.Load()
+	if err != nil {
+		d.fail("identity", "the identity can't be loaded, so the node can't start. check identity.cert-path and identity.key-path",
+			"can't load the identity: %v", err)
+	} else {
+		d.checkIdentity(identity)
+		if d.checkListening() {
+			d.checkReachable(ctx, identity, args[0])
+		}
+	}
+	d.checkDatabase(ctx)
+	d.checkDisk()
+
+	cause, failed := d.cause()
+	if !failed {
+		fmt.Println("\nno problem found. if the node is still offline, the satellite may not have checked it in yet")
+		return nil
+	}
+	fmt.Printf("\nmost likely cause of offline status: %s\n", cause)
+	return fmt.Errorf("diagnose found problems")
+}
+
+// checkIdentity checks that the identity is valid now and that its leaf is
+// signed by its certificate authority
+func (d *diagnosis) checkIdentity(identity *provider.FullIdentity) {
+	now := time.Now()
+	for name, cert := range map[string]*x509.Certificate{"certificate authority": identity.CA, "certificate": identity.Leaf} {
+		if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+			d.fail("identity", "the identity isn't valid, so satellites refuse the node. create a new identity",
+				"the %s is only valid from %s to %s", name,
+				cert.NotBefore.Format(time.RFC3339), cert.NotAfter.Format(time.RFC3339))
+			return
+		}
+	}
+	if err := identity.Leaf.CheckSignatureFrom(identity.CA); err != nil {
+		d.fail("identity", "the identity isn't valid, so satellites refuse the node. create a new identity",
+			"the certificate isn't signed by the certificate authority: %v", err)
+		return
+	}
+	d.ok("identity", "node %s, valid until %s", identity.ID, identity.Leaf.NotAfter.Format(time.RFC3339))
+}
+
+// checkListening checks that the node accepts connections locally
+func (d *diagnosis) checkListening() bool {
+	address := localAddress(runCfg.Identity.Address)
+	conn, err := net.DialTimeout("tcp", address, diagnoseCfg.Timeout)
+	if err != nil {
+		d.fail("listening", "the node isn't running, or doesn't listen on identity.address. start it with `storagenode run`",
+			"can't connect to %s: %v", address, err)
+		return false
+	}
+	_ = conn.Close()
+	d.ok("listening", "the node accepts connections on %s", address)
+	return true
+}
+
+// localAddress returns the address to connect to a server listening on
+// address from the same host
+func localAddress(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil || (host != "" && host != "0.0.0.0" && host != "::") {
+		return address
+	}
+	return net.JoinHostPort("127.0.0.1", port)
+}
+
+// checkReachable asks the satellite to dial the node back, and compares the
+// clock with the satellite's
+func (d *diagnosis) checkReachable(ctx context.Context, identity *provider.FullIdentity, satellite string) {
+	client, err := overlay.NewOverlayClient(identity, satellite)
+	if err != nil {
+		d.fail("satellite", "the satellite can't be reached. check the internet connection of the node",
+			"can't connect to the satellite %s: %v", satellite, err)
+		return
+	}
+	external := diagnoseCfg.ExternalAddress
+	if external == "" {
+		external = runCfg.Identity.Address
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 2*diagnoseCfg.Timeout)
+	defer cancel()
+	resp, err := client.Echo(ctx, external)
+	received := time.Now()
+	if err != nil {
+		d.fail("satellite", "the satellite can't be reached. check the internet connection of the node and the satellite address",
+			"the satellite %s didn't answer: %v", satellite, err)
+		return
+	}
+	d.ok("satellite", "the satellite sees the node at %s", resp.GetSeenAddress())
+
+	if resp.GetReachable() {
+		d.ok("reachable", "the satellite dialed the node at %s", resp.GetDialedAddress())
+	} else if resp.GetDialedAddress() == "" {
+		d.fail("reachable", "the external address of the node doesn't point to it. set --external-address to the public address of the node",
+			"the satellite didn't dial the node at %s: %s", external, resp.GetError())
+	} else {
+		d.fail("reachable", "the node isn't reachable from outside. forward its port to this machine in the router and open it in the firewall",
+			"the satellite can't dial the node at %s: %s", resp.GetDialedAddress(), resp.GetError())
+	}
+
+	satelliteTime, err := ptypes.Timestamp(resp.GetTime())
+	if err != nil {
+		d.fail("clock", "the satellite didn't send its time. it may be outdated", "%v", err)
+		return
+	}
+	skew := received.Sub(satelliteTime)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > diagnoseCfg.MaxClockSkew {
+		d.fail("clock", "the clock is off, so satellites reject the signatures of the node. sync it with NTP",
+			"the clock is %s off the satellite's", skew.Round(time.Second))
+		return
+	}
+	d.ok("clock", "the clock is within %s of the satellite's", skew.Round(time.Millisecond))
+}
+
+// checkDatabase checks the integrity of the piece database
+func (d *diagnosis) checkDatabase(ctx context.Context) {
+	dbPath := filepath.Join(runCfg.Storage.Path, "piecestore.db")
+	problems, err := psdb.CheckIntegrity(ctx, dbPath)
+	if err != nil {
+		d.fail("database", "the piece database can't be read, so the node can't start. check storage.path and its permissions",
+			"can't check %s: %v", dbPath, err)
+		return
+	}
+	if len(problems) > 0 {
+		if len(problems) > 3 {
+			problems = append(problems[:3], fmt.Sprintf("and %d more", len(problems)-3))
+		}
+		d.fail("database", "the piece database is corrupted. stop the node and restore it from a backup",
+			"%s is corrupted: %s", dbPath, strings.Join(problems, "; "))
+		return
+	}
+	d.ok("database", "%s is intact", dbPath)
+}
+
+// checkDisk times synced writes to the storage path
+func (d *diagnosis) checkDisk() {
+	var total time.Duration
+	data := make([]byte, 4096)
+	for i := 0; i < diagnoseCfg.WriteSamples; i++ {
+		elapsed, err := timeWrite(runCfg.Storage.Path, data)
+		if err != nil {
+			d.fail("disk", "the storage path isn't writable, so pieces can't be stored. check storage.path, its permissions and free space",
+				"can't write to %s: %v", runCfg.Storage.Path, err)
+			return
+		}
+		total += elapsed
+	}
+	if diagnoseCfg.WriteSamples <= 0 {
+		return
+	}
+
+	latency := total / time.Duration(diagnoseCfg.WriteSamples)
+	if latency > diagnoseCfg.MaxWriteLatency {
+		d.fail("disk", "the disk is too slow, so uploads and audits time out. move the storage path to a faster disk",
+			"synced writes take %s on average", latency)
+		return
+	}
+	d.ok("disk", "synced writes take %s on average", latency)
+}
+
+// timeWrite returns how long writing data to a temporary file in dir and
+// syncing it takes
+func timeWrite(dir string, data []byte) (elapsed time.Duration, err error) {
+	file, err := ioutil.TempFile(dir, "diagnose")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = file.Close()
+		_ = os.Remove(file.Name())
+	}()
+
+	start := time.Now()
+	if _, err := file.Write(data); err != nil {
+		return 0, err
+	}
+	if err := file.Sync(); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
@@ -11,6 +11,9 @@ import (
 
 	"storj.io/storj/pkg/cfgstruct"
 	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/nodeapi"
+	"storj.io/storj/pkg/nodestats"
+	"storj.io/storj/pkg/notification"
 	psserver "storj.io/storj/pkg/piecestore/rpc/server"
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/provider"
@@ -36,6 +39,10 @@ var (
 		Identity provider.IdentityConfig
 		Kademlia kademlia.Config
 		Storage  psserver.Config
+		Stats    nodestats.Config
+
+		Notifications notification.Config
+		API           nodeapi.Config
 	}
 	setupCfg struct {
 		BasePath string `default:"$CONFDIR" help:"base path for setup"`
@@ -54,7 +61,7 @@ func init() {
 }
 
 func cmdRun(cmd *cobra.Command, args []string) (err error) {
-	return runCfg.Identity.Run(process.Ctx(cmd), runCfg.Kademlia, runCfg.Storage)
+	return runCfg.Identity.Run(process.Ctx(cmd), runCfg.Kademlia, runCfg.Storage, runCfg.Stats, runCfg.Notifications, runCfg.API)
 }
 
 func cmdSetup(cmd *cobra.Command, args []string) (err error) {
@@ -79,9 +86,11 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 	}
 
 	overrides := map[string]interface{}{
-		"identity.cert-path": setupCfg.Identity.CertPath,
-		"identity.key-path":  setupCfg.Identity.KeyPath,
-		"storage.path":       filepath.Join(setupCfg.BasePath, "storage"),
+		"identity.cert-path":         setupCfg.Identity.CertPath,
+		"identity.key-path":          setupCfg.Identity.KeyPath,
+		"storage.path":               filepath.Join(setupCfg.BasePath, "storage"),
+		"notifications.database-url": "bolt://" + filepath.Join(setupCfg.BasePath, "notifications.db"),
+		"api.token-path":             filepath.Join(setupCfg.BasePath, "api-token"),
 	}
 
 	return process.SaveConfig(runCmd.Flags(),
@@ -91,5 +100,9 @@ func cmdSetup(cmd *cobra.Command, args []string) (err error) {
 func main() {
 	runCmd.Flags().String("config",
 		filepath.Join(defaultConfDir, "config.yaml"), "path to configuration")
+	updateOperatorCmd.Flags().String("config",
+		filepath.Join(defaultConfDir, "config.yaml"), "path to configuration")
+	diagnoseCmd.Flags().String("config",
+		filepath.Join(defaultConfDir, "config.yaml"), "path to configuration")
 	process.Exec(rootCmd)
 }
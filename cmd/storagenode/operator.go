@@ -0,0 +1,60 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/cfgstruct"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/process"
+)
+
+var (
+	updateOperatorCmd = &cobra.Command{
+		Use:   "update-operator SATELLITE",
+		Short: "Update the operator of the node on a satellite",
+		Long: "Sends the operator email, wallet and wallet signature of the configuration to the overlay of " +
+			"the satellite at address SATELLITE, without waiting for the satellite to check the node in. " +
+			"The node must have been checked in once.",
+		Args: cobra.ExactArgs(1),
+		RunE: cmdUpdateOperator,
+	}
+)
+
+func init() {
+	rootCmd.AddCommand(updateOperatorCmd)
+	cfgstruct.Bind(updateOperatorCmd.Flags(), &runCfg, cfgstruct.ConfDir(defaultConfDir))
+}
+
+func cmdUpdateOperator(cmd *cobra.Command, args []string) (err error) {
+	operator, err := runCfg.Storage.Operator()
+	if err != nil {
+		return err
+	}
+	if operator == nil {
+		return fmt.Errorf("no operator configured")
+	}
+
+	identity, err := runCfg.Identity.Load()
+	if err != nil {
+		return err
+	}
+	if err := overlay.ValidateOperator(operator, identity.CA.PublicKey); err != nil {
+		return err
+	}
+
+	client, err := overlay.NewOverlayClient(identity, args[0])
+	if err != nil {
+		return err
+	}
+	if err := client.UpdateOperator(process.Ctx(cmd), operator); err != nil {
+		return err
+	}
+
+	fmt.Printf("updated the operator of %s\n", identity.ID)
+	return nil
+}
@@ -0,0 +1,374 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/accesses"
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb/pdbclient"
+	"storj.io/storj/pkg/process"
+)
+
+var (
+	accessCmd = &cobra.Command{
+		Use:   "access",
+		Short: "Manage the named accesses to satellites and projects",
+	}
+	overwriteAccessFlag *bool
+	importOverwriteFlag *bool
+	readOnlyFlag        *bool
+	prefixFlags         *[]string
+	notAfterFlag        *string
+)
+
+func init() {
+	RootCmd.AddCommand(accessCmd)
+
+	createCmd := addSubCmd(accessCmd, &cobra.Command{
+		Use:   "create NAME",
+		Short: "Create a named access from the addresses and API key of the config and flags",
+		Args:  cobra.ExactArgs(1),
+		RunE:  createAccess,
+	})
+	overwriteAccessFlag = createCmd.Flags().Bool("overwrite", false, "if true, replace an existing access with the same name")
+
+	addSubCmd(accessCmd, &cobra.Command{
+		Use:   "list",
+		Short: "List the named accesses",
+		Args:  cobra.NoArgs,
+		RunE:  listAccesses,
+	})
+
+	addSubCmd(accessCmd, &cobra.Command{
+		Use:   "use NAME",
+		Short: "Use the named access for all further commands",
+		Args:  cobra.ExactArgs(1),
+		RunE:  useNamedAccess,
+	})
+
+	inspectCmd := addSubCmd(accessCmd, &cobra.Command{
+		Use:   "inspect NAME",
+		Short: "Print the addresses, project and restrictions of the named access",
+		Args:  cobra.ExactArgs(1),
+		RunE:  inspectAccess,
+	})
+	addOutputFlag(inspectCmd)
+
+	addSubCmd(accessCmd, &cobra.Command{
+		Use:   "export NAME",
+		Short: "Print the named access serialized, to be imported by another uplink",
+		Args:  cobra.ExactArgs(1),
+		RunE:  exportAccess,
+	})
+
+	importCmd := addSubCmd(accessCmd, &cobra.Command{
+		Use:   "import NAME SERIALIZED",
+		Short: "Import a serialized access under a name",
+		Args:  cobra.ExactArgs(2),
+		RunE:  importAccess,
+	})
+	importOverwriteFlag = importCmd.Flags().Bool("overwrite", false, "if true, replace an existing access with the same name")
+
+	restrictCmd := addSubCmd(accessCmd, &cobra.Command{
+		Use:   "restrict NAME NEW_NAME",
+		Short: "Create a restricted copy of a named access without contacting the satellite",
+		Args:  cobra.ExactArgs(2),
+		RunE:  restrictAccess,
+	})
+	readOnlyFlag = restrictCmd.Flags().Bool("readonly", false, "if true, the access can't upload or delete")
+	prefixFlags = restrictCmd.Flags().StringSlice("prefix", nil, "restrict the access to a bucket or bucket/path prefix, may be repeated")
+	notAfterFlag = restrictCmd.Flags().String("not-after", "", "RFC 3339 time or duration from now after which the access expires")
+
+	addSubCmd(accessCmd, &cobra.Command{
+		Use:   "revoke NAME",
+		Short: "Revoke the API key of the named access and every access restricted from it",
+		Args:  cobra.ExactArgs(1),
+		RunE:  revokeAccess,
+	})
+}
+
+func createAccess(cmd *cobra.Command, args []string) error {
+	store := cfg.AccessStore()
+	named, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	err = named.Add(args[0], accesses.Access{
+		OverlayAddr:   cfg.OverlayAddr,
+		PointerDBAddr: cfg.PointerDBAddr,
+		APIKey:        cfg.APIKey,
+	}, *overwriteAccessFlag)
+	if err != nil {
+		return err
+	}
+	if named.Current == "" {
+		named.Current = args[0]
+	}
+
+	if err := store.Save(named); err != nil {
+		return err
+	}
+	fmt.Printf("Created access %s\n", args[0])
+	return nil
+}
+
+func listAccesses(cmd *cobra.Command, args []string) error {
+	named, err := cfg.AccessStore().Load()
+	if err != nil {
+		return err
+	}
+
+	names := named.Names()
+	if len(names) == 0 {
+		fmt.Println("No accesses")
+		return nil
+	}
+
+	for _, name := range names {
+		access, _ := named.Get(name)
+		current := " "
+		if name == named.Current {
+			current = "*"
+		}
+		fmt.Printf("%s %s %s\n", current, name, access.PointerDBAddr)
+	}
+	return nil
+}
+
+func useNamedAccess(cmd *cobra.Command, args []string) error {
+	store := cfg.AccessStore()
+	named, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	if err := named.Use(args[0]); err != nil {
+		return err
+	}
+
+	if err := store.Save(named); err != nil {
+		return err
+	}
+	fmt.Printf("Using access %s\n", args[0])
+	return nil
+}
+
+func inspectAccess(cmd *cobra.Command, args []string) error {
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+
+	named, err := cfg.AccessStore().Load()
+	if err != nil {
+		return err
+	}
+	access, ok := named.Get(args[0])
+	if !ok {
+		return accesses.Error.New("no access called %q", args[0])
+	}
+
+	result := accessResult{
+		Name:          args[0],
+		Current:       args[0] == named.Current,
+		OverlayAddr:   access.OverlayAddr,
+		PointerDBAddr: access.PointerDBAddr,
+		ProjectID:     macaroon.ProjectID([]byte(access.APIKey)),
+		Restrictions:  []accessRestriction{},
+	}
+	if key, err := macaroon.ParseAPIKey(access.APIKey); err == nil {
+		result.Macaroon = true
+		caveats, err := key.Caveats()
+		if err != nil {
+			return err
+		}
+		for _, caveat := range caveats {
+			result.Restrictions = append(result.Restrictions, caveatRestriction(caveat))
+		}
+	}
+	if asJSON {
+		return printJSON(result)
+	}
+
+	fmt.Printf("Name:       %s\n", result.Name)
+	fmt.Printf("Current:    %t\n", result.Current)
+	fmt.Printf("Overlay:    %s\n", result.OverlayAddr)
+	fmt.Printf("PointerDB:  %s\n", result.PointerDBAddr)
+	fmt.Printf("Project:    %s\n", result.ProjectID)
+	if !result.Macaroon {
+		fmt.Println("API key:    not a macaroon, can't be restricted")
+	}
+	for _, r := range result.Restrictions {
+		fmt.Printf("Restricted: %s\n", formatRestriction(r))
+	}
+	return nil
+}
+
+// caveatRestriction returns the restriction of the API key by caveat
+func caveatRestriction(caveat *pb.Caveat) accessRestriction {
+	r := accessRestriction{
+		DisallowReads:   caveat.GetDisallowReads(),
+		DisallowWrites:  caveat.GetDisallowWrites(),
+		DisallowLists:   caveat.GetDisallowLists(),
+		DisallowDeletes: caveat.GetDisallowDeletes(),
+	}
+	for _, path := range caveat.GetAllowedPaths() {
+		prefix := string(path.GetBucket())
+		if len(path.GetPathPrefix()) > 0 {
+			prefix += "/" + string(path.GetPathPrefix())
+		}
+		r.Prefixes = append(r.Prefixes, prefix)
+	}
+	if t, err := ptypes.Timestamp(caveat.GetNotBefore()); err == nil {
+		r.NotBefore = optionalTime(t)
+	}
+	if t, err := ptypes.Timestamp(caveat.GetNotAfter()); err == nil {
+		r.NotAfter = optionalTime(t)
+	}
+	return r
+}
+
+// formatRestriction returns r as a line of text
+func formatRestriction(r accessRestriction) string {
+	var parts []string
+	for _, op := range []struct {
+		name       string
+		disallowed bool
+	}{
+		{"reads", r.DisallowReads},
+		{"writes", r.DisallowWrites},
+		{"lists", r.DisallowLists},
+		{"deletes", r.DisallowDeletes},
+	} {
+		if op.disallowed {
+			parts = append(parts, "no "+op.name)
+		}
+	}
+	if len(r.Prefixes) > 0 {
+		parts = append(parts, "prefixes "+strings.Join(r.Prefixes, ", "))
+	}
+	if r.NotBefore != nil {
+		parts = append(parts, "not before "+formatTime(*r.NotBefore))
+	}
+	if r.NotAfter != nil {
+		parts = append(parts, "not after "+formatTime(*r.NotAfter))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, "; ")
+}
+
+func exportAccess(cmd *cobra.Command, args []string) error {
+	named, err := cfg.AccessStore().Load()
+	if err != nil {
+		return err
+	}
+
+	access, ok := named.Get(args[0])
+	if !ok {
+		return accesses.Error.New("no access called %q", args[0])
+	}
+	serialized, err := access.Serialize()
+	if err != nil {
+		return err
+	}
+	fmt.Println(serialized)
+	return nil
+}
+
+func importAccess(cmd *cobra.Command, args []string) error {
+	access, err := accesses.ParseAccess(args[1])
+	if err != nil {
+		return err
+	}
+	return saveAccess(args[0], access, *importOverwriteFlag)
+}
+
+func restrictAccess(cmd *cobra.Command, args []string) error {
+	named, err := cfg.AccessStore().Load()
+	if err != nil {
+		return err
+	}
+	access, ok := named.Get(args[0])
+	if !ok {
+		return accesses.Error.New("no access called %q", args[0])
+	}
+
+	restrictions := accesses.Restrictions{ReadOnly: *readOnlyFlag, Prefixes: *prefixFlags}
+	if *notAfterFlag != "" {
+		restrictions.NotAfter, err = parseNotAfter(*notAfterFlag)
+		if err != nil {
+			return err
+		}
+	}
+
+	restricted, err := access.Restrict(restrictions)
+	if err != nil {
+		return err
+	}
+	return saveAccess(args[1], restricted, false)
+}
+
+func revokeAccess(cmd *cobra.Command, args []string) error {
+	ctx := process.Ctx(cmd)
+
+	cfg.Access = args[0]
+	if err := cfg.useAccess(); err != nil {
+		return err
+	}
+	identity, err := cfg.Load()
+	if err != nil {
+		return err
+	}
+
+	pdb, err := pdbclient.NewClient(identity, cfg.PointerDBAddr, []byte(cfg.APIKey))
+	if err != nil {
+		return err
+	}
+	if err := pdb.Revoke(ctx); err != nil {
+		return err
+	}
+	fmt.Printf("Revoked access %s\n", args[0])
+	return nil
+}
+
+// saveAccess adds access to the named accesses as name
+func saveAccess(name string, access accesses.Access, overwrite bool) error {
+	store := cfg.AccessStore()
+	named, err := store.Load()
+	if err != nil {
+		return err
+	}
+
+	if err := named.Add(name, access, overwrite); err != nil {
+		return err
+	}
+	if err := store.Save(named); err != nil {
+		return err
+	}
+	fmt.Printf("Created access %s\n", name)
+	return nil
+}
+
+// parseNotAfter parses an RFC 3339 time or a duration from now
+func parseNotAfter(s string) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		return time.Now().Add(d), nil
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, accesses.Error.New("invalid time %q", s)
+	}
+	return t, nil
+}
@@ -36,6 +36,7 @@ func init() {
 		RunE:  copyMain,
 	})
 	progress = cpCmd.Flags().Bool("progress", true, "if true, show progress")
+	addOutputFlag(cpCmd)
 }
 
 func cleanAbsPath(p string) string {
@@ -91,14 +92,16 @@ func upload(ctx context.Context, bs buckets.Store, srcFile string, destObj *url.
 	meta := objects.SerializableMeta{}
 	expTime := time.Time{}
 
-	_, err = o.Put(ctx, paths.New(destObj.Path), r, meta, expTime)
+	m, err := o.Put(ctx, paths.New(destObj.Path), r, meta, expTime)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Created %s\n", destObj)
-
-	return nil
+	return printCopied(fmt.Sprintf("Created %s", destObj), copyResult{
+		Source:      srcFile,
+		Destination: destObj.String(),
+		Size:        m.Size,
+	})
 }
 
 // download downloads s3 compatible object args[0] to args[1] on local machine
@@ -145,16 +148,19 @@ func download(ctx context.Context, bs buckets.Store, srcObj *url.URL, destFile s
 		r = bar.NewProxyReader(r)
 	}
 
-	_, err = io.Copy(f, r)
+	n, err := io.Copy(f, r)
 	if err != nil {
 		return err
 	}
 
-	if destFile != "-" {
-		fmt.Printf("Downloaded %s to %s\n", srcObj, destFile)
+	if destFile == "-" {
+		return nil
 	}
-
-	return nil
+	return printCopied(fmt.Sprintf("Downloaded %s to %s", srcObj, destFile), copyResult{
+		Source:      srcObj.String(),
+		Destination: destFile,
+		Size:        n,
+	})
 }
 
 // copy copies s3 compatible object args[0] to s3 compatible object args[1]
@@ -197,13 +203,29 @@ func copy(ctx context.Context, bs buckets.Store, srcObj *url.URL, destObj *url.U
 		destObj.Path = path.Join(destObj.Path, path.Base(srcObj.Path))
 	}
 
-	_, err = o.Put(ctx, paths.New(destObj.Path), r, meta, expTime)
+	m, err := o.Put(ctx, paths.New(destObj.Path), r, meta, expTime)
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("%s copied to %s\n", srcObj, destObj)
+	return printCopied(fmt.Sprintf("%s copied to %s", srcObj, destObj), copyResult{
+		Source:      srcObj.String(),
+		Destination: destObj.String(),
+		Size:        m.Size,
+	})
+}
 
+// printCopied prints the result of a copy, as text unless --output json is
+// set
+func printCopied(text string, result copyResult) error {
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		return printJSON(result)
+	}
+	fmt.Println(text)
 	return nil
 }
 
@@ -218,6 +240,15 @@ func copyMain(cmd *cobra.Command, args []string) (err error) {
 
 	ctx := process.Ctx(cmd)
 
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+	// progress bars would be mixed with the results
+	if asJSON {
+		*progress = false
+	}
+
 	u0, err := utils.ParseURL(args[0])
 	if err != nil {
 		return err
@@ -0,0 +1,362 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/zeebo/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	diagnoseNodesFlag   *int
+	diagnoseSizeFlag    *int64
+	diagnoseBucketFlag  *string
+	diagnoseTimeoutFlag *time.Duration
+	diagnoseSkewFlag    *time.Duration
+)
+
+func init() {
+	diagnoseCmd := addCmd(&cobra.Command{
+		Use:   "diagnose",
+		Short: "Check the connectivity of the uplink to the satellite and storage nodes",
+		Long: "Checks that the satellite is reachable and accepts the API key, that the clock agrees with the " +
+			"satellite's, that sample storage nodes can be dialed, and measures the upload and download " +
+			"throughput of a test object, which is deleted afterwards. Prints what to do about every problem found.",
+		RunE: diagnose,
+	})
+	diagnoseNodesFlag = diagnoseCmd.Flags().Int("nodes", 5, "how many sample storage nodes to dial")
+	diagnoseSizeFlag = diagnoseCmd.Flags().Int64("size", 1<<20, "the size of the test object in bytes")
+	diagnoseBucketFlag = diagnoseCmd.Flags().String("bucket", "", "the bucket to upload the test object to. if empty, a temporary bucket is created")
+	diagnoseTimeoutFlag = diagnoseCmd.Flags().Duration("timeout", 10*time.Second, "how long to wait for each connection")
+	diagnoseSkewFlag = diagnoseCmd.Flags().Duration("max-clock-skew", time.Minute, "how far the clock may be off the satellite's")
+	addOutputFlag(diagnoseCmd)
+}
+
+// Statuses of the findings of diagnose
+const (
+	findingOK      = "ok"
+	findingWarning = "warning"
+	findingFailed  = "failed"
+	findingSkipped = "skipped"
+)
+
+// diagnosis collects the findings of diagnose
+type diagnosis struct {
+	asJSON   bool
+	findings []findingResult
+}
+
+// report prints a finding and records it
+func (d *diagnosis) report(check, result, advice, format string, args ...interface{}) {
+	finding := findingResult{Check: check, Status: result, Detail: fmt.Sprintf(format, args...), Advice: advice}
+	d.findings = append(d.findings, finding)
+	if d.asJSON {
+		_ = printJSON(finding)
+		return
+	}
+	fmt.Printf("%-8s %-12s %s\n", strings.ToUpper(finding.Status), finding.Check, finding.Detail)
+	if finding.Advice != "" {
+		fmt.Printf("%-8s %-12s -> %s\n", "", "", finding.Advice)
+	}
+}
+
+// failed returns how many checks failed
+func (d *diagnosis) failed() (n int) {
+	for _, finding := range d.findings {
+		if finding.Status == findingFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// diagnose is the function executed when diagnoseCmd is called
+func diagnose(cmd *cobra.Command, args []string) (err error) {
+	ctx := process.Ctx(cmd)
+
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+	d := &diagnosis{asJSON: asJSON}
+
+	if err := cfg.useAccess(); err != nil {
+		return err
+	}
+	identity, err := cfg.Load()
+	if err != nil {
+		d.report("identity", findingFailed, "create an identity with `uplink setup` or point --cert-path and --key-path to one",
+			"can't load the identity: %v", err)
+		return fmt.Errorf("%d checks failed", d.failed())
+	}
+
+	if !d.dialSatellite(ctx) {
+		d.report("api-key", findingSkipped, "", "the satellite is unreachable")
+		return fmt.Errorf("%d checks failed", d.failed())
+	}
+
+	bs, err := cfg.GetBucketStore(ctx, identity)
+	if err != nil {
+		return err
+	}
+	if !d.checkAPIKey(ctx, bs) {
+		return fmt.Errorf("%d checks failed", d.failed())
+	}
+
+	oc, err := overlay.NewOverlayClient(identity, cfg.OverlayAddr)
+	if err != nil {
+		return err
+	}
+	oc.APIKey = []byte(cfg.APIKey)
+	d.dialNodes(ctx, oc, transport.NewClientWithTimeout(identity, *diagnoseTimeoutFlag))
+
+	d.checkObjects(ctx, bs)
+
+	if n := d.failed(); n > 0 {
+		return fmt.Errorf("%d checks failed", n)
+	}
+	return nil
+}
+
+// dialSatellite checks that the addresses of the satellite accept
+// connections, and returns whether they all do
+func (d *diagnosis) dialSatellite(ctx context.Context) bool {
+	addrs := []string{cfg.PointerDBAddr}
+	if cfg.OverlayAddr != cfg.PointerDBAddr {
+		addrs = append(addrs, cfg.OverlayAddr)
+	}
+
+	reachable := true
+	for _, addr := range addrs {
+		if addr == "" {
+			d.report("satellite", findingFailed, "set --pointer-db-addr and --overlay-addr, or import an access with `uplink access import`",
+				"no satellite address is configured")
+			return false
+		}
+		start := time.Now()
+		conn, err := (&net.Dialer{Timeout: *diagnoseTimeoutFlag}).DialContext(ctx, "tcp", addr)
+		if err != nil {
+			reachable = false
+			advice := "check the address, and that outbound connections to its port aren't blocked by a firewall or proxy"
+			if opErr, ok := err.(*net.OpError); ok {
+				if _, ok := opErr.Err.(*net.DNSError); ok {
+					advice = "the host name doesn't resolve, check the address and the DNS configuration"
+				}
+			}
+			d.report("satellite", findingFailed, advice, "%s is unreachable: %v", addr, err)
+			continue
+		}
+		_ = conn.Close()
+		d.report("satellite", findingOK, "", "%s is reachable in %s", addr, time.Since(start).Round(time.Millisecond))
+	}
+	return reachable
+}
+
+// checkAPIKey checks that the satellite accepts the API key by listing a
+// bucket, and returns whether it does
+func (d *diagnosis) checkAPIKey(ctx context.Context, bs buckets.Store) bool {
+	_, _, err := bs.List(ctx, "", "", 1)
+	switch statusCode(err) {
+	case codes.OK:
+		d.report("api-key", findingOK, "", "the API key is accepted")
+		return true
+	case codes.Unauthenticated:
+		d.report("api-key", findingFailed, "check --api-key, or create a new key on the satellite's console",
+			"the API key is rejected: %v", err)
+	case codes.PermissionDenied:
+		d.report("api-key", findingFailed, "the key may be restricted or expired; inspect it with `uplink access inspect`",
+			"the API key may not list buckets: %v", err)
+	default:
+		d.report("api-key", findingFailed, "the satellite is reachable but failed the request; check that the address is of a satellite and try again later",
+			"listing buckets failed: %v", err)
+	}
+	return false
+}
+
+// statusCode returns the gRPC status code of err, which may be wrapped
+func statusCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	code := codes.Unknown
+	errs.IsFunc(err, func(err error) bool {
+		code = status.Code(err)
+		return code != codes.Unknown
+	})
+	return code
+}
+
+// dialNodes dials sample storage nodes the satellite would choose for an
+// upload, which fails when outbound connections are blocked
+func (d *diagnosis) dialNodes(ctx context.Context, oc *overlay.Overlay, tc *transport.Transport) {
+	nodes, err := oc.Choose(ctx, *diagnoseNodesFlag, 0, "")
+	if err != nil {
+		d.report("nodes", findingFailed, "the satellite may not have enough storage nodes online, try again later",
+			"can't get sample storage nodes: %v", err)
+		return
+	}
+
+	var reachable int
+	var firstErr error
+	for _, node := range nodes {
+		dialCtx, cancel := context.WithTimeout(ctx, *diagnoseTimeoutFlag)
+		conn, err := tc.DialNode(dialCtx, node)
+		cancel()
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		_ = conn.Close()
+		reachable++
+	}
+
+	switch {
+	case len(nodes) == 0:
+		d.report("nodes", findingFailed, "the satellite may not have enough storage nodes online, try again later",
+			"the satellite returned no storage nodes")
+	case reachable == 0:
+		d.report("nodes", findingFailed, "outbound connections to storage nodes seem blocked: allow outgoing TCP connections to any port, or run the uplink outside the restricted network",
+			"none of %d storage nodes is reachable: %v", len(nodes), firstErr)
+	case reachable*2 < len(nodes):
+		d.report("nodes", findingWarning, "some networks only allow outgoing connections to common ports, which many storage nodes don't listen on",
+			"%d of %d storage nodes are reachable: %v", reachable, len(nodes), firstErr)
+	default:
+		d.report("nodes", findingOK, "", "%d of %d storage nodes are reachable", reachable, len(nodes))
+	}
+}
+
+// checkObjects uploads a tiny inline object to compare the clock with the
+// satellite's, and a test object to measure the throughput. They're deleted
+// afterwards, as is the temporary bucket they were uploaded to.
+func (d *diagnosis) checkObjects(ctx context.Context, bs buckets.Store) {
+	bucket := *diagnoseBucketFlag
+	if bucket == "" {
+		var suffix [4]byte
+		if _, err := rand.Read(suffix[:]); err != nil {
+			d.report("clock", findingSkipped, "", "can't name a temporary bucket: %v", err)
+			return
+		}
+		bucket = "diagnose-" + hex.EncodeToString(suffix[:])
+		if _, err := bs.Put(ctx, bucket, buckets.Defaults{PartnerID: cfg.PartnerID}); err != nil {
+			d.report("clock", findingSkipped, "the API key may not create buckets, pass an existing one with --bucket",
+				"can't create a temporary bucket: %v", err)
+			return
+		}
+		defer func() { _ = bs.Delete(ctx, bucket) }()
+	}
+	o, err := bs.GetObjectStore(ctx, bucket)
+	if err != nil {
+		d.report("clock", findingSkipped, "", "can't open bucket %s: %v", bucket, err)
+		return
+	}
+
+	d.checkClock(ctx, o)
+	d.measureThroughput(ctx, o)
+}
+
+// checkClock compares the clock with the satellite's by the creation date
+// the satellite gives a tiny inline object, which is committed in a single
+// round trip
+func (d *diagnosis) checkClock(ctx context.Context, o objects.Store) {
+	path := paths.New("diagnose", "clock")
+	start := time.Now()
+	m, err := o.Put(ctx, path, bytes.NewReader([]byte("clock")), objects.SerializableMeta{}, time.Time{})
+	elapsed := time.Since(start)
+	if err != nil {
+		d.report("clock", findingFailed, "the API key may not upload, check its restrictions with `uplink access inspect`",
+			"can't upload a test object: %v", err)
+		return
+	}
+	defer func() { _ = o.Delete(ctx, path) }()
+
+	// the object was created while the request was in flight
+	skew := m.Modified.Sub(start.Add(elapsed / 2))
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > *diagnoseSkewFlag+elapsed/2 {
+		d.report("clock", findingFailed, "synchronize the clock with NTP; order limits and API key restrictions are checked against the time",
+			"the clock is about %s off the satellite's", skew.Round(time.Second))
+		return
+	}
+	d.report("clock", findingOK, "", "the clock agrees with the satellite's within %s", (skew + elapsed/2).Round(time.Millisecond))
+}
+
+// measureThroughput uploads and downloads a test object of random data
+func (d *diagnosis) measureThroughput(ctx context.Context, o objects.Store) {
+	size := *diagnoseSizeFlag
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		d.report("upload", findingSkipped, "", "can't generate test data: %v", err)
+		return
+	}
+
+	path := paths.New("diagnose", "throughput")
+	start := time.Now()
+	_, err := o.Put(ctx, path, bytes.NewReader(data), objects.SerializableMeta{}, time.Time{})
+	if err != nil {
+		d.report("upload", findingFailed, "uploads need enough reachable storage nodes for the success threshold; see the nodes check",
+			"can't upload a %d byte test object: %v", size, err)
+		return
+	}
+	defer func() { _ = o.Delete(ctx, path) }()
+	d.report("upload", findingOK, "", "%s", throughput(size, time.Since(start)))
+
+	start = time.Now()
+	rr, _, err := o.Get(ctx, path)
+	if err != nil {
+		d.report("download", findingFailed, "", "can't download the test object: %v", err)
+		return
+	}
+	r, err := rr.Range(ctx, 0, rr.Size())
+	if err != nil {
+		d.report("download", findingFailed, "", "can't download the test object: %v", err)
+		return
+	}
+	var downloaded bytes.Buffer
+	_, err = io.Copy(&downloaded, r)
+	err = utils.CombineErrors(err, r.Close())
+	if err != nil {
+		d.report("download", findingFailed, "downloads need enough reachable storage nodes to reconstruct segments; see the nodes check",
+			"can't download the test object: %v", err)
+		return
+	}
+	if !bytes.Equal(downloaded.Bytes(), data) {
+		d.report("download", findingFailed, "report this to the satellite operator",
+			"the downloaded test object differs from the uploaded one")
+		return
+	}
+	d.report("download", findingOK, "", "%s", throughput(size, time.Since(start)))
+}
+
+// throughput describes transferring size bytes in elapsed
+func throughput(size int64, elapsed time.Duration) string {
+	if elapsed <= 0 {
+		elapsed = time.Nanosecond
+	}
+	return fmt.Sprintf("%d bytes in %s, %.2f MiB/s", size, elapsed.Round(time.Millisecond),
+		float64(size)/elapsed.Seconds()/(1<<20))
+}
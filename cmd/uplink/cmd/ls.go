@@ -15,6 +15,7 @@ import (
 	"storj.io/storj/pkg/process"
 	"storj.io/storj/pkg/storage/buckets"
 	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
 	"storj.io/storj/pkg/utils"
 )
 
@@ -29,11 +30,17 @@ func init() {
 		RunE:  list,
 	})
 	recursiveFlag = lsCmd.Flags().Bool("recursive", false, "if true, list recursively")
+	addOutputFlag(lsCmd)
 }
 
 func list(cmd *cobra.Command, args []string) error {
 	ctx := process.Ctx(cmd)
 
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+
 	bs, err := cfg.BucketStore(ctx)
 	if err != nil {
 		return err
@@ -48,7 +55,7 @@ func list(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("No bucket specified. Please use format sj://bucket/")
 		}
 
-		return listFiles(ctx, bs, u, false)
+		return listFiles(ctx, bs, u, false, asJSON)
 	}
 
 	startAfter := ""
@@ -62,9 +69,16 @@ func list(cmd *cobra.Command, args []string) error {
 		if len(items) > 0 {
 			noBuckets = false
 			for _, bucket := range items {
-				fmt.Println("BKT", formatTime(bucket.Meta.Created), bucket.Bucket)
+				if asJSON {
+					err = printJSON(listResult{Kind: "bucket", Bucket: bucket.Bucket, Created: optionalTime(bucket.Meta.Created)})
+					if err != nil {
+						return err
+					}
+				} else {
+					fmt.Println("BKT", formatTime(bucket.Meta.Created), bucket.Bucket)
+				}
 				if *recursiveFlag {
-					err := listFiles(ctx, bs, &url.URL{Host: bucket.Bucket, Path: "/"}, true)
+					err := listFiles(ctx, bs, &url.URL{Host: bucket.Bucket, Path: "/"}, true, asJSON)
 					if err != nil {
 						return err
 					}
@@ -77,14 +91,14 @@ func list(cmd *cobra.Command, args []string) error {
 		startAfter = items[len(items)-1].Bucket
 	}
 
-	if noBuckets {
+	if noBuckets && !asJSON {
 		fmt.Println("No buckets")
 	}
 
 	return nil
 }
 
-func listFiles(ctx context.Context, bs buckets.Store, u *url.URL, prependBucket bool) error {
+func listFiles(ctx context.Context, bs buckets.Store, u *url.URL, prependBucket, asJSON bool) error {
 	o, err := bs.GetObjectStore(ctx, u.Host)
 	if err != nil {
 		return err
@@ -99,6 +113,13 @@ func listFiles(ctx context.Context, bs buckets.Store, u *url.URL, prependBucket
 		}
 
 		for _, object := range items {
+			if asJSON {
+				if err := printJSON(listItemResult(u.Host, object)); err != nil {
+					return err
+				}
+				continue
+			}
+
 			path := object.Path.String()
 			if prependBucket {
 				path = fmt.Sprintf("%s/%s", u.Host, path)
@@ -120,6 +141,21 @@ func listFiles(ctx context.Context, bs buckets.Store, u *url.URL, prependBucket
 	return nil
 }
 
+// listItemResult returns the JSON result of object listed in bucket
+func listItemResult(bucket string, object objects.ListItem) listResult {
+	if object.IsPrefix {
+		return listResult{Kind: "prefix", Bucket: bucket, Path: object.Path.String() + "/"}
+	}
+	size := object.Meta.Size
+	return listResult{
+		Kind:     "object",
+		Bucket:   bucket,
+		Path:     object.Path.String(),
+		Modified: optionalTime(object.Meta.Modified),
+		Size:     &size,
+	}
+}
+
 func formatTime(t time.Time) string {
 	return t.Local().Format("2006-01-02 15:04:05")
 }
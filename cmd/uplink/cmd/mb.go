@@ -8,17 +8,64 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storage/buckets"
 	"storj.io/storj/pkg/utils"
 	"storj.io/storj/storage"
 )
 
+var (
+	rsMinFlag       *int
+	rsRepairFlag    *int
+	rsSuccessFlag   *int
+	rsTotalFlag     *int
+	rsShareSizeFlag *int
+
+	encCipherFlag    *string
+	encBlockSizeFlag *int
+)
+
 func init() {
-	addCmd(&cobra.Command{
+	mbCmd := addCmd(&cobra.Command{
 		Use:   "mb",
 		Short: "Create a new bucket",
 		RunE:  makeBucket,
 	})
+	rsMinFlag = mbCmd.Flags().Int("rs.min", 0, "the minimum pieces required to recover a segment of the bucket. if 0, the uplink default is used")
+	rsRepairFlag = mbCmd.Flags().Int("rs.repair", 0, "the minimum safe pieces before a segment of the bucket is repaired")
+	rsSuccessFlag = mbCmd.Flags().Int("rs.success", 0, "the desired total pieces for a segment of the bucket")
+	rsTotalFlag = mbCmd.Flags().Int("rs.total", 0, "the largest amount of pieces to encode a segment of the bucket to")
+	rsShareSizeFlag = mbCmd.Flags().Int("rs.share-size", 1024, "the size of each erasure share of the bucket in bytes")
+	encCipherFlag = mbCmd.Flags().String("enc.cipher", "", "the cipher of the objects of the bucket: aesgcm, aesgcmsiv or secretbox. if empty, the uplink default is used")
+	encBlockSizeFlag = mbCmd.Flags().Int("enc.block-size", 1024, "the size of the blocks the objects of the bucket are encrypted in")
+}
+
+// bucketDefaults returns the bucket defaults given on the command line
+func bucketDefaults() (buckets.Defaults, error) {
+	defaults := buckets.Defaults{PartnerID: cfg.PartnerID}
+	if *encCipherFlag != "" {
+		cipher, err := buckets.ParseCipher(*encCipherFlag)
+		if err != nil {
+			return defaults, err
+		}
+		defaults.Encryption = &buckets.EncryptionScheme{Cipher: cipher, BlockSize: *encBlockSizeFlag}
+	}
+	if *rsMinFlag == 0 {
+		if *rsRepairFlag != 0 || *rsSuccessFlag != 0 || *rsTotalFlag != 0 {
+			return defaults, fmt.Errorf("rs.min is required to set the redundancy scheme of the bucket")
+		}
+		return defaults, defaults.Validate()
+	}
+	defaults.Redundancy = &pb.RedundancyScheme{
+		Type:             pb.RedundancyScheme_RS,
+		MinReq:           int32(*rsMinFlag),
+		Total:            int32(*rsTotalFlag),
+		RepairThreshold:  int32(*rsRepairFlag),
+		SuccessThreshold: int32(*rsSuccessFlag),
+		ErasureShareSize: int32(*rsShareSizeFlag),
+	}
+	return defaults, defaults.Validate()
 }
 
 func makeBucket(cmd *cobra.Command, args []string) error {
@@ -36,6 +83,11 @@ func makeBucket(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("No bucket specified. Please use format sj://bucket/")
 	}
 
+	defaults, err := bucketDefaults()
+	if err != nil {
+		return err
+	}
+
 	bs, err := cfg.BucketStore(ctx)
 	if err != nil {
 		return err
@@ -48,7 +100,7 @@ func makeBucket(cmd *cobra.Command, args []string) error {
 	if !storage.ErrKeyNotFound.Has(err) {
 		return err
 	}
-	_, err = bs.Put(ctx, u.Host)
+	_, err = bs.Put(ctx, u.Host, defaults)
 	if err != nil {
 		return err
 	}
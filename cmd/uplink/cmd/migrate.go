@@ -0,0 +1,83 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/migrate"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storage/buckets"
+)
+
+var (
+	migrateManifest *string
+	migrateVerify   *bool
+)
+
+func init() {
+	migrateCmd := addCmd(&cobra.Command{
+		Use:   "migrate SOURCE DESTINATION [BUCKET...]",
+		Short: "Copies the buckets and objects of the access SOURCE to the access DESTINATION, usually of another satellite",
+		Args:  cobra.MinimumNArgs(2),
+		RunE:  migrateMain,
+	})
+	migrateManifest = migrateCmd.Flags().String("manifest", "migration.json",
+		"the manifest of the migrated objects. an interrupted migration is resumed from it")
+	migrateVerify = migrateCmd.Flags().Bool("verify", true,
+		"if true, the migrated objects are downloaded again and compared with the source")
+}
+
+// bucketStoreOf returns the bucket store of the named access
+func bucketStoreOf(ctx context.Context, access string) (buckets.Store, error) {
+	c := cfg
+	c.Access = access
+	return c.BucketStore(ctx)
+}
+
+// migrateMain is the function executed when migrateCmd is called
+func migrateMain(cmd *cobra.Command, args []string) error {
+	ctx := process.Ctx(cmd)
+
+	source, err := bucketStoreOf(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	dest, err := bucketStoreOf(ctx, args[1])
+	if err != nil {
+		return err
+	}
+
+	manifest, err := migrate.LoadManifest(*migrateManifest, args[0], args[1])
+	if err != nil {
+		return err
+	}
+
+	migrator := migrate.NewMigrator(zap.L(), source, dest, manifest)
+	stats, err := migrator.Migrate(ctx, args[2:])
+	fmt.Printf("Migrated %d objects (%d bytes) of %d buckets, skipped %d\n",
+		stats.Migrated, stats.Bytes, stats.Buckets, stats.Skipped)
+	if err != nil {
+		return err
+	}
+
+	if *migrateVerify {
+		mismatched, err := migrator.Verify(ctx)
+		if err != nil {
+			return err
+		}
+		for _, key := range mismatched {
+			fmt.Printf("Mismatched %s\n", key)
+		}
+		if len(mismatched) > 0 {
+			return migrate.Error.New("%d migrated objects don't match the source", len(mismatched))
+		}
+		fmt.Println("Verified the migrated objects")
+	}
+	return nil
+}
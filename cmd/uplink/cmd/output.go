@@ -0,0 +1,143 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// outputFormat is the format the results of the commands with an --output
+// flag are printed in
+var outputFormat string
+
+// addOutputFlag adds the --output flag to cmd
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&outputFormat, "output", "text", `format of the results, "text" or "json". json prints every result as a line of JSON`)
+}
+
+// jsonOutput returns whether results are printed as JSON
+func jsonOutput() (bool, error) {
+	switch outputFormat {
+	case "", "text":
+		return false, nil
+	case "json":
+		return true, nil
+	default:
+		return false, fmt.Errorf("Invalid output format %q. Please use text or json", outputFormat)
+	}
+}
+
+// printJSON prints v as a line of JSON
+func printJSON(v interface{}) error {
+	return json.NewEncoder(os.Stdout).Encode(v)
+}
+
+// The results printed with --output json. Fields are only ever added to
+// them, so that scripts parsing the output keep working.
+
+// listResult is a bucket, prefix or object listed by ls
+type listResult struct {
+	// Kind is "bucket", "prefix" or "object"
+	Kind   string `json:"kind"`
+	Bucket string `json:"bucket"`
+	// Path is empty for buckets, and ends with a slash for prefixes
+	Path     string     `json:"path,omitempty"`
+	Created  *time.Time `json:"created,omitempty"`
+	Modified *time.Time `json:"modified,omitempty"`
+	Size     *int64     `json:"size,omitempty"`
+}
+
+// statResult is an object described by stat
+type statResult struct {
+	Bucket      string            `json:"bucket"`
+	Path        string            `json:"path"`
+	Size        int64             `json:"size"`
+	Modified    *time.Time        `json:"modified,omitempty"`
+	Expiration  *time.Time        `json:"expiration,omitempty"`
+	ContentType string            `json:"content_type,omitempty"`
+	Checksum    string            `json:"checksum,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	// Created is when the last segment of the object was committed
+	Created *time.Time `json:"created,omitempty"`
+	// MetadataKeys are the sorted keys of the metadata, as the satellite
+	// has them
+	MetadataKeys []string        `json:"metadata_keys"`
+	Segments     []segmentResult `json:"segments"`
+}
+
+// segmentResult is a segment of an object described by stat
+type segmentResult struct {
+	// Type is "inline" or "remote"
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+	// Redundancy and Pieces are only set for remote segments
+	Redundancy *redundancyResult `json:"redundancy,omitempty"`
+	Pieces     int32             `json:"pieces,omitempty"`
+}
+
+// redundancyResult is the erasure coding of a remote segment
+type redundancyResult struct {
+	MinReq           int32 `json:"min_req"`
+	RepairThreshold  int32 `json:"repair_threshold"`
+	SuccessThreshold int32 `json:"success_threshold"`
+	Total            int32 `json:"total"`
+	ShareSize        int32 `json:"share_size"`
+}
+
+// copyResult is a file or object copied by cp or put
+type copyResult struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	Size        int64  `json:"size"`
+}
+
+// accessResult is a named access described by access inspect. The API key
+// isn't included, export the access to share it.
+type accessResult struct {
+	Name          string `json:"name"`
+	Current       bool   `json:"current"`
+	OverlayAddr   string `json:"overlay_addr"`
+	PointerDBAddr string `json:"pointer_db_addr"`
+	ProjectID     string `json:"project_id"`
+	// Macaroon is whether the API key is a macaroon, which can be restricted
+	Macaroon     bool                `json:"macaroon"`
+	Restrictions []accessRestriction `json:"restrictions"`
+}
+
+// accessRestriction is a restriction of the API key of an access. An access
+// is restricted by all of its restrictions at once.
+type accessRestriction struct {
+	DisallowReads   bool       `json:"disallow_reads,omitempty"`
+	DisallowWrites  bool       `json:"disallow_writes,omitempty"`
+	DisallowLists   bool       `json:"disallow_lists,omitempty"`
+	DisallowDeletes bool       `json:"disallow_deletes,omitempty"`
+	Prefixes        []string   `json:"prefixes,omitempty"`
+	NotBefore       *time.Time `json:"not_before,omitempty"`
+	NotAfter        *time.Time `json:"not_after,omitempty"`
+}
+
+// findingResult is a check made by diagnose
+type findingResult struct {
+	Check string `json:"check"`
+	// Status is "ok", "warning", "failed" or "skipped"
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	// Advice is what to do about the problem found, if any
+	Advice string `json:"advice,omitempty"`
+}
+
+// optionalTime returns nil for the zero time, so that it's left out of the
+// JSON results
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	t = t.UTC()
+	return &t
+}
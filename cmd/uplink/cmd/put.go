@@ -13,11 +13,12 @@ import (
 )
 
 func init() {
-	addCmd(&cobra.Command{
+	putCmd := addCmd(&cobra.Command{
 		Use:   "put",
 		Short: "Copies data from standard in to a Storj object",
 		RunE:  putMain,
 	})
+	addOutputFlag(putCmd)
 }
 
 // putMain is the function executed when putCmd is called
@@ -28,6 +29,14 @@ func putMain(cmd *cobra.Command, args []string) (err error) {
 
 	ctx := process.Ctx(cmd)
 
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+	if asJSON {
+		*progress = false
+	}
+
 	u0, err := utils.ParseURL(args[0])
 	if err != nil {
 		return err
@@ -9,8 +9,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"storj.io/storj/pkg/accesses"
 	"storj.io/storj/pkg/cfgstruct"
 	"storj.io/storj/pkg/miniogw"
+	"storj.io/storj/pkg/pointerdb/pdbclient"
 	"storj.io/storj/pkg/storage/buckets"
 )
 
@@ -19,6 +21,9 @@ const defaultConfDir = "$HOME/.storj/uplink"
 // Config is miniogw.Config configuration
 type Config struct {
 	miniogw.Config
+
+	Access       string `help:"the name of the access to use instead of the addresses and API key of the config. if empty, the access in use is used" default:""`
+	AccessesPath string `help:"path to the encrypted file of the named accesses" default:"$CONFDIR/accesses"`
 }
 
 var cfg Config
@@ -30,7 +35,13 @@ var RootCmd = &cobra.Command{
 }
 
 func addCmd(cmd *cobra.Command) *cobra.Command {
-	RootCmd.AddCommand(cmd)
+	return addSubCmd(RootCmd, cmd)
+}
+
+// addSubCmd adds cmd to parent and binds the uplink configuration to its
+// flags
+func addSubCmd(parent, cmd *cobra.Command) *cobra.Command {
+	parent.AddCommand(cmd)
 	cfgstruct.Bind(cmd.Flags(), &cfg, cfgstruct.ConfDir(defaultConfDir))
 	cmd.Flags().String("config", filepath.Join(defaultConfDir, "config.yaml"), "path to configuration")
 	return cmd
@@ -38,6 +49,10 @@ func addCmd(cmd *cobra.Command) *cobra.Command {
 
 // BucketStore loads the buckets.Store
 func (c *Config) BucketStore(ctx context.Context) (buckets.Store, error) {
+	if err := c.useAccess(); err != nil {
+		return nil, err
+	}
+
 	identity, err := c.Load()
 	if err != nil {
 		return nil, err
@@ -45,3 +60,49 @@ func (c *Config) BucketStore(ctx context.Context) (buckets.Store, error) {
 
 	return c.GetBucketStore(ctx, identity)
 }
+
+// PointerDBClient returns a client of the pointerdb of the access in use
+func (c *Config) PointerDBClient() (*pdbclient.PointerDB, error) {
+	if err := c.useAccess(); err != nil {
+		return nil, err
+	}
+
+	identity, err := c.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return pdbclient.NewClient(identity, c.PointerDBAddr, []byte(c.APIKey))
+}
+
+// AccessStore returns the store of the named accesses
+func (c *Config) AccessStore() *accesses.Store {
+	return accesses.NewStore(c.AccessesPath,
+		accesses.DefaultKeychain(c.AccessesPath, c.AccessesPath+".key"))
+}
+
+// useAccess replaces the addresses and API key of the config with the ones of
+// the selected access, or the access in use if none is selected
+func (c *Config) useAccess() error {
+	named, err := c.AccessStore().Load()
+	if err != nil {
+		return err
+	}
+
+	name := c.Access
+	if name == "" {
+		name = named.Current
+	}
+	if name == "" {
+		return nil
+	}
+
+	access, ok := named.Get(name)
+	if !ok {
+		return accesses.Error.New("no access called %q", name)
+	}
+	c.OverlayAddr = access.OverlayAddr
+	c.PointerDBAddr = access.PointerDBAddr
+	c.APIKey = access.APIKey
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/utils"
+)
+
+func init() {
+	statCmd := addCmd(&cobra.Command{
+		Use:   "stat",
+		Short: "Describes a Storj object and its segments without downloading it",
+		RunE:  statMain,
+	})
+	addOutputFlag(statCmd)
+}
+
+// statMain is the function executed when statCmd is called
+func statMain(cmd *cobra.Command, args []string) (err error) {
+	if len(args) == 0 {
+		return fmt.Errorf("No object specified")
+	}
+
+	ctx := process.Ctx(cmd)
+
+	asJSON, err := jsonOutput()
+	if err != nil {
+		return err
+	}
+
+	u, err := utils.ParseURL(args[0])
+	if err != nil {
+		return err
+	}
+	if u.Host == "" {
+		return fmt.Errorf("No bucket specified. Please use format sj://bucket/")
+	}
+
+	bs, err := cfg.BucketStore(ctx)
+	if err != nil {
+		return err
+	}
+	o, err := bs.GetObjectStore(ctx, u.Host)
+	if err != nil {
+		return err
+	}
+
+	path := paths.New(u.Path)
+	m, err := o.Meta(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	pdb, err := cfg.PointerDBClient()
+	if err != nil {
+		return err
+	}
+	info, err := pdb.GetObjectInfo(ctx, path.Prepend(u.Host))
+	if err != nil {
+		return err
+	}
+
+	result := statResult{
+		Bucket:      u.Host,
+		Path:        path.String(),
+		Size:        m.Size,
+		Modified:    optionalTime(m.Modified),
+		Expiration:  optionalTime(m.Expiration),
+		ContentType: m.ContentType,
+		Checksum:    m.Checksum,
+		Metadata:    m.UserDefined,
+		// the keys are never nil, so that scripts don't have to check
+		MetadataKeys: append([]string{}, info.GetMetadataKeys()...),
+	}
+	if created, err := ptypes.Timestamp(info.GetCreationDate()); err == nil {
+		result.Created = optionalTime(created)
+	}
+	for _, segment := range info.GetSegments() {
+		result.Segments = append(result.Segments, segmentStat(segment))
+	}
+	if asJSON {
+		return printJSON(result)
+	}
+
+	fmt.Printf("Path:         sj://%s/%s\n", result.Bucket, result.Path)
+	fmt.Printf("Size:         %d\n", result.Size)
+	fmt.Printf("Modified:     %s\n", formatTime(m.Modified))
+	if result.Created != nil {
+		fmt.Printf("Created:      %s\n", formatTime(*result.Created))
+	}
+	if !m.Expiration.IsZero() {
+		fmt.Printf("Expiration:   %s\n", formatTime(m.Expiration))
+	}
+	if result.ContentType != "" {
+		fmt.Printf("Content-Type: %s\n", result.ContentType)
+	}
+	if result.Checksum != "" {
+		fmt.Printf("Checksum:     %s\n", result.Checksum)
+	}
+	for _, key := range result.MetadataKeys {
+		fmt.Printf("Metadata:     %s\n", key)
+	}
+	fmt.Printf("Segments:     %d\n", len(result.Segments))
+	for i, segment := range result.Segments {
+		if segment.Redundancy == nil {
+			fmt.Printf("  %4d %-6s %12d\n", i, segment.Type, segment.Size)
+			continue
+		}
+		rs := segment.Redundancy
+		fmt.Printf("  %4d %-6s %12d  rs %d/%d/%d/%d  share %d  pieces %d\n", i, segment.Type, segment.Size,
+			rs.MinReq, rs.RepairThreshold, rs.SuccessThreshold, rs.Total, rs.ShareSize, segment.Pieces)
+	}
+	return nil
+}
+
+// segmentStat returns the result of a segment described by the satellite
+func segmentStat(segment *pb.SegmentInfo) segmentResult {
+	result := segmentResult{Type: "inline", Size: segment.GetSize()}
+	if segment.GetType() == pb.Pointer_REMOTE {
+		result.Type = "remote"
+		result.Pieces = segment.GetPieces()
+	}
+	if rs := segment.GetRedundancy(); rs != nil {
+		result.Redundancy = &redundancyResult{
+			MinReq:           rs.GetMinReq(),
+			RepairThreshold:  rs.GetRepairThreshold(),
+			SuccessThreshold: rs.GetSuccessThreshold(),
+			Total:            rs.GetTotal(),
+			ShareSize:        rs.GetErasureShareSize(),
+		}
+	}
+	return result
+}
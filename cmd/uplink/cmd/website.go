@@ -0,0 +1,88 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	websiteIndex     *string
+	websiteError     *string
+	websiteRedirects *[]string
+	websiteDisable   *bool
+)
+
+func init() {
+	websiteCmd := addCmd(&cobra.Command{
+		Use:   "website sj://BUCKET",
+		Short: "Configure a bucket to be served as a static website by the gateway",
+		Args:  cobra.ExactArgs(1),
+		RunE:  configureWebsite,
+	})
+	websiteIndex = websiteCmd.Flags().String("index", "index.html", "the object served for paths ending with a slash")
+	websiteError = websiteCmd.Flags().String("error", "", "the object served with status 404 for missing objects")
+	websiteRedirects = websiteCmd.Flags().StringSlice("redirect", nil,
+		"a PREFIX=REPLACEMENT rule redirecting the paths starting with PREFIX. may be repeated")
+	websiteDisable = websiteCmd.Flags().Bool("disable", false, "stop serving the bucket as a website")
+}
+
+// configureWebsite is the function executed when websiteCmd is called
+func configureWebsite(cmd *cobra.Command, args []string) error {
+	ctx := process.Ctx(cmd)
+
+	u, err := utils.ParseURL(args[0])
+	if err != nil {
+		return err
+	}
+	if u.Host == "" {
+		return fmt.Errorf("No bucket specified. Please use format sj://bucket/")
+	}
+
+	var web *buckets.Website
+	if !*websiteDisable {
+		web = &buckets.Website{IndexDocument: *websiteIndex, ErrorDocument: *websiteError}
+		for _, rule := range *websiteRedirects {
+			parts := strings.SplitN(rule, "=", 2)
+			if len(parts) != 2 || parts[0] == "" {
+				return fmt.Errorf("Invalid redirect %q. Please use format PREFIX=REPLACEMENT", rule)
+			}
+			if web.Redirects == nil {
+				web.Redirects = map[string]string{}
+			}
+			web.Redirects[parts[0]] = parts[1]
+		}
+	}
+
+	bs, err := cfg.BucketStore(ctx)
+	if err != nil {
+		return err
+	}
+	meta, err := bs.Get(ctx, u.Host)
+	if err != nil {
+		return err
+	}
+
+	// the bucket's settings are stored with it, so they're replaced by
+	// putting the bucket again
+	defaults := meta.Defaults
+	defaults.Website = web
+	if _, err := bs.Put(ctx, u.Host, defaults); err != nil {
+		return err
+	}
+
+	if web == nil {
+		fmt.Printf("Bucket %s is no longer a website\n", u.Host)
+	} else {
+		fmt.Printf("Bucket %s is a website\n", u.Host)
+	}
+	return nil
+}
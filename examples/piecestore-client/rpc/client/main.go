@@ -177,7 +177,7 @@ func main() {
 				if c.Args().Get(0) == "" {
 					return argError.New("Missing data Id")
 				}
-				err = psClient.Delete(context.Background(), client.PieceID(c.Args().Get(0)))
+				err = psClient.Delete(context.Background(), client.PieceID(c.Args().Get(0)), nil)
 
 				return err
 			},
@@ -37,7 +37,7 @@ require (
 	github.com/garyburd/redigo v1.0.1-0.20170216214944-0d253a66e6e1 // indirect
 	github.com/go-ini/ini v1.38.1 // indirect
 	github.com/go-redis/redis v0.0.0-20180417061816-9ccc23344a52
-	github.com/go-sql-driver/mysql v1.4.0 // indirect
+	github.com/go-sql-driver/mysql v1.4.0
 	github.com/gogo/protobuf v1.0.0
 	github.com/golang/glog v0.0.0-20160126235308-23def4e6c14b // indirect
 	github.com/golang/mock v1.1.1
@@ -72,7 +72,7 @@ require (
 	github.com/klauspost/pgzip v1.0.1 // indirect
 	github.com/klauspost/reedsolomon v0.0.0-20180704173009-925cb01d6510 // indirect
 	github.com/kurin/blazer v0.5.1 // indirect
-	github.com/lib/pq v0.0.0-20180523175426-90697d60dd84 // indirect
+	github.com/lib/pq v0.0.0-20180523175426-90697d60dd84
 	github.com/loov/hrtime v0.0.0-20180911122900-a9e82bc6c180
 	github.com/loov/plot v0.0.0-20180510142208-e59891ae1271
 	github.com/magiconair/properties v1.7.6 // indirect
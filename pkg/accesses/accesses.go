@@ -0,0 +1,63 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesses
+
+import (
+	"sort"
+)
+
+// Access is everything an uplink needs to access the buckets of a project on
+// a satellite
+type Access struct {
+	OverlayAddr   string `json:"overlay_addr"`
+	PointerDBAddr string `json:"pointer_db_addr"`
+	APIKey        string `json:"api_key"`
+}
+
+// Accesses are the named accesses of an uplink, and the one in use
+type Accesses struct {
+	Current string            `json:"current"`
+	Named   map[string]Access `json:"named"`
+}
+
+// Get returns the access called name
+func (a *Accesses) Get(name string) (Access, bool) {
+	access, ok := a.Named[name]
+	return access, ok
+}
+
+// Add adds an access called name. Existing accesses are only replaced if
+// overwrite is true.
+func (a *Accesses) Add(name string, access Access, overwrite bool) error {
+	if name == "" {
+		return Error.New("access name is empty")
+	}
+	if _, ok := a.Named[name]; ok && !overwrite {
+		return Error.New("access %q already exists", name)
+	}
+	if a.Named == nil {
+		a.Named = map[string]Access{}
+	}
+	a.Named[name] = access
+	return nil
+}
+
+// Use makes the access called name the current one
+func (a *Accesses) Use(name string) error {
+	if _, ok := a.Named[name]; !ok {
+		return Error.New("no access called %q", name)
+	}
+	a.Current = name
+	return nil
+}
+
+// Names returns the sorted names of the accesses
+func (a *Accesses) Names() []string {
+	names := make([]string, 0, len(a.Named))
+	for name := range a.Named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesses
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/gtank/cryptopasta"
+)
+
+// keychainService is the service the key of the accesses is stored under in
+// OS keychains
+const keychainService = "storj-uplink"
+
+// Keychain keeps the key the accesses are encrypted with
+type Keychain interface {
+	// Key returns the key, creating it if there is none yet
+	Key() (*[32]byte, error)
+}
+
+// DefaultKeychain returns the keychain of the OS where one is available, and
+// otherwise a FileKeychain keeping the key in the file at keyPath. Keys in OS
+// keychains are stored for the account account.
+func DefaultKeychain(account, keyPath string) Keychain {
+	switch runtime.GOOS {
+	case "darwin":
+		if _, err := exec.LookPath("security"); err == nil {
+			return &commandKeychain{lookup: macLookup(account), store: macStore(account)}
+		}
+	case "linux", "freebsd", "openbsd":
+		if _, err := exec.LookPath("secret-tool"); err == nil {
+			return &commandKeychain{lookup: secretToolLookup(account), store: secretToolStore(account)}
+		}
+	}
+	return FileKeychain(keyPath)
+}
+
+// FileKeychain keeps the key in a file only readable by the user
+type FileKeychain string
+
+// Key implements Keychain
+func (path FileKeychain) Key() (*[32]byte, error) {
+	encoded, err := ioutil.ReadFile(string(path))
+	if err == nil {
+		return decodeKey(encoded)
+	}
+	if !os.IsNotExist(err) {
+		return nil, Error.Wrap(err)
+	}
+
+	key := cryptopasta.NewEncryptionKey()
+	if err := os.MkdirAll(filepath.Dir(string(path)), 0700); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if err := ioutil.WriteFile(string(path), encodeKey(key), 0600); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return key, nil
+}
+
+// commandKeychain keeps the key in an OS keychain through its command line
+// tool
+type commandKeychain struct {
+	lookup func() *exec.Cmd
+	store  func(encoded []byte) *exec.Cmd
+}
+
+// Key implements Keychain
+func (k *commandKeychain) Key() (*[32]byte, error) {
+	// the tools fail for missing keys and all other errors alike, so any
+	// failed lookup creates a key, and failing to store it is the error
+	if encoded, err := k.lookup().Output(); err == nil {
+		return decodeKey(bytes.TrimSpace(encoded))
+	}
+
+	key := cryptopasta.NewEncryptionKey()
+	if out, err := k.store(encodeKey(key)).CombinedOutput(); err != nil {
+		return nil, Error.New("unable to store key in keychain: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return key, nil
+}
+
+func macLookup(account string) func() *exec.Cmd {
+	return func() *exec.Cmd {
+		return exec.Command("security", "find-generic-password", "-s", keychainService, "-a", account, "-w")
+	}
+}
+
+func macStore(account string) func([]byte) *exec.Cmd {
+	return func(encoded []byte) *exec.Cmd {
+		return exec.Command("security", "add-generic-password", "-U", "-s", keychainService, "-a", account, "-w", string(encoded))
+	}
+}
+
+func secretToolLookup(account string) func() *exec.Cmd {
+	return func() *exec.Cmd {
+		return exec.Command("secret-tool", "lookup", "service", keychainService, "account", account)
+	}
+}
+
+func secretToolStore(account string) func([]byte) *exec.Cmd {
+	return func(encoded []byte) *exec.Cmd {
+		cmd := exec.Command("secret-tool", "store", "--label=Storj uplink accesses", "service", keychainService, "account", account)
+		cmd.Stdin = bytes.NewReader(encoded)
+		return cmd
+	}
+}
+
+func encodeKey(key *[32]byte) []byte {
+	return []byte(base64.StdEncoding.EncodeToString(key[:]))
+}
+
+func decodeKey(encoded []byte) (*[32]byte, error) {
+	decoded, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(encoded)))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if len(decoded) != 32 {
+		return nil, Error.New("invalid key length %d", len(decoded))
+	}
+	key := &[32]byte{}
+	copy(key[:], decoded)
+	return key, nil
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesses
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	base58 "github.com/jbenet/go-base58"
+
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/pb"
+)
+
+// Restrictions narrow down what an access may be used for
+type Restrictions struct {
+	// ReadOnly disallows uploads and deletes
+	ReadOnly bool
+	// Prefixes restricts the access to paths starting with one of the
+	// prefixes, each a bucket optionally followed by a slash and a path
+	// prefix. All paths are allowed if empty.
+	Prefixes []string
+	// NotAfter is when the access expires, never if zero
+	NotAfter time.Time
+}
+
+// Restrict returns a copy of access whose API key is restricted by r. The
+// API key has to be a macaroon API key. The satellite isn't contacted, so
+// accesses can be restricted offline and shared. Paths aren't encrypted by
+// the uplink yet, so the restricted access has the same key material.
+func (access Access) Restrict(r Restrictions) (Access, error) {
+	key, err := macaroon.ParseAPIKey(access.APIKey)
+	if err != nil {
+		return Access{}, Error.New("only macaroon API keys can be restricted: %v", err)
+	}
+
+	caveat := pb.Caveat{
+		DisallowWrites:  r.ReadOnly,
+		DisallowDeletes: r.ReadOnly,
+	}
+	for _, prefix := range r.Prefixes {
+		parts := strings.SplitN(prefix, "/", 2)
+		if parts[0] == "" {
+			return Access{}, Error.New("prefix %q has no bucket", prefix)
+		}
+		path := &pb.Caveat_Path{Bucket: []byte(parts[0])}
+		if len(parts) > 1 {
+			path.PathPrefix = []byte(parts[1])
+		}
+		caveat.AllowedPaths = append(caveat.AllowedPaths, path)
+	}
+	if !r.NotAfter.IsZero() {
+		caveat.NotAfter, err = ptypes.TimestampProto(r.NotAfter)
+		if err != nil {
+			return Access{}, Error.Wrap(err)
+		}
+	}
+
+	restricted, err := key.Restrict(caveat)
+	if err != nil {
+		return Access{}, Error.Wrap(err)
+	}
+	access.APIKey = restricted.Serialize()
+	return access, nil
+}
+
+// Serialize returns access as a string that can be shared and imported with
+// ParseAccess
+func (access Access) Serialize() (string, error) {
+	data, err := json.Marshal(access)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	return base58.Encode(data), nil
+}
+
+// ParseAccess parses a serialized access
+func ParseAccess(serialized string) (Access, error) {
+	var access Access
+	data := base58.Decode(serialized)
+	if len(data) == 0 {
+		return Access{}, Error.New("invalid access encoding")
+	}
+	if err := json.Unmarshal(data, &access); err != nil {
+		return Access{}, Error.Wrap(err)
+	}
+	if access.PointerDBAddr == "" || access.APIKey == "" {
+		return Access{}, Error.New("access has no pointerdb address or API key")
+	}
+	return access, nil
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesses
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/macaroon"
+)
+
+func TestRestrict(t *testing.T) {
+	secret, err := macaroon.NewSecret()
+	if !assert.NoError(t, err) {
+		return
+	}
+	root, err := macaroon.NewAPIKey(secret)
+	if !assert.NoError(t, err) {
+		return
+	}
+	access := Access{OverlayAddr: "sat:7777", PointerDBAddr: "sat:7777", APIKey: root.Serialize()}
+
+	_, err = Access{APIKey: "static key"}.Restrict(Restrictions{ReadOnly: true})
+	assert.Error(t, err)
+
+	restricted, err := access.Restrict(Restrictions{
+		ReadOnly: true,
+		Prefixes: []string{"photos/2018/", "music"},
+		NotAfter: time.Now().Add(time.Hour),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	serialized, err := restricted.Serialize()
+	if !assert.NoError(t, err) {
+		return
+	}
+	imported, err := ParseAccess(serialized)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, restricted, imported)
+
+	key, err := macaroon.ParseAPIKey(imported.APIKey)
+	if !assert.NoError(t, err) {
+		return
+	}
+	now := time.Now()
+	assert.NoError(t, key.Check(secret, macaroon.Action{Op: macaroon.ActionRead, Bucket: []byte("photos"), Path: []byte("2018/a"), Time: now}))
+	assert.NoError(t, key.Check(secret, macaroon.Action{Op: macaroon.ActionRead, Bucket: []byte("music"), Path: []byte("a"), Time: now}))
+	assert.Error(t, key.Check(secret, macaroon.Action{Op: macaroon.ActionWrite, Bucket: []byte("music"), Path: []byte("a"), Time: now}))
+	assert.Error(t, key.Check(secret, macaroon.Action{Op: macaroon.ActionRead, Bucket: []byte("photos"), Path: []byte("2017/a"), Time: now}))
+
+	_, err = ParseAccess("not an access")
+	assert.Error(t, err)
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesses
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gtank/cryptopasta"
+)
+
+// Store keeps the accesses of an uplink in a file that is encrypted with the
+// key of a keychain
+type Store struct {
+	path     string
+	keychain Keychain
+}
+
+// NewStore creates a Store that keeps the accesses in the file at path,
+// encrypted with the key of keychain
+func NewStore(path string, keychain Keychain) *Store {
+	return &Store{path: path, keychain: keychain}
+}
+
+// Load reads and decrypts the accesses. If there is no file yet, no
+// accesses are returned.
+func (s *Store) Load() (*Accesses, error) {
+	accesses := &Accesses{Named: map[string]Access{}}
+
+	ciphertext, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return accesses, nil
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	key, err := s.keychain.Key()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := cryptopasta.Decrypt(ciphertext, key)
+	if err != nil {
+		return nil, Error.New("unable to decrypt %s: %v", s.path, err)
+	}
+
+	if err := json.Unmarshal(plaintext, accesses); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return accesses, nil
+}
+
+// Save encrypts and writes the accesses
+func (s *Store) Save(accesses *Accesses) error {
+	plaintext, err := json.Marshal(accesses)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	key, err := s.keychain.Key()
+	if err != nil {
+		return err
+	}
+	ciphertext, err := cryptopasta.Encrypt(plaintext, key)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(ioutil.WriteFile(s.path, ciphertext, 0600))
+}
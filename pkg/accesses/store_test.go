@@ -0,0 +1,54 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesses
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "accesses")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "accesses")
+	store := NewStore(path, FileKeychain(filepath.Join(dir, "accesses.key")))
+
+	accesses, err := store.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, accesses.Names())
+
+	prod := Access{OverlayAddr: "prod:7777", PointerDBAddr: "prod:7777", APIKey: "prod-secret"}
+	assert.NoError(t, accesses.Add("prod", prod, false))
+	assert.NoError(t, accesses.Add("dev", Access{APIKey: "dev-secret"}, false))
+	assert.Error(t, accesses.Add("prod", Access{}, false))
+	assert.Error(t, accesses.Use("staging"))
+	assert.NoError(t, accesses.Use("prod"))
+	assert.NoError(t, store.Save(accesses))
+
+	ciphertext, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.False(t, bytes.Contains(ciphertext, []byte("prod-secret")))
+
+	loaded, err := store.Load()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"dev", "prod"}, loaded.Names())
+	assert.Equal(t, "prod", loaded.Current)
+	access, ok := loaded.Get("prod")
+	assert.True(t, ok)
+	assert.Equal(t, prod, access)
+
+	// the accesses can't be read with another key
+	other := NewStore(path, FileKeychain(filepath.Join(dir, "other.key")))
+	_, err = other.Load()
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesslog
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default accesslog errs class
+	Error = errs.Class("access log error")
+)
+
+// Entry is the access log entry of a request to the satellite API
+type Entry struct {
+	Time time.Time `json:"time"`
+	// Project identifies the project of the API key of the request
+	Project string `json:"project"`
+	// Key identifies the API key of the request
+	Key       string `json:"key"`
+	Operation string `json:"operation"`
+	// PathHash identifies the path of the request without revealing it
+	PathHash string        `json:"path_hash,omitempty"`
+	Bytes    int64         `json:"bytes"`
+	Latency  time.Duration `json:"latency"`
+	Status   string        `json:"status"`
+}
+
+// Sink writes access log entries somewhere
+type Sink interface {
+	Write(entry *Entry) error
+	Close() error
+}
+
+// Log samples the requests to the satellite API into a sink
+type Log struct {
+	sink  Sink
+	rate  float64
+	rates map[string]float64
+}
+
+// New returns a log writing to sink a fraction rate of the requests, or the
+// fraction in rates of the requests of a project
+func New(sink Sink, rate float64, rates map[string]float64) *Log {
+	return &Log{sink: sink, rate: rate, rates: rates}
+}
+
+// Log writes entry to the sink if it's sampled. Failing to write it doesn't
+// fail the request, so errors are only counted.
+func (log *Log) Log(entry *Entry) {
+	rate, ok := log.rates[entry.Project]
+	if !ok {
+		rate = log.rate
+	}
+	if rate <= 0 || (rate < 1 && rand.Float64() >= rate) {
+		return
+	}
+	if err := log.sink.Write(entry); err != nil {
+		mon.Counter("access_log_errors").Inc(1)
+	}
+}
+
+// Close closes the sink
+func (log *Log) Close() error {
+	return log.sink.Close()
+}
+
+// ParseRates parses sampling rates by project of the form
+// project=rate[,project=rate...]
+func ParseRates(s string) (map[string]float64, error) {
+	rates := map[string]float64{}
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, Error.New("invalid sampling rate %q", pair)
+		}
+		rate, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || rate < 0 || rate > 1 {
+			return nil, Error.New("invalid sampling rate %q", pair)
+		}
+		rates[parts[0]] = rate
+	}
+	return rates, nil
+}
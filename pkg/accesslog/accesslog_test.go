@@ -0,0 +1,129 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesslog
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type memorySink struct {
+	mu      sync.Mutex
+	entries []*Entry
+}
+
+func (sink *memorySink) Write(entry *Entry) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	sink.entries = append(sink.entries, entry)
+	return nil
+}
+
+func (sink *memorySink) Close() error { return nil }
+
+func TestParseRates(t *testing.T) {
+	rates, err := ParseRates("a=0.5, b=1,")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"a": 0.5, "b": 1}, rates)
+
+	rates, err = ParseRates("")
+	assert.NoError(t, err)
+	assert.Empty(t, rates)
+
+	for _, invalid := range []string{"a", "=1", "a=x", "a=2", "a=-1"} {
+		_, err := ParseRates(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	sink := &memorySink{}
+	log := New(sink, 0, map[string]float64{"all": 1, "some": 0.5})
+
+	for i := 0; i < 1000; i++ {
+		log.Log(&Entry{Project: "all"})
+		log.Log(&Entry{Project: "some"})
+		log.Log(&Entry{Project: "none"})
+	}
+	counts := map[string]int{}
+	for _, entry := range sink.entries {
+		counts[entry.Project]++
+	}
+	assert.Equal(t, 1000, counts["all"])
+	assert.InDelta(t, 500, counts["some"], 150)
+	assert.Equal(t, 0, counts["none"])
+}
+
+func TestFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "accesslog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "access.log")
+	sink, err := Open("file://" + path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, sink.Write(&Entry{Project: "a", Operation: "get"}))
+	assert.NoError(t, sink.Write(&Entry{Project: "b", Operation: "put"}))
+	assert.NoError(t, sink.Close())
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = file.Close() }()
+	var operations []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &entry))
+		operations = append(operations, entry.Operation)
+	}
+	assert.Equal(t, []string{"get", "put"}, operations)
+}
+
+func TestHTTPSink(t *testing.T) {
+	var mu sync.Mutex
+	var received []Entry
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var batch []Entry
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+		mu.Lock()
+		received = append(received, batch...)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sink, err := Open(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 250; i++ {
+		assert.NoError(t, sink.Write(&Entry{Operation: "get"}))
+	}
+	// closing posts the queued entries
+	assert.NoError(t, sink.Close())
+	assert.Error(t, sink.Write(&Entry{}))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, received, 250)
+}
+
+func TestOpen(t *testing.T) {
+	_, err := Open("ftp://example.com")
+	assert.Error(t, err)
+}
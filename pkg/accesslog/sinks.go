@@ -0,0 +1,170 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accesslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"storj.io/storj/pkg/utils"
+)
+
+// Open opens the sink at rawurl: file://path appends JSON lines to a file,
+// syslog://[host:port] writes to the local or a remote syslog daemon, and
+// http(s)://... posts batches of entries as JSON arrays
+func Open(rawurl string) (Sink, error) {
+	u, err := utils.ParseURL(rawurl)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	switch u.Scheme {
+	case "file":
+		return openFile(strings.TrimPrefix(rawurl, "file://"))
+	case "syslog":
+		return openSyslog(u.Host)
+	case "http", "https":
+		return newHTTPSink(rawurl, http.DefaultClient), nil
+	default:
+		return nil, Error.New("unsupported access log scheme: %s", u.Scheme)
+	}
+}
+
+// fileSink appends entries to a file as JSON lines
+type fileSink struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+func openFile(path string) (*fileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &fileSink{file: file, enc: json.NewEncoder(file)}, nil
+}
+
+func (sink *fileSink) Write(entry *Entry) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	return Error.Wrap(sink.enc.Encode(entry))
+}
+
+func (sink *fileSink) Close() error {
+	return Error.Wrap(sink.file.Close())
+}
+
+const (
+	// httpBatchSize is the maximum number of entries posted at once
+	httpBatchSize = 100
+	// httpFlushInterval is how long entries wait for a batch to fill up
+	httpFlushInterval = time.Second
+	// httpQueueSize is how many entries wait to be posted before new
+	// entries are dropped
+	httpQueueSize = 10000
+)
+
+// httpSink posts batches of entries as JSON arrays. Entries are posted in
+// the background, and dropped if the endpoint can't keep up.
+type httpSink struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	closed  bool
+	entries chan *Entry
+	done    chan struct{}
+}
+
+func newHTTPSink(url string, client *http.Client) *httpSink {
+	sink := &httpSink{
+		url:     url,
+		client:  client,
+		entries: make(chan *Entry, httpQueueSize),
+		done:    make(chan struct{}),
+	}
+	go sink.run()
+	return sink
+}
+
+func (sink *httpSink) Write(entry *Entry) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if sink.closed {
+		return Error.New("closed")
+	}
+	select {
+	case sink.entries <- entry:
+	default:
+		mon.Counter("access_log_dropped").Inc(1)
+	}
+	return nil
+}
+
+// run posts the queued entries until the sink is closed
+func (sink *httpSink) run() {
+	defer close(sink.done)
+	ticker := time.NewTicker(httpFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Entry, 0, httpBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := sink.post(batch); err != nil {
+			mon.Counter("access_log_errors").Inc(1)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case entry, ok := <-sink.entries:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, entry)
+			if len(batch) >= httpBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// post posts a batch of entries
+func (sink *httpSink) post(batch []*Entry) error {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	resp, err := sink.client.Post(sink.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		return Error.New("posting entries: %s", resp.Status)
+	}
+	return nil
+}
+
+// Close posts the queued entries
+func (sink *httpSink) Close() error {
+	sink.mu.Lock()
+	if !sink.closed {
+		sink.closed = true
+		close(sink.entries)
+	}
+	sink.mu.Unlock()
+	<-sink.done
+	return nil
+}
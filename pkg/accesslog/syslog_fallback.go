@@ -0,0 +1,11 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information
+
+// +build !linux,!darwin,!freebsd
+
+package accesslog
+
+// openSyslog fails, as syslog isn't supported on this platform
+func openSyslog(addr string) (Sink, error) {
+	return nil, Error.New("syslog isn't supported on this platform")
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information
+
+// +build linux darwin freebsd
+
+package accesslog
+
+import (
+	"encoding/json"
+	"log/syslog"
+)
+
+// syslogSink writes entries to syslog as JSON
+type syslogSink struct {
+	writer *syslog.Writer
+}
+
+// openSyslog connects to the syslog daemon at addr, or the local one if addr
+// is empty
+func openSyslog(addr string) (Sink, error) {
+	network := ""
+	if addr != "" {
+		network = "udp"
+	}
+	writer, err := syslog.Dial(network, addr, syslog.LOG_INFO|syslog.LOG_DAEMON, "storj-access")
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &syslogSink{writer: writer}, nil
+}
+
+func (sink *syslogSink) Write(entry *Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(sink.writer.Info(string(line)))
+}
+
+func (sink *syslogSink) Close() error {
+	return Error.Wrap(sink.writer.Close())
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accounting
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Attribution is the usage attributed to a partner
+type Attribution struct {
+	PartnerID string
+	Value     int64
+}
+
+// Attribute attributes the future usage of a bucket to partnerID. Usage that
+// was already rolled up stays attributed to the previous partner. pointerdb
+// attributes buckets created with a partner id in their metadata.
+func (db *DB) Attribute(ctx context.Context, projectID, bucket, partnerID string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	defer db.locked()()
+
+	_, err = db.DB.ExecContext(ctx, `INSERT OR REPLACE INTO attributions (project_id, bucket, partner_id, created) VALUES (?, ?, ?, ?)`,
+		projectID, bucket, partnerID, time.Now().Unix())
+	return Error.Wrap(err)
+}
+
+// PartnerOf returns the partner a bucket is attributed to, or "" if the
+// bucket isn't attributed
+func (db *DB) PartnerOf(ctx context.Context, projectID, bucket string) (partnerID string, err error) {
+	defer mon.Task()(&ctx)(&err)
+	defer db.locked()()
+
+	partnerID, err = partnerOf(db.DB, projectID, bucket)
+	return partnerID, Error.Wrap(err)
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func partnerOf(db queryRower, projectID, bucket string) (partnerID string, err error) {
+	err = db.QueryRow(`SELECT partner_id FROM attributions WHERE project_id = ? AND bucket = ?`,
+		projectID, bucket).Scan(&partnerID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return partnerID, err
+}
+
+// Attributions sums the usage of kind between start and end per partner, for
+// revenue attribution reports. Usage that isn't attributed is left out.
+func (db *DB) Attributions(ctx context.Context, kind Kind, start, end time.Time) (attributions []Attribution, err error) {
+	defer mon.Task()(&ctx)(&err)
+	reader, done := db.reader()
+	defer done()
+
+	// hourly rollups are summed so that reports can start at any hour
+	rows, err := reader.QueryContext(ctx, `SELECT partner_id, SUM(value) FROM rollups WHERE kind = ? AND granularity = ? AND ? <= interval_start AND interval_start < ? AND partner_id != '' GROUP BY partner_id ORDER BY partner_id`,
+		kind, int64(Hourly), Hourly.start(start), end.Unix())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var attribution Attribution
+		if err := rows.Scan(&attribution.PartnerID, &attribution.Value); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		attributions = append(attributions, attribution)
+	}
+	return attributions, Error.Wrap(rows.Err())
+}
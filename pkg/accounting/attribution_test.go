@@ -0,0 +1,50 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accounting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttributions(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	day := time.Date(2018, 9, 20, 0, 0, 0, 0, time.UTC)
+	bucket1 := Key{ProjectID: "project", Bucket: "bucket1"}
+	bucket2 := Key{ProjectID: "project", Bucket: "bucket2"}
+	bucket3 := Key{ProjectID: "project", Bucket: "bucket3"}
+
+	partnerID, err := db.PartnerOf(ctx, "project", "bucket1")
+	assert.NoError(t, err)
+	assert.Equal(t, "", partnerID)
+
+	assert.NoError(t, db.Attribute(ctx, "project", "bucket1", "partner1"))
+	assert.NoError(t, db.Attribute(ctx, "project", "bucket2", "partner2"))
+
+	partnerID, err = db.PartnerOf(ctx, "project", "bucket1")
+	assert.NoError(t, err)
+	assert.Equal(t, "partner1", partnerID)
+
+	assert.NoError(t, db.Add(ctx, Bandwidth, bucket1, day, 10))
+	assert.NoError(t, db.Add(ctx, Bandwidth, bucket1, day.Add(5*time.Hour), 20))
+	assert.NoError(t, db.Add(ctx, Bandwidth, bucket2, day.Add(time.Hour), 3))
+	assert.NoError(t, db.Add(ctx, Bandwidth, bucket3, day.Add(time.Hour), 1000))
+	assert.NoError(t, db.Add(ctx, Storage, bucket2, day.Add(time.Hour), 50))
+
+	attributions, err := db.Attributions(ctx, Bandwidth, day, day.Add(24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []Attribution{{"partner1", 30}, {"partner2", 3}}, attributions)
+
+	attributions, err = db.Attributions(ctx, Bandwidth, day.Add(time.Hour), day.Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []Attribution{{"partner2", 3}}, attributions)
+
+	points, err := db.Query(ctx, Query{Bandwidth, Daily, Key{PartnerID: "partner1"}, day, day.Add(24 * time.Hour)})
+	assert.NoError(t, err)
+	assert.Equal(t, []Point{{day, 30}}, points)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accounting
+
+import (
+	"context"
+
+	"storj.io/storj/pkg/provider"
+)
+
+// CtxKey Used as accounting key
+type CtxKey int
+
+const (
+	ctxKeyAccounting CtxKey = iota
+)
+
+// Config is a configuration struct that is everything you need to start an
+// accounting responsibility
+type Config struct {
+	Path        string `help:"path to the usage rollup database" default:"$CONFDIR/accounting.db"`
+	ReplicaPath string `help:"path to a read-only replica of the usage rollup database, used for rollup queries and reports that tolerate stale results. if empty, the database is used" default:""`
+}
+
+// Run implements the provider.Responsibility interface
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	db, err := Open(ctx, c.Path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	if c.ReplicaPath != "" {
+		if err := db.OpenReplica(ctx, c.ReplicaPath); err != nil {
+			return err
+		}
+	}
+
+	return server.Run(context.WithValue(ctx, ctxKeyAccounting, db))
+}
+
+// LoadFromContext loads an existing accounting DB from the Provider context
+// stack if one exists.
+func LoadFromContext(ctx context.Context) *DB {
+	if v, ok := ctx.Value(ctxKeyAccounting).(*DB); ok {
+		return v
+	}
+	return nil
+}
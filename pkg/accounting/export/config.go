@@ -0,0 +1,57 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package export
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/backup"
+	"storj.io/storj/pkg/chore"
+	"storj.io/storj/pkg/provider"
+)
+
+// Config contains everything necessary to start the accounting export
+// responsibility
+type Config struct {
+	URL      string        `help:"where the accounting rollups are exported, as file:///DIR or s3://ACCESSKEY:SECRETKEY@HOST/BUCKET[/PREFIX]. exports are disabled if empty" default:""`
+	Interval time.Duration `help:"how frequently days due for export are looked for" default:"1h"`
+	Days     int           `help:"how many of the last days are exported if they weren't yet" default:"7"`
+	Delay    time.Duration `help:"how long after the end of a day it's exported, so that late usage is included" default:"6h"`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// accounting responsibility has been started before this one.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if c.URL == "" {
+		return server.Run(ctx)
+	}
+
+	db := accounting.LoadFromContext(ctx)
+	if db == nil {
+		return Error.New("programmer error: accounting responsibility unstarted")
+	}
+
+	dest, err := backup.OpenDestination(c.URL)
+	if err != nil {
+		return err
+	}
+	exporter := NewExporter(zap.L().Named("export"), db, dest, c.Days, c.Delay)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	export, err := chore.New(ctx, "export", c.Interval, exporter.Export)
+	if err != nil {
+		return err
+	}
+	go func() { _ = export.Run(ctx) }()
+
+	return server.Run(ctx)
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/backup"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for accounting exports
+	Error = errs.Class("export error")
+)
+
+const dayFormat = "2006-01-02"
+
+// The hourly rollups of a day are exported as CSV files with a header row to
+// DAY/project_usage.csv and DAY/node_usage.csv, where DAY is like
+// 2018-11-05. Times are RFC 3339 in UTC, storage is in byte-hours and
+// bandwidth in bytes.
+var (
+	projectUsageFile   = "project_usage.csv"
+	projectUsageHeader = []string{"interval_start", "kind", "project_id", "bucket", "partner_id", "value"}
+	nodeUsageFile      = "node_usage.csv"
+	nodeUsageHeader    = []string{"interval_start", "kind", "node_id", "value"}
+)
+
+// Exporter exports the rollups of completed days
+type Exporter struct {
+	log   *zap.Logger
+	db    *accounting.DB
+	dest  backup.Destination
+	days  int
+	delay time.Duration
+}
+
+// NewExporter creates an Exporter of the rollups in db to dest. It exports
+// the days of the last days days that weren't exported yet, once delay
+// passed since their end, so that late usage is included.
+func NewExporter(log *zap.Logger, db *accounting.DB, dest backup.Destination, days int, delay time.Duration) *Exporter {
+	return &Exporter{log: log, db: db, dest: dest, days: days, delay: delay}
+}
+
+// Export exports the days that are due
+func (exporter *Exporter) Export(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// the last day that ended at least delay ago
+	last := time.Now().UTC().Add(-exporter.delay).Truncate(24*time.Hour).AddDate(0, 0, -1)
+	for i := exporter.days - 1; i >= 0; i-- {
+		day := last.AddDate(0, 0, -i)
+		exported, err := exporter.dest.List(path.Join(day.Format(dayFormat), nodeUsageFile))
+		if err != nil {
+			return err
+		}
+		if len(exported) > 0 {
+			continue
+		}
+		if err := exporter.ExportDay(ctx, day); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ExportDay exports the rollups of the UTC day that contains day
+func (exporter *Exporter) ExportDay(ctx context.Context, day time.Time) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	start := day.UTC().Truncate(24 * time.Hour)
+	rollups, err := exporter.db.Rollups(ctx, accounting.Hourly, start, start.AddDate(0, 0, 1))
+	if err != nil {
+		return err
+	}
+
+	var projects, nodes [][]string
+	for _, rollup := range rollups {
+		intervalStart := rollup.Start.Format(time.RFC3339)
+		value := strconv.FormatInt(rollup.Value, 10)
+		switch {
+		case rollup.Key.NodeID != "":
+			nodes = append(nodes, []string{intervalStart, rollup.Kind.String(), rollup.Key.NodeID, value})
+		case rollup.Key.ProjectID != "":
+			projects = append(projects, []string{intervalStart, rollup.Kind.String(), rollup.Key.ProjectID,
+				rollup.Key.Bucket, rollup.Key.PartnerID, value})
+		}
+	}
+
+	dir := start.Format(dayFormat)
+	// the node usage is written last, as its presence marks the day exported
+	if err := exporter.put(path.Join(dir, projectUsageFile), projectUsageHeader, projects); err != nil {
+		return err
+	}
+	if err := exporter.put(path.Join(dir, nodeUsageFile), nodeUsageHeader, nodes); err != nil {
+		return err
+	}
+
+	exporter.log.Info("exported rollups", zap.String("day", dir),
+		zap.Int("projects", len(projects)), zap.Int("nodes", len(nodes)))
+	return nil
+}
+
+// put stores the CSV of header and records as name
+func (exporter *Exporter) put(name string, header []string, records [][]string) error {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return Error.Wrap(err)
+	}
+	if err := w.WriteAll(records); err != nil {
+		return Error.Wrap(err)
+	}
+	return exporter.dest.Put(name, &buf, int64(buf.Len()))
+}
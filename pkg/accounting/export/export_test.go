@@ -0,0 +1,72 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package export
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/backup"
+)
+
+func TestExport(t *testing.T) {
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "storj-export")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	db, err := accounting.Open(ctx, filepath.Join(dir, "accounting.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, db.Close()) }()
+
+	dest, err := backup.NewDirDestination(filepath.Join(dir, "export"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	yesterday := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -1)
+	assert.NoError(t, db.Add(ctx, accounting.Bandwidth, accounting.Key{ProjectID: "project", Bucket: "bucket"},
+		yesterday.Add(90*time.Minute), 100))
+	assert.NoError(t, db.Add(ctx, accounting.Storage, accounting.Key{NodeID: "node"}, yesterday, 5))
+	// today isn't over yet, so it isn't exported
+	assert.NoError(t, db.Add(ctx, accounting.Storage, accounting.Key{NodeID: "node"}, time.Now(), 5))
+
+	exporter := NewExporter(zap.NewNop(), db, dest, 2, 0)
+	if !assert.NoError(t, exporter.Export(ctx)) {
+		return
+	}
+
+	day := yesterday.Format(dayFormat)
+	names, err := dest.List("")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		yesterday.AddDate(0, 0, -1).Format(dayFormat) + "/node_usage.csv",
+		yesterday.AddDate(0, 0, -1).Format(dayFormat) + "/project_usage.csv",
+		day + "/node_usage.csv",
+		day + "/project_usage.csv",
+	}, names)
+
+	projects, err := ioutil.ReadFile(filepath.Join(dir, "export", day, projectUsageFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "interval_start,kind,project_id,bucket,partner_id,value\n"+
+		yesterday.Add(time.Hour).Format(time.RFC3339)+",bandwidth,project,bucket,,100\n", string(projects))
+
+	nodes, err := ioutil.ReadFile(filepath.Join(dir, "export", day, nodeUsageFile))
+	assert.NoError(t, err)
+	assert.Equal(t, "interval_start,kind,node_id,value\n"+
+		yesterday.Format(time.RFC3339)+",storage,node,5\n", string(nodes))
+}
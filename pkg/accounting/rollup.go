@@ -0,0 +1,352 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accounting
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // register sqlite to sql
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for accounting
+	Error = errs.Class("accounting error")
+)
+
+// Kind is the kind of usage that is rolled up
+type Kind int
+
+const (
+	// Bandwidth is the number of bytes transferred
+	Bandwidth Kind = iota
+	// Storage is the number of byte-hours stored. Storage is tracked in
+	// byte-hours so that it can be summed over intervals like bandwidth.
+	Storage
+	// RepairBandwidth is the number of bytes transferred to repair segments,
+	// which is paid by the satellite rather than customers
+	RepairBandwidth
+	// AuditBandwidth is the number of bytes transferred to audit segments
+	AuditBandwidth
+)
+
+// BandwidthKind returns the kind the bandwidth used with an order limit of
+// action is rolled up as, so that repair and audit traffic isn't counted as
+// customer traffic
+func BandwidthKind(action pb.PayerBandwidthAllocation_Action) Kind {
+	switch action {
+	case pb.PayerBandwidthAllocation_PUT_REPAIR, pb.PayerBandwidthAllocation_GET_REPAIR:
+		return RepairBandwidth
+	case pb.PayerBandwidthAllocation_GET_AUDIT:
+		return AuditBandwidth
+	default:
+		return Bandwidth
+	}
+}
+
+// String returns the name of the kind
+func (kind Kind) String() string {
+	switch kind {
+	case Bandwidth:
+		return "bandwidth"
+	case Storage:
+		return "storage"
+	case RepairBandwidth:
+		return "repair_bandwidth"
+	case AuditBandwidth:
+		return "audit_bandwidth"
+	default:
+		return fmt.Sprintf("kind(%d)", int(kind))
+	}
+}
+
+// Granularity is the length of a rollup interval
+type Granularity time.Duration
+
+const (
+	// Hourly rolls usage up into one hour intervals
+	Hourly = Granularity(time.Hour)
+	// Daily rolls usage up into one day intervals, starting at midnight UTC
+	Daily = Granularity(24 * time.Hour)
+)
+
+var granularities = []Granularity{Hourly, Daily}
+
+// start returns the start of the interval that contains t
+func (g Granularity) start(t time.Time) int64 {
+	seconds := int64(time.Duration(g) / time.Second)
+	unix := t.Unix()
+	return unix - unix%seconds
+}
+
+// Key is what usage is attributed to. Empty fields are not attributed, so
+// usage of a bucket and usage of a node are recorded under different keys.
+type Key struct {
+	ProjectID string
+	Bucket    string
+	NodeID    string
+	// PartnerID is the partner the usage is attributed to. If empty, usage
+	// of a bucket is attributed to the partner the bucket was attributed to
+	// with Attribute.
+	PartnerID string
+}
+
+// Query selects the rollups of a single kind and granularity between Start
+// and End. Only the non-empty fields of Key are matched, and the usage of all
+// matching keys is summed.
+type Query struct {
+	Kind        Kind
+	Granularity Granularity
+	Key         Key
+	Start       time.Time
+	End         time.Time
+}
+
+// MaxPoints is the most points a query returns, a year of hourly points.
+// Longer periods are queried in pages, each starting where the previous one
+// ended.
+const MaxPoints = 366 * 24
+
+// Point is the usage during the interval beginning at Start
+type Point struct {
+	Start time.Time
+	Value int64
+}
+
+// Rollup is the usage of kind by key during the interval beginning at Start
+type Rollup struct {
+	Kind  Kind
+	Key   Key
+	Start time.Time
+	Value int64
+}
+
+// DB stores usage rollups
+type DB struct {
+	mu sync.Mutex
+	DB *sql.DB
+
+	// replica is a read-only replica of DB for queries that tolerate stale
+	// results. If nil, DB is used.
+	replica *sql.DB
+}
+
+// Open opens the rollup database at path
+func Open(ctx context.Context, path string) (db *DB, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	sqlite, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?cache=shared&mode=rwc&mutex=full", path))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	_, err = sqlite.Exec("CREATE TABLE IF NOT EXISTS `rollups` (`kind` INT(10), `granularity` INT(10), `interval_start` INT(10), `project_id` TEXT, `bucket` TEXT, `node_id` TEXT, `partner_id` TEXT, `value` INT(10), UNIQUE (`kind`, `granularity`, `interval_start`, `project_id`, `bucket`, `node_id`, `partner_id`));")
+	if err != nil {
+		_ = sqlite.Close()
+		return nil, Error.Wrap(err)
+	}
+
+	_, err = sqlite.Exec("CREATE TABLE IF NOT EXISTS `attributions` (`project_id` TEXT, `bucket` TEXT, `partner_id` TEXT, `created` INT(10), PRIMARY KEY (`project_id`, `bucket`));")
+	if err != nil {
+		_ = sqlite.Close()
+		return nil, Error.Wrap(err)
+	}
+
+	_, err = sqlite.Exec("CREATE INDEX IF NOT EXISTS idx_rollups_interval ON rollups (kind, granularity, interval_start);")
+	if err != nil {
+		_ = sqlite.Close()
+		return nil, Error.Wrap(err)
+	}
+
+	return &DB{DB: sqlite}, nil
+}
+
+// OpenReplica opens the read-only replica of the database at path. Rollup
+// queries and attribution reports read from it, so they can lag behind the
+// latest usage.
+func (db *DB) OpenReplica(ctx context.Context, path string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	replica, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", path))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if err := replica.PingContext(ctx); err != nil {
+		_ = replica.Close()
+		return Error.Wrap(err)
+	}
+	db.replica = replica
+	return nil
+}
+
+// Close closes the database
+func (db *DB) Close() error {
+	if db.replica != nil {
+		return utils.CombineErrors(db.replica.Close(), db.DB.Close())
+	}
+	return db.DB.Close()
+}
+
+func (db *DB) locked() func() {
+	db.mu.Lock()
+	return db.mu.Unlock
+}
+
+// reader returns the database for queries that tolerate stale results and a
+// func to call when done
+func (db *DB) reader() (*sql.DB, func()) {
+	if db.replica != nil {
+		return db.replica, func() {}
+	}
+	return db.DB, db.locked()
+}
+
+// Add adds value to the hourly and daily rollups of key that contain at
+func (db *DB) Add(ctx context.Context, kind Kind, key Key, at time.Time, value int64) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	defer db.locked()()
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if key.PartnerID == "" && key.Bucket != "" {
+		key.PartnerID, err = partnerOf(tx, key.ProjectID, key.Bucket)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	for _, granularity := range granularities {
+		start := granularity.start(at)
+
+		_, err = tx.Exec(`INSERT OR IGNORE INTO rollups (kind, granularity, interval_start, project_id, bucket, node_id, partner_id, value) VALUES (?, ?, ?, ?, ?, ?, ?, 0)`,
+			kind, int64(granularity), start, key.ProjectID, key.Bucket, key.NodeID, key.PartnerID)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		_, err = tx.Exec(`UPDATE rollups SET value = value + ? WHERE kind = ? AND granularity = ? AND interval_start = ? AND project_id = ? AND bucket = ? AND node_id = ? AND partner_id = ?`,
+			value, kind, int64(granularity), start, key.ProjectID, key.Bucket, key.NodeID, key.PartnerID)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	return Error.Wrap(tx.Commit())
+}
+
+// Query returns a point for every interval between q.Start and q.End,
+// including intervals without usage. Queries of more than MaxPoints
+// intervals are refused.
+func (db *DB) Query(ctx context.Context, q Query) (points []Point, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if q.Granularity != Hourly && q.Granularity != Daily {
+		return nil, Error.New("invalid granularity %v", time.Duration(q.Granularity))
+	}
+	if q.End.Before(q.Start) {
+		return nil, Error.New("end %v is before start %v", q.End, q.Start)
+	}
+
+	first := q.Granularity.start(q.Start)
+	step := int64(time.Duration(q.Granularity) / time.Second)
+	count := (q.End.Unix() - first + step - 1) / step
+	if count > MaxPoints {
+		return nil, Error.New("query of %d intervals exceeds the maximum of %d", count, MaxPoints)
+	}
+
+	conditions := []string{"kind = ?", "granularity = ?", "? <= interval_start", "interval_start < ?"}
+	args := []interface{}{q.Kind, int64(q.Granularity), first, q.End.Unix()}
+	for _, match := range []struct {
+		column string
+		value  string
+	}{
+		{"project_id", q.Key.ProjectID},
+		{"bucket", q.Key.Bucket},
+		{"node_id", q.Key.NodeID},
+		{"partner_id", q.Key.PartnerID},
+	} {
+		if match.value != "" {
+			conditions = append(conditions, match.column+" = ?")
+			args = append(args, match.value)
+		}
+	}
+
+	values, err := func() (map[int64]int64, error) {
+		reader, done := db.reader()
+		defer done()
+
+		rows, err := reader.QueryContext(ctx, `SELECT interval_start, SUM(value) FROM rollups WHERE `+
+			strings.Join(conditions, " AND ")+` GROUP BY interval_start`, args...)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = rows.Close() }()
+
+		values := map[int64]int64{}
+		for rows.Next() {
+			var start, value int64
+			if err := rows.Scan(&start, &value); err != nil {
+				return nil, err
+			}
+			values[start] = value
+		}
+		return values, rows.Err()
+	}()
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	points = make([]Point, 0, count)
+	for start := first; start < q.End.Unix(); start += step {
+		points = append(points, Point{Start: time.Unix(start, 0).UTC(), Value: values[start]})
+	}
+	return points, nil
+}
+
+// Rollups returns the rollups of granularity between start and end, ordered
+// by interval and key
+func (db *DB) Rollups(ctx context.Context, granularity Granularity, start, end time.Time) (rollups []Rollup, err error) {
+	defer mon.Task()(&ctx)(&err)
+	reader, done := db.reader()
+	defer done()
+
+	rows, err := reader.QueryContext(ctx, `SELECT kind, interval_start, project_id, bucket, node_id, partner_id, value FROM rollups WHERE granularity = ? AND ? <= interval_start AND interval_start < ? ORDER BY interval_start, project_id, bucket, node_id, partner_id, kind`,
+		int64(granularity), granularity.start(start), end.Unix())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var rollup Rollup
+		var intervalStart int64
+		if err := rows.Scan(&rollup.Kind, &intervalStart, &rollup.Key.ProjectID, &rollup.Key.Bucket,
+			&rollup.Key.NodeID, &rollup.Key.PartnerID, &rollup.Value); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		rollup.Start = time.Unix(intervalStart, 0).UTC()
+		rollups = append(rollups, rollup)
+	}
+	return rollups, Error.Wrap(rows.Err())
+}
@@ -0,0 +1,168 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package accounting
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/pb"
+)
+
+var ctx = context.Background()
+
+func openTest(t *testing.T) (*DB, func()) {
+	tmpdir, err := ioutil.TempDir("", "storj-accounting")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := Open(ctx, filepath.Join(tmpdir, "accounting.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db, func() {
+		assert.NoError(t, db.Close())
+		assert.NoError(t, os.RemoveAll(tmpdir))
+	}
+}
+
+func TestRollups(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	day := time.Date(2018, 9, 20, 0, 0, 0, 0, time.UTC)
+	bucket1 := Key{ProjectID: "project", Bucket: "bucket1"}
+	bucket2 := Key{ProjectID: "project", Bucket: "bucket2"}
+	node := Key{NodeID: "node"}
+
+	for _, usage := range []struct {
+		kind  Kind
+		key   Key
+		at    time.Time
+		value int64
+	}{
+		{Bandwidth, bucket1, day.Add(10 * time.Minute), 100},
+		{Bandwidth, bucket1, day.Add(50 * time.Minute), 20},
+		{Bandwidth, bucket2, day.Add(2 * time.Hour), 5},
+		{Bandwidth, node, day.Add(2 * time.Hour), 7},
+		{Bandwidth, bucket1, day.Add(25 * time.Hour), 1},
+		{Storage, bucket1, day.Add(time.Hour), 1000},
+	} {
+		assert.NoError(t, db.Add(ctx, usage.kind, usage.key, usage.at, usage.value))
+	}
+
+	for _, tt := range []struct {
+		name   string
+		query  Query
+		values []int64
+	}{
+		{"hourly per bucket",
+			Query{Bandwidth, Hourly, bucket1, day, day.Add(3 * time.Hour)},
+			[]int64{120, 0, 0}},
+		{"hourly per project",
+			Query{Bandwidth, Hourly, Key{ProjectID: "project"}, day, day.Add(3 * time.Hour)},
+			[]int64{120, 0, 5}},
+		{"hourly per node",
+			Query{Bandwidth, Hourly, node, day, day.Add(3 * time.Hour)},
+			[]int64{0, 0, 7}},
+		{"daily per project",
+			Query{Bandwidth, Daily, Key{ProjectID: "project"}, day, day.Add(48 * time.Hour)},
+			[]int64{125, 1}},
+		{"daily storage",
+			Query{Storage, Daily, bucket1, day, day.Add(24 * time.Hour)},
+			[]int64{1000}},
+		{"unaligned start",
+			Query{Bandwidth, Hourly, bucket1, day.Add(30 * time.Minute), day.Add(time.Hour)},
+			[]int64{120}},
+	} {
+		points, err := db.Query(ctx, tt.query)
+		if !assert.NoError(t, err, tt.name) {
+			continue
+		}
+
+		var values []int64
+		for i, point := range points {
+			values = append(values, point.Value)
+			assert.Equal(t, tt.query.Granularity.start(tt.query.Start)+int64(i)*int64(time.Duration(tt.query.Granularity)/time.Second),
+				point.Start.Unix(), tt.name)
+		}
+		assert.Equal(t, tt.values, values, tt.name)
+	}
+}
+
+func TestQueryInvalid(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	now := time.Now()
+
+	_, err := db.Query(ctx, Query{Granularity: Granularity(time.Minute), Start: now, End: now})
+	assert.Error(t, err)
+
+	_, err = db.Query(ctx, Query{Granularity: Hourly, Start: now, End: now.Add(-time.Hour)})
+	assert.Error(t, err)
+
+	// long periods have to be queried in pages
+	start := Hourly.start(now)
+	points, err := db.Query(ctx, Query{Granularity: Hourly, Start: time.Unix(start, 0), End: time.Unix(start, 0).Add(MaxPoints * time.Hour)})
+	assert.NoError(t, err)
+	assert.Len(t, points, MaxPoints)
+	_, err = db.Query(ctx, Query{Granularity: Hourly, Start: time.Unix(start, 0), End: time.Unix(start, 0).Add((MaxPoints + 1) * time.Hour)})
+	assert.Error(t, err)
+}
+
+func TestReplica(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	tmpdir, err := ioutil.TempDir("", "storj-accounting-replica")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { assert.NoError(t, os.RemoveAll(tmpdir)) }()
+
+	// the replica stands in for a copy of db that is lagging behind
+	path := filepath.Join(tmpdir, "replica.db")
+	replica, err := Open(ctx, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { assert.NoError(t, replica.Close()) }()
+
+	at := time.Date(2018, 9, 20, 10, 0, 0, 0, time.UTC)
+	key := Key{ProjectID: "project"}
+	assert.NoError(t, db.Add(ctx, Storage, key, at, 10))
+	assert.NoError(t, replica.Add(ctx, Storage, key, at, 5))
+
+	if !assert.NoError(t, db.OpenReplica(ctx, path)) {
+		return
+	}
+
+	points, err := db.Query(ctx, Query{Kind: Storage, Granularity: Hourly, Key: key, Start: at, End: at.Add(time.Hour)})
+	if assert.NoError(t, err) && assert.Len(t, points, 1) {
+		assert.EqualValues(t, 5, points[0].Value)
+	}
+
+	// writes still go to db
+	assert.NoError(t, db.Add(ctx, Storage, key, at, 1))
+	var value int64
+	assert.NoError(t, db.DB.QueryRow("SELECT value FROM rollups WHERE granularity = ? AND project_id = ?", int64(Hourly), "project").Scan(&value))
+	assert.EqualValues(t, 11, value)
+}
+
+func TestBandwidthKind(t *testing.T) {
+	assert.Equal(t, Bandwidth, BandwidthKind(pb.PayerBandwidthAllocation_PUT))
+	assert.Equal(t, Bandwidth, BandwidthKind(pb.PayerBandwidthAllocation_GET))
+	assert.Equal(t, RepairBandwidth, BandwidthKind(pb.PayerBandwidthAllocation_PUT_REPAIR))
+	assert.Equal(t, RepairBandwidth, BandwidthKind(pb.PayerBandwidthAllocation_GET_REPAIR))
+	assert.Equal(t, AuditBandwidth, BandwidthKind(pb.PayerBandwidthAllocation_GET_AUDIT))
+}
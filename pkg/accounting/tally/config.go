@@ -0,0 +1,56 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package tally
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/chore"
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/provider"
+)
+
+// Config contains everything necessary to start the storage tally
+// responsibility
+type Config struct {
+	Interval time.Duration `help:"how frequently the storage of nodes is tallied" default:"1h"`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// accounting and metainfo loop responsibilities have been started before
+// this one.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if c.Interval <= 0 {
+		return Error.New("invalid interval: %v", c.Interval)
+	}
+
+	db := accounting.LoadFromContext(ctx)
+	if db == nil {
+		return Error.New("programmer error: accounting responsibility unstarted")
+	}
+
+	loop := metainfo.LoadFromContext(ctx)
+	if loop == nil {
+		return Error.New("programmer error: metainfo loop responsibility unstarted")
+	}
+
+	tally := NewTally(zap.L().Named("tally"), db, loop, c.Interval)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	run, err := chore.New(ctx, "tally", c.Interval, tally.Tally)
+	if err != nil {
+		return err
+	}
+	go func() { _ = run.Run(ctx) }()
+
+	return server.Run(ctx)
+}
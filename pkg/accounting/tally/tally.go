@@ -0,0 +1,83 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package tally
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for storage tallies
+	Error = errs.Class("tally error")
+)
+
+// Tally rolls up the storage of nodes. Every tally counts the bytes of the
+// pieces each node stores, and adds them to its storage for the interval
+// since the previous tally.
+type Tally struct {
+	log      *zap.Logger
+	db       *accounting.DB
+	loop     *metainfo.Loop
+	interval time.Duration
+	now      func() time.Time
+}
+
+// NewTally creates a Tally of the pointers of loop into db, run every
+// interval
+func NewTally(log *zap.Logger, db *accounting.DB, loop *metainfo.Loop, interval time.Duration) *Tally {
+	return &Tally{log: log, db: db, loop: loop, interval: interval, now: time.Now}
+}
+
+// counter sums the bytes stored by every node during an iteration of the
+// metainfo loop
+type counter struct {
+	bytes map[string]int64
+}
+
+// Pointer implements metainfo.Observer
+func (counter *counter) Pointer(ctx context.Context, path storage.Key, pointer *pb.Pointer) error {
+	remote := pointer.GetRemote()
+	if remote == nil {
+		return nil
+	}
+	size := audit.PieceSize(pointer)
+	for _, piece := range remote.GetRemotePieces() {
+		counter.bytes[piece.GetNodeId()] += size
+	}
+	return nil
+}
+
+// Tally counts the bytes stored by every node and adds them to the storage
+// rollups, in byte-hours over the tally interval
+func (tally *Tally) Tally(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	counter := &counter{bytes: map[string]int64{}}
+	if err := tally.loop.Join(ctx, counter); err != nil {
+		return Error.Wrap(err)
+	}
+
+	now := tally.now()
+	hours := tally.interval.Hours()
+	for nodeID, bytes := range counter.bytes {
+		value := int64(float64(bytes) * hours)
+		if err := tally.db.Add(ctx, accounting.Storage, accounting.Key{NodeID: nodeID}, now, value); err != nil {
+			return err
+		}
+	}
+	tally.log.Debug("tallied storage", zap.Int("nodes", len(counter.bytes)))
+	return nil
+}
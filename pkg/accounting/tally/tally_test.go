@@ -0,0 +1,86 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package tally
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestTally(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "storj-tally")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	db, err := accounting.Open(ctx, filepath.Join(dir, "accounting.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, db.Close()) }()
+
+	pointers := teststore.New()
+	for path, nodeIDs := range map[string][]string{
+		"l/photos/a": {"n1", "n2"},
+		"l/photos/b": {"n2", "n3"},
+	} {
+		pointer := &pb.Pointer{
+			Type: pb.Pointer_REMOTE,
+			Size: 1020,
+			Remote: &pb.RemoteSegment{
+				Redundancy: &pb.RedundancyScheme{MinReq: 2, Total: 2, ErasureShareSize: 256},
+				PieceId:    "piece",
+			},
+		}
+		for i, nodeID := range nodeIDs {
+			pointer.Remote.RemotePieces = append(pointer.Remote.RemotePieces, &pb.RemotePiece{PieceNum: int32(i), NodeId: nodeID})
+		}
+		value, err := proto.Marshal(pointer)
+		assert.NoError(t, err)
+		assert.NoError(t, pointers.Put(storage.Key(path), value))
+	}
+	// inline segments aren't stored on nodes
+	value, err := proto.Marshal(&pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: []byte("data"), Size: 4})
+	assert.NoError(t, err)
+	assert.NoError(t, pointers.Put(storage.Key("l/photos/c"), value))
+
+	loop := metainfo.NewLoop(metainfo.Config{}, pointers)
+	go func() { _ = loop.Run(ctx) }()
+
+	now := time.Date(2018, 11, 5, 10, 30, 0, 0, time.UTC)
+	tally := NewTally(zap.NewNop(), db, loop, 2*time.Hour)
+	tally.now = func() time.Time { return now }
+	if !assert.NoError(t, tally.Tally(ctx)) {
+		return
+	}
+
+	// 1020 bytes and padding take 2 stripes of 512 bytes, so pieces of 512
+	// bytes, stored for 2 hours
+	start := now.Truncate(time.Hour)
+	rollups, err := db.Rollups(ctx, accounting.Hourly, start, start.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []accounting.Rollup{
+		{Kind: accounting.Storage, Key: accounting.Key{NodeID: "n1"}, Start: start, Value: 1024},
+		{Kind: accounting.Storage, Key: accounting.Key{NodeID: "n2"}, Start: start, Value: 2048},
+		{Kind: accounting.Storage, Key: accounting.Key{NodeID: "n3"}, Start: start, Value: 1024},
+	}, rollups)
+}
@@ -0,0 +1,128 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package analytics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/storage"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default analytics errs class
+	Error = errs.Class("analytics error")
+)
+
+// The names of the events emitted by the satellite
+const (
+	// AccountCreated is emitted when a user registers with the console
+	AccountCreated = "account_created"
+	// BucketCreated is emitted when a bucket that didn't exist is created
+	BucketCreated = "bucket_created"
+	// FirstUpload is emitted when the first segment of a project is stored
+	FirstUpload = "first_upload"
+	// LimitHit is emitted when a request is aborted for exceeding a limit,
+	// or when a project goes beyond its bandwidth limit
+	LimitHit = "limit_hit"
+	// NodeVetted is emitted when a storage node reaches the vetting
+	// thresholds
+	NodeVetted = "node_vetted"
+)
+
+// Event is something that happened on the satellite that growth metrics are
+// computed from
+type Event struct {
+	Name string    `json:"name"`
+	Time time.Time `json:"time"`
+	// UserID is the console user the event is about, if any
+	UserID string `json:"user_id,omitempty"`
+	// ProjectID is the project the event is about, if any. Projects are
+	// identified like in the access log.
+	ProjectID  string            `json:"project_id,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// Sink sends events somewhere. Write must not block on the network.
+type Sink interface {
+	Write(event *Event) error
+	Close() error
+}
+
+// Events emits the events of the satellite to a sink. Emitting never fails
+// or slows down the operation that caused the event, so errors are only
+// counted. A nil *Events drops all events, so it can be left unset where
+// analytics aren't configured.
+type Events struct {
+	sink Sink
+
+	// once records the keys of the events emitted with Once
+	once storage.KeyValueStore
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+// New returns Events emitting to sink. once records which events emitted
+// with Once were emitted already, and may be nil if Once isn't used.
+func New(sink Sink, once storage.KeyValueStore) *Events {
+	return &Events{sink: sink, once: once, seen: map[string]bool{}}
+}
+
+// Emit emits event, setting its time if it isn't set
+func (events *Events) Emit(event Event) {
+	if events == nil {
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+	mon.Counter("analytics_events").Inc(1)
+	if err := events.sink.Write(&event); err != nil {
+		mon.Counter("analytics_errors").Inc(1)
+	}
+}
+
+// Once emits event unless an event with the same key was emitted with Once
+// before, so that milestones like the first upload of a project are emitted
+// only once without looking their cause up in the databases
+func (events *Events) Once(key string, event Event) {
+	if events == nil || events.once == nil {
+		return
+	}
+
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	if events.seen[key] {
+		return
+	}
+
+	_, err := events.once.Get(storage.Key(key))
+	switch {
+	case err == nil:
+	case storage.ErrKeyNotFound.Has(err):
+		if err := events.once.Put(storage.Key(key), storage.Value(event.Name)); err != nil {
+			// not emitting it until it's recorded avoids duplicates
+			mon.Counter("analytics_errors").Inc(1)
+			return
+		}
+		events.Emit(event)
+	default:
+		mon.Counter("analytics_errors").Inc(1)
+		return
+	}
+	events.seen[key] = true
+}
+
+// Close closes the sink, sending the events that weren't sent yet
+func (events *Events) Close() error {
+	if events == nil {
+		return nil
+	}
+	return events.sink.Close()
+}
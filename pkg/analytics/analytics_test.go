@@ -0,0 +1,133 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package analytics
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/storage/teststore"
+)
+
+type memorySink struct {
+	mu     sync.Mutex
+	events []*Event
+}
+
+func (sink *memorySink) Write(event *Event) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	sink.events = append(sink.events, event)
+	return nil
+}
+
+func (sink *memorySink) Close() error { return nil }
+
+func TestEmit(t *testing.T) {
+	sink := &memorySink{}
+	events := New(sink, nil)
+	events.Emit(Event{Name: AccountCreated, UserID: "user"})
+	if assert.Len(t, sink.events, 1) {
+		assert.Equal(t, AccountCreated, sink.events[0].Name)
+		assert.False(t, sink.events[0].Time.IsZero())
+	}
+
+	// without analytics, events are dropped
+	var disabled *Events
+	disabled.Emit(Event{Name: AccountCreated})
+	disabled.Once("key", Event{Name: FirstUpload})
+	assert.NoError(t, disabled.Close())
+}
+
+func TestOnce(t *testing.T) {
+	sink := &memorySink{}
+	once := teststore.New()
+	events := New(sink, once)
+	for i := 0; i < 3; i++ {
+		events.Once("first_upload/a", Event{Name: FirstUpload, ProjectID: "a"})
+	}
+	events.Once("first_upload/b", Event{Name: FirstUpload, ProjectID: "b"})
+	assert.Len(t, sink.events, 2)
+
+	// the emitted events are remembered across restarts
+	restarted := New(sink, once)
+	restarted.Once("first_upload/a", Event{Name: FirstUpload, ProjectID: "a"})
+	assert.Len(t, sink.events, 2)
+}
+
+func TestSegmentSink(t *testing.T) {
+	var mu sync.Mutex
+	var tracks []segmentTrack
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "writekey", user)
+		var body struct {
+			Batch []segmentTrack `json:"batch"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		tracks = append(tracks, body.Batch...)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	sink, err := Open("http://writekey@"+server.Listener.Addr().String()+"/v1/batch", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 150; i++ {
+		assert.NoError(t, sink.Write(&Event{Name: BucketCreated, ProjectID: "project", Properties: map[string]string{"bucket": "b"}}))
+	}
+	assert.NoError(t, sink.Write(&Event{Name: AccountCreated, UserID: "user"}))
+	// closing posts the queued events
+	assert.NoError(t, sink.Close())
+	assert.Error(t, sink.Write(&Event{}))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if assert.Len(t, tracks, 151) {
+		assert.Equal(t, "track", tracks[0].Type)
+		assert.Equal(t, BucketCreated, tracks[0].Event)
+		assert.Equal(t, "project", tracks[0].AnonymousID)
+		assert.Equal(t, map[string]string{"bucket": "b", "project_id": "project"}, tracks[0].Properties)
+		assert.Equal(t, "user", tracks[150].UserID)
+	}
+}
+
+func TestKafkaSink(t *testing.T) {
+	var received []kafkaRecord
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/topics/events", r.URL.Path)
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+		var body struct {
+			Records []kafkaRecord `json:"records"`
+		}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		received = append(received, body.Records...)
+	}))
+	defer server.Close()
+
+	sink, err := Open("kafka+"+server.URL+"/topics/events", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NoError(t, sink.Write(&Event{Name: LimitHit, ProjectID: "project"}))
+	assert.NoError(t, sink.Close())
+
+	if assert.Len(t, received, 1) {
+		assert.Equal(t, "project", received[0].Key)
+		assert.Equal(t, LimitHit, received[0].Value.Name)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	_, err := Open("ftp://example.com", nil)
+	assert.Error(t, err)
+}
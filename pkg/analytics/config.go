@@ -0,0 +1,65 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package analytics
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage/boltdb"
+)
+
+// CtxKey Used as analytics key
+type CtxKey int
+
+const (
+	ctxKeyAnalytics CtxKey = iota
+)
+
+// OnceBucket is the bolt bucket of the events emitted once
+const OnceBucket = "analytics_once"
+
+// Config is a configuration struct that is everything you need to start an
+// analytics responsibility
+type Config struct {
+	SinkURL  string `help:"where to send analytics events: log to log them, file://path for JSON lines, http(s)://[writekey@]host/path for a Segment-style batch API or kafka+http(s)://host/topics/topic for a Kafka REST proxy. disabled if empty" default:""`
+	OncePath string `help:"path to the database of the events emitted only once, like the first upload of a project" default:"$CONFDIR/analytics.db"`
+}
+
+// Run implements the provider.Responsibility interface. Responsibilities
+// emitting events have to be started after this one.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if c.SinkURL == "" {
+		return server.Run(ctx)
+	}
+
+	sink, err := Open(c.SinkURL, zap.L().Named("analytics"))
+	if err != nil {
+		return err
+	}
+	once, err := boltdb.New(c.OncePath, OnceBucket)
+	if err != nil {
+		return utils.CombineErrors(Error.Wrap(err), sink.Close())
+	}
+	defer func() { _ = once.Close() }()
+
+	events := New(sink, once)
+	defer func() { _ = events.Close() }()
+
+	return server.Run(context.WithValue(ctx, ctxKeyAnalytics, events))
+}
+
+// LoadFromContext loads the Events from the Provider context stack, or nil
+// if analytics aren't configured, in which case no events are emitted
+func LoadFromContext(ctx context.Context) *Events {
+	if v, ok := ctx.Value(ctxKeyAnalytics).(*Events); ok {
+		return v
+	}
+	return nil
+}
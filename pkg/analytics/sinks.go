@@ -0,0 +1,287 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/utils"
+)
+
+// Open opens the sink at rawurl: log writes events to log, file://path
+// appends them to a file as JSON lines, http(s)://[writekey@]host/path posts
+// batches of them to a Segment-style batch API, and
+// kafka+http(s)://host/topics/topic produces them to a Kafka topic through
+// a Kafka REST proxy
+func Open(rawurl string, log *zap.Logger) (Sink, error) {
+	if rawurl == "log" {
+		return &logSink{log: log}, nil
+	}
+	u, err := utils.ParseURL(rawurl)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	switch u.Scheme {
+	case "file":
+		return openFile(strings.TrimPrefix(rawurl, "file://"))
+	case "http", "https":
+		var writeKey string
+		if u.User != nil {
+			writeKey = u.User.Username()
+			u.User = nil
+		}
+		return newHTTPSink(u.String(), http.DefaultClient, segmentBatch{writeKey: writeKey}), nil
+	case "kafka+http", "kafka+https":
+		u.Scheme = strings.TrimPrefix(u.Scheme, "kafka+")
+		return newHTTPSink(u.String(), http.DefaultClient, kafkaRecords{}), nil
+	default:
+		return nil, Error.New("unsupported analytics scheme: %s", u.Scheme)
+	}
+}
+
+// logSink logs events
+type logSink struct {
+	log *zap.Logger
+}
+
+func (sink *logSink) Write(event *Event) error {
+	sink.log.Info("analytics event", zap.String("name", event.Name), zap.Time("time", event.Time),
+		zap.String("user", event.UserID), zap.String("project", event.ProjectID),
+		zap.Any("properties", event.Properties))
+	return nil
+}
+
+func (sink *logSink) Close() error { return nil }
+
+// fileSink appends events to a file as JSON lines
+type fileSink struct {
+	mu   sync.Mutex
+	file *os.File
+	enc  *json.Encoder
+}
+
+func openFile(path string) (*fileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &fileSink{file: file, enc: json.NewEncoder(file)}, nil
+}
+
+func (sink *fileSink) Write(event *Event) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	return Error.Wrap(sink.enc.Encode(event))
+}
+
+func (sink *fileSink) Close() error {
+	return Error.Wrap(sink.file.Close())
+}
+
+// batchFormat formats batches of events for an HTTP API
+type batchFormat interface {
+	// request returns a request posting batch to url
+	request(url string, batch []*Event) (*http.Request, error)
+}
+
+// segmentBatch formats batches of events as track calls of the Segment
+// batch API, authenticated with a write key
+type segmentBatch struct {
+	writeKey string
+}
+
+type segmentTrack struct {
+	Type        string            `json:"type"`
+	Event       string            `json:"event"`
+	UserID      string            `json:"userId,omitempty"`
+	AnonymousID string            `json:"anonymousId,omitempty"`
+	Timestamp   time.Time         `json:"timestamp"`
+	Properties  map[string]string `json:"properties"`
+}
+
+func (format segmentBatch) request(url string, batch []*Event) (*http.Request, error) {
+	tracks := make([]segmentTrack, 0, len(batch))
+	for _, event := range batch {
+		track := segmentTrack{
+			Type:       "track",
+			Event:      event.Name,
+			UserID:     event.UserID,
+			Timestamp:  event.Time,
+			Properties: map[string]string{},
+		}
+		for key, value := range event.Properties {
+			track.Properties[key] = value
+		}
+		if event.ProjectID != "" {
+			track.Properties["project_id"] = event.ProjectID
+		}
+		// events without a user are attributed to their project
+		if track.UserID == "" {
+			track.AnonymousID = event.ProjectID
+		}
+		tracks = append(tracks, track)
+	}
+
+	body, err := json.Marshal(struct {
+		Batch []segmentTrack `json:"batch"`
+	}{tracks})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(format.writeKey, "")
+	return req, nil
+}
+
+// kafkaRecords formats batches of events as records of the Kafka REST proxy,
+// keyed by project so that the events of a project stay in order
+type kafkaRecords struct{}
+
+type kafkaRecord struct {
+	Key   string `json:"key,omitempty"`
+	Value *Event `json:"value"`
+}
+
+func (kafkaRecords) request(url string, batch []*Event) (*http.Request, error) {
+	records := make([]kafkaRecord, 0, len(batch))
+	for _, event := range batch {
+		records = append(records, kafkaRecord{Key: event.ProjectID, Value: event})
+	}
+
+	body, err := json.Marshal(struct {
+		Records []kafkaRecord `json:"records"`
+	}{records})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	return req, nil
+}
+
+const (
+	// httpBatchSize is the maximum number of events posted at once
+	httpBatchSize = 100
+	// httpFlushInterval is how long events wait for a batch to fill up
+	httpFlushInterval = 5 * time.Second
+	// httpQueueSize is how many events wait to be posted before new events
+	// are dropped
+	httpQueueSize = 10000
+)
+
+// httpSink posts batches of events. Events are posted in the background, and
+// dropped if the endpoint can't keep up.
+type httpSink struct {
+	url    string
+	client *http.Client
+	format batchFormat
+
+	mu     sync.Mutex
+	closed bool
+	events chan *Event
+	done   chan struct{}
+}
+
+func newHTTPSink(url string, client *http.Client, format batchFormat) *httpSink {
+	sink := &httpSink{
+		url:    url,
+		client: client,
+		format: format,
+		events: make(chan *Event, httpQueueSize),
+		done:   make(chan struct{}),
+	}
+	go sink.run()
+	return sink
+}
+
+func (sink *httpSink) Write(event *Event) error {
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if sink.closed {
+		return Error.New("closed")
+	}
+	select {
+	case sink.events <- event:
+	default:
+		mon.Counter("analytics_dropped").Inc(1)
+	}
+	return nil
+}
+
+// run posts the queued events until the sink is closed
+func (sink *httpSink) run() {
+	defer close(sink.done)
+	ticker := time.NewTicker(httpFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Event, 0, httpBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := sink.post(batch); err != nil {
+			mon.Counter("analytics_errors").Inc(1)
+		}
+		batch = batch[:0]
+	}
+	for {
+		select {
+		case event, ok := <-sink.events:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, event)
+			if len(batch) >= httpBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// post posts a batch of events
+func (sink *httpSink) post(batch []*Event) error {
+	req, err := sink.format.request(sink.url, batch)
+	if err != nil {
+		return err
+	}
+	resp, err := sink.client.Do(req)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		return Error.New("posting events: %s", resp.Status)
+	}
+	return nil
+}
+
+// Close posts the queued events
+func (sink *httpSink) Close() error {
+	sink.mu.Lock()
+	if !sink.closed {
+		sink.closed = true
+		close(sink.events)
+	}
+	sink.mu.Unlock()
+	<-sink.done
+	return nil
+}
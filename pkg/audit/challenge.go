@@ -0,0 +1,40 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"crypto/rand"
+	"math/big"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/pb"
+)
+
+// NewChallenge picks a random leaf of a piece of pieceSize bytes to
+// challenge its node with
+func NewChallenge(pieceSize int64) (leaf int64, err error) {
+	count := eestream.MerkleLeafCount(pieceSize)
+	if count <= 0 {
+		return 0, Error.New("no leaves to challenge in a piece of %d bytes", pieceSize)
+	}
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(count)))
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	return n.Int64(), nil
+}
+
+// VerifyChallenge checks the answer of a node to the challenge of the leaf
+// of a piece of pieceSize bytes. The leaf must be in the Merkle tree with
+// root, computed by the uplink at upload. The node can only answer with the
+// data of the leaf, as the satellite picks the leaf at random and only the
+// data hashes to the root.
+func VerifyChallenge(root []byte, pieceSize int64, leaf int64, resp *pb.ChallengeResponse) Outcome {
+	count := eestream.MerkleLeafCount(pieceSize)
+	if !eestream.VerifyMerkleProof(root, count, int(leaf), resp.GetLeaf(), resp.GetProof()) {
+		mon.Counter("challenge_failed").Inc(1)
+		return Failed
+	}
+	return Passed
+}
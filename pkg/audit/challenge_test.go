@@ -0,0 +1,60 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/pb"
+)
+
+func TestChallenge(t *testing.T) {
+	data := make([]byte, 3*eestream.LeafSize+10)
+	for i := range data {
+		data[i] = byte(i*7 + i/eestream.LeafSize)
+	}
+	hasher := eestream.NewMerkleHasher()
+	_, _ = hasher.Write(data)
+	leaves := hasher.Leaves()
+	root := eestream.MerkleRoot(leaves)
+	size := int64(len(data))
+
+	leaf, err := NewChallenge(size)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, leaf >= 0 && leaf < 4)
+
+	answer := func(leaf int64) *pb.ChallengeResponse {
+		end := (leaf + 1) * eestream.LeafSize
+		if end > size {
+			end = size
+		}
+		data := data[leaf*eestream.LeafSize : end]
+		return &pb.ChallengeResponse{
+			Leaf:  data,
+			Proof: eestream.MerkleProof(leaves, int(leaf)),
+		}
+	}
+
+	assert.Equal(t, Passed, VerifyChallenge(root, size, leaf, answer(leaf)))
+	assert.Equal(t, Passed, VerifyChallenge(root, size, 3, answer(3)))
+	// the answer to another leaf
+	assert.Equal(t, Failed, VerifyChallenge(root, size, 2, answer(3)))
+	// corrupted data
+	corrupted := answer(1)
+	corrupted.Leaf = append([]byte{}, corrupted.Leaf...)
+	corrupted.Leaf[0]++
+	assert.Equal(t, Failed, VerifyChallenge(root, size, 1, corrupted))
+	// the leaf hashes alone don't answer a challenge
+	hashes := answer(1)
+	hashes.Leaf = leaves[1]
+	assert.Equal(t, Failed, VerifyChallenge(root, size, 1, hashes))
+
+	_, err = NewChallenge(0)
+	assert.Error(t, err)
+}
@@ -0,0 +1,201 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
+)
+
+// Outcome is the result of reverifying a contained node
+type Outcome int
+
+const (
+	// NotContained means that the node had no pending audit
+	NotContained Outcome = iota
+	// Passed means that the node returned the expected erasure share
+	Passed
+	// Failed means that the node returned another erasure share, or missed
+	// its last retry
+	Failed
+	// Pending means that the node missed the audit again, but has retries
+	// left
+	Pending
+)
+
+// String returns the name of the outcome
+func (outcome Outcome) String() string {
+	switch outcome {
+	case NotContained:
+		return "not contained"
+	case Passed:
+		return "passed"
+	case Failed:
+		return "failed"
+	case Pending:
+		return "pending"
+	default:
+		return "unknown"
+	}
+}
+
+// ShareGetter downloads single erasure shares of pieces from nodes
+type ShareGetter interface {
+	// GetShare downloads the stripeIndex-th erasure share of size shareSize
+	// of the piece pieceID stored on the node nodeID
+	GetShare(ctx context.Context, nodeID, pieceID string, stripeIndex int64, shareSize int32) ([]byte, error)
+}
+
+// Challenger challenges nodes to prove they store the data of a leaf of the
+// Merkle tree of a piece
+type Challenger interface {
+	// Challenge returns the answer of the node nodeID to the challenge of
+	// the leaf of the piece pieceID
+	Challenge(ctx context.Context, nodeID, pieceID string, leaf int64) (*pb.ChallengeResponse, error)
+}
+
+// Containment keeps the audits that nodes didn't answer, usually because they
+// timed out or dropped the connection. A contained node is asked for the same
+// erasure share until it answers, and fails the audit after maxReverifyCount
+// misses, so that it can't dodge an audit by not answering it.
+type Containment struct {
+	log              *zap.Logger
+	db               storage.KeyValueStore
+	maxReverifyCount int32
+}
+
+// NewContainment creates a Containment that keeps pending audits in db and
+// fails nodes that miss their pending audit maxReverifyCount times
+func NewContainment(log *zap.Logger, db storage.KeyValueStore, maxReverifyCount int) *Containment {
+	return &Containment{log: log, db: db, maxReverifyCount: int32(maxReverifyCount)}
+}
+
+// ExpectedShareHash returns the hash of an erasure share to keep with the
+// pending audit of a node
+func ExpectedShareHash(share []byte) []byte {
+	hash := sha256.Sum256(share)
+	return hash[:]
+}
+
+// Contain records the audit a node didn't answer. If the node already has a
+// pending audit, it keeps it, so a node can't replace a question it can't
+// answer by missing another audit.
+func (c *Containment) Contain(ctx context.Context, pending *pb.PendingAudit) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	existing, err := c.Get(ctx, pending.GetNodeId())
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+	return c.put(pending)
+}
+
+// Get returns the pending audit of a node, or nil if the node isn't contained
+func (c *Containment) Get(ctx context.Context, nodeID string) (pending *pb.PendingAudit, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	value, err := c.db.Get(storage.Key(nodeID))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, nil
+		}
+		return nil, Error.Wrap(err)
+	}
+
+	pending = &pb.PendingAudit{}
+	if err := proto.Unmarshal(value, pending); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return pending, nil
+}
+
+// Reverify asks a node for the erasure share of its pending audit again. It
+// is meant to be called whenever the satellite contacts the node, before the
+// node is audited anew. Passed and failed audits are removed from
+// containment.
+func (c *Containment) Reverify(ctx context.Context, nodeID string, shares ShareGetter) (outcome Outcome, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return c.reverify(ctx, nodeID, false, func(pending *pb.PendingAudit) (bool, error) {
+		share, err := shares.GetShare(ctx, nodeID, pending.GetPieceId(), pending.GetStripeIndex(), pending.GetShareSize())
+		if err != nil {
+			return false, err
+		}
+		return subtle.ConstantTimeCompare(ExpectedShareHash(share), pending.GetExpectedShareHash()) == 1, nil
+	})
+}
+
+// ReverifyChallenge is Reverify for the nodes contained after they didn't
+// answer the challenge of a leaf of a piece
+func (c *Containment) ReverifyChallenge(ctx context.Context, nodeID string, challenger Challenger) (outcome Outcome, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return c.reverify(ctx, nodeID, true, func(pending *pb.PendingAudit) (bool, error) {
+		resp, err := challenger.Challenge(ctx, nodeID, pending.GetPieceId(), pending.GetStripeIndex())
+		if err != nil {
+			return false, err
+		}
+		return VerifyChallenge(pending.GetMerkleRoot(), pending.GetPieceSize(), pending.GetStripeIndex(), resp) == Passed, nil
+	})
+}
+
+// reverify asks nodeID the question of its pending audit again with ask,
+// which returns whether the node answered it correctly. challenge is whether
+// ask challenges a leaf rather than downloading an erasure share.
+func (c *Containment) reverify(ctx context.Context, nodeID string, challenge bool, ask func(*pb.PendingAudit) (bool, error)) (Outcome, error) {
+	pending, err := c.Get(ctx, nodeID)
+	if err != nil {
+		return NotContained, err
+	}
+	if pending == nil {
+		return NotContained, nil
+	}
+	if challenge != (pending.GetMerkleRoot() != nil) {
+		return NotContained, Error.New("pending audit of node %s can't be asked this way", nodeID)
+	}
+
+	passed, err := ask(pending)
+	if err != nil {
+		pending.ReverifyCount++
+		if pending.ReverifyCount < c.maxReverifyCount {
+			c.log.Debug("contained node missed its pending audit again",
+				zap.String("node", nodeID), zap.Int32("misses", pending.ReverifyCount), zap.Error(err))
+			return Pending, c.put(pending)
+		}
+		c.log.Info("contained node missed all retries of its pending audit", zap.String("node", nodeID))
+		return Failed, c.release(nodeID)
+	}
+
+	outcome := Failed
+	if passed {
+		outcome = Passed
+	}
+	return outcome, c.release(nodeID)
+}
+
+// put stores the pending audit of a node
+func (c *Containment) put(pending *pb.PendingAudit) error {
+	value, err := proto.Marshal(pending)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(c.db.Put(storage.Key(pending.GetNodeId()), value))
+}
+
+// release removes a node from containment
+func (c *Containment) release(nodeID string) error {
+	err := c.db.Delete(storage.Key(nodeID))
+	if err != nil && !storage.ErrKeyNotFound.Has(err) {
+		return Error.Wrap(err)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage/teststore"
+)
+
+type shareGetterFunc func(nodeID string) ([]byte, error)
+
+func (f shareGetterFunc) GetShare(ctx context.Context, nodeID, pieceID string, stripeIndex int64, shareSize int32) ([]byte, error) {
+	return f(nodeID)
+}
+
+func TestContainment(t *testing.T) {
+	ctx := context.Background()
+	containment := NewContainment(zap.NewNop(), teststore.New(), 3)
+
+	share := []byte("share")
+	pending := func(nodeID string) *pb.PendingAudit {
+		return &pb.PendingAudit{
+			NodeId:            nodeID,
+			PieceId:           "piece",
+			StripeIndex:       7,
+			ShareSize:         int32(len(share)),
+			ExpectedShareHash: ExpectedShareHash(share),
+		}
+	}
+
+	timeout := shareGetterFunc(func(string) ([]byte, error) { return nil, errors.New("timeout") })
+	honest := shareGetterFunc(func(string) ([]byte, error) { return share, nil })
+	corrupt := shareGetterFunc(func(string) ([]byte, error) { return []byte("other"), nil })
+
+	outcome, err := containment.Reverify(ctx, "node", honest)
+	assert.NoError(t, err)
+	assert.Equal(t, NotContained, outcome)
+
+	// a contained node is asked the same question until it answers
+	assert.NoError(t, containment.Contain(ctx, pending("honest")))
+	other := pending("honest")
+	other.StripeIndex = 8
+	assert.NoError(t, containment.Contain(ctx, other))
+	got, err := containment.Get(ctx, "honest")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), got.GetStripeIndex())
+
+	outcome, err = containment.Reverify(ctx, "honest", timeout)
+	assert.NoError(t, err)
+	assert.Equal(t, Pending, outcome)
+	outcome, err = containment.Reverify(ctx, "honest", honest)
+	assert.NoError(t, err)
+	assert.Equal(t, Passed, outcome)
+	got, err = containment.Get(ctx, "honest")
+	assert.NoError(t, err)
+	assert.Nil(t, got)
+
+	assert.NoError(t, containment.Contain(ctx, pending("corrupt")))
+	outcome, err = containment.Reverify(ctx, "corrupt", corrupt)
+	assert.NoError(t, err)
+	assert.Equal(t, Failed, outcome)
+
+	// nodes fail after missing all retries
+	assert.NoError(t, containment.Contain(ctx, pending("offline")))
+	for _, expected := range []Outcome{Pending, Pending, Failed, NotContained} {
+		outcome, err = containment.Reverify(ctx, "offline", timeout)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, outcome)
+	}
+}
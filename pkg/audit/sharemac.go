@@ -0,0 +1,56 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/pb"
+)
+
+// ShareSize returns the size of the erasure shares stored on nodes for a
+// segment with the redundancy scheme rs, MAC included
+func ShareSize(rs *pb.RedundancyScheme) int32 {
+	if rs.GetShareMacs() {
+		return rs.GetErasureShareSize() + eestream.MACSize
+	}
+	return rs.GetErasureShareSize()
+}
+
+// paddingSize is the minimum number of bytes eestream.PadReader appends to
+// a segment
+const paddingSize = 4
+
+// PieceSize returns the size of the pieces of a remote pointer
+func PieceSize(pointer *pb.Pointer) int64 {
+	rs := pointer.GetRemote().GetRedundancy()
+	shareSize := int64(rs.GetErasureShareSize())
+	stripeSize := shareSize * int64(rs.GetMinReq())
+	if stripeSize <= 0 {
+		return 0
+	}
+	if rs.GetShareMacs() {
+		shareSize += eestream.MACSize
+	}
+	stripes := (pointer.GetSize() + paddingSize + stripeSize - 1) / stripeSize
+	return stripes * shareSize
+}
+
+// VerifyShareMAC checks an erasure share downloaded to audit the pieceNum-th
+// piece of the segment with root piece id pieceID and redundancy scheme rs.
+// If the shares of the segment have MACs, a share that doesn't match its MAC
+// fails the audit: the node returned a corrupted share, or a share of another
+// stripe, piece or segment. Otherwise the share can only be checked against
+// the other shares of its stripe, and passes.
+func VerifyShareMAC(rs *pb.RedundancyScheme, pieceID string, pieceNum int, stripeIndex int64, share []byte) Outcome {
+	if !rs.GetShareMacs() {
+		return Passed
+	}
+	size := int(rs.GetErasureShareSize())
+	mac := eestream.NewShareMAC([]byte(pieceID))
+	if len(share) != size+eestream.MACSize || !mac.Verify(stripeIndex, pieceNum, share[:size], share[size:]) {
+		mon.Counter("share_mac_mismatch").Inc(1)
+		return Failed
+	}
+	return Passed
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package audit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/pb"
+)
+
+func TestVerifyShareMAC(t *testing.T) {
+	rs := &pb.RedundancyScheme{MinReq: 2, Total: 4, ErasureShareSize: 8, ShareMacs: true}
+	assert.Equal(t, int32(8+eestream.MACSize), ShareSize(rs))
+
+	data := []byte("12345678")
+	share := eestream.NewShareMAC([]byte("piece")).Sum(append([]byte(nil), data...), 3, 1, data)
+
+	assert.Equal(t, Passed, VerifyShareMAC(rs, "piece", 1, 3, share))
+	// replayed from another stripe, swapped from another piece or segment
+	assert.Equal(t, Failed, VerifyShareMAC(rs, "piece", 1, 2, share))
+	assert.Equal(t, Failed, VerifyShareMAC(rs, "piece", 2, 3, share))
+	assert.Equal(t, Failed, VerifyShareMAC(rs, "other", 1, 3, share))
+	// truncated
+	assert.Equal(t, Failed, VerifyShareMAC(rs, "piece", 1, 3, share[:8]))
+
+	// shares without MACs can't be checked on their own
+	rs.ShareMacs = false
+	assert.Equal(t, int32(8), ShareSize(rs))
+	assert.Equal(t, Passed, VerifyShareMAC(rs, "piece", 1, 2, data))
+}
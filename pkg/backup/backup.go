@@ -0,0 +1,131 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for backups
+	Error = errs.Class("backup error")
+)
+
+// snapshotTimeFormat names snapshots so they sort by creation time
+const snapshotTimeFormat = "20060102T150405Z"
+
+// Service takes snapshots of the pointers seen by a metainfo loop
+type Service struct {
+	log  *zap.Logger
+	loop *metainfo.Loop
+	dest Destination
+}
+
+// NewService creates a Service storing snapshots of the pointers of loop in
+// dest
+func NewService(log *zap.Logger, loop *metainfo.Loop, dest Destination) *Service {
+	return &Service{log: log, loop: loop, dest: dest}
+}
+
+// Backup takes a snapshot of the pointers on the next iteration of the loop.
+// The pointers are spooled to a temporary file, as the loop can't wait for
+// the upload.
+func (service *Service) Backup(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	tmp, err := ioutil.TempFile("", "storj-backup")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		err = utils.CombineErrors(err, tmp.Close(), os.Remove(tmp.Name()))
+	}()
+
+	created := time.Now().UTC()
+	hash := sha256.New()
+	zw := gzip.NewWriter(io.MultiWriter(tmp, hash))
+	obs := &snapshotObserver{records: newRecordWriter(zw)}
+
+	if err := service.loop.Join(ctx, obs); err != nil {
+		return err
+	}
+	if err := obs.records.Flush(); err != nil {
+		return Error.Wrap(err)
+	}
+	if err := zw.Close(); err != nil {
+		return Error.Wrap(err)
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return Error.Wrap(err)
+	}
+
+	manifest := Manifest{
+		Name:           created.Format(snapshotTimeFormat),
+		Created:        created,
+		PointerVersion: pointerdb.PointerVersion,
+		Count:          obs.count,
+		Size:           size,
+		SHA256:         hex.EncodeToString(hash.Sum(nil)),
+	}
+	if err := service.dest.Put(path.Join(manifest.Name, pointersObject), tmp, size); err != nil {
+		return err
+	}
+
+	// the manifest is stored last, as it marks the snapshot complete
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if err := service.dest.Put(path.Join(manifest.Name, manifestObject), bytes.NewReader(data), int64(len(data))); err != nil {
+		return err
+	}
+
+	service.log.Info("stored snapshot", zap.String("name", manifest.Name),
+		zap.Int64("pointers", manifest.Count), zap.Int64("size", manifest.Size))
+	return nil
+}
+
+// snapshotObserver writes the pointers of an iteration as records
+type snapshotObserver struct {
+	records *recordWriter
+	count   int64
+}
+
+func (obs *snapshotObserver) Pointer(ctx context.Context, path storage.Key, pointer *pb.Pointer) error {
+	value, err := pointerdb.MarshalPointer(pointer)
+	if err != nil {
+		return err
+	}
+	if err := obs.records.Write(path, value); err != nil {
+		return Error.Wrap(err)
+	}
+	obs.count++
+	return nil
+}
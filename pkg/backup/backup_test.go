@@ -0,0 +1,114 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package backup
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func putPointer(t *testing.T, db storage.KeyValueStore, path string, size int64) {
+	value, err := pointerdb.MarshalPointer(&pb.Pointer{Type: pb.Pointer_INLINE, Size: size})
+	if assert.NoError(t, err) {
+		assert.NoError(t, db.Put(storage.Key(path), value))
+	}
+}
+
+func TestBackupRestore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dir, err := ioutil.TempDir("", "storj-backup")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	dest, err := OpenDestination("file://" + filepath.ToSlash(dir))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	db := teststore.New()
+	for i, path := range []string{"l/a/x", "l/b/y", "s0/a/x"} {
+		putPointer(t, db, path, int64(i))
+	}
+
+	loop := metainfo.NewLoop(metainfo.Config{CoalesceDuration: time.Millisecond}, db)
+	go func() { _ = loop.Run(ctx) }()
+
+	service := NewService(zap.NewNop(), loop, dest)
+	if !assert.NoError(t, service.Backup(ctx)) {
+		return
+	}
+
+	name, err := Latest(dest)
+	if !assert.NoError(t, err) {
+		return
+	}
+	snapshots, err := Snapshots(dest)
+	if assert.NoError(t, err) && assert.Len(t, snapshots, 1) {
+		assert.Equal(t, name, snapshots[0].Name)
+		assert.EqualValues(t, 3, snapshots[0].Count)
+	}
+
+	restored := teststore.New()
+	manifest, err := Restore(ctx, dest, name, restored)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, manifest.Count)
+
+	report, err := Verify(ctx, dest, name, restored)
+	assert.NoError(t, err)
+	assert.Equal(t, Report{Checked: 3}, report)
+
+	assert.NoError(t, restored.Delete(storage.Key("l/a/x")))
+	putPointer(t, restored, "l/b/y", 10)
+	putPointer(t, restored, "s1/a/x", 0)
+
+	report, err = Verify(ctx, dest, name, restored)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"l/a/x"}, report.Missing)
+	assert.Equal(t, []string{"l/b/y"}, report.Different)
+	assert.Equal(t, []string{"s1/a/x"}, report.Extra)
+}
+
+func TestCorruptSnapshot(t *testing.T) {
+	ctx := context.Background()
+
+	dir, err := ioutil.TempDir("", "storj-backup")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	dest, err := NewDirDestination(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// a snapshot without a manifest is incomplete
+	pointers := filepath.Join(dir, "20180101T000000Z", pointersObject)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(pointers), 0700))
+	assert.NoError(t, ioutil.WriteFile(pointers, []byte("garbage"), 0600))
+	_, err = Latest(dest)
+	assert.Error(t, err)
+
+	manifest := filepath.Join(dir, "20180101T000000Z", manifestObject)
+	assert.NoError(t, ioutil.WriteFile(manifest, []byte(`{"name":"20180101T000000Z","count":1}`), 0600))
+	_, err = Restore(ctx, dest, "20180101T000000Z", teststore.New())
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package backup
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/chore"
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/provider"
+)
+
+// Config contains everything necessary to start the metainfo backup
+// responsibility
+type Config struct {
+	URL      string        `help:"where pointerdb snapshots are stored, as file:///DIR or s3://ACCESSKEY:SECRETKEY@HOST/BUCKET[/PREFIX]. backups are disabled if empty" default:""`
+	Interval time.Duration `help:"how frequently pointerdb snapshots are taken" default:"24h"`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// metainfo loop responsibility has been started before this one.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if c.URL == "" {
+		return server.Run(ctx)
+	}
+
+	loop := metainfo.LoadFromContext(ctx)
+	if loop == nil {
+		return Error.New("programmer error: metainfo loop responsibility unstarted")
+	}
+
+	dest, err := OpenDestination(c.URL)
+	if err != nil {
+		return err
+	}
+	service := NewService(zap.L().Named("backup"), loop, dest)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	backup, err := chore.New(ctx, "backup", c.Interval, service.Backup)
+	if err != nil {
+		return err
+	}
+	go func() { _ = backup.Run(ctx) }()
+
+	return server.Run(ctx)
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package backup
+
+import (
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	minio "github.com/minio/minio-go"
+
+	"storj.io/storj/pkg/utils"
+)
+
+// Destination is where snapshots are stored
+type Destination interface {
+	// Put stores the size bytes of r as the object name
+	Put(name string, r io.Reader, size int64) error
+	// Get returns the contents of the object name
+	Get(name string) (io.ReadCloser, error)
+	// List returns the names of the objects that start with prefix, sorted
+	List(prefix string) ([]string, error)
+}
+
+// OpenDestination opens the destination at rawurl, which is either
+// file:///DIR or s3://ACCESSKEY:SECRETKEY@HOST/BUCKET[/PREFIX]. Use
+// s3+http:// for S3 compatible servers without TLS.
+func OpenDestination(rawurl string) (Destination, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return NewDirDestination(u.Path)
+	case "s3", "s3+http":
+		secret, _ := u.User.Password()
+		client, err := minio.New(u.Host, u.User.Username(), secret, u.Scheme == "s3")
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		parts := strings.SplitN(strings.Trim(u.Path, "/"), "/", 2)
+		if parts[0] == "" {
+			return nil, Error.New("missing bucket in %q", rawurl)
+		}
+		dest := &s3Destination{client: client, bucket: parts[0]}
+		if len(parts) == 2 {
+			dest.prefix = parts[1] + "/"
+		}
+		return dest, nil
+	default:
+		return nil, Error.New("unsupported destination %q", rawurl)
+	}
+}
+
+// dirDestination stores snapshots in a local directory
+type dirDestination struct {
+	dir string
+}
+
+// NewDirDestination returns a Destination storing snapshots in dir
+func NewDirDestination(dir string) (Destination, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &dirDestination{dir: dir}, nil
+}
+
+func (d *dirDestination) Put(name string, r io.Reader, size int64) (err error) {
+	target := filepath.Join(d.dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		return Error.Wrap(err)
+	}
+
+	// objects are written to a temporary file first, so an interrupted Put
+	// leaves no partial object behind
+	tmp, err := ioutil.TempFile(filepath.Dir(target), ".put")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	n, err := io.Copy(tmp, r)
+	if err != nil {
+		return Error.Wrap(utils.CombineErrors(err, tmp.Close()))
+	}
+	if err := tmp.Close(); err != nil {
+		return Error.Wrap(err)
+	}
+	if n != size {
+		return Error.New("%s: wrote %d bytes of %d", name, n, size)
+	}
+	return Error.Wrap(os.Rename(tmp.Name(), target))
+}
+
+func (d *dirDestination) Get(name string) (io.ReadCloser, error) {
+	f, err := os.Open(filepath.Join(d.dir, filepath.FromSlash(name)))
+	return f, Error.Wrap(err)
+}
+
+func (d *dirDestination) List(prefix string) (names []string, err error) {
+	err = filepath.Walk(d.dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".") {
+			return nil
+		}
+		rel, err := filepath.Rel(d.dir, p)
+		if err != nil {
+			return err
+		}
+		if name := filepath.ToSlash(rel); strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	sort.Strings(names)
+	return names, Error.Wrap(err)
+}
+
+// s3Destination stores snapshots in an S3 compatible bucket
+type s3Destination struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func (d *s3Destination) Put(name string, r io.Reader, size int64) error {
+	_, err := d.client.PutObject(d.bucket, d.prefix+name, r, size, minio.PutObjectOptions{})
+	return Error.Wrap(err)
+}
+
+func (d *s3Destination) Get(name string) (io.ReadCloser, error) {
+	obj, err := d.client.GetObject(d.bucket, d.prefix+name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	// GetObject doesn't fail on missing objects until they're read
+	if _, err := obj.Stat(); err != nil {
+		return nil, Error.Wrap(utils.CombineErrors(err, obj.Close()))
+	}
+	return obj, nil
+}
+
+func (d *s3Destination) List(prefix string) (names []string, err error) {
+	done := make(chan struct{})
+	defer close(done)
+
+	for info := range d.client.ListObjectsV2(d.bucket, d.prefix+prefix, true, done) {
+		if info.Err != nil {
+			return nil, Error.Wrap(info.Err)
+		}
+		names = append(names, strings.TrimPrefix(info.Key, d.prefix))
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// snapshotNames returns the names of the complete snapshots at dest, oldest
+// first
+func snapshotNames(dest Destination) ([]string, error) {
+	objects, err := dest.List("")
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, object := range objects {
+		if path.Base(object) == manifestObject {
+			names = append(names, path.Dir(object))
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package backup
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"time"
+)
+
+// A snapshot NAME consists of two objects at the destination:
+//
+//	NAME/pointers       the gzip compressed pointer records, ordered by path
+//	NAME/manifest.json  the Manifest, written once the pointers are stored
+//
+// Each pointer record is the uvarint length of the path, the path, the
+// uvarint length of the pointer and the protobuf encoded pb.Pointer of
+// version Manifest.PointerVersion. A snapshot without a manifest is
+// incomplete and is ignored.
+const (
+	pointersObject = "pointers"
+	manifestObject = "manifest.json"
+)
+
+// Manifest describes a snapshot of the pointers
+type Manifest struct {
+	Name           string    `json:"name"`
+	Created        time.Time `json:"created"`
+	PointerVersion int32     `json:"pointer_version"`
+	Count          int64     `json:"count"`
+	// Size and SHA256 are those of the compressed pointer records
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// recordWriter writes pointer records
+type recordWriter struct {
+	w   *bufio.Writer
+	buf [binary.MaxVarintLen64]byte
+}
+
+func newRecordWriter(w io.Writer) *recordWriter {
+	return &recordWriter{w: bufio.NewWriter(w)}
+}
+
+// Write writes the record of the pointer value at path
+func (rw *recordWriter) Write(path, value []byte) error {
+	for _, field := range [][]byte{path, value} {
+		n := binary.PutUvarint(rw.buf[:], uint64(len(field)))
+		if _, err := rw.w.Write(rw.buf[:n]); err != nil {
+			return err
+		}
+		if _, err := rw.w.Write(field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Flush writes the buffered records
+func (rw *recordWriter) Flush() error {
+	return rw.w.Flush()
+}
+
+// recordReader reads pointer records
+type recordReader struct {
+	r *bufio.Reader
+}
+
+func newRecordReader(r io.Reader) *recordReader {
+	return &recordReader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next record, or io.EOF after the last one
+func (rr *recordReader) Next() (path, value []byte, err error) {
+	path, err = rr.field()
+	if err != nil {
+		return nil, nil, err
+	}
+	value, err = rr.field()
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return path, value, err
+}
+
+func (rr *recordReader) field() ([]byte, error) {
+	size, err := binary.ReadUvarint(rr.r)
+	if err != nil {
+		return nil, err
+	}
+	field := make([]byte, size)
+	if _, err := io.ReadFull(rr.r, field); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return field, nil
+}
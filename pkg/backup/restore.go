@@ -0,0 +1,208 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"path"
+
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+// Snapshots returns the manifests of the complete snapshots at dest, oldest
+// first
+func Snapshots(dest Destination) ([]Manifest, error) {
+	names, err := snapshotNames(dest)
+	if err != nil {
+		return nil, err
+	}
+	manifests := make([]Manifest, 0, len(names))
+	for _, name := range names {
+		manifest, err := loadManifest(dest, name)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
+
+// Latest returns the name of the latest complete snapshot at dest
+func Latest(dest Destination) (string, error) {
+	names, err := snapshotNames(dest)
+	if err != nil {
+		return "", err
+	}
+	if len(names) == 0 {
+		return "", Error.New("no snapshots found")
+	}
+	return names[len(names)-1], nil
+}
+
+// loadManifest reads the manifest of the snapshot name
+func loadManifest(dest Destination, name string) (manifest Manifest, err error) {
+	r, err := dest.Get(path.Join(name, manifestObject))
+	if err != nil {
+		return manifest, err
+	}
+	defer func() { err = utils.CombineErrors(err, r.Close()) }()
+
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return manifest, Error.New("invalid manifest of %s: %v", name, err)
+	}
+	return manifest, nil
+}
+
+// readSnapshot calls fn with every record of the snapshot name, in order.
+// The records are checked against the manifest, so fn may see the records
+// of a corrupt snapshot before readSnapshot fails.
+func readSnapshot(dest Destination, name string, fn func(path, value []byte) error) (manifest Manifest, err error) {
+	manifest, err = loadManifest(dest, name)
+	if err != nil {
+		return manifest, err
+	}
+	if manifest.PointerVersion > pointerdb.PointerVersion {
+		return manifest, Error.New("snapshot %s has pointers of version %d, the supported version is %d",
+			name, manifest.PointerVersion, pointerdb.PointerVersion)
+	}
+
+	r, err := dest.Get(path.Join(name, pointersObject))
+	if err != nil {
+		return manifest, err
+	}
+	defer func() { err = utils.CombineErrors(err, r.Close()) }()
+
+	hash := sha256.New()
+	zr, err := gzip.NewReader(io.TeeReader(r, hash))
+	if err != nil {
+		return manifest, Error.Wrap(err)
+	}
+	records := newRecordReader(zr)
+
+	var count int64
+	for {
+		path, value, err := records.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, Error.Wrap(err)
+		}
+		if err := fn(path, value); err != nil {
+			return manifest, err
+		}
+		count++
+	}
+
+	// the gzip trailer may not have been read through the hash yet
+	if _, err := io.Copy(ioutil.Discard, io.TeeReader(r, hash)); err != nil {
+		return manifest, Error.Wrap(err)
+	}
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != manifest.SHA256 {
+		return manifest, Error.New("snapshot %s is corrupt: sha256 is %s, the manifest has %s", name, sum, manifest.SHA256)
+	}
+	if count != manifest.Count {
+		return manifest, Error.New("snapshot %s is corrupt: has %d pointers, the manifest has %d", name, count, manifest.Count)
+	}
+	return manifest, nil
+}
+
+// normalize upgrades a pointer to the current format, so pointers of
+// different versions compare equal
+func normalize(value []byte) ([]byte, error) {
+	pointer, _, err := pointerdb.UnmarshalPointer(value)
+	if err != nil {
+		return nil, err
+	}
+	return pointerdb.MarshalPointer(pointer)
+}
+
+// Restore writes the pointers of the snapshot name at dest to db. Pointers
+// in db that aren't in the snapshot are left alone, so restore to an empty
+// database to rebuild pointerdb as it was.
+func Restore(ctx context.Context, dest Destination, name string, db storage.KeyValueStore) (manifest Manifest, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return readSnapshot(dest, name, func(path, value []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		value, err := normalize(value)
+		if err != nil {
+			return err
+		}
+		return db.Put(storage.Key(path), storage.Value(value))
+	})
+}
+
+// Report lists the differences between a snapshot and a database
+type Report struct {
+	Checked int64
+	// Missing are the paths in the snapshot that aren't in the database
+	Missing []string
+	// Different are the paths whose pointer changed
+	Different []string
+	// Extra are the paths in the database that aren't in the snapshot
+	Extra []string
+}
+
+// Verify compares the pointers of the snapshot name at dest with the ones in
+// db. Both are in path order, so they're merged without holding either in
+// memory.
+func Verify(ctx context.Context, dest Destination, name string, db storage.KeyValueStore) (report Report, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.Iterate(storage.IterateOptions{Recurse: true}, func(it storage.Iterator) error {
+		var item storage.ListItem
+		more := it.Next(&item)
+
+		_, err := readSnapshot(dest, name, func(path, value []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			report.Checked++
+
+			for more && bytes.Compare(item.Key, path) < 0 {
+				report.Extra = append(report.Extra, item.Key.String())
+				more = it.Next(&item)
+			}
+			if !more || !bytes.Equal(item.Key, path) {
+				report.Missing = append(report.Missing, string(path))
+				return nil
+			}
+
+			expected, err := normalize(value)
+			if err != nil {
+				return err
+			}
+			actual, err := normalize(item.Value)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(expected, actual) {
+				report.Different = append(report.Different, string(path))
+			}
+			more = it.Next(&item)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for ; more; more = it.Next(&item) {
+			report.Extra = append(report.Extra, item.Key.String())
+		}
+		return nil
+	})
+	return report, err
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bloomfilter
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+
+	"github.com/zeebo/errs"
+)
+
+// Error is the default error class for bloom filters
+var Error = errs.Class("bloom filter error")
+
+const (
+	version = 1
+
+	// maxHashCount bounds the number of hash functions, which also bounds
+	// the cost of Add and Contains
+	maxHashCount = 32
+)
+
+// Filter is a bloom filter over piece ids. A filter never reports a false
+// negative, so every id that was added is always reported as contained.
+type Filter struct {
+	hashCount int
+	table     []byte
+}
+
+// NewOptimal returns a filter sized for expectedElements with the given
+// false positive rate
+func NewOptimal(expectedElements int, falsePositiveRate float64) *Filter {
+	if expectedElements < 1 {
+		expectedElements = 1
+	}
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		falsePositiveRate = 0.1
+	}
+
+	bits := -float64(expectedElements) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)
+	hashCount := int(math.Ceil(bits / float64(expectedElements) * math.Ln2))
+	if hashCount < 1 {
+		hashCount = 1
+	}
+	if hashCount > maxHashCount {
+		hashCount = maxHashCount
+	}
+
+	return &Filter{
+		hashCount: hashCount,
+		table:     make([]byte, int(math.Ceil(bits/8))),
+	}
+}
+
+// NewFromBytes decodes a filter serialized with Bytes
+func NewFromBytes(data []byte) (*Filter, error) {
+	if len(data) < 3 {
+		return nil, Error.New("not enough data")
+	}
+	if data[0] != version {
+		return nil, Error.New("unsupported version %d", data[0])
+	}
+	hashCount := int(data[1])
+	if hashCount < 1 || hashCount > maxHashCount {
+		return nil, Error.New("invalid hash count %d", hashCount)
+	}
+
+	table := make([]byte, len(data)-2)
+	copy(table, data[2:])
+	return &Filter{hashCount: hashCount, table: table}, nil
+}
+
+// Add adds id to the filter
+func (filter *Filter) Add(id []byte) {
+	h1, h2 := hash(id)
+	bits := uint64(len(filter.table)) * 8
+	for i := 0; i < filter.hashCount; i++ {
+		bit := (h1 + uint64(i)*h2) % bits
+		filter.table[bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Contains returns whether id may have been added to the filter
+func (filter *Filter) Contains(id []byte) bool {
+	h1, h2 := hash(id)
+	bits := uint64(len(filter.table)) * 8
+	for i := 0; i < filter.hashCount; i++ {
+		bit := (h1 + uint64(i)*h2) % bits
+		if filter.table[bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Size returns the size of the serialized filter in bytes
+func (filter *Filter) Size() int64 {
+	return int64(len(filter.table)) + 2
+}
+
+// Bytes serializes the filter
+func (filter *Filter) Bytes() []byte {
+	data := make([]byte, 0, filter.Size())
+	data = append(data, version, byte(filter.hashCount))
+	return append(data, filter.table...)
+}
+
+// hash returns the two base hashes used for double hashing
+func hash(id []byte) (h1, h2 uint64) {
+	sum := sha256.Sum256(id)
+	h1 = binary.BigEndian.Uint64(sum[0:8])
+	// h2 must be odd so that the probed bits are spread over the whole table
+	h2 = binary.BigEndian.Uint64(sum[8:16]) | 1
+	return h1, h2
+}
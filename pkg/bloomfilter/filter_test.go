@@ -0,0 +1,72 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package bloomfilter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoFalseNegatives(t *testing.T) {
+	filter := NewOptimal(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		filter.Add([]byte(fmt.Sprintf("piece-%d", i)))
+	}
+	for i := 0; i < 1000; i++ {
+		assert.True(t, filter.Contains([]byte(fmt.Sprintf("piece-%d", i))))
+	}
+}
+
+func TestFalsePositiveRate(t *testing.T) {
+	for _, rate := range []float64{0.1, 0.05, 0.01} {
+		filter := NewOptimal(10000, rate)
+		for i := 0; i < 10000; i++ {
+			filter.Add([]byte(fmt.Sprintf("piece-%d", i)))
+		}
+
+		positives := 0
+		for i := 0; i < 10000; i++ {
+			if filter.Contains([]byte(fmt.Sprintf("other-%d", i))) {
+				positives++
+			}
+		}
+
+		observed := float64(positives) / 10000
+		assert.True(t, observed < rate*2, fmt.Sprintf("rate %v: observed %v", rate, observed))
+	}
+}
+
+func TestBytes(t *testing.T) {
+	filter := NewOptimal(100, 0.1)
+	for i := 0; i < 100; i++ {
+		filter.Add([]byte(fmt.Sprintf("piece-%d", i)))
+	}
+
+	data := filter.Bytes()
+	assert.Equal(t, filter.Size(), int64(len(data)))
+
+	decoded, err := NewFromBytes(data)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, filter, decoded)
+	for i := 0; i < 100; i++ {
+		assert.True(t, decoded.Contains([]byte(fmt.Sprintf("piece-%d", i))))
+	}
+}
+
+func TestNewFromBytesInvalid(t *testing.T) {
+	for _, data := range [][]byte{
+		nil,
+		{version, 1},
+		{version + 1, 1, 0},
+		{version, 0, 0},
+		{version, maxHashCount + 1, 0},
+	} {
+		_, err := NewFromBytes(data)
+		assert.Error(t, err)
+	}
+}
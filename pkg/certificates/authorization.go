@@ -0,0 +1,128 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package certificates
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/mr-tron/base58/base58"
+
+	"storj.io/storj/storage"
+)
+
+const (
+	tokenSize    = 24
+	tokensPrefix = "tokens/"
+	nodesPrefix  = "nodes/"
+)
+
+// Authorization authorizes a node to have its certificate authority signed
+// once
+type Authorization struct {
+	Token   string    `json:"token"`
+	Created time.Time `json:"created"`
+	// NodeID and Claimed are set once the token was claimed
+	NodeID  string    `json:"node_id,omitempty"`
+	Claimed time.Time `json:"claimed,omitempty"`
+}
+
+// AuthorizationDB stores the authorization tokens by token, and the tokens
+// claimed by the nodes that claimed them
+type AuthorizationDB struct {
+	db storage.KeyValueStore
+	mu sync.Mutex
+}
+
+// NewAuthorizationDB creates an AuthorizationDB stored in db
+func NewAuthorizationDB(db storage.KeyValueStore) *AuthorizationDB {
+	return &AuthorizationDB{db: db}
+}
+
+// Create creates count new authorization tokens
+func (auths *AuthorizationDB) Create(count int) ([]string, error) {
+	tokens := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		b := make([]byte, tokenSize)
+		if _, err := rand.Read(b); err != nil {
+			return tokens, Error.Wrap(err)
+		}
+		auth := Authorization{Token: base58.Encode(b), Created: time.Now().UTC()}
+		if err := auths.put(auth); err != nil {
+			return tokens, err
+		}
+		tokens = append(tokens, auth.Token)
+	}
+	return tokens, nil
+}
+
+// Get returns the authorization of token
+func (auths *AuthorizationDB) Get(token string) (*Authorization, error) {
+	if token == "" {
+		return nil, ErrAuthorization.New("no token")
+	}
+	value, err := auths.db.Get(storage.Key(tokensPrefix + token))
+	if storage.ErrKeyNotFound.Has(err) {
+		return nil, ErrAuthorization.New("unknown token")
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	auth := &Authorization{}
+	if err := json.Unmarshal(value, auth); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return auth, nil
+}
+
+// Claim claims token for nodeID. A token can only be claimed once, and a
+// node can only claim one token.
+func (auths *AuthorizationDB) Claim(token, nodeID string) error {
+	auths.mu.Lock()
+	defer auths.mu.Unlock()
+
+	auth, err := auths.Get(token)
+	if err != nil {
+		return err
+	}
+	if auth.NodeID != "" {
+		return ErrAuthorization.New("token already claimed")
+	}
+	signed, err := auths.IsSigned(nodeID)
+	if err != nil {
+		return err
+	}
+	if signed {
+		return ErrAuthorization.New("node %s already claimed a token", nodeID)
+	}
+
+	auth.NodeID, auth.Claimed = nodeID, time.Now().UTC()
+	if err := auths.put(*auth); err != nil {
+		return err
+	}
+	return Error.Wrap(auths.db.Put(storage.Key(nodesPrefix+nodeID), storage.Value(token)))
+}
+
+// IsSigned returns whether nodeID claimed a token, and so had its
+// certificate authority signed
+func (auths *AuthorizationDB) IsSigned(nodeID string) (bool, error) {
+	_, err := auths.db.Get(storage.Key(nodesPrefix + nodeID))
+	if storage.ErrKeyNotFound.Has(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	return true, nil
+}
+
+func (auths *AuthorizationDB) put(auth Authorization) error {
+	value, err := json.Marshal(auth)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(auths.db.Put(storage.Key(tokensPrefix+auth.Token), storage.Value(value)))
+}
@@ -0,0 +1,95 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/storage/teststore"
+)
+
+func newCA(t *testing.T) *provider.FullCertificateAuthority {
+	ca, err := provider.NewCA(context.Background(), 12, 4)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return ca
+}
+
+func TestSign(t *testing.T) {
+	ctx := context.Background()
+	signer, node, other := newCA(t), newCA(t), newCA(t)
+	auths := NewAuthorizationDB(teststore.New())
+	s := NewServer(zap.NewNop(), signer, auths, 12)
+
+	tokens, err := auths.Create(2)
+	if !assert.NoError(t, err) || !assert.Len(t, tokens, 2) {
+		t.FailNow()
+	}
+
+	csr, err := node.CertificateRequest()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	_, err = s.Sign(ctx, &pb.SigningRequest{AuthToken: "unknown", Csr: csr})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.Sign(ctx, &pb.SigningRequest{AuthToken: tokens[0], Csr: []byte("csr")})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = NewServer(zap.NewNop(), signer, auths, 200).Sign(ctx, &pb.SigningRequest{AuthToken: tokens[0], Csr: csr})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	signed, err := auths.IsSigned(node.ID.String())
+	assert.NoError(t, err)
+	assert.False(t, signed)
+
+	resp, err := s.Sign(ctx, &pb.SigningRequest{AuthToken: tokens[0], Csr: csr})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	chain, err := provider.ParseCertChain(resp.GetChain())
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, node.SetSignedChain(chain))
+	assert.Error(t, other.SetSignedChain(chain))
+
+	fi, err := node.NewIdentity()
+	if assert.NoError(t, err) {
+		certs, err := provider.ParseCertChain(fi.Chain())
+		assert.NoError(t, err)
+		assert.Len(t, certs, 3)
+		assert.NoError(t, peertls.VerifyPeerCertChains(nil, [][]*x509.Certificate{certs}))
+	}
+
+	signed, err = auths.IsSigned(node.ID.String())
+	assert.NoError(t, err)
+	assert.True(t, signed)
+
+	auth, err := auths.Get(tokens[0])
+	if assert.NoError(t, err) {
+		assert.Equal(t, node.ID.String(), auth.NodeID)
+	}
+
+	otherCSR, err := other.CertificateRequest()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_, err = s.Sign(ctx, &pb.SigningRequest{AuthToken: tokens[0], Csr: otherCSR})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.Sign(ctx, &pb.SigningRequest{AuthToken: tokens[1], Csr: csr})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.Sign(ctx, &pb.SigningRequest{AuthToken: tokens[1], Csr: otherCSR})
+	assert.NoError(t, err)
+}
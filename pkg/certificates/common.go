@@ -0,0 +1,17 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package certificates
+
+import (
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for the certificate signing service
+	Error = errs.Class("certificates error")
+	// ErrAuthorization is returned for tokens that can't be claimed
+	ErrAuthorization = errs.Class("authorization error")
+)
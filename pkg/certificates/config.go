@@ -0,0 +1,87 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package certificates
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage/boltdb"
+)
+
+// AuthorizationBucket is the bucket the authorizations are stored in
+const AuthorizationBucket = "authorizations"
+
+// CtxKey is used as the key of the AuthorizationDB in the context
+type CtxKey int
+
+const (
+	ctxKeyAuthorizations CtxKey = iota
+)
+
+// Config contains everything necessary to run the certificate signing
+// service
+type Config struct {
+	Enabled       bool   `help:"whether the certificate authorities of authorized nodes are signed" default:"false"`
+	CertPath      string `help:"path to the certificate chain of the signing certificate authority" default:"$CONFDIR/signer.cert"`
+	KeyPath       string `help:"path to the private key of the signing certificate authority" default:"$CONFDIR/signer.key"`
+	DatabaseURL   string `help:"the database connection string of the authorization tokens" default:"bolt://$CONFDIR/authorizations.db"`
+	MinDifficulty uint   `help:"minimum difficulty of the certificate authorities that are signed" default:"12"`
+}
+
+// OpenAuthorizationDB opens the authorization database of the config
+func (c Config) OpenAuthorizationDB() (*AuthorizationDB, func() error, error) {
+	dburl, err := utils.ParseURL(c.DatabaseURL)
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+	if dburl.Scheme != "bolt" {
+		return nil, nil, Error.New("unsupported db scheme: %s", dburl.Scheme)
+	}
+	bdb, err := boltdb.New(dburl.Path, AuthorizationBucket)
+	if err != nil {
+		return nil, nil, Error.Wrap(err)
+	}
+	return NewAuthorizationDB(bdb), bdb.Close, nil
+}
+
+// Run implements the provider.Responsibility interface. Responsibilities
+// started after it can check which nodes have signed identities with
+// LoadFromContext.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !c.Enabled {
+		return server.Run(ctx)
+	}
+
+	ca, err := provider.FullCAConfig{CertPath: c.CertPath, KeyPath: c.KeyPath}.Load()
+	if err != nil {
+		return err
+	}
+
+	auths, closeDB, err := c.OpenAuthorizationDB()
+	if err != nil {
+		return err
+	}
+	defer func() { err = utils.CombineErrors(err, closeDB()) }()
+
+	s := NewServer(zap.L().Named("certificates"), ca, auths, uint16(c.MinDifficulty))
+	pb.RegisterCertificatesServer(server.GRPC(), s)
+
+	return server.Run(context.WithValue(ctx, ctxKeyAuthorizations, auths))
+}
+
+// LoadFromContext loads an existing AuthorizationDB from the Provider
+// context stack if one exists.
+func LoadFromContext(ctx context.Context) *AuthorizationDB {
+	if v, ok := ctx.Value(ctxKeyAuthorizations).(*AuthorizationDB); ok {
+		return v
+	}
+	return nil
+}
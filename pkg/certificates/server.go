@@ -0,0 +1,79 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package certificates
+
+import (
+	"context"
+	"crypto/x509"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls"
+	"storj.io/storj/pkg/provider"
+)
+
+// Server implements the certificate signing service. It signs the
+// certificate authorities of nodes with its own, in exchange for an
+// authorization token.
+type Server struct {
+	log           *zap.Logger
+	ca            *provider.FullCertificateAuthority
+	auths         *AuthorizationDB
+	minDifficulty uint16
+}
+
+// NewServer creates a certificate signing service that signs with ca the
+// certificate authorities of at least minDifficulty
+func NewServer(log *zap.Logger, ca *provider.FullCertificateAuthority, auths *AuthorizationDB, minDifficulty uint16) *Server {
+	return &Server{log: log, ca: ca, auths: auths, minDifficulty: minDifficulty}
+}
+
+// Sign claims the authorization token of the request and returns the signed
+// certificate authority of the node that signed the certificate request
+func (s *Server) Sign(ctx context.Context, req *pb.SigningRequest) (resp *pb.SigningResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	csr, err := x509.ParseCertificateRequest(req.GetCsr())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid certificate request: %v", err)
+	}
+	if err := csr.CheckSignature(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid certificate request: %v", err)
+	}
+
+	nodeID, difficulty, err := provider.NodeIDFromKey(csr.PublicKey)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if difficulty < s.minDifficulty {
+		return nil, status.Errorf(codes.InvalidArgument, "difficulty %d is below %d", difficulty, s.minDifficulty)
+	}
+
+	template, err := peertls.CATemplate()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	signed, err := peertls.NewCert(template, s.ca.Cert, csr.PublicKey, s.ca.Key)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	if err := s.auths.Claim(req.GetAuthToken(), nodeID); err != nil {
+		if ErrAuthorization.Has(err) {
+			return nil, status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		s.log.Error("err claiming authorization", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	s.log.Info("signed certificate authority", zap.String("node", nodeID))
+
+	resp = &pb.SigningResponse{Chain: [][]byte{signed.Raw, s.ca.Cert.Raw}}
+	for _, cert := range s.ca.RestChain {
+		resp.Chain = append(resp.Chain, cert.Raw)
+	}
+	return resp, nil
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package chore
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/lease"
+)
+
+// Func is the work of a chore
+type Func func(ctx context.Context) error
+
+// Chore runs a Func at an interval on the satellite holding the chores
+// lease. It can be paused, resumed and triggered while it runs.
+type Chore struct {
+	log      *zap.Logger
+	name     string
+	interval time.Duration
+	jitter   time.Duration
+	fn       Func
+
+	trigger chan struct{}
+
+	mu     sync.Mutex
+	status Status
+}
+
+// Status is the state of a chore
+type Status struct {
+	Name         string        `json:"name"`
+	Interval     time.Duration `json:"interval"`
+	Paused       bool          `json:"paused"`
+	Running      bool          `json:"running"`
+	LastStart    time.Time     `json:"last_start"`
+	LastDuration time.Duration `json:"last_duration"`
+	LastError    string        `json:"last_error,omitempty"`
+}
+
+// newChore creates a chore that runs fn every interval, starting after a
+// random delay of up to jitter
+func newChore(log *zap.Logger, name string, interval, jitter time.Duration, fn Func) *Chore {
+	return &Chore{
+		log:      log.Named(name),
+		name:     name,
+		interval: interval,
+		jitter:   jitter,
+		fn:       fn,
+		trigger:  make(chan struct{}, 1),
+		status:   Status{Name: name, Interval: interval},
+	}
+}
+
+// Run runs the chore until ctx is canceled
+func (chore *Chore) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if chore.jitter > 0 {
+		delay := time.NewTimer(time.Duration(rand.Int63n(int64(chore.jitter))))
+		select {
+		case <-delay.C:
+		case <-chore.trigger:
+			delay.Stop()
+		case <-ctx.Done():
+			delay.Stop()
+			return ctx.Err()
+		}
+	}
+
+	ticker := time.NewTicker(chore.interval)
+	defer ticker.Stop()
+
+	triggered := false
+	for {
+		if triggered || !chore.Paused() {
+			chore.runOnce(ctx)
+		}
+
+		select {
+		case <-ticker.C:
+			triggered = false
+		case <-chore.trigger:
+			triggered = true
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// runOnce runs fn if the satellite holds the chores lease
+func (chore *Chore) runOnce(ctx context.Context) {
+	start := time.Now()
+	chore.update(func(status *Status) { status.Running = true })
+
+	err := lease.RunChore(ctx, func(ctx context.Context) error {
+		chore.update(func(status *Status) { status.LastStart = start })
+		return chore.fn(ctx)
+	})
+	if err != nil && ctx.Err() == nil {
+		chore.log.Error("chore failed", zap.Error(err))
+	}
+
+	chore.update(func(status *Status) {
+		status.Running = false
+		if status.LastStart == start {
+			status.LastDuration = time.Since(start)
+			status.LastError = ""
+			if err != nil {
+				status.LastError = err.Error()
+			}
+		}
+	})
+}
+
+func (chore *Chore) update(fn func(status *Status)) {
+	chore.mu.Lock()
+	defer chore.mu.Unlock()
+	fn(&chore.status)
+}
+
+// Name returns the name of the chore
+func (chore *Chore) Name() string { return chore.name }
+
+// Status returns the state of the chore
+func (chore *Chore) Status() Status {
+	chore.mu.Lock()
+	defer chore.mu.Unlock()
+	return chore.status
+}
+
+// Paused returns whether the chore is paused
+func (chore *Chore) Paused() bool {
+	return chore.Status().Paused
+}
+
+// Pause stops running the chore at its interval. A run in progress isn't
+// interrupted.
+func (chore *Chore) Pause() {
+	chore.update(func(status *Status) { status.Paused = true })
+}
+
+// Resume runs the chore at its interval again
+func (chore *Chore) Resume() {
+	chore.update(func(status *Status) { status.Paused = false })
+}
+
+// Trigger runs the chore as soon as possible, even if it is paused
+func (chore *Chore) Trigger() {
+	select {
+	case chore.trigger <- struct{}{}:
+	default:
+	}
+}
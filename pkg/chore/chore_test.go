@@ -0,0 +1,85 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package chore
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestChore(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var runs int64
+	registry := NewRegistry(zap.NewNop(), 0)
+	chore, err := registry.New("test", time.Hour, func(ctx context.Context) error {
+		atomic.AddInt64(&runs, 1)
+		return nil
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = registry.New("test", time.Hour, nil)
+	assert.Error(t, err)
+
+	go func() { _ = chore.Run(ctx) }()
+	waitFor(t, func() bool { return atomic.LoadInt64(&runs) == 1 })
+
+	// triggered chores run even when paused
+	chore.Pause()
+	chore.Trigger()
+	waitFor(t, func() bool { return atomic.LoadInt64(&runs) == 2 })
+
+	status := chore.Status()
+	assert.True(t, status.Paused)
+	assert.False(t, status.LastStart.IsZero())
+	assert.Empty(t, status.LastError)
+}
+
+func TestAdminAPI(t *testing.T) {
+	registry := NewRegistry(zap.NewNop(), 0)
+	chore, err := registry.New("gc", time.Hour, func(ctx context.Context) error { return nil })
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	do := func(method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		registry.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	assert.Equal(t, http.StatusOK, do("POST", "/chores/gc/pause").Code)
+	assert.True(t, chore.Paused())
+
+	w := do("GET", "/chores/")
+	var statuses []Status
+	if assert.Equal(t, http.StatusOK, w.Code) && assert.NoError(t, json.NewDecoder(w.Body).Decode(&statuses)) {
+		assert.Equal(t, []Status{chore.Status()}, statuses)
+	}
+
+	assert.Equal(t, http.StatusOK, do("POST", "/chores/gc/resume").Code)
+	assert.False(t, chore.Paused())
+
+	assert.Equal(t, http.StatusNotFound, do("POST", "/chores/other/pause").Code)
+	assert.Equal(t, http.StatusNotFound, do("POST", "/chores/gc/stop").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, do("GET", "/chores/gc/pause").Code)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+	}
+}
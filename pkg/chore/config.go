@@ -0,0 +1,55 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package chore
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/provider"
+)
+
+// CtxKey Used as chore key
+type CtxKey int
+
+const (
+	ctxKeyRegistry CtxKey = iota
+)
+
+// Config is a configuration struct that is everything you need to start a
+// chore registry responsibility
+type Config struct {
+	Jitter time.Duration `help:"the maximum random delay before the first run of each chore, so satellites started together don't run them at once" default:"1m"`
+}
+
+// Run implements the provider.Responsibility interface
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	registry := NewRegistry(zap.L().Named("chores"), c.Jitter)
+	process.HandleDebug("/chores/", registry)
+
+	return server.Run(context.WithValue(ctx, ctxKeyRegistry, registry))
+}
+
+// LoadFromContext loads an existing chore Registry from the Provider context
+// stack if one exists.
+func LoadFromContext(ctx context.Context) *Registry {
+	if v, ok := ctx.Value(ctxKeyRegistry).(*Registry); ok {
+		return v
+	}
+	return nil
+}
+
+// New creates a chore that runs fn every interval, registered with the
+// registry in ctx if there is one
+func New(ctx context.Context, name string, interval time.Duration, fn Func) (*Chore, error) {
+	if registry := LoadFromContext(ctx); registry != nil {
+		return registry.New(name, interval, fn)
+	}
+	return newChore(zap.L().Named("chores"), name, interval, 0, fn), nil
+}
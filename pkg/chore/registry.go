@@ -0,0 +1,114 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package chore
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Registry keeps the chores of a satellite, so they can be controlled with
+// its admin API
+type Registry struct {
+	log    *zap.Logger
+	jitter time.Duration
+
+	mu     sync.Mutex
+	chores map[string]*Chore
+}
+
+// NewRegistry creates a registry whose chores start after a random delay of
+// up to jitter
+func NewRegistry(log *zap.Logger, jitter time.Duration) *Registry {
+	return &Registry{log: log, jitter: jitter, chores: map[string]*Chore{}}
+}
+
+// New creates and registers a chore that runs fn every interval
+func (registry *Registry) New(name string, interval time.Duration, fn Func) (*Chore, error) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.chores[name]; ok {
+		return nil, Error.New("chore %q already registered", name)
+	}
+	chore := newChore(registry.log, name, interval, registry.jitter, fn)
+	registry.chores[name] = chore
+	return chore, nil
+}
+
+// Get returns the chore called name, or nil
+func (registry *Registry) Get(name string) *Chore {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	return registry.chores[name]
+}
+
+// Statuses returns the states of the chores, ordered by name
+func (registry *Registry) Statuses() []Status {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	statuses := make([]Status, 0, len(registry.chores))
+	for _, chore := range registry.chores {
+		statuses = append(statuses, chore.Status())
+	}
+	sort.Slice(statuses, func(i, k int) bool { return statuses[i].Name < statuses[k].Name })
+	return statuses
+}
+
+// ServeHTTP implements the admin API of the chores, mounted at /chores/:
+//
+//	GET  /chores/                lists the states of the chores
+//	POST /chores/NAME/pause      pauses a chore
+//	POST /chores/NAME/resume     resumes a chore
+//	POST /chores/NAME/trigger    runs a chore as soon as possible
+func (registry *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(req.URL.Path, "/chores"), "/")
+	if path == "" {
+		if req.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(registry.Statuses())
+		return
+	}
+
+	if req.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 {
+		http.NotFound(w, req)
+		return
+	}
+	chore := registry.Get(parts[0])
+	if chore == nil {
+		http.NotFound(w, req)
+		return
+	}
+
+	switch parts[1] {
+	case "pause":
+		chore.Pause()
+	case "resume":
+		chore.Resume()
+	case "trigger":
+		chore.Trigger()
+	default:
+		http.NotFound(w, req)
+		return
+	}
+	registry.log.Info("chore "+parts[1], zap.String("chore", parts[0]))
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(chore.Status())
+}
@@ -0,0 +1,166 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"storj.io/storj/pkg/macaroon"
+)
+
+// APIKeyInfo describes an API key of a project without its secret
+type APIKeyInfo struct {
+	Name      string
+	CreatedBy string
+	Created   time.Time
+}
+
+// CreateAPIKey issues a new API key called name for projectID and returns
+// the key. Only the hash of the key is stored, so it can't be retrieved
+// again. Only admins and owners may issue API keys.
+func (db *DB) CreateAPIKey(ctx context.Context, userID, projectID, name string) (key string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if name == "" {
+		return "", Error.New("API key name is empty")
+	}
+
+	// the tail is kept to revoke the key while the project is being deleted
+	var tail []byte
+	if len(db.APIKeySecret) > 0 {
+		apiKey, err := macaroon.NewAPIKey(db.APIKeySecret)
+		if err != nil {
+			return "", Error.Wrap(err)
+		}
+		key, tail = apiKey.Serialize(), apiKey.Tail()
+	} else {
+		key, err = randomID(32)
+		if err != nil {
+			return "", Error.Wrap(err)
+		}
+	}
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := require(tx, projectID, userID, Admin); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`INSERT INTO api_keys (key_hash, project_id, name, created_by, created, tail, pointerdb_project) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			hashSecret(key), projectID, name, userID, time.Now().Unix(), tail, macaroon.ProjectID([]byte(key)))
+		return Error.Wrap(err)
+	})
+	if err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// APIKeys returns the API keys of projectID. Every member may list the API
+// keys of a project.
+func (db *DB) APIKeys(ctx context.Context, userID, projectID string) (keys []APIKeyInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := require(tx, projectID, userID, Member); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(`SELECT name, created_by, created FROM api_keys WHERE project_id = ? ORDER BY created, name`, projectID)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			var info APIKeyInfo
+			var created int64
+			if err := rows.Scan(&info.Name, &info.CreatedBy, &created); err != nil {
+				return Error.Wrap(err)
+			}
+			info.Created = time.Unix(created, 0).UTC()
+			keys = append(keys, info)
+		}
+		return Error.Wrap(rows.Err())
+	})
+	return keys, err
+}
+
+// ProjectOfAPIKey returns the project key was issued for. Keys of projects
+// that are being deleted are refused.
+func (db *DB) ProjectOfAPIKey(ctx context.Context, key string) (projectID string, err error) {
+	defer mon.Task()(&ctx)(&err)
+	defer db.locked()()
+
+	err = db.DB.QueryRowContext(ctx, `SELECT project_id FROM api_keys WHERE key_hash = ?`, hashSecret(key)).Scan(&projectID)
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound.New("API key")
+	}
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	if err := checkNotDeleting(db.DB, ProjectDeletion, projectID); err != nil {
+		return "", err
+	}
+	return projectID, nil
+}
+
+// setRevoked revokes the API keys of projectID in pointerdb, or restores
+// them if revoked is false
+func (db *DB) setRevoked(ctx context.Context, tx *sql.Tx, projectID string, revoked bool) error {
+	if db.Revocations == nil {
+		return nil
+	}
+
+	rows, err := tx.Query(`SELECT tail FROM api_keys WHERE project_id = ? AND tail IS NOT NULL`, projectID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	var tails [][]byte
+	for rows.Next() {
+		var tail []byte
+		if err := rows.Scan(&tail); err != nil {
+			_ = rows.Close()
+			return Error.Wrap(err)
+		}
+		tails = append(tails, tail)
+	}
+	if err := rows.Close(); err != nil {
+		return Error.Wrap(err)
+	}
+
+	for _, tail := range tails {
+		if revoked {
+			err = db.Revocations.Revoke(ctx, tail)
+		} else {
+			err = db.Revocations.Restore(ctx, tail)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pointerdbProjects returns the projects pointerdb knows the API keys of
+// projectID by
+func (db *DB) pointerdbProjects(ctx context.Context, projectID string) (projects []string, err error) {
+	defer mon.Task()(&ctx)(&err)
+	defer db.locked()()
+
+	rows, err := db.DB.QueryContext(ctx, `SELECT DISTINCT pointerdb_project FROM api_keys WHERE project_id = ? AND pointerdb_project IS NOT NULL`, projectID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		var project string
+		if err := rows.Scan(&project); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		projects = append(projects, project)
+	}
+	return projects, Error.Wrap(rows.Err())
+}
@@ -0,0 +1,42 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+
+	base58 "github.com/jbenet/go-base58"
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default console errs class
+	Error = errs.Class("console error")
+	// ErrUnauthorized is returned for requests the role of the user in the
+	// project doesn't allow
+	ErrUnauthorized = errs.Class("unauthorized")
+	// ErrNotFound is returned for projects, members and invitations that
+	// don't exist
+	ErrNotFound = errs.Class("not found")
+)
+
+// randomID returns a random base58 encoded id of size bytes
+func randomID(size int) (string, error) {
+	id := make([]byte, size)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return base58.Encode(id), nil
+}
+
+// hashSecret returns the hash a secret token or key is stored as, so that
+// the secrets can't be read from the database
+func hashSecret(secret string) []byte {
+	hash := sha256.Sum256([]byte(secret))
+	return hash[:]
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/analytics"
+	"storj.io/storj/pkg/chore"
+	"storj.io/storj/pkg/mail"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/provider"
+)
+
+// CtxKey Used as console key
+type CtxKey int
+
+const (
+	ctxKeyConsole CtxKey = iota
+)
+
+// Config is a configuration struct that is everything you need to start a
+// console responsibility
+type Config struct {
+	Path                 string        `help:"path to the console database" default:"$CONFDIR/console.db"`
+	InvitationExpiration time.Duration `help:"how long project invitations are valid" default:"168h"`
+	ExternalAddress      string        `help:"address of the console the links in emails point to" default:"http://localhost:10100/"`
+	DeletionGracePeriod  time.Duration `help:"how long deleted projects and accounts are kept before their data is deleted" default:"720h"`
+	DeletionInterval     time.Duration `help:"how frequently due deletions are processed" default:"1h"`
+	RequireSignupToken   bool          `help:"only let users with a signup token register" default:"false"`
+	Mail                 mail.Config
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// PointerDB responsibility has been started before this one.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pdb := pointerdb.LoadFromContext(ctx)
+	if pdb == nil {
+		return Error.New("programmer error: pointerdb responsibility unstarted")
+	}
+
+	mailService, err := c.Mail.NewService(zap.L().Named("mail"))
+	if err != nil {
+		return err
+	}
+
+	db, err := Open(ctx, c.Path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+	db.InvitationExpiration = c.InvitationExpiration
+	db.DeletionGracePeriod = c.DeletionGracePeriod
+	db.ExternalAddress = c.ExternalAddress
+	db.RequireSignupToken = c.RequireSignupToken
+	db.Mail = mailService
+	db.Analytics = analytics.LoadFromContext(ctx)
+	db.APIKeySecret = pdb.APIKeySecret()
+	db.Revocations = pdb.Revocations()
+	db.Log = zap.L().Named("console")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	deleter := NewDeleter(zap.L().Named("console"), db, pdb.DB)
+	deletions, err := chore.New(ctx, "deletions", c.DeletionInterval, deleter.Process)
+	if err != nil {
+		return err
+	}
+	go func() { _ = deletions.Run(ctx) }()
+
+	return server.Run(context.WithValue(ctx, ctxKeyConsole, db))
+}
+
+// LoadFromContext loads an existing console DB from the Provider context
+// stack if one exists.
+func LoadFromContext(ctx context.Context) *DB {
+	if v, ok := ctx.Value(ctxKeyConsole).(*DB); ok {
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,322 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/mail"
+)
+
+var ctx = context.Background()
+
+func openTest(t *testing.T) (*DB, func()) {
+	tmpdir, err := ioutil.TempDir("", "storj-console")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := Open(ctx, filepath.Join(tmpdir, "console.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db, func() {
+		assert.NoError(t, db.Close())
+		assert.NoError(t, os.RemoveAll(tmpdir))
+	}
+}
+
+func TestInvitations(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	project, err := db.CreateProject(ctx, "alice", "project")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = db.Invite(ctx, "alice", project.ID, "bob@example.com", Owner)
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	invitation, err := db.Invite(ctx, "alice", project.ID, "Bob@Example.com", Admin)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = db.AcceptInvitation(ctx, "mallory", "mallory@example.com", invitation.Token)
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	projectID, err := db.AcceptInvitation(ctx, "bob", "bob@example.com", invitation.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, project.ID, projectID)
+
+	_, err = db.AcceptInvitation(ctx, "bob", "bob@example.com", invitation.Token)
+	assert.True(t, ErrNotFound.Has(err))
+
+	members, err := db.Members(ctx, "bob", project.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, members, 2) {
+		assert.Equal(t, "alice", members[0].UserID)
+		assert.Equal(t, Owner, members[0].Role)
+		assert.Equal(t, "bob", members[1].UserID)
+		assert.Equal(t, Admin, members[1].Role)
+	}
+
+	db.InvitationExpiration = -time.Minute
+	expired, err := db.Invite(ctx, "bob", project.ID, "carol@example.com", Member)
+	if assert.NoError(t, err) {
+		_, err = db.AcceptInvitation(ctx, "carol", "carol@example.com", expired.Token)
+		assert.True(t, ErrUnauthorized.Has(err))
+	}
+}
+
+func TestRoles(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	project, err := db.CreateProject(ctx, "alice", "project")
+	if !assert.NoError(t, err) {
+		return
+	}
+	for user, role := range map[string]Role{"bob": Admin, "carol": Member} {
+		invitation, err := db.Invite(ctx, "alice", project.ID, user+"@example.com", role)
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, err = db.AcceptInvitation(ctx, user, user+"@example.com", invitation.Token)
+		assert.NoError(t, err)
+	}
+
+	// members can view but not change settings or issue keys
+	_, err = db.GetProject(ctx, "carol", project.ID)
+	assert.NoError(t, err)
+	err = db.UpdateProject(ctx, "carol", project.ID, "renamed", "")
+	assert.True(t, ErrUnauthorized.Has(err))
+	_, err = db.CreateAPIKey(ctx, "carol", project.ID, "key")
+	assert.True(t, ErrUnauthorized.Has(err))
+	_, err = db.GetProject(ctx, "mallory", project.ID)
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	// admins can change settings and issue keys
+	assert.NoError(t, db.UpdateProject(ctx, "bob", project.ID, "renamed", "description"))
+	key, err := db.CreateAPIKey(ctx, "bob", project.ID, "key")
+	assert.NoError(t, err)
+	projectID, err := db.ProjectOfAPIKey(ctx, key)
+	assert.NoError(t, err)
+	assert.Equal(t, project.ID, projectID)
+	keys, err := db.APIKeys(ctx, "carol", project.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 1) {
+		assert.Equal(t, "key", keys[0].Name)
+		assert.Equal(t, "bob", keys[0].CreatedBy)
+	}
+
+	// only owners can grant or revoke the owner role
+	assert.True(t, ErrUnauthorized.Has(db.SetRole(ctx, "bob", project.ID, "carol", Owner)))
+	assert.True(t, ErrUnauthorized.Has(db.SetRole(ctx, "bob", project.ID, "alice", Member)))
+	assert.True(t, ErrUnauthorized.Has(db.SetRole(ctx, "alice", project.ID, "alice", Admin)))
+	assert.True(t, ErrUnauthorized.Has(db.RemoveMember(ctx, "alice", project.ID, "alice")))
+	assert.NoError(t, db.SetRole(ctx, "alice", project.ID, "bob", Owner))
+	assert.NoError(t, db.RemoveMember(ctx, "alice", project.ID, "alice"))
+
+	// members can leave
+	assert.NoError(t, db.RemoveMember(ctx, "carol", project.ID, "carol"))
+	members, err := db.Members(ctx, "bob", project.ID)
+	assert.NoError(t, err)
+	assert.Len(t, members, 1)
+}
+
+type mailbox struct {
+	messages []*mail.Message
+	err      error // the error of every send, if not nil
+}
+
+func (box *mailbox) SendEmail(ctx context.Context, msg *mail.Message) error {
+	if box.err != nil {
+		return box.err
+	}
+	box.messages = append(box.messages, msg)
+	return nil
+}
+
+// token returns the token of the link in the last message
+func (box *mailbox) token() string {
+	if len(box.messages) == 0 {
+		return ""
+	}
+	body := box.messages[len(box.messages)-1].Body
+	i := strings.Index(body, "?token=")
+	if i < 0 {
+		return ""
+	}
+	return strings.Fields(body[i+len("?token="):])[0]
+}
+
+func TestRegistration(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	box := &mailbox{}
+	db.Mail = mail.NewService(box, "noreply@example.com")
+	db.ExternalAddress = "https://console.example.com/"
+
+	user, err := db.Register(ctx, "Alice@Example.com", "password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "alice@example.com", user.Email)
+	_, err = db.Register(ctx, "alice@example.com", "password")
+	assert.Error(t, err)
+
+	// inactive users can't log in
+	_, err = db.Authenticate(ctx, "alice@example.com", "password")
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	if assert.Len(t, box.messages, 1) {
+		assert.Equal(t, []string{"alice@example.com"}, box.messages[0].To)
+		assert.Contains(t, box.messages[0].Body, "https://console.example.com/activate?token=")
+	}
+	assert.NoError(t, db.Activate(ctx, box.token()))
+	assert.True(t, ErrNotFound.Has(db.Activate(ctx, box.token())))
+
+	authenticated, err := db.Authenticate(ctx, "alice@example.com", "password")
+	if assert.NoError(t, err) {
+		assert.Equal(t, user.ID, authenticated.ID)
+	}
+	_, err = db.Authenticate(ctx, "alice@example.com", "wrong password")
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	// password reset
+	assert.NoError(t, db.RequestPasswordReset(ctx, "nobody@example.com"))
+	assert.Len(t, box.messages, 1)
+	assert.NoError(t, db.RequestPasswordReset(ctx, "alice@example.com"))
+	assert.Len(t, box.messages, 2)
+	assert.NoError(t, db.ResetPassword(ctx, box.token(), "new password"))
+	_, err = db.Authenticate(ctx, "alice@example.com", "new password")
+	assert.NoError(t, err)
+
+	// invitations are emailed
+	project, err := db.CreateProject(ctx, user.ID, "photos")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = db.Invite(ctx, user.ID, project.ID, "bob@example.com", Member)
+	assert.NoError(t, err)
+	if assert.Len(t, box.messages, 3) {
+		assert.Equal(t, []string{"bob@example.com"}, box.messages[2].To)
+		assert.Contains(t, box.messages[2].Subject, "photos")
+	}
+	projectID, err := db.AcceptInvitation(ctx, "bob", "bob@example.com", box.token())
+	assert.NoError(t, err)
+	assert.Equal(t, project.ID, projectID)
+}
+
+func TestRegistrationMailFailure(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	box := &mailbox{err: errors.New("mail server down")}
+	db.Mail = mail.NewService(box, "noreply@example.com")
+
+	// the user is registered even though the activation email isn't sent
+	user, err := db.Register(ctx, "alice@example.com", "password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "alice@example.com", user.Email)
+	assert.Empty(t, box.messages)
+	_, err = db.Register(ctx, "alice@example.com", "password")
+	assert.Error(t, err)
+
+	// and can be activated once the mail is sent again
+	box.err = nil
+	assert.NoError(t, db.RequestPasswordReset(ctx, "alice@example.com"))
+	assert.NoError(t, db.ResetPassword(ctx, box.token(), "new password"))
+	authenticated, err := db.Authenticate(ctx, "alice@example.com", "new password")
+	if assert.NoError(t, err) {
+		assert.Equal(t, user.ID, authenticated.ID)
+	}
+}
+
+func TestSignupTokens(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	db.RequireSignupToken = true
+	db.ExternalAddress = "https://console.example.com/"
+
+	_, err := db.Register(ctx, "alice@example.com", "password")
+	assert.True(t, ErrUnauthorized.Has(err))
+	_, err = db.RegisterWithToken(ctx, "alice@example.com", "password", "invalid")
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	_, err = db.CreateSignupToken(ctx, "", "", 1, 0)
+	assert.Error(t, err)
+	token, err := db.CreateSignupToken(ctx, "", "beta", 1, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "https://console.example.com/signup?token="+token.Token, db.SignupLink(token.Token))
+
+	alice, err := db.RegisterWithToken(ctx, "alice@example.com", "password", token.Token)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, token.ID, alice.SignupToken)
+	_, err = db.RegisterWithToken(ctx, "bob@example.com", "password", token.Token)
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	// registering an existing email doesn't use the token
+	referral, err := db.CreateSignupToken(ctx, alice.ID, "referral", 0, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = db.RegisterWithToken(ctx, "alice@example.com", "password", referral.Token)
+	assert.Error(t, err)
+	_, err = db.RegisterWithToken(ctx, "bob@example.com", "password", referral.Token)
+	assert.NoError(t, err)
+
+	// projects are attributed to the token of their creator
+	project, err := db.CreateProject(ctx, alice.ID, "project")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, token.ID, project.SignupToken)
+	project, err = db.GetProject(ctx, alice.ID, project.ID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, token.ID, project.SignupToken)
+	}
+
+	tokens, err := db.SignupTokens(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, tokens, 2) {
+		assert.Equal(t, "beta", tokens[0].Name)
+		assert.Equal(t, 1, tokens[0].Uses)
+		assert.Empty(t, tokens[0].Token)
+		assert.Equal(t, "referral", tokens[1].Name)
+		assert.Equal(t, alice.ID, tokens[1].CreatedBy)
+		assert.Equal(t, 1, tokens[1].Uses)
+	}
+
+	// revoked and expired tokens can't be used
+	assert.NoError(t, db.RevokeSignupToken(ctx, referral.ID))
+	assert.True(t, ErrNotFound.Has(db.RevokeSignupToken(ctx, "unknown")))
+	_, err = db.RegisterWithToken(ctx, "carol@example.com", "password", referral.Token)
+	assert.True(t, ErrUnauthorized.Has(err))
+	expired, err := db.CreateSignupToken(ctx, "", "expired", 0, -time.Minute)
+	if assert.NoError(t, err) {
+		_, err = db.RegisterWithToken(ctx, "carol@example.com", "password", expired.Token)
+		assert.True(t, ErrUnauthorized.Has(err))
+	}
+}
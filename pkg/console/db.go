@@ -0,0 +1,199 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3" // register sqlite to sql
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/analytics"
+	"storj.io/storj/pkg/mail"
+	"storj.io/storj/pkg/pointerdb"
+)
+
+const (
+	// DefaultInvitationExpiration is how long invitations are valid by default
+	DefaultInvitationExpiration = 7 * 24 * time.Hour
+	// DefaultDeletionGracePeriod is how long deleted projects and accounts
+	// are kept by default before their data is deleted
+	DefaultDeletionGracePeriod = 30 * 24 * time.Hour
+)
+
+var schema = []string{
+	"CREATE TABLE IF NOT EXISTS `projects` (`id` TEXT PRIMARY KEY, `name` TEXT, `description` TEXT, `created` INT(10), `signup_token` TEXT);",
+	"CREATE TABLE IF NOT EXISTS `members` (`project_id` TEXT, `user_id` TEXT, `role` INT(10), `created` INT(10), PRIMARY KEY (`project_id`, `user_id`));",
+	"CREATE TABLE IF NOT EXISTS `invitations` (`token_hash` BLOB PRIMARY KEY, `project_id` TEXT, `email` TEXT, `role` INT(10), `invited_by` TEXT, `expires` INT(10));",
+	"CREATE TABLE IF NOT EXISTS `api_keys` (`key_hash` BLOB PRIMARY KEY, `project_id` TEXT, `name` TEXT, `created_by` TEXT, `created` INT(10), `tail` BLOB, `pointerdb_project` TEXT);",
+	"CREATE TABLE IF NOT EXISTS `users` (`id` TEXT PRIMARY KEY, `email` TEXT UNIQUE, `password_hash` BLOB, `active` INT(1), `created` INT(10), `signup_token` TEXT);",
+	"CREATE TABLE IF NOT EXISTS `signup_tokens` (`id` TEXT PRIMARY KEY, `token_hash` BLOB UNIQUE, `name` TEXT, `created_by` TEXT, `max_uses` INT(10), `uses` INT(10), `expires` INT(10), `created` INT(10), `revoked` INT(1));",
+	"CREATE TABLE IF NOT EXISTS `user_tokens` (`token_hash` BLOB PRIMARY KEY, `kind` INT(10), `user_id` TEXT, `expires` INT(10));",
+	"CREATE TABLE IF NOT EXISTS `deletions` (`id` TEXT PRIMARY KEY, `kind` INT(10), `target` TEXT, `parent` TEXT, `requested_by` TEXT, `phase` INT(10), `requested` INT(10), `due` INT(10));",
+	"CREATE TABLE IF NOT EXISTS `deletion_log` (`deletion_id` TEXT, `phase` INT(10), `at` INT(10), `message` TEXT);",
+	"CREATE INDEX IF NOT EXISTS idx_members_user ON members (user_id);",
+	"CREATE INDEX IF NOT EXISTS idx_api_keys_project ON api_keys (project_id);",
+}
+
+// columns are the columns added to the tables after they were created
+var columns = []struct{ table, column, definition string }{
+	{"api_keys", "tail", "BLOB"},
+	{"api_keys", "pointerdb_project", "TEXT"},
+}
+
+// DB stores the projects of the console, their members, invitations and API
+// keys. Every method that acts on a project takes the id of the
+// authenticated user making the request and checks that the role of the user
+// in the project allows it.
+type DB struct {
+	// InvitationExpiration is how long new invitations are valid
+	InvitationExpiration time.Duration
+	// DeletionGracePeriod is how long deleted projects and accounts are kept
+	// before their data is deleted
+	DeletionGracePeriod time.Duration
+	// Mail sends activation, password reset and invitation emails if not nil
+	Mail *mail.Service
+	// ExternalAddress is the address of the console the links in emails
+	// point to
+	ExternalAddress string
+	// RequireSignupToken only lets users with a signup token register
+	RequireSignupToken bool
+	// Analytics emits the events of the console if not nil
+	Analytics *analytics.Events
+	// APIKeySecret is the root secret of the macaroon API keys pointerdb
+	// accepts. If empty, API keys are random and can't access pointerdb.
+	APIKeySecret []byte
+	// Revocations revokes the API keys of projects while they are being
+	// deleted, if not nil
+	Revocations *pointerdb.Revocations
+	// Log logs the errors that don't fail the requests of the console
+	Log *zap.Logger
+
+	mu sync.Mutex
+	DB *sql.DB
+}
+
+// Open opens the console database at path
+func Open(ctx context.Context, path string) (db *DB, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	sqlite, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?cache=shared&mode=rwc&mutex=full", path))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	for _, stmt := range schema {
+		if _, err = sqlite.Exec(stmt); err != nil {
+			_ = sqlite.Close()
+			return nil, Error.Wrap(err)
+		}
+	}
+	for _, c := range columns {
+		if err = addColumn(sqlite, c.table, c.column, c.definition); err != nil {
+			_ = sqlite.Close()
+			return nil, Error.Wrap(err)
+		}
+	}
+
+	return &DB{
+		InvitationExpiration: DefaultInvitationExpiration,
+		DeletionGracePeriod:  DefaultDeletionGracePeriod,
+		Log:                  zap.NewNop(),
+		DB:                   sqlite,
+	}, nil
+}
+
+// addColumn adds column to table, unless the table has it already
+func addColumn(db *sql.DB, table, column, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(`%s`);", table))
+	if err != nil {
+		return err
+	}
+	found := false
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, typ string
+		var value sql.NullString
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &value, &pk); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		found = found || name == column
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if found {
+		return nil
+	}
+	_, err = db.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s;", table, column, definition))
+	return err
+}
+
+// Close closes the database
+func (db *DB) Close() error {
+	return db.DB.Close()
+}
+
+func (db *DB) locked() func() {
+	db.mu.Lock()
+	return db.mu.Unlock
+}
+
+// withTx runs fn in a transaction that is committed if fn succeeds
+func (db *DB) withTx(ctx context.Context, fn func(tx *sql.Tx) error) (err error) {
+	defer db.locked()()
+
+	tx, err := db.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err = fn(tx); err != nil {
+		return err
+	}
+	return Error.Wrap(tx.Commit())
+}
+
+// roleOf returns the role of userID in projectID
+func roleOf(tx *sql.Tx, projectID, userID string) (Role, error) {
+	var role Role
+	err := tx.QueryRow(`SELECT role FROM members WHERE project_id = ? AND user_id = ?`, projectID, userID).Scan(&role)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound.New("user %q is not a member of project %q", userID, projectID)
+	}
+	return role, Error.Wrap(err)
+}
+
+// require checks that userID has at least the role min in projectID and
+// returns the role of userID. Access to projects that are being deleted is
+// refused.
+func require(tx *sql.Tx, projectID, userID string, min Role) (Role, error) {
+	if err := checkNotDeleting(tx, ProjectDeletion, projectID); err != nil {
+		return 0, err
+	}
+
+	role, err := roleOf(tx, projectID, userID)
+	if ErrNotFound.Has(err) {
+		return 0, ErrUnauthorized.New("user %q is not a member of project %q", userID, projectID)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if role < min {
+		return 0, ErrUnauthorized.New("%s role required, user %q is %s", min, userID, role)
+	}
+	return role, nil
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/storage"
+)
+
+// Deleter processes deletion requests once their grace period has ended. It
+// deletes the objects of deleted projects from pointerdb, which leaves their
+// pieces to be removed from the nodes by the next garbage collection, and
+// then purges the console records.
+type Deleter struct {
+	log      *zap.Logger
+	db       *DB
+	pointers storage.KeyValueStore
+}
+
+// NewDeleter creates a Deleter deleting objects from pointers
+func NewDeleter(log *zap.Logger, db *DB, pointers storage.KeyValueStore) *Deleter {
+	return &Deleter{log: log, db: db, pointers: pointers}
+}
+
+// Process advances all due deletions through their remaining phases
+func (deleter *Deleter) Process(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	deletions, err := deleter.db.DueDeletions(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, deletion := range deletions {
+		if err := deleter.process(ctx, deletion); err != nil {
+			// the deletion stays in its phase and is retried next time
+			deleter.log.Error("deletion failed",
+				zap.String("deletion", deletion.ID),
+				zap.Stringer("phase", deletion.Phase),
+				zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func (deleter *Deleter) process(ctx context.Context, deletion *Deletion) error {
+	if deletion.Phase == DeletionRequested {
+		message := "memberships deleted"
+		if deletion.Kind == ProjectDeletion {
+			segments, err := deleter.deleteProjectData(ctx, deletion.Target)
+			if err != nil {
+				return err
+			}
+			message = fmt.Sprintf("%d segments deleted, pieces left to garbage collection", segments)
+		}
+		if err := deleter.db.FinishDataDeletion(ctx, deletion, message); err != nil {
+			return err
+		}
+		deleter.log.Info("deletion data deleted", zap.String("deletion", deletion.ID), zap.String("result", message))
+	}
+
+	if err := deleter.db.Purge(ctx, deletion); err != nil {
+		return err
+	}
+	deleter.log.Info("deletion purged", zap.String("deletion", deletion.ID))
+	return nil
+}
+
+// deleteProjectData deletes the pointers of all segments in the buckets of
+// projectID and returns how many were deleted
+func (deleter *Deleter) deleteProjectData(ctx context.Context, projectID string) (deleted int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	projects, err := deleter.db.pointerdbProjects(ctx, projectID)
+	if err != nil {
+		return 0, err
+	}
+	if len(projects) == 0 {
+		return 0, nil
+	}
+	ofProject := map[string]bool{}
+	for _, project := range projects {
+		ofProject[project] = true
+	}
+
+	// pointerdb isn't partitioned by project, bucket names are unique across
+	// the satellite. The pointers of buckets record the project that created
+	// them, and segment paths start with the segment index followed by the
+	// bucket, so the whole database is scanned twice. Keys are collected
+	// first so that the database isn't modified while iterating.
+	inProject := map[string]bool{}
+	err = deleter.iterate(func(key storage.Key, parts []string, value storage.Value) error {
+		if len(parts) != 2 {
+			return nil
+		}
+		pointer, _, err := pointerdb.UnmarshalPointer(value)
+		if err != nil {
+			deleter.log.Warn("skipping unreadable bucket", zap.String("bucket", parts[1]), zap.Error(err))
+			return nil
+		}
+		if ofProject[pointer.GetProjectId()] {
+			inProject[parts[1]] = true
+		}
+		return nil
+	})
+	if err != nil || len(inProject) == 0 {
+		return 0, err
+	}
+
+	var keys storage.Keys
+	err = deleter.iterate(func(key storage.Key, parts []string, value storage.Value) error {
+		if inProject[parts[1]] {
+			keys = append(keys, storage.CloneKey(key))
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, key := range keys {
+		if err := deleter.pointers.Delete(key); err != nil && !storage.ErrKeyNotFound.Has(err) {
+			return deleted, Error.Wrap(err)
+		}
+		deleted++
+	}
+	return deleted, nil
+}
+
+// iterate calls fn with every pointer in the buckets of the satellite and
+// its path split into the segment index, the bucket and the object path
+func (deleter *Deleter) iterate(fn func(key storage.Key, parts []string, value storage.Value) error) error {
+	return Error.Wrap(deleter.pointers.Iterate(storage.IterateOptions{Recurse: true},
+		func(it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(&item) {
+				parts := strings.SplitN(item.Key.String(), "/", 3)
+				if len(parts) < 2 {
+					continue
+				}
+				if err := fn(item.Key, parts, item.Value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestDeletion(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	secret, err := macaroon.NewSecret()
+	if !assert.NoError(t, err) {
+		return
+	}
+	db.APIKeySecret = secret
+	db.Revocations = pointerdb.NewRevocations(teststore.New())
+
+	alice, err := db.CreateProject(ctx, "alice", "alice")
+	if !assert.NoError(t, err) {
+		return
+	}
+	shared, err := db.CreateProject(ctx, "alice", "shared")
+	if !assert.NoError(t, err) {
+		return
+	}
+	invitation, err := db.Invite(ctx, "alice", shared.ID, "bob@example.com", Admin)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = db.AcceptInvitation(ctx, "bob", "bob@example.com", invitation.Token)
+	assert.NoError(t, err)
+	assert.NoError(t, db.SetRole(ctx, "alice", shared.ID, "bob", Owner))
+
+	key, err := db.CreateAPIKey(ctx, "alice", alice.ID, "uplink")
+	if !assert.NoError(t, err) {
+		return
+	}
+	apiKey, err := macaroon.ParseAPIKey(key)
+	if !assert.NoError(t, err) {
+		return
+	}
+	revoked := func() bool {
+		revoked, err := db.Revocations.IsRevoked(ctx, apiKey.Tails(secret))
+		assert.NoError(t, err)
+		return revoked
+	}
+
+	pointers := teststore.New()
+	for bucket, project := range map[string]string{"photos": macaroon.ProjectID([]byte(key)), "other": "other"} {
+		pointer, err := pointerdb.MarshalPointer(&pb.Pointer{ProjectId: project})
+		if assert.NoError(t, err) {
+			assert.NoError(t, pointers.Put(storage.Key("l/"+bucket), pointer))
+		}
+	}
+	for _, path := range []string{"l/photos/a", "s0/photos/a", "l/photos/b", "l/other/c"} {
+		assert.NoError(t, pointers.Put(storage.Key(path), storage.Value("pointer")))
+	}
+
+	deleter := NewDeleter(zap.NewNop(), db, pointers)
+
+	// canceling restores access
+	db.DeletionGracePeriod = time.Hour
+	deletion, err := db.RequestAccountDeletion(ctx, "alice")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = db.GetProject(ctx, "alice", alice.ID)
+	assert.True(t, ErrUnauthorized.Has(err))
+	assert.True(t, revoked())
+	_, err = db.GetProject(ctx, "alice", shared.ID)
+	assert.NoError(t, err)
+	assert.True(t, ErrUnauthorized.Has(db.CancelDeletion(ctx, "bob", deletion.ID)))
+	assert.NoError(t, db.CancelDeletion(ctx, "alice", deletion.ID))
+	_, err = db.GetProject(ctx, "alice", alice.ID)
+	assert.NoError(t, err)
+	assert.False(t, revoked())
+
+	// nothing is deleted during the grace period
+	deletion, err = db.RequestAccountDeletion(ctx, "alice")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, deleter.Process(ctx))
+	assert.True(t, revoked())
+	keys, err := storage.ListKeys(pointers, nil, 10)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 6)
+
+	// after the grace period the data and records are deleted
+	_, err = db.DB.Exec(`UPDATE deletions SET due = ?`, time.Now().Add(-time.Minute).Unix())
+	assert.NoError(t, err)
+	assert.NoError(t, deleter.Process(ctx))
+
+	keys, err = storage.ListKeys(pointers, nil, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, storage.Keys{storage.Key("l/other"), storage.Key("l/other/c")}, keys)
+	assert.True(t, revoked())
+
+	members, err := db.Members(ctx, "bob", shared.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, members, 1) {
+		assert.Equal(t, "bob", members[0].UserID)
+	}
+
+	var projects int
+	assert.NoError(t, db.DB.QueryRow(`SELECT COUNT(*) FROM projects`).Scan(&projects))
+	assert.Equal(t, 1, projects)
+
+	events, err := db.DeletionLog(ctx, deletion.ID)
+	assert.NoError(t, err)
+	var phases []DeletionPhase
+	for _, event := range events {
+		phases = append(phases, event.Phase)
+	}
+	assert.Equal(t, []DeletionPhase{DeletionRequested, DataDeleted, Purged}, phases)
+
+	due, err := db.DueDeletions(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, due, 0)
+}
@@ -0,0 +1,373 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// DeletionKind is what a deletion request deletes
+type DeletionKind int
+
+const (
+	// ProjectDeletion deletes a project, its objects and its console records
+	ProjectDeletion DeletionKind = iota + 1
+	// AccountDeletion deletes a user, its memberships and the projects the
+	// user is the only owner of
+	AccountDeletion
+)
+
+// DeletionPhase is the phase of a deletion request
+type DeletionPhase int
+
+const (
+	// DeletionRequested means access is disabled and the data is kept until
+	// the grace period ends
+	DeletionRequested DeletionPhase = iota + 1
+	// DeletionCanceled means the deletion was canceled during the grace
+	// period and access is restored
+	DeletionCanceled
+	// DataDeleted means the objects or memberships were deleted. The pieces
+	// of deleted objects are removed from the nodes by garbage collection.
+	DataDeleted
+	// Purged means the console records were deleted
+	Purged
+)
+
+// String returns the name of the phase
+func (phase DeletionPhase) String() string {
+	switch phase {
+	case DeletionRequested:
+		return "requested"
+	case DeletionCanceled:
+		return "canceled"
+	case DataDeleted:
+		return "data deleted"
+	case Purged:
+		return "purged"
+	default:
+		return "invalid"
+	}
+}
+
+// Deletion is a request to delete a project or an account
+type Deletion struct {
+	ID          string
+	Kind        DeletionKind
+	Target      string
+	RequestedBy string
+	Phase       DeletionPhase
+	Requested   time.Time
+	Due         time.Time
+}
+
+// DeletionEvent is an entry in the audit log of a deletion
+type DeletionEvent struct {
+	Phase   DeletionPhase
+	At      time.Time
+	Message string
+}
+
+type queryRower interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// checkNotDeleting returns an error if the deletion of target is pending or
+// done
+func checkNotDeleting(db queryRower, kind DeletionKind, target string) error {
+	var deletions int
+	err := db.QueryRow(`SELECT COUNT(*) FROM deletions WHERE kind = ? AND target = ? AND phase != ?`,
+		kind, target, DeletionCanceled).Scan(&deletions)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if deletions > 0 {
+		return ErrUnauthorized.New("%q is being deleted", target)
+	}
+	return nil
+}
+
+// RequestProjectDeletion disables access to projectID, revoking its API
+// keys, and schedules its deletion after the grace period. Only owners may
+// delete a project.
+func (db *DB) RequestProjectDeletion(ctx context.Context, userID, projectID string) (deletion *Deletion, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := require(tx, projectID, userID, Owner); err != nil {
+			return err
+		}
+		deletion, err = db.insertDeletion(tx, ProjectDeletion, projectID, "", userID)
+		if err != nil {
+			return err
+		}
+		return db.setRevoked(ctx, tx, projectID, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+// RequestAccountDeletion disables userID and schedules its deletion after the
+// grace period, together with the projects userID is the only owner of
+func (db *DB) RequestAccountDeletion(ctx context.Context, userID string) (deletion *Deletion, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		if err := checkNotDeleting(tx, AccountDeletion, userID); err != nil {
+			return err
+		}
+		deletion, err = db.insertDeletion(tx, AccountDeletion, userID, "", userID)
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(`SELECT project_id FROM members m WHERE user_id = ? AND role = ? AND
+			(SELECT COUNT(*) FROM members o WHERE o.project_id = m.project_id AND o.role = ?) = 1`,
+			userID, Owner, Owner)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		var projects []string
+		for rows.Next() {
+			var projectID string
+			if err := rows.Scan(&projectID); err != nil {
+				_ = rows.Close()
+				return Error.Wrap(err)
+			}
+			projects = append(projects, projectID)
+		}
+		if err := rows.Close(); err != nil {
+			return Error.Wrap(err)
+		}
+
+		for _, projectID := range projects {
+			if checkNotDeleting(tx, ProjectDeletion, projectID) != nil {
+				continue
+			}
+			if _, err := db.insertDeletion(tx, ProjectDeletion, projectID, deletion.ID, userID); err != nil {
+				return err
+			}
+			if err := db.setRevoked(ctx, tx, projectID, true); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deletion, nil
+}
+
+// insertDeletion schedules the deletion of target
+func (db *DB) insertDeletion(tx *sql.Tx, kind DeletionKind, target, parent, userID string) (*Deletion, error) {
+	id, err := randomID(16)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	now := time.Now().UTC().Truncate(time.Second)
+	deletion := &Deletion{
+		ID:          id,
+		Kind:        kind,
+		Target:      target,
+		RequestedBy: userID,
+		Phase:       DeletionRequested,
+		Requested:   now,
+		Due:         now.Add(db.DeletionGracePeriod),
+	}
+
+	_, err = tx.Exec(`INSERT INTO deletions (id, kind, target, parent, requested_by, phase, requested, due) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		deletion.ID, deletion.Kind, deletion.Target, parent, deletion.RequestedBy, deletion.Phase,
+		deletion.Requested.Unix(), deletion.Due.Unix())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return deletion, logDeletion(tx, deletion.ID, DeletionRequested, "access disabled, requested by "+userID)
+}
+
+// CancelDeletion cancels deletionID and restores access, if the grace period
+// hasn't ended yet. Only the user who requested a deletion may cancel it.
+// Canceling an account deletion also cancels the project deletions it
+// scheduled.
+func (db *DB) CancelDeletion(ctx context.Context, userID, deletionID string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return db.withTx(ctx, func(tx *sql.Tx) error {
+		var requestedBy string
+		var phase DeletionPhase
+		err := tx.QueryRow(`SELECT requested_by, phase FROM deletions WHERE id = ?`, deletionID).Scan(&requestedBy, &phase)
+		if err == sql.ErrNoRows {
+			return ErrNotFound.New("deletion %q", deletionID)
+		}
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		if requestedBy != userID {
+			return ErrUnauthorized.New("deletion %q was requested by another user", deletionID)
+		}
+		if phase != DeletionRequested {
+			return Error.New("deletion %q can't be canceled, it is %s", deletionID, phase)
+		}
+
+		rows, err := tx.Query(`SELECT target FROM deletions WHERE (id = ? OR parent = ?) AND kind = ? AND phase = ?`,
+			deletionID, deletionID, ProjectDeletion, DeletionRequested)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		var projects []string
+		for rows.Next() {
+			var projectID string
+			if err := rows.Scan(&projectID); err != nil {
+				_ = rows.Close()
+				return Error.Wrap(err)
+			}
+			projects = append(projects, projectID)
+		}
+		if err := rows.Close(); err != nil {
+			return Error.Wrap(err)
+		}
+
+		_, err = tx.Exec(`UPDATE deletions SET phase = ? WHERE (id = ? OR parent = ?) AND phase = ?`,
+			DeletionCanceled, deletionID, deletionID, DeletionRequested)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		for _, projectID := range projects {
+			if err := db.setRevoked(ctx, tx, projectID, false); err != nil {
+				return err
+			}
+		}
+		return logDeletion(tx, deletionID, DeletionCanceled, "access restored, canceled by "+userID)
+	})
+}
+
+// DueDeletions returns the deletions that have a phase to process at now
+func (db *DB) DueDeletions(ctx context.Context, now time.Time) (deletions []*Deletion, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT id, kind, target, requested_by, phase, requested, due FROM deletions
+			WHERE (phase = ? AND due <= ?) OR phase = ? ORDER BY due, id`,
+			DeletionRequested, now.Unix(), DataDeleted)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			deletion := &Deletion{}
+			var requested, due int64
+			err := rows.Scan(&deletion.ID, &deletion.Kind, &deletion.Target, &deletion.RequestedBy,
+				&deletion.Phase, &requested, &due)
+			if err != nil {
+				return Error.Wrap(err)
+			}
+			deletion.Requested = time.Unix(requested, 0).UTC()
+			deletion.Due = time.Unix(due, 0).UTC()
+			deletions = append(deletions, deletion)
+		}
+		return Error.Wrap(rows.Err())
+	})
+	return deletions, err
+}
+
+// DeletionLog returns the audit log of deletionID
+func (db *DB) DeletionLog(ctx context.Context, deletionID string) (events []DeletionEvent, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT phase, at, message FROM deletion_log WHERE deletion_id = ? ORDER BY rowid`, deletionID)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			var event DeletionEvent
+			var at int64
+			if err := rows.Scan(&event.Phase, &at, &event.Message); err != nil {
+				return Error.Wrap(err)
+			}
+			event.At = time.Unix(at, 0).UTC()
+			events = append(events, event)
+		}
+		return Error.Wrap(rows.Err())
+	})
+	return events, err
+}
+
+// FinishDataDeletion records that the data of deletion was deleted
+func (db *DB) FinishDataDeletion(ctx context.Context, deletion *Deletion, message string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return db.withTx(ctx, func(tx *sql.Tx) error {
+		if deletion.Kind == AccountDeletion {
+			_, err := tx.Exec(`DELETE FROM members WHERE user_id = ?`, deletion.Target)
+			if err != nil {
+				return Error.Wrap(err)
+			}
+		}
+		return advanceDeletion(tx, deletion, DeletionRequested, DataDeleted, message)
+	})
+}
+
+// Purge deletes the console records of deletion once its data was deleted
+func (db *DB) Purge(ctx context.Context, deletion *Deletion) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var stmts []string
+	switch deletion.Kind {
+	case ProjectDeletion:
+		stmts = []string{
+			`DELETE FROM projects WHERE id = ?`,
+			`DELETE FROM members WHERE project_id = ?`,
+			`DELETE FROM invitations WHERE project_id = ?`,
+			`DELETE FROM api_keys WHERE project_id = ?`,
+		}
+	case AccountDeletion:
+		stmts = []string{
+			`DELETE FROM users WHERE id = ?`,
+			`DELETE FROM user_tokens WHERE user_id = ?`,
+			`DELETE FROM members WHERE user_id = ?`,
+		}
+	default:
+		return Error.New("invalid deletion kind %d", deletion.Kind)
+	}
+
+	return db.withTx(ctx, func(tx *sql.Tx) error {
+		for _, stmt := range stmts {
+			if _, err := tx.Exec(stmt, deletion.Target); err != nil {
+				return Error.Wrap(err)
+			}
+		}
+		return advanceDeletion(tx, deletion, DataDeleted, Purged, "console records purged")
+	})
+}
+
+// advanceDeletion moves deletion from phase from to phase to and logs it
+func advanceDeletion(tx *sql.Tx, deletion *Deletion, from, to DeletionPhase, message string) error {
+	result, err := tx.Exec(`UPDATE deletions SET phase = ? WHERE id = ? AND phase = ?`, to, deletion.ID, from)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if updated == 0 {
+		return Error.New("deletion %q is not %s", deletion.ID, from)
+	}
+	deletion.Phase = to
+	return logDeletion(tx, deletion.ID, to, message)
+}
+
+func logDeletion(tx *sql.Tx, deletionID string, phase DeletionPhase, message string) error {
+	_, err := tx.Exec(`INSERT INTO deletion_log (deletion_id, phase, at, message) VALUES (?, ?, ?, ?)`,
+		deletionID, phase, time.Now().Unix(), message)
+	return Error.Wrap(err)
+}
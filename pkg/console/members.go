@@ -0,0 +1,258 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"storj.io/storj/pkg/mail"
+)
+
+// ProjectMember is a user that is a member of a project
+type ProjectMember struct {
+	UserID string
+	Role   Role
+	Joined time.Time
+}
+
+// Invitation is an invitation of an email address to join a project. The
+// token of the invitation is only returned when it is created; it is sent to
+// the invited email address and is needed to accept the invitation.
+type Invitation struct {
+	Token     string
+	ProjectID string
+	Email     string
+	Role      Role
+	Expires   time.Time
+}
+
+// Members returns the members of projectID. Every member may list the
+// members of a project.
+func (db *DB) Members(ctx context.Context, userID, projectID string) (members []ProjectMember, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := require(tx, projectID, userID, Member); err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(`SELECT user_id, role, created FROM members WHERE project_id = ? ORDER BY created, user_id`, projectID)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			var member ProjectMember
+			var created int64
+			if err := rows.Scan(&member.UserID, &member.Role, &created); err != nil {
+				return Error.Wrap(err)
+			}
+			member.Joined = time.Unix(created, 0).UTC()
+			members = append(members, member)
+		}
+		return Error.Wrap(rows.Err())
+	})
+	return members, err
+}
+
+// Invite invites email to join projectID with role and emails the
+// invitation. Admins and owners may invite members and admins. The owner role
+// can't be given by invitation, an owner has to grant it with SetRole.
+func (db *DB) Invite(ctx context.Context, userID, projectID, email string, role Role) (invitation *Invitation, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	email = normalizeEmail(email)
+	if email == "" {
+		return nil, Error.New("email is empty")
+	}
+	if !role.valid() {
+		return nil, Error.New("invalid role %d", role)
+	}
+	if role == Owner {
+		return nil, ErrUnauthorized.New("owner role can't be given by invitation")
+	}
+
+	token, err := randomID(32)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	invitation = &Invitation{
+		Token:     token,
+		ProjectID: projectID,
+		Email:     email,
+		Role:      role,
+		Expires:   time.Now().Add(db.InvitationExpiration).UTC().Truncate(time.Second),
+	}
+
+	var projectName string
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		own, err := require(tx, projectID, userID, Admin)
+		if err != nil {
+			return err
+		}
+		if role > own {
+			return ErrUnauthorized.New("%s can't invite %s", own, role)
+		}
+
+		err = tx.QueryRow(`SELECT name FROM projects WHERE id = ?`, projectID).Scan(&projectName)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		_, err = tx.Exec(`INSERT INTO invitations (token_hash, project_id, email, role, invited_by, expires) VALUES (?, ?, ?, ?, ?, ?)`,
+			hashSecret(token), projectID, email, role, userID, invitation.Expires.Unix())
+		return Error.Wrap(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.sendMail(ctx, email, mail.Invitation, mail.InvitationData{
+		ProjectName: projectName,
+		Role:        role.String(),
+		Link:        db.link("invitation", token),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return invitation, nil
+}
+
+// AcceptInvitation makes userID a member of the project email was invited to
+// with token. The caller is responsible for checking that email is the
+// verified address of userID. The invitation can only be used once.
+func (db *DB) AcceptInvitation(ctx context.Context, userID, email, token string) (projectID string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		var invited string
+		var role Role
+		var expires int64
+		err := tx.QueryRow(`SELECT project_id, email, role, expires FROM invitations WHERE token_hash = ?`, hashSecret(token)).
+			Scan(&projectID, &invited, &role, &expires)
+		if err == sql.ErrNoRows {
+			return ErrNotFound.New("invitation")
+		}
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		if invited != normalizeEmail(email) {
+			return ErrUnauthorized.New("invitation is for a different email address")
+		}
+		if time.Now().Unix() > expires {
+			return ErrUnauthorized.New("invitation expired")
+		}
+
+		_, err = tx.Exec(`DELETE FROM invitations WHERE token_hash = ?`, hashSecret(token))
+		if err != nil {
+			return Error.Wrap(err)
+		}
+
+		current, err := roleOf(tx, projectID, userID)
+		if err == nil {
+			if current < role {
+				_, err = tx.Exec(`UPDATE members SET role = ? WHERE project_id = ? AND user_id = ?`, role, projectID, userID)
+			}
+			return Error.Wrap(err)
+		}
+		if !ErrNotFound.Has(err) {
+			return err
+		}
+
+		_, err = tx.Exec(`INSERT INTO members (project_id, user_id, role, created) VALUES (?, ?, ?, ?)`,
+			projectID, userID, role, time.Now().Unix())
+		return Error.Wrap(err)
+	})
+	if err != nil {
+		return "", err
+	}
+	return projectID, nil
+}
+
+// SetRole changes the role of memberID in projectID. Admins may change roles
+// of members and admins up to admin, only owners may grant or revoke the
+// owner role. The last owner of a project can't be demoted.
+func (db *DB) SetRole(ctx context.Context, userID, projectID, memberID string, role Role) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !role.valid() {
+		return Error.New("invalid role %d", role)
+	}
+
+	return db.withTx(ctx, func(tx *sql.Tx) error {
+		own, err := require(tx, projectID, userID, Admin)
+		if err != nil {
+			return err
+		}
+		current, err := roleOf(tx, projectID, memberID)
+		if err != nil {
+			return err
+		}
+		if current > own || role > own {
+			return ErrUnauthorized.New("%s can't change %s to %s", own, current, role)
+		}
+		if current == Owner && role != Owner {
+			if err := checkNotLastOwner(tx, projectID); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(`UPDATE members SET role = ? WHERE project_id = ? AND user_id = ?`, role, projectID, memberID)
+		return Error.Wrap(err)
+	})
+}
+
+// RemoveMember removes memberID from projectID. Admins may remove members and
+// admins, owners may also remove owners, and every member may leave. The
+// last owner of a project can't be removed.
+func (db *DB) RemoveMember(ctx context.Context, userID, projectID, memberID string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return db.withTx(ctx, func(tx *sql.Tx) error {
+		required := Admin
+		if userID == memberID {
+			required = Member
+		}
+		own, err := require(tx, projectID, userID, required)
+		if err != nil {
+			return err
+		}
+		current, err := roleOf(tx, projectID, memberID)
+		if err != nil {
+			return err
+		}
+		if current > own {
+			return ErrUnauthorized.New("%s can't remove %s", own, current)
+		}
+		if current == Owner {
+			if err := checkNotLastOwner(tx, projectID); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(`DELETE FROM members WHERE project_id = ? AND user_id = ?`, projectID, memberID)
+		return Error.Wrap(err)
+	})
+}
+
+// checkNotLastOwner returns an error if projectID has only one owner
+func checkNotLastOwner(tx *sql.Tx, projectID string) error {
+	var owners int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM members WHERE project_id = ? AND role = ?`, projectID, Owner).Scan(&owners)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if owners <= 1 {
+		return ErrUnauthorized.New("project %q needs an owner", projectID)
+	}
+	return nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
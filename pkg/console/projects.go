@@ -0,0 +1,105 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Project is a console project
+type Project struct {
+	ID          string
+	Name        string
+	Description string
+	Created     time.Time
+	// SignupToken is the id of the signup token the user who created the
+	// project registered with
+	SignupToken string
+}
+
+// CreateProject creates a project called name with userID as its owner. The
+// project is attributed to the signup token of the user.
+func (db *DB) CreateProject(ctx context.Context, userID, name string) (project *Project, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if name == "" {
+		return nil, Error.New("project name is empty")
+	}
+
+	id, err := randomID(16)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	project = &Project{ID: id, Name: name, Created: time.Now().UTC().Truncate(time.Second)}
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		var signupToken sql.NullString
+		err := tx.QueryRow(`SELECT signup_token FROM users WHERE id = ?`, userID).Scan(&signupToken)
+		if err != nil && err != sql.ErrNoRows {
+			return Error.Wrap(err)
+		}
+		project.SignupToken = signupToken.String
+
+		_, err = tx.Exec(`INSERT INTO projects (id, name, description, created, signup_token) VALUES (?, ?, '', ?, ?)`,
+			project.ID, project.Name, project.Created.Unix(), project.SignupToken)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		_, err = tx.Exec(`INSERT INTO members (project_id, user_id, role, created) VALUES (?, ?, ?, ?)`,
+			project.ID, userID, Owner, project.Created.Unix())
+		return Error.Wrap(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// GetProject returns the project projectID. Every member may view a
+// project.
+func (db *DB) GetProject(ctx context.Context, userID, projectID string) (project *Project, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := require(tx, projectID, userID, Member); err != nil {
+			return err
+		}
+
+		var created int64
+		var signupToken sql.NullString
+		project = &Project{ID: projectID}
+		err := tx.QueryRow(`SELECT name, description, created, signup_token FROM projects WHERE id = ?`, projectID).
+			Scan(&project.Name, &project.Description, &created, &signupToken)
+		if err == sql.ErrNoRows {
+			return ErrNotFound.New("project %q", projectID)
+		}
+		project.Created = time.Unix(created, 0).UTC()
+		project.SignupToken = signupToken.String
+		return Error.Wrap(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+// UpdateProject changes the settings of projectID. Only admins and owners may
+// change project settings.
+func (db *DB) UpdateProject(ctx context.Context, userID, projectID, name, description string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if name == "" {
+		return Error.New("project name is empty")
+	}
+
+	return db.withTx(ctx, func(tx *sql.Tx) error {
+		if _, err := require(tx, projectID, userID, Admin); err != nil {
+			return err
+		}
+		_, err := tx.Exec(`UPDATE projects SET name = ?, description = ? WHERE id = ?`, name, description, projectID)
+		return Error.Wrap(err)
+	})
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"strings"
+)
+
+// Role is the role of a member in a project. Roles are ordered, every role
+// may do everything the lower roles may do.
+type Role int
+
+const (
+	// Member may view the project, its members and its API keys
+	Member Role = iota + 1
+	// Admin may also change the project settings, issue API keys and invite
+	// and remove members up to their own role
+	Admin
+	// Owner may also grant and revoke the admin and owner roles
+	Owner
+)
+
+// String returns the name of the role
+func (role Role) String() string {
+	switch role {
+	case Member:
+		return "member"
+	case Admin:
+		return "admin"
+	case Owner:
+		return "owner"
+	default:
+		return "invalid"
+	}
+}
+
+// ParseRole returns the role called name
+func ParseRole(name string) (Role, error) {
+	for _, role := range []Role{Member, Admin, Owner} {
+		if strings.EqualFold(name, role.String()) {
+			return role, nil
+		}
+	}
+	return 0, Error.New("invalid role %q", name)
+}
+
+// valid checks that role is one of the defined roles
+func (role Role) valid() bool {
+	return Member <= role && role <= Owner
+}
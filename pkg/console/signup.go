@@ -0,0 +1,163 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// SignupToken lets a limited number of users register, for referral links or
+// to hand out invitations to a private beta. The users who register with a
+// token and their projects are attributed to it.
+type SignupToken struct {
+	ID string
+	// Token is the secret of the token. It is only set when the token is
+	// created.
+	Token string
+	Name  string
+	// CreatedBy is the id of the user referring others with the token, or
+	// empty for tokens created by the satellite operator
+	CreatedBy string
+	// MaxUses is how many users may register with the token, 0 if unlimited
+	MaxUses int
+	Uses    int
+	// Expires is when the token stops being valid, zero if never
+	Expires time.Time
+	Created time.Time
+	Revoked bool
+}
+
+// CreateSignupToken creates a signup token called name that maxUses users
+// may register with, or any number if maxUses is 0, until expiration if it is
+// not 0. createdBy is the id of the referring user, or empty.
+func (db *DB) CreateSignupToken(ctx context.Context, createdBy, name string, maxUses int, expiration time.Duration) (token *SignupToken, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if name == "" {
+		return nil, Error.New("signup token name is empty")
+	}
+	if maxUses < 0 {
+		return nil, Error.New("invalid signup token uses %d", maxUses)
+	}
+
+	id, err := randomID(16)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	secret, err := randomID(32)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	token = &SignupToken{
+		ID:        id,
+		Token:     secret,
+		Name:      name,
+		CreatedBy: createdBy,
+		MaxUses:   maxUses,
+		Created:   time.Now().UTC().Truncate(time.Second),
+	}
+	var expires int64
+	if expiration != 0 {
+		token.Expires = token.Created.Add(expiration)
+		expires = token.Expires.Unix()
+	}
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`INSERT INTO signup_tokens (id, token_hash, name, created_by, max_uses, uses, expires, created, revoked) VALUES (?, ?, ?, ?, ?, 0, ?, ?, 0)`,
+			token.ID, hashSecret(secret), token.Name, token.CreatedBy, token.MaxUses, expires, token.Created.Unix())
+		return Error.Wrap(err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// SignupLink returns the console link to register with token
+func (db *DB) SignupLink(token string) string {
+	return db.link("signup", token)
+}
+
+// SignupTokens returns the signup tokens, without their secrets, with how
+// many users registered with them
+func (db *DB) SignupTokens(ctx context.Context) (tokens []SignupToken, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT id, name, created_by, max_uses, uses, expires, created, revoked FROM signup_tokens ORDER BY created, name`)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			var token SignupToken
+			var expires, created int64
+			err := rows.Scan(&token.ID, &token.Name, &token.CreatedBy, &token.MaxUses, &token.Uses, &expires, &created, &token.Revoked)
+			if err != nil {
+				return Error.Wrap(err)
+			}
+			if expires != 0 {
+				token.Expires = time.Unix(expires, 0).UTC()
+			}
+			token.Created = time.Unix(created, 0).UTC()
+			tokens = append(tokens, token)
+		}
+		return Error.Wrap(rows.Err())
+	})
+	return tokens, err
+}
+
+// RevokeSignupToken stops users from registering with the signup token id.
+// The users who already registered with it stay attributed to it.
+func (db *DB) RevokeSignupToken(ctx context.Context, id string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return db.withTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE signup_tokens SET revoked = 1 WHERE id = ?`, id)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		if n == 0 {
+			return ErrNotFound.New("signup token %q", id)
+		}
+		return nil
+	})
+}
+
+// useSignupToken counts a use of token and returns its id if users can
+// still register with it
+func useSignupToken(tx *sql.Tx, token string) (id string, err error) {
+	var maxUses, uses int
+	var expires int64
+	var revoked bool
+	err = tx.QueryRow(`SELECT id, max_uses, uses, expires, revoked FROM signup_tokens WHERE token_hash = ?`, hashSecret(token)).
+		Scan(&id, &maxUses, &uses, &expires, &revoked)
+	if err == sql.ErrNoRows {
+		return "", ErrUnauthorized.New("invalid signup token")
+	}
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	switch {
+	case revoked:
+		return "", ErrUnauthorized.New("signup token revoked")
+	case expires != 0 && time.Now().Unix() > expires:
+		return "", ErrUnauthorized.New("signup token expired")
+	case maxUses > 0 && uses >= maxUses:
+		return "", ErrUnauthorized.New("signup token used up")
+	}
+
+	_, err = tx.Exec(`UPDATE signup_tokens SET uses = uses + 1 WHERE id = ?`, id)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	return id, nil
+}
@@ -0,0 +1,278 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package console
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"storj.io/storj/pkg/analytics"
+	"storj.io/storj/pkg/mail"
+)
+
+const (
+	// activationExpiration is how long activation links are valid
+	activationExpiration = 24 * time.Hour
+	// passwordResetExpiration is how long password reset links are valid
+	passwordResetExpiration = time.Hour
+	// minPasswordLength is the minimum length of user passwords
+	minPasswordLength = 8
+)
+
+// tokenKind is what a user token is used for
+type tokenKind int
+
+const (
+	activationToken tokenKind = iota + 1
+	passwordResetToken
+)
+
+// User is a console user
+type User struct {
+	ID      string
+	Email   string
+	Active  bool
+	Created time.Time
+	// SignupToken is the id of the signup token the user registered with
+	SignupToken string
+}
+
+// Register creates an inactive user with email and password and sends an
+// activation link to email. The user is created even if the link can't be
+// sent.
+func (db *DB) Register(ctx context.Context, email, password string) (user *User, err error) {
+	return db.RegisterWithToken(ctx, email, password, "")
+}
+
+// RegisterWithToken registers a user like Register, attributing the user to
+// signupToken if it is not empty. Users can only register without a signup
+// token if the console doesn't require one.
+func (db *DB) RegisterWithToken(ctx context.Context, email, password, signupToken string) (user *User, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if signupToken == "" && db.RequireSignupToken {
+		return nil, ErrUnauthorized.New("a signup token is required to register")
+	}
+
+	email = normalizeEmail(email)
+	if !strings.Contains(email, "@") {
+		return nil, Error.New("invalid email %q", email)
+	}
+	if len(password) < minPasswordLength {
+		return nil, Error.New("password must be at least %d characters", minPasswordLength)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	id, err := randomID(16)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	user = &User{ID: id, Email: email, Created: time.Now().UTC().Truncate(time.Second)}
+
+	var token string
+	err = db.withTx(ctx, func(tx *sql.Tx) (err error) {
+		var exists int
+		err = tx.QueryRow(`SELECT COUNT(*) FROM users WHERE email = ?`, email).Scan(&exists)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		if exists > 0 {
+			return Error.New("email %q is already registered", email)
+		}
+
+		if signupToken != "" {
+			user.SignupToken, err = useSignupToken(tx, signupToken)
+			if err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.Exec(`INSERT INTO users (id, email, password_hash, active, created, signup_token) VALUES (?, ?, ?, 0, ?, ?)`,
+			user.ID, user.Email, hash, user.Created.Unix(), user.SignupToken)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		token, err = createToken(tx, activationToken, user.ID, activationExpiration)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := analytics.Event{Name: analytics.AccountCreated, UserID: user.ID}
+	if user.SignupToken != "" {
+		event.Properties = map[string]string{"signup_token": user.SignupToken}
+	}
+	db.Analytics.Emit(event)
+
+	// if the activation email can't be sent, the user can still be activated
+	// with a password reset
+	err = db.sendMail(ctx, email, mail.Activation, mail.LinkData{Link: db.link("activate", token)})
+	if err != nil {
+		db.Log.Warn("could not send the activation email", zap.String("user", user.ID), zap.Error(err))
+	}
+	return user, nil
+}
+
+// Activate activates the user the activation token was sent to
+func (db *DB) Activate(ctx context.Context, token string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	return db.withTx(ctx, func(tx *sql.Tx) error {
+		userID, err := useToken(tx, activationToken, token)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(`UPDATE users SET active = 1 WHERE id = ?`, userID)
+		return Error.Wrap(err)
+	})
+}
+
+// Authenticate returns the active user with email and password. Accounts
+// that are being deleted can't log in.
+func (db *DB) Authenticate(ctx context.Context, email, password string) (user *User, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var hash []byte
+	var created int64
+	var signupToken sql.NullString
+	var deleting error
+	user = &User{}
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`SELECT id, email, password_hash, active, created, signup_token FROM users WHERE email = ?`, normalizeEmail(email)).
+			Scan(&user.ID, &user.Email, &hash, &user.Active, &created, &signupToken)
+		if err == sql.ErrNoRows {
+			return ErrUnauthorized.New("invalid email or password")
+		}
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		deleting = checkNotDeleting(tx, AccountDeletion, user.ID)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword(hash, []byte(password)) != nil {
+		return nil, ErrUnauthorized.New("invalid email or password")
+	}
+	if !user.Active {
+		return nil, ErrUnauthorized.New("user %q is not activated", user.Email)
+	}
+	if deleting != nil {
+		return nil, deleting
+	}
+	user.Created = time.Unix(created, 0).UTC()
+	user.SignupToken = signupToken.String
+	return user, nil
+}
+
+// RequestPasswordReset sends a password reset link to email. It doesn't
+// return an error if no user is registered with email, so that it can't be
+// used to find out which addresses are registered.
+func (db *DB) RequestPasswordReset(ctx context.Context, email string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	email = normalizeEmail(email)
+	var token string
+	err = db.withTx(ctx, func(tx *sql.Tx) error {
+		var userID string
+		err := tx.QueryRow(`SELECT id FROM users WHERE email = ?`, email).Scan(&userID)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		token, err = createToken(tx, passwordResetToken, userID, passwordResetExpiration)
+		return err
+	})
+	if err != nil || token == "" {
+		return err
+	}
+
+	return db.sendMail(ctx, email, mail.PasswordReset, mail.LinkData{Link: db.link("password-reset", token)})
+}
+
+// ResetPassword sets the password of the user the password reset token was
+// sent to
+func (db *DB) ResetPassword(ctx context.Context, token, password string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(password) < minPasswordLength {
+		return Error.New("password must be at least %d characters", minPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	return db.withTx(ctx, func(tx *sql.Tx) error {
+		userID, err := useToken(tx, passwordResetToken, token)
+		if err != nil {
+			return err
+		}
+		// following a reset link also proves the user owns the address
+		_, err = tx.Exec(`UPDATE users SET password_hash = ?, active = 1 WHERE id = ?`, hash, userID)
+		return Error.Wrap(err)
+	})
+}
+
+// createToken creates a token of kind for userID that expires after
+// expiration
+func createToken(tx *sql.Tx, kind tokenKind, userID string, expiration time.Duration) (string, error) {
+	token, err := randomID(32)
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	_, err = tx.Exec(`INSERT INTO user_tokens (token_hash, kind, user_id, expires) VALUES (?, ?, ?, ?)`,
+		hashSecret(token), kind, userID, time.Now().Add(expiration).Unix())
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	return token, nil
+}
+
+// useToken deletes token and returns the user it was created for if it is
+// of kind and not expired
+func useToken(tx *sql.Tx, kind tokenKind, token string) (userID string, err error) {
+	var expires int64
+	err = tx.QueryRow(`SELECT user_id, expires FROM user_tokens WHERE token_hash = ? AND kind = ?`, hashSecret(token), kind).
+		Scan(&userID, &expires)
+	if err == sql.ErrNoRows {
+		return "", ErrNotFound.New("token")
+	}
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	_, err = tx.Exec(`DELETE FROM user_tokens WHERE token_hash = ?`, hashSecret(token))
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	if time.Now().Unix() > expires {
+		return "", ErrUnauthorized.New("token expired")
+	}
+	return userID, nil
+}
+
+// link returns the console link to path with token
+func (db *DB) link(path, token string) string {
+	return strings.TrimSuffix(db.ExternalAddress, "/") + "/" + path + "?token=" + token
+}
+
+// sendMail sends tmpl to email if the console sends emails
+func (db *DB) sendMail(ctx context.Context, email string, tmpl *mail.Template, data interface{}) error {
+	if db.Mail == nil {
+		return nil
+	}
+	return db.Mail.Send(ctx, email, tmpl, data)
+}
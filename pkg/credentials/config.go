@@ -0,0 +1,60 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package credentials
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage/boltdb"
+	"storj.io/storj/storage/storelogger"
+)
+
+// CredentialsBucket is the bucket the credentials are stored in
+const CredentialsBucket = "credentials"
+
+// Config contains everything necessary to run the credential service
+type Config struct {
+	Enabled     bool   `help:"whether access grants are exchanged for S3 credentials of hosted gateways" default:"false"`
+	DatabaseURL string `help:"the database connection string to use" default:"bolt://$CONFDIR/credentials.db"`
+	GatewayIDs  string `help:"comma-separated node ids of the gateways that may resolve S3 credentials to access grants" default:""`
+}
+
+// Run implements the provider.Responsibility interface
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !c.Enabled {
+		return server.Run(ctx)
+	}
+
+	dburl, err := utils.ParseURL(c.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	if dburl.Scheme != "bolt" {
+		return Error.New("unsupported db scheme: %s", dburl.Scheme)
+	}
+
+	bdb, err := boltdb.New(dburl.Path, CredentialsBucket)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = bdb.Close() }()
+
+	var gateways []string
+	if c.GatewayIDs != "" {
+		gateways = strings.Split(c.GatewayIDs, ",")
+	}
+
+	s := NewServer(zap.L().Named("credentials"), storelogger.New(zap.L().Named("credentials"), bdb), gateways)
+	pb.RegisterCredentialsServer(server.GRPC(), s)
+
+	return server.Run(ctx)
+}
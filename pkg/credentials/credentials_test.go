@@ -0,0 +1,135 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package credentials
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	grpccredentials "google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/storage/teststore"
+)
+
+func peerContext(t *testing.T) (context.Context, *provider.FullIdentity) {
+	ca, err := provider.NewCA(context.Background(), 12, 4)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	identity, err := ca.NewIdentity()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return peer.NewContext(context.Background(), &peer.Peer{
+		AuthInfo: grpccredentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{identity.Leaf, identity.CA},
+		}},
+	}), identity
+}
+
+func TestCredentials(t *testing.T) {
+	ctx := context.Background()
+	gatewayCtx, gateway := peerContext(t)
+	otherCtx, _ := peerContext(t)
+	s := NewServer(zap.NewNop(), teststore.New(), []string{gateway.ID.String()})
+
+	_, err := s.Register(ctx, &pb.RegisterCredentialsRequest{Grant: &pb.AccessGrant{SatelliteAddr: "satellite"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	grant := &pb.AccessGrant{SatelliteAddr: "satellite", APIKey: []byte("key"), PartnerId: "partner"}
+	creds, err := s.Register(ctx, &pb.RegisterCredentialsRequest{Grant: grant})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NotEmpty(t, creds.GetAccessKeyId())
+	assert.NotEmpty(t, creds.GetSecretKey())
+
+	other, err := s.Register(ctx, &pb.RegisterCredentialsRequest{Grant: grant})
+	if assert.NoError(t, err) {
+		assert.NotEqual(t, creds.GetAccessKeyId(), other.GetAccessKeyId())
+	}
+
+	resolve := &pb.ResolveCredentialsRequest{AccessKeyId: creds.GetAccessKeyId()}
+	resolved, err := s.Resolve(gatewayCtx, resolve)
+	if assert.NoError(t, err) {
+		assert.Equal(t, creds.GetSecretKey(), resolved.GetSecretKey())
+		assert.True(t, proto.Equal(grant, resolved.GetGrant()))
+	}
+
+	_, err = s.Resolve(ctx, resolve)
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.Resolve(otherCtx, resolve)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.Resolve(gatewayCtx, &pb.ResolveCredentialsRequest{AccessKeyId: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = s.Revoke(ctx, &pb.RevokeCredentialsRequest{AccessKeyId: creds.GetAccessKeyId(), SecretKey: other.GetSecretKey()})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.Revoke(ctx, &pb.RevokeCredentialsRequest{AccessKeyId: creds.GetAccessKeyId(), SecretKey: creds.GetSecretKey()})
+	assert.NoError(t, err)
+	_, err = s.Resolve(gatewayCtx, resolve)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+// watchStream is the server side of a WatchRevocations stream that passes
+// the revocations to sent
+type watchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan string
+}
+
+func (stream *watchStream) Context() context.Context { return stream.ctx }
+
+func (stream *watchStream) Send(revoked *pb.RevokedCredentials) error {
+	stream.sent <- revoked.GetAccessKeyId()
+	return nil
+}
+
+func TestWatchRevocations(t *testing.T) {
+	ctx := context.Background()
+	gatewayCtx, gateway := peerContext(t)
+	otherCtx, _ := peerContext(t)
+	s := NewServer(zap.NewNop(), teststore.New(), []string{gateway.ID.String()})
+
+	err := s.WatchRevocations(&pb.WatchRevocationsRequest{}, &watchStream{ctx: otherCtx})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	creds, err := s.Register(ctx, &pb.RegisterCredentialsRequest{Grant: &pb.AccessGrant{SatelliteAddr: "satellite", APIKey: []byte("key")}})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	watchCtx, cancel := context.WithCancel(gatewayCtx)
+	stream := &watchStream{ctx: watchCtx, sent: make(chan string, 1)}
+	done := make(chan error, 1)
+	go func() { done <- s.WatchRevocations(&pb.WatchRevocationsRequest{}, stream) }()
+
+	// the revocation is only streamed once the gateway watches
+	for watching := false; !watching; time.Sleep(time.Millisecond) {
+		s.mu.Lock()
+		watching = len(s.watchers) == 1
+		s.mu.Unlock()
+	}
+
+	_, err = s.Revoke(ctx, &pb.RevokeCredentialsRequest{AccessKeyId: creds.GetAccessKeyId(), SecretKey: creds.GetSecretKey()})
+	if assert.NoError(t, err) {
+		assert.Equal(t, creds.GetAccessKeyId(), <-stream.sent)
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
@@ -0,0 +1,208 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package credentials
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/mr-tron/base58/base58"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/storage"
+)
+
+const (
+	accessKeyIDSize = 15
+	secretKeySize   = 30
+
+	// watchBuffer is how many revocations are queued for a watching
+	// gateway before its stream is ended for falling behind
+	watchBuffer = 64
+)
+
+// Server implements the credential service. The credentials are stored in db
+// by access key id, together with the access grant they were issued for.
+type Server struct {
+	log      *zap.Logger
+	db       storage.KeyValueStore
+	gateways map[string]bool
+
+	mu       sync.Mutex
+	watchers map[chan string]struct{}
+}
+
+// NewServer creates a credential service that only resolves credentials for
+// the gateways with the node ids in gateways
+func NewServer(log *zap.Logger, db storage.KeyValueStore, gateways []string) *Server {
+	s := &Server{log: log, db: db, gateways: map[string]bool{}, watchers: map[chan string]struct{}{}}
+	for _, id := range gateways {
+		s.gateways[id] = true
+	}
+	return s
+}
+
+// Register stores an access grant and returns new S3 credentials for it
+func (s *Server) Register(ctx context.Context, req *pb.RegisterCredentialsRequest) (resp *pb.RegisterCredentialsResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	grant := req.GetGrant()
+	if grant.GetSatelliteAddr() == "" || len(grant.GetAPIKey()) == 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "access grant requires a satellite address and an API key")
+	}
+
+	accessKeyID, err := randomKey(accessKeyIDSize)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	secretKey, err := randomKey(secretKeySize)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	value, err := proto.Marshal(&pb.ResolveCredentialsResponse{SecretKey: secretKey, Grant: grant})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if err = s.db.Put(storage.Key(accessKeyID), value); err != nil {
+		s.log.Error("err storing credentials", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	return &pb.RegisterCredentialsResponse{AccessKeyId: accessKeyID, SecretKey: secretKey}, nil
+}
+
+// Resolve returns the secret key and access grant of an access key id to a
+// trusted gateway
+func (s *Server) Resolve(ctx context.Context, req *pb.ResolveCredentialsRequest) (resp *pb.ResolveCredentialsResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if err = s.trustedGateway(ctx); err != nil {
+		return nil, err
+	}
+	return s.get(req.GetAccessKeyId())
+}
+
+// Revoke deletes credentials, which requires their secret key
+func (s *Server) Revoke(ctx context.Context, req *pb.RevokeCredentialsRequest) (resp *pb.RevokeCredentialsResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	stored, err := s.get(req.GetAccessKeyId())
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(stored.GetSecretKey()), []byte(req.GetSecretKey())) != 1 {
+		return nil, status.Errorf(codes.PermissionDenied, "invalid secret key")
+	}
+
+	if err = s.db.Delete(storage.Key(req.GetAccessKeyId())); err != nil {
+		s.log.Error("err deleting credentials", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	s.notify(req.GetAccessKeyId())
+	return &pb.RevokeCredentialsResponse{}, nil
+}
+
+// WatchRevocations streams the access key ids of the credentials revoked
+// while the stream is open to a trusted gateway. A gateway that falls behind
+// has its stream ended, and has to forget the credentials it resolved
+// before watching again.
+func (s *Server) WatchRevocations(req *pb.WatchRevocationsRequest, stream pb.Credentials_WatchRevocationsServer) (err error) {
+	ctx := stream.Context()
+	defer mon.Task()(&ctx)(&err)
+
+	if err = s.trustedGateway(ctx); err != nil {
+		return err
+	}
+
+	revoked := make(chan string, watchBuffer)
+	s.mu.Lock()
+	s.watchers[revoked] = struct{}{}
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.watchers, revoked)
+		s.mu.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case accessKeyID, ok := <-revoked:
+			if !ok {
+				return status.Errorf(codes.ResourceExhausted, "too many revocations queued")
+			}
+			if err = stream.Send(&pb.RevokedCredentials{AccessKeyId: accessKeyID}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// notify queues the revocation of accessKeyID for the watching gateways
+func (s *Server) notify(accessKeyID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for revoked := range s.watchers {
+		select {
+		case revoked <- accessKeyID:
+		default:
+			// the gateway can't be told about the revocation, so its stream
+			// is ended instead
+			delete(s.watchers, revoked)
+			close(revoked)
+		}
+	}
+}
+
+// trustedGateway returns an error if the peer of ctx isn't a trusted gateway
+func (s *Server) trustedGateway(ctx context.Context) error {
+	gateway, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return status.Errorf(codes.Unauthenticated, err.Error())
+	}
+	if !s.gateways[gateway.ID.String()] {
+		return status.Errorf(codes.PermissionDenied, "%s is not a trusted gateway", gateway.ID)
+	}
+	return nil
+}
+
+func (s *Server) get(accessKeyID string) (*pb.ResolveCredentialsResponse, error) {
+	if accessKeyID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "access key id not specified")
+	}
+
+	value, err := s.db.Get(storage.Key(accessKeyID))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, status.Errorf(codes.NotFound, "unknown access key id")
+		}
+		s.log.Error("err getting credentials", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	stored := &pb.ResolveCredentialsResponse{}
+	if err = proto.Unmarshal(value, stored); err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return stored, nil
+}
+
+// randomKey returns a random base58 encoded key of size bytes
+func randomKey(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", Error.Wrap(err)
+	}
+	return base58.Encode(b), nil
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package discovery
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/chore"
+	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/vetting"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for node discovery
+	Error = errs.Class("discovery error")
+)
+
+// Config contains everything necessary to start the node discovery
+// responsibility
+type Config struct {
+	Interval    time.Duration `help:"how frequently the network is crawled for new and changed nodes" default:"1h"`
+	Lookups     int           `help:"how many random ids the network is crawled from" default:"8"`
+	Limit       int           `help:"the maximum number of nodes found per lookup" default:"128"`
+	Concurrency int           `help:"how many nodes are verified in parallel" default:"5"`
+	DialTimeout time.Duration `help:"how long to wait for connections to the nodes verified" default:"20s"`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// Kademlia and Overlay responsibilities have been started before this one,
+// and tracks the uptime of nodes if the vetting responsibility has been
+// started too.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	kad := kademlia.LoadFromContext(ctx)
+	if kad == nil {
+		return Error.New("programmer error: kademlia responsibility unstarted")
+	}
+
+	cache := overlay.LoadFromContext(ctx)
+	if cache == nil {
+		return Error.New("programmer error: overlay responsibility unstarted")
+	}
+
+	verifier := NewVerifier(zap.L().Named("discovery"), transport.NewClientWithTimeout(server.Identity(), c.DialTimeout), vetting.LoadFromContext(ctx))
+	service := NewService(zap.L().Named("discovery"), c, kad, cache, verifier)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	discover, err := chore.New(ctx, "discovery", c.Interval, func(ctx context.Context) error {
+		_, err := service.Discover(ctx)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	go func() { _ = discover.Run(ctx) }()
+
+	return server.Run(ctx)
+}
@@ -0,0 +1,198 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package discovery
+
+import (
+	"context"
+	"crypto/rand"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/dht"
+	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
+)
+
+// Service crawls the network for nodes that are unknown to the overlay cache
+// or have changed since they were cached
+type Service struct {
+	log      *zap.Logger
+	config   Config
+	dht      dht.DHT
+	cache    *overlay.Cache
+	verifier Verifier
+}
+
+// NewService creates a new node discovery Service
+func NewService(log *zap.Logger, config Config, dht dht.DHT, cache *overlay.Cache, verifier Verifier) *Service {
+	return &Service{
+		log:      log,
+		config:   config,
+		dht:      dht,
+		cache:    cache,
+		verifier: verifier,
+	}
+}
+
+// Stats summarizes a crawl of the network
+type Stats struct {
+	// Found is the number of distinct nodes found
+	Found int
+	// New is the number of found nodes that weren't cached
+	New int
+	// Updated is the number of cached nodes whose address or capacity changed
+	Updated int
+	// Unreachable is the number of nodes that couldn't be verified
+	Unreachable int
+	// Invalid is the number of nodes whose identity didn't match their id
+	Invalid int
+}
+
+// Discover looks up random ids on the network, verifies every node found and
+// refreshes the overlay cache with the verified nodes
+func (service *Service) Discover(ctx context.Context) (stats Stats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	nodes, err := service.crawl(ctx)
+	if err != nil {
+		return stats, err
+	}
+	stats.Found = len(nodes)
+
+	concurrency := service.config.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	limiter := make(chan struct{}, concurrency)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		select {
+		case limiter <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return stats, ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(node *pb.Node) {
+			defer wg.Done()
+			defer func() { <-limiter }()
+
+			result := service.refresh(ctx, node)
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch result {
+			case resultNew:
+				stats.New++
+			case resultUpdated:
+				stats.Updated++
+			case resultUnreachable:
+				stats.Unreachable++
+			case resultInvalid:
+				stats.Invalid++
+			}
+		}(node)
+	}
+	wg.Wait()
+
+	service.log.Info("node discovery finished",
+		zap.Int("found", stats.Found),
+		zap.Int("new", stats.New),
+		zap.Int("updated", stats.Updated),
+		zap.Int("unreachable", stats.Unreachable),
+		zap.Int("invalid", stats.Invalid))
+	return stats, nil
+}
+
+// crawl returns the distinct nodes found by looking up random ids
+func (service *Service) crawl(ctx context.Context) (nodes []*pb.Node, err error) {
+	lookups := service.config.Lookups
+	if lookups < 1 {
+		lookups = 1
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i < lookups; i++ {
+		id, err := randomID()
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+
+		found, err := service.dht.GetNodes(ctx, id.String(), service.config.Limit)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+
+		for _, node := range found {
+			if node == nil || seen[node.GetId()] {
+				continue
+			}
+			seen[node.GetId()] = true
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes, nil
+}
+
+type result int
+
+const (
+	resultUnchanged result = iota
+	resultNew
+	resultUpdated
+	resultUnreachable
+	resultInvalid
+)
+
+// refresh verifies node and stores it in the cache if it is new or changed
+func (service *Service) refresh(ctx context.Context, node *pb.Node) result {
+	verified, err := service.verifier.Verify(ctx, node)
+	if err != nil {
+		service.log.Debug("could not verify node",
+			zap.String("node", node.GetId()), zap.Error(err))
+		if ErrIdentity.Has(err) {
+			return resultInvalid
+		}
+		return resultUnreachable
+	}
+
+	cached, err := service.cache.Get(ctx, verified.GetId())
+	if err != nil && !storage.ErrKeyNotFound.Has(err) {
+		service.log.Warn("could not get cached node",
+			zap.String("node", verified.GetId()), zap.Error(err))
+		return resultUnchanged
+	}
+	// the operator set with UpdateOperator is kept for nodes not reporting one
+	if cached != nil && verified.Operator == nil {
+		verified.Operator = cached.Operator
+	}
+	if cached != nil && proto.Equal(cached, verified) {
+		return resultUnchanged
+	}
+
+	if err := service.cache.Put(verified.GetId(), *verified); err != nil {
+		service.log.Warn("could not cache node",
+			zap.String("node", verified.GetId()), zap.Error(err))
+		return resultUnchanged
+	}
+	if cached == nil {
+		return resultNew
+	}
+	return resultUpdated
+}
+
+func randomID() (*kademlia.NodeID, error) {
+	id := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+	return kademlia.StringToNodeID(string(id)), nil
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage/teststore"
+)
+
+var ctx = context.Background()
+
+type mockVerifier struct {
+	free        map[string]int64
+	unreachable map[string]bool
+	impostors   map[string]bool
+}
+
+func (v *mockVerifier) Verify(ctx context.Context, node *pb.Node) (*pb.Node, error) {
+	if v.unreachable[node.Id] {
+		return nil, Error.New("connection refused")
+	}
+	if v.impostors[node.Id] {
+		return nil, ErrIdentity.New("node %s has identity impostor", node.Id)
+	}
+	return &pb.Node{
+		Id:           node.Id,
+		Address:      node.Address,
+		Type:         pb.NodeType_STORAGE,
+		Restrictions: &pb.NodeRestrictions{FreeDisk: v.free[node.Id]},
+	}, nil
+}
+
+func newNode(id, address string) *pb.Node {
+	return &pb.Node{Id: id, Address: &pb.NodeAddress{Address: address}}
+}
+
+func TestDiscover(t *testing.T) {
+	kad := kademlia.NewMockKademlia()
+	kad.Nodes = []*pb.Node{
+		newNode("new", "127.0.0.1:1"),
+		newNode("moved", "127.0.0.1:2"),
+		newNode("unchanged", "127.0.0.1:3"),
+		newNode("unreachable", "127.0.0.1:4"),
+		newNode("impostor", "127.0.0.1:5"),
+	}
+
+	cache := &overlay.Cache{DB: teststore.New(), DHT: kad}
+	assert.NoError(t, cache.Put("moved", pb.Node{
+		Id:           "moved",
+		Address:      &pb.NodeAddress{Address: "127.0.0.1:9"},
+		Type:         pb.NodeType_STORAGE,
+		Restrictions: &pb.NodeRestrictions{FreeDisk: 10},
+	}))
+	assert.NoError(t, cache.Put("unchanged", pb.Node{
+		Id:           "unchanged",
+		Address:      &pb.NodeAddress{Address: "127.0.0.1:3"},
+		Type:         pb.NodeType_STORAGE,
+		Restrictions: &pb.NodeRestrictions{FreeDisk: 30},
+	}))
+
+	verifier := &mockVerifier{
+		free:        map[string]int64{"new": 100, "moved": 10, "unchanged": 30},
+		unreachable: map[string]bool{"unreachable": true},
+		impostors:   map[string]bool{"impostor": true},
+	}
+
+	// every lookup finds the same nodes, which are only verified once
+	config := Config{Lookups: 3, Limit: 10, Concurrency: 2}
+	service := NewService(zap.NewNop(), config, kad, cache, verifier)
+
+	stats, err := service.Discover(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Stats{Found: 5, New: 1, Updated: 1, Unreachable: 1, Invalid: 1}, stats)
+
+	node, err := cache.Get(ctx, "new")
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(100), node.GetRestrictions().GetFreeDisk())
+	}
+	node, err = cache.Get(ctx, "moved")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "127.0.0.1:2", node.GetAddress().GetAddress())
+	}
+	_, err = cache.Get(ctx, "impostor")
+	assert.Error(t, err)
+
+	// nothing changed since the last crawl
+	stats, err = service.Discover(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, Stats{Found: 5, Unreachable: 1, Invalid: 1}, stats)
+}
+
+func TestDiscoverLookupFailure(t *testing.T) {
+	service := NewService(zap.NewNop(), Config{}, failingDHT{kademlia.NewMockKademlia()},
+		&overlay.Cache{DB: teststore.New()}, &mockVerifier{})
+	_, err := service.Discover(ctx)
+	assert.Error(t, err)
+}
+
+type failingDHT struct{ *kademlia.MockKademlia }
+
+func (failingDHT) GetNodes(ctx context.Context, start string, limit int, restrictions ...pb.Restriction) ([]*pb.Node, error) {
+	return nil, errors.New("lookup failed")
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package discovery
+
+import (
+	"context"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/vetting"
+)
+
+// ErrIdentity is returned when a node doesn't have the identity of its id
+var ErrIdentity = errs.Class("identity mismatch")
+
+// Verifier checks that a node is reachable and is who it claims to be
+type Verifier interface {
+	// Verify returns node updated with its current capacity
+	Verify(ctx context.Context, node *pb.Node) (*pb.Node, error)
+}
+
+type verifier struct {
+	log       *zap.Logger
+	transport *transport.Transport
+	vetting   *vetting.Tracker
+}
+
+// NewVerifier creates a Verifier that dials storage nodes and asks them
+// for their stats. Every verification counts as an uptime check of the node
+// in tracker, which may be nil, sends the node its vetting progress and
+// audit status and records the maintenance window it announces.
+func NewVerifier(log *zap.Logger, t *transport.Transport, tracker *vetting.Tracker) Verifier {
+	return &verifier{log: log, transport: t, vetting: tracker}
+}
+
+// Verify dials node, checks the id of its TLS identity and only then asks it
+// for its free disk space, whether it reached its monthly bandwidth caps and
+// its operator. Invalid operators are dropped, the node is still verified.
+func (v *verifier) Verify(ctx context.Context, node *pb.Node) (verified *pb.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	req, err := v.vetting.CheckIn(ctx, node.GetId())
+	if err != nil {
+		v.log.Warn("could not get vetting progress", zap.String("node", node.GetId()), zap.Error(err))
+		req = &pb.StatsReq{}
+	}
+
+	conn, p, err := v.transport.DialNodePeer(ctx, node)
+	if err != nil {
+		v.recordUptime(ctx, node.GetId(), false)
+		return nil, Error.Wrap(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	// the stats request carries the vetting progress and audit status of the
+	// node, so it is only sent to the node with the identity of its id
+	identity, err := provider.PeerIdentityFromPeer(p)
+	if err != nil {
+		return nil, ErrIdentity.Wrap(err)
+	}
+	if identity.ID.String() != node.GetId() {
+		return nil, ErrIdentity.New("node %s has identity %s", node.GetId(), identity.ID)
+	}
+
+	stats, err := pb.NewPieceStoreRoutesClient(conn).Stats(ctx, req)
+	if err != nil {
+		v.recordUptime(ctx, node.GetId(), false)
+		return nil, Error.Wrap(err)
+	}
+	v.recordUptime(ctx, node.GetId(), true)
+	if err := v.vetting.RecordMaintenance(ctx, node.GetId(), stats.GetMaintenance()); err != nil {
+		v.log.Warn("could not record maintenance window", zap.String("node", node.GetId()), zap.Error(err))
+	}
+
+	operator := stats.GetOperator()
+	if operator != nil {
+		if err := overlay.ValidateOperator(operator, identity.CA.PublicKey); err != nil {
+			v.log.Info("dropped invalid node operator", zap.String("node", node.GetId()), zap.Error(err))
+			operator = nil
+		}
+	}
+
+	return &pb.Node{
+		Id:      node.GetId(),
+		Address: node.GetAddress(),
+		Type:    pb.NodeType_STORAGE,
+		Restrictions: &pb.NodeRestrictions{
+			FreeBandwidth: node.GetRestrictions().GetFreeBandwidth(),
+			FreeDisk:      stats.GetAvailableSpace(),
+			IngressFull:   stats.GetIngressFull(),
+			EgressFull:    stats.GetEgressFull(),
+		},
+		Operator: operator,
+	}, nil
+}
+
+// recordUptime counts an uptime check of nodeID toward its vetting
+func (v *verifier) recordUptime(ctx context.Context, nodeID string, up bool) {
+	if err := v.vetting.RecordUptime(ctx, nodeID, up); err != nil {
+		v.log.Warn("could not record uptime check", zap.String("node", nodeID), zap.Error(err))
+	}
+}
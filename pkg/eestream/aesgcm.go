@@ -28,7 +28,9 @@ type aesgcmEncrypter struct {
 // protect against data reordering.
 //
 // When in doubt, generate a new key from crypto/rand and a startingNonce
-// from crypto/rand as often as possible.
+// from crypto/rand as often as possible. Retried writes that encrypt a block
+// again with different data reuse its nonce; use NewAESGCMSIVEncrypter if
+// that can happen.
 func NewAESGCMEncrypter(key *[32]byte, startingNonce *[12]byte,
 	encryptedBlockSize int) (Transformer, error) {
 	block, err := aes.NewCipher((*key)[:])
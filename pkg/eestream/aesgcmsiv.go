@@ -0,0 +1,303 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+)
+
+const (
+	sivNonceSize = 12
+	sivTagSize   = 16
+)
+
+// NewAESGCMSIVEncrypter returns a Transformer that encrypts the data passing
+// through with key using AES-GCM-SIV (RFC 8452). Nonces are derived from
+// startingNonce like with NewAESGCMEncrypter, but reusing a nonce, like when
+// a block is encrypted again with different data after a failed write, only
+// reveals whether the blocks were identical, instead of the key stream and
+// authentication key as it does with AES-GCM.
+func NewAESGCMSIVEncrypter(key *[32]byte, startingNonce *[12]byte,
+	encryptedBlockSize int) (Transformer, error) {
+	aead, err := NewAESGCMSIV((*key)[:])
+	if err != nil {
+		return nil, err
+	}
+	if encryptedBlockSize <= aead.Overhead() {
+		return nil, Error.New("block size too small")
+	}
+	return &aesgcmEncrypter{
+		blockSize:     encryptedBlockSize - aead.Overhead(),
+		key:           *key,
+		startingNonce: *startingNonce,
+		overhead:      aead.Overhead(),
+		aesgcm:        aead,
+	}, nil
+}
+
+// NewAESGCMSIVDecrypter returns a Transformer that decrypts the data passing
+// through with key. See the comments for NewAESGCMSIVEncrypter.
+func NewAESGCMSIVDecrypter(key *[32]byte, startingNonce *[12]byte,
+	encryptedBlockSize int) (Transformer, error) {
+	aead, err := NewAESGCMSIV((*key)[:])
+	if err != nil {
+		return nil, err
+	}
+	if encryptedBlockSize <= aead.Overhead() {
+		return nil, Error.New("block size too small")
+	}
+	return &aesgcmDecrypter{
+		blockSize:     encryptedBlockSize - aead.Overhead(),
+		key:           *key,
+		startingNonce: *startingNonce,
+		overhead:      aead.Overhead(),
+		aesgcm:        aead,
+	}, nil
+}
+
+// aesgcmsiv implements AEAD_AES_128_GCM_SIV and AEAD_AES_256_GCM_SIV
+type aesgcmsiv struct {
+	block  cipher.Block
+	keyLen int
+}
+
+// NewAESGCMSIV returns the AES-GCM-SIV AEAD of a 16 or 32 byte key
+func NewAESGCMSIV(key []byte) (cipher.AEAD, error) {
+	if len(key) != 16 && len(key) != 32 {
+		return nil, Error.New("invalid AES-GCM-SIV key size %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return &aesgcmsiv{block: block, keyLen: len(key)}, nil
+}
+
+func (s *aesgcmsiv) NonceSize() int { return sivNonceSize }
+func (s *aesgcmsiv) Overhead() int  { return sivTagSize }
+
+// deriveKeys derives the authentication and encryption keys of nonce
+func (s *aesgcmsiv) deriveKeys(nonce []byte) (authKey [16]byte, encBlock cipher.Block, err error) {
+	var in, out [16]byte
+	copy(in[4:], nonce)
+	encKey := make([]byte, s.keyLen)
+	for i := 0; i < 2+s.keyLen/8; i++ {
+		binary.LittleEndian.PutUint32(in[:4], uint32(i))
+		s.block.Encrypt(out[:], in[:])
+		if i < 2 {
+			copy(authKey[8*i:], out[:8])
+		} else {
+			copy(encKey[8*(i-2):], out[:8])
+		}
+	}
+	encBlock, err = aes.NewCipher(encKey)
+	return authKey, encBlock, err
+}
+
+// sivTag computes the tag of plaintext and additionalData
+func sivTag(encBlock cipher.Block, authKey *[16]byte, nonce, plaintext, additionalData []byte) (t [16]byte) {
+	p := newPolyval(authKey)
+	p.update(additionalData)
+	p.update(plaintext)
+	var lengths [16]byte
+	binary.LittleEndian.PutUint64(lengths[:8], uint64(len(additionalData))*8)
+	binary.LittleEndian.PutUint64(lengths[8:], uint64(len(plaintext))*8)
+	p.update(lengths[:])
+
+	sum := p.sum()
+	for i := range nonce {
+		sum[i] ^= nonce[i]
+	}
+	sum[15] &= 0x7f
+	encBlock.Encrypt(t[:], sum[:])
+	return t
+}
+
+// sivCTR xors in with the key stream starting at the counter block of tag
+func sivCTR(encBlock cipher.Block, tag *[16]byte, out, in []byte) {
+	counter := *tag
+	counter[15] |= 0x80
+	var stream [16]byte
+	for len(in) > 0 {
+		encBlock.Encrypt(stream[:], counter[:])
+		binary.LittleEndian.PutUint32(counter[:4], binary.LittleEndian.Uint32(counter[:4])+1)
+		n := len(in)
+		if n > 16 {
+			n = 16
+		}
+		for i := 0; i < n; i++ {
+			out[i] = in[i] ^ stream[i]
+		}
+		in, out = in[n:], out[n:]
+	}
+}
+
+func (s *aesgcmsiv) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != sivNonceSize {
+		panic("eestream: incorrect nonce length given to AES-GCM-SIV")
+	}
+	authKey, encBlock, err := s.deriveKeys(nonce)
+	if err != nil {
+		panic(err)
+	}
+	t := sivTag(encBlock, &authKey, nonce, plaintext, additionalData)
+
+	ret, out := sliceForAppend(dst, len(plaintext)+sivTagSize)
+	sivCTR(encBlock, &t, out, plaintext)
+	copy(out[len(plaintext):], t[:])
+	return ret
+}
+
+func (s *aesgcmsiv) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != sivNonceSize {
+		panic("eestream: incorrect nonce length given to AES-GCM-SIV")
+	}
+	if len(ciphertext) < sivTagSize {
+		return nil, Error.New("message authentication failed")
+	}
+	authKey, encBlock, err := s.deriveKeys(nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	var expected [16]byte
+	copy(expected[:], ciphertext[len(ciphertext)-sivTagSize:])
+	ciphertext = ciphertext[:len(ciphertext)-sivTagSize]
+
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	sivCTR(encBlock, &expected, out, ciphertext)
+	t := sivTag(encBlock, &authKey, nonce, out, additionalData)
+	if subtle.ConstantTimeCompare(t[:], expected[:]) != 1 {
+		for i := range out {
+			out[i] = 0
+		}
+		return nil, Error.New("message authentication failed")
+	}
+	return ret, nil
+}
+
+// sliceForAppend extends in by n bytes, and returns the extended slice and
+// the n bytes
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	return head, head[len(in):]
+}
+
+// fieldElement is an element of the POLYVAL field GF(2^128), defined by
+// x^128 + x^127 + x^126 + x^121 + 1, whose lowest coefficients are in the
+// lowest bits of lo
+type fieldElement struct {
+	lo, hi uint64
+}
+
+func loadFieldElement(b []byte) fieldElement {
+	return fieldElement{
+		lo: binary.LittleEndian.Uint64(b[:8]),
+		hi: binary.LittleEndian.Uint64(b[8:16]),
+	}
+}
+
+// mulX returns e multiplied by x
+func (e fieldElement) mulX() fieldElement {
+	carry := e.hi >> 63
+	e.hi = e.hi<<1 | e.lo>>63
+	e.lo <<= 1
+	if carry != 0 {
+		e.hi ^= 0xc200000000000000
+		e.lo ^= 1
+	}
+	return e
+}
+
+// divX returns e divided by x
+func (e fieldElement) divX() fieldElement {
+	if e.lo&1 != 0 {
+		e.hi ^= 0xc200000000000000
+		e.lo ^= 1
+		e.lo = e.lo>>1 | e.hi<<63
+		e.hi = e.hi>>1 | 1<<63
+		return e
+	}
+	e.lo = e.lo>>1 | e.hi<<63
+	e.hi >>= 1
+	return e
+}
+
+// polyval computes POLYVAL, using a table of the multiples of the key by
+// all 4 bit polynomials
+type polyval struct {
+	table [16]fieldElement
+	s     fieldElement
+}
+
+func newPolyval(key *[16]byte) *polyval {
+	// POLYVAL multiplies by the key and x^-128
+	h := loadFieldElement(key[:])
+	for i := 0; i < 128; i++ {
+		h = h.divX()
+	}
+
+	p := &polyval{}
+	for i := 1; i < 16; i++ {
+		var m fieldElement
+		x := h
+		for bit := 0; bit < 4; bit++ {
+			if i&(1<<uint(bit)) != 0 {
+				m.lo ^= x.lo
+				m.hi ^= x.hi
+			}
+			x = x.mulX()
+		}
+		p.table[i] = m
+	}
+	return p
+}
+
+// update adds data, padded with zeros to 16 bytes, to the sum
+func (p *polyval) update(data []byte) {
+	var block [16]byte
+	for len(data) > 0 {
+		n := copy(block[:], data)
+		for i := n; i < 16; i++ {
+			block[i] = 0
+		}
+		data = data[n:]
+
+		x := loadFieldElement(block[:])
+		p.s.lo ^= x.lo
+		p.s.hi ^= x.hi
+		p.s = p.mul(p.s)
+	}
+}
+
+// mul multiplies e by the key and x^-128
+func (p *polyval) mul(e fieldElement) fieldElement {
+	var z fieldElement
+	for i := 124; i >= 0; i -= 4 {
+		z = z.mulX().mulX().mulX().mulX()
+		var nibble uint64
+		if i >= 64 {
+			nibble = e.hi >> uint(i-64) & 0xf
+		} else {
+			nibble = e.lo >> uint(i) & 0xf
+		}
+		z.lo ^= p.table[nibble].lo
+		z.hi ^= p.table[nibble].hi
+	}
+	return z
+}
+
+func (p *polyval) sum() (out [16]byte) {
+	binary.LittleEndian.PutUint64(out[:8], p.s.lo)
+	binary.LittleEndian.PutUint64(out[8:], p.s.hi)
+	return out
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io/ioutil"
+	"testing"
+)
+
+func TestAESGCMSIVVectors(t *testing.T) {
+	// test vectors of RFC 8452
+	for _, v := range []struct {
+		key, nonce, plaintext, result string
+	}{
+		{"01000000000000000000000000000000", "030000000000000000000000", "", "dc20e2d83f25705bb49e439eca56de25"},
+		{"0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "", "07f5f4169bbf55a8400cd47ea6fd400f"},
+		{"0100000000000000000000000000000000000000000000000000000000000000", "030000000000000000000000", "0100000000000000", "c2ef328e5c71c83b843122130f7364b761e0b97427e3df28"},
+	} {
+		key, _ := hex.DecodeString(v.key)
+		nonce, _ := hex.DecodeString(v.nonce)
+		plaintext, _ := hex.DecodeString(v.plaintext)
+
+		aead, err := NewAESGCMSIV(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sealed := aead.Seal(nil, nonce, plaintext, nil)
+		if hex.EncodeToString(sealed) != v.result {
+			t.Fatalf("expected %s got %x", v.result, sealed)
+		}
+		opened, err := aead.Open(nil, nonce, sealed, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(opened, plaintext) {
+			t.Fatalf("expected %x got %x", plaintext, opened)
+		}
+
+		sealed[0] ^= 1
+		if _, err := aead.Open(nil, nonce, sealed, nil); err == nil {
+			t.Fatal("expected tampered ciphertext to fail authentication")
+		}
+	}
+}
+
+func TestAESGCMSIV(t *testing.T) {
+	var key [32]byte
+	copy(key[:], randData(32))
+	var firstNonce [12]byte
+	copy(firstNonce[:], randData(12))
+	encrypter, err := NewAESGCMSIVEncrypter(&key, &firstNonce, 4*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := randData(encrypter.InBlockSize() * 10)
+	encrypted := TransformReader(
+		ioutil.NopCloser(bytes.NewReader(data)), encrypter, 0)
+	decrypter, err := NewAESGCMSIVDecrypter(&key, &firstNonce, 4*1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decrypted := TransformReader(encrypted, decrypter, 0)
+	data2, err := ioutil.ReadAll(decrypted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, data2) {
+		t.Fatalf("encryption/decryption failed")
+	}
+}
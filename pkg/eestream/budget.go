@@ -0,0 +1,175 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryBudget caps the total memory of the read buffers of all concurrent
+// encoders and decoders, in addition to the max buffer memory of each. Streams
+// whose buffers don't fit in the budget wait until enough memory is released.
+// Waiting streams are served in order, so large streams aren't starved by a
+// succession of small ones.
+type MemoryBudget struct {
+	mu      sync.Mutex
+	limit   int
+	used    int
+	waiters []*budgetWaiter
+}
+
+type budgetWaiter struct {
+	n     int
+	ready chan struct{}
+}
+
+// NewMemoryBudget creates a budget of limit bytes
+func NewMemoryBudget(limit int) *MemoryBudget {
+	return &MemoryBudget{limit: limit}
+}
+
+// Acquire blocks until n bytes of the budget are available and reserves them,
+// or until ctx is canceled. The return value is the number of bytes reserved,
+// which is at most the limit of the budget, so a stream larger than the budget
+// can still proceed on its own.
+func (b *MemoryBudget) Acquire(ctx context.Context, n int) (int, error) {
+	if n > b.limit {
+		n = b.limit
+	}
+
+	b.mu.Lock()
+	if len(b.waiters) == 0 && b.used+n <= b.limit {
+		b.used += n
+		b.mu.Unlock()
+		return n, nil
+	}
+	w := &budgetWaiter{n: n, ready: make(chan struct{})}
+	b.waiters = append(b.waiters, w)
+	b.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return n, nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		select {
+		case <-w.ready:
+			// the memory was reserved while canceling
+			b.used -= n
+			b.notify()
+		default:
+			b.remove(w)
+		}
+		return 0, ctx.Err()
+	}
+}
+
+// Release returns n reserved bytes to the budget
+func (b *MemoryBudget) Release(n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.used -= n
+	b.notify()
+}
+
+// notify reserves memory for the waiters at the front of the queue that fit
+// in the budget. b.mu must be held.
+func (b *MemoryBudget) notify() {
+	for len(b.waiters) > 0 && b.used+b.waiters[0].n <= b.limit {
+		w := b.waiters[0]
+		b.waiters = b.waiters[1:]
+		b.used += w.n
+		close(w.ready)
+	}
+}
+
+// remove removes w from the queue. b.mu must be held.
+func (b *MemoryBudget) remove(w *budgetWaiter) {
+	for i, waiter := range b.waiters {
+		if waiter == w {
+			b.waiters = append(b.waiters[:i], b.waiters[i+1:]...)
+			break
+		}
+	}
+	// the removed waiter may have blocked the ones behind it
+	b.notify()
+}
+
+var (
+	globalBudgetMu sync.Mutex
+	globalBudget   *MemoryBudget
+)
+
+// UseMemoryBudget sets the budget that all encoders and decoders of the
+// process reserve their read buffers from. A nil budget, the default, doesn't
+// limit the total memory.
+func UseMemoryBudget(b *MemoryBudget) {
+	globalBudgetMu.Lock()
+	defer globalBudgetMu.Unlock()
+	globalBudget = b
+}
+
+// reservation is memory reserved from the global budget, which can be
+// returned in parts as the buffers it holds are freed
+type reservation struct {
+	mu       sync.Mutex
+	budget   *MemoryBudget
+	reserved int
+}
+
+// reserve reserves n bytes from the global budget if there is one
+func reserve(ctx context.Context, n int) (*reservation, error) {
+	globalBudgetMu.Lock()
+	b := globalBudget
+	globalBudgetMu.Unlock()
+
+	if b == nil {
+		return &reservation{}, nil
+	}
+	reserved, err := b.Acquire(ctx, n)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &reservation{budget: b, reserved: reserved}, nil
+}
+
+// release returns n bytes of the reservation to the budget, or what is left
+// of it if that's less
+func (r *reservation) release(n int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if n > r.reserved {
+		n = r.reserved
+	}
+	r.shrink(r.reserved - n)
+}
+
+// releaseAllBut returns the reservation to the budget but for keep bytes
+func (r *reservation) releaseAllBut(keep int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.shrink(keep)
+}
+
+// releaseAll returns what is left of the reservation to the budget
+func (r *reservation) releaseAll() {
+	r.releaseAllBut(0)
+}
+
+// shrink returns the reservation to the budget but for keep bytes. r.mu must
+// be held.
+func (r *reservation) shrink(keep int) {
+	if keep < 0 {
+		keep = 0
+	}
+	if keep >= r.reserved {
+		return
+	}
+	if r.budget != nil {
+		r.budget.Release(r.reserved - keep)
+	}
+	r.reserved = keep
+}
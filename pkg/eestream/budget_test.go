@@ -0,0 +1,208 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vivint/infectious"
+)
+
+func acquired(t *testing.T, results <-chan int, expected int) {
+	select {
+	case n := <-results:
+		assert.Equal(t, expected, n)
+	case <-time.After(time.Second):
+		t.Fatalf("%d bytes were not acquired", expected)
+	}
+}
+
+func waiting(t *testing.T, results <-chan int) {
+	select {
+	case n := <-results:
+		t.Fatalf("%d bytes were acquired over the limit", n)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestMemoryBudget(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryBudget(100)
+
+	// requests above the limit are clamped to it
+	n, err := b.Acquire(ctx, 150)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, n)
+	b.Release(n)
+
+	n, err = b.Acquire(ctx, 60)
+	assert.NoError(t, err)
+	assert.Equal(t, 60, n)
+
+	acquire := func(n int) <-chan int {
+		results := make(chan int, 1)
+		go func() {
+			n, err := b.Acquire(ctx, n)
+			assert.NoError(t, err)
+			results <- n
+		}()
+		// wait for the request to be queued
+		time.Sleep(10 * time.Millisecond)
+		return results
+	}
+
+	// the small request fits, but has to wait behind the large one
+	large := acquire(50)
+	small := acquire(10)
+	waiting(t, small)
+
+	b.Release(60)
+	acquired(t, large, 50)
+	acquired(t, small, 10)
+
+	// canceled requests don't block the queue
+	canceled, cancel := context.WithCancel(ctx)
+	errs := make(chan error)
+	go func() {
+		_, err := b.Acquire(canceled, 80)
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	queued := acquire(30)
+	waiting(t, queued)
+
+	cancel()
+	assert.Error(t, <-errs)
+	acquired(t, queued, 30)
+
+	b.Release(50 + 10 + 30)
+	n, err = b.Acquire(ctx, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, n)
+}
+
+func TestRSMemoryBudget(t *testing.T) {
+	ctx := context.Background()
+	data := randData(32 * 1024)
+	fc, err := infectious.NewFEC(2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es := NewRSScheme(fc, 8*1024)
+	rs, err := NewRedundancyStrategy(es, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	budget := NewMemoryBudget(1024 * 1024)
+	UseMemoryBudget(budget)
+	defer UseMemoryBudget(nil)
+
+	readers, err := EncodeReader(ctx, bytes.NewReader(data), rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readerMap := make(map[int]io.ReadCloser, len(readers))
+	for i, reader := range readers {
+		readerMap[i] = ioutil.NopCloser(reader)
+	}
+	decoder := DecodeReaders(ctx, readerMap, rs, 32*1024, 0)
+	data2, err := ioutil.ReadAll(decoder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, data, data2)
+	assert.NoError(t, decoder.Close())
+
+	// all buffers are returned to the budget
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	n, err := budget.Acquire(ctx, 1024*1024)
+	assert.NoError(t, err)
+	assert.Equal(t, 1024*1024, n)
+}
+
+func TestEncodeMemoryBudgetSlowReaders(t *testing.T) {
+	ctx := context.Background()
+	data := randData(32 * 1024)
+	fc, err := infectious.NewFEC(2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := NewRedundancyStrategy(NewRSScheme(fc, 1024), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	budget := NewMemoryBudget(1024 * 1024)
+	UseMemoryBudget(budget)
+	defer UseMemoryBudget(nil)
+
+	// the buffers fit all the blocks, so the input is encoded long before
+	// the slow readers are done
+	mbm := len(data) / rs.DecodedBlockSize() * rs.TotalCount() * rs.EncodedBlockSize()
+	readers, err := EncodeReader(ctx, bytes.NewReader(data), rs, mbm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	er := readers[0].(*encodedPiece).er
+
+	// sample the bytes of the blocks not read yet against the budget used
+	// until the readers are done
+	done := make(chan struct{})
+	sampled := make(chan int, 1)
+	go func() {
+		peak := 0
+		defer func() { sampled <- peak }()
+		for {
+			er.mux.Lock()
+			inflight := er.buffered * rs.EncodedBlockSize()
+			budget.mu.Lock()
+			used := budget.used
+			budget.mu.Unlock()
+			er.mux.Unlock()
+
+			if inflight > peak {
+				peak = inflight
+			}
+			if used < inflight {
+				t.Errorf("%d bytes in flight but only %d used from the budget", inflight, used)
+				return
+			}
+			select {
+			case <-done:
+				return
+			case <-time.After(100 * time.Microsecond):
+			}
+		}
+	}()
+
+	slowReaders := make([]io.Reader, len(readers))
+	for i, reader := range readers {
+		slowReaders[i] = SlowReader(reader, time.Millisecond)
+	}
+	pieces, err := readAll(slowReaders)
+	close(done)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, pieces, rs.TotalCount())
+
+	peak := <-sampled
+	assert.True(t, peak > 0)
+	assert.True(t, peak <= mbm+rs.DecodedBlockSize(), "peak of %d bytes in flight", peak)
+
+	// all buffers are returned to the budget once the readers are done
+	ctx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	n, err := budget.Acquire(ctx, 1024*1024)
+	assert.NoError(t, err)
+	assert.Equal(t, 1024*1024, n)
+}
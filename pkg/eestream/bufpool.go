@@ -0,0 +1,34 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"sync"
+)
+
+// bufferPools maps buffer sizes to the pools of the buffers of that size, so
+// the buffers of finished streams are reused by new ones instead of being
+// left to the garbage collector
+var bufferPools sync.Map // map[int]*sync.Pool
+
+// getBuffer returns a buffer of the given size. Its content is undefined.
+func getBuffer(size int) []byte {
+	if pool, ok := bufferPools.Load(size); ok {
+		if buf, ok := pool.(*sync.Pool).Get().(*[]byte); ok {
+			return *buf
+		}
+	}
+	return make([]byte, size)
+}
+
+// putBuffer returns a buffer from getBuffer to its pool. The buffer must not
+// be used anymore.
+func putBuffer(buf []byte) {
+	buf = buf[:cap(buf)]
+	pool, ok := bufferPools.Load(len(buf))
+	if !ok {
+		pool, _ = bufferPools.LoadOrStore(len(buf), &sync.Pool{})
+	}
+	pool.(*sync.Pool).Put(&buf)
+}
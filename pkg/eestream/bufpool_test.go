@@ -0,0 +1,164 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vivint/infectious"
+)
+
+func TestBufferPool(t *testing.T) {
+	buf := getBuffer(100)
+	assert.Len(t, buf, 100)
+	putBuffer(buf[:10])
+	// buffers are pooled by their capacity
+	assert.Len(t, getBuffer(100), 100)
+	assert.Len(t, getBuffer(200), 200)
+}
+
+// encodePieces returns the pieces of data encoded with rs
+func encodePieces(ctx context.Context, t testing.TB, data []byte, rs RedundancyStrategy) [][]byte {
+	readers, err := EncodeReader(ctx, bytes.NewReader(data), rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pieces, err := readAll(readers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pieces
+}
+
+// pieceReaders returns readers of the pieces, delayed by delay on each read
+func pieceReaders(pieces [][]byte, delay time.Duration) map[int]io.ReadCloser {
+	readers := make(map[int]io.ReadCloser, len(pieces))
+	for i, piece := range pieces {
+		readers[i] = ioutil.NopCloser(SlowReader(bytes.NewReader(piece), delay))
+	}
+	return readers
+}
+
+func TestStripeReaderCloseWhileReading(t *testing.T) {
+	fc, err := infectious.NewFEC(2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es := NewRSScheme(fc, 1024)
+
+	// the pieces never deliver a share, so the stripe is awaited until the
+	// reader is closed
+	readers := map[int]io.ReadCloser{}
+	var writers []*io.PipeWriter
+	for i := 0; i < es.TotalCount(); i++ {
+		pr, pw := io.Pipe()
+		readers[i] = pr
+		writers = append(writers, pw)
+	}
+	defer func() {
+		for _, pw := range writers {
+			_ = pw.Close()
+		}
+	}()
+
+	r := NewStripeReader(readers, es, 0)
+	done := make(chan error, 1)
+	go func() {
+		_, err := r.ReadStripe(0, nil)
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	assert.NoError(t, r.Close())
+
+	select {
+	case err := <-done:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("ReadStripe not interrupted by Close")
+	}
+	// the buffers returned to the pool aren't read anymore
+	_, err = r.ReadStripe(0, nil)
+	assert.Error(t, err)
+}
+
+// TestDecodeCloseWhileReading closes decoders while they are read, and
+// decodes other streams at the same time, which reuse the buffers of the
+// closed decoders. Run with -race to detect a closed decoder still using its
+// buffers.
+func TestDecodeCloseWhileReading(t *testing.T) {
+	ctx := context.Background()
+	data := randData(64 * 1024)
+	fc, err := infectious.NewFEC(2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := NewRedundancyStrategy(NewRSScheme(fc, 1024), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pieces := encodePieces(ctx, t, data, rs)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			decoder := DecodeReaders(ctx, pieceReaders(pieces, time.Millisecond), rs, int64(len(data)), 0)
+			read := make(chan []byte, 1)
+			go func() {
+				// the read fails once the decoder is closed
+				decoded, _ := ioutil.ReadAll(decoder)
+				read <- decoded
+			}()
+			time.Sleep(time.Duration(rand.Intn(10)) * time.Millisecond)
+			assert.NoError(t, decoder.Close())
+			decoded := <-read
+			assert.Equal(t, data[:len(decoded)], decoded)
+		}()
+		go func() {
+			defer wg.Done()
+			decoder := DecodeReaders(ctx, pieceReaders(pieces, 0), rs, int64(len(data)), 0)
+			defer func() { assert.NoError(t, decoder.Close()) }()
+			decoded, err := ioutil.ReadAll(decoder)
+			assert.NoError(t, err)
+			assert.Equal(t, data, decoded)
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkDecodeReaders(b *testing.B) {
+	ctx := context.Background()
+	data := randData(20 * 1024 * 50)
+	fc, err := infectious.NewFEC(20, 40)
+	if err != nil {
+		b.Fatal(err)
+	}
+	rs, err := NewRedundancyStrategy(NewRSScheme(fc, 1024), 0, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+	pieces := encodePieces(ctx, b, data, rs)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		decoder := DecodeReaders(ctx, pieceReaders(pieces, 0), rs, int64(len(data)), 0)
+		if _, err := io.Copy(ioutil.Discard, decoder); err != nil {
+			b.Fatal(err)
+		}
+		if err := decoder.Close(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
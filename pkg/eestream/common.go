@@ -5,7 +5,12 @@ package eestream
 
 import (
 	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 )
 
-// Error is the default eestream errs class
-var Error = errs.Class("eestream error")
+var (
+	// Error is the default eestream errs class
+	Error = errs.Class("eestream error")
+
+	mon = monkit.Package()
+)
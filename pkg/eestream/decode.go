@@ -6,7 +6,6 @@ package eestream
 import (
 	"context"
 	"io"
-	"io/ioutil"
 	"sync"
 
 	"storj.io/storj/internal/pkg/readcloser"
@@ -20,12 +19,15 @@ type decodedReader struct {
 	readers         map[int]io.ReadCloser
 	scheme          ErasureScheme
 	stripeReader    *StripeReader
-	outbuf          []byte
+	stripe          []byte // buffer of the decoded stripe
+	outbuf          []byte // undelivered part of the decoded stripe
 	err             error
 	currentStripe   int64
 	expectedStripes int64
+	skip            int // bytes of the current stripe before the offset
 	close           sync.Once
 	closeErr        error
+	release         func()
 }
 
 // DecodeReaders takes a map of readers and an ErasureScheme returning a
@@ -34,9 +36,31 @@ type decodedReader struct {
 // rs is a map of erasure piece numbers to erasure piece streams.
 // expectedSize is the number of bytes expected to be returned by the Reader.
 // mbm is the maximum memory (in bytes) to be allocated for read buffers. If
-// set to 0, the minimum possible memory will be used.
+// set to 0, the minimum possible memory will be used. The read buffers are
+// reserved from the memory budget set with UseMemoryBudget, if any, and
+// returned when the Reader is closed.
 func DecodeReaders(ctx context.Context, rs map[int]io.ReadCloser,
 	es ErasureScheme, expectedSize int64, mbm int) io.ReadCloser {
+	return DecodeReadersAt(ctx, rs, es, 0, expectedSize, mbm)
+}
+
+// DecodeReadersAt is like DecodeReaders, but the returned Reader starts at
+// offset into the expectedSize bytes of decoded data. The stripes before the
+// offset are skipped without being decoded, and the decoded bytes of the
+// stripe containing the offset that precede it are dropped without being
+// copied.
+func DecodeReadersAt(ctx context.Context, rs map[int]io.ReadCloser,
+	es ErasureScheme, offset, expectedSize int64, mbm int) io.ReadCloser {
+	return decodeReaders(ctx, rs, es, 0, offset, expectedSize, mbm)
+}
+
+// decodeReaders is like DecodeReadersAt, for readers whose first stripe is
+// the stripe number first of the erasure coded data
+func decodeReaders(ctx context.Context, rs map[int]io.ReadCloser,
+	es ErasureScheme, first, offset, expectedSize int64, mbm int) io.ReadCloser {
+	if offset < 0 || offset > expectedSize {
+		return readcloser.FatalReadCloser(Error.New("invalid offset %d", offset))
+	}
 	if expectedSize < 0 {
 		return readcloser.FatalReadCloser(Error.New("negative expected size"))
 	}
@@ -48,12 +72,21 @@ func DecodeReaders(ctx context.Context, rs map[int]io.ReadCloser,
 	if err := checkMBM(mbm); err != nil {
 		return readcloser.FatalReadCloser(err)
 	}
+	reservation, err := reserve(ctx, stripeReaderMemory(es, mbm)+es.DecodedBlockSize())
+	if err != nil {
+		return readcloser.FatalReadCloser(err)
+	}
+	stripeReader := NewStripeReader(rs, es, mbm)
+	stripeReader.first = first
 	dr := &decodedReader{
 		readers:         rs,
 		scheme:          es,
-		stripeReader:    NewStripeReader(rs, es, mbm),
-		outbuf:          make([]byte, 0, es.DecodedBlockSize()),
+		stripeReader:    stripeReader,
+		stripe:          make([]byte, 0, es.DecodedBlockSize()),
+		currentStripe:   offset / int64(es.DecodedBlockSize()),
 		expectedStripes: expectedSize / int64(es.DecodedBlockSize()),
+		skip:            int(offset % int64(es.DecodedBlockSize())),
+		release:         reservation.releaseAll,
 	}
 	dr.ctx, dr.cancel = context.WithCancel(ctx)
 	// Kick off a goroutine to watch for context cancelation.
@@ -77,19 +110,19 @@ func (dr *decodedReader) Read(p []byte) (n int, err error) {
 			return 0, dr.err
 		}
 		// read the input buffers of the next stripe - may also decode it
-		dr.outbuf, dr.err = dr.stripeReader.ReadStripe(dr.currentStripe, dr.outbuf)
+		dr.outbuf, dr.err = dr.stripeReader.ReadStripe(dr.currentStripe, dr.stripe[:0])
 		if dr.err != nil {
 			return 0, dr.err
 		}
 		dr.currentStripe++
+		// the first stripe may start before the offset
+		dr.outbuf, dr.skip = dr.outbuf[dr.skip:], 0
 	}
 
 	// copy what data we have to the output
 	n = copy(p, dr.outbuf)
-	// slide the remaining bytes to the beginning
-	copy(dr.outbuf, dr.outbuf[n:])
-	// shrink the remaining buffer
-	dr.outbuf = dr.outbuf[:len(dr.outbuf)-n]
+	// advance past the copied bytes
+	dr.outbuf = dr.outbuf[n:]
 	return n, nil
 }
 
@@ -106,6 +139,8 @@ func (dr *decodedReader) Close() error {
 		// close the stripe reader
 		errs[len(dr.readers)] = dr.stripeReader.Close()
 		dr.closeErr = utils.CombineErrors(errs...)
+		// return the read buffers to the memory budget
+		dr.release()
 	})
 	return dr.closeErr
 }
@@ -193,14 +228,10 @@ func (dr *decodedRanger) Range(ctx context.Context, offset, length int64) (io.Re
 			readers[res.i] = res.r
 		}
 	}
-	// decode from all those ranges
-	r := DecodeReaders(ctx, readers, dr.es, blockCount*int64(dr.es.DecodedBlockSize()), dr.mbm)
-	// offset might start a few bytes in, potentially discard the initial bytes
-	_, err := io.CopyN(ioutil.Discard, r,
-		offset-firstBlock*int64(dr.es.DecodedBlockSize()))
-	if err != nil {
-		return nil, Error.Wrap(err)
-	}
+	// decode from all those ranges. offset might start a few bytes into the
+	// first block, which the decoder drops.
+	r := decodeReaders(ctx, readers, dr.es, firstBlock, offset-firstBlock*int64(dr.es.DecodedBlockSize()),
+		blockCount*int64(dr.es.DecodedBlockSize()), dr.mbm)
 	// length might not have included all of the blocks, limit what we return
 	return readcloser.LimitReadCloser(r, length), nil
 }
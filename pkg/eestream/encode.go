@@ -24,6 +24,17 @@ type ErasureScheme interface {
 	// 'in', and append the combined data to 'out', returning it.
 	Decode(out []byte, in map[int][]byte) ([]byte, error)
 
+	// EncodeSingleStripe erasure codes the single stripe 'in', of
+	// DecodedBlockSize bytes, and returns its TotalCount shares, indexed by
+	// share number.
+	EncodeSingleStripe(in []byte) ([][]byte, error)
+
+	// RebuildShare rebuilds share 'num' of a stripe from a mapping of at least
+	// RequiredCount of its shares, 'in', and appends it to 'out', returning
+	// it. The shares of 'in' are error corrected first if there are more than
+	// RequiredCount, and are left unmodified.
+	RebuildShare(out []byte, in map[int][]byte, num int) ([]byte, error)
+
 	// EncodedBlockSize is the size the erasure coded pieces should be that come
 	// from Encode and are passed to Decode.
 	EncodedBlockSize() int
@@ -97,15 +108,20 @@ func (rs *RedundancyStrategy) OptimumThreshold() int {
 }
 
 type encodedReader struct {
-	ctx    context.Context
-	cancel context.CancelFunc
-	r      io.Reader
-	rs     RedundancyStrategy
-	inbuf  []byte
-	eps    map[int](*encodedPiece)
-	mux    sync.Mutex
-	start  time.Time
-	done   int // number of readers done
+	ctx         context.Context
+	cancel      context.CancelFunc
+	r           io.Reader
+	rs          RedundancyStrategy
+	first       int64 // number of the first encoded stripe
+	inbuf       []byte
+	eps         map[int](*encodedPiece)
+	mux         sync.Mutex
+	start       time.Time
+	done        int // number of readers done
+	reservation *reservation
+	buffered    int           // number of encoded blocks not read yet
+	finished    bool          // whether the whole input is encoded
+	encoded     chan struct{} // closed when the whole input is encoded
 }
 
 type block struct {
@@ -119,22 +135,42 @@ type block struct {
 // Readers.
 //
 // mbm is the maximum memory (in bytes) to be allocated for read buffers. If
-// set to 0, the minimum possible memory will be used.
+// set to 0, the minimum possible memory will be used. The read buffers are
+// reserved from the memory budget set with UseMemoryBudget, if any. Once the
+// whole input is encoded, the buffer of each block is returned as it is read,
+// and all of them are returned when ctx is canceled.
 //
 // When the minimum threshold is reached a timer will be started with another
 // 1.5x the amount of time that took so far. The Readers will be aborted as
 // soon as the timer expires or the optimum threshold is reached.
 func EncodeReader(ctx context.Context, r io.Reader, rs RedundancyStrategy,
 	mbm int) ([]io.Reader, error) {
+	return encodeReader(ctx, r, rs, 0, mbm)
+}
+
+// encodeReader is like EncodeReader, numbering the stripes of r from first
+func encodeReader(ctx context.Context, r io.Reader, rs RedundancyStrategy,
+	first int64, mbm int) ([]io.Reader, error) {
 	if err := checkMBM(mbm); err != nil {
 		return nil, err
 	}
+	chanSize := mbm / (rs.TotalCount() * rs.EncodedBlockSize())
+	if chanSize < 1 {
+		chanSize = 1
+	}
+	reservation, err := reserve(ctx, chanSize*rs.TotalCount()*rs.EncodedBlockSize()+rs.DecodedBlockSize())
+	if err != nil {
+		return nil, err
+	}
 	er := &encodedReader{
-		r:     r,
-		rs:    rs,
-		inbuf: make([]byte, rs.DecodedBlockSize()),
-		eps:   make(map[int](*encodedPiece), rs.TotalCount()),
-		start: time.Now(),
+		r:           r,
+		rs:          rs,
+		first:       first,
+		inbuf:       make([]byte, rs.DecodedBlockSize()),
+		eps:         make(map[int](*encodedPiece), rs.TotalCount()),
+		start:       time.Now(),
+		reservation: reservation,
+		encoded:     make(chan struct{}),
 	}
 	er.ctx, er.cancel = context.WithCancel(ctx)
 	readers := make([]io.Reader, 0, rs.TotalCount())
@@ -145,18 +181,28 @@ func EncodeReader(ctx context.Context, r io.Reader, rs RedundancyStrategy,
 		er.eps[i].ctx, er.eps[i].cancel = context.WithCancel(er.ctx)
 		readers = append(readers, er.eps[i])
 	}
-	chanSize := mbm / (rs.TotalCount() * rs.EncodedBlockSize())
-	if chanSize < 1 {
-		chanSize = 1
-	}
 	for i := 0; i < rs.TotalCount(); i++ {
 		er.eps[i].ch = make(chan block, chanSize)
 	}
 	go er.fillBuffer()
+	// once the whole input is encoded, return the read buffers to the memory
+	// budget but for the blocks still to be read, and return these too if the
+	// readers are abandoned
+	go func() {
+		select {
+		case <-er.ctx.Done():
+		case <-er.encoded:
+			er.encodeDone()
+			<-er.ctx.Done()
+		}
+		er.reservation.releaseAll()
+	}()
 	return readers, nil
 }
 
 func (er *encodedReader) fillBuffer() {
+	// no more blocks are buffered once the input is consumed
+	defer close(er.encoded)
 	// these channels will synchronize the erasure encoder output with the
 	// goroutines for adding the output to the reader buffers
 	copiers := make(map[int]chan block, er.rs.TotalCount())
@@ -177,13 +223,17 @@ func (er *encodedReader) fillBuffer() {
 			}
 			return
 		}
-		err = er.rs.Encode(er.inbuf, func(num int, data []byte) {
+		err = er.encode(blockNum, func(num int, data []byte) {
 			b := block{
 				i:    num,
 				num:  blockNum,
-				data: make([]byte, len(data)),
+				data: getBuffer(len(data)),
 			}
-			// data is reused by infecious, so add a copy to the channel
+			er.mux.Lock()
+			er.buffered++
+			er.mux.Unlock()
+			// data is reused by infecious, so add a copy to the channel. the
+			// reader returns the copy to the pool once it is read.
 			copy(b.data, data)
 			// send the block to the goroutine for adding it to the reader buffer
 			copiers[num] <- b
@@ -197,6 +247,14 @@ func (er *encodedReader) fillBuffer() {
 	}
 }
 
+// encode erasure codes the blockNum-th block read
+func (er *encodedReader) encode(blockNum int64, out func(num int, data []byte)) error {
+	if ss, ok := stripeScheme(er.rs); ok {
+		return ss.EncodeStripe(er.first+blockNum, er.inbuf, out)
+	}
+	return er.rs.Encode(er.inbuf, out)
+}
+
 // copyData waits for data block from the erasure encoder and copies it to the
 // targeted reader buffer
 func (er *encodedReader) copyData(num int, copier <-chan block) {
@@ -215,6 +273,8 @@ func (er *encodedReader) copyData(num int, copier <-chan block) {
 func (er *encodedReader) addToReader(b block) {
 	if er.eps[b.i].ch == nil {
 		// this channel is already closed for slowness - skip it
+		putBuffer(b.data)
+		er.blockRead()
 		return
 	}
 	for {
@@ -257,6 +317,26 @@ func (er *encodedReader) checkSlowChannel(num int) (closed bool) {
 	return closed
 }
 
+// encodeDone returns the read buffers to the memory budget once the whole
+// input is encoded, but for those of the blocks not read yet
+func (er *encodedReader) encodeDone() {
+	er.mux.Lock()
+	defer er.mux.Unlock()
+	er.finished = true
+	er.reservation.releaseAllBut(er.buffered * er.rs.EncodedBlockSize())
+}
+
+// blockRead is called every time an encoded block is read or dropped, and
+// returns its buffer to the memory budget if the whole input is encoded
+func (er *encodedReader) blockRead() {
+	er.mux.Lock()
+	defer er.mux.Unlock()
+	er.buffered--
+	if er.finished {
+		er.reservation.release(er.rs.EncodedBlockSize())
+	}
+}
+
 // Called every time an encoded piece is done reading everything
 func (er *encodedReader) readerDone() {
 	er.mux.Lock()
@@ -278,7 +358,8 @@ type encodedPiece struct {
 	cancel context.CancelFunc
 	er     *encodedReader
 	ch     chan block
-	outbuf []byte
+	block  []byte // buffer of the current block
+	outbuf []byte // unread part of the current block
 	err    error
 }
 
@@ -301,7 +382,7 @@ func (ep *encodedPiece) Read(p []byte) (n int, err error) {
 				}
 				return 0, ep.err
 			}
-			ep.outbuf = b.data
+			ep.block, ep.outbuf = b.data, b.data
 		case <-ep.ctx.Done():
 			// context was canceled due to:
 			//  - slowness
@@ -313,10 +394,14 @@ func (ep *encodedPiece) Read(p []byte) (n int, err error) {
 
 	// we have some buffer remaining for this piece. write it to the output
 	n = copy(p, ep.outbuf)
-	// slide the unused (if any) bytes to the beginning of the buffer
-	copy(ep.outbuf, ep.outbuf[n:])
-	// and shrink the buffer
-	ep.outbuf = ep.outbuf[:len(ep.outbuf)-n]
+	// advance past the copied bytes
+	ep.outbuf = ep.outbuf[n:]
+	if len(ep.outbuf) == 0 {
+		// the whole block is read, so its buffer can be reused
+		putBuffer(ep.block)
+		ep.block = nil
+		ep.er.blockRead()
+	}
 	return n, nil
 }
 
@@ -367,7 +452,7 @@ func (er *EncodedRanger) Range(ctx context.Context, offset, length int64) ([]io.
 	if err != nil {
 		return nil, err
 	}
-	readers, err := EncodeReader(ctx, r, er.rs, er.mbm)
+	readers, err := encodeReader(ctx, r, er.rs, firstBlock, er.mbm)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,123 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+
+	"github.com/zeebo/errs"
+)
+
+// MACSize is the size of the MAC appended to every erasure share by the
+// schemes returned by NewMACScheme
+const MACSize = 16
+
+// ErrShareMAC is returned for erasure shares that don't match their MAC
+var ErrShareMAC = errs.Class("share MAC mismatch")
+
+// ShareMAC authenticates the erasure shares of a segment. The MAC of a share
+// is bound to its stripe and share number, so the shares of a segment can't
+// be replayed from another stripe or swapped between pieces, and its key is
+// bound to the segment, so shares can't be taken from another segment.
+type ShareMAC struct {
+	key []byte
+}
+
+// NewShareMAC returns the MAC of the erasure shares of the segment identified
+// by segmentKey, e.g. the root piece ID of the segment. The storage nodes
+// must not know segmentKey.
+func NewShareMAC(segmentKey []byte) *ShareMAC {
+	mac := hmac.New(sha256.New, segmentKey)
+	_, _ = mac.Write([]byte("share mac"))
+	return &ShareMAC{key: mac.Sum(nil)}
+}
+
+// Sum appends the MAC of share number num of stripe to out and returns it
+func (m *ShareMAC) Sum(out []byte, stripe int64, num int, share []byte) []byte {
+	var position [12]byte
+	binary.BigEndian.PutUint64(position[:8], uint64(stripe))
+	binary.BigEndian.PutUint32(position[8:], uint32(num))
+
+	mac := hmac.New(sha256.New, m.key)
+	_, _ = mac.Write(position[:])
+	_, _ = mac.Write(share)
+	return append(out, mac.Sum(nil)[:MACSize]...)
+}
+
+// Verify checks that sum is the MAC of share number num of stripe
+func (m *ShareMAC) Verify(stripe int64, num int, share, sum []byte) bool {
+	var expected [sha256.Size]byte
+	return hmac.Equal(m.Sum(expected[:0], stripe, num, share), sum)
+}
+
+// StripeScheme is an ErasureScheme whose erasure shares depend on the number
+// of their stripe in the erasure coded data.
+type StripeScheme interface {
+	ErasureScheme
+
+	// EncodeStripe is like Encode, for the stripe number stripe.
+	EncodeStripe(stripe int64, in []byte, out func(num int, data []byte)) error
+
+	// VerifyShare checks share number num of stripe, of EncodedBlockSize
+	// bytes, and returns the part of it passed to Decode.
+	VerifyShare(stripe int64, num int, share []byte) ([]byte, error)
+}
+
+// stripeScheme returns es as a StripeScheme, if its shares depend on their
+// stripe number
+func stripeScheme(es ErasureScheme) (StripeScheme, bool) {
+	switch rs := es.(type) {
+	case RedundancyStrategy:
+		es = rs.ErasureScheme
+	case *RedundancyStrategy:
+		es = rs.ErasureScheme
+	}
+	ss, ok := es.(StripeScheme)
+	return ss, ok
+}
+
+// macScheme appends a ShareMAC to the erasure shares of another scheme
+type macScheme struct {
+	ErasureScheme
+	mac *ShareMAC
+}
+
+// NewMACScheme returns a StripeScheme appending the MAC of every erasure
+// share of es to it. The shares passed to Decode and RebuildShare and
+// returned by EncodeSingleStripe and RebuildShare are the shares of es,
+// without MACs.
+func NewMACScheme(es ErasureScheme, mac *ShareMAC) StripeScheme {
+	return &macScheme{ErasureScheme: es, mac: mac}
+}
+
+func (s *macScheme) Encode(in []byte, out func(num int, data []byte)) error {
+	return Error.New("erasure shares with MACs need their stripe number")
+}
+
+func (s *macScheme) EncodeStripe(stripe int64, in []byte, out func(num int, data []byte)) error {
+	share := make([]byte, 0, s.EncodedBlockSize())
+	return s.ErasureScheme.Encode(in, func(num int, data []byte) {
+		share = append(share[:0], data...)
+		share = s.mac.Sum(share, stripe, num, data)
+		out(num, share)
+	})
+}
+
+func (s *macScheme) VerifyShare(stripe int64, num int, share []byte) ([]byte, error) {
+	if len(share) != s.EncodedBlockSize() {
+		return nil, ErrShareMAC.New("share %d of stripe %d has size %d instead of %d",
+			num, stripe, len(share), s.EncodedBlockSize())
+	}
+	data, sum := share[:len(share)-MACSize], share[len(share)-MACSize:]
+	if !s.mac.Verify(stripe, num, data, sum) {
+		return nil, ErrShareMAC.New("share %d of stripe %d", num, stripe)
+	}
+	return data, nil
+}
+
+func (s *macScheme) EncodedBlockSize() int {
+	return s.ErasureScheme.EncodedBlockSize() + MACSize
+}
@@ -0,0 +1,122 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vivint/infectious"
+
+	"storj.io/storj/pkg/ranger"
+)
+
+func TestShareMAC(t *testing.T) {
+	mac := NewShareMAC([]byte("segment"))
+	share := []byte("share")
+
+	sum := mac.Sum(nil, 3, 1, share)
+	assert.Len(t, sum, MACSize)
+	assert.True(t, mac.Verify(3, 1, share, sum))
+	assert.False(t, mac.Verify(2, 1, share, sum))
+	assert.False(t, mac.Verify(3, 2, share, sum))
+	assert.False(t, mac.Verify(3, 1, []byte("shard"), sum))
+	assert.False(t, NewShareMAC([]byte("other")).Verify(3, 1, share, sum))
+}
+
+func TestMACScheme(t *testing.T) {
+	ctx := context.Background()
+	data := randData(32 * 1024)
+	fc, err := infectious.NewFEC(2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es := NewMACScheme(NewRSScheme(fc, 1024), NewShareMAC([]byte("segment")))
+	assert.Equal(t, 1024+MACSize, es.EncodedBlockSize())
+	assert.Equal(t, 2048, es.DecodedBlockSize())
+	assert.Error(t, es.Encode(data[:2048], func(int, []byte) {}))
+
+	rs, err := NewRedundancyStrategy(es, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readers, err := EncodeReader(ctx, bytes.NewReader(data), rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pieces, err := readAll(readers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, pieces[0], 16*(1024+MACSize))
+
+	decode := func(pieces [][]byte) ([]byte, error) {
+		readerMap := make(map[int]io.ReadCloser, len(pieces))
+		for i, piece := range pieces {
+			readerMap[i] = ioutil.NopCloser(bytes.NewReader(piece))
+		}
+		decoder := DecodeReaders(ctx, readerMap, rs, int64(len(data)), 0)
+		defer func() { _ = decoder.Close() }()
+		return ioutil.ReadAll(decoder)
+	}
+	decoded, err := decode(pieces)
+	assert.NoError(t, err)
+	assert.Equal(t, data, decoded)
+
+	stripe := func(piece []byte, num int) []byte {
+		return piece[num*es.EncodedBlockSize() : (num+1)*es.EncodedBlockSize()]
+	}
+
+	// the shares of swapped pieces are rejected, and decoded from the others
+	swapped := [][]byte{pieces[1], pieces[0], pieces[2], pieces[3]}
+	decoded, err = decode(swapped)
+	assert.NoError(t, err)
+	assert.Equal(t, data, decoded)
+
+	// replayed stripes are rejected
+	replayed := make([][]byte, len(pieces))
+	for i, piece := range pieces {
+		replayed[i] = append([]byte(nil), piece...)
+		copy(stripe(replayed[i], 5), stripe(piece, 4))
+	}
+	_, err = decode(replayed)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "share MAC mismatch")
+	}
+
+	// ranges number their stripes from the start of the data
+	rrs := map[int]ranger.Ranger{}
+	for i, piece := range pieces {
+		rrs[i] = ranger.ByteRanger(piece)
+	}
+	rr, err := Decode(rrs, rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := rr.Range(ctx, 3000, 5000)
+	if assert.NoError(t, err) {
+		got, err := ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, data[3000:8000], got)
+		assert.NoError(t, r.Close())
+	}
+
+	encoded, err := NewEncodedRanger(ranger.ByteRanger(data), rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	shares, err := encoded.Range(ctx, 5*int64(es.EncodedBlockSize()), int64(es.EncodedBlockSize()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, share := range shares {
+		got, err := ioutil.ReadAll(share)
+		assert.NoError(t, err)
+		assert.Equal(t, stripe(pieces[i], 5), got)
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+)
+
+// LeafSize is the size of the leaves of the Merkle trees of pieces. The last
+// leaf of a piece may be shorter.
+const LeafSize = 16 * 1024
+
+// MerkleHasher computes the hashes of the leaves of the Merkle tree of the
+// data written to it, e.g. while a piece is uploaded
+type MerkleHasher struct {
+	leaves [][]byte
+	leaf   hash.Hash
+	filled int
+}
+
+// NewMerkleHasher returns a MerkleHasher of no data
+func NewMerkleHasher() *MerkleHasher {
+	return &MerkleHasher{}
+}
+
+// Write implements io.Writer
+func (h *MerkleHasher) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		if h.leaf == nil {
+			h.leaf = sha256.New()
+			_, _ = h.leaf.Write([]byte{0})
+		}
+		chunk := LeafSize - h.filled
+		if chunk > len(p) {
+			chunk = len(p)
+		}
+		_, _ = h.leaf.Write(p[:chunk])
+		h.filled += chunk
+		p = p[chunk:]
+		if h.filled == LeafSize {
+			h.leaves = append(h.leaves, h.leaf.Sum(nil))
+			h.leaf, h.filled = nil, 0
+		}
+	}
+	return n, nil
+}
+
+// Leaves returns the hashes of the leaves of the data written so far
+func (h *MerkleHasher) Leaves() [][]byte {
+	if h.leaf == nil {
+		return h.leaves
+	}
+	return append(h.leaves[:len(h.leaves):len(h.leaves)], h.leaf.Sum(nil))
+}
+
+// MerkleLeaf returns the hash of the leaf data
+func MerkleLeaf(data []byte) []byte {
+	h := sha256.New()
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write(data)
+	return h.Sum(nil)
+}
+
+// merkleNode returns the hash of the inner node with the children left and
+// right. Leaves and inner nodes are hashed with distinct prefixes, so that
+// an inner node can't pass for a leaf.
+func merkleNode(left, right []byte) []byte {
+	h := sha256.New()
+	_, _ = h.Write([]byte{1})
+	_, _ = h.Write(left)
+	_, _ = h.Write(right)
+	return h.Sum(nil)
+}
+
+// merkleLevel returns the level of the Merkle tree above level. The last
+// node of a level with an odd number of nodes is promoted as is.
+func merkleLevel(level [][]byte) [][]byte {
+	next := make([][]byte, 0, (len(level)+1)/2)
+	for i := 0; i < len(level); i += 2 {
+		if i+1 == len(level) {
+			next = append(next, level[i])
+			continue
+		}
+		next = append(next, merkleNode(level[i], level[i+1]))
+	}
+	return next
+}
+
+// MerkleRoot returns the root of the Merkle tree of leaves, or nil if there
+// are none
+func MerkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return nil
+	}
+	level := leaves
+	for len(level) > 1 {
+		level = merkleLevel(level)
+	}
+	return level[0]
+}
+
+// MerkleProof returns the hashes of the siblings of the index-th leaf from
+// the leaves up to the root, which prove that the leaf is in the tree
+func MerkleProof(leaves [][]byte, index int) [][]byte {
+	return MerkleRangeProof(leaves, index, index)
+}
+
+// MerkleRangeProof returns the hashes of the siblings of the leaves from
+// first to last, inclusive, from the leaves up to the root, which prove that
+// the range of leaves is in the tree
+func MerkleRangeProof(leaves [][]byte, first, last int) [][]byte {
+	var proof [][]byte
+	level := leaves
+	for len(level) > 1 {
+		if first%2 == 1 {
+			proof = append(proof, level[first-1])
+		}
+		if last%2 == 0 && last+1 < len(level) {
+			proof = append(proof, level[last+1])
+		}
+		level, first, last = merkleLevel(level), first/2, last/2
+	}
+	return proof
+}
+
+// VerifyMerkleProof checks that leaf is the data of the index-th of count
+// leaves of the Merkle tree with root
+func VerifyMerkleProof(root []byte, count, index int, leaf []byte, proof [][]byte) bool {
+	return VerifyMerkleRange(root, count, index, [][]byte{MerkleLeaf(leaf)}, proof)
+}
+
+// VerifyMerkleRange checks that hashes are the hashes of the leaves of the
+// Merkle tree with root and count leaves, starting from the first-th leaf
+func VerifyMerkleRange(root []byte, count, first int, hashes [][]byte, proof [][]byte) bool {
+	last := first + len(hashes) - 1
+	if first < 0 || len(hashes) == 0 || last >= count {
+		return false
+	}
+	level := append([][]byte(nil), hashes...)
+	for n := count; n > 1; n = (n + 1) / 2 {
+		if first%2 == 1 {
+			if len(proof) == 0 {
+				return false
+			}
+			level, proof = append([][]byte{proof[0]}, level...), proof[1:]
+			first--
+		}
+		if last%2 == 0 && last+1 < n {
+			if len(proof) == 0 {
+				return false
+			}
+			level, proof = append(level, proof[0]), proof[1:]
+			last++
+		}
+		level, first, last = merkleLevel(level), first/2, last/2
+	}
+	return len(proof) == 0 && hmac.Equal(level[0], root)
+}
+
+// MerkleLeafCount returns the number of leaves of a piece of size bytes
+func MerkleLeafCount(size int64) int {
+	return int((size + LeafSize - 1) / LeafSize)
+}
@@ -0,0 +1,82 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerkle(t *testing.T) {
+	for _, size := range []int{1, LeafSize, LeafSize + 1, 5*LeafSize - 3, 8 * LeafSize} {
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i*7 + i/LeafSize)
+		}
+
+		// the leaves don't depend on how the data is written
+		hasher := NewMerkleHasher()
+		for p := data; len(p) > 0; {
+			n := 1000
+			if n > len(p) {
+				n = len(p)
+			}
+			_, _ = hasher.Write(p[:n])
+			p = p[n:]
+		}
+		leaves := hasher.Leaves()
+		count := MerkleLeafCount(int64(size))
+		if !assert.Len(t, leaves, count, size) {
+			continue
+		}
+		root := MerkleRoot(leaves)
+
+		for index := 0; index < count; index++ {
+			end := (index + 1) * LeafSize
+			if end > size {
+				end = size
+			}
+			leaf := data[index*LeafSize : end]
+			assert.Equal(t, MerkleLeaf(leaf), leaves[index])
+
+			proof := MerkleProof(leaves, index)
+			assert.True(t, VerifyMerkleProof(root, count, index, leaf, proof), "%d/%d", index, count)
+
+			corrupted := append([]byte{}, leaf...)
+			corrupted[0]++
+			assert.False(t, VerifyMerkleProof(root, count, index, corrupted, proof))
+			if count > 1 {
+				assert.False(t, VerifyMerkleProof(root, count, (index+1)%count, leaf, proof))
+			}
+			assert.False(t, VerifyMerkleProof(root, count, count, leaf, proof))
+		}
+	}
+}
+
+func TestMerkleRange(t *testing.T) {
+	for count := 1; count <= 9; count++ {
+		var leaves [][]byte
+		for i := 0; i < count; i++ {
+			leaves = append(leaves, MerkleLeaf([]byte{byte(i)}))
+		}
+		root := MerkleRoot(leaves)
+
+		for first := 0; first < count; first++ {
+			for last := first; last < count; last++ {
+				proof := MerkleRangeProof(leaves, first, last)
+				hashes := leaves[first : last+1]
+				assert.True(t, VerifyMerkleRange(root, count, first, hashes, proof), "%d-%d/%d", first, last, count)
+
+				corrupted := append([][]byte{}, hashes...)
+				corrupted[len(corrupted)-1] = MerkleLeaf([]byte("corrupted"))
+				assert.False(t, VerifyMerkleRange(root, count, first, corrupted, proof))
+				if first > 0 {
+					assert.False(t, VerifyMerkleRange(root, count, first-1, hashes, proof))
+				}
+			}
+		}
+		assert.False(t, VerifyMerkleRange(root, count, 0, nil, nil))
+	}
+}
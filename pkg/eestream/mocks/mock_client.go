@@ -70,6 +70,19 @@ func (mr *MockErasureSchemeMockRecorder) Encode(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encode", reflect.TypeOf((*MockErasureScheme)(nil).Encode), arg0, arg1)
 }
 
+// EncodeSingleStripe mocks base method
+func (m *MockErasureScheme) EncodeSingleStripe(arg0 []byte) ([][]byte, error) {
+	ret := m.ctrl.Call(m, "EncodeSingleStripe", arg0)
+	ret0, _ := ret[0].([][]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EncodeSingleStripe indicates an expected call of EncodeSingleStripe
+func (mr *MockErasureSchemeMockRecorder) EncodeSingleStripe(arg0 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncodeSingleStripe", reflect.TypeOf((*MockErasureScheme)(nil).EncodeSingleStripe), arg0)
+}
+
 // EncodedBlockSize mocks base method
 func (m *MockErasureScheme) EncodedBlockSize() int {
 	ret := m.ctrl.Call(m, "EncodedBlockSize")
@@ -82,6 +95,19 @@ func (mr *MockErasureSchemeMockRecorder) EncodedBlockSize() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EncodedBlockSize", reflect.TypeOf((*MockErasureScheme)(nil).EncodedBlockSize))
 }
 
+// RebuildShare mocks base method
+func (m *MockErasureScheme) RebuildShare(arg0 []byte, arg1 map[int][]byte, arg2 int) ([]byte, error) {
+	ret := m.ctrl.Call(m, "RebuildShare", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RebuildShare indicates an expected call of RebuildShare
+func (mr *MockErasureSchemeMockRecorder) RebuildShare(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildShare", reflect.TypeOf((*MockErasureScheme)(nil).RebuildShare), arg0, arg1, arg2)
+}
+
 // RequiredCount mocks base method
 func (m *MockErasureScheme) RequiredCount() int {
 	ret := m.ctrl.Call(m, "RequiredCount")
@@ -22,6 +22,7 @@ type PieceBuffer struct {
 	totalwr      int64 // total bytes ever written to the buffer
 	lastwr       int64 // total bytes ever written when last notified newDataCond
 	err          error
+	closed       bool
 }
 
 // NewPieceBuffer creates and initializes a new PieceBuffer using buf as its
@@ -45,12 +46,15 @@ func (b *PieceBuffer) Read(p []byte) (n int, err error) {
 	b.cond.L.Lock()
 	defer b.cond.L.Unlock()
 
-	for b.empty() {
+	for b.empty() && !b.closed {
 		if b.err != nil {
 			return 0, b.err
 		}
 		b.cond.Wait()
 	}
+	if b.closed {
+		return 0, io.ErrClosedPipe
+	}
 
 	if b.rpos >= b.wpos {
 		nn := copy(p, b.buf[b.rpos:])
@@ -81,12 +85,15 @@ func (b *PieceBuffer) Skip(n int) error {
 	defer b.cond.L.Unlock()
 
 	for n > 0 {
-		for b.empty() {
+		for b.empty() && !b.closed {
 			if b.err != nil {
 				return b.err
 			}
 			b.cond.Wait()
 		}
+		if b.closed {
+			return io.ErrClosedPipe
+		}
 
 		if b.rpos >= b.wpos {
 			if len(b.buf)-b.rpos > n {
@@ -139,12 +146,15 @@ func (b *PieceBuffer) write(p []byte) (n int, err error) {
 	b.cond.L.Lock()
 	defer b.cond.L.Unlock()
 
-	for b.full {
+	for b.full && !b.closed {
 		if b.err != nil {
 			return n, b.err
 		}
 		b.cond.Wait()
 	}
+	if b.closed {
+		return n, io.ErrClosedPipe
+	}
 
 	var wr int
 	if b.wpos < b.rpos {
@@ -163,9 +173,17 @@ func (b *PieceBuffer) write(p []byte) (n int, err error) {
 }
 
 // Close sets io.ErrClosedPipe to the buffer to prevent further writes and
-// blocking on read.
+// blocking on read. Unlike other errors, it is returned by Read even if there
+// is data left in the buffer, so the buffer is never accessed after Close
+// returns and may be reused.
 func (b *PieceBuffer) Close() error {
-	b.SetError(io.ErrClosedPipe)
+	b.cond.L.Lock()
+	b.closed = true
+	b.err = io.ErrClosedPipe
+	b.cond.L.Unlock()
+
+	b.cond.Broadcast()
+	b.notifyNewData()
 	return nil
 }
 
@@ -182,7 +200,9 @@ func (b *PieceBuffer) setError(err error) {
 	b.cond.L.Lock()
 	defer b.cond.L.Unlock()
 
-	b.err = err
+	if !b.closed {
+		b.err = err
+	}
 }
 
 // getError is a helper method that locks the mutex before getting the error.
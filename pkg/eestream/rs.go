@@ -32,6 +32,47 @@ func (s *rsScheme) Decode(out []byte, in map[int][]byte) ([]byte, error) {
 	return s.fc.Decode(out, shares)
 }
 
+func (s *rsScheme) EncodeSingleStripe(in []byte) ([][]byte, error) {
+	if len(in) != s.DecodedBlockSize() {
+		return nil, Error.New("stripe size (%d) is not the decoded block size (%d)",
+			len(in), s.DecodedBlockSize())
+	}
+	shares := make([][]byte, s.fc.Total())
+	err := s.fc.Encode(in, func(share infectious.Share) {
+		// the data of the shares is reused by infectious
+		shares[share.Number] = append([]byte(nil), share.Data...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+func (s *rsScheme) RebuildShare(out []byte, in map[int][]byte, num int) ([]byte, error) {
+	if num < 0 || num >= s.fc.Total() {
+		return nil, Error.New("invalid share number %d", num)
+	}
+	if len(in) < s.fc.Required() {
+		return nil, Error.New("not enough shares to rebuild share %d: %d of %d required",
+			num, len(in), s.fc.Required())
+	}
+	// decoding corrects the shares in place, so it works on copies
+	shares := make([]infectious.Share, 0, len(in))
+	for n, data := range in {
+		shares = append(shares, infectious.Share{Number: n, Data: append([]byte(nil), data...)})
+	}
+	stripe, err := s.fc.Decode(nil, shares)
+	if err != nil {
+		return nil, err
+	}
+
+	share := make([]byte, s.blockSize)
+	if err := s.fc.EncodeSingle(stripe, share, num); err != nil {
+		return nil, err
+	}
+	return append(out, share...), nil
+}
+
 func (s *rsScheme) EncodedBlockSize() int {
 	return s.blockSize
 }
@@ -142,6 +142,105 @@ func TestRSRanger(t *testing.T) {
 	}
 }
 
+func TestRSRangerOffsets(t *testing.T) {
+	ctx := context.Background()
+	data := randData(32 * 1024)
+	fc, err := infectious.NewFEC(2, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs, err := NewRedundancyStrategy(NewRSScheme(fc, 1024), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	readers, err := EncodeReader(ctx, bytes.NewReader(data), rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pieces, err := readAll(readers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rrs := map[int]ranger.Ranger{}
+	for i, piece := range pieces {
+		rrs[i] = ranger.ByteRanger(piece)
+	}
+	rr, err := Decode(rrs, rs, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct{ offset, length int64 }{
+		{0, 1}, {1, 100}, {2047, 2}, {2048, 2048}, {3000, 5000}, {31 * 1024, 1024}, {32*1024 - 1, 1},
+	} {
+		r, err := rr.Range(ctx, tt.offset, tt.length)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		got, err := ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, data[tt.offset:tt.offset+tt.length], got, "offset %d length %d", tt.offset, tt.length)
+		assert.NoError(t, r.Close())
+	}
+
+	// the stripes before the offset are skipped
+	readerMap := make(map[int]io.ReadCloser, len(pieces))
+	for i, piece := range pieces {
+		readerMap[i] = ioutil.NopCloser(bytes.NewReader(piece))
+	}
+	decoder := DecodeReadersAt(ctx, readerMap, rs, 5000, 32*1024, 0)
+	got, err := ioutil.ReadAll(decoder)
+	assert.NoError(t, err)
+	assert.Equal(t, data[5000:], got)
+	assert.NoError(t, decoder.Close())
+
+	_, err = ioutil.ReadAll(DecodeReadersAt(ctx, readerMap, rs, 33*1024, 32*1024, 0))
+	assert.Error(t, err)
+}
+
+func TestRSStripe(t *testing.T) {
+	fc, err := infectious.NewFEC(2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es := NewRSScheme(fc, 1024)
+
+	_, err = es.EncodeSingleStripe(randData(1024))
+	assert.Error(t, err)
+	stripe := randData(es.DecodedBlockSize())
+	shares, err := es.EncodeSingleStripe(stripe)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, shares, 5)
+	decoded, err := es.Decode(nil, map[int][]byte{1: shares[1], 4: shares[4]})
+	assert.NoError(t, err)
+	assert.Equal(t, stripe, decoded)
+
+	// any share is rebuilt from any 2 others
+	for num := range shares {
+		in := map[int][]byte{(num + 1) % 5: shares[(num+1)%5], (num + 3) % 5: shares[(num+3)%5]}
+		share, err := es.RebuildShare(nil, in, num)
+		assert.NoError(t, err)
+		assert.Equal(t, shares[num], share)
+	}
+
+	// corrupt shares are corrected with enough other shares, without
+	// modifying them
+	corrupt := append([]byte(nil), shares[0]...)
+	corrupt[10]++
+	in := map[int][]byte{0: corrupt, 1: shares[1], 2: shares[2], 3: shares[3]}
+	share, err := es.RebuildShare([]byte("prefix"), in, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, append([]byte("prefix"), shares[4]...), share)
+	assert.NotEqual(t, shares[0], in[0])
+
+	_, err = es.RebuildShare(nil, map[int][]byte{0: shares[0]}, 4)
+	assert.Error(t, err)
+	_, err = es.RebuildShare(nil, in, 5)
+	assert.Error(t, err)
+}
+
 func TestNewRedundancyStrategy(t *testing.T) {
 	for i, tt := range []struct {
 		min       int
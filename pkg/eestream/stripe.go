@@ -16,21 +16,19 @@ import (
 // StripeReader can read and decodes stripes from a set of readers
 type StripeReader struct {
 	scheme ErasureScheme
+	first  int64 // number of the first stripe in the erasure coded data
 	cond   *sync.Cond
 	bufs   map[int]*PieceBuffer
 	inbufs [][]byte
 	inmap  map[int][]byte
 	errmap map[int]error
+	closed bool
 }
 
 // NewStripeReader creates a new StripeReader from the given readers, erasure
 // scheme and max buffer memory.
 func NewStripeReader(rs map[int]io.ReadCloser, es ErasureScheme, mbm int) *StripeReader {
-	bufSize := mbm / es.TotalCount()
-	bufSize -= bufSize % es.EncodedBlockSize()
-	if bufSize < es.EncodedBlockSize() {
-		bufSize = es.EncodedBlockSize()
-	}
+	bufSize := pieceBufferSize(es, mbm)
 
 	r := &StripeReader{
 		scheme: es,
@@ -42,14 +40,16 @@ func NewStripeReader(rs map[int]io.ReadCloser, es ErasureScheme, mbm int) *Strip
 	}
 
 	for i := 0; i < es.TotalCount(); i++ {
-		r.inbufs[i] = make([]byte, es.EncodedBlockSize())
-		r.bufs[i] = NewPieceBuffer(make([]byte, bufSize), es.EncodedBlockSize(), r.cond)
+		r.inbufs[i] = getBuffer(es.EncodedBlockSize())
+		r.bufs[i] = NewPieceBuffer(getBuffer(bufSize), es.EncodedBlockSize(), r.cond)
 	}
 
 	// Kick off a goroutine each reader to be copied into a PieceBuffer.
 	for i, buf := range r.bufs {
 		go func(r io.Reader, buf *PieceBuffer) {
-			_, err := io.Copy(buf, r)
+			copyBuf := getBuffer(copyBufferSize)
+			defer putBuffer(copyBuf)
+			_, err := io.CopyBuffer(buf, r, copyBuf)
 			if err != nil {
 				buf.SetError(err)
 				return
@@ -61,7 +61,29 @@ func NewStripeReader(rs map[int]io.ReadCloser, es ErasureScheme, mbm int) *Strip
 	return r
 }
 
-// Close closes the StripeReader and all PieceBuffers.
+// pieceBufferSize returns the size of the buffer of each piece of a
+// StripeReader with max buffer memory mbm
+func pieceBufferSize(es ErasureScheme, mbm int) int {
+	bufSize := mbm / es.TotalCount()
+	bufSize -= bufSize % es.EncodedBlockSize()
+	if bufSize < es.EncodedBlockSize() {
+		bufSize = es.EncodedBlockSize()
+	}
+	return bufSize
+}
+
+// stripeReaderMemory returns the memory allocated by the buffers of a
+// StripeReader with max buffer memory mbm
+func stripeReaderMemory(es ErasureScheme, mbm int) int {
+	return es.TotalCount() * (pieceBufferSize(es, mbm) + es.EncodedBlockSize())
+}
+
+// copyBufferSize is the size of the buffers the piece streams are read into
+// before they are written to the PieceBuffers
+const copyBufferSize = 32 * 1024
+
+// Close closes the StripeReader and all PieceBuffers, and returns their
+// buffers to the pool.
 func (r *StripeReader) Close() error {
 	errs := make(chan error, len(r.bufs))
 	for _, buf := range r.bufs {
@@ -76,6 +98,17 @@ func (r *StripeReader) Close() error {
 			first = Error.Wrap(err)
 		}
 	}
+
+	// wait for a stripe being decoded from the input buffers
+	r.cond.L.Lock()
+	defer r.cond.L.Unlock()
+	if !r.closed {
+		r.closed = true
+		for i, buf := range r.bufs {
+			putBuffer(buf.buf)
+			putBuffer(r.inbufs[i])
+		}
+	}
 	return first
 }
 
@@ -93,6 +126,9 @@ func (r *StripeReader) ReadStripe(num int64, p []byte) ([]byte, error) {
 		for r.readAvailableShares(num) == 0 {
 			r.cond.Wait()
 		}
+		if r.closed {
+			return nil, Error.Wrap(io.ErrClosedPipe)
+		}
 		if r.hasEnoughShares() {
 			out, err := r.scheme.Decode(p, r.inmap)
 			if err != nil {
@@ -117,11 +153,11 @@ func (r *StripeReader) readAvailableShares(num int64) (n int) {
 			continue
 		}
 		if r.bufs[i].HasShare(num) {
-			err := r.bufs[i].ReadShare(num, r.inbufs[i])
+			share, err := r.readShare(i, num)
 			if err != nil {
 				r.errmap[i] = err
 			} else {
-				r.inmap[i] = r.inbufs[i]
+				r.inmap[i] = share
 			}
 			n++
 		}
@@ -129,6 +165,25 @@ func (r *StripeReader) readAvailableShares(num int64) (n int) {
 	return n
 }
 
+// readShare reads the num-th erasure share of piece i and, if the shares
+// depend on their stripe number, verifies it
+func (r *StripeReader) readShare(i int, num int64) ([]byte, error) {
+	err := r.bufs[i].ReadShare(num, r.inbufs[i])
+	if err != nil {
+		return nil, err
+	}
+	ss, ok := stripeScheme(r.scheme)
+	if !ok {
+		return r.inbufs[i], nil
+	}
+	share, err := ss.VerifyShare(r.first+num, i, r.inbufs[i])
+	if err != nil {
+		mon.Counter("share_mac_mismatch").Inc(1)
+		return nil, err
+	}
+	return share, nil
+}
+
 // pendingReaders checks if there are any pending readers to get a share from.
 func (r *StripeReader) pendingReaders() bool {
 	return len(r.inmap)+len(r.errmap) < r.scheme.TotalCount()
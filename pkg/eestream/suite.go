@@ -0,0 +1,168 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/vivint/infectious"
+)
+
+// SuiteID identifies the encryption and erasure code of segments. It's
+// recorded in pointers, so the ids of registered suites must never change or
+// be reused.
+type SuiteID int32
+
+const (
+	// SuiteUnspecified is the suite of segments stored before suites were
+	// recorded, which are Reed-Solomon coded
+	SuiteUnspecified SuiteID = 0
+	// SuiteRS stores segments Reed-Solomon coded without encrypting them,
+	// for clients that encrypt objects themselves
+	SuiteRS SuiteID = 1
+	// SuiteAESGCMRS encrypts segments with AES-GCM and Reed-Solomon codes
+	// them
+	SuiteAESGCMRS SuiteID = 2
+	// SuiteSecretboxRS encrypts segments with NaCl secretbox and
+	// Reed-Solomon codes them
+	SuiteSecretboxRS SuiteID = 3
+	// SuiteAESGCMSIVRS encrypts segments with AES-GCM-SIV and Reed-Solomon
+	// codes them
+	SuiteAESGCMSIVRS SuiteID = 4
+)
+
+// Suite is an encryption and erasure code implementation
+type Suite struct {
+	ID   SuiteID
+	Name string
+
+	// NonceSize is the size of the starting nonces of the cipher, 0 if
+	// segments aren't encrypted
+	NonceSize int
+	// NewEncrypter and NewDecrypter return the cipher's transformers of
+	// blocks of encryptedBlockSize, nil if segments aren't encrypted
+	NewEncrypter func(key *[32]byte, startingNonce []byte, encryptedBlockSize int) (Transformer, error)
+	NewDecrypter func(key *[32]byte, startingNonce []byte, encryptedBlockSize int) (Transformer, error)
+
+	// NewErasure returns the erasure scheme of required out of total pieces
+	// with shares of shareSize
+	NewErasure func(required, total, shareSize int) (ErasureScheme, error)
+}
+
+// Encrypted returns whether the suite encrypts segments
+func (suite Suite) Encrypted() bool {
+	return suite.NewEncrypter != nil
+}
+
+var (
+	suitesMu sync.RWMutex
+	suites   = map[SuiteID]Suite{}
+)
+
+// RegisterSuite makes suite available by its id and name. It panics if the
+// id or name is already registered, or the suite is incomplete.
+func RegisterSuite(suite Suite) {
+	suitesMu.Lock()
+	defer suitesMu.Unlock()
+
+	if suite.ID == SuiteUnspecified || suite.Name == "" || suite.NewErasure == nil ||
+		(suite.NewEncrypter == nil) != (suite.NewDecrypter == nil) {
+		panic("eestream: invalid suite " + suite.Name)
+	}
+	for _, registered := range suites {
+		if registered.ID == suite.ID || registered.Name == suite.Name {
+			panic("eestream: suite " + suite.Name + " registered twice")
+		}
+	}
+	suites[suite.ID] = suite
+}
+
+// LookupSuite returns the suite with id. Segments stored before suites were
+// recorded are Reed-Solomon coded without encryption.
+func LookupSuite(id SuiteID) (Suite, error) {
+	if id == SuiteUnspecified {
+		id = SuiteRS
+	}
+	suitesMu.RLock()
+	defer suitesMu.RUnlock()
+
+	suite, ok := suites[id]
+	if !ok {
+		return Suite{}, Error.New("unknown suite %d", id)
+	}
+	return suite, nil
+}
+
+// ParseSuite returns the suite called name
+func ParseSuite(name string) (Suite, error) {
+	suitesMu.RLock()
+	defer suitesMu.RUnlock()
+
+	for _, suite := range suites {
+		if suite.Name == name {
+			return suite, nil
+		}
+	}
+	return Suite{}, Error.New("unknown suite %q", name)
+}
+
+// Suites returns the registered suites ordered by id
+func Suites() []Suite {
+	suitesMu.RLock()
+	defer suitesMu.RUnlock()
+
+	list := make([]Suite, 0, len(suites))
+	for _, suite := range suites {
+		list = append(list, suite)
+	}
+	sort.Slice(list, func(i, k int) bool { return list[i].ID < list[k].ID })
+	return list
+}
+
+// newRS returns the Reed-Solomon scheme of required out of total pieces
+func newRS(required, total, shareSize int) (ErasureScheme, error) {
+	fc, err := infectious.NewFEC(required, total)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return NewRSScheme(fc, shareSize), nil
+}
+
+// nonce12 and nonce24 adapt the ciphers to starting nonces passed as slices,
+// which must be of the size of their nonces
+func nonce12(fn func(*[32]byte, *[12]byte, int) (Transformer, error)) func(*[32]byte, []byte, int) (Transformer, error) {
+	return func(key *[32]byte, startingNonce []byte, encryptedBlockSize int) (Transformer, error) {
+		var nonce [12]byte
+		if len(startingNonce) != len(nonce) {
+			return nil, Error.New("invalid nonce size %d", len(startingNonce))
+		}
+		copy(nonce[:], startingNonce)
+		return fn(key, &nonce, encryptedBlockSize)
+	}
+}
+
+func nonce24(fn func(*[32]byte, *[24]byte, int) (Transformer, error)) func(*[32]byte, []byte, int) (Transformer, error) {
+	return func(key *[32]byte, startingNonce []byte, encryptedBlockSize int) (Transformer, error) {
+		var nonce [24]byte
+		if len(startingNonce) != len(nonce) {
+			return nil, Error.New("invalid nonce size %d", len(startingNonce))
+		}
+		copy(nonce[:], startingNonce)
+		return fn(key, &nonce, encryptedBlockSize)
+	}
+}
+
+func init() {
+	RegisterSuite(Suite{ID: SuiteRS, Name: "rs", NewErasure: newRS})
+	RegisterSuite(Suite{ID: SuiteAESGCMRS, Name: "aesgcm-rs", NonceSize: 12,
+		NewEncrypter: nonce12(NewAESGCMEncrypter), NewDecrypter: nonce12(NewAESGCMDecrypter),
+		NewErasure: newRS})
+	RegisterSuite(Suite{ID: SuiteSecretboxRS, Name: "secretbox-rs", NonceSize: 24,
+		NewEncrypter: nonce24(NewSecretboxEncrypter), NewDecrypter: nonce24(NewSecretboxDecrypter),
+		NewErasure: newRS})
+	RegisterSuite(Suite{ID: SuiteAESGCMSIVRS, Name: "aesgcmsiv-rs", NonceSize: 12,
+		NewEncrypter: nonce12(NewAESGCMSIVEncrypter), NewDecrypter: nonce12(NewAESGCMSIVDecrypter),
+		NewErasure: newRS})
+}
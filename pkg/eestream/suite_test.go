@@ -0,0 +1,63 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package eestream
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuites(t *testing.T) {
+	var key [32]byte
+	copy(key[:], randData(32))
+
+	for _, suite := range Suites() {
+		byName, err := ParseSuite(suite.Name)
+		assert.NoError(t, err)
+		assert.Equal(t, suite.ID, byName.ID)
+
+		es, err := suite.NewErasure(2, 4, 1024)
+		if !assert.NoError(t, err, suite.Name) {
+			continue
+		}
+		assert.Equal(t, 2, es.RequiredCount())
+		assert.Equal(t, 4, es.TotalCount())
+
+		if !suite.Encrypted() {
+			continue
+		}
+		nonce := randData(suite.NonceSize)
+		encrypter, err := suite.NewEncrypter(&key, nonce, 1024)
+		if !assert.NoError(t, err, suite.Name) {
+			continue
+		}
+		decrypter, err := suite.NewDecrypter(&key, nonce, 1024)
+		if !assert.NoError(t, err, suite.Name) {
+			continue
+		}
+		data := randData(encrypter.InBlockSize() * 3)
+		encrypted := TransformReader(ioutil.NopCloser(bytes.NewReader(data)), encrypter, 0)
+		decrypted, err := ioutil.ReadAll(TransformReader(encrypted, decrypter, 0))
+		assert.NoError(t, err, suite.Name)
+		assert.True(t, bytes.Equal(data, decrypted), suite.Name)
+
+		_, err = suite.NewEncrypter(&key, nonce[1:], 1024)
+		assert.Error(t, err, suite.Name)
+	}
+
+	// segments stored before suites were recorded are Reed-Solomon coded
+	suite, err := LookupSuite(SuiteUnspecified)
+	assert.NoError(t, err)
+	assert.Equal(t, SuiteRS, suite.ID)
+
+	_, err = LookupSuite(1000)
+	assert.Error(t, err)
+	_, err = ParseSuite("rot13-rs")
+	assert.Error(t, err)
+
+	assert.Panics(t, func() { RegisterSuite(Suite{ID: SuiteRS, Name: "other", NewErasure: newRS}) })
+}
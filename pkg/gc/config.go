@@ -0,0 +1,72 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/chore"
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for garbage collection
+	Error = errs.Class("gc error")
+)
+
+// Config contains everything necessary to start the garbage collection
+// responsibility
+type Config struct {
+	Interval          time.Duration `help:"how frequently retain filters are built and sent to storage nodes" default:"168h"`
+	FalsePositiveRate float64       `help:"the false positive rate of the retain bloom filters" default:"0.1"`
+	CreationBuffer    time.Duration `help:"how far before the snapshot pieces are treated as garbage, to protect uploads in progress" default:"24h"`
+	Concurrency       int           `help:"how many storage nodes retain requests are sent to in parallel" default:"5"`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// metainfo loop and Overlay responsibilities have been started before this
+// one.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if c.Interval <= 0 {
+		return Error.New("invalid interval: %v", c.Interval)
+	}
+	if c.FalsePositiveRate <= 0 || c.FalsePositiveRate >= 1 {
+		return Error.New("invalid false positive rate: %v", c.FalsePositiveRate)
+	}
+
+	loop := metainfo.LoadFromContext(ctx)
+	if loop == nil {
+		return Error.New("programmer error: metainfo loop responsibility unstarted")
+	}
+
+	cache := overlay.LoadFromContext(ctx)
+	if cache == nil {
+		return Error.New("programmer error: overlay responsibility unstarted")
+	}
+
+	sender := NewSender(server.Identity(), transport.NewClient(server.Identity()), cache)
+	service := NewService(zap.L().Named("gc"), c, loop, sender)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	collect, err := chore.New(ctx, "gc", c.Interval, service.Collect)
+	if err != nil {
+		return err
+	}
+	go func() { _ = collect.Run(ctx) }()
+
+	return server.Run(ctx)
+}
@@ -0,0 +1,24 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunInvalidConfig(t *testing.T) {
+	ctx := context.Background()
+
+	err := Config{FalsePositiveRate: 0.1}.Run(ctx, nil)
+	assert.True(t, Error.Has(err))
+	assert.Equal(t, "gc error: invalid interval: 0s", err.Error())
+
+	err = Config{Interval: time.Hour}.Run(ctx, nil)
+	assert.True(t, Error.Has(err))
+	assert.Equal(t, "gc error: invalid false positive rate: 0", err.Error())
+}
@@ -0,0 +1,59 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"context"
+	"time"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+)
+
+// Sender sends retain requests to storage nodes
+type Sender interface {
+	SendRetain(ctx context.Context, nodeID string, createdBefore time.Time, filter []byte) (*pb.RetainSummary, error)
+}
+
+// Overlay looks up the address of a storage node
+type Overlay interface {
+	Get(ctx context.Context, nodeID string) (*pb.Node, error)
+}
+
+type sender struct {
+	identity  *provider.FullIdentity
+	transport transport.Client
+	overlay   Overlay
+}
+
+// NewSender creates a Sender that dials storage nodes found in the overlay
+func NewSender(identity *provider.FullIdentity, t transport.Client, overlay Overlay) Sender {
+	return &sender{identity: identity, transport: t, overlay: overlay}
+}
+
+// SendRetain sends a retain request to the storage node with nodeID
+func (s *sender) SendRetain(ctx context.Context, nodeID string, createdBefore time.Time, filter []byte) (summary *pb.RetainSummary, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	node, err := s.overlay.Get(ctx, nodeID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	conn, err := s.transport.DialNode(ctx, node)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	ps, err := client.NewPSClient(conn, 0, s.identity.Key)
+	if err != nil {
+		_ = conn.Close()
+		return nil, Error.Wrap(err)
+	}
+	defer func() { _ = ps.Close() }()
+
+	return ps.Retain(ctx, createdBefore, filter)
+}
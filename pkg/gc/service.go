@@ -0,0 +1,259 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/bloomfilter"
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/storage"
+)
+
+// Service builds retain filters from pointerdb and sends them to storage
+// nodes
+type Service struct {
+	log    *zap.Logger
+	config Config
+	loop   *metainfo.Loop
+	sender Sender
+
+	mu   sync.Mutex
+	last *Plan
+}
+
+// NewService creates a new garbage collection Service
+func NewService(log *zap.Logger, config Config, loop *metainfo.Loop, sender Sender) *Service {
+	return &Service{
+		log:    log,
+		config: config,
+		loop:   loop,
+		sender: sender,
+	}
+}
+
+// Status is the state of a retain request for a single node
+type Status int
+
+const (
+	// StatusPending means the retain request has not been sent yet
+	StatusPending Status = iota
+	// StatusDone means the node has processed the retain request
+	StatusDone
+	// StatusFailed means the retain request could not be delivered
+	StatusFailed
+)
+
+// Plan is the set of retain filters built from a single pointerdb snapshot
+type Plan struct {
+	// CreatedBefore is the creation time of the filters. Nodes must keep
+	// every piece created after it.
+	CreatedBefore time.Time
+	// Filters contains the retain filter of every node, by node id
+	Filters map[string]*bloomfilter.Filter
+	// Pieces contains the number of pieces added to every filter
+	Pieces map[string]int
+
+	mu        sync.Mutex
+	statuses  map[string]Status
+	summaries map[string]*pb.RetainSummary
+}
+
+// Progress summarizes the completion of a plan
+type Progress struct {
+	Pending  int
+	Done     int
+	Failed   int
+	Retained int64
+	Deleted  int64
+}
+
+// planner collects the pieces of every node during an iteration of the
+// metainfo loop
+type planner struct {
+	pieces map[string][]client.PieceID
+}
+
+// Pointer implements metainfo.Observer
+func (planner *planner) Pointer(ctx context.Context, path storage.Key, pointer *pb.Pointer) error {
+	remote := pointer.GetRemote()
+	if remote == nil {
+		return nil
+	}
+
+	pieceID := client.PieceID(remote.GetPieceId())
+	for _, piece := range remote.GetRemotePieces() {
+		nodeID := piece.GetNodeId()
+		derived, err := pieceID.Derive([]byte(nodeID))
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		planner.pieces[nodeID] = append(planner.pieces[nodeID], derived)
+	}
+	return nil
+}
+
+// CreatePlan joins the metainfo loop and builds a retain filter for every
+// node that stores pieces.
+func (service *Service) CreatePlan(ctx context.Context) (plan *Plan, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// The loop iterates over a single consistent view of the store, opened
+	// after joining. Pieces for pointers committed after this view was
+	// opened are protected by CreatedBefore, which additionally leaves room
+	// for uploads that were still in progress.
+	createdBefore := time.Now().Add(-service.config.CreationBuffer)
+
+	planner := &planner{pieces: map[string][]client.PieceID{}}
+	if err := service.loop.Join(ctx, planner); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	pieces := planner.pieces
+
+	plan = &Plan{
+		CreatedBefore: createdBefore,
+		Filters:       map[string]*bloomfilter.Filter{},
+		Pieces:        map[string]int{},
+		statuses:      map[string]Status{},
+		summaries:     map[string]*pb.RetainSummary{},
+	}
+	for nodeID, ids := range pieces {
+		filter := bloomfilter.NewOptimal(len(ids), service.config.FalsePositiveRate)
+		for _, id := range ids {
+			filter.Add([]byte(id))
+		}
+		plan.Filters[nodeID] = filter
+		plan.Pieces[nodeID] = len(ids)
+		plan.statuses[nodeID] = StatusPending
+	}
+
+	return plan, nil
+}
+
+// Send sends the retain requests of plan to all pending nodes
+func (service *Service) Send(ctx context.Context, plan *Plan) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	concurrency := service.config.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	limiter := make(chan struct{}, concurrency)
+
+	var wg sync.WaitGroup
+	for _, nodeID := range plan.pending() {
+		select {
+		case limiter <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		}
+
+		wg.Add(1)
+		go func(nodeID string) {
+			defer wg.Done()
+			defer func() { <-limiter }()
+
+			filter := plan.Filters[nodeID].Bytes()
+			summary, err := service.sender.SendRetain(ctx, nodeID, plan.CreatedBefore, filter)
+			if err != nil {
+				service.log.Warn("failed to send retain request",
+					zap.String("node", nodeID), zap.Error(err))
+				plan.finish(nodeID, StatusFailed, nil)
+				return
+			}
+			plan.finish(nodeID, StatusDone, summary)
+		}(nodeID)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// Collect creates a new plan and sends it to all nodes
+func (service *Service) Collect(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	plan, err := service.CreatePlan(ctx)
+	if err != nil {
+		return err
+	}
+
+	service.mu.Lock()
+	service.last = plan
+	service.mu.Unlock()
+
+	err = service.Send(ctx, plan)
+
+	progress := plan.Progress()
+	service.log.Info("garbage collection finished",
+		zap.Int("done", progress.Done),
+		zap.Int("failed", progress.Failed),
+		zap.Int64("deleted", progress.Deleted))
+	return err
+}
+
+// LastPlan returns the most recently created plan, or nil
+func (service *Service) LastPlan() *Plan {
+	service.mu.Lock()
+	defer service.mu.Unlock()
+	return service.last
+}
+
+// Status returns the status of the retain request for nodeID
+func (plan *Plan) Status(nodeID string) Status {
+	plan.mu.Lock()
+	defer plan.mu.Unlock()
+	return plan.statuses[nodeID]
+}
+
+// Progress returns how many nodes have completed the plan
+func (plan *Plan) Progress() Progress {
+	plan.mu.Lock()
+	defer plan.mu.Unlock()
+
+	var progress Progress
+	for nodeID, status := range plan.statuses {
+		switch status {
+		case StatusPending:
+			progress.Pending++
+		case StatusDone:
+			progress.Done++
+			progress.Retained += plan.summaries[nodeID].GetRetained()
+			progress.Deleted += plan.summaries[nodeID].GetDeleted()
+		case StatusFailed:
+			progress.Failed++
+		}
+	}
+	return progress
+}
+
+// pending returns the sorted ids of nodes that have not completed the plan
+func (plan *Plan) pending() []string {
+	plan.mu.Lock()
+	defer plan.mu.Unlock()
+
+	var nodes []string
+	for nodeID, status := range plan.statuses {
+		if status != StatusDone {
+			nodes = append(nodes, nodeID)
+		}
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+func (plan *Plan) finish(nodeID string, status Status, summary *pb.RetainSummary) {
+	plan.mu.Lock()
+	defer plan.mu.Unlock()
+	plan.statuses[nodeID] = status
+	plan.summaries[nodeID] = summary
+}
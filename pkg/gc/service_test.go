@@ -0,0 +1,170 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gc
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/bloomfilter"
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+var ctx = context.Background()
+
+type mockSender struct {
+	mu      sync.Mutex
+	failing map[string]bool
+	filters map[string][]byte
+}
+
+func (m *mockSender) SendRetain(ctx context.Context, nodeID string, createdBefore time.Time, filter []byte) (*pb.RetainSummary, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failing[nodeID] {
+		return nil, errors.New("node unreachable")
+	}
+	m.filters[nodeID] = filter
+	return &pb.RetainSummary{Retained: 1, Deleted: 2}, nil
+}
+
+// startLoop runs a metainfo loop over db until stop is called
+func startLoop(db storage.KeyValueStore) (loop *metainfo.Loop, stop func()) {
+	loop = metainfo.NewLoop(metainfo.Config{}, db)
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = loop.Run(ctx)
+	}()
+	return loop, func() {
+		cancel()
+		<-done
+	}
+}
+
+func putPointer(t *testing.T, db storage.KeyValueStore, path string, pointer *pb.Pointer) {
+	value, err := proto.Marshal(pointer)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, db.Put(storage.Key(path), value))
+}
+
+func remotePointer(pieceID string, nodeIDs ...string) *pb.Pointer {
+	var pieces []*pb.RemotePiece
+	for i, nodeID := range nodeIDs {
+		pieces = append(pieces, &pb.RemotePiece{PieceNum: int32(i), NodeId: nodeID})
+	}
+	return &pb.Pointer{
+		Type: pb.Pointer_REMOTE,
+		Remote: &pb.RemoteSegment{
+			PieceId:      pieceID,
+			RemotePieces: pieces,
+		},
+	}
+}
+
+func derive(t *testing.T, pieceID, nodeID string) []byte {
+	derived, err := client.PieceID(pieceID).Derive([]byte(nodeID))
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return []byte(derived)
+}
+
+func TestCreatePlan(t *testing.T) {
+	db := teststore.New()
+	putPointer(t, db, "a/b", remotePointer("piece-ab", "node1", "node2"))
+	putPointer(t, db, "a/c", remotePointer("piece-ac", "node2", "node3"))
+	putPointer(t, db, "a/inline", &pb.Pointer{
+		Type:          pb.Pointer_INLINE,
+		InlineSegment: []byte("inline data"),
+	})
+
+	config := Config{FalsePositiveRate: 0.01, CreationBuffer: time.Hour}
+	loop, stop := startLoop(db)
+	defer stop()
+	service := NewService(zap.NewNop(), config, loop, &mockSender{})
+
+	before := time.Now().Add(-config.CreationBuffer)
+	plan, err := service.CreatePlan(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.False(t, plan.CreatedBefore.Before(before))
+	assert.Len(t, plan.Filters, 3)
+	assert.Equal(t, map[string]int{"node1": 1, "node2": 2, "node3": 1}, plan.Pieces)
+
+	assert.True(t, plan.Filters["node1"].Contains(derive(t, "piece-ab", "node1")))
+	assert.True(t, plan.Filters["node2"].Contains(derive(t, "piece-ab", "node2")))
+	assert.True(t, plan.Filters["node2"].Contains(derive(t, "piece-ac", "node2")))
+	assert.True(t, plan.Filters["node3"].Contains(derive(t, "piece-ac", "node3")))
+
+	for nodeID := range plan.Filters {
+		assert.Equal(t, StatusPending, plan.Status(nodeID))
+	}
+	assert.Equal(t, Progress{Pending: 3}, plan.Progress())
+}
+
+func TestCreatePlanInvalidPointer(t *testing.T) {
+	db := teststore.New()
+	assert.NoError(t, db.Put(storage.Key("a/b"), storage.Value("not a pointer")))
+
+	loop, stop := startLoop(db)
+	defer stop()
+	service := NewService(zap.NewNop(), Config{FalsePositiveRate: 0.1}, loop, &mockSender{})
+	_, err := service.CreatePlan(ctx)
+	assert.Error(t, err)
+}
+
+func TestSend(t *testing.T) {
+	db := teststore.New()
+	putPointer(t, db, "a/b", remotePointer("piece-ab", "node1", "node2", "node3"))
+
+	sender := &mockSender{
+		failing: map[string]bool{"node2": true},
+		filters: map[string][]byte{},
+	}
+	config := Config{FalsePositiveRate: 0.1, Concurrency: 2}
+	loop, stop := startLoop(db)
+	defer stop()
+	service := NewService(zap.NewNop(), config, loop, sender)
+
+	assert.Nil(t, service.LastPlan())
+	assert.NoError(t, service.Collect(ctx))
+
+	plan := service.LastPlan()
+	if !assert.NotNil(t, plan) {
+		return
+	}
+	assert.Equal(t, StatusDone, plan.Status("node1"))
+	assert.Equal(t, StatusFailed, plan.Status("node2"))
+	assert.Equal(t, StatusDone, plan.Status("node3"))
+	assert.Equal(t, Progress{Done: 2, Failed: 1, Retained: 2, Deleted: 4}, plan.Progress())
+
+	filter, err := bloomfilter.NewFromBytes(sender.filters["node1"])
+	if assert.NoError(t, err) {
+		assert.True(t, filter.Contains(derive(t, "piece-ab", "node1")))
+	}
+
+	// resending only retries the failed node
+	delete(sender.failing, "node2")
+	sender.filters = map[string][]byte{}
+	assert.NoError(t, service.Send(ctx, plan))
+	assert.Len(t, sender.filters, 1)
+	assert.Equal(t, Progress{Done: 3, Retained: 3, Deleted: 6}, plan.Progress())
+}
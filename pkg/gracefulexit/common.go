@@ -0,0 +1,19 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default graceful exit errs class
+	Error = errs.Class("graceful exit error")
+	// ErrInvalidReceipt is returned for transfers whose receipts don't prove
+	// that the piece was received
+	ErrInvalidReceipt = errs.Class("invalid transfer receipt")
+)
@@ -0,0 +1,63 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage/boltdb"
+	"storj.io/storj/storage/storelogger"
+)
+
+// ProgressBucket is the bucket the progress of exiting nodes is stored in
+const ProgressBucket = "exitprogress"
+
+// Config contains everything necessary to run the graceful exit service
+type Config struct {
+	DatabaseURL   string `help:"the database connection string to use" default:"bolt://$CONFDIR/gracefulexit.db"`
+	MaxFailures   int    `help:"the number of transfers with invalid receipts after which the graceful exit of a node fails" default:"10"`
+	MinDifficulty uint   `help:"the minimum difficulty of the identities of the nodes pieces are transferred to" default:"12"`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// PointerDB and Overlay responsibilities have been started before this one.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pdb := pointerdb.LoadFromContext(ctx)
+	if pdb == nil {
+		return Error.New("programmer error: pointerdb responsibility unstarted")
+	}
+
+	cache := overlay.LoadFromContext(ctx)
+	if cache == nil {
+		return Error.New("programmer error: overlay responsibility unstarted")
+	}
+
+	dburl, err := utils.ParseURL(c.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	if dburl.Scheme != "bolt" {
+		return Error.New("unsupported db scheme: %s", dburl.Scheme)
+	}
+
+	bdb, err := boltdb.New(dburl.Path, ProgressBucket)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = bdb.Close() }()
+
+	s := NewServer(zap.L().Named("gracefulexit"), pdb.DB, storelogger.New(zap.L().Named("gracefulexit"), bdb), cache, c.MaxFailures, uint16(c.MinDifficulty))
+	pb.RegisterGracefulExitServer(server.GRPC(), s)
+
+	return server.Run(ctx)
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gtank/cryptopasta"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls"
+	"storj.io/storj/pkg/provider"
+)
+
+// SignReceipt signs the receipt of a piece received from an exiting node on
+// behalf of the receiving node identity. The receiving node and the
+// timestamp of data are set by SignReceipt.
+func SignReceipt(identity *provider.FullIdentity, data *pb.PieceTransferReceipt_Data) (*pb.PieceTransferReceipt, error) {
+	key, ok := identity.Key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, peertls.ErrUnsupportedKey.New("%T", identity.Key)
+	}
+
+	data.ReceivingNodeId = identity.ID.Bytes()
+	data.TimestampUnixSec = time.Now().Unix()
+
+	serialized, err := proto.Marshal(data)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	signature, err := cryptopasta.Sign(serialized, key)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return &pb.PieceTransferReceipt{
+		Signature: signature,
+		Data:      serialized,
+		Certs:     identity.Chain(),
+	}, nil
+}
+
+// VerifyReceipt checks the certificate chain and the signature of a transfer
+// receipt, and that it was signed by the node it names as the receiving
+// node, whose identity has at least minDifficulty. It returns the data of the
+// receipt.
+func VerifyReceipt(receipt *pb.PieceTransferReceipt, minDifficulty uint16) (*pb.PieceTransferReceipt_Data, error) {
+	certs, err := provider.ParseCertChain(receipt.GetCerts())
+	if err != nil {
+		return nil, ErrInvalidReceipt.Wrap(err)
+	}
+	if len(certs) < 2 {
+		return nil, ErrInvalidReceipt.New("invalid certificate chain")
+	}
+	if err := peertls.VerifyPeerCertChains(nil, [][]*x509.Certificate{certs}); err != nil {
+		return nil, ErrInvalidReceipt.Wrap(err)
+	}
+
+	receiving, err := provider.PeerIdentityFromCerts(certs[0], certs[1])
+	if err != nil {
+		return nil, ErrInvalidReceipt.Wrap(err)
+	}
+	if difficulty := receiving.ID.Difficulty(); difficulty < minDifficulty {
+		return nil, ErrInvalidReceipt.New("difficulty %d of receiving node is below %d", difficulty, minDifficulty)
+	}
+
+	key, ok := receiving.Leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, peertls.ErrUnsupportedKey.New("%T", receiving.Leaf.PublicKey)
+	}
+	if !cryptopasta.Verify(receipt.GetData(), receipt.GetSignature(), key) {
+		return nil, ErrInvalidReceipt.New("invalid signature")
+	}
+
+	data := &pb.PieceTransferReceipt_Data{}
+	if err := proto.Unmarshal(receipt.GetData(), data); err != nil {
+		return nil, ErrInvalidReceipt.Wrap(err)
+	}
+	if string(data.GetReceivingNodeId()) != receiving.ID.String() {
+		return nil, ErrInvalidReceipt.New("receipt was signed by another node")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,429 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+// targetCandidates is how many nodes of the overlay are considered when the
+// target of a transfer is chosen
+const targetCandidates = 100
+
+// Server implements the graceful exit service. Exiting nodes transfer their
+// pieces to the targets the service chooses in the overlay. Pointers are
+// only moved to the target of a piece once its receipt is verified, and each
+// receipt that can't be verified counts as a failed transfer of the exiting
+// node.
+type Server struct {
+	log           *zap.Logger
+	pointers      storage.KeyValueStore
+	progress      storage.KeyValueStore
+	overlay       *overlay.Cache
+	maxFailures   int64
+	minDifficulty uint16
+
+	// mu serializes the updates of pointers and exit progress
+	mu sync.Mutex
+}
+
+// NewServer creates a graceful exit service that updates the pointers in
+// pointers, chooses the targets of transfers in cache and keeps the progress
+// and targets of exiting nodes in progress. The exit of a node fails once
+// more than maxFailures of its transfers failed. Receipts are only accepted
+// from nodes with identities of at least minDifficulty.
+func NewServer(log *zap.Logger, pointers, progress storage.KeyValueStore, cache *overlay.Cache, maxFailures int, minDifficulty uint16) *Server {
+	return &Server{
+		log:           log,
+		pointers:      pointers,
+		progress:      progress,
+		overlay:       cache,
+		maxFailures:   int64(maxFailures),
+		minDifficulty: minDifficulty,
+	}
+}
+
+// InitiateExit starts the graceful exit of the calling node
+func (s *Server) InitiateExit(ctx context.Context, req *pb.InitiateExitRequest) (resp *pb.InitiateExitResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	exiting, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+	if err := s.Initiate(ctx, exiting.ID.String()); err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return &pb.InitiateExitResponse{}, nil
+}
+
+// Initiate starts the graceful exit of nodeID. The progress of an exit that
+// was already started is kept.
+func (s *Server) Initiate(ctx context.Context, nodeID string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	progress, err := s.Progress(ctx, nodeID)
+	if err != nil {
+		return err
+	}
+	if progress.GetStartedUnixSec() != 0 {
+		return nil
+	}
+	s.log.Info("graceful exit initiated", zap.String("node", nodeID))
+	progress.StartedUnixSec = time.Now().Unix()
+	return s.putProgress(nodeID, progress)
+}
+
+// GetTransferTarget returns the node the calling node has to transfer a
+// piece to
+func (s *Server) GetTransferTarget(ctx context.Context, req *pb.TransferTargetRequest) (resp *pb.TransferTargetResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	exiting, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+
+	node, err := s.Target(ctx, exiting.ID.String(), req.GetPath(), req.GetPieceNum())
+	switch {
+	case err == nil:
+		return &pb.TransferTargetResponse{Node: node}, nil
+	case Error.Has(err):
+		return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+	default:
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+}
+
+// Target returns the node the exiting node has to transfer the piece
+// pieceNum of the segment at path to. It is chosen among the nodes of the
+// overlay that don't store a piece of the segment, and kept until the piece
+// is transferred or the node leaves the overlay.
+func (s *Server) Target(ctx context.Context, exitingNodeID, path string, pieceNum int32) (node *pb.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.exitingProgress(ctx, exitingNodeID); err != nil {
+		return nil, err
+	}
+
+	key := targetKey(exitingNodeID, path, pieceNum)
+	target, err := s.progress.Get(key)
+	switch {
+	case err == nil:
+		node, err := s.overlay.Get(ctx, string(target))
+		if err != nil && !storage.ErrKeyNotFound.Has(err) {
+			return nil, Error.Wrap(err)
+		}
+		if node != nil {
+			return node, nil
+		}
+		// the target left the overlay, another one is chosen
+	case !storage.ErrKeyNotFound.Has(err):
+		return nil, Error.Wrap(err)
+	}
+
+	pointer, err := s.getPointer(path)
+	if err != nil {
+		return nil, err
+	}
+	if pointer == nil || findPiece(pointer, exitingNodeID, pieceNum) == nil {
+		return nil, Error.New("exiting node doesn't store piece %d of %s", pieceNum, path)
+	}
+
+	node, err = s.selectTarget(ctx, pointer)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.progress.Put(key, storage.Value(node.GetId())); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return node, nil
+}
+
+// selectTarget chooses a random node of the overlay that doesn't store a
+// piece of pointer, accepts uploads and isn't exiting itself
+func (s *Server) selectTarget(ctx context.Context, pointer *pb.Pointer) (*pb.Node, error) {
+	seen := map[string]bool{}
+	for _, piece := range pointer.GetRemote().GetRemotePieces() {
+		seen[piece.GetNodeId()] = true
+	}
+
+	// the candidates start at a random node id, and wrap around at the end
+	// of the overlay
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+	start := storage.Key{alphabet[rand.Intn(len(alphabet))]}
+	keys, err := s.overlay.DB.List(start, targetCandidates)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if len(keys) < targetCandidates {
+		more, err := s.overlay.DB.List(nil, targetCandidates-len(keys))
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		keys = append(keys, more...)
+	}
+
+	var candidates []string
+	for _, key := range keys {
+		if !seen[key.String()] {
+			seen[key.String()] = true
+			candidates = append(candidates, key.String())
+		}
+	}
+	for _, i := range rand.Perm(len(candidates)) {
+		node, err := s.overlay.Get(ctx, candidates[i])
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		if node == nil || node.GetRestrictions().GetIngressFull() {
+			continue
+		}
+		progress, err := s.Progress(ctx, node.GetId())
+		if err != nil {
+			return nil, err
+		}
+		if progress.GetStartedUnixSec() != 0 {
+			continue
+		}
+		return node, nil
+	}
+	return nil, Error.New("no node to transfer the piece to")
+}
+
+// TransferSucceeded moves a piece of the calling node to the node that signed
+// the receipt of the request
+func (s *Server) TransferSucceeded(ctx context.Context, req *pb.TransferSucceededRequest) (resp *pb.TransferSucceededResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	exiting, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+
+	err = s.Transfer(ctx, exiting.ID.String(), req)
+	switch {
+	case err == nil:
+		return &pb.TransferSucceededResponse{}, nil
+	case ErrInvalidReceipt.Has(err):
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	case Error.Has(err):
+		return nil, status.Errorf(codes.FailedPrecondition, err.Error())
+	default:
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+}
+
+// Transfer moves the piece of the exiting node to the receiving node of the
+// receipt of req, if the receipt is valid. Otherwise the failed transfer is
+// counted against the exiting node.
+func (s *Server) Transfer(ctx context.Context, exitingNodeID string, req *pb.TransferSucceededRequest) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	progress, err := s.exitingProgress(ctx, exitingNodeID)
+	if err != nil {
+		return err
+	}
+
+	err = s.movePiece(ctx, exitingNodeID, req)
+	if ErrInvalidReceipt.Has(err) {
+		s.log.Info("failed transfer of exiting node", zap.String("node", exitingNodeID),
+			zap.String("path", req.GetPath()), zap.Error(err))
+		progress.Failed++
+		return utils.CombineErrors(err, s.putProgress(exitingNodeID, progress))
+	}
+	if err != nil {
+		return err
+	}
+
+	progress.Transferred++
+	return s.putProgress(exitingNodeID, progress)
+}
+
+// Progress returns the counts of transferred and failed pieces of an exiting
+// node
+func (s *Server) Progress(ctx context.Context, nodeID string) (progress *pb.ExitProgress, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	progress = &pb.ExitProgress{}
+	value, err := s.progress.Get(storage.Key(nodeID))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return progress, nil
+		}
+		return nil, Error.Wrap(err)
+	}
+	if err := proto.Unmarshal(value, progress); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return progress, nil
+}
+
+// exitingProgress returns the progress of a node whose exit is in progress.
+// It fails if the node isn't exiting or its exit failed.
+func (s *Server) exitingProgress(ctx context.Context, nodeID string) (*pb.ExitProgress, error) {
+	progress, err := s.Progress(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	if progress.GetStartedUnixSec() == 0 {
+		return nil, Error.New("node %s is not exiting", nodeID)
+	}
+	if progress.GetFailed() > s.maxFailures {
+		return nil, Error.New("graceful exit of node %s failed", nodeID)
+	}
+	return progress, nil
+}
+
+// movePiece verifies the receipt of req and replaces the exiting node in the
+// pointer at req.Path with the receiving node, which has to be the target
+// handed out for the piece
+func (s *Server) movePiece(ctx context.Context, exitingNodeID string, req *pb.TransferSucceededRequest) error {
+	data, err := VerifyReceipt(req.GetReceipt(), s.minDifficulty)
+	if err != nil {
+		return err
+	}
+	receivingNodeID := string(data.GetReceivingNodeId())
+	if string(data.GetExitingNodeId()) != exitingNodeID {
+		return ErrInvalidReceipt.New("receipt is for another exiting node")
+	}
+	if receivingNodeID == exitingNodeID {
+		return ErrInvalidReceipt.New("piece was transferred to the exiting node")
+	}
+	if len(req.GetPieceHash()) == 0 || !bytes.Equal(data.GetPieceHash(), req.GetPieceHash()) {
+		return ErrInvalidReceipt.New("receiving node got another piece")
+	}
+
+	key := targetKey(exitingNodeID, req.GetPath(), req.GetPieceNum())
+	target, err := s.progress.Get(key)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return ErrInvalidReceipt.New("no target was handed out for piece %d of %s", req.GetPieceNum(), req.GetPath())
+		}
+		return Error.Wrap(err)
+	}
+	if string(target) != receivingNodeID {
+		return ErrInvalidReceipt.New("piece was transferred to another node than its target")
+	}
+	node, err := s.overlay.Get(ctx, receivingNodeID)
+	if err != nil && !storage.ErrKeyNotFound.Has(err) {
+		return Error.Wrap(err)
+	}
+	if node == nil {
+		return ErrInvalidReceipt.New("receiving node %s is unknown", receivingNodeID)
+	}
+
+	pointer, err := s.getPointer(req.GetPath())
+	if err != nil {
+		return err
+	}
+	if pointer == nil {
+		return ErrInvalidReceipt.New("no pointer at %s", req.GetPath())
+	}
+
+	remote := pointer.GetRemote()
+	for _, p := range remote.GetRemotePieces() {
+		if p.GetNodeId() == receivingNodeID {
+			return ErrInvalidReceipt.New("receiving node already stores a piece of %s", req.GetPath())
+		}
+	}
+	piece := findPiece(pointer, exitingNodeID, req.GetPieceNum())
+	if piece == nil {
+		return ErrInvalidReceipt.New("exiting node doesn't store piece %d of %s", req.GetPieceNum(), req.GetPath())
+	}
+	// the hash of the piece is the root of its Merkle tree, which uplinks
+	// record for the pieces they upload
+	if root := piece.GetMerkleRoot(); len(root) > 0 && !bytes.Equal(root, data.GetPieceHash()) {
+		return ErrInvalidReceipt.New("receiving node got a corrupted piece")
+	}
+
+	derived, err := client.PieceID(remote.GetPieceId()).Derive([]byte(receivingNodeID))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if data.GetPieceId() != derived.String() {
+		return ErrInvalidReceipt.New("receipt is for another piece")
+	}
+
+	piece.NodeId = receivingNodeID
+	value, err := proto.Marshal(pointer)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if err := s.pointers.Put(storage.Key(req.GetPath()), value); err != nil {
+		return Error.Wrap(err)
+	}
+	if err := s.progress.Delete(key); err != nil && !storage.ErrKeyNotFound.Has(err) {
+		return Error.Wrap(err)
+	}
+	return nil
+}
+
+// getPointer returns the pointer at path, or nil if there is none
+func (s *Server) getPointer(path string) (*pb.Pointer, error) {
+	value, err := s.pointers.Get(storage.Key(path))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, nil
+		}
+		return nil, Error.Wrap(err)
+	}
+	pointer := &pb.Pointer{}
+	if err := proto.Unmarshal(value, pointer); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return pointer, nil
+}
+
+// findPiece returns the remote piece pieceNum of pointer stored by nodeID,
+// or nil
+func findPiece(pointer *pb.Pointer, nodeID string, pieceNum int32) *pb.RemotePiece {
+	for _, piece := range pointer.GetRemote().GetRemotePieces() {
+		if piece.GetPieceNum() == pieceNum && piece.GetNodeId() == nodeID {
+			return piece
+		}
+	}
+	return nil
+}
+
+// targetKey is the key the target of a transfer is kept at with the
+// progress of exiting nodes
+func targetKey(exitingNodeID, path string, pieceNum int32) storage.Key {
+	return storage.Key(fmt.Sprintf("target/%s/%d/%s", exitingNodeID, pieceNum, path))
+}
+
+// putProgress stores the progress of an exiting node
+func (s *Server) putProgress(nodeID string, progress *pb.ExitProgress) error {
+	value, err := proto.Marshal(progress)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(s.progress.Put(storage.Key(nodeID), value))
+}
@@ -0,0 +1,169 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package gracefulexit
+
+import (
+	"context"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+func newIdentity(t *testing.T) *provider.FullIdentity {
+	ca, err := provider.NewCA(context.Background(), 12, 4)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	identity, err := ca.NewIdentity()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return identity
+}
+
+func TestTransfer(t *testing.T) {
+	ctx := context.Background()
+	receiving := newIdentity(t)
+	exitingID, receivingID := "exiting", receiving.ID.String()
+
+	pointers := teststore.New()
+	pointer := &pb.Pointer{
+		Type: pb.Pointer_REMOTE,
+		Remote: &pb.RemoteSegment{
+			PieceId: "rootpiece",
+			RemotePieces: []*pb.RemotePiece{
+				{PieceNum: 0, NodeId: "other"},
+				{PieceNum: 1, NodeId: exitingID},
+			},
+		},
+	}
+	value, err := proto.Marshal(pointer)
+	assert.NoError(t, err)
+	assert.NoError(t, pointers.Put(storage.Key("path"), value))
+
+	// the same segment, whose piece had another Merkle root at upload
+	otherRoot := sha256.Sum256([]byte("other"))
+	pointer.Remote.RemotePieces[1].MerkleRoot = otherRoot[:]
+	value, err = proto.Marshal(pointer)
+	assert.NoError(t, err)
+	assert.NoError(t, pointers.Put(storage.Key("corrupted"), value))
+
+	cache := &overlay.Cache{DB: teststore.New()}
+	assert.NoError(t, cache.Put(receivingID, pb.Node{Id: receivingID}))
+	server := NewServer(zap.NewNop(), pointers, teststore.New(), cache, 2, 12)
+
+	sign := func(identity *provider.FullIdentity, pieceHash []byte) *pb.PieceTransferReceipt {
+		derived, err := client.PieceID("rootpiece").Derive(identity.ID.Bytes())
+		assert.NoError(t, err)
+		receipt, err := SignReceipt(identity, &pb.PieceTransferReceipt_Data{
+			ExitingNodeId: []byte(exitingID),
+			PieceId:       derived.String(),
+			PieceHash:     pieceHash,
+		})
+		assert.NoError(t, err)
+		return receipt
+	}
+	pieceHash := sha256.Sum256([]byte("piece"))
+	receipt := sign(receiving, pieceHash[:])
+	request := func(pieceHash []byte) *pb.TransferSucceededRequest {
+		return &pb.TransferSucceededRequest{Path: "path", PieceNum: 1, PieceHash: pieceHash, Receipt: receipt}
+	}
+
+	// nodes that aren't exiting can't transfer pieces
+	_, err = server.Target(ctx, exitingID, "path", 1)
+	assert.True(t, Error.Has(err))
+	err = server.Transfer(ctx, exitingID, request(pieceHash[:]))
+	assert.True(t, Error.Has(err))
+	assert.False(t, ErrInvalidReceipt.Has(err))
+
+	// the target is the only node of the overlay without a piece of the
+	// segment, and is kept
+	assert.NoError(t, server.Initiate(ctx, exitingID))
+	for i := 0; i < 2; i++ {
+		target, err := server.Target(ctx, exitingID, "path", 1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, receivingID, target.GetId())
+		}
+	}
+
+	// receipts for other pieces fail the transfer
+	otherHash := sha256.Sum256([]byte("other"))
+	err = server.Transfer(ctx, exitingID, request(otherHash[:]))
+	assert.True(t, ErrInvalidReceipt.Has(err))
+	err = server.Transfer(ctx, "other", request(pieceHash[:]))
+	assert.True(t, Error.Has(err))
+
+	// a receiving node the exiting node made up isn't the target of the
+	// piece, nor in the overlay
+	forged := request(pieceHash[:])
+	forged.Receipt = sign(newIdentity(t), pieceHash[:])
+	err = server.Transfer(ctx, exitingID, forged)
+	assert.True(t, ErrInvalidReceipt.Has(err))
+
+	assert.NoError(t, server.Transfer(ctx, exitingID, request(pieceHash[:])))
+	value, err = pointers.Get(storage.Key("path"))
+	assert.NoError(t, err)
+	assert.NoError(t, proto.Unmarshal(value, pointer))
+	assert.Equal(t, receivingID, pointer.GetRemote().GetRemotePieces()[1].GetNodeId())
+
+	progress, err := server.Progress(ctx, exitingID)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), progress.GetTransferred())
+	assert.Equal(t, int64(2), progress.GetFailed())
+
+	// pieces that don't match the root recorded at upload fail the transfer
+	other := NewServer(zap.NewNop(), pointers, teststore.New(), cache, 2, 12)
+	assert.NoError(t, other.Initiate(ctx, exitingID))
+	_, err = other.Target(ctx, exitingID, "corrupted", 1)
+	assert.NoError(t, err)
+	corrupted := request(pieceHash[:])
+	corrupted.Path = "corrupted"
+	err = other.Transfer(ctx, exitingID, corrupted)
+	assert.True(t, ErrInvalidReceipt.Has(err))
+
+	// the piece was moved already, and the exit fails with the third failure
+	err = server.Transfer(ctx, exitingID, request(pieceHash[:]))
+	assert.True(t, ErrInvalidReceipt.Has(err))
+	err = server.Transfer(ctx, exitingID, request(pieceHash[:]))
+	assert.True(t, Error.Has(err))
+	assert.False(t, ErrInvalidReceipt.Has(err))
+}
+
+func TestVerifyReceipt(t *testing.T) {
+	receiving := newIdentity(t)
+
+	receipt, err := SignReceipt(receiving, &pb.PieceTransferReceipt_Data{PieceId: "piece"})
+	assert.NoError(t, err)
+	data, err := VerifyReceipt(receipt, 12)
+	if assert.NoError(t, err) {
+		assert.Equal(t, receiving.ID.Bytes(), data.GetReceivingNodeId())
+		assert.Equal(t, "piece", data.GetPieceId())
+	}
+
+	tampered := *receipt
+	tampered.Data = append([]byte{}, receipt.Data...)
+	tampered.Data[len(tampered.Data)-1]++
+	_, err = VerifyReceipt(&tampered, 12)
+	assert.True(t, ErrInvalidReceipt.Has(err))
+
+	other := newIdentity(t)
+	forged := *receipt
+	forged.Certs = [][]byte{other.Leaf.Raw, other.CA.Raw}
+	_, err = VerifyReceipt(&forged, 12)
+	assert.True(t, ErrInvalidReceipt.Has(err))
+
+	// identities below the difficulty aren't trusted
+	_, err = VerifyReceipt(receipt, receiving.ID.Difficulty()+1)
+	assert.True(t, ErrInvalidReceipt.Has(err))
+}
@@ -0,0 +1,91 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/chore"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/provider"
+)
+
+// Config contains everything necessary to start the health responsibility
+type Config struct {
+	Timeout       time.Duration `help:"how long the checks of a health or readiness probe may take" default:"5s"`
+	OverlayMaxAge time.Duration `help:"how long after its last successful refresh the overlay cache is stale" default:"5m"`
+}
+
+// Run implements the provider.Responsibility interface. Run checks the
+// dependencies started before it, so it should be started last.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	checker := NewChecker(c.Timeout)
+
+	if pdb := pointerdb.LoadFromContext(ctx); pdb != nil {
+		checker.Add("pointerdb", Readiness, func(ctx context.Context) error {
+			_, err := pdb.DB.List(nil, 1)
+			return err
+		})
+	}
+	if cache := overlay.LoadFromContext(ctx); cache != nil {
+		checker.Add("overlay", Readiness, func(ctx context.Context) error {
+			if _, err := cache.DB.List(nil, 1); err != nil {
+				return err
+			}
+			return checkFresh(cache.LastRefresh(), c.OverlayMaxAge)
+		})
+	}
+	if db := accounting.LoadFromContext(ctx); db != nil {
+		checker.Add("accounting", Readiness, func(ctx context.Context) error {
+			return db.DB.PingContext(ctx)
+		})
+	}
+	if registry := chore.LoadFromContext(ctx); registry != nil {
+		checker.Add("chores", Liveness, func(ctx context.Context) error {
+			return checkChores(registry.Statuses(), time.Now())
+		})
+	}
+
+	process.HandleDebug("/health", checker.Handler(Liveness))
+	process.HandleDebug("/ready", checker.Handler(Liveness, Readiness))
+
+	return server.Run(ctx)
+}
+
+// checkFresh fails if refreshed is more than maxAge ago
+func checkFresh(refreshed time.Time, maxAge time.Duration) error {
+	if refreshed.IsZero() {
+		return Error.New("never refreshed")
+	}
+	if age := time.Since(refreshed); age > maxAge {
+		return Error.New("last refreshed %v ago", age.Round(time.Second))
+	}
+	return nil
+}
+
+// checkChores fails if a chore has been running for more than twice its
+// interval at now. A chore waiting for the lease hasn't started.
+func checkChores(statuses []chore.Status, now time.Time) error {
+	var stuck []string
+	for _, status := range statuses {
+		if !status.Running || status.LastStart.IsZero() {
+			continue
+		}
+		if running := now.Sub(status.LastStart); running > 2*status.Interval {
+			stuck = append(stuck, fmt.Sprintf("%s running for %v", status.Name, running.Round(time.Second)))
+		}
+	}
+	if len(stuck) > 0 {
+		return Error.New("stuck chores: %s", strings.Join(stuck, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for health checks
+	Error = errs.Class("health error")
+)
+
+// Check checks a dependency of the process, returning an error if it's
+// unhealthy
+type Check func(ctx context.Context) error
+
+// Kind is what failing a check means for the process
+type Kind int
+
+const (
+	// Readiness checks fail while the process can't serve requests, like
+	// when a database is unreachable. The process recovers on its own.
+	Readiness Kind = iota
+	// Liveness checks fail when the process is stuck and must be restarted
+	Liveness
+)
+
+// Status is the result of a check
+type Status struct {
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// Report is the result of the checks of an endpoint
+type Report struct {
+	OK     bool              `json:"ok"`
+	Checks map[string]Status `json:"checks"`
+}
+
+type namedCheck struct {
+	name  string
+	kind  Kind
+	check Check
+}
+
+// Checker runs the checks of a process
+type Checker struct {
+	timeout time.Duration
+
+	mu     sync.Mutex
+	checks []namedCheck
+}
+
+// NewChecker creates a Checker whose checks time out after timeout
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add adds the check of the dependency name
+func (checker *Checker) Add(name string, kind Kind, check Check) {
+	checker.mu.Lock()
+	defer checker.mu.Unlock()
+	checker.checks = append(checker.checks, namedCheck{name: name, kind: kind, check: check})
+	sort.Slice(checker.checks, func(i, k int) bool { return checker.checks[i].name < checker.checks[k].name })
+}
+
+// Run runs the checks of the given kinds in parallel
+func (checker *Checker) Run(ctx context.Context, kinds ...Kind) (report Report) {
+	defer mon.Task()(&ctx)(nil)
+
+	checker.mu.Lock()
+	var checks []namedCheck
+	for _, check := range checker.checks {
+		for _, kind := range kinds {
+			if check.kind == kind {
+				checks = append(checks, check)
+			}
+		}
+	}
+	checker.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, checker.timeout)
+	defer cancel()
+
+	// checks that ignore ctx are reported as timed out, but left running
+	type result struct {
+		index  int
+		status Status
+	}
+	results := make(chan result, len(checks))
+	for i, check := range checks {
+		go func(i int, check namedCheck) {
+			status := Status{OK: true}
+			if err := check.check(ctx); err != nil {
+				status = Status{Error: err.Error()}
+			}
+			results <- result{index: i, status: status}
+		}(i, check)
+	}
+
+	statuses := make([]Status, len(checks))
+	for i := range statuses {
+		statuses[i] = Status{Error: "timed out"}
+	}
+collect:
+	for range checks {
+		select {
+		case result := <-results:
+			statuses[result.index] = result.status
+		case <-ctx.Done():
+			break collect
+		}
+	}
+
+	report = Report{OK: true, Checks: make(map[string]Status, len(checks))}
+	for i, check := range checks {
+		report.Checks[check.name] = statuses[i]
+		report.OK = report.OK && statuses[i].OK
+	}
+	return report
+}
+
+// Handler returns an http.Handler that serves the report of the checks of
+// the given kinds as JSON, with status 503 if any failed
+func (checker *Checker) Handler(kinds ...Kind) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := checker.Run(r.Context(), kinds...)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-cache")
+		if !report.OK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(report)
+	})
+}
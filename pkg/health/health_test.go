@@ -0,0 +1,63 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/chore"
+)
+
+func TestChecker(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Add("db", Readiness, func(ctx context.Context) error { return errors.New("unreachable") })
+	checker.Add("cache", Readiness, func(ctx context.Context) error { return nil })
+	checker.Add("chores", Liveness, func(ctx context.Context) error { return nil })
+	checker.Add("slow", Readiness, func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	checker.Handler(Liveness).ServeHTTP(rec, httptest.NewRequest("GET", "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	checker.Handler(Liveness, Readiness).ServeHTTP(rec, httptest.NewRequest("GET", "/ready", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var report Report
+	assert.NoError(t, json.NewDecoder(rec.Body).Decode(&report))
+	assert.Equal(t, Report{Checks: map[string]Status{
+		"cache":  {OK: true},
+		"chores": {OK: true},
+		"db":     {Error: "unreachable"},
+		"slow":   {Error: "timed out"},
+	}}, report)
+}
+
+func TestCheckChores(t *testing.T) {
+	now := time.Now()
+	assert.NoError(t, checkChores([]chore.Status{
+		{Name: "idle", Interval: time.Hour, LastStart: now.Add(-5 * time.Hour)},
+		{Name: "waiting", Interval: time.Hour, Running: true},
+		{Name: "running", Interval: time.Hour, Running: true, LastStart: now.Add(-time.Hour)},
+	}, now))
+
+	assert.Error(t, checkChores([]chore.Status{
+		{Name: "stuck", Interval: time.Hour, Running: true, LastStart: now.Add(-3 * time.Hour)},
+	}, now))
+
+	assert.Error(t, checkFresh(time.Time{}, time.Minute))
+	assert.Error(t, checkFresh(now.Add(-time.Hour), time.Minute))
+	assert.NoError(t, checkFresh(now, time.Minute))
+}
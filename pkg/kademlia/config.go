@@ -6,8 +6,10 @@ package kademlia
 import (
 	"context"
 	"net"
+	"time"
 
 	"github.com/zeebo/errs"
+	"go.uber.org/zap"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
 	"storj.io/storj/pkg/pb"
@@ -33,6 +35,11 @@ type Config struct {
 	BootstrapAddr string `help:"the kademlia node to bootstrap against" default:"bootstrap-dev.storj.io:8080"`
 	// TODO(jt): remove this! kademlia should just use the grpc server
 	TODOListenAddr string `help:"the host/port for kademlia to listen on. TODO(jt): this should be removed!" default:"127.0.0.1:7776"`
+
+	RoutingTableDir string        `help:"the directory the routing table is persisted to" default:"$CONFDIR/kademlia"`
+	RevalidateAfter time.Duration `help:"how long a k-bucket can go without updates before its nodes are pinged again on startup" default:"1h"`
+
+	LookupCacheTTL time.Duration `help:"how long the results of node lookups are cached, 0 to disable" default:"1m"`
 }
 
 // Run implements provider.Responsibility
@@ -57,13 +64,39 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (
 	if err != nil {
 		return Error.Wrap(err)
 	}
+	self := &pb.Node{
+		Id: server.Identity().ID.String(),
+		Address: &pb.NodeAddress{
+			Transport: defaultTransport,
+			Address:   c.TODOListenAddr,
+		},
+	}
+	rt, err := OpenRoutingTable(self, c.RoutingTableDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rt.Close() }()
+
+	// bootstrap against the nodes known from the previous run as well, so a
+	// restart doesn't depend on the bootstrap node alone
+	bootstrapNodes := []pb.Node{*in}
+	restored, err := rt.Nodes()
+	if err != nil {
+		return err
+	}
+	for _, node := range restored {
+		bootstrapNodes = append(bootstrapNodes, *node)
+	}
+
 	// TODO(jt): kademlia should register on server.GRPC() instead of listening
 	// itself
-	kad, err := NewKademlia(server.Identity().ID, []pb.Node{*in}, host, port)
+	kad, err := NewKademlia(server.Identity().ID, bootstrapNodes, host, port)
 	if err != nil {
 		return err
 	}
 	defer func() { _ = kad.Disconnect() }()
+	kad.routingTable = rt
+	kad.lookups = newLookupCache(c.LookupCacheTTL)
 
 	// TODO(jt): ListenAndServe should probably be blocking and we should kick
 	// it off in a goroutine here
@@ -72,6 +105,13 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (
 		return err
 	}
 
+	removed, err := rt.Revalidate(ctx, kad.Ping, c.RevalidateAfter)
+	if err != nil {
+		return err
+	}
+	zap.S().Infof("restored %d nodes from the routing table, %d were stale",
+		len(restored), removed)
+
 	// TODO(jt): Bootstrap should probably be blocking and we should kick it off
 	// in a goroutine here
 	err = kad.Bootstrap(ctx)
@@ -9,6 +9,7 @@ import (
 	"log"
 	"net"
 	"strconv"
+	"time"
 
 	bkad "github.com/coyle/kademlia"
 	"github.com/zeebo/errs"
@@ -25,12 +26,13 @@ var defaultTransport = pb.NodeTransport_TCP
 
 // Kademlia is an implementation of kademlia adhering to the DHT interface.
 type Kademlia struct {
-	routingTable   RoutingTable
+	routingTable   *RoutingTable
 	bootstrapNodes []pb.Node
 	ip             string
 	port           string
 	stun           bool
 	dht            *bkad.DHT
+	lookups        *lookupCache
 }
 
 // NewKademlia returns a newly configured Kademlia instance
@@ -66,13 +68,7 @@ func NewKademlia(id dht.NodeID, bootstrapNodes []pb.Node, ip string, port string
 		return nil, err
 	}
 
-	rt := RoutingTable{
-		// ht:  bdht.HT,
-		// dht: bdht,
-	}
-
 	return &Kademlia{
-		routingTable:   rt,
 		bootstrapNodes: bootstrapNodes,
 		ip:             ip,
 		port:           port,
@@ -99,6 +95,7 @@ func (k Kademlia) GetNodes(ctx context.Context, start string, limit int, restric
 	}
 
 	nodes := convertNetworkNodes(nn)
+	k.remember(nodes...)
 
 	for _, r := range restrictions {
 		nodes = restrict(r, nodes)
@@ -108,6 +105,9 @@ func (k Kademlia) GetNodes(ctx context.Context, start string, limit int, restric
 
 // GetRoutingTable provides the routing table for the Kademlia DHT
 func (k *Kademlia) GetRoutingTable(ctx context.Context) (dht.RoutingTable, error) {
+	if k.routingTable != nil {
+		return k.routingTable, nil
+	}
 	return &RoutingTable{
 		// ht:  k.dht.HT,
 		// dht: k.dht,
@@ -130,9 +130,11 @@ func (k *Kademlia) Ping(ctx context.Context, node pb.Node) (pb.Node, error) {
 
 	ok, err := k.dht.Ping(n)
 	if err != nil {
+		k.lookups.forget(node.Id)
 		return pb.Node{}, err
 	}
 	if !ok {
+		k.lookups.forget(node.Id)
 		return pb.Node{}, NodeErr.New("node unavailable")
 	}
 	return node, nil
@@ -140,7 +142,13 @@ func (k *Kademlia) Ping(ctx context.Context, node pb.Node) (pb.Node, error) {
 
 // FindNode looks up the provided NodeID first in the local Node, and if it is not found
 // begins searching the network for the NodeID. Returns and error if node was not found
+// Recently found nodes are returned from the lookup cache, if enabled.
 func (k *Kademlia) FindNode(ctx context.Context, ID dht.NodeID) (pb.Node, error) {
+	if node, ok := k.lookups.get(ID.String(), time.Now()); ok {
+		mon.Counter("find_node_cache_hits").Inc(1)
+		return node, nil
+	}
+
 	nodes, err := k.dht.FindNode(ID.Bytes())
 	if err != nil {
 		return pb.Node{}, err
@@ -149,16 +157,32 @@ func (k *Kademlia) FindNode(ctx context.Context, ID dht.NodeID) (pb.Node, error)
 
 	for _, v := range nodes {
 		if string(v.ID) == ID.String() {
-			return pb.Node{Id: string(v.ID), Address: &pb.NodeAddress{
+			node := pb.Node{Id: string(v.ID), Address: &pb.NodeAddress{
 				Transport: defaultTransport,
 				Address:   net.JoinHostPort(v.IP.String(), strconv.Itoa(v.Port)),
 			},
-			}, nil
+			}
+			k.remember(&node)
+			k.lookups.add(node, time.Now())
+			return node, nil
 		}
 	}
 	return pb.Node{}, NodeErr.New("node not found")
 }
 
+// remember persists nodes found on the network to the routing table, if
+// there is one, so that they can be restored after a restart
+func (k Kademlia) remember(nodes ...*pb.Node) {
+	if k.routingTable == nil {
+		return
+	}
+	for _, node := range nodes {
+		if err := k.routingTable.ConnectionSuccess(node); err != nil {
+			log.Printf("Failed to persist node %s: %s\n", node.Id, err)
+		}
+	}
+}
+
 // ListenAndServe connects the kademlia node to the network and listens for incoming requests
 func (k *Kademlia) ListenAndServe() error {
 	if err := k.dht.CreateSocket(); err != nil {
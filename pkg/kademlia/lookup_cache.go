@@ -0,0 +1,98 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kademlia
+
+import (
+	"sync"
+	"time"
+
+	"storj.io/storj/pkg/pb"
+)
+
+// maxCachedLookups is how many FindNode results are cached at most
+const maxCachedLookups = 1000
+
+// lookupCache caches the results of recent FindNode lookups, so that a burst
+// of lookups of the same nodes, e.g. by uploads, doesn't repeat identical
+// iterative queries. The nil lookupCache doesn't cache anything.
+type lookupCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	lookups map[string]cachedLookup
+}
+
+type cachedLookup struct {
+	node    pb.Node
+	expires time.Time
+}
+
+// newLookupCache returns a cache keeping results for ttl, or nil if ttl is 0
+func newLookupCache(ttl time.Duration) *lookupCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &lookupCache{ttl: ttl, lookups: map[string]cachedLookup{}}
+}
+
+// get returns the cached node with id, if it didn't expire
+func (cache *lookupCache) get(id string, now time.Time) (pb.Node, bool) {
+	if cache == nil {
+		return pb.Node{}, false
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	lookup, ok := cache.lookups[id]
+	if !ok {
+		return pb.Node{}, false
+	}
+	if !now.Before(lookup.expires) {
+		delete(cache.lookups, id)
+		return pb.Node{}, false
+	}
+	return lookup.node, true
+}
+
+// add caches node, found at now
+func (cache *lookupCache) add(node pb.Node, now time.Time) {
+	if cache == nil {
+		return
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if _, ok := cache.lookups[node.Id]; !ok && len(cache.lookups) >= maxCachedLookups {
+		cache.evict(now)
+	}
+	cache.lookups[node.Id] = cachedLookup{node: node, expires: now.Add(cache.ttl)}
+}
+
+// forget drops the cached node with id, e.g. once it can't be reached at its
+// cached address
+func (cache *lookupCache) forget(id string) {
+	if cache == nil {
+		return
+	}
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	delete(cache.lookups, id)
+}
+
+// evict makes room for a lookup by dropping the expired lookups, or the one
+// expiring first if none expired
+func (cache *lookupCache) evict(now time.Time) {
+	var first string
+	var firstExpires time.Time
+	for id, lookup := range cache.lookups {
+		if !now.Before(lookup.expires) {
+			delete(cache.lookups, id)
+			continue
+		}
+		if first == "" || lookup.expires.Before(firstExpires) {
+			first, firstExpires = id, lookup.expires
+		}
+	}
+	if len(cache.lookups) >= maxCachedLookups {
+		delete(cache.lookups, first)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kademlia
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/pb"
+)
+
+func TestLookupCache(t *testing.T) {
+	var disabled *lookupCache
+	disabled.add(pb.Node{Id: "a"}, time.Now())
+	_, ok := disabled.get("a", time.Now())
+	assert.False(t, ok)
+	assert.Nil(t, newLookupCache(0))
+
+	cache := newLookupCache(time.Minute)
+	now := time.Now()
+	node := pb.Node{Id: "a", Address: &pb.NodeAddress{Address: "127.0.0.1:7777"}}
+	cache.add(node, now)
+
+	cached, ok := cache.get("a", now.Add(time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, node, cached)
+
+	_, ok = cache.get("a", now.Add(time.Minute))
+	assert.False(t, ok)
+	assert.Empty(t, cache.lookups)
+
+	cache.add(node, now)
+	cache.forget("a")
+	_, ok = cache.get("a", now)
+	assert.False(t, ok)
+}
+
+func TestLookupCacheEviction(t *testing.T) {
+	cache := newLookupCache(time.Minute)
+	now := time.Now()
+	for i := 0; i < maxCachedLookups; i++ {
+		cache.add(pb.Node{Id: strconv.Itoa(i)}, now.Add(time.Duration(i)*time.Millisecond))
+	}
+	cache.add(pb.Node{Id: "new"}, now.Add(time.Second))
+	assert.Len(t, cache.lookups, maxCachedLookups)
+
+	// the lookup expiring first is evicted
+	_, ok := cache.get("0", now.Add(time.Second))
+	assert.False(t, ok)
+	_, ok = cache.get("new", now.Add(time.Second))
+	assert.True(t, ok)
+}
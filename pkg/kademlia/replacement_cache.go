@@ -4,13 +4,18 @@
 package kademlia
 
 import (
+	"bytes"
+
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/storage"
 )
 
+// addToReplacementCache caches node as a candidate to replace the nodes of the full k bucket
+// kadBucketID, most recently seen last. A node already cached is moved to the end, and the least
+// recently seen node is dropped once the cache of the bucket is full.
 func (rt *RoutingTable) addToReplacementCache(kadBucketID storage.Key, node *pb.Node) {
 	bucketID := string(kadBucketID)
-	nodes := rt.replacementCache[bucketID]
+	nodes := withoutNode(rt.replacementCache[bucketID], node.Id)
 	nodes = append(nodes, node)
 	if len(nodes) > rt.rcBucketSize {
 		copy(nodes, nodes[1:])
@@ -18,3 +23,51 @@ func (rt *RoutingTable) addToReplacementCache(kadBucketID storage.Key, node *pb.
 	}
 	rt.replacementCache[bucketID] = nodes
 }
+
+// removeFromReplacementCache drops the node nodeID from the cache of the k bucket kadBucketID
+func (rt *RoutingTable) removeFromReplacementCache(kadBucketID storage.Key, nodeID storage.Key) {
+	bucketID := string(kadBucketID)
+	nodes, ok := rt.replacementCache[bucketID]
+	if !ok {
+		return
+	}
+	nodes = withoutNode(nodes, string(nodeID))
+	if len(nodes) == 0 {
+		delete(rt.replacementCache, bucketID)
+		return
+	}
+	rt.replacementCache[bucketID] = nodes
+}
+
+// splitReplacementCache moves the cached nodes of the k bucket kadBucketID that belong to the k
+// bucket newBucketID, split from it, to the cache of newBucketID
+func (rt *RoutingTable) splitReplacementCache(kadBucketID storage.Key, newBucketID storage.Key) {
+	var kept, moved []*pb.Node
+	for _, node := range rt.replacementCache[string(kadBucketID)] {
+		if bytes.Compare([]byte(node.Id), newBucketID) <= 0 {
+			moved = append(moved, node)
+		} else {
+			kept = append(kept, node)
+		}
+	}
+	if len(moved) == 0 {
+		return
+	}
+	if len(kept) == 0 {
+		delete(rt.replacementCache, string(kadBucketID))
+	} else {
+		rt.replacementCache[string(kadBucketID)] = kept
+	}
+	rt.replacementCache[string(newBucketID)] = moved
+}
+
+// withoutNode: helper, returns a copy of nodes without the node id
+func withoutNode(nodes []*pb.Node, id string) []*pb.Node {
+	var others []*pb.Node
+	for _, node := range nodes {
+		if node.Id != id {
+			others = append(others, node)
+		}
+	}
+	return others
+}
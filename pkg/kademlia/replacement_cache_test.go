@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
 )
 
 func TestAddToReplacementCache(t *testing.T) {
@@ -30,3 +31,61 @@ func TestAddToReplacementCache(t *testing.T) {
 	rt.addToReplacementCache(kadBucketID2, node4)
 	assert.Equal(t, []*pb.Node{node3, node4}, rt.replacementCache[string(kadBucketID2)])
 }
+
+func TestAddToReplacementCacheMovesSeenNode(t *testing.T) {
+	rt, cleanup := createRoutingTable(t, []byte{244, 255})
+	defer cleanup()
+	kadBucketID := []byte{255, 255}
+	node1 := mockNode(string([]byte{100, 255}))
+	node2 := mockNode(string([]byte{90, 255}))
+	rt.addToReplacementCache(kadBucketID, node1)
+	rt.addToReplacementCache(kadBucketID, node2)
+	rt.addToReplacementCache(kadBucketID, node1)
+	assert.Equal(t, []*pb.Node{node2, node1}, rt.replacementCache[string(kadBucketID)])
+
+	rt.removeFromReplacementCache(kadBucketID, storage.Key(node2.Id))
+	assert.Equal(t, []*pb.Node{node1}, rt.replacementCache[string(kadBucketID)])
+	rt.removeFromReplacementCache(kadBucketID, storage.Key(node1.Id))
+	assert.Empty(t, rt.replacementCache[string(kadBucketID)])
+}
+
+func TestSplitReplacementCache(t *testing.T) {
+	rt, cleanup := createRoutingTable(t, []byte{244, 255})
+	defer cleanup()
+	kadBucketID := []byte{255, 255}
+	splitBucketID := []byte{127, 255}
+	node1 := mockNode(string([]byte{100, 255}))
+	node2 := mockNode(string([]byte{200, 255}))
+	rt.addToReplacementCache(kadBucketID, node1)
+	rt.addToReplacementCache(kadBucketID, node2)
+
+	rt.splitReplacementCache(kadBucketID, splitBucketID)
+	assert.Equal(t, []*pb.Node{node2}, rt.replacementCache[string(kadBucketID)])
+	assert.Equal(t, []*pb.Node{node1}, rt.replacementCache[string(splitBucketID)])
+}
+
+func TestConnectionFailedPromotesCachedNode(t *testing.T) {
+	rt, cleanup := createRoutingTable(t, []byte("AA"))
+	defer cleanup()
+	kadBucketID := []byte{255, 255}
+	node := mockNode("BB")
+	ok, err := rt.addNode(node)
+	assert.True(t, ok)
+	assert.NoError(t, err)
+	cached := mockNode("CC")
+	failing := mockNode("DD")
+	rt.addToReplacementCache(kadBucketID, cached)
+	rt.addToReplacementCache(kadBucketID, failing)
+
+	// the failed node is dropped from the cache instead of being promoted
+	assert.NoError(t, rt.ConnectionFailed(failing))
+	assert.NoError(t, rt.ConnectionFailed(node))
+
+	_, err = rt.nodeBucketDB.Get(storage.Key(node.Id))
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+	_, err = rt.nodeBucketDB.Get(storage.Key(cached.Id))
+	assert.NoError(t, err)
+	_, err = rt.nodeBucketDB.Get(storage.Key(failing.Id))
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+	assert.Empty(t, rt.replacementCache[string(kadBucketID)])
+}
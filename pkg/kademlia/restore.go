@@ -0,0 +1,109 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kademlia
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"storj.io/storj/pkg/pb"
+)
+
+// Pinger checks that a node is still accessible on the network
+type Pinger func(ctx context.Context, node pb.Node) (pb.Node, error)
+
+// OpenRoutingTable opens the routing table persisted in dir, creating it if
+// it doesn't exist. The k-buckets and nodes of the previous run are restored.
+// The kbuckets.db and nodes.db of older versions aren't, their nodes are found
+// again while bootstrapping.
+func OpenRoutingTable(localNode *pb.Node, dir string) (*RoutingTable, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, RoutingErr.Wrap(err)
+	}
+	return NewRoutingTable(localNode, &RoutingOptions{
+		path:         filepath.Join(dir, "routing.db"),
+		idLength:     len(localNode.Id) * 8,
+		bucketSize:   20,
+		rcBucketSize: 5,
+	})
+}
+
+// Nodes returns all nodes in the routing table except the local node
+func (rt *RoutingTable) Nodes() ([]*pb.Node, error) {
+	nodeIDs, err := rt.nodeBucketDB.List(nil, 0)
+	if err != nil {
+		return nil, RoutingErr.New("could not get node ids %s", err)
+	}
+	ids, serializedNodes, err := rt.getNodesFromIDs(nodeIDs)
+	if err != nil {
+		return nil, RoutingErr.New("could not get nodes %s", err)
+	}
+	nodes, err := unmarshalNodes(ids, serializedNodes)
+	if err != nil {
+		return nil, RoutingErr.New("could not unmarshal nodes %s", err)
+	}
+
+	others := nodes[:0]
+	for _, node := range nodes {
+		if node.Id != rt.self.Id {
+			others = append(others, node)
+		}
+	}
+	return others, nil
+}
+
+// Revalidate pings the nodes of every k-bucket that hasn't been updated for
+// staleAfter, such as the k-buckets restored from a previous run. Nodes that
+// don't respond are removed. It returns the number of removed nodes.
+func (rt *RoutingTable) Revalidate(ctx context.Context, ping Pinger, staleAfter time.Duration) (removed int, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bucketIDs, err := rt.kadBucketDB.List(nil, 0)
+	if err != nil {
+		return 0, RoutingErr.New("could not get bucket ids %s", err)
+	}
+
+	for _, bucketID := range bucketIDs {
+		updated, err := rt.GetBucketTimestamp(string(bucketID), nil)
+		if err != nil {
+			return removed, err
+		}
+		if time.Since(updated) < staleAfter {
+			continue
+		}
+
+		nodes, err := rt.getUnmarshaledNodesFromBucket(bucketID)
+		if err != nil {
+			return removed, err
+		}
+
+		for _, node := range nodes {
+			if node.Id == rt.self.Id {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return removed, err
+			}
+
+			if _, err := ping(ctx, *node); err != nil {
+				if err := rt.ConnectionFailed(node); err != nil {
+					return removed, err
+				}
+				removed++
+				continue
+			}
+			if err := rt.ConnectionSuccess(node); err != nil {
+				return removed, err
+			}
+		}
+
+		if err := rt.SetBucketTimestamp(string(bucketID), time.Now()); err != nil {
+			return removed, err
+		}
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package kademlia
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/pb"
+)
+
+func TestRoutingTableRestore(t *testing.T) {
+	dir, cleanup := tempdir(t)
+	defer cleanup()
+
+	rt, err := OpenRoutingTable(mockNode("AA"), dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, rt.ConnectionSuccess(mockNode("BB")))
+	assert.NoError(t, rt.ConnectionSuccess(mockNode("CC")))
+	assert.NoError(t, rt.Close())
+
+	rt, err = OpenRoutingTable(mockNode("AA"), dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, rt.Close()) }()
+
+	nodes, err := rt.Nodes()
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.Node{mockNode("BB"), mockNode("CC")}, nodes)
+
+	var pinged []string
+	ping := func(ctx context.Context, node pb.Node) (pb.Node, error) {
+		pinged = append(pinged, node.Id)
+		if node.Id == "CC" {
+			return pb.Node{}, errors.New("unreachable")
+		}
+		return node, nil
+	}
+
+	// the buckets were just updated, so nothing is stale
+	removed, err := rt.Revalidate(context.Background(), ping, time.Hour)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, removed)
+	assert.Empty(t, pinged)
+
+	removed, err = rt.Revalidate(context.Background(), ping, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, removed)
+	assert.Equal(t, []string{"BB", "CC"}, pinged)
+
+	nodes, err = rt.Nodes()
+	assert.NoError(t, err)
+	assert.Equal(t, []*pb.Node{mockNode("BB")}, nodes)
+}
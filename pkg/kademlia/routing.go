@@ -14,13 +14,16 @@ import (
 
 	"storj.io/storj/pkg/dht"
 	"storj.io/storj/pkg/pb"
-	"storj.io/storj/pkg/utils"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/boltdb"
+	"storj.io/storj/storage/namespace"
 	"storj.io/storj/storage/storelogger"
 )
 
 const (
+	// RoutingBucket is the bolt bucket of the kademlia routing table, which
+	// holds the k-bucket ids and the node ids in separate namespaces
+	RoutingBucket = "routing"
 	// KademliaBucket is the string representing the bucket used for the kademlia routing table k-bucket ids
 	KademliaBucket = "kbuckets"
 	// NodeBucket is the string representing the bucket used for the kademlia routing table node ids
@@ -33,8 +36,9 @@ var RoutingErr = errs.Class("routing table error")
 // RoutingTable implements the RoutingTable interface
 type RoutingTable struct {
 	self             *pb.Node
-	kadBucketDB      storage.KeyValueStore
-	nodeBucketDB     storage.KeyValueStore
+	db               storage.KeyValueStore
+	kadBucketDB      *namespace.Store
+	nodeBucketDB     *namespace.Store
 	transport        *pb.NodeTransport
 	mutex            *sync.Mutex
 	replacementCache map[string][]*pb.Node
@@ -45,8 +49,7 @@ type RoutingTable struct {
 
 //RoutingOptions for configuring RoutingTable
 type RoutingOptions struct {
-	kpath        string
-	npath        string
+	path         string
 	idLength     int //TODO (JJ): add checks for > 0
 	bucketSize   int
 	rcBucketSize int
@@ -54,20 +57,17 @@ type RoutingOptions struct {
 
 // NewRoutingTable returns a newly configured instance of a RoutingTable
 func NewRoutingTable(localNode *pb.Node, options *RoutingOptions) (*RoutingTable, error) {
-	kdb, err := boltdb.New(options.kpath, KademliaBucket)
+	bdb, err := boltdb.New(options.path, RoutingBucket)
 	if err != nil {
-		return nil, RoutingErr.New("could not create kadBucketDB: %s", err)
-	}
-
-	ndb, err := boltdb.New(options.npath, NodeBucket)
-	if err != nil {
-		return nil, RoutingErr.New("could not create nodeBucketDB: %s", err)
+		return nil, RoutingErr.New("could not create routing table db: %s", err)
 	}
+	db := storelogger.New(zap.L(), bdb)
 	rp := make(map[string][]*pb.Node)
 	rt := &RoutingTable{
 		self:             localNode,
-		kadBucketDB:      storelogger.New(zap.L(), kdb),
-		nodeBucketDB:     storelogger.New(zap.L(), ndb),
+		db:               db,
+		kadBucketDB:      namespace.New(db, KademliaBucket+"/"),
+		nodeBucketDB:     namespace.New(db, NodeBucket+"/"),
 		transport:        &defaultTransport,
 		mutex:            &sync.Mutex{},
 		replacementCache: rp,
@@ -82,11 +82,9 @@ func NewRoutingTable(localNode *pb.Node, options *RoutingOptions) (*RoutingTable
 	return rt, nil
 }
 
-// Close closes underlying databases
+// Close closes underlying database
 func (rt *RoutingTable) Close() error {
-	kerr := rt.kadBucketDB.Close()
-	nerr := rt.nodeBucketDB.Close()
-	return utils.CombineErrors(kerr, nerr)
+	return rt.db.Close()
 }
 
 // Local returns the local nodes ID
@@ -199,13 +197,17 @@ func (rt *RoutingTable) ConnectionSuccess(node *pb.Node) error {
 }
 
 // ConnectionFailed removes a node from the routing table when
-// a connection fails for the node on the network
+// a connection fails for the node on the network. The node is replaced
+// with the most recently seen node of the replacement cache of its k bucket.
 func (rt *RoutingTable) ConnectionFailed(node *pb.Node) error {
+	rt.mutex.Lock()
+	defer rt.mutex.Unlock()
 	nodeID := storage.Key(node.Id)
 	bucketID, err := rt.getKBucketID(nodeID)
 	if err != nil {
 		return RoutingErr.New("could not get k bucket %s", err)
 	}
+	rt.removeFromReplacementCache(bucketID, nodeID)
 	err = rt.removeNode(bucketID, nodeID)
 	if err != nil {
 		return RoutingErr.New("could not remove node %s", err)
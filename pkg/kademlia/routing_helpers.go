@@ -23,17 +23,21 @@ func (rt *RoutingTable) addNode(node *pb.Node) (bool, error) {
 	defer rt.mutex.Unlock()
 	nodeKey := storage.Key(node.Id)
 	if bytes.Equal(nodeKey, storage.Key(rt.self.Id)) {
-		err := rt.createOrUpdateKBucket(rt.createFirstBucketID(), time.Now())
-		if err != nil {
-			return false, RoutingErr.New("could not create initial K bucket: %s", err)
-		}
 		nodeValue, err := marshalNode(*node)
 		if err != nil {
 			return false, RoutingErr.New("could not marshal initial node: %s", err)
 		}
-		err = rt.putNode(nodeKey, nodeValue)
+		err = rt.update(func(kbuckets, nodes storage.Transaction) error {
+			if err := kbuckets.Put(rt.createFirstBucketID(), kBucketValue(time.Now())); err != nil {
+				return RoutingErr.New("could not create initial K bucket: %s", err)
+			}
+			if err := nodes.Put(nodeKey, nodeValue); err != nil {
+				return RoutingErr.New("could not add initial node to nodeBucketDB: %s", err)
+			}
+			return nil
+		})
 		if err != nil {
-			return false, RoutingErr.New("could not add initial node to nodeBucketDB: %s", err)
+			return false, err
 		}
 		return true, nil
 	}
@@ -60,11 +64,17 @@ func (rt *RoutingTable) addNode(node *pb.Node) (bool, error) {
 			if err != nil {
 				return false, RoutingErr.New("could not determine leaf depth: %s", err)
 			}
-			kadBucketID = rt.splitBucket(kadBucketID, depth)
-			err = rt.createOrUpdateKBucket(kadBucketID, time.Now())
+			if depth >= rt.idLength {
+				// the bucket covers a single id and can't be split further
+				rt.addToReplacementCache(kadBucketID, node)
+				return false, nil
+			}
+			splitBucketID := rt.splitBucket(kadBucketID, depth)
+			err = rt.createOrUpdateKBucket(splitBucketID, time.Now())
 			if err != nil {
 				return false, RoutingErr.New("could not split and create K bucket: %s", err)
 			}
+			rt.splitReplacementCache(kadBucketID, splitBucketID)
 			kadBucketID, err = rt.getKBucketID(nodeKey)
 			if err != nil {
 				return false, RoutingErr.New("could not get k bucket Id within add node split bucket checks: %s", err)
@@ -87,14 +97,19 @@ func (rt *RoutingTable) addNode(node *pb.Node) (bool, error) {
 	if err != nil {
 		return false, RoutingErr.New("could not marshal node: %s", err)
 	}
-	err = rt.putNode(nodeKey, nodeValue)
-	if err != nil {
-		return false, RoutingErr.New("could not add node to nodeBucketDB: %s", err)
-	}
-	err = rt.createOrUpdateKBucket(kadBucketID, time.Now())
+	err = rt.update(func(kbuckets, nodes storage.Transaction) error {
+		if err := nodes.Put(nodeKey, nodeValue); err != nil {
+			return RoutingErr.New("could not add node to nodeBucketDB: %s", err)
+		}
+		if err := kbuckets.Put(kadBucketID, kBucketValue(time.Now())); err != nil {
+			return RoutingErr.New("could not create or update K bucket: %s", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return false, RoutingErr.New("could not create or update K bucket: %s", err)
+		return false, err
 	}
+	rt.removeFromReplacementCache(kadBucketID, nodeKey)
 	return true, nil
 }
 
@@ -113,34 +128,57 @@ func (rt *RoutingTable) updateNode(node *pb.Node) error {
 }
 
 // removeNode will remove churned nodes and replace those entries with nodes from the replacement cache.
+// The node is removed and replaced in one transaction.
 func (rt *RoutingTable) removeNode(kadBucketID storage.Key, nodeID storage.Key) error {
-	_, err := rt.nodeBucketDB.Get(nodeID)
-	if storage.ErrKeyNotFound.Has(err) {
-		return nil
-	} else if err != nil {
-		return RoutingErr.New("could not get node %s", err)
-	}
-	err = rt.nodeBucketDB.Delete(nodeID)
-	if err != nil {
-		return RoutingErr.New("could not delete node %s", err)
-	}
-	nodes := rt.replacementCache[string(kadBucketID)]
-	if len(nodes) == 0 {
+	cached := rt.replacementCache[string(kadBucketID)]
+	var replacement *pb.Node
+	if len(cached) > 0 {
+		replacement = cached[len(cached)-1]
+	}
+	replaced := false
+	err := rt.update(func(_, nodes storage.Transaction) error {
+		replaced = false
+		_, err := nodes.Get(nodeID)
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil
+		} else if err != nil {
+			return RoutingErr.New("could not get node %s", err)
+		}
+		err = nodes.Delete(nodeID)
+		if err != nil {
+			return RoutingErr.New("could not delete node %s", err)
+		}
+		if replacement == nil {
+			return nil
+		}
+		val, err := marshalNode(*replacement)
+		if err != nil {
+			return err
+		}
+		err = nodes.Put(storage.Key(replacement.Id), val)
+		if err != nil {
+			return RoutingErr.New("could not add key value pair to nodeBucketDB: %s", err)
+		}
+		replaced = true
 		return nil
-	}
-	last := nodes[len(nodes)-1]
-	val, err := marshalNode(*last)
+	})
 	if err != nil {
 		return err
 	}
-	err = rt.putNode(storage.Key(last.Id), val)
-	if err != nil {
-		return err
+	if replaced {
+		rt.replacementCache[string(kadBucketID)] = cached[:len(cached)-1]
 	}
-	rt.replacementCache[string(kadBucketID)] = nodes[:len(nodes)-1]
 	return nil
 }
 
+// update: helper, calls fn with the k buckets and the nodes of the routing table in one transaction,
+// so that updates of both stay consistent
+func (rt *RoutingTable) update(fn func(kbuckets, nodes storage.Transaction) error) error {
+	return rt.db.Update(func(tx storage.Transaction) error {
+		return fn(rt.kadBucketDB.Scope(tx), rt.nodeBucketDB.Scope(tx))
+	})
+}
+
 // marshalNode: helper, sanitizes Node for db insertion
 func marshalNode(node pb.Node) ([]byte, error) {
 	node.Id = "-"
@@ -162,15 +200,20 @@ func (rt *RoutingTable) putNode(nodeKey storage.Key, nodeValue storage.Value) er
 
 // createOrUpdateKBucket: helper, adds or updates given kbucket
 func (rt *RoutingTable) createOrUpdateKBucket(bucketID storage.Key, now time.Time) error {
-	dateTime := make([]byte, binary.MaxVarintLen64)
-	binary.PutVarint(dateTime, now.UnixNano())
-	err := rt.kadBucketDB.Put(bucketID, dateTime)
+	err := rt.kadBucketDB.Put(bucketID, kBucketValue(now))
 	if err != nil {
 		return RoutingErr.New("could not add or update k bucket: %s", err)
 	}
 	return nil
 }
 
+// kBucketValue: helper, returns the stored value of a k bucket last updated at now
+func kBucketValue(now time.Time) storage.Value {
+	dateTime := make([]byte, binary.MaxVarintLen64)
+	binary.PutVarint(dateTime, now.UnixNano())
+	return dateTime
+}
+
 // getKBucketID: helper, returns the id of the corresponding k bucket given a node id.
 // The node doesn't have to be in the routing table at time of search
 func (rt *RoutingTable) getKBucketID(nodeID storage.Key) (storage.Key, error) {
@@ -38,8 +38,7 @@ func createRoutingTable(t *testing.T, localNodeID []byte) (*RoutingTable, func()
 	}
 	localNode := &pb.Node{Id: string(localNodeID)}
 	options := &RoutingOptions{
-		kpath:        filepath.Join(tempdir, "Kadbucket"),
-		npath:        filepath.Join(tempdir, "Nodebucket"),
+		path:         filepath.Join(tempdir, "Routing"),
 		idLength:     16,
 		bucketSize:   6,
 		rcBucketSize: 2,
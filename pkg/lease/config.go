@@ -0,0 +1,90 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package lease
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/storage/redis"
+)
+
+// CtxKey Used as lease key
+type CtxKey int
+
+const (
+	ctxKeyLeaser CtxKey = iota
+)
+
+// ChoresLease is the name of the lease of the satellite instance running the
+// chores
+const ChoresLease = "satellite-chores"
+
+// Config is a configuration struct that is everything you need to start a
+// lease responsibility
+type Config struct {
+	URL string        `help:"the redis URL of the leases shared by the satellite instances, so only one of them runs the chores. if empty, this instance runs them" default:""`
+	TTL time.Duration `help:"how long the chores lease lasts without being renewed" default:"30s"`
+}
+
+// Run implements the provider.Responsibility interface
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if c.URL == "" {
+		return server.Run(ctx)
+	}
+
+	client, err := redis.NewClientFrom(c.URL)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	// the instances may share an identity, so the owner is unique per process
+	owner, err := newOwner()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	leaser := NewLeaser(zap.L().Named("lease"), client, ChoresLease, owner, c.TTL)
+	go func() {
+		if err := leaser.Run(ctx); err != nil && err != context.Canceled {
+			zap.S().Error("Error holding lease: ", err)
+		}
+	}()
+
+	return server.Run(context.WithValue(ctx, ctxKeyLeaser, leaser))
+}
+
+// newOwner returns a random owner name starting with the hostname
+func newOwner() (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", Error.Wrap(err)
+	}
+	return hostname + "-" + hex.EncodeToString(id), nil
+}
+
+// LoadFromContext loads an existing Leaser from the Provider context stack if
+// one exists.
+func LoadFromContext(ctx context.Context) *Leaser {
+	if v, ok := ctx.Value(ctxKeyLeaser).(*Leaser); ok {
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package lease
+
+import (
+	"sync"
+	"time"
+)
+
+// Store grants leases, which are locks that expire unless they are renewed
+type Store interface {
+	// AcquireLease acquires the lease name for owner for ttl, or renews it if
+	// owner already holds it. It returns false if another owner holds it.
+	AcquireLease(name, owner string, ttl time.Duration) (bool, error)
+	// ReleaseLease releases the lease name if owner holds it
+	ReleaseLease(name, owner string) error
+}
+
+// MemoryStore is a Store for the instances of a single process
+type MemoryStore struct {
+	mu     sync.Mutex
+	leases map[string]memoryLease
+}
+
+type memoryLease struct {
+	owner   string
+	expires time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{leases: map[string]memoryLease{}}
+}
+
+// AcquireLease implements Store
+func (store *MemoryStore) AcquireLease(name, owner string, ttl time.Duration) (bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	now := time.Now()
+	if lease, ok := store.leases[name]; ok && lease.owner != owner && now.Before(lease.expires) {
+		return false, nil
+	}
+	store.leases[name] = memoryLease{owner: owner, expires: now.Add(ttl)}
+	return true, nil
+}
+
+// ReleaseLease implements Store
+func (store *MemoryStore) ReleaseLease(name, owner string) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if store.leases[name].owner == owner {
+		delete(store.leases, name)
+	}
+	return nil
+}
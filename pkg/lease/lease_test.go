@@ -0,0 +1,100 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package lease
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestMemoryStore(t *testing.T) {
+	store := NewMemoryStore()
+
+	acquired, err := store.AcquireLease("chores", "a", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+	acquired, err = store.AcquireLease("chores", "b", time.Hour)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	assert.NoError(t, store.ReleaseLease("chores", "a"))
+	acquired, err = store.AcquireLease("chores", "b", time.Nanosecond)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	// expired leases can be taken over
+	time.Sleep(time.Millisecond)
+	acquired, err = store.AcquireLease("chores", "a", time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestLeaser(t *testing.T) {
+	store := NewMemoryStore()
+	ttl := 30 * time.Millisecond
+	a := NewLeaser(zap.NewNop(), store, ChoresLease, "a", ttl)
+	b := NewLeaser(zap.NewNop(), store, ChoresLease, "b", ttl)
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	doneA := make(chan error, 1)
+	go func() { doneA <- a.Run(ctxA) }()
+	waitFor(t, a.Holding)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() { _ = b.Run(ctx) }()
+	time.Sleep(2 * ttl)
+	assert.False(t, b.Holding())
+
+	ran := false
+	assert.NoError(t, b.RunChore(ctx, func(ctx context.Context) error {
+		ran = true
+		return nil
+	}))
+	assert.False(t, ran)
+
+	// a chore is canceled when its leaser stops holding the lease
+	started := make(chan struct{})
+	chore := make(chan error, 1)
+	go func() {
+		chore <- a.RunChore(ctx, func(ctx context.Context) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+	<-started
+	cancelA()
+	assert.Equal(t, context.Canceled, <-chore)
+	assert.Equal(t, context.Canceled, <-doneA)
+
+	// a released the lease, so b takes over
+	waitFor(t, b.Holding)
+	assert.NoError(t, b.RunChore(ctx, func(ctx context.Context) error {
+		ran = true
+		return nil
+	}))
+	assert.True(t, ran)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+	}
+}
+
+func TestRunChoreWithoutLeaser(t *testing.T) {
+	ran := false
+	assert.NoError(t, RunChore(context.Background(), func(ctx context.Context) error {
+		ran = true
+		return nil
+	}))
+	assert.True(t, ran)
+}
@@ -0,0 +1,127 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package lease
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Leaser holds a lease for as long as it can, so that of several satellite
+// instances sharing a Store only the one holding the lease runs the chores
+type Leaser struct {
+	log   *zap.Logger
+	store Store
+	name  string
+	owner string
+	ttl   time.Duration
+
+	mu sync.Mutex
+	// term is canceled when the leaser stops holding the lease. It is nil
+	// while the lease is held by someone else.
+	term       context.Context
+	cancelTerm func()
+}
+
+// NewLeaser creates a leaser that tries to hold the lease name in store as
+// owner, renewing it before ttl runs out
+func NewLeaser(log *zap.Logger, store Store, name, owner string, ttl time.Duration) *Leaser {
+	return &Leaser{log: log, store: store, name: name, owner: owner, ttl: ttl}
+}
+
+// Run acquires and renews the lease until ctx is canceled, and releases it
+// then
+func (leaser *Leaser) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ticker := time.NewTicker(leaser.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		leaser.renew(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			leaser.stepDown()
+			if err := leaser.store.ReleaseLease(leaser.name, leaser.owner); err != nil {
+				leaser.log.Warn("err releasing lease", zap.Error(err))
+			}
+			return ctx.Err()
+		}
+	}
+}
+
+// renew acquires or renews the lease, and starts or ends the term
+// accordingly
+func (leaser *Leaser) renew(ctx context.Context) {
+	start := time.Now()
+	acquired, err := leaser.store.AcquireLease(leaser.name, leaser.owner, leaser.ttl)
+	if err != nil {
+		leaser.log.Warn("err acquiring lease", zap.Error(err))
+	}
+	// a renewal that took too long may have let the lease expire meanwhile
+	if err != nil || !acquired || time.Since(start) > leaser.ttl/3 {
+		leaser.stepDown()
+		return
+	}
+
+	leaser.mu.Lock()
+	defer leaser.mu.Unlock()
+	if leaser.term == nil {
+		leaser.term, leaser.cancelTerm = context.WithCancel(ctx)
+		leaser.log.Info("holding lease", zap.String("name", leaser.name))
+	}
+}
+
+// stepDown ends the term, if any
+func (leaser *Leaser) stepDown() {
+	leaser.mu.Lock()
+	defer leaser.mu.Unlock()
+	if leaser.term != nil {
+		leaser.cancelTerm()
+		leaser.term, leaser.cancelTerm = nil, nil
+		leaser.log.Info("lost lease", zap.String("name", leaser.name))
+	}
+}
+
+// Holding returns whether the leaser holds the lease
+func (leaser *Leaser) Holding() bool {
+	leaser.mu.Lock()
+	defer leaser.mu.Unlock()
+	return leaser.term != nil
+}
+
+// RunChore runs fn if the leaser holds the lease, canceling the context of
+// fn if the lease is lost meanwhile. Otherwise it returns nil right away.
+func (leaser *Leaser) RunChore(ctx context.Context, fn func(ctx context.Context) error) error {
+	leaser.mu.Lock()
+	term := leaser.term
+	leaser.mu.Unlock()
+	if term == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-term.Done():
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return fn(ctx)
+}
+
+// RunChore runs fn with the leaser in ctx. Without a leaser, the instance is
+// the only one and always runs fn.
+func RunChore(ctx context.Context, fn func(ctx context.Context) error) error {
+	if leaser := LoadFromContext(ctx); leaser != nil {
+		return leaser.RunChore(ctx, fn)
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,193 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	base58 "github.com/jbenet/go-base58"
+
+	"storj.io/storj/pkg/pb"
+)
+
+// ActionType is the kind of request an API key is used for
+type ActionType int
+
+const (
+	// ActionRead is downloading objects and their metadata
+	ActionRead ActionType = iota + 1
+	// ActionWrite is uploading objects
+	ActionWrite
+	// ActionList is listing objects and buckets
+	ActionList
+	// ActionDelete is deleting objects
+	ActionDelete
+)
+
+// Action is a request an API key is used for
+type Action struct {
+	Op     ActionType
+	Bucket []byte
+	// Path is the path within the bucket, or the prefix for lists
+	Path []byte
+	Time time.Time
+}
+
+// APIKey is an API key backed by a macaroon whose caveats are pb.Caveats
+type APIKey struct {
+	mac *Macaroon
+}
+
+// NewAPIKey creates an API key without restrictions derived from secret
+func NewAPIKey(secret []byte) (*APIKey, error) {
+	mac, err := NewUnrestricted(secret)
+	if err != nil {
+		return nil, err
+	}
+	return &APIKey{mac: mac}, nil
+}
+
+// ParseAPIKey parses a serialized API key
+func ParseAPIKey(key string) (*APIKey, error) {
+	data := base58.Decode(key)
+	if len(data) == 0 {
+		return nil, Error.New("invalid API key encoding")
+	}
+	mac, err := ParseMacaroon(data)
+	if err != nil {
+		return nil, err
+	}
+	return &APIKey{mac: mac}, nil
+}
+
+// Serialize returns the string representation of the key
+func (key *APIKey) Serialize() string {
+	return base58.Encode(key.mac.Serialize())
+}
+
+// Head returns the head of the macaroon of the key, which identifies the
+// root key it was derived from
+func (key *APIKey) Head() []byte { return key.mac.Head() }
+
+// Tail returns the tail of the macaroon of the key
+func (key *APIKey) Tail() []byte { return key.mac.Tail() }
+
+// ProjectID identifies the project of a serialized API key. API keys
+// restricted from the same root key share its project, other keys are a
+// project of their own.
+func ProjectID(key []byte) string {
+	hash := sha256.Sum256(key)
+	if apiKey, err := ParseAPIKey(string(key)); err == nil {
+		hash = sha256.Sum256(apiKey.Head())
+	}
+	return hex.EncodeToString(hash[:8])
+}
+
+// Validate checks that the key was derived from secret, ignoring its
+// caveats
+func (key *APIKey) Validate(secret []byte) bool { return key.mac.Validate(secret) }
+
+// Tails returns the tails of the key and of every key it was derived from,
+// so revoking a key also revokes the keys restricted from it
+func (key *APIKey) Tails(secret []byte) [][]byte { return key.mac.Tails(secret) }
+
+// Restrict returns a copy of the key further restricted by caveat. It
+// doesn't need the root secret, so keys can be restricted offline.
+func (key *APIKey) Restrict(caveat pb.Caveat) (*APIKey, error) {
+	if len(caveat.Nonce) == 0 {
+		caveat.Nonce = make([]byte, 8)
+		if _, err := rand.Read(caveat.Nonce); err != nil {
+			return nil, Error.Wrap(err)
+		}
+	}
+	data, err := proto.Marshal(&caveat)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &APIKey{mac: key.mac.AddFirstPartyCaveat(data)}, nil
+}
+
+// Caveats returns the caveats of the key
+func (key *APIKey) Caveats() (caveats []*pb.Caveat, err error) {
+	for _, data := range key.mac.Caveats() {
+		caveat := &pb.Caveat{}
+		if err := proto.Unmarshal(data, caveat); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		caveats = append(caveats, caveat)
+	}
+	return caveats, nil
+}
+
+// Check checks that the key was derived from secret and that its caveats
+// allow action
+func (key *APIKey) Check(secret []byte, action Action) error {
+	if !key.mac.Validate(secret) {
+		return ErrUnauthorized.New("invalid API key")
+	}
+
+	caveats, err := key.Caveats()
+	if err != nil {
+		return ErrUnauthorized.Wrap(err)
+	}
+	for _, caveat := range caveats {
+		if !allows(caveat, action) {
+			return ErrUnauthorized.New("action not allowed by API key")
+		}
+	}
+	return nil
+}
+
+// allows checks whether caveat allows action
+func allows(caveat *pb.Caveat, action Action) bool {
+	switch action.Op {
+	case ActionRead:
+		if caveat.DisallowReads {
+			return false
+		}
+	case ActionWrite:
+		if caveat.DisallowWrites {
+			return false
+		}
+	case ActionList:
+		if caveat.DisallowLists {
+			return false
+		}
+	case ActionDelete:
+		if caveat.DisallowDeletes {
+			return false
+		}
+	default:
+		return false
+	}
+
+	if caveat.NotAfter != nil {
+		notAfter, err := ptypes.Timestamp(caveat.NotAfter)
+		if err != nil || action.Time.After(notAfter) {
+			return false
+		}
+	}
+	if caveat.NotBefore != nil {
+		notBefore, err := ptypes.Timestamp(caveat.NotBefore)
+		if err != nil || action.Time.Before(notBefore) {
+			return false
+		}
+	}
+
+	if len(caveat.AllowedPaths) == 0 {
+		return true
+	}
+	for _, path := range caveat.AllowedPaths {
+		if bytes.Equal(path.Bucket, action.Bucket) && bytes.HasPrefix(action.Path, path.PathPrefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/pb"
+)
+
+func TestMacaroon(t *testing.T) {
+	secret, err := NewSecret()
+	if !assert.NoError(t, err) {
+		return
+	}
+	m, err := NewUnrestricted(secret)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, m.Validate(secret))
+
+	restricted := m.AddFirstPartyCaveat([]byte("caveat"))
+	assert.True(t, restricted.Validate(secret))
+	assert.Len(t, m.Caveats(), 0)
+	assert.NotEqual(t, m.Tail(), restricted.Tail())
+	assert.Equal(t, [][]byte{m.Tail(), restricted.Tail()}, restricted.Tails(secret))
+
+	parsed, err := ParseMacaroon(restricted.Serialize())
+	if assert.NoError(t, err) {
+		assert.Equal(t, restricted, parsed)
+	}
+
+	// caveats can't be removed or changed
+	tampered := &Macaroon{head: restricted.head, caveats: [][]byte{[]byte("other")}, tail: restricted.tail}
+	assert.False(t, tampered.Validate(secret))
+	stripped := &Macaroon{head: restricted.head, tail: restricted.tail}
+	assert.False(t, stripped.Validate(secret))
+
+	_, err = ParseMacaroon(restricted.Serialize()[:10])
+	assert.Error(t, err)
+}
+
+func TestAPIKey(t *testing.T) {
+	secret, err := NewSecret()
+	if !assert.NoError(t, err) {
+		return
+	}
+	root, err := NewAPIKey(secret)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	now := time.Now()
+	notAfter, err := ptypes.TimestampProto(now.Add(time.Hour))
+	if !assert.NoError(t, err) {
+		return
+	}
+	key, err := root.Restrict(pb.Caveat{
+		DisallowWrites: true,
+		AllowedPaths: []*pb.Caveat_Path{
+			{Bucket: []byte("photos"), PathPrefix: []byte("2018/")},
+			{Bucket: []byte("music")},
+		},
+		NotAfter: notAfter,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	parsed, err := ParseAPIKey(key.Serialize())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, root.Head(), parsed.Head())
+
+	for _, test := range []struct {
+		action  Action
+		allowed bool
+	}{
+		{Action{Op: ActionRead, Bucket: []byte("photos"), Path: []byte("2018/a"), Time: now}, true},
+		{Action{Op: ActionList, Bucket: []byte("photos"), Path: []byte("2018/"), Time: now}, true},
+		{Action{Op: ActionRead, Bucket: []byte("music"), Path: []byte("song"), Time: now}, true},
+		{Action{Op: ActionWrite, Bucket: []byte("photos"), Path: []byte("2018/a"), Time: now}, false},
+		{Action{Op: ActionRead, Bucket: []byte("photos"), Path: []byte("2017/a"), Time: now}, false},
+		{Action{Op: ActionList, Bucket: []byte("photos"), Path: []byte(""), Time: now}, false},
+		{Action{Op: ActionRead, Bucket: []byte("photos"), Path: []byte("2018/a"), Time: now.Add(2 * time.Hour)}, false},
+	} {
+		err := parsed.Check(secret, test.action)
+		if test.allowed {
+			assert.NoError(t, err, "%+v", test.action)
+		} else {
+			assert.True(t, ErrUnauthorized.Has(err), "%+v", test.action)
+		}
+	}
+
+	// root keys allow everything, but only with the right secret
+	assert.NoError(t, root.Check(secret, Action{Op: ActionWrite, Bucket: []byte("other"), Time: now}))
+	other, err := NewSecret()
+	if assert.NoError(t, err) {
+		assert.True(t, ErrUnauthorized.Has(root.Check(other, Action{Op: ActionRead, Time: now})))
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"github.com/zeebo/errs"
+)
+
+var (
+	// Error is the default macaroon errs class
+	Error = errs.Class("macaroon error")
+	// ErrUnauthorized is returned for keys that don't allow an action
+	ErrUnauthorized = errs.Class("unauthorized")
+)
@@ -0,0 +1,155 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package macaroon
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+)
+
+// Macaroon is a chain of first party caveats authenticated by a tail that
+// only the holder of the root secret can verify. Anyone holding a macaroon
+// can add caveats to it, but nobody can remove them.
+type Macaroon struct {
+	head    []byte
+	caveats [][]byte
+	tail    []byte
+}
+
+// NewSecret creates a new random root secret
+func NewSecret() ([]byte, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return secret, nil
+}
+
+// NewUnrestricted creates a macaroon without caveats with a random head
+func NewUnrestricted(secret []byte) (*Macaroon, error) {
+	head := make([]byte, 32)
+	if _, err := rand.Read(head); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &Macaroon{head: head, tail: sign(secret, head)}, nil
+}
+
+// AddFirstPartyCaveat returns a copy of m with caveat added
+func (m *Macaroon) AddFirstPartyCaveat(caveat []byte) *Macaroon {
+	caveats := append([][]byte{}, m.caveats...)
+	caveats = append(caveats, append([]byte{}, caveat...))
+	return &Macaroon{head: m.head, caveats: caveats, tail: sign(m.tail, caveat)}
+}
+
+// Validate checks that the tail of m was derived from secret
+func (m *Macaroon) Validate(secret []byte) bool {
+	tail := sign(secret, m.head)
+	for _, caveat := range m.caveats {
+		tail = sign(tail, caveat)
+	}
+	return hmac.Equal(tail, m.tail)
+}
+
+// Tails returns the tails of m and of every macaroon m was derived from,
+// starting with the unrestricted one, as computed from secret
+func (m *Macaroon) Tails(secret []byte) [][]byte {
+	tails := make([][]byte, 0, len(m.caveats)+1)
+	tail := sign(secret, m.head)
+	tails = append(tails, tail)
+	for _, caveat := range m.caveats {
+		tail = sign(tail, caveat)
+		tails = append(tails, tail)
+	}
+	return tails
+}
+
+// Head returns the head of m, which identifies the root key m derives from
+func (m *Macaroon) Head() []byte { return append([]byte{}, m.head...) }
+
+// Caveats returns the caveats of m
+func (m *Macaroon) Caveats() [][]byte {
+	caveats := make([][]byte, 0, len(m.caveats))
+	for _, caveat := range m.caveats {
+		caveats = append(caveats, append([]byte{}, caveat...))
+	}
+	return caveats
+}
+
+// Tail returns the tail of m, which identifies m and every key derived from
+// it
+func (m *Macaroon) Tail() []byte { return append([]byte{}, m.tail...) }
+
+// Serialize returns the binary representation of m: the version, the
+// length prefixed head, the number of caveats, the length prefixed caveats
+// and the tail
+func (m *Macaroon) Serialize() []byte {
+	var data []byte
+	data = append(data, version)
+	data = appendBytes(data, m.head)
+	data = appendUvarint(data, uint64(len(m.caveats)))
+	for _, caveat := range m.caveats {
+		data = appendBytes(data, caveat)
+	}
+	return appendBytes(data, m.tail)
+}
+
+// ParseMacaroon parses the binary representation of a macaroon
+func ParseMacaroon(data []byte) (_ *Macaroon, err error) {
+	if len(data) == 0 || data[0] != version {
+		return nil, Error.New("invalid macaroon version")
+	}
+	data = data[1:]
+
+	m := &Macaroon{}
+	if m.head, data, err = readBytes(data); err != nil {
+		return nil, err
+	}
+	count, n := binary.Uvarint(data)
+	if n <= 0 || count > uint64(len(data)) {
+		return nil, Error.New("invalid caveat count")
+	}
+	data = data[n:]
+	for i := uint64(0); i < count; i++ {
+		var caveat []byte
+		if caveat, data, err = readBytes(data); err != nil {
+			return nil, err
+		}
+		m.caveats = append(m.caveats, caveat)
+	}
+	if m.tail, data, err = readBytes(data); err != nil {
+		return nil, err
+	}
+	if len(data) != 0 {
+		return nil, Error.New("trailing data")
+	}
+	return m, nil
+}
+
+const version = 1
+
+func sign(secret, data []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	_, _ = mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func appendUvarint(data []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(data, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendBytes(data, b []byte) []byte {
+	return append(appendUvarint(data, uint64(len(b))), b...)
+}
+
+func readBytes(data []byte) (b, rest []byte, err error) {
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size > uint64(len(data)-n) {
+		return nil, nil, Error.New("invalid length")
+	}
+	data = data[n:]
+	return append([]byte{}, data[:size]...), data[size:], nil
+}
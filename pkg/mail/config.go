@@ -0,0 +1,45 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package mail
+
+import (
+	"net"
+	"net/smtp"
+
+	"go.uber.org/zap"
+)
+
+// Config configures how emails are sent
+type Config struct {
+	Sender       string `help:"how emails are sent: log, smtp or ses" default:"log"`
+	From         string `help:"address emails are sent from" default:"Storj <noreply@storj.io>"`
+	SMTPAddress  string `help:"host:port of the smtp server" default:"localhost:25"`
+	SMTPUsername string `help:"username for the smtp server, no authentication if empty" default:""`
+	SMTPPassword string `help:"password for the smtp server" default:""`
+	SESRegion    string `help:"aws region of the ses sender" default:"us-east-1"`
+}
+
+// NewService creates a mail service with the configured sender
+func (c Config) NewService(log *zap.Logger) (*Service, error) {
+	var sender Sender
+	switch c.Sender {
+	case "log":
+		sender = NewLogSender(log)
+	case "smtp":
+		smtpSender := &SMTPSender{Address: c.SMTPAddress}
+		if c.SMTPUsername != "" {
+			host, _, err := net.SplitHostPort(c.SMTPAddress)
+			if err != nil {
+				return nil, Error.Wrap(err)
+			}
+			smtpSender.Auth = smtp.PlainAuth("", c.SMTPUsername, c.SMTPPassword, host)
+		}
+		sender = smtpSender
+	case "ses":
+		sender = &SESSender{Region: c.SESRegion}
+	default:
+		return nil, Error.New("unknown sender %q", c.Sender)
+	}
+	return NewService(sender, c.From), nil
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Message is an email message
+type Message struct {
+	From    string
+	To      []string
+	Subject string
+	Body    string
+}
+
+// Bytes returns the message formatted for delivery over SMTP
+func (msg *Message) Bytes() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", msg.From)
+	fmt.Fprintf(&buf, "To: %s\r\n", strings.Join(msg.To, ", "))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.Replace(msg.Body, "\n", "\r\n", -1))
+	return buf.Bytes()
+}
+
+// Sender delivers email messages
+type Sender interface {
+	SendEmail(ctx context.Context, msg *Message) error
+}
+
+// Service renders email templates and sends them with a Sender
+type Service struct {
+	sender Sender
+	from   string
+}
+
+// NewService creates a Service that sends emails from the address from
+// using sender
+func NewService(sender Sender, from string) *Service {
+	return &Service{sender: sender, from: from}
+}
+
+// Send renders tmpl with data and sends the result to the address to
+func (s *Service) Send(ctx context.Context, to string, tmpl *Template, data interface{}) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	subject, body, err := tmpl.Render(data)
+	if err != nil {
+		return err
+	}
+
+	return s.sender.SendEmail(ctx, &Message{
+		From:    s.from,
+		To:      []string{to},
+		Subject: subject,
+		Body:    body,
+	})
+}
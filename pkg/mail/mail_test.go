@@ -0,0 +1,43 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package mail
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recorder struct {
+	messages []*Message
+}
+
+func (r *recorder) SendEmail(ctx context.Context, msg *Message) error {
+	r.messages = append(r.messages, msg)
+	return nil
+}
+
+func TestSend(t *testing.T) {
+	ctx := context.Background()
+	sender := &recorder{}
+	service := NewService(sender, "noreply@example.com")
+
+	err := service.Send(ctx, "alice@example.com", Invitation, InvitationData{
+		ProjectName: "photos\r\nBcc: mallory@example.com",
+		Role:        "admin",
+		Link:        "https://example.com/invitation/token",
+	})
+	assert.NoError(t, err)
+	if assert.Len(t, sender.messages, 1) {
+		msg := sender.messages[0]
+		assert.Equal(t, "noreply@example.com", msg.From)
+		assert.Equal(t, []string{"alice@example.com"}, msg.To)
+		assert.Equal(t, "You have been invited to photos Bcc: mallory@example.com on Storj", msg.Subject)
+		assert.Contains(t, msg.Body, "https://example.com/invitation/token")
+	}
+
+	_, err = (Config{Sender: "carrier pigeon"}).NewService(nil)
+	assert.Error(t, err)
+}
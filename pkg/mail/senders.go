@@ -0,0 +1,58 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package mail
+
+import (
+	"context"
+	"net/smtp"
+
+	"go.uber.org/zap"
+)
+
+// SMTPSender sends emails through an SMTP server
+type SMTPSender struct {
+	// Address is the host:port of the SMTP server
+	Address string
+	// Auth is used to authenticate to the server if not nil
+	Auth smtp.Auth
+}
+
+// SendEmail implements Sender
+func (sender *SMTPSender) SendEmail(ctx context.Context, msg *Message) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return Error.Wrap(smtp.SendMail(sender.Address, sender.Auth, msg.From, msg.To, msg.Bytes()))
+}
+
+// SESSender is a placeholder for sending emails with Amazon SES. It doesn't
+// send anything yet.
+type SESSender struct {
+	Region string
+}
+
+// SendEmail implements Sender
+func (sender *SESSender) SendEmail(ctx context.Context, msg *Message) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return Error.New("SES sender (region %q) is not implemented", sender.Region)
+}
+
+// LogSender logs emails instead of sending them, for development
+type LogSender struct {
+	log *zap.Logger
+}
+
+// NewLogSender creates a LogSender logging to log
+func NewLogSender(log *zap.Logger) *LogSender {
+	return &LogSender{log: log}
+}
+
+// SendEmail implements Sender
+func (sender *LogSender) SendEmail(ctx context.Context, msg *Message) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	sender.log.Info("email",
+		zap.String("from", msg.From),
+		zap.Strings("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Body))
+	return nil
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package mail
+
+import (
+	"bytes"
+	"strings"
+	"text/template"
+)
+
+// Template is an email template with a subject and a body
+type Template struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// NewTemplate parses an email template called name
+func NewTemplate(name, subject, body string) (*Template, error) {
+	s, err := template.New(name + ".subject").Parse(subject)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	b, err := template.New(name + ".body").Parse(body)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &Template{subject: s, body: b}, nil
+}
+
+// MustTemplate is like NewTemplate but panics if the template can't be
+// parsed. It is intended for templates defined in the source.
+func MustTemplate(name, subject, body string) *Template {
+	tmpl, err := NewTemplate(name, subject, body)
+	if err != nil {
+		panic(err)
+	}
+	return tmpl
+}
+
+// Render returns the subject and the body of the template filled with data
+func (tmpl *Template) Render(data interface{}) (subject, body string, err error) {
+	var buf bytes.Buffer
+	if err := tmpl.subject.Execute(&buf, data); err != nil {
+		return "", "", Error.Wrap(err)
+	}
+	// a newline in the subject would inject headers into the message
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	buf.Reset()
+	if err := tmpl.body.Execute(&buf, data); err != nil {
+		return "", "", Error.Wrap(err)
+	}
+	return subject, buf.String(), nil
+}
+
+// LinkData is the data of the Activation and PasswordReset templates
+type LinkData struct {
+	Link string
+}
+
+// InvitationData is the data of the Invitation template
+type InvitationData struct {
+	ProjectName string
+	Role        string
+	Link        string
+}
+
+var (
+	// Activation is sent to verify the email address of a new account
+	Activation = MustTemplate("activation",
+		`Activate your Storj account`,
+		`Welcome to Storj!
+
+Please activate your account by following this link:
+
+{{.Link}}
+
+If you didn't create an account, you can ignore this email.
+`)
+
+	// PasswordReset is sent when a password reset is requested
+	PasswordReset = MustTemplate("password-reset",
+		`Reset your Storj password`,
+		`A password reset was requested for your Storj account.
+
+You can choose a new password by following this link:
+
+{{.Link}}
+
+If you didn't request a password reset, you can ignore this email.
+`)
+
+	// Invitation is sent to invite an email address to join a project
+	Invitation = MustTemplate("invitation",
+		`You have been invited to {{.ProjectName}} on Storj`,
+		`You have been invited to join the project {{.ProjectName}} as {{.Role}}.
+
+You can accept the invitation by following this link:
+
+{{.Link}}
+`)
+)
@@ -0,0 +1,63 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/pkg/provider"
+)
+
+// CtxKey Used as metainfo loop key
+type CtxKey int
+
+const (
+	ctxKeyLoop CtxKey = iota
+)
+
+// Config is a configuration struct that is everything you need to start a
+// metainfo loop responsibility
+type Config struct {
+	CoalesceDuration time.Duration `help:"how long to wait for more observers to join before iterating over pointerdb" default:"5s"`
+	RateLimit        float64       `help:"the maximum number of pointers visited per second, unlimited if 0" default:"0"`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// PointerDB responsibility has been started before this one.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pdb := pointerdb.LoadFromContext(ctx)
+	if pdb == nil {
+		return Error.New("programmer error: pointerdb responsibility unstarted")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the loop reads the primary: garbage collection deletes the pieces
+	// missing from it, so a lagging replica would lose recently committed
+	// segments
+	loop := NewLoop(c, pdb.DB)
+	go func() {
+		if err := loop.Run(ctx); err != nil && err != context.Canceled {
+			zap.S().Error("Error with metainfo loop: ", err)
+		}
+	}()
+
+	return server.Run(context.WithValue(ctx, ctxKeyLoop, loop))
+}
+
+// LoadFromContext loads an existing metainfo Loop from the Provider context
+// stack if one exists.
+func LoadFromContext(ctx context.Context) *Loop {
+	if v, ok := ctx.Value(ctxKeyLoop).(*Loop); ok {
+		return v
+	}
+	return nil
+}
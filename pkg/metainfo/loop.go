@@ -0,0 +1,197 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb"
+	"storj.io/storj/storage"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default metainfo errs class
+	Error = errs.Class("metainfo error")
+	// ErrClosed is returned to observers when the loop stops
+	ErrClosed = errs.Class("metainfo loop closed")
+)
+
+// Observer is notified of every pointer during an iteration of the loop
+type Observer interface {
+	Pointer(ctx context.Context, path storage.Key, pointer *pb.Pointer) error
+}
+
+// observer is an observer that joined the loop
+type observer struct {
+	ctx      context.Context
+	observer Observer
+	done     chan error
+}
+
+func (obs *observer) finish(err error) {
+	obs.done <- err
+}
+
+// Loop iterates over pointerdb once for all observers that joined since the
+// previous iteration, so that every subsystem that needs to see all pointers
+// doesn't do its own full scan of the database
+type Loop struct {
+	config Config
+	db     storage.KeyValueStore
+	join   chan *observer
+	done   chan struct{}
+}
+
+// NewLoop creates a loop over the pointers in db
+func NewLoop(config Config, db storage.KeyValueStore) *Loop {
+	return &Loop{
+		config: config,
+		db:     db,
+		join:   make(chan *observer),
+		done:   make(chan struct{}),
+	}
+}
+
+// Join waits for the next iteration of the loop and returns when obs has
+// been notified of every pointer. Pointers are visited in order, and obs
+// shouldn't block, as it holds up the other observers.
+func (loop *Loop) Join(ctx context.Context, obs Observer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	joined := &observer{ctx: ctx, observer: obs, done: make(chan error, 1)}
+	select {
+	case loop.join <- joined:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-loop.done:
+		return ErrClosed.New("")
+	}
+
+	return <-joined.done
+}
+
+// Run runs iterations of the loop until ctx is canceled
+func (loop *Loop) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	defer close(loop.done)
+
+	for {
+		if err := loop.runOnce(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// runOnce waits for the first observer, gives others the coalesce duration
+// to join and then iterates once
+func (loop *Loop) runOnce(ctx context.Context) error {
+	var observers []*observer
+	select {
+	case obs := <-loop.join:
+		observers = append(observers, obs)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(loop.config.CoalesceDuration)
+	defer timer.Stop()
+waitformore:
+	for {
+		select {
+		case obs := <-loop.join:
+			observers = append(observers, obs)
+		case <-timer.C:
+			break waitformore
+		case <-ctx.Done():
+			finishAll(observers, ErrClosed.New(""))
+			return ctx.Err()
+		}
+	}
+
+	// errors of the iteration are returned to the observers
+	_ = loop.iterate(ctx, observers)
+	return ctx.Err()
+}
+
+// iterate notifies observers of every pointer in the database
+func (loop *Loop) iterate(ctx context.Context, observers []*observer) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var limiter <-chan time.Time
+	if loop.config.RateLimit > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / loop.config.RateLimit))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
+	err = loop.db.Iterate(storage.IterateOptions{Recurse: true},
+		func(it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(&item) {
+				if limiter != nil {
+					select {
+					case <-limiter:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				}
+
+				// observers see pointers in the current format, but they
+				// are only written back when they are read by the server
+				pointer, _, err := pointerdb.UnmarshalPointer(item.Value)
+				if err != nil {
+					return Error.New("invalid pointer %q: %v", item.Key, err)
+				}
+
+				observers = notify(observers, item.Key, pointer)
+				if len(observers) == 0 {
+					return nil
+				}
+			}
+			return nil
+		})
+
+	if err != nil {
+		switch {
+		case ctx.Err() != nil:
+			err = ErrClosed.Wrap(err)
+		case !Error.Has(err):
+			err = Error.Wrap(err)
+		}
+		finishAll(observers, err)
+		return err
+	}
+	finishAll(observers, nil)
+	return nil
+}
+
+// notify notifies observers of pointer and returns the observers that are
+// still interested in the iteration
+func notify(observers []*observer, path storage.Key, pointer *pb.Pointer) []*observer {
+	remaining := observers[:0]
+	for _, obs := range observers {
+		if err := obs.ctx.Err(); err != nil {
+			obs.finish(err)
+			continue
+		}
+		if err := obs.observer.Pointer(obs.ctx, path, pointer); err != nil {
+			obs.finish(err)
+			continue
+		}
+		remaining = append(remaining, obs)
+	}
+	return remaining
+}
+
+func finishAll(observers []*observer, err error) {
+	for _, obs := range observers {
+		obs.finish(err)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package metainfo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+type pathCollector struct {
+	paths []string
+	fail  string
+}
+
+func (collector *pathCollector) Pointer(ctx context.Context, path storage.Key, pointer *pb.Pointer) error {
+	if path.String() == collector.fail {
+		return errors.New("observer failed")
+	}
+	collector.paths = append(collector.paths, path.String())
+	return nil
+}
+
+func TestLoop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := teststore.New()
+	for _, path := range []string{"a/a", "a/b", "b/a"} {
+		value, err := proto.Marshal(&pb.Pointer{Type: pb.Pointer_INLINE})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.NoError(t, db.Put(storage.Key(path), value))
+	}
+
+	loop := NewLoop(Config{CoalesceDuration: 100 * time.Millisecond}, db)
+	done := make(chan error)
+	go func() { done <- loop.Run(ctx) }()
+
+	observers := []*pathCollector{{}, {}, {fail: "a/b"}}
+	errs := make([]error, len(observers))
+	var wg sync.WaitGroup
+	for i, observer := range observers {
+		wg.Add(1)
+		go func(i int, observer *pathCollector) {
+			defer wg.Done()
+			errs[i] = loop.Join(ctx, observer)
+		}(i, observer)
+	}
+	wg.Wait()
+
+	// the observers joined the same iteration and the failing one didn't
+	// affect the others
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	assert.Error(t, errs[2])
+	assert.Equal(t, []string{"a/a", "a/b", "b/a"}, observers[0].paths)
+	assert.Equal(t, []string{"a/a", "a/b", "b/a"}, observers[1].paths)
+	assert.Equal(t, []string{"a/a"}, observers[2].paths)
+
+	// invalid pointers fail the iteration
+	assert.NoError(t, db.Put(storage.Key("c"), storage.Value("not a pointer")))
+	assert.True(t, Error.Has(loop.Join(ctx, &pathCollector{})))
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+	assert.True(t, ErrClosed.Has(loop.Join(context.Background(), &pathCollector{})))
+}
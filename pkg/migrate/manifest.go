@@ -0,0 +1,113 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package migrate
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+	"time"
+)
+
+// Manifest records the objects that were migrated, so that an interrupted
+// migration can be resumed and the migrated objects verified. It's saved as
+// JSON after every object.
+type Manifest struct {
+	Source      string            `json:"source"`
+	Destination string            `json:"destination"`
+	Objects     map[string]*Entry `json:"objects"`
+
+	path string
+	mu   sync.Mutex
+}
+
+// Entry is an object in a Manifest, keyed by bucket/path
+type Entry struct {
+	// Size and Modified are those of the source object, to tell whether it
+	// changed since it was migrated
+	Size     int64     `json:"size"`
+	Modified time.Time `json:"modified"`
+	// SHA256 is the hex encoded hash of the object's data
+	SHA256   string    `json:"sha256"`
+	Migrated time.Time `json:"migrated"`
+	Verified bool      `json:"verified"`
+}
+
+// LoadManifest loads the manifest at path of a migration from source to
+// destination, or creates a new one if there's none yet
+func LoadManifest(path, source, destination string) (*Manifest, error) {
+	manifest := &Manifest{
+		Source:      source,
+		Destination: destination,
+		Objects:     map[string]*Entry{},
+		path:        path,
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if manifest.Source != source || manifest.Destination != destination {
+		return nil, Error.New("manifest %s is of a migration from %q to %q", path,
+			manifest.Source, manifest.Destination)
+	}
+	if manifest.Objects == nil {
+		manifest.Objects = map[string]*Entry{}
+	}
+	return manifest, nil
+}
+
+// Get returns the entry of key
+func (m *Manifest) Get(key string) (Entry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.Objects[key]
+	if !ok {
+		return Entry{}, false
+	}
+	return *entry, true
+}
+
+// Keys returns the keys of the entries
+func (m *Manifest) Keys() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]string, 0, len(m.Objects))
+	for key := range m.Objects {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// Set sets the entry of key and saves the manifest
+func (m *Manifest) Set(key string, entry Entry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.Objects[key] = &entry
+	return m.save()
+}
+
+// save writes the manifest to a temporary file which then replaces the
+// manifest, so it's never left half written
+func (m *Manifest) save() error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	tmp := m.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(os.Rename(tmp, m.path))
+}
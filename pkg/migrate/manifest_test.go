@@ -0,0 +1,47 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package migrate
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storj-migrate")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "migration.json")
+
+	manifest, err := LoadManifest(path, "prod", "new-prod")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, ok := manifest.Get("bucket/object")
+	assert.False(t, ok)
+
+	entry := Entry{Size: 10, Modified: time.Now().UTC().Truncate(time.Second), SHA256: "abcd"}
+	assert.NoError(t, manifest.Set("bucket/object", entry))
+
+	// the migration is resumed from the saved manifest
+	manifest, err = LoadManifest(path, "prod", "new-prod")
+	if !assert.NoError(t, err) {
+		return
+	}
+	loaded, ok := manifest.Get("bucket/object")
+	assert.True(t, ok)
+	assert.Equal(t, entry.Size, loaded.Size)
+	assert.True(t, entry.Modified.Equal(loaded.Modified))
+	assert.Equal(t, []string{"bucket/object"}, manifest.Keys())
+
+	_, err = LoadManifest(path, "prod", "other")
+	assert.Error(t, err)
+}
@@ -0,0 +1,259 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for migrations
+	Error = errs.Class("migrate error")
+)
+
+// Stats counts what a migration did
+type Stats struct {
+	Buckets  int
+	Migrated int
+	Skipped  int
+	Bytes    int64
+}
+
+// Migrator copies the buckets and objects of a project from one satellite to
+// another. The objects are downloaded through the source's bucket store and
+// uploaded through the destination's, so each access stores them its own way.
+type Migrator struct {
+	log      *zap.Logger
+	source   buckets.Store
+	dest     buckets.Store
+	manifest *Manifest
+}
+
+// NewMigrator creates a Migrator from source to dest, which records its
+// progress in manifest
+func NewMigrator(log *zap.Logger, source, dest buckets.Store, manifest *Manifest) *Migrator {
+	return &Migrator{log: log, source: source, dest: dest, manifest: manifest}
+}
+
+// Migrate migrates the given buckets, or all the buckets of the source if
+// none are given. The objects already in the manifest that didn't change
+// since are skipped.
+func (m *Migrator) Migrate(ctx context.Context, bucketNames []string) (stats Stats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(bucketNames) == 0 {
+		bucketNames, err = m.listBuckets(ctx)
+		if err != nil {
+			return stats, err
+		}
+	}
+
+	for _, bucket := range bucketNames {
+		if err := m.migrateBucket(ctx, bucket, &stats); err != nil {
+			return stats, err
+		}
+		stats.Buckets++
+	}
+	return stats, nil
+}
+
+// listBuckets returns the names of the source's buckets
+func (m *Migrator) listBuckets(ctx context.Context) (names []string, err error) {
+	startAfter := ""
+	for {
+		items, more, err := m.source.List(ctx, startAfter, "", 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			names = append(names, item.Bucket)
+		}
+		if !more || len(items) == 0 {
+			return names, nil
+		}
+		startAfter = items[len(items)-1].Bucket
+	}
+}
+
+// migrateBucket creates bucket on the destination, if it's missing, and
+// migrates its objects
+func (m *Migrator) migrateBucket(ctx context.Context, bucket string, stats *Stats) error {
+	sourceMeta, err := m.source.Get(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	if _, err := m.dest.Get(ctx, bucket); err != nil {
+		// the redundancy scheme is left to the destination's uplink, as it
+		// depends on the size of the network
+		defaults := sourceMeta.Defaults
+		defaults.Redundancy = nil
+		if _, err := m.dest.Put(ctx, bucket, defaults); err != nil {
+			return err
+		}
+		m.log.Info("created bucket", zap.String("bucket", bucket))
+	}
+
+	source, err := m.source.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return err
+	}
+	dest, err := m.dest.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return err
+	}
+
+	startAfter := paths.New("")
+	for {
+		items, more, err := source.List(ctx, paths.New(""), startAfter, nil, true, 0, meta.All)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if item.IsPrefix {
+				continue
+			}
+			if err := m.migrateObject(ctx, source, dest, bucket, item, stats); err != nil {
+				return err
+			}
+		}
+		if !more || len(items) == 0 {
+			return nil
+		}
+		startAfter = items[len(items)-1].Path
+	}
+}
+
+// migrateObject copies an object of bucket from source to dest, unless it
+// was migrated already
+func (m *Migrator) migrateObject(ctx context.Context, source, dest objects.Store, bucket string,
+	item objects.ListItem, stats *Stats) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	key := bucket + "/" + item.Path.String()
+	if entry, ok := m.manifest.Get(key); ok &&
+		entry.Size == item.Meta.Size && entry.Modified.Equal(item.Meta.Modified) {
+		stats.Skipped++
+		return nil
+	}
+	if !item.Meta.Expiration.IsZero() && item.Meta.Expiration.Before(time.Now()) {
+		stats.Skipped++
+		return nil
+	}
+
+	rr, objMeta, err := source.Get(ctx, item.Path)
+	if err != nil {
+		return err
+	}
+	r, err := rr.Range(ctx, 0, rr.Size())
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(r)
+
+	hash := sha256.New()
+	putMeta, err := dest.Put(ctx, item.Path, io.TeeReader(r, hash), objMeta.SerializableMeta, objMeta.Expiration)
+	if err != nil {
+		return err
+	}
+	if putMeta.Size != rr.Size() {
+		return Error.New("%s: uploaded %d bytes of %d", key, putMeta.Size, rr.Size())
+	}
+
+	stats.Migrated++
+	stats.Bytes += putMeta.Size
+	m.log.Info("migrated object", zap.String("object", key), zap.Int64("size", putMeta.Size))
+
+	return m.manifest.Set(key, Entry{
+		Size:     item.Meta.Size,
+		Modified: item.Meta.Modified,
+		SHA256:   hex.EncodeToString(hash.Sum(nil)),
+		Migrated: time.Now(),
+	})
+}
+
+// Verify downloads the objects in the manifest that weren't verified yet from
+// the destination and compares their hashes with the ones of the migration.
+// It returns the keys of the objects that don't match.
+func (m *Migrator) Verify(ctx context.Context) (mismatched []string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	keys := m.manifest.Keys()
+	sort.Strings(keys)
+
+	stores := map[string]objects.Store{}
+	for _, key := range keys {
+		entry, _ := m.manifest.Get(key)
+		if entry.Verified {
+			continue
+		}
+
+		parts := strings.SplitN(key, "/", 2)
+		if len(parts) != 2 {
+			return mismatched, Error.New("invalid manifest key %q", key)
+		}
+		bucket, path := parts[0], paths.New(parts[1])
+
+		store, ok := stores[bucket]
+		if !ok {
+			store, err = m.dest.GetObjectStore(ctx, bucket)
+			if err != nil {
+				return mismatched, err
+			}
+			stores[bucket] = store
+		}
+
+		sum, size, err := hashObject(ctx, store, path)
+		if err != nil {
+			return mismatched, err
+		}
+		if sum != entry.SHA256 || size != entry.Size {
+			m.log.Error("migrated object doesn't match", zap.String("object", key))
+			mismatched = append(mismatched, key)
+			continue
+		}
+
+		entry.Verified = true
+		if err := m.manifest.Set(key, entry); err != nil {
+			return mismatched, err
+		}
+	}
+	return mismatched, nil
+}
+
+// hashObject returns the hex encoded hash and the size of the object at path
+func hashObject(ctx context.Context, store objects.Store, path paths.Path) (sum string, size int64, err error) {
+	rr, _, err := store.Get(ctx, path)
+	if err != nil {
+		return "", 0, err
+	}
+	r, err := rr.Range(ctx, 0, rr.Size())
+	if err != nil {
+		return "", 0, err
+	}
+	defer utils.LogClose(r)
+
+	hash := sha256.New()
+	size, err = io.Copy(hash, r)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
@@ -0,0 +1,177 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package migrate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/storage"
+)
+
+// Checkpoint records how far a migration of pointers got, so that an
+// interrupted migration is resumed after the last copied pointer. It's saved
+// as JSON after every batch.
+type Checkpoint struct {
+	Source      string `json:"source"`
+	Destination string `json:"destination"`
+	// Last is the key of the last copied pointer
+	Last    string    `json:"last"`
+	Copied  int64     `json:"copied"`
+	Updated time.Time `json:"updated"`
+
+	path string
+}
+
+// LoadCheckpoint loads the checkpoint at path of a migration of pointers
+// from source to destination, or creates a new one if there's none yet
+func LoadCheckpoint(path, source, destination string) (*Checkpoint, error) {
+	checkpoint := &Checkpoint{Source: source, Destination: destination, path: path}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if err := json.Unmarshal(data, checkpoint); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if checkpoint.Source != source || checkpoint.Destination != destination {
+		return nil, Error.New("checkpoint %s is of a migration from %q to %q", path,
+			checkpoint.Source, checkpoint.Destination)
+	}
+	return checkpoint, nil
+}
+
+// save writes the checkpoint to a temporary file which then replaces the
+// checkpoint, so it's never left half written
+func (c *Checkpoint) save() error {
+	c.Updated = time.Now().UTC()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	tmp := c.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(os.Rename(tmp, c.path))
+}
+
+// PointerStats counts what a migration of pointers did
+type PointerStats struct {
+	Copied  int
+	Bytes   int64
+	Sampled int
+	// Mismatched are the keys of the sampled pointers that weren't read back
+	// from the destination as they were copied
+	Mismatched []string
+}
+
+// PointerMigrator copies the pointers of a pointerdb database to another,
+// e.g. from bolt to SQL. Pointers written or deleted in the source during
+// the migration may be missed, so the satellite should be in read-only mode.
+type PointerMigrator struct {
+	log        *zap.Logger
+	source     storage.KeyValueStore
+	dest       storage.KeyValueStore
+	checkpoint *Checkpoint
+	batchSize  int
+	sampleRate float64
+}
+
+// NewPointerMigrator creates a PointerMigrator from source to dest, which
+// copies batchSize pointers in each transaction, records its progress in
+// checkpoint after each, and reads sampleRate of the copied pointers back
+// from dest to verify them
+func NewPointerMigrator(log *zap.Logger, source, dest storage.KeyValueStore, checkpoint *Checkpoint,
+	batchSize int, sampleRate float64) *PointerMigrator {
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+	return &PointerMigrator{log: log, source: source, dest: dest, checkpoint: checkpoint,
+		batchSize: batchSize, sampleRate: sampleRate}
+}
+
+// Migrate copies the pointers after the checkpoint, in the order of their
+// keys
+func (m *PointerMigrator) Migrate(ctx context.Context) (stats PointerStats, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	opts := storage.IterateOptions{Recurse: true}
+	if m.checkpoint.Last != "" {
+		opts.First = storage.NextKey(storage.Key(m.checkpoint.Last))
+		m.log.Info("resuming migration", zap.String("after", m.checkpoint.Last),
+			zap.Int64("copied", m.checkpoint.Copied))
+	}
+
+	var batch storage.Items
+	err = m.source.Iterate(opts, func(it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(&item) {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			batch = append(batch, storage.CloneItem(item))
+			if len(batch) < m.batchSize {
+				continue
+			}
+			if err := m.copy(ctx, batch, &stats); err != nil {
+				return err
+			}
+			batch = batch[:0]
+		}
+		return nil
+	})
+	if err == nil && len(batch) > 0 {
+		err = m.copy(ctx, batch, &stats)
+	}
+	return stats, err
+}
+
+// copy puts the batch of pointers to the destination in a transaction,
+// verifies a sample of them and saves the checkpoint
+func (m *PointerMigrator) copy(ctx context.Context, batch storage.Items, stats *PointerStats) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = m.dest.Update(func(tx storage.Transaction) error {
+		for _, item := range batch {
+			if err := tx.Put(item.Key, item.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	for _, item := range batch {
+		stats.Copied++
+		stats.Bytes += int64(len(item.Value))
+		if m.sampleRate <= 0 || rand.Float64() >= m.sampleRate {
+			continue
+		}
+		stats.Sampled++
+		value, err := m.dest.Get(item.Key)
+		if err != nil || !bytes.Equal(value, item.Value) {
+			m.log.Warn("pointer mismatch", zap.String("key", item.Key.String()), zap.Error(err))
+			stats.Mismatched = append(stats.Mismatched, item.Key.String())
+		}
+	}
+
+	m.checkpoint.Last = batch[len(batch)-1].Key.String()
+	m.checkpoint.Copied += int64(len(batch))
+	m.log.Debug("copied pointers", zap.Int("count", len(batch)), zap.String("last", m.checkpoint.Last))
+	return m.checkpoint.save()
+}
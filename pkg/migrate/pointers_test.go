@@ -0,0 +1,82 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+// corrupted is a store which reads back a different value of key
+type corrupted struct {
+	*teststore.Client
+	key storage.Key
+}
+
+func (c *corrupted) Get(key storage.Key) (storage.Value, error) {
+	value, err := c.Client.Get(key)
+	if err == nil && key.Equal(c.key) {
+		value = append(storage.CloneValue(value), 'x')
+	}
+	return value, err
+}
+
+func TestPointerMigrator(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "storj-migrate")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "migrate-pointers.json")
+
+	source := teststore.New()
+	for i := 0; i < 10; i++ {
+		key := storage.Key(fmt.Sprintf("project/bucket/l/object%d", i))
+		assert.NoError(t, source.Put(key, storage.Value(fmt.Sprintf("pointer%d", i))))
+	}
+	dest := &corrupted{Client: teststore.New(), key: storage.Key("project/bucket/l/object7")}
+
+	checkpoint, err := LoadCheckpoint(path, "bolt", "postgres")
+	if !assert.NoError(t, err) {
+		return
+	}
+	// the migration was interrupted after the 5th pointer
+	checkpoint.Last = "project/bucket/l/object4"
+	checkpoint.Copied = 5
+
+	stats, err := NewPointerMigrator(zap.NewNop(), source, dest, checkpoint, 3, 1).Migrate(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 5, stats.Copied)
+	assert.Equal(t, 5, stats.Sampled)
+	assert.Equal(t, []string{"project/bucket/l/object7"}, stats.Mismatched)
+
+	keys, err := dest.List(nil, 0)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 5)
+	assert.Equal(t, "project/bucket/l/object5", keys[0].String())
+
+	// the checkpoint is saved after every batch
+	checkpoint, err = LoadCheckpoint(path, "bolt", "postgres")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "project/bucket/l/object9", checkpoint.Last)
+	assert.Equal(t, int64(10), checkpoint.Copied)
+
+	_, err = LoadCheckpoint(path, "bolt", "mysql")
+	assert.Error(t, err)
+}
@@ -5,34 +5,46 @@ package miniogw
 
 import (
 	"context"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
 	"os"
+	"time"
 
 	"github.com/minio/cli"
 	minio "github.com/minio/minio/cmd"
-	"github.com/vivint/infectious"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
 	"storj.io/storj/pkg/eestream"
 	"storj.io/storj/pkg/miniogw/logging"
 	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/pointerdb/pdbclient"
 	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/ranger"
 	"storj.io/storj/pkg/storage/buckets"
 	ecclient "storj.io/storj/pkg/storage/ec"
 	"storj.io/storj/pkg/storage/objects"
 	segment "storj.io/storj/pkg/storage/segments"
 	streams "storj.io/storj/pkg/storage/streams"
 	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/pkg/website"
 )
 
 // RSConfig is a configuration struct that keeps details about default
 // redundancy strategy information
 type RSConfig struct {
-	MaxBufferMem     int `help:"maximum buffer memory (in bytes) to be allocated for read buffers" default:"0x400000"`
-	ErasureShareSize int `help:"the size of each new erasure sure in bytes" default:"1024"`
-	MinThreshold     int `help:"the minimum pieces required to recover a segment. k." default:"20"`
-	RepairThreshold  int `help:"the minimum safe pieces before a repair is triggered. m." default:"30"`
-	SuccessThreshold int `help:"the desired total pieces for a segment. o." default:"40"`
-	MaxThreshold     int `help:"the largest amount of pieces to encode to. n." default:"50"`
+	MaxBufferMem      int  `help:"maximum buffer memory (in bytes) to be allocated for read buffers" default:"0x400000"`
+	MaxTotalBufferMem int  `help:"maximum buffer memory (in bytes) shared by the read buffers of all concurrent uploads and downloads. 0 means no limit" default:"0"`
+	ErasureShareSize  int  `help:"the size of each new erasure sure in bytes" default:"1024"`
+	MinThreshold      int  `help:"the minimum pieces required to recover a segment. k." default:"20"`
+	RepairThreshold   int  `help:"the minimum safe pieces before a repair is triggered. m." default:"30"`
+	SuccessThreshold  int  `help:"the desired total pieces for a segment. o." default:"40"`
+	MaxThreshold      int  `help:"the largest amount of pieces to encode to. n." default:"50"`
+	ShareMACs         bool `help:"whether to follow the erasure shares of new segments by a MAC bound to their segment, stripe and share number, so replayed or swapped shares are detected" default:"false"`
 }
 
 // MinioConfig is a configuration struct that keeps details about starting
@@ -53,6 +65,26 @@ type ClientConfig struct {
 	APIKey        string `help:"API Key (TODO: this needs to change to macaroons somehow)"`
 	MaxInlineSize int    `help:"max inline segment size in bytes" default:"4096"`
 	SegmentSize   int64  `help:"the size of a segment in bytes" default:"64000000"`
+	PartnerID     string `help:"the partner that the usage of new buckets is attributed to"`
+
+	InflightSegments int `help:"how many segments of an upload are uploaded at once. with more than 1, segments are buffered in memory, using up to this many times the segment size per upload" default:"1"`
+
+	DialTimeout time.Duration `help:"how long to wait for connections to storage nodes. 0 means no timeout" default:"0"`
+	ecclient.Limits
+
+	InstrumentRanges bool          `help:"whether to record the size, duration and number of reads of every range of an object read" default:"false"`
+	SlowRange        time.Duration `help:"ranges of objects taking longer than this to read are logged with their call path, if ranges are instrumented. 0 disables the logging" default:"0"`
+
+	CredentialsAddr string        `help:"address of the credential service. if set, the gateway is hosted and resolves the S3 credentials of its tenants to access grants instead of using the API key" default:""`
+	TenantTTL       time.Duration `help:"how long a hosted gateway uses the resolved S3 credentials of a tenant before resolving them again" default:"10m"`
+	MaxTenants      int           `help:"how many tenants a hosted gateway keeps the connections of" default:"1000"`
+}
+
+// WebsiteConfig is a configuration struct for serving the buckets with a
+// website configuration as static websites next to the gateway
+type WebsiteConfig struct {
+	WebsiteAddr   string `help:"address to serve the buckets in website mode on. disabled if empty" default:""`
+	WebsiteDomain string `help:"if set, website buckets are also served by host as subdomains of this domain, like BUCKET.DOMAIN" default:""`
 }
 
 // Config is a general miniogw configuration struct. This should be everything
@@ -62,6 +94,7 @@ type Config struct {
 	MinioConfig
 	ClientConfig
 	RSConfig
+	WebsiteConfig
 }
 
 // Run starts a Minio Gateway given proper config
@@ -73,11 +106,26 @@ func (c Config) Run(ctx context.Context) (err error) {
 		return err
 	}
 
+	if c.MaxTotalBufferMem > 0 {
+		eestream.UseMemoryBudget(eestream.NewMemoryBudget(c.MaxTotalBufferMem))
+	}
+
+	if c.CredentialsAddr != "" {
+		return c.runHosted(ctx, identity)
+	}
+
+	// minio serves on a loopback address behind the handler of the ?tagging
+	// requests, which listens on the address of the gateway
+	minioAddr, err := loopbackAddress()
+	if err != nil {
+		return err
+	}
+
 	err = minio.RegisterGatewayCommand(cli.Command{
 		Name:  "storj",
 		Usage: "Storj",
 		Action: func(cliCtx *cli.Context) error {
-			return c.action(ctx, cliCtx, identity)
+			return c.action(ctx, cliCtx, identity, minioAddr)
 		},
 		HideHelpCommand: true,
 	})
@@ -96,65 +144,199 @@ func (c Config) Run(ctx context.Context) (err error) {
 	}
 
 	minio.Main([]string{"storj", "gateway", "storj",
-		"--address", c.Address, "--config-dir", c.MinioDir, "--quiet"})
+		"--address", minioAddr, "--config-dir", c.MinioDir, "--quiet"})
 	return Error.New("unexpected minio exit")
 }
 
-func (c Config) action(ctx context.Context, cliCtx *cli.Context, identity *provider.FullIdentity) (err error) {
+func (c Config) action(ctx context.Context, cliCtx *cli.Context, identity *provider.FullIdentity, minioAddr string) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	gw, err := c.NewGateway(ctx, identity)
+	gw, err := c.newGateway(ctx, identity)
 	if err != nil {
 		return err
 	}
 
+	ln, err := net.Listen("tcp", c.Address)
+	if err != nil {
+		return err
+	}
+	log := zap.L().Named("tagging")
+	handler := NewTaggingHandler(log, gw, c.AccessKey, c.SecretKey,
+		httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: minioAddr}))
+	go func() {
+		log.Error("gateway server stopped", zap.Error((&http.Server{Handler: handler}).Serve(ln)))
+	}()
+
+	if c.WebsiteAddr != "" {
+		if err := c.serveWebsites(ctx, identity); err != nil {
+			return err
+		}
+	}
+
 	minio.StartGateway(cliCtx, logging.Gateway(gw))
 	return Error.New("unexpected minio exit")
 }
 
-// GetBucketStore returns an implementation of buckets.Store
-func (c Config) GetBucketStore(ctx context.Context, identity *provider.FullIdentity) (bs buckets.Store, err error) {
+// runHosted serves the S3 API for the tenants whose credentials the
+// credential service resolves. Minio authenticates all requests with the
+// credentials of the gateway, so hosted gateways serve the API themselves to
+// authenticate every request with the credentials of its tenant.
+func (c Config) runHosted(ctx context.Context, identity *provider.FullIdentity) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	t := transport.NewClient(identity)
+	if c.WebsiteAddr != "" {
+		return Error.New("website mode isn't supported by hosted gateways")
+	}
 
-	var oc overlay.Client
-	oc, err = overlay.NewOverlayClient(identity, c.OverlayAddr)
+	dialOpt, err := identity.DialOption()
 	if err != nil {
-		return nil, err
+		return err
+	}
+	conn, err := grpc.Dial(c.CredentialsAddr, dialOpt)
+	if err != nil {
+		return err
 	}
+	defer func() { _ = conn.Close() }()
 
-	pdb, err := pdbclient.NewClient(identity, c.PointerDBAddr, []byte(c.APIKey))
+	log := zap.L().Named("hosted")
+	// the satellite of an access grant serves both the overlay and pointerdb
+	tenants := NewTenants(log, pb.NewCredentialsClient(conn),
+		func(ctx context.Context, grant *pb.AccessGrant) (buckets.Store, func() error, error) {
+			return c.newBucketStore(ctx, identity, grant.GetSatelliteAddr(), grant.GetSatelliteAddr(), grant.GetAPIKey())
+		}, c.TenantTTL, c.MaxTenants)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		if err := tenants.Watch(ctx); err != nil && err != context.Canceled {
+			log.Error("watching revoked credentials stopped", zap.Error(err))
+		}
+	}()
+
+	ln, err := net.Listen("tcp", c.Address)
 	if err != nil {
-		return nil, err
+		return err
+	}
+	server := &http.Server{Handler: NewHostedHandler(log, tenants, c.rangeInstrumentation())}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	log.Info("serving hosted gateway", zap.Stringer("addr", ln.Addr()))
+	err = server.Serve(ln)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return Error.Wrap(err)
+}
+
+// serveWebsites starts serving the website buckets on the website address
+func (c Config) serveWebsites(ctx context.Context, identity *provider.FullIdentity) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bs, err := c.GetBucketStore(ctx, identity)
+	if err != nil {
+		return err
 	}
+	ln, err := net.Listen("tcp", c.WebsiteAddr)
+	if err != nil {
+		return err
+	}
+
+	log := zap.L().Named("website")
+	handler := website.NewHandler(log, bs, c.WebsiteDomain)
+	go func() {
+		log.Info("serving websites", zap.Stringer("addr", ln.Addr()))
+		log.Error("website server stopped", zap.Error((&http.Server{Handler: handler}).Serve(ln)))
+	}()
+	return nil
+}
 
-	ec := ecclient.NewClient(identity, t, c.MaxBufferMem)
-	fc, err := infectious.NewFEC(c.MinThreshold, c.MaxThreshold)
+// GetBucketStore returns an implementation of buckets.Store
+func (c Config) GetBucketStore(ctx context.Context, identity *provider.FullIdentity) (bs buckets.Store, err error) {
+	defer mon.Task()(&ctx)(&err)
+	// the store is used for the lifetime of the process, so it's never
+	// closed
+	bs, _, err = c.newBucketStore(ctx, identity, c.OverlayAddr, c.PointerDBAddr, []byte(c.APIKey))
+	return bs, err
+}
+
+// newBucketStore returns an implementation of buckets.Store that accesses
+// the given overlay and pointerdb with apiKey, and a function closing the
+// connections to them
+func (c Config) newBucketStore(ctx context.Context, identity *provider.FullIdentity,
+	overlayAddr, pointerDBAddr string, apiKey []byte) (bs buckets.Store, closeStore func() error, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	t := transport.NewClientWithTimeout(identity, c.DialTimeout)
+
+	oc, err := overlay.NewOverlayClient(identity, overlayAddr)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	rs, err := eestream.NewRedundancyStrategy(
-		eestream.NewRSScheme(fc, c.ErasureShareSize),
-		c.RepairThreshold, c.SuccessThreshold)
+	oc.APIKey = apiKey
+
+	pdb, err := pdbclient.NewClient(identity, pointerDBAddr, apiKey)
 	if err != nil {
-		return nil, err
+		return nil, nil, utils.CombineErrors(err, oc.Close())
+	}
+	closeStore = func() error {
+		return utils.CombineErrors(oc.Close(), pdb.Close())
 	}
 
-	segments := segment.NewSegmentStore(oc, ec, pdb, rs, c.MaxInlineSize)
+	ec := ecclient.NewClientWithLimits(identity, t, c.MaxBufferMem, c.Limits)
+
+	// newObjectStore creates an object store whose segments are stored with
+	// the redundancy scheme rs
+	newObjectStore := func(rs *pb.RedundancyScheme) (objects.Store, error) {
+		// segments are Reed-Solomon coded, objects are encrypted by their
+		// clients
+		suite, err := eestream.LookupSuite(eestream.SuiteRS)
+		if err != nil {
+			return nil, err
+		}
+		es, err := suite.NewErasure(int(rs.GetMinReq()), int(rs.GetTotal()), int(rs.GetErasureShareSize()))
+		if err != nil {
+			return nil, err
+		}
+		strategy, err := eestream.NewRedundancyStrategy(es,
+			int(rs.GetRepairThreshold()), int(rs.GetSuccessThreshold()))
+		if err != nil {
+			return nil, err
+		}
+
+		segments := segment.NewSegmentStoreWithSuite(oc, ec, pdb, strategy, c.MaxInlineSize, c.ShareMACs, suite.ID)
 
-	// segment size 64MB
-	stream, err := streams.NewStreamStore(segments, c.SegmentSize)
+		// segment size 64MB
+		stream, err := streams.NewStreamStore(segments, c.SegmentSize, c.InflightSegments)
+		if err != nil {
+			return nil, err
+		}
+		return objects.NewStore(stream), nil
+	}
+
+	obj, err := newObjectStore(&pb.RedundancyScheme{
+		Type:             pb.RedundancyScheme_RS,
+		MinReq:           int32(c.MinThreshold),
+		Total:            int32(c.MaxThreshold),
+		RepairThreshold:  int32(c.RepairThreshold),
+		SuccessThreshold: int32(c.SuccessThreshold),
+		ErasureShareSize: int32(c.ErasureShareSize),
+	})
 	if err != nil {
-		return nil, err
+		return nil, nil, utils.CombineErrors(err, closeStore())
 	}
-	obj := objects.NewStore(stream)
 
-	return buckets.NewStore(obj), nil
+	return buckets.NewStoreWithDefaults(obj, newObjectStore), closeStore, nil
 }
 
 // NewGateway creates a new minio Gateway
 func (c Config) NewGateway(ctx context.Context, identity *provider.FullIdentity) (gw minio.Gateway, err error) {
+	return c.newGateway(ctx, identity)
+}
+
+func (c Config) newGateway(ctx context.Context, identity *provider.FullIdentity) (gw *Storj, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	bs, err := c.GetBucketStore(ctx, identity)
@@ -162,5 +344,26 @@ func (c Config) NewGateway(ctx context.Context, identity *provider.FullIdentity)
 		return nil, err
 	}
 
-	return NewStorjGateway(bs), nil
+	gateway := NewStorjGateway(bs, c.PartnerID)
+	gateway.ranges = c.rangeInstrumentation()
+	return gateway, nil
+}
+
+// loopbackAddress returns a free address on the loopback interface
+func loopbackAddress() (string, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	addr := ln.Addr().String()
+	return addr, ln.Close()
+}
+
+// rangeInstrumentation returns the instrumentation of the ranges of objects
+// read through the gateway, or nil if they aren't instrumented
+func (c Config) rangeInstrumentation() *ranger.Instrumentation {
+	if !c.InstrumentRanges {
+		return nil
+	}
+	return ranger.NewInstrumentation(zap.L().Named("ranges"), c.SlowRange)
 }
@@ -29,15 +29,19 @@ var (
 	Error = errs.Class("Storj Gateway error")
 )
 
-// NewStorjGateway creates a *Storj object from an existing ObjectStore
-func NewStorjGateway(bs buckets.Store) *Storj {
-	return &Storj{bs: bs, multipart: NewMultipartUploads()}
+// NewStorjGateway creates a *Storj object from an existing ObjectStore.
+// Buckets created through the gateway are attributed to partnerID.
+func NewStorjGateway(bs buckets.Store, partnerID string) *Storj {
+	return &Storj{bs: bs, partnerID: partnerID, multipart: NewMultipartUploads()}
 }
 
 //Storj is the implementation of a minio cmd.Gateway
 type Storj struct {
 	bs        buckets.Store
+	partnerID string
 	multipart *MultipartUploads
+	// ranges instruments the ranges of the objects read, if not nil
+	ranges *ranger.Instrumentation
 }
 
 // Name implements cmd.Gateway
@@ -48,7 +52,7 @@ func (s *Storj) Name() string {
 // NewGatewayLayer implements cmd.Gateway
 func (s *Storj) NewGatewayLayer(creds auth.Credentials) (
 	minio.ObjectLayer, error) {
-	return &storjObjects{storj: s}, nil
+	return &storjObjects{storj: s, ranges: s.ranges}, nil
 }
 
 // Production implements cmd.Gateway
@@ -59,6 +63,8 @@ func (s *Storj) Production() bool {
 type storjObjects struct {
 	minio.GatewayUnsupported
 	storj *Storj
+	// ranges instruments the ranges of the objects read, if not nil
+	ranges *ranger.Instrumentation
 }
 
 func (s *storjObjects) DeleteBucket(ctx context.Context, bucket string) (err error) {
@@ -116,8 +122,11 @@ func (s *storjObjects) getObject(ctx context.Context, bucket, object string) (rr
 	}
 
 	rr, _, err = o.Get(ctx, paths.New(object))
+	if err != nil {
+		return nil, err
+	}
 
-	return rr, err
+	return s.ranges.Instrument("object", rr), nil
 }
 
 func (s *storjObjects) GetObject(ctx context.Context, bucket, object string,
@@ -338,7 +347,7 @@ func (s *storjObjects) MakeBucketWithLocation(ctx context.Context,
 	if !storage.ErrKeyNotFound.Has(err) {
 		return err
 	}
-	_, err = s.storj.bs.Put(ctx, bucket)
+	_, err = s.storj.bs.Put(ctx, bucket, buckets.Defaults{PartnerID: s.storj.partnerID})
 	return err
 }
 
@@ -0,0 +1,989 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	minio "github.com/minio/minio/cmd"
+	"github.com/minio/minio/pkg/hash"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/storage"
+)
+
+const (
+	// isoFormat is the format of the timestamps in S3 responses
+	isoFormat = "2006-01-02T15:04:05.000Z"
+	// maxListKeys is the most keys or parts a listing returns
+	maxListKeys = 1000
+)
+
+// unsupportedSubresources are the subresources of buckets and objects that
+// hosted gateways don't serve
+var unsupportedSubresources = []string{
+	"acl", "cors", "encryption", "lifecycle", "logging", "notification",
+	"policy", "replication", "requestPayment", "torrent", "versioning",
+	"versions", "website",
+}
+
+// apiError is an S3 error response
+type apiError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func newAPIError(statusCode int, code, format string, args ...interface{}) apiError {
+	return apiError{StatusCode: statusCode, Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+func (err apiError) Error() string {
+	return err.Code + ": " + err.Message
+}
+
+// HostedHandler serves the S3 API of a hosted gateway. Every request is
+// authenticated with the signature of its own S3 credentials, which are
+// resolved to the tenant whose buckets the request accesses. Buckets are
+// addressed by path.
+type HostedHandler struct {
+	log     *zap.Logger
+	tenants *Tenants
+	// ranges instruments the ranges of the objects read, if not nil
+	ranges *ranger.Instrumentation
+}
+
+// NewHostedHandler creates a handler of the S3 requests of the tenants
+func NewHostedHandler(log *zap.Logger, tenants *Tenants, ranges *ranger.Instrumentation) *HostedHandler {
+	return &HostedHandler{log: log, tenants: tenants, ranges: ranges}
+}
+
+// ServeHTTP authenticates a request and serves it for its tenant
+func (h *HostedHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	objects, release, err := h.authenticate(ctx, r)
+	if err != nil {
+		writeError(h.log, w, r, err)
+		return
+	}
+	defer release()
+
+	if err = h.serve(ctx, w, r, objects); err != nil {
+		writeError(h.log, w, r, err)
+	}
+}
+
+// authenticate verifies the signature of r with the credentials of its
+// tenant and returns the object layer of the tenant, which must be released
+// once the request is served
+func (h *HostedHandler) authenticate(ctx context.Context, r *http.Request) (objects *storjObjects, release func(), err error) {
+	sig, err := parseSignature(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tn, err := h.tenants.acquire(ctx, sig.accessKeyID)
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, nil, newAPIError(http.StatusForbidden, "InvalidAccessKeyId",
+				"the access key id %s doesn't exist", sig.accessKeyID)
+		}
+		return nil, nil, err
+	}
+	release = func() { h.tenants.release(tn) }
+
+	if err := sig.verify(r, tn.secretKey, time.Now()); err != nil {
+		release()
+		return nil, nil, err
+	}
+	return &storjObjects{storj: tn.storj, ranges: h.ranges}, release, nil
+}
+
+// serve routes an authenticated request
+func (h *HostedHandler) serve(ctx context.Context, w http.ResponseWriter, r *http.Request, objects *storjObjects) error {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	bucket, object := path, ""
+	if i := strings.IndexByte(path, '/'); i >= 0 {
+		bucket, object = path[:i], path[i+1:]
+	}
+	query := r.URL.Query()
+
+	for _, subresource := range unsupportedSubresources {
+		if _, ok := query[subresource]; ok {
+			return newAPIError(http.StatusNotImplemented, "NotImplemented", "?%s is not supported", subresource)
+		}
+	}
+
+	switch {
+	case bucket == "":
+		if r.Method != http.MethodGet {
+			return errMethodNotAllowed
+		}
+		return h.listBuckets(ctx, w, objects)
+	case object == "":
+		return h.serveBucket(ctx, w, r, objects, bucket, query)
+	default:
+		return h.serveObject(ctx, w, r, objects, bucket, object, query)
+	}
+}
+
+var errMethodNotAllowed = newAPIError(http.StatusMethodNotAllowed, "MethodNotAllowed",
+	"the method is not allowed against this resource")
+
+func (h *HostedHandler) serveBucket(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	objects *storjObjects, bucket string, query url.Values) error {
+	_, location := query["location"]
+	_, uploads := query["uploads"]
+	_, del := query["delete"]
+
+	switch {
+	case r.Method == http.MethodGet && location:
+		if _, err := objects.GetBucketInfo(ctx, bucket); err != nil {
+			return err
+		}
+		return writeXML(w, http.StatusOK, locationResponse{})
+	case r.Method == http.MethodGet && uploads:
+		return newAPIError(http.StatusNotImplemented, "NotImplemented", "listing multipart uploads is not supported")
+	case r.Method == http.MethodGet && query.Get("list-type") == "2":
+		return h.listObjectsV2(ctx, w, objects, bucket, query)
+	case r.Method == http.MethodGet:
+		return h.listObjects(ctx, w, objects, bucket, query)
+	case r.Method == http.MethodHead:
+		_, err := objects.GetBucketInfo(ctx, bucket)
+		return err
+	case r.Method == http.MethodPut:
+		if err := objects.MakeBucketWithLocation(ctx, bucket, ""); err != nil {
+			return err
+		}
+		w.Header().Set("Location", "/"+bucket)
+		return nil
+	case r.Method == http.MethodDelete:
+		if err := objects.DeleteBucket(ctx, bucket); err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	case r.Method == http.MethodPost && del:
+		return h.deleteObjects(ctx, w, r, objects, bucket)
+	}
+	return errMethodNotAllowed
+}
+
+func (h *HostedHandler) serveObject(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	objects *storjObjects, bucket, object string, query url.Values) error {
+	_, tagging := query["tagging"]
+	_, uploads := query["uploads"]
+	uploadID := query.Get("uploadId")
+
+	switch {
+	case tagging:
+		return serveTagging(ctx, w, r, objects, bucket, object)
+	case r.Method == http.MethodGet && uploadID != "":
+		return h.listParts(ctx, w, objects, bucket, object, uploadID, query)
+	case r.Method == http.MethodGet, r.Method == http.MethodHead:
+		return h.getObject(ctx, w, r, objects, bucket, object)
+	case r.Method == http.MethodPut && uploadID != "":
+		return h.putPart(ctx, w, r, objects, bucket, object, uploadID, query)
+	case r.Method == http.MethodPut && r.Header.Get("X-Amz-Copy-Source") != "":
+		return h.copyObject(ctx, w, r, objects, bucket, object)
+	case r.Method == http.MethodPut:
+		return h.putObject(ctx, w, r, objects, bucket, object)
+	case r.Method == http.MethodPost && uploads:
+		return h.newMultipartUpload(ctx, w, r, objects, bucket, object)
+	case r.Method == http.MethodPost && uploadID != "":
+		return h.completeMultipartUpload(ctx, w, r, objects, bucket, object, uploadID)
+	case r.Method == http.MethodDelete && uploadID != "":
+		if _, err := objects.storj.multipart.Get(bucket, object, uploadID); err != nil {
+			return errNoSuchUpload
+		}
+		if err := objects.AbortMultipartUpload(ctx, bucket, object, uploadID); err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	case r.Method == http.MethodDelete:
+		if err := objects.DeleteObject(ctx, bucket, object); err != nil && !storage.ErrKeyNotFound.Has(err) {
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	return errMethodNotAllowed
+}
+
+var errNoSuchUpload = newAPIError(http.StatusNotFound, "NoSuchUpload", "the multipart upload doesn't exist")
+
+type owner struct {
+	ID          string
+	DisplayName string
+}
+
+type bucketEntry struct {
+	Name         string
+	CreationDate string
+}
+
+type listBucketsResponse struct {
+	XMLName xml.Name      `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListAllMyBucketsResult"`
+	Owner   owner         `xml:"Owner"`
+	Buckets []bucketEntry `xml:"Buckets>Bucket"`
+}
+
+func (h *HostedHandler) listBuckets(ctx context.Context, w http.ResponseWriter, objects *storjObjects) error {
+	infos, err := objects.ListBuckets(ctx)
+	if err != nil {
+		return err
+	}
+	resp := listBucketsResponse{}
+	for _, info := range infos {
+		resp.Buckets = append(resp.Buckets, bucketEntry{Name: info.Name, CreationDate: info.Created.UTC().Format(isoFormat)})
+	}
+	return writeXML(w, http.StatusOK, resp)
+}
+
+type locationResponse struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ LocationConstraint"`
+	Location string   `xml:",chardata"`
+}
+
+type objectEntry struct {
+	Key          string
+	LastModified string
+	ETag         string
+	Size         int64
+	StorageClass string
+}
+
+type commonPrefix struct {
+	Prefix string
+}
+
+type listObjectsResponse struct {
+	XMLName        xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name           string
+	Prefix         string
+	Marker         string
+	NextMarker     string `xml:",omitempty"`
+	MaxKeys        int
+	Delimiter      string `xml:",omitempty"`
+	IsTruncated    bool
+	Contents       []objectEntry
+	CommonPrefixes []commonPrefix
+}
+
+type listObjectsV2Response struct {
+	XMLName               xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Name                  string
+	Prefix                string
+	StartAfter            string `xml:",omitempty"`
+	ContinuationToken     string `xml:",omitempty"`
+	NextContinuationToken string `xml:",omitempty"`
+	KeyCount              int
+	MaxKeys               int
+	Delimiter             string `xml:",omitempty"`
+	IsTruncated           bool
+	Contents              []objectEntry
+	CommonPrefixes        []commonPrefix
+}
+
+// listParams returns the delimiter and the maximum number of keys of a
+// listing
+func listParams(query url.Values) (delimiter string, maxKeys int, err error) {
+	delimiter = query.Get("delimiter")
+	if delimiter != "" && delimiter != "/" {
+		return "", 0, newAPIError(http.StatusNotImplemented, "NotImplemented", "only / is supported as delimiter")
+	}
+	maxKeys, err = intParam(query, "max-keys", maxListKeys)
+	if err != nil {
+		return "", 0, err
+	}
+	if maxKeys > maxListKeys {
+		maxKeys = maxListKeys
+	}
+	return delimiter, maxKeys, nil
+}
+
+func (h *HostedHandler) listObjects(ctx context.Context, w http.ResponseWriter, objects *storjObjects,
+	bucket string, query url.Values) error {
+	delimiter, maxKeys, err := listParams(query)
+	if err != nil {
+		return err
+	}
+	prefix, marker := query.Get("prefix"), query.Get("marker")
+
+	result, err := objects.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+	if err != nil {
+		return err
+	}
+
+	resp := listObjectsResponse{
+		Name:        bucket,
+		Prefix:      prefix,
+		Marker:      marker,
+		NextMarker:  result.NextMarker,
+		MaxKeys:     maxKeys,
+		Delimiter:   delimiter,
+		IsTruncated: result.IsTruncated,
+	}
+	for _, info := range result.Objects {
+		resp.Contents = append(resp.Contents, newObjectEntry(info))
+	}
+	for _, prefix := range result.Prefixes {
+		resp.CommonPrefixes = append(resp.CommonPrefixes, commonPrefix{Prefix: prefix})
+	}
+	return writeXML(w, http.StatusOK, resp)
+}
+
+func (h *HostedHandler) listObjectsV2(ctx context.Context, w http.ResponseWriter, objects *storjObjects,
+	bucket string, query url.Values) error {
+	delimiter, maxKeys, err := listParams(query)
+	if err != nil {
+		return err
+	}
+	prefix, token, startAfter := query.Get("prefix"), query.Get("continuation-token"), query.Get("start-after")
+
+	result, err := objects.ListObjectsV2(ctx, bucket, prefix, token, delimiter, maxKeys, false, startAfter)
+	if err != nil {
+		return err
+	}
+
+	resp := listObjectsV2Response{
+		Name:                  bucket,
+		Prefix:                prefix,
+		StartAfter:            startAfter,
+		ContinuationToken:     token,
+		NextContinuationToken: result.NextContinuationToken,
+		KeyCount:              len(result.Objects) + len(result.Prefixes),
+		MaxKeys:               maxKeys,
+		Delimiter:             delimiter,
+		IsTruncated:           result.IsTruncated,
+	}
+	for _, info := range result.Objects {
+		resp.Contents = append(resp.Contents, newObjectEntry(info))
+	}
+	for _, prefix := range result.Prefixes {
+		resp.CommonPrefixes = append(resp.CommonPrefixes, commonPrefix{Prefix: prefix})
+	}
+	return writeXML(w, http.StatusOK, resp)
+}
+
+func newObjectEntry(info minio.ObjectInfo) objectEntry {
+	return objectEntry{
+		Key:          info.Name,
+		LastModified: info.ModTime.UTC().Format(isoFormat),
+		ETag:         quoteETag(info.ETag),
+		Size:         info.Size,
+		StorageClass: "STANDARD",
+	}
+}
+
+// getObject serves GET and HEAD requests of objects
+func (h *HostedHandler) getObject(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	objects *storjObjects, bucket, object string) error {
+	info, err := objects.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+
+	offset, length, ranged, err := parseRange(r.Header.Get("Range"), info.Size)
+	if err != nil {
+		return err
+	}
+
+	header := w.Header()
+	writeObjectHeaders(header, info)
+	header.Set("Accept-Ranges", "bytes")
+	header.Set("Content-Length", strconv.FormatInt(length, 10))
+	statusCode := http.StatusOK
+	if ranged {
+		header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, info.Size))
+		statusCode = http.StatusPartialContent
+	}
+	w.WriteHeader(statusCode)
+
+	if r.Method == http.MethodHead || length == 0 {
+		return nil
+	}
+	// the status is sent already, so failures can only be logged
+	if err := objects.GetObject(ctx, bucket, object, offset, length, w, ""); err != nil {
+		h.log.Warn("reading object failed", zap.String("bucket", bucket), zap.String("object", object), zap.Error(err))
+	}
+	return nil
+}
+
+// writeObjectHeaders sets the headers describing an object
+func writeObjectHeaders(header http.Header, info minio.ObjectInfo) {
+	for key, value := range info.UserDefined {
+		if key == tagsKey {
+			continue
+		}
+		header.Set(key, value)
+	}
+	if tags, err := decodeTags(info.UserDefined); err == nil && len(tags) > 0 {
+		header.Set("X-Amz-Tagging-Count", strconv.Itoa(len(tags)))
+	}
+	if info.ContentType != "" {
+		header.Set("Content-Type", info.ContentType)
+	}
+	header.Set("ETag", quoteETag(info.ETag))
+	header.Set("Last-Modified", info.ModTime.UTC().Format(http.TimeFormat))
+}
+
+// parseRange returns the offset and length of the byte range of an object
+// of size that the Range header requests. Like S3, only single ranges are
+// served, other ranges are ignored.
+func parseRange(rangeHeader string, size int64) (offset, length int64, ranged bool, err error) {
+	spec := strings.TrimPrefix(rangeHeader, "bytes=")
+	if rangeHeader == "" || spec == rangeHeader || strings.Contains(spec, ",") {
+		return 0, size, false, nil
+	}
+	invalid := newAPIError(http.StatusRequestedRangeNotSatisfiable, "InvalidRange", "the requested range is not satisfiable")
+
+	dash := strings.IndexByte(spec, '-')
+	if dash < 0 {
+		return 0, size, false, nil
+	}
+	first, last := spec[:dash], spec[dash+1:]
+
+	if first == "" {
+		// the last bytes of the object
+		suffix, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || suffix <= 0 || size == 0 {
+			return 0, 0, false, invalid
+		}
+		if suffix > size {
+			suffix = size
+		}
+		return size - suffix, suffix, true, nil
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, false, invalid
+	}
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false, invalid
+		}
+		if end >= size {
+			end = size - 1
+		}
+	}
+	return start, end - start + 1, true, nil
+}
+
+// supportedHeaders are the headers of uploads that are stored with objects
+// besides their content type and user metadata
+var supportedHeaders = []string{
+	"cache-control", "content-disposition", "content-encoding", "content-language", "expires",
+}
+
+// objectMetadata returns the metadata of an upload in the request headers,
+// keyed like minio keys it for the gateway
+func objectMetadata(header http.Header) (map[string]string, error) {
+	metadata := map[string]string{}
+	if contentType := header.Get("Content-Type"); contentType != "" {
+		metadata["content-type"] = contentType
+	}
+	for _, name := range supportedHeaders {
+		if value := header.Get(name); value != "" {
+			metadata[name] = value
+		}
+	}
+	// aws-chunked only describes how the payload was signed
+	if encoding := strings.TrimPrefix(metadata["content-encoding"], "aws-chunked"); encoding == "" {
+		delete(metadata, "content-encoding")
+	} else {
+		metadata["content-encoding"] = strings.TrimPrefix(encoding, ",")
+	}
+	for key, values := range header {
+		if strings.HasPrefix(key, "X-Amz-Meta-") && len(values) > 0 {
+			metadata[key] = values[0]
+		}
+	}
+
+	if tagging := header.Get("X-Amz-Tagging"); tagging != "" {
+		tags, err := decodeTags(map[string]string{tagsKey: tagging})
+		if err != nil {
+			return nil, newAPIError(http.StatusBadRequest, "InvalidTag", "X-Amz-Tagging is invalid")
+		}
+		encoded, err := encodeTags(tags)
+		if err != nil {
+			return nil, newAPIError(http.StatusBadRequest, "InvalidTag", "%v", err)
+		}
+		metadata[tagsKey] = encoded
+	}
+	return metadata, nil
+}
+
+// uploadReader returns the payload of an upload, which is verified against
+// its Content-MD5 header if it has one
+func uploadReader(r *http.Request) (*hash.Reader, error) {
+	if r.ContentLength < 0 {
+		return nil, newAPIError(http.StatusLengthRequired, "MissingContentLength", "Content-Length is required")
+	}
+
+	body := io.Reader(r.Body)
+	if contentMD5 := r.Header.Get("Content-MD5"); contentMD5 != "" {
+		sum, err := base64.StdEncoding.DecodeString(contentMD5)
+		if err != nil || len(sum) != md5.Size {
+			return nil, newAPIError(http.StatusBadRequest, "InvalidDigest", "Content-MD5 is invalid")
+		}
+		body = &digestReader{body: r.Body, hash: md5.New(), sum: sum,
+			err: newAPIError(http.StatusBadRequest, "BadDigest", "the payload doesn't match Content-MD5")}
+	}
+
+	data, err := hash.NewReader(body, r.ContentLength, "", "")
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return data, nil
+}
+
+func (h *HostedHandler) putObject(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	objects *storjObjects, bucket, object string) error {
+	metadata, err := objectMetadata(r.Header)
+	if err != nil {
+		return err
+	}
+	data, err := uploadReader(r)
+	if err != nil {
+		return err
+	}
+
+	info, err := objects.PutObject(ctx, bucket, object, data, metadata)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("ETag", quoteETag(info.ETag))
+	return nil
+}
+
+type copyObjectResponse struct {
+	XMLName      xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CopyObjectResult"`
+	LastModified string
+	ETag         string
+}
+
+func (h *HostedHandler) copyObject(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	objects *storjObjects, bucket, object string) error {
+	source, err := url.PathUnescape(r.Header.Get("X-Amz-Copy-Source"))
+	if err != nil {
+		return newAPIError(http.StatusBadRequest, "InvalidArgument", "X-Amz-Copy-Source is invalid")
+	}
+	if i := strings.IndexByte(source, '?'); i >= 0 {
+		source = source[:i]
+	}
+	source = strings.TrimPrefix(source, "/")
+	slash := strings.IndexByte(source, '/')
+	if slash <= 0 || slash == len(source)-1 {
+		return newAPIError(http.StatusBadRequest, "InvalidArgument", "X-Amz-Copy-Source must be bucket/object")
+	}
+	srcBucket, srcObject := source[:slash], source[slash+1:]
+
+	srcInfo, err := objects.GetObjectInfo(ctx, srcBucket, srcObject)
+	if err != nil {
+		return err
+	}
+	if r.Header.Get("X-Amz-Metadata-Directive") == "REPLACE" {
+		metadata, err := objectMetadata(r.Header)
+		if err != nil {
+			return err
+		}
+		srcInfo.ContentType = metadata["content-type"]
+		delete(metadata, "content-type")
+		srcInfo.UserDefined = metadata
+	}
+
+	info, err := objects.CopyObject(ctx, srcBucket, srcObject, bucket, object, srcInfo)
+	if err != nil {
+		return err
+	}
+	return writeXML(w, http.StatusOK, copyObjectResponse{
+		LastModified: info.ModTime.UTC().Format(isoFormat),
+		ETag:         quoteETag(info.ETag),
+	})
+}
+
+type deleteRequest struct {
+	Quiet   bool
+	Objects []struct {
+		Key string
+	} `xml:"Object"`
+}
+
+type deletedEntry struct {
+	Key string
+}
+
+type deleteErrorEntry struct {
+	Key     string
+	Code    string
+	Message string
+}
+
+type deleteResponse struct {
+	XMLName xml.Name           `xml:"http://s3.amazonaws.com/doc/2006-03-01/ DeleteResult"`
+	Deleted []deletedEntry     `xml:"Deleted"`
+	Errors  []deleteErrorEntry `xml:"Error"`
+}
+
+func (h *HostedHandler) deleteObjects(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	objects *storjObjects, bucket string) error {
+	var req deleteRequest
+	if err := decodeXML(r, &req); err != nil {
+		return err
+	}
+	if len(req.Objects) > maxListKeys {
+		return newAPIError(http.StatusBadRequest, "MalformedXML", "at most %d objects can be deleted at once", maxListKeys)
+	}
+
+	resp := deleteResponse{}
+	for _, object := range req.Objects {
+		err := objects.DeleteObject(ctx, bucket, object.Key)
+		if err != nil && !storage.ErrKeyNotFound.Has(err) {
+			apiErr := toAPIError(h.log, err)
+			resp.Errors = append(resp.Errors, deleteErrorEntry{Key: object.Key, Code: apiErr.Code, Message: apiErr.Message})
+			continue
+		}
+		if !req.Quiet {
+			resp.Deleted = append(resp.Deleted, deletedEntry{Key: object.Key})
+		}
+	}
+	return writeXML(w, http.StatusOK, resp)
+}
+
+type tagEntry struct {
+	Key   string
+	Value string
+}
+
+type taggingDocument struct {
+	XMLName xml.Name   `xml:"Tagging"`
+	TagSet  []tagEntry `xml:"TagSet>Tag"`
+}
+
+// serveTagging serves a ?tagging request of an object
+func serveTagging(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	objects *storjObjects, bucket, object string) error {
+	switch r.Method {
+	case http.MethodGet:
+		return getTagging(ctx, w, objects, bucket, object)
+	case http.MethodPut:
+		return putTagging(ctx, r, objects, bucket, object)
+	case http.MethodDelete:
+		if err := objects.DeleteObjectTagging(ctx, bucket, object); err != nil {
+			return err
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return nil
+	}
+	return errMethodNotAllowed
+}
+
+func getTagging(ctx context.Context, w http.ResponseWriter, objects *storjObjects, bucket, object string) error {
+	tags, err := objects.GetObjectTagging(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+	resp := taggingDocument{}
+	for key, value := range tags {
+		resp.TagSet = append(resp.TagSet, tagEntry{Key: key, Value: value})
+	}
+	return writeXML(w, http.StatusOK, resp)
+}
+
+func putTagging(ctx context.Context, r *http.Request, objects *storjObjects, bucket, object string) error {
+	var req taggingDocument
+	if err := decodeXML(r, &req); err != nil {
+		return err
+	}
+	tags := make(map[string]string, len(req.TagSet))
+	for _, tag := range req.TagSet {
+		if _, ok := tags[tag.Key]; ok {
+			return newAPIError(http.StatusBadRequest, "InvalidTag", "tag %q is set twice", tag.Key)
+		}
+		tags[tag.Key] = tag.Value
+	}
+	if _, err := encodeTags(tags); err != nil {
+		return newAPIError(http.StatusBadRequest, "InvalidTag", "%v", err)
+	}
+	return objects.PutObjectTagging(ctx, bucket, object, tags)
+}
+
+type newMultipartUploadResponse struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ InitiateMultipartUploadResult"`
+	Bucket   string
+	Key      string
+	UploadID string `xml:"UploadId"`
+}
+
+func (h *HostedHandler) newMultipartUpload(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	objects *storjObjects, bucket, object string) error {
+	if _, err := objects.GetBucketInfo(ctx, bucket); err != nil {
+		return err
+	}
+	metadata, err := objectMetadata(r.Header)
+	if err != nil {
+		return err
+	}
+	uploadID, err := objects.NewMultipartUpload(ctx, bucket, object, metadata)
+	if err != nil {
+		return err
+	}
+	return writeXML(w, http.StatusOK, newMultipartUploadResponse{Bucket: bucket, Key: object, UploadID: uploadID})
+}
+
+func (h *HostedHandler) putPart(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	objects *storjObjects, bucket, object, uploadID string, query url.Values) error {
+	if r.Header.Get("X-Amz-Copy-Source") != "" {
+		return newAPIError(http.StatusNotImplemented, "NotImplemented", "copying parts is not supported")
+	}
+	partNumber, err := strconv.Atoi(query.Get("partNumber"))
+	if err != nil || partNumber < 1 || partNumber > 10000 {
+		return newAPIError(http.StatusBadRequest, "InvalidArgument", "partNumber must be between 1 and 10000")
+	}
+	if _, err := objects.storj.multipart.Get(bucket, object, uploadID); err != nil {
+		return errNoSuchUpload
+	}
+	data, err := uploadReader(r)
+	if err != nil {
+		return err
+	}
+
+	info, err := objects.PutObjectPart(ctx, bucket, object, uploadID, partNumber, data)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("ETag", quoteETag(info.ETag))
+	return nil
+}
+
+type completeMultipartUploadRequest struct {
+	Parts []struct {
+		PartNumber int
+		ETag       string
+	} `xml:"Part"`
+}
+
+type completeMultipartUploadResponse struct {
+	XMLName  xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ CompleteMultipartUploadResult"`
+	Location string
+	Bucket   string
+	Key      string
+	ETag     string
+}
+
+func (h *HostedHandler) completeMultipartUpload(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	objects *storjObjects, bucket, object, uploadID string) error {
+	if _, err := objects.storj.multipart.Get(bucket, object, uploadID); err != nil {
+		return errNoSuchUpload
+	}
+	var req completeMultipartUploadRequest
+	if err := decodeXML(r, &req); err != nil {
+		return err
+	}
+	if len(req.Parts) == 0 {
+		return newAPIError(http.StatusBadRequest, "MalformedXML", "at least one part must be listed")
+	}
+	parts := make([]minio.CompletePart, len(req.Parts))
+	for i, part := range req.Parts {
+		parts[i] = minio.CompletePart{PartNumber: part.PartNumber, ETag: part.ETag}
+	}
+
+	info, err := objects.CompleteMultipartUpload(ctx, bucket, object, uploadID, parts)
+	if err != nil {
+		return err
+	}
+	return writeXML(w, http.StatusOK, completeMultipartUploadResponse{
+		Location: "/" + bucket + "/" + object,
+		Bucket:   bucket,
+		Key:      object,
+		ETag:     quoteETag(info.ETag),
+	})
+}
+
+type partEntry struct {
+	PartNumber   int
+	LastModified string
+	ETag         string
+	Size         int64
+}
+
+type listPartsResponse struct {
+	XMLName              xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListPartsResult"`
+	Bucket               string
+	Key                  string
+	UploadID             string `xml:"UploadId"`
+	StorageClass         string
+	PartNumberMarker     int
+	NextPartNumberMarker int
+	MaxParts             int
+	IsTruncated          bool
+	Parts                []partEntry `xml:"Part"`
+}
+
+func (h *HostedHandler) listParts(ctx context.Context, w http.ResponseWriter, objects *storjObjects,
+	bucket, object, uploadID string, query url.Values) error {
+	maxParts, err := intParam(query, "max-parts", maxListKeys)
+	if err != nil {
+		return err
+	}
+	if maxParts > maxListKeys {
+		maxParts = maxListKeys
+	}
+	marker, err := intParam(query, "part-number-marker", 0)
+	if err != nil {
+		return err
+	}
+	if _, err := objects.storj.multipart.Get(bucket, object, uploadID); err != nil {
+		return errNoSuchUpload
+	}
+
+	result, err := objects.ListObjectParts(ctx, bucket, object, uploadID, marker, maxParts)
+	if err != nil {
+		return err
+	}
+	resp := listPartsResponse{
+		Bucket:               bucket,
+		Key:                  object,
+		UploadID:             uploadID,
+		StorageClass:         "STANDARD",
+		PartNumberMarker:     marker,
+		NextPartNumberMarker: result.NextPartNumberMarker,
+		MaxParts:             maxParts,
+		IsTruncated:          result.IsTruncated,
+	}
+	for _, part := range result.Parts {
+		resp.Parts = append(resp.Parts, partEntry{
+			PartNumber:   part.PartNumber,
+			LastModified: part.LastModified.UTC().Format(isoFormat),
+			ETag:         quoteETag(part.ETag),
+			Size:         part.Size,
+		})
+	}
+	return writeXML(w, http.StatusOK, resp)
+}
+
+// intParam returns the non-negative integer query parameter name, or def if
+// it isn't set
+func intParam(query url.Values, name string, def int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, newAPIError(http.StatusBadRequest, "InvalidArgument", "%s must be a non-negative integer", name)
+	}
+	return n, nil
+}
+
+func quoteETag(etag string) string {
+	return `"` + strings.Trim(etag, `"`) + `"`
+}
+
+// maxXMLRequestSize bounds the XML bodies of requests
+const maxXMLRequestSize = 1 << 20
+
+func decodeXML(r *http.Request, v interface{}) error {
+	if err := xml.NewDecoder(io.LimitReader(r.Body, maxXMLRequestSize)).Decode(v); err != nil {
+		if apiErr, ok := err.(apiError); ok {
+			return apiErr
+		}
+		return newAPIError(http.StatusBadRequest, "MalformedXML", "the XML is not well-formed")
+	}
+	return nil
+}
+
+func writeXML(w http.ResponseWriter, statusCode int, v interface{}) error {
+	body, err := xml.Marshal(v)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write([]byte(xml.Header))
+	_, _ = w.Write(body)
+	return nil
+}
+
+type errorResponse struct {
+	XMLName  xml.Name `xml:"Error"`
+	Code     string
+	Message  string
+	Resource string
+}
+
+// writeError writes the S3 error response of err
+func writeError(log *zap.Logger, w http.ResponseWriter, r *http.Request, err error) {
+	apiErr := toAPIError(log, err)
+	if r.Method == http.MethodHead {
+		w.WriteHeader(apiErr.StatusCode)
+		return
+	}
+	_ = writeXML(w, apiErr.StatusCode, errorResponse{Code: apiErr.Code, Message: apiErr.Message, Resource: r.URL.Path})
+}
+
+// toAPIError returns the S3 error of err, logging unexpected errors
+func toAPIError(log *zap.Logger, err error) apiError {
+	switch err := err.(type) {
+	case apiError:
+		return err
+	case minio.BucketNotFound:
+		return newAPIError(http.StatusNotFound, "NoSuchBucket", "the bucket %s doesn't exist", err.Bucket)
+	case minio.BucketAlreadyExists:
+		return newAPIError(http.StatusConflict, "BucketAlreadyOwnedByYou", "the bucket %s already exists", err.Bucket)
+	case minio.BucketNotEmpty:
+		return newAPIError(http.StatusConflict, "BucketNotEmpty", "the bucket %s isn't empty", err.Bucket)
+	case minio.ObjectNotFound:
+		return newAPIError(http.StatusNotFound, "NoSuchKey", "the object %s doesn't exist", err.Object)
+	case minio.InvalidPart:
+		return newAPIError(http.StatusBadRequest, "InvalidPart", "a listed part wasn't uploaded or its ETag doesn't match")
+	}
+	if storage.ErrKeyNotFound.Has(err) {
+		return newAPIError(http.StatusNotFound, "NoSuchKey", "the object doesn't exist")
+	}
+	if apiErr, ok := errCause(err).(apiError); ok {
+		// the payload failed verification while it was read
+		return apiErr
+	}
+
+	log.Error("serving request failed", zap.Error(err))
+	return newAPIError(http.StatusInternalServerError, "InternalError", "we encountered an internal error, please try again")
+}
+
+// errCause returns the innermost cause of an error wrapped with errs or
+// with a Cause method
+func errCause(err error) error {
+	for {
+		causer, ok := err.(interface{ Cause() error })
+		if !ok {
+			return err
+		}
+		cause := causer.Cause()
+		if cause == nil || cause == err {
+			return err
+		}
+		err = cause
+	}
+}
@@ -0,0 +1,499 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	miniogo "github.com/minio/minio-go"
+	"github.com/minio/minio-go/pkg/s3signer"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/buckets"
+	mock_buckets "storj.io/storj/pkg/storage/buckets/mocks"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/storage"
+)
+
+// fakeCredentials is a credential service that resolves the credentials set
+// with set
+type fakeCredentials struct {
+	pb.CredentialsClient
+
+	mu       sync.Mutex
+	creds    map[string]*pb.ResolveCredentialsResponse
+	resolves int
+	revoked  chan string
+}
+
+func newFakeCredentials() *fakeCredentials {
+	return &fakeCredentials{creds: map[string]*pb.ResolveCredentialsResponse{}, revoked: make(chan string)}
+}
+
+func (c *fakeCredentials) set(accessKeyID, secretKey string, grant *pb.AccessGrant) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.creds[accessKeyID] = &pb.ResolveCredentialsResponse{SecretKey: secretKey, Grant: grant}
+}
+
+func (c *fakeCredentials) remove(accessKeyID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.creds, accessKeyID)
+}
+
+func (c *fakeCredentials) Resolve(ctx context.Context, req *pb.ResolveCredentialsRequest, opts ...grpc.CallOption) (*pb.ResolveCredentialsResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.resolves++
+	resp, ok := c.creds[req.GetAccessKeyId()]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown access key id")
+	}
+	return resp, nil
+}
+
+func (c *fakeCredentials) WatchRevocations(ctx context.Context, req *pb.WatchRevocationsRequest, opts ...grpc.CallOption) (pb.Credentials_WatchRevocationsClient, error) {
+	return &revocationStream{ctx: ctx, revoked: c.revoked}, nil
+}
+
+type revocationStream struct {
+	grpc.ClientStream
+	ctx     context.Context
+	revoked chan string
+}
+
+func (stream *revocationStream) Recv() (*pb.RevokedCredentials, error) {
+	select {
+	case <-stream.ctx.Done():
+		return nil, stream.ctx.Err()
+	case accessKeyID := <-stream.revoked:
+		return &pb.RevokedCredentials{AccessKeyId: accessKeyID}, nil
+	}
+}
+
+func TestTenants(t *testing.T) {
+	creds := newFakeCredentials()
+	grant := &pb.AccessGrant{SatelliteAddr: "satellite", APIKey: []byte("key")}
+	creds.set("tenant", "secret", grant)
+	creds.set("other", "secret", &pb.AccessGrant{SatelliteAddr: "satellite", APIKey: []byte("other key")})
+
+	var mu sync.Mutex
+	closed := map[string]int{}
+	closedStores := func(apiKey string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return closed[apiKey]
+	}
+	tenants := NewTenants(zap.NewNop(), creds,
+		func(ctx context.Context, grant *pb.AccessGrant) (buckets.Store, func() error, error) {
+			return nil, func() error {
+				mu.Lock()
+				defer mu.Unlock()
+				closed[string(grant.GetAPIKey())]++
+				return nil
+			}, nil
+		}, time.Hour, 1)
+
+	tn, err := tenants.acquire(ctx, "tenant")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, "secret", tn.secretKey)
+	tenants.release(tn)
+
+	// resolved tenants are cached
+	cached, err := tenants.acquire(ctx, "tenant")
+	if assert.NoError(t, err) {
+		assert.True(t, tn == cached)
+		tenants.release(cached)
+	}
+	assert.Equal(t, 1, creds.resolves)
+
+	// expired tenants are resolved again, and kept if their credentials
+	// didn't change
+	tenants.expireAll()
+	renewed, err := tenants.acquire(ctx, "tenant")
+	if assert.NoError(t, err) {
+		assert.True(t, tn == renewed)
+		tenants.release(renewed)
+	}
+	assert.Equal(t, 2, creds.resolves)
+	assert.Equal(t, 0, closedStores("key"))
+
+	// tenants whose credentials changed are replaced
+	creds.set("tenant", "new secret", grant)
+	tenants.expireAll()
+	replaced, err := tenants.acquire(ctx, "tenant")
+	if assert.NoError(t, err) {
+		assert.False(t, tn == replaced)
+		assert.Equal(t, "new secret", replaced.secretKey)
+		tenants.release(replaced)
+	}
+	assert.Equal(t, 1, closedStores("key"))
+
+	// the least recently used tenant is evicted
+	other, err := tenants.acquire(ctx, "other")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.Equal(t, 2, closedStores("key"))
+	assert.Len(t, tenants.tenants, 1)
+
+	// revoked tenants are closed once they aren't used anymore
+	tenants.Revoke("other")
+	assert.Equal(t, 0, closedStores("other key"))
+	tenants.release(other)
+	assert.Equal(t, 1, closedStores("other key"))
+
+	_, err = tenants.acquire(ctx, "unknown")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestTenantsWatch(t *testing.T) {
+	creds := newFakeCredentials()
+	creds.set("tenant", "secret", &pb.AccessGrant{SatelliteAddr: "satellite", APIKey: []byte("key")})
+	tenants := NewTenants(zap.NewNop(), creds,
+		func(ctx context.Context, grant *pb.AccessGrant) (buckets.Store, func() error, error) {
+			return nil, nil, nil
+		}, time.Hour, 10)
+
+	tn, err := tenants.acquire(ctx, "tenant")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	tenants.release(tn)
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- tenants.Watch(watchCtx) }()
+
+	creds.revoked <- "tenant"
+	for revoked := false; !revoked; time.Sleep(time.Millisecond) {
+		tenants.mu.Lock()
+		revoked = tn.removed
+		tenants.mu.Unlock()
+	}
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
+
+func TestSignatureV4(t *testing.T) {
+	const accessKeyID, secretKey = "AKIDEXAMPLE", "secret"
+	body := []byte("payload")
+	sum := sha256.Sum256(body)
+
+	newRequest := func(method, target string, body []byte) *http.Request {
+		req := httptest.NewRequest(method, "http://gateway.test"+target, bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		return req
+	}
+	signed := func(req *http.Request, payload string) *http.Request {
+		req.Header.Set("X-Amz-Content-Sha256", payload)
+		return s3signer.SignV4(*req, accessKeyID, secretKey, "", "us-east-1")
+	}
+	verify := func(req *http.Request, secretKey string, now time.Time) error {
+		sig, err := parseSignature(req)
+		if err != nil {
+			return err
+		}
+		if !assert.Equal(t, accessKeyID, sig.accessKeyID) {
+			t.FailNow()
+		}
+		if err := sig.verify(req, secretKey, now); err != nil {
+			return err
+		}
+		_, err = ioutil.ReadAll(req.Body)
+		return errCause(err)
+	}
+	code := func(err error) string {
+		if apiErr, ok := err.(apiError); ok {
+			return apiErr.Code
+		}
+		return ""
+	}
+	now := time.Now()
+
+	// signed in the header
+	req := signed(newRequest("PUT", "/bucket/some%20object?tagging", body), hex.EncodeToString(sum[:]))
+	assert.NoError(t, verify(req, secretKey, now))
+
+	req = signed(newRequest("PUT", "/bucket/object", body), hex.EncodeToString(sum[:]))
+	assert.Equal(t, "SignatureDoesNotMatch", code(verify(req, "wrong secret key", now)))
+
+	req = signed(newRequest("PUT", "/bucket/object", body), hex.EncodeToString(sum[:]))
+	assert.Equal(t, "RequestTimeTooSkewed", code(verify(req, secretKey, now.Add(time.Hour))))
+
+	req = signed(newRequest("PUT", "/bucket/object", body), hex.EncodeToString(sum[:]))
+	req.URL.Path = "/bucket/other"
+	assert.Equal(t, "SignatureDoesNotMatch", code(verify(req, secretKey, now)))
+
+	req = signed(newRequest("PUT", "/bucket/object", []byte("other payload")), hex.EncodeToString(sum[:]))
+	assert.Equal(t, "XAmzContentSHA256Mismatch", code(verify(req, secretKey, now)))
+
+	req = signed(newRequest("PUT", "/bucket/object", []byte("other payload")), unsignedPayload)
+	assert.NoError(t, verify(req, secretKey, now))
+
+	req = newRequest("GET", "/bucket/object", nil)
+	assert.Equal(t, "AccessDenied", code(verify(req, secretKey, now)))
+
+	// presigned
+	presigned := func(expires int64) *http.Request {
+		url := s3signer.PreSignV4(*newRequest("GET", "/bucket/object", nil), accessKeyID, secretKey, "", "us-east-1", expires).URL
+		return newRequest("GET", url.RequestURI(), nil)
+	}
+	assert.NoError(t, verify(presigned(60), secretKey, now))
+	assert.Equal(t, "SignatureDoesNotMatch", code(verify(presigned(60), "wrong secret key", now)))
+	assert.Equal(t, "AccessDenied", code(verify(presigned(60), secretKey, now.Add(time.Hour))))
+
+	// signed chunk by chunk
+	data := bytes.Repeat([]byte("chunk"), 30000)
+	streamed := func(data []byte) *http.Request {
+		req := newRequest("PUT", "/bucket/object", data)
+		return s3signer.StreamingSignV4(req, accessKeyID, secretKey, "", "us-east-1", int64(len(data)), time.Now().UTC())
+	}
+	req = streamed(data)
+	sig, err := parseSignature(req)
+	if assert.NoError(t, err) && assert.NoError(t, sig.verify(req, secretKey, now)) {
+		assert.Equal(t, int64(len(data)), req.ContentLength)
+		decoded, err := ioutil.ReadAll(req.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, data, decoded)
+	}
+
+	req = streamed(data)
+	encoded, err := ioutil.ReadAll(req.Body)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	// a byte of the last data chunk is changed
+	encoded[len(encoded)-100] ^= 1
+	req.Body = ioutil.NopCloser(bytes.NewReader(encoded))
+	assert.Equal(t, "SignatureDoesNotMatch", code(verify(req, secretKey, now)))
+}
+
+func TestParseRange(t *testing.T) {
+	for i, tt := range []struct {
+		header         string
+		offset, length int64
+		ranged         bool
+		err            bool
+	}{
+		{"", 0, 100, false, false},
+		{"bytes=0-9", 0, 10, true, false},
+		{"bytes=90-", 90, 10, true, false},
+		{"bytes=90-200", 90, 10, true, false},
+		{"bytes=-10", 90, 10, true, false},
+		{"bytes=-200", 0, 100, true, false},
+		{"bytes=0-1,5-6", 0, 100, false, false},
+		{"bytes=100-", 0, 0, false, true},
+		{"bytes=9-0", 0, 0, false, true},
+	} {
+		offset, length, ranged, err := parseRange(tt.header, 100)
+		if tt.err {
+			assert.Error(t, err, i)
+			continue
+		}
+		if assert.NoError(t, err, i) {
+			assert.Equal(t, tt.offset, offset, i)
+			assert.Equal(t, tt.length, length, i)
+			assert.Equal(t, tt.ranged, ranged, i)
+		}
+	}
+}
+
+func TestHostedHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockOS := NewMockStore(ctrl)
+
+	creds := newFakeCredentials()
+	creds.set("tenant", "tenant secret", &pb.AccessGrant{SatelliteAddr: "satellite", APIKey: []byte("key")})
+	tenants := NewTenants(zap.NewNop(), creds,
+		func(ctx context.Context, grant *pb.AccessGrant) (buckets.Store, func() error, error) {
+			return mockBS, nil, nil
+		}, time.Hour, 10)
+
+	server := httptest.NewServer(NewHostedHandler(zap.NewNop(), tenants, nil))
+	defer server.Close()
+	endpoint := strings.TrimPrefix(server.URL, "http://")
+
+	client, err := miniogo.NewWithRegion(endpoint, "tenant", "tenant secret", false, "us-east-1")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	mockBS.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{}, storage.ErrKeyNotFound.New("bucket"))
+	mockBS.EXPECT().Put(gomock.Any(), "bucket", buckets.Defaults{}).Return(buckets.Meta{}, nil)
+	assert.NoError(t, client.MakeBucket("bucket", ""))
+
+	data := bytes.Repeat([]byte("data"), 50000)
+	modified := time.Now().Round(time.Second)
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "bucket").Return(mockOS, nil).AnyTimes()
+	mockOS.EXPECT().Put(gomock.Any(), paths.New("object"), gomock.Any(), gomock.Any(), time.Time{}).
+		DoAndReturn(func(ctx context.Context, path paths.Path, r io.Reader, meta objects.SerializableMeta, expiration time.Time) (objects.Meta, error) {
+			uploaded, err := ioutil.ReadAll(r)
+			if err != nil {
+				return objects.Meta{}, err
+			}
+			assert.Equal(t, data, uploaded)
+			assert.Equal(t, "text/plain", meta.ContentType)
+			assert.Equal(t, "value", meta.UserDefined["X-Amz-Meta-Key"])
+			return objects.Meta{SerializableMeta: meta, Modified: modified, Size: int64(len(uploaded)), Checksum: "checksum"}, nil
+		})
+	n, err := client.PutObject("bucket", "object", bytes.NewReader(data), int64(len(data)), miniogo.PutObjectOptions{
+		ContentType:  "text/plain",
+		UserMetadata: map[string]string{"key": "value"},
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(len(data)), n)
+	}
+
+	mockOS.EXPECT().Meta(gomock.Any(), paths.New("object")).Return(objects.Meta{
+		SerializableMeta: objects.SerializableMeta{ContentType: "text/plain"},
+		Modified:         modified,
+		Size:             int64(len(data)),
+		Checksum:         "checksum",
+	}, nil)
+	info, err := client.StatObject("bucket", "object", miniogo.StatObjectOptions{})
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(len(data)), info.Size)
+		assert.Equal(t, "checksum", info.ETag)
+		assert.Equal(t, "text/plain", info.ContentType)
+		assert.True(t, modified.Equal(info.LastModified))
+	}
+
+	mockOS.EXPECT().Meta(gomock.Any(), paths.New("missing")).Return(objects.Meta{}, storage.ErrKeyNotFound.New("missing"))
+	_, err = client.StatObject("bucket", "missing", miniogo.StatObjectOptions{})
+	assert.Equal(t, "NoSuchKey", miniogo.ToErrorResponse(err).Code)
+
+	wrongSecret, err := miniogo.NewWithRegion(endpoint, "tenant", "wrong secret", false, "us-east-1")
+	if assert.NoError(t, err) {
+		_, err = wrongSecret.ListBuckets()
+		assert.Equal(t, "SignatureDoesNotMatch", miniogo.ToErrorResponse(err).Code)
+	}
+
+	// revoked credentials are refused
+	creds.remove("tenant")
+	tenants.Revoke("tenant")
+	_, err = client.ListBuckets()
+	assert.Equal(t, "InvalidAccessKeyId", miniogo.ToErrorResponse(err).Code)
+}
+
+func TestTaggingHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBS := mock_buckets.NewMockStore(ctrl)
+	mockOS := NewMockStore(ctrl)
+
+	passed := 0
+	minio := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		passed++
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(NewTaggingHandler(zap.NewNop(), NewStorjGateway(mockBS, ""), "access", "secret", minio))
+	defer server.Close()
+
+	do := func(method, target, accessKey, body string) *http.Response {
+		req, err := http.NewRequest(method, server.URL+target, strings.NewReader(body))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		sum := sha256.Sum256([]byte(body))
+		req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(sum[:]))
+		resp, err := http.DefaultClient.Do(s3signer.SignV4(*req, accessKey, "secret", "", "us-east-1"))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return resp
+	}
+	read := func(resp *http.Response) string {
+		defer func() { _ = resp.Body.Close() }()
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.NoError(t, err)
+		return string(body)
+	}
+
+	data := []byte("data")
+	meta := objects.Meta{
+		SerializableMeta: objects.SerializableMeta{ContentType: "text/plain", UserDefined: map[string]string{"X-Amz-Meta-Key": "value"}},
+		Size:             int64(len(data)),
+	}
+	mockBS.EXPECT().GetObjectStore(gomock.Any(), "bucket").Return(mockOS, nil).AnyTimes()
+
+	// tags are set by copying the object onto itself
+	mockOS.EXPECT().Meta(gomock.Any(), paths.New("some object")).Return(meta, nil)
+	mockOS.EXPECT().Get(gomock.Any(), paths.New("some object")).Return(ranger.ByteRanger(data), meta, nil)
+	mockOS.EXPECT().Put(gomock.Any(), paths.New("some object"), gomock.Any(), gomock.Any(), time.Time{}).
+		DoAndReturn(func(ctx context.Context, path paths.Path, r io.Reader, put objects.SerializableMeta, expiration time.Time) (objects.Meta, error) {
+			uploaded, err := ioutil.ReadAll(r)
+			if err != nil {
+				return objects.Meta{}, err
+			}
+			assert.Equal(t, data, uploaded)
+			assert.Equal(t, "text/plain", put.ContentType)
+			assert.Equal(t, "value", put.UserDefined["X-Amz-Meta-Key"])
+			assert.Equal(t, "color=blue", put.UserDefined[tagsKey])
+			meta.SerializableMeta = put
+			return meta, nil
+		})
+	resp := do("PUT", "/bucket/some%20object?tagging", "access",
+		"<Tagging><TagSet><Tag><Key>color</Key><Value>blue</Value></Tag></TagSet></Tagging>")
+	assert.Equal(t, http.StatusOK, resp.StatusCode, read(resp))
+
+	mockOS.EXPECT().Meta(gomock.Any(), paths.New("some object")).DoAndReturn(
+		func(ctx context.Context, path paths.Path) (objects.Meta, error) { return meta, nil })
+	resp = do("GET", "/bucket/some%20object?tagging", "access", "")
+	if assert.Equal(t, http.StatusOK, resp.StatusCode) {
+		assert.Contains(t, read(resp), "<Tag><Key>color</Key><Value>blue</Value></Tag>")
+	}
+
+	// tags are removed the same way
+	mockOS.EXPECT().Meta(gomock.Any(), paths.New("some object")).DoAndReturn(
+		func(ctx context.Context, path paths.Path) (objects.Meta, error) { return meta, nil })
+	mockOS.EXPECT().Get(gomock.Any(), paths.New("some object")).Return(ranger.ByteRanger(data), meta, nil)
+	mockOS.EXPECT().Put(gomock.Any(), paths.New("some object"), gomock.Any(), gomock.Any(), time.Time{}).
+		DoAndReturn(func(ctx context.Context, path paths.Path, r io.Reader, put objects.SerializableMeta, expiration time.Time) (objects.Meta, error) {
+			_, hasTags := put.UserDefined[tagsKey]
+			assert.False(t, hasTags)
+			assert.Equal(t, "value", put.UserDefined["X-Amz-Meta-Key"])
+			return meta, nil
+		})
+	resp = do("DELETE", "/bucket/some%20object?tagging", "access", "")
+	assert.Equal(t, http.StatusNoContent, resp.StatusCode, read(resp))
+
+	resp = do("GET", "/bucket/some%20object?tagging", "other", "")
+	if assert.Equal(t, http.StatusForbidden, resp.StatusCode) {
+		assert.Contains(t, read(resp), "InvalidAccessKeyId")
+	}
+
+	// the other requests are served by minio
+	assert.Equal(t, 0, passed)
+	read(do("GET", "/bucket/some%20object", "access", ""))
+	read(do("GET", "/bucket?tagging", "access", ""))
+	assert.Equal(t, 2, passed)
+}
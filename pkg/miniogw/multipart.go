@@ -2,99 +2,72 @@ package miniogw
 
 import (
 	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
 	"io"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
-	"sync/atomic"
 	"time"
 
 	minio "github.com/minio/minio/cmd"
 	"github.com/minio/minio/pkg/hash"
+	"go.uber.org/zap"
 
 	"storj.io/storj/pkg/paths"
 	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storage/streams"
 )
 
 func (s *storjObjects) NewMultipartUpload(ctx context.Context, bucket, object string, metadata map[string]string) (uploadID string, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	uploads := s.storj.multipart
-
-	upload, err := uploads.Create(bucket, object, metadata)
-	if err != nil {
-		return "", err
-	}
-
-	objectStore, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	upload, err := s.storj.multipart.Create(bucket, object, metadata)
 	if err != nil {
-		uploads.RemoveByID(upload.ID)
-		upload.fail(err)
 		return "", err
 	}
-
-	go func() {
-		// setting zero value means the object never expires
-		expTime := time.Time{}
-
-		tempContType := metadata["content-type"]
-		delete(metadata, "content-type")
-
-		//metadata serialized
-		serMetaInfo := objects.SerializableMeta{
-			ContentType: tempContType,
-			UserDefined: metadata,
-		}
-
-		result, err := objectStore.Put(ctx, paths.New(object), upload.Stream, serMetaInfo, expTime)
-		uploads.RemoveByID(upload.ID)
-
-		if err != nil {
-			upload.fail(err)
-		} else {
-			upload.complete(minio.ObjectInfo{
-				Name:        object,
-				Bucket:      bucket,
-				ModTime:     result.Modified,
-				Size:        result.Size,
-				ETag:        result.Checksum,
-				ContentType: result.ContentType,
-				UserDefined: result.UserDefined,
-			})
-		}
-	}()
-
 	return upload.ID, nil
 }
 
 func (s *storjObjects) PutObjectPart(ctx context.Context, bucket, object, uploadID string, partID int, data *hash.Reader) (info minio.PartInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	uploads := s.storj.multipart
-
-	upload, err := uploads.Get(bucket, object, uploadID)
+	upload, err := s.storj.multipart.Get(bucket, object, uploadID)
 	if err != nil {
 		return minio.PartInfo{}, err
 	}
 
-	part, err := upload.Stream.AddPart(partID, data)
+	objectStore, err := s.storj.bs.GetObjectStore(ctx, bucket)
 	if err != nil {
 		return minio.PartInfo{}, err
 	}
 
-	err = <-part.Done
+	// the previous upload of the part is replaced, so that its segments
+	// aren't left behind when the new one is smaller
+	if previous, ok := upload.getPart(partID); ok {
+		err = objectStore.DeleteParts(ctx, paths.New(object), uploadID, []streams.Part{previous.part})
+		if err != nil {
+			return minio.PartInfo{}, err
+		}
+		upload.removePart(partID)
+	}
+
+	sum := md5.New()
+	part, err := objectStore.PutPart(ctx, paths.New(object), uploadID, partID, io.TeeReader(data, sum), time.Time{})
 	if err != nil {
 		return minio.PartInfo{}, err
 	}
+	part.ETag = hex.EncodeToString(sum.Sum(nil))
 
 	partInfo := minio.PartInfo{
-		PartNumber:   part.Number,
+		PartNumber:   partID,
 		LastModified: time.Now(),
-		ETag:         data.SHA256HexString(),
-		Size:         atomic.LoadInt64(&part.Size),
+		ETag:         part.ETag,
+		Size:         part.Size,
 	}
-
-	upload.addCompletedPart(partInfo)
+	upload.addPart(uploadedPart{info: partInfo, part: part})
 
 	return partInfo, nil
 }
@@ -102,37 +75,100 @@ func (s *storjObjects) PutObjectPart(ctx context.Context, bucket, object, upload
 func (s *storjObjects) AbortMultipartUpload(ctx context.Context, bucket, object, uploadID string) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	uploads := s.storj.multipart
+	upload, err := s.storj.multipart.Remove(bucket, object, uploadID)
+	if err != nil {
+		return err
+	}
 
-	upload, err := uploads.Remove(bucket, object, uploadID)
+	objectStore, err := s.storj.bs.GetObjectStore(ctx, bucket)
 	if err != nil {
 		return err
 	}
 
-	errAbort := Error.New("abort")
-	upload.Stream.Abort(errAbort)
-	r := <-upload.Done
-	if r.Error != errAbort {
-		return r.Error
+	var parts []streams.Part
+	for _, uploaded := range upload.getParts() {
+		parts = append(parts, uploaded.part)
 	}
-	return nil
+	return objectStore.DeleteParts(ctx, paths.New(object), uploadID, parts)
 }
 
 func (s *storjObjects) CompleteMultipartUpload(ctx context.Context, bucket, object, uploadID string, uploadedParts []minio.CompletePart) (objInfo minio.ObjectInfo, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	uploads := s.storj.multipart
-	upload, err := uploads.Remove(bucket, object, uploadID)
+	upload, err := s.storj.multipart.Get(bucket, object, uploadID)
 	if err != nil {
 		return minio.ObjectInfo{}, err
 	}
 
-	// notify stream that there aren't more parts coming
-	upload.Stream.Close()
-	// wait for completion
-	result := <-upload.Done
-	// return the final info
-	return result.Info, result.Error
+	// the object is stitched together from the listed parts, in the listed
+	// order, whatever order they were uploaded in
+	var parts []streams.Part
+	sums := md5.New()
+	for i, completed := range uploadedParts {
+		if i > 0 && completed.PartNumber <= uploadedParts[i-1].PartNumber {
+			return minio.ObjectInfo{}, Error.New("parts must be listed in ascending order")
+		}
+		uploaded, ok := upload.getPart(completed.PartNumber)
+		if !ok || strings.Trim(completed.ETag, `"`) != uploaded.part.ETag {
+			return minio.ObjectInfo{}, minio.InvalidPart{}
+		}
+		sum, err := hex.DecodeString(uploaded.part.ETag)
+		if err != nil {
+			return minio.ObjectInfo{}, Error.Wrap(err)
+		}
+		_, _ = sums.Write(sum)
+		parts = append(parts, uploaded.part)
+	}
+
+	objectStore, err := s.storj.bs.GetObjectStore(ctx, bucket)
+	if err != nil {
+		return minio.ObjectInfo{}, err
+	}
+
+	metadata := make(map[string]string, len(upload.Metadata))
+	for key, value := range upload.Metadata {
+		metadata[key] = value
+	}
+	contentType := metadata["content-type"]
+	delete(metadata, "content-type")
+
+	serMetaInfo := objects.SerializableMeta{
+		ContentType: contentType,
+		UserDefined: metadata,
+	}
+
+	// setting zero value means the object never expires
+	result, err := objectStore.CommitParts(ctx, paths.New(object), uploadID, parts, serMetaInfo, time.Time{})
+	if err != nil {
+		return minio.ObjectInfo{}, err
+	}
+
+	// the parts that weren't listed are discarded
+	listed := map[int]bool{}
+	for _, part := range parts {
+		listed[part.Number] = true
+	}
+	var unlisted []streams.Part
+	for _, uploaded := range upload.getParts() {
+		if !listed[uploaded.part.Number] {
+			unlisted = append(unlisted, uploaded.part)
+		}
+	}
+	if err := objectStore.DeleteParts(ctx, paths.New(object), uploadID, unlisted); err != nil {
+		zap.S().Warnf("Failed deleting unlisted parts of upload %s: %v", uploadID, err)
+	}
+
+	s.storj.multipart.RemoveByID(uploadID)
+
+	return minio.ObjectInfo{
+		Name:        object,
+		Bucket:      bucket,
+		ModTime:     result.Modified,
+		Size:        result.Size,
+		ETag:        hex.EncodeToString(sums.Sum(nil)) + "-" + strconv.Itoa(len(parts)),
+		ContentType: result.ContentType,
+		UserDefined: result.UserDefined,
+	}, nil
 }
 
 func (s *storjObjects) ListObjectParts(ctx context.Context, bucket, object, uploadID string, partNumberMarker int, maxParts int) (result minio.ListPartsInfo, err error) {
@@ -150,11 +186,9 @@ func (s *storjObjects) ListObjectParts(ctx context.Context, bucket, object, uplo
 	list.PartNumberMarker = partNumberMarker
 	list.MaxParts = maxParts
 	list.UserDefined = upload.Metadata
-	list.Parts = upload.getCompletedParts()
-
-	sort.Slice(list.Parts, func(i, k int) bool {
-		return list.Parts[i].PartNumber < list.Parts[k].PartNumber
-	})
+	for _, uploaded := range upload.getParts() {
+		list.Parts = append(list.Parts, uploaded.info)
+	}
 
 	var first int
 	for i, p := range list.Parts {
@@ -181,7 +215,6 @@ func (s *storjObjects) ListObjectParts(ctx context.Context, bucket, object, uplo
 // MultipartUploads manages pending multipart uploads
 type MultipartUploads struct {
 	mu      sync.RWMutex
-	lastID  int
 	pending map[string]*MultipartUpload
 }
 
@@ -192,20 +225,19 @@ func NewMultipartUploads() *MultipartUploads {
 	}
 }
 
-// Create creates a new upload
+// Create creates a new upload. Several uploads to the same location can be
+// pending, the one that completes last wins.
 func (uploads *MultipartUploads) Create(bucket, object string, metadata map[string]string) (*MultipartUpload, error) {
-	uploads.mu.Lock()
-	defer uploads.mu.Unlock()
-
-	for id, upload := range uploads.pending {
-		if upload.Bucket == bucket && upload.Object == object {
-			upload.Stream.Abort(Error.New("aborted by another upload to the same location"))
-			delete(uploads.pending, id)
-		}
+	// the parts of an upload are stored under its id, so ids must not
+	// repeat, even across restarts
+	var id [16]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return nil, Error.Wrap(err)
 	}
+	uploadID := hex.EncodeToString(id[:])
 
-	uploads.lastID++
-	uploadID := "Upload" + strconv.Itoa(uploads.lastID)
+	uploads.mu.Lock()
+	defer uploads.mu.Unlock()
 
 	upload := NewMultipartUpload(uploadID, bucket, object, metadata)
 	uploads.pending[uploadID] = upload
@@ -215,8 +247,8 @@ func (uploads *MultipartUploads) Create(bucket, object string, metadata map[stri
 
 // Get finds a pending upload
 func (uploads *MultipartUploads) Get(bucket, object, uploadID string) (*MultipartUpload, error) {
-	uploads.mu.Lock()
-	defer uploads.mu.Unlock()
+	uploads.mu.RLock()
+	defer uploads.mu.RUnlock()
 
 	upload, ok := uploads.pending[uploadID]
 	if !ok {
@@ -231,8 +263,8 @@ func (uploads *MultipartUploads) Get(bucket, object, uploadID string) (*Multipar
 
 // Remove returns and removes a pending upload
 func (uploads *MultipartUploads) Remove(bucket, object, uploadID string) (*MultipartUpload, error) {
-	uploads.mu.RLock()
-	defer uploads.mu.RUnlock()
+	uploads.mu.Lock()
+	defer uploads.mu.Unlock()
 
 	upload, ok := uploads.pending[uploadID]
 	if !ok {
@@ -249,9 +281,16 @@ func (uploads *MultipartUploads) Remove(bucket, object, uploadID string) (*Multi
 
 // RemoveByID removes pending upload by id
 func (uploads *MultipartUploads) RemoveByID(uploadID string) {
+	uploads.mu.Lock()
+	defer uploads.mu.Unlock()
+	delete(uploads.pending, uploadID)
+}
+
+// hasPending returns whether any uploads are pending
+func (uploads *MultipartUploads) hasPending() bool {
 	uploads.mu.RLock()
 	defer uploads.mu.RUnlock()
-	delete(uploads.pending, uploadID)
+	return len(uploads.pending) > 0
 }
 
 // MultipartUpload is partial info about a pending upload
@@ -260,196 +299,64 @@ type MultipartUpload struct {
 	Bucket   string
 	Object   string
 	Metadata map[string]string
-	Done     chan (*MultipartUploadResult)
-	Stream   *MultipartStream
 
-	mu        sync.Mutex
-	completed []minio.PartInfo
+	mu    sync.Mutex
+	parts map[int]uploadedPart
 }
 
-// MultipartUploadResult contains either an Error or the uploaded ObjectInfo
-type MultipartUploadResult struct {
-	Error error
-	Info  minio.ObjectInfo
+// uploadedPart is a part of a pending upload that finished uploading
+type uploadedPart struct {
+	info minio.PartInfo
+	part streams.Part
 }
 
 // NewMultipartUpload creates a new MultipartUpload
 func NewMultipartUpload(uploadID string, bucket, object string, metadata map[string]string) *MultipartUpload {
-	upload := &MultipartUpload{
+	return &MultipartUpload{
 		ID:       uploadID,
 		Bucket:   bucket,
 		Object:   object,
 		Metadata: metadata,
-		Done:     make(chan *MultipartUploadResult, 1),
-		Stream:   NewMultipartStream(),
+		parts:    map[int]uploadedPart{},
 	}
-	return upload
 }
 
-// addCompletedPart adds a completed part to the list
-func (upload *MultipartUpload) addCompletedPart(part minio.PartInfo) {
+// addPart adds an uploaded part, replacing an earlier upload of it
+func (upload *MultipartUpload) addPart(part uploadedPart) {
 	upload.mu.Lock()
 	defer upload.mu.Unlock()
 
-	upload.completed = append(upload.completed, part)
+	upload.parts[part.info.PartNumber] = part
 }
 
-func (upload *MultipartUpload) getCompletedParts() []minio.PartInfo {
+// removePart removes an uploaded part
+func (upload *MultipartUpload) removePart(number int) {
 	upload.mu.Lock()
 	defer upload.mu.Unlock()
 
-	return append([]minio.PartInfo{}, upload.completed...)
-}
-
-// fail aborts the upload with an error
-func (upload *MultipartUpload) fail(err error) {
-	upload.Done <- &MultipartUploadResult{Error: err}
-	close(upload.Done)
-}
-
-// complete completes the upload
-func (upload *MultipartUpload) complete(info minio.ObjectInfo) {
-	upload.Done <- &MultipartUploadResult{Info: info}
-	close(upload.Done)
-}
-
-// MultipartStream serializes multiple readers into a single reader
-type MultipartStream struct {
-	mu         sync.Mutex
-	moreParts  sync.Cond
-	err        error
-	closed     bool
-	finished   bool
-	nextID     int
-	nextNumber int
-	parts      []*StreamPart
-}
-
-// StreamPart is a reader waiting in MultipartStream
-type StreamPart struct {
-	Number int
-	ID     int
-	Size   int64
-	Reader *hash.Reader
-	Done   chan error
-}
-
-// NewMultipartStream creates a new MultipartStream
-func NewMultipartStream() *MultipartStream {
-	stream := &MultipartStream{}
-	stream.moreParts.L = &stream.mu
-	stream.nextID = 1
-	return stream
+	delete(upload.parts, number)
 }
 
-// Abort aborts the stream reading
-func (stream *MultipartStream) Abort(err error) {
-	stream.mu.Lock()
-	defer stream.mu.Unlock()
-
-	if stream.finished {
-		return
-	}
-
-	if stream.err == nil {
-		stream.err = err
-	}
-	stream.finished = true
-	stream.closed = true
-
-	for _, part := range stream.parts {
-		part.Done <- err
-		close(part.Done)
-	}
-	stream.parts = nil
-
-	stream.moreParts.Broadcast()
-}
-
-// Close closes the stream, but lets it complete
-func (stream *MultipartStream) Close() {
-	stream.mu.Lock()
-	defer stream.mu.Unlock()
-
-	stream.closed = true
-	stream.moreParts.Broadcast()
-}
-
-// Read implements io.Reader interface, blocking when there's no part
-func (stream *MultipartStream) Read(data []byte) (n int, err error) {
-	var part *StreamPart
-	stream.mu.Lock()
-	for {
-		// has an error occurred?
-		if stream.err != nil {
-			stream.mu.Unlock()
-			return 0, Error.Wrap(err)
-		}
-		// do we have the next part?
-		if len(stream.parts) > 0 && stream.nextID == stream.parts[0].ID {
-			part = stream.parts[0]
-			break
-		}
-		// we don't have the next part and are closed, hence we are complete
-		if stream.closed {
-			stream.finished = true
-			stream.mu.Unlock()
-			return 0, io.EOF
-		}
-
-		stream.moreParts.Wait()
-	}
-	stream.mu.Unlock()
-
-	// read as much as we can
-	n, err = part.Reader.Read(data)
-	atomic.AddInt64(&part.Size, int64(n))
-
-	if err == io.EOF {
-		// the part completed, hence advance to the next one
-		err = nil
-
-		stream.mu.Lock()
-		stream.parts = stream.parts[1:]
-		stream.nextID++
-		stream.mu.Unlock()
-
-		close(part.Done)
-	} else if err != nil {
-		// something bad happened, abort the whole thing
-		stream.Abort(err)
-		return n, Error.Wrap(err)
-	}
+// getPart returns the uploaded part number
+func (upload *MultipartUpload) getPart(number int) (uploadedPart, bool) {
+	upload.mu.Lock()
+	defer upload.mu.Unlock()
 
-	return n, err
+	part, ok := upload.parts[number]
+	return part, ok
 }
 
-// AddPart adds a new part to the stream to wait
-func (stream *MultipartStream) AddPart(partID int, data *hash.Reader) (*StreamPart, error) {
-	stream.mu.Lock()
-	defer stream.mu.Unlock()
-
-	for _, p := range stream.parts {
-		if p.ID == partID {
-			return nil, Error.New("Part %d already exists", partID)
-		}
-	}
+// getParts returns the uploaded parts ordered by part number
+func (upload *MultipartUpload) getParts() []uploadedPart {
+	upload.mu.Lock()
+	defer upload.mu.Unlock()
 
-	stream.nextNumber++
-	part := &StreamPart{
-		Number: stream.nextNumber - 1,
-		ID:     partID,
-		Size:   0,
-		Reader: data,
-		Done:   make(chan error, 1),
+	parts := make([]uploadedPart, 0, len(upload.parts))
+	for _, part := range upload.parts {
+		parts = append(parts, part)
 	}
-
-	stream.parts = append(stream.parts, part)
-	sort.Slice(stream.parts, func(i, k int) bool {
-		return stream.parts[i].ID < stream.parts[k].ID
+	sort.Slice(parts, func(i, k int) bool {
+		return parts[i].info.PartNumber < parts[k].info.PartNumber
 	})
-
-	stream.moreParts.Broadcast()
-
-	return part, nil
+	return parts
 }
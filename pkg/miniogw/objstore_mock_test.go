@@ -18,6 +18,7 @@ import (
 	paths "storj.io/storj/pkg/paths"
 	ranger "storj.io/storj/pkg/ranger"
 	objects "storj.io/storj/pkg/storage/objects"
+	streams "storj.io/storj/pkg/storage/streams"
 )
 
 // MockStore is a mock of Store interface
@@ -43,6 +44,19 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// CommitParts mocks base method
+func (m *MockStore) CommitParts(arg0 context.Context, arg1 paths.Path, arg2 string, arg3 []streams.Part, arg4 objects.SerializableMeta, arg5 time.Time) (objects.Meta, error) {
+	ret := m.ctrl.Call(m, "CommitParts", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(objects.Meta)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CommitParts indicates an expected call of CommitParts
+func (mr *MockStoreMockRecorder) CommitParts(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommitParts", reflect.TypeOf((*MockStore)(nil).CommitParts), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
 // Delete mocks base method
 func (m *MockStore) Delete(arg0 context.Context, arg1 paths.Path) error {
 	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
@@ -55,6 +69,18 @@ func (mr *MockStoreMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockStore)(nil).Delete), arg0, arg1)
 }
 
+// DeleteParts mocks base method
+func (m *MockStore) DeleteParts(arg0 context.Context, arg1 paths.Path, arg2 string, arg3 []streams.Part) error {
+	ret := m.ctrl.Call(m, "DeleteParts", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteParts indicates an expected call of DeleteParts
+func (mr *MockStoreMockRecorder) DeleteParts(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteParts", reflect.TypeOf((*MockStore)(nil).DeleteParts), arg0, arg1, arg2, arg3)
+}
+
 // Get mocks base method
 func (m *MockStore) Get(arg0 context.Context, arg1 paths.Path) (ranger.Ranger, objects.Meta, error) {
 	ret := m.ctrl.Call(m, "Get", arg0, arg1)
@@ -108,3 +134,16 @@ func (m *MockStore) Put(arg0 context.Context, arg1 paths.Path, arg2 io.Reader, a
 func (mr *MockStoreMockRecorder) Put(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockStore)(nil).Put), arg0, arg1, arg2, arg3, arg4)
 }
+
+// PutPart mocks base method
+func (m *MockStore) PutPart(arg0 context.Context, arg1 paths.Path, arg2 string, arg3 int, arg4 io.Reader, arg5 time.Time) (streams.Part, error) {
+	ret := m.ctrl.Call(m, "PutPart", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(streams.Part)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PutPart indicates an expected call of PutPart
+func (mr *MockStoreMockRecorder) PutPart(arg0, arg1, arg2, arg3, arg4, arg5 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PutPart", reflect.TypeOf((*MockStore)(nil).PutPart), arg0, arg1, arg2, arg3, arg4, arg5)
+}
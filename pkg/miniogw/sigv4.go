@@ -0,0 +1,428 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AWS signature version 4 constants
+const (
+	signV4Algorithm  = "AWS4-HMAC-SHA256"
+	chunkAlgorithm   = "AWS4-HMAC-SHA256-PAYLOAD"
+	streamingPayload = "STREAMING-AWS4-HMAC-SHA256-PAYLOAD"
+	unsignedPayload  = "UNSIGNED-PAYLOAD"
+	emptySHA256      = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	amzDateFormat   = "20060102T150405Z"
+	scopeDateFormat = "20060102"
+
+	// maxClockSkew is how far the date of a request signed in its header may
+	// be off the clock of the gateway
+	maxClockSkew = 15 * time.Minute
+	// maxPresignedExpiry is the longest a presigned request may be valid
+	maxPresignedExpiry = 7 * 24 * time.Hour
+	// maxChunkSize bounds the chunks of a payload signed chunk by chunk,
+	// which are buffered until their signature is verified
+	maxChunkSize = 16 << 20
+)
+
+// signature is the AWS signature version 4 of a request, either from its
+// Authorization header or from the query of a presigned URL
+type signature struct {
+	accessKeyID   string
+	amzDate       string
+	date          time.Time
+	scopeDate     string
+	region        string
+	service       string
+	signedHeaders []string
+	signature     string
+	presigned     bool
+	// expires is how long a presigned request is valid
+	expires time.Duration
+}
+
+// parseSignature returns the signature of r, without verifying it
+func parseSignature(r *http.Request) (*signature, error) {
+	var credential, signedHeaders string
+	sig := &signature{}
+
+	query := r.URL.Query()
+	if authorization := r.Header.Get("Authorization"); authorization != "" {
+		if !strings.HasPrefix(authorization, signV4Algorithm+" ") {
+			return nil, newAPIError(http.StatusBadRequest, "InvalidRequest",
+				"only AWS signature version 4 is supported")
+		}
+		for _, field := range strings.Split(strings.TrimPrefix(authorization, signV4Algorithm+" "), ",") {
+			field = strings.TrimSpace(field)
+			switch {
+			case strings.HasPrefix(field, "Credential="):
+				credential = strings.TrimPrefix(field, "Credential=")
+			case strings.HasPrefix(field, "SignedHeaders="):
+				signedHeaders = strings.TrimPrefix(field, "SignedHeaders=")
+			case strings.HasPrefix(field, "Signature="):
+				sig.signature = strings.TrimPrefix(field, "Signature=")
+			}
+		}
+		sig.amzDate = r.Header.Get("X-Amz-Date")
+	} else if query.Get("X-Amz-Algorithm") != "" {
+		if query.Get("X-Amz-Algorithm") != signV4Algorithm {
+			return nil, newAPIError(http.StatusBadRequest, "InvalidRequest",
+				"only AWS signature version 4 is supported")
+		}
+		sig.presigned = true
+		credential = query.Get("X-Amz-Credential")
+		signedHeaders = query.Get("X-Amz-SignedHeaders")
+		sig.signature = query.Get("X-Amz-Signature")
+		sig.amzDate = query.Get("X-Amz-Date")
+
+		expires, err := strconv.ParseInt(query.Get("X-Amz-Expires"), 10, 64)
+		if err != nil || expires < 0 {
+			return nil, newAPIError(http.StatusBadRequest, "AuthorizationQueryParametersError",
+				"X-Amz-Expires must be a non-negative number of seconds")
+		}
+		sig.expires = time.Duration(expires) * time.Second
+		if sig.expires > maxPresignedExpiry {
+			return nil, newAPIError(http.StatusBadRequest, "AuthorizationQueryParametersError",
+				"X-Amz-Expires must be less than a week")
+		}
+	} else {
+		return nil, newAPIError(http.StatusForbidden, "AccessDenied", "anonymous access is not allowed")
+	}
+
+	if credential == "" || signedHeaders == "" || sig.signature == "" {
+		return nil, newAPIError(http.StatusBadRequest, "AuthorizationHeaderMalformed",
+			"the credential, signed headers and signature are required")
+	}
+
+	// the credential is the access key id followed by the scope of the
+	// signing key: date/region/service/aws4_request
+	parts := strings.Split(credential, "/")
+	if len(parts) < 5 || parts[len(parts)-1] != "aws4_request" {
+		return nil, newAPIError(http.StatusBadRequest, "AuthorizationHeaderMalformed",
+			"invalid credential %q", credential)
+	}
+	sig.accessKeyID = strings.Join(parts[:len(parts)-4], "/")
+	sig.scopeDate = parts[len(parts)-4]
+	sig.region = parts[len(parts)-3]
+	sig.service = parts[len(parts)-2]
+	if sig.service != "s3" {
+		return nil, newAPIError(http.StatusBadRequest, "AuthorizationHeaderMalformed",
+			"the credential is for service %q instead of s3", sig.service)
+	}
+
+	date, err := time.Parse(amzDateFormat, sig.amzDate)
+	if err != nil {
+		return nil, newAPIError(http.StatusForbidden, "AccessDenied", "X-Amz-Date is missing or invalid")
+	}
+	sig.date = date
+	if date.Format(scopeDateFormat) != sig.scopeDate {
+		return nil, newAPIError(http.StatusBadRequest, "AuthorizationHeaderMalformed",
+			"the credential date doesn't match X-Amz-Date")
+	}
+
+	sig.signedHeaders = strings.Split(signedHeaders, ";")
+	hasHost := false
+	for _, header := range sig.signedHeaders {
+		if header == "host" {
+			hasHost = true
+		}
+	}
+	if !hasHost {
+		return nil, newAPIError(http.StatusBadRequest, "AuthorizationHeaderMalformed",
+			"the host header must be signed")
+	}
+
+	return sig, nil
+}
+
+// verify verifies the signature of r with secretKey at time now. The body of
+// r is replaced with one that fails to be read if the payload doesn't match
+// its signed hash, and with the decoded payload if it's signed chunk by
+// chunk.
+func (sig *signature) verify(r *http.Request, secretKey string, now time.Time) error {
+	if sig.presigned {
+		if now.Before(sig.date.Add(-maxClockSkew)) {
+			return newAPIError(http.StatusForbidden, "AccessDenied", "the request is not valid yet")
+		}
+		if now.After(sig.date.Add(sig.expires)) {
+			return newAPIError(http.StatusForbidden, "AccessDenied", "the request has expired")
+		}
+	} else if skew := now.Sub(sig.date); skew > maxClockSkew || skew < -maxClockSkew {
+		return newAPIError(http.StatusForbidden, "RequestTimeTooSkewed",
+			"the difference between the request time and the current time is too large")
+	}
+
+	payload := unsignedPayload
+	if !sig.presigned {
+		payload = r.Header.Get("X-Amz-Content-Sha256")
+		if payload == "" {
+			return newAPIError(http.StatusBadRequest, "InvalidRequest", "missing required header X-Amz-Content-Sha256")
+		}
+	}
+
+	key := sig.signingKey(secretKey)
+	expected := hex.EncodeToString(hmacSHA256(key, sig.stringToSign(sig.canonicalRequest(r, payload))))
+	if !hmac.Equal([]byte(expected), []byte(sig.signature)) {
+		return newAPIError(http.StatusForbidden, "SignatureDoesNotMatch",
+			"the request signature doesn't match the signature calculated with the secret key")
+	}
+
+	switch payload {
+	case unsignedPayload:
+	case streamingPayload:
+		size, err := strconv.ParseInt(r.Header.Get("X-Amz-Decoded-Content-Length"), 10, 64)
+		if err != nil || size < 0 {
+			return newAPIError(http.StatusLengthRequired, "MissingContentLength",
+				"X-Amz-Decoded-Content-Length is required for payloads signed chunk by chunk")
+		}
+		r.ContentLength = size
+		r.Body = &chunkedReader{
+			body:     r.Body,
+			r:        bufio.NewReader(r.Body),
+			key:      key,
+			sig:      sig,
+			previous: sig.signature,
+		}
+	default:
+		sum, err := hex.DecodeString(payload)
+		if err != nil || len(sum) != sha256.Size {
+			return newAPIError(http.StatusBadRequest, "InvalidArgument", "X-Amz-Content-Sha256 is invalid")
+		}
+		r.Body = &digestReader{body: r.Body, hash: sha256.New(), sum: sum,
+			err: newAPIError(http.StatusBadRequest, "XAmzContentSHA256Mismatch",
+				"the payload doesn't match X-Amz-Content-Sha256")}
+	}
+	return nil
+}
+
+// scope returns the scope of the signing key
+func (sig *signature) scope() string {
+	return strings.Join([]string{sig.scopeDate, sig.region, sig.service, "aws4_request"}, "/")
+}
+
+// signingKey derives the signing key of the scope from secretKey
+func (sig *signature) signingKey(secretKey string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretKey), []byte(sig.scopeDate))
+	key = hmacSHA256(key, []byte(sig.region))
+	key = hmacSHA256(key, []byte(sig.service))
+	return hmacSHA256(key, []byte("aws4_request"))
+}
+
+// stringToSign returns the string signed for the canonical request
+func (sig *signature) stringToSign(canonicalRequest string) []byte {
+	sum := sha256.Sum256([]byte(canonicalRequest))
+	return []byte(strings.Join([]string{
+		signV4Algorithm, sig.amzDate, sig.scope(), hex.EncodeToString(sum[:]),
+	}, "\n"))
+}
+
+// canonicalRequest returns the canonical form of r with the hash of its
+// payload that is signed
+func (sig *signature) canonicalRequest(r *http.Request, payload string) string {
+	var headers bytes.Buffer
+	for _, name := range sig.signedHeaders {
+		headers.WriteString(name)
+		headers.WriteByte(':')
+		headers.WriteString(headerValue(r, name))
+		headers.WriteByte('\n')
+	}
+
+	return strings.Join([]string{
+		r.Method,
+		uriEncode(r.URL.Path, false),
+		canonicalQuery(r.URL.RawQuery, sig.presigned),
+		headers.String(),
+		strings.Join(sig.signedHeaders, ";"),
+		payload,
+	}, "\n")
+}
+
+// headerValue returns the canonical value of the header name of r
+func headerValue(r *http.Request, name string) string {
+	switch name {
+	case "host":
+		return r.Host
+	case "content-length":
+		if r.Header.Get("Content-Length") == "" && r.ContentLength >= 0 {
+			return strconv.FormatInt(r.ContentLength, 10)
+		}
+	case "transfer-encoding":
+		return strings.Join(r.TransferEncoding, ",")
+	}
+
+	values := r.Header[http.CanonicalHeaderKey(name)]
+	trimmed := make([]string, len(values))
+	for i, value := range values {
+		trimmed[i] = strings.Join(strings.Fields(value), " ")
+	}
+	return strings.Join(trimmed, ",")
+}
+
+// canonicalQuery returns the canonical form of a query, leaving out the
+// signature of presigned requests
+func canonicalQuery(rawQuery string, presigned bool) string {
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		// an unparsable query can't match the signature anyway
+		return rawQuery
+	}
+
+	var pairs []string
+	for key, values := range query {
+		if presigned && key == "X-Amz-Signature" {
+			continue
+		}
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode encodes s like AWS does for signatures, leaving slashes as they
+// are unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	const hexDigits = "0123456789ABCDEF"
+
+	var encoded bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !encodeSlash:
+			encoded.WriteByte(c)
+		default:
+			encoded.WriteByte('%')
+			encoded.WriteByte(hexDigits[c>>4])
+			encoded.WriteByte(hexDigits[c&15])
+		}
+	}
+	return encoded.String()
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// digestReader reads body, failing with err at its end if its hash doesn't
+// match sum
+type digestReader struct {
+	body io.ReadCloser
+	hash hash.Hash
+	sum  []byte
+	err  error
+}
+
+func (r *digestReader) Read(p []byte) (n int, err error) {
+	n, err = r.body.Read(p)
+	_, _ = r.hash.Write(p[:n])
+	if err == io.EOF && !bytes.Equal(r.hash.Sum(nil), r.sum) {
+		return n, r.err
+	}
+	return n, err
+}
+
+func (r *digestReader) Close() error {
+	return r.body.Close()
+}
+
+// chunkedReader decodes a payload that is signed chunk by chunk. Every
+// chunk is verified before it's read.
+type chunkedReader struct {
+	body     io.ReadCloser
+	r        *bufio.Reader
+	key      []byte
+	sig      *signature
+	previous string // the signature of the previous chunk
+
+	chunk []byte // the rest of the verified chunk
+	buf   []byte
+	err   error
+}
+
+func (r *chunkedReader) Read(p []byte) (n int, err error) {
+	for len(r.chunk) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		r.err = r.next()
+	}
+	n = copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+	return n, nil
+}
+
+func (r *chunkedReader) Close() error {
+	return r.body.Close()
+}
+
+// next reads and verifies the next chunk, which looks like
+// hex(size);chunk-signature=signature\r\ndata\r\n. The last chunk is empty.
+func (r *chunkedReader) next() error {
+	malformed := newAPIError(http.StatusBadRequest, "IncompleteBody", "the chunked payload is malformed")
+
+	line, err := r.r.ReadSlice('\n')
+	if err != nil {
+		return malformed
+	}
+	header := strings.TrimSuffix(string(line), "\r\n")
+	sizeField, signatureField := header, ""
+	if i := strings.IndexByte(header, ';'); i >= 0 {
+		sizeField, signatureField = header[:i], header[i+1:]
+	}
+	if !strings.HasPrefix(signatureField, "chunk-signature=") {
+		return malformed
+	}
+	size, err := strconv.ParseInt(sizeField, 16, 64)
+	if err != nil || size < 0 || size > maxChunkSize {
+		return malformed
+	}
+
+	if int64(cap(r.buf)) < size {
+		r.buf = make([]byte, size)
+	}
+	data := r.buf[:size]
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return malformed
+	}
+	var crlf [2]byte
+	if _, err := io.ReadFull(r.r, crlf[:]); err != nil || string(crlf[:]) != "\r\n" {
+		return malformed
+	}
+
+	sum := sha256.Sum256(data)
+	stringToSign := strings.Join([]string{
+		chunkAlgorithm, r.sig.amzDate, r.sig.scope(), r.previous, emptySHA256, hex.EncodeToString(sum[:]),
+	}, "\n")
+	expected := hex.EncodeToString(hmacSHA256(r.key, []byte(stringToSign)))
+	chunkSignature := strings.TrimPrefix(signatureField, "chunk-signature=")
+	if !hmac.Equal([]byte(expected), []byte(chunkSignature)) {
+		return newAPIError(http.StatusForbidden, "SignatureDoesNotMatch",
+			"the signature of a chunk of the payload doesn't match")
+	}
+	r.previous = chunkSignature
+
+	if size == 0 {
+		return io.EOF
+	}
+	r.chunk = data
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	minio "github.com/minio/minio/cmd"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/utils"
+)
+
+// tagsKey is the user defined metadata key that the tags of an object are
+// stored under, encoded like the X-Amz-Tagging header. The metadata is
+// stored with the object, so the tags are protected like the rest of it.
+const tagsKey = "X-Amz-Tagging"
+
+// S3 limits on object tags
+const (
+	maxTags           = 10
+	maxTagKeyLength   = 128
+	maxTagValueLength = 256
+)
+
+// encodeTags encodes tags like the X-Amz-Tagging header
+func encodeTags(tags map[string]string) (string, error) {
+	if len(tags) > maxTags {
+		return "", Error.New("an object can have at most %d tags", maxTags)
+	}
+	values := url.Values{}
+	for key, value := range tags {
+		if key == "" || utf8.RuneCountInString(key) > maxTagKeyLength {
+			return "", Error.New("invalid tag key %q", key)
+		}
+		if utf8.RuneCountInString(value) > maxTagValueLength {
+			return "", Error.New("the value of tag %q is longer than %d characters", key, maxTagValueLength)
+		}
+		values.Set(key, value)
+	}
+	return values.Encode(), nil
+}
+
+// decodeTags returns the tags in the user defined metadata of an object
+func decodeTags(userDefined map[string]string) (map[string]string, error) {
+	values, err := url.ParseQuery(userDefined[tagsKey])
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	tags := make(map[string]string, len(values))
+	for key := range values {
+		tags[key] = values.Get(key)
+	}
+	return tags, nil
+}
+
+// hasTags returns whether the object with the user defined metadata has all
+// the tag keys
+func hasTags(userDefined map[string]string, keys []string) bool {
+	if len(keys) == 0 {
+		return true
+	}
+	tags, err := decodeTags(userDefined)
+	if err != nil {
+		return false
+	}
+	for _, key := range keys {
+		if _, ok := tags[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// GetObjectTagging returns the tags of an object
+func (s *storjObjects) GetObjectTagging(ctx context.Context, bucket, object string) (tags map[string]string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	info, err := s.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return nil, err
+	}
+	return decodeTags(info.UserDefined)
+}
+
+// PutObjectTagging replaces the tags of an object. Objects can't be changed
+// in place, so the object is copied onto itself with the new tags, like S3
+// clients do to replace other metadata.
+func (s *storjObjects) PutObjectTagging(ctx context.Context, bucket, object string, tags map[string]string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	encoded, err := encodeTags(tags)
+	if err != nil {
+		return err
+	}
+	return s.replaceTags(ctx, bucket, object, encoded)
+}
+
+// DeleteObjectTagging removes the tags of an object
+func (s *storjObjects) DeleteObjectTagging(ctx context.Context, bucket, object string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return s.replaceTags(ctx, bucket, object, "")
+}
+
+// replaceTags copies an object onto itself with the encoded tags
+func (s *storjObjects) replaceTags(ctx context.Context, bucket, object, encoded string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	info, err := s.GetObjectInfo(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+
+	userDefined := make(map[string]string, len(info.UserDefined)+1)
+	for key, value := range info.UserDefined {
+		userDefined[key] = value
+	}
+	if encoded == "" {
+		delete(userDefined, tagsKey)
+	} else {
+		userDefined[tagsKey] = encoded
+	}
+
+	rr, err := s.getObject(ctx, bucket, object)
+	if err != nil {
+		return err
+	}
+	r, err := rr.Range(ctx, 0, rr.Size())
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(r)
+
+	_, err = s.putObject(ctx, bucket, object, r, objects.SerializableMeta{
+		ContentType: info.ContentType,
+		UserDefined: userDefined,
+	})
+	return err
+}
+
+// ListObjectsWithTags lists objects like ListObjects, leaving out the
+// objects that don't have all the tag keys. The filter is applied to each
+// page, so pages can hold fewer than maxKeys objects even if more follow.
+func (s *storjObjects) ListObjectsWithTags(ctx context.Context, bucket, prefix, marker, delimiter string,
+	maxKeys int, keys []string) (result minio.ListObjectsInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	result, err = s.ListObjects(ctx, bucket, prefix, marker, delimiter, maxKeys)
+	if err != nil {
+		return result, err
+	}
+
+	filtered := result.Objects[:0]
+	for _, object := range result.Objects {
+		if hasTags(object.UserDefined, keys) {
+			filtered = append(filtered, object)
+		}
+	}
+	result.Objects = filtered
+	return result, nil
+}
+
+// TaggingHandler serves the ?tagging requests of objects, which the vendored
+// minio doesn't route to the object layer, in front of the minio router,
+// and passes the other requests on to it. The requests it serves are
+// authenticated with the credentials of the gateway, like minio
+// authenticates the others.
+type TaggingHandler struct {
+	log       *zap.Logger
+	objects   *storjObjects
+	accessKey string
+	secretKey string
+	minio     http.Handler
+}
+
+// NewTaggingHandler creates a handler serving the ?tagging requests with the
+// objects of gw in front of the minio router
+func NewTaggingHandler(log *zap.Logger, gw *Storj, accessKey, secretKey string, minio http.Handler) *TaggingHandler {
+	return &TaggingHandler{
+		log:       log,
+		objects:   &storjObjects{storj: gw, ranges: gw.ranges},
+		accessKey: accessKey,
+		secretKey: secretKey,
+		minio:     minio,
+	}
+}
+
+// ServeHTTP serves the ?tagging requests of objects and passes the other
+// requests on to minio
+func (h *TaggingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	i := strings.IndexByte(path, '/')
+	if _, tagging := r.URL.Query()["tagging"]; !tagging || i <= 0 || i == len(path)-1 {
+		h.minio.ServeHTTP(w, r)
+		return
+	}
+
+	ctx := r.Context()
+	var err error
+	defer mon.Task()(&ctx)(&err)
+
+	if err = h.authenticate(r); err == nil {
+		err = serveTagging(ctx, w, r, h.objects, path[:i], path[i+1:])
+	}
+	if err != nil {
+		writeError(h.log, w, r, err)
+	}
+}
+
+// authenticate verifies the signature of r with the credentials of the
+// gateway
+func (h *TaggingHandler) authenticate(r *http.Request) error {
+	sig, err := parseSignature(r)
+	if err != nil {
+		return err
+	}
+	if sig.accessKeyID != h.accessKey {
+		return newAPIError(http.StatusForbidden, "InvalidAccessKeyId",
+			"the access key id %s doesn't exist", sig.accessKeyID)
+	}
+	return sig.verify(r, h.secretKey, time.Now())
+}
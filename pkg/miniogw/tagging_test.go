@@ -0,0 +1,42 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTags(t *testing.T) {
+	encoded, err := encodeTags(map[string]string{"project": "a b", "team": "storage&ops", "empty": ""})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "empty=&project=a+b&team=storage%26ops", encoded)
+
+	userDefined := map[string]string{tagsKey: encoded, "X-Amz-Meta-Other": "x"}
+	tags, err := decodeTags(userDefined)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"project": "a b", "team": "storage&ops", "empty": ""}, tags)
+
+	assert.True(t, hasTags(userDefined, nil))
+	assert.True(t, hasTags(userDefined, []string{"empty", "team"}))
+	assert.False(t, hasTags(userDefined, []string{"team", "missing"}))
+	assert.False(t, hasTags(map[string]string{}, []string{"team"}))
+
+	tooMany := map[string]string{}
+	for _, key := range strings.Split("abcdefghijk", "") {
+		tooMany[key] = ""
+	}
+	_, err = encodeTags(tooMany)
+	assert.Error(t, err)
+
+	_, err = encodeTags(map[string]string{"": "value"})
+	assert.Error(t, err)
+
+	_, err = encodeTags(map[string]string{"key": strings.Repeat("v", maxTagValueLength+1)})
+	assert.Error(t, err)
+}
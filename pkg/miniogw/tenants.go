@@ -0,0 +1,272 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package miniogw
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storage/buckets"
+)
+
+// watchRetryDelay is how long to wait before watching the revocations of
+// credentials again after the stream broke
+const watchRetryDelay = 5 * time.Second
+
+// Tenants resolves the S3 credentials of the users of a hosted gateway to
+// the access grants the credential service stored for them. Resolved
+// tenants are resolved again after ttl, at most maxTenants of them are kept,
+// and they are forgotten when the credential service revokes their
+// credentials.
+type Tenants struct {
+	log         *zap.Logger
+	credentials pb.CredentialsClient
+	newStore    func(ctx context.Context, grant *pb.AccessGrant) (buckets.Store, func() error, error)
+	ttl         time.Duration
+	maxTenants  int
+
+	mu      sync.Mutex
+	tenants map[string]*tenant
+}
+
+// tenant is a user of a hosted gateway. Its expiry, last use, users and
+// removal are guarded by the mutex of Tenants.
+type tenant struct {
+	accessKeyID string
+	secretKey   string
+	grant       *pb.AccessGrant
+	storj       *Storj
+	close       func() error
+
+	expires  time.Time
+	lastUsed time.Time
+	// users is how many requests use the tenant. The store of a removed
+	// tenant is closed once the last of them is done.
+	users   int
+	removed bool
+}
+
+// NewTenants creates a resolver of S3 credentials that accesses the buckets
+// of a tenant with the bucket store newStore returns for its access grant,
+// and closes the store with the function returned with it once the tenant
+// is forgotten
+func NewTenants(log *zap.Logger, credentials pb.CredentialsClient,
+	newStore func(ctx context.Context, grant *pb.AccessGrant) (buckets.Store, func() error, error),
+	ttl time.Duration, maxTenants int) *Tenants {
+	return &Tenants{
+		log:         log,
+		credentials: credentials,
+		newStore:    newStore,
+		ttl:         ttl,
+		maxTenants:  maxTenants,
+		tenants:     map[string]*tenant{},
+	}
+}
+
+// acquire returns the tenant with the S3 access key id accessKeyID. It must
+// be released once the caller is done with it.
+func (t *Tenants) acquire(ctx context.Context, accessKeyID string) (tn *tenant, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	now := time.Now()
+	t.mu.Lock()
+	cached, ok := t.tenants[accessKeyID]
+	if ok && now.Before(cached.expires) {
+		cached.users++
+		cached.lastUsed = now
+		t.mu.Unlock()
+		return cached, nil
+	}
+	t.mu.Unlock()
+
+	resp, err := t.credentials.Resolve(ctx, &pb.ResolveCredentialsRequest{AccessKeyId: accessKeyID})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			t.Revoke(accessKeyID)
+		}
+		return nil, err
+	}
+
+	// a tenant whose credentials didn't change is kept, so that its pending
+	// multipart uploads aren't lost
+	t.mu.Lock()
+	tn = t.reuse(accessKeyID, resp, now)
+	t.mu.Unlock()
+	if tn != nil {
+		return tn, nil
+	}
+
+	bs, closeStore, err := t.newStore(ctx, resp.GetGrant())
+	if err != nil {
+		return nil, err
+	}
+	tn = &tenant{
+		accessKeyID: accessKeyID,
+		secretKey:   resp.GetSecretKey(),
+		grant:       resp.GetGrant(),
+		storj:       NewStorjGateway(bs, resp.GetGrant().GetPartnerId()),
+		close:       closeStore,
+		expires:     now.Add(t.ttl),
+		lastUsed:    now,
+		users:       1,
+	}
+
+	t.mu.Lock()
+	if other := t.reuse(accessKeyID, resp, now); other != nil {
+		// a concurrent request created the tenant first
+		t.mu.Unlock()
+		t.closeStore(tn)
+		return other, nil
+	}
+	defer t.mu.Unlock()
+	if other, ok := t.tenants[accessKeyID]; ok {
+		t.remove(other)
+	}
+	t.tenants[accessKeyID] = tn
+	t.evict()
+	return tn, nil
+}
+
+// reuse extends the cached tenant with accessKeyID if it was resolved with
+// the same credentials as resp, and returns it acquired. t.mu must be held.
+func (t *Tenants) reuse(accessKeyID string, resp *pb.ResolveCredentialsResponse, now time.Time) *tenant {
+	tn, ok := t.tenants[accessKeyID]
+	if !ok || tn.secretKey != resp.GetSecretKey() || !proto.Equal(tn.grant, resp.GetGrant()) {
+		return nil
+	}
+	tn.expires = now.Add(t.ttl)
+	tn.lastUsed = now
+	tn.users++
+	return tn
+}
+
+// release marks a use of an acquired tenant done
+func (t *Tenants) release(tn *tenant) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tn.users--
+	if tn.removed && tn.users == 0 {
+		t.closeStore(tn)
+	}
+}
+
+// Revoke forgets the tenant with the S3 access key id accessKeyID
+func (t *Tenants) Revoke(accessKeyID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if tn, ok := t.tenants[accessKeyID]; ok {
+		t.remove(tn)
+	}
+}
+
+// Watch forgets the tenants whose credentials the credential service
+// revokes until ctx is canceled. While the revocations aren't watched, the
+// tenants are resolved again before they are used.
+func (t *Tenants) Watch(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for {
+		err := t.watch(ctx)
+		// credentials may be revoked until the revocations are watched again
+		t.expireAll()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		t.log.Warn("watching revoked credentials failed", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(watchRetryDelay):
+		}
+	}
+}
+
+// watch forgets the tenants whose credentials are revoked until the stream
+// of revocations breaks
+func (t *Tenants) watch(ctx context.Context) error {
+	stream, err := t.credentials.WatchRevocations(ctx, &pb.WatchRevocationsRequest{})
+	if err != nil {
+		return err
+	}
+	// the tenants resolved before may have been revoked before the stream
+	// was opened
+	t.expireAll()
+
+	for {
+		revoked, err := stream.Recv()
+		if err != nil {
+			return err
+		}
+		t.Revoke(revoked.GetAccessKeyId())
+	}
+}
+
+// expireAll makes the tenants be resolved again before they are used
+func (t *Tenants) expireAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, tn := range t.tenants {
+		tn.expires = time.Time{}
+	}
+}
+
+// evict removes the least recently used tenants while there are more than
+// maxTenants. Tenants with pending multipart uploads are only evicted if
+// all tenants have some. t.mu must be held.
+func (t *Tenants) evict() {
+	for len(t.tenants) > t.maxTenants {
+		var victim *tenant
+		for _, tn := range t.tenants {
+			if victim == nil || evictBefore(tn, victim) {
+				victim = tn
+			}
+		}
+		t.remove(victim)
+	}
+}
+
+// evictBefore returns whether tenant a should be evicted before tenant b
+func evictBefore(a, b *tenant) bool {
+	aPending, bPending := a.storj.multipart.hasPending(), b.storj.multipart.hasPending()
+	if aPending != bPending {
+		return bPending
+	}
+	return a.lastUsed.Before(b.lastUsed)
+}
+
+// remove forgets a tenant, closing its store if it isn't used. t.mu must be
+// held.
+func (t *Tenants) remove(tn *tenant) {
+	if t.tenants[tn.accessKeyID] == tn {
+		delete(t.tenants, tn.accessKeyID)
+	}
+	if tn.removed {
+		return
+	}
+	tn.removed = true
+	if tn.users == 0 {
+		t.closeStore(tn)
+	}
+}
+
+// closeStore closes the store of a removed tenant
+func (t *Tenants) closeStore(tn *tenant) {
+	if tn.close == nil {
+		return
+	}
+	if err := tn.close(); err != nil {
+		t.log.Warn("closing the store of a tenant failed", zap.String("access key id", tn.accessKeyID), zap.Error(err))
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package multinode
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"storj.io/storj/pkg/nodeapi"
+	"storj.io/storj/pkg/notification"
+)
+
+// Client reads the node API of a storage node
+type Client struct {
+	node   Node
+	client *http.Client
+}
+
+// NewClient creates a Client reading the node API of node with client
+func NewClient(node Node, client *http.Client) *Client {
+	return &Client{node: node, client: client}
+}
+
+// Node returns the id of the node and its limits
+func (c *Client) Node(ctx context.Context) (node *nodeapi.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+	node = &nodeapi.Node{}
+	return node, c.get(ctx, "node", node)
+}
+
+// Space returns the disk space used and available on the node
+func (c *Client) Space(ctx context.Context) (space *nodeapi.Space, err error) {
+	defer mon.Task()(&ctx)(&err)
+	space = &nodeapi.Space{}
+	return space, c.get(ctx, "space", space)
+}
+
+// Bandwidth returns the bandwidth used by the node this month
+func (c *Client) Bandwidth(ctx context.Context) (bandwidth *nodeapi.Bandwidth, err error) {
+	defer mon.Task()(&ctx)(&err)
+	bandwidth = &nodeapi.Bandwidth{}
+	return bandwidth, c.get(ctx, "bandwidth", bandwidth)
+}
+
+// Notifications returns the notifications the node received, newest first.
+// It returns an ErrNotFound error if the node doesn't receive notifications.
+func (c *Client) Notifications(ctx context.Context) (notifications []notification.Notification, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = c.get(ctx, "notifications", &notifications)
+	return notifications, err
+}
+
+// get decodes the response of the endpoint of the node API into v
+func (c *Client) get(ctx context.Context, endpoint string, v interface{}) error {
+	url := strings.TrimSuffix(c.node.URL, "/") + nodeapi.Prefix + endpoint
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.node.Token)
+
+	resp, err := c.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound.New("%s", endpoint)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return Error.New("%s: %s", endpoint, resp.Status)
+	}
+	return Error.Wrap(json.NewDecoder(resp.Body).Decode(v))
+}
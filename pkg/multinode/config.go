@@ -0,0 +1,56 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package multinode
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Config contains everything necessary to run the multinode service
+type Config struct {
+	Address   string        `help:"address to serve the multinode dashboard API on" default:"127.0.0.1:7780"`
+	NodesPath string        `help:"path to the list of managed nodes and their API tokens" default:"$CONFDIR/nodes.json"`
+	Timeout   time.Duration `help:"how long to wait for the node API of each node" default:"10s"`
+}
+
+// Run serves the status of the managed nodes until ctx is canceled
+func (c Config) Run(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	nodes, err := LoadNodes(c.NodesPath)
+	if err != nil {
+		return err
+	}
+	log := zap.L().Named("multinode")
+	if len(nodes) == 0 {
+		log.Warn("no nodes to manage, add them with multinode add", zap.String("nodes path", c.NodesPath))
+	}
+
+	lis, err := net.Listen("tcp", c.Address)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if addr, ok := lis.Addr().(*net.TCPAddr); ok && !addr.IP.IsLoopback() {
+		log.Warn("multinode dashboard API is reachable from other hosts", zap.String("address", c.Address))
+	}
+
+	service := NewService(log, nodes, &http.Client{Timeout: c.Timeout})
+	server := &http.Server{Handler: service}
+	go func() {
+		<-ctx.Done()
+		_ = server.Close()
+	}()
+
+	log.Info("serving multinode dashboard API", zap.String("address", lis.Addr().String()), zap.Int("nodes", len(nodes)))
+	err = server.Serve(lis)
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return Error.Wrap(err)
+}
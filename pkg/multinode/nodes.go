@@ -0,0 +1,60 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package multinode
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default multinode errs class
+	Error = errs.Class("multinode error")
+	// ErrNotFound is returned for the endpoints a node doesn't serve
+	ErrNotFound = errs.Class("not found")
+)
+
+// Node is a storage node managed by the service
+type Node struct {
+	// Name identifies the node to its operator
+	Name string `json:"name"`
+	// URL is the base URL of the node API of the node, e.g.
+	// http://10.0.0.2:7778
+	URL string `json:"url"`
+	// Token is the API token the node issued to the service
+	Token string `json:"token"`
+}
+
+// LoadNodes reads the nodes stored at path. There are no nodes if the file
+// doesn't exist.
+func LoadNodes(path string) ([]Node, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	var nodes []Node
+	if err := json.Unmarshal(data, &nodes); err != nil {
+		return nil, Error.New("invalid nodes file %s: %v", path, err)
+	}
+	return nodes, nil
+}
+
+// SaveNodes stores nodes at path. The file holds the API tokens of the nodes,
+// so only its owner may read it.
+func SaveNodes(path string, nodes []Node) error {
+	data, err := json.MarshalIndent(nodes, "", "  ")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(ioutil.WriteFile(path, data, 0600))
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package multinode
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/nodeapi"
+	"storj.io/storj/pkg/notification"
+	"storj.io/storj/pkg/pb"
+)
+
+// NodeStatus is the status of a managed node, as reported by its node API.
+// The reports of offline nodes aren't set.
+type NodeStatus struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Online bool   `json:"online"`
+	// Error is why the status of the node couldn't be read
+	Error     string             `json:"error,omitempty"`
+	Node      *nodeapi.Node      `json:"node,omitempty"`
+	Space     *nodeapi.Space     `json:"space,omitempty"`
+	Bandwidth *nodeapi.Bandwidth `json:"bandwidth,omitempty"`
+	// Payouts are the payout notifications the node received, newest first
+	Payouts []notification.Notification `json:"payouts"`
+}
+
+// Totals sums the status of the online nodes. Sizes are in bytes.
+type Totals struct {
+	Nodes   int   `json:"nodes"`
+	Online  int   `json:"online"`
+	Used    int64 `json:"used"`
+	Ingress int64 `json:"ingress"`
+	Egress  int64 `json:"egress"`
+	// Available is the space the online nodes may still use, not counting
+	// the nodes where it's unknown
+	Available int64 `json:"available"`
+}
+
+// Status is the status of all managed nodes
+type Status struct {
+	Totals Totals       `json:"totals"`
+	Nodes  []NodeStatus `json:"nodes"`
+}
+
+// Service aggregates the status of many storage nodes for an operator
+// running a fleet of them. It serves it as JSON:
+//
+//	GET /api/v1/status  the totals and the status of each node
+type Service struct {
+	log    *zap.Logger
+	nodes  []Node
+	client *http.Client
+}
+
+// NewService creates a Service managing nodes, whose node APIs are read with
+// client
+func NewService(log *zap.Logger, nodes []Node, client *http.Client) *Service {
+	return &Service{log: log, nodes: nodes, client: client}
+}
+
+// Status reads the status of all nodes at once
+func (service *Service) Status(ctx context.Context) (status *Status, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	status = &Status{Nodes: make([]NodeStatus, len(service.nodes))}
+	var wg sync.WaitGroup
+	for i, node := range service.nodes {
+		wg.Add(1)
+		go func(i int, node Node) {
+			defer wg.Done()
+			status.Nodes[i] = service.nodeStatus(ctx, node)
+		}(i, node)
+	}
+	wg.Wait()
+
+	status.Totals.Nodes = len(status.Nodes)
+	for _, node := range status.Nodes {
+		if !node.Online {
+			continue
+		}
+		status.Totals.Online++
+		status.Totals.Used += node.Space.Used
+		if node.Space.Available > 0 {
+			status.Totals.Available += node.Space.Available
+		}
+		status.Totals.Ingress += node.Bandwidth.Ingress
+		status.Totals.Egress += node.Bandwidth.Egress
+	}
+	return status, nil
+}
+
+// nodeStatus reads the status of node
+func (service *Service) nodeStatus(ctx context.Context, node Node) NodeStatus {
+	status := NodeStatus{Name: node.Name, URL: node.URL, Payouts: []notification.Notification{}}
+	client := NewClient(node, service.client)
+
+	var err error
+	defer func() {
+		if err != nil {
+			service.log.Debug("node status unavailable", zap.String("node", node.Name), zap.Error(err))
+			status.Error = err.Error()
+		}
+	}()
+	if status.Node, err = client.Node(ctx); err != nil {
+		return status
+	}
+	if status.Space, err = client.Space(ctx); err != nil {
+		return status
+	}
+	if status.Bandwidth, err = client.Bandwidth(ctx); err != nil {
+		return status
+	}
+	notifications, err := client.Notifications(ctx)
+	switch {
+	case ErrNotFound.Has(err):
+		// the node doesn't receive notifications
+		err = nil
+	case err != nil:
+		return status
+	}
+	for _, n := range notifications {
+		if n.Type == pb.Notification_PAYOUT.String() {
+			status.Payouts = append(status.Payouts, n)
+		}
+	}
+	status.Online = true
+	return status
+}
+
+// ServeHTTP implements http.Handler
+func (service *Service) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if strings.TrimSuffix(req.URL.Path, "/") != "/api/v1/status" {
+		http.NotFound(w, req)
+		return
+	}
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	status, err := service.Status(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
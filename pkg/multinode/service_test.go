@@ -0,0 +1,117 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package multinode
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/nodeapi"
+	"storj.io/storj/pkg/notification"
+)
+
+// fakeNode serves the node API of a node with the token, used and
+// available space, and notifications
+func fakeNode(token string, used, available int64, notifications []notification.Notification) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer "+token {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		var result interface{}
+		switch req.URL.Path {
+		case "/api/v1/node":
+			result = nodeapi.Node{ID: "id-" + token, Wallet: "0x" + token}
+		case "/api/v1/space":
+			result = nodeapi.Space{Used: used, Available: available}
+		case "/api/v1/bandwidth":
+			result = nodeapi.Bandwidth{Ingress: used, Egress: 2 * used}
+		case "/api/v1/notifications":
+			if notifications == nil {
+				http.NotFound(w, req)
+				return
+			}
+			result = notifications
+		default:
+			http.NotFound(w, req)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(result)
+	}))
+}
+
+func TestStatus(t *testing.T) {
+	ctx := context.Background()
+
+	node1 := fakeNode("token1", 100, 1000, []notification.Notification{
+		{ID: "sat/1", Type: "PAYOUT", Title: "paid"},
+		{ID: "sat/2", Type: "INFO", Title: "hello"},
+	})
+	defer node1.Close()
+	// node2 doesn't receive notifications and doesn't know its free space
+	node2 := fakeNode("token2", 50, -1, nil)
+	defer node2.Close()
+
+	service := NewService(zap.NewNop(), []Node{
+		{Name: "node1", URL: node1.URL, Token: "token1"},
+		{Name: "node2", URL: node2.URL + "/", Token: "token2"},
+		{Name: "wrong-token", URL: node1.URL, Token: "token2"},
+	}, http.DefaultClient)
+
+	status, err := service.Status(ctx)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, Totals{Nodes: 3, Online: 2, Used: 150, Available: 1000, Ingress: 150, Egress: 300}, status.Totals)
+
+	if assert.Len(t, status.Nodes, 3) {
+		assert.True(t, status.Nodes[0].Online)
+		assert.Equal(t, "0xtoken1", status.Nodes[0].Node.Wallet)
+		if assert.Len(t, status.Nodes[0].Payouts, 1) {
+			assert.Equal(t, "paid", status.Nodes[0].Payouts[0].Title)
+		}
+
+		assert.True(t, status.Nodes[1].Online)
+		assert.Empty(t, status.Nodes[1].Payouts)
+
+		assert.False(t, status.Nodes[2].Online)
+		assert.Contains(t, status.Nodes[2].Error, "401")
+	}
+
+	// the status is served as JSON
+	w := httptest.NewRecorder()
+	service.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/status", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var served Status
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&served))
+	assert.Equal(t, status.Totals, served.Totals)
+}
+
+func TestNodes(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storj-multinode")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "nodes.json")
+	nodes, err := LoadNodes(path)
+	assert.NoError(t, err)
+	assert.Empty(t, nodes)
+
+	saved := []Node{{Name: "node1", URL: "http://10.0.0.2:7778", Token: "secret"}}
+	assert.NoError(t, SaveNodes(path, saved))
+	nodes, err = LoadNodes(path)
+	assert.NoError(t, err)
+	assert.Equal(t, saved, nodes)
+}
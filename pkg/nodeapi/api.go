@@ -0,0 +1,381 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodeapi
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/notification"
+	psserver "storj.io/storj/pkg/piecestore/rpc/server"
+	"storj.io/storj/pkg/piecestore/rpc/server/psdb"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default node API errs class
+	Error = errs.Class("node api error")
+)
+
+// Prefix is the path prefix of the endpoints of the current version of the
+// API. Fields are only ever added to its responses, so that dashboards built
+// on it keep working.
+const Prefix = "/api/v1/"
+
+// Node is the response of GET /api/v1/node
+type Node struct {
+	ID      string    `json:"id"`
+	Started time.Time `json:"started"`
+	// MaxBandwidth is the bandwidth limit of each upload and download in bytes
+	// per second, 0 if unlimited
+	MaxBandwidth int64 `json:"max_bandwidth"`
+	// Wallet is the address the payouts of the node are sent to, if
+	// configured
+	Wallet string `json:"wallet,omitempty"`
+	// Warnings are the problems the operator has to act on, e.g. failing the
+	// audits of a satellite, meant to be shown prominently
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Space is the response of GET /api/v1/space. Sizes are in bytes.
+type Space struct {
+	Used int64 `json:"used"`
+	// Available is the space uploads may still use, -1 if it's unknown
+	Available int64 `json:"available"`
+	// Satellites are the satellites disk space is allocated to
+	Satellites []SatelliteSpace `json:"satellites"`
+}
+
+// SatelliteSpace is the disk space allocated to a satellite and used by its
+// pieces
+type SatelliteSpace struct {
+	ID        string `json:"id"`
+	Allocated int64  `json:"allocated"`
+	Used      int64  `json:"used"`
+}
+
+// Bandwidth is the response of GET /api/v1/bandwidth, the bandwidth used
+// this calendar month in bytes. Caps of 0 are unlimited.
+type Bandwidth struct {
+	Since      time.Time `json:"since"`
+	Ingress    int64     `json:"ingress"`
+	Egress     int64     `json:"egress"`
+	Cap        int64     `json:"cap"`
+	IngressCap int64     `json:"ingress_cap"`
+	EgressCap  int64     `json:"egress_cap"`
+	// RepairIngress, RepairEgress and AuditEgress are the parts of the
+	// ingress and egress used by satellites to repair and audit segments
+	RepairIngress int64 `json:"repair_ingress"`
+	RepairEgress  int64 `json:"repair_egress"`
+	AuditEgress   int64 `json:"audit_egress"`
+	// Satellites are the satellites that used bandwidth this month or have
+	// bandwidth allocated
+	Satellites []SatelliteBandwidth `json:"satellites"`
+}
+
+// SatelliteBandwidth is the bandwidth used by a satellite this month and
+// allocated to it
+type SatelliteBandwidth struct {
+	ID            string `json:"id"`
+	Ingress       int64  `json:"ingress"`
+	Egress        int64  `json:"egress"`
+	Allocated     int64  `json:"allocated"`
+	RepairIngress int64  `json:"repair_ingress"`
+	RepairEgress  int64  `json:"repair_egress"`
+	AuditEgress   int64  `json:"audit_egress"`
+}
+
+// Vetting is an item of the response of GET /api/v1/vetting, the latest
+// vetting progress sent by a satellite
+type Vetting struct {
+	Satellite       string `json:"satellite"`
+	AuditCount      int64  `json:"audit_count"`
+	AuditThreshold  int64  `json:"audit_threshold"`
+	UptimeCount     int64  `json:"uptime_count"`
+	UptimeThreshold int64  `json:"uptime_threshold"`
+	Vetted          bool   `json:"vetted"`
+}
+
+// Audit is an item of the response of GET /api/v1/audits, the latest audit
+// status sent by a satellite. Failing nodes passed fewer than
+// SuccessThreshold of their audits or missed too many in a row, and risk
+// being disqualified.
+type Audit struct {
+	Satellite         string  `json:"satellite"`
+	AuditCount        int64   `json:"audit_count"`
+	AuditSuccessCount int64   `json:"audit_success_count"`
+	MissedAudits      int64   `json:"missed_audits"`
+	SuccessThreshold  float64 `json:"success_threshold"`
+	Failing           bool    `json:"failing"`
+}
+
+// API serves the data of the storage node dashboard as JSON to local
+// dashboards and exporters:
+//
+//	GET /api/v1/node                       the id of the node, its limits and warnings
+//	GET /api/v1/space                      the disk space used and available
+//	GET /api/v1/bandwidth                  the bandwidth used this month
+//	GET /api/v1/vetting                    the vetting progress with each satellite
+//	GET /api/v1/audits                     the audit status with each satellite
+//	GET /api/v1/maintenance                the next maintenance window and the transfers to drain
+//	GET /api/v1/notifications[?unread=true]  the notifications of satellites
+//
+// Requests are authenticated with the token as "Authorization: Bearer TOKEN".
+type API struct {
+	log     *zap.Logger
+	id      string
+	started time.Time
+	ps      *psserver.Server
+	inbox   *notification.Inbox
+	token   string
+	origins map[string]bool
+}
+
+// New creates the API of the node id serving the data of ps. inbox may be
+// nil if notifications aren't received. Browsers may call the API from the
+// origins, or from any origin if they include "*".
+func New(log *zap.Logger, id string, ps *psserver.Server, inbox *notification.Inbox, token string, origins []string) *API {
+	api := &API{
+		log:     log,
+		id:      id,
+		started: time.Now().UTC(),
+		ps:      ps,
+		inbox:   inbox,
+		token:   token,
+		origins: map[string]bool{},
+	}
+	for _, origin := range origins {
+		api.origins[origin] = true
+	}
+	return api
+}
+
+// ServeHTTP implements http.Handler
+func (api *API) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	api.cors(w, req)
+	if req.Method == http.MethodOptions {
+		// preflight requests aren't authenticated, browsers don't send
+		// the token with them
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	if !api.authorized(req) {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var result interface{}
+	var err error
+	switch strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, Prefix), "/") {
+	case "node":
+		result = api.Node()
+	case "space":
+		result, err = api.Space()
+	case "bandwidth":
+		result, err = api.Bandwidth()
+	case "vetting":
+		result = api.Vetting()
+	case "audits":
+		result = api.Audits()
+	case "maintenance":
+		result = api.ps.Maintenance()
+	case "notifications":
+		if api.inbox == nil {
+			http.NotFound(w, req)
+			return
+		}
+		var notifications []notification.Notification
+		notifications, err = api.inbox.List(req.Context(), req.URL.Query().Get("unread") == "true")
+		if notifications == nil {
+			notifications = []notification.Notification{}
+		}
+		result = notifications
+	default:
+		http.NotFound(w, req)
+		return
+	}
+
+	if err != nil {
+		api.log.Error("failed serving node api request", zap.String("path", req.URL.Path), zap.Error(err))
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// authorized returns whether req carries the token
+func (api *API) authorized(req *http.Request) bool {
+	const bearer = "Bearer "
+	header := req.Header.Get("Authorization")
+	if api.token == "" || !strings.HasPrefix(header, bearer) {
+		return false
+	}
+	token := strings.TrimPrefix(header, bearer)
+	return subtle.ConstantTimeCompare([]byte(token), []byte(api.token)) == 1
+}
+
+// cors allows the browsers of the configured origins to call the API
+func (api *API) cors(w http.ResponseWriter, req *http.Request) {
+	origin := req.Header.Get("Origin")
+	if origin == "" || !(api.origins["*"] || api.origins[origin]) {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+	w.Header().Set("Access-Control-Allow-Headers", "Authorization")
+	w.Header().Set("Access-Control-Max-Age", "600")
+	w.Header().Add("Vary", "Origin")
+}
+
+// Node returns the id of the node, its limits and warnings
+func (api *API) Node() *Node {
+	node := &Node{
+		ID:           api.id,
+		Started:      api.started,
+		MaxBandwidth: api.ps.MaxBandwidth(),
+		Wallet:       api.ps.Operator().GetWallet(),
+	}
+	for _, audit := range api.Audits() {
+		if audit.Failing {
+			node.Warnings = append(node.Warnings, fmt.Sprintf(
+				"failing the audits of satellite %s: passed %d of %d audits, missed %d in a row. the node risks being disqualified",
+				audit.Satellite, audit.AuditSuccessCount, audit.AuditCount, audit.MissedAudits))
+		}
+	}
+	return node
+}
+
+// Space returns the disk space used and available
+func (api *API) Space() (_ *Space, err error) {
+	space := &Space{
+		Available:  api.ps.AvailableSpace(),
+		Satellites: []SatelliteSpace{},
+	}
+	if space.Used, err = api.ps.DB.SumTTLSizes(); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for satellite, allocation := range api.ps.Allocations() {
+		if allocation.Space <= 0 {
+			continue
+		}
+		used, err := api.ps.DB.SumSatelliteTTLSizes(satellite)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		space.Satellites = append(space.Satellites, SatelliteSpace{
+			ID:        satellite,
+			Allocated: allocation.Space,
+			Used:      used,
+		})
+	}
+	sort.Slice(space.Satellites, func(i, k int) bool {
+		return space.Satellites[i].ID < space.Satellites[k].ID
+	})
+	return space, nil
+}
+
+// Bandwidth returns the bandwidth used this month
+func (api *API) Bandwidth() (*Bandwidth, error) {
+	since := psserver.StartOfMonth(time.Now())
+	used, err := api.ps.DB.GetBandwidthUsedSince(since)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	usedBy, err := api.ps.DB.GetBandwidthUsedSinceBySatellite(since)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	bandwidth := &Bandwidth{
+		Since:         since,
+		Ingress:       used.Ingress,
+		Egress:        used.Egress,
+		RepairIngress: used.RepairIngress,
+		RepairEgress:  used.RepairEgress,
+		AuditEgress:   used.AuditEgress,
+		Satellites:    []SatelliteBandwidth{},
+	}
+	bandwidth.Cap, bandwidth.IngressCap, bandwidth.EgressCap = api.ps.BandwidthCaps()
+
+	allocations := api.ps.Allocations()
+	for satellite, allocation := range allocations {
+		if _, ok := usedBy[satellite]; !ok && allocation.Bandwidth > 0 {
+			usedBy[satellite] = psdb.BandwidthUsage{}
+		}
+	}
+	for satellite, usage := range usedBy {
+		if satellite == "" {
+			// order limits aren't verified, so the satellite is unknown
+			continue
+		}
+		bandwidth.Satellites = append(bandwidth.Satellites, SatelliteBandwidth{
+			ID:            satellite,
+			Ingress:       usage.Ingress,
+			Egress:        usage.Egress,
+			Allocated:     allocations[satellite].Bandwidth,
+			RepairIngress: usage.RepairIngress,
+			RepairEgress:  usage.RepairEgress,
+			AuditEgress:   usage.AuditEgress,
+		})
+	}
+	sort.Slice(bandwidth.Satellites, func(i, k int) bool {
+		return bandwidth.Satellites[i].ID < bandwidth.Satellites[k].ID
+	})
+	return bandwidth, nil
+}
+
+// Vetting returns the vetting progress with each satellite that sent it,
+// ordered by satellite
+func (api *API) Vetting() []Vetting {
+	vetting := []Vetting{}
+	for satellite, progress := range api.ps.Vetting() {
+		vetting = append(vetting, Vetting{
+			Satellite:       satellite,
+			AuditCount:      progress.GetAuditCount(),
+			AuditThreshold:  progress.GetAuditThreshold(),
+			UptimeCount:     progress.GetUptimeCount(),
+			UptimeThreshold: progress.GetUptimeThreshold(),
+			Vetted:          progress.GetVetted(),
+		})
+	}
+	sort.Slice(vetting, func(i, k int) bool {
+		return vetting[i].Satellite < vetting[k].Satellite
+	})
+	return vetting
+}
+
+// Audits returns the audit status with each satellite that sent it, ordered
+// by satellite
+func (api *API) Audits() []Audit {
+	audits := []Audit{}
+	for satellite, audit := range api.ps.Audits() {
+		audits = append(audits, Audit{
+			Satellite:         satellite,
+			AuditCount:        audit.GetAuditCount(),
+			AuditSuccessCount: audit.GetAuditSuccessCount(),
+			MissedAudits:      audit.GetMissedAudits(),
+			SuccessThreshold:  audit.GetSuccessThreshold(),
+			Failing:           audit.GetFailing(),
+		})
+	}
+	sort.Slice(audits, func(i, k int) bool {
+		return audits[i].Satellite < audits[k].Satellite
+	})
+	return audits
+}
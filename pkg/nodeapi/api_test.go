@@ -0,0 +1,143 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodeapi
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/notification"
+	"storj.io/storj/pkg/pb"
+	psserver "storj.io/storj/pkg/piecestore/rpc/server"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestAPI(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "storj-nodeapi")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	ps, err := psserver.Initialize(ctx, psserver.Config{
+		Path:                 dir,
+		MonthlyBandwidthCap:  1000,
+		SatelliteAllocations: "sat1=500/200",
+	}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, ps.Stop(ctx)) }()
+
+	assert.NoError(t, ps.DB.AddTTL("piece1", 0, 100, "sat1"))
+	assert.NoError(t, ps.DB.AddTTL("piece2", 0, 50, "sat2"))
+	assert.NoError(t, ps.DB.AddBandwidthUsed(pb.PayerBandwidthAllocation_PUT, 30, time.Now(), "sat2"))
+	assert.NoError(t, ps.DB.AddBandwidthUsed(pb.PayerBandwidthAllocation_GET, 20, time.Now(), "sat2"))
+	assert.NoError(t, ps.DB.AddBandwidthUsed(pb.PayerBandwidthAllocation_GET_REPAIR, 5, time.Now(), "sat2"))
+
+	inbox := notification.NewInbox(teststore.New())
+	_, err = inbox.Add(ctx, "sat1", &pb.Notification{Id: "1", Title: "hello"})
+	assert.NoError(t, err)
+
+	api := New(zap.NewNop(), "node1", ps, inbox, "secret", []string{"https://dashboard.example"})
+	get := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		api.ServeHTTP(w, req)
+		return w
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, get("/api/v1/node", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, get("/api/v1/node", "wrong").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/v1/unknown", "secret").Code)
+
+	var node Node
+	w := get("/api/v1/node", "secret")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&node))
+	assert.Equal(t, "node1", node.ID)
+	assert.Empty(t, node.Warnings)
+
+	var audits []Audit
+	assert.NoError(t, json.NewDecoder(get("/api/v1/audits", "secret").Body).Decode(&audits))
+	assert.Equal(t, []Audit{}, audits)
+
+	var space Space
+	assert.NoError(t, json.NewDecoder(get("/api/v1/space", "secret").Body).Decode(&space))
+	assert.Equal(t, int64(150), space.Used)
+	assert.Equal(t, []SatelliteSpace{{ID: "sat1", Allocated: 500, Used: 100}}, space.Satellites)
+
+	var bandwidth Bandwidth
+	assert.NoError(t, json.NewDecoder(get("/api/v1/bandwidth", "secret").Body).Decode(&bandwidth))
+	assert.Equal(t, int64(30), bandwidth.Ingress)
+	assert.Equal(t, int64(25), bandwidth.Egress)
+	assert.Equal(t, int64(5), bandwidth.RepairEgress)
+	assert.Equal(t, int64(1000), bandwidth.Cap)
+	assert.Equal(t, []SatelliteBandwidth{
+		{ID: "sat1", Allocated: 200},
+		{ID: "sat2", Ingress: 30, Egress: 25, RepairEgress: 5},
+	}, bandwidth.Satellites)
+
+	var notifications []notification.Notification
+	assert.NoError(t, json.NewDecoder(get("/api/v1/notifications?unread=true", "secret").Body).Decode(&notifications))
+	if assert.Len(t, notifications, 1) {
+		assert.Equal(t, "hello", notifications[0].Title)
+	}
+}
+
+func TestCORS(t *testing.T) {
+	api := New(zap.NewNop(), "node1", nil, nil, "secret", []string{"https://dashboard.example"})
+
+	// preflight requests of allowed origins succeed without the token
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/node", nil)
+	req.Header.Set("Origin", "https://dashboard.example")
+	w := httptest.NewRecorder()
+	api.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "https://dashboard.example", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+
+	req = httptest.NewRequest(http.MethodOptions, "/api/v1/node", nil)
+	req.Header.Set("Origin", "https://other.example")
+	w = httptest.NewRecorder()
+	api.ServeHTTP(w, req)
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+}
+
+func TestLoadToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storj-nodeapi")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "api-token")
+	token, err := LoadToken(path)
+	assert.NoError(t, err)
+	assert.Len(t, token, 64)
+
+	// the token is kept across restarts
+	again, err := LoadToken(path)
+	assert.NoError(t, err)
+	assert.Equal(t, token, again)
+
+	info, err := os.Stat(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodeapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/notification"
+	psserver "storj.io/storj/pkg/piecestore/rpc/server"
+	"storj.io/storj/pkg/provider"
+)
+
+// Config contains everything necessary to serve the node API
+type Config struct {
+	Address        string `help:"address to serve the local node API for dashboards on, e.g. 127.0.0.1:7778. disabled if empty" default:""`
+	TokenPath      string `help:"path to the token authenticating requests to the node API. a random token is created if the file doesn't exist" default:"$CONFDIR/api-token"`
+	AllowedOrigins string `help:"comma-separated origins of the web dashboards allowed to call the node API from a browser, or * for any origin" default:""`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// piece store responsibility has been started before this one, and serves
+// notifications if the notifications responsibility has been started too.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if c.Address == "" {
+		return server.Run(ctx)
+	}
+
+	ps := psserver.LoadFromContext(ctx)
+	if ps == nil {
+		return Error.New("programmer error: piece store responsibility unstarted")
+	}
+
+	log := zap.L().Named("nodeapi")
+	token, err := LoadToken(c.TokenPath)
+	if err != nil {
+		return err
+	}
+
+	var origins []string
+	if c.AllowedOrigins != "" {
+		origins = strings.Split(c.AllowedOrigins, ",")
+	}
+
+	lis, err := net.Listen("tcp", c.Address)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if addr, ok := lis.Addr().(*net.TCPAddr); ok && !addr.IP.IsLoopback() {
+		log.Warn("node api is reachable from other hosts", zap.String("address", c.Address))
+	}
+
+	api := New(log, server.Identity().ID.String(), ps, notification.LoadFromContext(ctx), token, origins)
+	mux := http.NewServeMux()
+	mux.Handle(Prefix, api)
+	httpServer := &http.Server{Handler: mux}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := httpServer.Serve(lis); err != nil && err != http.ErrServerClosed {
+			log.Error("node api server died", zap.Error(err))
+		}
+	}()
+	defer func() {
+		_ = httpServer.Close()
+		<-done
+	}()
+
+	log.Info("serving node api", zap.String("address", lis.Addr().String()), zap.String("token path", c.TokenPath))
+	return server.Run(ctx)
+}
+
+// LoadToken reads the token at path, creating a random one if the file
+// doesn't exist
+func LoadToken(path string) (string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err == nil {
+		token := strings.TrimSpace(string(data))
+		if token == "" {
+			return "", Error.New("empty token in %s", path)
+		}
+		return token, nil
+	}
+	if !os.IsNotExist(err) {
+		return "", Error.Wrap(err)
+	}
+
+	var random [32]byte
+	if _, err := rand.Read(random[:]); err != nil {
+		return "", Error.Wrap(err)
+	}
+	token := hex.EncodeToString(random[:])
+	if err := ioutil.WriteFile(path, []byte(token+"\n"), 0600); err != nil {
+		return "", Error.Wrap(err)
+	}
+	return token, nil
+}
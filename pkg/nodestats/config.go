@@ -0,0 +1,50 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodestats
+
+import (
+	"context"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	psserver "storj.io/storj/pkg/piecestore/rpc/server"
+	"storj.io/storj/pkg/provider"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for node statistics
+	Error = errs.Class("node stats error")
+)
+
+// Config contains everything necessary to start the statistics reporter
+// responsibility
+type Config struct {
+	Enabled  bool          `help:"if true, anonymized operational statistics (os, disk size, bandwidth class and uptime) are sent to help plan the network's capacity. nothing identifying the node is sent" default:"false"`
+	URL      string        `help:"the endpoint the statistics are sent to" default:"https://stats.storj.io/v1/nodes"`
+	Interval time.Duration `help:"how often the statistics are sent" default:"24h"`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// piece store responsibility has been started before this one.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !c.Enabled {
+		return server.Run(ctx)
+	}
+
+	ps := psserver.LoadFromContext(ctx)
+	if ps == nil {
+		return Error.New("programmer error: piece store responsibility unstarted")
+	}
+
+	reporter := NewReporter(zap.L().Named("nodestats"), c.URL, ps)
+	go reporter.Run(ctx, c.Interval)
+
+	return server.Run(ctx)
+}
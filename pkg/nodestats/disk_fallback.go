@@ -0,0 +1,11 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information
+
+// +build !linux,!darwin,!freebsd
+
+package nodestats
+
+// diskSize returns the size of the disk path is on, -1 if it's unknown
+func diskSize(path string) int64 {
+	return -1
+}
@@ -0,0 +1,17 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information
+
+// +build linux darwin freebsd
+
+package nodestats
+
+import "syscall"
+
+// diskSize returns the size of the disk path is on, -1 if it's unknown
+func diskSize(path string) int64 {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return -1
+	}
+	return int64(stat.Blocks) * int64(stat.Bsize)
+}
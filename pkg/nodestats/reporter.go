@@ -0,0 +1,169 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodestats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"runtime"
+	"time"
+
+	"go.uber.org/zap"
+
+	psserver "storj.io/storj/pkg/piecestore/rpc/server"
+)
+
+// Report is the anonymized statistics of a node. Sizes, bandwidth and uptime
+// are reported as coarse classes rather than exact values.
+type Report struct {
+	OS        string `json:"os"`
+	Arch      string `json:"arch"`
+	DiskSize  string `json:"disk_size"`
+	UsedSpace string `json:"used_space"`
+	Bandwidth string `json:"bandwidth"`
+	Uptime    string `json:"uptime"`
+}
+
+// Reporter sends the statistics of a node to the stats endpoint
+type Reporter struct {
+	log     *zap.Logger
+	url     string
+	server  *psserver.Server
+	started time.Time
+	client  *http.Client
+}
+
+// NewReporter creates a Reporter sending the statistics of server to url
+func NewReporter(log *zap.Logger, url string, server *psserver.Server) *Reporter {
+	return &Reporter{
+		log:     log,
+		url:     url,
+		server:  server,
+		started: time.Now(),
+		client:  &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Run sends the statistics every interval until ctx is canceled
+func (r *Reporter) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Send(ctx); err != nil {
+			r.log.Warn("failed sending statistics", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Report collects the current statistics
+func (r *Reporter) Report() (Report, error) {
+	used, err := r.server.DB.SumTTLSizes()
+	if err != nil {
+		return Report{}, Error.Wrap(err)
+	}
+
+	return Report{
+		OS:        runtime.GOOS,
+		Arch:      runtime.GOARCH,
+		DiskSize:  sizeClass(diskSize(r.server.DataDir)),
+		UsedSpace: sizeClass(used),
+		Bandwidth: bandwidthClass(r.server.MaxBandwidth()),
+		Uptime:    uptimeClass(time.Since(r.started)),
+	}, nil
+}
+
+// Send sends the current statistics
+func (r *Reporter) Send(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	report, err := r.Report()
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		return Error.New("unexpected status %s", resp.Status)
+	}
+
+	r.log.Info("sent anonymized statistics", zap.ByteString("report", body))
+	return nil
+}
+
+const (
+	gb = 1 << 30
+	tb = 1 << 40
+	mb = 1 << 20
+)
+
+// sizeClass returns the class of a size in bytes, which is unknown if the
+// size is negative
+func sizeClass(size int64) string {
+	switch {
+	case size < 0:
+		return "unknown"
+	case size < 100*gb:
+		return "<100GB"
+	case size < tb:
+		return "100GB-1TB"
+	case size < 10*tb:
+		return "1TB-10TB"
+	default:
+		return ">=10TB"
+	}
+}
+
+// bandwidthClass returns the class of a bandwidth limit in bytes per second
+func bandwidthClass(bandwidth int64) string {
+	switch {
+	case bandwidth <= 0:
+		return "unlimited"
+	case bandwidth < mb:
+		return "<1MB/s"
+	case bandwidth < 10*mb:
+		return "1-10MB/s"
+	case bandwidth < 100*mb:
+		return "10-100MB/s"
+	default:
+		return ">=100MB/s"
+	}
+}
+
+// uptimeClass returns the class of how long the node has been running
+func uptimeClass(uptime time.Duration) string {
+	const day = 24 * time.Hour
+	switch {
+	case uptime < day:
+		return "<1d"
+	case uptime < 7*day:
+		return "1d-1w"
+	case uptime < 30*day:
+		return "1w-1mo"
+	default:
+		return ">=1mo"
+	}
+}
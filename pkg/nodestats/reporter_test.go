@@ -0,0 +1,67 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package nodestats
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	psserver "storj.io/storj/pkg/piecestore/rpc/server"
+)
+
+func TestClasses(t *testing.T) {
+	assert.Equal(t, "unknown", sizeClass(-1))
+	assert.Equal(t, "<100GB", sizeClass(0))
+	assert.Equal(t, "100GB-1TB", sizeClass(500*gb))
+	assert.Equal(t, ">=10TB", sizeClass(10*tb))
+
+	assert.Equal(t, "unlimited", bandwidthClass(0))
+	assert.Equal(t, "1-10MB/s", bandwidthClass(5*mb))
+
+	assert.Equal(t, "<1d", uptimeClass(time.Hour))
+	assert.Equal(t, "1w-1mo", uptimeClass(10*24*time.Hour))
+}
+
+func TestSend(t *testing.T) {
+	ctx := context.Background()
+	dir, err := ioutil.TempDir("", "storj-nodestats")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	ps, err := psserver.Initialize(ctx, psserver.Config{Path: dir, MaxBandwidth: 50 * mb}, nil)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, ps.Stop(ctx)) }()
+
+	reports := make(chan Report, 1)
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report Report
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&report))
+		reports <- report
+	}))
+	defer endpoint.Close()
+
+	reporter := NewReporter(zap.NewNop(), endpoint.URL, ps)
+	assert.NoError(t, reporter.Send(ctx))
+
+	report := <-reports
+	assert.Equal(t, runtime.GOOS, report.OS)
+	assert.Equal(t, "<100GB", report.UsedSpace)
+	assert.Equal(t, "10-100MB/s", report.Bandwidth)
+	assert.Equal(t, "<1d", report.Uptime)
+	assert.NotEmpty(t, report.DiskSize)
+}
@@ -0,0 +1,19 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"github.com/zeebo/errs"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default notification errs class
+	Error = errs.Class("notification error")
+	// ErrUntrusted is returned for notifications of satellites the node
+	// doesn't accept notifications from
+	ErrUntrusted = errs.Class("untrusted satellite")
+)
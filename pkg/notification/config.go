@@ -0,0 +1,71 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage/boltdb"
+)
+
+// CtxKey Used as notification inbox key
+type CtxKey int
+
+const (
+	ctxKeyInbox CtxKey = iota
+)
+
+// Config contains everything necessary for a storage node to receive the
+// notifications of satellites
+type Config struct {
+	DatabaseURL  string `help:"the database the notifications are stored in" default:"bolt://$CONFDIR/notifications.db"`
+	SatelliteIDs string `help:"comma-separated ids of the satellites whose notifications are accepted. if empty, the notifications of any satellite are accepted" default:""`
+}
+
+// Run implements the provider.Responsibility interface. The inbox is served
+// on the debug endpoints at /notifications/.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	dburl, err := utils.ParseURL(c.DatabaseURL)
+	if err != nil {
+		return err
+	}
+	if dburl.Scheme != "bolt" {
+		return Error.New("unsupported db scheme: %s", dburl.Scheme)
+	}
+
+	db, err := boltdb.New(dburl.Path, InboxBucket)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = db.Close() }()
+
+	var satelliteIDs []string
+	if c.SatelliteIDs != "" {
+		satelliteIDs = strings.Split(c.SatelliteIDs, ",")
+	}
+
+	inbox := NewInbox(db)
+	pb.RegisterNotificationsServer(server.GRPC(), NewServer(zap.L().Named("notifications"), inbox, satelliteIDs))
+	process.HandleDebug("/notifications/", inbox)
+
+	return server.Run(context.WithValue(ctx, ctxKeyInbox, inbox))
+}
+
+// LoadFromContext loads the Inbox from the Provider context stack, or nil if
+// the notifications responsibility isn't started
+func LoadFromContext(ctx context.Context) *Inbox {
+	if v, ok := ctx.Value(ctxKeyInbox).(*Inbox); ok {
+		return v
+	}
+	return nil
+}
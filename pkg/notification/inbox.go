@@ -0,0 +1,204 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
+)
+
+// InboxBucket is the bolt bucket the notifications of a node are stored in
+const InboxBucket = "notifications"
+
+const (
+	maxTitleSize   = 256
+	maxMessageSize = 16 * 1024
+)
+
+// Notification is a notification of a satellite stored on a node
+type Notification struct {
+	// ID is unique for the node: the id of the satellite and the id of the
+	// notification, separated by a slash
+	ID        string    `json:"id"`
+	Satellite string    `json:"satellite"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Created   time.Time `json:"created"`
+	Received  time.Time `json:"received"`
+	Read      bool      `json:"read"`
+}
+
+// Inbox stores the notifications received by a node and whether the operator
+// read them
+type Inbox struct {
+	db storage.KeyValueStore
+}
+
+// NewInbox creates the inbox stored in db
+func NewInbox(db storage.KeyValueStore) *Inbox {
+	return &Inbox{db: db}
+}
+
+// Add stores notification n of satellite. It returns false if the
+// notification was already stored, in which case it is left untouched.
+func (inbox *Inbox) Add(ctx context.Context, satellite string, n *pb.Notification) (added bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	switch {
+	case n.GetId() == "" || strings.Contains(n.GetId(), "/"):
+		return false, Error.New("invalid notification id %q", n.GetId())
+	case len(n.GetTitle()) > maxTitleSize:
+		return false, Error.New("notification title of %d bytes, the maximum is %d", len(n.GetTitle()), maxTitleSize)
+	case len(n.GetMessage()) > maxMessageSize:
+		return false, Error.New("notification message of %d bytes, the maximum is %d", len(n.GetMessage()), maxMessageSize)
+	}
+
+	id := satellite + "/" + n.GetId()
+	_, err = inbox.db.Get(storage.Key(id))
+	if err == nil {
+		return false, nil
+	}
+	if !storage.ErrKeyNotFound.Has(err) {
+		return false, Error.Wrap(err)
+	}
+
+	value, err := json.Marshal(Notification{
+		ID:        id,
+		Satellite: satellite,
+		Type:      n.GetType().String(),
+		Title:     n.GetTitle(),
+		Message:   n.GetMessage(),
+		Created:   time.Unix(n.GetCreatedUnixSec(), 0).UTC(),
+		Received:  time.Now().UTC(),
+	})
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	if err := inbox.db.Put(storage.Key(id), value); err != nil {
+		return false, Error.Wrap(err)
+	}
+	mon.Counter("notifications_received").Inc(1)
+	return true, nil
+}
+
+// List returns the notifications, or only the unread ones, newest first
+func (inbox *Inbox) List(ctx context.Context, unreadOnly bool) (notifications []Notification, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	err = inbox.db.Iterate(storage.IterateOptions{Recurse: true}, func(it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(&item) {
+			var n Notification
+			if err := json.Unmarshal(item.Value, &n); err != nil {
+				return err
+			}
+			if unreadOnly && n.Read {
+				continue
+			}
+			notifications = append(notifications, n)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	sort.SliceStable(notifications, func(i, k int) bool {
+		return notifications[i].Received.After(notifications[k].Received)
+	})
+	return notifications, nil
+}
+
+// MarkRead marks the notification id as read, or all notifications if id is
+// empty
+func (inbox *Inbox) MarkRead(ctx context.Context, id string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if id != "" {
+		return inbox.markRead(storage.Key(id))
+	}
+	var keys storage.Keys
+	err = inbox.db.Iterate(storage.IterateOptions{Recurse: true}, func(it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(&item) {
+			keys = append(keys, storage.CloneKey(item.Key))
+		}
+		return nil
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	for _, key := range keys {
+		if err := inbox.markRead(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markRead marks the notification at key as read
+func (inbox *Inbox) markRead(key storage.Key) error {
+	value, err := inbox.db.Get(key)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return err
+		}
+		return Error.Wrap(err)
+	}
+	var n Notification
+	if err := json.Unmarshal(value, &n); err != nil {
+		return Error.Wrap(err)
+	}
+	if n.Read {
+		return nil
+	}
+	n.Read = true
+	if value, err = json.Marshal(n); err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(inbox.db.Put(key, value))
+}
+
+// ServeHTTP implements the inbox of the node operator, mounted at
+// /notifications/:
+//
+//	GET  /notifications/[?unread=true]  lists the notifications, newest first
+//	POST /notifications/read[?id=ID]    marks the notification ID, or all of them, as read
+func (inbox *Inbox) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	path := strings.Trim(strings.TrimPrefix(req.URL.Path, "/notifications"), "/")
+
+	var result interface{}
+	var err error
+	switch {
+	case path == "" && req.Method == http.MethodGet:
+		result, err = inbox.List(ctx, req.URL.Query().Get("unread") == "true")
+	case path == "read" && req.Method == http.MethodPost:
+		err = inbox.MarkRead(ctx, req.URL.Query().Get("id"))
+		result = struct{}{}
+	case path == "" || path == "read":
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	default:
+		http.NotFound(w, req)
+		return
+	}
+
+	switch {
+	case storage.ErrKeyNotFound.Has(err):
+		http.NotFound(w, req)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	}
+}
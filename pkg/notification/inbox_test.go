@@ -0,0 +1,93 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestInbox(t *testing.T) {
+	ctx := context.Background()
+	inbox := NewInbox(teststore.New())
+
+	added, err := inbox.Add(ctx, "sat", &pb.Notification{Id: "1", Type: pb.Notification_PAYOUT, Title: "paid"})
+	assert.NoError(t, err)
+	assert.True(t, added)
+	added, err = inbox.Add(ctx, "sat", &pb.Notification{Id: "2", Title: "hello"})
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	// notifications sent again are stored once
+	added, err = inbox.Add(ctx, "sat", &pb.Notification{Id: "1", Title: "paid again"})
+	assert.NoError(t, err)
+	assert.False(t, added)
+
+	_, err = inbox.Add(ctx, "sat", &pb.Notification{Id: "a/b", Title: "invalid"})
+	assert.True(t, Error.Has(err))
+
+	notifications, err := inbox.List(ctx, false)
+	assert.NoError(t, err)
+	if assert.Len(t, notifications, 2) {
+		ids := []string{notifications[0].ID, notifications[1].ID}
+		assert.ElementsMatch(t, []string{"sat/1", "sat/2"}, ids)
+	}
+
+	assert.NoError(t, inbox.MarkRead(ctx, "sat/1"))
+	unread, err := inbox.List(ctx, true)
+	assert.NoError(t, err)
+	if assert.Len(t, unread, 1) {
+		assert.Equal(t, "sat/2", unread[0].ID)
+		assert.Equal(t, "INFO", unread[0].Type)
+	}
+
+	assert.Error(t, inbox.MarkRead(ctx, "sat/3"))
+
+	assert.NoError(t, inbox.MarkRead(ctx, ""))
+	unread, err = inbox.List(ctx, true)
+	assert.NoError(t, err)
+	assert.Empty(t, unread)
+}
+
+func TestReceive(t *testing.T) {
+	ctx := context.Background()
+	inbox := NewInbox(teststore.New())
+	server := NewServer(zap.NewNop(), inbox, []string{"trusted"})
+
+	assert.NoError(t, server.Receive(ctx, "trusted", &pb.Notification{Id: "1", Title: "hi"}))
+	err := server.Receive(ctx, "other", &pb.Notification{Id: "2", Title: "spam"})
+	assert.True(t, ErrUntrusted.Has(err))
+
+	notifications, err := inbox.List(ctx, false)
+	assert.NoError(t, err)
+	assert.Len(t, notifications, 1)
+}
+
+func TestInboxHTTP(t *testing.T) {
+	ctx := context.Background()
+	inbox := NewInbox(teststore.New())
+	_, err := inbox.Add(ctx, "sat", &pb.Notification{Id: "1", Title: "hi"})
+	assert.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	inbox.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications/read?id=sat/1", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	inbox.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/notifications/?unread=true", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "null\n", rec.Body.String())
+
+	rec = httptest.NewRecorder()
+	inbox.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/notifications/read?id=sat/2", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,49 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"context"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/transport"
+)
+
+// Overlay looks up the addresses of storage nodes
+type Overlay interface {
+	Get(ctx context.Context, nodeID string) (*pb.Node, error)
+}
+
+// Sender sends the notifications of a satellite to storage nodes
+type Sender struct {
+	transport transport.Client
+	overlay   Overlay
+}
+
+// NewSender creates a sender dialing the nodes found in overlay
+func NewSender(t transport.Client, overlay Overlay) *Sender {
+	return &Sender{transport: t, overlay: overlay}
+}
+
+// Send sends notification n to the storage node with nodeID
+func (s *Sender) Send(ctx context.Context, nodeID string, n *pb.Notification) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	node, err := s.overlay.Get(ctx, nodeID)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if node == nil {
+		return Error.New("node %s not found", nodeID)
+	}
+
+	conn, err := s.transport.DialNode(ctx, node)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	_, err = pb.NewNotificationsClient(conn).Notify(ctx, &pb.NotifyRequest{Notification: n})
+	return Error.Wrap(err)
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package notification
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
+)
+
+// Server implements the notifications service of a storage node
+type Server struct {
+	log   *zap.Logger
+	inbox *Inbox
+	// satellites are the ids of the satellites whose notifications are
+	// accepted. If empty, the notifications of any satellite are accepted.
+	satellites map[string]bool
+}
+
+// NewServer creates a notifications service storing the notifications of
+// satelliteIDs, or of any satellite if empty, in inbox
+func NewServer(log *zap.Logger, inbox *Inbox, satelliteIDs []string) *Server {
+	satellites := map[string]bool{}
+	for _, id := range satelliteIDs {
+		satellites[id] = true
+	}
+	return &Server{log: log, inbox: inbox, satellites: satellites}
+}
+
+// Notify stores the notification of the calling satellite
+func (s *Server) Notify(ctx context.Context, req *pb.NotifyRequest) (resp *pb.NotifyResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	satellite, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+
+	err = s.Receive(ctx, satellite.ID.String(), req.GetNotification())
+	switch {
+	case err == nil:
+		return &pb.NotifyResponse{}, nil
+	case ErrUntrusted.Has(err):
+		return nil, status.Errorf(codes.PermissionDenied, err.Error())
+	case Error.Has(err):
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	default:
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+}
+
+// Receive stores notification n of satellite, if its notifications are
+// accepted
+func (s *Server) Receive(ctx context.Context, satellite string, n *pb.Notification) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(s.satellites) > 0 && !s.satellites[satellite] {
+		return ErrUntrusted.New("%s", satellite)
+	}
+	if n == nil {
+		return Error.New("missing notification")
+	}
+	added, err := s.inbox.Add(ctx, satellite, n)
+	if err != nil {
+		return err
+	}
+	if added {
+		s.log.Info("notification received", zap.String("satellite", satellite),
+			zap.Stringer("type", n.GetType()), zap.String("title", n.GetTitle()))
+	}
+	return nil
+}
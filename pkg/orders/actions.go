@@ -0,0 +1,33 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package orders
+
+import (
+	"storj.io/storj/pkg/pb"
+)
+
+// Request returns the request an order limit of action allows: PUT for the
+// actions uploading a piece, DELETE for deletes and GET for the actions
+// downloading one
+func Request(action pb.PayerBandwidthAllocation_Action) pb.PayerBandwidthAllocation_Action {
+	switch action {
+	case pb.PayerBandwidthAllocation_PUT, pb.PayerBandwidthAllocation_PUT_REPAIR:
+		return pb.PayerBandwidthAllocation_PUT
+	case pb.PayerBandwidthAllocation_DELETE:
+		return pb.PayerBandwidthAllocation_DELETE
+	default:
+		return pb.PayerBandwidthAllocation_GET
+	}
+}
+
+// IsCustomer returns whether action is requested by customers, rather than
+// by the satellite repairing or auditing segments
+func IsCustomer(action pb.PayerBandwidthAllocation_Action) bool {
+	switch action {
+	case pb.PayerBandwidthAllocation_PUT, pb.PayerBandwidthAllocation_GET, pb.PayerBandwidthAllocation_DELETE:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package orders
+
+import (
+	"github.com/zeebo/errs"
+)
+
+var (
+	// Error is the default orders errs class
+	Error = errs.Class("orders error")
+	// ErrUnauthorized is returned for order limits that don't allow a request
+	ErrUnauthorized = errs.Class("unauthorized order limit")
+)
@@ -0,0 +1,34 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package orders
+
+import (
+	"github.com/gogo/protobuf/proto"
+
+	"storj.io/storj/pkg/pb"
+)
+
+// Nodes returns the storage nodes the order limits were issued for, with the
+// addresses the satellite put in them, so that uplinks dial the nodes without
+// looking them up. The order limits aren't verified, they came from the
+// satellite over an authenticated connection.
+func Nodes(limits []*pb.PayerBandwidthAllocation) ([]*pb.Node, error) {
+	nodes := make([]*pb.Node, len(limits))
+	for i, limit := range limits {
+		data := &pb.PayerBandwidthAllocation_Data{}
+		if err := proto.Unmarshal(limit.GetData(), data); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		if len(data.GetStorageNodeId()) == 0 {
+			return nil, Error.New("order limit %d has no storage node", i)
+		}
+		nodes[i] = &pb.Node{Id: string(data.GetStorageNodeId())}
+		// the satellite doesn't know the address of unreachable nodes, which
+		// fail to dial like offline nodes
+		if address := data.GetStorageNodeAddress(); address != "" {
+			nodes[i].Address = &pb.NodeAddress{Transport: pb.NodeTransport_TCP, Address: address}
+		}
+	}
+	return nodes, nil
+}
@@ -0,0 +1,233 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package orders
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gtank/cryptopasta"
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/storage/teststore"
+)
+
+func newIdentity(t *testing.T) *provider.FullIdentity {
+	ca, err := provider.NewCA(context.Background(), 12, 4)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	identity, err := ca.NewIdentity()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return identity
+}
+
+func signLimit(t *testing.T, signer *Signer, data *pb.PayerBandwidthAllocation_Data) *pb.PayerBandwidthAllocation {
+	limit, err := signer.Sign(data)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return limit
+}
+
+func TestVerify(t *testing.T) {
+	satellite := newIdentity(t)
+	signer := NewSigner(satellite)
+
+	valid := func() *pb.PayerBandwidthAllocation_Data {
+		return &pb.PayerBandwidthAllocation_Data{
+			Renter:            []byte("uplink"),
+			MaxSize:           1024,
+			ExpirationUnixSec: time.Now().Add(time.Hour).Unix(),
+			Action:            pb.PayerBandwidthAllocation_GET,
+			PieceId:           "piece",
+			StorageNodeId:     []byte("node"),
+		}
+	}
+
+	trusted := []string{satellite.ID.String()}
+	verifier := NewVerifier("node", trusted)
+	data, err := verifier.Verify(signLimit(t, signer, valid()), pb.PayerBandwidthAllocation_GET, "piece", "uplink")
+	if assert.NoError(t, err) {
+		assert.Equal(t, satellite.ID.Bytes(), data.GetPayer())
+		assert.NotEmpty(t, data.GetSerialNumber())
+		assert.Equal(t, int64(1024), data.GetMaxSize())
+	}
+
+	del := valid()
+	del.Action = pb.PayerBandwidthAllocation_DELETE
+	_, err = verifier.Verify(signLimit(t, signer, del), pb.PayerBandwidthAllocation_DELETE, "piece", "uplink")
+	assert.NoError(t, err)
+
+	// order limits of repairs and audits allow the downloads they're for
+	audit := valid()
+	audit.Action = pb.PayerBandwidthAllocation_GET_AUDIT
+	data, err = verifier.Verify(signLimit(t, signer, audit), pb.PayerBandwidthAllocation_GET, "piece", "uplink")
+	if assert.NoError(t, err) {
+		assert.Equal(t, pb.PayerBandwidthAllocation_GET_AUDIT, data.GetAction())
+	}
+
+	get := pb.PayerBandwidthAllocation_GET
+	for _, tt := range []struct {
+		name     string
+		verifier *Verifier
+		modify   func(data *pb.PayerBandwidthAllocation_Data)
+		action   pb.PayerBandwidthAllocation_Action
+		pieceID  string
+		renterID string
+	}{
+		{"untrusted satellite", NewVerifier("node", []string{"other"}), nil, get, "piece", "uplink"},
+		{"no trusted satellites", NewVerifier("node", nil), nil, get, "piece", "uplink"},
+		{"other node", NewVerifier("other", trusted), nil, get, "piece", "uplink"},
+		{"expired", verifier, func(data *pb.PayerBandwidthAllocation_Data) {
+			data.ExpirationUnixSec = time.Now().Add(-time.Minute).Unix()
+		}, get, "piece", "uplink"},
+		{"other action", verifier, nil, pb.PayerBandwidthAllocation_PUT, "piece", "uplink"},
+		{"delete with download", verifier, nil, pb.PayerBandwidthAllocation_DELETE, "piece", "uplink"},
+		{"repair of other action", verifier, func(data *pb.PayerBandwidthAllocation_Data) {
+			data.Action = pb.PayerBandwidthAllocation_GET_REPAIR
+		}, pb.PayerBandwidthAllocation_PUT, "piece", "uplink"},
+		{"other piece", verifier, nil, get, "other", "uplink"},
+		{"other uplink", verifier, nil, get, "piece", "other"},
+	} {
+		data := valid()
+		if tt.modify != nil {
+			tt.modify(data)
+		}
+		_, err := tt.verifier.Verify(signLimit(t, signer, data), tt.action, tt.pieceID, tt.renterID)
+		assert.True(t, ErrUnauthorized.Has(err), tt.name)
+	}
+
+	// a limit can't be modified without invalidating the signature
+	limit := signLimit(t, signer, valid())
+	data = valid()
+	data.Payer = satellite.ID.Bytes()
+	data.MaxSize = 1 << 30
+	limit.Data, err = proto.Marshal(data)
+	assert.NoError(t, err)
+	_, err = verifier.Verify(limit, pb.PayerBandwidthAllocation_GET, "piece", "uplink")
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	// nor signed by another satellite with the certificates of this one
+	other := signLimit(t, NewSigner(newIdentity(t)), valid())
+	other.Certs = limit.Certs
+	_, err = verifier.Verify(other, pb.PayerBandwidthAllocation_GET, "piece", "uplink")
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	_, err = verifier.Verify(&pb.PayerBandwidthAllocation{}, pb.PayerBandwidthAllocation_GET, "piece", "uplink")
+	assert.True(t, ErrUnauthorized.Has(err))
+}
+
+func TestVerifyAgreement(t *testing.T) {
+	satellite, uplink := newIdentity(t), newIdentity(t)
+	renter := &provider.PeerIdentity{CA: uplink.CA, Leaf: uplink.Leaf, ID: uplink.ID}
+	signer := NewSigner(satellite)
+	verifier := NewVerifier("node", []string{satellite.ID.String()})
+
+	limit := signLimit(t, signer, &pb.PayerBandwidthAllocation_Data{
+		Renter:            uplink.ID.Bytes(),
+		MaxSize:           1024,
+		ExpirationUnixSec: time.Now().Add(-time.Minute).Unix(),
+		StorageNodeId:     []byte("node"),
+	})
+
+	agreement := func(signer *provider.FullIdentity, total int64) *pb.RenterBandwidthAllocation {
+		data, err := proto.Marshal(&pb.RenterBandwidthAllocation_Data{PayerAllocation: limit, Total: total})
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		signature, err := cryptopasta.Sign(data, signer.Key.(*ecdsa.PrivateKey))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return &pb.RenterBandwidthAllocation{Data: data, Signature: signature}
+	}
+
+	// expired order limits can still be settled
+	data, err := verifier.VerifyAgreement(agreement(uplink, 1024), renter)
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(1024), data.GetTotal())
+	}
+
+	_, err = verifier.VerifyAgreement(agreement(uplink, 1025), renter)
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	_, err = NewVerifier("other", []string{satellite.ID.String()}).VerifyAgreement(agreement(uplink, 1), renter)
+	assert.True(t, ErrUnauthorized.Has(err))
+
+	// the agreement must be signed by the uplink of the order limit
+	other := newIdentity(t)
+	_, err = verifier.VerifyAgreement(agreement(other, 1), renter)
+	assert.True(t, ErrUnauthorized.Has(err))
+	_, err = verifier.VerifyAgreement(agreement(other, 1), &provider.PeerIdentity{CA: other.CA, Leaf: other.Leaf, ID: other.ID})
+	assert.True(t, ErrUnauthorized.Has(err))
+}
+
+func TestUsedSerials(t *testing.T) {
+	db := teststore.New()
+	serials := NewUsedSerials(db, 2)
+	now := time.Now()
+	serials.now = func() time.Time { return now }
+
+	later := now.Add(time.Hour).Unix()
+	assert.NoError(t, serials.Use("a", later))
+	assert.NoError(t, serials.Use("b", now.Add(time.Minute).Unix()))
+	assert.True(t, ErrUnauthorized.Has(serials.Use("a", later)))
+	assert.True(t, ErrUnauthorized.Has(serials.Use("", later)))
+
+	// serial numbers are written to disk as they are used, and the least
+	// recently used is forgotten in memory
+	assert.NoError(t, serials.Use("c", later))
+	assert.Len(t, serials.serials, 2)
+	keys, err := db.List(nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, keys.Strings())
+	assert.True(t, ErrUnauthorized.Has(serials.Use("b", later)))
+
+	// serial numbers are still known after a crash
+	restarted := NewUsedSerials(db, 2)
+	for _, serial := range []string{"a", "b", "c"} {
+		assert.True(t, ErrUnauthorized.Has(restarted.Use(serial, later)), serial)
+	}
+
+	// the order limit of b expired
+	now = now.Add(2 * time.Minute)
+	assert.NoError(t, serials.DeleteExpired())
+	keys, err = db.List(nil, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, keys.Strings())
+	assert.NoError(t, serials.Use("b", later))
+}
+
+func TestNodes(t *testing.T) {
+	signer := NewSigner(newIdentity(t))
+
+	limits := []*pb.PayerBandwidthAllocation{
+		signLimit(t, signer, &pb.PayerBandwidthAllocation_Data{
+			StorageNodeId:      []byte("node1"),
+			StorageNodeAddress: "127.0.0.1:7777",
+		}),
+		signLimit(t, signer, &pb.PayerBandwidthAllocation_Data{
+			StorageNodeId: []byte("node2"),
+		}),
+	}
+	nodes, err := Nodes(limits)
+	if assert.NoError(t, err) && assert.Len(t, nodes, 2) {
+		assert.Equal(t, "node1", nodes[0].GetId())
+		assert.Equal(t, "127.0.0.1:7777", nodes[0].GetAddress().GetAddress())
+		// the satellite didn't know the address of node2
+		assert.Equal(t, "node2", nodes[1].GetId())
+		assert.Nil(t, nodes[1].GetAddress())
+	}
+
+	_, err = Nodes([]*pb.PayerBandwidthAllocation{signLimit(t, signer, &pb.PayerBandwidthAllocation_Data{})})
+	assert.True(t, Error.Has(err))
+}
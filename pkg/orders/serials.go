@@ -0,0 +1,147 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package orders
+
+import (
+	"container/list"
+	"context"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/storage"
+)
+
+// UsedSerials keeps track of the serial numbers of the order limits a storage
+// node accepted, so that replayed order limits are refused. Serial numbers
+// are written to a store on disk before their order limits are accepted, so
+// they are still known after a crash, and the most recently used are also
+// kept in memory, up to a maximum number. Serial numbers are forgotten once
+// their order limits expire, as expired order limits are refused anyway.
+type UsedSerials struct {
+	mu      sync.Mutex
+	db      storage.KeyValueStore
+	max     int
+	serials map[string]*list.Element
+	recent  *list.List // of *usedSerial, the most recently used first
+	now     func() time.Time
+}
+
+type usedSerial struct {
+	serial     string
+	expiration int64
+}
+
+// NewUsedSerials creates UsedSerials that keeps the serial numbers in db,
+// and at most maxInMemory of them in memory
+func NewUsedSerials(db storage.KeyValueStore, maxInMemory int) *UsedSerials {
+	return &UsedSerials{
+		db:      db,
+		max:     maxInMemory,
+		serials: map[string]*list.Element{},
+		recent:  list.New(),
+		now:     time.Now,
+	}
+}
+
+// Use marks the serial number of an order limit that expires at expiration
+// (in unix seconds) as used. It fails if the serial number was already used.
+func (u *UsedSerials) Use(serial string, expiration int64) error {
+	if serial == "" {
+		return ErrUnauthorized.New("order limit has no serial number")
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if elem, ok := u.serials[serial]; ok {
+		u.recent.MoveToFront(elem)
+		return ErrUnauthorized.New("order limit %s was already used", serial)
+	}
+	_, err := u.db.Get(storage.Key(serial))
+	if err == nil {
+		return ErrUnauthorized.New("order limit %s was already used", serial)
+	}
+	if !storage.ErrKeyNotFound.Has(err) {
+		return Error.Wrap(err)
+	}
+
+	value := make([]byte, 8)
+	binary.BigEndian.PutUint64(value, uint64(expiration))
+	if err := u.db.Put(storage.Key(serial), value); err != nil {
+		return Error.Wrap(err)
+	}
+
+	u.serials[serial] = u.recent.PushFront(&usedSerial{serial: serial, expiration: expiration})
+	u.evict()
+	return nil
+}
+
+// evict forgets the least recently used serial numbers in memory until the
+// memory limit is respected. They are still known on disk. u.mu must be held.
+func (u *UsedSerials) evict() {
+	for u.recent.Len() > u.max {
+		elem := u.recent.Back()
+		u.recent.Remove(elem)
+		delete(u.serials, elem.Value.(*usedSerial).serial)
+	}
+}
+
+// DeleteExpired forgets the serial numbers of expired order limits
+func (u *UsedSerials) DeleteExpired() error {
+	now := u.now().Unix()
+
+	u.mu.Lock()
+	for elem := u.recent.Front(); elem != nil; {
+		next := elem.Next()
+		if used := elem.Value.(*usedSerial); used.expiration <= now {
+			u.recent.Remove(elem)
+			delete(u.serials, used.serial)
+		}
+		elem = next
+	}
+	u.mu.Unlock()
+
+	// the disk can't be written while it's iterated
+	var expired storage.Keys
+	err := u.db.Iterate(storage.IterateOptions{Recurse: true}, func(it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(&item) {
+			if len(item.Value) != 8 || int64(binary.BigEndian.Uint64(item.Value)) <= now {
+				expired = append(expired, storage.CloneKey(item.Key))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+
+	for _, key := range expired {
+		if err := u.db.Delete(key); err != nil && !storage.ErrKeyNotFound.Has(err) {
+			return Error.Wrap(err)
+		}
+	}
+	return nil
+}
+
+// Run forgets the serial numbers of expired order limits every interval
+// until ctx is canceled
+func (u *UsedSerials) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := u.DeleteExpired(); err != nil {
+				zap.S().Errorf("Failed deleting expired serial numbers: %v", err)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
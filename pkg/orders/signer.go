@@ -0,0 +1,62 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package orders
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gtank/cryptopasta"
+	"github.com/mr-tron/base58/base58"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls"
+	"storj.io/storj/pkg/provider"
+)
+
+// Signer signs order limits on behalf of a satellite
+type Signer struct {
+	identity *provider.FullIdentity
+}
+
+// NewSigner creates a Signer that signs order limits with the key of the
+// satellite identity
+func NewSigner(identity *provider.FullIdentity) *Signer {
+	return &Signer{identity: identity}
+}
+
+// Sign signs the order limit data. The payer of the order limit is set to the
+// satellite, and a random serial number is assigned if data has none.
+func (s *Signer) Sign(data *pb.PayerBandwidthAllocation_Data) (*pb.PayerBandwidthAllocation, error) {
+	key, ok := s.identity.Key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, peertls.ErrUnsupportedKey.New("%T", s.identity.Key)
+	}
+
+	data.Payer = s.identity.ID.Bytes()
+	if data.SerialNumber == "" {
+		serial := make([]byte, 16)
+		if _, err := rand.Read(serial); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		data.SerialNumber = base58.Encode(serial)
+	}
+
+	serialized, err := proto.Marshal(data)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	signature, err := cryptopasta.Sign(serialized, key)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	return &pb.PayerBandwidthAllocation{
+		Signature: signature,
+		Data:      serialized,
+		Certs:     s.identity.Chain(),
+	}, nil
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package orders
+
+import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gtank/cryptopasta"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls"
+	"storj.io/storj/pkg/provider"
+)
+
+// Verifier verifies the order limits presented to a storage node
+type Verifier struct {
+	nodeID     string
+	satellites map[string]bool
+	now        func() time.Time
+}
+
+// NewVerifier creates a Verifier for the storage node nodeID, which only
+// accepts the order limits of the satellites satelliteIDs
+func NewVerifier(nodeID string, satelliteIDs []string) *Verifier {
+	satellites := map[string]bool{}
+	for _, id := range satelliteIDs {
+		satellites[id] = true
+	}
+	return &Verifier{nodeID: nodeID, satellites: satellites, now: time.Now}
+}
+
+// Verify checks that the order limit was signed by an accepted satellite,
+// hasn't expired, and allows the uplink renterID to perform action, PUT, GET
+// or DELETE, on the piece pieceID of this node. Order limits of repair and audit
+// actions allow the request they upload or download for. It returns the
+// data of the order limit.
+func (v *Verifier) Verify(limit *pb.PayerBandwidthAllocation, action pb.PayerBandwidthAllocation_Action, pieceID, renterID string) (*pb.PayerBandwidthAllocation_Data, error) {
+	data, err := v.verifySignature(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.GetExpirationUnixSec() <= v.now().Unix() {
+		return nil, ErrUnauthorized.New("order limit %s expired", data.GetSerialNumber())
+	}
+	if Request(data.GetAction()) != action {
+		return nil, ErrUnauthorized.New("order limit is for %s, not %s", data.GetAction(), action)
+	}
+	if data.GetPieceId() != pieceID {
+		return nil, ErrUnauthorized.New("order limit is for another piece")
+	}
+	if string(data.GetRenter()) != renterID {
+		return nil, ErrUnauthorized.New("order limit is for another uplink")
+	}
+
+	return data, nil
+}
+
+// VerifyAgreement checks that a bandwidth agreement was signed by renter,
+// that its order limit was signed by an accepted satellite for renter, and
+// that the agreement doesn't exceed it. Agreements are settled after their
+// order limits expire, so expiration isn't checked.
+func (v *Verifier) VerifyAgreement(agreement *pb.RenterBandwidthAllocation, renter *provider.PeerIdentity) (*pb.RenterBandwidthAllocation_Data, error) {
+	key, ok := renter.Leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, peertls.ErrUnsupportedKey.New("%T", renter.Leaf.PublicKey)
+	}
+	if !cryptopasta.Verify(agreement.GetData(), agreement.GetSignature(), key) {
+		return nil, ErrUnauthorized.New("invalid agreement signature")
+	}
+
+	data := &pb.RenterBandwidthAllocation_Data{}
+	if err := proto.Unmarshal(agreement.GetData(), data); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	limit, err := v.verifySignature(data.GetPayerAllocation())
+	if err != nil {
+		return nil, err
+	}
+	if string(limit.GetRenter()) != renter.ID.String() {
+		return nil, ErrUnauthorized.New("order limit is for another uplink")
+	}
+	if data.GetTotal() > limit.GetMaxSize() {
+		return nil, ErrUnauthorized.New("agreement total %d exceeds order limit of %d", data.GetTotal(), limit.GetMaxSize())
+	}
+
+	return data, nil
+}
+
+// verifySignature checks the certificate chain and the signature of the
+// order limit, and that it was issued to this node
+func (v *Verifier) verifySignature(limit *pb.PayerBandwidthAllocation) (*pb.PayerBandwidthAllocation_Data, error) {
+	certs, err := provider.ParseCertChain(limit.GetCerts())
+	if err != nil {
+		return nil, ErrUnauthorized.Wrap(err)
+	}
+	if len(certs) < 2 {
+		return nil, ErrUnauthorized.New("invalid certificate chain")
+	}
+	if err := peertls.VerifyPeerCertChains(nil, [][]*x509.Certificate{certs}); err != nil {
+		return nil, ErrUnauthorized.Wrap(err)
+	}
+
+	satellite, err := provider.PeerIdentityFromCerts(certs[0], certs[1])
+	if err != nil {
+		return nil, ErrUnauthorized.Wrap(err)
+	}
+	if !v.satellites[satellite.ID.String()] {
+		return nil, ErrUnauthorized.New("satellite %s is not trusted", satellite.ID)
+	}
+
+	key, ok := satellite.Leaf.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, peertls.ErrUnsupportedKey.New("%T", satellite.Leaf.PublicKey)
+	}
+	if !cryptopasta.Verify(limit.GetData(), limit.GetSignature(), key) {
+		return nil, ErrUnauthorized.New("invalid signature")
+	}
+
+	data := &pb.PayerBandwidthAllocation_Data{}
+	if err := proto.Unmarshal(limit.GetData(), data); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if string(data.GetPayer()) != satellite.ID.String() {
+		return nil, ErrUnauthorized.New("order limit was signed by another satellite")
+	}
+	if string(data.GetStorageNodeId()) != v.nodeID {
+		return nil, ErrUnauthorized.New("order limit is for another node")
+	}
+
+	return data, nil
+}
@@ -7,6 +7,8 @@ import (
 	"context"
 	"crypto/rand"
 	"log"
+	"sync/atomic"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/zeebo/errs"
@@ -15,10 +17,12 @@ import (
 	"storj.io/storj/pkg/dht"
 	"storj.io/storj/pkg/kademlia"
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/utils"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/boltdb"
 	"storj.io/storj/storage/redis"
 	"storj.io/storj/storage/storelogger"
+	"storj.io/storj/storage/writethrough"
 )
 
 const (
@@ -36,6 +40,10 @@ var OverlayError = errs.Class("Overlay Error")
 type Cache struct {
 	DB  storage.KeyValueStore
 	DHT dht.DHT
+
+	// refreshed is the time of the last successful refresh in unix
+	// nanoseconds, accessed atomically
+	refreshed int64
 }
 
 // NewRedisOverlayCache returns a pointer to a new Cache instance with an initialized connection to Redis.
@@ -51,6 +59,46 @@ func NewRedisOverlayCache(address, password string, db int, DHT dht.DHT) (*Cache
 	}, nil
 }
 
+// NewRedisOverlayCacheFrom returns a pointer to a new Cache instance with an
+// initialized connection to the Redis of a connection URL, which may be a
+// Sentinel or Cluster deployment (see redis.NewClientFrom).
+func NewRedisOverlayCacheFrom(address string, DHT dht.DHT) (*Cache, error) {
+	rc, err := redis.NewClientFrom(address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Cache{
+		DB:  rc,
+		DHT: DHT,
+	}, nil
+}
+
+// NewWriteThroughOverlayCache returns a pointer to a new Cache instance that
+// reads from the Redis of a connection URL and writes through to a Bolt db,
+// which Redis is rebuilt from when it starts empty. Nodes expire in the Bolt
+// db with the TTL of Redis.
+func NewWriteThroughOverlayCache(address, dbPath string, DHT dht.DHT) (*Cache, error) {
+	rc, err := redis.NewClientFrom(address)
+	if err != nil {
+		return nil, err
+	}
+	bc, err := boltdb.New(dbPath, OverlayBucket)
+	if err != nil {
+		return nil, utils.CombineErrors(err, rc.Close())
+	}
+
+	db, err := writethrough.New(rc, bc, rc.TTL)
+	if err != nil {
+		return nil, utils.CombineErrors(err, rc.Close(), bc.Close())
+	}
+
+	return &Cache{
+		DB:  db,
+		DHT: DHT,
+	}, nil
+}
+
 // NewBoltOverlayCache returns a pointer to a new Cache instance with an initialized connection to a Bolt db.
 func NewBoltOverlayCache(dbPath string, DHT dht.DHT) (*Cache, error) {
 	bc, err := boltdb.New(dbPath, OverlayBucket)
@@ -145,6 +193,9 @@ func (o *Cache) Bootstrap(ctx context.Context) error {
 		}
 	}
 
+	if err == nil {
+		o.markRefreshed()
+	}
 	return err
 }
 
@@ -194,9 +245,24 @@ func (o *Cache) Refresh(ctx context.Context) error {
 
 	}
 
+	o.markRefreshed()
 	return err
 }
 
+func (o *Cache) markRefreshed() {
+	atomic.StoreInt64(&o.refreshed, time.Now().UnixNano())
+}
+
+// LastRefresh returns when the cache was last bootstrapped or refreshed
+// successfully, or the zero time if it never was
+func (o *Cache) LastRefresh() time.Time {
+	refreshed := atomic.LoadInt64(&o.refreshed)
+	if refreshed == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, refreshed)
+}
+
 // Walk iterates over each node in each bucket to traverse the network
 func (o *Cache) Walk(ctx context.Context) error {
 	// TODO: This should walk the cache, rather than be a duplicate of refresh
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"github.com/zeebo/errs"
+	"google.golang.org/grpc"
 
 	"storj.io/storj/pkg/dht"
 	"storj.io/storj/pkg/pb"
@@ -18,6 +19,7 @@ import (
 // Choose returns a list of storage NodeID's that fit the provided criteria.
 // 	limit is the maximum number of nodes to be returned.
 // 	space is the storage and bandwidth requested consumption in bytes.
+// 	bucket is the bucket the nodes store data of, for its placement.
 //
 // Lookup finds a Node with the provided identifier.
 
@@ -26,14 +28,18 @@ var ClientError = errs.Class("Client Error")
 
 //Client implements the Overlay Client interface
 type Client interface {
-	Choose(ctx context.Context, limit int, space int64) ([]*pb.Node, error)
+	Choose(ctx context.Context, limit int, space int64, bucket string) ([]*pb.Node, error)
 	Lookup(ctx context.Context, nodeID dht.NodeID) (*pb.Node, error)
 	BulkLookup(ctx context.Context, nodeIDs []dht.NodeID) ([]*pb.Node, error)
 }
 
 // Overlay is the overlay concrete implementation of the client interface
 type Overlay struct {
+	conn   *grpc.ClientConn
 	client pb.OverlayClient
+	// APIKey identifies the project of the uploads nodes are chosen for,
+	// for its placement
+	APIKey []byte
 }
 
 // NewOverlayClient returns a new intialized Overlay Client
@@ -42,26 +48,37 @@ func NewOverlayClient(identity *provider.FullIdentity, address string) (*Overlay
 	if err != nil {
 		return nil, err
 	}
-	c, err := NewClient(address, dialOpt)
+	conn, err := grpc.Dial(address, dialOpt)
 	if err != nil {
 		return nil, err
 	}
 
 	return &Overlay{
-		client: c,
+		conn:   conn,
+		client: pb.NewOverlayClient(conn),
 	}, nil
 }
 
+// Close closes the connection to the overlay
+func (o *Overlay) Close() error {
+	if o.conn == nil {
+		return nil
+	}
+	return o.conn.Close()
+}
+
 // a compiler trick to make sure *Overlay implements Client
 var _ Client = (*Overlay)(nil)
 
 // Choose implements the client.Choose interface
-func (o *Overlay) Choose(ctx context.Context, amount int, space int64) ([]*pb.Node, error) {
+func (o *Overlay) Choose(ctx context.Context, amount int, space int64, bucket string) ([]*pb.Node, error) {
 	// TODO(coyle): We will also need to communicate with the reputation service here
 	resp, err := o.client.FindStorageNodes(ctx, &pb.FindStorageNodesRequest{
 		Opts: &pb.OverlayOptions{Amount: int64(amount), Restrictions: &pb.NodeRestrictions{
 			FreeDisk: space,
 		}},
+		ApiKey: o.APIKey,
+		Bucket: bucket,
 	})
 	if err != nil {
 		return nil, Error.Wrap(err)
@@ -98,3 +115,18 @@ func (o *Overlay) BulkLookup(ctx context.Context, nodeIDs []dht.NodeID) ([]*pb.N
 	}
 	return nodes, nil
 }
+
+// UpdateOperator updates the operator of the calling storage node. It's
+// called by storage nodes, which are identified by their TLS identity.
+func (o *Overlay) UpdateOperator(ctx context.Context, operator *pb.NodeOperator) error {
+	_, err := o.client.UpdateOperator(ctx, &pb.UpdateOperatorRequest{Operator: operator})
+	return ClientError.Wrap(err)
+}
+
+// Echo asks the satellite to dial the calling storage node back at address,
+// to check that it's reachable from outside. If address has no host, the
+// host the request comes from is dialed.
+func (o *Overlay) Echo(ctx context.Context, address string) (*pb.EchoResponse, error) {
+	resp, err := o.client.Echo(ctx, &pb.EchoRequest{Address: address})
+	return resp, ClientError.Wrap(err)
+}
@@ -0,0 +1,85 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strings"
+)
+
+// Geolocator locates IP addresses
+type Geolocator interface {
+	// Country returns the ISO 3166-1 alpha-2 code of the country of ip, or
+	// false if it's unknown
+	Country(ip net.IP) (string, bool)
+}
+
+// CIDRGeolocator locates IP addresses from a list of networks and their
+// countries
+type CIDRGeolocator struct {
+	networks []locatedNetwork
+}
+
+type locatedNetwork struct {
+	network *net.IPNet
+	country string
+}
+
+// LoadCIDRGeolocator loads the networks of the file at path, which has a
+// network in CIDR notation and the code of its country per line, e.g.
+// "192.0.2.0/24 DE". Empty lines and lines starting with # are ignored.
+func LoadCIDRGeolocator(path string) (_ *CIDRGeolocator, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { _ = file.Close() }()
+
+	locator := &CIDRGeolocator{}
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, Error.New("%s:%d: expected a network and a country", path, line)
+		}
+		if err := locator.Add(fields[0], fields[1]); err != nil {
+			return nil, Error.New("%s:%d: %v", path, line, err)
+		}
+	}
+	return locator, Error.Wrap(scanner.Err())
+}
+
+// Add locates the addresses of the network cidr in country
+func (locator *CIDRGeolocator) Add(cidr, country string) error {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if !countryPattern.MatchString(country) {
+		return Error.New("invalid country %q", country)
+	}
+	locator.networks = append(locator.networks, locatedNetwork{network: network, country: country})
+	return nil
+}
+
+// Country implements Geolocator. The most specific network containing ip
+// determines its country.
+func (locator *CIDRGeolocator) Country(ip net.IP) (string, bool) {
+	country, best := "", -1
+	for _, located := range locator.networks {
+		if !located.network.Contains(ip) {
+			continue
+		}
+		if ones, _ := located.network.Mask.Size(); ones > best {
+			country, best = located.country, ones
+		}
+	}
+	return country, best >= 0
+}
@@ -5,6 +5,7 @@ package overlay
 
 import (
 	"context"
+	"sort"
 	"strings"
 
 	"github.com/zeebo/errs"
@@ -48,6 +49,22 @@ func (mo *MockOverlay) Lookup(ctx context.Context, req *pb.LookupRequest) (
 	return &pb.LookupResponse{Node: mo.nodes[req.NodeID]}, nil
 }
 
+// ListNodes lists all nodes of the mock by id
+func (mo *MockOverlay) ListNodes(ctx context.Context, req *pb.ListNodesRequest) (
+	*pb.ListNodesResponse, error) {
+	ids := make([]string, 0, len(mo.nodes))
+	for id := range mo.nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	resp := &pb.ListNodesResponse{}
+	for _, id := range ids {
+		resp.Nodes = append(resp.Nodes, mo.nodes[id])
+	}
+	return resp, nil
+}
+
 //BulkLookup finds multiple storage nodes based on the requests
 func (mo *MockOverlay) BulkLookup(ctx context.Context, reqs *pb.LookupRequests) (
 	*pb.LookupResponses, error) {
@@ -60,6 +77,19 @@ func (mo *MockOverlay) BulkLookup(ctx context.Context, reqs *pb.LookupRequests)
 	return &pb.LookupResponses{Lookupresponse: responses}, nil
 }
 
+// UpdateOperator updates the operator of a node of the mock. The mock
+// doesn't know the calling node, so the request fails.
+func (mo *MockOverlay) UpdateOperator(ctx context.Context, req *pb.UpdateOperatorRequest) (
+	*pb.UpdateOperatorResponse, error) {
+	return nil, errs.New("the mock overlay doesn't update node operators")
+}
+
+// Echo dials the calling node back. The mock doesn't dial nodes, so the
+// request fails.
+func (mo *MockOverlay) Echo(ctx context.Context, req *pb.EchoRequest) (*pb.EchoResponse, error) {
+	return nil, errs.New("the mock overlay doesn't dial nodes back")
+}
+
 // MockConfig specifies static nodes for mock overlay
 type MockConfig struct {
 	Nodes string `help:"a comma-separated list of <node-id>:<ip>:<port>" default:""`
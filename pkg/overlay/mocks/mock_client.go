@@ -50,16 +50,16 @@ func (mr *MockClientMockRecorder) BulkLookup(arg0, arg1 interface{}) *gomock.Cal
 }
 
 // Choose mocks base method
-func (m *MockClient) Choose(arg0 context.Context, arg1 int, arg2 int64) ([]*pb.Node, error) {
-	ret := m.ctrl.Call(m, "Choose", arg0, arg1, arg2)
+func (m *MockClient) Choose(arg0 context.Context, arg1 int, arg2 int64, arg3 string) ([]*pb.Node, error) {
+	ret := m.ctrl.Call(m, "Choose", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].([]*pb.Node)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Choose indicates an expected call of Choose
-func (mr *MockClientMockRecorder) Choose(arg0, arg1, arg2 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Choose", reflect.TypeOf((*MockClient)(nil).Choose), arg0, arg1, arg2)
+func (mr *MockClientMockRecorder) Choose(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Choose", reflect.TypeOf((*MockClient)(nil).Choose), arg0, arg1, arg2, arg3)
 }
 
 // Lookup mocks base method
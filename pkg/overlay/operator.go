@@ -0,0 +1,71 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"net/mail"
+	"regexp"
+	"strings"
+
+	"github.com/gtank/cryptopasta"
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/peertls"
+)
+
+// ErrOperator is returned for invalid node operators
+var ErrOperator = errs.Class("invalid node operator")
+
+// walletPattern matches ethereum addresses
+var walletPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+// countryPattern matches ISO 3166-1 alpha-2 country codes
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+// walletMessage returns the message signed to prove the choice of wallet
+func walletMessage(wallet string) []byte {
+	return []byte("storj node wallet:" + strings.ToLower(wallet))
+}
+
+// SignWallet signs wallet with the key of a node's certificate authority, to
+// prove that the owner of the node chose it
+func SignWallet(caKey crypto.PrivateKey, wallet string) ([]byte, error) {
+	key, ok := caKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, peertls.ErrUnsupportedKey.New("%T", caKey)
+	}
+	signature, err := cryptopasta.Sign(walletMessage(wallet), key)
+	return signature, Error.Wrap(err)
+}
+
+// ValidateOperator checks the format of the email, wallet and country of a
+// node operator and, if the wallet is signed, that it was signed by the key
+// of the node's certificate authority
+func ValidateOperator(operator *pb.NodeOperator, caKey crypto.PublicKey) error {
+	address, err := mail.ParseAddress(operator.GetEmail())
+	if err != nil || address.Address != operator.GetEmail() {
+		return ErrOperator.New("invalid email %q", operator.GetEmail())
+	}
+	if !walletPattern.MatchString(operator.GetWallet()) {
+		return ErrOperator.New("invalid wallet %q", operator.GetWallet())
+	}
+	if operator.GetCountry() != "" && !countryPattern.MatchString(operator.GetCountry()) {
+		return ErrOperator.New("invalid country %q", operator.GetCountry())
+	}
+
+	if len(operator.GetWalletSignature()) == 0 {
+		return nil
+	}
+	key, ok := caKey.(*ecdsa.PublicKey)
+	if !ok {
+		return peertls.ErrUnsupportedKey.New("%T", caKey)
+	}
+	if !cryptopasta.Verify(walletMessage(operator.GetWallet()), operator.GetWalletSignature(), key) {
+		return ErrOperator.New("invalid wallet signature")
+	}
+	return nil
+}
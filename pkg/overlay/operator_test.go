@@ -0,0 +1,51 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
+)
+
+func TestValidateOperator(t *testing.T) {
+	ctx := context.Background()
+	ca, err := provider.NewCA(ctx, 12, 4)
+	require.NoError(t, err)
+	other, err := provider.NewCA(ctx, 12, 4)
+	require.NoError(t, err)
+
+	wallet := "0x0123456789abcdef0123456789ABCDEF01234567"
+	signature, err := SignWallet(ca.Key, wallet)
+	require.NoError(t, err)
+	otherSignature, err := SignWallet(other.Key, wallet)
+	require.NoError(t, err)
+
+	for _, tt := range []struct {
+		name     string
+		operator *pb.NodeOperator
+		valid    bool
+	}{
+		{"unsigned", &pb.NodeOperator{Email: "ops@example.com", Wallet: wallet}, true},
+		{"signed", &pb.NodeOperator{Email: "ops@example.com", Wallet: wallet, WalletSignature: signature}, true},
+		{"bad email", &pb.NodeOperator{Email: "ops", Wallet: wallet}, false},
+		{"named email", &pb.NodeOperator{Email: "Ops <ops@example.com>", Wallet: wallet}, false},
+		{"short wallet", &pb.NodeOperator{Email: "ops@example.com", Wallet: "0x0123"}, false},
+		{"bad wallet", &pb.NodeOperator{Email: "ops@example.com", Wallet: "0123456789abcdef0123456789ABCDEF0123456789"}, false},
+		{"other signer", &pb.NodeOperator{Email: "ops@example.com", Wallet: wallet, WalletSignature: otherSignature}, false},
+		{"bad signature", &pb.NodeOperator{Email: "ops@example.com", Wallet: wallet, WalletSignature: []byte("bad")}, false},
+	} {
+		err := ValidateOperator(tt.operator, ca.Cert.PublicKey)
+		if tt.valid {
+			assert.NoError(t, err, tt.name)
+		} else {
+			assert.True(t, ErrOperator.Has(err), tt.name)
+		}
+	}
+}
@@ -105,3 +105,57 @@ func TestOverlayBulkLookup(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, r)
 }
+
+func TestOverlayListNodes(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	var items []storage.ListItem
+	for i := 0; i < 3; i++ {
+		id, err := kademlia.NewID()
+		assert.NoError(t, err)
+		items = append(items, storage.ListItem{
+			Key:   storage.Key(id.String()),
+			Value: NewNodeAddressValue(t, "127.0.0.1:9090"),
+		})
+	}
+
+	srv := NewMockServer(items)
+	go func() { assert.NoError(t, srv.Serve(lis)) }()
+	defer srv.Stop()
+
+	address := lis.Addr().String()
+	c, err := NewClient(address, grpc.WithInsecure())
+	assert.NoError(t, err)
+
+	r, err := c.ListNodes(context.Background(), &pb.ListNodesRequest{Limit: 2})
+	if assert.NoError(t, err) {
+		assert.Len(t, r.Nodes, 2)
+		assert.NotEmpty(t, r.Cursor)
+	}
+
+	r, err = c.ListNodes(context.Background(), &pb.ListNodesRequest{Limit: 2, Cursor: r.GetCursor()})
+	if assert.NoError(t, err) {
+		assert.Len(t, r.Nodes, 1)
+		assert.Empty(t, r.Cursor)
+	}
+}
+
+func TestEchoAddress(t *testing.T) {
+	ctx := context.Background()
+	seen := &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 34567}
+
+	address, err := echoAddress(ctx, ":7777", seen)
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:7777", address)
+
+	address, err = echoAddress(ctx, "127.0.0.1:7777", seen)
+	assert.NoError(t, err)
+	assert.Equal(t, "127.0.0.1:7777", address)
+
+	_, err = echoAddress(ctx, "10.0.0.1:7777", seen)
+	assert.Error(t, err)
+
+	_, err = echoAddress(ctx, "7777", seen)
+	assert.Error(t, err)
+}
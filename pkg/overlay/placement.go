@@ -0,0 +1,238 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"strings"
+
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
+)
+
+// PlacementBucket is the bolt bucket of the placements assigned to buckets
+// and projects
+const PlacementBucket = "placements"
+
+// Placement constrains the nodes storing the data of the buckets it's
+// assigned to to the nodes in some countries, e.g. the EU
+type Placement struct {
+	Name      string
+	Countries map[string]bool
+}
+
+// Allows returns whether the nodes in country may store data under the
+// placement. The nil placement allows every node.
+func (p *Placement) Allows(country string) bool {
+	return p == nil || p.Countries[country]
+}
+
+// ParsePlacements parses placements like "eu=DE,FR,NL;us=US"
+func ParsePlacements(rules string) (map[string]*Placement, error) {
+	placements := map[string]*Placement{}
+	for _, rule := range strings.Split(rules, ";") {
+		if strings.TrimSpace(rule) == "" {
+			continue
+		}
+		parts := strings.SplitN(rule, "=", 2)
+		name := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || name == "" {
+			return nil, Error.New("invalid placement %q", rule)
+		}
+		placement := &Placement{Name: name, Countries: map[string]bool{}}
+		for _, country := range strings.Split(parts[1], ",") {
+			country = strings.TrimSpace(country)
+			if !countryPattern.MatchString(country) {
+				return nil, Error.New("invalid country %q of placement %q", country, name)
+			}
+			placement.Countries[country] = true
+		}
+		placements[name] = placement
+	}
+	return placements, nil
+}
+
+// PlacementAssignment assigns a placement to a project, or to one of its
+// buckets, which takes precedence over the placement of its project
+type PlacementAssignment struct {
+	Project   string `json:"project"`
+	Bucket    string `json:"bucket,omitempty"`
+	Placement string `json:"placement"`
+}
+
+// Placements stores the placements assigned to buckets and projects, and
+// checks which nodes they allow
+type Placements struct {
+	rules  map[string]*Placement
+	db     storage.KeyValueStore
+	locate Geolocator
+}
+
+// NewPlacements creates the placements of rules, assigned in db. The
+// countries declared by node operators are checked with locate, if not nil.
+func NewPlacements(rules map[string]*Placement, db storage.KeyValueStore, locate Geolocator) *Placements {
+	return &Placements{rules: rules, db: db, locate: locate}
+}
+
+func placementKey(project, bucket string) storage.Key {
+	return storage.Key(project + "/" + bucket)
+}
+
+// Assign assigns a placement to a project or bucket
+func (p *Placements) Assign(ctx context.Context, assignment PlacementAssignment) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	if assignment.Project == "" || strings.Contains(assignment.Project+assignment.Bucket, "/") {
+		return Error.New("invalid project %q or bucket %q", assignment.Project, assignment.Bucket)
+	}
+	if _, ok := p.rules[assignment.Placement]; !ok {
+		return Error.New("unknown placement %q", assignment.Placement)
+	}
+	value, err := json.Marshal(assignment)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(p.db.Put(placementKey(assignment.Project, assignment.Bucket), value))
+}
+
+// Unassign removes the placement of a project or bucket
+func (p *Placements) Unassign(ctx context.Context, project, bucket string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = p.db.Delete(placementKey(project, bucket))
+	if storage.ErrKeyNotFound.Has(err) {
+		return err
+	}
+	return Error.Wrap(err)
+}
+
+// List returns the assigned placements, ordered by project and bucket
+func (p *Placements) List(ctx context.Context) (assignments []PlacementAssignment, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = p.db.Iterate(storage.IterateOptions{Recurse: true}, func(it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(&item) {
+			var assignment PlacementAssignment
+			if err := json.Unmarshal(item.Value, &assignment); err != nil {
+				return err
+			}
+			assignments = append(assignments, assignment)
+		}
+		return nil
+	})
+	return assignments, Error.Wrap(err)
+}
+
+// Lookup returns the placement of bucket of the project of apiKey, or of
+// the project if the bucket has none. It returns nil if neither has one,
+// or if p is nil.
+func (p *Placements) Lookup(ctx context.Context, apiKey []byte, bucket string) (_ *Placement, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if p == nil {
+		return nil, nil
+	}
+	project := macaroon.ProjectID(apiKey)
+	keys := storage.Keys{placementKey(project, "")}
+	if bucket != "" {
+		keys = storage.Keys{placementKey(project, bucket), keys[0]}
+	}
+	values, err := p.db.GetAll(keys)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		var assignment PlacementAssignment
+		if err := json.Unmarshal(value, &assignment); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		placement, ok := p.rules[assignment.Placement]
+		if !ok {
+			// refuse to place data anywhere rather than ignore the placement
+			return nil, Error.New("unknown placement %q", assignment.Placement)
+		}
+		return placement, nil
+	}
+	return nil, nil
+}
+
+// Allows returns whether node may store data under placement
+func (p *Placements) Allows(placement *Placement, node *pb.Node) bool {
+	if placement == nil {
+		return true
+	}
+	return placement.Allows(p.Country(node))
+}
+
+// Country returns the country of node declared by its operator. If the
+// placements check countries with a Geolocator, the country is only returned
+// if the IP address of the node is located in it.
+func (p *Placements) Country(node *pb.Node) string {
+	country := node.GetOperator().GetCountry()
+	if country == "" || p.locate == nil {
+		return country
+	}
+	host, _, err := net.SplitHostPort(node.GetAddress().GetAddress())
+	if err != nil {
+		return ""
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+	located, ok := p.locate.Country(ip)
+	if !ok || located != country {
+		return ""
+	}
+	return country
+}
+
+// ServeHTTP implements the admin API of the placements, mounted at
+// /overlay/placements/:
+//
+//	GET    /overlay/placements/                          lists the assigned placements
+//	POST   /overlay/placements/                          assigns the JSON PlacementAssignment
+//	DELETE /overlay/placements/?project=ID[&bucket=NAME]  removes a placement
+func (p *Placements) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	if strings.Trim(strings.TrimPrefix(req.URL.Path, "/overlay/placements"), "/") != "" {
+		http.NotFound(w, req)
+		return
+	}
+
+	var result interface{}
+	var err error
+	switch req.Method {
+	case http.MethodGet:
+		result, err = p.List(ctx)
+	case http.MethodPost:
+		var assignment PlacementAssignment
+		if err := json.NewDecoder(req.Body).Decode(&assignment); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = p.Assign(ctx, assignment)
+		result = assignment
+	case http.MethodDelete:
+		err = p.Unassign(ctx, req.URL.Query().Get("project"), req.URL.Query().Get("bucket"))
+		result = struct{}{}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case storage.ErrKeyNotFound.Has(err):
+		http.NotFound(w, req)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package overlay
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestParsePlacements(t *testing.T) {
+	placements, err := ParsePlacements("eu=DE, FR;us=US;")
+	require.NoError(t, err)
+	assert.Len(t, placements, 2)
+	assert.True(t, placements["eu"].Allows("FR"))
+	assert.False(t, placements["eu"].Allows("US"))
+	assert.True(t, placements["us"].Allows("US"))
+
+	var none *Placement
+	assert.True(t, none.Allows(""))
+
+	for _, rules := range []string{"eu", "=DE", "eu=de", "eu=DE,"} {
+		_, err := ParsePlacements(rules)
+		assert.Error(t, err, rules)
+	}
+}
+
+func TestCIDRGeolocator(t *testing.T) {
+	locator := &CIDRGeolocator{}
+	require.NoError(t, locator.Add("192.0.2.0/24", "DE"))
+	require.NoError(t, locator.Add("192.0.2.128/25", "FR"))
+	assert.Error(t, locator.Add("192.0.2.0/24", "Germany"))
+	assert.Error(t, locator.Add("192.0.2.0", "DE"))
+
+	for _, tt := range []struct {
+		ip      string
+		country string
+		ok      bool
+	}{
+		{"192.0.2.1", "DE", true},
+		{"192.0.2.200", "FR", true},
+		{"198.51.100.1", "", false},
+	} {
+		country, ok := locator.Country(net.ParseIP(tt.ip))
+		assert.Equal(t, tt.country, country, tt.ip)
+		assert.Equal(t, tt.ok, ok, tt.ip)
+	}
+}
+
+func TestPlacements(t *testing.T) {
+	ctx := context.Background()
+	rules, err := ParsePlacements("eu=DE,FR;us=US")
+	require.NoError(t, err)
+	locator := &CIDRGeolocator{}
+	require.NoError(t, locator.Add("192.0.2.0/24", "DE"))
+	placements := NewPlacements(rules, teststore.New(), locator)
+
+	root, err := macaroon.NewAPIKey([]byte("secret"))
+	require.NoError(t, err)
+	restricted, err := root.Restrict(pb.Caveat{DisallowDeletes: true})
+	require.NoError(t, err)
+	apiKey := []byte(restricted.Serialize())
+	project := macaroon.ProjectID([]byte(root.Serialize()))
+	assert.Equal(t, project, macaroon.ProjectID(apiKey))
+
+	placement, err := placements.Lookup(ctx, apiKey, "photos")
+	require.NoError(t, err)
+	assert.Nil(t, placement)
+
+	require.NoError(t, placements.Assign(ctx, PlacementAssignment{Project: project, Placement: "eu"}))
+	require.NoError(t, placements.Assign(ctx, PlacementAssignment{Project: project, Bucket: "photos", Placement: "us"}))
+	assert.Error(t, placements.Assign(ctx, PlacementAssignment{Project: project, Placement: "asia"}))
+	assert.Error(t, placements.Assign(ctx, PlacementAssignment{Project: project, Bucket: "a/b", Placement: "eu"}))
+
+	// the placement of a bucket takes precedence over its project's
+	placement, err = placements.Lookup(ctx, apiKey, "photos")
+	require.NoError(t, err)
+	assert.Equal(t, "us", placement.Name)
+	placement, err = placements.Lookup(ctx, apiKey, "videos")
+	require.NoError(t, err)
+	assert.Equal(t, "eu", placement.Name)
+
+	assignments, err := placements.List(ctx)
+	require.NoError(t, err)
+	assert.Len(t, assignments, 2)
+
+	require.NoError(t, placements.Unassign(ctx, project, "photos"))
+	placement, err = placements.Lookup(ctx, apiKey, "photos")
+	require.NoError(t, err)
+	assert.Equal(t, "eu", placement.Name)
+
+	node := func(address, country string) *pb.Node {
+		return &pb.Node{
+			Address:  &pb.NodeAddress{Address: address},
+			Operator: &pb.NodeOperator{Country: country},
+		}
+	}
+	// the declared country must match the location of the node's address
+	assert.True(t, placements.Allows(placement, node("192.0.2.1:7777", "DE")))
+	assert.False(t, placements.Allows(placement, node("192.0.2.1:7777", "FR")))
+	assert.False(t, placements.Allows(placement, node("198.51.100.1:7777", "DE")))
+	assert.False(t, placements.Allows(placement, node("192.0.2.1:7777", "")))
+	assert.True(t, placements.Allows(nil, node("198.51.100.1:7777", "")))
+
+	var none *Placements
+	placement, err = none.Lookup(ctx, apiKey, "photos")
+	assert.NoError(t, err)
+	assert.Nil(t, placement)
+}
+
+func TestPlacementsServeHTTP(t *testing.T) {
+	rules, err := ParsePlacements("eu=DE,FR")
+	require.NoError(t, err)
+	placements := NewPlacements(rules, teststore.New(), nil)
+
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		placements.ServeHTTP(w, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return w
+	}
+
+	w := serve(http.MethodPost, "/overlay/placements/", `{"project":"p","bucket":"b","placement":"eu"}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve(http.MethodPost, "/overlay/placements/", `{"project":"p","placement":"us"}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = serve(http.MethodGet, "/overlay/placements/", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"bucket":"b"`)
+
+	w = serve(http.MethodDelete, "/overlay/placements/?project=p&bucket=b", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	w = serve(http.MethodDelete, "/overlay/placements/?project=p&bucket=b", "")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
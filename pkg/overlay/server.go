@@ -6,17 +6,25 @@ package overlay
 import (
 	"context"
 	"fmt"
+	"net"
+	"strings"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 	"gopkg.in/spacemonkeygo/monkit.v2"
+	"storj.io/storj/pkg/certificates"
 	"storj.io/storj/pkg/dht"
 
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
 	"storj.io/storj/storage"
 )
 
@@ -29,6 +37,17 @@ type Server struct {
 	cache   *Cache
 	logger  *zap.Logger
 	metrics *monkit.Registry
+	// signed, if set, restricts the nodes found for storing data to the nodes
+	// with signed identities
+	signed *certificates.AuthorizationDB
+	// whitelist, if set, restricts the nodes found for storing data to the
+	// nodes of a private network
+	whitelist *provider.Whitelist
+	// placements, if set, constrain the nodes found for storing the data of
+	// buckets and projects they are assigned to
+	placements *Placements
+	// transport, if set, dials storage nodes back for Echo
+	transport transport.Client
 }
 
 // Lookup finds the address of a node in our overlay network
@@ -63,11 +82,16 @@ func (o *Server) FindStorageNodes(ctx context.Context, req *pb.FindStorageNodesR
 	restrictedBandwidth := restrictions.GetFreeBandwidth()
 	restrictedSpace := restrictions.GetFreeDisk()
 
+	placement, err := o.placements.Lookup(ctx, req.GetApiKey(), req.GetBucket())
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
 	var start storage.Key
 	result := []*pb.Node{}
 	for {
 		var nodes []*pb.Node
-		nodes, start, err = o.populate(ctx, start, maxNodes, restrictedBandwidth, restrictedSpace)
+		nodes, start, err = o.populate(ctx, start, maxNodes, restrictedBandwidth, restrictedSpace, placement)
 		if err != nil {
 			return nil, Error.Wrap(err)
 		}
@@ -97,6 +121,151 @@ func (o *Server) FindStorageNodes(ctx context.Context, req *pb.FindStorageNodesR
 	}, nil
 }
 
+// ListNodes lists the nodes in the overlay cache by id
+func (o *Server) ListNodes(ctx context.Context, req *pb.ListNodesRequest) (resp *pb.ListNodesResponse, err error) {
+	opts, err := storage.ApplyCursor(storage.ListOptions{
+		Recursive:    true,
+		IncludeValue: true,
+		Limit:        int(req.GetLimit()),
+	}, req.GetCursor())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	items, more, err := storage.ListV2(o.cache.DB, opts)
+	if err != nil {
+		o.logger.Error("Error listing nodes", zap.Error(err))
+		return nil, Error.Wrap(err)
+	}
+
+	resp = &pb.ListNodesResponse{Cursor: storage.NextCursor(opts, items, more)}
+	for _, item := range items {
+		node := &pb.Node{}
+		if err := proto.Unmarshal(item.Value, node); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		resp.Nodes = append(resp.Nodes, node)
+	}
+	return resp, nil
+}
+
+// UpdateOperator updates the operator of the calling storage node, which
+// must have been checked in already
+func (o *Server) UpdateOperator(ctx context.Context, req *pb.UpdateOperatorRequest) (resp *pb.UpdateOperatorResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	identity, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+	if err := ValidateOperator(req.GetOperator(), identity.CA.PublicKey); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+
+	node, err := o.cache.Get(ctx, identity.ID.String())
+	if err != nil && !storage.ErrKeyNotFound.Has(err) {
+		return nil, Error.Wrap(err)
+	}
+	if node == nil {
+		return nil, status.Errorf(codes.NotFound, "node %s isn't known yet", identity.ID)
+	}
+
+	node.Operator = req.GetOperator()
+	if err := o.cache.Put(node.GetId(), *node); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	o.logger.Info("updated node operator", zap.String("node", node.GetId()))
+	return &pb.UpdateOperatorResponse{}, nil
+}
+
+// Echo dials the calling storage node back at the address of the request and
+// checks that it answers with its identity. Nodes that aren't reachable get
+// the reason in the response rather than an error. The host of the address
+// must resolve to the host the request came from, so that the satellite
+// doesn't dial arbitrary hosts on behalf of its callers.
+func (o *Server) Echo(ctx context.Context, req *pb.EchoRequest) (resp *pb.EchoResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if o.transport == nil {
+		return nil, status.Errorf(codes.Unimplemented, "echo isn't enabled")
+	}
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "unable to get grpc peer from context")
+	}
+	identity, err := provider.PeerIdentityFromPeer(p)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+
+	resp = &pb.EchoResponse{SeenAddress: p.Addr.String()}
+	resp.DialedAddress, err = echoAddress(ctx, req.GetAddress(), p.Addr)
+	if err == nil {
+		err = o.echo(ctx, identity.ID.String(), resp.DialedAddress)
+	}
+	if err != nil {
+		o.logger.Debug("echo failed", zap.String("node", identity.ID.String()), zap.Error(err))
+		resp.Error = err.Error()
+	}
+	resp.Reachable = err == nil
+	resp.Time = ptypes.TimestampNow()
+	return resp, nil
+}
+
+// echoAddress returns the address to dial a node back at, from the address
+// it claims and the address its request came from
+func echoAddress(ctx context.Context, address string, seen net.Addr) (string, error) {
+	seenHost, _, err := net.SplitHostPort(seen.String())
+	if err != nil {
+		return "", Error.Wrap(err)
+	}
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return "", Error.New("invalid address %q: %v", address, err)
+	}
+	if host == "" {
+		return net.JoinHostPort(seenHost, port), nil
+	}
+
+	ips, err := net.DefaultResolver.LookupHost(ctx, host)
+	if err != nil {
+		return "", Error.New("can't resolve %s: %v", host, err)
+	}
+	seenIP := net.ParseIP(seenHost)
+	for _, ip := range ips {
+		if net.ParseIP(ip).Equal(seenIP) {
+			return address, nil
+		}
+	}
+	return "", Error.New("%s resolves to %s, but the request came from %s", host,
+		strings.Join(ips, ", "), seenHost)
+}
+
+// echo dials the node nodeID at address and checks its identity
+func (o *Server) echo(ctx context.Context, nodeID, address string) error {
+	conn, err := o.transport.DialNode(ctx, &pb.Node{
+		Id:      nodeID,
+		Address: &pb.NodeAddress{Transport: pb.NodeTransport_TCP, Address: address},
+	})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+
+	var p peer.Peer
+	if _, err := pb.NewPieceStoreRoutesClient(conn).Stats(ctx, &pb.StatsReq{}, grpc.Peer(&p)); err != nil {
+		return Error.Wrap(err)
+	}
+	identity, err := provider.PeerIdentityFromPeer(&p)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if identity.ID.String() != nodeID {
+		return Error.New("%s is the node %s", address, identity.ID)
+	}
+	return nil
+}
+
 func (o *Server) getNodes(ctx context.Context, keys storage.Keys) ([]*pb.Node, error) {
 	values, err := o.cache.DB.GetAll(keys)
 	if err != nil {
@@ -117,7 +286,8 @@ func (o *Server) getNodes(ctx context.Context, keys storage.Keys) ([]*pb.Node, e
 
 }
 
-func (o *Server) populate(ctx context.Context, starting storage.Key, maxNodes, restrictedBandwidth, restrictedSpace int64) ([]*pb.Node, storage.Key, error) {
+func (o *Server) populate(ctx context.Context, starting storage.Key, maxNodes, restrictedBandwidth, restrictedSpace int64,
+	placement *Placement) ([]*pb.Node, storage.Key, error) {
 	limit := int(maxNodes * 2)
 	keys, err := o.cache.DB.List(starting, limit)
 	if err != nil {
@@ -142,6 +312,25 @@ func (o *Server) populate(ctx context.Context, starting storage.Key, maxNodes, r
 		if rest.GetFreeBandwidth() < restrictedBandwidth || rest.GetFreeDisk() < restrictedSpace {
 			continue
 		}
+		// the nodes are looked for to upload to
+		if rest.GetIngressFull() {
+			continue
+		}
+		if !o.whitelist.Listed(v.GetId()) {
+			continue
+		}
+		if !o.placements.Allows(placement, v) {
+			continue
+		}
+		if o.signed != nil {
+			ok, err := o.signed.IsSigned(v.GetId())
+			if err != nil {
+				return nil, nil, Error.Wrap(err)
+			}
+			if !ok {
+				continue
+			}
+		}
 
 		result = append(result, v)
 	}
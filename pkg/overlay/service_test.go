@@ -51,6 +51,10 @@ func (o *TestMockOverlay) BulkLookup(ctx context.Context, reqs *pb.LookupRequest
 	return &pb.LookupResponses{}, nil
 }
 
+func (o *TestMockOverlay) ListNodes(ctx context.Context, req *pb.ListNodesRequest) (*pb.ListNodesResponse, error) {
+	return &pb.ListNodesResponse{}, nil
+}
+
 func TestNewServerNilArgs(t *testing.T) {
 
 	server := NewServer(nil, nil, nil, nil)
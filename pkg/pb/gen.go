@@ -7,3 +7,10 @@ package pb
 //go:generate protoc --go_out=plugins=grpc:. overlay.proto
 //go:generate protoc --go_out=plugins=grpc:. pointerdb.proto
 //go:generate protoc --go_out=plugins=grpc:. piecestore.proto
+//go:generate protoc --go_out=plugins=grpc:. proxy.proto
+//go:generate protoc --go_out=plugins=grpc:. credentials.proto
+//go:generate protoc --go_out=plugins=grpc:. audit.proto
+//go:generate protoc --go_out=plugins=grpc:. gracefulexit.proto
+//go:generate protoc --go_out=plugins=grpc:. macaroon.proto
+//go:generate protoc --go_out=plugins=grpc:. certificates.proto
+//go:generate protoc --go_out=plugins=grpc:. notification.proto
@@ -19,10 +19,17 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type MetaStreamInfo struct {
-	NumberOfSegments     int64    `protobuf:"varint,1,opt,name=number_of_segments,json=numberOfSegments,proto3" json:"number_of_segments,omitempty"`
-	SegmentsSize         int64    `protobuf:"varint,2,opt,name=segments_size,json=segmentsSize,proto3" json:"segments_size,omitempty"`
-	LastSegmentSize      int64    `protobuf:"varint,3,opt,name=last_segment_size,json=lastSegmentSize,proto3" json:"last_segment_size,omitempty"`
-	Metadata             []byte   `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	NumberOfSegments int64  `protobuf:"varint,1,opt,name=number_of_segments,json=numberOfSegments,proto3" json:"number_of_segments,omitempty"`
+	SegmentsSize     int64  `protobuf:"varint,2,opt,name=segments_size,json=segmentsSize,proto3" json:"segments_size,omitempty"`
+	LastSegmentSize  int64  `protobuf:"varint,3,opt,name=last_segment_size,json=lastSegmentSize,proto3" json:"last_segment_size,omitempty"`
+	Metadata         []byte `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// upload_id and parts are set for streams stitched together from the
+	// parts of a multipart upload, whose segments stay where they were uploaded
+	UploadId string            `protobuf:"bytes,5,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	Parts    []*MetaStreamPart `protobuf:"bytes,6,rep,name=parts,proto3" json:"parts,omitempty"`
+	// checksum is the sha256 hash of the whole stream, verified by full
+	// downloads. It isn't set for stitched streams.
+	Checksum             []byte   `protobuf:"bytes,7,opt,name=checksum,proto3" json:"checksum,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -32,7 +39,7 @@ func (m *MetaStreamInfo) Reset()         { *m = MetaStreamInfo{} }
 func (m *MetaStreamInfo) String() string { return proto.CompactTextString(m) }
 func (*MetaStreamInfo) ProtoMessage()    {}
 func (*MetaStreamInfo) Descriptor() ([]byte, []int) {
-	return fileDescriptor_meta_5b06d82fd046d147, []int{0}
+	return fileDescriptor_meta_f17ec30f74c8df9e, []int{0}
 }
 func (m *MetaStreamInfo) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_MetaStreamInfo.Unmarshal(m, b)
@@ -80,23 +87,122 @@ func (m *MetaStreamInfo) GetMetadata() []byte {
 	return nil
 }
 
+func (m *MetaStreamInfo) GetUploadId() string {
+	if m != nil {
+		return m.UploadId
+	}
+	return ""
+}
+
+func (m *MetaStreamInfo) GetParts() []*MetaStreamPart {
+	if m != nil {
+		return m.Parts
+	}
+	return nil
+}
+
+func (m *MetaStreamInfo) GetChecksum() []byte {
+	if m != nil {
+		return m.Checksum
+	}
+	return nil
+}
+
+type MetaStreamPart struct {
+	Number               int32    `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	Etag                 string   `protobuf:"bytes,2,opt,name=etag,proto3" json:"etag,omitempty"`
+	NumberOfSegments     int64    `protobuf:"varint,3,opt,name=number_of_segments,json=numberOfSegments,proto3" json:"number_of_segments,omitempty"`
+	SegmentsSize         int64    `protobuf:"varint,4,opt,name=segments_size,json=segmentsSize,proto3" json:"segments_size,omitempty"`
+	LastSegmentSize      int64    `protobuf:"varint,5,opt,name=last_segment_size,json=lastSegmentSize,proto3" json:"last_segment_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MetaStreamPart) Reset()         { *m = MetaStreamPart{} }
+func (m *MetaStreamPart) String() string { return proto.CompactTextString(m) }
+func (*MetaStreamPart) ProtoMessage()    {}
+func (*MetaStreamPart) Descriptor() ([]byte, []int) {
+	return fileDescriptor_meta_f17ec30f74c8df9e, []int{1}
+}
+func (m *MetaStreamPart) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MetaStreamPart.Unmarshal(m, b)
+}
+func (m *MetaStreamPart) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MetaStreamPart.Marshal(b, m, deterministic)
+}
+func (dst *MetaStreamPart) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MetaStreamPart.Merge(dst, src)
+}
+func (m *MetaStreamPart) XXX_Size() int {
+	return xxx_messageInfo_MetaStreamPart.Size(m)
+}
+func (m *MetaStreamPart) XXX_DiscardUnknown() {
+	xxx_messageInfo_MetaStreamPart.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MetaStreamPart proto.InternalMessageInfo
+
+func (m *MetaStreamPart) GetNumber() int32 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *MetaStreamPart) GetEtag() string {
+	if m != nil {
+		return m.Etag
+	}
+	return ""
+}
+
+func (m *MetaStreamPart) GetNumberOfSegments() int64 {
+	if m != nil {
+		return m.NumberOfSegments
+	}
+	return 0
+}
+
+func (m *MetaStreamPart) GetSegmentsSize() int64 {
+	if m != nil {
+		return m.SegmentsSize
+	}
+	return 0
+}
+
+func (m *MetaStreamPart) GetLastSegmentSize() int64 {
+	if m != nil {
+		return m.LastSegmentSize
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*MetaStreamInfo)(nil), "streams.MetaStreamInfo")
+	proto.RegisterType((*MetaStreamPart)(nil), "streams.MetaStreamPart")
 }
 
-func init() { proto.RegisterFile("meta.proto", fileDescriptor_meta_5b06d82fd046d147) }
-
-var fileDescriptor_meta_5b06d82fd046d147 = []byte{
-	// 171 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0xca, 0x4d, 0x2d, 0x49,
-	0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0x62, 0x2f, 0x2e, 0x29, 0x4a, 0x4d, 0xcc, 0x2d, 0x56,
-	0x5a, 0xcd, 0xc8, 0xc5, 0xe7, 0x9b, 0x5a, 0x92, 0x18, 0x0c, 0xe6, 0x7b, 0xe6, 0xa5, 0xe5, 0x0b,
-	0xe9, 0x70, 0x09, 0xe5, 0x95, 0xe6, 0x26, 0xa5, 0x16, 0xc5, 0xe7, 0xa7, 0xc5, 0x17, 0xa7, 0xa6,
-	0xe7, 0xa6, 0xe6, 0x95, 0x14, 0x4b, 0x30, 0x2a, 0x30, 0x6a, 0x30, 0x07, 0x09, 0x40, 0x64, 0xfc,
-	0xd3, 0x82, 0xa1, 0xe2, 0x42, 0xca, 0x5c, 0xbc, 0x30, 0x35, 0xf1, 0xc5, 0x99, 0x55, 0xa9, 0x12,
-	0x4c, 0x60, 0x85, 0x3c, 0x30, 0xc1, 0xe0, 0xcc, 0xaa, 0x54, 0x21, 0x2d, 0x2e, 0xc1, 0x9c, 0xc4,
-	0xe2, 0x12, 0x98, 0x69, 0x10, 0x85, 0xcc, 0x60, 0x85, 0xfc, 0x20, 0x09, 0xa8, 0x69, 0x60, 0xb5,
-	0x52, 0x5c, 0x1c, 0x20, 0x87, 0xa6, 0x24, 0x96, 0x24, 0x4a, 0xb0, 0x28, 0x30, 0x6a, 0xf0, 0x04,
-	0xc1, 0xf9, 0x4e, 0x2c, 0x51, 0x4c, 0x05, 0x49, 0x49, 0x6c, 0x60, 0x3f, 0x18, 0x03, 0x02, 0x00,
-	0x00, 0xff, 0xff, 0xf0, 0xed, 0xd8, 0x89, 0xd1, 0x00, 0x00, 0x00,
+func init() { proto.RegisterFile("meta.proto", fileDescriptor_meta_f17ec30f74c8df9e) }
+
+var fileDescriptor_meta_f17ec30f74c8df9e = []byte{
+	// 274 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x8d, 0x51, 0xbb, 0x4e, 0xc3, 0x30,
+	0x14, 0x55, 0x9e, 0x6d, 0x2e, 0xe5, 0x75, 0x07, 0xb0, 0x60, 0xa9, 0xca, 0x52, 0x21, 0xc8, 0x00,
+	0x7f, 0xc0, 0xd6, 0x01, 0x81, 0x9c, 0x8d, 0x25, 0x72, 0x1a, 0xa7, 0x54, 0xd4, 0x71, 0x14, 0x3b,
+	0x0b, 0xbf, 0xc1, 0xe7, 0xf0, 0x73, 0x38, 0x76, 0x02, 0x0c, 0x20, 0x75, 0xf3, 0x79, 0xd8, 0xf7,
+	0x9e, 0x63, 0x00, 0xc1, 0x35, 0x4b, 0x9b, 0x56, 0x6a, 0x89, 0x13, 0xa5, 0x5b, 0xce, 0x84, 0x5a,
+	0x7c, 0xf8, 0x70, 0xf4, 0x68, 0xf8, 0xcc, 0xe2, 0x55, 0x5d, 0x49, 0xbc, 0x01, 0xac, 0x3b, 0x51,
+	0xf0, 0x36, 0x97, 0x55, 0xae, 0xf8, 0x46, 0xf0, 0x5a, 0x2b, 0xe2, 0xcd, 0xbd, 0x65, 0x40, 0x4f,
+	0x9c, 0xf2, 0x54, 0x65, 0x03, 0x8f, 0x57, 0x70, 0x38, 0x7a, 0x72, 0xb5, 0x7d, 0xe7, 0xc4, 0xb7,
+	0xc6, 0xd9, 0x48, 0x66, 0x86, 0xc3, 0x6b, 0x38, 0xdd, 0x31, 0xa5, 0xc7, 0xd7, 0x9c, 0x31, 0xb0,
+	0xc6, 0xe3, 0x5e, 0x18, 0x5e, 0xb3, 0xde, 0x0b, 0x98, 0xf6, 0x8b, 0x96, 0x4c, 0x33, 0x12, 0x1a,
+	0xcb, 0x8c, 0x7e, 0x63, 0xbc, 0x84, 0xa4, 0x6b, 0x76, 0x92, 0x95, 0xf9, 0xb6, 0x24, 0x91, 0x11,
+	0x13, 0x3a, 0x75, 0xc4, 0xaa, 0xc4, 0x5b, 0x88, 0x1a, 0xd6, 0x9a, 0x55, 0xe3, 0x79, 0xb0, 0x3c,
+	0xb8, 0x3b, 0x4f, 0x87, 0x8c, 0xe9, 0x4f, 0xbe, 0x67, 0xa3, 0x53, 0xe7, 0xea, 0xe7, 0xac, 0x5f,
+	0xf9, 0xfa, 0x4d, 0x75, 0x82, 0x4c, 0xdc, 0x9c, 0x11, 0x2f, 0x3e, 0xbd, 0xdf, 0xad, 0xf4, 0xb7,
+	0xf0, 0x0c, 0x62, 0x97, 0xdd, 0x36, 0x11, 0xd1, 0x01, 0x21, 0x42, 0x68, 0x8c, 0x1b, 0x1b, 0x3b,
+	0xa1, 0xf6, 0xfc, 0x4f, 0x83, 0xc1, 0xbe, 0x0d, 0x86, 0xfb, 0x36, 0x18, 0xfd, 0xd9, 0xe0, 0x43,
+	0xf8, 0xe2, 0x37, 0x45, 0x11, 0xdb, 0x9f, 0xbe, 0xff, 0x02, 0x0e, 0xa6, 0x22, 0x9d, 0xf7, 0x01,
+	0x00, 0x00,
 }
@@ -7,6 +7,7 @@ import proto "github.com/golang/protobuf/proto"
 import fmt "fmt"
 import math "math"
 import duration "github.com/golang/protobuf/ptypes/duration"
+import timestamp "github.com/golang/protobuf/ptypes/timestamp"
 
 import (
 	context "golang.org/x/net/context"
@@ -42,7 +43,7 @@ func (x NodeTransport) String() string {
 	return proto.EnumName(NodeTransport_name, int32(x))
 }
 func (NodeTransport) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{0}
+	return fileDescriptor_overlay_b85a984f53143705, []int{0}
 }
 
 // NodeType is an enum of possible node types
@@ -66,7 +67,7 @@ func (x NodeType) String() string {
 	return proto.EnumName(NodeType_name, int32(x))
 }
 func (NodeType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{1}
+	return fileDescriptor_overlay_b85a984f53143705, []int{1}
 }
 
 type Restriction_Operator int32
@@ -98,7 +99,7 @@ func (x Restriction_Operator) String() string {
 	return proto.EnumName(Restriction_Operator_name, int32(x))
 }
 func (Restriction_Operator) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{13, 0}
+	return fileDescriptor_overlay_b85a984f53143705, []int{20, 0}
 }
 
 type Restriction_Operand int32
@@ -121,7 +122,7 @@ func (x Restriction_Operand) String() string {
 	return proto.EnumName(Restriction_Operand_name, int32(x))
 }
 func (Restriction_Operand) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{13, 1}
+	return fileDescriptor_overlay_b85a984f53143705, []int{20, 1}
 }
 
 // LookupRequest is is request message for the lookup rpc call
@@ -136,7 +137,7 @@ func (m *LookupRequest) Reset()         { *m = LookupRequest{} }
 func (m *LookupRequest) String() string { return proto.CompactTextString(m) }
 func (*LookupRequest) ProtoMessage()    {}
 func (*LookupRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{0}
+	return fileDescriptor_overlay_b85a984f53143705, []int{0}
 }
 func (m *LookupRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LookupRequest.Unmarshal(m, b)
@@ -175,7 +176,7 @@ func (m *LookupResponse) Reset()         { *m = LookupResponse{} }
 func (m *LookupResponse) String() string { return proto.CompactTextString(m) }
 func (*LookupResponse) ProtoMessage()    {}
 func (*LookupResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{1}
+	return fileDescriptor_overlay_b85a984f53143705, []int{1}
 }
 func (m *LookupResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LookupResponse.Unmarshal(m, b)
@@ -214,7 +215,7 @@ func (m *LookupRequests) Reset()         { *m = LookupRequests{} }
 func (m *LookupRequests) String() string { return proto.CompactTextString(m) }
 func (*LookupRequests) ProtoMessage()    {}
 func (*LookupRequests) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{2}
+	return fileDescriptor_overlay_b85a984f53143705, []int{2}
 }
 func (m *LookupRequests) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LookupRequests.Unmarshal(m, b)
@@ -253,7 +254,7 @@ func (m *LookupResponses) Reset()         { *m = LookupResponses{} }
 func (m *LookupResponses) String() string { return proto.CompactTextString(m) }
 func (*LookupResponses) ProtoMessage()    {}
 func (*LookupResponses) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{3}
+	return fileDescriptor_overlay_b85a984f53143705, []int{3}
 }
 func (m *LookupResponses) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_LookupResponses.Unmarshal(m, b)
@@ -292,7 +293,7 @@ func (m *FindStorageNodesResponse) Reset()         { *m = FindStorageNodesRespon
 func (m *FindStorageNodesResponse) String() string { return proto.CompactTextString(m) }
 func (*FindStorageNodesResponse) ProtoMessage()    {}
 func (*FindStorageNodesResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{4}
+	return fileDescriptor_overlay_b85a984f53143705, []int{4}
 }
 func (m *FindStorageNodesResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FindStorageNodesResponse.Unmarshal(m, b)
@@ -321,19 +322,23 @@ func (m *FindStorageNodesResponse) GetNodes() []*Node {
 
 // FindStorageNodesRequest is is request message for the FindStorageNodes rpc call
 type FindStorageNodesRequest struct {
-	ObjectSize           int64              `protobuf:"varint,1,opt,name=objectSize,proto3" json:"objectSize,omitempty"`
-	ContractLength       *duration.Duration `protobuf:"bytes,2,opt,name=contractLength,proto3" json:"contractLength,omitempty"`
-	Opts                 *OverlayOptions    `protobuf:"bytes,3,opt,name=opts,proto3" json:"opts,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
-	XXX_unrecognized     []byte             `json:"-"`
-	XXX_sizecache        int32              `json:"-"`
+	ObjectSize     int64              `protobuf:"varint,1,opt,name=objectSize,proto3" json:"objectSize,omitempty"`
+	ContractLength *duration.Duration `protobuf:"bytes,2,opt,name=contractLength,proto3" json:"contractLength,omitempty"`
+	Opts           *OverlayOptions    `protobuf:"bytes,3,opt,name=opts,proto3" json:"opts,omitempty"`
+	// api_key and bucket identify the bucket the nodes are looked for, for
+	// its placement
+	ApiKey               []byte   `protobuf:"bytes,4,opt,name=api_key,json=apiKey,proto3" json:"api_key,omitempty"`
+	Bucket               string   `protobuf:"bytes,5,opt,name=bucket,proto3" json:"bucket,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *FindStorageNodesRequest) Reset()         { *m = FindStorageNodesRequest{} }
 func (m *FindStorageNodesRequest) String() string { return proto.CompactTextString(m) }
 func (*FindStorageNodesRequest) ProtoMessage()    {}
 func (*FindStorageNodesRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{5}
+	return fileDescriptor_overlay_b85a984f53143705, []int{5}
 }
 func (m *FindStorageNodesRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_FindStorageNodesRequest.Unmarshal(m, b)
@@ -374,6 +379,116 @@ func (m *FindStorageNodesRequest) GetOpts() *OverlayOptions {
 	return nil
 }
 
+func (m *FindStorageNodesRequest) GetApiKey() []byte {
+	if m != nil {
+		return m.ApiKey
+	}
+	return nil
+}
+
+func (m *FindStorageNodesRequest) GetBucket() string {
+	if m != nil {
+		return m.Bucket
+	}
+	return ""
+}
+
+// ListNodesRequest is request message for the ListNodes rpc call
+type ListNodesRequest struct {
+	// cursor continues the listing that returned it
+	Cursor               string   `protobuf:"bytes,1,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	Limit                int32    `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListNodesRequest) Reset()         { *m = ListNodesRequest{} }
+func (m *ListNodesRequest) String() string { return proto.CompactTextString(m) }
+func (*ListNodesRequest) ProtoMessage()    {}
+func (*ListNodesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_overlay_b85a984f53143705, []int{6}
+}
+func (m *ListNodesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListNodesRequest.Unmarshal(m, b)
+}
+func (m *ListNodesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListNodesRequest.Marshal(b, m, deterministic)
+}
+func (dst *ListNodesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListNodesRequest.Merge(dst, src)
+}
+func (m *ListNodesRequest) XXX_Size() int {
+	return xxx_messageInfo_ListNodesRequest.Size(m)
+}
+func (m *ListNodesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListNodesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListNodesRequest proto.InternalMessageInfo
+
+func (m *ListNodesRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
+func (m *ListNodesRequest) GetLimit() int32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+// ListNodesResponse is response message for the ListNodes rpc call
+type ListNodesResponse struct {
+	Nodes []*Node `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"`
+	// cursor is set if there are more nodes and continues the listing
+	Cursor               string   `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListNodesResponse) Reset()         { *m = ListNodesResponse{} }
+func (m *ListNodesResponse) String() string { return proto.CompactTextString(m) }
+func (*ListNodesResponse) ProtoMessage()    {}
+func (*ListNodesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_overlay_b85a984f53143705, []int{7}
+}
+func (m *ListNodesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListNodesResponse.Unmarshal(m, b)
+}
+func (m *ListNodesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListNodesResponse.Marshal(b, m, deterministic)
+}
+func (dst *ListNodesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListNodesResponse.Merge(dst, src)
+}
+func (m *ListNodesResponse) XXX_Size() int {
+	return xxx_messageInfo_ListNodesResponse.Size(m)
+}
+func (m *ListNodesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListNodesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListNodesResponse proto.InternalMessageInfo
+
+func (m *ListNodesResponse) GetNodes() []*Node {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+func (m *ListNodesResponse) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
 // NodeAddress contains the information needed to communicate with a node on the network
 type NodeAddress struct {
 	Transport            NodeTransport `protobuf:"varint,1,opt,name=transport,proto3,enum=overlay.NodeTransport" json:"transport,omitempty"`
@@ -387,7 +502,7 @@ func (m *NodeAddress) Reset()         { *m = NodeAddress{} }
 func (m *NodeAddress) String() string { return proto.CompactTextString(m) }
 func (*NodeAddress) ProtoMessage()    {}
 func (*NodeAddress) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{6}
+	return fileDescriptor_overlay_b85a984f53143705, []int{8}
 }
 func (m *NodeAddress) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeAddress.Unmarshal(m, b)
@@ -437,7 +552,7 @@ func (m *OverlayOptions) Reset()         { *m = OverlayOptions{} }
 func (m *OverlayOptions) String() string { return proto.CompactTextString(m) }
 func (*OverlayOptions) ProtoMessage()    {}
 func (*OverlayOptions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{7}
+	return fileDescriptor_overlay_b85a984f53143705, []int{9}
 }
 func (m *OverlayOptions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_OverlayOptions.Unmarshal(m, b)
@@ -503,7 +618,7 @@ func (m *NodeRep) Reset()         { *m = NodeRep{} }
 func (m *NodeRep) String() string { return proto.CompactTextString(m) }
 func (*NodeRep) ProtoMessage()    {}
 func (*NodeRep) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{8}
+	return fileDescriptor_overlay_b85a984f53143705, []int{10}
 }
 func (m *NodeRep) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeRep.Unmarshal(m, b)
@@ -523,10 +638,12 @@ func (m *NodeRep) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_NodeRep proto.InternalMessageInfo
 
-//  NodeRestrictions contains all relevant data about a nodes ability to store data
+// NodeRestrictions contains all relevant data about a nodes ability to store data
 type NodeRestrictions struct {
 	FreeBandwidth        int64    `protobuf:"varint,1,opt,name=freeBandwidth,proto3" json:"freeBandwidth,omitempty"`
 	FreeDisk             int64    `protobuf:"varint,2,opt,name=freeDisk,proto3" json:"freeDisk,omitempty"`
+	IngressFull          bool     `protobuf:"varint,3,opt,name=ingressFull,proto3" json:"ingressFull,omitempty"`
+	EgressFull           bool     `protobuf:"varint,4,opt,name=egressFull,proto3" json:"egressFull,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -536,7 +653,7 @@ func (m *NodeRestrictions) Reset()         { *m = NodeRestrictions{} }
 func (m *NodeRestrictions) String() string { return proto.CompactTextString(m) }
 func (*NodeRestrictions) ProtoMessage()    {}
 func (*NodeRestrictions) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{9}
+	return fileDescriptor_overlay_b85a984f53143705, []int{11}
 }
 func (m *NodeRestrictions) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeRestrictions.Unmarshal(m, b)
@@ -570,12 +687,27 @@ func (m *NodeRestrictions) GetFreeDisk() int64 {
 	return 0
 }
 
+func (m *NodeRestrictions) GetIngressFull() bool {
+	if m != nil {
+		return m.IngressFull
+	}
+	return false
+}
+
+func (m *NodeRestrictions) GetEgressFull() bool {
+	if m != nil {
+		return m.EgressFull
+	}
+	return false
+}
+
 // Node represents a node in the overlay network
 type Node struct {
 	Id                   string            `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Address              *NodeAddress      `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
 	Type                 NodeType          `protobuf:"varint,3,opt,name=type,proto3,enum=overlay.NodeType" json:"type,omitempty"`
 	Restrictions         *NodeRestrictions `protobuf:"bytes,4,opt,name=restrictions,proto3" json:"restrictions,omitempty"`
+	Operator             *NodeOperator     `protobuf:"bytes,5,opt,name=operator,proto3" json:"operator,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -585,7 +717,7 @@ func (m *Node) Reset()         { *m = Node{} }
 func (m *Node) String() string { return proto.CompactTextString(m) }
 func (*Node) ProtoMessage()    {}
 func (*Node) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{10}
+	return fileDescriptor_overlay_b85a984f53143705, []int{12}
 }
 func (m *Node) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Node.Unmarshal(m, b)
@@ -633,6 +765,264 @@ func (m *Node) GetRestrictions() *NodeRestrictions {
 	return nil
 }
 
+func (m *Node) GetOperator() *NodeOperator {
+	if m != nil {
+		return m.Operator
+	}
+	return nil
+}
+
+// NodeOperator is the contact and payout information of the operator of a
+// storage node
+type NodeOperator struct {
+	Email  string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Wallet string `protobuf:"bytes,2,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	// wallet_signature optionally proves that the wallet was chosen by the
+	// owner of the node's certificate authority, who signed it
+	WalletSignature []byte `protobuf:"bytes,3,opt,name=wallet_signature,json=walletSignature,proto3" json:"wallet_signature,omitempty"`
+	// country is the ISO 3166-1 alpha-2 code of the country the node is in
+	Country              string   `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *NodeOperator) Reset()         { *m = NodeOperator{} }
+func (m *NodeOperator) String() string { return proto.CompactTextString(m) }
+func (*NodeOperator) ProtoMessage()    {}
+func (*NodeOperator) Descriptor() ([]byte, []int) {
+	return fileDescriptor_overlay_b85a984f53143705, []int{13}
+}
+func (m *NodeOperator) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_NodeOperator.Unmarshal(m, b)
+}
+func (m *NodeOperator) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeOperator.Marshal(b, m, deterministic)
+}
+func (dst *NodeOperator) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeOperator.Merge(dst, src)
+}
+func (m *NodeOperator) XXX_Size() int {
+	return xxx_messageInfo_NodeOperator.Size(m)
+}
+func (m *NodeOperator) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeOperator.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeOperator proto.InternalMessageInfo
+
+func (m *NodeOperator) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *NodeOperator) GetWallet() string {
+	if m != nil {
+		return m.Wallet
+	}
+	return ""
+}
+
+func (m *NodeOperator) GetWalletSignature() []byte {
+	if m != nil {
+		return m.WalletSignature
+	}
+	return nil
+}
+
+func (m *NodeOperator) GetCountry() string {
+	if m != nil {
+		return m.Country
+	}
+	return ""
+}
+
+type UpdateOperatorRequest struct {
+	Operator             *NodeOperator `protobuf:"bytes,1,opt,name=operator,proto3" json:"operator,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
+}
+
+func (m *UpdateOperatorRequest) Reset()         { *m = UpdateOperatorRequest{} }
+func (m *UpdateOperatorRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateOperatorRequest) ProtoMessage()    {}
+func (*UpdateOperatorRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_overlay_b85a984f53143705, []int{14}
+}
+func (m *UpdateOperatorRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateOperatorRequest.Unmarshal(m, b)
+}
+func (m *UpdateOperatorRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateOperatorRequest.Marshal(b, m, deterministic)
+}
+func (dst *UpdateOperatorRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateOperatorRequest.Merge(dst, src)
+}
+func (m *UpdateOperatorRequest) XXX_Size() int {
+	return xxx_messageInfo_UpdateOperatorRequest.Size(m)
+}
+func (m *UpdateOperatorRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateOperatorRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateOperatorRequest proto.InternalMessageInfo
+
+func (m *UpdateOperatorRequest) GetOperator() *NodeOperator {
+	if m != nil {
+		return m.Operator
+	}
+	return nil
+}
+
+type UpdateOperatorResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *UpdateOperatorResponse) Reset()         { *m = UpdateOperatorResponse{} }
+func (m *UpdateOperatorResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateOperatorResponse) ProtoMessage()    {}
+func (*UpdateOperatorResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_overlay_b85a984f53143705, []int{15}
+}
+func (m *UpdateOperatorResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_UpdateOperatorResponse.Unmarshal(m, b)
+}
+func (m *UpdateOperatorResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_UpdateOperatorResponse.Marshal(b, m, deterministic)
+}
+func (dst *UpdateOperatorResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_UpdateOperatorResponse.Merge(dst, src)
+}
+func (m *UpdateOperatorResponse) XXX_Size() int {
+	return xxx_messageInfo_UpdateOperatorResponse.Size(m)
+}
+func (m *UpdateOperatorResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_UpdateOperatorResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_UpdateOperatorResponse proto.InternalMessageInfo
+
+// EchoRequest is a request message for the Echo rpc call
+type EchoRequest struct {
+	// address is the external address of the calling storage node. if it
+	// has no host, the host the request came from is dialed
+	Address              string   `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *EchoRequest) Reset()         { *m = EchoRequest{} }
+func (m *EchoRequest) String() string { return proto.CompactTextString(m) }
+func (*EchoRequest) ProtoMessage()    {}
+func (*EchoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_overlay_b85a984f53143705, []int{16}
+}
+func (m *EchoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EchoRequest.Unmarshal(m, b)
+}
+func (m *EchoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EchoRequest.Marshal(b, m, deterministic)
+}
+func (dst *EchoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EchoRequest.Merge(dst, src)
+}
+func (m *EchoRequest) XXX_Size() int {
+	return xxx_messageInfo_EchoRequest.Size(m)
+}
+func (m *EchoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_EchoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EchoRequest proto.InternalMessageInfo
+
+func (m *EchoRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+// EchoResponse is a response message for the Echo rpc call
+type EchoResponse struct {
+	Reachable bool `protobuf:"varint,1,opt,name=reachable,proto3" json:"reachable,omitempty"`
+	// error is why the node couldn't be dialed, if it isn't reachable
+	Error string `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	// dialed_address is the address the node was dialed at
+	DialedAddress string `protobuf:"bytes,3,opt,name=dialed_address,json=dialedAddress,proto3" json:"dialed_address,omitempty"`
+	// seen_address is the address the request came from
+	SeenAddress string `protobuf:"bytes,4,opt,name=seen_address,json=seenAddress,proto3" json:"seen_address,omitempty"`
+	// time is the time of the satellite when it answered
+	Time                 *timestamp.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *EchoResponse) Reset()         { *m = EchoResponse{} }
+func (m *EchoResponse) String() string { return proto.CompactTextString(m) }
+func (*EchoResponse) ProtoMessage()    {}
+func (*EchoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_overlay_b85a984f53143705, []int{17}
+}
+func (m *EchoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_EchoResponse.Unmarshal(m, b)
+}
+func (m *EchoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_EchoResponse.Marshal(b, m, deterministic)
+}
+func (dst *EchoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_EchoResponse.Merge(dst, src)
+}
+func (m *EchoResponse) XXX_Size() int {
+	return xxx_messageInfo_EchoResponse.Size(m)
+}
+func (m *EchoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_EchoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_EchoResponse proto.InternalMessageInfo
+
+func (m *EchoResponse) GetReachable() bool {
+	if m != nil {
+		return m.Reachable
+	}
+	return false
+}
+
+func (m *EchoResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+func (m *EchoResponse) GetDialedAddress() string {
+	if m != nil {
+		return m.DialedAddress
+	}
+	return ""
+}
+
+func (m *EchoResponse) GetSeenAddress() string {
+	if m != nil {
+		return m.SeenAddress
+	}
+	return ""
+}
+
+func (m *EchoResponse) GetTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
 type QueryRequest struct {
 	Sender               *Node    `protobuf:"bytes,1,opt,name=sender,proto3" json:"sender,omitempty"`
 	Target               *Node    `protobuf:"bytes,2,opt,name=target,proto3" json:"target,omitempty"`
@@ -646,7 +1036,7 @@ func (m *QueryRequest) Reset()         { *m = QueryRequest{} }
 func (m *QueryRequest) String() string { return proto.CompactTextString(m) }
 func (*QueryRequest) ProtoMessage()    {}
 func (*QueryRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{11}
+	return fileDescriptor_overlay_b85a984f53143705, []int{18}
 }
 func (m *QueryRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryRequest.Unmarshal(m, b)
@@ -699,7 +1089,7 @@ func (m *QueryResponse) Reset()         { *m = QueryResponse{} }
 func (m *QueryResponse) String() string { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()    {}
 func (*QueryResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{12}
+	return fileDescriptor_overlay_b85a984f53143705, []int{19}
 }
 func (m *QueryResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_QueryResponse.Unmarshal(m, b)
@@ -746,7 +1136,7 @@ func (m *Restriction) Reset()         { *m = Restriction{} }
 func (m *Restriction) String() string { return proto.CompactTextString(m) }
 func (*Restriction) ProtoMessage()    {}
 func (*Restriction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_overlay_ce394371f276e940, []int{13}
+	return fileDescriptor_overlay_b85a984f53143705, []int{20}
 }
 func (m *Restriction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Restriction.Unmarshal(m, b)
@@ -794,11 +1184,18 @@ func init() {
 	proto.RegisterType((*LookupResponses)(nil), "overlay.LookupResponses")
 	proto.RegisterType((*FindStorageNodesResponse)(nil), "overlay.FindStorageNodesResponse")
 	proto.RegisterType((*FindStorageNodesRequest)(nil), "overlay.FindStorageNodesRequest")
+	proto.RegisterType((*ListNodesRequest)(nil), "overlay.ListNodesRequest")
+	proto.RegisterType((*ListNodesResponse)(nil), "overlay.ListNodesResponse")
 	proto.RegisterType((*NodeAddress)(nil), "overlay.NodeAddress")
 	proto.RegisterType((*OverlayOptions)(nil), "overlay.OverlayOptions")
 	proto.RegisterType((*NodeRep)(nil), "overlay.NodeRep")
 	proto.RegisterType((*NodeRestrictions)(nil), "overlay.NodeRestrictions")
 	proto.RegisterType((*Node)(nil), "overlay.Node")
+	proto.RegisterType((*NodeOperator)(nil), "overlay.NodeOperator")
+	proto.RegisterType((*UpdateOperatorRequest)(nil), "overlay.UpdateOperatorRequest")
+	proto.RegisterType((*UpdateOperatorResponse)(nil), "overlay.UpdateOperatorResponse")
+	proto.RegisterType((*EchoRequest)(nil), "overlay.EchoRequest")
+	proto.RegisterType((*EchoResponse)(nil), "overlay.EchoResponse")
 	proto.RegisterType((*QueryRequest)(nil), "overlay.QueryRequest")
 	proto.RegisterType((*QueryResponse)(nil), "overlay.QueryResponse")
 	proto.RegisterType((*Restriction)(nil), "overlay.Restriction")
@@ -826,6 +1223,13 @@ type OverlayClient interface {
 	BulkLookup(ctx context.Context, in *LookupRequests, opts ...grpc.CallOption) (*LookupResponses, error)
 	// FindStorageNodes finds a list of nodes in the network that meet the specified request parameters
 	FindStorageNodes(ctx context.Context, in *FindStorageNodesRequest, opts ...grpc.CallOption) (*FindStorageNodesResponse, error)
+	// ListNodes lists the nodes in the overlay cache by id
+	ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error)
+	// UpdateOperator updates the operator of the calling storage node
+	UpdateOperator(ctx context.Context, in *UpdateOperatorRequest, opts ...grpc.CallOption) (*UpdateOperatorResponse, error)
+	// Echo dials the calling storage node back, to check that it's reachable
+	// from outside
+	Echo(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (*EchoResponse, error)
 }
 
 type overlayClient struct {
@@ -863,6 +1267,33 @@ func (c *overlayClient) FindStorageNodes(ctx context.Context, in *FindStorageNod
 	return out, nil
 }
 
+func (c *overlayClient) ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error) {
+	out := new(ListNodesResponse)
+	err := c.cc.Invoke(ctx, "/overlay.Overlay/ListNodes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *overlayClient) UpdateOperator(ctx context.Context, in *UpdateOperatorRequest, opts ...grpc.CallOption) (*UpdateOperatorResponse, error) {
+	out := new(UpdateOperatorResponse)
+	err := c.cc.Invoke(ctx, "/overlay.Overlay/UpdateOperator", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *overlayClient) Echo(ctx context.Context, in *EchoRequest, opts ...grpc.CallOption) (*EchoResponse, error) {
+	out := new(EchoResponse)
+	err := c.cc.Invoke(ctx, "/overlay.Overlay/Echo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OverlayServer is the server API for Overlay service.
 type OverlayServer interface {
 	// Lookup finds a nodes address from the network
@@ -871,6 +1302,13 @@ type OverlayServer interface {
 	BulkLookup(context.Context, *LookupRequests) (*LookupResponses, error)
 	// FindStorageNodes finds a list of nodes in the network that meet the specified request parameters
 	FindStorageNodes(context.Context, *FindStorageNodesRequest) (*FindStorageNodesResponse, error)
+	// ListNodes lists the nodes in the overlay cache by id
+	ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error)
+	// UpdateOperator updates the operator of the calling storage node
+	UpdateOperator(context.Context, *UpdateOperatorRequest) (*UpdateOperatorResponse, error)
+	// Echo dials the calling storage node back, to check that it's reachable
+	// from outside
+	Echo(context.Context, *EchoRequest) (*EchoResponse, error)
 }
 
 func RegisterOverlayServer(s *grpc.Server, srv OverlayServer) {
@@ -931,6 +1369,60 @@ func _Overlay_FindStorageNodes_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Overlay_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OverlayServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/overlay.Overlay/ListNodes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OverlayServer).ListNodes(ctx, req.(*ListNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Overlay_UpdateOperator_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOperatorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OverlayServer).UpdateOperator(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/overlay.Overlay/UpdateOperator",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OverlayServer).UpdateOperator(ctx, req.(*UpdateOperatorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Overlay_Echo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EchoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OverlayServer).Echo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/overlay.Overlay/Echo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OverlayServer).Echo(ctx, req.(*EchoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Overlay_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "overlay.Overlay",
 	HandlerType: (*OverlayServer)(nil),
@@ -947,6 +1439,18 @@ var _Overlay_serviceDesc = grpc.ServiceDesc{
 			MethodName: "FindStorageNodes",
 			Handler:    _Overlay_FindStorageNodes_Handler,
 		},
+		{
+			MethodName: "ListNodes",
+			Handler:    _Overlay_ListNodes_Handler,
+		},
+		{
+			MethodName: "UpdateOperator",
+			Handler:    _Overlay_UpdateOperator_Handler,
+		},
+		{
+			MethodName: "Echo",
+			Handler:    _Overlay_Echo_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "overlay.proto",
@@ -1016,61 +1520,82 @@ var _Nodes_serviceDesc = grpc.ServiceDesc{
 	Metadata: "overlay.proto",
 }
 
-func init() { proto.RegisterFile("overlay.proto", fileDescriptor_overlay_ce394371f276e940) }
-
-var fileDescriptor_overlay_ce394371f276e940 = []byte{
-	// 840 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x6d, 0x6f, 0xe3, 0x44,
-	0x10, 0xae, 0xf3, 0x9e, 0x49, 0x13, 0x7c, 0xa3, 0xa3, 0x35, 0x11, 0x9c, 0x7a, 0x0b, 0x27, 0x8e,
-	0x22, 0xe5, 0xa4, 0xdc, 0xa9, 0x52, 0x25, 0x50, 0xd5, 0xd2, 0x52, 0x9d, 0x08, 0x2d, 0xb7, 0xb5,
-	0x84, 0x84, 0xc4, 0x07, 0x27, 0xde, 0xcb, 0x99, 0x26, 0x5e, 0xb3, 0xbb, 0x3e, 0x08, 0xff, 0x08,
-	0x89, 0xdf, 0xc4, 0xef, 0xe0, 0x13, 0x42, 0xde, 0x5d, 0x3b, 0xb1, 0xdb, 0x9c, 0xb8, 0x4f, 0xf6,
-	0xcc, 0x3c, 0xf3, 0xec, 0xbc, 0x43, 0x9f, 0xbf, 0x65, 0x62, 0x11, 0xac, 0x46, 0x89, 0xe0, 0x8a,
-	0x63, 0xdb, 0x8a, 0xc3, 0x47, 0x73, 0xce, 0xe7, 0x0b, 0xf6, 0x4c, 0xab, 0xa7, 0xe9, 0xeb, 0x67,
-	0x61, 0x2a, 0x02, 0x15, 0xf1, 0xd8, 0x00, 0xc9, 0xe7, 0xd0, 0x9f, 0x70, 0x7e, 0x9b, 0x26, 0x94,
-	0xfd, 0x9a, 0x32, 0xa9, 0x70, 0x0f, 0x5a, 0x31, 0x0f, 0xd9, 0xcb, 0x73, 0xcf, 0x39, 0x70, 0x9e,
-	0x76, 0xa9, 0x95, 0xc8, 0x73, 0x18, 0xe4, 0x40, 0x99, 0xf0, 0x58, 0x32, 0x7c, 0x0c, 0x8d, 0xcc,
-	0xa6, 0x71, 0xbd, 0x71, 0x7f, 0x94, 0x47, 0x70, 0xc5, 0x43, 0x46, 0xb5, 0x89, 0x5c, 0xad, 0x9d,
-	0x34, 0xbb, 0xc4, 0xaf, 0xa0, 0xbf, 0xd0, 0x1a, 0x61, 0x34, 0x9e, 0x73, 0x50, 0x7f, 0xda, 0x1b,
-	0xef, 0x15, 0xde, 0x25, 0x3c, 0x2d, 0x83, 0x09, 0x85, 0x0f, 0xca, 0x41, 0x48, 0x3c, 0x81, 0x41,
-	0x8e, 0x31, 0x2a, 0xcb, 0xb8, 0x7f, 0x87, 0xd1, 0x98, 0x69, 0x05, 0x4e, 0x4e, 0xc0, 0xfb, 0x36,
-	0x8a, 0xc3, 0x1b, 0xc5, 0x45, 0x30, 0x67, 0x59, 0xf0, 0xb2, 0x48, 0xf1, 0x53, 0x68, 0x66, 0x79,
-	0x48, 0xcb, 0x59, 0xc9, 0xd1, 0xd8, 0xc8, 0x9f, 0x0e, 0xec, 0xdf, 0x65, 0x30, 0xd5, 0x7c, 0x04,
-	0xc0, 0xa7, 0xbf, 0xb0, 0x99, 0xba, 0x89, 0xfe, 0x30, 0x95, 0xaa, 0xd3, 0x0d, 0x0d, 0x9e, 0xc2,
-	0x60, 0xc6, 0x63, 0x25, 0x82, 0x99, 0x9a, 0xb0, 0x78, 0xae, 0xde, 0x78, 0x35, 0x5d, 0xcd, 0x8f,
-	0x46, 0xa6, 0x6f, 0xa3, 0xbc, 0x6f, 0xa3, 0x73, 0xdb, 0x37, 0x5a, 0x71, 0xc0, 0x2f, 0xa1, 0xc1,
-	0x13, 0x25, 0xbd, 0xba, 0x76, 0x5c, 0xa7, 0x7d, 0x6d, 0xbe, 0xd7, 0x49, 0xe6, 0x25, 0xa9, 0x06,
-	0x91, 0x9f, 0xa1, 0x97, 0xc5, 0x77, 0x1a, 0x86, 0x82, 0x49, 0x89, 0x2f, 0xa0, 0xab, 0x44, 0x10,
-	0xcb, 0x84, 0x0b, 0xa5, 0xa3, 0x1b, 0x6c, 0x74, 0x22, 0x03, 0xfa, 0xb9, 0x95, 0xae, 0x81, 0xe8,
-	0x41, 0x3b, 0x30, 0x04, 0x3a, 0xda, 0x2e, 0xcd, 0x45, 0xf2, 0xaf, 0x03, 0x83, 0xf2, 0xbb, 0x78,
-	0x0c, 0xb0, 0x0c, 0x7e, 0x9f, 0x04, 0x8a, 0xc5, 0xb3, 0x95, 0x9d, 0x95, 0x77, 0x64, 0xb7, 0x01,
-	0xc6, 0x23, 0xe8, 0x2f, 0xa3, 0x98, 0xb2, 0x24, 0x55, 0xda, 0x68, 0x6b, 0xe3, 0x96, 0xbb, 0xc0,
-	0x12, 0x5a, 0x86, 0x21, 0x81, 0xdd, 0x65, 0x14, 0xdf, 0x24, 0x8c, 0x85, 0xdf, 0x4d, 0x13, 0x53,
-	0x99, 0x3a, 0x2d, 0xe9, 0xb2, 0x31, 0x0f, 0x96, 0x3c, 0x8d, 0x95, 0xd7, 0xd0, 0x56, 0x2b, 0xe1,
-	0xd7, 0xb0, 0x2b, 0x98, 0x54, 0x22, 0x9a, 0xe9, 0xf0, 0xbd, 0xa6, 0x0d, 0xb8, 0xfc, 0xe4, 0x1a,
-	0x40, 0x4b, 0x70, 0xd2, 0x85, 0xb6, 0x0d, 0x8a, 0xf8, 0xe0, 0x56, 0xc1, 0xf8, 0x19, 0xf4, 0x5f,
-	0x0b, 0xc6, 0xce, 0x82, 0x38, 0xfc, 0x2d, 0x0a, 0xd5, 0x1b, 0x3b, 0x11, 0x65, 0x25, 0x0e, 0xa1,
-	0x93, 0x29, 0xce, 0x23, 0x79, 0xab, 0x53, 0xae, 0xd3, 0x42, 0x26, 0x7f, 0x39, 0xd0, 0xc8, 0x68,
-	0x71, 0x00, 0xb5, 0x28, 0xb4, 0x3b, 0x5a, 0x8b, 0x42, 0x1c, 0x95, 0x9b, 0xd2, 0x1b, 0x3f, 0x2c,
-	0xc5, 0x6c, 0x3b, 0x5e, 0xb4, 0x0a, 0x9f, 0x40, 0x43, 0xad, 0x12, 0xa6, 0x8b, 0x33, 0x18, 0x3f,
-	0x28, 0x77, 0x7d, 0x95, 0x30, 0xaa, 0xcd, 0x77, 0xea, 0xd1, 0x78, 0xbf, 0x7a, 0x08, 0xd8, 0x7d,
-	0x95, 0x32, 0xb1, 0xca, 0xf7, 0xe1, 0x09, 0xb4, 0x24, 0x8b, 0x43, 0x26, 0xee, 0xbf, 0x1a, 0xd6,
-	0x98, 0xc1, 0x54, 0x20, 0xe6, 0x4c, 0xd9, 0x5c, 0xaa, 0x30, 0x63, 0xc4, 0x87, 0xd0, 0x5c, 0x44,
-	0xcb, 0x48, 0xd9, 0x0e, 0x1b, 0x81, 0x04, 0xd0, 0xb7, 0x6f, 0xda, 0x2d, 0xfe, 0x9f, 0x8f, 0x7e,
-	0x01, 0x9d, 0xe2, 0x86, 0xd4, 0xee, 0xdb, 0xf7, 0xc2, 0x4c, 0xfe, 0x71, 0xa0, 0xb7, 0x91, 0x35,
-	0x1e, 0x43, 0x87, 0x27, 0x4c, 0x04, 0x8a, 0x0b, 0xbb, 0x46, 0x9f, 0x14, 0xae, 0x1b, 0xb8, 0xd1,
-	0xb5, 0x05, 0xd1, 0x02, 0x8e, 0x47, 0xd0, 0xd6, 0xff, 0x71, 0xa8, 0x73, 0x1d, 0x8c, 0x3f, 0xde,
-	0xee, 0x19, 0x87, 0x34, 0x07, 0x67, 0xb9, 0xbf, 0x0d, 0x16, 0x29, 0xcb, 0x73, 0xd7, 0x02, 0x79,
-	0x01, 0x9d, 0xfc, 0x0d, 0x6c, 0x41, 0x6d, 0xe2, 0xbb, 0x3b, 0xd9, 0xf7, 0xe2, 0x95, 0xeb, 0x64,
-	0xdf, 0x4b, 0xdf, 0xad, 0x61, 0x1b, 0xea, 0x13, 0xff, 0xc2, 0xad, 0x67, 0x3f, 0x97, 0xfe, 0x85,
-	0xdb, 0x20, 0x87, 0xd0, 0xb6, 0xfc, 0xf8, 0xa0, 0x32, 0xa1, 0xee, 0x0e, 0xee, 0xae, 0xc7, 0xd1,
-	0x75, 0x0e, 0x3d, 0xe8, 0x97, 0x0e, 0x43, 0xc6, 0xe2, 0x7f, 0xf3, 0x83, 0xbb, 0x73, 0x48, 0xa0,
-	0x93, 0x0f, 0x0f, 0x76, 0xa1, 0x79, 0x7a, 0xfe, 0xfd, 0xcb, 0x2b, 0x77, 0x07, 0x7b, 0xd0, 0xbe,
-	0xf1, 0xaf, 0xe9, 0xe9, 0xe5, 0x85, 0xeb, 0x8c, 0xff, 0x76, 0xa0, 0x6d, 0x0f, 0x04, 0x1e, 0x43,
-	0xcb, 0x9c, 0x66, 0xdc, 0x72, 0xfd, 0x87, 0xdb, 0x6e, 0x38, 0x9e, 0x00, 0x9c, 0xa5, 0x8b, 0x5b,
-	0xeb, 0xbe, 0x7f, 0xbf, 0xbb, 0x1c, 0x7a, 0x5b, 0xfc, 0x25, 0xfe, 0x08, 0x6e, 0xf5, 0x64, 0xe3,
-	0x41, 0x81, 0xde, 0x72, 0xcd, 0x87, 0x8f, 0xdf, 0x81, 0x30, 0xcc, 0xe3, 0x13, 0x68, 0x1a, 0xb6,
-	0x23, 0x68, 0xea, 0x29, 0xc4, 0x0f, 0x0b, 0xa7, 0xcd, 0x4d, 0x18, 0xee, 0x55, 0xd5, 0x86, 0xe0,
-	0xac, 0xf1, 0x53, 0x2d, 0x99, 0x4e, 0x5b, 0xfa, 0x32, 0x3e, 0xff, 0x2f, 0x00, 0x00, 0xff, 0xff,
-	0xd3, 0xc6, 0x96, 0x47, 0xd7, 0x07, 0x00, 0x00,
+func init() { proto.RegisterFile("overlay.proto", fileDescriptor_overlay_b85a984f53143705) }
+
+var fileDescriptor_overlay_b85a984f53143705 = []byte{
+	// 1181 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x95, 0x56, 0xdb, 0x6e, 0xdb, 0x46,
+	0x10, 0x0d, 0x75, 0xd7, 0x48, 0x62, 0xe8, 0x45, 0x6c, 0xab, 0x44, 0xea, 0xd8, 0x6c, 0x83, 0x34,
+	0x2e, 0xa0, 0xa0, 0x72, 0x10, 0xa0, 0x40, 0x0b, 0xd7, 0xae, 0x1d, 0x23, 0x8d, 0x62, 0xc7, 0x6b,
+	0x15, 0x05, 0x02, 0x04, 0x06, 0x25, 0x6e, 0x64, 0xd6, 0x14, 0xc9, 0xf2, 0x92, 0x54, 0x7d, 0xeb,
+	0x4f, 0xf4, 0x23, 0xfa, 0x0d, 0xfd, 0x92, 0xbe, 0xf4, 0x37, 0xfa, 0x54, 0x74, 0x6f, 0xbc, 0xca,
+	0x0a, 0x92, 0x27, 0x72, 0x66, 0xce, 0xcc, 0xee, 0x5c, 0xf6, 0xec, 0x42, 0xcf, 0x7b, 0x4b, 0x02,
+	0xc7, 0x5c, 0x0c, 0xfc, 0xc0, 0x8b, 0x3c, 0xd4, 0x94, 0xa2, 0xbe, 0x35, 0xf3, 0xbc, 0x99, 0x43,
+	0x1e, 0x71, 0xf5, 0x24, 0x7e, 0xf3, 0xc8, 0x8a, 0x03, 0x33, 0xb2, 0x3d, 0x57, 0x00, 0xf5, 0x7b,
+	0x65, 0x7b, 0x64, 0xcf, 0x49, 0x18, 0x99, 0x73, 0x5f, 0x00, 0x8c, 0x07, 0xd0, 0x1b, 0x79, 0xde,
+	0x75, 0xec, 0x63, 0xf2, 0x4b, 0x4c, 0x2d, 0x68, 0x03, 0x1a, 0xae, 0x67, 0x91, 0x67, 0x47, 0x7d,
+	0x65, 0x5b, 0xf9, 0xa2, 0x8d, 0xa5, 0x64, 0xec, 0x81, 0x9a, 0x00, 0x43, 0xdf, 0x73, 0x43, 0x82,
+	0x76, 0xa0, 0xc6, 0x6c, 0x1c, 0xd7, 0x19, 0xf6, 0x06, 0xc9, 0x16, 0x4f, 0xa9, 0x12, 0x73, 0x93,
+	0x71, 0x9a, 0x39, 0xf1, 0xe8, 0x21, 0xfa, 0x06, 0x7a, 0x0e, 0xd7, 0x04, 0x42, 0x43, 0xbd, 0xab,
+	0xd4, 0x7b, 0x23, 0xf5, 0x2e, 0xe0, 0x71, 0x11, 0x6c, 0x60, 0xb8, 0x5d, 0xdc, 0x44, 0x88, 0xf6,
+	0x41, 0x4d, 0x30, 0x42, 0x25, 0x23, 0x6e, 0x2e, 0x45, 0x14, 0x66, 0x5c, 0x82, 0x1b, 0xfb, 0xd0,
+	0x7f, 0x6a, 0xbb, 0xd6, 0x45, 0xe4, 0x05, 0xe6, 0x8c, 0xb0, 0xcd, 0x87, 0x69, 0x8a, 0x9f, 0x41,
+	0x9d, 0xe5, 0x11, 0xca, 0x98, 0xa5, 0x1c, 0x85, 0xcd, 0xf8, 0x5b, 0x81, 0xcd, 0xe5, 0x08, 0xa2,
+	0x9a, 0x5b, 0x00, 0xde, 0xe4, 0x67, 0x32, 0x8d, 0x2e, 0xec, 0xdf, 0x44, 0xa5, 0xaa, 0x38, 0xa7,
+	0x41, 0x07, 0xa0, 0x4e, 0x3d, 0x37, 0x0a, 0xcc, 0x69, 0x34, 0x22, 0xee, 0x2c, 0xba, 0xea, 0x57,
+	0x78, 0x35, 0x3f, 0x19, 0x88, 0xc6, 0x0d, 0x92, 0xc6, 0x0d, 0x8e, 0x64, 0x63, 0x71, 0xc9, 0x01,
+	0x7d, 0x09, 0x35, 0xcf, 0x8f, 0xc2, 0x7e, 0x95, 0x3b, 0x66, 0x69, 0x9f, 0x89, 0xef, 0x99, 0xcf,
+	0xbc, 0x42, 0xcc, 0x41, 0x68, 0x13, 0x9a, 0xa6, 0x6f, 0x5f, 0x5e, 0x93, 0x45, 0xbf, 0x46, 0xf1,
+	0x5d, 0xdc, 0xa0, 0xe2, 0x73, 0xb2, 0x60, 0x6d, 0x9f, 0xc4, 0xd3, 0x6b, 0x12, 0xf5, 0xeb, 0xa2,
+	0xed, 0x42, 0x32, 0xbe, 0x03, 0x6d, 0x64, 0x87, 0x51, 0x21, 0x29, 0x8a, 0x9d, 0xc6, 0x41, 0xe8,
+	0x05, 0xc9, 0x88, 0x08, 0x09, 0xdd, 0x81, 0xba, 0x63, 0xcf, 0xed, 0x88, 0xe7, 0x50, 0xc7, 0x42,
+	0x30, 0x5e, 0xc2, 0x5a, 0x2e, 0xc2, 0x47, 0x14, 0x36, 0xb7, 0x4e, 0x25, 0xbf, 0x8e, 0xf1, 0x1a,
+	0x3a, 0x0c, 0x76, 0x60, 0x59, 0xb4, 0x89, 0x21, 0x7a, 0x0c, 0x6d, 0x5a, 0x0f, 0x97, 0x46, 0x0e,
+	0x22, 0xbe, 0x23, 0x35, 0x37, 0x4e, 0x0c, 0x38, 0x4e, 0xac, 0x38, 0x03, 0xa2, 0x3e, 0xad, 0x84,
+	0x08, 0x20, 0xa3, 0x27, 0xa2, 0xf1, 0x9f, 0x02, 0x6a, 0xb1, 0x78, 0xe8, 0x6b, 0x80, 0xb9, 0xf9,
+	0xeb, 0xc8, 0x8c, 0x88, 0x3b, 0x5d, 0xc8, 0x81, 0x7f, 0x4f, 0x8b, 0x72, 0x60, 0xf4, 0x04, 0x7a,
+	0x73, 0xdb, 0xc5, 0xc4, 0x8f, 0x23, 0x6e, 0x94, 0x0d, 0xd6, 0x8a, 0x19, 0x13, 0x1f, 0x17, 0x61,
+	0xc8, 0x80, 0x2e, 0x55, 0x5c, 0xf8, 0x84, 0x58, 0xcf, 0x27, 0xbe, 0x68, 0x6f, 0x15, 0x17, 0x74,
+	0xac, 0x40, 0xe6, 0xdc, 0x8b, 0xdd, 0x88, 0x37, 0xb3, 0x8a, 0xa5, 0x84, 0xbe, 0x85, 0x2e, 0xcd,
+	0x24, 0x0a, 0xec, 0x29, 0xdf, 0x3e, 0x6f, 0x29, 0xdb, 0x70, 0x71, 0xc9, 0x0c, 0x80, 0x0b, 0x70,
+	0xa3, 0x0d, 0x4d, 0xb9, 0x29, 0xe3, 0x0f, 0x05, 0xb4, 0x32, 0x1a, 0x7d, 0x0e, 0xbd, 0x37, 0x01,
+	0x21, 0x87, 0xa6, 0x6b, 0xbd, 0xb3, 0x2d, 0x3a, 0xb3, 0x62, 0xae, 0x8b, 0x4a, 0xa4, 0x43, 0x8b,
+	0x29, 0x8e, 0xec, 0xf0, 0x9a, 0xe7, 0x5c, 0xc5, 0xa9, 0x8c, 0xb6, 0xa1, 0x63, 0xbb, 0x33, 0x56,
+	0xed, 0xa7, 0xb1, 0xe3, 0xf0, 0xdc, 0x5a, 0x38, 0xaf, 0x62, 0x07, 0x87, 0x64, 0x80, 0x1a, 0x07,
+	0xe4, 0x34, 0xc6, 0x3f, 0x0a, 0xd4, 0xd8, 0xc6, 0x90, 0x0a, 0x15, 0xdb, 0x92, 0x83, 0x48, 0xff,
+	0xd0, 0xa0, 0xd8, 0xd7, 0xce, 0xf0, 0x4e, 0x21, 0x6d, 0x39, 0x34, 0x69, 0xb7, 0xd1, 0x7d, 0xa8,
+	0x45, 0x0b, 0x9f, 0xf0, 0x3d, 0xa8, 0xc3, 0xb5, 0xe2, 0xe0, 0x50, 0x03, 0xe6, 0xe6, 0xa5, 0x92,
+	0xd6, 0x3e, 0xaa, 0xa4, 0xe8, 0x2b, 0x68, 0x79, 0x3e, 0xa1, 0xe3, 0x41, 0x87, 0x59, 0x74, 0x63,
+	0xbd, 0xe0, 0x7a, 0x26, 0x8d, 0x38, 0x85, 0x19, 0xbf, 0x2b, 0xd0, 0xcd, 0x9b, 0xd8, 0xf1, 0x22,
+	0x73, 0xd3, 0x76, 0x64, 0xb2, 0x42, 0x60, 0x33, 0xf0, 0xce, 0x74, 0x1c, 0x12, 0x25, 0x87, 0x44,
+	0x48, 0xe8, 0x21, 0x68, 0xe2, 0xef, 0x32, 0xb4, 0x67, 0xae, 0x19, 0xc5, 0x81, 0xc8, 0xb1, 0x8b,
+	0x6f, 0x0b, 0xfd, 0x45, 0xa2, 0x66, 0x47, 0x61, 0xca, 0xe6, 0x26, 0x10, 0xa4, 0x40, 0x8f, 0x82,
+	0x14, 0x8d, 0x1f, 0x60, 0xfd, 0x47, 0xdf, 0xa2, 0x93, 0x9c, 0xee, 0x4f, 0x52, 0x40, 0x3e, 0x1f,
+	0xe5, 0xc3, 0xf2, 0xe9, 0xc3, 0x46, 0x39, 0x96, 0x64, 0xe0, 0x07, 0xd0, 0x39, 0x9e, 0x5e, 0x79,
+	0x49, 0xec, 0xdc, 0xc9, 0x54, 0x8a, 0x27, 0xf3, 0x2f, 0x5a, 0x12, 0x81, 0x94, 0x34, 0x72, 0x17,
+	0xda, 0x01, 0x31, 0xa7, 0x57, 0xe6, 0xc4, 0x11, 0xec, 0xda, 0xc2, 0x99, 0x82, 0x17, 0x2c, 0x08,
+	0x52, 0xfa, 0x10, 0x02, 0x6d, 0xb8, 0x6a, 0xd9, 0xa6, 0x43, 0xac, 0xcb, 0x64, 0x95, 0x2a, 0x37,
+	0xf7, 0x84, 0x36, 0x61, 0x95, 0x1d, 0xe8, 0x86, 0x84, 0xb8, 0x29, 0x48, 0x54, 0xa6, 0xc3, 0x74,
+	0x09, 0x64, 0x40, 0x47, 0x87, 0x5e, 0xa7, 0xb2, 0xa1, 0xfa, 0x12, 0x1f, 0x8c, 0x93, 0xbb, 0x16,
+	0x73, 0x9c, 0x11, 0x40, 0xf7, 0x3c, 0x26, 0xc1, 0x22, 0x49, 0xf4, 0x3e, 0x34, 0x42, 0xe2, 0x5a,
+	0x24, 0xb8, 0xf9, 0x0a, 0x95, 0x46, 0x06, 0x8b, 0xcc, 0x60, 0x26, 0x3b, 0xbc, 0x0c, 0x13, 0xc6,
+	0x8c, 0x7d, 0x05, 0x53, 0x48, 0xf6, 0x35, 0xa1, 0x27, 0xd7, 0x94, 0x25, 0xfb, 0xc0, 0x45, 0x1f,
+	0x42, 0x2b, 0xbd, 0x50, 0x2b, 0x37, 0x71, 0x74, 0x6a, 0x36, 0xfe, 0x55, 0xa0, 0x93, 0x1b, 0x7d,
+	0x4a, 0x96, 0xc5, 0xd9, 0x50, 0x87, 0x9f, 0xa6, 0xae, 0x39, 0xdc, 0x60, 0x79, 0x46, 0x28, 0x59,
+	0x36, 0xf9, 0xbf, 0x6b, 0xf1, 0x5c, 0xd5, 0xe1, 0xdd, 0xd5, 0x9e, 0xae, 0x85, 0x13, 0x30, 0xcb,
+	0xfd, 0xad, 0xe9, 0xc4, 0x24, 0xc9, 0x9d, 0x0b, 0xc6, 0x63, 0x68, 0xa5, 0x87, 0xa7, 0x01, 0x95,
+	0xd1, 0x58, 0xbb, 0xc5, 0xbe, 0xc7, 0xe7, 0x9a, 0xc2, 0xbe, 0x27, 0x63, 0xad, 0x82, 0x9a, 0x50,
+	0x1d, 0x8d, 0x8f, 0xb5, 0x2a, 0xfb, 0x39, 0xa1, 0x3f, 0x35, 0x63, 0x17, 0x9a, 0x32, 0x3e, 0x5a,
+	0x2b, 0x11, 0x1d, 0xf5, 0xef, 0x66, 0xac, 0xa6, 0x29, 0xbb, 0x7d, 0xe8, 0x15, 0x2e, 0x18, 0x16,
+	0x65, 0xfc, 0xfd, 0x4b, 0xed, 0xd6, 0xae, 0x01, 0xad, 0x84, 0x41, 0x50, 0x1b, 0xea, 0x07, 0x47,
+	0x2f, 0x9e, 0x9d, 0x52, 0xf7, 0x0e, 0x34, 0x2f, 0xc6, 0x67, 0xf8, 0xe0, 0xe4, 0x58, 0x53, 0x86,
+	0x7f, 0x56, 0xe9, 0x52, 0x22, 0x3d, 0x5a, 0xb4, 0x86, 0x78, 0xa7, 0xa0, 0x15, 0x4f, 0x21, 0x7d,
+	0xd5, 0x83, 0x86, 0xbe, 0x80, 0xe0, 0x30, 0x76, 0xae, 0xa5, 0xfb, 0xe6, 0xcd, 0xee, 0xa1, 0xde,
+	0x5f, 0xe1, 0x1f, 0xa2, 0x9f, 0x40, 0x2b, 0xbf, 0x5f, 0xd0, 0x76, 0x8a, 0x5e, 0xf1, 0xb4, 0xd1,
+	0x77, 0xde, 0x83, 0x90, 0x3b, 0x3b, 0x84, 0x76, 0x7a, 0xf5, 0xa3, 0x8c, 0x2b, 0xcb, 0x0f, 0x0a,
+	0x5d, 0xbf, 0xc9, 0x24, 0x63, 0x9c, 0x83, 0x5a, 0xa4, 0x0d, 0xb4, 0x95, 0xa2, 0x6f, 0xe4, 0x26,
+	0xfd, 0xde, 0x4a, 0xbb, 0x0c, 0xb9, 0x07, 0x35, 0xc6, 0x22, 0x28, 0xbb, 0x19, 0x72, 0xf4, 0xa3,
+	0xaf, 0x97, 0xb4, 0xc2, 0x69, 0xb8, 0x0f, 0x75, 0x91, 0xc7, 0x13, 0xa8, 0xf3, 0x13, 0x85, 0x32,
+	0x60, 0xfe, 0x54, 0xeb, 0x1b, 0x65, 0xb5, 0x08, 0x70, 0x58, 0x7b, 0x55, 0xf1, 0x27, 0x93, 0x06,
+	0x67, 0x87, 0xbd, 0xff, 0x01, 0xb4, 0x60, 0x09, 0x60, 0xd1, 0x0b, 0x00, 0x00,
 }
@@ -23,9 +23,49 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// Action is what the order limit allows. Repair and audit traffic has
+// its own actions, so that it's accounted apart from customer traffic.
+type PayerBandwidthAllocation_Action int32
+
+const (
+	PayerBandwidthAllocation_PUT        PayerBandwidthAllocation_Action = 0
+	PayerBandwidthAllocation_GET        PayerBandwidthAllocation_Action = 1
+	PayerBandwidthAllocation_PUT_REPAIR PayerBandwidthAllocation_Action = 2
+	PayerBandwidthAllocation_GET_REPAIR PayerBandwidthAllocation_Action = 3
+	PayerBandwidthAllocation_GET_AUDIT  PayerBandwidthAllocation_Action = 4
+	PayerBandwidthAllocation_DELETE     PayerBandwidthAllocation_Action = 5
+)
+
+var PayerBandwidthAllocation_Action_name = map[int32]string{
+	0: "PUT",
+	1: "GET",
+	2: "PUT_REPAIR",
+	3: "GET_REPAIR",
+	4: "GET_AUDIT",
+	5: "DELETE",
+}
+var PayerBandwidthAllocation_Action_value = map[string]int32{
+	"PUT":        0,
+	"GET":        1,
+	"PUT_REPAIR": 2,
+	"GET_REPAIR": 3,
+	"GET_AUDIT":  4,
+	"DELETE":     5,
+}
+
+func (x PayerBandwidthAllocation_Action) String() string {
+	return proto.EnumName(PayerBandwidthAllocation_Action_name, int32(x))
+}
+func (PayerBandwidthAllocation_Action) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{0, 0}
+}
+
+// PayerBandwidthAllocation is an order limit signed by a satellite, which
+// allows an uplink to perform an action on a single piece of a storage node
 type PayerBandwidthAllocation struct {
 	Signature            []byte   `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
 	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Certs                [][]byte `protobuf:"bytes,3,rep,name=certs,proto3" json:"certs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -35,7 +75,7 @@ func (m *PayerBandwidthAllocation) Reset()         { *m = PayerBandwidthAllocati
 func (m *PayerBandwidthAllocation) String() string { return proto.CompactTextString(m) }
 func (*PayerBandwidthAllocation) ProtoMessage()    {}
 func (*PayerBandwidthAllocation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{0}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{0}
 }
 func (m *PayerBandwidthAllocation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PayerBandwidthAllocation.Unmarshal(m, b)
@@ -69,22 +109,33 @@ func (m *PayerBandwidthAllocation) GetData() []byte {
 	return nil
 }
 
+func (m *PayerBandwidthAllocation) GetCerts() [][]byte {
+	if m != nil {
+		return m.Certs
+	}
+	return nil
+}
+
 type PayerBandwidthAllocation_Data struct {
-	Payer                []byte   `protobuf:"bytes,1,opt,name=payer,proto3" json:"payer,omitempty"`
-	Renter               []byte   `protobuf:"bytes,2,opt,name=renter,proto3" json:"renter,omitempty"`
-	MaxSize              int64    `protobuf:"varint,3,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
-	ExpirationUnixSec    int64    `protobuf:"varint,4,opt,name=expiration_unix_sec,json=expirationUnixSec,proto3" json:"expiration_unix_sec,omitempty"`
-	SerialNumber         string   `protobuf:"bytes,5,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Payer                []byte                          `protobuf:"bytes,1,opt,name=payer,proto3" json:"payer,omitempty"`
+	Renter               []byte                          `protobuf:"bytes,2,opt,name=renter,proto3" json:"renter,omitempty"`
+	MaxSize              int64                           `protobuf:"varint,3,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	ExpirationUnixSec    int64                           `protobuf:"varint,4,opt,name=expiration_unix_sec,json=expirationUnixSec,proto3" json:"expiration_unix_sec,omitempty"`
+	SerialNumber         string                          `protobuf:"bytes,5,opt,name=serial_number,json=serialNumber,proto3" json:"serial_number,omitempty"`
+	Action               PayerBandwidthAllocation_Action `protobuf:"varint,6,opt,name=action,proto3,enum=piecestoreroutes.PayerBandwidthAllocation_Action" json:"action,omitempty"`
+	PieceId              string                          `protobuf:"bytes,7,opt,name=piece_id,json=pieceId,proto3" json:"piece_id,omitempty"`
+	StorageNodeId        []byte                          `protobuf:"bytes,8,opt,name=storage_node_id,json=storageNodeId,proto3" json:"storage_node_id,omitempty"`
+	StorageNodeAddress   string                          `protobuf:"bytes,9,opt,name=storage_node_address,json=storageNodeAddress,proto3" json:"storage_node_address,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                        `json:"-"`
+	XXX_unrecognized     []byte                          `json:"-"`
+	XXX_sizecache        int32                           `json:"-"`
 }
 
 func (m *PayerBandwidthAllocation_Data) Reset()         { *m = PayerBandwidthAllocation_Data{} }
 func (m *PayerBandwidthAllocation_Data) String() string { return proto.CompactTextString(m) }
 func (*PayerBandwidthAllocation_Data) ProtoMessage()    {}
 func (*PayerBandwidthAllocation_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{0, 0}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{0, 0}
 }
 func (m *PayerBandwidthAllocation_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PayerBandwidthAllocation_Data.Unmarshal(m, b)
@@ -139,6 +190,34 @@ func (m *PayerBandwidthAllocation_Data) GetSerialNumber() string {
 	return ""
 }
 
+func (m *PayerBandwidthAllocation_Data) GetAction() PayerBandwidthAllocation_Action {
+	if m != nil {
+		return m.Action
+	}
+	return PayerBandwidthAllocation_PUT
+}
+
+func (m *PayerBandwidthAllocation_Data) GetPieceId() string {
+	if m != nil {
+		return m.PieceId
+	}
+	return ""
+}
+
+func (m *PayerBandwidthAllocation_Data) GetStorageNodeId() []byte {
+	if m != nil {
+		return m.StorageNodeId
+	}
+	return nil
+}
+
+func (m *PayerBandwidthAllocation_Data) GetStorageNodeAddress() string {
+	if m != nil {
+		return m.StorageNodeAddress
+	}
+	return ""
+}
+
 type RenterBandwidthAllocation struct {
 	Signature            []byte   `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
 	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
@@ -151,7 +230,7 @@ func (m *RenterBandwidthAllocation) Reset()         { *m = RenterBandwidthAlloca
 func (m *RenterBandwidthAllocation) String() string { return proto.CompactTextString(m) }
 func (*RenterBandwidthAllocation) ProtoMessage()    {}
 func (*RenterBandwidthAllocation) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{1}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{1}
 }
 func (m *RenterBandwidthAllocation) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RenterBandwidthAllocation.Unmarshal(m, b)
@@ -197,7 +276,7 @@ func (m *RenterBandwidthAllocation_Data) Reset()         { *m = RenterBandwidthA
 func (m *RenterBandwidthAllocation_Data) String() string { return proto.CompactTextString(m) }
 func (*RenterBandwidthAllocation_Data) ProtoMessage()    {}
 func (*RenterBandwidthAllocation_Data) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{1, 0}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{1, 0}
 }
 func (m *RenterBandwidthAllocation_Data) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RenterBandwidthAllocation_Data.Unmarshal(m, b)
@@ -243,7 +322,7 @@ func (m *PieceStore) Reset()         { *m = PieceStore{} }
 func (m *PieceStore) String() string { return proto.CompactTextString(m) }
 func (*PieceStore) ProtoMessage()    {}
 func (*PieceStore) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{2}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{2}
 }
 func (m *PieceStore) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceStore.Unmarshal(m, b)
@@ -278,9 +357,12 @@ func (m *PieceStore) GetPiecedata() *PieceStore_PieceData {
 }
 
 type PieceStore_PieceData struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ExpirationUnixSec    int64    `protobuf:"varint,2,opt,name=expiration_unix_sec,json=expirationUnixSec,proto3" json:"expiration_unix_sec,omitempty"`
-	Content              []byte   `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Id                string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpirationUnixSec int64  `protobuf:"varint,2,opt,name=expiration_unix_sec,json=expirationUnixSec,proto3" json:"expiration_unix_sec,omitempty"`
+	Content           []byte `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// exiting_node_id is set when the piece is transferred from a node that
+	// leaves the network, which needs a transfer receipt for the satellite
+	ExitingNodeId        []byte   `protobuf:"bytes,4,opt,name=exiting_node_id,json=exitingNodeId,proto3" json:"exiting_node_id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -290,7 +372,7 @@ func (m *PieceStore_PieceData) Reset()         { *m = PieceStore_PieceData{} }
 func (m *PieceStore_PieceData) String() string { return proto.CompactTextString(m) }
 func (*PieceStore_PieceData) ProtoMessage()    {}
 func (*PieceStore_PieceData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{2, 0}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{2, 0}
 }
 func (m *PieceStore_PieceData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceStore_PieceData.Unmarshal(m, b)
@@ -331,6 +413,13 @@ func (m *PieceStore_PieceData) GetContent() []byte {
 	return nil
 }
 
+func (m *PieceStore_PieceData) GetExitingNodeId() []byte {
+	if m != nil {
+		return m.ExitingNodeId
+	}
+	return nil
+}
+
 type PieceId struct {
 	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -342,7 +431,7 @@ func (m *PieceId) Reset()         { *m = PieceId{} }
 func (m *PieceId) String() string { return proto.CompactTextString(m) }
 func (*PieceId) ProtoMessage()    {}
 func (*PieceId) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{3}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{3}
 }
 func (m *PieceId) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceId.Unmarshal(m, b)
@@ -382,7 +471,7 @@ func (m *PieceSummary) Reset()         { *m = PieceSummary{} }
 func (m *PieceSummary) String() string { return proto.CompactTextString(m) }
 func (*PieceSummary) ProtoMessage()    {}
 func (*PieceSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{4}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{4}
 }
 func (m *PieceSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceSummary.Unmarshal(m, b)
@@ -435,7 +524,7 @@ func (m *PieceRetrieval) Reset()         { *m = PieceRetrieval{} }
 func (m *PieceRetrieval) String() string { return proto.CompactTextString(m) }
 func (*PieceRetrieval) ProtoMessage()    {}
 func (*PieceRetrieval) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{5}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{5}
 }
 func (m *PieceRetrieval) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceRetrieval.Unmarshal(m, b)
@@ -482,7 +571,7 @@ func (m *PieceRetrieval_PieceData) Reset()         { *m = PieceRetrieval_PieceDa
 func (m *PieceRetrieval_PieceData) String() string { return proto.CompactTextString(m) }
 func (*PieceRetrieval_PieceData) ProtoMessage()    {}
 func (*PieceRetrieval_PieceData) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{5, 0}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{5, 0}
 }
 func (m *PieceRetrieval_PieceData) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceRetrieval_PieceData.Unmarshal(m, b)
@@ -535,7 +624,7 @@ func (m *PieceRetrievalStream) Reset()         { *m = PieceRetrievalStream{} }
 func (m *PieceRetrievalStream) String() string { return proto.CompactTextString(m) }
 func (*PieceRetrievalStream) ProtoMessage()    {}
 func (*PieceRetrievalStream) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{6}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{6}
 }
 func (m *PieceRetrievalStream) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceRetrievalStream.Unmarshal(m, b)
@@ -570,17 +659,18 @@ func (m *PieceRetrievalStream) GetContent() []byte {
 }
 
 type PieceDelete struct {
-	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Id                   string                    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	OrderLimit           *PayerBandwidthAllocation `protobuf:"bytes,2,opt,name=order_limit,json=orderLimit,proto3" json:"order_limit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
 }
 
 func (m *PieceDelete) Reset()         { *m = PieceDelete{} }
 func (m *PieceDelete) String() string { return proto.CompactTextString(m) }
 func (*PieceDelete) ProtoMessage()    {}
 func (*PieceDelete) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{7}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{7}
 }
 func (m *PieceDelete) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceDelete.Unmarshal(m, b)
@@ -607,6 +697,13 @@ func (m *PieceDelete) GetId() string {
 	return ""
 }
 
+func (m *PieceDelete) GetOrderLimit() *PayerBandwidthAllocation {
+	if m != nil {
+		return m.OrderLimit
+	}
+	return nil
+}
+
 type PieceDeleteSummary struct {
 	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -618,7 +715,7 @@ func (m *PieceDeleteSummary) Reset()         { *m = PieceDeleteSummary{} }
 func (m *PieceDeleteSummary) String() string { return proto.CompactTextString(m) }
 func (*PieceDeleteSummary) ProtoMessage()    {}
 func (*PieceDeleteSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{8}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{8}
 }
 func (m *PieceDeleteSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceDeleteSummary.Unmarshal(m, b)
@@ -646,18 +743,19 @@ func (m *PieceDeleteSummary) GetMessage() string {
 }
 
 type PieceStoreSummary struct {
-	Message              string   `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
-	TotalReceived        int64    `protobuf:"varint,2,opt,name=totalReceived,proto3" json:"totalReceived,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Message              string                `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	TotalReceived        int64                 `protobuf:"varint,2,opt,name=totalReceived,proto3" json:"totalReceived,omitempty"`
+	Receipt              *PieceTransferReceipt `protobuf:"bytes,3,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
 func (m *PieceStoreSummary) Reset()         { *m = PieceStoreSummary{} }
 func (m *PieceStoreSummary) String() string { return proto.CompactTextString(m) }
 func (*PieceStoreSummary) ProtoMessage()    {}
 func (*PieceStoreSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{9}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{9}
 }
 func (m *PieceStoreSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PieceStoreSummary.Unmarshal(m, b)
@@ -691,17 +789,156 @@ func (m *PieceStoreSummary) GetTotalReceived() int64 {
 	return 0
 }
 
-type StatsReq struct {
+func (m *PieceStoreSummary) GetReceipt() *PieceTransferReceipt {
+	if m != nil {
+		return m.Receipt
+	}
+	return nil
+}
+
+// PieceTransferReceipt is signed by a node that received a piece from a node
+// leaving the network, so that the satellite can verify the transfer
+type PieceTransferReceipt struct {
+	Signature            []byte   `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	Data                 []byte   `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Certs                [][]byte `protobuf:"bytes,3,rep,name=certs,proto3" json:"certs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PieceTransferReceipt) Reset()         { *m = PieceTransferReceipt{} }
+func (m *PieceTransferReceipt) String() string { return proto.CompactTextString(m) }
+func (*PieceTransferReceipt) ProtoMessage()    {}
+func (*PieceTransferReceipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{10}
+}
+func (m *PieceTransferReceipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PieceTransferReceipt.Unmarshal(m, b)
+}
+func (m *PieceTransferReceipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PieceTransferReceipt.Marshal(b, m, deterministic)
+}
+func (dst *PieceTransferReceipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PieceTransferReceipt.Merge(dst, src)
+}
+func (m *PieceTransferReceipt) XXX_Size() int {
+	return xxx_messageInfo_PieceTransferReceipt.Size(m)
+}
+func (m *PieceTransferReceipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_PieceTransferReceipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PieceTransferReceipt proto.InternalMessageInfo
+
+func (m *PieceTransferReceipt) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *PieceTransferReceipt) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *PieceTransferReceipt) GetCerts() [][]byte {
+	if m != nil {
+		return m.Certs
+	}
+	return nil
+}
+
+type PieceTransferReceipt_Data struct {
+	ExitingNodeId        []byte   `protobuf:"bytes,1,opt,name=exiting_node_id,json=exitingNodeId,proto3" json:"exiting_node_id,omitempty"`
+	ReceivingNodeId      []byte   `protobuf:"bytes,2,opt,name=receiving_node_id,json=receivingNodeId,proto3" json:"receiving_node_id,omitempty"`
+	PieceId              string   `protobuf:"bytes,3,opt,name=piece_id,json=pieceId,proto3" json:"piece_id,omitempty"`
+	PieceHash            []byte   `protobuf:"bytes,4,opt,name=piece_hash,json=pieceHash,proto3" json:"piece_hash,omitempty"`
+	TimestampUnixSec     int64    `protobuf:"varint,5,opt,name=timestamp_unix_sec,json=timestampUnixSec,proto3" json:"timestamp_unix_sec,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *PieceTransferReceipt_Data) Reset()         { *m = PieceTransferReceipt_Data{} }
+func (m *PieceTransferReceipt_Data) String() string { return proto.CompactTextString(m) }
+func (*PieceTransferReceipt_Data) ProtoMessage()    {}
+func (*PieceTransferReceipt_Data) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{10, 0}
+}
+func (m *PieceTransferReceipt_Data) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PieceTransferReceipt_Data.Unmarshal(m, b)
+}
+func (m *PieceTransferReceipt_Data) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PieceTransferReceipt_Data.Marshal(b, m, deterministic)
+}
+func (dst *PieceTransferReceipt_Data) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PieceTransferReceipt_Data.Merge(dst, src)
+}
+func (m *PieceTransferReceipt_Data) XXX_Size() int {
+	return xxx_messageInfo_PieceTransferReceipt_Data.Size(m)
+}
+func (m *PieceTransferReceipt_Data) XXX_DiscardUnknown() {
+	xxx_messageInfo_PieceTransferReceipt_Data.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PieceTransferReceipt_Data proto.InternalMessageInfo
+
+func (m *PieceTransferReceipt_Data) GetExitingNodeId() []byte {
+	if m != nil {
+		return m.ExitingNodeId
+	}
+	return nil
+}
+
+func (m *PieceTransferReceipt_Data) GetReceivingNodeId() []byte {
+	if m != nil {
+		return m.ReceivingNodeId
+	}
+	return nil
+}
+
+func (m *PieceTransferReceipt_Data) GetPieceId() string {
+	if m != nil {
+		return m.PieceId
+	}
+	return ""
+}
+
+func (m *PieceTransferReceipt_Data) GetPieceHash() []byte {
+	if m != nil {
+		return m.PieceHash
+	}
+	return nil
+}
+
+func (m *PieceTransferReceipt_Data) GetTimestampUnixSec() int64 {
+	if m != nil {
+		return m.TimestampUnixSec
+	}
+	return 0
+}
+
+type StatsReq struct {
+	// vetting is the vetting progress of the node with the satellite asking,
+	// if the satellite tracks it
+	Vetting *VettingProgress `protobuf:"bytes,1,opt,name=vetting,proto3" json:"vetting,omitempty"`
+	// audit is how the node fares in the audits of the satellite asking, if
+	// the satellite tracks it
+	Audit                *AuditStatus `protobuf:"bytes,2,opt,name=audit,proto3" json:"audit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
 func (m *StatsReq) Reset()         { *m = StatsReq{} }
 func (m *StatsReq) String() string { return proto.CompactTextString(m) }
 func (*StatsReq) ProtoMessage()    {}
 func (*StatsReq) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{10}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{11}
 }
 func (m *StatsReq) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatsReq.Unmarshal(m, b)
@@ -721,19 +958,185 @@ func (m *StatsReq) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_StatsReq proto.InternalMessageInfo
 
-type StatSummary struct {
-	UsedSpace            int64    `protobuf:"varint,1,opt,name=usedSpace,proto3" json:"usedSpace,omitempty"`
-	AvailableSpace       int64    `protobuf:"varint,2,opt,name=availableSpace,proto3" json:"availableSpace,omitempty"`
+func (m *StatsReq) GetVetting() *VettingProgress {
+	if m != nil {
+		return m.Vetting
+	}
+	return nil
+}
+
+func (m *StatsReq) GetAudit() *AuditStatus {
+	if m != nil {
+		return m.Audit
+	}
+	return nil
+}
+
+// VettingProgress is how far a node is toward being vetted by a satellite:
+// the successful audits and uptime checks it passed and how many it needs
+type VettingProgress struct {
+	AuditCount           int64    `protobuf:"varint,1,opt,name=audit_count,json=auditCount,proto3" json:"audit_count,omitempty"`
+	AuditThreshold       int64    `protobuf:"varint,2,opt,name=audit_threshold,json=auditThreshold,proto3" json:"audit_threshold,omitempty"`
+	UptimeCount          int64    `protobuf:"varint,3,opt,name=uptime_count,json=uptimeCount,proto3" json:"uptime_count,omitempty"`
+	UptimeThreshold      int64    `protobuf:"varint,4,opt,name=uptime_threshold,json=uptimeThreshold,proto3" json:"uptime_threshold,omitempty"`
+	Vetted               bool     `protobuf:"varint,5,opt,name=vetted,proto3" json:"vetted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *VettingProgress) Reset()         { *m = VettingProgress{} }
+func (m *VettingProgress) String() string { return proto.CompactTextString(m) }
+func (*VettingProgress) ProtoMessage()    {}
+func (*VettingProgress) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{12}
+}
+func (m *VettingProgress) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_VettingProgress.Unmarshal(m, b)
+}
+func (m *VettingProgress) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_VettingProgress.Marshal(b, m, deterministic)
+}
+func (dst *VettingProgress) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_VettingProgress.Merge(dst, src)
+}
+func (m *VettingProgress) XXX_Size() int {
+	return xxx_messageInfo_VettingProgress.Size(m)
+}
+func (m *VettingProgress) XXX_DiscardUnknown() {
+	xxx_messageInfo_VettingProgress.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_VettingProgress proto.InternalMessageInfo
+
+func (m *VettingProgress) GetAuditCount() int64 {
+	if m != nil {
+		return m.AuditCount
+	}
+	return 0
+}
+
+func (m *VettingProgress) GetAuditThreshold() int64 {
+	if m != nil {
+		return m.AuditThreshold
+	}
+	return 0
+}
+
+func (m *VettingProgress) GetUptimeCount() int64 {
+	if m != nil {
+		return m.UptimeCount
+	}
+	return 0
+}
+
+func (m *VettingProgress) GetUptimeThreshold() int64 {
+	if m != nil {
+		return m.UptimeThreshold
+	}
+	return 0
+}
+
+func (m *VettingProgress) GetVetted() bool {
+	if m != nil {
+		return m.Vetted
+	}
+	return false
+}
+
+// AuditStatus is how a node fares in the audits of a satellite: the audits
+// it was judged on and passed, and the audits in a row it didn't answer. A
+// failing node passed fewer of its audits than the satellite requires, or
+// missed too many in a row, and risks being disqualified.
+type AuditStatus struct {
+	AuditCount           int64    `protobuf:"varint,1,opt,name=audit_count,json=auditCount,proto3" json:"audit_count,omitempty"`
+	AuditSuccessCount    int64    `protobuf:"varint,2,opt,name=audit_success_count,json=auditSuccessCount,proto3" json:"audit_success_count,omitempty"`
+	MissedAudits         int64    `protobuf:"varint,3,opt,name=missed_audits,json=missedAudits,proto3" json:"missed_audits,omitempty"`
+	SuccessThreshold     float64  `protobuf:"fixed64,4,opt,name=success_threshold,json=successThreshold,proto3" json:"success_threshold,omitempty"`
+	Failing              bool     `protobuf:"varint,5,opt,name=failing,proto3" json:"failing,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *AuditStatus) Reset()         { *m = AuditStatus{} }
+func (m *AuditStatus) String() string { return proto.CompactTextString(m) }
+func (*AuditStatus) ProtoMessage()    {}
+func (*AuditStatus) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{13}
+}
+func (m *AuditStatus) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AuditStatus.Unmarshal(m, b)
+}
+func (m *AuditStatus) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AuditStatus.Marshal(b, m, deterministic)
+}
+func (dst *AuditStatus) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AuditStatus.Merge(dst, src)
+}
+func (m *AuditStatus) XXX_Size() int {
+	return xxx_messageInfo_AuditStatus.Size(m)
+}
+func (m *AuditStatus) XXX_DiscardUnknown() {
+	xxx_messageInfo_AuditStatus.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AuditStatus proto.InternalMessageInfo
+
+func (m *AuditStatus) GetAuditCount() int64 {
+	if m != nil {
+		return m.AuditCount
+	}
+	return 0
+}
+
+func (m *AuditStatus) GetAuditSuccessCount() int64 {
+	if m != nil {
+		return m.AuditSuccessCount
+	}
+	return 0
+}
+
+func (m *AuditStatus) GetMissedAudits() int64 {
+	if m != nil {
+		return m.MissedAudits
+	}
+	return 0
+}
+
+func (m *AuditStatus) GetSuccessThreshold() float64 {
+	if m != nil {
+		return m.SuccessThreshold
+	}
+	return 0
+}
+
+func (m *AuditStatus) GetFailing() bool {
+	if m != nil {
+		return m.Failing
+	}
+	return false
+}
+
+type StatSummary struct {
+	UsedSpace      int64         `protobuf:"varint,1,opt,name=usedSpace,proto3" json:"usedSpace,omitempty"`
+	AvailableSpace int64         `protobuf:"varint,2,opt,name=availableSpace,proto3" json:"availableSpace,omitempty"`
+	IngressFull    bool          `protobuf:"varint,3,opt,name=ingressFull,proto3" json:"ingressFull,omitempty"`
+	EgressFull     bool          `protobuf:"varint,4,opt,name=egressFull,proto3" json:"egressFull,omitempty"`
+	Operator       *NodeOperator `protobuf:"bytes,5,opt,name=operator,proto3" json:"operator,omitempty"`
+	// maintenance is the maintenance window the node is in or enters soon,
+	// during which it is offline or refuses new transfers
+	Maintenance          *MaintenanceWindow `protobuf:"bytes,6,opt,name=maintenance,proto3" json:"maintenance,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
 func (m *StatSummary) Reset()         { *m = StatSummary{} }
 func (m *StatSummary) String() string { return proto.CompactTextString(m) }
 func (*StatSummary) ProtoMessage()    {}
 func (*StatSummary) Descriptor() ([]byte, []int) {
-	return fileDescriptor_piecestore_1cd776e1a43644fb, []int{11}
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{14}
 }
 func (m *StatSummary) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_StatSummary.Unmarshal(m, b)
@@ -767,49 +1170,586 @@ func (m *StatSummary) GetAvailableSpace() int64 {
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*PayerBandwidthAllocation)(nil), "piecestoreroutes.PayerBandwidthAllocation")
-	proto.RegisterType((*PayerBandwidthAllocation_Data)(nil), "piecestoreroutes.PayerBandwidthAllocation.Data")
-	proto.RegisterType((*RenterBandwidthAllocation)(nil), "piecestoreroutes.RenterBandwidthAllocation")
-	proto.RegisterType((*RenterBandwidthAllocation_Data)(nil), "piecestoreroutes.RenterBandwidthAllocation.Data")
-	proto.RegisterType((*PieceStore)(nil), "piecestoreroutes.PieceStore")
-	proto.RegisterType((*PieceStore_PieceData)(nil), "piecestoreroutes.PieceStore.PieceData")
-	proto.RegisterType((*PieceId)(nil), "piecestoreroutes.PieceId")
-	proto.RegisterType((*PieceSummary)(nil), "piecestoreroutes.PieceSummary")
-	proto.RegisterType((*PieceRetrieval)(nil), "piecestoreroutes.PieceRetrieval")
-	proto.RegisterType((*PieceRetrieval_PieceData)(nil), "piecestoreroutes.PieceRetrieval.PieceData")
-	proto.RegisterType((*PieceRetrievalStream)(nil), "piecestoreroutes.PieceRetrievalStream")
-	proto.RegisterType((*PieceDelete)(nil), "piecestoreroutes.PieceDelete")
-	proto.RegisterType((*PieceDeleteSummary)(nil), "piecestoreroutes.PieceDeleteSummary")
-	proto.RegisterType((*PieceStoreSummary)(nil), "piecestoreroutes.PieceStoreSummary")
-	proto.RegisterType((*StatsReq)(nil), "piecestoreroutes.StatsReq")
-	proto.RegisterType((*StatSummary)(nil), "piecestoreroutes.StatSummary")
+func (m *StatSummary) GetIngressFull() bool {
+	if m != nil {
+		return m.IngressFull
+	}
+	return false
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
-
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
-
-// PieceStoreRoutesClient is the client API for PieceStoreRoutes service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type PieceStoreRoutesClient interface {
-	Piece(ctx context.Context, in *PieceId, opts ...grpc.CallOption) (*PieceSummary, error)
-	Retrieve(ctx context.Context, opts ...grpc.CallOption) (PieceStoreRoutes_RetrieveClient, error)
-	Store(ctx context.Context, opts ...grpc.CallOption) (PieceStoreRoutes_StoreClient, error)
-	Delete(ctx context.Context, in *PieceDelete, opts ...grpc.CallOption) (*PieceDeleteSummary, error)
-	Stats(ctx context.Context, in *StatsReq, opts ...grpc.CallOption) (*StatSummary, error)
+func (m *StatSummary) GetEgressFull() bool {
+	if m != nil {
+		return m.EgressFull
+	}
+	return false
 }
 
-type pieceStoreRoutesClient struct {
-	cc *grpc.ClientConn
+func (m *StatSummary) GetOperator() *NodeOperator {
+	if m != nil {
+		return m.Operator
+	}
+	return nil
 }
 
-func NewPieceStoreRoutesClient(cc *grpc.ClientConn) PieceStoreRoutesClient {
+func (m *StatSummary) GetMaintenance() *MaintenanceWindow {
+	if m != nil {
+		return m.Maintenance
+	}
+	return nil
+}
+
+type MaintenanceWindow struct {
+	StartUnixSec         int64    `protobuf:"varint,1,opt,name=start_unix_sec,json=startUnixSec,proto3" json:"start_unix_sec,omitempty"`
+	EndUnixSec           int64    `protobuf:"varint,2,opt,name=end_unix_sec,json=endUnixSec,proto3" json:"end_unix_sec,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MaintenanceWindow) Reset()         { *m = MaintenanceWindow{} }
+func (m *MaintenanceWindow) String() string { return proto.CompactTextString(m) }
+func (*MaintenanceWindow) ProtoMessage()    {}
+func (*MaintenanceWindow) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{15}
+}
+func (m *MaintenanceWindow) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MaintenanceWindow.Unmarshal(m, b)
+}
+func (m *MaintenanceWindow) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MaintenanceWindow.Marshal(b, m, deterministic)
+}
+func (dst *MaintenanceWindow) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MaintenanceWindow.Merge(dst, src)
+}
+func (m *MaintenanceWindow) XXX_Size() int {
+	return xxx_messageInfo_MaintenanceWindow.Size(m)
+}
+func (m *MaintenanceWindow) XXX_DiscardUnknown() {
+	xxx_messageInfo_MaintenanceWindow.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MaintenanceWindow proto.InternalMessageInfo
+
+func (m *MaintenanceWindow) GetStartUnixSec() int64 {
+	if m != nil {
+		return m.StartUnixSec
+	}
+	return 0
+}
+
+func (m *MaintenanceWindow) GetEndUnixSec() int64 {
+	if m != nil {
+		return m.EndUnixSec
+	}
+	return 0
+}
+
+type RetainRequest struct {
+	CreationUnixSec      int64    `protobuf:"varint,1,opt,name=creation_unix_sec,json=creationUnixSec,proto3" json:"creation_unix_sec,omitempty"`
+	Filter               []byte   `protobuf:"bytes,2,opt,name=filter,proto3" json:"filter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetainRequest) Reset()         { *m = RetainRequest{} }
+func (m *RetainRequest) String() string { return proto.CompactTextString(m) }
+func (*RetainRequest) ProtoMessage()    {}
+func (*RetainRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{16}
+}
+func (m *RetainRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetainRequest.Unmarshal(m, b)
+}
+func (m *RetainRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetainRequest.Marshal(b, m, deterministic)
+}
+func (dst *RetainRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetainRequest.Merge(dst, src)
+}
+func (m *RetainRequest) XXX_Size() int {
+	return xxx_messageInfo_RetainRequest.Size(m)
+}
+func (m *RetainRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetainRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetainRequest proto.InternalMessageInfo
+
+func (m *RetainRequest) GetCreationUnixSec() int64 {
+	if m != nil {
+		return m.CreationUnixSec
+	}
+	return 0
+}
+
+func (m *RetainRequest) GetFilter() []byte {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+type RetainSummary struct {
+	Retained             int64    `protobuf:"varint,1,opt,name=retained,proto3" json:"retained,omitempty"`
+	Deleted              int64    `protobuf:"varint,2,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RetainSummary) Reset()         { *m = RetainSummary{} }
+func (m *RetainSummary) String() string { return proto.CompactTextString(m) }
+func (*RetainSummary) ProtoMessage()    {}
+func (*RetainSummary) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{17}
+}
+func (m *RetainSummary) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RetainSummary.Unmarshal(m, b)
+}
+func (m *RetainSummary) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RetainSummary.Marshal(b, m, deterministic)
+}
+func (dst *RetainSummary) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RetainSummary.Merge(dst, src)
+}
+func (m *RetainSummary) XXX_Size() int {
+	return xxx_messageInfo_RetainSummary.Size(m)
+}
+func (m *RetainSummary) XXX_DiscardUnknown() {
+	xxx_messageInfo_RetainSummary.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RetainSummary proto.InternalMessageInfo
+
+func (m *RetainSummary) GetRetained() int64 {
+	if m != nil {
+		return m.Retained
+	}
+	return 0
+}
+
+func (m *RetainSummary) GetDeleted() int64 {
+	if m != nil {
+		return m.Deleted
+	}
+	return 0
+}
+
+// StatPiecesRequest asks for the metadata of pieces, without their data
+type StatPiecesRequest struct {
+	Ids                  []string `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StatPiecesRequest) Reset()         { *m = StatPiecesRequest{} }
+func (m *StatPiecesRequest) String() string { return proto.CompactTextString(m) }
+func (*StatPiecesRequest) ProtoMessage()    {}
+func (*StatPiecesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{18}
+}
+func (m *StatPiecesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatPiecesRequest.Unmarshal(m, b)
+}
+func (m *StatPiecesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatPiecesRequest.Marshal(b, m, deterministic)
+}
+func (dst *StatPiecesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatPiecesRequest.Merge(dst, src)
+}
+func (m *StatPiecesRequest) XXX_Size() int {
+	return xxx_messageInfo_StatPiecesRequest.Size(m)
+}
+func (m *StatPiecesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatPiecesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatPiecesRequest proto.InternalMessageInfo
+
+func (m *StatPiecesRequest) GetIds() []string {
+	if m != nil {
+		return m.Ids
+	}
+	return nil
+}
+
+type PieceStat struct {
+	Id                   string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Exists               bool     `protobuf:"varint,2,opt,name=exists,proto3" json:"exists,omitempty"`
+	Size                 int64    `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	CreationUnixSec      int64    `protobuf:"varint,4,opt,name=creation_unix_sec,json=creationUnixSec,proto3" json:"creation_unix_sec,omitempty"`
+	ExpirationUnixSec    int64    `protobuf:"varint,5,opt,name=expiration_unix_sec,json=expirationUnixSec,proto3" json:"expiration_unix_sec,omitempty"`
+	Hash                 []byte   `protobuf:"bytes,6,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *PieceStat) Reset()         { *m = PieceStat{} }
+func (m *PieceStat) String() string { return proto.CompactTextString(m) }
+func (*PieceStat) ProtoMessage()    {}
+func (*PieceStat) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{19}
+}
+func (m *PieceStat) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_PieceStat.Unmarshal(m, b)
+}
+func (m *PieceStat) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_PieceStat.Marshal(b, m, deterministic)
+}
+func (dst *PieceStat) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_PieceStat.Merge(dst, src)
+}
+func (m *PieceStat) XXX_Size() int {
+	return xxx_messageInfo_PieceStat.Size(m)
+}
+func (m *PieceStat) XXX_DiscardUnknown() {
+	xxx_messageInfo_PieceStat.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_PieceStat proto.InternalMessageInfo
+
+func (m *PieceStat) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *PieceStat) GetExists() bool {
+	if m != nil {
+		return m.Exists
+	}
+	return false
+}
+
+func (m *PieceStat) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *PieceStat) GetCreationUnixSec() int64 {
+	if m != nil {
+		return m.CreationUnixSec
+	}
+	return 0
+}
+
+func (m *PieceStat) GetExpirationUnixSec() int64 {
+	if m != nil {
+		return m.ExpirationUnixSec
+	}
+	return 0
+}
+
+func (m *PieceStat) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type StatPiecesResponse struct {
+	Pieces               []*PieceStat `protobuf:"bytes,1,rep,name=pieces,proto3" json:"pieces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *StatPiecesResponse) Reset()         { *m = StatPiecesResponse{} }
+func (m *StatPiecesResponse) String() string { return proto.CompactTextString(m) }
+func (*StatPiecesResponse) ProtoMessage()    {}
+func (*StatPiecesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{20}
+}
+func (m *StatPiecesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StatPiecesResponse.Unmarshal(m, b)
+}
+func (m *StatPiecesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StatPiecesResponse.Marshal(b, m, deterministic)
+}
+func (dst *StatPiecesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StatPiecesResponse.Merge(dst, src)
+}
+func (m *StatPiecesResponse) XXX_Size() int {
+	return xxx_messageInfo_StatPiecesResponse.Size(m)
+}
+func (m *StatPiecesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StatPiecesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StatPiecesResponse proto.InternalMessageInfo
+
+func (m *StatPiecesResponse) GetPieces() []*PieceStat {
+	if m != nil {
+		return m.Pieces
+	}
+	return nil
+}
+
+// ChallengeRequest asks a node to prove that it stores a piece with a leaf of
+// the Merkle tree of the piece, without downloading the piece
+type ChallengeRequest struct {
+	PieceId              string   `protobuf:"bytes,1,opt,name=piece_id,json=pieceId,proto3" json:"piece_id,omitempty"`
+	Leaf                 int64    `protobuf:"varint,2,opt,name=leaf,proto3" json:"leaf,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChallengeRequest) Reset()         { *m = ChallengeRequest{} }
+func (m *ChallengeRequest) String() string { return proto.CompactTextString(m) }
+func (*ChallengeRequest) ProtoMessage()    {}
+func (*ChallengeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{21}
+}
+func (m *ChallengeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChallengeRequest.Unmarshal(m, b)
+}
+func (m *ChallengeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChallengeRequest.Marshal(b, m, deterministic)
+}
+func (dst *ChallengeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChallengeRequest.Merge(dst, src)
+}
+func (m *ChallengeRequest) XXX_Size() int {
+	return xxx_messageInfo_ChallengeRequest.Size(m)
+}
+func (m *ChallengeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChallengeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChallengeRequest proto.InternalMessageInfo
+
+func (m *ChallengeRequest) GetPieceId() string {
+	if m != nil {
+		return m.PieceId
+	}
+	return ""
+}
+
+func (m *ChallengeRequest) GetLeaf() int64 {
+	if m != nil {
+		return m.Leaf
+	}
+	return 0
+}
+
+type ChallengeResponse struct {
+	Leaf                 []byte   `protobuf:"bytes,1,opt,name=leaf,proto3" json:"leaf,omitempty"`
+	Proof                [][]byte `protobuf:"bytes,2,rep,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ChallengeResponse) Reset()         { *m = ChallengeResponse{} }
+func (m *ChallengeResponse) String() string { return proto.CompactTextString(m) }
+func (*ChallengeResponse) ProtoMessage()    {}
+func (*ChallengeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{22}
+}
+func (m *ChallengeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ChallengeResponse.Unmarshal(m, b)
+}
+func (m *ChallengeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ChallengeResponse.Marshal(b, m, deterministic)
+}
+func (dst *ChallengeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ChallengeResponse.Merge(dst, src)
+}
+func (m *ChallengeResponse) XXX_Size() int {
+	return xxx_messageInfo_ChallengeResponse.Size(m)
+}
+func (m *ChallengeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ChallengeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ChallengeResponse proto.InternalMessageInfo
+
+func (m *ChallengeResponse) GetLeaf() []byte {
+	if m != nil {
+		return m.Leaf
+	}
+	return nil
+}
+
+func (m *ChallengeResponse) GetProof() [][]byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+// ProofRequest asks a node for the hashes of a range of leaves of the Merkle
+// tree of a piece and their proof, so that a partial download of the piece
+// can be verified without downloading the whole piece
+type ProofRequest struct {
+	PieceId              string   `protobuf:"bytes,1,opt,name=piece_id,json=pieceId,proto3" json:"piece_id,omitempty"`
+	FirstLeaf            int64    `protobuf:"varint,2,opt,name=first_leaf,json=firstLeaf,proto3" json:"first_leaf,omitempty"`
+	LastLeaf             int64    `protobuf:"varint,3,opt,name=last_leaf,json=lastLeaf,proto3" json:"last_leaf,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProofRequest) Reset()         { *m = ProofRequest{} }
+func (m *ProofRequest) String() string { return proto.CompactTextString(m) }
+func (*ProofRequest) ProtoMessage()    {}
+func (*ProofRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{23}
+}
+func (m *ProofRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProofRequest.Unmarshal(m, b)
+}
+func (m *ProofRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProofRequest.Marshal(b, m, deterministic)
+}
+func (dst *ProofRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProofRequest.Merge(dst, src)
+}
+func (m *ProofRequest) XXX_Size() int {
+	return xxx_messageInfo_ProofRequest.Size(m)
+}
+func (m *ProofRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProofRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProofRequest proto.InternalMessageInfo
+
+func (m *ProofRequest) GetPieceId() string {
+	if m != nil {
+		return m.PieceId
+	}
+	return ""
+}
+
+func (m *ProofRequest) GetFirstLeaf() int64 {
+	if m != nil {
+		return m.FirstLeaf
+	}
+	return 0
+}
+
+func (m *ProofRequest) GetLastLeaf() int64 {
+	if m != nil {
+		return m.LastLeaf
+	}
+	return 0
+}
+
+type ProofResponse struct {
+	Leaves               [][]byte `protobuf:"bytes,1,rep,name=leaves,proto3" json:"leaves,omitempty"`
+	Proof                [][]byte `protobuf:"bytes,2,rep,name=proof,proto3" json:"proof,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ProofResponse) Reset()         { *m = ProofResponse{} }
+func (m *ProofResponse) String() string { return proto.CompactTextString(m) }
+func (*ProofResponse) ProtoMessage()    {}
+func (*ProofResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_piecestore_4c543755608a6e7f, []int{24}
+}
+func (m *ProofResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ProofResponse.Unmarshal(m, b)
+}
+func (m *ProofResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ProofResponse.Marshal(b, m, deterministic)
+}
+func (dst *ProofResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ProofResponse.Merge(dst, src)
+}
+func (m *ProofResponse) XXX_Size() int {
+	return xxx_messageInfo_ProofResponse.Size(m)
+}
+func (m *ProofResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ProofResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ProofResponse proto.InternalMessageInfo
+
+func (m *ProofResponse) GetLeaves() [][]byte {
+	if m != nil {
+		return m.Leaves
+	}
+	return nil
+}
+
+func (m *ProofResponse) GetProof() [][]byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*PayerBandwidthAllocation)(nil), "piecestoreroutes.PayerBandwidthAllocation")
+	proto.RegisterType((*PayerBandwidthAllocation_Data)(nil), "piecestoreroutes.PayerBandwidthAllocation.Data")
+	proto.RegisterType((*RenterBandwidthAllocation)(nil), "piecestoreroutes.RenterBandwidthAllocation")
+	proto.RegisterType((*RenterBandwidthAllocation_Data)(nil), "piecestoreroutes.RenterBandwidthAllocation.Data")
+	proto.RegisterType((*PieceStore)(nil), "piecestoreroutes.PieceStore")
+	proto.RegisterType((*PieceStore_PieceData)(nil), "piecestoreroutes.PieceStore.PieceData")
+	proto.RegisterType((*PieceId)(nil), "piecestoreroutes.PieceId")
+	proto.RegisterType((*PieceSummary)(nil), "piecestoreroutes.PieceSummary")
+	proto.RegisterType((*PieceRetrieval)(nil), "piecestoreroutes.PieceRetrieval")
+	proto.RegisterType((*PieceRetrieval_PieceData)(nil), "piecestoreroutes.PieceRetrieval.PieceData")
+	proto.RegisterType((*PieceRetrievalStream)(nil), "piecestoreroutes.PieceRetrievalStream")
+	proto.RegisterType((*PieceDelete)(nil), "piecestoreroutes.PieceDelete")
+	proto.RegisterType((*PieceDeleteSummary)(nil), "piecestoreroutes.PieceDeleteSummary")
+	proto.RegisterType((*PieceStoreSummary)(nil), "piecestoreroutes.PieceStoreSummary")
+	proto.RegisterType((*PieceTransferReceipt)(nil), "piecestoreroutes.PieceTransferReceipt")
+	proto.RegisterType((*PieceTransferReceipt_Data)(nil), "piecestoreroutes.PieceTransferReceipt.Data")
+	proto.RegisterType((*StatsReq)(nil), "piecestoreroutes.StatsReq")
+	proto.RegisterType((*VettingProgress)(nil), "piecestoreroutes.VettingProgress")
+	proto.RegisterType((*AuditStatus)(nil), "piecestoreroutes.AuditStatus")
+	proto.RegisterType((*StatSummary)(nil), "piecestoreroutes.StatSummary")
+	proto.RegisterType((*MaintenanceWindow)(nil), "piecestoreroutes.MaintenanceWindow")
+	proto.RegisterType((*RetainRequest)(nil), "piecestoreroutes.RetainRequest")
+	proto.RegisterType((*RetainSummary)(nil), "piecestoreroutes.RetainSummary")
+	proto.RegisterType((*StatPiecesRequest)(nil), "piecestoreroutes.StatPiecesRequest")
+	proto.RegisterType((*PieceStat)(nil), "piecestoreroutes.PieceStat")
+	proto.RegisterType((*StatPiecesResponse)(nil), "piecestoreroutes.StatPiecesResponse")
+	proto.RegisterType((*ChallengeRequest)(nil), "piecestoreroutes.ChallengeRequest")
+	proto.RegisterType((*ChallengeResponse)(nil), "piecestoreroutes.ChallengeResponse")
+	proto.RegisterType((*ProofRequest)(nil), "piecestoreroutes.ProofRequest")
+	proto.RegisterType((*ProofResponse)(nil), "piecestoreroutes.ProofResponse")
+	proto.RegisterEnum("piecestoreroutes.PayerBandwidthAllocation_Action", PayerBandwidthAllocation_Action_name, PayerBandwidthAllocation_Action_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// PieceStoreRoutesClient is the client API for PieceStoreRoutes service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PieceStoreRoutesClient interface {
+	Piece(ctx context.Context, in *PieceId, opts ...grpc.CallOption) (*PieceSummary, error)
+	Retrieve(ctx context.Context, opts ...grpc.CallOption) (PieceStoreRoutes_RetrieveClient, error)
+	Store(ctx context.Context, opts ...grpc.CallOption) (PieceStoreRoutes_StoreClient, error)
+	Delete(ctx context.Context, in *PieceDelete, opts ...grpc.CallOption) (*PieceDeleteSummary, error)
+	Stats(ctx context.Context, in *StatsReq, opts ...grpc.CallOption) (*StatSummary, error)
+	Retain(ctx context.Context, in *RetainRequest, opts ...grpc.CallOption) (*RetainSummary, error)
+	StatPieces(ctx context.Context, in *StatPiecesRequest, opts ...grpc.CallOption) (*StatPiecesResponse, error)
+	Challenge(ctx context.Context, in *ChallengeRequest, opts ...grpc.CallOption) (*ChallengeResponse, error)
+	Proof(ctx context.Context, in *ProofRequest, opts ...grpc.CallOption) (*ProofResponse, error)
+}
+
+type pieceStoreRoutesClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewPieceStoreRoutesClient(cc *grpc.ClientConn) PieceStoreRoutesClient {
 	return &pieceStoreRoutesClient{cc}
 }
 
@@ -905,6 +1845,42 @@ func (c *pieceStoreRoutesClient) Stats(ctx context.Context, in *StatsReq, opts .
 	return out, nil
 }
 
+func (c *pieceStoreRoutesClient) Retain(ctx context.Context, in *RetainRequest, opts ...grpc.CallOption) (*RetainSummary, error) {
+	out := new(RetainSummary)
+	err := c.cc.Invoke(ctx, "/piecestoreroutes.PieceStoreRoutes/Retain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pieceStoreRoutesClient) StatPieces(ctx context.Context, in *StatPiecesRequest, opts ...grpc.CallOption) (*StatPiecesResponse, error) {
+	out := new(StatPiecesResponse)
+	err := c.cc.Invoke(ctx, "/piecestoreroutes.PieceStoreRoutes/StatPieces", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pieceStoreRoutesClient) Challenge(ctx context.Context, in *ChallengeRequest, opts ...grpc.CallOption) (*ChallengeResponse, error) {
+	out := new(ChallengeResponse)
+	err := c.cc.Invoke(ctx, "/piecestoreroutes.PieceStoreRoutes/Challenge", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pieceStoreRoutesClient) Proof(ctx context.Context, in *ProofRequest, opts ...grpc.CallOption) (*ProofResponse, error) {
+	out := new(ProofResponse)
+	err := c.cc.Invoke(ctx, "/piecestoreroutes.PieceStoreRoutes/Proof", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PieceStoreRoutesServer is the server API for PieceStoreRoutes service.
 type PieceStoreRoutesServer interface {
 	Piece(context.Context, *PieceId) (*PieceSummary, error)
@@ -912,6 +1888,10 @@ type PieceStoreRoutesServer interface {
 	Store(PieceStoreRoutes_StoreServer) error
 	Delete(context.Context, *PieceDelete) (*PieceDeleteSummary, error)
 	Stats(context.Context, *StatsReq) (*StatSummary, error)
+	Retain(context.Context, *RetainRequest) (*RetainSummary, error)
+	StatPieces(context.Context, *StatPiecesRequest) (*StatPiecesResponse, error)
+	Challenge(context.Context, *ChallengeRequest) (*ChallengeResponse, error)
+	Proof(context.Context, *ProofRequest) (*ProofResponse, error)
 }
 
 func RegisterPieceStoreRoutesServer(s *grpc.Server, srv PieceStoreRoutesServer) {
@@ -1024,6 +2004,78 @@ func _PieceStoreRoutes_Stats_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PieceStoreRoutes_Retain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PieceStoreRoutesServer).Retain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/piecestoreroutes.PieceStoreRoutes/Retain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PieceStoreRoutesServer).Retain(ctx, req.(*RetainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PieceStoreRoutes_StatPieces_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StatPiecesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PieceStoreRoutesServer).StatPieces(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/piecestoreroutes.PieceStoreRoutes/StatPieces",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PieceStoreRoutesServer).StatPieces(ctx, req.(*StatPiecesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PieceStoreRoutes_Challenge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChallengeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PieceStoreRoutesServer).Challenge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/piecestoreroutes.PieceStoreRoutes/Challenge",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PieceStoreRoutesServer).Challenge(ctx, req.(*ChallengeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PieceStoreRoutes_Proof_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProofRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PieceStoreRoutesServer).Proof(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/piecestoreroutes.PieceStoreRoutes/Proof",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PieceStoreRoutesServer).Proof(ctx, req.(*ProofRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _PieceStoreRoutes_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "piecestoreroutes.PieceStoreRoutes",
 	HandlerType: (*PieceStoreRoutesServer)(nil),
@@ -1040,6 +2092,22 @@ var _PieceStoreRoutes_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Stats",
 			Handler:    _PieceStoreRoutes_Stats_Handler,
 		},
+		{
+			MethodName: "Retain",
+			Handler:    _PieceStoreRoutes_Retain_Handler,
+		},
+		{
+			MethodName: "StatPieces",
+			Handler:    _PieceStoreRoutes_StatPieces_Handler,
+		},
+		{
+			MethodName: "Challenge",
+			Handler:    _PieceStoreRoutes_Challenge_Handler,
+		},
+		{
+			MethodName: "Proof",
+			Handler:    _PieceStoreRoutes_Proof_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -1057,51 +2125,109 @@ var _PieceStoreRoutes_serviceDesc = grpc.ServiceDesc{
 	Metadata: "piecestore.proto",
 }
 
-func init() { proto.RegisterFile("piecestore.proto", fileDescriptor_piecestore_1cd776e1a43644fb) }
-
-var fileDescriptor_piecestore_1cd776e1a43644fb = []byte{
-	// 679 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xdb, 0x4e, 0xdb, 0x4c,
-	0x10, 0xc6, 0xce, 0x89, 0x4c, 0x02, 0x3f, 0x2c, 0x08, 0x39, 0x16, 0xfc, 0x8a, 0x0c, 0x42, 0x11,
-	0xbf, 0x14, 0xfd, 0xa2, 0x4f, 0x50, 0x14, 0xa9, 0xe5, 0x86, 0xa2, 0xb5, 0xb8, 0x41, 0xaa, 0xa2,
-	0x8d, 0x3d, 0xd0, 0x95, 0x7c, 0x48, 0xbd, 0x1b, 0x1a, 0xb8, 0xec, 0x53, 0x54, 0xea, 0x6d, 0x9f,
-	0xa4, 0xaf, 0xd4, 0x17, 0xa8, 0xbc, 0xeb, 0x43, 0x20, 0x71, 0xb8, 0x69, 0xef, 0x76, 0x4e, 0xdf,
-	0x7c, 0x33, 0xdf, 0xc4, 0x81, 0x9d, 0x29, 0x47, 0x0f, 0x85, 0x8c, 0x13, 0x1c, 0x4e, 0x93, 0x58,
-	0xc6, 0x64, 0xc1, 0x93, 0xc4, 0x33, 0x89, 0xc2, 0xf9, 0x65, 0x80, 0x75, 0xcd, 0x1e, 0x31, 0xb9,
-	0x60, 0x91, 0xff, 0x85, 0xfb, 0xf2, 0xd3, 0xdb, 0x20, 0x88, 0x3d, 0x26, 0x79, 0x1c, 0x91, 0x43,
-	0x68, 0x0b, 0x7e, 0x1f, 0x31, 0x39, 0x4b, 0xd0, 0x32, 0xfa, 0xc6, 0xa0, 0x4b, 0x4b, 0x07, 0x21,
-	0x50, 0xf7, 0x99, 0x64, 0x96, 0xa9, 0x02, 0xea, 0x6d, 0xff, 0x30, 0xa0, 0x3e, 0x62, 0x92, 0x91,
-	0x7d, 0x68, 0x4c, 0x53, 0xd8, 0xac, 0x4c, 0x1b, 0xe4, 0x00, 0x9a, 0x09, 0x46, 0x12, 0x93, 0xac,
-	0x28, 0xb3, 0x48, 0x0f, 0x36, 0x43, 0x36, 0x1f, 0x0b, 0xfe, 0x84, 0x56, 0xad, 0x6f, 0x0c, 0x6a,
-	0xb4, 0x15, 0xb2, 0xb9, 0xcb, 0x9f, 0x90, 0x0c, 0x61, 0x0f, 0xe7, 0x53, 0x9e, 0x28, 0x46, 0xe3,
-	0x59, 0xc4, 0xe7, 0x63, 0x81, 0x9e, 0x55, 0x57, 0x59, 0xbb, 0x65, 0xe8, 0x26, 0xe2, 0x73, 0x17,
-	0x3d, 0x72, 0x0c, 0x5b, 0x02, 0x13, 0xce, 0x82, 0x71, 0x34, 0x0b, 0x27, 0x98, 0x58, 0x8d, 0xbe,
-	0x31, 0x68, 0xd3, 0xae, 0x76, 0x5e, 0x29, 0x9f, 0xf3, 0xd3, 0x80, 0x1e, 0x55, 0xad, 0xff, 0xcc,
-	0xd8, 0x22, 0x9b, 0xfa, 0x06, 0x76, 0xd4, 0xa0, 0x63, 0x56, 0xa0, 0x29, 0x80, 0xce, 0xf9, 0xd9,
-	0xf0, 0xe5, 0xea, 0x87, 0x55, 0x6b, 0xa7, 0xff, 0x28, 0x8c, 0x05, 0x42, 0xfb, 0xd0, 0x90, 0xb1,
-	0x64, 0x81, 0xea, 0x59, 0xa3, 0xda, 0x70, 0xbe, 0x99, 0x00, 0xd7, 0x29, 0xa8, 0x9b, 0x82, 0x92,
-	0x8f, 0xb0, 0x37, 0xc9, 0xc1, 0x96, 0xda, 0xff, 0xb7, 0xdc, 0xbe, 0x72, 0x7e, 0xba, 0x0a, 0x87,
-	0x8c, 0xa0, 0xad, 0x20, 0x8a, 0xd9, 0x3b, 0xe7, 0xa7, 0x2b, 0x66, 0x2a, 0xf8, 0xe8, 0x67, 0xba,
-	0x15, 0x5a, 0x16, 0xda, 0x08, 0xed, 0xc2, 0x4f, 0xb6, 0xc1, 0xe4, 0xbe, 0x22, 0xd8, 0xa6, 0x26,
-	0xf7, 0xab, 0xa4, 0x36, 0xab, 0xa4, 0xb6, 0xa0, 0xe5, 0xc5, 0x91, 0xc4, 0x48, 0xaa, 0xa3, 0xe9,
-	0xd2, 0xdc, 0x74, 0x7a, 0xd0, 0x52, 0x6d, 0x2e, 0xfd, 0x97, 0x4d, 0x9c, 0x09, 0x74, 0x35, 0xc9,
-	0x59, 0x18, 0xb2, 0xe4, 0x71, 0x89, 0x04, 0x81, 0xba, 0x3a, 0x43, 0xdd, 0x55, 0xbd, 0xab, 0x88,
-	0xd5, 0x2a, 0x88, 0x39, 0x5f, 0x4d, 0xd8, 0x56, 0x4d, 0x28, 0xca, 0x84, 0xe3, 0x03, 0x0b, 0xfe,
-	0xb6, 0x3a, 0xef, 0x33, 0x75, 0x46, 0xa5, 0x3a, 0x67, 0x15, 0xea, 0x14, 0x9c, 0x96, 0x14, 0x4a,
-	0x9f, 0xf6, 0xbb, 0x75, 0x0a, 0xad, 0x5a, 0xce, 0x01, 0x34, 0xe3, 0xbb, 0x3b, 0x81, 0x32, 0xdb,
-	0x47, 0x66, 0x39, 0x23, 0xd8, 0x7f, 0xde, 0xcf, 0x95, 0x09, 0xb2, 0xb0, 0xc0, 0x30, 0x16, 0x30,
-	0x16, 0x94, 0x34, 0x9f, 0x2b, 0x79, 0x04, 0x1d, 0x4d, 0x07, 0x03, 0x94, 0xb8, 0xa4, 0xe6, 0x10,
-	0xc8, 0x42, 0x38, 0xd7, 0xd4, 0x82, 0x56, 0x88, 0x42, 0xb0, 0x7b, 0xcc, 0x52, 0x73, 0xd3, 0x71,
-	0x61, 0xb7, 0x3c, 0xd1, 0x57, 0xd3, 0xc9, 0x09, 0x6c, 0xa9, 0xdf, 0x1a, 0x45, 0x0f, 0xf9, 0x03,
-	0xfa, 0xd9, 0xe0, 0xcf, 0x9d, 0x0e, 0xc0, 0xa6, 0x2b, 0x99, 0x14, 0x14, 0x3f, 0x3b, 0x2e, 0x74,
-	0xd2, 0x77, 0x0e, 0x7d, 0x08, 0xed, 0x99, 0x40, 0xdf, 0x9d, 0x32, 0x2f, 0x9f, 0xb8, 0x74, 0x90,
-	0x53, 0xd8, 0x66, 0x0f, 0x8c, 0x07, 0x6c, 0x12, 0xa0, 0x4e, 0xd1, 0xf8, 0x2f, 0xbc, 0xe7, 0xdf,
-	0x6b, 0xb0, 0x53, 0xd2, 0xa6, 0x4a, 0x4c, 0x32, 0x82, 0x86, 0xf2, 0x91, 0x5e, 0x85, 0xd0, 0x97,
-	0xbe, 0xfd, 0x6f, 0xd5, 0x2f, 0x54, 0xd3, 0x73, 0x36, 0xc8, 0x2d, 0x6c, 0x66, 0x02, 0x21, 0xe9,
-	0xbf, 0x76, 0x31, 0xf6, 0xe9, 0x6b, 0x19, 0x5a, 0x63, 0x67, 0x63, 0x60, 0xfc, 0x6f, 0x90, 0x2b,
-	0x68, 0xe8, 0x4f, 0xd3, 0xe1, 0xba, 0x0f, 0x85, 0x7d, 0xbc, 0x2e, 0x5a, 0x30, 0x1d, 0x18, 0xe4,
-	0x03, 0x34, 0xb3, 0x33, 0x38, 0xaa, 0x28, 0xd1, 0x61, 0xfb, 0x64, 0x6d, 0xb8, 0x1c, 0x7e, 0x94,
-	0x12, 0x64, 0x52, 0x10, 0x7b, 0xb9, 0x20, 0x57, 0xd4, 0x3e, 0x5a, 0x1d, 0x2b, 0x50, 0x2e, 0xea,
-	0xb7, 0xe6, 0x74, 0x32, 0x69, 0xaa, 0x7f, 0xd8, 0x37, 0xbf, 0x03, 0x00, 0x00, 0xff, 0xff, 0xa4,
-	0x5e, 0x53, 0x96, 0x75, 0x07, 0x00, 0x00,
+func init() { proto.RegisterFile("piecestore.proto", fileDescriptor_piecestore_4c543755608a6e7f) }
+
+var fileDescriptor_piecestore_4c543755608a6e7f = []byte{
+	// 1607 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb5, 0x18, 0xcb, 0x72, 0xdb, 0x54,
+	0x34, 0xf2, 0xdb, 0xc7, 0x4e, 0x6c, 0xdf, 0x16, 0xc6, 0x71, 0x9b, 0x36, 0x55, 0x4a, 0x09, 0x2d,
+	0x93, 0x69, 0xd3, 0x25, 0xc3, 0x0c, 0x69, 0x1d, 0xda, 0x40, 0x48, 0x33, 0x72, 0x52, 0x86, 0x32,
+	0x8c, 0xe7, 0xc6, 0xba, 0x49, 0xc4, 0xc8, 0x92, 0x91, 0xe4, 0xb4, 0x65, 0x58, 0xb1, 0x67, 0x58,
+	0xb1, 0x67, 0x86, 0x8f, 0x60, 0x5d, 0x96, 0x7c, 0x07, 0x0b, 0x3e, 0x83, 0x73, 0x5f, 0x92, 0x1c,
+	0x4b, 0x49, 0x17, 0x65, 0xa7, 0xf3, 0xb8, 0xe7, 0xfd, 0xb2, 0xa1, 0x3d, 0x71, 0xd8, 0x88, 0x85,
+	0x91, 0x1f, 0xb0, 0x8d, 0x49, 0xe0, 0x47, 0x3e, 0x49, 0x61, 0x02, 0x7f, 0x1a, 0xb1, 0xb0, 0xb7,
+	0xe8, 0x9f, 0xb1, 0xc0, 0xa5, 0xaf, 0x25, 0x83, 0xf9, 0x7b, 0x09, 0xba, 0xfb, 0xf4, 0x35, 0x0b,
+	0x1e, 0x51, 0xcf, 0x7e, 0xe9, 0xd8, 0xd1, 0xe9, 0x96, 0xeb, 0xfa, 0x23, 0x1a, 0x39, 0xbe, 0x47,
+	0xae, 0x43, 0x3d, 0x74, 0x4e, 0x3c, 0x1a, 0x4d, 0x03, 0xd6, 0x35, 0x56, 0x8d, 0xf5, 0xa6, 0x95,
+	0x20, 0x08, 0x81, 0x92, 0x4d, 0x23, 0xda, 0x2d, 0x08, 0x82, 0xf8, 0x26, 0x57, 0xa1, 0x3c, 0x62,
+	0x41, 0x14, 0x76, 0x8b, 0xab, 0x45, 0x44, 0x4a, 0xa0, 0xf7, 0x4f, 0x01, 0x4a, 0x7d, 0x45, 0x9e,
+	0x70, 0x65, 0x4a, 0x98, 0x04, 0xc8, 0xfb, 0x50, 0x09, 0x98, 0x17, 0x21, 0x5a, 0x8a, 0x52, 0x10,
+	0x59, 0x86, 0xda, 0x98, 0xbe, 0x1a, 0x86, 0xce, 0x8f, 0x0c, 0xe5, 0x19, 0xeb, 0x45, 0xab, 0x8a,
+	0xf0, 0x00, 0x41, 0xb2, 0x01, 0x57, 0xd8, 0xab, 0x89, 0x13, 0x08, 0x3b, 0x87, 0x53, 0xcf, 0x41,
+	0x36, 0x36, 0xea, 0x96, 0x04, 0x57, 0x27, 0x21, 0x1d, 0x22, 0x65, 0xc0, 0x46, 0x64, 0x0d, 0x16,
+	0x43, 0x16, 0x38, 0xd4, 0x1d, 0x7a, 0xd3, 0xf1, 0x11, 0x6a, 0x2a, 0x23, 0x67, 0xdd, 0x6a, 0x4a,
+	0xe4, 0x9e, 0xc0, 0x91, 0x1d, 0xa8, 0xd0, 0x11, 0x7f, 0xd5, 0xad, 0x20, 0x75, 0x69, 0xf3, 0xc1,
+	0xc6, 0xf9, 0xe8, 0x6d, 0xe4, 0x85, 0x6a, 0x63, 0x4b, 0x3c, 0xb4, 0x94, 0x00, 0x6e, 0xba, 0x78,
+	0x3b, 0x74, 0xec, 0x6e, 0x55, 0xa8, 0xaa, 0x0a, 0x78, 0xc7, 0x26, 0x77, 0xa0, 0xc5, 0x25, 0xd2,
+	0x13, 0x36, 0xf4, 0x7c, 0x5b, 0x70, 0xd4, 0x84, 0xdb, 0x8b, 0x0a, 0xbd, 0x87, 0x58, 0xe4, 0xbb,
+	0x0f, 0x57, 0x67, 0xf8, 0xa8, 0x6d, 0x07, 0x2c, 0x0c, 0xbb, 0x75, 0x21, 0x8e, 0xa4, 0x98, 0xb7,
+	0x24, 0xc5, 0x3c, 0x84, 0x8a, 0x34, 0x83, 0x54, 0xa1, 0xb8, 0x7f, 0x78, 0xd0, 0x5e, 0xe0, 0x1f,
+	0x4f, 0xb6, 0x0f, 0xda, 0x06, 0x59, 0x02, 0x40, 0xcc, 0xd0, 0xda, 0xde, 0xdf, 0xda, 0xb1, 0xda,
+	0x05, 0x0e, 0x23, 0x41, 0xc3, 0x45, 0xb2, 0x08, 0x75, 0x0e, 0x6f, 0x1d, 0xf6, 0x77, 0x0e, 0xda,
+	0x25, 0x02, 0x50, 0xe9, 0x6f, 0xef, 0x6e, 0x1f, 0x6c, 0xb7, 0xcb, 0xe6, 0x5f, 0x06, 0x2c, 0x5b,
+	0x22, 0x23, 0xef, 0xa4, 0x46, 0x7a, 0xa1, 0x2a, 0x86, 0x43, 0x68, 0x8b, 0xfc, 0x0f, 0x69, 0x2c,
+	0x4d, 0x08, 0x68, 0x6c, 0xde, 0x7d, 0xfb, 0xc0, 0x5b, 0x2d, 0x21, 0x23, 0x65, 0x10, 0xd6, 0x58,
+	0xe4, 0x47, 0xd4, 0x15, 0x3a, 0x8b, 0x96, 0x04, 0xcc, 0x37, 0x05, 0x0c, 0x00, 0x17, 0x3a, 0xe0,
+	0x42, 0xc9, 0x77, 0x70, 0xe5, 0x48, 0x0b, 0x9b, 0x53, 0x7f, 0x6f, 0x5e, 0x7d, 0xae, 0xff, 0x56,
+	0x96, 0x1c, 0xd2, 0x87, 0xba, 0x10, 0x11, 0xfb, 0xde, 0xd8, 0xbc, 0x93, 0xe1, 0x53, 0x6c, 0x8f,
+	0xfc, 0xe4, 0x51, 0xb1, 0x92, 0x87, 0xbd, 0x5f, 0x0c, 0xa8, 0xc7, 0x04, 0xcc, 0x58, 0x01, 0x4b,
+	0xc5, 0x10, 0xd9, 0xc7, 0xaf, 0xbc, 0x16, 0x28, 0xe4, 0xb5, 0x40, 0x17, 0xaa, 0x23, 0x1f, 0xbd,
+	0xf0, 0x22, 0xd1, 0x4c, 0x4d, 0x4b, 0x83, 0xbc, 0x22, 0xd9, 0x2b, 0x27, 0x72, 0xbc, 0x93, 0xb8,
+	0x22, 0x4b, 0xb2, 0x22, 0x15, 0x5a, 0x56, 0xa4, 0xb9, 0x0c, 0xd5, 0x7d, 0x55, 0xc4, 0xe7, 0x8c,
+	0x31, 0x8f, 0xa0, 0x29, 0xbd, 0x99, 0x8e, 0xc7, 0x34, 0x78, 0x3d, 0x67, 0x2c, 0xd6, 0x81, 0x68,
+	0x63, 0x69, 0x9d, 0xf8, 0xce, 0x73, 0xa0, 0x98, 0xe3, 0x80, 0xf9, 0x73, 0x01, 0x96, 0x84, 0x12,
+	0x8b, 0x45, 0x81, 0xc3, 0xce, 0xa8, 0xfb, 0x7f, 0xa7, 0xf1, 0xa9, 0x4a, 0x63, 0x3f, 0x49, 0xe3,
+	0xdd, 0x9c, 0x34, 0xc6, 0x36, 0xcd, 0xa5, 0x92, 0x7f, 0xf6, 0x9e, 0x5c, 0x94, 0xc9, 0xac, 0xe0,
+	0xe0, 0x4c, 0xf4, 0x8f, 0x8f, 0x43, 0x16, 0xa9, 0x78, 0x28, 0xc8, 0xec, 0xc3, 0xd5, 0x59, 0x7d,
+	0x83, 0x28, 0x60, 0x74, 0x1c, 0xcb, 0x30, 0x52, 0x32, 0x52, 0x19, 0x2f, 0xcc, 0x64, 0xdc, 0xfc,
+	0x1e, 0x1a, 0xd2, 0x1c, 0xe6, 0xb2, 0x88, 0xcd, 0x19, 0xf4, 0x25, 0x34, 0xfc, 0xc0, 0xc6, 0xce,
+	0x74, 0x9d, 0xb1, 0x13, 0x5d, 0xe0, 0x79, 0x5e, 0x53, 0x82, 0x78, 0xbe, 0xcb, 0x5f, 0x9b, 0x1b,
+	0x40, 0x52, 0xba, 0x74, 0x81, 0xa0, 0x6d, 0x63, 0x9c, 0x59, 0x38, 0xc1, 0x94, 0x5e, 0x0d, 0x9a,
+	0xbf, 0x19, 0xd0, 0x49, 0x3a, 0xe3, 0x52, 0x7e, 0x72, 0x1b, 0x16, 0x45, 0x8b, 0x5b, 0xf8, 0xc4,
+	0x39, 0x63, 0xb6, 0x0a, 0xe3, 0x2c, 0x92, 0x7c, 0x06, 0xd5, 0x80, 0x7f, 0x4f, 0x64, 0x40, 0xf3,
+	0xfb, 0xf1, 0x20, 0xa0, 0x5e, 0x78, 0xcc, 0x02, 0x4b, 0x72, 0x5b, 0xfa, 0x99, 0xf9, 0x47, 0x41,
+	0x85, 0xfe, 0x1c, 0xc7, 0x3b, 0xdb, 0x92, 0x38, 0x67, 0xe5, 0x60, 0xcc, 0xe8, 0x47, 0x23, 0xa3,
+	0x1f, 0xc9, 0x5d, 0xe8, 0x08, 0xe3, 0xce, 0xd2, 0x9c, 0x52, 0x4f, 0x2b, 0x26, 0x28, 0xde, 0xf4,
+	0x42, 0x2a, 0xce, 0x2e, 0xa4, 0x15, 0x00, 0x49, 0x3a, 0xa5, 0xe1, 0xa9, 0xea, 0x7c, 0x59, 0xba,
+	0x4f, 0x11, 0x41, 0x3e, 0x06, 0x12, 0x39, 0x18, 0xec, 0x88, 0x8e, 0x27, 0x49, 0x97, 0x96, 0x45,
+	0x90, 0xdb, 0x31, 0x45, 0x37, 0xe9, 0x4f, 0x50, 0x1b, 0x44, 0x34, 0x0a, 0x2d, 0xf6, 0x03, 0xf9,
+	0x04, 0xaa, 0x67, 0x2c, 0xe2, 0x06, 0xab, 0x8e, 0xbc, 0x35, 0x1f, 0xf3, 0xe7, 0x92, 0x61, 0x3f,
+	0xf0, 0x4f, 0xf8, 0x0e, 0xb3, 0xf4, 0x0b, 0xf2, 0x10, 0xca, 0x74, 0x6a, 0xc7, 0xd5, 0xb7, 0x32,
+	0xff, 0x74, 0x8b, 0x93, 0xb9, 0xb2, 0x69, 0x68, 0x49, 0x5e, 0xf3, 0x8d, 0x01, 0xad, 0x73, 0x12,
+	0xc9, 0x4d, 0x68, 0x08, 0xe2, 0x70, 0xe4, 0x4f, 0xb1, 0x13, 0x64, 0x83, 0x80, 0x40, 0x3d, 0xe6,
+	0x18, 0xf2, 0x21, 0xb4, 0x24, 0x43, 0x74, 0x8a, 0x0f, 0x4e, 0x7d, 0x57, 0x97, 0xd0, 0x92, 0x40,
+	0x1f, 0x68, 0x2c, 0xb9, 0x05, 0xcd, 0xe9, 0x84, 0x7b, 0xac, 0x44, 0xc9, 0xce, 0x6c, 0x48, 0x9c,
+	0x94, 0xf5, 0x11, 0xb4, 0x15, 0x4b, 0x22, 0x4c, 0x1e, 0x25, 0x2d, 0x89, 0x4f, 0xa4, 0x61, 0x87,
+	0x73, 0x5f, 0xb1, 0x60, 0x79, 0x2c, 0x6b, 0x96, 0x82, 0xcc, 0xbf, 0x0d, 0x68, 0xa4, 0x5c, 0xbb,
+	0xdc, 0x7e, 0x9c, 0xa3, 0x92, 0x21, 0x9c, 0x8e, 0x30, 0x42, 0xa1, 0x62, 0x54, 0x8b, 0x40, 0x90,
+	0x06, 0x92, 0x22, 0xf9, 0xf1, 0x16, 0x1a, 0x3b, 0x61, 0xc8, 0xec, 0xa1, 0xa0, 0x85, 0xca, 0x8f,
+	0xa6, 0x44, 0x0a, 0xd5, 0x21, 0xb9, 0x07, 0x1d, 0x2d, 0x6e, 0xd6, 0x13, 0xc3, 0x6a, 0x2b, 0x42,
+	0xe2, 0x0a, 0x36, 0xe7, 0x31, 0x75, 0x5c, 0x9e, 0x68, 0xe9, 0x8b, 0x06, 0xcd, 0x5f, 0x0b, 0xd0,
+	0xe0, 0x7e, 0xe8, 0x36, 0xc6, 0x5e, 0x99, 0xa2, 0x92, 0xc1, 0x84, 0x8e, 0xf4, 0xac, 0x4a, 0x10,
+	0x58, 0xf8, 0x4b, 0xf4, 0x0c, 0x5f, 0xd2, 0x23, 0x97, 0x49, 0x16, 0x9d, 0x88, 0x19, 0x2c, 0x59,
+	0x85, 0x06, 0x0a, 0xe7, 0xd9, 0xfd, 0x7c, 0xea, 0xba, 0xc2, 0xfe, 0x9a, 0x95, 0x46, 0x91, 0x1b,
+	0x00, 0x2c, 0x61, 0x28, 0x09, 0x86, 0x14, 0x86, 0x3c, 0x80, 0x9a, 0x3f, 0x61, 0xb8, 0x5f, 0x7c,
+	0x79, 0x0a, 0x36, 0x36, 0xdf, 0xdb, 0xd0, 0x87, 0x31, 0xef, 0x98, 0x67, 0x8a, 0x68, 0xc5, 0x6c,
+	0x64, 0x1b, 0x1a, 0x63, 0xea, 0xf0, 0xf9, 0x49, 0x3d, 0xb4, 0xac, 0x22, 0x5e, 0xad, 0xcd, 0x97,
+	0xe5, 0x57, 0x09, 0xd3, 0xd7, 0x8e, 0x67, 0xfb, 0x2f, 0xad, 0xf4, 0x3b, 0xf3, 0x5b, 0xe8, 0xcc,
+	0x71, 0xe0, 0x0c, 0x5b, 0xc2, 0x2e, 0x0a, 0xa2, 0xa4, 0xbf, 0x64, 0x6c, 0x9a, 0x02, 0xab, 0x37,
+	0xf8, 0x2a, 0x34, 0x99, 0x67, 0x9f, 0x5f, 0xf5, 0x80, 0x38, 0xdd, 0x7d, 0x03, 0x58, 0xc4, 0xc5,
+	0x80, 0xe2, 0xb1, 0xfd, 0xa6, 0x68, 0x15, 0x1f, 0x11, 0x23, 0xdc, 0x0f, 0xb3, 0x1b, 0x56, 0xca,
+	0x6e, 0x69, 0x82, 0x16, 0x8f, 0x05, 0x79, 0xec, 0xb8, 0xa9, 0x33, 0x5c, 0x42, 0xe6, 0xb6, 0x16,
+	0xaa, 0x93, 0xd8, 0x83, 0x5a, 0x20, 0x10, 0xcc, 0x56, 0xb2, 0x62, 0x98, 0x97, 0x82, 0x2d, 0x06,
+	0xbd, 0x6e, 0x22, 0x0d, 0x9a, 0x1f, 0x40, 0x87, 0x57, 0x82, 0x18, 0xa1, 0xa1, 0xb6, 0xaf, 0x0d,
+	0x45, 0xc7, 0x0e, 0x51, 0x4a, 0x11, 0x27, 0x12, 0xff, 0x34, 0xff, 0xd4, 0x47, 0x0f, 0x67, 0x9e,
+	0xdb, 0x4c, 0x68, 0x23, 0xce, 0xc0, 0x10, 0x8b, 0xb6, 0x20, 0x9b, 0x46, 0x42, 0xf1, 0xfa, 0x2b,
+	0xa6, 0xd6, 0x5f, 0xa6, 0xef, 0xa5, 0x6c, 0xdf, 0x73, 0x6e, 0x91, 0x72, 0xde, 0x31, 0x85, 0xfa,
+	0xc4, 0xb4, 0xac, 0xc8, 0xa9, 0xce, 0xbf, 0xcd, 0x1d, 0x20, 0x69, 0x07, 0xc3, 0x89, 0xef, 0x85,
+	0x0c, 0xe7, 0x58, 0x45, 0x96, 0x88, 0x70, 0xb2, 0xb1, 0x79, 0x2d, 0xf7, 0x0e, 0xa4, 0x91, 0xa5,
+	0x58, 0xcd, 0x2d, 0x68, 0x3f, 0xe6, 0x87, 0x08, 0xf3, 0x4e, 0x98, 0x0e, 0x55, 0x7a, 0x82, 0x1b,
+	0xb3, 0x13, 0x1c, 0xad, 0x71, 0x19, 0x3d, 0xd6, 0x07, 0x04, 0xff, 0x36, 0x3f, 0x85, 0x4e, 0x4a,
+	0x84, 0x32, 0x46, 0x33, 0xca, 0x75, 0x22, 0xbe, 0xc5, 0x6f, 0xb2, 0xc0, 0xf7, 0xf9, 0x6b, 0xb1,
+	0x8c, 0x04, 0x60, 0x32, 0x3c, 0xe8, 0xf8, 0xc7, 0x5b, 0x68, 0xc7, 0xfd, 0x71, 0xec, 0x04, 0x61,
+	0x34, 0x4c, 0xd9, 0x50, 0x17, 0x98, 0x5d, 0x2e, 0xff, 0x1a, 0xd4, 0x5d, 0xaa, 0xa9, 0x32, 0x3f,
+	0x35, 0x8e, 0xd8, 0x95, 0x56, 0x2e, 0x2a, 0x35, 0xca, 0x42, 0x4c, 0x30, 0x32, 0x9e, 0xa9, 0x70,
+	0x61, 0x11, 0x4a, 0x28, 0xdb, 0xca, 0xcd, 0x7f, 0xcb, 0xd0, 0x4e, 0x6e, 0x05, 0x4b, 0x84, 0x13,
+	0x8f, 0xef, 0xb2, 0xc0, 0x91, 0xe5, 0x9c, 0x50, 0xef, 0xd8, 0xbd, 0x1b, 0x79, 0x59, 0x90, 0x25,
+	0x6e, 0x2e, 0x90, 0x17, 0x50, 0x53, 0x37, 0x16, 0xce, 0x9b, 0xcb, 0x8e, 0xbe, 0xde, 0x9d, 0xcb,
+	0x38, 0xe4, 0x99, 0x66, 0x2e, 0xac, 0x1b, 0xf7, 0x0d, 0xb2, 0x07, 0x65, 0xf9, 0x33, 0xe4, 0xfa,
+	0x45, 0x3f, 0x0a, 0x7a, 0x6b, 0x17, 0x51, 0x63, 0x4b, 0xd7, 0x0d, 0xf2, 0x0c, 0x7f, 0xad, 0xc9,
+	0x4b, 0x6e, 0x25, 0xe7, 0x89, 0x24, 0xf7, 0x6e, 0x5f, 0x48, 0x4e, 0x9c, 0xef, 0x73, 0x03, 0x71,
+	0x8b, 0x93, 0xde, 0xfc, 0x03, 0xbd, 0xde, 0x7b, 0x2b, 0xd9, 0xb4, 0x44, 0xca, 0x2e, 0x54, 0xe4,
+	0xe0, 0x20, 0x37, 0xb3, 0x4e, 0xf1, 0xd4, 0x9c, 0xea, 0xe5, 0x32, 0x24, 0xd2, 0xbe, 0x01, 0x48,
+	0xda, 0x8b, 0xac, 0x65, 0x2b, 0x9f, 0x99, 0x2e, 0x59, 0xee, 0xce, 0x77, 0x28, 0x8a, 0x7e, 0x0e,
+	0xf5, 0xb8, 0x57, 0x88, 0x39, 0xff, 0xe8, 0x7c, 0x2f, 0x66, 0x65, 0x66, 0xae, 0xd9, 0x50, 0xee,
+	0x17, 0x58, 0x89, 0xbc, 0x4e, 0x49, 0x56, 0xb9, 0xa5, 0xba, 0x2b, 0xcb, 0xfd, 0x99, 0xb6, 0x30,
+	0x17, 0x1e, 0x95, 0x5e, 0x14, 0x26, 0x47, 0x47, 0x15, 0xf1, 0xaf, 0xcd, 0xc3, 0xff, 0x00, 0x64,
+	0xee, 0xec, 0x91, 0xea, 0x11, 0x00, 0x00,
 }
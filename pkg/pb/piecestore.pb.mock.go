@@ -39,6 +39,24 @@ func (m *MockPieceStoreRoutesClient) EXPECT() *MockPieceStoreRoutesClientMockRec
 	return m.recorder
 }
 
+// Challenge mocks base method
+func (m *MockPieceStoreRoutesClient) Challenge(arg0 context.Context, arg1 *ChallengeRequest, arg2 ...grpc.CallOption) (*ChallengeResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Challenge", varargs...)
+	ret0, _ := ret[0].(*ChallengeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Challenge indicates an expected call of Challenge
+func (mr *MockPieceStoreRoutesClientMockRecorder) Challenge(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Challenge", reflect.TypeOf((*MockPieceStoreRoutesClient)(nil).Challenge), varargs...)
+}
+
 // Delete mocks base method
 func (m *MockPieceStoreRoutesClient) Delete(arg0 context.Context, arg1 *PieceDelete, arg2 ...grpc.CallOption) (*PieceDeleteSummary, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -75,6 +93,42 @@ func (mr *MockPieceStoreRoutesClientMockRecorder) Piece(arg0, arg1 interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Piece", reflect.TypeOf((*MockPieceStoreRoutesClient)(nil).Piece), varargs...)
 }
 
+// Proof mocks base method
+func (m *MockPieceStoreRoutesClient) Proof(arg0 context.Context, arg1 *ProofRequest, arg2 ...grpc.CallOption) (*ProofResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Proof", varargs...)
+	ret0, _ := ret[0].(*ProofResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Proof indicates an expected call of Proof
+func (mr *MockPieceStoreRoutesClientMockRecorder) Proof(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Proof", reflect.TypeOf((*MockPieceStoreRoutesClient)(nil).Proof), varargs...)
+}
+
+// Retain mocks base method
+func (m *MockPieceStoreRoutesClient) Retain(arg0 context.Context, arg1 *RetainRequest, arg2 ...grpc.CallOption) (*RetainSummary, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Retain", varargs...)
+	ret0, _ := ret[0].(*RetainSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retain indicates an expected call of Retain
+func (mr *MockPieceStoreRoutesClientMockRecorder) Retain(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retain", reflect.TypeOf((*MockPieceStoreRoutesClient)(nil).Retain), varargs...)
+}
+
 // Retrieve mocks base method
 func (m *MockPieceStoreRoutesClient) Retrieve(arg0 context.Context, arg1 ...grpc.CallOption) (PieceStoreRoutes_RetrieveClient, error) {
 	varargs := []interface{}{arg0}
@@ -93,6 +147,24 @@ func (mr *MockPieceStoreRoutesClientMockRecorder) Retrieve(arg0 interface{}, arg
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retrieve", reflect.TypeOf((*MockPieceStoreRoutesClient)(nil).Retrieve), varargs...)
 }
 
+// StatPieces mocks base method
+func (m *MockPieceStoreRoutesClient) StatPieces(arg0 context.Context, arg1 *StatPiecesRequest, arg2 ...grpc.CallOption) (*StatPiecesResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StatPieces", varargs...)
+	ret0, _ := ret[0].(*StatPiecesResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatPieces indicates an expected call of StatPieces
+func (mr *MockPieceStoreRoutesClientMockRecorder) StatPieces(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatPieces", reflect.TypeOf((*MockPieceStoreRoutesClient)(nil).StatPieces), varargs...)
+}
+
 // Stats mocks base method
 func (m *MockPieceStoreRoutesClient) Stats(arg0 context.Context, arg1 *StatsReq, arg2 ...grpc.CallOption) (*StatSummary, error) {
 	varargs := []interface{}{arg0, arg1}
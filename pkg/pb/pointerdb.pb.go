@@ -41,7 +41,7 @@ func (x RedundancyScheme_SchemeType) String() string {
 	return proto.EnumName(RedundancyScheme_SchemeType_name, int32(x))
 }
 func (RedundancyScheme_SchemeType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{0, 0}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{0, 0}
 }
 
 type EncryptionScheme_EncryptionType int32
@@ -49,22 +49,25 @@ type EncryptionScheme_EncryptionType int32
 const (
 	EncryptionScheme_AESGCM    EncryptionScheme_EncryptionType = 0
 	EncryptionScheme_SECRETBOX EncryptionScheme_EncryptionType = 1
+	EncryptionScheme_AESGCMSIV EncryptionScheme_EncryptionType = 2
 )
 
 var EncryptionScheme_EncryptionType_name = map[int32]string{
 	0: "AESGCM",
 	1: "SECRETBOX",
+	2: "AESGCMSIV",
 }
 var EncryptionScheme_EncryptionType_value = map[string]int32{
 	"AESGCM":    0,
 	"SECRETBOX": 1,
+	"AESGCMSIV": 2,
 }
 
 func (x EncryptionScheme_EncryptionType) String() string {
 	return proto.EnumName(EncryptionScheme_EncryptionType_name, int32(x))
 }
 func (EncryptionScheme_EncryptionType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{1, 0}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{1, 0}
 }
 
 type Pointer_DataType int32
@@ -87,17 +90,20 @@ func (x Pointer_DataType) String() string {
 	return proto.EnumName(Pointer_DataType_name, int32(x))
 }
 func (Pointer_DataType) EnumDescriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{4, 0}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{4, 0}
 }
 
 type RedundancyScheme struct {
 	Type RedundancyScheme_SchemeType `protobuf:"varint,1,opt,name=type,proto3,enum=pointerdb.RedundancyScheme_SchemeType" json:"type,omitempty"`
 	// these values apply to RS encoding
-	MinReq               int32    `protobuf:"varint,2,opt,name=min_req,json=minReq,proto3" json:"min_req,omitempty"`
-	Total                int32    `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
-	RepairThreshold      int32    `protobuf:"varint,4,opt,name=repair_threshold,json=repairThreshold,proto3" json:"repair_threshold,omitempty"`
-	SuccessThreshold     int32    `protobuf:"varint,5,opt,name=success_threshold,json=successThreshold,proto3" json:"success_threshold,omitempty"`
-	ErasureShareSize     int32    `protobuf:"varint,6,opt,name=erasure_share_size,json=erasureShareSize,proto3" json:"erasure_share_size,omitempty"`
+	MinReq           int32 `protobuf:"varint,2,opt,name=min_req,json=minReq,proto3" json:"min_req,omitempty"`
+	Total            int32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	RepairThreshold  int32 `protobuf:"varint,4,opt,name=repair_threshold,json=repairThreshold,proto3" json:"repair_threshold,omitempty"`
+	SuccessThreshold int32 `protobuf:"varint,5,opt,name=success_threshold,json=successThreshold,proto3" json:"success_threshold,omitempty"`
+	ErasureShareSize int32 `protobuf:"varint,6,opt,name=erasure_share_size,json=erasureShareSize,proto3" json:"erasure_share_size,omitempty"`
+	// whether the erasure shares are followed by a MAC bound to their segment,
+	// stripe and share number. erasure_share_size doesn't include the MAC.
+	ShareMacs            bool     `protobuf:"varint,7,opt,name=share_macs,json=shareMacs,proto3" json:"share_macs,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -107,7 +113,7 @@ func (m *RedundancyScheme) Reset()         { *m = RedundancyScheme{} }
 func (m *RedundancyScheme) String() string { return proto.CompactTextString(m) }
 func (*RedundancyScheme) ProtoMessage()    {}
 func (*RedundancyScheme) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{0}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{0}
 }
 func (m *RedundancyScheme) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RedundancyScheme.Unmarshal(m, b)
@@ -169,6 +175,13 @@ func (m *RedundancyScheme) GetErasureShareSize() int32 {
 	return 0
 }
 
+func (m *RedundancyScheme) GetShareMacs() bool {
+	if m != nil {
+		return m.ShareMacs
+	}
+	return false
+}
+
 type EncryptionScheme struct {
 	Type                   EncryptionScheme_EncryptionType `protobuf:"varint,1,opt,name=type,proto3,enum=pointerdb.EncryptionScheme_EncryptionType" json:"type,omitempty"`
 	EncryptedEncryptionKey []byte                          `protobuf:"bytes,2,opt,name=encrypted_encryption_key,json=encryptedEncryptionKey,proto3" json:"encrypted_encryption_key,omitempty"`
@@ -182,7 +195,7 @@ func (m *EncryptionScheme) Reset()         { *m = EncryptionScheme{} }
 func (m *EncryptionScheme) String() string { return proto.CompactTextString(m) }
 func (*EncryptionScheme) ProtoMessage()    {}
 func (*EncryptionScheme) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{1}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{1}
 }
 func (m *EncryptionScheme) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_EncryptionScheme.Unmarshal(m, b)
@@ -224,8 +237,12 @@ func (m *EncryptionScheme) GetEncryptedStartingNonce() []byte {
 }
 
 type RemotePiece struct {
-	PieceNum             int32    `protobuf:"varint,1,opt,name=piece_num,json=pieceNum,proto3" json:"piece_num,omitempty"`
-	NodeId               string   `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	PieceNum int32  `protobuf:"varint,1,opt,name=piece_num,json=pieceNum,proto3" json:"piece_num,omitempty"`
+	NodeId   string `protobuf:"bytes,2,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	// merkle_root is the root of the Merkle tree of the piece, which
+	// downloads of the piece are verified and nodes are challenged against.
+	// Empty for pieces uploaded without it.
+	MerkleRoot           []byte   `protobuf:"bytes,3,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -235,7 +252,7 @@ func (m *RemotePiece) Reset()         { *m = RemotePiece{} }
 func (m *RemotePiece) String() string { return proto.CompactTextString(m) }
 func (*RemotePiece) ProtoMessage()    {}
 func (*RemotePiece) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{2}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{2}
 }
 func (m *RemotePiece) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemotePiece.Unmarshal(m, b)
@@ -269,6 +286,13 @@ func (m *RemotePiece) GetNodeId() string {
 	return ""
 }
 
+func (m *RemotePiece) GetMerkleRoot() []byte {
+	if m != nil {
+		return m.MerkleRoot
+	}
+	return nil
+}
+
 type RemoteSegment struct {
 	Redundancy           *RedundancyScheme `protobuf:"bytes,1,opt,name=redundancy,proto3" json:"redundancy,omitempty"`
 	PieceId              string            `protobuf:"bytes,2,opt,name=piece_id,json=pieceId,proto3" json:"piece_id,omitempty"`
@@ -283,7 +307,7 @@ func (m *RemoteSegment) Reset()         { *m = RemoteSegment{} }
 func (m *RemoteSegment) String() string { return proto.CompactTextString(m) }
 func (*RemoteSegment) ProtoMessage()    {}
 func (*RemoteSegment) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{3}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{3}
 }
 func (m *RemoteSegment) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_RemoteSegment.Unmarshal(m, b)
@@ -332,23 +356,33 @@ func (m *RemoteSegment) GetMerkleRoot() []byte {
 }
 
 type Pointer struct {
-	Type                 Pointer_DataType     `protobuf:"varint,1,opt,name=type,proto3,enum=pointerdb.Pointer_DataType" json:"type,omitempty"`
-	InlineSegment        []byte               `protobuf:"bytes,3,opt,name=inline_segment,json=inlineSegment,proto3" json:"inline_segment,omitempty"`
-	Remote               *RemoteSegment       `protobuf:"bytes,4,opt,name=remote,proto3" json:"remote,omitempty"`
-	Size                 int64                `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
-	CreationDate         *timestamp.Timestamp `protobuf:"bytes,6,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
-	ExpirationDate       *timestamp.Timestamp `protobuf:"bytes,7,opt,name=expiration_date,json=expirationDate,proto3" json:"expiration_date,omitempty"`
-	Metadata             []byte               `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Type           Pointer_DataType     `protobuf:"varint,1,opt,name=type,proto3,enum=pointerdb.Pointer_DataType" json:"type,omitempty"`
+	InlineSegment  []byte               `protobuf:"bytes,3,opt,name=inline_segment,json=inlineSegment,proto3" json:"inline_segment,omitempty"`
+	Remote         *RemoteSegment       `protobuf:"bytes,4,opt,name=remote,proto3" json:"remote,omitempty"`
+	Size           int64                `protobuf:"varint,5,opt,name=size,proto3" json:"size,omitempty"`
+	CreationDate   *timestamp.Timestamp `protobuf:"bytes,6,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
+	ExpirationDate *timestamp.Timestamp `protobuf:"bytes,7,opt,name=expiration_date,json=expirationDate,proto3" json:"expiration_date,omitempty"`
+	Metadata       []byte               `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// version is the version of the pointer format. Pointers stored before
+	// the format was versioned have version 0.
+	Version int32 `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	// suite is the id of the encryption and erasure suite of the segment, as
+	// registered in eestream. Pointers stored before suites were recorded
+	// have suite 0.
+	Suite int32 `protobuf:"varint,10,opt,name=suite,proto3" json:"suite,omitempty"`
+	// project_id is the project of the API key that created the bucket, for
+	// the pointers of buckets. It's set by pointerdb.
+	ProjectId            string   `protobuf:"bytes,11,opt,name=project_id,json=projectId,proto3" json:"project_id,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Pointer) Reset()         { *m = Pointer{} }
 func (m *Pointer) String() string { return proto.CompactTextString(m) }
 func (*Pointer) ProtoMessage()    {}
 func (*Pointer) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{4}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{4}
 }
 func (m *Pointer) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Pointer.Unmarshal(m, b)
@@ -417,11 +451,36 @@ func (m *Pointer) GetMetadata() []byte {
 	return nil
 }
 
+func (m *Pointer) GetVersion() int32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+func (m *Pointer) GetSuite() int32 {
+	if m != nil {
+		return m.Suite
+	}
+	return 0
+}
+
+func (m *Pointer) GetProjectId() string {
+	if m != nil {
+		return m.ProjectId
+	}
+	return ""
+}
+
 // PutRequest is a request message for the Put rpc call
 type PutRequest struct {
-	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
-	Pointer              *Pointer `protobuf:"bytes,2,opt,name=pointer,proto3" json:"pointer,omitempty"`
-	APIKey               []byte   `protobuf:"bytes,3,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	Path    string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Pointer *Pointer `protobuf:"bytes,2,opt,name=pointer,proto3" json:"pointer,omitempty"`
+	APIKey  []byte   `protobuf:"bytes,3,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	// idempotency_key identifies the commit of a segment, so a commit retried
+	// with the same key returns the result of the first one instead of
+	// writing the pointer again
+	IdempotencyKey       string   `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -431,7 +490,7 @@ func (m *PutRequest) Reset()         { *m = PutRequest{} }
 func (m *PutRequest) String() string { return proto.CompactTextString(m) }
 func (*PutRequest) ProtoMessage()    {}
 func (*PutRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{5}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{5}
 }
 func (m *PutRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutRequest.Unmarshal(m, b)
@@ -472,6 +531,13 @@ func (m *PutRequest) GetAPIKey() []byte {
 	return nil
 }
 
+func (m *PutRequest) GetIdempotencyKey() string {
+	if m != nil {
+		return m.IdempotencyKey
+	}
+	return ""
+}
+
 // GetRequest is a request message for the Get rpc call
 type GetRequest struct {
 	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
@@ -485,7 +551,7 @@ func (m *GetRequest) Reset()         { *m = GetRequest{} }
 func (m *GetRequest) String() string { return proto.CompactTextString(m) }
 func (*GetRequest) ProtoMessage()    {}
 func (*GetRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{6}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{6}
 }
 func (m *GetRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetRequest.Unmarshal(m, b)
@@ -521,13 +587,16 @@ func (m *GetRequest) GetAPIKey() []byte {
 
 // ListRequest is a request message for the List rpc call
 type ListRequest struct {
-	Prefix               string   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
-	StartAfter           string   `protobuf:"bytes,2,opt,name=start_after,json=startAfter,proto3" json:"start_after,omitempty"`
-	EndBefore            string   `protobuf:"bytes,3,opt,name=end_before,json=endBefore,proto3" json:"end_before,omitempty"`
-	Recursive            bool     `protobuf:"varint,4,opt,name=recursive,proto3" json:"recursive,omitempty"`
-	Limit                int32    `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
-	MetaFlags            uint32   `protobuf:"fixed32,6,opt,name=meta_flags,json=metaFlags,proto3" json:"meta_flags,omitempty"`
-	APIKey               []byte   `protobuf:"bytes,7,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	Prefix     string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	StartAfter string `protobuf:"bytes,2,opt,name=start_after,json=startAfter,proto3" json:"start_after,omitempty"`
+	EndBefore  string `protobuf:"bytes,3,opt,name=end_before,json=endBefore,proto3" json:"end_before,omitempty"`
+	Recursive  bool   `protobuf:"varint,4,opt,name=recursive,proto3" json:"recursive,omitempty"`
+	Limit      int32  `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	MetaFlags  uint32 `protobuf:"fixed32,6,opt,name=meta_flags,json=metaFlags,proto3" json:"meta_flags,omitempty"`
+	APIKey     []byte `protobuf:"bytes,7,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	// cursor continues the listing that returned it, in place of start_after
+	// and end_before
+	Cursor               string   `protobuf:"bytes,8,opt,name=cursor,proto3" json:"cursor,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -537,7 +606,7 @@ func (m *ListRequest) Reset()         { *m = ListRequest{} }
 func (m *ListRequest) String() string { return proto.CompactTextString(m) }
 func (*ListRequest) ProtoMessage()    {}
 func (*ListRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{7}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{7}
 }
 func (m *ListRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListRequest.Unmarshal(m, b)
@@ -606,18 +675,26 @@ func (m *ListRequest) GetAPIKey() []byte {
 	return nil
 }
 
+func (m *ListRequest) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
 // PutResponse is a response message for the Put rpc call
 type PutResponse struct {
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	CreationDate         *timestamp.Timestamp `protobuf:"bytes,1,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
 }
 
 func (m *PutResponse) Reset()         { *m = PutResponse{} }
 func (m *PutResponse) String() string { return proto.CompactTextString(m) }
 func (*PutResponse) ProtoMessage()    {}
 func (*PutResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{8}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{8}
 }
 func (m *PutResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_PutResponse.Unmarshal(m, b)
@@ -637,9 +714,20 @@ func (m *PutResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_PutResponse proto.InternalMessageInfo
 
+func (m *PutResponse) GetCreationDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreationDate
+	}
+	return nil
+}
+
 // GetResponse is a response message for the Get rpc call
 type GetResponse struct {
-	Pointer              []byte   `protobuf:"bytes,1,opt,name=pointer,proto3" json:"pointer,omitempty"`
+	Pointer []byte `protobuf:"bytes,1,opt,name=pointer,proto3" json:"pointer,omitempty"`
+	// nodes contains the addresses of the nodes storing the pieces of a remote
+	// pointer, in the order of the pointer's remote pieces. Nodes the
+	// satellite doesn't know the address of have no id.
+	Nodes                []*Node  `protobuf:"bytes,2,rep,name=nodes,proto3" json:"nodes,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -649,7 +737,7 @@ func (m *GetResponse) Reset()         { *m = GetResponse{} }
 func (m *GetResponse) String() string { return proto.CompactTextString(m) }
 func (*GetResponse) ProtoMessage()    {}
 func (*GetResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{9}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{9}
 }
 func (m *GetResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_GetResponse.Unmarshal(m, b)
@@ -676,20 +764,29 @@ func (m *GetResponse) GetPointer() []byte {
 	return nil
 }
 
+func (m *GetResponse) GetNodes() []*Node {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
 // ListResponse is a response message for the List rpc call
 type ListResponse struct {
-	Items                []*ListResponse_Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
-	More                 bool                 `protobuf:"varint,2,opt,name=more,proto3" json:"more,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
-	XXX_unrecognized     []byte               `json:"-"`
-	XXX_sizecache        int32                `json:"-"`
+	Items []*ListResponse_Item `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	More  bool                 `protobuf:"varint,2,opt,name=more,proto3" json:"more,omitempty"`
+	// cursor is set if there are more items and continues the listing
+	Cursor               string   `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *ListResponse) Reset()         { *m = ListResponse{} }
 func (m *ListResponse) String() string { return proto.CompactTextString(m) }
 func (*ListResponse) ProtoMessage()    {}
 func (*ListResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{10}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{10}
 }
 func (m *ListResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListResponse.Unmarshal(m, b)
@@ -723,6 +820,13 @@ func (m *ListResponse) GetMore() bool {
 	return false
 }
 
+func (m *ListResponse) GetCursor() string {
+	if m != nil {
+		return m.Cursor
+	}
+	return ""
+}
+
 type ListResponse_Item struct {
 	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
 	Pointer              *Pointer `protobuf:"bytes,2,opt,name=pointer,proto3" json:"pointer,omitempty"`
@@ -736,7 +840,7 @@ func (m *ListResponse_Item) Reset()         { *m = ListResponse_Item{} }
 func (m *ListResponse_Item) String() string { return proto.CompactTextString(m) }
 func (*ListResponse_Item) ProtoMessage()    {}
 func (*ListResponse_Item) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{10, 0}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{10, 0}
 }
 func (m *ListResponse_Item) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_ListResponse_Item.Unmarshal(m, b)
@@ -789,7 +893,7 @@ func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
 func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteRequest) ProtoMessage()    {}
 func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{11}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{11}
 }
 func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteRequest.Unmarshal(m, b)
@@ -834,7 +938,7 @@ func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
 func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteResponse) ProtoMessage()    {}
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_pointerdb_380d8ac3c70f6d44, []int{12}
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{12}
 }
 func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_DeleteResponse.Unmarshal(m, b)
@@ -854,6 +958,611 @@ func (m *DeleteResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DeleteResponse proto.InternalMessageInfo
 
+// OrderLimitsRequest is a request message for the OrderLimits rpc call
+type OrderLimitsRequest struct {
+	Path   string                          `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Action PayerBandwidthAllocation_Action `protobuf:"varint,2,opt,name=action,proto3,enum=piecestoreroutes.PayerBandwidthAllocation_Action" json:"action,omitempty"`
+	// piece_id and node_ids are only set for PUT. Otherwise the order limits
+	// are issued for the pieces of the pointer at path.
+	PieceId              string   `protobuf:"bytes,3,opt,name=piece_id,json=pieceId,proto3" json:"piece_id,omitempty"`
+	NodeIds              []string `protobuf:"bytes,4,rep,name=node_ids,json=nodeIds,proto3" json:"node_ids,omitempty"`
+	APIKey               []byte   `protobuf:"bytes,5,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *OrderLimitsRequest) Reset()         { *m = OrderLimitsRequest{} }
+func (m *OrderLimitsRequest) String() string { return proto.CompactTextString(m) }
+func (*OrderLimitsRequest) ProtoMessage()    {}
+func (*OrderLimitsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{13}
+}
+func (m *OrderLimitsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrderLimitsRequest.Unmarshal(m, b)
+}
+func (m *OrderLimitsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrderLimitsRequest.Marshal(b, m, deterministic)
+}
+func (dst *OrderLimitsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderLimitsRequest.Merge(dst, src)
+}
+func (m *OrderLimitsRequest) XXX_Size() int {
+	return xxx_messageInfo_OrderLimitsRequest.Size(m)
+}
+func (m *OrderLimitsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderLimitsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderLimitsRequest proto.InternalMessageInfo
+
+func (m *OrderLimitsRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *OrderLimitsRequest) GetAction() PayerBandwidthAllocation_Action {
+	if m != nil {
+		return m.Action
+	}
+	return PayerBandwidthAllocation_PUT
+}
+
+func (m *OrderLimitsRequest) GetPieceId() string {
+	if m != nil {
+		return m.PieceId
+	}
+	return ""
+}
+
+func (m *OrderLimitsRequest) GetNodeIds() []string {
+	if m != nil {
+		return m.NodeIds
+	}
+	return nil
+}
+
+func (m *OrderLimitsRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
+	}
+	return nil
+}
+
+// OrderLimitsResponse is a response message for the OrderLimits rpc call
+type OrderLimitsResponse struct {
+	// limits contains an order limit for every node, in the order of node_ids
+	// or the pointer's remote pieces
+	Limits               []*PayerBandwidthAllocation `protobuf:"bytes,1,rep,name=limits,proto3" json:"limits,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                    `json:"-"`
+	XXX_unrecognized     []byte                      `json:"-"`
+	XXX_sizecache        int32                       `json:"-"`
+}
+
+func (m *OrderLimitsResponse) Reset()         { *m = OrderLimitsResponse{} }
+func (m *OrderLimitsResponse) String() string { return proto.CompactTextString(m) }
+func (*OrderLimitsResponse) ProtoMessage()    {}
+func (*OrderLimitsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{14}
+}
+func (m *OrderLimitsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_OrderLimitsResponse.Unmarshal(m, b)
+}
+func (m *OrderLimitsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_OrderLimitsResponse.Marshal(b, m, deterministic)
+}
+func (dst *OrderLimitsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_OrderLimitsResponse.Merge(dst, src)
+}
+func (m *OrderLimitsResponse) XXX_Size() int {
+	return xxx_messageInfo_OrderLimitsResponse.Size(m)
+}
+func (m *OrderLimitsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_OrderLimitsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_OrderLimitsResponse proto.InternalMessageInfo
+
+func (m *OrderLimitsResponse) GetLimits() []*PayerBandwidthAllocation {
+	if m != nil {
+		return m.Limits
+	}
+	return nil
+}
+
+// RevokeRequest is a request message for the Revoke rpc call
+type RevokeRequest struct {
+	// API_key is the key to revoke
+	APIKey               []byte   `protobuf:"bytes,1,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeRequest) Reset()         { *m = RevokeRequest{} }
+func (m *RevokeRequest) String() string { return proto.CompactTextString(m) }
+func (*RevokeRequest) ProtoMessage()    {}
+func (*RevokeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{15}
+}
+func (m *RevokeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeRequest.Unmarshal(m, b)
+}
+func (m *RevokeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeRequest.Marshal(b, m, deterministic)
+}
+func (dst *RevokeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeRequest.Merge(dst, src)
+}
+func (m *RevokeRequest) XXX_Size() int {
+	return xxx_messageInfo_RevokeRequest.Size(m)
+}
+func (m *RevokeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeRequest proto.InternalMessageInfo
+
+func (m *RevokeRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
+	}
+	return nil
+}
+
+// RevokeResponse is a response message for the Revoke rpc call
+type RevokeResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RevokeResponse) Reset()         { *m = RevokeResponse{} }
+func (m *RevokeResponse) String() string { return proto.CompactTextString(m) }
+func (*RevokeResponse) ProtoMessage()    {}
+func (*RevokeResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{16}
+}
+func (m *RevokeResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RevokeResponse.Unmarshal(m, b)
+}
+func (m *RevokeResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RevokeResponse.Marshal(b, m, deterministic)
+}
+func (dst *RevokeResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RevokeResponse.Merge(dst, src)
+}
+func (m *RevokeResponse) XXX_Size() int {
+	return xxx_messageInfo_RevokeResponse.Size(m)
+}
+func (m *RevokeResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RevokeResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RevokeResponse proto.InternalMessageInfo
+
+// BatchRequestItem is a request of a batch. Exactly one of its fields is set.
+type BatchRequestItem struct {
+	Put                  *PutRequest         `protobuf:"bytes,1,opt,name=put,proto3" json:"put,omitempty"`
+	Get                  *GetRequest         `protobuf:"bytes,2,opt,name=get,proto3" json:"get,omitempty"`
+	Delete               *DeleteRequest      `protobuf:"bytes,3,opt,name=delete,proto3" json:"delete,omitempty"`
+	OrderLimits          *OrderLimitsRequest `protobuf:"bytes,4,opt,name=order_limits,json=orderLimits,proto3" json:"order_limits,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}            `json:"-"`
+	XXX_unrecognized     []byte              `json:"-"`
+	XXX_sizecache        int32               `json:"-"`
+}
+
+func (m *BatchRequestItem) Reset()         { *m = BatchRequestItem{} }
+func (m *BatchRequestItem) String() string { return proto.CompactTextString(m) }
+func (*BatchRequestItem) ProtoMessage()    {}
+func (*BatchRequestItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{17}
+}
+func (m *BatchRequestItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchRequestItem.Unmarshal(m, b)
+}
+func (m *BatchRequestItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchRequestItem.Marshal(b, m, deterministic)
+}
+func (dst *BatchRequestItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchRequestItem.Merge(dst, src)
+}
+func (m *BatchRequestItem) XXX_Size() int {
+	return xxx_messageInfo_BatchRequestItem.Size(m)
+}
+func (m *BatchRequestItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchRequestItem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchRequestItem proto.InternalMessageInfo
+
+func (m *BatchRequestItem) GetPut() *PutRequest {
+	if m != nil {
+		return m.Put
+	}
+	return nil
+}
+
+func (m *BatchRequestItem) GetGet() *GetRequest {
+	if m != nil {
+		return m.Get
+	}
+	return nil
+}
+
+func (m *BatchRequestItem) GetDelete() *DeleteRequest {
+	if m != nil {
+		return m.Delete
+	}
+	return nil
+}
+
+func (m *BatchRequestItem) GetOrderLimits() *OrderLimitsRequest {
+	if m != nil {
+		return m.OrderLimits
+	}
+	return nil
+}
+
+// BatchRequest is a request message for the Batch rpc call
+type BatchRequest struct {
+	Requests []*BatchRequestItem `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	// API_key is used for the requests that don't have one
+	APIKey               []byte   `protobuf:"bytes,2,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BatchRequest) Reset()         { *m = BatchRequest{} }
+func (m *BatchRequest) String() string { return proto.CompactTextString(m) }
+func (*BatchRequest) ProtoMessage()    {}
+func (*BatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{18}
+}
+func (m *BatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchRequest.Unmarshal(m, b)
+}
+func (m *BatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchRequest.Marshal(b, m, deterministic)
+}
+func (dst *BatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchRequest.Merge(dst, src)
+}
+func (m *BatchRequest) XXX_Size() int {
+	return xxx_messageInfo_BatchRequest.Size(m)
+}
+func (m *BatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchRequest proto.InternalMessageInfo
+
+func (m *BatchRequest) GetRequests() []*BatchRequestItem {
+	if m != nil {
+		return m.Requests
+	}
+	return nil
+}
+
+func (m *BatchRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
+	}
+	return nil
+}
+
+// BatchResponseItem is the response to the request of a batch with the same
+// index. The field of the request that was set is set.
+type BatchResponseItem struct {
+	Put                  *PutResponse         `protobuf:"bytes,1,opt,name=put,proto3" json:"put,omitempty"`
+	Get                  *GetResponse         `protobuf:"bytes,2,opt,name=get,proto3" json:"get,omitempty"`
+	Delete               *DeleteResponse      `protobuf:"bytes,3,opt,name=delete,proto3" json:"delete,omitempty"`
+	OrderLimits          *OrderLimitsResponse `protobuf:"bytes,4,opt,name=order_limits,json=orderLimits,proto3" json:"order_limits,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *BatchResponseItem) Reset()         { *m = BatchResponseItem{} }
+func (m *BatchResponseItem) String() string { return proto.CompactTextString(m) }
+func (*BatchResponseItem) ProtoMessage()    {}
+func (*BatchResponseItem) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{19}
+}
+func (m *BatchResponseItem) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchResponseItem.Unmarshal(m, b)
+}
+func (m *BatchResponseItem) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchResponseItem.Marshal(b, m, deterministic)
+}
+func (dst *BatchResponseItem) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchResponseItem.Merge(dst, src)
+}
+func (m *BatchResponseItem) XXX_Size() int {
+	return xxx_messageInfo_BatchResponseItem.Size(m)
+}
+func (m *BatchResponseItem) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchResponseItem.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchResponseItem proto.InternalMessageInfo
+
+func (m *BatchResponseItem) GetPut() *PutResponse {
+	if m != nil {
+		return m.Put
+	}
+	return nil
+}
+
+func (m *BatchResponseItem) GetGet() *GetResponse {
+	if m != nil {
+		return m.Get
+	}
+	return nil
+}
+
+func (m *BatchResponseItem) GetDelete() *DeleteResponse {
+	if m != nil {
+		return m.Delete
+	}
+	return nil
+}
+
+func (m *BatchResponseItem) GetOrderLimits() *OrderLimitsResponse {
+	if m != nil {
+		return m.OrderLimits
+	}
+	return nil
+}
+
+// BatchResponse is a response message for the Batch rpc call
+type BatchResponse struct {
+	Responses            []*BatchResponseItem `protobuf:"bytes,1,rep,name=responses,proto3" json:"responses,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *BatchResponse) Reset()         { *m = BatchResponse{} }
+func (m *BatchResponse) String() string { return proto.CompactTextString(m) }
+func (*BatchResponse) ProtoMessage()    {}
+func (*BatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{20}
+}
+func (m *BatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BatchResponse.Unmarshal(m, b)
+}
+func (m *BatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BatchResponse.Marshal(b, m, deterministic)
+}
+func (dst *BatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BatchResponse.Merge(dst, src)
+}
+func (m *BatchResponse) XXX_Size() int {
+	return xxx_messageInfo_BatchResponse.Size(m)
+}
+func (m *BatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BatchResponse proto.InternalMessageInfo
+
+func (m *BatchResponse) GetResponses() []*BatchResponseItem {
+	if m != nil {
+		return m.Responses
+	}
+	return nil
+}
+
+// GetObjectInfoRequest is a request message for the GetObjectInfo rpc call
+type GetObjectInfoRequest struct {
+	// path is the path of the object, its bucket followed by its path in the
+	// bucket, without a segment
+	Path                 string   `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	APIKey               []byte   `protobuf:"bytes,2,opt,name=API_key,json=APIKey,proto3" json:"API_key,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetObjectInfoRequest) Reset()         { *m = GetObjectInfoRequest{} }
+func (m *GetObjectInfoRequest) String() string { return proto.CompactTextString(m) }
+func (*GetObjectInfoRequest) ProtoMessage()    {}
+func (*GetObjectInfoRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{21}
+}
+func (m *GetObjectInfoRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetObjectInfoRequest.Unmarshal(m, b)
+}
+func (m *GetObjectInfoRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetObjectInfoRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetObjectInfoRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetObjectInfoRequest.Merge(dst, src)
+}
+func (m *GetObjectInfoRequest) XXX_Size() int {
+	return xxx_messageInfo_GetObjectInfoRequest.Size(m)
+}
+func (m *GetObjectInfoRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetObjectInfoRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetObjectInfoRequest proto.InternalMessageInfo
+
+func (m *GetObjectInfoRequest) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *GetObjectInfoRequest) GetAPIKey() []byte {
+	if m != nil {
+		return m.APIKey
+	}
+	return nil
+}
+
+// SegmentInfo describes a segment of an object
+type SegmentInfo struct {
+	// path is the path of the segment, starting with the segment
+	Path string           `protobuf:"bytes,1,opt,name=path,proto3" json:"path,omitempty"`
+	Type Pointer_DataType `protobuf:"varint,2,opt,name=type,proto3,enum=pointerdb.Pointer_DataType" json:"type,omitempty"`
+	Size int64            `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	// redundancy and pieces are only set for remote segments. pieces is the
+	// number of pieces stored.
+	Redundancy           *RedundancyScheme `protobuf:"bytes,4,opt,name=redundancy,proto3" json:"redundancy,omitempty"`
+	Pieces               int32             `protobuf:"varint,5,opt,name=pieces,proto3" json:"pieces,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SegmentInfo) Reset()         { *m = SegmentInfo{} }
+func (m *SegmentInfo) String() string { return proto.CompactTextString(m) }
+func (*SegmentInfo) ProtoMessage()    {}
+func (*SegmentInfo) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{22}
+}
+func (m *SegmentInfo) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SegmentInfo.Unmarshal(m, b)
+}
+func (m *SegmentInfo) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SegmentInfo.Marshal(b, m, deterministic)
+}
+func (dst *SegmentInfo) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SegmentInfo.Merge(dst, src)
+}
+func (m *SegmentInfo) XXX_Size() int {
+	return xxx_messageInfo_SegmentInfo.Size(m)
+}
+func (m *SegmentInfo) XXX_DiscardUnknown() {
+	xxx_messageInfo_SegmentInfo.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SegmentInfo proto.InternalMessageInfo
+
+func (m *SegmentInfo) GetPath() string {
+	if m != nil {
+		return m.Path
+	}
+	return ""
+}
+
+func (m *SegmentInfo) GetType() Pointer_DataType {
+	if m != nil {
+		return m.Type
+	}
+	return Pointer_INLINE
+}
+
+func (m *SegmentInfo) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *SegmentInfo) GetRedundancy() *RedundancyScheme {
+	if m != nil {
+		return m.Redundancy
+	}
+	return nil
+}
+
+func (m *SegmentInfo) GetPieces() int32 {
+	if m != nil {
+		return m.Pieces
+	}
+	return 0
+}
+
+// GetObjectInfoResponse is a response message for the GetObjectInfo rpc call
+type GetObjectInfoResponse struct {
+	Size           int64                `protobuf:"varint,1,opt,name=size,proto3" json:"size,omitempty"`
+	CreationDate   *timestamp.Timestamp `protobuf:"bytes,2,opt,name=creation_date,json=creationDate,proto3" json:"creation_date,omitempty"`
+	ExpirationDate *timestamp.Timestamp `protobuf:"bytes,3,opt,name=expiration_date,json=expirationDate,proto3" json:"expiration_date,omitempty"`
+	// segments are the segments of the object in order, the last segment
+	// last
+	Segments    []*SegmentInfo `protobuf:"bytes,4,rep,name=segments,proto3" json:"segments,omitempty"`
+	ContentType string         `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	// metadata_keys are the sorted keys of the user-defined metadata. The
+	// values are left out.
+	MetadataKeys         []string `protobuf:"bytes,6,rep,name=metadata_keys,json=metadataKeys,proto3" json:"metadata_keys,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetObjectInfoResponse) Reset()         { *m = GetObjectInfoResponse{} }
+func (m *GetObjectInfoResponse) String() string { return proto.CompactTextString(m) }
+func (*GetObjectInfoResponse) ProtoMessage()    {}
+func (*GetObjectInfoResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_pointerdb_11dd1a3d44f49e42, []int{23}
+}
+func (m *GetObjectInfoResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetObjectInfoResponse.Unmarshal(m, b)
+}
+func (m *GetObjectInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetObjectInfoResponse.Marshal(b, m, deterministic)
+}
+func (dst *GetObjectInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetObjectInfoResponse.Merge(dst, src)
+}
+func (m *GetObjectInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_GetObjectInfoResponse.Size(m)
+}
+func (m *GetObjectInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetObjectInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetObjectInfoResponse proto.InternalMessageInfo
+
+func (m *GetObjectInfoResponse) GetSize() int64 {
+	if m != nil {
+		return m.Size
+	}
+	return 0
+}
+
+func (m *GetObjectInfoResponse) GetCreationDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.CreationDate
+	}
+	return nil
+}
+
+func (m *GetObjectInfoResponse) GetExpirationDate() *timestamp.Timestamp {
+	if m != nil {
+		return m.ExpirationDate
+	}
+	return nil
+}
+
+func (m *GetObjectInfoResponse) GetSegments() []*SegmentInfo {
+	if m != nil {
+		return m.Segments
+	}
+	return nil
+}
+
+func (m *GetObjectInfoResponse) GetContentType() string {
+	if m != nil {
+		return m.ContentType
+	}
+	return ""
+}
+
+func (m *GetObjectInfoResponse) GetMetadataKeys() []string {
+	if m != nil {
+		return m.MetadataKeys
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*RedundancyScheme)(nil), "pointerdb.RedundancyScheme")
 	proto.RegisterType((*EncryptionScheme)(nil), "pointerdb.EncryptionScheme")
@@ -869,6 +1578,17 @@ func init() {
 	proto.RegisterType((*ListResponse_Item)(nil), "pointerdb.ListResponse.Item")
 	proto.RegisterType((*DeleteRequest)(nil), "pointerdb.DeleteRequest")
 	proto.RegisterType((*DeleteResponse)(nil), "pointerdb.DeleteResponse")
+	proto.RegisterType((*OrderLimitsRequest)(nil), "pointerdb.OrderLimitsRequest")
+	proto.RegisterType((*OrderLimitsResponse)(nil), "pointerdb.OrderLimitsResponse")
+	proto.RegisterType((*RevokeRequest)(nil), "pointerdb.RevokeRequest")
+	proto.RegisterType((*RevokeResponse)(nil), "pointerdb.RevokeResponse")
+	proto.RegisterType((*BatchRequestItem)(nil), "pointerdb.BatchRequestItem")
+	proto.RegisterType((*BatchRequest)(nil), "pointerdb.BatchRequest")
+	proto.RegisterType((*BatchResponseItem)(nil), "pointerdb.BatchResponseItem")
+	proto.RegisterType((*BatchResponse)(nil), "pointerdb.BatchResponse")
+	proto.RegisterType((*GetObjectInfoRequest)(nil), "pointerdb.GetObjectInfoRequest")
+	proto.RegisterType((*SegmentInfo)(nil), "pointerdb.SegmentInfo")
+	proto.RegisterType((*GetObjectInfoResponse)(nil), "pointerdb.GetObjectInfoResponse")
 	proto.RegisterEnum("pointerdb.RedundancyScheme_SchemeType", RedundancyScheme_SchemeType_name, RedundancyScheme_SchemeType_value)
 	proto.RegisterEnum("pointerdb.EncryptionScheme_EncryptionType", EncryptionScheme_EncryptionType_name, EncryptionScheme_EncryptionType_value)
 	proto.RegisterEnum("pointerdb.Pointer_DataType", Pointer_DataType_name, Pointer_DataType_value)
@@ -894,6 +1614,14 @@ type PointerDBClient interface {
 	List(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (*ListResponse, error)
 	// Delete formats and hands off a file path to delete from boltdb
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
+	// OrderLimits signs the order limits an uplink needs to access the pieces of a segment
+	OrderLimits(ctx context.Context, in *OrderLimitsRequest, opts ...grpc.CallOption) (*OrderLimitsResponse, error)
+	// Revoke revokes an API key and every key derived from it
+	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeResponse, error)
+	// Batch executes several requests in order in one round trip
+	Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error)
+	// GetObjectInfo describes an object and its segments without their data
+	GetObjectInfo(ctx context.Context, in *GetObjectInfoRequest, opts ...grpc.CallOption) (*GetObjectInfoResponse, error)
 }
 
 type pointerDBClient struct {
@@ -940,6 +1668,42 @@ func (c *pointerDBClient) Delete(ctx context.Context, in *DeleteRequest, opts ..
 	return out, nil
 }
 
+func (c *pointerDBClient) OrderLimits(ctx context.Context, in *OrderLimitsRequest, opts ...grpc.CallOption) (*OrderLimitsResponse, error) {
+	out := new(OrderLimitsResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/OrderLimits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeResponse, error) {
+	out := new(RevokeResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/Revoke", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) Batch(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (*BatchResponse, error) {
+	out := new(BatchResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/Batch", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *pointerDBClient) GetObjectInfo(ctx context.Context, in *GetObjectInfoRequest, opts ...grpc.CallOption) (*GetObjectInfoResponse, error) {
+	out := new(GetObjectInfoResponse)
+	err := c.cc.Invoke(ctx, "/pointerdb.PointerDB/GetObjectInfo", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PointerDBServer is the server API for PointerDB service.
 type PointerDBServer interface {
 	// Put formats and hands off a file path to be saved to boltdb
@@ -950,6 +1714,14 @@ type PointerDBServer interface {
 	List(context.Context, *ListRequest) (*ListResponse, error)
 	// Delete formats and hands off a file path to delete from boltdb
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
+	// OrderLimits signs the order limits an uplink needs to access the pieces of a segment
+	OrderLimits(context.Context, *OrderLimitsRequest) (*OrderLimitsResponse, error)
+	// Revoke revokes an API key and every key derived from it
+	Revoke(context.Context, *RevokeRequest) (*RevokeResponse, error)
+	// Batch executes several requests in order in one round trip
+	Batch(context.Context, *BatchRequest) (*BatchResponse, error)
+	// GetObjectInfo describes an object and its segments without their data
+	GetObjectInfo(context.Context, *GetObjectInfoRequest) (*GetObjectInfoResponse, error)
 }
 
 func RegisterPointerDBServer(s *grpc.Server, srv PointerDBServer) {
@@ -1028,6 +1800,78 @@ func _PointerDB_Delete_Handler(srv interface{}, ctx context.Context, dec func(in
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PointerDB_OrderLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OrderLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).OrderLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/OrderLimits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).OrderLimits(ctx, req.(*OrderLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/Revoke",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).Revoke(ctx, req.(*RevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_Batch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).Batch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/Batch",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).Batch(ctx, req.(*BatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PointerDB_GetObjectInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetObjectInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PointerDBServer).GetObjectInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pointerdb.PointerDB/GetObjectInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PointerDBServer).GetObjectInfo(ctx, req.(*GetObjectInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _PointerDB_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pointerdb.PointerDB",
 	HandlerType: (*PointerDBServer)(nil),
@@ -1048,75 +1892,129 @@ var _PointerDB_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Delete",
 			Handler:    _PointerDB_Delete_Handler,
 		},
+		{
+			MethodName: "OrderLimits",
+			Handler:    _PointerDB_OrderLimits_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _PointerDB_Revoke_Handler,
+		},
+		{
+			MethodName: "Batch",
+			Handler:    _PointerDB_Batch_Handler,
+		},
+		{
+			MethodName: "GetObjectInfo",
+			Handler:    _PointerDB_GetObjectInfo_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "pointerdb.proto",
 }
 
-func init() { proto.RegisterFile("pointerdb.proto", fileDescriptor_pointerdb_380d8ac3c70f6d44) }
-
-var fileDescriptor_pointerdb_380d8ac3c70f6d44 = []byte{
-	// 990 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x54, 0xdb, 0x6e, 0xdb, 0x46,
-	0x13, 0x36, 0x25, 0x59, 0x12, 0x47, 0x96, 0xcd, 0x7f, 0x91, 0xdf, 0x61, 0xe4, 0x14, 0x31, 0x08,
-	0xb4, 0x75, 0x9b, 0x40, 0x2e, 0xd4, 0x00, 0x3d, 0xa4, 0x07, 0xf8, 0xa0, 0x1a, 0x42, 0x12, 0x47,
-	0x58, 0xf9, 0xa2, 0xe8, 0x0d, 0x41, 0x8b, 0x63, 0x79, 0x11, 0xf1, 0xe0, 0xdd, 0x65, 0x11, 0xe5,
-	0x4d, 0xfa, 0x30, 0xbd, 0xec, 0x03, 0xf4, 0x41, 0x8a, 0x5e, 0xf4, 0x05, 0x8a, 0xdd, 0x25, 0x45,
-	0xd2, 0x6e, 0x53, 0xa0, 0xe8, 0x8d, 0xb4, 0xf3, 0xed, 0x37, 0xb3, 0x3b, 0xdf, 0x7c, 0x5c, 0xd8,
-	0x49, 0x13, 0x16, 0x4b, 0xe4, 0xe1, 0xe5, 0x30, 0xe5, 0x89, 0x4c, 0x88, 0xbd, 0x06, 0x06, 0x8f,
-	0x16, 0x49, 0xb2, 0x58, 0xe2, 0xa1, 0xde, 0xb8, 0xcc, 0xae, 0x0e, 0x25, 0x8b, 0x50, 0xc8, 0x20,
-	0x4a, 0x0d, 0xd7, 0xfb, 0xa9, 0x01, 0x0e, 0xc5, 0x30, 0x8b, 0xc3, 0x20, 0x9e, 0xaf, 0x66, 0xf3,
-	0x6b, 0x8c, 0x90, 0x7c, 0x09, 0x2d, 0xb9, 0x4a, 0xd1, 0xb5, 0xf6, 0xad, 0x83, 0xed, 0xd1, 0x07,
-	0xc3, 0xf2, 0x80, 0xdb, 0xd4, 0xa1, 0xf9, 0xbb, 0x58, 0xa5, 0x48, 0x75, 0x0e, 0xb9, 0x0f, 0x9d,
-	0x88, 0xc5, 0x3e, 0xc7, 0x1b, 0xb7, 0xb1, 0x6f, 0x1d, 0x6c, 0xd2, 0x76, 0xc4, 0x62, 0x8a, 0x37,
-	0xe4, 0x1e, 0x6c, 0xca, 0x44, 0x06, 0x4b, 0xb7, 0xa9, 0x61, 0x13, 0x90, 0x8f, 0xc0, 0xe1, 0x98,
-	0x06, 0x8c, 0xfb, 0xf2, 0x9a, 0xa3, 0xb8, 0x4e, 0x96, 0xa1, 0xdb, 0xd2, 0x84, 0x1d, 0x83, 0x5f,
-	0x14, 0x30, 0x79, 0x0c, 0xff, 0x13, 0xd9, 0x7c, 0x8e, 0x42, 0x54, 0xb8, 0x9b, 0x9a, 0xeb, 0xe4,
-	0x1b, 0x25, 0xf9, 0x09, 0x10, 0xe4, 0x81, 0xc8, 0x38, 0xfa, 0xe2, 0x3a, 0x50, 0xbf, 0xec, 0x2d,
-	0xba, 0x6d, 0xc3, 0xce, 0x77, 0x66, 0x6a, 0x63, 0xc6, 0xde, 0xa2, 0x77, 0x0f, 0xa0, 0x6c, 0x84,
-	0xb4, 0xa1, 0x41, 0x67, 0xce, 0x86, 0xf7, 0x87, 0x05, 0xce, 0x38, 0x9e, 0xf3, 0x55, 0x2a, 0x59,
-	0x12, 0xe7, 0xda, 0x7c, 0x53, 0xd3, 0xe6, 0xe3, 0x8a, 0x36, 0xb7, 0xa9, 0x15, 0xa0, 0xa2, 0xcf,
-	0xe7, 0xe0, 0xa2, 0xc1, 0x31, 0xf4, 0x71, 0xcd, 0xf0, 0x5f, 0xe3, 0x4a, 0x0b, 0xb6, 0x45, 0x77,
-	0xd7, 0xfb, 0x65, 0x81, 0xe7, 0xb8, 0xaa, 0x67, 0x0a, 0x19, 0x70, 0xc9, 0xe2, 0x85, 0x1f, 0x27,
-	0xf1, 0x1c, 0xb5, 0xa6, 0xd5, 0xcc, 0x59, 0xbe, 0x7d, 0xae, 0x76, 0xbd, 0xc7, 0xb0, 0x5d, 0xbf,
-	0x0b, 0x01, 0x68, 0x1f, 0x8d, 0x67, 0x67, 0x27, 0x2f, 0x9d, 0x0d, 0xd2, 0x07, 0x7b, 0x36, 0x3e,
-	0xa1, 0xe3, 0x8b, 0xe3, 0x57, 0xdf, 0x3b, 0x96, 0x77, 0x02, 0x3d, 0x8a, 0x51, 0x22, 0x71, 0xca,
-	0x70, 0x8e, 0x64, 0x0f, 0xec, 0x54, 0x2d, 0xfc, 0x38, 0x8b, 0x74, 0xd3, 0x9b, 0xb4, 0xab, 0x81,
-	0xf3, 0x2c, 0x52, 0xc3, 0x8e, 0x93, 0x10, 0x7d, 0x16, 0xea, 0xbb, 0xdb, 0xb4, 0xad, 0xc2, 0x49,
-	0xe8, 0xfd, 0x62, 0x41, 0xdf, 0x54, 0x99, 0xe1, 0x22, 0xc2, 0x58, 0x92, 0x67, 0x00, 0x7c, 0x6d,
-	0x1e, 0x5d, 0xa8, 0x37, 0xda, 0x7b, 0x87, 0xb3, 0x68, 0x85, 0x4e, 0x1e, 0x80, 0x39, 0xb3, 0x3c,
-	0xa8, 0xa3, 0xe3, 0x49, 0x48, 0x9e, 0x41, 0x9f, 0xeb, 0x83, 0x7c, 0x8d, 0x08, 0xb7, 0xb9, 0xdf,
-	0x3c, 0xe8, 0x8d, 0x76, 0x6b, 0xa5, 0xd7, 0xed, 0xd0, 0x2d, 0x5e, 0x06, 0x82, 0x3c, 0x82, 0x5e,
-	0x84, 0xfc, 0xf5, 0x12, 0x7d, 0x9e, 0x24, 0x52, 0x1b, 0x6f, 0x8b, 0x82, 0x81, 0x68, 0x92, 0x48,
-	0xef, 0xb7, 0x06, 0x74, 0xa6, 0xa6, 0x10, 0x39, 0xac, 0x4d, 0xbe, 0x7a, 0xf7, 0x9c, 0x31, 0x3c,
-	0x0d, 0x64, 0x50, 0x19, 0xf5, 0xfb, 0xb0, 0xcd, 0xe2, 0x25, 0x8b, 0xd1, 0x17, 0x46, 0x84, 0x7c,
-	0x4c, 0x7d, 0x83, 0x16, 0xca, 0x7c, 0x02, 0x6d, 0x73, 0x29, 0x7d, 0x7e, 0x6f, 0xe4, 0xde, 0xb9,
-	0x7a, 0xce, 0xa4, 0x39, 0x8f, 0x10, 0x68, 0x69, 0x3b, 0x2b, 0xf3, 0x37, 0xa9, 0x5e, 0x93, 0x6f,
-	0xa1, 0x3f, 0xe7, 0x18, 0x68, 0x2f, 0x85, 0x81, 0x34, 0x5e, 0xef, 0x8d, 0x06, 0x43, 0xf3, 0x02,
-	0x0c, 0x8b, 0x17, 0x60, 0x78, 0x51, 0xbc, 0x00, 0x74, 0xab, 0x48, 0x38, 0x0d, 0x24, 0x92, 0x13,
-	0xd8, 0xc1, 0x37, 0x29, 0xe3, 0x95, 0x12, 0x9d, 0x7f, 0x2c, 0xb1, 0x5d, 0xa6, 0xe8, 0x22, 0x03,
-	0xe8, 0x46, 0x28, 0x83, 0x30, 0x90, 0x81, 0xdb, 0xd5, 0xcd, 0xae, 0x63, 0xcf, 0x83, 0x6e, 0x21,
-	0x90, 0xf2, 0xdf, 0xe4, 0xfc, 0xc5, 0xe4, 0x7c, 0xec, 0x6c, 0xa8, 0x35, 0x1d, 0xbf, 0x7c, 0x75,
-	0x31, 0x76, 0x2c, 0x6f, 0x01, 0x30, 0xcd, 0x24, 0xc5, 0x9b, 0x0c, 0x85, 0x54, 0x7d, 0xa6, 0x81,
-	0xbc, 0xd6, 0x8a, 0xdb, 0x54, 0xaf, 0xc9, 0x13, 0xe8, 0xe4, 0xf2, 0x68, 0x27, 0xf4, 0x46, 0xe4,
-	0xee, 0x20, 0x68, 0x41, 0x51, 0x06, 0x3d, 0x9a, 0x4e, 0xf4, 0xc7, 0x65, 0xb4, 0x6f, 0x1f, 0x4d,
-	0x27, 0xcf, 0x71, 0xe5, 0x7d, 0x01, 0x70, 0x86, 0xef, 0x3c, 0xa8, 0x92, 0xda, 0xa8, 0xa5, 0xfe,
-	0x6a, 0x41, 0xef, 0x05, 0x13, 0xeb, 0xe4, 0x5d, 0x68, 0xa7, 0x1c, 0xaf, 0xd8, 0x9b, 0x3c, 0x3d,
-	0x8f, 0x94, 0xb9, 0xf4, 0x57, 0xea, 0x07, 0x57, 0xc5, 0x6d, 0x6d, 0x0a, 0x1a, 0x3a, 0x52, 0x08,
-	0x79, 0x0f, 0x00, 0xe3, 0xd0, 0xbf, 0xc4, 0xab, 0x84, 0x9b, 0x4f, 0xd8, 0xa6, 0x36, 0xc6, 0xe1,
-	0xb1, 0x06, 0xc8, 0x43, 0xb0, 0x39, 0xce, 0x33, 0x2e, 0xd8, 0x8f, 0xc6, 0x1a, 0x5d, 0x5a, 0x02,
-	0xea, 0x39, 0x5d, 0xb2, 0x88, 0xc9, 0xfc, 0x05, 0x34, 0x81, 0x2a, 0xa9, 0xf4, 0xf6, 0xaf, 0x96,
-	0xc1, 0x42, 0x68, 0x0b, 0x74, 0xa8, 0xad, 0x90, 0xef, 0x14, 0x50, 0xed, 0xa9, 0x53, 0xeb, 0xa9,
-	0x0f, 0x3d, 0xad, 0xbb, 0x48, 0x93, 0x58, 0xa0, 0xf7, 0x21, 0xf4, 0xb4, 0x3a, 0x26, 0x24, 0x6e,
-	0xa9, 0xb9, 0xa5, 0xd3, 0x8a, 0xd0, 0xfb, 0xd9, 0x82, 0x2d, 0xa3, 0x45, 0x4e, 0x1d, 0xc1, 0x26,
-	0x93, 0x18, 0x09, 0xd7, 0xd2, 0x9f, 0xe1, 0xc3, 0xca, 0x70, 0xaa, 0xbc, 0xe1, 0x44, 0x62, 0x44,
-	0x0d, 0x55, 0xa9, 0x1f, 0x29, 0x05, 0x1a, 0xba, 0x47, 0xbd, 0x1e, 0x20, 0xb4, 0x14, 0xe5, 0x3f,
-	0xb0, 0xc0, 0x1e, 0xd8, 0x4c, 0xf8, 0xf9, 0x84, 0x9a, 0xfa, 0x88, 0x2e, 0x13, 0x53, 0x1d, 0x7b,
-	0x5f, 0x41, 0xff, 0x14, 0x97, 0x28, 0xf1, 0x5f, 0x39, 0xc1, 0x81, 0xed, 0x22, 0xdb, 0xb4, 0x35,
-	0xfa, 0xdd, 0x02, 0x3b, 0xbf, 0xc1, 0xe9, 0x31, 0x79, 0x0a, 0xcd, 0x69, 0x26, 0xc9, 0xff, 0xab,
-	0xd7, 0x5b, 0xbb, 0x7b, 0xb0, 0x7b, 0x1b, 0xce, 0x25, 0x7c, 0x0a, 0xcd, 0x33, 0xac, 0x67, 0x95,
-	0x56, 0xad, 0x65, 0x55, 0x67, 0xf4, 0x19, 0xb4, 0x94, 0xc0, 0x64, 0xf7, 0x8e, 0xe2, 0x26, 0xef,
-	0xfe, 0xdf, 0x4c, 0x82, 0x7c, 0x0d, 0x6d, 0xd3, 0x04, 0xa9, 0x3e, 0x3c, 0x35, 0x55, 0x06, 0x0f,
-	0xfe, 0x62, 0xc7, 0xa4, 0x1f, 0xb7, 0x7e, 0x68, 0xa4, 0x97, 0x97, 0x6d, 0xfd, 0x36, 0x7c, 0xfa,
-	0x67, 0x00, 0x00, 0x00, 0xff, 0xff, 0x53, 0x8e, 0xa9, 0x23, 0x8c, 0x08, 0x00, 0x00,
+func init() { proto.RegisterFile("pointerdb.proto", fileDescriptor_pointerdb_11dd1a3d44f49e42) }
+
+var fileDescriptor_pointerdb_11dd1a3d44f49e42 = []byte{
+	// 1600 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xad, 0x57, 0x4b, 0x73, 0x1b, 0x45,
+	0x10, 0xce, 0xea, 0xad, 0xd6, 0xc3, 0xca, 0x90, 0x38, 0x8a, 0x92, 0x90, 0xb0, 0x29, 0x88, 0x09,
+	0x94, 0x42, 0x04, 0x55, 0x40, 0xc2, 0xcb, 0x72, 0x44, 0x4a, 0x15, 0xc7, 0x76, 0x8d, 0x5c, 0x14,
+	0x70, 0x59, 0xd6, 0xda, 0xb1, 0xb5, 0x44, 0xbb, 0xab, 0xec, 0xae, 0x42, 0xcc, 0x91, 0x33, 0x07,
+	0xfe, 0x08, 0x77, 0xaa, 0xe0, 0x44, 0x15, 0x3f, 0x81, 0x2a, 0x2e, 0xfc, 0x00, 0xfe, 0x05, 0x33,
+	0x3d, 0xb3, 0xd2, 0xac, 0x2c, 0x39, 0x0f, 0xb8, 0xd8, 0x3b, 0x3d, 0x5f, 0xcf, 0xf4, 0x7c, 0xfd,
+	0x4d, 0xf7, 0x08, 0xd6, 0x26, 0x81, 0xeb, 0xc7, 0x2c, 0x74, 0x0e, 0xda, 0x93, 0x30, 0x88, 0x03,
+	0x52, 0x9e, 0x19, 0x5a, 0x57, 0x8f, 0x82, 0xe0, 0x68, 0xcc, 0x6e, 0xe1, 0xc4, 0xc1, 0xf4, 0xf0,
+	0x56, 0xec, 0x7a, 0x2c, 0x8a, 0x6d, 0x6f, 0x22, 0xb1, 0xad, 0x5a, 0xf0, 0x84, 0x85, 0x63, 0xfb,
+	0x58, 0x0d, 0x1b, 0x13, 0x97, 0x0d, 0x39, 0x20, 0x08, 0x99, 0xb4, 0x98, 0xbf, 0x64, 0xa0, 0x41,
+	0x99, 0x33, 0xf5, 0x1d, 0xdb, 0x1f, 0x1e, 0x0f, 0x86, 0x23, 0xe6, 0x31, 0x72, 0x07, 0x72, 0xf1,
+	0xf1, 0x84, 0x35, 0x8d, 0x6b, 0xc6, 0x46, 0xbd, 0xf3, 0x46, 0x7b, 0x1e, 0xc1, 0x22, 0xb4, 0x2d,
+	0xff, 0xed, 0x73, 0x34, 0x45, 0x1f, 0x72, 0x01, 0x8a, 0x9e, 0xeb, 0x5b, 0x21, 0x7b, 0xdc, 0xcc,
+	0x70, 0xf7, 0x3c, 0x2d, 0xf0, 0x21, 0x65, 0x8f, 0xc9, 0x39, 0xc8, 0xc7, 0x41, 0x6c, 0x8f, 0x9b,
+	0x59, 0x34, 0xcb, 0x01, 0x79, 0x13, 0x1a, 0x21, 0x9b, 0xd8, 0x6e, 0x68, 0xc5, 0xa3, 0x90, 0x45,
+	0xa3, 0x60, 0xec, 0x34, 0x73, 0x08, 0x58, 0x93, 0xf6, 0xfd, 0xc4, 0x4c, 0xde, 0x82, 0xb3, 0xd1,
+	0x74, 0xc8, 0xc3, 0x8f, 0x34, 0x6c, 0x1e, 0xb1, 0x0d, 0x35, 0x31, 0x07, 0xbf, 0x0d, 0x84, 0x85,
+	0x76, 0x34, 0x0d, 0x99, 0x15, 0x8d, 0x6c, 0xf1, 0xd7, 0xfd, 0x9e, 0x35, 0x0b, 0x12, 0xad, 0x66,
+	0x06, 0x62, 0x62, 0xc0, 0xed, 0xe4, 0x0a, 0x80, 0x44, 0x79, 0xf6, 0x30, 0x6a, 0x16, 0x39, 0xaa,
+	0x44, 0xcb, 0x68, 0x79, 0xc8, 0x0d, 0xe6, 0x39, 0x80, 0xf9, 0x39, 0x49, 0x01, 0x32, 0x74, 0xd0,
+	0x38, 0x63, 0xfe, 0xc0, 0xa9, 0xeb, 0xf9, 0xc3, 0xf0, 0x78, 0x12, 0xbb, 0x81, 0xaf, 0xa8, 0xfb,
+	0x24, 0x45, 0xdd, 0x4d, 0x8d, 0xba, 0x45, 0xa8, 0x66, 0xd0, 0xe8, 0xfb, 0x00, 0x9a, 0x4c, 0xda,
+	0x99, 0x63, 0xb1, 0x19, 0xc2, 0x7a, 0xc4, 0x8e, 0x91, 0xcf, 0x2a, 0x5d, 0x9f, 0xcd, 0xcf, 0x17,
+	0x78, 0xc0, 0x8e, 0xd3, 0x9e, 0x5c, 0x03, 0x61, 0xec, 0xfa, 0x47, 0x96, 0x1f, 0xf8, 0x43, 0x86,
+	0x94, 0xeb, 0x9e, 0x03, 0x35, 0xbd, 0x23, 0x66, 0xcd, 0x3b, 0x50, 0x4f, 0xc7, 0x42, 0x00, 0x0a,
+	0x9b, 0xbd, 0xc1, 0xfd, 0xad, 0x87, 0x8d, 0x33, 0xa4, 0x06, 0xe5, 0x41, 0x6f, 0x8b, 0xf6, 0xf6,
+	0xbb, 0xbb, 0x5f, 0x36, 0x0c, 0x31, 0x94, 0x53, 0x83, 0xfe, 0x17, 0x8d, 0x8c, 0xe9, 0x40, 0x85,
+	0x32, 0x2f, 0x88, 0xd9, 0x9e, 0x50, 0x16, 0xb9, 0x04, 0x65, 0x94, 0x98, 0xe5, 0x4f, 0x3d, 0xe4,
+	0x20, 0x4f, 0x4b, 0x68, 0xd8, 0x99, 0x7a, 0x42, 0x1a, 0x7e, 0xe0, 0x30, 0xcb, 0x75, 0xf0, 0x28,
+	0x65, 0x5a, 0x10, 0xc3, 0xbe, 0x43, 0xae, 0x42, 0xc5, 0x63, 0xe1, 0xa3, 0x31, 0xb3, 0xc2, 0x20,
+	0x88, 0x55, 0xb4, 0x20, 0x4d, 0x94, 0x5b, 0xcc, 0x3f, 0x0c, 0xa8, 0xc9, 0x6d, 0x06, 0xec, 0xc8,
+	0x63, 0x7e, 0x4c, 0xee, 0x02, 0x84, 0x33, 0x2d, 0xe2, 0x4e, 0x95, 0xce, 0xa5, 0x53, 0x84, 0x4a,
+	0x35, 0x38, 0xb9, 0x08, 0x32, 0xa8, 0x79, 0x24, 0x45, 0x1c, 0xf3, 0x50, 0xee, 0x42, 0x2d, 0xc4,
+	0x8d, 0x2c, 0x79, 0x55, 0x78, 0x30, 0x59, 0xbe, 0xf4, 0x7a, 0x6a, 0xe9, 0xd9, 0x79, 0x69, 0x35,
+	0x9c, 0x0f, 0xa2, 0xc5, 0x73, 0xe4, 0x4e, 0x9c, 0xe3, 0xb7, 0x2c, 0x14, 0xf7, 0xe4, 0x42, 0xe4,
+	0x56, 0x4a, 0x29, 0x7a, 0xec, 0x0a, 0xd1, 0xbe, 0x67, 0xc7, 0xb6, 0x26, 0x8d, 0xd7, 0xa1, 0xee,
+	0xfa, 0x63, 0xd7, 0xe7, 0x5a, 0x96, 0x24, 0x28, 0xa2, 0x6a, 0xd2, 0x9a, 0x30, 0xf3, 0x0e, 0x14,
+	0x64, 0x50, 0xb8, 0x7f, 0xa5, 0xd3, 0x3c, 0x11, 0xba, 0x42, 0x52, 0x85, 0x23, 0x04, 0x72, 0x78,
+	0x3b, 0xc4, 0x5d, 0xca, 0x52, 0xfc, 0x26, 0x9f, 0x42, 0x6d, 0x18, 0x32, 0x1b, 0xb5, 0xe7, 0xd8,
+	0xb1, 0xbc, 0x3a, 0x95, 0x4e, 0xab, 0x2d, 0x2b, 0x4e, 0x3b, 0xa9, 0x38, 0xed, 0xfd, 0xa4, 0xe2,
+	0xd0, 0x6a, 0xe2, 0xc0, 0xe3, 0x66, 0x64, 0x0b, 0xd6, 0xd8, 0xd3, 0x89, 0x1b, 0x6a, 0x4b, 0x14,
+	0x9f, 0xb9, 0x44, 0x7d, 0xee, 0x82, 0x8b, 0xb4, 0xa0, 0xe4, 0xb1, 0xd8, 0xe6, 0xde, 0x76, 0xb3,
+	0x84, 0x87, 0x9d, 0x8d, 0x49, 0x13, 0x8a, 0xbc, 0xb6, 0x45, 0x1c, 0xda, 0x2c, 0xa3, 0xd0, 0x92,
+	0xa1, 0xa8, 0x34, 0xd1, 0xd4, 0xe5, 0x1b, 0x82, 0xac, 0x34, 0x38, 0x10, 0x77, 0x9c, 0xef, 0xf8,
+	0x2d, 0x1b, 0xc6, 0x22, 0xed, 0x15, 0x4c, 0x7b, 0x59, 0x59, 0xfa, 0x8e, 0x69, 0x42, 0x29, 0xe1,
+	0x5b, 0xc8, 0xbf, 0xbf, 0xb3, 0xdd, 0xdf, 0xe9, 0x71, 0xf9, 0xf3, 0x6f, 0xda, 0x7b, 0xb8, 0xbb,
+	0xdf, 0x6b, 0x18, 0xe6, 0x4f, 0x06, 0xc0, 0xde, 0x34, 0xe6, 0xd5, 0x6c, 0xca, 0x03, 0x16, 0xbc,
+	0x4d, 0xec, 0x78, 0x84, 0x19, 0x2c, 0x53, 0xfc, 0xe6, 0x75, 0xa7, 0xa8, 0xe8, 0x46, 0x65, 0x55,
+	0x3a, 0xe4, 0x64, 0x62, 0x69, 0x02, 0x11, 0x37, 0x62, 0x73, 0xaf, 0x8f, 0x97, 0x5b, 0xe6, 0xb2,
+	0xc0, 0x87, 0xe2, 0x32, 0xdf, 0x80, 0x35, 0xd7, 0x61, 0xde, 0x84, 0xa7, 0x87, 0x0b, 0x16, 0x01,
+	0x39, 0xdc, 0xa5, 0xae, 0x99, 0x39, 0xd0, 0xfc, 0x10, 0xe0, 0x3e, 0x3b, 0x35, 0x22, 0x6d, 0x8f,
+	0x8c, 0xbe, 0x87, 0xf9, 0x8f, 0x01, 0x95, 0x6d, 0x37, 0x9a, 0x39, 0xaf, 0x43, 0x61, 0x12, 0xb2,
+	0x43, 0xf7, 0xa9, 0x72, 0x57, 0x23, 0xa1, 0x6a, 0x2c, 0x27, 0x96, 0x7d, 0x98, 0x1c, 0xab, 0x4c,
+	0x01, 0x4d, 0x9b, 0xc2, 0x22, 0x98, 0x65, 0xbe, 0x63, 0x1d, 0xb0, 0x43, 0xde, 0x57, 0xf0, 0x20,
+	0x9c, 0x59, 0x6e, 0xe9, 0xa2, 0x81, 0x5c, 0x86, 0x72, 0xc8, 0x86, 0x53, 0x9e, 0x9b, 0x27, 0x52,
+	0x93, 0xbc, 0xb6, 0xce, 0x0c, 0x22, 0x59, 0x63, 0xd7, 0x73, 0x63, 0x55, 0xc9, 0xe5, 0x40, 0x2c,
+	0x29, 0x12, 0x6d, 0x1d, 0x8e, 0xed, 0xa3, 0x08, 0xb5, 0x57, 0xa4, 0x65, 0x61, 0xf9, 0x5c, 0x18,
+	0xf4, 0x33, 0x15, 0x53, 0xbc, 0xf1, 0x33, 0x88, 0x85, 0x83, 0x10, 0xe5, 0xc2, 0xcf, 0x20, 0x47,
+	0xe6, 0x0e, 0x54, 0x30, 0x71, 0xd1, 0x24, 0xf0, 0xa3, 0x25, 0xea, 0x36, 0x5e, 0x4c, 0xdd, 0xe6,
+	0x36, 0x54, 0x90, 0x76, 0xb5, 0x5e, 0x73, 0x9e, 0x75, 0x03, 0xe3, 0x99, 0x65, 0xf8, 0x3a, 0xe4,
+	0x45, 0x91, 0x8b, 0x38, 0x6d, 0xa2, 0x8e, 0xd4, 0xda, 0x49, 0x43, 0xde, 0xe1, 0x56, 0x2a, 0xe7,
+	0xcc, 0x3f, 0x0d, 0xa8, 0xca, 0x4c, 0xa8, 0xf5, 0x3a, 0x90, 0xe7, 0x92, 0xf5, 0x22, 0xbe, 0x9a,
+	0xf0, 0xba, 0xac, 0x69, 0x48, 0xc7, 0xb5, 0xfb, 0x1c, 0x44, 0x25, 0x54, 0xe4, 0xde, 0x13, 0xfc,
+	0x67, 0x90, 0x61, 0xfc, 0xd6, 0xe8, 0xc8, 0xea, 0x74, 0xb4, 0x18, 0xe4, 0x84, 0xeb, 0xff, 0xa0,
+	0x60, 0x5e, 0xf0, 0xdd, 0xc8, 0x52, 0xba, 0xc9, 0xe2, 0xd6, 0x25, 0x37, 0xda, 0xc3, 0xb1, 0xf9,
+	0x11, 0xd4, 0xee, 0xb1, 0x31, 0x8b, 0xd9, 0x4b, 0xe9, 0xb3, 0x01, 0xf5, 0xc4, 0x5b, 0x1e, 0xd7,
+	0xfc, 0xdd, 0x00, 0xb2, 0x1b, 0x3a, 0x2c, 0xdc, 0x16, 0x22, 0x89, 0x4e, 0x5b, 0xb5, 0x0f, 0x05,
+	0x7b, 0x28, 0xd2, 0x85, 0x8b, 0xd6, 0x3b, 0xb7, 0xdb, 0xf3, 0xa7, 0x4f, 0x18, 0x4c, 0x63, 0x16,
+	0xb5, 0xf7, 0xec, 0x63, 0x16, 0x76, 0x6d, 0xdf, 0xf9, 0xce, 0x75, 0xe2, 0xd1, 0xe6, 0x78, 0x1c,
+	0x0c, 0x31, 0xc1, 0xed, 0x4d, 0x74, 0xa4, 0x6a, 0x81, 0x54, 0xb7, 0xc8, 0xa6, 0xbb, 0x05, 0x9f,
+	0x52, 0x1d, 0x2d, 0xe2, 0xca, 0xce, 0x8a, 0x29, 0xd9, 0xd2, 0x52, 0x12, 0xcd, 0xa7, 0x8e, 0xf5,
+	0x15, 0xbc, 0x92, 0x3a, 0x83, 0x4a, 0x79, 0x17, 0x0a, 0x28, 0xfd, 0x24, 0xe7, 0x37, 0x9f, 0x3f,
+	0x60, 0xaa, 0x3c, 0xcd, 0x0d, 0xd1, 0x25, 0x9f, 0x04, 0x8f, 0x66, 0x7c, 0x6b, 0x41, 0x18, 0x8b,
+	0xdc, 0x26, 0x48, 0xc5, 0xed, 0x5f, 0x06, 0x34, 0xba, 0x76, 0x3c, 0x1c, 0x29, 0x5f, 0xd4, 0xc7,
+	0x0d, 0xc8, 0x4e, 0xa6, 0xb1, 0xba, 0x1d, 0xe7, 0x75, 0x1d, 0xcc, 0xaa, 0x20, 0x15, 0x08, 0x01,
+	0x3c, 0x62, 0xb1, 0x12, 0x8c, 0x0e, 0x9c, 0x17, 0x27, 0x2a, 0x10, 0xa2, 0x3b, 0x39, 0x98, 0x54,
+	0xa4, 0x32, 0xdd, 0x9d, 0x52, 0x5a, 0xa1, 0x0a, 0x47, 0x3e, 0x83, 0x6a, 0x20, 0xf8, 0xb2, 0x14,
+	0x3d, 0xb2, 0xab, 0x5d, 0xd1, 0xfc, 0x4e, 0x4a, 0x82, 0x56, 0x82, 0xb9, 0xcd, 0xfc, 0x06, 0xaa,
+	0xfa, 0xc9, 0xc8, 0xfb, 0x50, 0x0a, 0xe5, 0x67, 0x42, 0xb6, 0xde, 0x7d, 0x17, 0x49, 0xa0, 0x33,
+	0xf0, 0x6a, 0xa9, 0xfe, 0x6d, 0xc0, 0x59, 0xe5, 0x27, 0xe9, 0x44, 0xf6, 0x36, 0x74, 0xf6, 0xd6,
+	0x17, 0xd9, 0x93, 0x40, 0x49, 0xdf, 0x86, 0x4e, 0xdf, 0xfa, 0x22, 0x7d, 0x09, 0x52, 0xf0, 0x77,
+	0x7b, 0x81, 0xbf, 0x8b, 0x4b, 0xf8, 0x53, 0xf8, 0x84, 0xc0, 0xcd, 0xa5, 0x04, 0xbe, 0xba, 0x8a,
+	0x40, 0xe5, 0x9d, 0x62, 0xf0, 0x01, 0xd4, 0x52, 0xc7, 0xe3, 0xbf, 0x10, 0x78, 0x09, 0x97, 0xdf,
+	0xcb, 0x8a, 0xd4, 0x09, 0x2e, 0xe8, 0x1c, 0x6e, 0x6e, 0xc1, 0x39, 0x7e, 0xac, 0xdd, 0x03, 0x6c,
+	0xbc, 0xfe, 0x61, 0xf0, 0x52, 0xc5, 0xe1, 0x57, 0xde, 0xbc, 0xd4, 0x3b, 0x46, 0xac, 0xb1, 0xd4,
+	0x39, 0x79, 0x61, 0x65, 0x9e, 0xf7, 0x85, 0x95, 0x3c, 0x84, 0xb2, 0xda, 0x43, 0x28, 0xfd, 0xd0,
+	0xcc, 0xbd, 0xd8, 0x43, 0x53, 0xb4, 0x54, 0xf9, 0x8c, 0x94, 0xdd, 0x4d, 0x8d, 0xcc, 0x9f, 0x33,
+	0x70, 0x7e, 0x81, 0x03, 0x45, 0x6c, 0x12, 0x82, 0x71, 0xda, 0x5b, 0x2c, 0xf3, 0xdf, 0xdf, 0x62,
+	0xd9, 0x17, 0x7e, 0x8b, 0x75, 0xa0, 0xa4, 0xde, 0x9d, 0xb2, 0xd6, 0xa5, 0x85, 0xaa, 0xe5, 0x82,
+	0xce, 0x70, 0xe4, 0x35, 0xa8, 0x0e, 0x03, 0x8e, 0xf0, 0x63, 0x0b, 0x33, 0x91, 0xc7, 0xec, 0x54,
+	0x94, 0x0d, 0xdf, 0x5a, 0xd7, 0xa1, 0x96, 0x3c, 0xe9, 0x44, 0x9a, 0x45, 0xb3, 0x17, 0x75, 0xb4,
+	0x9a, 0x18, 0x79, 0xb2, 0xa3, 0xce, 0x8f, 0x39, 0x28, 0xab, 0x9c, 0xdd, 0xeb, 0x92, 0xf7, 0x20,
+	0xcb, 0x6f, 0x10, 0x59, 0x5e, 0x8f, 0x5a, 0x2b, 0x2e, 0x9a, 0xf0, 0xe2, 0x94, 0x93, 0xe5, 0xc5,
+	0xa9, 0xb5, 0xe2, 0xd2, 0xf1, 0x5a, 0x91, 0x13, 0x1d, 0x97, 0xac, 0x9f, 0x68, 0xc1, 0xd2, 0xef,
+	0xc2, 0x8a, 0xd6, 0x4c, 0x3e, 0x86, 0x82, 0xbc, 0x8f, 0x64, 0x65, 0x89, 0x6b, 0xad, 0xbe, 0xbc,
+	0x84, 0x3f, 0x30, 0xb4, 0x5b, 0x49, 0x4e, 0x2f, 0x77, 0xad, 0x67, 0x5c, 0x66, 0x11, 0x8c, 0x2c,
+	0xf7, 0x24, 0xfd, 0x6b, 0x40, 0xeb, 0x15, 0xa9, 0x60, 0xd2, 0xbd, 0x81, 0xdf, 0xf6, 0x3c, 0xde,
+	0x68, 0x72, 0x61, 0x45, 0x9d, 0x6c, 0x35, 0x57, 0x5d, 0x7e, 0x42, 0xa1, 0x96, 0x52, 0x3a, 0xb9,
+	0x9a, 0x66, 0xfa, 0x44, 0x1d, 0x68, 0x5d, 0x5b, 0x0d, 0x90, 0x6b, 0x76, 0x73, 0x5f, 0x67, 0x26,
+	0x07, 0x07, 0x05, 0x14, 0xed, 0xbb, 0xff, 0x02, 0x5c, 0x3d, 0x72, 0xa5, 0x21, 0x11, 0x00, 0x00,
 }
@@ -0,0 +1,129 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package peertls
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"time"
+
+	"github.com/zeebo/errs"
+)
+
+// RevocationExtID is the object identifier of the leaf certificate extension
+// carrying the revocation of the leaf it replaced
+var RevocationExtID = asn1.ObjectIdentifier{2, 999, 1, 1}
+
+// ErrRevoked is returned for certificates that were revoked
+var ErrRevoked = errs.Class("certificate revoked")
+
+// Revocation revokes a leaf certificate. It's signed by the CA of the leaf,
+// so it can be passed around by anyone.
+type Revocation struct {
+	// Timestamp is the unix time of the revocation
+	Timestamp int64
+	// CertHash is the sha256 hash of the revoked leaf
+	CertHash []byte
+	// Signature is the CA's signature of the timestamp and hash
+	Signature []byte
+}
+
+// NewRevocation creates a revocation of leaf, signed by the CA key
+func NewRevocation(leaf *x509.Certificate, caKey crypto.PrivateKey) (*Revocation, error) {
+	k, ok := caKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, ErrUnsupportedKey.New("%T", caKey)
+	}
+
+	hash := sha256.Sum256(leaf.Raw)
+	rev := &Revocation{
+		Timestamp: time.Now().Unix(),
+		CertHash:  hash[:],
+	}
+	r, s, err := ecdsa.Sign(rand.Reader, k, rev.digest())
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	rev.Signature, err = asn1.Marshal(ecdsaSignature{R: r, S: s})
+	if err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return rev, nil
+}
+
+// digest returns the hash the CA signs
+func (rev *Revocation) digest() []byte {
+	h := sha256.New()
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], uint64(rev.Timestamp))
+	_, _ = h.Write(ts[:])
+	_, _ = h.Write(rev.CertHash)
+	return h.Sum(nil)
+}
+
+// Verify checks that the revocation is signed by ca
+func (rev *Revocation) Verify(ca *x509.Certificate) error {
+	pubKey, ok := ca.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return ErrUnsupportedKey.New("%T", ca.PublicKey)
+	}
+	var signature ecdsaSignature
+	if _, err := asn1.Unmarshal(rev.Signature, &signature); err != nil {
+		return ErrVerifySignature.New("unable to unmarshal ecdsa signature: %v", err)
+	}
+	if !ecdsa.Verify(pubKey, rev.digest(), signature.R, signature.S) {
+		return ErrVerifySignature.New("revocation signature verification failed")
+	}
+	return nil
+}
+
+// Revokes returns whether the revocation revokes leaf
+func (rev *Revocation) Revokes(leaf *x509.Certificate) bool {
+	hash := sha256.Sum256(leaf.Raw)
+	return bytes.Equal(rev.CertHash, hash[:])
+}
+
+// Marshal encodes the revocation
+func (rev *Revocation) Marshal() ([]byte, error) {
+	data, err := asn1.Marshal(*rev)
+	return data, errs.Wrap(err)
+}
+
+// UnmarshalRevocation decodes a revocation encoded with Marshal
+func UnmarshalRevocation(data []byte) (*Revocation, error) {
+	rev := &Revocation{}
+	if _, err := asn1.Unmarshal(data, rev); err != nil {
+		return nil, errs.Wrap(err)
+	}
+	return rev, nil
+}
+
+// Extension returns the certificate extension carrying the revocation, to
+// add to the leaf that replaces the revoked one. Peers learn about the
+// revocation when they verify the new leaf.
+func (rev *Revocation) Extension() (pkix.Extension, error) {
+	data, err := rev.Marshal()
+	if err != nil {
+		return pkix.Extension{}, err
+	}
+	return pkix.Extension{Id: RevocationExtID, Value: data}, nil
+}
+
+// RevocationFromCert returns the revocation carried by a leaf certificate,
+// or nil if it carries none
+func RevocationFromCert(leaf *x509.Certificate) (*Revocation, error) {
+	for _, ext := range leaf.Extensions {
+		if ext.Id.Equal(RevocationExtID) {
+			return UnmarshalRevocation(ext.Value)
+		}
+	}
+	return nil, nil
+}
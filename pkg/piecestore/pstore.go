@@ -9,10 +9,12 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"time"
 
 	"github.com/zeebo/errs"
 
 	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/utils"
 )
 
 // IDLength -- Minimum ID length
@@ -120,3 +122,72 @@ func Delete(id string, dir string) error {
 
 	return nil
 }
+
+// TrashDir is the directory, relative to the pstore directory, that pieces
+// are moved to when they are no longer retained
+const TrashDir = "trash"
+
+// Trash moves data from the pstore directory into its trash directory. The
+// modification time of the data is set to when it was trashed.
+//	id is the id of the data to be trashed
+//	dir is the pstore directory containing all other data stored
+//	returns error if failed and nil if successful
+func Trash(id string, dir string) error {
+	dataPath, err := PathByID(id, dir)
+	if err != nil {
+		return err
+	}
+
+	trashPath, err := PathByID(id, filepath.Join(dir, TrashDir))
+	if err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(dataPath); os.IsNotExist(err) {
+		return nil
+	}
+
+	if err = os.MkdirAll(filepath.Dir(trashPath), 0700); err != nil {
+		return err
+	}
+
+	if err = os.Rename(dataPath, trashPath); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	return os.Chtimes(trashPath, now, now)
+}
+
+// EmptyTrash deletes the data trashed before trashedBefore
+//	dir is the pstore directory containing all other data stored
+//	returns the number of deleted pieces, and an error if some couldn't be
+//	deleted
+func EmptyTrash(dir string, trashedBefore time.Time) (deleted int, err error) {
+	if dir == "" {
+		return 0, ArgError.New("No path provided")
+	}
+
+	var errs []error
+	err = filepath.Walk(filepath.Join(dir, TrashDir), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || !info.ModTime().Before(trashedBefore) {
+			return nil
+		}
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		deleted++
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return deleted, utils.CombineErrors(errs...)
+}
@@ -5,10 +5,12 @@ package pstore
 
 import (
 	"context"
+	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -272,3 +274,73 @@ func TestDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestTrash(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := filepath.Join(os.TempDir(), "storj-pstore-trash")
+	defer func() { assert.NoError(os.RemoveAll(dir)) }()
+
+	id := "11111111111111111111"
+	file, err := StoreWriter(id, dir)
+	if !assert.NoError(err) {
+		return
+	}
+	_, err = file.Write([]byte("butts"))
+	assert.NoError(err)
+	assert.NoError(file.Close())
+
+	assert.NoError(Trash(id, dir))
+
+	dataPath, err := PathByID(id, dir)
+	assert.NoError(err)
+	_, err = os.Stat(dataPath)
+	assert.True(os.IsNotExist(err))
+
+	trashPath, err := PathByID(id, filepath.Join(dir, TrashDir))
+	assert.NoError(err)
+	data, err := ioutil.ReadFile(trashPath)
+	assert.NoError(err)
+	assert.Equal("butts", string(data))
+
+	// trashing a missing piece is not an error
+	assert.NoError(Trash(id, dir))
+
+	assert.EqualError(Trash("111111", dir), "argError: Invalid id length")
+}
+
+func TestEmptyTrash(t *testing.T) {
+	assert := assert.New(t)
+
+	dir := filepath.Join(os.TempDir(), "storj-pstore-empty-trash")
+	defer func() { assert.NoError(os.RemoveAll(dir)) }()
+
+	// there is no trash yet
+	deleted, err := EmptyTrash(dir, time.Now())
+	assert.NoError(err)
+	assert.Equal(0, deleted)
+
+	id := "11111111111111111111"
+	file, err := StoreWriter(id, dir)
+	if !assert.NoError(err) {
+		return
+	}
+	assert.NoError(file.Close())
+	assert.NoError(Trash(id, dir))
+
+	// data is trashed now, not when it was stored
+	deleted, err = EmptyTrash(dir, time.Now().Add(-time.Minute))
+	assert.NoError(err)
+	assert.Equal(0, deleted)
+
+	deleted, err = EmptyTrash(dir, time.Now().Add(time.Minute))
+	assert.NoError(err)
+	assert.Equal(1, deleted)
+	trashPath, err := PathByID(id, filepath.Join(dir, TrashDir))
+	assert.NoError(err)
+	_, err = os.Stat(trashPath)
+	assert.True(os.IsNotExist(err))
+
+	_, err = EmptyTrash("", time.Now())
+	assert.EqualError(err, "argError: No path provided")
+}
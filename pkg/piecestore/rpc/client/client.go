@@ -17,6 +17,8 @@ import (
 	"go.uber.org/zap"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/gtank/cryptopasta"
 
@@ -27,6 +29,39 @@ import (
 // ClientError is any error returned by the client
 var ClientError = errs.Class("PSClient error")
 
+// IsBusy checks if err is the error of a request the node refused because it
+// already serves as many requests as it is configured to. The request should
+// be retried on another node.
+func IsBusy(err error) bool {
+	return errs.IsFunc(err, func(err error) bool {
+		return status.Code(err) == codes.ResourceExhausted
+	})
+}
+
+// IsNotFound checks if err is the error of a request for a piece the node
+// doesn't have
+func IsNotFound(err error) bool {
+	return errs.IsFunc(err, func(err error) bool {
+		return status.Code(err) == codes.NotFound
+	})
+}
+
+// IsLimitExceeded checks if err is the error of a transfer the node aborted
+// because it exceeded its order limit or the bandwidth allocated to it
+func IsLimitExceeded(err error) bool {
+	return errs.IsFunc(err, func(err error) bool {
+		return status.Code(err) == codes.OutOfRange
+	})
+}
+
+// IsUnimplemented checks if err is the error of a request the node doesn't
+// support yet
+func IsUnimplemented(err error) bool {
+	return errs.IsFunc(err, func(err error) bool {
+		return status.Code(err) == codes.Unimplemented
+	})
+}
+
 var (
 	defaultBandwidthMsgSize = flag.Int(
 		"piecestore.rpc.client.default_bandwidth_msg_size", 32*1024,
@@ -39,10 +74,14 @@ var (
 // PSClient is an interface describing the functions for interacting with piecestore nodes
 type PSClient interface {
 	Meta(ctx context.Context, id PieceID) (*pb.PieceSummary, error)
+	StatPieces(ctx context.Context, ids []PieceID) ([]*pb.PieceStat, error)
 	Put(ctx context.Context, id PieceID, data io.Reader, ttl time.Time, ba *pb.PayerBandwidthAllocation) error
 	Get(ctx context.Context, id PieceID, size int64, ba *pb.PayerBandwidthAllocation) (ranger.Ranger, error)
-	Delete(ctx context.Context, pieceID PieceID) error
+	Delete(ctx context.Context, pieceID PieceID, ba *pb.PayerBandwidthAllocation) error
 	Stats(ctx context.Context) error
+	Retain(ctx context.Context, createdBefore time.Time, filter []byte) (*pb.RetainSummary, error)
+	Challenge(ctx context.Context, id PieceID, leaf int64) (*pb.ChallengeResponse, error)
+	Proof(ctx context.Context, id PieceID, first, last int64) (*pb.ProofResponse, error)
 	io.Closer
 }
 
@@ -99,43 +138,97 @@ func (client *Client) Meta(ctx context.Context, id PieceID) (*pb.PieceSummary, e
 	return client.route.Piece(ctx, &pb.PieceId{Id: id.String()})
 }
 
+// StatPieces requests whether the pieces exist and, if they do, their size,
+// creation time and hash, in the order of ids
+func (client *Client) StatPieces(ctx context.Context, ids []PieceID) ([]*pb.PieceStat, error) {
+	req := &pb.StatPiecesRequest{Ids: make([]string, 0, len(ids))}
+	for _, id := range ids {
+		req.Ids = append(req.Ids, id.String())
+	}
+	reply, err := client.route.StatPieces(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return reply.GetPieces(), nil
+}
+
+// Challenge asks a piece store Server for the leaf-th leaf of the Merkle
+// tree of a piece and its proof
+func (client *Client) Challenge(ctx context.Context, id PieceID, leaf int64) (*pb.ChallengeResponse, error) {
+	return client.route.Challenge(ctx, &pb.ChallengeRequest{PieceId: id.String(), Leaf: leaf})
+}
+
+// Proof asks a piece store Server for the hashes of the leaves of the Merkle
+// tree of a piece from first to last, inclusive, and their proof
+func (client *Client) Proof(ctx context.Context, id PieceID, first, last int64) (*pb.ProofResponse, error) {
+	return client.route.Proof(ctx, &pb.ProofRequest{PieceId: id.String(), FirstLeaf: first, LastLeaf: last})
+}
+
 // Put uploads a Piece to a piece store Server
 func (client *Client) Put(ctx context.Context, id PieceID, data io.Reader, ttl time.Time, ba *pb.PayerBandwidthAllocation) error {
+	_, err := client.store(ctx, &pb.PieceStore_PieceData{Id: id.String(), ExpirationUnixSec: ttl.Unix()}, data, ba)
+	return err
+}
+
+// Transfer uploads a Piece of the exiting node exitingNodeID to a piece store
+// Server, and returns the receipt the Server signed for it
+func (client *Client) Transfer(ctx context.Context, id PieceID, data io.Reader, ttl time.Time, ba *pb.PayerBandwidthAllocation, exitingNodeID string) (*pb.PieceTransferReceipt, error) {
+	summary, err := client.store(ctx, &pb.PieceStore_PieceData{
+		Id:                id.String(),
+		ExpirationUnixSec: ttl.Unix(),
+		ExitingNodeId:     []byte(exitingNodeID),
+	}, data, ba)
+	if err != nil {
+		return nil, err
+	}
+	if summary.GetReceipt() == nil {
+		return nil, ClientError.New("no transfer receipt for piece %s", id)
+	}
+	return summary.GetReceipt(), nil
+}
+
+// store uploads the data of a Piece to a piece store Server. The summary of
+// the upload is nil if the Server didn't return one.
+func (client *Client) store(ctx context.Context, pd *pb.PieceStore_PieceData, data io.Reader, ba *pb.PayerBandwidthAllocation) (*pb.PieceStoreSummary, error) {
+	id := PieceID(pd.GetId())
 	stream, err := client.route.Store(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	msg := &pb.PieceStore{Piecedata: &pb.PieceStore_PieceData{Id: id.String(), ExpirationUnixSec: ttl.Unix()}}
+	msg := &pb.PieceStore{Piecedata: pd}
 	if err = stream.Send(msg); err != nil {
 		if _, closeErr := stream.CloseAndRecv(); closeErr != nil {
 			zap.S().Errorf("error closing stream %s :: %v.Send() = %v", closeErr, stream, closeErr)
 		}
 
-		return fmt.Errorf("%v.Send() = %v", stream, err)
+		return nil, fmt.Errorf("%v.Send() = %v", stream, err)
 	}
 
 	writer := &StreamWriter{signer: client, stream: stream, pba: ba}
 
-	defer func() {
-		if err := writer.Close(); err != nil && err != io.EOF {
-			log.Printf("failed to close writer: %s\n", err)
-		}
-	}()
-
 	bufw := bufio.NewWriterSize(writer, 32*1024)
 
 	_, err = io.Copy(bufw, data)
 	if err == io.ErrUnexpectedEOF {
 		_ = writer.Close()
 		zap.S().Infof("Node cut from upload due to slow connection. Deleting piece %s...", id)
-		return client.Delete(ctx, id)
+		// nodes verifying order limits refuse the delete, but delete the
+		// incomplete piece themselves
+		return nil, client.Delete(ctx, id, nil)
+	}
+	if err == nil {
+		err = bufw.Flush()
+	}
+
+	if closeErr := writer.Close(); closeErr != nil && closeErr != io.EOF {
+		log.Printf("failed to close writer: %s\n", closeErr)
 	}
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return bufw.Flush()
+	return writer.summary, nil
 }
 
 // Get begins downloading a Piece from a piece store Server
@@ -148,9 +241,9 @@ func (client *Client) Get(ctx context.Context, id PieceID, size int64, ba *pb.Pa
 	return PieceRangerSize(client, stream, id, size, ba), nil
 }
 
-// Delete a Piece from a piece store Server
-func (client *Client) Delete(ctx context.Context, id PieceID) error {
-	reply, err := client.route.Delete(ctx, &pb.PieceDelete{Id: id.String()})
+// Delete a Piece from a piece store Server, authorized by the order limit ba
+func (client *Client) Delete(ctx context.Context, id PieceID, ba *pb.PayerBandwidthAllocation) error {
+	reply, err := client.route.Delete(ctx, &pb.PieceDelete{Id: id.String(), OrderLimit: ba})
 	if err != nil {
 		return err
 	}
@@ -169,6 +262,15 @@ func (client *Client) Stats(ctx context.Context) error {
 	return nil
 }
 
+// Retain asks a piece store Server to delete all pieces created before
+// createdBefore that are not in the serialized bloom filter
+func (client *Client) Retain(ctx context.Context, createdBefore time.Time, filter []byte) (*pb.RetainSummary, error) {
+	return client.route.Retain(ctx, &pb.RetainRequest{
+		CreationUnixSec: createdBefore.Unix(),
+		Filter:          filter,
+	})
+}
+
 // sign a message using the clients private key
 func (client *Client) sign(msg []byte) (signature []byte, err error) {
 	if client.prikey == nil {
@@ -5,6 +5,7 @@ package client
 
 import (
 	"fmt"
+	"io"
 	"log"
 
 	"github.com/gogo/protobuf/proto"
@@ -20,6 +21,7 @@ type StreamWriter struct {
 	signer       *Client // We need this for signing
 	totalWritten int64
 	pba          *pb.PayerBandwidthAllocation
+	summary      *pb.PieceStoreSummary
 }
 
 // Write Piece data to a piece store server upload stream
@@ -51,6 +53,13 @@ func (s *StreamWriter) Write(b []byte) (int, error) {
 
 	// Second we send the actual content
 	if err := s.stream.Send(msg); err != nil {
+		if err == io.EOF {
+			// the server ended the stream, the reason is returned by
+			// CloseAndRecv
+			if _, closeErr := s.stream.CloseAndRecv(); closeErr != nil {
+				return 0, closeErr
+			}
+		}
 		return 0, fmt.Errorf("%v.Send() = %v", s.stream, err)
 	}
 
@@ -65,6 +74,7 @@ func (s *StreamWriter) Close() error {
 	}
 
 	log.Printf("Route summary: %v", reply)
+	s.summary = reply
 
 	return nil
 }
@@ -0,0 +1,143 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package server
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/orders"
+	"storj.io/storj/pkg/pb"
+)
+
+// Allocation is the disk space and monthly bandwidth of a node allocated to
+// a satellite. The zero values don't limit anything.
+type Allocation struct {
+	Space     int64
+	Bandwidth int64
+}
+
+// ParseAllocations parses the allocations of satellites like
+// "ID1=100000000000/500000000000,ID2=50000000000/0", the disk space and the
+// monthly bandwidth in bytes of each satellite
+func ParseAllocations(s string) (map[string]Allocation, error) {
+	allocations := map[string]Allocation{}
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, ServerError.New("invalid satellite allocation %q", entry)
+		}
+		amounts := strings.SplitN(parts[1], "/", 2)
+		if len(amounts) != 2 {
+			return nil, ServerError.New("invalid satellite allocation %q: expected space/bandwidth", entry)
+		}
+		space, err := strconv.ParseInt(amounts[0], 10, 64)
+		if err != nil || space < 0 {
+			return nil, ServerError.New("invalid space of satellite allocation %q", entry)
+		}
+		bandwidth, err := strconv.ParseInt(amounts[1], 10, 64)
+		if err != nil || bandwidth < 0 {
+			return nil, ServerError.New("invalid bandwidth of satellite allocation %q", entry)
+		}
+		allocations[parts[0]] = Allocation{Space: space, Bandwidth: bandwidth}
+	}
+	return allocations, nil
+}
+
+// Allocations returns the disk space and bandwidth allocated to each
+// satellite
+func (s *Server) Allocations() map[string]Allocation {
+	allocations := make(map[string]Allocation, len(s.allocations))
+	for satellite, allocation := range s.allocations {
+		allocations[satellite] = allocation
+	}
+	return allocations
+}
+
+// errAllocationFull returns the error of the uploads refused because the
+// disk space allocated to their satellite is used up. Like busy nodes,
+// uplinks recognize it with client.IsBusy and use other nodes instead.
+func errAllocationFull(satellite string) error {
+	return status.Errorf(codes.ResourceExhausted, "node full: space allocated to satellite %s used up", satellite)
+}
+
+// satelliteOf returns the id of the satellite that issued the order limit,
+// without verifying its signature. It's only trusted once the order limit
+// was verified.
+func satelliteOf(limit *pb.PayerBandwidthAllocation) string {
+	data := &pb.PayerBandwidthAllocation_Data{}
+	if err := proto.Unmarshal(limit.GetData(), data); err != nil {
+		return ""
+	}
+	return string(data.GetPayer())
+}
+
+// limitAction returns the action of the order limit, without verifying its
+// signature, or request if the order limit isn't for request
+func limitAction(limit *pb.PayerBandwidthAllocation, request pb.PayerBandwidthAllocation_Action) pb.PayerBandwidthAllocation_Action {
+	data := &pb.PayerBandwidthAllocation_Data{}
+	if err := proto.Unmarshal(limit.GetData(), data); err != nil {
+		return request
+	}
+	if orders.Request(data.GetAction()) != request {
+		return request
+	}
+	return data.GetAction()
+}
+
+// satelliteSpace returns the disk space used by the pieces of satellite and
+// the space its uploads may still use, which is the available space unless
+// the satellite has a smaller allocation left. The available space is -1 if
+// it's unknown.
+func (s *Server) satelliteSpace(satellite string) (used, available int64, err error) {
+	available = s.AvailableSpace()
+	allocated := s.allocations[satellite].Space
+	if allocated <= 0 {
+		return 0, available, nil
+	}
+	used, err = s.DB.SumSatelliteTTLSizes(satellite)
+	if err != nil {
+		return 0, 0, err
+	}
+	left := allocated - used
+	if left < 0 {
+		left = 0
+	}
+	if available < 0 || left < available {
+		available = left
+	}
+	return used, available, nil
+}
+
+// checkAllocation returns a ResourceExhausted error if the requests of
+// action for satellite can't be served anymore, because the monthly
+// bandwidth caps of the node or of the satellite were reached or, for
+// uploads, because the disk space allocated to the satellite is used up
+func (s *Server) checkAllocation(satellite string, action pb.PayerBandwidthAllocation_Action) error {
+	if err := s.bandwidth.check(satellite, action); err != nil {
+		if ErrBandwidthCap.Has(err) {
+			return errBandwidthCap(err)
+		}
+		return err
+	}
+	if action != pb.PayerBandwidthAllocation_PUT {
+		return nil
+	}
+	_, available, err := s.satelliteSpace(satellite)
+	if err != nil {
+		return err
+	}
+	if available == 0 && s.allocations[satellite].Space > 0 {
+		return errAllocationFull(satellite)
+	}
+	return nil
+}
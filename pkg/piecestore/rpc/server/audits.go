@@ -0,0 +1,95 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"storj.io/storj/pkg/pb"
+)
+
+// AuditAlert is posted as JSON to the audit alert URL of the node when a
+// satellite reports that the node started failing its audits
+type AuditAlert struct {
+	NodeID    string `json:"node_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	Satellite string `json:"satellite"`
+	// AuditCount counts the audits the node was judged on, and
+	// AuditSuccessCount those it passed. MissedAudits counts the audits in a
+	// row it didn't answer.
+	AuditCount        int64     `json:"audit_count"`
+	AuditSuccessCount int64     `json:"audit_success_count"`
+	MissedAudits      int64     `json:"missed_audits"`
+	SuccessThreshold  float64   `json:"success_threshold"`
+	Time              time.Time `json:"time"`
+}
+
+// Audits returns the latest audit status sent by each satellite
+func (s *Server) Audits() map[string]*pb.AuditStatus {
+	s.vettingMu.Lock()
+	defer s.vettingMu.Unlock()
+	audits := make(map[string]*pb.AuditStatus, len(s.audits))
+	for satellite, audit := range s.audits {
+		audits[satellite] = audit
+	}
+	return audits
+}
+
+// recordAudits records the audit status sent by satellite, and alerts the
+// operator when the node starts failing the audits of the satellite
+func (s *Server) recordAudits(satellite string, audit *pb.AuditStatus) {
+	s.vettingMu.Lock()
+	wasFailing := s.audits[satellite].GetFailing()
+	s.audits[satellite] = audit
+	s.vettingMu.Unlock()
+
+	if !audit.GetFailing() || wasFailing {
+		return
+	}
+	log.Printf("WARNING: failing the audits of satellite %s: passed %d of %d audits, missed %d in a row. the node risks being disqualified",
+		satellite, audit.GetAuditSuccessCount(), audit.GetAuditCount(), audit.GetMissedAudits())
+
+	if s.auditAlertURL == "" {
+		return
+	}
+	alert := AuditAlert{
+		Email:             s.operator.GetEmail(),
+		Satellite:         satellite,
+		AuditCount:        audit.GetAuditCount(),
+		AuditSuccessCount: audit.GetAuditSuccessCount(),
+		MissedAudits:      audit.GetMissedAudits(),
+		SuccessThreshold:  audit.GetSuccessThreshold(),
+		Time:              time.Now().UTC(),
+	}
+	if s.identity != nil {
+		alert.NodeID = s.identity.ID.String()
+	}
+	go func() {
+		if err := s.postAuditAlert(alert); err != nil {
+			log.Printf("Failed to post the audit alert: %v", err)
+		}
+	}()
+}
+
+// postAuditAlert posts alert to the audit alert URL
+func (s *Server) postAuditAlert(alert AuditAlert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return ServerError.Wrap(err)
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(s.auditAlertURL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return ServerError.Wrap(err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		return ServerError.New("audit alert refused: %s", resp.Status)
+	}
+	return nil
+}
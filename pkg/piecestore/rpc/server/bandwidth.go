@@ -0,0 +1,170 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package server
+
+import (
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/server/psdb"
+)
+
+// ErrBandwidthCap is the class of the errors of the order limits refused
+// because the monthly bandwidth cap of their action was reached
+var ErrBandwidthCap = errs.Class("bandwidth cap exceeded")
+
+// errBandwidthCap returns the error of the requests refused because of err.
+// Like busy nodes, uplinks recognize it with client.IsBusy and use other
+// nodes instead.
+func errBandwidthCap(err error) error {
+	return status.Error(codes.ResourceExhausted, err.Error())
+}
+
+// bandwidthCaps keeps track of the bandwidth used during the current calendar
+// month (UTC) and checks it against the monthly caps of the node and the
+// monthly bandwidth allocated to satellites. The caps of 0 don't limit
+// anything.
+type bandwidthCaps struct {
+	db      *psdb.DB
+	total   int64
+	ingress int64
+	egress  int64
+	// allocated is the monthly bandwidth allocated to each satellite
+	allocated map[string]int64
+
+	mu    sync.Mutex
+	month time.Time
+	used  psdb.BandwidthUsage
+	// usedBy is the bandwidth used by each satellite, if any is allocated
+	usedBy map[string]psdb.BandwidthUsage
+}
+
+func newBandwidthCaps(db *psdb.DB, config Config, allocations map[string]Allocation) *bandwidthCaps {
+	caps := &bandwidthCaps{
+		db:      db,
+		total:   config.MonthlyBandwidthCap,
+		ingress: config.MonthlyIngressCap,
+		egress:  config.MonthlyEgressCap,
+	}
+	for satellite, allocation := range allocations {
+		if allocation.Bandwidth > 0 {
+			if caps.allocated == nil {
+				caps.allocated = map[string]int64{}
+			}
+			caps.allocated[satellite] = allocation.Bandwidth
+		}
+	}
+	return caps
+}
+
+// BandwidthCaps returns the monthly caps of the bandwidth used by uploads
+// and downloads together, by uploads and by downloads. 0 means no limit.
+func (s *Server) BandwidthCaps() (total, ingress, egress int64) {
+	if s.bandwidth == nil {
+		return 0, 0, 0
+	}
+	return s.bandwidth.total, s.bandwidth.ingress, s.bandwidth.egress
+}
+
+// StartOfMonth returns the start of the calendar month of now in UTC, since
+// when the bandwidth used is checked against the monthly caps
+func StartOfMonth(now time.Time) time.Time {
+	now = now.UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// rollover loads the usage of the month of now from the database when the
+// month changed. It must be called with mu held.
+func (caps *bandwidthCaps) rollover(now time.Time) error {
+	month := StartOfMonth(now)
+	if month.Equal(caps.month) {
+		return nil
+	}
+	used, err := caps.db.GetBandwidthUsedSince(month)
+	if err != nil {
+		return err
+	}
+	var usedBy map[string]psdb.BandwidthUsage
+	if caps.allocated != nil {
+		if usedBy, err = caps.db.GetBandwidthUsedSinceBySatellite(month); err != nil {
+			return err
+		}
+	}
+	caps.month, caps.used, caps.usedBy = month, used, usedBy
+	return nil
+}
+
+// limited returns whether any cap is set
+func (caps *bandwidthCaps) limited() bool {
+	return caps != nil && (caps.total > 0 || caps.ingress > 0 || caps.egress > 0 || caps.allocated != nil)
+}
+
+// add records amount bytes transferred for action of an order limit of
+// satellite
+func (caps *bandwidthCaps) add(satellite string, action pb.PayerBandwidthAllocation_Action, amount int64) error {
+	if caps == nil {
+		return nil
+	}
+	now := time.Now()
+	if err := caps.db.AddBandwidthUsed(action, amount, now, satellite); err != nil {
+		return err
+	}
+
+	caps.mu.Lock()
+	defer caps.mu.Unlock()
+	if !caps.month.Equal(StartOfMonth(now)) {
+		// the usage is loaded from the database on the next check
+		caps.month = time.Time{}
+		return nil
+	}
+	usedBy := caps.usedBy[satellite]
+	caps.used.Add(action, amount)
+	usedBy.Add(action, amount)
+	if caps.usedBy != nil {
+		caps.usedBy[satellite] = usedBy
+	}
+	return nil
+}
+
+// full returns whether uploads and downloads of satellite can't be served
+// anymore this month
+func (caps *bandwidthCaps) full(satellite string) (ingressFull, egressFull bool, err error) {
+	if !caps.limited() {
+		return false, false, nil
+	}
+	caps.mu.Lock()
+	defer caps.mu.Unlock()
+	if err := caps.rollover(time.Now()); err != nil {
+		return false, false, err
+	}
+
+	totalFull := caps.total > 0 && caps.used.Total() >= caps.total
+	ingressFull = totalFull || (caps.ingress > 0 && caps.used.Ingress >= caps.ingress)
+	egressFull = totalFull || (caps.egress > 0 && caps.used.Egress >= caps.egress)
+	if allocated := caps.allocated[satellite]; allocated > 0 && caps.usedBy[satellite].Total() >= allocated {
+		return true, true, nil
+	}
+	return ingressFull, egressFull, nil
+}
+
+// check returns an ErrBandwidthCap error if the cap of action, or the
+// bandwidth allocated to satellite, is reached
+func (caps *bandwidthCaps) check(satellite string, action pb.PayerBandwidthAllocation_Action) error {
+	ingressFull, egressFull, err := caps.full(satellite)
+	if err != nil {
+		return err
+	}
+	switch {
+	case action == pb.PayerBandwidthAllocation_PUT && ingressFull:
+		return ErrBandwidthCap.New("monthly ingress cap reached")
+	case action == pb.PayerBandwidthAllocation_GET && egressFull:
+		return ErrBandwidthCap.New("monthly egress cap reached")
+	}
+	return nil
+}
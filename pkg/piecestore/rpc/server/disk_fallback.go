@@ -0,0 +1,12 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information
+
+// +build !linux,!darwin,!freebsd
+
+package server
+
+// diskFree returns the space available on the disk path is on, -1 if it's
+// unknown
+func diskFree(path string) int64 {
+	return -1
+}
@@ -0,0 +1,18 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information
+
+// +build linux darwin freebsd
+
+package server
+
+import "syscall"
+
+// diskFree returns the space available on the disk path is on, -1 if it's
+// unknown
+func diskFree(path string) int64 {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return -1
+	}
+	return int64(stat.Bavail) * int64(stat.Bsize)
+}
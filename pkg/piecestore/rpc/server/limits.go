@@ -0,0 +1,185 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package server
+
+import (
+	"io"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errBusy returns the error of the requests refused because the node already
+// serves as many requests of the kind as it is configured to. Uplinks
+// recognize it with client.IsBusy and use other nodes instead.
+func errBusy(kind string) error {
+	return status.Errorf(codes.ResourceExhausted, "node busy: too many concurrent %s", kind)
+}
+
+// errDiskFull returns the error of the uploads refused because the free disk
+// space is below the watermark. Like busy nodes, uplinks recognize it with
+// client.IsBusy and use other nodes instead.
+func errDiskFull() error {
+	return status.Error(codes.ResourceExhausted, "node full: free disk space below watermark")
+}
+
+// AvailableSpace returns the disk space uploads may use before the free
+// space falls below the watermark, or -1 if the free space is unknown
+func (s *Server) AvailableSpace() int64 {
+	free := diskFree(filepath.Dir(s.DataDir))
+	if free < 0 {
+		return -1
+	}
+	if free < s.minFreeSpace {
+		return 0
+	}
+	return free - s.minFreeSpace
+}
+
+// limiter limits the number of concurrent operations of a kind. The nil
+// limiter doesn't limit anything.
+type limiter chan struct{}
+
+// newLimiter returns a limiter of n concurrent operations, or nil if n is 0
+func newLimiter(n int) limiter {
+	if n <= 0 {
+		return nil
+	}
+	return make(limiter, n)
+}
+
+// tryAcquire starts an operation if the limit isn't reached
+func (l limiter) tryAcquire() bool {
+	if l == nil {
+		return true
+	}
+	select {
+	case l <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// acquire starts an operation, waiting until another one is done if the limit
+// is reached
+func (l limiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release ends an operation started with tryAcquire or acquire
+func (l limiter) release() {
+	if l != nil {
+		<-l
+	}
+}
+
+// rateLimit limits the bandwidth of a single request. The nil rateLimit
+// doesn't limit anything.
+type rateLimit struct {
+	ctx   context.Context
+	rate  int64 // bytes per second
+	start time.Time
+	total int64
+}
+
+// newRateLimit returns the rate limit of a request, or nil if the bandwidth
+// of requests isn't limited
+func (s *Server) newRateLimit(ctx context.Context) *rateLimit {
+	if s.maxBandwidth <= 0 {
+		return nil
+	}
+	return &rateLimit{ctx: ctx, rate: s.maxBandwidth, start: time.Now()}
+}
+
+// wait accounts n transferred bytes and blocks until the average rate of the
+// request is within the limit again
+func (r *rateLimit) wait(n int) error {
+	if r == nil {
+		return nil
+	}
+	r.total += int64(n)
+	due := r.start.Add(time.Duration(float64(r.total) / float64(r.rate) * float64(time.Second)))
+	delay := time.Until(due)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-r.ctx.Done():
+		return r.ctx.Err()
+	}
+}
+
+// throttledReader reads from r within a rate limit
+type throttledReader struct {
+	r    io.Reader
+	rate *rateLimit
+}
+
+func (t *throttledReader) Read(p []byte) (n int, err error) {
+	n, err = t.r.Read(p)
+	if waitErr := t.rate.wait(n); waitErr != nil && err == nil {
+		err = waitErr
+	}
+	return n, err
+}
+
+// throttledWriter writes to w within a rate limit
+type throttledWriter struct {
+	w    io.Writer
+	rate *rateLimit
+}
+
+func (t *throttledWriter) Write(p []byte) (n int, err error) {
+	if err = t.rate.wait(len(p)); err != nil {
+		return 0, err
+	}
+	return t.w.Write(p)
+}
+
+// diskReader holds a slot of the disk IO limit during every read of r
+type diskReader struct {
+	ctx   context.Context
+	limit limiter
+	r     io.Reader
+}
+
+func (d *diskReader) Read(p []byte) (n int, err error) {
+	if err = d.limit.acquire(d.ctx); err != nil {
+		return 0, err
+	}
+	defer d.limit.release()
+	return d.r.Read(p)
+}
+
+// diskWriter holds a slot of the disk IO limit during every write to w
+type diskWriter struct {
+	ctx   context.Context
+	limit limiter
+	w     io.Writer
+}
+
+func (d *diskWriter) Write(p []byte) (n int, err error) {
+	if err = d.limit.acquire(d.ctx); err != nil {
+		return 0, err
+	}
+	defer d.limit.release()
+	return d.w.Write(p)
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package server
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+)
+
+// MaintenanceWindow is a weekly window, in UTC, during which the operator
+// takes the node offline for maintenance
+type MaintenanceWindow struct {
+	Weekday time.Weekday
+	// Start is the start of the window since the start of Weekday
+	Start    time.Duration
+	Duration time.Duration
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// ParseMaintenanceWindows parses the weekly maintenance windows like
+// "sun/02:00/2h,wed/23:30/1h", the day, the start time in UTC and the
+// duration of each window
+func ParseMaintenanceWindows(s string) ([]MaintenanceWindow, error) {
+	var windows []MaintenanceWindow
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, "/")
+		if len(parts) != 3 {
+			return nil, ServerError.New("invalid maintenance window %q: expected day/hh:mm/duration", entry)
+		}
+		weekday, ok := weekdays[strings.ToLower(parts[0])]
+		if !ok {
+			return nil, ServerError.New("invalid day of maintenance window %q", entry)
+		}
+		start, err := time.Parse("15:04", parts[1])
+		if err != nil {
+			return nil, ServerError.New("invalid start of maintenance window %q", entry)
+		}
+		duration, err := time.ParseDuration(parts[2])
+		if err != nil || duration <= 0 || duration > 7*24*time.Hour {
+			return nil, ServerError.New("invalid duration of maintenance window %q", entry)
+		}
+		windows = append(windows, MaintenanceWindow{
+			Weekday:  weekday,
+			Start:    time.Duration(start.Hour())*time.Hour + time.Duration(start.Minute())*time.Minute,
+			Duration: duration,
+		})
+	}
+	return windows, nil
+}
+
+// next returns the occurrence of the window that is ongoing at now, or the
+// next one
+func (window MaintenanceWindow) next(now time.Time) (start, end time.Time) {
+	now = now.UTC()
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	// the occurrence of the window in the week before now may still be on
+	day = day.AddDate(0, 0, int(window.Weekday-now.Weekday())-7)
+	for {
+		start = day.Add(window.Start)
+		end = start.Add(window.Duration)
+		if end.After(now) {
+			return start, end
+		}
+		day = day.AddDate(0, 0, 7)
+	}
+}
+
+// MaintenanceState is the state of the maintenance of the node
+type MaintenanceState struct {
+	// Start and End are the ongoing or next maintenance window, zero if no
+	// windows are configured
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Draining is true once new transfers are refused before the window
+	Draining bool `json:"draining"`
+	// InFlight is the number of uploads and downloads being served, which
+	// should be 0 before the node is taken offline
+	InFlight int32 `json:"in_flight"`
+}
+
+// maintenance schedules the maintenance windows of the node
+type maintenance struct {
+	windows []MaintenanceWindow
+	// notice is how long before a window satellites are told of it, and
+	// drain how long before it new transfers are refused
+	notice time.Duration
+	drain  time.Duration
+	// inFlight counts the uploads and downloads being served
+	inFlight int32
+}
+
+// window returns the ongoing or next maintenance window, or false if none
+// are configured
+func (m *maintenance) window(now time.Time) (start, end time.Time, ok bool) {
+	for _, window := range m.windows {
+		s, e := window.next(now)
+		if !ok || s.Before(start) {
+			start, end, ok = s, e, true
+		}
+	}
+	return start, end, ok
+}
+
+// Maintenance returns the state of the maintenance of the node
+func (s *Server) Maintenance() MaintenanceState {
+	now := time.Now()
+	state := MaintenanceState{InFlight: atomic.LoadInt32(&s.maintenance.inFlight)}
+	if start, end, ok := s.maintenance.window(now); ok {
+		state.Start, state.End = start, end
+		state.Draining = !now.Before(start.Add(-s.maintenance.drain))
+	}
+	return state
+}
+
+// announcedMaintenance returns the maintenance window satellites are told
+// of at check-in, or nil if none is ongoing or starting soon
+func (s *Server) announcedMaintenance(now time.Time) *pb.MaintenanceWindow {
+	start, end, ok := s.maintenance.window(now)
+	if !ok || now.Before(start.Add(-s.maintenance.notice)) {
+		return nil
+	}
+	return &pb.MaintenanceWindow{StartUnixSec: start.Unix(), EndUnixSec: end.Unix()}
+}
+
+// startTransfer starts serving an upload or download, unless the node is
+// draining before a maintenance window. Uplinks recognize the refusal with
+// client.IsBusy and use other nodes instead. done must be called when the
+// transfer ends.
+func (s *Server) startTransfer() (done func(), err error) {
+	if s.Maintenance().Draining {
+		mon.Counter("maintenance_refused").Inc(1)
+		return nil, status.Error(codes.ResourceExhausted, "node busy: maintenance window")
+	}
+	atomic.AddInt32(&s.maintenance.inFlight, 1)
+	return func() { atomic.AddInt32(&s.maintenance.inFlight, -1) }, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package server
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/pb"
+	pstore "storj.io/storj/pkg/piecestore"
+	"storj.io/storj/pkg/utils"
+)
+
+// merkleTree returns the path of the piece id and the leaves of its Merkle
+// tree, which are computed from the piece if they weren't recorded
+func (s *Server) merkleTree(ctx context.Context, id string) (path string, leaves [][]byte, err error) {
+	path, err = pstore.PathByID(id, s.DataDir)
+	if err != nil {
+		return "", nil, status.Errorf(codes.InvalidArgument, "invalid piece id: %v", err)
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", nil, status.Errorf(codes.NotFound, "piece %s not found", id)
+	}
+
+	leaves, err = s.DB.GetLeaves(id)
+	if err != nil {
+		return "", nil, err
+	}
+	// pieces stored before Merkle trees were recorded are hashed once
+	if leaves == nil {
+		if leaves, err = s.hashPiece(ctx, id, path); err != nil {
+			return "", nil, err
+		}
+	}
+	return path, leaves, nil
+}
+
+// Challenge answers a challenge of a satellite with a leaf of a piece and
+// the proof that it's part of the Merkle tree of the piece, so that
+// satellites can audit pieces without downloading them. Only trusted
+// satellites may challenge the node, as the leaf is piece data.
+func (s *Server) Challenge(ctx context.Context, in *pb.ChallengeRequest) (_ *pb.ChallengeResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if _, err := s.trustedSatellite(ctx); err != nil {
+		return nil, err
+	}
+	path, leaves, err := s.merkleTree(ctx, in.GetPieceId())
+	if err != nil {
+		return nil, err
+	}
+	index := in.GetLeaf()
+	if index < 0 || index >= int64(len(leaves)) {
+		return nil, status.Errorf(codes.OutOfRange, "leaf %d out of %d", index, len(leaves))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.LogClose(file)
+
+	leaf := make([]byte, eestream.LeafSize)
+	n, err := file.ReadAt(leaf, index*eestream.LeafSize)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	leaf = leaf[:n]
+
+	mon.Counter("challenges_answered").Inc(1)
+	return &pb.ChallengeResponse{
+		Leaf:  leaf,
+		Proof: eestream.MerkleProof(leaves, int(index)),
+	}, nil
+}
+
+// Proof returns the hashes of a range of leaves of the Merkle tree of a
+// piece and the proof that they're part of the tree, so that uplinks and
+// satellites can verify partial downloads of the piece
+func (s *Server) Proof(ctx context.Context, in *pb.ProofRequest) (_ *pb.ProofResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, leaves, err := s.merkleTree(ctx, in.GetPieceId())
+	if err != nil {
+		return nil, err
+	}
+	first, last := in.GetFirstLeaf(), in.GetLastLeaf()
+	if first < 0 || first > last || last >= int64(len(leaves)) {
+		return nil, status.Errorf(codes.OutOfRange, "leaves %d-%d out of %d", first, last, len(leaves))
+	}
+
+	return &pb.ProofResponse{
+		Leaves: leaves[first : last+1],
+		Proof:  eestream.MerkleRangeProof(leaves, int(first), int(last)),
+	}, nil
+}
@@ -0,0 +1,152 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package server
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/gogo/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/orders"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/provider"
+)
+
+// orderLimit verifies the order limits of the bandwidth allocations of a
+// single request
+type orderLimit struct {
+	server *Server
+	action pb.PayerBandwidthAllocation_Action
+	id     string
+
+	// checked is set once the bandwidth caps and the allocation of the
+	// satellite allowed the request
+	checked bool
+	// satellite is the id of the satellite of the order limit
+	satellite string
+
+	signature []byte
+	data      *pb.PayerBandwidthAllocation_Data
+}
+
+func (s *Server) newOrderLimit(action pb.PayerBandwidthAllocation_Action, id string) *orderLimit {
+	return &orderLimit{server: s, action: action, id: id}
+}
+
+// verify checks that the order limit of the allocation allows the request and
+// that the allocation doesn't exceed it. Uplinks send the same order limit
+// with every allocation, so it is only verified once, and an order limit used
+// by another request is refused. Requests are refused once the monthly
+// bandwidth cap of their action or the allocation of their satellite is
+// reached.
+func (l *orderLimit) verify(ctx context.Context, alloc *pb.RenterBandwidthAllocation_Data) error {
+	limit := alloc.GetPayerAllocation()
+	if l.server.orders == nil {
+		// the satellite of unverified order limits is only used to account
+		// their bandwidth
+		return l.check(satelliteOf(limit))
+	}
+
+	if l.data == nil || !bytes.Equal(l.signature, limit.GetSignature()) {
+		uplink, err := provider.PeerIdentityFromContext(ctx)
+		if err != nil {
+			return err
+		}
+
+		data, err := l.server.orders.Verify(limit, l.action, l.id, uplink.ID.String())
+		if err != nil {
+			return err
+		}
+		// the payer of the order limit is only trusted once its signature
+		// was verified
+		if err := l.check(string(data.GetPayer())); err != nil {
+			return err
+		}
+		if l.server.serials != nil {
+			// an order limit allows a single request
+			if err := l.server.serials.Use(data.GetSerialNumber(), data.GetExpirationUnixSec()); err != nil {
+				return err
+			}
+		}
+		l.signature, l.data = limit.GetSignature(), data
+	}
+
+	return l.allow(alloc.GetTotal())
+}
+
+// check checks the bandwidth caps and the allocation of satellite, once for
+// each satellite the request is for
+func (l *orderLimit) check(satellite string) error {
+	if l.checked && l.satellite == satellite {
+		return nil
+	}
+	if err := l.server.checkAllocation(satellite, l.action); err != nil {
+		return err
+	}
+	l.satellite, l.checked = satellite, true
+	return nil
+}
+
+// allow checks that total bytes may be transferred
+func (l *orderLimit) allow(total int64) error {
+	if l.server.orders == nil {
+		return nil
+	}
+	if l.data == nil {
+		return orders.ErrUnauthorized.New("missing order limit")
+	}
+	if total > l.data.GetMaxSize() {
+		return errLimitExceeded("%d bytes exceed order limit of %d", total, l.data.GetMaxSize())
+	}
+	return nil
+}
+
+// allowReceived checks, as the data of an upload arrives, that the received
+// bytes are within the order limit and were allocated by the uplink, so
+// that uploads are aborted before the excess is written
+func (l *orderLimit) allowReceived(received, allocated int64) error {
+	if err := l.allow(received); err != nil {
+		return err
+	}
+	if l.server.orders != nil && received > allocated {
+		return errLimitExceeded("%d bytes received exceed the %d bytes allocated by the uplink", received, allocated)
+	}
+	return nil
+}
+
+// errLimitExceeded returns the error of the transfers aborted because they
+// exceed their order limit or the bandwidth allocated by the uplink. Uplinks
+// recognize it with client.IsLimitExceeded.
+func errLimitExceeded(format string, args ...interface{}) error {
+	mon.Counter("order_limit_exceeded").Inc(1)
+	return status.Errorf(codes.OutOfRange, "order limit exceeded: "+format, args...)
+}
+
+// saveAgreement stores the bandwidth agreement of a request of action to be
+// settled with the satellite, after verifying that the uplink signed it and
+// that it is within its order limit, and accounts the bandwidth it used
+// under the action of its order limit, so that repair and audit traffic is
+// accounted apart
+func (s *Server) saveAgreement(ctx context.Context, action pb.PayerBandwidthAllocation_Action, ba *pb.RenterBandwidthAllocation) error {
+	data := &pb.RenterBandwidthAllocation_Data{}
+	if s.orders != nil {
+		uplink, err := provider.PeerIdentityFromContext(ctx)
+		if err != nil {
+			return err
+		}
+		if data, err = s.orders.VerifyAgreement(ba, uplink); err != nil {
+			return err
+		}
+	} else if err := proto.Unmarshal(ba.GetData(), data); err != nil {
+		return err
+	}
+	if err := s.DB.WriteBandwidthAllocToDB(ba); err != nil {
+		return err
+	}
+	limit := data.GetPayerAllocation()
+	return s.bandwidth.add(satelliteOf(limit), limitAction(limit, action), data.GetTotal())
+}
@@ -4,12 +4,15 @@
 package psdb
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"database/sql"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
@@ -34,10 +37,12 @@ type DB struct {
 	mu       sync.Mutex
 	DB       *sql.DB // TODO: hide
 	check    *time.Ticker
+	trashTTL time.Duration
 }
 
-// Open opens DB at DBPath
-func Open(ctx context.Context, DataPath, DBPath string) (db *DB, err error) {
+// Open opens DB at DBPath. The pieces trashed more than trashTTL ago are
+// deleted along with the expired pieces.
+func Open(ctx context.Context, DataPath, DBPath string, trashTTL time.Duration) (db *DB, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	if err = os.MkdirAll(filepath.Dir(DBPath), 0700); err != nil {
@@ -64,7 +69,7 @@ func Open(ctx context.Context, DataPath, DBPath string) (db *DB, err error) {
 	}
 	defer func() { _ = tx.Rollback() }()
 
-	_, err = tx.Exec("CREATE TABLE IF NOT EXISTS `ttl` (`id` BLOB UNIQUE, `created` INT(10), `expires` INT(10), `size` INT(10));")
+	_, err = tx.Exec("CREATE TABLE IF NOT EXISTS `ttl` (`id` BLOB UNIQUE, `created` INT(10), `expires` INT(10), `size` INT(10), `satellite` TEXT NOT NULL DEFAULT '', `hash` BLOB, `leaves` BLOB);")
 	if err != nil {
 		return nil, err
 	}
@@ -74,11 +79,38 @@ func Open(ctx context.Context, DataPath, DBPath string) (db *DB, err error) {
 		return nil, err
 	}
 
+	_, err = tx.Exec("CREATE TABLE IF NOT EXISTS `bandwidth_usage` (`action` INT(10), `amount` INT(10), `created` INT(10), `satellite` TEXT NOT NULL DEFAULT '');")
+	if err != nil {
+		return nil, err
+	}
+
+	// the satellites of pieces and bandwidth usage weren't recorded before
+	for _, table := range []string{"ttl", "bandwidth_usage"} {
+		if err = addColumn(tx, table, "satellite", "TEXT NOT NULL DEFAULT ''"); err != nil {
+			return nil, err
+		}
+	}
+
+	// the hashes of pieces weren't recorded before
+	if err = addColumn(tx, "ttl", "hash", "BLOB"); err != nil {
+		return nil, err
+	}
+
+	// nor the hashes of the leaves of their Merkle trees
+	if err = addColumn(tx, "ttl", "leaves", "BLOB"); err != nil {
+		return nil, err
+	}
+
 	_, err = tx.Exec("CREATE INDEX IF NOT EXISTS idx_ttl_expires ON ttl (expires);")
 	if err != nil {
 		return nil, err
 	}
 
+	_, err = tx.Exec("CREATE INDEX IF NOT EXISTS idx_ttl_satellite ON ttl (satellite);")
+	if err != nil {
+		return nil, err
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		return nil, err
@@ -88,12 +120,73 @@ func Open(ctx context.Context, DataPath, DBPath string) (db *DB, err error) {
 		DB:       sqlite,
 		dataPath: DataPath,
 		check:    time.NewTicker(*defaultCheckInterval),
+		trashTTL: trashTTL,
 	}
 	go db.garbageCollect(ctx)
 
 	return db, nil
 }
 
+// addColumn adds column to table, unless the table has it already
+func addColumn(tx *sql.Tx, table, column, definition string) error {
+	rows, err := tx.Query(fmt.Sprintf("PRAGMA table_info(`%s`);", table))
+	if err != nil {
+		return err
+	}
+	found := false
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, typ string
+		var value sql.NullString
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &value, &pk); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		found = found || name == column
+	}
+	if err := rows.Close(); err != nil {
+		return err
+	}
+	if found {
+		return nil
+	}
+	_, err = tx.Exec(fmt.Sprintf("ALTER TABLE `%s` ADD COLUMN `%s` %s;", table, column, definition))
+	return err
+}
+
+// CheckIntegrity runs the integrity check of sqlite on the database at
+// DBPath, opened read-only so that it can be checked while the node runs. It
+// returns the problems found, none if the database is intact.
+func CheckIntegrity(ctx context.Context, DBPath string) (problems []string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if _, err := os.Stat(DBPath); err != nil {
+		return nil, err
+	}
+	sqlite, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?mode=ro", DBPath))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = utils.CombineErrors(err, sqlite.Close()) }()
+
+	rows, err := sqlite.QueryContext(ctx, `PRAGMA integrity_check`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { err = utils.CombineErrors(err, rows.Close()) }()
+
+	for rows.Next() {
+		var problem string
+		if err := rows.Scan(&problem); err != nil {
+			return nil, err
+		}
+		if problem != "ok" {
+			problems = append(problems, problem)
+		}
+	}
+	return problems, rows.Err()
+}
+
 // Close the database
 func (db *DB) Close() error {
 	return db.DB.Close()
@@ -104,7 +197,8 @@ func (db *DB) locked() func() {
 	return db.mu.Unlock
 }
 
-// DeleteExpired checks for expired TTLs in the DB and removes data from both the DB and the FS
+// DeleteExpired checks for expired TTLs in the DB and removes data from both the DB and the FS,
+// and empties the trash of the pieces trashed more than the trash TTL ago
 func (db *DB) DeleteExpired(ctx context.Context) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
@@ -152,6 +246,10 @@ func (db *DB) DeleteExpired(ctx context.Context) (err error) {
 		}
 	}
 
+	if _, err := pstore.EmptyTrash(db.dataPath, time.Now().Add(-db.trashTTL)); err != nil {
+		errs = append(errs, err)
+	}
+
 	if len(errs) > 0 {
 		return utils.CombineErrors(errs...)
 	}
@@ -201,12 +299,110 @@ func (db *DB) GetBandwidthAllocationBySignature(signature []byte) ([][]byte, err
 	return agreements, nil
 }
 
-// AddTTL adds TTL into database by id
-func (db *DB) AddTTL(id string, expiration, size int64) error {
+// BandwidthUsage is the bandwidth used by uploads (ingress) and downloads
+// (egress). RepairIngress, RepairEgress and AuditEgress are the parts of them
+// used by the satellite to repair and audit segments rather than by its
+// customers.
+type BandwidthUsage struct {
+	Ingress int64
+	Egress  int64
+
+	RepairIngress int64
+	RepairEgress  int64
+	AuditEgress   int64
+}
+
+// Add adds amount bytes transferred for the action of an order limit
+func (usage *BandwidthUsage) Add(action pb.PayerBandwidthAllocation_Action, amount int64) {
+	switch action {
+	case pb.PayerBandwidthAllocation_PUT:
+		usage.Ingress += amount
+	case pb.PayerBandwidthAllocation_GET:
+		usage.Egress += amount
+	case pb.PayerBandwidthAllocation_PUT_REPAIR:
+		usage.Ingress += amount
+		usage.RepairIngress += amount
+	case pb.PayerBandwidthAllocation_GET_REPAIR:
+		usage.Egress += amount
+		usage.RepairEgress += amount
+	case pb.PayerBandwidthAllocation_GET_AUDIT:
+		usage.Egress += amount
+		usage.AuditEgress += amount
+	}
+}
+
+// Total returns the bandwidth used by uploads and downloads together
+func (usage BandwidthUsage) Total() int64 {
+	return usage.Ingress + usage.Egress
+}
+
+// AddBandwidthUsed records amount bytes transferred at created for the
+// action of an order limit of satellite
+func (db *DB) AddBandwidthUsed(action pb.PayerBandwidthAllocation_Action, amount int64, created time.Time, satellite string) error {
+	defer db.locked()()
+
+	_, err := db.DB.Exec(`INSERT INTO bandwidth_usage (action, amount, created, satellite) VALUES (?, ?, ?, ?)`, int32(action), amount, created.Unix(), satellite)
+	return err
+}
+
+// GetBandwidthUsedSince sums the bandwidth used since since
+func (db *DB) GetBandwidthUsedSince(since time.Time) (usage BandwidthUsage, err error) {
+	defer db.locked()()
+
+	rows, err := db.DB.Query(`SELECT action, SUM(amount) FROM bandwidth_usage WHERE created >= ? GROUP BY action`, since.Unix())
+	if err != nil {
+		return usage, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var action int32
+		var amount int64
+		if err := rows.Scan(&action, &amount); err != nil {
+			return usage, err
+		}
+		usage.Add(pb.PayerBandwidthAllocation_Action(action), amount)
+	}
+	return usage, rows.Err()
+}
+
+// GetBandwidthUsedSinceBySatellite sums the bandwidth used by each satellite
+// since since
+func (db *DB) GetBandwidthUsedSinceBySatellite(since time.Time) (_ map[string]BandwidthUsage, err error) {
+	defer db.locked()()
+
+	rows, err := db.DB.Query(`SELECT satellite, action, SUM(amount) FROM bandwidth_usage WHERE created >= ? GROUP BY satellite, action`, since.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	usages := map[string]BandwidthUsage{}
+	for rows.Next() {
+		var satellite string
+		var action int32
+		var amount int64
+		if err := rows.Scan(&satellite, &action, &amount); err != nil {
+			return nil, err
+		}
+		usage := usages[satellite]
+		usage.Add(pb.PayerBandwidthAllocation_Action(action), amount)
+		usages[satellite] = usage
+	}
+	return usages, rows.Err()
+}
+
+// AddTTL adds TTL into database by id, for a piece uploaded with an order
+// limit of satellite
+func (db *DB) AddTTL(id string, expiration, size int64, satellite string) error {
 	defer db.locked()()
 
 	created := time.Now().Unix()
-	_, err := db.DB.Exec("INSERT OR REPLACE INTO ttl (id, created, expires, size) VALUES (?, ?, ?, ?)", id, created, expiration, size)
+	_, err := db.DB.Exec("INSERT OR REPLACE INTO ttl (id, created, expires, size, satellite) VALUES (?, ?, ?, ?, ?)", id, created, expiration, size, satellite)
 	return err
 }
 
@@ -226,6 +422,15 @@ func (db *DB) SumTTLSizes() (sum int64, err error) {
 	return sum, err
 }
 
+// SumSatelliteTTLSizes sums the sizes of the pieces uploaded with the order
+// limits of satellite
+func (db *DB) SumSatelliteTTLSizes(satellite string) (sum int64, err error) {
+	defer db.locked()()
+
+	err = db.DB.QueryRow(`SELECT COALESCE(SUM(size), 0) FROM ttl WHERE satellite = ?;`, satellite).Scan(&sum)
+	return sum, err
+}
+
 // DeleteTTLByID finds the TTL in the database by id and delete it
 func (db *DB) DeleteTTLByID(id string) error {
 	defer db.locked()()
@@ -236,3 +441,134 @@ func (db *DB) DeleteTTLByID(id string) error {
 	}
 	return err
 }
+
+// PieceInfo contains the id and creation time of a stored piece
+type PieceInfo struct {
+	ID      string
+	Created int64
+}
+
+// ListPieces returns the id and creation time of every stored piece
+func (db *DB) ListPieces(ctx context.Context) (pieces []PieceInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return db.listPieces(ctx, `SELECT id, created FROM ttl`)
+}
+
+// ListPiecesBySatellite returns the id and creation time of the pieces
+// stored for satellite
+func (db *DB) ListPiecesBySatellite(ctx context.Context, satellite string) (pieces []PieceInfo, err error) {
+	defer mon.Task()(&ctx)(&err)
+	return db.listPieces(ctx, `SELECT id, created FROM ttl WHERE satellite=?`, satellite)
+}
+
+func (db *DB) listPieces(ctx context.Context, query string, args ...interface{}) (pieces []PieceInfo, err error) {
+	defer db.locked()()
+
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	for rows.Next() {
+		var piece PieceInfo
+		if err := rows.Scan(&piece.ID, &piece.Created); err != nil {
+			return nil, err
+		}
+		pieces = append(pieces, piece)
+	}
+	return pieces, rows.Err()
+}
+
+// SetMerkleTree records the root of the Merkle tree of the piece id, which
+// is the hash of the piece, and the hashes of its leaves, which proofs of
+// the piece are computed from
+func (db *DB) SetMerkleTree(id string, root []byte, leaves [][]byte) error {
+	defer db.locked()()
+
+	_, err := db.DB.Exec(`UPDATE ttl SET hash=?, leaves=? WHERE id=?`, root, bytes.Join(leaves, nil), id)
+	return err
+}
+
+// GetLeaves returns the hashes of the leaves of the Merkle tree of the piece
+// id, nil if the piece or its leaves weren't recorded
+func (db *DB) GetLeaves(id string) (leaves [][]byte, err error) {
+	defer db.locked()()
+
+	var joined []byte
+	err = db.DB.QueryRow(`SELECT leaves FROM ttl WHERE id=?`, id).Scan(&joined)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for len(joined) >= sha256.Size {
+		leaves = append(leaves, joined[:sha256.Size])
+		joined = joined[sha256.Size:]
+	}
+	return leaves, nil
+}
+
+// PieceStat contains the metadata of a stored piece. Hash is the root of
+// the Merkle tree of the piece, nil if it wasn't recorded.
+type PieceStat struct {
+	ID         string
+	Size       int64
+	Created    int64
+	Expiration int64
+	Hash       []byte
+}
+
+// StatPieces returns the metadata of the pieces of ids that are in the
+// database, by id
+func (db *DB) StatPieces(ctx context.Context, ids []string) (_ map[string]PieceStat, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if len(ids) == 0 {
+		return map[string]PieceStat{}, nil
+	}
+	defer db.locked()()
+
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	// the hashes recorded before Merkle trees are SHA-256 hashes of the
+	// whole pieces, and are computed again
+	query := `SELECT id, size, created, expires, CASE WHEN leaves IS NULL THEN NULL ELSE hash END
+		FROM ttl WHERE id IN (?` + strings.Repeat(`, ?`, len(ids)-1) + `)`
+	rows, err := db.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	stats := map[string]PieceStat{}
+	for rows.Next() {
+		var stat PieceStat
+		var size, created, expires sql.NullInt64
+		if err := rows.Scan(&stat.ID, &size, &created, &expires, &stat.Hash); err != nil {
+			return nil, err
+		}
+		stat.Size, stat.Created, stat.Expiration = size.Int64, created.Int64, expires.Int64
+		stats[stat.ID] = stat
+	}
+	return stats, rows.Err()
+}
+
+// DeleteTTLCreatedBefore deletes the TTL of id when the piece was created
+// before createdBefore, and returns whether it was deleted
+func (db *DB) DeleteTTLCreatedBefore(id string, createdBefore int64) (deleted bool, err error) {
+	defer db.locked()()
+
+	result, err := db.DB.Exec(`DELETE FROM ttl WHERE id=? AND created < ?`, id, createdBefore)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
@@ -10,10 +10,12 @@ import (
 	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	_ "github.com/mattn/go-sqlite3"
 	"storj.io/storj/pkg/pb"
+	pstore "storj.io/storj/pkg/piecestore"
 
 	"golang.org/x/net/context"
 )
@@ -29,7 +31,7 @@ func openTest(t testing.TB) (*DB, func()) {
 	}
 	dbpath := filepath.Join(tmpdir, "psdb.db")
 
-	db, err := Open(ctx, "", dbpath)
+	db, err := Open(ctx, "", dbpath, time.Hour)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -67,7 +69,7 @@ func TestHappyPath(t *testing.T) {
 			t.Run("#"+strconv.Itoa(P), func(t *testing.T) {
 				t.Parallel()
 				for _, ttl := range tests {
-					err := db.AddTTL(ttl.ID, ttl.Expiration, 0)
+					err := db.AddTTL(ttl.ID, ttl.Expiration, 0, "")
 					if err != nil {
 						t.Fatal(err)
 					}
@@ -184,6 +186,284 @@ func TestHappyPath(t *testing.T) {
 	})
 }
 
+func TestListPieces(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	for _, id := range []string{"old", "new"} {
+		if err := db.AddTTL(id, 0, 0, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := db.DB.Exec(`UPDATE ttl SET created = 100 WHERE id = "old"`); err != nil {
+		t.Fatal(err)
+	}
+
+	pieces, err := db.ListPieces(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pieces) != 2 {
+		t.Fatalf("expected 2 pieces got %d", len(pieces))
+	}
+
+	deleted, err := db.DeleteTTLCreatedBefore("new", 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted {
+		t.Fatal("deleted piece created after the timestamp")
+	}
+
+	deleted, err = db.DeleteTTLCreatedBefore("old", 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deleted {
+		t.Fatal("expected piece to be deleted")
+	}
+
+	pieces, err = db.ListPieces(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pieces) != 1 || pieces[0].ID != "new" {
+		t.Fatalf("expected only new piece got %v", pieces)
+	}
+
+	if err := db.AddTTL("other", 0, 0, "b"); err != nil {
+		t.Fatal(err)
+	}
+	pieces, err = db.ListPiecesBySatellite(ctx, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pieces) != 1 || pieces[0].ID != "other" {
+		t.Fatalf("expected only the piece of satellite b got %v", pieces)
+	}
+}
+
+func TestBandwidthUsage(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	now := time.Now()
+	for _, used := range []struct {
+		action    pb.PayerBandwidthAllocation_Action
+		amount    int64
+		created   time.Time
+		satellite string
+	}{
+		{pb.PayerBandwidthAllocation_PUT, 100, now.Add(-48 * time.Hour), "a"},
+		{pb.PayerBandwidthAllocation_PUT, 10, now, "a"},
+		{pb.PayerBandwidthAllocation_PUT, 20, now, "b"},
+		{pb.PayerBandwidthAllocation_GET, 5, now, "a"},
+		{pb.PayerBandwidthAllocation_PUT_REPAIR, 3, now, "b"},
+		{pb.PayerBandwidthAllocation_GET_REPAIR, 2, now, "b"},
+		{pb.PayerBandwidthAllocation_GET_AUDIT, 1, now, "a"},
+	} {
+		if err := db.AddBandwidthUsed(used.action, used.amount, used.created, used.satellite); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage, err := db.GetBandwidthUsedSince(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := BandwidthUsage{Ingress: 33, Egress: 8, RepairIngress: 3, RepairEgress: 2, AuditEgress: 1}
+	if usage != expected || usage.Total() != 41 {
+		t.Fatalf("unexpected usage %+v", usage)
+	}
+
+	usages, err := db.GetBandwidthUsedSinceBySatellite(now.Add(-time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usages) != 2 || usages["a"] != (BandwidthUsage{Ingress: 10, Egress: 6, AuditEgress: 1}) ||
+		usages["b"] != (BandwidthUsage{Ingress: 23, Egress: 2, RepairIngress: 3, RepairEgress: 2}) {
+		t.Fatalf("unexpected usages %+v", usages)
+	}
+}
+
+func TestSatelliteTTLSizes(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	for i, satellite := range []string{"a", "a", "b"} {
+		if err := db.AddTTL(strconv.Itoa(i), 0, 100, satellite); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for satellite, expected := range map[string]int64{"a": 200, "b": 100, "c": 0} {
+		sum, err := db.SumSatelliteTTLSizes(satellite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sum != expected {
+			t.Fatalf("expected %d bytes of satellite %s got %d", expected, satellite, sum)
+		}
+	}
+}
+
+func TestStatPieces(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	if err := db.AddTTL("a", 1234, 100, "sat"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddTTL("b", 0, 200, "sat"); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.SetMerkleTree("a", []byte("hash"), [][]byte{make([]byte, 32)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddTTL("old", 0, 300, "sat"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.DB.Exec(`UPDATE ttl SET hash=? WHERE id=?`, []byte("sha256"), "old"); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := db.StatPieces(ctx, []string{"a", "b", "c", "old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 3 {
+		t.Fatalf("expected 3 pieces got %d", len(stats))
+	}
+	a := stats["a"]
+	if a.Size != 100 || a.Expiration != 1234 || a.Created == 0 || !bytes.Equal(a.Hash, []byte("hash")) {
+		t.Fatalf("unexpected stat of a: %+v", a)
+	}
+	if b := stats["b"]; b.Size != 200 || b.Hash != nil {
+		t.Fatalf("unexpected stat of b: %+v", b)
+	}
+	// hashes recorded before Merkle trees aren't returned
+	if old := stats["old"]; old.Hash != nil {
+		t.Fatalf("unexpected stat of old: %+v", old)
+	}
+
+	leaves, err := db.GetLeaves("a")
+	if err != nil || len(leaves) != 1 {
+		t.Fatalf("unexpected leaves of a: %v, %v", leaves, err)
+	}
+	if leaves, err := db.GetLeaves("c"); err != nil || leaves != nil {
+		t.Fatalf("unexpected leaves of c: %v, %v", leaves, err)
+	}
+
+	stats, err = db.StatPieces(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stats) != 0 {
+		t.Fatalf("expected no pieces got %d", len(stats))
+	}
+}
+
+func TestAddColumn(t *testing.T) {
+	db, cleanup := openTest(t)
+	defer cleanup()
+
+	tx, err := db.DB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	// adding an existing column does nothing
+	if err := addColumn(tx, "ttl", "satellite", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		t.Fatal(err)
+	}
+	if err := addColumn(tx, "ttl", "other", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(`SELECT other FROM ttl`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteExpiredTrash(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "storj-psdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(tmpdir) }()
+	dataPath := filepath.Join(tmpdir, "data")
+
+	db, err := Open(ctx, dataPath, filepath.Join(tmpdir, "psdb.db"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	trashPaths := map[string]string{}
+	for _, id := range []string{"11111111111111111111", "22222222222222222222"} {
+		file, err := pstore.StoreWriter(id, dataPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := file.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if err := pstore.Trash(id, dataPath); err != nil {
+			t.Fatal(err)
+		}
+		if trashPaths[id], err = pstore.PathByID(id, filepath.Join(dataPath, pstore.TrashDir)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the first piece was trashed longer than the trash TTL ago
+	trashed := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(trashPaths["11111111111111111111"], trashed, trashed); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := db.DeleteExpired(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(trashPaths["11111111111111111111"]); !os.IsNotExist(err) {
+		t.Fatalf("expected the expired trash to be deleted, got %v", err)
+	}
+	if _, err := os.Stat(trashPaths["22222222222222222222"]); err != nil {
+		t.Fatalf("expected the recent trash to be kept, got %v", err)
+	}
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "storj-psdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(tmpdir) }()
+	dbpath := filepath.Join(tmpdir, "psdb.db")
+
+	if _, err := CheckIntegrity(ctx, dbpath); !os.IsNotExist(err) {
+		t.Fatalf("expected a missing database, got %v", err)
+	}
+
+	db, err := Open(ctx, "", dbpath, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AddTTL("piece", 0, 10, "satellite"); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := CheckIntegrity(ctx, dbpath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(problems) != 0 {
+		t.Fatalf("expected no problems, got %v", problems)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
 func BenchmarkWriteBandwidthAllocation(b *testing.B) {
 	db, cleanup := openTest(b)
 	defer cleanup()
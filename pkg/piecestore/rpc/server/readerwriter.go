@@ -35,11 +35,13 @@ type StreamReader struct {
 	src                 *utils.ReaderSource
 	bandwidthAllocation *pb.RenterBandwidthAllocation
 	currentTotal        int64
+	limit               *orderLimit
+	received            int64
 }
 
-// NewStreamReader returns a new StreamReader for Server.Store
-func NewStreamReader(s *Server, stream pb.PieceStoreRoutes_StoreServer) *StreamReader {
-	sr := &StreamReader{}
+// NewStreamReader returns a new StreamReader for Server.Store of the piece id
+func NewStreamReader(s *Server, stream pb.PieceStoreRoutes_StoreServer, id string) *StreamReader {
+	sr := &StreamReader{limit: s.newOrderLimit(pb.PayerBandwidthAllocation_PUT, id)}
 	sr.src = utils.NewReaderSource(func() ([]byte, error) {
 
 		recv, err := stream.Recv()
@@ -61,6 +63,10 @@ func NewStreamReader(s *Server, stream pb.PieceStoreRoutes_StoreServer) *StreamR
 				return nil, err
 			}
 
+			if err = sr.limit.verify(stream.Context(), deserializedData); err != nil {
+				return nil, err
+			}
+
 			// Update bandwidthallocation to be stored
 			if deserializedData.GetTotal() > sr.currentTotal {
 				sr.bandwidthAllocation = ba
@@ -68,6 +74,13 @@ func NewStreamReader(s *Server, stream pb.PieceStoreRoutes_StoreServer) *StreamR
 			}
 		}
 
+		// the data is checked before it's written, so uploads exceeding
+		// their limits are aborted as soon as they do
+		sr.received += int64(len(pd.GetContent()))
+		if err = sr.limit.allowReceived(sr.received, sr.currentTotal); err != nil {
+			return nil, err
+		}
+
 		return pd.GetContent(), nil
 	})
 
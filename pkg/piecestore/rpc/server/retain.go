@@ -0,0 +1,95 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package server
+
+import (
+	"log"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/bloomfilter"
+	"storj.io/storj/pkg/pb"
+	pstore "storj.io/storj/pkg/piecestore"
+)
+
+// Retain -- Keep the pieces of the calling satellite in its retain filter
+// and move the rest of its pieces to the trash. Only trusted satellites may
+// call it, and the pieces of other satellites are left alone.
+func (s *Server) Retain(ctx context.Context, in *pb.RetainRequest) (summary *pb.RetainSummary, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	satellite, err := s.trustedSatellite(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := bloomfilter.NewFromBytes(in.GetFilter())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid retain filter: %v", err)
+	}
+
+	if !atomic.CompareAndSwapInt32(&s.retaining, 0, 1) {
+		return nil, status.Errorf(codes.Unavailable, "retain already in progress")
+	}
+
+	pieces, err := s.DB.ListPiecesBySatellite(ctx, satellite)
+	if err != nil {
+		atomic.StoreInt32(&s.retaining, 0)
+		return nil, err
+	}
+
+	createdBefore := in.GetCreationUnixSec()
+
+	var retained int64
+	var garbage []string
+	for _, piece := range pieces {
+		// the filter only knows about pieces created before its timestamp
+		if piece.Created >= createdBefore || filter.Contains([]byte(piece.ID)) {
+			retained++
+			continue
+		}
+		garbage = append(garbage, piece.ID)
+	}
+
+	log.Printf("Retaining %d pieces, moving %d pieces to trash...", retained, len(garbage))
+
+	s.retainWG.Add(1)
+	go func() {
+		defer s.retainWG.Done()
+		defer atomic.StoreInt32(&s.retaining, 0)
+		s.trash(garbage, createdBefore)
+	}()
+
+	return &pb.RetainSummary{Retained: retained, Deleted: int64(len(garbage))}, nil
+}
+
+// trash moves pieces to the trash one by one, waiting retainThrottle between
+// pieces so that garbage collection doesn't starve uploads and downloads
+func (s *Server) trash(ids []string, createdBefore int64) {
+	for _, id := range ids {
+		if atomic.LoadInt32(&s.stopping) != 0 {
+			return
+		}
+
+		// the piece may have been stored again since the filter was applied
+		deleted, err := s.DB.DeleteTTLCreatedBefore(id, createdBefore)
+		if err != nil {
+			log.Printf("Failed to delete TTL of %s: %v", id, err)
+			continue
+		}
+		if !deleted {
+			continue
+		}
+
+		if err := pstore.Trash(id, s.DataDir); err != nil {
+			log.Printf("Failed to move %s to trash: %v", id, err)
+		}
+
+		time.Sleep(s.retainThrottle)
+	}
+}
@@ -28,6 +28,17 @@ func (s *Server) Retrieve(stream pb.PieceStoreRoutes_RetrieveServer) (err error)
 	ctx := stream.Context()
 	defer mon.Task()(&ctx)(&err)
 
+	if !s.downloads.tryAcquire() {
+		return errBusy("downloads")
+	}
+	defer s.downloads.release()
+
+	done, err := s.startTransfer()
+	if err != nil {
+		return err
+	}
+	defer done()
+
 	// Receive Signature
 	recv, err := stream.Recv()
 	if err != nil {
@@ -84,10 +95,13 @@ func (s *Server) retrieveData(ctx context.Context, stream pb.PieceStoreRoutes_Re
 
 	defer utils.LogClose(storeFile)
 
-	writer := NewStreamWriter(s, stream)
+	writer := &throttledWriter{w: NewStreamWriter(s, stream), rate: s.newRateLimit(ctx)}
+	file := &diskReader{ctx: ctx, limit: s.diskIO, r: storeFile}
 	allocationTracking := sync2.NewThrottle()
 	totalAllocated := int64(0)
 
+	limit := s.newOrderLimit(pb.PayerBandwidthAllocation_GET, id)
+
 	// Bandwidth Allocation recv loop
 	go func() {
 		var lastTotal int64
@@ -96,10 +110,10 @@ func (s *Server) retrieveData(ctx context.Context, stream pb.PieceStoreRoutes_Re
 			if lastAllocation == nil {
 				return
 			}
-			err := s.DB.WriteBandwidthAllocToDB(lastAllocation)
+			err := s.saveAgreement(ctx, pb.PayerBandwidthAllocation_GET, lastAllocation)
 			if err != nil {
 				// TODO: handle error properly
-				log.Println("WriteBandwidthAllocToDB Error:", err)
+				log.Println("saveAgreement Error:", err)
 			}
 		}()
 
@@ -122,7 +136,10 @@ func (s *Server) retrieveData(ctx context.Context, stream pb.PieceStoreRoutes_Re
 				return
 			}
 
-			// TODO: break when lastTotal >= allocData.GetPayer_allocation().GetData().GetMax_size()
+			if err = limit.verify(ctx, allocData); err != nil {
+				allocationTracking.Fail(err)
+				return
+			}
 
 			if lastTotal > allocData.GetTotal() {
 				allocationTracking.Fail(fmt.Errorf("got lower allocation was %v got %v", lastTotal, allocData.GetTotal()))
@@ -152,7 +169,7 @@ func (s *Server) retrieveData(ctx context.Context, stream pb.PieceStoreRoutes_Re
 		}
 
 		used += nextMessageSize
-		n, err := io.CopyN(writer, storeFile, nextMessageSize)
+		n, err := io.CopyN(writer, file, nextMessageSize)
 		// correct errors when needed
 		if n != nextMessageSize {
 			if pErr := allocationTracking.Produce(nextMessageSize - n); pErr != nil {
@@ -6,21 +6,32 @@ package server
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"encoding/hex"
 	"log"
 	"os"
 	"path/filepath"
 	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gtank/cryptopasta"
 	"github.com/zeebo/errs"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"gopkg.in/spacemonkeygo/monkit.v2"
 
+	"storj.io/storj/pkg/orders"
+	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/peertls"
 	pstore "storj.io/storj/pkg/piecestore"
 	"storj.io/storj/pkg/piecestore/rpc/server/psdb"
 	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage/boltdb"
 )
 
 var (
@@ -30,9 +41,48 @@ var (
 	ServerError = errs.Class("PSServer error")
 )
 
+// serialsBucket is the bolt bucket of the serial numbers of used order limits
+const serialsBucket = "serials"
+
+// CtxKey Used as piece store server key
+type CtxKey int
+
+const (
+	ctxKeyServer CtxKey = iota
+)
+
 // Config contains everything necessary for a server
 type Config struct {
-	Path string `help:"path to store data in" default:"$CONFDIR"`
+	Path           string        `help:"path to store data in" default:"$CONFDIR"`
+	RetainThrottle time.Duration `help:"how long to wait between moving unretained pieces to the trash" default:"10ms"`
+	TrashTTL       time.Duration `help:"how long unretained pieces are kept in the trash before they are deleted, in case they were collected by mistake" default:"168h"`
+	VerifyOrders   bool          `help:"if true, requests without a valid order limit signed by a satellite are refused" default:"true"`
+	SatelliteIDs   string        `help:"comma-separated ids of the trusted satellites, whose order limits are accepted and which may garbage collect pieces. required to verify order limits" default:""`
+
+	MaxConcurrentUploads   int   `help:"maximum number of uploads served at once. further uploads are refused as the node is busy. 0 means no limit" default:"0"`
+	MaxConcurrentDownloads int   `help:"maximum number of downloads served at once. further downloads are refused as the node is busy. 0 means no limit" default:"0"`
+	MaxBandwidth           int64 `help:"maximum bandwidth (in bytes per second) of each upload and download. 0 means no limit" default:"0"`
+	MaxConcurrentDiskIO    int   `help:"maximum number of piece file reads and writes at once. 0 means no limit" default:"0"`
+	MinFreeSpace           int64 `help:"free disk space (in bytes) below which uploads are refused. downloads and deletes are still served" default:"1073741824"`
+
+	MonthlyBandwidthCap int64 `help:"maximum bandwidth (in bytes) used by uploads and downloads together each calendar month. 0 means no limit" default:"0"`
+	MonthlyIngressCap   int64 `help:"maximum bandwidth (in bytes) used by uploads each calendar month. 0 means no limit" default:"0"`
+	MonthlyEgressCap    int64 `help:"maximum bandwidth (in bytes) used by downloads each calendar month. 0 means no limit" default:"0"`
+
+	MaintenanceWindows string        `help:"weekly windows during which the node is taken offline for maintenance, as comma-separated day/hh:mm/duration in UTC, e.g. sun/02:00/2h. satellites don't hold the downtime against the node within their monthly budget" default:""`
+	MaintenanceNotice  time.Duration `help:"how long before a maintenance window satellites are told of it" default:"1h"`
+	MaintenanceDrain   time.Duration `help:"how long before a maintenance window new uploads and downloads are refused, so the ones in flight finish before the node goes offline" default:"5m"`
+
+	SatelliteAllocations string `help:"disk space and monthly bandwidth (in bytes) allocated to satellites, as comma-separated id=space/bandwidth. 0 means no limit. satellites not listed are only limited by the limits of the node" default:""`
+
+	OperatorEmail   string `help:"the email address of the operator of the node, sent to satellites" default:""`
+	OperatorWallet  string `help:"the ethereum address the payouts of the node are sent to" default:""`
+	OperatorCountry string `help:"the ISO 3166-1 alpha-2 code of the country the node is in, e.g. DE. satellites check it against the node's IP address" default:""`
+	WalletSignature string `help:"the hex signature of the wallet by the certificate authority of the node, created with identity ca sign-wallet. optional" default:""`
+	AuditAlertURL   string `help:"the url a JSON alert is posted to when a satellite reports that the node is failing its audits, e.g. a webhook mailing the operator. no alert is posted if empty" default:""`
+
+	MaxUsedSerials      int           `help:"maximum number of serial numbers of used order limits kept in memory. all of them are kept on disk" default:"100000"`
+	UsedSerialsInterval time.Duration `help:"how often the serial numbers of expired order limits are deleted" default:"1m"`
 }
 
 // Run implements provider.Responsibility
@@ -43,6 +93,41 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 	if err != nil {
 		return err
 	}
+	s.identity = server.Identity()
+
+	if s.operator, err = c.Operator(); err != nil {
+		return err
+	}
+	if s.operator != nil {
+		if err := overlay.ValidateOperator(s.operator, server.Identity().CA.PublicKey); err != nil {
+			return err
+		}
+	}
+
+	if c.VerifyOrders {
+		if len(s.satellites) == 0 {
+			return ServerError.New("storage.satellite-ids must list the trusted satellites to verify order limits")
+		}
+		s.orders = orders.NewVerifier(server.Identity().ID.String(), c.satelliteIDs())
+
+		db, err := boltdb.New(filepath.Join(c.Path, "serials.db"), serialsBucket)
+		if err != nil {
+			return err
+		}
+		defer utils.LogClose(db)
+
+		s.serials = orders.NewUsedSerials(db, c.MaxUsedSerials)
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			s.serials.Run(ctx, c.UsedSerialsInterval)
+		}()
+		defer func() {
+			cancel()
+			<-done
+		}()
+	}
 
 	pb.RegisterPieceStoreRoutesServer(server.GRPC(), s)
 
@@ -50,7 +135,44 @@ func (c Config) Run(ctx context.Context, server *provider.Provider) (err error)
 		log.Fatal(s.Stop(ctx))
 	}()
 
-	return server.Run(ctx)
+	return server.Run(context.WithValue(ctx, ctxKeyServer, s))
+}
+
+// satelliteIDs returns the ids of the trusted satellites
+func (c Config) satelliteIDs() (ids []string) {
+	for _, id := range strings.Split(c.SatelliteIDs, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Operator returns the configured operator of the node, or nil if none is
+// configured
+func (c Config) Operator() (*pb.NodeOperator, error) {
+	if c.OperatorEmail == "" && c.OperatorWallet == "" && c.OperatorCountry == "" {
+		return nil, nil
+	}
+	signature, err := hex.DecodeString(c.WalletSignature)
+	if err != nil {
+		return nil, ServerError.New("invalid wallet signature: %v", err)
+	}
+	return &pb.NodeOperator{
+		Email:           c.OperatorEmail,
+		Wallet:          c.OperatorWallet,
+		WalletSignature: signature,
+		Country:         c.OperatorCountry,
+	}, nil
+}
+
+// LoadFromContext loads an existing piece store Server from the Provider
+// context stack if one exists.
+func LoadFromContext(ctx context.Context) *Server {
+	if v, ok := ctx.Value(ctxKeyServer).(*Server); ok {
+		return v
+	}
+	return nil
 }
 
 // Server -- GRPC server meta data used in route calls
@@ -58,6 +180,51 @@ type Server struct {
 	DataDir string
 	DB      *psdb.DB
 	pkey    crypto.PrivateKey
+	// operator is sent to satellites at check-in, if configured
+	operator *pb.NodeOperator
+	// identity signs the receipts of pieces transferred from exiting nodes.
+	// If nil, transferred pieces are refused.
+	identity *provider.FullIdentity
+	// satellites are the ids of the trusted satellites, which may call the
+	// RPCs reserved to satellites
+	satellites map[string]bool
+	// orders verifies the order limits of requests. If nil, order limits
+	// aren't verified.
+	orders *orders.Verifier
+	// serials keeps track of the order limits that were used. If nil,
+	// replayed order limits aren't refused.
+	serials *orders.UsedSerials
+
+	// uploads, downloads and diskIO limit the number of concurrent requests
+	// and disk operations, and maxBandwidth the bandwidth of each request. The
+	// zero values don't limit anything.
+	uploads      limiter
+	downloads    limiter
+	diskIO       limiter
+	maxBandwidth int64
+	// minFreeSpace is the free disk space below which uploads are refused
+	minFreeSpace int64
+	// bandwidth checks the bandwidth used this month against the monthly
+	// caps. If nil, the bandwidth used isn't accounted.
+	bandwidth *bandwidthCaps
+	// allocations are the disk space and bandwidth allocated to satellites
+	allocations map[string]Allocation
+	// vetting and audits are the latest vetting progress and audit status
+	// sent by each satellite
+	vettingMu sync.Mutex
+	vetting   map[string]*pb.VettingProgress
+	audits    map[string]*pb.AuditStatus
+	// auditAlertURL is where alerts are posted when the node starts failing
+	// the audits of a satellite, none if empty
+	auditAlertURL string
+	// maintenance schedules the maintenance windows, and counts the
+	// transfers to drain before them
+	maintenance maintenance
+
+	retainThrottle time.Duration
+	retaining      int32
+	stopping       int32
+	retainWG       sync.WaitGroup
 }
 
 // Initialize -- initializes a server struct
@@ -65,16 +232,89 @@ func Initialize(ctx context.Context, config Config, pkey crypto.PrivateKey) (*Se
 	dbPath := filepath.Join(config.Path, "piecestore.db")
 	dataDir := filepath.Join(config.Path, "piece-store-data")
 
-	db, err := psdb.Open(ctx, dataDir, dbPath)
+	allocations, err := ParseAllocations(config.SatelliteAllocations)
+	if err != nil {
+		return nil, err
+	}
+	windows, err := ParseMaintenanceWindows(config.MaintenanceWindows)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := psdb.Open(ctx, dataDir, dbPath, config.TrashTTL)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Server{DataDir: dataDir, DB: db, pkey: pkey}, nil
+	satellites := map[string]bool{}
+	for _, id := range config.satelliteIDs() {
+		satellites[id] = true
+	}
+
+	return &Server{
+		DataDir:        dataDir,
+		DB:             db,
+		pkey:           pkey,
+		satellites:     satellites,
+		uploads:        newLimiter(config.MaxConcurrentUploads),
+		downloads:      newLimiter(config.MaxConcurrentDownloads),
+		diskIO:         newLimiter(config.MaxConcurrentDiskIO),
+		maxBandwidth:   config.MaxBandwidth,
+		minFreeSpace:   config.MinFreeSpace,
+		bandwidth:      newBandwidthCaps(db, config, allocations),
+		allocations:    allocations,
+		vetting:        map[string]*pb.VettingProgress{},
+		audits:         map[string]*pb.AuditStatus{},
+		auditAlertURL:  config.AuditAlertURL,
+		retainThrottle: config.RetainThrottle,
+		maintenance: maintenance{
+			windows: windows,
+			notice:  config.MaintenanceNotice,
+			drain:   config.MaintenanceDrain,
+		},
+	}, nil
+}
+
+// MaxBandwidth returns the bandwidth limit of each upload and download, 0 if
+// they aren't limited
+func (s *Server) MaxBandwidth() int64 {
+	return s.maxBandwidth
+}
+
+// Operator returns the operator of the node, or nil if none is configured
+func (s *Server) Operator() *pb.NodeOperator {
+	return s.operator
+}
+
+// Vetting returns the latest vetting progress sent by each satellite
+func (s *Server) Vetting() map[string]*pb.VettingProgress {
+	s.vettingMu.Lock()
+	defer s.vettingMu.Unlock()
+	vetting := make(map[string]*pb.VettingProgress, len(s.vetting))
+	for satellite, progress := range s.vetting {
+		vetting[satellite] = progress
+	}
+	return vetting
+}
+
+// trustedSatellite returns the id of the calling satellite, or an error if
+// the caller isn't a trusted satellite
+func (s *Server) trustedSatellite(ctx context.Context) (string, error) {
+	identity, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return "", status.Errorf(codes.Unauthenticated, err.Error())
+	}
+	id := identity.ID.String()
+	if !s.satellites[id] {
+		return "", status.Errorf(codes.PermissionDenied, "%s is not a trusted satellite", id)
+	}
+	return id, nil
 }
 
 // Stop the piececstore node
 func (s *Server) Stop(ctx context.Context) (err error) {
+	atomic.StoreInt32(&s.stopping, 1)
+	s.retainWG.Wait()
 	return s.DB.Close()
 }
 
@@ -97,6 +337,9 @@ func (s *Server) Piece(ctx context.Context, in *pb.PieceId) (*pb.PieceSummary, e
 	}
 
 	fileInfo, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, status.Errorf(codes.NotFound, "piece %s not found", in.GetId())
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -111,22 +354,78 @@ func (s *Server) Piece(ctx context.Context, in *pb.PieceId) (*pb.PieceSummary, e
 	return &pb.PieceSummary{Id: in.GetId(), Size: fileInfo.Size(), ExpirationUnixSec: ttl}, nil
 }
 
-// Stats will return statistics about the Server
+// Stats will return statistics about the Server. Satellites check the
+// nodes in with it, so the node advertises there the disk space left for
+// uploads, whether it reached the monthly bandwidth caps of uploads and
+// downloads, and its operator. Satellites with an allocation are advertised
+// the space and bandwidth left of their allocation. Satellites send the
+// node its vetting progress and audit status with it, and are told of the
+// upcoming maintenance window.
 func (s *Server) Stats(ctx context.Context, in *pb.StatsReq) (*pb.StatSummary, error) {
 	log.Printf("Getting Stats...\n")
 
-	totalUsed, err := s.DB.SumTTLSizes()
+	var satellite string
+	if peer, err := provider.PeerIdentityFromContext(ctx); err == nil {
+		satellite = peer.ID.String()
+	}
+	if satellite != "" && in.GetVetting() != nil {
+		s.vettingMu.Lock()
+		s.vetting[satellite] = in.GetVetting()
+		s.vettingMu.Unlock()
+	}
+	if satellite != "" && in.GetAudit() != nil {
+		s.recordAudits(satellite, in.GetAudit())
+	}
+
+	totalUsed, available, err := s.satelliteSpace(satellite)
+	if err != nil {
+		return nil, err
+	}
+	if s.allocations[satellite].Space <= 0 {
+		if totalUsed, err = s.DB.SumTTLSizes(); err != nil {
+			return nil, err
+		}
+	}
+
+	ingressFull, egressFull, err := s.bandwidth.full(satellite)
 	if err != nil {
 		return nil, err
 	}
 
-	return &pb.StatSummary{UsedSpace: totalUsed, AvailableSpace: 0}, nil
+	if available < 0 {
+		available = 0
+	}
+
+	return &pb.StatSummary{
+		UsedSpace:      totalUsed,
+		AvailableSpace: available,
+		IngressFull:    ingressFull,
+		EgressFull:     egressFull,
+		Operator:       s.operator,
+		Maintenance:    s.announcedMaintenance(time.Now()),
+	}, nil
 }
 
 // Delete -- Delete data by Id from piecestore
 func (s *Server) Delete(ctx context.Context, in *pb.PieceDelete) (*pb.PieceDeleteSummary, error) {
 	log.Printf("Deleting %s...", in.GetId())
 
+	if s.orders != nil {
+		uplink, err := provider.PeerIdentityFromContext(ctx)
+		if err != nil {
+			return nil, status.Errorf(codes.Unauthenticated, err.Error())
+		}
+		data, err := s.orders.Verify(in.GetOrderLimit(), pb.PayerBandwidthAllocation_DELETE, in.GetId(), uplink.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		if s.serials != nil {
+			if err := s.serials.Use(data.GetSerialNumber(), data.GetExpirationUnixSec()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	if err := s.deleteByID(in.GetId()); err != nil {
 		return nil, err
 	}
@@ -7,17 +7,21 @@ import (
 	"bytes"
 	"crypto"
 	"crypto/ecdsa"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/gtank/cryptopasta"
@@ -25,9 +29,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
+	"storj.io/storj/pkg/bloomfilter"
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/orders"
 	"storj.io/storj/pkg/pb"
 	pstore "storj.io/storj/pkg/piecestore"
+	"storj.io/storj/pkg/piecestore/rpc/client"
 	"storj.io/storj/pkg/piecestore/rpc/server/psdb"
 	"storj.io/storj/pkg/provider"
 )
@@ -128,6 +138,51 @@ func TestPiece(t *testing.T) {
 	}
 }
 
+func TestStatPieces(t *testing.T) {
+	TS := NewTestServer(t)
+	defer TS.Stop()
+
+	assert := assert.New(t)
+
+	const id = "11111111111111111111"
+	if err := writeFileToDir(id, TS.s.DataDir); err != nil {
+		t.Errorf("Error: %v\nCould not create test piece", err)
+		return
+	}
+	defer func() { _ = pstore.Delete(id, TS.s.DataDir) }()
+
+	_, err := TS.s.DB.DB.Exec(fmt.Sprintf(`INSERT INTO ttl (id, created, expires) VALUES ("%s", "%d", "%d")`, id, 1234567890, 9999999999))
+	assert.NoError(err)
+
+	resp, err := TS.c.StatPieces(ctx, &pb.StatPiecesRequest{Ids: []string{"22222222222222222222", id, "123"}})
+	if !assert.NoError(err) {
+		return
+	}
+	pieces := resp.GetPieces()
+	if !assert.Len(pieces, 3) {
+		return
+	}
+	assert.False(pieces[0].GetExists())
+	assert.False(pieces[2].GetExists())
+
+	hash := eestream.MerkleRoot([][]byte{eestream.MerkleLeaf([]byte("butts"))})
+	piece := pieces[1]
+	assert.Equal(id, piece.GetId())
+	assert.True(piece.GetExists())
+	assert.Equal(int64(5), piece.GetSize())
+	assert.Equal(int64(1234567890), piece.GetCreationUnixSec())
+	assert.Equal(int64(9999999999), piece.GetExpirationUnixSec())
+	assert.Equal(hash, piece.GetHash())
+
+	// the hash computed for the piece is recorded
+	stats, err := TS.s.DB.StatPieces(ctx, []string{id})
+	assert.NoError(err)
+	assert.Equal(hash, stats[id].Hash)
+
+	_, err = TS.c.StatPieces(ctx, &pb.StatPiecesRequest{Ids: make([]string, maxStatPieces+1)})
+	assert.Error(err)
+}
+
 func TestRetrieve(t *testing.T) {
 	t.Skip("broken test")
 
@@ -404,6 +459,43 @@ func TestStore(t *testing.T) {
 	}
 }
 
+func TestLowDiskSpace(t *testing.T) {
+	assert := assert.New(t)
+
+	TS := NewTestServer(t)
+	defer TS.Stop()
+
+	if TS.s.AvailableSpace() < 0 {
+		t.Skip("free disk space is unknown on this platform")
+	}
+
+	stats, err := TS.c.Stats(ctx, &pb.StatsReq{})
+	assert.NoError(err)
+	assert.True(stats.GetAvailableSpace() > 0)
+
+	// the watermark is above any free space
+	TS.s.minFreeSpace = 1 << 62
+
+	stats, err = TS.c.Stats(ctx, &pb.StatsReq{})
+	assert.NoError(err)
+	assert.Equal(int64(0), stats.GetAvailableSpace())
+
+	stream, err := TS.c.Store(ctx)
+	assert.NoError(err)
+	err = stream.Send(&pb.PieceStore{Piecedata: &pb.PieceStore_PieceData{Id: "99999999999999999999"}})
+	if err != io.EOF {
+		assert.NoError(err)
+	}
+	_, err = stream.CloseAndRecv()
+	assert.True(client.IsBusy(err))
+
+	// deletes are still served
+	assert.NoError(writeFileToDir("11111111111111111111", TS.s.DataDir))
+	resp, err := TS.c.Delete(ctx, &pb.PieceDelete{Id: "11111111111111111111"})
+	assert.NoError(err)
+	assert.Equal(OK, resp.GetMessage())
+}
+
 func TestDelete(t *testing.T) {
 	TS := NewTestServer(t)
 	defer TS.Stop()
@@ -476,6 +568,226 @@ func TestDelete(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("should refuse deletes without an order limit", func(t *testing.T) {
+		assert := assert.New(t)
+
+		TS.s.orders = orders.NewVerifier("node", []string{"satellite"})
+		defer func() { TS.s.orders = nil }()
+
+		if err := writeFileToDir("11111111111111111111", TS.s.DataDir); err != nil {
+			t.Errorf("Error: %v\nCould not create test piece", err)
+			return
+		}
+		defer func() {
+			assert.NoError(pstore.Delete("11111111111111111111", TS.s.DataDir))
+		}()
+
+		_, err := TS.c.Delete(ctx, &pb.PieceDelete{Id: "11111111111111111111"})
+		assert.Error(err)
+
+		filePath, err := pstore.PathByID("11111111111111111111", TS.s.DataDir)
+		assert.NoError(err)
+		_, err = os.Stat(filePath)
+		assert.NoError(err)
+	})
+}
+
+func TestRetain(t *testing.T) {
+	TS := NewTestServer(t)
+	defer TS.Stop()
+
+	db := TS.s.DB.DB
+
+	pieces := []struct {
+		id        string
+		created   int64
+		satellite string
+		retained  bool
+	}{
+		{id: "11111111111111111111", created: 100, satellite: TS.id, retained: true},   // in the filter
+		{id: "22222222222222222222", created: 100, satellite: TS.id, retained: false},  // garbage
+		{id: "33333333333333333333", created: 300, satellite: TS.id, retained: true},   // newer than the filter
+		{id: "44444444444444444444", created: 100, satellite: "other", retained: true}, // of another satellite
+	}
+
+	filter := bloomfilter.NewOptimal(len(pieces), 0.01)
+	for _, piece := range pieces {
+		if err := writeFileToDir(piece.id, TS.s.DataDir); err != nil {
+			t.Errorf("Error: %v\nCould not create test piece", err)
+			return
+		}
+
+		_, err := db.Exec(`INSERT INTO ttl (id, created, expires, satellite) VALUES (?, ?, 0, ?)`, piece.id, piece.created, piece.satellite)
+		assert.NoError(t, err)
+
+		if piece.created < 200 && piece.retained && piece.satellite == TS.id {
+			filter.Add([]byte(piece.id))
+		}
+	}
+
+	t.Run("should refuse untrusted satellites", func(t *testing.T) {
+		_, err := TS.c.Retain(ctx, &pb.RetainRequest{CreationUnixSec: 200, Filter: filter.Bytes()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	TS.s.satellites = map[string]bool{TS.id: true}
+
+	t.Run("should err with invalid filter", func(t *testing.T) {
+		_, err := TS.c.Retain(ctx, &pb.RetainRequest{CreationUnixSec: 200, Filter: []byte("butts")})
+		assert.Error(t, err)
+	})
+
+	t.Run("should move unretained pieces to trash", func(t *testing.T) {
+		assert := assert.New(t)
+
+		resp, err := TS.c.Retain(ctx, &pb.RetainRequest{CreationUnixSec: 200, Filter: filter.Bytes()})
+		if !assert.NoError(err) {
+			return
+		}
+		assert.Equal(int64(2), resp.GetRetained())
+		assert.Equal(int64(1), resp.GetDeleted())
+
+		// wait for the pieces to be moved to trash
+		TS.s.retainWG.Wait()
+
+		for _, piece := range pieces {
+			dataPath, err := pstore.PathByID(piece.id, TS.s.DataDir)
+			assert.NoError(err)
+			trashPath, err := pstore.PathByID(piece.id, filepath.Join(TS.s.DataDir, pstore.TrashDir))
+			assert.NoError(err)
+
+			_, dataErr := os.Stat(dataPath)
+			_, trashErr := os.Stat(trashPath)
+			_, ttlErr := TS.s.DB.GetTTLByID(piece.id)
+			if piece.retained {
+				assert.NoError(dataErr)
+				assert.True(os.IsNotExist(trashErr))
+				assert.NoError(ttlErr)
+			} else {
+				assert.True(os.IsNotExist(dataErr))
+				assert.NoError(trashErr)
+				assert.Error(ttlErr)
+			}
+		}
+	})
+}
+
+func TestLimits(t *testing.T) {
+	assert := assert.New(t)
+
+	uploads := newLimiter(2)
+	assert.True(uploads.tryAcquire())
+	assert.True(uploads.tryAcquire())
+	assert.False(uploads.tryAcquire())
+	uploads.release()
+	assert.True(uploads.tryAcquire())
+
+	// the nil limiter doesn't limit anything
+	unlimited := newLimiter(0)
+	for i := 0; i < 10; i++ {
+		assert.True(unlimited.tryAcquire())
+	}
+	unlimited.release()
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Error(uploads.acquire(canceled))
+
+	assert.True(client.IsBusy(errBusy("uploads")))
+	assert.False(client.IsBusy(io.EOF))
+
+	s := &Server{maxBandwidth: 100 * 1024}
+	data := bytes.Repeat([]byte{1}, 10*1024)
+	var buf bytes.Buffer
+	start := time.Now()
+	n, err := io.Copy(&throttledWriter{w: &buf, rate: s.newRateLimit(ctx)}, bytes.NewReader(data))
+	assert.NoError(err)
+	assert.Equal(int64(len(data)), n)
+	// 10 KiB at 100 KiB/s
+	assert.True(time.Since(start) >= 90*time.Millisecond)
+
+	// requests aren't throttled without a bandwidth limit
+	assert.Nil((&Server{}).newRateLimit(ctx))
+}
+
+func TestBandwidthCaps(t *testing.T) {
+	assert := assert.New(t)
+
+	s, cleanup := newTestServerStruct(t)
+	defer cleanup()
+
+	// usage of the previous month doesn't count
+	lastMonth := StartOfMonth(time.Now()).Add(-time.Hour)
+	assert.NoError(s.DB.AddBandwidthUsed(pb.PayerBandwidthAllocation_GET, 1000, lastMonth, ""))
+
+	s.bandwidth = newBandwidthCaps(s.DB, Config{MonthlyBandwidthCap: 300, MonthlyEgressCap: 100}, nil)
+	assert.NoError(s.bandwidth.check("", pb.PayerBandwidthAllocation_PUT))
+	assert.NoError(s.bandwidth.check("", pb.PayerBandwidthAllocation_GET))
+
+	assert.NoError(s.bandwidth.add("", pb.PayerBandwidthAllocation_GET, 100))
+	assert.NoError(s.bandwidth.check("", pb.PayerBandwidthAllocation_PUT))
+	assert.True(ErrBandwidthCap.Has(s.bandwidth.check("", pb.PayerBandwidthAllocation_GET)))
+
+	stats, err := s.Stats(ctx, &pb.StatsReq{})
+	assert.NoError(err)
+	assert.False(stats.GetIngressFull())
+	assert.True(stats.GetEgressFull())
+
+	// the total cap applies to both
+	assert.NoError(s.bandwidth.add("", pb.PayerBandwidthAllocation_PUT, 200))
+	assert.True(ErrBandwidthCap.Has(s.bandwidth.check("", pb.PayerBandwidthAllocation_PUT)))
+
+	// usage is loaded from the database after a restart
+	s.bandwidth = newBandwidthCaps(s.DB, Config{MonthlyIngressCap: 200}, nil)
+	assert.NoError(s.bandwidth.check("", pb.PayerBandwidthAllocation_GET))
+	assert.Error(s.bandwidth.check("", pb.PayerBandwidthAllocation_PUT))
+
+	limit := s.newOrderLimit(pb.PayerBandwidthAllocation_PUT, "id")
+	assert.True(client.IsBusy(limit.verify(ctx, &pb.RenterBandwidthAllocation_Data{})))
+
+	// nothing is limited without caps
+	s.bandwidth = newBandwidthCaps(s.DB, Config{}, nil)
+	assert.NoError(s.bandwidth.check("", pb.PayerBandwidthAllocation_PUT))
+}
+
+func TestSatelliteAllocations(t *testing.T) {
+	assert := assert.New(t)
+
+	allocations, err := ParseAllocations("a=300/100, b=0/0")
+	assert.NoError(err)
+	assert.Equal(map[string]Allocation{"a": {Space: 300, Bandwidth: 100}, "b": {}}, allocations)
+	for _, invalid := range []string{"a", "=1/1", "a=1", "a=x/1", "a=1/-1"} {
+		_, err := ParseAllocations(invalid)
+		assert.Error(err, invalid)
+	}
+
+	s, cleanup := newTestServerStruct(t)
+	defer cleanup()
+	s.allocations = allocations
+	s.bandwidth = newBandwidthCaps(s.DB, Config{}, allocations)
+
+	// the space and bandwidth of a satellite don't count for the others
+	assert.NoError(s.DB.AddTTL("piece", 0, 300, "a"))
+	assert.NoError(s.bandwidth.add("a", pb.PayerBandwidthAllocation_GET, 100))
+	assert.True(client.IsBusy(s.checkAllocation("a", pb.PayerBandwidthAllocation_PUT)))
+	assert.True(client.IsBusy(s.checkAllocation("a", pb.PayerBandwidthAllocation_GET)))
+	assert.NoError(s.checkAllocation("b", pb.PayerBandwidthAllocation_PUT))
+	assert.NoError(s.checkAllocation("c", pb.PayerBandwidthAllocation_GET))
+
+	used, available, err := s.satelliteSpace("a")
+	assert.NoError(err)
+	assert.Equal(int64(300), used)
+	assert.Equal(int64(0), available)
+
+	// usage is loaded from the database after a restart
+	s.bandwidth = newBandwidthCaps(s.DB, Config{}, allocations)
+	ingressFull, egressFull, err := s.bandwidth.full("a")
+	assert.NoError(err)
+	assert.True(ingressFull && egressFull)
+	ingressFull, egressFull, err = s.bandwidth.full("b")
+	assert.NoError(err)
+	assert.False(ingressFull || egressFull)
 }
 
 func newTestServerStruct(t *testing.T) (*Server, func()) {
@@ -487,7 +799,7 @@ func newTestServerStruct(t *testing.T) (*Server, func()) {
 	tempDBPath := filepath.Join(tmp, "test.db")
 	tempDir := filepath.Join(tmp, "test-data", "3000")
 
-	psDB, err := psdb.Open(ctx, tempDir, tempDBPath)
+	psDB, err := psdb.Open(ctx, tempDir, tempDBPath, time.Hour)
 	if err != nil {
 		t.Fatalf("failed open psdb: %v", err)
 	}
@@ -523,6 +835,8 @@ type TestServer struct {
 	conn     *grpc.ClientConn
 	c        pb.PieceStoreRoutesClient
 	k        crypto.PrivateKey
+	// id is the id of the identity of the client
+	id string
 }
 
 func NewTestServer(t *testing.T) *TestServer {
@@ -551,7 +865,7 @@ func NewTestServer(t *testing.T) *TestServer {
 
 	k, ok := fiC.Key.(*ecdsa.PrivateKey)
 	assert.True(t, ok)
-	ts := &TestServer{s: s, scleanup: cleanup, grpcs: grpcs, k: k}
+	ts := &TestServer{s: s, scleanup: cleanup, grpcs: grpcs, k: k, id: fiC.ID.String()}
 	addr := ts.start()
 	ts.c, ts.conn = connect(addr, co)
 
@@ -585,3 +899,170 @@ func serializeData(ba *pb.RenterBandwidthAllocation_Data) []byte {
 	data, _ := proto.Marshal(ba)
 	return data
 }
+
+func TestMaintenance(t *testing.T) {
+	assert := assert.New(t)
+
+	windows, err := ParseMaintenanceWindows("sun/02:00/2h, Wed/23:30/1h")
+	assert.NoError(err)
+	assert.Equal([]MaintenanceWindow{
+		{Weekday: time.Sunday, Start: 2 * time.Hour, Duration: 2 * time.Hour},
+		{Weekday: time.Wednesday, Start: 23*time.Hour + 30*time.Minute, Duration: time.Hour},
+	}, windows)
+	for _, invalid := range []string{"sun", "sunday/02:00/2h", "sun/25:00/2h", "sun/02:00/-1h"} {
+		_, err := ParseMaintenanceWindows(invalid)
+		assert.Error(err, invalid)
+	}
+
+	// Sunday 2018-11-04 03:00 is within the first window, and 05:00 is
+	// before the second one
+	m := &maintenance{windows: windows}
+	start, end, ok := m.window(time.Date(2018, 11, 4, 3, 0, 0, 0, time.UTC))
+	assert.True(ok)
+	assert.Equal(time.Date(2018, 11, 4, 2, 0, 0, 0, time.UTC), start)
+	assert.Equal(time.Date(2018, 11, 4, 4, 0, 0, 0, time.UTC), end)
+	start, _, _ = m.window(time.Date(2018, 11, 4, 5, 0, 0, 0, time.UTC))
+	assert.Equal(time.Date(2018, 11, 7, 23, 30, 0, 0, time.UTC), start)
+
+	// satellites are told of the window within the notice, and transfers
+	// are refused within the drain period
+	now := time.Now().UTC()
+	soon := now.Add(30 * time.Minute)
+	s := &Server{maintenance: maintenance{
+		windows: []MaintenanceWindow{{
+			Weekday:  soon.Weekday(),
+			Start:    soon.Sub(time.Date(soon.Year(), soon.Month(), soon.Day(), 0, 0, 0, 0, time.UTC)),
+			Duration: time.Hour,
+		}},
+		notice: time.Hour,
+		drain:  10 * time.Minute,
+	}}
+	assert.Nil(s.announcedMaintenance(now.Add(-time.Hour)))
+	if announced := s.announcedMaintenance(now); assert.NotNil(announced) {
+		assert.Equal(soon.Unix(), announced.GetStartUnixSec())
+	}
+	done, err := s.startTransfer()
+	assert.NoError(err)
+	assert.Equal(int32(1), s.Maintenance().InFlight)
+	done()
+	assert.Equal(int32(0), s.Maintenance().InFlight)
+
+	s.maintenance.drain = time.Hour
+	assert.True(s.Maintenance().Draining)
+	_, err = s.startTransfer()
+	assert.True(client.IsBusy(err))
+}
+
+func TestAuditAlerts(t *testing.T) {
+	assert := assert.New(t)
+
+	alerts := make(chan AuditAlert, 2)
+	alerter := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert AuditAlert
+		assert.NoError(json.NewDecoder(r.Body).Decode(&alert))
+		alerts <- alert
+	}))
+	defer alerter.Close()
+
+	s := &Server{
+		operator:      &pb.NodeOperator{Email: "operator@example.com"},
+		audits:        map[string]*pb.AuditStatus{},
+		auditAlertURL: alerter.URL,
+	}
+	passing := &pb.AuditStatus{AuditCount: 10, AuditSuccessCount: 9, SuccessThreshold: 0.6}
+	failing := &pb.AuditStatus{AuditCount: 10, AuditSuccessCount: 5, SuccessThreshold: 0.6, Failing: true}
+
+	// the operator is alerted once when the node starts failing
+	s.recordAudits("sat1", passing)
+	s.recordAudits("sat1", failing)
+	s.recordAudits("sat1", failing)
+	select {
+	case alert := <-alerts:
+		assert.Equal("sat1", alert.Satellite)
+		assert.Equal("operator@example.com", alert.Email)
+		assert.Equal(int64(5), alert.AuditSuccessCount)
+		assert.Equal(0.6, alert.SuccessThreshold)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no audit alert posted")
+	}
+	select {
+	case <-alerts:
+		t.Fatal("audit alert posted twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Equal(map[string]*pb.AuditStatus{"sat1": failing}, s.Audits())
+}
+
+func TestOrderLimitExceeded(t *testing.T) {
+	assert := assert.New(t)
+
+	s := &Server{orders: orders.NewVerifier("node", []string{"satellite"})}
+	limit := s.newOrderLimit(pb.PayerBandwidthAllocation_PUT, "id")
+	assert.True(orders.ErrUnauthorized.Has(limit.allowReceived(10, 10)))
+
+	limit.data = &pb.PayerBandwidthAllocation_Data{MaxSize: 100}
+	assert.NoError(limit.allowReceived(50, 60))
+	// data the uplink didn't allocate, and data beyond the order limit
+	assert.True(client.IsLimitExceeded(limit.allowReceived(60, 50)))
+	assert.True(client.IsLimitExceeded(limit.allowReceived(150, 150)))
+	assert.False(client.IsBusy(limit.allowReceived(150, 150)))
+
+	// nothing is checked when order limits aren't verified
+	unverified := (&Server{}).newOrderLimit(pb.PayerBandwidthAllocation_PUT, "id")
+	assert.NoError(unverified.allowReceived(150, 0))
+}
+
+func TestMerkleTree(t *testing.T) {
+	TS := NewTestServer(t)
+	defer TS.Stop()
+
+	assert := assert.New(t)
+
+	const id = "33333333333333333333"
+	data := make([]byte, 3*eestream.LeafSize+10)
+	for i := range data {
+		data[i] = byte(i*7 + i/eestream.LeafSize)
+	}
+	file, err := pstore.StoreWriter(id, TS.s.DataDir)
+	if !assert.NoError(err) {
+		return
+	}
+	_, err = file.Write(data)
+	assert.NoError(err)
+	assert.NoError(file.Close())
+	defer func() { _ = pstore.Delete(id, TS.s.DataDir) }()
+	assert.NoError(TS.s.DB.AddTTL(id, 0, int64(len(data)), "sat1"))
+
+	hasher := eestream.NewMerkleHasher()
+	_, _ = hasher.Write(data)
+	leaves := hasher.Leaves()
+	root := eestream.MerkleRoot(leaves)
+
+	// only trusted satellites may challenge the node
+	_, err = TS.c.Challenge(ctx, &pb.ChallengeRequest{PieceId: id, Leaf: 3})
+	assert.Equal(codes.PermissionDenied, status.Code(err))
+	TS.s.satellites = map[string]bool{TS.id: true}
+
+	// the tree of pieces stored before trees were recorded is computed
+	challenge, err := TS.c.Challenge(ctx, &pb.ChallengeRequest{PieceId: id, Leaf: 3})
+	if assert.NoError(err) {
+		assert.Equal(data[3*eestream.LeafSize:], challenge.GetLeaf())
+		assert.True(eestream.VerifyMerkleProof(root, 4, 3, challenge.GetLeaf(), challenge.GetProof()))
+	}
+	recorded, err := TS.s.DB.GetLeaves(id)
+	assert.NoError(err)
+	assert.Equal(leaves, recorded)
+
+	proof, err := TS.c.Proof(ctx, &pb.ProofRequest{PieceId: id, FirstLeaf: 1, LastLeaf: 2})
+	if assert.NoError(err) {
+		assert.Equal(leaves[1:3], proof.GetLeaves())
+		assert.True(eestream.VerifyMerkleRange(root, 4, 1, proof.GetLeaves(), proof.GetProof()))
+	}
+
+	_, err = TS.c.Proof(ctx, &pb.ProofRequest{PieceId: id, FirstLeaf: 2, LastLeaf: 4})
+	assert.Error(err)
+	_, err = TS.c.Challenge(ctx, &pb.ChallengeRequest{PieceId: id, Leaf: 4})
+	assert.Error(err)
+	_, err = TS.c.Challenge(ctx, &pb.ChallengeRequest{PieceId: "44444444444444444444"})
+	assert.Error(err)
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package server
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/pb"
+	pstore "storj.io/storj/pkg/piecestore"
+	"storj.io/storj/pkg/utils"
+)
+
+// maxStatPieces is how many pieces a StatPieces request may ask for
+const maxStatPieces = 1000
+
+// StatPieces returns whether the requested pieces exist and, for those that
+// do, their size, creation time, expiration and hash, so that satellites can
+// verify segments without downloading them
+func (s *Server) StatPieces(ctx context.Context, in *pb.StatPiecesRequest) (_ *pb.StatPiecesResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ids := in.GetIds()
+	if len(ids) > maxStatPieces {
+		return nil, status.Errorf(codes.InvalidArgument, "too many pieces: %d > %d", len(ids), maxStatPieces)
+	}
+
+	stats, err := s.DB.StatPieces(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.StatPiecesResponse{Pieces: make([]*pb.PieceStat, 0, len(ids))}
+	for _, id := range ids {
+		piece := &pb.PieceStat{Id: id}
+		response.Pieces = append(response.Pieces, piece)
+
+		path, err := pstore.PathByID(id, s.DataDir)
+		if err != nil {
+			continue
+		}
+		info, err := os.Stat(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		stat := stats[id]
+		piece.Exists = true
+		piece.Size = info.Size()
+		piece.CreationUnixSec = stat.Created
+		piece.ExpirationUnixSec = stat.Expiration
+		piece.Hash = stat.Hash
+		if piece.CreationUnixSec == 0 {
+			piece.CreationUnixSec = info.ModTime().Unix()
+		}
+
+		// pieces stored before Merkle trees were recorded are hashed once
+		if piece.Hash == nil {
+			leaves, err := s.hashPiece(ctx, id, path)
+			if err != nil {
+				return nil, err
+			}
+			piece.Hash = eestream.MerkleRoot(leaves)
+		}
+	}
+
+	mon.IntVal("stat_pieces").Observe(int64(len(ids)))
+	return response, nil
+}
+
+// hashPiece computes the Merkle tree of the piece id from its file at path,
+// records it, and returns its leaves
+func (s *Server) hashPiece(ctx context.Context, id, path string) (_ [][]byte, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer utils.LogClose(file)
+
+	h := eestream.NewMerkleHasher()
+	if _, err := io.Copy(h, &diskReader{ctx: ctx, limit: s.diskIO, r: file}); err != nil {
+		return nil, err
+	}
+	leaves := h.Leaves()
+	if err := s.DB.SetMerkleTree(id, eestream.MerkleRoot(leaves), leaves); err != nil {
+		return nil, err
+	}
+	return leaves, nil
+}
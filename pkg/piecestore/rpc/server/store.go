@@ -9,6 +9,8 @@ import (
 	"log"
 
 	"github.com/zeebo/errs"
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/gracefulexit"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/piecestore"
 	"storj.io/storj/pkg/utils"
@@ -24,6 +26,23 @@ var StoreError = errs.Class("store error")
 func (s *Server) Store(reqStream pb.PieceStoreRoutes_StoreServer) (err error) {
 	ctx := reqStream.Context()
 	defer mon.Task()(&ctx)(&err)
+
+	if !s.uploads.tryAcquire() {
+		return errBusy("uploads")
+	}
+	defer s.uploads.release()
+
+	done, err := s.startTransfer()
+	if err != nil {
+		return err
+	}
+	defer done()
+
+	// refuse uploads before the disk fills up rather than failing mid-piece
+	if s.AvailableSpace() == 0 {
+		return errDiskFull()
+	}
+
 	// Receive id/ttl
 	recv, err := reqStream.Recv()
 	if err != nil {
@@ -44,22 +63,51 @@ func (s *Server) Store(reqStream pb.PieceStoreRoutes_StoreServer) (err error) {
 		return StoreError.New("Piece ID not specified")
 	}
 
-	total, err := s.storeData(ctx, reqStream, pd.GetId())
+	// the Merkle tree of every piece is recorded for StatPieces and proofs,
+	// and pieces transferred from exiting nodes get a receipt of its root
+	transfer := len(pd.GetExitingNodeId()) > 0
+	if transfer && s.identity == nil {
+		return StoreError.New("piece transfers are not accepted")
+	}
+	leaves := eestream.NewMerkleHasher()
+
+	total, satellite, err := s.storeData(ctx, reqStream, pd.GetId(), leaves)
 	if err != nil {
 		return err
 	}
 
-	if err = s.DB.AddTTL(pd.GetId(), pd.GetExpirationUnixSec(), total); err != nil {
+	if err = s.DB.AddTTL(pd.GetId(), pd.GetExpirationUnixSec(), total, satellite); err != nil {
 		deleteErr := s.deleteByID(pd.GetId())
 		return StoreError.New("failed to write piece meta data to database: %v", utils.CombineErrors(err, deleteErr))
 	}
 
+	root := eestream.MerkleRoot(leaves.Leaves())
+	if err = s.DB.SetMerkleTree(pd.GetId(), root, leaves.Leaves()); err != nil {
+		// the tree is computed again when it's requested
+		log.Printf("Failed to record the Merkle tree of %s: %v", pd.GetId(), err)
+	}
+
 	log.Printf("Successfully stored %s.", pd.GetId())
 
-	return reqStream.SendAndClose(&pb.PieceStoreSummary{Message: OK, TotalReceived: total})
+	summary := &pb.PieceStoreSummary{Message: OK, TotalReceived: total}
+	if transfer {
+		summary.Receipt, err = gracefulexit.SignReceipt(s.identity, &pb.PieceTransferReceipt_Data{
+			ExitingNodeId: pd.GetExitingNodeId(),
+			PieceId:       pd.GetId(),
+			PieceHash:     root,
+		})
+		if err != nil {
+			return StoreError.Wrap(err)
+		}
+	}
+
+	return reqStream.SendAndClose(summary)
 }
 
-func (s *Server) storeData(ctx context.Context, stream pb.PieceStoreRoutes_StoreServer, id string) (total int64, err error) {
+// storeData writes the piece data of the stream to disk, and returns its
+// size and the satellite of its order limit. If h isn't nil, the data is
+// written to h too.
+func (s *Server) storeData(ctx context.Context, stream pb.PieceStoreRoutes_StoreServer, id string, h io.Writer) (total int64, satellite string, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	// Delete data if we error
@@ -74,25 +122,33 @@ func (s *Server) storeData(ctx context.Context, stream pb.PieceStoreRoutes_Store
 	// Initialize file for storing data
 	storeFile, err := pstore.StoreWriter(id, s.DataDir)
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
 	defer utils.LogClose(storeFile)
 
-	reader := NewStreamReader(s, stream)
+	reader := NewStreamReader(s, stream, id)
 
 	defer func() {
-		baWriteErr := s.DB.WriteBandwidthAllocToDB(reader.bandwidthAllocation)
+		if reader.bandwidthAllocation == nil {
+			return
+		}
+		baWriteErr := s.saveAgreement(ctx, pb.PayerBandwidthAllocation_PUT, reader.bandwidthAllocation)
 		if baWriteErr != nil {
-			log.Printf("WriteBandwidthAllocToDB Error: %s\n", baWriteErr.Error())
+			log.Printf("saveAgreement Error: %s\n", baWriteErr.Error())
 		}
 	}()
 
-	total, err = io.Copy(storeFile, reader)
+	var w io.Writer = &diskWriter{ctx: ctx, limit: s.diskIO, w: storeFile}
+	if h != nil {
+		w = io.MultiWriter(w, h)
+	}
+
+	total, err = io.Copy(w, &throttledReader{r: reader, rate: s.newRateLimit(ctx)})
 
 	if err != nil && err != io.EOF {
-		return 0, err
+		return 0, "", err
 	}
 
-	return total, nil
+	return total, reader.limit.satellite, nil
 }
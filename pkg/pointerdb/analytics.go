@@ -0,0 +1,105 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/analytics"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/storage"
+)
+
+// createsBucket returns whether putting the pointer at path creates a
+// bucket. Buckets are stored at their name below the last segment, so
+// their paths have no object path. It's only checked if analytics are
+// emitted or buckets are attributed.
+func (s *Server) createsBucket(path string) bool {
+	if (s.analytics == nil && s.attributions == nil) || !isBucket(path) {
+		return false
+	}
+	_, err := s.DB.Get(storage.Key(path))
+	return storage.ErrKeyNotFound.Has(err)
+}
+
+// isBucket returns whether path is the path of a bucket
+func isBucket(path string) bool {
+	parts := strings.Split(path, "/")
+	return len(parts) == 2 && parts[1] != ""
+}
+
+// emitPut emits the events of putting the pointer at path with APIKey
+func (s *Server) emitPut(APIKey []byte, path string, createdBucket bool) {
+	if s.analytics == nil {
+		return
+	}
+	project := projectID(APIKey)
+	parts := strings.SplitN(path, "/", 3)
+	if createdBucket {
+		s.analytics.Emit(analytics.Event{
+			Name:       analytics.BucketCreated,
+			ProjectID:  project,
+			Properties: map[string]string{"bucket": parts[1]},
+		})
+	}
+	if len(parts) == 3 {
+		s.analytics.Once(analytics.FirstUpload+"/"+project, analytics.Event{
+			Name:      analytics.FirstUpload,
+			ProjectID: project,
+		})
+	}
+}
+
+// emitAborted emits that the request r was aborted for exceeding a limit
+func (s *Server) emitAborted(r *request) {
+	if s.analytics == nil {
+		return
+	}
+	s.analytics.Emit(analytics.Event{
+		Name:      analytics.LimitHit,
+		ProjectID: projectID(r.apiKey),
+		Properties: map[string]string{
+			"method": r.method,
+			"code":   status.Code(r.aborted).String(),
+		},
+	})
+}
+
+// emitBandwidthLimit emits that project went beyond its bandwidth limit, or
+// that a download was rejected for it if allowed is false
+func (s *Server) emitBandwidthLimit(project string, allowed bool) {
+	if s.analytics == nil {
+		return
+	}
+	s.analytics.Emit(analytics.Event{
+		Name:      analytics.LimitHit,
+		ProjectID: project,
+		Properties: map[string]string{
+			"limit":   "bandwidth",
+			"allowed": strconv.FormatBool(allowed),
+		},
+	})
+}
+
+// attribute attributes the usage of the bucket created at path to the
+// partner of its defaults. The bucket is created even if that fails.
+func (s *Server) attribute(ctx context.Context, APIKey []byte, path string, bucket *pb.Pointer) {
+	if s.attributions == nil {
+		return
+	}
+	defaults, err := buckets.ParseDefaults(bucket.GetMetadata())
+	if err != nil || defaults.PartnerID == "" {
+		return
+	}
+	name := strings.SplitN(path, "/", 3)[1]
+	if err := s.attributions.Attribute(ctx, projectID(APIKey), name, defaults.PartnerID); err != nil {
+		s.logger.Error("err attributing bucket", zap.String("bucket", name), zap.Error(err))
+	}
+}
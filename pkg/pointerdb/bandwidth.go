@@ -0,0 +1,273 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/storage"
+)
+
+// BandwidthBucket is the bolt bucket of the bandwidth allocated to projects
+const BandwidthBucket = "bandwidth"
+
+// ErrBandwidthExceeded is returned for downloads beyond the bandwidth
+// policy of their project
+var ErrBandwidthExceeded = errs.Class("bandwidth limit exceeded")
+
+// BandwidthMode is how the policy of a project limits its bandwidth
+type BandwidthMode string
+
+const (
+	// HardCap rejects the downloads beyond the monthly limit
+	HardCap BandwidthMode = "hard"
+	// Burst allows downloads up to a burst allowance beyond the monthly
+	// limit, which are billed as overage
+	Burst BandwidthMode = "burst"
+	// Alert never rejects downloads, but alerts when the project goes
+	// beyond the monthly limit
+	Alert BandwidthMode = "alert"
+)
+
+// BandwidthPolicy limits the download bandwidth allocated to a project each
+// month
+type BandwidthPolicy struct {
+	Mode  BandwidthMode `json:"mode"`
+	Limit int64         `json:"limit"`
+	// Burst is the allowance beyond Limit in the Burst mode
+	Burst int64 `json:"burst,omitempty"`
+}
+
+// allows returns whether the policy allows a project to be allocated
+// allocated bytes in a month
+func (policy BandwidthPolicy) allows(allocated int64) bool {
+	switch policy.Mode {
+	case HardCap:
+		return allocated <= policy.Limit
+	case Burst:
+		return allocated <= policy.Limit+policy.Burst
+	default:
+		return true
+	}
+}
+
+// ParseBandwidthPolicies parses the bandwidth policies of projects like
+// "project1=hard:1000000,project2=burst:1000000:500000,*=alert:1000000",
+// the mode, the monthly limit in bytes and the burst allowance in bytes of
+// each project. The policy of the project * applies to the projects without
+// a policy of their own.
+func ParseBandwidthPolicies(s string) (map[string]BandwidthPolicy, error) {
+	policies := map[string]BandwidthPolicy{}
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, Error.New("invalid bandwidth policy %q", pair)
+		}
+		fields := strings.Split(parts[1], ":")
+		policy := BandwidthPolicy{Mode: BandwidthMode(fields[0])}
+		switch {
+		case (policy.Mode == HardCap || policy.Mode == Alert) && len(fields) == 2:
+		case policy.Mode == Burst && len(fields) == 3:
+		default:
+			return nil, Error.New("invalid bandwidth policy %q: expected %s", pair, policyFormat(policy.Mode))
+		}
+		var err error
+		policy.Limit, err = strconv.ParseInt(fields[1], 10, 64)
+		if err != nil || policy.Limit < 0 {
+			return nil, Error.New("invalid bandwidth limit %q", pair)
+		}
+		if policy.Mode == Burst {
+			policy.Burst, err = strconv.ParseInt(fields[2], 10, 64)
+			if err != nil || policy.Burst < 0 {
+				return nil, Error.New("invalid burst allowance %q", pair)
+			}
+		}
+		policies[parts[0]] = policy
+	}
+	return policies, nil
+}
+
+// policyFormat returns the format of the policies of mode
+func policyFormat(mode BandwidthMode) string {
+	switch mode {
+	case HardCap, Alert:
+		return string(mode) + ":limit"
+	case Burst:
+		return "burst:limit:burst"
+	default:
+		return "hard:limit, burst:limit:burst or alert:limit"
+	}
+}
+
+// BandwidthUsage is the bandwidth allocated to a project in a month
+type BandwidthUsage struct {
+	Project   string           `json:"project"`
+	Month     string           `json:"month"`
+	Allocated int64            `json:"allocated"`
+	Policy    *BandwidthPolicy `json:"policy,omitempty"`
+	// Overage is the bandwidth allocated beyond the limit of the policy,
+	// which is billed to projects with a burst allowance
+	Overage int64 `json:"overage,omitempty"`
+}
+
+// Bandwidth accounts the download bandwidth allocated to projects with
+// order limits each month, and enforces their policies. Allocations start
+// over at the start of every month, in UTC.
+type Bandwidth struct {
+	log      *zap.Logger
+	db       storage.KeyValueStore
+	policies map[string]BandwidthPolicy
+
+	// mu serializes the allocations, which read and write the usage. A
+	// database shared by several satellites may lose concurrent
+	// allocations of the same project.
+	mu  sync.Mutex
+	now func() time.Time
+}
+
+// NewBandwidth creates the bandwidth accounting stored in db, enforcing
+// policies by project
+func NewBandwidth(log *zap.Logger, db storage.KeyValueStore, policies map[string]BandwidthPolicy) *Bandwidth {
+	return &Bandwidth{log: log, db: db, policies: policies, now: time.Now}
+}
+
+// month returns the month of t, at which allocations start over
+func month(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// bandwidthKey returns the key of the bandwidth allocated to project in
+// month. Keys start with the month, so that the usage of a month is listed
+// by prefix.
+func bandwidthKey(month, project string) storage.Key {
+	return storage.Key(month + "/" + project)
+}
+
+// Policy returns the bandwidth policy of project, or false if its
+// bandwidth isn't limited
+func (b *Bandwidth) Policy(project string) (BandwidthPolicy, bool) {
+	if policy, ok := b.policies[project]; ok {
+		return policy, true
+	}
+	policy, ok := b.policies["*"]
+	return policy, ok
+}
+
+// Allocate allocates bytes of bandwidth to project, or returns an
+// ErrBandwidthExceeded error if the allocation goes beyond its policy.
+// alert is true if the allocation took the project beyond its monthly
+// limit.
+func (b *Bandwidth) Allocate(ctx context.Context, project string, bytes int64) (alert bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := bandwidthKey(month(b.now()), project)
+	allocated, err := b.get(key)
+	if err != nil {
+		return false, err
+	}
+
+	policy, limited := b.Policy(project)
+	if limited && !policy.allows(allocated+bytes) {
+		mon.Counter("bandwidth_rejected").Inc(1)
+		return false, ErrBandwidthExceeded.New("project %s was allocated %d bytes of its %d byte %s limit this month",
+			project, allocated, policy.Limit, policy.Mode)
+	}
+
+	value := strconv.FormatInt(allocated+bytes, 10)
+	if err := b.db.Put(key, storage.Value(value)); err != nil {
+		return false, Error.Wrap(err)
+	}
+	mon.IntVal("bandwidth_allocated").Observe(bytes)
+
+	alert = limited && allocated <= policy.Limit && allocated+bytes > policy.Limit
+	if alert {
+		b.log.Warn("project went beyond its bandwidth limit", zap.String("project", project),
+			zap.String("mode", string(policy.Mode)), zap.Int64("limit", policy.Limit))
+	}
+	return alert, nil
+}
+
+// get returns the bandwidth allocated at key
+func (b *Bandwidth) get(key storage.Key) (int64, error) {
+	value, err := b.db.Get(key)
+	if storage.ErrKeyNotFound.Has(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, Error.Wrap(err)
+	}
+	allocated, err := strconv.ParseInt(string(value), 10, 64)
+	return allocated, Error.Wrap(err)
+}
+
+// Usage returns the bandwidth allocated to every project in month, like
+// "2006-01"
+func (b *Bandwidth) Usage(ctx context.Context, month string) (usage []BandwidthUsage, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	prefix := month + "/"
+	err = b.db.Iterate(storage.IterateOptions{Prefix: storage.Key(prefix), Recurse: true},
+		func(it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(&item) {
+				allocated, err := strconv.ParseInt(string(item.Value), 10, 64)
+				if err != nil {
+					return Error.Wrap(err)
+				}
+				project := strings.TrimPrefix(item.Key.String(), prefix)
+				entry := BandwidthUsage{Project: project, Month: month, Allocated: allocated}
+				if policy, ok := b.Policy(project); ok {
+					entry.Policy = &policy
+					if allocated > policy.Limit {
+						entry.Overage = allocated - policy.Limit
+					}
+				}
+				usage = append(usage, entry)
+			}
+			return nil
+		})
+	return usage, err
+}
+
+// ServeHTTP implements the admin API of the bandwidth, mounted at
+// /pointerdb/bandwidth:
+//
+//	GET /pointerdb/bandwidth?month=2006-01  returns the JSON BandwidthUsage
+//	                                        of every project in the month,
+//	                                        the current one by default
+func (b *Bandwidth) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	m := req.URL.Query().Get("month")
+	if m == "" {
+		m = month(b.now())
+	} else if _, err := time.Parse("2006-01", m); err != nil {
+		http.Error(w, "invalid month", http.StatusBadRequest)
+		return
+	}
+	usage, err := b.Usage(req.Context(), m)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(usage)
+}
@@ -0,0 +1,86 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/pb"
+)
+
+// maxBatchSize is the maximum number of requests of a batch
+const maxBatchSize = 100
+
+// Batch executes the requests of a batch in order, as if they were sent one
+// after the other. It stops at the first request that fails and returns its
+// error, so the requests before it took effect and the ones after it didn't.
+func (s *Server) Batch(ctx context.Context, req *pb.BatchRequest) (resp *pb.BatchResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+	s.logger.Debug("entering pointerdb batch")
+
+	requests := req.GetRequests()
+	if len(requests) > maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch of %d requests exceeds the maximum of %d", len(requests), maxBatchSize)
+	}
+
+	resp = &pb.BatchResponse{Responses: make([]*pb.BatchResponseItem, 0, len(requests))}
+	for i, request := range requests {
+		response, err := s.batchItem(ctx, request, req.GetAPIKey())
+		if err != nil {
+			return nil, status.Errorf(status.Code(err), "batch request %d: %s", i, status.Convert(err).Message())
+		}
+		resp.Responses = append(resp.Responses, response)
+	}
+	return resp, nil
+}
+
+// batchItem executes a request of a batch, with the API key of the batch if
+// it has none
+func (s *Server) batchItem(ctx context.Context, request *pb.BatchRequestItem, APIKey []byte) (*pb.BatchResponseItem, error) {
+	set := 0
+	for _, isSet := range []bool{request.GetPut() != nil, request.GetGet() != nil, request.GetDelete() != nil, request.GetOrderLimits() != nil} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return nil, status.Errorf(codes.InvalidArgument, "exactly one request must be set")
+	}
+
+	var err error
+	response := &pb.BatchResponseItem{}
+	switch {
+	case request.GetPut() != nil:
+		put := request.GetPut()
+		if len(put.APIKey) == 0 {
+			put.APIKey = APIKey
+		}
+		response.Put, err = s.Put(ctx, put)
+	case request.GetGet() != nil:
+		get := request.GetGet()
+		if len(get.APIKey) == 0 {
+			get.APIKey = APIKey
+		}
+		response.Get, err = s.Get(ctx, get)
+	case request.GetDelete() != nil:
+		del := request.GetDelete()
+		if len(del.APIKey) == 0 {
+			del.APIKey = APIKey
+		}
+		response.Delete, err = s.Delete(ctx, del)
+	case request.GetOrderLimits() != nil:
+		limits := request.GetOrderLimits()
+		if len(limits.APIKey) == 0 {
+			limits.APIKey = APIKey
+		}
+		response.OrderLimits, err = s.OrderLimits(ctx, limits)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
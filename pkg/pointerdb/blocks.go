@@ -0,0 +1,274 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/storage"
+)
+
+// BlockBucket is the bolt bucket of the blocked objects and their audit
+// trail
+const BlockBucket = "blocks"
+
+const (
+	blockPrefix = "block/"
+	trailPrefix = "trail/"
+)
+
+// Block blocks the downloads of an object, or of the objects under a prefix,
+// e.g. after an abuse report or a takedown notice. The data of the objects
+// isn't deleted.
+type Block struct {
+	// Path is the path of the object, bucket/path, or the prefix of the
+	// objects, which ends at a path component
+	Path    string    `json:"path"`
+	Prefix  bool      `json:"prefix"`
+	Reason  string    `json:"reason"`
+	By      string    `json:"by"`
+	Created time.Time `json:"created"`
+}
+
+// matches returns whether the block applies to the object at path
+func (block *Block) matches(path string) bool {
+	if block.Prefix {
+		return path == block.Path || strings.HasPrefix(path, block.Path+"/")
+	}
+	return path == block.Path
+}
+
+// BlockEvent is an entry of the audit trail of the blocks
+type BlockEvent struct {
+	// Action is "block" or "unblock"
+	Action string    `json:"action"`
+	Block  Block     `json:"block"`
+	By     string    `json:"by"`
+	Time   time.Time `json:"time"`
+}
+
+// Blocks stores the blocked objects, and the audit trail of who blocked and
+// unblocked what and when
+type Blocks struct {
+	log *zap.Logger
+	db  storage.KeyValueStore
+}
+
+// NewBlocks creates the blocks stored in db
+func NewBlocks(log *zap.Logger, db storage.KeyValueStore) *Blocks {
+	return &Blocks{log: log, db: db}
+}
+
+// Block blocks the object or prefix of block on behalf of block.By, and
+// returns the stored block
+func (b *Blocks) Block(ctx context.Context, block Block) (_ *Block, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	block.Path = strings.Trim(block.Path, "/")
+	if block.Path == "" {
+		return nil, Error.New("empty block path")
+	}
+	if block.By == "" {
+		return nil, Error.New("block of %q by nobody", block.Path)
+	}
+	block.Created = time.Now().UTC()
+
+	value, err := json.Marshal(block)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if err := b.record(BlockEvent{Action: "block", Block: block, By: block.By, Time: block.Created}); err != nil {
+		return nil, err
+	}
+	if err := b.db.Put(storage.Key(blockPrefix+block.Path), value); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	b.log.Info("blocked", zap.String("path", block.Path), zap.Bool("prefix", block.Prefix),
+		zap.String("reason", block.Reason), zap.String("by", block.By))
+	return &block, nil
+}
+
+// Unblock lifts the block of path on behalf of by
+func (b *Blocks) Unblock(ctx context.Context, path, by string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if by == "" {
+		return Error.New("unblock of %q by nobody", path)
+	}
+	path = strings.Trim(path, "/")
+	block, err := b.get(storage.Key(blockPrefix + path))
+	if err != nil {
+		return err
+	}
+	if err := b.record(BlockEvent{Action: "unblock", Block: *block, By: by, Time: time.Now().UTC()}); err != nil {
+		return err
+	}
+	if err := b.db.Delete(storage.Key(blockPrefix + path)); err != nil {
+		return Error.Wrap(err)
+	}
+	b.log.Info("unblocked", zap.String("path", path), zap.String("by", by))
+	return nil
+}
+
+// Blocked returns the block of the segment at path, or nil if it isn't
+// blocked
+func (b *Blocks) Blocked(ctx context.Context, path string) (block *Block, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	// segment paths start with the segment
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return nil, nil
+	}
+	object := parts[1]
+
+	// the block of the object, or of any prefix of it
+	var keys storage.Keys
+	for i := len(object); i > 0 && len(keys) < storage.LookupLimit; i = strings.LastIndex(object[:i], "/") {
+		keys = append(keys, storage.Key(blockPrefix+object[:i]))
+	}
+	values, err := b.db.GetAll(keys)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for _, value := range values {
+		if value == nil {
+			continue
+		}
+		block = &Block{}
+		if err := json.Unmarshal(value, block); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		if block.matches(object) {
+			return block, nil
+		}
+	}
+	return nil, nil
+}
+
+// List returns the blocks, ordered by path
+func (b *Blocks) List(ctx context.Context) (blocks []Block, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = b.iterate(blockPrefix, func(value storage.Value) error {
+		var block Block
+		if err := json.Unmarshal(value, &block); err != nil {
+			return err
+		}
+		blocks = append(blocks, block)
+		return nil
+	})
+	return blocks, Error.Wrap(err)
+}
+
+// Trail returns the audit trail of the blocks, oldest first
+func (b *Blocks) Trail(ctx context.Context) (events []BlockEvent, err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = b.iterate(trailPrefix, func(value storage.Value) error {
+		var event BlockEvent
+		if err := json.Unmarshal(value, &event); err != nil {
+			return err
+		}
+		events = append(events, event)
+		return nil
+	})
+	return events, Error.Wrap(err)
+}
+
+// get returns the block at key
+func (b *Blocks) get(key storage.Key) (*Block, error) {
+	value, err := b.db.Get(key)
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, err
+		}
+		return nil, Error.Wrap(err)
+	}
+	block := &Block{}
+	return block, Error.Wrap(json.Unmarshal(value, block))
+}
+
+// record appends event to the audit trail
+func (b *Blocks) record(event BlockEvent) error {
+	var suffix [4]byte
+	if _, err := rand.Read(suffix[:]); err != nil {
+		return Error.Wrap(err)
+	}
+	value, err := json.Marshal(event)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	// keys are ordered by time, with a random suffix to keep simultaneous
+	// events
+	key := trailPrefix + event.Time.Format("20060102T150405.000000000Z") + hex.EncodeToString(suffix[:])
+	return Error.Wrap(b.db.Put(storage.Key(key), value))
+}
+
+// iterate calls fn with the values of the keys starting with prefix
+func (b *Blocks) iterate(prefix string, fn func(storage.Value) error) error {
+	return b.db.Iterate(storage.IterateOptions{Prefix: storage.Key(prefix), Recurse: true},
+		func(it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(&item) {
+				if err := fn(item.Value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+}
+
+// ServeHTTP implements the admin API of the blocks, mounted at
+// /pointerdb/blocks/:
+//
+//	GET    /pointerdb/blocks/                   lists the blocks
+//	POST   /pointerdb/blocks/                   blocks the object or prefix of the JSON Block
+//	DELETE /pointerdb/blocks/?path=PATH&by=WHO  lifts the block of PATH
+//	GET    /pointerdb/blocks/trail              lists the audit trail
+func (b *Blocks) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	ctx := req.Context()
+	path := strings.Trim(strings.TrimPrefix(req.URL.Path, "/pointerdb/blocks"), "/")
+
+	var result interface{}
+	var err error
+	switch {
+	case path == "trail" && req.Method == http.MethodGet:
+		result, err = b.Trail(ctx)
+	case path != "":
+		http.NotFound(w, req)
+		return
+	case req.Method == http.MethodGet:
+		result, err = b.List(ctx)
+	case req.Method == http.MethodPost:
+		var block Block
+		if err := json.NewDecoder(req.Body).Decode(&block); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		result, err = b.Block(ctx, block)
+	case req.Method == http.MethodDelete:
+		err = b.Unblock(ctx, req.URL.Query().Get("path"), req.URL.Query().Get("by"))
+		result = struct{}{}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch {
+	case storage.ErrKeyNotFound.Has(err):
+		http.NotFound(w, req)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(result)
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"storj.io/storj/storage"
+)
+
+// CommitBucket is the bolt bucket of the commits of segments by idempotency
+// key
+const CommitBucket = "commits"
+
+// Commit is the result of the commit of a segment with an idempotency key
+type Commit struct {
+	Path         string    `json:"path"`
+	CreationDate time.Time `json:"creation_date"`
+}
+
+// Commits stores the results of the commits of segments by idempotency key,
+// so commits retried after an ambiguous failure don't write the pointer
+// again
+type Commits struct {
+	db     storage.KeyValueStore
+	window time.Duration
+}
+
+// NewCommits creates the commits stored in db. Idempotency keys can be
+// reused for another commit after window.
+func NewCommits(db storage.KeyValueStore, window time.Duration) *Commits {
+	return &Commits{db: db, window: window}
+}
+
+// commitKey scopes the idempotency key to the API key of the uplink
+func commitKey(APIKey []byte, idempotencyKey string) storage.Key {
+	return storage.Key(keyID(APIKey) + "/" + idempotencyKey)
+}
+
+// Get returns the commit with the idempotency key of the API key, or nil if
+// there's none within the window
+func (c *Commits) Get(ctx context.Context, APIKey []byte, idempotencyKey string) (commit *Commit, err error) {
+	defer mon.Task()(&ctx)(&err)
+	value, err := c.db.Get(commitKey(APIKey, idempotencyKey))
+	if storage.ErrKeyNotFound.Has(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	commit = &Commit{}
+	if err := json.Unmarshal(value, commit); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if c.window > 0 && time.Since(commit.CreationDate) > c.window {
+		return nil, nil
+	}
+	return commit, nil
+}
+
+// Put stores the commit with the idempotency key of the API key
+func (c *Commits) Put(ctx context.Context, APIKey []byte, idempotencyKey string, commit *Commit) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	value, err := json.Marshal(commit)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(c.db.Put(commitKey(APIKey, idempotencyKey), value))
+}
@@ -0,0 +1,284 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/accesslog"
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/storage"
+)
+
+// Cost is the cost of requests to pointerdb
+type Cost struct {
+	Requests int64 `json:"requests"`
+	// Scanned is the number of items read from the database
+	Scanned int64 `json:"scanned"`
+	// Bytes is the size of the responses
+	Bytes    int64         `json:"bytes"`
+	Duration time.Duration `json:"duration"`
+	// Aborted is the number of requests aborted for exceeding a limit
+	Aborted int64 `json:"aborted"`
+}
+
+// add adds other to cost
+func (cost *Cost) add(other Cost) {
+	cost.Requests += other.Requests
+	cost.Scanned += other.Scanned
+	cost.Bytes += other.Bytes
+	cost.Duration += other.Duration
+	cost.Aborted += other.Aborted
+}
+
+// Costs accumulates the costs of the requests of every API key. The costs
+// of the keys without requests for ttl are forgotten, and at most maxKeys
+// keys are kept, forgetting the keys without requests for the longest.
+type Costs struct {
+	maxKeys int
+	ttl     time.Duration
+	now     func() time.Time
+
+	mu    sync.Mutex
+	byKey map[string]*keyCost
+}
+
+// keyCost is the cost of the requests of an API key
+type keyCost struct {
+	Cost
+	// last is when the key was last used
+	last time.Time
+}
+
+// NewCosts creates an empty Costs keeping the costs of at most maxKeys API
+// keys for ttl after their last request
+func NewCosts(maxKeys int, ttl time.Duration) *Costs {
+	return &Costs{maxKeys: maxKeys, ttl: ttl, now: time.Now, byKey: map[string]*keyCost{}}
+}
+
+// Add adds cost to the costs of the API key with id keyID
+func (costs *Costs) Add(keyID string, cost Cost) {
+	costs.mu.Lock()
+	defer costs.mu.Unlock()
+	now := costs.now()
+	total, ok := costs.byKey[keyID]
+	if !ok {
+		costs.evict(now)
+		total = &keyCost{}
+		costs.byKey[keyID] = total
+	}
+	total.add(cost)
+	total.last = now
+}
+
+// evict forgets the expired costs, and the costs of the least recently used
+// keys until there is room for another key. costs.mu must be held.
+func (costs *Costs) evict(now time.Time) {
+	for keyID, cost := range costs.byKey {
+		if now.Sub(cost.last) > costs.ttl {
+			delete(costs.byKey, keyID)
+		}
+	}
+	for len(costs.byKey) > 0 && len(costs.byKey) >= costs.maxKeys {
+		var victim string
+		for keyID, cost := range costs.byKey {
+			if victim == "" || cost.last.Before(costs.byKey[victim].last) {
+				victim = keyID
+			}
+		}
+		delete(costs.byKey, victim)
+	}
+}
+
+// ByKey returns the costs by API key id
+func (costs *Costs) ByKey() map[string]Cost {
+	costs.mu.Lock()
+	defer costs.mu.Unlock()
+	byKey := make(map[string]Cost, len(costs.byKey))
+	now := costs.now()
+	for keyID, cost := range costs.byKey {
+		if now.Sub(cost.last) <= costs.ttl {
+			byKey[keyID] = cost.Cost
+		}
+	}
+	return byKey
+}
+
+// ServeHTTP serves the costs by API key id as JSON
+func (costs *Costs) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(costs.ByKey())
+}
+
+// keyID identifies an API key in logs and metrics without revealing it
+func keyID(APIKey []byte) string {
+	hash := sha256.Sum256(APIKey)
+	return hex.EncodeToString(hash[:8])
+}
+
+// projectID identifies the project of an API key in logs
+func projectID(APIKey []byte) string {
+	return macaroon.ProjectID(APIKey)
+}
+
+// pathHash identifies a path in logs without revealing it
+func pathHash(path string) string {
+	if path == "" {
+		return ""
+	}
+	hash := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(hash[:8])
+}
+
+// request tracks the cost of a request
+type request struct {
+	method string
+	keyID  string
+	start  time.Time
+
+	// apiKey is authenticated, and path is only kept for the access log
+	apiKey []byte
+	path   string
+
+	cancel func()
+	// authenticated is whether the API key was authenticated, as only the
+	// costs of authenticated keys are accumulated
+	authenticated bool
+
+	scanned    int64
+	bytes      int64
+	maxScanned int64
+	maxBytes   int64
+	aborted    error
+}
+
+// begin starts tracking the cost of a request with the API key on path, and
+// returns the context of the request, which is canceled after the request
+// timeout. end must be called when the request is done.
+func (s *Server) begin(ctx context.Context, method string, APIKey []byte, path string) (context.Context, *request) {
+	r := &request{
+		method:     method,
+		keyID:      keyID(APIKey),
+		start:      time.Now(),
+		apiKey:     APIKey,
+		path:       path,
+		cancel:     func() {},
+		maxScanned: s.config.MaxScanned,
+		maxBytes:   s.config.MaxScannedBytes,
+	}
+	if s.config.RequestTimeout > 0 {
+		ctx, r.cancel = context.WithTimeout(ctx, s.config.RequestTimeout)
+	}
+	return ctx, r
+}
+
+// authenticate validates the API key of the request r for action
+func (s *Server) authenticate(ctx context.Context, r *request, action macaroon.Action) error {
+	if err := s.validateAuth(ctx, r.apiKey, action); err != nil {
+		return err
+	}
+	r.authenticated = true
+	return nil
+}
+
+// end accounts the cost of a request that returned resp and err
+func (s *Server) end(r *request, resp proto.Message, err error) {
+	r.cancel()
+
+	cost := Cost{Requests: 1, Scanned: r.scanned, Duration: time.Since(r.start)}
+	if resp != nil && err == nil {
+		cost.Bytes = int64(proto.Size(resp))
+	}
+	if r.aborted != nil {
+		cost.Aborted = 1
+		s.logger.Warn("aborted request", zap.String("method", r.method), zap.String("key", r.keyID),
+			zap.Int64("scanned", cost.Scanned), zap.Duration("duration", cost.Duration), zap.Error(r.aborted))
+		s.emitAborted(r)
+	} else {
+		s.logger.Debug("request cost", zap.String("method", r.method), zap.String("key", r.keyID),
+			zap.Int64("scanned", cost.Scanned), zap.Int64("bytes", cost.Bytes), zap.Duration("duration", cost.Duration))
+	}
+
+	mon.IntVal("request_scanned").Observe(cost.Scanned)
+	mon.IntVal("request_bytes").Observe(cost.Bytes)
+	mon.Counter("requests_aborted").Inc(cost.Aborted)
+	if s.costs != nil && r.authenticated {
+		s.costs.Add(r.keyID, cost)
+	}
+	if s.accessLog != nil {
+		s.accessLog.Log(&accesslog.Entry{
+			Time:      r.start,
+			Project:   projectID(r.apiKey),
+			Key:       r.keyID,
+			Operation: r.method,
+			PathHash:  pathHash(r.path),
+			Bytes:     cost.Bytes,
+			Latency:   cost.Duration,
+			Status:    status.Code(err).String(),
+		})
+	}
+}
+
+// read accounts reading an item of size bytes from the database
+func (r *request) read(size int) {
+	r.scanned++
+	r.bytes += int64(size)
+}
+
+// scan accounts reading an item of size bytes from the database, and
+// returns an error if the request exceeded a limit
+func (r *request) scan(ctx context.Context, size int) error {
+	r.read(size)
+	switch {
+	case r.aborted != nil:
+	case ctx.Err() != nil:
+		r.aborted = status.Errorf(codes.DeadlineExceeded, "request took too long after scanning %d items", r.scanned)
+	case r.maxScanned > 0 && r.scanned > r.maxScanned:
+		r.aborted = status.Errorf(codes.ResourceExhausted, "request scanned more than %d items", r.maxScanned)
+	case r.maxBytes > 0 && r.bytes > r.maxBytes:
+		r.aborted = status.Errorf(codes.ResourceExhausted, "request read more than %d bytes", r.maxBytes)
+	}
+	return r.aborted
+}
+
+// meteredStore accounts the items iterated over to a request, and stops
+// iterating once the request exceeded a limit
+type meteredStore struct {
+	storage.KeyValueStore
+	ctx context.Context
+	r   *request
+}
+
+// store returns db metered for the request with ctx
+func (r *request) store(ctx context.Context, db storage.KeyValueStore) storage.KeyValueStore {
+	return &meteredStore{KeyValueStore: db, ctx: ctx, r: r}
+}
+
+// Iterate iterates over the items of the store until the request exceeds a
+// limit, in which case it returns the error of the request
+func (store *meteredStore) Iterate(opts storage.IterateOptions, fn func(storage.Iterator) error) error {
+	err := store.KeyValueStore.Iterate(opts, func(it storage.Iterator) error {
+		return fn(storage.IteratorFunc(func(item *storage.ListItem) bool {
+			if !it.Next(item) {
+				return false
+			}
+			return store.r.scan(store.ctx, len(item.Key)+len(item.Value)) == nil
+		}))
+	})
+	if err != nil {
+		return err
+	}
+	return store.r.aborted
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/storage"
+)
+
+// GetObjectInfo describes the object at the path of req, its bucket followed
+// by its path in the bucket, and its segments, without their data
+func (s *Server) GetObjectInfo(ctx context.Context, req *pb.GetObjectInfoRequest) (resp *pb.GetObjectInfoResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+	s.logger.Debug("entering pointerdb get object info")
+	ctx, r := s.begin(ctx, "get_object_info", req.GetAPIKey(), req.GetPath())
+	defer func() { s.end(r, resp, err) }()
+
+	lastPath := "l/" + req.GetPath()
+	if err = s.authenticate(ctx, r, actionOn(macaroon.ActionRead, lastPath)); err != nil {
+		return nil, err
+	}
+
+	last, err := s.getSegment(ctx, r, lastPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// the last segment has the metadata of the stream
+	var info pb.MetaStreamInfo
+	if err := proto.Unmarshal(last.GetMetadata(), &info); err != nil {
+		s.logger.Error("err unmarshaling stream metadata", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	var meta objects.SerializableMeta
+	if err := proto.Unmarshal(info.GetMetadata(), &meta); err != nil {
+		s.logger.Error("err unmarshaling object metadata", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	resp = &pb.GetObjectInfoResponse{
+		CreationDate:   last.GetCreationDate(),
+		ExpirationDate: last.GetExpirationDate(),
+		ContentType:    meta.GetContentType(),
+	}
+	for key := range meta.GetUserDefined() {
+		resp.MetadataKeys = append(resp.MetadataKeys, key)
+	}
+	sort.Strings(resp.MetadataKeys)
+
+	for _, path := range streamSegmentPaths(req.GetPath(), &info) {
+		pointer, err := s.getSegment(ctx, r, path)
+		if err != nil {
+			return nil, err
+		}
+		resp.Segments = append(resp.Segments, segmentInfo(path, pointer))
+		resp.Size += pointer.GetSize()
+	}
+	resp.Segments = append(resp.Segments, segmentInfo(lastPath, last))
+	resp.Size += last.GetSize()
+
+	return resp, nil
+}
+
+// getSegment returns the pointer at path, read for r
+func (s *Server) getSegment(ctx context.Context, r *request, path string) (*pb.Pointer, error) {
+	pointerBytes, err := s.DB.Get([]byte(path))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		}
+		s.logger.Error("err getting pointer", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if err := r.scan(ctx, len(pointerBytes)); err != nil {
+		return nil, err
+	}
+
+	pointer, _, err := UnmarshalPointer(pointerBytes)
+	if err != nil {
+		s.logger.Error("err unmarshaling pointer", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return pointer, nil
+}
+
+// streamSegmentPaths returns the paths of the segments of the stream at
+// path before its last segment, in order. The segments of the parts of
+// multipart uploads come first.
+func streamSegmentPaths(path string, info *pb.MetaStreamInfo) (paths []string) {
+	for _, part := range info.GetParts() {
+		for i := int64(0); i < part.GetNumberOfSegments(); i++ {
+			paths = append(paths, fmt.Sprintf("u%s.p%d.s%d/%s", info.GetUploadId(), part.GetNumber(), i, path))
+		}
+	}
+	for i := int64(0); i < info.GetNumberOfSegments(); i++ {
+		paths = append(paths, fmt.Sprintf("s%d/%s", i, path))
+	}
+	return paths
+}
+
+// segmentInfo describes the segment at path
+func segmentInfo(path string, pointer *pb.Pointer) *pb.SegmentInfo {
+	info := &pb.SegmentInfo{
+		Path: path,
+		Type: pointer.GetType(),
+		Size: pointer.GetSize(),
+	}
+	if remote := pointer.GetRemote(); remote != nil {
+		info.Redundancy = remote.GetRedundancy()
+		info.Pieces = int32(len(remote.GetRemotePieces()))
+	}
+	return info
+}
@@ -0,0 +1,222 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/orders"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/storage"
+)
+
+// OrderLimits signs the order limits the uplink of the request needs to
+// upload or download the pieces of a segment
+func (s *Server) OrderLimits(ctx context.Context, req *pb.OrderLimitsRequest) (resp *pb.OrderLimitsResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+	s.logger.Debug("entering pointerdb order limits")
+	ctx, r := s.begin(ctx, "order_limits", req.GetAPIKey(), req.GetPath())
+	defer func() { s.end(r, resp, err) }()
+
+	// repair and audit traffic is paid by the satellite, so uplinks can't
+	// get order limits for it
+	if !orders.IsCustomer(req.GetAction()) {
+		return nil, status.Errorf(codes.InvalidArgument, "order limits for %s are not issued to uplinks", req.GetAction())
+	}
+	// uploads couldn't be committed and pointers not deleted anyway
+	if req.GetAction() != pb.PayerBandwidthAllocation_GET {
+		if err = s.readOnly.check(); err != nil {
+			return nil, err
+		}
+	}
+
+	op := macaroon.ActionRead
+	switch req.GetAction() {
+	case pb.PayerBandwidthAllocation_PUT:
+		op = macaroon.ActionWrite
+	case pb.PayerBandwidthAllocation_DELETE:
+		op = macaroon.ActionDelete
+	}
+	if err = s.authenticate(ctx, r, actionOn(op, req.GetPath())); err != nil {
+		return nil, err
+	}
+	if op == macaroon.ActionRead {
+		if err = s.checkBlocked(ctx, req.GetPath()); err != nil {
+			return nil, err
+		}
+	}
+	if s.signer == nil {
+		return nil, status.Errorf(codes.Unimplemented, "order limits are not issued")
+	}
+
+	uplink, err := provider.PeerIdentityFromContext(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, err.Error())
+	}
+
+	pieceID, nodeIDs, maxSize := req.GetPieceId(), req.GetNodeIds(), s.config.MaxPieceSize
+	// size is the logical size of the segment downloaded
+	var size int64
+	if req.GetAction() != pb.PayerBandwidthAllocation_PUT {
+		// only the pieces of existing pointers can be accessed
+		pointer, err := s.getPointer(req.GetPath())
+		if err != nil {
+			return nil, err
+		}
+		remote := pointer.GetRemote()
+		if remote == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "pointer at %s has no remote pieces", req.GetPath())
+		}
+
+		pieceID, nodeIDs, maxSize = remote.GetPieceId(), nil, audit.PieceSize(pointer)
+		size = pointer.GetSize()
+		if req.GetAction() == pb.PayerBandwidthAllocation_DELETE {
+			// deletes transfer no data
+			maxSize = 0
+		}
+		for _, piece := range remote.GetRemotePieces() {
+			nodeIDs = append(nodeIDs, piece.GetNodeId())
+		}
+	}
+	if pieceID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "piece id not specified")
+	}
+	if req.GetAction() == pb.PayerBandwidthAllocation_PUT {
+		if err = s.checkPlacement(ctx, req.GetAPIKey(), req.GetPath(), nodeIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	if req.GetAction() == pb.PayerBandwidthAllocation_GET {
+		// projects are charged the bytes of the segment they download, not
+		// the erasure shares of every node they get order limits for, as
+		// the uplink only uses as many as it needs to decode the segment
+		if err = s.allocateBandwidth(ctx, req.GetAPIKey(), size); err != nil {
+			return nil, err
+		}
+		s.recordBandwidth(ctx, req.GetAPIKey(), req.GetPath(), req.GetAction(), size)
+	}
+
+	addresses, err := s.addresses(ctx, nodeIDs)
+	if err != nil {
+		s.logger.Error("err looking up node addresses", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	expiration := time.Now().Add(s.config.OrderLimitExpiration).Unix()
+	resp = &pb.OrderLimitsResponse{}
+	for i, nodeID := range nodeIDs {
+		derived, err := client.PieceID(pieceID).Derive([]byte(nodeID))
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+
+		limit, err := s.signer.Sign(&pb.PayerBandwidthAllocation_Data{
+			Renter:             uplink.ID.Bytes(),
+			MaxSize:            maxSize,
+			ExpirationUnixSec:  expiration,
+			Action:             req.GetAction(),
+			PieceId:            string(derived),
+			StorageNodeId:      []byte(nodeID),
+			StorageNodeAddress: addresses[i],
+		})
+		if err != nil {
+			s.logger.Error("err signing order limit", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		resp.Limits = append(resp.Limits, limit)
+	}
+
+	return resp, nil
+}
+
+// allocateBandwidth allocates bytes of download bandwidth to the project of
+// APIKey, if bandwidth is accounted, and returns a ResourceExhausted error if
+// it goes beyond the policy of the project
+func (s *Server) allocateBandwidth(ctx context.Context, APIKey []byte, bytes int64) (err error) {
+	if s.bandwidth == nil {
+		return nil
+	}
+	project := projectID(APIKey)
+	alert, err := s.bandwidth.Allocate(ctx, project, bytes)
+	if ErrBandwidthExceeded.Has(err) {
+		s.emitBandwidthLimit(project, false)
+		return status.Errorf(codes.ResourceExhausted, err.Error())
+	}
+	if err != nil {
+		s.logger.Error("err allocating bandwidth", zap.Error(err))
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	if alert {
+		s.emitBandwidthLimit(project, true)
+	}
+	return nil
+}
+
+// recordBandwidth rolls up bytes of bandwidth used with order limits of
+// action by the bucket of the segment at path. The order limits are issued
+// anyway if that fails.
+func (s *Server) recordBandwidth(ctx context.Context, APIKey []byte, path string, action pb.PayerBandwidthAllocation_Action, bytes int64) {
+	if s.usage == nil {
+		return
+	}
+	key := accounting.Key{ProjectID: projectID(APIKey)}
+	// segment paths start with the segment
+	if parts := strings.SplitN(path, "/", 3); len(parts) > 1 {
+		key.Bucket = parts[1]
+	}
+	if err := s.usage.Add(ctx, accounting.BandwidthKind(action), key, time.Now(), bytes); err != nil {
+		s.logger.Error("err recording bandwidth", zap.Error(err))
+	}
+}
+
+// addresses returns the cached addresses of the nodes of nodeIDs, which the
+// uplink dials with the order limits, or empty addresses for the nodes which
+// aren't cached
+func (s *Server) addresses(ctx context.Context, nodeIDs []string) (addresses []string, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	addresses = make([]string, len(nodeIDs))
+	if s.nodes == nil || len(nodeIDs) == 0 {
+		return addresses, nil
+	}
+	nodes, err := s.nodes.GetAll(ctx, nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i, node := range nodes {
+		if i < len(addresses) {
+			addresses[i] = node.GetAddress().GetAddress()
+		}
+	}
+	return addresses, nil
+}
+
+func (s *Server) getPointer(path string) (*pb.Pointer, error) {
+	pointerBytes, err := s.DB.Get([]byte(path))
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			return nil, status.Errorf(codes.NotFound, err.Error())
+		}
+		s.logger.Error("err getting pointer", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+
+	pointer, _, err := UnmarshalPointer(pointerBytes)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	return pointer, nil
+}
@@ -14,6 +14,7 @@ import (
 
 	p "storj.io/storj/pkg/paths"
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
 	"storj.io/storj/pkg/provider"
 	"storj.io/storj/storage"
 )
@@ -24,6 +25,7 @@ var (
 
 // PointerDB creates a grpcClient
 type PointerDB struct {
+	conn       *grpc.ClientConn
 	grpcClient pb.PointerDBClient
 	APIKey     []byte
 }
@@ -41,11 +43,14 @@ type ListItem struct {
 // Client services offerred for the interface
 type Client interface {
 	Put(ctx context.Context, path p.Path, pointer *pb.Pointer) error
-	Get(ctx context.Context, path p.Path) (*pb.Pointer, error)
+	Get(ctx context.Context, path p.Path) (*pb.Pointer, []*pb.Node, error)
 	List(ctx context.Context, prefix, startAfter, endBefore p.Path,
 		recursive bool, limit int, metaFlags uint32) (
 		items []ListItem, more bool, err error)
 	Delete(ctx context.Context, path p.Path) error
+	OrderLimits(ctx context.Context, path p.Path, action pb.PayerBandwidthAllocation_Action,
+		pieceID client.PieceID, nodeIDs []string) ([]*pb.PayerBandwidthAllocation, error)
+	Batch(ctx context.Context, requests ...*pb.BatchRequestItem) ([]*pb.BatchResponseItem, error)
 }
 
 // NewClient initializes a new pointerdb client
@@ -54,13 +59,13 @@ func NewClient(identity *provider.FullIdentity, address string, APIKey []byte) (
 	if err != nil {
 		return nil, err
 	}
-	c, err := clientConnection(address, dialOpt)
-
+	conn, err := grpc.Dial(address, dialOpt)
 	if err != nil {
 		return nil, err
 	}
 	return &PointerDB{
-		grpcClient: c,
+		conn:       conn,
+		grpcClient: pb.NewPointerDBClient(conn),
 		APIKey:     APIKey,
 	}, nil
 }
@@ -68,14 +73,12 @@ func NewClient(identity *provider.FullIdentity, address string, APIKey []byte) (
 // a compiler trick to make sure *PointerDB implements Client
 var _ Client = (*PointerDB)(nil)
 
-// ClientConnection makes a server connection
-func clientConnection(serverAddr string, opts ...grpc.DialOption) (pb.PointerDBClient, error) {
-	conn, err := grpc.Dial(serverAddr, opts...)
-
-	if err != nil {
-		return nil, err
+// Close closes the connection to pointerdb
+func (pdb *PointerDB) Close() error {
+	if pdb.conn == nil {
+		return nil
 	}
-	return pb.NewPointerDBClient(conn), nil
+	return pdb.conn.Close()
 }
 
 // Put is the interface to make a PUT request, needs Pointer and APIKey
@@ -87,25 +90,43 @@ func (pdb *PointerDB) Put(ctx context.Context, path p.Path, pointer *pb.Pointer)
 	return err
 }
 
-// Get is the interface to make a GET request, needs PATH and APIKey
-func (pdb *PointerDB) Get(ctx context.Context, path p.Path) (pointer *pb.Pointer, err error) {
+// Get is the interface to make a GET request, needs PATH and APIKey. If the
+// satellite knows them, it also returns the addresses of the nodes storing
+// the pointer's remote pieces, in order. Unknown nodes are nil.
+func (pdb *PointerDB) Get(ctx context.Context, path p.Path) (pointer *pb.Pointer, nodes []*pb.Node, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	res, err := pdb.grpcClient.Get(ctx, &pb.GetRequest{Path: path.String(), APIKey: pdb.APIKey})
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
-			return nil, storage.ErrKeyNotFound.Wrap(err)
+			return nil, nil, storage.ErrKeyNotFound.Wrap(err)
 		}
-		return nil, Error.Wrap(err)
+		if isBlocked(err) {
+			return nil, nil, ErrBlocked.Wrap(err)
+		}
+		return nil, nil, Error.Wrap(err)
 	}
 
+	return UnmarshalGetResponse(res)
+}
+
+// UnmarshalGetResponse returns the pointer of a Get response, and the
+// addresses of the nodes storing its remote pieces. Unknown nodes are nil.
+func UnmarshalGetResponse(res *pb.GetResponse) (pointer *pb.Pointer, nodes []*pb.Node, err error) {
 	pointer = &pb.Pointer{}
 	err = proto.Unmarshal(res.GetPointer(), pointer)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return pointer, nil
+	for _, node := range res.GetNodes() {
+		if node.GetId() == "" {
+			node = nil
+		}
+		nodes = append(nodes, node)
+	}
+
+	return pointer, nodes, nil
 }
 
 // List is the interface to make a LIST request, needs StartingPathKey, Limit, and APIKey
@@ -148,3 +169,79 @@ func (pdb *PointerDB) Delete(ctx context.Context, path p.Path) (err error) {
 
 	return err
 }
+
+// OrderLimits is the interface to make an OrderLimits request, needs Path and
+// APIKey. It returns the order limits signed by the satellite for every node.
+// For PUT, the order limits are for the piece pieceID of the nodes nodeIDs.
+// Otherwise the satellite uses the pieces of the pointer at path, and
+// pieceID and nodeIDs are ignored.
+func (pdb *PointerDB) OrderLimits(ctx context.Context, path p.Path, action pb.PayerBandwidthAllocation_Action,
+	pieceID client.PieceID, nodeIDs []string) (limits []*pb.PayerBandwidthAllocation, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	res, err := pdb.grpcClient.OrderLimits(ctx, &pb.OrderLimitsRequest{
+		Path:    path.String(),
+		Action:  action,
+		PieceId: string(pieceID),
+		NodeIds: nodeIDs,
+		APIKey:  pdb.APIKey,
+	})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, storage.ErrKeyNotFound.Wrap(err)
+		}
+		if isBlocked(err) {
+			return nil, ErrBlocked.Wrap(err)
+		}
+		return nil, Error.Wrap(err)
+	}
+
+	return res.GetLimits(), nil
+}
+
+// Batch sends requests to be executed in order in one round trip, with the
+// API key of the client, and returns their responses. If a request fails,
+// the requests before it took effect and the ones after it didn't.
+func (pdb *PointerDB) Batch(ctx context.Context, requests ...*pb.BatchRequestItem) (responses []*pb.BatchResponseItem, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	res, err := pdb.grpcClient.Batch(ctx, &pb.BatchRequest{Requests: requests, APIKey: pdb.APIKey})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, storage.ErrKeyNotFound.Wrap(err)
+		}
+		if isBlocked(err) {
+			return nil, ErrBlocked.Wrap(err)
+		}
+		return nil, Error.Wrap(err)
+	}
+	if len(res.GetResponses()) != len(requests) {
+		return nil, Error.New("got %d responses for %d requests", len(res.GetResponses()), len(requests))
+	}
+
+	return res.GetResponses(), nil
+}
+
+// Revoke revokes the API key of the client, and with it every key
+// restricted from it
+func (pdb *PointerDB) Revoke(ctx context.Context) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	_, err = pdb.grpcClient.Revoke(ctx, &pb.RevokeRequest{APIKey: pdb.APIKey})
+	return Error.Wrap(err)
+}
+
+// GetObjectInfo describes the object at path, its bucket followed by its
+// path in the bucket, and its segments
+func (pdb *PointerDB) GetObjectInfo(ctx context.Context, path p.Path) (info *pb.GetObjectInfoResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	info, err = pdb.grpcClient.GetObjectInfo(ctx, &pb.GetObjectInfoRequest{Path: path.String(), APIKey: pdb.APIKey})
+	if err != nil {
+		if status.Code(err) == codes.NotFound {
+			return nil, storage.ErrKeyNotFound.Wrap(err)
+		}
+		return nil, Error.Wrap(err)
+	}
+	return info, nil
+}
@@ -15,10 +15,13 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	p "storj.io/storj/pkg/paths"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/storage"
 )
 
 const (
@@ -145,13 +148,14 @@ func TestGet(t *testing.T) {
 
 		gc.EXPECT().Get(gomock.Any(), &getRequest).Return(&getResponse, tt.err)
 
-		pointer, err := pdb.Get(ctx, tt.path)
+		pointer, nodes, err := pdb.Get(ctx, tt.path)
 
 		if err != nil {
 			assert.True(t, strings.Contains(err.Error(), tt.errString), errTag)
 			assert.Nil(t, pointer)
 		} else {
 			assert.NotNil(t, pointer)
+			assert.Nil(t, nodes)
 			assert.NoError(t, err, errTag)
 		}
 	}
@@ -279,3 +283,72 @@ func TestDelete(t *testing.T) {
 		}
 	}
 }
+
+func TestOrderLimits(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockPointerDBClient(ctrl)
+	pdb := PointerDB{grpcClient: gc, APIKey: []byte("abc123")}
+
+	limits := []*pb.PayerBandwidthAllocation{{Signature: []byte("signature")}}
+	request := pb.OrderLimitsRequest{
+		Path:    "file1/file2",
+		Action:  pb.PayerBandwidthAllocation_PUT,
+		PieceId: "piece",
+		NodeIds: []string{"node1"},
+		APIKey:  []byte("abc123"),
+	}
+	gc.EXPECT().OrderLimits(gomock.Any(), &request).Return(&pb.OrderLimitsResponse{Limits: limits}, nil)
+
+	got, err := pdb.OrderLimits(ctx, p.New("file1/file2"), pb.PayerBandwidthAllocation_PUT, "piece", []string{"node1"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, limits, got)
+	}
+
+	gc.EXPECT().OrderLimits(gomock.Any(), gomock.Any()).Return(nil, status.Errorf(codes.NotFound, "not found"))
+	_, err = pdb.OrderLimits(ctx, p.New("file1/file3"), pb.PayerBandwidthAllocation_GET, "", nil)
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+}
+
+func TestRevoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockPointerDBClient(ctrl)
+	pdb := PointerDB{grpcClient: gc, APIKey: []byte("abc123")}
+
+	gc.EXPECT().Revoke(gomock.Any(), &pb.RevokeRequest{APIKey: []byte("abc123")}).Return(&pb.RevokeResponse{}, nil)
+	assert.NoError(t, pdb.Revoke(ctx))
+
+	gc.EXPECT().Revoke(gomock.Any(), gomock.Any()).Return(nil, status.Errorf(codes.FailedPrecondition, "not supported"))
+	assert.Error(t, pdb.Revoke(ctx))
+}
+
+func TestBatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	gc := NewMockPointerDBClient(ctrl)
+	pdb := PointerDB{grpcClient: gc, APIKey: []byte("abc123")}
+
+	requests := []*pb.BatchRequestItem{
+		{Put: &pb.PutRequest{Path: "a/b/c", Pointer: &pb.Pointer{}}},
+		{Get: &pb.GetRequest{Path: "a/b/c"}},
+	}
+	responses := []*pb.BatchResponseItem{{Put: &pb.PutResponse{}}, {Get: &pb.GetResponse{}}}
+
+	gc.EXPECT().Batch(gomock.Any(), &pb.BatchRequest{Requests: requests, APIKey: []byte("abc123")}).
+		Return(&pb.BatchResponse{Responses: responses}, nil)
+	got, err := pdb.Batch(ctx, requests...)
+	assert.NoError(t, err)
+	assert.Equal(t, responses, got)
+
+	gc.EXPECT().Batch(gomock.Any(), gomock.Any()).Return(&pb.BatchResponse{Responses: responses[:1]}, nil)
+	_, err = pdb.Batch(ctx, requests...)
+	assert.Error(t, err)
+
+	gc.EXPECT().Batch(gomock.Any(), gomock.Any()).Return(nil, status.Errorf(codes.NotFound, "batch request 1: not found"))
+	_, err = pdb.Batch(ctx, requests...)
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+}
@@ -4,8 +4,26 @@
 package pdbclient
 
 import (
+	"strings"
+
 	"github.com/zeebo/errs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// Error is the pdbclient error class
+	Error = errs.Class("pointerdb client error")
+
+	// ErrBlocked is returned for the objects blocked by the satellite, e.g.
+	// after an abuse report or a takedown notice. Their data isn't deleted,
+	// but can't be downloaded.
+	ErrBlocked = errs.Class("object blocked")
 )
 
-// Error is the pdbclient error class
-var Error = errs.Class("pointerdb client error")
+// isBlocked returns whether err is the status of a request for a blocked
+// object
+func isBlocked(err error) bool {
+	return status.Code(err) == codes.FailedPrecondition &&
+		strings.HasPrefix(status.Convert(err).Message(), "object blocked")
+}
@@ -11,6 +11,7 @@ import (
 	gomock "github.com/golang/mock/gomock"
 	paths "storj.io/storj/pkg/paths"
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
 	"storj.io/storj/pkg/pointerdb/pdbclient"
 )
 
@@ -37,6 +38,24 @@ func (m *MockClient) EXPECT() *MockClientMockRecorder {
 	return m.recorder
 }
 
+// Batch mocks base method
+func (m *MockClient) Batch(arg0 context.Context, arg1 ...*pb.BatchRequestItem) ([]*pb.BatchResponseItem, error) {
+	varargs := []interface{}{arg0}
+	for _, a := range arg1 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Batch", varargs...)
+	ret0, _ := ret[0].([]*pb.BatchResponseItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Batch indicates an expected call of Batch
+func (mr *MockClientMockRecorder) Batch(arg0 interface{}, arg1 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0}, arg1...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*MockClient)(nil).Batch), varargs...)
+}
+
 // Delete mocks base method
 func (m *MockClient) Delete(arg0 context.Context, arg1 paths.Path) error {
 	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
@@ -50,11 +69,12 @@ func (mr *MockClientMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
 }
 
 // Get mocks base method
-func (m *MockClient) Get(arg0 context.Context, arg1 paths.Path) (*pb.Pointer, error) {
+func (m *MockClient) Get(arg0 context.Context, arg1 paths.Path) (*pb.Pointer, []*pb.Node, error) {
 	ret := m.ctrl.Call(m, "Get", arg0, arg1)
 	ret0, _ := ret[0].(*pb.Pointer)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret1, _ := ret[1].([]*pb.Node)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
 }
 
 // Get indicates an expected call of Get
@@ -76,6 +96,19 @@ func (mr *MockClientMockRecorder) List(arg0, arg1, arg2, arg3, arg4, arg5, arg6
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockClient)(nil).List), arg0, arg1, arg2, arg3, arg4, arg5, arg6)
 }
 
+// OrderLimits mocks base method
+func (m *MockClient) OrderLimits(arg0 context.Context, arg1 paths.Path, arg2 pb.PayerBandwidthAllocation_Action, arg3 client.PieceID, arg4 []string) ([]*pb.PayerBandwidthAllocation, error) {
+	ret := m.ctrl.Call(m, "OrderLimits", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]*pb.PayerBandwidthAllocation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrderLimits indicates an expected call of OrderLimits
+func (mr *MockClientMockRecorder) OrderLimits(arg0, arg1, arg2, arg3, arg4 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderLimits", reflect.TypeOf((*MockClient)(nil).OrderLimits), arg0, arg1, arg2, arg3, arg4)
+}
+
 // Put mocks base method
 func (m *MockClient) Put(arg0 context.Context, arg1 paths.Path, arg2 *pb.Pointer) error {
 	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2)
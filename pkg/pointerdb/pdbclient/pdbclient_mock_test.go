@@ -39,6 +39,24 @@ func (m *MockPointerDBClient) EXPECT() *MockPointerDBClientMockRecorder {
 	return m.recorder
 }
 
+// Batch mocks base method
+func (m *MockPointerDBClient) Batch(arg0 context.Context, arg1 *pb.BatchRequest, arg2 ...grpc.CallOption) (*pb.BatchResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Batch", varargs...)
+	ret0, _ := ret[0].(*pb.BatchResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Batch indicates an expected call of Batch
+func (mr *MockPointerDBClientMockRecorder) Batch(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Batch", reflect.TypeOf((*MockPointerDBClient)(nil).Batch), varargs...)
+}
+
 // Delete mocks base method
 func (m *MockPointerDBClient) Delete(arg0 context.Context, arg1 *pb.DeleteRequest, arg2 ...grpc.CallOption) (*pb.DeleteResponse, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -75,6 +93,24 @@ func (mr *MockPointerDBClientMockRecorder) Get(arg0, arg1 interface{}, arg2 ...i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPointerDBClient)(nil).Get), varargs...)
 }
 
+// GetObjectInfo mocks base method
+func (m *MockPointerDBClient) GetObjectInfo(arg0 context.Context, arg1 *pb.GetObjectInfoRequest, arg2 ...grpc.CallOption) (*pb.GetObjectInfoResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetObjectInfo", varargs...)
+	ret0, _ := ret[0].(*pb.GetObjectInfoResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObjectInfo indicates an expected call of GetObjectInfo
+func (mr *MockPointerDBClientMockRecorder) GetObjectInfo(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObjectInfo", reflect.TypeOf((*MockPointerDBClient)(nil).GetObjectInfo), varargs...)
+}
+
 // List mocks base method
 func (m *MockPointerDBClient) List(arg0 context.Context, arg1 *pb.ListRequest, arg2 ...grpc.CallOption) (*pb.ListResponse, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -103,6 +139,42 @@ func (mr *MockPointerDBClientMockRecorder) ReverseList(arg0, arg1 interface{}, a
 	return mr.List(arg0, arg1, arg2...)
 }
 
+// OrderLimits mocks base method
+func (m *MockPointerDBClient) OrderLimits(arg0 context.Context, arg1 *pb.OrderLimitsRequest, arg2 ...grpc.CallOption) (*pb.OrderLimitsResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "OrderLimits", varargs...)
+	ret0, _ := ret[0].(*pb.OrderLimitsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OrderLimits indicates an expected call of OrderLimits
+func (mr *MockPointerDBClientMockRecorder) OrderLimits(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OrderLimits", reflect.TypeOf((*MockPointerDBClient)(nil).OrderLimits), varargs...)
+}
+
+// Revoke mocks base method
+func (m *MockPointerDBClient) Revoke(arg0 context.Context, arg1 *pb.RevokeRequest, arg2 ...grpc.CallOption) (*pb.RevokeResponse, error) {
+	varargs := []interface{}{arg0, arg1}
+	for _, a := range arg2 {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Revoke", varargs...)
+	ret0, _ := ret[0].(*pb.RevokeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoke indicates an expected call of Revoke
+func (mr *MockPointerDBClientMockRecorder) Revoke(arg0, arg1 interface{}, arg2 ...interface{}) *gomock.Call {
+	varargs := append([]interface{}{arg0, arg1}, arg2...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockPointerDBClient)(nil).Revoke), varargs...)
+}
+
 // Put mocks base method
 func (m *MockPointerDBClient) Put(arg0 context.Context, arg1 *pb.PutRequest, arg2 ...grpc.CallOption) (*pb.PutResponse, error) {
 	varargs := []interface{}{arg0, arg1}
@@ -4,18 +4,30 @@
 package pointerdb
 
 import (
+	"bytes"
 	"context"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
+	base58 "github.com/jbenet/go-base58"
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
+	"storj.io/storj/pkg/accesslog"
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/analytics"
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/orders"
+	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/pointerdb/auth"
+	"storj.io/storj/pkg/storage/buckets"
 	"storj.io/storj/pkg/storage/meta"
 	"storj.io/storj/storage"
 )
@@ -30,21 +42,205 @@ type Server struct {
 	DB     storage.KeyValueStore
 	logger *zap.Logger
 	config Config
+	nodes  NodeCache
+	signer *orders.Signer
+	// costs are the costs of the requests by API key, if accounted
+	costs *Costs
+
+	// apiKeySecret is the root secret of macaroon API keys
+	apiKeySecret []byte
+	// revocations are the revoked macaroon API keys, if any
+	revocations *Revocations
+	// commits are the commits by idempotency key. If nil, idempotency keys
+	// are ignored.
+	commits *Commits
+
+	// accessLog samples the requests for abuse investigation and support,
+	// if enabled
+	accessLog *accesslog.Log
+
+	// blocks are the objects blocked from downloads, if any
+	blocks *Blocks
+
+	// bandwidth accounts the download bandwidth of projects and enforces
+	// their policies, if enabled
+	bandwidth *Bandwidth
+
+	// analytics emits the events of the requests, if configured
+	analytics *analytics.Events
+	// attributions attributes the usage of new buckets to the partners
+	// they were created with, if set
+	attributions Attributor
+	// usage rolls up the download bandwidth of buckets, if set
+	usage UsageRecorder
+
+	// placements constrain the nodes the segments of buckets are uploaded
+	// to, if any
+	placements *overlay.Placements
+
+	// readOnly rejects the writes and deletions when enabled, if set
+	readOnly *ReadOnly
+	// suites rejects new segments stored with disabled suites, if set
+	suites *Suites
+
+	// replica is a read replica of DB for reads that tolerate stale
+	// results. If nil, DB is used.
+	replica storage.KeyValueStore
+
+	// writeMu serializes the writes of Put with writing back migrated
+	// pointers, so a migrated pointer never replaces a newer one
+	writeMu sync.Mutex
+}
+
+// Attributor attributes the usage of buckets to partners
+type Attributor interface {
+	Attribute(ctx context.Context, projectID, bucket, partnerID string) error
+}
+
+// UsageRecorder rolls up usage samples
+type UsageRecorder interface {
+	Add(ctx context.Context, kind accounting.Kind, key accounting.Key, at time.Time, value int64) error
+}
+
+// NodeCache looks up the addresses of nodes
+type NodeCache interface {
+	GetAll(ctx context.Context, nodeIDs []string) ([]*pb.Node, error)
 }
 
 // NewServer creates instance of Server
 func NewServer(db storage.KeyValueStore, logger *zap.Logger, c Config) *Server {
 	return &Server{
-		DB:     db,
-		logger: logger,
-		config: c,
+		DB:           db,
+		logger:       logger,
+		config:       c,
+		costs:        NewCosts(c.CostsMaxKeys, c.CostsTTL),
+		apiKeySecret: base58.Decode(c.APIKeySecret),
+	}
+}
+
+// APIKeySecret returns the root secret of macaroon API keys, empty if only
+// the static API key is accepted
+func (s *Server) APIKeySecret() []byte {
+	return s.apiKeySecret
+}
+
+// Revocations returns the revoked macaroon API keys, nil if macaroon API
+// keys aren't accepted
+func (s *Server) Revocations() *Revocations {
+	return s.revocations
+}
+
+// Replica returns the read replica of the pointer database, for listings
+// and reports that tolerate missing the latest writes. Reads that must see
+// them, like the metainfo loop garbage collection and audits rely on, and
+// all writes, use DB.
+func (s *Server) Replica() storage.KeyValueStore {
+	if s.replica != nil {
+		return s.replica
+	}
+	return s.DB
+}
+
+func (s *Server) validateAuth(ctx context.Context, APIKey []byte, action macaroon.Action) error {
+	if auth.ValidateAPIKey(string(APIKey)) {
+		return nil
+	}
+	if secret := s.apiKeySecret; len(secret) > 0 {
+		key, err := macaroon.ParseAPIKey(string(APIKey))
+		if err == nil {
+			err = key.Check(secret, action)
+		}
+		if err != nil {
+			s.logger.Error("unauthorized request: ", zap.Error(err))
+			return status.Errorf(codes.PermissionDenied, err.Error())
+		}
+		return s.checkRevoked(ctx, key)
+	}
+	s.logger.Error("unauthorized request: ", zap.Error(status.Errorf(codes.Unauthenticated, "Invalid API credential")))
+	return status.Errorf(codes.Unauthenticated, "Invalid API credential")
+}
+
+// checkRevoked checks that neither key nor a key it was restricted from was
+// revoked
+func (s *Server) checkRevoked(ctx context.Context, key *macaroon.APIKey) error {
+	if s.revocations == nil {
+		return nil
+	}
+	revoked, err := s.revocations.IsRevoked(ctx, key.Tails(s.apiKeySecret))
+	if err != nil {
+		s.logger.Error("err checking revocations", zap.Error(err))
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	if revoked {
+		s.logger.Error("unauthorized request: ", zap.Error(macaroon.ErrUnauthorized.New("revoked API key")))
+		return status.Errorf(codes.PermissionDenied, "API key revoked")
+	}
+	return nil
+}
+
+// actionOn returns the action of a request of type op for a segment path,
+// which starts with the segment index followed by the bucket
+func actionOn(op macaroon.ActionType, path string) macaroon.Action {
+	action := macaroon.Action{Op: op, Time: time.Now()}
+	parts := strings.SplitN(path, "/", 3)
+	if len(parts) > 1 {
+		action.Bucket = []byte(parts[1])
+	}
+	if len(parts) > 2 {
+		action.Path = []byte(parts[2])
+	}
+	return action
+}
+
+// checkBlocked fails the downloads of the segment at path if its object is
+// blocked
+func (s *Server) checkBlocked(ctx context.Context, path string) error {
+	if s.blocks == nil {
+		return nil
+	}
+	block, err := s.blocks.Blocked(ctx, path)
+	if err != nil {
+		s.logger.Error("err getting block", zap.Error(err))
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	if block != nil {
+		mon.Counter("blocked_downloads").Inc(1)
+		return status.Errorf(codes.FailedPrecondition, "object blocked: %s", block.Reason)
 	}
+	return nil
 }
 
-func (s *Server) validateAuth(APIKey []byte) error {
-	if !auth.ValidateAPIKey(string(APIKey)) {
-		s.logger.Error("unauthorized request: ", zap.Error(status.Errorf(codes.Unauthenticated, "Invalid API credential")))
-		return status.Errorf(codes.Unauthenticated, "Invalid API credential")
+// checkPlacement checks that the placement of the bucket of the segment at
+// path, if any, allows uploads to the nodes of nodeIDs
+func (s *Server) checkPlacement(ctx context.Context, apiKey []byte, path string, nodeIDs []string) error {
+	if s.placements == nil {
+		return nil
+	}
+	var bucket string
+	// segment paths start with the segment
+	if parts := strings.SplitN(path, "/", 3); len(parts) > 1 {
+		bucket = parts[1]
+	}
+	placement, err := s.placements.Lookup(ctx, apiKey, bucket)
+	if err != nil {
+		s.logger.Error("err getting placement", zap.Error(err))
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	if placement == nil || len(nodeIDs) == 0 {
+		return nil
+	}
+	if s.nodes == nil {
+		return status.Errorf(codes.FailedPrecondition, "placement %q can't be checked without the overlay", placement.Name)
+	}
+	nodes, err := s.nodes.GetAll(ctx, nodeIDs)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	for i, node := range nodes {
+		if node == nil || !s.placements.Allows(placement, node) {
+			mon.Counter("placement_violations").Inc(1)
+			return status.Errorf(codes.FailedPrecondition, "node %s isn't in placement %q", nodeIDs[i], placement.Name)
+		}
 	}
 	return nil
 }
@@ -66,47 +262,154 @@ func (s *Server) validateSegment(req *pb.PutRequest) error {
 	return nil
 }
 
+// checkRedundancy checks that a remote segment at path is stored with the
+// default redundancy scheme of its bucket, if the bucket has one
+func (s *Server) checkRedundancy(path string, pointer *pb.Pointer) error {
+	parts := strings.SplitN(path, "/", 3)
+	if pointer.GetRemote() == nil || len(parts) < 3 {
+		return nil
+	}
+	value, err := s.DB.Get(storage.Key("l/" + parts[1]))
+	if storage.ErrKeyNotFound.Has(err) {
+		return nil
+	}
+	if err != nil {
+		s.logger.Error("err getting bucket", zap.Error(err))
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	bucket, _, err := UnmarshalPointer(value)
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	defaults, err := buckets.ParseDefaults(bucket.GetMetadata())
+	if err != nil {
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	if rs := defaults.Redundancy; rs != nil && !proto.Equal(rs, pointer.GetRemote().GetRedundancy()) {
+		return status.Errorf(codes.InvalidArgument, "segments of bucket %q must be stored with its redundancy scheme %v", parts[1], rs)
+	}
+	return nil
+}
+
 // Put formats and hands off a key/value (path/pointer) to be saved to boltdb
 func (s *Server) Put(ctx context.Context, req *pb.PutRequest) (resp *pb.PutResponse, err error) {
 	defer mon.Task()(&ctx)(&err)
 	s.logger.Debug("entering pointerdb put")
+	ctx, r := s.begin(ctx, "put", req.GetAPIKey(), req.GetPath())
+	defer func() { s.end(r, resp, err) }()
+
+	if err = s.readOnly.check(); err != nil {
+		return nil, err
+	}
 
 	err = s.validateSegment(req)
 	if err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, err.Error())
 	}
 
-	if err = s.validateAuth(req.GetAPIKey()); err != nil {
+	if err = s.suites.check(req.GetPointer().GetSuite()); err != nil {
+		return nil, err
+	}
+
+	if err = s.authenticate(ctx, r, actionOn(macaroon.ActionWrite, req.GetPath())); err != nil {
+		return nil, err
+	}
+
+	if err = s.checkRedundancy(req.GetPath(), req.GetPointer()); err != nil {
 		return nil, err
 	}
 
+	// the uplink may have uploaded the pieces with order limits it got
+	// before the placement of the bucket changed
+	var nodeIDs []string
+	for _, piece := range req.GetPointer().GetRemote().GetRemotePieces() {
+		nodeIDs = append(nodeIDs, piece.GetNodeId())
+	}
+	if len(nodeIDs) > 0 {
+		if err = s.checkPlacement(ctx, req.GetAPIKey(), req.GetPath(), nodeIDs); err != nil {
+			return nil, err
+		}
+	}
+
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	idempotent := s.commits != nil && req.GetIdempotencyKey() != ""
+	if idempotent {
+		commit, err := s.commits.Get(ctx, req.GetAPIKey(), req.GetIdempotencyKey())
+		if err != nil {
+			s.logger.Error("err getting commit", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		if commit != nil {
+			if commit.Path != req.GetPath() {
+				return nil, status.Errorf(codes.FailedPrecondition, "idempotency key was used to commit another path")
+			}
+			// the commit is retried, the pointer is already stored
+			creationDate, err := ptypes.TimestampProto(commit.CreationDate)
+			if err != nil {
+				return nil, status.Errorf(codes.Internal, err.Error())
+			}
+			return &pb.PutResponse{CreationDate: creationDate}, nil
+		}
+	}
+
 	// Update the pointer with the creation date
-	req.GetPointer().CreationDate = ptypes.TimestampNow()
+	now := time.Now()
+	req.GetPointer().CreationDate, err = ptypes.TimestampProto(now)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	// the buckets of a project are found by the project that created them
+	// once it is deleted
+	if isBucket(req.GetPath()) {
+		req.GetPointer().ProjectId = projectID(req.GetAPIKey())
+	}
 
-	pointerBytes, err := proto.Marshal(req.GetPointer())
+	pointerBytes, err := MarshalPointer(req.GetPointer())
 	if err != nil {
 		s.logger.Error("err marshaling pointer", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 
+	createdBucket := s.createsBucket(req.GetPath())
+
 	// TODO(kaloyan): make sure that we know we are overwriting the pointer!
 	// In such case we should delete the pieces of the old segment if it was
 	// a remote one.
-	if err = s.DB.Put([]byte(req.GetPath()), pointerBytes); err != nil {
+	err = s.DB.Put([]byte(req.GetPath()), pointerBytes)
+	if err != nil {
 		s.logger.Error("err putting pointer", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
 	s.logger.Debug("put to the db: " + req.GetPath())
+	s.emitPut(req.GetAPIKey(), req.GetPath(), createdBucket)
+	if createdBucket {
+		s.attribute(ctx, req.GetAPIKey(), req.GetPath(), req.GetPointer())
+	}
+
+	if idempotent {
+		err = s.commits.Put(ctx, req.GetAPIKey(), req.GetIdempotencyKey(), &Commit{Path: req.GetPath(), CreationDate: now})
+		if err != nil {
+			s.logger.Error("err putting commit", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+	}
 
-	return &pb.PutResponse{}, nil
+	return &pb.PutResponse{CreationDate: req.GetPointer().GetCreationDate()}, nil
 }
 
 // Get formats and hands off a file path to get from boltdb
 func (s *Server) Get(ctx context.Context, req *pb.GetRequest) (resp *pb.GetResponse, err error) {
 	defer mon.Task()(&ctx)(&err)
 	s.logger.Debug("entering pointerdb get")
+	ctx, r := s.begin(ctx, "get", req.GetAPIKey(), req.GetPath())
+	defer func() { s.end(r, resp, err) }()
 
-	if err = s.validateAuth(req.GetAPIKey()); err != nil {
+	if err = s.authenticate(ctx, r, actionOn(macaroon.ActionRead, req.GetPath())); err != nil {
+		return nil, err
+	}
+	if err = s.checkBlocked(ctx, req.GetPath()); err != nil {
 		return nil, err
 	}
 
@@ -118,18 +421,95 @@ func (s *Server) Get(ctx context.Context, req *pb.GetRequest) (resp *pb.GetRespo
 		s.logger.Error("err getting pointer", zap.Error(err))
 		return nil, status.Errorf(codes.Internal, err.Error())
 	}
+	r.read(len(pointerBytes))
+
+	pointer, migrated, err := UnmarshalPointer(pointerBytes)
+	if err != nil {
+		s.logger.Error("err unmarshaling pointer", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	if migrated {
+		stored := pointerBytes
+		pointerBytes, err = MarshalPointer(pointer)
+		if err != nil {
+			s.logger.Error("err marshaling pointer", zap.Error(err))
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		if s.config.LazyMigration && s.readOnly.check() == nil {
+			s.writeBack([]byte(req.GetPath()), stored, pointerBytes)
+		}
+	}
 
-	return &pb.GetResponse{
+	resp = &pb.GetResponse{
 		Pointer: pointerBytes,
-	}, nil
+	}
+
+	if s.nodes != nil && s.config.LookupNodes {
+		resp.Nodes, err = s.lookupNodes(ctx, pointer)
+		if err != nil {
+			// the uplink can still look the nodes up itself
+			s.logger.Warn("err looking up nodes", zap.Error(err))
+		}
+	}
+
+	return resp, nil
+}
+
+// writeBack replaces the pointer stored at path in an older format with the
+// migrated pointer, unless it was changed since it was read. Failing to write
+// it back is harmless, as it is migrated again on the next read.
+func (s *Server) writeBack(path storage.Key, stored, migrated []byte) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	current, err := s.DB.Get(path)
+	if err == nil && bytes.Equal(current, stored) {
+		err = s.DB.Put(path, migrated)
+	}
+	if err != nil && !storage.ErrKeyNotFound.Has(err) {
+		s.logger.Warn("err writing back migrated pointer", zap.Error(err))
+	}
+}
+
+// lookupNodes returns the cached addresses of the nodes storing the pieces
+// of a remote pointer, so that uplinks don't have to look them up before
+// dialing the nodes
+func (s *Server) lookupNodes(ctx context.Context, pointer *pb.Pointer) (nodes []*pb.Node, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	pieces := pointer.GetRemote().GetRemotePieces()
+	if len(pieces) == 0 {
+		return nil, nil
+	}
+
+	var nodeIDs []string
+	for _, piece := range pieces {
+		nodeIDs = append(nodeIDs, piece.GetNodeId())
+	}
+
+	cached, err := s.nodes.GetAll(ctx, nodeIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	nodes = make([]*pb.Node, len(cached))
+	for i, node := range cached {
+		if node == nil {
+			node = &pb.Node{}
+		}
+		nodes[i] = node
+	}
+	return nodes, nil
 }
 
 // List returns all Path keys in the Pointers bucket
 func (s *Server) List(ctx context.Context, req *pb.ListRequest) (resp *pb.ListResponse, err error) {
 	defer mon.Task()(&ctx)(&err)
 	s.logger.Debug("entering pointerdb list")
+	ctx, r := s.begin(ctx, "list", req.GetAPIKey(), req.GetPrefix())
+	defer func() { s.end(r, resp, err) }()
 
-	if err = s.validateAuth(req.APIKey); err != nil {
+	if err = s.authenticate(ctx, r, actionOn(macaroon.ActionList, req.Prefix)); err != nil {
 		return nil, err
 	}
 
@@ -141,15 +521,30 @@ func (s *Server) List(ctx context.Context, req *pb.ListRequest) (resp *pb.ListRe
 		}
 	}
 
-	rawItems, more, err := storage.ListV2(s.DB, storage.ListOptions{
+	opts := storage.ListOptions{
 		Prefix:       prefix, //storage.Key(req.Prefix),
 		StartAfter:   storage.Key(req.StartAfter),
 		EndBefore:    storage.Key(req.EndBefore),
 		Recursive:    req.Recursive,
 		Limit:        int(req.Limit),
 		IncludeValue: req.MetaFlags != meta.None,
-	})
+	}
+	if req.Cursor != "" {
+		if req.StartAfter != "" || req.EndBefore != "" {
+			return nil, status.Errorf(codes.InvalidArgument, "cursor cannot be combined with start-after or end-before")
+		}
+		if opts, err = storage.ApplyCursor(opts, req.Cursor); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, err.Error())
+		}
+	}
+
+	// listings tolerate stale results, uplinks get the latest version of a
+	// pointer with Get
+	rawItems, more, err := storage.ListV2(r.store(ctx, s.Replica()), opts)
 	if err != nil {
+		if r.aborted != nil {
+			return nil, r.aborted
+		}
 		return nil, status.Errorf(codes.Internal, "ListV2: %v", err)
 	}
 
@@ -158,7 +553,7 @@ func (s *Server) List(ctx context.Context, req *pb.ListRequest) (resp *pb.ListRe
 		items = append(items, s.createListItem(rawItem, req.MetaFlags))
 	}
 
-	return &pb.ListResponse{Items: items, More: more}, nil
+	return &pb.ListResponse{Items: items, More: more, Cursor: storage.NextCursor(opts, rawItems, more)}, nil
 }
 
 // createListItem creates a new list item with the given path. It also adds
@@ -186,8 +581,7 @@ func (s *Server) setMetadata(item *pb.ListResponse_Item, data []byte, metaFlags
 		return nil
 	}
 
-	pr := &pb.Pointer{}
-	err = proto.Unmarshal(data, pr)
+	pr, _, err := UnmarshalPointer(data)
 	if err != nil {
 		return err
 	}
@@ -215,8 +609,14 @@ func (s *Server) setMetadata(item *pb.ListResponse_Item, data []byte, metaFlags
 func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (resp *pb.DeleteResponse, err error) {
 	defer mon.Task()(&ctx)(&err)
 	s.logger.Debug("entering pointerdb delete")
+	ctx, r := s.begin(ctx, "delete", req.GetAPIKey(), req.GetPath())
+	defer func() { s.end(r, resp, err) }()
+
+	if err = s.readOnly.check(); err != nil {
+		return nil, err
+	}
 
-	if err = s.validateAuth(req.GetAPIKey()); err != nil {
+	if err = s.authenticate(ctx, r, actionOn(macaroon.ActionDelete, req.GetPath())); err != nil {
 		return nil, err
 	}
 
@@ -228,3 +628,28 @@ func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (resp *pb.De
 	s.logger.Debug("deleted pointer at path: " + req.GetPath())
 	return &pb.DeleteResponse{}, nil
 }
+
+// Revoke revokes the API key of the request, and with it every key
+// restricted from it. Holding a key is enough to revoke it.
+func (s *Server) Revoke(ctx context.Context, req *pb.RevokeRequest) (resp *pb.RevokeResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(s.apiKeySecret) == 0 || s.revocations == nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "API keys can't be revoked")
+	}
+
+	key, err := macaroon.ParseAPIKey(string(req.GetAPIKey()))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, err.Error())
+	}
+	if !key.Validate(s.apiKeySecret) {
+		return nil, status.Errorf(codes.PermissionDenied, "invalid API key")
+	}
+
+	if err = s.revocations.Revoke(ctx, key.Tail()); err != nil {
+		s.logger.Error("err revoking API key", zap.Error(err))
+		return nil, status.Errorf(codes.Internal, err.Error())
+	}
+	s.logger.Info("revoked API key", zap.String("head", base58.Encode(key.Head())))
+	return &pb.RevokeResponse{}, nil
+}
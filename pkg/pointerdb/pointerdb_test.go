@@ -5,12 +5,24 @@ package pointerdb
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
 	"github.com/golang/protobuf/proto"
@@ -18,9 +30,19 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/stretchr/testify/assert"
 
+	"storj.io/storj/pkg/accesslog"
+	"storj.io/storj/pkg/accounting"
+	"storj.io/storj/pkg/analytics"
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/macaroon"
+	"storj.io/storj/pkg/orders"
+	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/paths"
 	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/provider"
 	"storj.io/storj/pkg/storage/meta"
+	"storj.io/storj/pkg/storage/objects"
 	"storj.io/storj/storage"
 	"storj.io/storj/storage/teststore"
 )
@@ -79,7 +101,7 @@ func TestServiceGet(t *testing.T) {
 
 		path := "a/b/c"
 
-		pr := &pb.Pointer{Size: 123}
+		pr := &pb.Pointer{Size: 123, Version: PointerVersion}
 		prBytes, err := proto.Marshal(pr)
 		assert.NoError(t, err, errTag)
 
@@ -104,6 +126,150 @@ func TestServiceGet(t *testing.T) {
 	}
 }
 
+type mockNodeCache map[string]*pb.Node
+
+func (cache mockNodeCache) GetAll(ctx context.Context, nodeIDs []string) ([]*pb.Node, error) {
+	var nodes []*pb.Node
+	for _, id := range nodeIDs {
+		nodes = append(nodes, cache[id])
+	}
+	return nodes, nil
+}
+
+func TestServiceGetNodes(t *testing.T) {
+	db := teststore.New()
+	cache := mockNodeCache{
+		"node1": {Id: "node1", Address: &pb.NodeAddress{Address: "127.0.0.1:1"}},
+		"node3": {Id: "node3", Address: &pb.NodeAddress{Address: "127.0.0.1:3"}},
+	}
+	s := Server{DB: db, logger: zap.NewNop(), config: Config{LookupNodes: true}, nodes: cache}
+
+	pr := &pb.Pointer{
+		Type: pb.Pointer_REMOTE,
+		Remote: &pb.RemoteSegment{
+			RemotePieces: []*pb.RemotePiece{
+				{PieceNum: 0, NodeId: "node1"},
+				{PieceNum: 1, NodeId: "node2"},
+				{PieceNum: 2, NodeId: "node3"},
+			},
+		},
+	}
+	prBytes, err := proto.Marshal(pr)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Put(storage.Key("a/b/c"), storage.Value(prBytes)))
+
+	resp, err := s.Get(ctx, &pb.GetRequest{Path: "a/b/c"})
+	if assert.NoError(t, err) && assert.Len(t, resp.GetNodes(), 3) {
+		assert.Equal(t, "127.0.0.1:1", resp.GetNodes()[0].GetAddress().GetAddress())
+		assert.Equal(t, "", resp.GetNodes()[1].GetId())
+		assert.Equal(t, "127.0.0.1:3", resp.GetNodes()[2].GetAddress().GetAddress())
+	}
+
+	s.config.LookupNodes = false
+	resp, err = s.Get(ctx, &pb.GetRequest{Path: "a/b/c"})
+	assert.NoError(t, err)
+	assert.Empty(t, resp.GetNodes())
+}
+
+func newIdentity(t *testing.T) *provider.FullIdentity {
+	ca, err := provider.NewCA(ctx, 12, 4)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	identity, err := ca.NewIdentity()
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return identity
+}
+
+func TestServiceOrderLimits(t *testing.T) {
+	db := teststore.New()
+	config := Config{OrderLimitExpiration: time.Hour, MaxPieceSize: 1 << 20}
+	cache := mockNodeCache{
+		"node1": {Id: "node1", Address: &pb.NodeAddress{Address: "127.0.0.1:1"}},
+		"node3": {Id: "node3", Address: &pb.NodeAddress{Address: "127.0.0.1:3"}},
+	}
+	satellite := newIdentity(t)
+	s := Server{DB: db, logger: zap.NewNop(), config: config, signer: orders.NewSigner(satellite), nodes: cache}
+
+	uplink := newIdentity(t)
+	uplinkCtx := peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{uplink.Leaf, uplink.CA},
+		}},
+	})
+
+	pr := &pb.Pointer{
+		Type: pb.Pointer_REMOTE,
+		Remote: &pb.RemoteSegment{
+			Redundancy: &pb.RedundancyScheme{MinReq: 2, Total: 3, ErasureShareSize: 256},
+			PieceId:    "piece",
+			RemotePieces: []*pb.RemotePiece{
+				{PieceNum: 0, NodeId: "node1"},
+				{PieceNum: 1, NodeId: "node2"},
+			},
+		},
+		Size: 1000,
+	}
+	prBytes, err := proto.Marshal(pr)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Put(storage.Key("a/b/c"), storage.Value(prBytes)))
+
+	verify := func(limit *pb.PayerBandwidthAllocation, action pb.PayerBandwidthAllocation_Action, nodeID string) *pb.PayerBandwidthAllocation_Data {
+		derived, err := client.PieceID("piece").Derive([]byte(nodeID))
+		assert.NoError(t, err)
+		data, err := orders.NewVerifier(nodeID, []string{satellite.ID.String()}).Verify(limit, action, string(derived), uplink.ID.String())
+		assert.NoError(t, err)
+		return data
+	}
+
+	// downloads are limited to the pieces of the pointer
+	resp, err := s.OrderLimits(uplinkCtx, &pb.OrderLimitsRequest{
+		Path:    "a/b/c",
+		Action:  pb.PayerBandwidthAllocation_GET,
+		PieceId: "other",
+		NodeIds: []string{"node3"},
+	})
+	if assert.NoError(t, err) && assert.Len(t, resp.GetLimits(), 2) {
+		// 1000 bytes and padding take 2 stripes of 512 bytes
+		data := verify(resp.GetLimits()[0], pb.PayerBandwidthAllocation_GET, "node1")
+		assert.Equal(t, int64(512), data.GetMaxSize())
+		assert.Equal(t, "127.0.0.1:1", data.GetStorageNodeAddress())
+		// the address of node2 isn't known
+		assert.Equal(t, "", verify(resp.GetLimits()[1], pb.PayerBandwidthAllocation_GET, "node2").GetStorageNodeAddress())
+	}
+
+	resp, err = s.OrderLimits(uplinkCtx, &pb.OrderLimitsRequest{
+		Path:    "a/b/d",
+		Action:  pb.PayerBandwidthAllocation_PUT,
+		PieceId: "piece",
+		NodeIds: []string{"node3"},
+	})
+	if assert.NoError(t, err) && assert.Len(t, resp.GetLimits(), 1) {
+		data := verify(resp.GetLimits()[0], pb.PayerBandwidthAllocation_PUT, "node3")
+		assert.Equal(t, int64(1<<20), data.GetMaxSize())
+		assert.Equal(t, "127.0.0.1:3", data.GetStorageNodeAddress())
+	}
+
+	// deletes are limited to the pieces of the pointer too
+	resp, err = s.OrderLimits(uplinkCtx, &pb.OrderLimitsRequest{
+		Path:   "a/b/c",
+		Action: pb.PayerBandwidthAllocation_DELETE,
+	})
+	if assert.NoError(t, err) && assert.Len(t, resp.GetLimits(), 2) {
+		data := verify(resp.GetLimits()[0], pb.PayerBandwidthAllocation_DELETE, "node1")
+		assert.Equal(t, int64(0), data.GetMaxSize())
+	}
+
+	_, err = s.OrderLimits(uplinkCtx, &pb.OrderLimitsRequest{Path: "a/b/d", Action: pb.PayerBandwidthAllocation_GET})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// order limits are only issued to authenticated uplinks
+	_, err = s.OrderLimits(ctx, &pb.OrderLimitsRequest{Path: "a/b/c", Action: pb.PayerBandwidthAllocation_GET})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
 func TestServiceDelete(t *testing.T) {
 	for i, tt := range []struct {
 		apiKey    []byte
@@ -221,8 +387,27 @@ func TestServiceList(t *testing.T) {
 					{Path: "müsic/album/söng3.mp3"},
 					{Path: "müsic/söng1.mp3"},
 				},
-				More: true,
+				More:   true,
+				Cursor: storage.NextCursor(storage.ListOptions{}, storage.Items{{Key: key("müsic/söng1.mp3")}}, true),
+			},
+		}, {
+			Request: pb.ListRequest{Recursive: true, Limit: 3,
+				Cursor: storage.NextCursor(storage.ListOptions{}, storage.Items{{Key: key("müsic/söng1.mp3")}}, true)},
+			Expected: &pb.ListResponse{
+				Items: []*pb.ListResponse_Item{
+					{Path: "müsic/söng2.mp3"},
+					{Path: "müsic/söng4.mp3"},
+					{Path: "sample.😶"},
+				},
+				More:   true,
+				Cursor: storage.NextCursor(storage.ListOptions{}, storage.Items{{Key: key("sample.😶")}}, true),
 			},
+		}, {
+			Request: pb.ListRequest{Recursive: true, StartAfter: "müsic", Cursor: "cursor"},
+			Error:   errorWithCode(codes.InvalidArgument),
+		}, {
+			Request: pb.ListRequest{Recursive: true, Cursor: "not a cursor"},
+			Error:   errorWithCode(codes.InvalidArgument),
 		}, {
 			Request: pb.ListRequest{MetaFlags: meta.All},
 			Expected: &pb.ListResponse{
@@ -319,3 +504,943 @@ func TestServiceList(t *testing.T) {
 		}
 	}
 }
+
+func TestServiceMacaroonAuth(t *testing.T) {
+	secret, err := macaroon.NewSecret()
+	if !assert.NoError(t, err) {
+		return
+	}
+	root, err := macaroon.NewAPIKey(secret)
+	if !assert.NoError(t, err) {
+		return
+	}
+	restricted, err := root.Restrict(pb.Caveat{
+		DisallowWrites:  true,
+		DisallowDeletes: true,
+		AllowedPaths:    []*pb.Caveat_Path{{Bucket: []byte("photos"), PathPrefix: []byte("2018/")}},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	db := teststore.New()
+	s := Server{DB: db, logger: zap.NewNop(), apiKeySecret: secret}
+	rootKey, restrictedKey := []byte(root.Serialize()), []byte(restricted.Serialize())
+
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/photos/2018/a", Pointer: &pb.Pointer{}, APIKey: rootKey})
+	assert.NoError(t, err)
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/photos/2018/b", Pointer: &pb.Pointer{}, APIKey: restrictedKey})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/photos/2018/a", APIKey: restrictedKey})
+	assert.NoError(t, err)
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/photos/2017/a", APIKey: restrictedKey})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/other/2018/a", APIKey: restrictedKey})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	other, err := macaroon.NewSecret()
+	if assert.NoError(t, err) {
+		s.apiKeySecret = other
+		_, err = s.Get(ctx, &pb.GetRequest{Path: "l/photos/2018/a", APIKey: rootKey})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	}
+}
+
+func TestServiceRevoke(t *testing.T) {
+	secret, err := macaroon.NewSecret()
+	if !assert.NoError(t, err) {
+		return
+	}
+	root, err := macaroon.NewAPIKey(secret)
+	if !assert.NoError(t, err) {
+		return
+	}
+	shared, err := root.Restrict(pb.Caveat{DisallowDeletes: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+	derived, err := shared.Restrict(pb.Caveat{DisallowWrites: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	s := Server{DB: teststore.New(), logger: zap.NewNop(), apiKeySecret: secret}
+	get := func(key *macaroon.APIKey) error {
+		_, err := s.Get(ctx, &pb.GetRequest{Path: "l/photos/a", APIKey: []byte(key.Serialize())})
+		return err
+	}
+
+	_, err = s.Revoke(ctx, &pb.RevokeRequest{APIKey: []byte(shared.Serialize())})
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	s.revocations = NewRevocations(teststore.New())
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/photos/a", Pointer: &pb.Pointer{}, APIKey: []byte(root.Serialize())})
+	assert.NoError(t, err)
+
+	_, err = s.Revoke(ctx, &pb.RevokeRequest{APIKey: []byte(shared.Serialize())})
+	assert.NoError(t, err)
+
+	// revoking a key revokes the keys derived from it, but not its parents
+	assert.Equal(t, codes.PermissionDenied, status.Code(get(shared)))
+	assert.Equal(t, codes.PermissionDenied, status.Code(get(derived)))
+	assert.NoError(t, get(root))
+
+	// restoring a key restores the keys derived from it
+	assert.NoError(t, s.revocations.Restore(ctx, shared.Tail()))
+	assert.NoError(t, get(derived))
+
+	// buckets record the project of the key that created them
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/photos", Pointer: &pb.Pointer{}, APIKey: []byte(shared.Serialize())})
+	assert.NoError(t, err)
+	value, err := s.DB.Get(storage.Key("l/photos"))
+	if assert.NoError(t, err) {
+		bucket, _, err := UnmarshalPointer(value)
+		if assert.NoError(t, err) {
+			assert.Equal(t, macaroon.ProjectID([]byte(root.Serialize())), bucket.GetProjectId())
+		}
+	}
+
+	other, err := macaroon.NewSecret()
+	if !assert.NoError(t, err) {
+		return
+	}
+	forged, err := macaroon.NewAPIKey(other)
+	if assert.NoError(t, err) {
+		_, err = s.Revoke(ctx, &pb.RevokeRequest{APIKey: []byte(forged.Serialize())})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	}
+}
+
+func TestServiceGetMigrates(t *testing.T) {
+	db := teststore.New()
+	s := Server{DB: db, logger: zap.NewNop(), config: Config{LazyMigration: true}}
+
+	// an inline pointer stored before pointers were versioned
+	old, err := proto.Marshal(&pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: []byte("data")})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, db.Put(storage.Key("l/a/b"), storage.Value(old)))
+
+	resp, err := s.Get(ctx, &pb.GetRequest{Path: "l/a/b"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	pointer := &pb.Pointer{}
+	if assert.NoError(t, proto.Unmarshal(resp.GetPointer(), pointer)) {
+		assert.EqualValues(t, PointerVersion, pointer.Version)
+		assert.EqualValues(t, 4, pointer.Size)
+	}
+
+	// the migrated pointer was written back
+	stored, err := db.Get(storage.Key("l/a/b"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, resp.GetPointer(), []byte(stored))
+	}
+
+	newer, err := proto.Marshal(&pb.Pointer{Version: PointerVersion + 1})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, db.Put(storage.Key("l/a/c"), storage.Value(newer)))
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/a/c"})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestServiceListReplica(t *testing.T) {
+	primary, replica := teststore.New(), teststore.New()
+	s := Server{DB: primary, replica: replica, logger: zap.NewNop()}
+
+	_, err := s.Put(ctx, &pb.PutRequest{Path: "l/a/b", Pointer: &pb.Pointer{}})
+	assert.NoError(t, err)
+
+	// the replica hasn't caught up yet, but gets see the write
+	resp, err := s.List(ctx, &pb.ListRequest{Prefix: "l/a", Recursive: true})
+	if assert.NoError(t, err) {
+		assert.Empty(t, resp.GetItems())
+	}
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/a/b"})
+	assert.NoError(t, err)
+
+	value, err := primary.Get(storage.Key("l/a/b"))
+	if assert.NoError(t, err) {
+		assert.NoError(t, replica.Put(storage.Key("l/a/b"), value))
+	}
+	resp, err = s.List(ctx, &pb.ListRequest{Prefix: "l/a", Recursive: true})
+	if assert.NoError(t, err) && assert.Len(t, resp.GetItems(), 1) {
+		assert.Equal(t, "b", resp.GetItems()[0].GetPath())
+	}
+}
+
+func TestServiceBatch(t *testing.T) {
+	db := teststore.New()
+	s := Server{DB: db, logger: zap.NewNop(), config: Config{MaxInlineSegmentSize: 8000}}
+
+	pointer := &pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: []byte("data"), Size: 4}
+	resp, err := s.Batch(ctx, &pb.BatchRequest{Requests: []*pb.BatchRequestItem{
+		{Put: &pb.PutRequest{Path: "a/b/c", Pointer: pointer}},
+		{Get: &pb.GetRequest{Path: "a/b/c"}},
+	}})
+	if assert.NoError(t, err) && assert.Len(t, resp.GetResponses(), 2) {
+		assert.NotNil(t, resp.GetResponses()[0].GetPut())
+		got := &pb.Pointer{}
+		assert.NoError(t, proto.Unmarshal(resp.GetResponses()[1].GetGet().GetPointer(), got))
+		assert.Equal(t, pointer.GetInlineSegment(), got.GetInlineSegment())
+	}
+
+	_, err = s.Batch(ctx, &pb.BatchRequest{
+		Requests: []*pb.BatchRequestItem{{Get: &pb.GetRequest{Path: "a/b/c"}}},
+		APIKey:   []byte("wrong key"),
+	})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	// the requests after a failed one aren't executed
+	_, err = s.Batch(ctx, &pb.BatchRequest{Requests: []*pb.BatchRequestItem{
+		{Delete: &pb.DeleteRequest{Path: "a/b/c"}},
+		{Get: &pb.GetRequest{Path: "a/b/c"}},
+		{Put: &pb.PutRequest{Path: "d/e/f", Pointer: pointer}},
+	}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	assert.Contains(t, err.Error(), "batch request 1")
+	_, err = db.Get(storage.Key("d/e/f"))
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+
+	_, err = s.Batch(ctx, &pb.BatchRequest{Requests: []*pb.BatchRequestItem{{}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestServicePutIdempotent(t *testing.T) {
+	db := teststore.New()
+	s := Server{DB: db, logger: zap.NewNop(), config: Config{MaxInlineSegmentSize: 8000},
+		commits: NewCommits(teststore.New(), time.Hour)}
+
+	put := func(path, idempotencyKey string, data []byte) (*pb.PutResponse, error) {
+		return s.Put(ctx, &pb.PutRequest{
+			Path:           path,
+			Pointer:        &pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: data},
+			IdempotencyKey: idempotencyKey,
+		})
+	}
+
+	first, err := put("a/b/c", "key", []byte("first"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	stored, err := db.Get(storage.Key("a/b/c"))
+	assert.NoError(t, err)
+
+	// the retried commit returns the first result without writing again
+	retried, err := put("a/b/c", "key", []byte("retried"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, first.GetCreationDate(), retried.GetCreationDate())
+	}
+	value, err := db.Get(storage.Key("a/b/c"))
+	assert.NoError(t, err)
+	assert.Equal(t, stored, value)
+
+	_, err = put("d/e/f", "key", []byte("other"))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+
+	// other keys and commits without a key are applied
+	_, err = put("a/b/c", "other key", []byte("second"))
+	assert.NoError(t, err)
+	_, err = put("a/b/c", "", []byte("third"))
+	assert.NoError(t, err)
+	value, err = db.Get(storage.Key("a/b/c"))
+	assert.NoError(t, err)
+	assert.NotEqual(t, stored, value)
+}
+
+func TestServiceCosts(t *testing.T) {
+	db := teststore.New()
+	for _, path := range []string{"a/1", "a/2", "a/3", "a/4"} {
+		assert.NoError(t, db.Put(storage.Key(path), storage.Value("pointer")))
+	}
+	s := Server{DB: db, logger: zap.NewNop(), costs: NewCosts(10, time.Hour), config: Config{MaxScanned: 3}}
+
+	_, err := s.Get(ctx, &pb.GetRequest{Path: "a/1"})
+	assert.NoError(t, err)
+	// the costs of unauthenticated keys aren't accumulated
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "a/1", APIKey: []byte("wrong key")})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = s.List(ctx, &pb.ListRequest{Prefix: "a", Recursive: true, Limit: 2})
+	assert.NoError(t, err)
+
+	_, err = s.List(ctx, &pb.ListRequest{Prefix: "a", Recursive: true})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	s.config = Config{RequestTimeout: time.Nanosecond}
+	_, err = s.List(ctx, &pb.ListRequest{Prefix: "a", Recursive: true})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+
+	costs := s.costs.ByKey()
+	if assert.Len(t, costs, 1) {
+		cost := costs[keyID(nil)]
+		assert.Equal(t, int64(4), cost.Requests)
+		assert.Equal(t, int64(2), cost.Aborted)
+		// the get, the two listed items and the one telling there are more,
+		// the items up to the one over the limit, and the one past the timeout
+		assert.Equal(t, int64(1+3+4+1), cost.Scanned)
+		assert.True(t, cost.Bytes > 0)
+	}
+}
+
+func TestCostsEviction(t *testing.T) {
+	now := time.Now()
+	costs := NewCosts(2, time.Hour)
+	costs.now = func() time.Time { return now }
+
+	costs.Add("a", Cost{Requests: 1})
+	now = now.Add(time.Minute)
+	costs.Add("b", Cost{Requests: 1})
+	now = now.Add(time.Minute)
+	costs.Add("a", Cost{Requests: 1})
+	now = now.Add(time.Minute)
+	// b was used the longest ago
+	costs.Add("c", Cost{Requests: 1})
+	assert.Equal(t, map[string]Cost{"a": {Requests: 2}, "c": {Requests: 1}}, costs.ByKey())
+
+	// a expires first
+	now = now.Add(time.Hour - time.Second)
+	assert.Equal(t, map[string]Cost{"c": {Requests: 1}}, costs.ByKey())
+	costs.Add("d", Cost{Requests: 1})
+	assert.Len(t, costs.byKey, 2)
+}
+
+type accessLogSink struct {
+	entries []*accesslog.Entry
+}
+
+func (sink *accessLogSink) Write(entry *accesslog.Entry) error {
+	sink.entries = append(sink.entries, entry)
+	return nil
+}
+
+func (sink *accessLogSink) Close() error { return nil }
+
+func TestServiceAccessLog(t *testing.T) {
+	secret, err := macaroon.NewSecret()
+	if !assert.NoError(t, err) {
+		return
+	}
+	root, err := macaroon.NewAPIKey(secret)
+	if !assert.NoError(t, err) {
+		return
+	}
+	restricted, err := root.Restrict(pb.Caveat{DisallowWrites: true})
+	if !assert.NoError(t, err) {
+		return
+	}
+	rootKey, restrictedKey := []byte(root.Serialize()), []byte(restricted.Serialize())
+
+	sink := &accessLogSink{}
+	db := teststore.New()
+	s := Server{DB: db, logger: zap.NewNop(), apiKeySecret: secret,
+		accessLog: accesslog.New(sink, 1, map[string]float64{projectID([]byte("sampled out")): 0})}
+
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "a/b", Pointer: &pb.Pointer{}, APIKey: rootKey})
+	assert.NoError(t, err)
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "a/c", Pointer: &pb.Pointer{}, APIKey: restrictedKey})
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "a/b", APIKey: restrictedKey})
+	assert.NoError(t, err)
+	_, _ = s.Get(ctx, &pb.GetRequest{Path: "a/b", APIKey: []byte("sampled out")})
+
+	if !assert.Len(t, sink.entries, 3) {
+		return
+	}
+	// the keys restricted from a root key share its project
+	for _, entry := range sink.entries {
+		assert.Equal(t, projectID(rootKey), entry.Project)
+	}
+	assert.Equal(t, keyID(rootKey), sink.entries[0].Key)
+	assert.Equal(t, keyID(restrictedKey), sink.entries[1].Key)
+
+	assert.Equal(t, "put", sink.entries[0].Operation)
+	assert.Equal(t, codes.OK.String(), sink.entries[0].Status)
+	assert.Equal(t, codes.PermissionDenied.String(), sink.entries[1].Status)
+	assert.Equal(t, "get", sink.entries[2].Operation)
+	assert.Equal(t, pathHash("a/b"), sink.entries[2].PathHash)
+	assert.NotEqual(t, pathHash("a/b"), pathHash("a/c"))
+	assert.True(t, sink.entries[2].Bytes > 0)
+}
+
+func TestServiceBlocks(t *testing.T) {
+	db := teststore.New()
+	blocks := NewBlocks(zap.NewNop(), teststore.New())
+	s := Server{DB: db, logger: zap.NewNop(), blocks: blocks}
+	for _, path := range []string{"l/photos/a", "s0/photos/a", "l/photos/ab", "l/videos/x/y"} {
+		assert.NoError(t, db.Put(storage.Key(path), storage.Value("pointer")))
+	}
+	get := func(path string) error {
+		_, err := s.Get(ctx, &pb.GetRequest{Path: path})
+		return err
+	}
+
+	_, err := blocks.Block(ctx, Block{Path: "photos/a", Reason: "dmca"})
+	assert.Error(t, err)
+	_, err = blocks.Block(ctx, Block{Path: "/photos/a", Reason: "dmca", By: "alice"})
+	assert.NoError(t, err)
+	_, err = blocks.Block(ctx, Block{Path: "videos/", Prefix: true, Reason: "abuse", By: "bob"})
+	assert.NoError(t, err)
+
+	// all the segments of a blocked object are blocked, with the reason
+	for _, path := range []string{"l/photos/a", "s0/photos/a"} {
+		err := get(path)
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.Contains(t, status.Convert(err).Message(), "object blocked: dmca")
+	}
+	assert.NoError(t, get("l/photos/ab"))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(get("l/videos/x/y")))
+
+	list, err := blocks.List(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, list, 2) {
+		assert.Equal(t, "photos/a", list[0].Path)
+		assert.Equal(t, "videos", list[1].Path)
+	}
+
+	// unblocking keeps the data and the audit trail
+	assert.Error(t, blocks.Unblock(ctx, "photos/a", ""))
+	assert.True(t, storage.ErrKeyNotFound.Has(blocks.Unblock(ctx, "photos/b", "carol")))
+	assert.NoError(t, blocks.Unblock(ctx, "photos/a", "carol"))
+	assert.NoError(t, get("l/photos/a"))
+
+	trail, err := blocks.Trail(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, trail, 3) {
+		assert.Equal(t, "block", trail[0].Action)
+		assert.Equal(t, "alice", trail[0].By)
+		assert.Equal(t, "unblock", trail[2].Action)
+		assert.Equal(t, "carol", trail[2].By)
+		assert.Equal(t, "dmca", trail[2].Block.Reason)
+	}
+
+	// the admin API
+	server := httptest.NewServer(blocks)
+	defer server.Close()
+	resp, err := http.Post(server.URL+"/pointerdb/blocks/", "application/json",
+		strings.NewReader(`{"path": "photos/ab", "reason": "spam", "by": "dave"}`))
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.NoError(t, resp.Body.Close())
+	}
+	assert.Equal(t, codes.FailedPrecondition, status.Code(get("l/photos/ab")))
+
+	resp, err = http.Get(server.URL + "/pointerdb/blocks/trail")
+	if assert.NoError(t, err) {
+		var events []BlockEvent
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&events))
+		assert.NoError(t, resp.Body.Close())
+		assert.Len(t, events, 4)
+	}
+}
+
+func TestServiceReadOnly(t *testing.T) {
+	db := teststore.New()
+	flags := teststore.New()
+	readOnly, err := NewReadOnly(zap.NewNop(), flags, true, "migration")
+	if !assert.NoError(t, err) {
+		return
+	}
+	s := Server{DB: db, logger: zap.NewNop(), readOnly: readOnly}
+	assert.NoError(t, db.Put(storage.Key("l/a"), storage.Value("pointer")))
+
+	// writes and deletions are rejected with the reason, reads continue
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/b", Pointer: &pb.Pointer{}})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "read-only")
+	assert.Contains(t, status.Convert(err).Message(), "migration")
+	_, err = s.Delete(ctx, &pb.DeleteRequest{Path: "l/a"})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	_, err = s.Get(ctx, &pb.GetRequest{Path: "l/a"})
+	assert.NoError(t, err)
+
+	// the admin API switches the mode back
+	w := httptest.NewRecorder()
+	readOnly.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/pointerdb/read-only", strings.NewReader(`{"enabled": false}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = httptest.NewRecorder()
+	readOnly.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/pointerdb/read-only", strings.NewReader(`{"enabled": false, "by": "alice"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var state ReadOnlyState
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&state))
+	assert.False(t, state.Enabled)
+	assert.Equal(t, "alice", state.By)
+
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/b", Pointer: &pb.Pointer{}})
+	assert.NoError(t, err)
+	_, err = s.Delete(ctx, &pb.DeleteRequest{Path: "l/a"})
+	assert.NoError(t, err)
+
+	// the mode is shared by the satellites using the same database, and
+	// stays as it was switched when they restart
+	other, err := NewReadOnly(zap.NewNop(), flags, false, "")
+	if assert.NoError(t, err) {
+		assert.NoError(t, other.Set(true, "incident", "bob"))
+		_, err = s.Put(ctx, &pb.PutRequest{Path: "l/c", Pointer: &pb.Pointer{}})
+		assert.Contains(t, status.Convert(err).Message(), "incident")
+	}
+}
+
+func TestServiceSuites(t *testing.T) {
+	db := teststore.New()
+	flagDB := teststore.New()
+	suites, err := NewSuites(zap.NewNop(), flagDB, "rs, aesgcmsiv-rs")
+	if !assert.NoError(t, err) {
+		return
+	}
+	s := Server{DB: db, logger: zap.NewNop(), suites: suites}
+
+	put := func(suite eestream.SuiteID) error {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: "l/a", Pointer: &pb.Pointer{Suite: int32(suite)}})
+		return err
+	}
+	assert.NoError(t, put(eestream.SuiteRS))
+	assert.NoError(t, put(eestream.SuiteAESGCMSIVRS))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(put(eestream.SuiteAESGCMRS)))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(put(eestream.SuiteUnspecified)))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(put(1000)))
+
+	// the admin API deprecates a suite
+	w := httptest.NewRecorder()
+	suites.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/pointerdb/suites", strings.NewReader(`{"name": "rs", "enabled": false, "by": "alice"}`)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var flags []SuiteFlag
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&flags))
+	if assert.Len(t, flags, len(eestream.Suites())+1) {
+		assert.Equal(t, "unspecified", flags[0].Name)
+		assert.Equal(t, "rs", flags[1].Name)
+		assert.False(t, flags[1].Enabled)
+		assert.Equal(t, "alice", flags[1].By)
+	}
+	assert.Equal(t, codes.FailedPrecondition, status.Code(put(eestream.SuiteRS)))
+
+	// the switched flags are shared by the satellites using the same
+	// database, and take precedence over their config
+	other, err := NewSuites(zap.NewNop(), flagDB, "rs")
+	if assert.NoError(t, err) {
+		assert.NoError(t, other.Set("aesgcm-rs", true, "bob"))
+		assert.NoError(t, put(eestream.SuiteAESGCMRS))
+		assert.Equal(t, codes.FailedPrecondition, status.Code(other.check(int32(eestream.SuiteRS))))
+	}
+
+	w = httptest.NewRecorder()
+	suites.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/pointerdb/suites", strings.NewReader(`{"name": "rot13-rs", "enabled": true, "by": "alice"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	_, err = NewSuites(zap.NewNop(), flagDB, "rs,rot13-rs")
+	assert.Error(t, err)
+}
+
+func TestServiceGetObjectInfo(t *testing.T) {
+	db := teststore.New()
+	s := Server{DB: db, logger: zap.NewNop()}
+
+	put := func(path string, pointer *pb.Pointer) {
+		value, err := MarshalPointer(pointer)
+		if assert.NoError(t, err) {
+			assert.NoError(t, db.Put(storage.Key(path), storage.Value(value)))
+		}
+	}
+
+	objectMeta, err := proto.Marshal(&objects.SerializableMeta{
+		ContentType: "text/plain",
+		UserDefined: map[string]string{"owner": "secret", "color": "blue"},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	streamMeta, err := proto.Marshal(&pb.MetaStreamInfo{
+		NumberOfSegments: 1,
+		SegmentsSize:     100,
+		LastSegmentSize:  4,
+		Metadata:         objectMeta,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	redundancy := &pb.RedundancyScheme{MinReq: 2, Total: 4, RepairThreshold: 3, SuccessThreshold: 4}
+	put("s0/bucket/object", &pb.Pointer{
+		Type: pb.Pointer_REMOTE,
+		Size: 100,
+		Remote: &pb.RemoteSegment{
+			Redundancy:   redundancy,
+			PieceId:      "piece",
+			RemotePieces: []*pb.RemotePiece{{PieceNum: 0, NodeId: "a"}, {PieceNum: 1, NodeId: "b"}, {PieceNum: 3, NodeId: "c"}},
+		},
+	})
+	put("l/bucket/object", &pb.Pointer{
+		Type:          pb.Pointer_INLINE,
+		InlineSegment: []byte("data"),
+		Size:          4,
+		CreationDate:  ptypes.TimestampNow(),
+		Metadata:      streamMeta,
+	})
+
+	resp, err := s.GetObjectInfo(ctx, &pb.GetObjectInfoRequest{Path: "bucket/object"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualValues(t, 104, resp.GetSize())
+	assert.NotNil(t, resp.GetCreationDate())
+	assert.Equal(t, "text/plain", resp.GetContentType())
+	assert.Equal(t, []string{"color", "owner"}, resp.GetMetadataKeys())
+	if assert.Len(t, resp.GetSegments(), 2) {
+		remote, inline := resp.GetSegments()[0], resp.GetSegments()[1]
+		assert.Equal(t, "s0/bucket/object", remote.GetPath())
+		assert.Equal(t, pb.Pointer_REMOTE, remote.GetType())
+		assert.True(t, proto.Equal(redundancy, remote.GetRedundancy()))
+		assert.EqualValues(t, 3, remote.GetPieces())
+		assert.Equal(t, "l/bucket/object", inline.GetPath())
+		assert.Equal(t, pb.Pointer_INLINE, inline.GetType())
+		assert.EqualValues(t, 4, inline.GetSize())
+		assert.Nil(t, inline.GetRedundancy())
+	}
+
+	_, err = s.GetObjectInfo(ctx, &pb.GetObjectInfoRequest{Path: "bucket/missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// a missing segment fails the request
+	assert.NoError(t, db.Delete(storage.Key("s0/bucket/object")))
+	_, err = s.GetObjectInfo(ctx, &pb.GetObjectInfoRequest{Path: "bucket/object"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+type analyticsSink struct {
+	events []*analytics.Event
+}
+
+func (sink *analyticsSink) Write(event *analytics.Event) error {
+	sink.events = append(sink.events, event)
+	return nil
+}
+
+func (sink *analyticsSink) Close() error { return nil }
+
+func TestServiceAnalytics(t *testing.T) {
+	sink := &analyticsSink{}
+	s := Server{DB: teststore.New(), logger: zap.NewNop(), config: Config{MaxInlineSegmentSize: 8000, MaxScanned: 1},
+		analytics: analytics.New(sink, teststore.New())}
+
+	pointer := &pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: []byte("data"), Size: 4}
+	for _, path := range []string{"l/bucket", "l/bucket", "s0/bucket/a", "l/bucket/a", "l/bucket/b"} {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: pointer})
+		assert.NoError(t, err)
+	}
+	_, err := s.List(ctx, &pb.ListRequest{Prefix: "l/bucket", Recursive: true})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+
+	var names []string
+	for _, event := range sink.events {
+		names = append(names, event.Name)
+		assert.Equal(t, projectID(nil), event.ProjectID)
+	}
+	// the bucket is only created once, and only the first segment is an
+	// upload of the project
+	assert.Equal(t, []string{analytics.BucketCreated, analytics.FirstUpload, analytics.LimitHit}, names)
+	if assert.Len(t, sink.events, 3) {
+		assert.Equal(t, "bucket", sink.events[0].Properties["bucket"])
+		assert.Equal(t, "list", sink.events[2].Properties["method"])
+	}
+}
+
+func TestBandwidth(t *testing.T) {
+	policies, err := ParseBandwidthPolicies("hard=hard:100, burst=burst:100:50,alert=alert:100")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, BandwidthPolicy{Mode: Burst, Limit: 100, Burst: 50}, policies["burst"])
+	for _, invalid := range []string{"p", "p=hard", "p=hard:1:2", "p=burst:1", "p=other:1", "p=hard:-1", "=alert:1"} {
+		_, err := ParseBandwidthPolicies(invalid)
+		assert.Error(t, err, invalid)
+	}
+
+	now := time.Date(2018, time.December, 31, 23, 0, 0, 0, time.UTC)
+	bandwidth := NewBandwidth(zap.NewNop(), teststore.New(), policies)
+	bandwidth.now = func() time.Time { return now }
+
+	allocate := func(project string, bytes int64) (alert bool, exceeded bool) {
+		alert, err := bandwidth.Allocate(ctx, project, bytes)
+		if !ErrBandwidthExceeded.Has(err) {
+			assert.NoError(t, err)
+		}
+		return alert, err != nil
+	}
+	for _, project := range []string{"hard", "burst", "alert"} {
+		alert, exceeded := allocate(project, 100)
+		assert.False(t, alert, project)
+		assert.False(t, exceeded, project)
+	}
+
+	// hard caps reject anything beyond the limit
+	_, exceeded := allocate("hard", 1)
+	assert.True(t, exceeded)
+	// bursts are allowed up to the allowance, and alerted once
+	alert, exceeded := allocate("burst", 30)
+	assert.True(t, alert)
+	assert.False(t, exceeded)
+	alert, exceeded = allocate("burst", 20)
+	assert.False(t, alert)
+	assert.False(t, exceeded)
+	_, exceeded = allocate("burst", 1)
+	assert.True(t, exceeded)
+	// alerts never reject
+	alert, exceeded = allocate("alert", 1000)
+	assert.True(t, alert)
+	assert.False(t, exceeded)
+	// projects without a policy aren't limited
+	alert, exceeded = allocate("other", 1000)
+	assert.False(t, alert)
+	assert.False(t, exceeded)
+
+	usage, err := bandwidth.Usage(ctx, "2018-12")
+	assert.NoError(t, err)
+	byProject := map[string]BandwidthUsage{}
+	for _, entry := range usage {
+		byProject[entry.Project] = entry
+	}
+	assert.Len(t, byProject, 4)
+	assert.Equal(t, int64(150), byProject["burst"].Allocated)
+	assert.Equal(t, int64(50), byProject["burst"].Overage)
+	assert.Equal(t, int64(100), byProject["hard"].Allocated)
+	assert.Nil(t, byProject["other"].Policy)
+
+	// allocations start over every month
+	now = now.Add(time.Hour)
+	_, exceeded = allocate("hard", 100)
+	assert.False(t, exceeded)
+
+	w := httptest.NewRecorder()
+	bandwidth.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pointerdb/bandwidth", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var served []BandwidthUsage
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&served))
+	assert.Equal(t, []BandwidthUsage{{Project: "hard", Month: "2019-01", Allocated: 100, Policy: &BandwidthPolicy{Mode: HardCap, Limit: 100}}}, served)
+
+	w = httptest.NewRecorder()
+	bandwidth.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/pointerdb/bandwidth?month=december", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestServiceBandwidth(t *testing.T) {
+	db := teststore.New()
+	config := Config{OrderLimitExpiration: time.Hour}
+	s := Server{DB: db, logger: zap.NewNop(), config: config, signer: orders.NewSigner(newIdentity(t))}
+	s.bandwidth = NewBandwidth(zap.NewNop(), teststore.New(), map[string]BandwidthPolicy{"*": {Mode: HardCap, Limit: 2048}})
+
+	uplink := newIdentity(t)
+	uplinkCtx := peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{uplink.Leaf, uplink.CA},
+		}},
+	})
+
+	pr := &pb.Pointer{
+		Type: pb.Pointer_REMOTE,
+		Remote: &pb.RemoteSegment{
+			Redundancy: &pb.RedundancyScheme{MinReq: 2, Total: 3, ErasureShareSize: 256},
+			PieceId:    "piece",
+			RemotePieces: []*pb.RemotePiece{
+				{PieceNum: 0, NodeId: "node1"},
+				{PieceNum: 1, NodeId: "node2"},
+			},
+		},
+		Size: 1000,
+	}
+	prBytes, err := proto.Marshal(pr)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Put(storage.Key("a/b/c"), storage.Value(prBytes)))
+
+	// every download is allocated the 1000 bytes of the segment, not the
+	// 2 order limits of 512 bytes
+	get := &pb.OrderLimitsRequest{Path: "a/b/c", Action: pb.PayerBandwidthAllocation_GET}
+	for i := 0; i < 2; i++ {
+		_, err = s.OrderLimits(uplinkCtx, get)
+		assert.NoError(t, err)
+	}
+	_, err = s.OrderLimits(uplinkCtx, get)
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+	usage, err := s.bandwidth.Usage(ctx, month(time.Now()))
+	if assert.NoError(t, err) && assert.Len(t, usage, 1) {
+		assert.Equal(t, int64(2000), usage[0].Allocated)
+	}
+
+	// uploads aren't limited
+	_, err = s.OrderLimits(uplinkCtx, &pb.OrderLimitsRequest{
+		Path:    "a/b/d",
+		Action:  pb.PayerBandwidthAllocation_PUT,
+		PieceId: "piece",
+		NodeIds: []string{"node3"},
+	})
+	assert.NoError(t, err)
+}
+
+func TestServiceBucketRedundancy(t *testing.T) {
+	s := Server{DB: teststore.New(), logger: zap.NewNop()}
+
+	// the metadata of a bucket as the bucket store stores it
+	objectMeta, err := proto.Marshal(&objects.SerializableMeta{UserDefined: map[string]string{
+		"default-rs-type": "0", "default-rs-reqd": "2", "default-rs-total": "6",
+		"default-rs-repair": "3", "default-rs-success": "5", "default-rs-sharsz": "1024",
+	}})
+	if !assert.NoError(t, err) {
+		return
+	}
+	bucketMeta, err := proto.Marshal(&pb.MetaStreamInfo{Metadata: objectMeta})
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/photos", Pointer: &pb.Pointer{Metadata: bucketMeta}})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	put := func(path string, rs *pb.RedundancyScheme) error {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: &pb.Pointer{
+			Type:   pb.Pointer_REMOTE,
+			Remote: &pb.RemoteSegment{Redundancy: rs},
+		}})
+		return err
+	}
+	rs := &pb.RedundancyScheme{Type: pb.RedundancyScheme_RS, MinReq: 2, Total: 6,
+		RepairThreshold: 3, SuccessThreshold: 5, ErasureShareSize: 1024}
+	other := &pb.RedundancyScheme{Type: pb.RedundancyScheme_RS, MinReq: 1, Total: 2,
+		RepairThreshold: 1, SuccessThreshold: 2, ErasureShareSize: 1024}
+
+	assert.NoError(t, put("s0/photos/a", rs))
+	assert.Equal(t, codes.InvalidArgument, status.Code(put("s0/photos/a", other)))
+	// buckets without a default redundancy scheme accept any
+	assert.NoError(t, put("s0/other/a", other))
+}
+
+func TestServiceAttribution(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "storj-pointerdb")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, os.RemoveAll(tmpdir)) }()
+	usage, err := accounting.Open(ctx, filepath.Join(tmpdir, "accounting.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, usage.Close()) }()
+
+	s := Server{DB: teststore.New(), logger: zap.NewNop(), attributions: usage}
+
+	// the metadata of buckets as the bucket store stores it
+	bucket := func(userDefined map[string]string) *pb.Pointer {
+		objectMeta, err := proto.Marshal(&objects.SerializableMeta{UserDefined: userDefined})
+		assert.NoError(t, err)
+		bucketMeta, err := proto.Marshal(&pb.MetaStreamInfo{Metadata: objectMeta})
+		assert.NoError(t, err)
+		return &pb.Pointer{Metadata: bucketMeta}
+	}
+	for path, pointer := range map[string]*pb.Pointer{
+		"l/photos": bucket(map[string]string{"partner-id": "partner1"}),
+		"l/other":  bucket(nil),
+	} {
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: pointer})
+		assert.NoError(t, err)
+	}
+	// only new buckets are attributed
+	_, err = s.Put(ctx, &pb.PutRequest{Path: "l/photos", Pointer: bucket(map[string]string{"partner-id": "partner2"})})
+	assert.NoError(t, err)
+
+	// the usage of attributed buckets is rolled up for their partner
+	day := time.Date(2018, 9, 20, 0, 0, 0, 0, time.UTC)
+	project := projectID(nil)
+	assert.NoError(t, usage.Add(ctx, accounting.Bandwidth, accounting.Key{ProjectID: project, Bucket: "photos"}, day, 10))
+	assert.NoError(t, usage.Add(ctx, accounting.Bandwidth, accounting.Key{ProjectID: project, Bucket: "other"}, day, 20))
+
+	attributions, err := usage.Attributions(ctx, accounting.Bandwidth, day, day.Add(24*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, []accounting.Attribution{{PartnerID: "partner1", Value: 10}}, attributions)
+}
+
+func TestServiceUsage(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "storj-pointerdb")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, os.RemoveAll(tmpdir)) }()
+	usage, err := accounting.Open(ctx, filepath.Join(tmpdir, "accounting.db"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, usage.Close()) }()
+
+	db := teststore.New()
+	config := Config{OrderLimitExpiration: time.Hour, MaxPieceSize: 1 << 20}
+	s := Server{DB: db, logger: zap.NewNop(), config: config, signer: orders.NewSigner(newIdentity(t)), usage: usage}
+
+	uplink := newIdentity(t)
+	uplinkCtx := peer.NewContext(ctx, &peer.Peer{
+		AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+			PeerCertificates: []*x509.Certificate{uplink.Leaf, uplink.CA},
+		}},
+	})
+
+	pr := &pb.Pointer{
+		Type: pb.Pointer_REMOTE,
+		Remote: &pb.RemoteSegment{
+			Redundancy:   &pb.RedundancyScheme{MinReq: 2, Total: 3, ErasureShareSize: 256},
+			PieceId:      "piece",
+			RemotePieces: []*pb.RemotePiece{{PieceNum: 0, NodeId: "node1"}},
+		},
+		Size: 1000,
+	}
+	prBytes, err := proto.Marshal(pr)
+	assert.NoError(t, err)
+	assert.NoError(t, db.Put(storage.Key("l/photos/a"), storage.Value(prBytes)))
+
+	start := time.Now()
+	for _, action := range []pb.PayerBandwidthAllocation_Action{pb.PayerBandwidthAllocation_GET, pb.PayerBandwidthAllocation_GET, pb.PayerBandwidthAllocation_DELETE} {
+		_, err = s.OrderLimits(uplinkCtx, &pb.OrderLimitsRequest{Path: "l/photos/a", Action: action})
+		assert.NoError(t, err)
+	}
+
+	// the downloads of the segment are rolled up for its bucket
+	rollups, err := usage.Rollups(ctx, accounting.Daily, start, time.Now().Add(time.Second))
+	assert.NoError(t, err)
+	if assert.Len(t, rollups, 1) {
+		assert.Equal(t, accounting.Bandwidth, rollups[0].Kind)
+		assert.Equal(t, accounting.Key{ProjectID: projectID(nil), Bucket: "photos"}, rollups[0].Key)
+		assert.Equal(t, int64(2000), rollups[0].Value)
+	}
+}
+
+func TestServicePlacement(t *testing.T) {
+	rules, err := overlay.ParsePlacements("eu=DE,FR")
+	if !assert.NoError(t, err) {
+		return
+	}
+	placements := overlay.NewPlacements(rules, teststore.New(), nil)
+	if !assert.NoError(t, placements.Assign(ctx, overlay.PlacementAssignment{
+		Project: macaroon.ProjectID(nil), Bucket: "photos", Placement: "eu",
+	})) {
+		return
+	}
+	cache := mockNodeCache{
+		"de": {Id: "de", Operator: &pb.NodeOperator{Country: "DE"}},
+		"us": {Id: "us", Operator: &pb.NodeOperator{Country: "US"}},
+	}
+	s := Server{DB: teststore.New(), logger: zap.NewNop(), nodes: cache, placements: placements}
+
+	put := func(path string, nodeIDs ...string) error {
+		remote := &pb.RemoteSegment{}
+		for i, nodeID := range nodeIDs {
+			remote.RemotePieces = append(remote.RemotePieces, &pb.RemotePiece{PieceNum: int32(i), NodeId: nodeID})
+		}
+		_, err := s.Put(ctx, &pb.PutRequest{Path: path, Pointer: &pb.Pointer{Type: pb.Pointer_REMOTE, Remote: remote}})
+		return err
+	}
+
+	assert.NoError(t, put("s0/photos/a", "de"))
+	// pieces uploaded outside of the placement aren't committed
+	assert.Equal(t, codes.FailedPrecondition, status.Code(put("s0/photos/b", "de", "us")))
+	assert.Equal(t, codes.FailedPrecondition, status.Code(put("s0/photos/c", "unknown")))
+	assert.NoError(t, put("s0/other/a", "us"))
+}
@@ -0,0 +1,136 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/storage"
+)
+
+// ReadOnlyState is the state of the read-only mode
+type ReadOnlyState struct {
+	Enabled bool   `json:"enabled"`
+	Reason  string `json:"reason,omitempty"`
+	By      string `json:"by,omitempty"`
+	// Since is when the mode was last switched
+	Since time.Time `json:"since"`
+}
+
+// FlagBucket is the bolt bucket of the read-only mode and the suite flags
+const FlagBucket = "flags"
+
+// readOnlyKey is the key of the ReadOnlyState in the flags
+const readOnlyKey = "read-only"
+
+// ReadOnly is the switch of the read-only mode, in which writes and
+// deletions of pointers are rejected while reads continue, e.g. during
+// database incidents or migrations. The mode is stored in a database, so
+// that switching it on one of the satellites sharing the database switches
+// it on all of them.
+type ReadOnly struct {
+	log *zap.Logger
+	db  storage.KeyValueStore
+}
+
+// NewReadOnly creates the switch of the read-only mode stored in db,
+// enabling it for reason if enabled is true. Otherwise the mode stays as it
+// was last switched.
+func NewReadOnly(log *zap.Logger, db storage.KeyValueStore, enabled bool, reason string) (*ReadOnly, error) {
+	ro := &ReadOnly{log: log, db: db}
+	if enabled {
+		if err := ro.Set(true, reason, "config"); err != nil {
+			return nil, err
+		}
+	}
+	return ro, nil
+}
+
+// Set enables or disables the read-only mode on behalf of by
+func (ro *ReadOnly) Set(enabled bool, reason, by string) error {
+	if by == "" {
+		return Error.New("read-only mode switched by nobody")
+	}
+	value, err := json.Marshal(ReadOnlyState{Enabled: enabled, Reason: reason, By: by, Since: time.Now().UTC()})
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if err := ro.db.Put(storage.Key(readOnlyKey), value); err != nil {
+		return Error.Wrap(err)
+	}
+
+	ro.log.Warn("read-only mode switched", zap.Bool("enabled", enabled),
+		zap.String("reason", reason), zap.String("by", by))
+	return nil
+}
+
+// State returns the state of the read-only mode, which is disabled if it
+// was never switched
+func (ro *ReadOnly) State() (state ReadOnlyState, err error) {
+	value, err := ro.db.Get(storage.Key(readOnlyKey))
+	if storage.ErrKeyNotFound.Has(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, Error.Wrap(err)
+	}
+	return state, Error.Wrap(json.Unmarshal(value, &state))
+}
+
+// check returns an Unavailable error if the read-only mode is enabled
+func (ro *ReadOnly) check() error {
+	if ro == nil {
+		return nil
+	}
+	state, err := ro.State()
+	if err != nil {
+		ro.log.Error("err getting read-only mode", zap.Error(err))
+		return status.Errorf(codes.Internal, err.Error())
+	}
+	if !state.Enabled {
+		return nil
+	}
+	mon.Counter("read_only_rejected").Inc(1)
+	if state.Reason == "" {
+		return status.Errorf(codes.Unavailable, "satellite is read-only, try again later")
+	}
+	return status.Errorf(codes.Unavailable, "satellite is read-only, try again later: %s", state.Reason)
+}
+
+// ServeHTTP implements the admin API of the read-only mode, mounted at
+// /pointerdb/read-only:
+//
+//	GET /pointerdb/read-only  returns the JSON ReadOnlyState
+//	PUT /pointerdb/read-only  switches the mode to the JSON ReadOnlyState
+func (ro *ReadOnly) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var state ReadOnlyState
+		if err := json.NewDecoder(req.Body).Decode(&state); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := ro.Set(state.Enabled, state.Reason, state.By); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	state, err := ro.State()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(state)
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"context"
+	"time"
+
+	"storj.io/storj/storage"
+)
+
+// RevocationBucket is the bolt bucket of the revoked API keys
+const RevocationBucket = "revocations"
+
+// Revocations stores the tails of revoked macaroon API keys
+type Revocations struct {
+	db storage.KeyValueStore
+}
+
+// NewRevocations creates the revocations stored in db
+func NewRevocations(db storage.KeyValueStore) *Revocations {
+	return &Revocations{db: db}
+}
+
+// Revoke revokes the API key with tail, and with it every key restricted
+// from it
+func (r *Revocations) Revoke(ctx context.Context, tail []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	revoked, err := time.Now().UTC().MarshalText()
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	return Error.Wrap(r.db.Put(storage.Key(tail), storage.Value(revoked)))
+}
+
+// Restore undoes the revocation of the API key with tail
+func (r *Revocations) Restore(ctx context.Context, tail []byte) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	err = r.db.Delete(storage.Key(tail))
+	if storage.ErrKeyNotFound.Has(err) {
+		return nil
+	}
+	return Error.Wrap(err)
+}
+
+// IsRevoked returns whether any of tails was revoked
+func (r *Revocations) IsRevoked(ctx context.Context, tails [][]byte) (revoked bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+	for _, tail := range tails {
+		_, err := r.db.Get(storage.Key(tail))
+		if err == nil {
+			return true, nil
+		}
+		if !storage.ErrKeyNotFound.Has(err) {
+			return false, Error.Wrap(err)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/storage"
+)
+
+// unspecifiedSuite is the name of the flag of the segments of uplinks that
+// don't record their suite yet
+const unspecifiedSuite = "unspecified"
+
+// SuiteFlag is the feature flag of a suite, which allows new segments to be
+// stored with it when enabled
+type SuiteFlag struct {
+	ID      eestream.SuiteID `json:"id"`
+	Name    string           `json:"name"`
+	Enabled bool             `json:"enabled"`
+	By      string           `json:"by,omitempty"`
+	// Since is when the flag was last switched
+	Since time.Time `json:"since"`
+}
+
+// suitePrefix is the prefix of the keys of the switched suite flags in the
+// flags
+const suitePrefix = "suite/"
+
+// suiteKey returns the key of the flag of the suite id
+func suiteKey(id eestream.SuiteID) storage.Key {
+	return storage.Key(suitePrefix + strconv.Itoa(int(id)))
+}
+
+// Suites are the feature flags of the encryption and erasure suites, which
+// control the suites new segments may be stored with, so that weak suites
+// are deprecated without touching the segments already stored with them.
+// The flags switched with Set are stored in a database, like the read-only
+// mode, and take precedence over the flags of the config.
+type Suites struct {
+	log *zap.Logger
+	db  storage.KeyValueStore
+	// defaults are the flags of the config
+	defaults map[eestream.SuiteID]SuiteFlag
+}
+
+// NewSuites creates the feature flags of the registered suites stored in
+// db, enabling the suites called by the comma separated names unless they
+// were switched since. The unspecified suite is the suite of uplinks that
+// don't record their suite yet.
+func NewSuites(log *zap.Logger, db storage.KeyValueStore, names string) (*Suites, error) {
+	now := time.Now().UTC()
+	defaults := map[eestream.SuiteID]SuiteFlag{
+		eestream.SuiteUnspecified: {ID: eestream.SuiteUnspecified, Name: unspecifiedSuite, By: "config", Since: now},
+	}
+	for _, suite := range eestream.Suites() {
+		defaults[suite.ID] = SuiteFlag{ID: suite.ID, Name: suite.Name, By: "config", Since: now}
+	}
+
+	suites := &Suites{log: log, db: db, defaults: defaults}
+	for _, name := range strings.Split(names, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		flag, ok := suites.lookup(name)
+		if !ok {
+			return nil, Error.New("unknown suite %q", name)
+		}
+		flag.Enabled = true
+		defaults[flag.ID] = flag
+	}
+	return suites, nil
+}
+
+// lookup returns the default flag of the suite called name
+func (suites *Suites) lookup(name string) (SuiteFlag, bool) {
+	for _, flag := range suites.defaults {
+		if flag.Name == name {
+			return flag, true
+		}
+	}
+	return SuiteFlag{}, false
+}
+
+// Set enables or disables the suite called name on behalf of by
+func (suites *Suites) Set(name string, enabled bool, by string) error {
+	if by == "" {
+		return Error.New("suite %s switched by nobody", name)
+	}
+	flag, ok := suites.lookup(name)
+	if !ok {
+		return Error.New("unknown suite %q", name)
+	}
+	flag.Enabled, flag.By, flag.Since = enabled, by, time.Now().UTC()
+	value, err := json.Marshal(flag)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if err := suites.db.Put(suiteKey(flag.ID), value); err != nil {
+		return Error.Wrap(err)
+	}
+
+	suites.log.Warn("suite switched", zap.String("suite", name), zap.Bool("enabled", enabled),
+		zap.String("by", by))
+	return nil
+}
+
+// Flags returns the flags of the suites ordered by id
+func (suites *Suites) Flags() ([]SuiteFlag, error) {
+	flags := make([]SuiteFlag, 0, len(suites.defaults))
+	var keys storage.Keys
+	for _, flag := range suites.defaults {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, k int) bool { return flags[i].ID < flags[k].ID })
+	for _, flag := range flags {
+		keys = append(keys, suiteKey(flag.ID))
+	}
+
+	values, err := suites.db.GetAll(keys)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	for i, value := range values {
+		if value == nil {
+			continue
+		}
+		if err := json.Unmarshal(value, &flags[i]); err != nil {
+			return nil, Error.Wrap(err)
+		}
+	}
+	return flags, nil
+}
+
+// check returns a FailedPrecondition error if new segments may not be
+// stored with the suite id
+func (suites *Suites) check(id int32) error {
+	if suites == nil {
+		return nil
+	}
+	flag, ok := suites.defaults[eestream.SuiteID(id)]
+	if ok {
+		value, err := suites.db.Get(suiteKey(flag.ID))
+		if err == nil {
+			err = json.Unmarshal(value, &flag)
+		}
+		if err != nil && !storage.ErrKeyNotFound.Has(err) {
+			suites.log.Error("err getting suite flag", zap.Error(err))
+			return status.Errorf(codes.Internal, err.Error())
+		}
+	}
+	if ok && flag.Enabled {
+		return nil
+	}
+
+	mon.Counter("suite_rejected").Inc(1)
+	if !ok {
+		return status.Errorf(codes.FailedPrecondition, "unknown suite %d", id)
+	}
+	return status.Errorf(codes.FailedPrecondition, "suite %s isn't allowed for new segments", flag.Name)
+}
+
+// ServeHTTP implements the admin API of the suites, mounted at
+// /pointerdb/suites:
+//
+//	GET /pointerdb/suites  returns the JSON SuiteFlag of every suite
+//	PUT /pointerdb/suites  switches the suite of the JSON SuiteFlag, by name
+func (suites *Suites) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPut:
+		var flag SuiteFlag
+		if err := json.NewDecoder(req.Body).Decode(&flag); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := suites.Set(flag.Name, flag.Enabled, flag.By); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flags, err := suites.Flags()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(flags)
+}
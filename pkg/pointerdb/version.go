@@ -0,0 +1,67 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package pointerdb
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"storj.io/storj/pkg/pb"
+)
+
+// PointerVersion is the version of the pointer format written by this
+// satellite
+const PointerVersion = 1
+
+// migrations upgrade pointers by one version: migrations[v] upgrades a
+// pointer of version v. To change the pointer format, bump PointerVersion
+// and append the upgrade of the previous version.
+var migrations = []func(pointer *pb.Pointer) error{
+	0: migrateUnversioned,
+}
+
+// migrateUnversioned upgrades pointers stored before the format was
+// versioned. Some of their inline pointers didn't set the size.
+func migrateUnversioned(pointer *pb.Pointer) error {
+	if pointer.Type == pb.Pointer_INLINE && pointer.Size == 0 {
+		pointer.Size = int64(len(pointer.InlineSegment))
+	}
+	return nil
+}
+
+// MarshalPointer marshals pointer in the current format
+func MarshalPointer(pointer *pb.Pointer) ([]byte, error) {
+	pointer.Version = PointerVersion
+	data, err := proto.Marshal(pointer)
+	return data, Error.Wrap(err)
+}
+
+// UnmarshalPointer unmarshals a stored pointer and upgrades it to the current
+// format. migrated is whether it was stored in an older format, so it can be
+// written back.
+func UnmarshalPointer(data []byte) (pointer *pb.Pointer, migrated bool, err error) {
+	pointer = &pb.Pointer{}
+	if err := proto.Unmarshal(data, pointer); err != nil {
+		return nil, false, Error.Wrap(err)
+	}
+	migrated, err = migratePointer(pointer)
+	if err != nil {
+		return nil, false, err
+	}
+	return pointer, migrated, nil
+}
+
+// migratePointer upgrades pointer to the current format
+func migratePointer(pointer *pb.Pointer) (migrated bool, err error) {
+	if pointer.Version < 0 || pointer.Version > PointerVersion {
+		return false, Error.New("unsupported pointer version %d, the supported version is %d", pointer.Version, PointerVersion)
+	}
+	for pointer.Version < PointerVersion {
+		if err := migrations[pointer.Version](pointer); err != nil {
+			return false, Error.Wrap(err)
+		}
+		pointer.Version++
+		migrated = true
+	}
+	return migrated, nil
+}
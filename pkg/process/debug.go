@@ -18,6 +18,8 @@ import (
 var (
 	debugAddr = flag.String("debug.addr", "localhost:0",
 		"address to listen on for debug endpoints")
+
+	debugMux http.ServeMux
 )
 
 func init() {
@@ -26,15 +28,21 @@ func init() {
 	*http.DefaultServeMux = http.ServeMux{}
 }
 
+// HandleDebug registers handler for pattern on the debug endpoints
+func HandleDebug(pattern string, handler http.Handler) {
+	debugMux.Handle(pattern, handler)
+}
+
 func initDebug(logger *zap.Logger, r *monkit.Registry) (
 	err error) {
-	var mux http.ServeMux
+	mux := &debugMux
 	mux.HandleFunc("/debug/pprof/", pprof.Index)
 	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
 	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
 	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
 	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
 	mux.Handle("/mon/", http.StripPrefix("/mon", present.HTTP(r)))
+	mux.Handle("/log/level", levels)
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = fmt.Fprintln(w, "OK")
 	})
@@ -43,7 +51,7 @@ func initDebug(logger *zap.Logger, r *monkit.Registry) (
 		return err
 	}
 	go func() {
-		err := (&http.Server{Handler: &mux}).Serve(ln)
+		err := (&http.Server{Handler: mux}).Serve(ln)
 		if err != nil {
 			logger.Error("debug server died", zap.Error(err))
 		}
@@ -140,7 +140,9 @@ func cleanup(cmd *cobra.Command) {
 		}
 		defer func() { _ = logger.Sync() }()
 		defer zap.ReplaceGlobals(logger)()
-		defer zap.RedirectStdLog(logger)()
+		// the standard library logger is used by the piece store, whose
+		// level can be set with the name "stdlog"
+		defer zap.RedirectStdLog(logger.Named("stdlog"))()
 
 		logger.Debug("logging initialized")
 
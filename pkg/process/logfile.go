@@ -0,0 +1,145 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"storj.io/storj/pkg/utils"
+)
+
+var (
+	logMaxSize = flag.Int64("log.max-size", 0,
+		"rotate the log file once it's larger than this many bytes. 0 disables rotating by size")
+	logMaxAge = flag.Duration("log.max-age", 0,
+		"rotate the log file once it's been written to for this long. 0 disables rotating by age")
+	logMaxBackups = flag.Int("log.max-backups", 5,
+		"how many rotated log files to keep. 0 keeps all of them")
+)
+
+// openLogOutput opens the log output, which is rotated if it's a file and
+// rotating is enabled
+func openLogOutput(output string) (zapcore.WriteSyncer, error) {
+	if output == "stdout" || output == "stderr" || (*logMaxSize <= 0 && *logMaxAge <= 0) {
+		sink, _, err := zap.Open(output)
+		return sink, err
+	}
+	return openRotatingFile(output, *logMaxSize, *logMaxAge, *logMaxBackups)
+}
+
+// rotatingFile is a log file that is renamed and replaced with a new one
+// once it gets too large or too old. The rotated files are named after the
+// file with the time of the rotation appended.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+func openRotatingFile(path string, maxSize int64, maxAge time.Duration, maxBackups int) (*rotatingFile, error) {
+	f := &rotatingFile{
+		path:       path,
+		maxSize:    maxSize,
+		maxAge:     maxAge,
+		maxBackups: maxBackups,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the log file, appending to it if it exists
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return Error.Wrap(utils.CombineErrors(err, file.Close()))
+	}
+	f.file = file
+	f.size = info.Size()
+	f.opened = time.Now()
+	return nil
+}
+
+// Write implements io.Writer, rotating the file first if needed
+func (f *rotatingFile) Write(p []byte) (n int, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	tooLarge := f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize
+	tooOld := f.maxAge > 0 && time.Since(f.opened) >= f.maxAge
+	if tooLarge || tooOld {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err = f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Sync implements zapcore.WriteSyncer
+func (f *rotatingFile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Sync()
+}
+
+// Close closes the current log file
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+// rotate renames the current log file, opens a new one and removes the
+// oldest rotated files
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return Error.Wrap(err)
+	}
+	rotated := f.path + "." + time.Now().UTC().Format("20060102T150405.000000000")
+	if err := os.Rename(f.path, rotated); err != nil {
+		return Error.Wrap(err)
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	if f.maxBackups <= 0 {
+		return nil
+	}
+	backups, err := filepath.Glob(f.path + ".*")
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	// the appended times sort in the order the files were rotated in
+	sort.Strings(backups)
+	for len(backups) > f.maxBackups {
+		if err := os.Remove(backups[0]); err != nil {
+			return Error.Wrap(err)
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
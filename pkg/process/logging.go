@@ -4,7 +4,11 @@
 package process
 
 import (
+	"encoding/json"
 	"flag"
+	"net/http"
+	"strings"
+	"sync"
 
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
@@ -16,35 +20,231 @@ var (
 	Error = errs.Class("process error")
 
 	logLevel    = zap.LevelFlag("log.level", zapcore.WarnLevel, "the minimum log level to log")
+	logLevels   = flag.String("log.levels", "", "minimum log levels of subsystems, overriding log.level, e.g. 'kademlia=debug,pointerdb=info'")
 	logDev      = flag.Bool("log.development", false, "if true, set logging to development mode")
 	logCaller   = flag.Bool("log.caller", false, "if true, log function filename and line number")
 	logStack    = flag.Bool("log.stack", false, "if true, log stack traces")
 	logEncoding = flag.String("log.encoding", "console", "configures log encoding. can either be 'console' or 'json'")
 	logOutput   = flag.String("log.output", "stderr",
 		"can be stdout, stderr, or a filename")
+
+	// levels are the log levels of the running process, which can be
+	// changed at /log/level on the debug endpoints
+	levels = newLevelOverrides()
 )
 
 func newLogger() (*zap.Logger, error) {
-	return zap.Config{
-		Level:             zap.NewAtomicLevelAt(*logLevel),
-		Development:       *logDev,
-		DisableCaller:     !*logCaller,
-		DisableStacktrace: !*logStack,
-		Encoding:          *logEncoding,
-		EncoderConfig: zapcore.EncoderConfig{
-			TimeKey:        "T",
-			LevelKey:       "L",
-			NameKey:        "N",
-			CallerKey:      "C",
-			MessageKey:     "M",
-			StacktraceKey:  "S",
-			LineEnding:     zapcore.DefaultLineEnding,
-			EncodeLevel:    zapcore.CapitalColorLevelEncoder,
-			EncodeTime:     zapcore.ISO8601TimeEncoder,
-			EncodeDuration: zapcore.StringDurationEncoder,
-			EncodeCaller:   zapcore.ShortCallerEncoder,
-		},
-		OutputPaths:      []string{*logOutput},
-		ErrorOutputPaths: []string{*logOutput},
-	}.Build()
+	levels.level.SetLevel(*logLevel)
+	if err := levels.parse(*logLevels); err != nil {
+		return nil, err
+	}
+
+	console := *logOutput == "stdout" || *logOutput == "stderr"
+	encoderConfig := zapcore.EncoderConfig{
+		TimeKey:        "T",
+		LevelKey:       "L",
+		NameKey:        "N",
+		CallerKey:      "C",
+		MessageKey:     "M",
+		StacktraceKey:  "S",
+		LineEnding:     zapcore.DefaultLineEnding,
+		EncodeLevel:    zapcore.CapitalLevelEncoder,
+		EncodeTime:     zapcore.ISO8601TimeEncoder,
+		EncodeDuration: zapcore.StringDurationEncoder,
+		EncodeCaller:   zapcore.ShortCallerEncoder,
+	}
+
+	var encoder zapcore.Encoder
+	switch *logEncoding {
+	case "console":
+		if console {
+			encoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
+		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	case "json":
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	default:
+		return nil, Error.New("unknown log encoding %q", *logEncoding)
+	}
+
+	output, err := openLogOutput(*logOutput)
+	if err != nil {
+		return nil, err
+	}
+
+	core := &levelCore{
+		Core:   zapcore.NewCore(encoder, output, zapcore.DebugLevel),
+		levels: levels,
+	}
+
+	opts := []zap.Option{zap.ErrorOutput(output)}
+	if *logDev {
+		opts = append(opts, zap.Development())
+	}
+	if *logCaller {
+		opts = append(opts, zap.AddCaller())
+	}
+	if *logStack {
+		opts = append(opts, zap.AddStacktrace(zapcore.ErrorLevel))
+	}
+	return zap.New(core, opts...), nil
+}
+
+// levelOverrides is the minimum log level, with overrides for named loggers
+// and the loggers named below them
+type levelOverrides struct {
+	level zap.AtomicLevel
+
+	mu        sync.RWMutex
+	overrides map[string]zapcore.Level
+}
+
+func newLevelOverrides() *levelOverrides {
+	return &levelOverrides{
+		level:     zap.NewAtomicLevel(),
+		overrides: map[string]zapcore.Level{},
+	}
+}
+
+// parse replaces the overrides with the ones in a list like
+// 'kademlia=debug,pointerdb=info'
+func (l *levelOverrides) parse(list string) error {
+	overrides := map[string]zapcore.Level{}
+	for _, override := range strings.Split(list, ",") {
+		if strings.TrimSpace(override) == "" {
+			continue
+		}
+		parts := strings.SplitN(override, "=", 2)
+		if len(parts) != 2 {
+			return Error.New("invalid log level override %q", override)
+		}
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(strings.TrimSpace(parts[1]))); err != nil {
+			return Error.Wrap(err)
+		}
+		overrides[strings.TrimSpace(parts[0])] = level
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.overrides = overrides
+	return nil
+}
+
+// set sets the level of the logger name and the loggers below it, or the
+// minimum level if name is empty
+func (l *levelOverrides) set(name string, level zapcore.Level) {
+	if name == "" {
+		l.level.SetLevel(level)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.overrides[name] = level
+}
+
+// reset removes the override of the logger name
+func (l *levelOverrides) reset(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.overrides, name)
+}
+
+// enabled returns whether the logger name logs at level. The override of
+// the closest name wins, so "kademlia" applies to "kademlia.routing".
+func (l *levelOverrides) enabled(name string, level zapcore.Level) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	for name != "" {
+		if override, ok := l.overrides[name]; ok {
+			return override.Enabled(level)
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return l.level.Enabled(level)
+}
+
+// minimum returns the lowest level any logger logs at
+func (l *levelOverrides) minimum() zapcore.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	minimum := l.level.Level()
+	for _, level := range l.overrides {
+		if level < minimum {
+			minimum = level
+		}
+	}
+	return minimum
+}
+
+// ServeHTTP shows the log levels on GET and changes them on PUT or POST,
+// with the form values name and level. An empty name changes the minimum
+// level and an empty level removes the override of name.
+func (l *levelOverrides) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPut, http.MethodPost:
+		name, text := r.FormValue("name"), r.FormValue("level")
+		if text == "" && name != "" {
+			l.reset(name)
+			break
+		}
+		var level zapcore.Level
+		if err := level.UnmarshalText([]byte(text)); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		l.set(name, level)
+		zap.S().Infof("Log level of %q set to %s", name, level)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	l.mu.RLock()
+	status := struct {
+		Level     string            `json:"level"`
+		Overrides map[string]string `json:"overrides"`
+	}{
+		Level:     l.level.Level().String(),
+		Overrides: map[string]string{},
+	}
+	for name, level := range l.overrides {
+		status.Overrides[name] = level.String()
+	}
+	l.mu.RUnlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(status)
+}
+
+// levelCore filters the entries of a core by the level of their logger
+type levelCore struct {
+	zapcore.Core
+	levels *levelOverrides
+}
+
+// Enabled implements zapcore.Core
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return c.levels.minimum().Enabled(level)
+}
+
+// With implements zapcore.Core
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields), levels: c.levels}
+}
+
+// Check implements zapcore.Core
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.levels.enabled(entry.LoggerName, entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
 }
@@ -0,0 +1,82 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package process
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogLevels(t *testing.T) {
+	levels := newLevelOverrides()
+	levels.level.SetLevel(zapcore.WarnLevel)
+	assert.NoError(t, levels.parse("kademlia=debug, kademlia.routing=error"))
+	assert.Error(t, levels.parse("kademlia"))
+
+	assert.False(t, levels.enabled("", zapcore.InfoLevel))
+	assert.True(t, levels.enabled("pointerdb", zapcore.WarnLevel))
+	assert.True(t, levels.enabled("kademlia", zapcore.DebugLevel))
+	assert.True(t, levels.enabled("kademlia.bucket", zapcore.DebugLevel))
+	assert.False(t, levels.enabled("kademlia.routing", zapcore.WarnLevel))
+	assert.False(t, levels.enabled("kademliax", zapcore.InfoLevel))
+	assert.Equal(t, zapcore.DebugLevel, levels.minimum())
+
+	set := func(name, level string) int {
+		req := httptest.NewRequest(http.MethodPost, "/log/level",
+			strings.NewReader(url.Values{"name": {name}, "level": {level}}.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		levels.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, set("pointerdb", "info"))
+	assert.True(t, levels.enabled("pointerdb", zapcore.InfoLevel))
+	assert.Equal(t, http.StatusOK, set("pointerdb", ""))
+	assert.False(t, levels.enabled("pointerdb", zapcore.InfoLevel))
+	assert.Equal(t, http.StatusOK, set("", "error"))
+	assert.False(t, levels.enabled("pointerdb", zapcore.WarnLevel))
+	assert.Equal(t, http.StatusBadRequest, set("", "loud"))
+}
+
+func TestRotatingFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storj-logging")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	path := filepath.Join(dir, "node.log")
+	f, err := openRotatingFile(path, 10, 0, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, f.Close()) }()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := f.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, f.Sync())
+
+	current, err := ioutil.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "fourth\n", string(current))
+
+	backups, err := filepath.Glob(path + ".*")
+	assert.NoError(t, err)
+	if assert.Len(t, backups, 2) {
+		previous, err := ioutil.ReadFile(backups[1])
+		assert.NoError(t, err)
+		assert.Equal(t, "third\n", string(previous))
+	}
+}
@@ -26,6 +26,12 @@ var (
 		"application name for telemetry identification")
 	metricAppSuffix = flag.String("metrics.app_suffix", "-dev",
 		"application suffix")
+	metricSpoolDir = flag.String("metrics.spool-dir", "",
+		"if set, telemetry that can't be sent is kept in this directory and sent once the collector is reachable")
+	metricSpoolSize = flag.Int64("metrics.spool-size", telemetry.DefaultSpoolSize,
+		"how many bytes of telemetry to keep in the spool")
+	metricSpoolAge = flag.Duration("metrics.spool-age", telemetry.DefaultSpoolAge,
+		"how long to keep telemetry in the spool")
 )
 
 func initMetrics(ctx context.Context, r *monkit.Registry, instanceID string) (
@@ -33,12 +39,20 @@ func initMetrics(ctx context.Context, r *monkit.Registry, instanceID string) (
 	if *metricCollector == "" || *metricInterval == 0 {
 		return Error.New("telemetry disabled")
 	}
+	var spool *telemetry.Spool
+	if *metricSpoolDir != "" {
+		spool, err = telemetry.NewSpool(os.ExpandEnv(*metricSpoolDir), *metricSpoolSize, *metricSpoolAge)
+		if err != nil {
+			return err
+		}
+	}
 	c, err := telemetry.NewClient(*metricCollector, telemetry.ClientOpts{
 		Interval:      *metricInterval,
 		Application:   *metricApp + *metricAppSuffix,
 		Instance:      instanceID,
 		Registry:      r,
 		FloatEncoding: admproto.Float32Encoding,
+		Spool:         spool,
 	})
 	if err != nil {
 		return err
@@ -7,7 +7,9 @@ import (
 	"context"
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/rand"
 	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"io/ioutil"
 	"os"
@@ -23,6 +25,9 @@ import (
 type PeerCertificateAuthority struct {
 	// Cert is the x509 certificate of the CA
 	Cert *x509.Certificate
+	// RestChain is the rest of the chain after the CA, if it was signed by
+	// another one
+	RestChain []*x509.Certificate
 	// The ID is calculated from the CA public key.
 	ID nodeID
 }
@@ -31,6 +36,9 @@ type PeerCertificateAuthority struct {
 type FullCertificateAuthority struct {
 	// Cert is the x509 certificate of the CA
 	Cert *x509.Certificate
+	// RestChain is the rest of the chain after the CA, if it was signed by
+	// another one
+	RestChain []*x509.Certificate
 	// The ID is calculated from the CA public key.
 	ID nodeID
 	// Key is the private key of the CA
@@ -103,9 +111,10 @@ func (fc FullCAConfig) Load() (*FullCertificateAuthority, error) {
 	}
 
 	return &FullCertificateAuthority{
-		Cert: p.Cert,
-		Key:  k,
-		ID:   p.ID,
+		Cert:      p.Cert,
+		RestChain: p.RestChain,
+		Key:       k,
+		ID:        p.ID,
 	}, nil
 }
 
@@ -138,14 +147,15 @@ func (pc PeerCAConfig) Load() (*PeerCertificateAuthority, error) {
 			pc.CertPath, err)
 	}
 
-	i, err := idFromKey(c[len(c)-1].PublicKey)
+	i, err := idFromKey(c[0].PublicKey)
 	if err != nil {
 		return nil, err
 	}
 
 	return &PeerCertificateAuthority{
-		Cert: c[0],
-		ID:   i,
+		Cert:      c[0],
+		RestChain: c[1:],
+		ID:        i,
 	}, nil
 }
 
@@ -186,7 +196,7 @@ func (fc FullCAConfig) Save(ca *FullCertificateAuthority) error {
 	}
 	defer utils.LogClose(k)
 
-	if err = peertls.WriteChain(c, ca.Cert); err != nil {
+	if err = peertls.WriteChain(c, append([]*x509.Certificate{ca.Cert}, ca.RestChain...)...); err != nil {
 		return err
 	}
 	if err = peertls.WriteKey(k, ca.Key); err != nil {
@@ -197,12 +207,13 @@ func (fc FullCAConfig) Save(ca *FullCertificateAuthority) error {
 
 // NewIdentity generates a new `FullIdentity` based on the CA. The CA
 // cert is included in the identity's cert chain and the identity's leaf cert
-// is signed by the CA.
-func (ca FullCertificateAuthority) NewIdentity() (*FullIdentity, error) {
+// is signed by the CA. The leaf cert includes the given extensions.
+func (ca FullCertificateAuthority) NewIdentity(extensions ...pkix.Extension) (*FullIdentity, error) {
 	lT, err := peertls.LeafTemplate()
 	if err != nil {
 		return nil, err
 	}
+	lT.ExtraExtensions = extensions
 	k, err := peertls.NewKey()
 	if err != nil {
 		return nil, err
@@ -217,9 +228,55 @@ func (ca FullCertificateAuthority) NewIdentity() (*FullIdentity, error) {
 	}
 
 	return &FullIdentity{
-		CA:   ca.Cert,
-		Leaf: l,
-		Key:  k,
-		ID:   ca.ID,
+		CA:        ca.Cert,
+		Leaf:      l,
+		RestChain: ca.RestChain,
+		Key:       k,
+		ID:        ca.ID,
 	}, nil
 }
+
+// RotateIdentity generates a new `FullIdentity` based on the CA to replace
+// old, whose leaf is revoked. The revocation is carried by the new leaf, so
+// peers learn about it when they verify the new leaf.
+func (ca FullCertificateAuthority) RotateIdentity(old *FullIdentity) (*FullIdentity, error) {
+	if old.ID != ca.ID {
+		return nil, Error.New("identity %s isn't of CA %s", old.ID, ca.ID)
+	}
+	rev, err := peertls.NewRevocation(old.Leaf, ca.Key)
+	if err != nil {
+		return nil, err
+	}
+	ext, err := rev.Extension()
+	if err != nil {
+		return nil, err
+	}
+	return ca.NewIdentity(ext)
+}
+
+// CertificateRequest creates a DER-encoded certificate signing request for
+// the CA, which proves the possession of its key to a signer
+func (ca FullCertificateAuthority) CertificateRequest() ([]byte, error) {
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, ca.Key)
+	return csr, errs.Wrap(err)
+}
+
+// SetSignedChain replaces the self-signed certificate of the CA with the
+// chain returned by a signer, after checking that it certifies the CA key
+func (ca *FullCertificateAuthority) SetSignedChain(chain []*x509.Certificate) error {
+	if len(chain) < 2 {
+		return Error.New("signed chain is too short")
+	}
+	id, err := idFromKey(chain[0].PublicKey)
+	if err != nil {
+		return err
+	}
+	if id != ca.ID {
+		return Error.New("signed certificate is for %s, not %s", id, ca.ID)
+	}
+	if err := peertls.VerifyPeerCertChains(nil, [][]*x509.Certificate{chain}); err != nil {
+		return err
+	}
+	ca.Cert, ca.RestChain = chain[0], chain[1:]
+	return nil
+}
@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"net"
 	"os"
+	"strings"
 
 	"github.com/zeebo/errs"
 	"go.uber.org/zap"
@@ -52,8 +53,17 @@ type FullIdentity struct {
 	Leaf *x509.Certificate
 	// The ID taken from the CA public key
 	ID nodeID
+	// RestChain is the rest of the chain after the CA, if the CA was
+	// signed by another one, ending with the self-signed root.
+	RestChain []*x509.Certificate
 	// Key is the key this identity uses with the leaf for communication.
 	Key crypto.PrivateKey
+	// Revocations, if set, is the revocation database the leaves of peers
+	// are checked against, and the revocations they carry recorded in.
+	Revocations *RevocationDB
+	// Whitelist, if set, restricts the peers to the peers of a private
+	// network
+	Whitelist *Whitelist
 }
 
 // IdentitySetupConfig allows you to run a set of Responsibilities with the given
@@ -71,6 +81,11 @@ type IdentityConfig struct {
 	CertPath string `help:"path to the certificate chain for this identity" default:"$CONFDIR/identity.cert"`
 	KeyPath  string `help:"path to the private key for this identity" default:"$CONFDIR/identity.key"`
 	Address  string `help:"address to listen on" default:":7777"`
+
+	RevocationDBURL string `help:"url of the database of revoked peer certificates. if empty, revocations aren't checked" default:"bolt://$CONFDIR/revocations.db"`
+
+	PeerCAWhitelistPath string `help:"path to the PEM encoded certificate authorities that peers must be signed by, for private networks. if neither this nor peer-id-whitelist is set, any peer is accepted" default:""`
+	PeerIDWhitelist     string `help:"comma-separated ids of the peers that are accepted without being signed by a whitelisted certificate authority" default:""`
 }
 
 // FullIdentityFromPEM loads a FullIdentity from a certificate chain and
@@ -103,10 +118,11 @@ func FullIdentityFromPEM(chainPEM, keyPEM []byte) (*FullIdentity, error) {
 	}
 
 	return &FullIdentity{
-		CA:   ch[1],
-		Leaf: ch[0],
-		Key:  k,
-		ID:   i,
+		CA:        ch[1],
+		Leaf:      ch[0],
+		RestChain: ch[2:],
+		Key:       k,
+		ID:        i,
 	}, nil
 }
 
@@ -143,7 +159,16 @@ func PeerIdentityFromContext(ctx context.Context) (*PeerIdentity, error) {
 	if !ok {
 		return nil, Error.New("unable to get grpc peer from contex")
 	}
-	tlsInfo := p.AuthInfo.(credentials.TLSInfo)
+	return PeerIdentityFromPeer(p)
+}
+
+// PeerIdentityFromPeer loads a PeerIdentity from a grpc peer's TLS
+// credentials, such as the peer of a client call made with grpc.Peer
+func PeerIdentityFromPeer(p *peer.Peer) (*PeerIdentity, error) {
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, Error.New("peer is not authenticated with TLS")
+	}
 	c := tlsInfo.State.PeerCertificates
 	if len(c) < 2 {
 		return nil, Error.New("invalid certificate chain")
@@ -168,6 +193,7 @@ func (is IdentitySetupConfig) Create(ca *FullCertificateAuthority) (*FullIdentit
 		return nil, err
 	}
 	fi.CA = ca.Cert
+	fi.RestChain = ca.RestChain
 	ic := IdentityConfig{
 		CertPath: is.CertPath,
 		KeyPath:  is.KeyPath,
@@ -208,7 +234,8 @@ func (ic IdentityConfig) Save(fi *FullIdentity) error {
 	}
 	defer utils.LogClose(k)
 
-	if err = peertls.WriteChain(c, fi.Leaf, fi.CA); err != nil {
+	chain := append([]*x509.Certificate{fi.Leaf, fi.CA}, fi.RestChain...)
+	if err = peertls.WriteChain(c, chain...); err != nil {
 		return err
 	}
 	if err = peertls.WriteKey(k, fi.Key); err != nil {
@@ -228,6 +255,27 @@ func (ic IdentityConfig) Run(ctx context.Context,
 		return err
 	}
 
+	if ic.RevocationDBURL != "" {
+		pi.Revocations, err = OpenRevocationDB(ic.RevocationDBURL)
+		if err != nil {
+			return err
+		}
+		defer func() { err = utils.CombineErrors(err, pi.Revocations.Close()) }()
+	}
+
+	if ic.PeerCAWhitelistPath != "" || ic.PeerIDWhitelist != "" {
+		var ids []string
+		if ic.PeerIDWhitelist != "" {
+			ids = strings.Split(ic.PeerIDWhitelist, ",")
+		}
+		pi.Whitelist, err = LoadWhitelist(ic.PeerCAWhitelistPath, ids)
+		if err != nil {
+			return err
+		}
+		zap.S().Infof("Private network mode: %d whitelisted certificate authorities, %d whitelisted peers",
+			len(pi.Whitelist.CAs), len(pi.Whitelist.IDs))
+	}
+
 	lis, err := net.Listen("tcp", ic.Address)
 	if err != nil {
 		return err
@@ -248,42 +296,68 @@ func (ic IdentityConfig) Run(ctx context.Context,
 // ServerOption returns a grpc `ServerOption` for incoming connections
 // to the node with this full identity
 func (fi *FullIdentity) ServerOption() (grpc.ServerOption, error) {
-	ch := [][]byte{fi.Leaf.Raw, fi.CA.Raw}
-	c, err := peertls.TLSCert(ch, fi.Leaf, fi.Key)
+	c, err := peertls.TLSCert(fi.Chain(), fi.Leaf, fi.Key)
 	if err != nil {
 		return nil, err
 	}
 
 	tlsConfig := &tls.Config{
-		Certificates:       []tls.Certificate{*c},
-		InsecureSkipVerify: true,
-		ClientAuth:         tls.RequireAnyClientCert,
-		VerifyPeerCertificate: peertls.VerifyPeerFunc(
-			peertls.VerifyPeerCertChains,
-		),
+		Certificates:          []tls.Certificate{*c},
+		InsecureSkipVerify:    true,
+		ClientAuth:            tls.RequireAnyClientCert,
+		VerifyPeerCertificate: fi.verifyPeer(),
 	}
 
 	return grpc.Creds(credentials.NewTLS(tlsConfig)), nil
 }
 
+// Chain returns the DER-encoded certificate chain of the identity, starting
+// with the leaf
+func (fi *FullIdentity) Chain() [][]byte {
+	chain := [][]byte{fi.Leaf.Raw, fi.CA.Raw}
+	for _, cert := range fi.RestChain {
+		chain = append(chain, cert.Raw)
+	}
+	return chain
+}
+
+// verifyPeer returns the verification of the certificates of peers
+func (fi *FullIdentity) verifyPeer() peertls.PeerCertVerificationFunc {
+	verifications := []peertls.PeerCertVerificationFunc{peertls.VerifyPeerCertChains}
+	if fi.Whitelist != nil {
+		verifications = append(verifications, fi.Whitelist.VerifyPeer)
+	}
+	if fi.Revocations != nil {
+		verifications = append(verifications, fi.Revocations.VerifyPeer)
+	}
+	return peertls.VerifyPeerFunc(verifications...)
+}
+
 // DialOption returns a grpc `DialOption` for making outgoing connections
 // to the node with this peer identity
 func (fi *FullIdentity) DialOption() (grpc.DialOption, error) {
-	ch := [][]byte{fi.Leaf.Raw, fi.CA.Raw}
-	c, err := peertls.TLSCert(ch, fi.Leaf, fi.Key)
+	creds, err := fi.TransportCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return grpc.WithTransportCredentials(creds), nil
+}
+
+// TransportCredentials returns the TLS credentials of the outgoing
+// connections made with this identity
+func (fi *FullIdentity) TransportCredentials() (credentials.TransportCredentials, error) {
+	c, err := peertls.TLSCert(fi.Chain(), fi.Leaf, fi.Key)
 	if err != nil {
 		return nil, err
 	}
 
 	tlsConfig := &tls.Config{
-		Certificates:       []tls.Certificate{*c},
-		InsecureSkipVerify: true,
-		VerifyPeerCertificate: peertls.VerifyPeerFunc(
-			peertls.VerifyPeerCertChains,
-		),
+		Certificates:          []tls.Certificate{*c},
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: fi.verifyPeer(),
 	}
 
-	return grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)), nil
+	return credentials.NewTLS(tlsConfig), nil
 }
 
 type nodeID string
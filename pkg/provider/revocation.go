@@ -0,0 +1,108 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package provider
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+
+	"storj.io/storj/pkg/peertls"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/boltdb"
+)
+
+// RevocationBucket is the bolt bucket of the revocation database
+const RevocationBucket = "revocations"
+
+// RevocationDB stores the revocations of leaf certificates, keyed by the
+// CA and the hash of the revoked leaf
+type RevocationDB struct {
+	DB storage.KeyValueStore
+}
+
+// NewRevocationDB creates a RevocationDB stored in db
+func NewRevocationDB(db storage.KeyValueStore) *RevocationDB {
+	return &RevocationDB{DB: db}
+}
+
+// OpenRevocationDB opens the revocation database at a bolt://PATH url
+func OpenRevocationDB(rawurl string) (*RevocationDB, error) {
+	u, err := utils.ParseURL(rawurl)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if u.Scheme != "bolt" {
+		return nil, Error.New("unsupported revocation database %q", rawurl)
+	}
+	db, err := boltdb.New(u.Path, RevocationBucket)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return NewRevocationDB(db), nil
+}
+
+// Close closes the database
+func (db *RevocationDB) Close() error {
+	return db.DB.Close()
+}
+
+func revocationKey(ca *x509.Certificate, certHash []byte) storage.Key {
+	caHash := sha256.Sum256(ca.Raw)
+	return storage.Key(append(caHash[:], certHash...))
+}
+
+// Put stores rev after checking that it's signed by ca
+func (db *RevocationDB) Put(ca *x509.Certificate, rev *peertls.Revocation) error {
+	if err := rev.Verify(ca); err != nil {
+		return err
+	}
+	data, err := rev.Marshal()
+	if err != nil {
+		return err
+	}
+	return Error.Wrap(db.DB.Put(revocationKey(ca, rev.CertHash), data))
+}
+
+// IsRevoked returns whether the leaf of ca was revoked
+func (db *RevocationDB) IsRevoked(ca, leaf *x509.Certificate) (bool, error) {
+	hash := sha256.Sum256(leaf.Raw)
+	value, err := db.DB.Get(revocationKey(ca, hash[:]))
+	if storage.ErrKeyNotFound.Has(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	return !value.IsZero(), nil
+}
+
+// VerifyPeer is a peertls.PeerCertVerificationFunc that records the
+// revocation carried by the peer's leaf and rejects revoked leaves
+func (db *RevocationDB) VerifyPeer(_ [][]byte, parsedChains [][]*x509.Certificate) error {
+	chain := parsedChains[0]
+	if len(chain) < 2 {
+		return peertls.ErrVerifyPeerCert.New("invalid certificate chain")
+	}
+	leaf, ca := chain[0], chain[1]
+
+	rev, err := peertls.RevocationFromCert(leaf)
+	if err != nil {
+		return peertls.ErrVerifyPeerCert.Wrap(err)
+	}
+	if rev != nil {
+		if err := db.Put(ca, rev); err != nil {
+			return peertls.ErrVerifyPeerCert.Wrap(err)
+		}
+	}
+
+	revoked, err := db.IsRevoked(ca, leaf)
+	if err != nil {
+		return err
+	}
+	if revoked {
+		return peertls.ErrRevoked.New("leaf of %s", leaf.SerialNumber)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/peertls"
+	"storj.io/storj/storage/teststore"
+)
+
+func TestRotateIdentity(t *testing.T) {
+	ca, err := NewCA(context.Background(), 12, 4)
+	assert.NoError(t, err)
+	old, err := ca.NewIdentity()
+	assert.NoError(t, err)
+
+	rotated, err := ca.RotateIdentity(old)
+	assert.NoError(t, err)
+	assert.Equal(t, old.ID, rotated.ID)
+
+	rev, err := peertls.RevocationFromCert(rotated.Leaf)
+	assert.NoError(t, err)
+	if assert.NotNil(t, rev) {
+		assert.NoError(t, rev.Verify(ca.Cert))
+		assert.True(t, rev.Revokes(old.Leaf))
+		assert.False(t, rev.Revokes(rotated.Leaf))
+	}
+
+	db := NewRevocationDB(teststore.New())
+	verify := func(fi *FullIdentity) error {
+		return peertls.VerifyPeerFunc(peertls.VerifyPeerCertChains, db.VerifyPeer)(
+			[][]byte{fi.Leaf.Raw, fi.CA.Raw}, nil)
+	}
+
+	assert.NoError(t, verify(old))
+	assert.NoError(t, verify(rotated))
+	assert.True(t, peertls.ErrRevoked.Has(verify(old)))
+
+	other, err := NewCA(context.Background(), 12, 4)
+	assert.NoError(t, err)
+	_, err = other.RotateIdentity(old)
+	assert.Error(t, err)
+}
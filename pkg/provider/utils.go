@@ -115,6 +115,16 @@ func newCAWorker(ctx context.Context, difficulty uint16, caC chan FullCertificat
 	caC <- ca
 }
 
+// NodeIDFromKey returns the node id and its difficulty for the public key of
+// a certificate authority
+func NodeIDFromKey(k crypto.PublicKey) (id string, difficulty uint16, err error) {
+	i, err := idFromKey(k)
+	if err != nil {
+		return "", 0, err
+	}
+	return i.String(), i.Difficulty(), nil
+}
+
 func idFromKey(k crypto.PublicKey) (nodeID, error) {
 	kb, err := x509.MarshalPKIXPublicKey(k)
 	if err != nil {
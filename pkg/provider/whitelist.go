@@ -0,0 +1,96 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package provider
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/peertls"
+)
+
+// ErrNotWhitelisted is returned for peers that aren't on the whitelist
+var ErrNotWhitelisted = errs.Class("peer not whitelisted")
+
+// Whitelist restricts the peers of a private network to the peers whose
+// identities are signed by one of its certificate authorities, or whose ids
+// it lists
+type Whitelist struct {
+	CAs []*x509.Certificate
+	IDs map[string]bool
+}
+
+// NewWhitelist returns the whitelist of the peers signed by cas and of the
+// peers with ids
+func NewWhitelist(cas []*x509.Certificate, ids []string) *Whitelist {
+	w := &Whitelist{CAs: cas, IDs: map[string]bool{}}
+	for _, id := range ids {
+		w.IDs[id] = true
+	}
+	return w
+}
+
+// LoadWhitelist loads the whitelist of the peers signed by the certificate
+// authorities in the PEM file at caPath, if not empty, and of the peers with
+// ids
+func LoadWhitelist(caPath string, ids []string) (*Whitelist, error) {
+	var cas []*x509.Certificate
+	if caPath != "" {
+		data, err := ioutil.ReadFile(caPath)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		raw, err := decodePEM(data)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		cas, err = ParseCertChain(raw)
+		if err != nil {
+			return nil, Error.Wrap(err)
+		}
+		if len(cas) == 0 {
+			return nil, Error.New("no certificate authorities in %s", caPath)
+		}
+	}
+	return NewWhitelist(cas, ids), nil
+}
+
+// Listed returns whether the peer with id may be on the whitelist, as far as
+// can be told from its id alone. The peers signed by a certificate authority
+// of the whitelist can't be told from their id, so they are only checked
+// when connecting to them.
+func (w *Whitelist) Listed(id string) bool {
+	return w == nil || len(w.CAs) > 0 || w.IDs[id]
+}
+
+// VerifyPeer is a peertls.PeerCertVerificationFunc that rejects the peers
+// that aren't on the whitelist. It relies on peertls.VerifyPeerCertChains
+// having checked the signatures of the chain.
+func (w *Whitelist) VerifyPeer(_ [][]byte, parsedChains [][]*x509.Certificate) error {
+	chain := parsedChains[0]
+	if len(chain) < 2 {
+		return peertls.ErrVerifyPeerCert.New("invalid certificate chain")
+	}
+
+	id, err := idFromKey(chain[1].PublicKey)
+	if err != nil {
+		return peertls.ErrVerifyPeerCert.Wrap(err)
+	}
+	if w.IDs[id.String()] {
+		return nil
+	}
+
+	// the CA of the peer, or a certificate it chains up to, must be signed
+	// by a whitelisted certificate authority
+	for _, cert := range chain[1:] {
+		for _, ca := range w.CAs {
+			if err := ca.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature); err == nil {
+				return nil
+			}
+		}
+	}
+	return ErrNotWhitelisted.New("%s", id)
+}
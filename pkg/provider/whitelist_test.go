@@ -0,0 +1,53 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package provider
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/peertls"
+)
+
+func TestWhitelist(t *testing.T) {
+	ctx := context.Background()
+	newCA := func() *FullCertificateAuthority {
+		ca, err := NewCA(ctx, 12, 4)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return ca
+	}
+	signer, signed, listed, other := newCA(), newCA(), newCA(), newCA()
+
+	// sign the CA of signed with signer
+	template, err := peertls.CATemplate()
+	assert.NoError(t, err)
+	cert, err := peertls.NewCert(template, signer.Cert, signed.Cert.PublicKey, signer.Key)
+	assert.NoError(t, err)
+	assert.NoError(t, signed.SetSignedChain([]*x509.Certificate{cert, signer.Cert}))
+
+	whitelist := NewWhitelist([]*x509.Certificate{signer.Cert}, []string{listed.ID.String()})
+	verify := func(ca *FullCertificateAuthority) error {
+		fi, err := ca.NewIdentity()
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return peertls.VerifyPeerFunc(peertls.VerifyPeerCertChains, whitelist.VerifyPeer)(fi.Chain(), nil)
+	}
+
+	assert.NoError(t, verify(signed))
+	assert.NoError(t, verify(listed))
+	assert.True(t, ErrNotWhitelisted.Has(verify(other)))
+
+	assert.True(t, whitelist.Listed(other.ID.String()))
+	ids := NewWhitelist(nil, []string{listed.ID.String()})
+	assert.True(t, ids.Listed(listed.ID.String()))
+	assert.False(t, ids.Listed(other.ID.String()))
+	var none *Whitelist
+	assert.True(t, none.Listed(other.ID.String()))
+}
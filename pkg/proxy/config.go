@@ -0,0 +1,139 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/pointerdb/pdbclient"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/storage/buckets"
+	ecclient "storj.io/storj/pkg/storage/ec"
+	"storj.io/storj/pkg/storage/objects"
+	segment "storj.io/storj/pkg/storage/segments"
+	streams "storj.io/storj/pkg/storage/streams"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+)
+
+// Config contains everything necessary to run the proxy, which encrypts,
+// erasure codes and distributes the pieces of objects on behalf of clients
+// that can't do it themselves. Clients trust the proxy with their API keys
+// and encryption keys. It only keeps the encryption keys for the duration of
+// a request, and the API keys with their connections to pointerdb for
+// StoreTTL after their last request.
+type Config struct {
+	Enabled       bool   `help:"whether uploads and downloads on behalf of constrained clients are served" default:"false"`
+	SatelliteAddr string `help:"address of the satellite the proxy stores objects through" default:"127.0.0.1:7777"`
+
+	MaxBufferMem      int  `help:"maximum buffer memory (in bytes) to be allocated for read buffers" default:"0x400000"`
+	MaxTotalBufferMem int  `help:"maximum buffer memory (in bytes) shared by the read buffers of all concurrent uploads and downloads. 0 means no limit" default:"0"`
+	ErasureShareSize  int  `help:"the size of each new erasure sure in bytes" default:"1024"`
+	MinThreshold      int  `help:"the minimum pieces required to recover a segment. k." default:"20"`
+	RepairThreshold   int  `help:"the minimum safe pieces before a repair is triggered. m." default:"30"`
+	SuccessThreshold  int  `help:"the desired total pieces for a segment. o." default:"40"`
+	MaxThreshold      int  `help:"the largest amount of pieces to encode to. n." default:"50"`
+	ShareMACs         bool `help:"whether to follow the erasure shares of new segments by a MAC bound to their segment, stripe and share number, so replayed or swapped shares are detected" default:"false"`
+
+	MaxInlineSize       int   `help:"max inline segment size in bytes" default:"4096"`
+	SegmentSize         int64 `help:"the size of a segment in bytes" default:"64000000"`
+	EncryptionBlockSize int   `help:"the size of the blocks objects are encrypted in" default:"1024"`
+
+	EncryptionCipher string `help:"the cipher of objects in buckets without a default cipher: aesgcm, or aesgcmsiv to tolerate reused nonces" default:"aesgcm"`
+
+	DialTimeout time.Duration `help:"how long to wait for connections to storage nodes. 0 means no timeout" default:"0"`
+
+	MaxStores int           `help:"the maximum number of API keys whose connections to pointerdb are kept open between their requests" default:"1000"`
+	StoreTTL  time.Duration `help:"how long the connection to pointerdb of an API key is kept open after its last request" default:"10m"`
+	ecclient.Limits
+}
+
+// Run implements the provider.Responsibility interface
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if !c.Enabled {
+		return server.Run(ctx)
+	}
+
+	if c.MaxTotalBufferMem > 0 {
+		eestream.UseMemoryBudget(eestream.NewMemoryBudget(c.MaxTotalBufferMem))
+	}
+
+	identity := server.Identity()
+	oc, err := overlay.NewOverlayClient(identity, c.SatelliteAddr)
+	if err != nil {
+		return err
+	}
+	ec := ecclient.NewClientWithLimits(identity, transport.NewClientWithTimeout(identity, c.DialTimeout), c.MaxBufferMem, c.Limits)
+
+	stores := NewStores(zap.L().Named("proxy"), func(apiKey []byte) (buckets.Store, io.Closer, error) {
+		pdb, err := pdbclient.NewClient(identity, c.SatelliteAddr, apiKey)
+		if err != nil {
+			return nil, nil, err
+		}
+		bs, err := c.newBucketStore(oc, ec, pdb)
+		if err != nil {
+			return nil, nil, utils.CombineErrors(err, pdb.Close())
+		}
+		return bs, pdb, nil
+	}, c.StoreTTL, c.MaxStores)
+	defer func() { err = utils.CombineErrors(err, stores.Close(), oc.Close()) }()
+
+	pb.RegisterProxyServer(server.GRPC(), NewServer(zap.L().Named("proxy"), c, stores.Get))
+
+	return server.Run(ctx)
+}
+
+// newBucketStore returns a bucket store that accesses pointerdb through pdb
+func (c Config) newBucketStore(oc overlay.Client, ec ecclient.Client, pdb pdbclient.Client) (buckets.Store, error) {
+	// newObjectStore creates an object store whose segments are stored with
+	// the redundancy scheme rs
+	newObjectStore := func(rs *pb.RedundancyScheme) (objects.Store, error) {
+		// segments are Reed-Solomon coded, objects were already encrypted
+		// by the proxy
+		suite, err := eestream.LookupSuite(eestream.SuiteRS)
+		if err != nil {
+			return nil, err
+		}
+		es, err := suite.NewErasure(int(rs.GetMinReq()), int(rs.GetTotal()), int(rs.GetErasureShareSize()))
+		if err != nil {
+			return nil, err
+		}
+		strategy, err := eestream.NewRedundancyStrategy(es,
+			int(rs.GetRepairThreshold()), int(rs.GetSuccessThreshold()))
+		if err != nil {
+			return nil, err
+		}
+
+		segments := segment.NewSegmentStoreWithSuite(oc, ec, pdb, strategy, c.MaxInlineSize, c.ShareMACs, suite.ID)
+		// the proxy serves many uploads at once, so it doesn't buffer segments
+		stream, err := streams.NewStreamStore(segments, c.SegmentSize, 1)
+		if err != nil {
+			return nil, err
+		}
+		return objects.NewStore(stream), nil
+	}
+
+	obj, err := newObjectStore(&pb.RedundancyScheme{
+		Type:             pb.RedundancyScheme_RS,
+		MinReq:           int32(c.MinThreshold),
+		Total:            int32(c.MaxThreshold),
+		RepairThreshold:  int32(c.RepairThreshold),
+		SuccessThreshold: int32(c.SuccessThreshold),
+		ErasureShareSize: int32(c.ErasureShareSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return buckets.NewStoreWithDefaults(obj, newObjectStore), nil
+}
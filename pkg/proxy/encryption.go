@@ -0,0 +1,102 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"io/ioutil"
+
+	"storj.io/storj/internal/pkg/readcloser"
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/buckets"
+)
+
+const (
+	// keySize is the size of the encryption keys of clients
+	keySize = 32
+	// nonceSize is the size of the random nonce objects start with
+	nonceSize = 12
+)
+
+// metaCipher is the user defined metadata of objects recording the cipher
+// they were encrypted with. Objects without it are encrypted with AES-GCM.
+const metaCipher = "storj-proxy-cipher"
+
+// encrypt returns a reader of the encrypted content of data. The encrypted
+// object starts with the random nonce it was encrypted with, followed by the
+// blocks of the padded data encrypted with cipher, AES-GCM or AES-GCM-SIV.
+func encrypt(data io.Reader, cipher buckets.Cipher, key *[keySize]byte, blockSize int) (io.ReadCloser, error) {
+	var nonce [nonceSize]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	var encrypter eestream.Transformer
+	var err error
+	switch cipher {
+	case buckets.AESGCM:
+		encrypter, err = eestream.NewAESGCMEncrypter(key, &nonce, blockSize)
+	case buckets.AESGCMSIV:
+		encrypter, err = eestream.NewAESGCMSIVEncrypter(key, &nonce, blockSize)
+	default:
+		return nil, Error.New("unsupported cipher %s", cipher)
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	padded := eestream.PadReader(ioutil.NopCloser(data), encrypter.InBlockSize())
+	return readcloser.MultiReadCloser(
+		ioutil.NopCloser(bytes.NewReader(nonce[:])),
+		eestream.TransformReader(padded, encrypter, 0),
+	), nil
+}
+
+// decrypt returns a ranger of the decrypted content of an object that was
+// encrypted with encrypt
+func decrypt(ctx context.Context, rr ranger.Ranger, cipher buckets.Cipher, key *[keySize]byte, blockSize int) (ranger.Ranger, error) {
+	if rr.Size() < nonceSize {
+		return nil, Error.New("object is not encrypted")
+	}
+
+	r, err := rr.Range(ctx, 0, nonceSize)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	var nonce [nonceSize]byte
+	_, err = io.ReadFull(r, nonce[:])
+	if closeErr := r.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	var decrypter eestream.Transformer
+	switch cipher {
+	case buckets.AESGCM:
+		decrypter, err = eestream.NewAESGCMDecrypter(key, &nonce, blockSize)
+	case buckets.AESGCMSIV:
+		decrypter, err = eestream.NewAESGCMSIVDecrypter(key, &nonce, blockSize)
+	default:
+		return nil, Error.New("unsupported cipher %s", cipher)
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	encrypted, err := ranger.Subrange(rr, nonceSize, rr.Size()-nonceSize)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	padded, err := eestream.Transform(encrypted, decrypter)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return eestream.UnpadSlow(ctx, padded)
+}
@@ -0,0 +1,249 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package proxy
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/utils"
+)
+
+// BucketStoreFunc returns the bucket store that accesses pointerdb with the
+// API key of a client, and a function releasing it once the request is done
+type BucketStoreFunc func(apiKey []byte) (bs buckets.Store, release func(), err error)
+
+// Server implements the proxy rpc service
+type Server struct {
+	log     *zap.Logger
+	config  Config
+	buckets BucketStoreFunc
+}
+
+// NewServer creates a proxy Server that stores objects in the bucket stores
+// returned by bucketStore
+func NewServer(log *zap.Logger, config Config, bucketStore BucketStoreFunc) *Server {
+	return &Server{log: log, config: config, buckets: bucketStore}
+}
+
+// Upload encrypts and stores the object streamed by the client
+func (s *Server) Upload(stream pb.Proxy_UploadServer) (err error) {
+	ctx := stream.Context()
+	defer mon.Task()(&ctx)(&err)
+
+	req, err := stream.Recv()
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	object := req.GetObject()
+	if object == nil {
+		return status.Errorf(codes.InvalidArgument, "object not specified")
+	}
+
+	bs, release, err := s.buckets(object.GetLocation().GetAPIKey())
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	store, path, key, err := s.objectStore(ctx, bs, object.GetLocation())
+	if err != nil {
+		return err
+	}
+
+	data := &countingReader{r: utils.NewReaderSource(func() ([]byte, error) {
+		req, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return req.GetContent(), nil
+	})}
+
+	cipher, err := s.uploadCipher(ctx, bs, object.GetLocation())
+	if err != nil {
+		return err
+	}
+
+	encrypted, err := encrypt(data, cipher, key, s.config.EncryptionBlockSize)
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(encrypted)
+
+	var expiration time.Time
+	if object.GetExpirationUnixSec() > 0 {
+		expiration = time.Unix(object.GetExpirationUnixSec(), 0)
+	}
+
+	meta := objects.SerializableMeta{
+		ContentType: object.GetContentType(),
+		UserDefined: map[string]string{metaCipher: cipher.String()},
+	}
+	_, err = store.Put(ctx, path, encrypted, meta, expiration)
+	if err != nil {
+		s.log.Debug("upload failed", zap.Error(err))
+		return err
+	}
+
+	return stream.SendAndClose(&pb.UploadResponse{Size: data.n})
+}
+
+// Download streams a range of a decrypted object to the client
+func (s *Server) Download(req *pb.DownloadRequest, stream pb.Proxy_DownloadServer) (err error) {
+	ctx := stream.Context()
+	defer mon.Task()(&ctx)(&err)
+
+	bs, release, err := s.buckets(req.GetLocation().GetAPIKey())
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	store, path, key, err := s.objectStore(ctx, bs, req.GetLocation())
+	if err != nil {
+		return err
+	}
+
+	encrypted, meta, err := store.Get(ctx, path)
+	if err != nil {
+		return err
+	}
+
+	cipher := buckets.AESGCM
+	if name, ok := meta.UserDefined[metaCipher]; ok {
+		if cipher, err = buckets.ParseCipher(name); err != nil {
+			return Error.Wrap(err)
+		}
+	}
+
+	rr, err := decrypt(ctx, encrypted, cipher, key, s.config.EncryptionBlockSize)
+	if err != nil {
+		return err
+	}
+
+	size, offset, length := rr.Size(), req.GetOffset(), req.GetLength()
+	if offset < 0 || offset > size {
+		return status.Errorf(codes.OutOfRange, "offset %d is outside of object of size %d", offset, size)
+	}
+	if length < 0 || offset+length > size {
+		length = size - offset
+	}
+
+	r, err := rr.Range(ctx, offset, length)
+	if err != nil {
+		return err
+	}
+	defer utils.LogClose(r)
+
+	if err = stream.Send(&pb.DownloadResponse{Size: size}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&pb.DownloadResponse{Content: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// DeleteObject deletes an object
+func (s *Server) DeleteObject(ctx context.Context, req *pb.DeleteObjectRequest) (resp *pb.DeleteObjectResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	bs, release, err := s.buckets(req.GetLocation().GetAPIKey())
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	store, path, _, err := s.objectStore(ctx, bs, req.GetLocation())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = store.Delete(ctx, path); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteObjectResponse{}, nil
+}
+
+// objectStore returns the object store of the bucket of location in bs, the
+// bucket store with the access rights of the client's API key
+func (s *Server) objectStore(ctx context.Context, bs buckets.Store, location *pb.ObjectLocation) (
+	store objects.Store, path paths.Path, key *[keySize]byte, err error) {
+	if location.GetBucket() == "" || location.GetPath() == "" {
+		return nil, nil, nil, status.Errorf(codes.InvalidArgument, "bucket and path must be specified")
+	}
+	if len(location.GetEncryptionKey()) != keySize {
+		return nil, nil, nil, status.Errorf(codes.InvalidArgument, "encryption key must be %d bytes", keySize)
+	}
+
+	store, err = bs.GetObjectStore(ctx, location.GetBucket())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	key = new([keySize]byte)
+	copy(key[:], location.GetEncryptionKey())
+	return store, paths.New(location.GetPath()), key, nil
+}
+
+// uploadCipher returns the cipher of new objects at location in bs: the
+// cipher of the bucket's default encryption, or else the cipher of the
+// proxy's configuration. Uploads to buckets whose cipher the proxy doesn't
+// support are refused.
+func (s *Server) uploadCipher(ctx context.Context, bs buckets.Store, location *pb.ObjectLocation) (buckets.Cipher, error) {
+	bucket, err := bs.Get(ctx, location.GetBucket())
+	if err != nil {
+		return buckets.Unencrypted, err
+	}
+	if enc := bucket.Defaults.Encryption; enc != nil {
+		switch enc.Cipher {
+		case buckets.AESGCM, buckets.AESGCMSIV:
+			return enc.Cipher, nil
+		case buckets.SecretBox:
+			return buckets.Unencrypted, status.Errorf(codes.FailedPrecondition,
+				"the proxy can't encrypt objects with the cipher %s of bucket %q", enc.Cipher, location.GetBucket())
+		}
+	}
+	if s.config.EncryptionCipher == "" {
+		return buckets.AESGCM, nil
+	}
+	cipher, err := buckets.ParseCipher(s.config.EncryptionCipher)
+	if err != nil {
+		return buckets.Unencrypted, Error.Wrap(err)
+	}
+	return cipher, nil
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (n int, err error) {
+	n, err = cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,384 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/buckets"
+	mock_buckets "storj.io/storj/pkg/storage/buckets/mocks"
+	"storj.io/storj/pkg/storage/objects"
+)
+
+// memoryStore keeps objects in memory
+type memoryStore struct {
+	objects.Store
+	data map[string][]byte
+	meta map[string]objects.SerializableMeta
+}
+
+func (m *memoryStore) Get(ctx context.Context, path paths.Path) (ranger.Ranger, objects.Meta, error) {
+	data, ok := m.data[path.String()]
+	if !ok {
+		return nil, objects.Meta{}, status.Errorf(codes.NotFound, "not found")
+	}
+	return ranger.ByteRanger(data), objects.Meta{SerializableMeta: m.meta[path.String()]}, nil
+}
+
+func (m *memoryStore) Put(ctx context.Context, path paths.Path, data io.Reader,
+	metadata objects.SerializableMeta, expiration time.Time) (objects.Meta, error) {
+	b, err := ioutil.ReadAll(data)
+	if err != nil {
+		return objects.Meta{}, err
+	}
+	m.data[path.String()] = b
+	if m.meta == nil {
+		m.meta = map[string]objects.SerializableMeta{}
+	}
+	m.meta[path.String()] = metadata
+	return objects.Meta{}, nil
+}
+
+func (m *memoryStore) Delete(ctx context.Context, path paths.Path) error {
+	delete(m.data, path.String())
+	delete(m.meta, path.String())
+	return nil
+}
+
+type uploadStream struct {
+	grpc.ServerStream
+	reqs []*pb.UploadRequest
+	resp *pb.UploadResponse
+}
+
+func (s *uploadStream) Context() context.Context { return context.Background() }
+
+func (s *uploadStream) Recv() (*pb.UploadRequest, error) {
+	if len(s.reqs) == 0 {
+		return nil, io.EOF
+	}
+	req := s.reqs[0]
+	s.reqs = s.reqs[1:]
+	return req, nil
+}
+
+func (s *uploadStream) SendAndClose(resp *pb.UploadResponse) error {
+	s.resp = resp
+	return nil
+}
+
+type downloadStream struct {
+	grpc.ServerStream
+	resps []*pb.DownloadResponse
+}
+
+func (s *downloadStream) Context() context.Context { return context.Background() }
+
+func (s *downloadStream) Send(resp *pb.DownloadResponse) error {
+	// grpc serializes messages on send, so the content buffer is reused
+	resp.Content = append([]byte(nil), resp.Content...)
+	s.resps = append(s.resps, resp)
+	return nil
+}
+
+func TestEncryption(t *testing.T) {
+	ctx := context.Background()
+	key := new([keySize]byte)
+	data := make([]byte, 5000)
+	_, _ = rand.Read(data)
+
+	for _, cipher := range []buckets.Cipher{buckets.AESGCM, buckets.AESGCMSIV} {
+		for _, size := range []int{0, 1, 1023, 1024, 5000} {
+			r, err := encrypt(bytes.NewReader(data[:size]), cipher, key, 1024)
+			if !assert.NoError(t, err) {
+				continue
+			}
+			encrypted, err := ioutil.ReadAll(r)
+			assert.NoError(t, err)
+			assert.NoError(t, r.Close())
+
+			rr, err := decrypt(ctx, ranger.ByteRanger(encrypted), cipher, key, 1024)
+			if !assert.NoError(t, err) {
+				continue
+			}
+			assert.Equal(t, int64(size), rr.Size())
+			decrypted, err := rr.Range(ctx, 0, rr.Size())
+			if assert.NoError(t, err) {
+				plaintext, err := ioutil.ReadAll(decrypted)
+				assert.NoError(t, err)
+				assert.Equal(t, data[:size], plaintext)
+			}
+
+			other := new([keySize]byte)
+			other[0] = 1
+			_, err = decrypt(ctx, ranger.ByteRanger(encrypted), cipher, other, 1024)
+			assert.Error(t, err)
+		}
+	}
+
+	_, err := encrypt(bytes.NewReader(data), buckets.SecretBox, key, 1024)
+	assert.Error(t, err)
+}
+
+func TestUploadDownload(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := &memoryStore{data: map[string][]byte{}}
+	bs := mock_buckets.NewMockStore(ctrl)
+	bs.EXPECT().GetObjectStore(gomock.Any(), "bucket").Return(store, nil).AnyTimes()
+	bs.EXPECT().Get(gomock.Any(), "bucket").Return(buckets.Meta{}, nil).AnyTimes()
+
+	var apiKeys []string
+	released := 0
+	s := NewServer(zap.NewNop(), Config{EncryptionBlockSize: 1024}, func(apiKey []byte) (buckets.Store, func(), error) {
+		apiKeys = append(apiKeys, string(apiKey))
+		return bs, func() { released++ }, nil
+	})
+
+	location := &pb.ObjectLocation{
+		Bucket:        "bucket",
+		Path:          "path",
+		APIKey:        []byte("key"),
+		EncryptionKey: make([]byte, keySize),
+	}
+	data := make([]byte, 3000)
+	_, _ = rand.Read(data)
+
+	upload := &uploadStream{reqs: []*pb.UploadRequest{
+		{Object: &pb.UploadRequest_Object{Location: location}},
+		{Content: data[:1000]},
+		{Content: data[1000:]},
+	}}
+	if !assert.NoError(t, s.Upload(upload)) {
+		t.FailNow()
+	}
+	assert.Equal(t, int64(len(data)), upload.resp.GetSize())
+	assert.Equal(t, []string{"key"}, apiKeys)
+	assert.Equal(t, 1, released)
+	assert.Equal(t, "aesgcm", store.meta["path"].UserDefined[metaCipher])
+	assert.NotContains(t, string(store.data["path"]), string(data[:100]))
+
+	for _, tt := range []struct {
+		offset, length int64
+		expected       []byte
+	}{
+		{0, -1, data},
+		{1000, 500, data[1000:1500]},
+		{2500, 1000, data[2500:]},
+		{3000, -1, []byte{}},
+	} {
+		download := &downloadStream{}
+		err := s.Download(&pb.DownloadRequest{Location: location, Offset: tt.offset, Length: tt.length}, download)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.Equal(t, int64(len(data)), download.resps[0].GetSize())
+		received := []byte{}
+		for _, resp := range download.resps[1:] {
+			received = append(received, resp.GetContent()...)
+		}
+		assert.Equal(t, tt.expected, received)
+	}
+
+	err := s.Download(&pb.DownloadRequest{Location: location, Offset: 3001}, &downloadStream{})
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+
+	invalid := *location
+	invalid.EncryptionKey = []byte("short")
+	err = s.Download(&pb.DownloadRequest{Location: &invalid}, &downloadStream{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	_, err = s.DeleteObject(context.Background(), &pb.DeleteObjectRequest{Location: location})
+	assert.NoError(t, err)
+	assert.Empty(t, store.data)
+	// every request released its bucket store
+	assert.Equal(t, len(apiKeys), released)
+}
+
+func TestUploadCipher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := &memoryStore{data: map[string][]byte{}}
+	bs := mock_buckets.NewMockStore(ctrl)
+	bs.EXPECT().GetObjectStore(gomock.Any(), gomock.Any()).Return(store, nil).AnyTimes()
+	bs.EXPECT().Get(gomock.Any(), "gcm").Return(buckets.Meta{Defaults: buckets.Defaults{
+		Encryption: &buckets.EncryptionScheme{Cipher: buckets.AESGCM, BlockSize: 1024},
+	}}, nil).AnyTimes()
+	bs.EXPECT().Get(gomock.Any(), "default").Return(buckets.Meta{}, nil).AnyTimes()
+	bs.EXPECT().Get(gomock.Any(), "secretbox").Return(buckets.Meta{Defaults: buckets.Defaults{
+		Encryption: &buckets.EncryptionScheme{Cipher: buckets.SecretBox, BlockSize: 1024},
+	}}, nil).AnyTimes()
+
+	config := Config{EncryptionBlockSize: 1024, EncryptionCipher: "aesgcmsiv"}
+	s := NewServer(zap.NewNop(), config, func(apiKey []byte) (buckets.Store, func(), error) {
+		return bs, func() {}, nil
+	})
+
+	data := make([]byte, 3000)
+	_, _ = rand.Read(data)
+	// buckets with a default cipher override the proxy's
+	for bucket, cipher := range map[string]string{"gcm": "aesgcm", "default": "aesgcmsiv"} {
+		location := &pb.ObjectLocation{
+			Bucket:        bucket,
+			Path:          bucket,
+			EncryptionKey: make([]byte, keySize),
+		}
+		upload := &uploadStream{reqs: []*pb.UploadRequest{
+			{Object: &pb.UploadRequest_Object{Location: location}},
+			{Content: data},
+		}}
+		if !assert.NoError(t, s.Upload(upload)) {
+			continue
+		}
+		assert.Equal(t, cipher, store.meta[bucket].UserDefined[metaCipher])
+
+		download := &downloadStream{}
+		if !assert.NoError(t, s.Download(&pb.DownloadRequest{Location: location, Length: -1}, download)) {
+			continue
+		}
+		received := []byte{}
+		for _, resp := range download.resps[1:] {
+			received = append(received, resp.GetContent()...)
+		}
+		assert.Equal(t, data, received)
+	}
+
+	// buckets with a cipher the proxy doesn't support refuse uploads
+	location := &pb.ObjectLocation{Bucket: "secretbox", Path: "secretbox", EncryptionKey: make([]byte, keySize)}
+	upload := &uploadStream{reqs: []*pb.UploadRequest{
+		{Object: &pb.UploadRequest_Object{Location: location}},
+		{Content: data},
+	}}
+	assert.Equal(t, codes.FailedPrecondition, status.Code(s.Upload(upload)))
+	assert.NotContains(t, store.data, "secretbox")
+}
+
+// closer counts how often it was closed
+type closer struct{ closed int }
+
+func (c *closer) Close() error {
+	c.closed++
+	return nil
+}
+
+func TestStores(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	conns := map[string]*closer{}
+	stores := NewStores(zap.NewNop(), func(apiKey []byte) (buckets.Store, io.Closer, error) {
+		conn := &closer{}
+		conns[string(apiKey)] = conn
+		return mock_buckets.NewMockStore(ctrl), conn, nil
+	}, time.Hour, 2)
+	now := time.Now()
+	stores.now = func() time.Time { return now }
+
+	get := func(apiKey string) func() {
+		_, release, err := stores.Get([]byte(apiKey))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+		return release
+	}
+
+	// stores are reused between requests
+	get("a")()
+	first := conns["a"]
+	get("a")()
+	assert.Equal(t, first, conns["a"])
+
+	// the least recently used store is closed once there are too many
+	now = now.Add(time.Minute)
+	releaseB := get("b")
+	now = now.Add(time.Minute)
+	get("c")()
+	assert.Equal(t, 1, conns["a"].closed)
+	assert.Equal(t, 0, conns["c"].closed)
+
+	// stores in use aren't evicted, and new stores are kept
+	now = now.Add(time.Minute)
+	get("a")()
+	assert.Equal(t, 0, conns["a"].closed)
+	assert.Equal(t, 1, conns["c"].closed)
+	assert.Equal(t, 0, conns["b"].closed)
+	releaseB()
+	assert.Equal(t, 0, conns["b"].closed)
+
+	// unused stores expire
+	now = now.Add(2 * time.Hour)
+	get("d")()
+	assert.Equal(t, 1, conns["a"].closed)
+	assert.Equal(t, 1, conns["b"].closed)
+	assert.Equal(t, 0, conns["d"].closed)
+	assert.Len(t, stores.stores, 1)
+
+	releaseD := get("d")
+	assert.NoError(t, stores.Close())
+	assert.Equal(t, 0, conns["d"].closed)
+	releaseD()
+	assert.Equal(t, 1, conns["d"].closed)
+}
+
+func TestStoresConcurrentOpen(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	dialing := make(chan struct{})
+	unblock := make(chan struct{})
+	var mu sync.Mutex
+	var conns []*closer
+	stores := NewStores(zap.NewNop(), func(apiKey []byte) (buckets.Store, io.Closer, error) {
+		if string(apiKey) == "slow" {
+			dialing <- struct{}{}
+			<-unblock
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		conn := &closer{}
+		conns = append(conns, conn)
+		return mock_buckets.NewMockStore(ctrl), conn, nil
+	}, time.Hour, 10)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, release, err := stores.Get([]byte("slow"))
+		if assert.NoError(t, err) {
+			release()
+		}
+	}()
+	<-dialing
+
+	// a slow dial doesn't block the other clients
+	_, release, err := stores.Get([]byte("fast"))
+	assert.NoError(t, err)
+	release()
+
+	close(unblock)
+	<-done
+	assert.Len(t, stores.stores, 2)
+	for _, conn := range conns {
+		assert.Equal(t, 0, conn.closed)
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package proxy
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/utils"
+)
+
+// OpenStoreFunc opens the bucket store that accesses pointerdb with the API
+// key of a client, and returns the connection to close once it's forgotten
+type OpenStoreFunc func(apiKey []byte) (buckets.Store, io.Closer, error)
+
+// Stores keeps the bucket stores of the API keys of clients open between
+// their requests. The stores unused for ttl are closed, and at most maxStores
+// of them are kept open, closing the least recently used first. Stores in use
+// are only closed once their requests are done, so more than maxStores may be
+// open while they are all used.
+type Stores struct {
+	log       *zap.Logger
+	open      OpenStoreFunc
+	ttl       time.Duration
+	maxStores int
+	now       func() time.Time
+
+	mu     sync.Mutex
+	stores map[string]*store
+}
+
+// store is the bucket store of an API key. Its last use, users and removal
+// are guarded by the mutex of Stores.
+type store struct {
+	apiKey string
+	bs     buckets.Store
+	conn   io.Closer
+
+	lastUsed time.Time
+	// users is how many requests use the store. A removed store is closed
+	// once the last of them is done.
+	users   int
+	removed bool
+}
+
+// NewStores creates the bucket stores opened with open
+func NewStores(log *zap.Logger, open OpenStoreFunc, ttl time.Duration, maxStores int) *Stores {
+	return &Stores{
+		log:       log,
+		open:      open,
+		ttl:       ttl,
+		maxStores: maxStores,
+		now:       time.Now,
+		stores:    map[string]*store{},
+	}
+}
+
+// Get returns the bucket store of apiKey and a function releasing it, which
+// must be called once the request is done with it. It implements
+// BucketStoreFunc.
+func (s *Stores) Get(apiKey []byte) (_ buckets.Store, release func(), err error) {
+	s.mu.Lock()
+	s.expire(s.now())
+	if st, ok := s.acquire(string(apiKey)); ok {
+		s.mu.Unlock()
+		return st.bs, func() { s.release(st) }, nil
+	}
+	s.mu.Unlock()
+
+	// dialing pointerdb doesn't block the requests of other clients
+	bs, conn, err := s.open(apiKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if st, ok := s.acquire(string(apiKey)); ok {
+		// a concurrent request opened the store first
+		s.closeConn(conn)
+		return st.bs, func() { s.release(st) }, nil
+	}
+	st := &store{apiKey: string(apiKey), bs: bs, conn: conn, users: 1, lastUsed: s.now()}
+	s.stores[st.apiKey] = st
+	s.evict()
+	return st.bs, func() { s.release(st) }, nil
+}
+
+// acquire returns the open store of apiKey marked used, if any. s.mu must be
+// held.
+func (s *Stores) acquire(apiKey string) (*store, bool) {
+	st, ok := s.stores[apiKey]
+	if !ok {
+		return nil, false
+	}
+	st.users++
+	st.lastUsed = s.now()
+	return st, true
+}
+
+// release marks a use of a store done
+func (s *Stores) release(st *store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	st.users--
+	if st.removed && st.users == 0 {
+		s.closeStore(st)
+	}
+}
+
+// expire removes the stores unused for ttl. s.mu must be held.
+func (s *Stores) expire(now time.Time) {
+	for _, st := range s.stores {
+		if st.users == 0 && now.Sub(st.lastUsed) > s.ttl {
+			s.remove(st)
+		}
+	}
+}
+
+// evict removes the least recently used stores that aren't in use while
+// there are more than maxStores. s.mu must be held.
+func (s *Stores) evict() {
+	for len(s.stores) > s.maxStores {
+		var victim *store
+		for _, st := range s.stores {
+			if st.users > 0 {
+				continue
+			}
+			if victim == nil || st.lastUsed.Before(victim.lastUsed) {
+				victim = st
+			}
+		}
+		if victim == nil {
+			return
+		}
+		s.remove(victim)
+	}
+}
+
+// remove forgets a store, closing it if it isn't used. s.mu must be held.
+func (s *Stores) remove(st *store) {
+	delete(s.stores, st.apiKey)
+	st.removed = true
+	if st.users == 0 {
+		s.closeStore(st)
+	}
+}
+
+// closeStore closes the connection of a removed store
+func (s *Stores) closeStore(st *store) {
+	s.closeConn(st.conn)
+}
+
+// closeConn closes the connection of a bucket store
+func (s *Stores) closeConn(conn io.Closer) {
+	if err := conn.Close(); err != nil {
+		s.log.Warn("closing the connection of a bucket store failed", zap.Error(err))
+	}
+}
+
+// Close closes the stores that aren't used, and the others once their
+// requests are done
+func (s *Stores) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for _, st := range s.stores {
+		delete(s.stores, st.apiKey)
+		st.removed = true
+		if st.users == 0 {
+			errs = append(errs, st.conn.Close())
+		}
+	}
+	return utils.CombineErrors(errs...)
+}
@@ -12,7 +12,6 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"log"
 	"mime"
 	"mime/multipart"
@@ -25,7 +24,11 @@ import (
 )
 
 // ServeContent is the Go standard library's http.ServeContent but modified to
-// work with Rangers.
+// work with Rangers. It serves single and multiple byte ranges, HEAD requests
+// and conditional requests. Like with http.ServeContent, the conditional
+// requests use the ETag the caller set in the Etag header of w, if any, and
+// modtime. The content type is detected from the extension of name or the
+// content unless it is set in w, or unset explicitly by setting it to nil.
 func ServeContent(ctx context.Context, w http.ResponseWriter, r *http.Request, name string, modtime time.Time, content Ranger) {
 	setLastModified(w, modtime)
 	done, rangeReq := checkPreconditions(w, r, modtime)
@@ -38,10 +41,14 @@ func ServeContent(ctx context.Context, w http.ResponseWriter, r *http.Request, n
 	size := content.Size()
 
 	if size <= 0 {
+		w.Header().Set("Accept-Ranges", "bytes")
+		w.Header().Set("Content-Length", "0")
 		w.WriteHeader(code)
 		return
 	}
 
+	ctype := detectContentType(ctx, w, name, content)
+
 	// handle Content-Range header.
 	sendSize := size
 	sendContent := func() (io.ReadCloser, error) {
@@ -82,43 +89,6 @@ func ServeContent(ctx context.Context, w http.ResponseWriter, r *http.Request, n
 		code = http.StatusPartialContent
 		w.Header().Set("Content-Range", ra.contentRange(size))
 	case len(ranges) > 1:
-		// If Content-Type isn't set, use the file's extension to find it, but
-		// if the Content-Type is unset explicitly, do not sniff the type.
-		ctypes, haveType := w.Header()["Content-Type"]
-		var ctype string
-		if !haveType {
-			ctype = mime.TypeByExtension(filepath.Ext(name))
-			if ctype == "" {
-				// read a chunk to decide between utf-8 text and binary
-				var buf [sniffLen]byte
-				amount := content.Size()
-				if amount > sniffLen {
-					amount = sniffLen
-				}
-				// TODO: cache this somewhere so we don't have to pull it out again
-				r, err := content.Range(ctx, 0, amount)
-				if err == nil {
-					defer func() {
-						if err := r.Close(); err != nil {
-							log.Printf("Error Closing ranger: %s", err)
-						}
-					}()
-
-					n, err := io.ReadFull(r, buf[:])
-					if err != nil {
-						log.Printf("Error Reading full: %s", err)
-					}
-
-					ctype = http.DetectContentType(buf[:n])
-				}
-			}
-			if ctype != "" {
-				w.Header().Set("Content-Type", ctype)
-			}
-		} else if len(ctypes) > 0 {
-			ctype = ctypes[0]
-		}
-
 		sendSize = rangesMIMESize(ranges, ctype, size)
 		code = http.StatusPartialContent
 
@@ -126,54 +96,10 @@ func ServeContent(ctx context.Context, w http.ResponseWriter, r *http.Request, n
 		mw := multipart.NewWriter(pw)
 		w.Header().Set("Content-Type",
 			"multipart/byteranges; boundary="+mw.Boundary())
-		sendContent = func() (io.ReadCloser, error) { return ioutil.NopCloser(pr), nil }
-		// cause writing goroutine to fail and exit if CopyN doesn't finish.
-		defer func() {
-			if err := pr.Close(); err != nil {
-				log.Printf("Error Closing pipereader: %s", err)
-			}
-		}()
-
-		go func() {
-			for _, ra := range ranges {
-				part, err := mw.CreatePart(ra.mimeHeader(ctype, size))
-				if err != nil {
-					if err := pw.CloseWithError(err); err != nil {
-						log.Printf("Error Closing pipewriter with errors: %s", err)
-					}
-
-					return
-				}
-				partReader, err := content.Range(ctx, ra.start, ra.length)
-				if err != nil {
-					if err := pw.CloseWithError(err); err != nil {
-						log.Printf("Error Closing pipewriter with errors: %s", err)
-					}
-
-					return
-				}
-				defer func() {
-					if err := partReader.Close(); err != nil {
-						log.Printf("Error Closing partReader: %s", err)
-					}
-				}()
-
-				if _, err := io.Copy(part, partReader); err != nil {
-					if err := pw.CloseWithError(err); err != nil {
-						log.Printf("Error Closing pipewriter with errors: %s", err)
-					}
-
-					return
-				}
-			}
-			if err := mw.Close(); err != nil {
-				log.Printf("Error Closing writer: %s", err)
-			}
-
-			if err := pw.Close(); err != nil {
-				log.Printf("Error closing pipewriter: %s", err)
-			}
-		}()
+		sendContent = func() (io.ReadCloser, error) {
+			go writeRanges(ctx, pw, mw, ranges, ctype, content)
+			return pr, nil
+		}
 	}
 
 	w.Header().Set("Accept-Ranges", "bytes")
@@ -181,22 +107,98 @@ func ServeContent(ctx context.Context, w http.ResponseWriter, r *http.Request, n
 		w.Header().Set("Content-Length", strconv.FormatInt(sendSize, 10))
 	}
 
+	if r.Method == http.MethodHead {
+		w.WriteHeader(code)
+		return
+	}
+
+	// the content is opened before the header is written, so that failures
+	// are still reported with an error status
+	body, err := sendContent()
+	if err != nil {
+		log.Printf("Error opening content: %s", err)
+		w.Header().Del("Content-Length")
+		w.Header().Del("Content-Range")
+		http.Error(w, "failed to read content", http.StatusInternalServerError)
+		return
+	}
+	defer func() {
+		if err := body.Close(); err != nil {
+			log.Printf("Error closing: %s", err)
+		}
+	}()
+
 	w.WriteHeader(code)
+	if _, err := io.CopyN(w, body, sendSize); err != nil {
+		log.Printf("Error Copying bytes: %s", err)
+	}
+}
+
+// detectContentType sets the Content-Type header of w, unless it is set or
+// unset explicitly, from the extension of name or by sniffing the start of
+// content, and returns it
+func detectContentType(ctx context.Context, w http.ResponseWriter, name string, content Ranger) string {
+	ctypes, haveType := w.Header()["Content-Type"]
+	if haveType {
+		if len(ctypes) > 0 {
+			return ctypes[0]
+		}
+		return ""
+	}
 
-	if r.Method != http.MethodHead {
-		r, err := sendContent()
+	ctype := mime.TypeByExtension(filepath.Ext(name))
+	if ctype == "" {
+		// read a chunk to decide between utf-8 text and binary
+		amount := content.Size()
+		if amount > sniffLen {
+			amount = sniffLen
+		}
+		r, err := content.Range(ctx, 0, amount)
 		if err != nil {
-			return
+			log.Printf("Error opening content to sniff: %s", err)
+			return ""
 		}
+		defer func() {
+			if err := r.Close(); err != nil {
+				log.Printf("Error Closing ranger: %s", err)
+			}
+		}()
 
-		if _, err := io.CopyN(w, r, sendSize); err != nil {
-			log.Printf("Error Copying bytes: %s", err)
+		var buf [sniffLen]byte
+		n, err := io.ReadFull(r, buf[:amount])
+		if err != nil {
+			log.Printf("Error Reading full: %s", err)
 		}
+		ctype = http.DetectContentType(buf[:n])
+	}
+	w.Header().Set("Content-Type", ctype)
+	return ctype
+}
 
-		if err := r.Close(); err != nil {
-			log.Printf("Error closing: %s", err)
+// writeRanges writes the ranges of content as the parts of mw, which writes
+// to pw
+func writeRanges(ctx context.Context, pw *io.PipeWriter, mw *multipart.Writer, ranges []httpRange, ctype string, content Ranger) {
+	for _, ra := range ranges {
+		part, err := mw.CreatePart(ra.mimeHeader(ctype, content.Size()))
+		if err != nil {
+			_ = pw.CloseWithError(err)
+			return
+		}
+		partReader, err := content.Range(ctx, ra.start, ra.length)
+		if err != nil {
+			_ = pw.CloseWithError(err)
+			return
+		}
+		_, err = io.Copy(part, partReader)
+		if closeErr := partReader.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = pw.CloseWithError(err)
+			return
 		}
 	}
+	_ = pw.CloseWithError(mw.Close())
 }
 
 var unixEpochTime = time.Unix(0, 0)
@@ -5,7 +5,12 @@ package ranger
 
 import (
 	"context"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"strconv"
 	"testing"
 	"time"
 
@@ -73,6 +78,87 @@ func TestServeContentParseRange(t *testing.T) {
 	assert.Equal(t, "23", writer.Result().Header.Get("Content-Length"))
 }
 
+// brokenRanger fails to open its ranges
+type brokenRanger struct{ ByteRanger }
+
+func (brokenRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	return nil, Error.New("broken")
+}
+
+func TestServeContentRanges(t *testing.T) {
+	modtime := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	etag := `"abc"`
+	serve := func(method string, headers map[string]string, content Ranger) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/file.txt", nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		w.Header().Set("Etag", etag)
+		ServeContent(context.Background(), w, req, "file.txt", modtime, content)
+		return w
+	}
+	content := ByteRanger("abcdefghij")
+
+	w := serve(http.MethodGet, nil, content)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "abcdefghij", w.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+
+	w = serve(http.MethodGet, map[string]string{"Range": "bytes=2-4"}, content)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "cde", w.Body.String())
+	assert.Equal(t, "bytes 2-4/10", w.Header().Get("Content-Range"))
+
+	w = serve(http.MethodGet, map[string]string{"Range": "bytes=0-1,-2"}, content)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	_, params, err := mime.ParseMediaType(w.Header().Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, w.Header().Get("Content-Length"), strconv.Itoa(w.Body.Len()))
+	mr := multipart.NewReader(w.Body, params["boundary"])
+	for _, expected := range []string{"ab", "ij"} {
+		part, err := mr.NextPart()
+		if !assert.NoError(t, err) {
+			break
+		}
+		data, err := ioutil.ReadAll(part)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, string(data))
+		assert.Equal(t, "text/plain; charset=utf-8", part.Header.Get("Content-Type"))
+	}
+	_, err = mr.NextPart()
+	assert.Equal(t, io.EOF, err)
+
+	w = serve(http.MethodHead, map[string]string{"Range": "bytes=2-4"}, content)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "3", w.Header().Get("Content-Length"))
+	assert.Equal(t, 0, w.Body.Len())
+
+	w = serve(http.MethodGet, map[string]string{"Range": "bytes=20-"}, content)
+	assert.Equal(t, http.StatusRequestedRangeNotSatisfiable, w.Code)
+	assert.Equal(t, "bytes */10", w.Header().Get("Content-Range"))
+
+	// the range is ignored if the content changed
+	w = serve(http.MethodGet, map[string]string{"Range": "bytes=2-4", "If-Range": `"other"`}, content)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "abcdefghij", w.Body.String())
+	w = serve(http.MethodGet, map[string]string{"Range": "bytes=2-4", "If-Range": etag}, content)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	w = serve(http.MethodGet, map[string]string{"Range": "bytes=2-4", "If-Range": modtime.Format(http.TimeFormat)}, content)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+
+	w = serve(http.MethodGet, map[string]string{"If-None-Match": etag}, content)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	w = serve(http.MethodGet, map[string]string{"If-Match": `"other"`}, content)
+	assert.Equal(t, http.StatusPreconditionFailed, w.Code)
+	w = serve(http.MethodGet, map[string]string{"If-Modified-Since": modtime.Format(http.TimeFormat)}, content)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+
+	// failures to read the content are reported before the header is written
+	w = serve(http.MethodGet, map[string]string{"Range": "bytes=2-4"}, brokenRanger{content})
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}
+
 func Test_isZeroTime(t *testing.T) {
 	for _, tt := range []struct {
 		name     string
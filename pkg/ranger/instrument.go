@@ -0,0 +1,141 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ranger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+var mon = monkit.Package()
+
+// maxCallers is how many callers of a slow range are logged
+const maxCallers = 16
+
+// Instrumentation records the Range calls of the rangers it instruments. The
+// nil Instrumentation doesn't record anything.
+type Instrumentation struct {
+	log  *zap.Logger
+	slow time.Duration
+}
+
+// NewInstrumentation returns an Instrumentation logging the ranges that take
+// longer than slow to read, with their call path, to log. Ranges aren't logged
+// if slow is 0.
+func NewInstrumentation(log *zap.Logger, slow time.Duration) *Instrumentation {
+	return &Instrumentation{log: log, slow: slow}
+}
+
+// Instrument returns rr recording the size of every range, how long it took
+// from the Range call until the reader was closed, and how many reads of the
+// underlying reader it took, in the series of name
+func (in *Instrumentation) Instrument(name string, rr Ranger) Ranger {
+	if in == nil {
+		return rr
+	}
+	return &instrumentedRanger{Ranger: rr, in: in, name: name}
+}
+
+type instrumentedRanger struct {
+	Ranger
+	in   *Instrumentation
+	name string
+}
+
+// Range implements Ranger.Range
+func (ir *instrumentedRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	start := time.Now()
+	var callers []uintptr
+	if ir.in.slow > 0 {
+		// the call path is only formatted for slow ranges
+		callers = make([]uintptr, maxCallers)
+		callers = callers[:runtime.Callers(2, callers)]
+	}
+
+	mon.IntVal(ir.name + "_range_size").Observe(length)
+	r, err := ir.Ranger.Range(ctx, offset, length)
+	if err != nil {
+		mon.Counter(ir.name + "_range_errors").Inc(1)
+		return nil, err
+	}
+	return &instrumentedReader{
+		r:       r,
+		ir:      ir,
+		offset:  offset,
+		length:  length,
+		start:   start,
+		callers: callers,
+	}, nil
+}
+
+// instrumentedReader records the reads of a range of an instrumentedRanger
+type instrumentedReader struct {
+	r       io.ReadCloser
+	ir      *instrumentedRanger
+	offset  int64
+	length  int64
+	start   time.Time
+	callers []uintptr
+
+	reads int64
+	read  int64
+	once  sync.Once
+}
+
+func (r *instrumentedReader) Read(p []byte) (n int, err error) {
+	n, err = r.r.Read(p)
+	r.reads++
+	r.read += int64(n)
+	return n, err
+}
+
+func (r *instrumentedReader) Close() error {
+	r.once.Do(r.record)
+	return r.r.Close()
+}
+
+// record records the range once it is closed
+func (r *instrumentedReader) record() {
+	took := time.Since(r.start)
+	name := r.ir.name
+	mon.FloatVal(name + "_range_seconds").Observe(took.Seconds())
+	mon.IntVal(name + "_range_reads").Observe(r.reads)
+	mon.IntVal(name + "_range_read").Observe(r.read)
+
+	if slow := r.ir.in.slow; slow > 0 && took > slow {
+		r.ir.in.log.Warn("slow range",
+			zap.String("ranger", name),
+			zap.Int64("offset", r.offset),
+			zap.Int64("length", r.length),
+			zap.Int64("read", r.read),
+			zap.Int64("reads", r.reads),
+			zap.Duration("took", took),
+			zap.String("callers", callPath(r.callers)))
+	}
+}
+
+// callPath formats the call path of callers, innermost first
+func callPath(callers []uintptr) string {
+	if len(callers) == 0 {
+		return ""
+	}
+	var path []string
+	frames := runtime.CallersFrames(callers)
+	for {
+		frame, more := frames.Next()
+		path = append(path, fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line))
+		if !more {
+			break
+		}
+	}
+	return strings.Join(path, " <- ")
+}
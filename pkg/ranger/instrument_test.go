@@ -0,0 +1,53 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ranger
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestInstrument(t *testing.T) {
+	ctx := context.Background()
+	rr := ByteRanger([]byte("abcdef"))
+
+	var in *Instrumentation
+	assert.Equal(t, rr, in.Instrument("bytes", rr))
+
+	var logs bytes.Buffer
+	log := zap.New(zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()),
+		zapcore.AddSync(&logs), zap.DebugLevel))
+
+	for _, slow := range []time.Duration{0, time.Hour, time.Nanosecond} {
+		logs.Reset()
+		irr := NewInstrumentation(log, slow).Instrument("bytes", rr)
+		assert.Equal(t, rr.Size(), irr.Size())
+
+		r, err := irr.Range(ctx, 1, 4)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		data, err := ioutil.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "bcde", string(data))
+		assert.NoError(t, r.Close())
+
+		if slow == time.Nanosecond {
+			assert.Contains(t, logs.String(), "slow range")
+			assert.Contains(t, logs.String(), "TestInstrument")
+		} else {
+			assert.Empty(t, logs.String())
+		}
+	}
+
+	_, err := NewInstrumentation(log, 0).Instrument("bytes", rr).Range(ctx, 0, 7)
+	assert.Error(t, err)
+}
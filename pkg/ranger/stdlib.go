@@ -0,0 +1,169 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ranger
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"time"
+
+	"storj.io/storj/pkg/utils"
+)
+
+// Reader reads a Ranger like a file, so that it can be passed to the
+// standard library: it is an io.ReadSeeker for http.ServeContent, an
+// io.ReaderAt for archive/zip and an io.WriterTo for io.Copy. Sequential
+// reads share a single range from the current offset to the end of the
+// ranger, which is reopened after seeks.
+type Reader struct {
+	ctx    context.Context
+	rr     Ranger
+	offset int64
+	r      io.ReadCloser
+}
+
+// NewReader returns a Reader of rr, whose ranges are read with ctx
+func NewReader(ctx context.Context, rr Ranger) *Reader {
+	return &Reader{ctx: ctx, rr: rr}
+}
+
+// Size returns the size of the ranger
+func (r *Reader) Size() int64 {
+	return r.rr.Size()
+}
+
+// open opens the range from the current offset to the end of the ranger, if
+// it isn't open
+func (r *Reader) open() (err error) {
+	if r.r == nil {
+		r.r, err = r.rr.Range(r.ctx, r.offset, r.rr.Size()-r.offset)
+	}
+	return err
+}
+
+// Read implements io.Reader
+func (r *Reader) Read(p []byte) (n int, err error) {
+	if r.offset >= r.rr.Size() {
+		return 0, io.EOF
+	}
+	if err := r.open(); err != nil {
+		return 0, err
+	}
+	n, err = r.r.Read(p)
+	r.offset += int64(n)
+	return n, err
+}
+
+// Seek implements io.Seeker. Seeking past the end is allowed, reads return
+// io.EOF there.
+func (r *Reader) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += r.offset
+	case io.SeekEnd:
+		offset += r.rr.Size()
+	default:
+		return r.offset, Error.New("invalid whence %d", whence)
+	}
+	if offset < 0 {
+		return r.offset, Error.New("negative offset")
+	}
+	if offset != r.offset {
+		if err := r.Close(); err != nil {
+			return r.offset, err
+		}
+		r.offset = offset
+	}
+	return offset, nil
+}
+
+// ReadAt implements io.ReaderAt. Every call reads a range of its own, so it
+// doesn't move the offset of Read and may be called concurrently.
+func (r *Reader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, Error.New("negative offset")
+	}
+	size := r.rr.Size()
+	if off >= size {
+		return 0, io.EOF
+	}
+	length := int64(len(p))
+	if length > size-off {
+		length = size - off
+	}
+	rc, err := r.rr.Range(r.ctx, off, length)
+	if err != nil {
+		return 0, err
+	}
+	n, err = io.ReadFull(rc, p[:length])
+	err = utils.CombineErrors(err, rc.Close())
+	if err == nil && n < len(p) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// WriteTo implements io.WriterTo. It writes the ranger from the current
+// offset to its end to w.
+func (r *Reader) WriteTo(w io.Writer) (n int64, err error) {
+	if r.offset >= r.rr.Size() {
+		return 0, nil
+	}
+	if err := r.open(); err != nil {
+		return 0, err
+	}
+	n, err = io.Copy(w, r.r)
+	r.offset += n
+	return n, err
+}
+
+// Close closes the range read by Read, if any. The Reader can still be
+// used after it is closed.
+func (r *Reader) Close() error {
+	if r.r == nil {
+		return nil
+	}
+	err := r.r.Close()
+	r.r = nil
+	return err
+}
+
+// File is a Reader with the methods of an http.File, so that a ranger can be
+// served by an http.FileSystem
+type File struct {
+	*Reader
+	name    string
+	modTime time.Time
+}
+
+var _ http.File = (*File)(nil)
+
+// NewFile returns a File of rr named name, last modified at modTime
+func NewFile(ctx context.Context, rr Ranger, name string, modTime time.Time) *File {
+	return &File{Reader: NewReader(ctx, rr), name: name, modTime: modTime}
+}
+
+// Readdir implements http.File. A File is never a directory.
+func (f *File) Readdir(count int) ([]os.FileInfo, error) {
+	return nil, Error.New("%s is not a directory", f.name)
+}
+
+// Stat implements http.File
+func (f *File) Stat() (os.FileInfo, error) {
+	return fileInfo{f}, nil
+}
+
+// fileInfo describes a File
+type fileInfo struct{ f *File }
+
+func (info fileInfo) Name() string       { return path.Base(info.f.name) }
+func (info fileInfo) Size() int64        { return info.f.Size() }
+func (info fileInfo) Mode() os.FileMode  { return 0444 }
+func (info fileInfo) ModTime() time.Time { return info.f.modTime }
+func (info fileInfo) IsDir() bool        { return false }
+func (info fileInfo) Sys() interface{}   { return nil }
@@ -0,0 +1,105 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ranger
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader(t *testing.T) {
+	ctx := context.Background()
+	r := NewReader(ctx, ByteRanger("abcdef"))
+	defer func() { assert.NoError(t, r.Close()) }()
+
+	buf := make([]byte, 2)
+	n, err := r.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", string(buf[:n]))
+
+	pos, err := r.Seek(1, io.SeekCurrent)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), pos)
+	rest, err := ioutil.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, "def", string(rest))
+
+	pos, err = r.Seek(-4, io.SeekEnd)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), pos)
+	var out bytes.Buffer
+	written, err := r.WriteTo(&out)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), written)
+	assert.Equal(t, "cdef", out.String())
+
+	_, err = r.Seek(-1, io.SeekStart)
+	assert.Error(t, err)
+	_, err = r.Seek(10, io.SeekStart)
+	assert.NoError(t, err)
+	_, err = r.Read(buf)
+	assert.Equal(t, io.EOF, err)
+
+	n, err = r.ReadAt(buf, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, "ef", string(buf[:n]))
+	n, err = r.ReadAt(buf, 5)
+	assert.Equal(t, io.EOF, err)
+	assert.Equal(t, "f", string(buf[:n]))
+	_, err = r.ReadAt(buf, 6)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestReaderZip(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	w, err := zw.Create("hello.txt")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("hello world"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	r := NewReader(context.Background(), ByteRanger(archive.Bytes()))
+	zr, err := zip.NewReader(r, r.Size())
+	require.NoError(t, err)
+	require.Len(t, zr.File, 1)
+	rc, err := zr.File[0].Open()
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.NoError(t, rc.Close())
+	assert.Equal(t, "hello world", string(data))
+}
+
+func TestFileServeContent(t *testing.T) {
+	modTime := time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	f := NewFile(context.Background(), ByteRanger("abcdef"), "dir/test.txt", modTime)
+
+	info, err := f.Stat()
+	require.NoError(t, err)
+	assert.Equal(t, "test.txt", info.Name())
+	assert.Equal(t, int64(6), info.Size())
+	assert.False(t, info.IsDir())
+	_, err = f.Readdir(0)
+	assert.Error(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/test.txt", nil)
+	req.Header.Set("Range", "bytes=2-4")
+	w := httptest.NewRecorder()
+	http.ServeContent(w, req, info.Name(), info.ModTime(), f)
+	assert.Equal(t, http.StatusPartialContent, w.Code)
+	assert.Equal(t, "cde", w.Body.String())
+	assert.Equal(t, "text/plain; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.NoError(t, f.Close())
+}
@@ -11,6 +11,7 @@ import (
 	"storj.io/storj/pkg/paths"
 	"storj.io/storj/pkg/ranger"
 	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/storage/streams"
 )
 
 type prefixedObjStore struct {
@@ -70,3 +71,37 @@ func (o *prefixedObjStore) List(ctx context.Context, prefix, startAfter,
 	defer mon.Task()(&ctx)(&err)
 	return o.o.List(ctx, prefix.Prepend(o.prefix), startAfter, endBefore, recursive, limit, metaFlags)
 }
+
+func (o *prefixedObjStore) PutPart(ctx context.Context, path paths.Path, uploadID string,
+	number int, data io.Reader, expiration time.Time) (part streams.Part, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return streams.Part{}, objects.NoPathError.New("")
+	}
+
+	return o.o.PutPart(ctx, path.Prepend(o.prefix), uploadID, number, data, expiration)
+}
+
+func (o *prefixedObjStore) CommitParts(ctx context.Context, path paths.Path, uploadID string,
+	parts []streams.Part, metadata objects.SerializableMeta, expiration time.Time) (
+	meta objects.Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return objects.Meta{}, objects.NoPathError.New("")
+	}
+
+	return o.o.CommitParts(ctx, path.Prepend(o.prefix), uploadID, parts, metadata, expiration)
+}
+
+func (o *prefixedObjStore) DeleteParts(ctx context.Context, path paths.Path, uploadID string,
+	parts []streams.Part) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return objects.NoPathError.New("")
+	}
+
+	return o.o.DeleteParts(ctx, path.Prepend(o.prefix), uploadID, parts)
+}
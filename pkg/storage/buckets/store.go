@@ -6,14 +6,18 @@ package buckets
 import (
 	"bytes"
 	"context"
+	"net/url"
+	"strconv"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	minio "github.com/minio/minio/cmd"
 	"github.com/zeebo/errs"
 
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
 	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/storage/meta"
 	"storj.io/storj/pkg/storage/objects"
 	"storj.io/storj/storage"
@@ -27,7 +31,7 @@ var NoBucketError = errs.Class("no bucket specified")
 // Store creates an interface for interacting with buckets
 type Store interface {
 	Get(ctx context.Context, bucket string) (meta Meta, err error)
-	Put(ctx context.Context, bucket string) (meta Meta, err error)
+	Put(ctx context.Context, bucket string, defaults Defaults) (meta Meta, err error)
 	Delete(ctx context.Context, bucket string) (err error)
 	List(ctx context.Context, startAfter, endBefore string, limit int) (items []ListItem, more bool, err error)
 	GetObjectStore(ctx context.Context, bucketName string) (store objects.Store, err error)
@@ -41,34 +45,178 @@ type ListItem struct {
 
 // BucketStore contains objects store
 type BucketStore struct {
-	o objects.Store
+	o        objects.Store
+	newStore ObjectStoreFunc
 }
 
 // Meta is the bucket metadata struct
 type Meta struct {
-	Created time.Time
+	Created  time.Time
+	Defaults Defaults
 }
 
+// Cipher is the encryption algorithm of objects
+type Cipher int
+
+const (
+	// Unencrypted means objects are stored without encryption
+	Unencrypted Cipher = iota
+	// AESGCM means objects are encrypted with AES-GCM
+	AESGCM
+	// SecretBox means objects are encrypted with NaCl secretbox
+	SecretBox
+	// AESGCMSIV means objects are encrypted with AES-GCM-SIV, which doesn't
+	// reveal the key stream when a nonce is reused
+	AESGCMSIV
+)
+
+// cipherNames are the names of the ciphers
+var cipherNames = map[Cipher]string{
+	Unencrypted: "none",
+	AESGCM:      "aesgcm",
+	SecretBox:   "secretbox",
+	AESGCMSIV:   "aesgcmsiv",
+}
+
+// String returns the name of the cipher
+func (cipher Cipher) String() string {
+	if name, ok := cipherNames[cipher]; ok {
+		return name
+	}
+	return "invalid"
+}
+
+// ParseCipher returns the cipher called name
+func ParseCipher(name string) (Cipher, error) {
+	for cipher, cipherName := range cipherNames {
+		if cipherName == name {
+			return cipher, nil
+		}
+	}
+	return Unencrypted, errs.New("invalid cipher %q", name)
+}
+
+// EncryptionScheme contains the encryption parameters of objects
+type EncryptionScheme struct {
+	Cipher    Cipher
+	BlockSize int
+}
+
+// Defaults are the settings of a bucket that override the uplink defaults
+// for the objects stored in it
+type Defaults struct {
+	// Redundancy is the redundancy scheme of the segments of the bucket's
+	// objects. If nil, the uplink's redundancy scheme is used.
+	Redundancy *pb.RedundancyScheme
+	// Encryption is the encryption of the bucket's objects. If nil, the
+	// uplink's encryption is used. It's applied by the clients that encrypt
+	// objects, like the proxy, which refuses uploads to buckets whose cipher
+	// it doesn't support.
+	Encryption *EncryptionScheme
+	// PartnerID is the partner that the usage of the bucket is attributed
+	// to, usually the tool the bucket was created with. Empty if the bucket
+	// isn't attributed.
+	PartnerID string
+	// Website is the static website configuration of the bucket. If nil,
+	// the bucket isn't served as a website.
+	Website *Website
+}
+
+// Validate checks that the redundancy scheme and encryption of defaults
+// can be used to store objects
+func (defaults Defaults) Validate() error {
+	if rs := defaults.Redundancy; rs != nil {
+		if rs.GetType() != pb.RedundancyScheme_RS {
+			return errs.New("unsupported redundancy scheme type %v", rs.GetType())
+		}
+		min, repair := rs.GetMinReq(), rs.GetRepairThreshold()
+		success, total := rs.GetSuccessThreshold(), rs.GetTotal()
+		if min <= 0 || min > repair || repair > success || success > total || total > 256 {
+			return errs.New("invalid redundancy scheme: 0 < min (%d) <= repair (%d) <= success (%d) <= total (%d) <= 256 doesn't hold",
+				min, repair, success, total)
+		}
+		if rs.GetErasureShareSize() <= 0 {
+			return errs.New("invalid erasure share size %d", rs.GetErasureShareSize())
+		}
+	}
+	if enc := defaults.Encryption; enc != nil {
+		if _, ok := cipherNames[enc.Cipher]; !ok {
+			return errs.New("invalid cipher %d", enc.Cipher)
+		}
+		if enc.BlockSize <= 0 {
+			return errs.New("invalid encryption block size %d", enc.BlockSize)
+		}
+	}
+	return nil
+}
+
+// ParseDefaults returns the defaults of a bucket from the metadata of the
+// pointer the bucket is stored at
+func ParseDefaults(pointerMetadata []byte) (Defaults, error) {
+	var stream pb.MetaStreamInfo
+	if err := proto.Unmarshal(pointerMetadata, &stream); err != nil {
+		return Defaults{}, err
+	}
+	var object objects.SerializableMeta
+	if err := proto.Unmarshal(stream.GetMetadata(), &object); err != nil {
+		return Defaults{}, err
+	}
+	return parseDefaults(object.GetUserDefined()), nil
+}
+
+// Website configures how a bucket is served as a static website
+type Website struct {
+	// IndexDocument is the object served for a path ending with a slash,
+	// relative to the path, like index.html
+	IndexDocument string
+	// ErrorDocument is the object served with status 404 for missing
+	// objects. If empty, a plain 404 is returned.
+	ErrorDocument string
+	// Redirects maps path prefixes to the prefixes that replace them in a
+	// redirect, like S3 routing rules with a KeyPrefixEquals condition
+	Redirects map[string]string
+}
+
+// ObjectStoreFunc creates an objects.Store that stores segments with the
+// redundancy scheme rs
+type ObjectStoreFunc func(rs *pb.RedundancyScheme) (objects.Store, error)
+
 // NewStore instantiates BucketStore
 func NewStore(obj objects.Store) Store {
 	return &BucketStore{o: obj}
 }
 
+// NewStoreWithDefaults instantiates a BucketStore that stores the objects of
+// buckets with a default redundancy scheme in an object store created by
+// newStore
+func NewStoreWithDefaults(obj objects.Store, newStore ObjectStoreFunc) Store {
+	return &BucketStore{o: obj, newStore: newStore}
+}
+
 // GetObjectStore returns an implementation of objects.Store
 func (b *BucketStore) GetObjectStore(ctx context.Context, bucket string) (objects.Store, error) {
 	if bucket == "" {
 		return nil, NoBucketError.New("")
 	}
 
-	_, err := b.Get(ctx, bucket)
+	m, err := b.Get(ctx, bucket)
 	if err != nil {
 		if storage.ErrKeyNotFound.Has(err) {
 			return nil, minio.BucketNotFound{Bucket: bucket}
 		}
 		return nil, err
 	}
+
+	o := b.o
+	if m.Defaults.Redundancy != nil && b.newStore != nil {
+		o, err = b.newStore(m.Defaults.Redundancy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	prefixed := prefixedObjStore{
-		o:      b.o,
+		o:      o,
 		prefix: bucket,
 	}
 	return &prefixed, nil
@@ -90,16 +238,21 @@ func (b *BucketStore) Get(ctx context.Context, bucket string) (meta Meta, err er
 }
 
 // Put calls objects store Put
-func (b *BucketStore) Put(ctx context.Context, bucket string) (meta Meta, err error) {
+func (b *BucketStore) Put(ctx context.Context, bucket string, defaults Defaults) (meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 	if bucket == "" {
 		return Meta{}, NoBucketError.New("")
 	}
+	if err := defaults.Validate(); err != nil {
+		return Meta{}, err
+	}
 
 	p := paths.New(bucket)
 	r := bytes.NewReader(nil)
 	var exp time.Time
-	m, err := b.o.Put(ctx, p, r, objects.SerializableMeta{}, exp)
+	m, err := b.o.Put(ctx, p, r, objects.SerializableMeta{
+		UserDefined: serializeDefaults(defaults),
+	}, exp)
 	if err != nil {
 		return Meta{}, err
 	}
@@ -141,6 +294,98 @@ func (b *BucketStore) List(ctx context.Context, startAfter, endBefore string, li
 // convertMeta converts stream metadata to object metadata
 func convertMeta(m objects.Meta) Meta {
 	return Meta{
-		Created: m.Modified,
+		Created:  m.Modified,
+		Defaults: parseDefaults(m.UserDefined),
+	}
+}
+
+// metadata keys of the bucket defaults
+const (
+	keyRSType    = "default-rs-type"
+	keyRSMinReq  = "default-rs-reqd"
+	keyRSTotal   = "default-rs-total"
+	keyRSRepair  = "default-rs-repair"
+	keyRSSuccess = "default-rs-success"
+	keyRSShare   = "default-rs-sharsz"
+	keyEncType   = "default-enc-type"
+	keyEncBlock  = "default-enc-blksz"
+	keyPartnerID = "partner-id"
+	keyWebIndex  = "website-index"
+	keyWebError  = "website-error"
+	keyWebRoutes = "website-redirects"
+)
+
+// serializeDefaults converts bucket defaults to user defined metadata
+func serializeDefaults(defaults Defaults) map[string]string {
+	m := map[string]string{}
+	if rs := defaults.Redundancy; rs != nil {
+		m[keyRSType] = strconv.Itoa(int(rs.GetType()))
+		m[keyRSMinReq] = strconv.Itoa(int(rs.GetMinReq()))
+		m[keyRSTotal] = strconv.Itoa(int(rs.GetTotal()))
+		m[keyRSRepair] = strconv.Itoa(int(rs.GetRepairThreshold()))
+		m[keyRSSuccess] = strconv.Itoa(int(rs.GetSuccessThreshold()))
+		m[keyRSShare] = strconv.Itoa(int(rs.GetErasureShareSize()))
+	}
+	if enc := defaults.Encryption; enc != nil {
+		m[keyEncType] = strconv.Itoa(int(enc.Cipher))
+		m[keyEncBlock] = strconv.Itoa(enc.BlockSize)
+	}
+	if defaults.PartnerID != "" {
+		m[keyPartnerID] = defaults.PartnerID
+	}
+	if web := defaults.Website; web != nil {
+		m[keyWebIndex] = web.IndexDocument
+		m[keyWebError] = web.ErrorDocument
+		redirects := url.Values{}
+		for prefix, replacement := range web.Redirects {
+			redirects.Set(prefix, replacement)
+		}
+		m[keyWebRoutes] = redirects.Encode()
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return m
+}
+
+// parseDefaults converts user defined metadata to bucket defaults. Buckets
+// created without defaults have no defaults.
+func parseDefaults(m map[string]string) (defaults Defaults) {
+	atoi := func(key string) int {
+		// the metadata is only ever written by serializeDefaults
+		v, _ := strconv.Atoi(m[key])
+		return v
+	}
+
+	if _, ok := m[keyRSType]; ok {
+		defaults.Redundancy = &pb.RedundancyScheme{
+			Type:             pb.RedundancyScheme_SchemeType(atoi(keyRSType)),
+			MinReq:           int32(atoi(keyRSMinReq)),
+			Total:            int32(atoi(keyRSTotal)),
+			RepairThreshold:  int32(atoi(keyRSRepair)),
+			SuccessThreshold: int32(atoi(keyRSSuccess)),
+			ErasureShareSize: int32(atoi(keyRSShare)),
+		}
+	}
+	if _, ok := m[keyEncType]; ok {
+		defaults.Encryption = &EncryptionScheme{
+			Cipher:    Cipher(atoi(keyEncType)),
+			BlockSize: atoi(keyEncBlock),
+		}
+	}
+	defaults.PartnerID = m[keyPartnerID]
+	if _, ok := m[keyWebIndex]; ok {
+		defaults.Website = &Website{
+			IndexDocument: m[keyWebIndex],
+			ErrorDocument: m[keyWebError],
+		}
+		redirects, _ := url.ParseQuery(m[keyWebRoutes])
+		if len(redirects) > 0 {
+			defaults.Website.Redirects = map[string]string{}
+			for prefix := range redirects {
+				defaults.Website.Redirects[prefix] = redirects.Get(prefix)
+			}
+		}
 	}
+	return defaults
 }
@@ -27,10 +27,13 @@ var mon = monkit.Package()
 // Client defines an interface for storing erasure coded data to piece store nodes
 type Client interface {
 	Put(ctx context.Context, nodes []*pb.Node, rs eestream.RedundancyStrategy,
-		pieceID client.PieceID, data io.Reader, expiration time.Time) error
+		pieceID client.PieceID, data io.Reader, expiration time.Time,
+		limits []*pb.PayerBandwidthAllocation) (roots [][]byte, err error)
 	Get(ctx context.Context, nodes []*pb.Node, es eestream.ErasureScheme,
-		pieceID client.PieceID, size int64) (ranger.Ranger, error)
-	Delete(ctx context.Context, nodes []*pb.Node, pieceID client.PieceID) error
+		pieceID client.PieceID, size int64,
+		limits []*pb.PayerBandwidthAllocation, roots [][]byte) (ranger.Ranger, error)
+	Delete(ctx context.Context, nodes []*pb.Node, pieceID client.PieceID,
+		limits []*pb.PayerBandwidthAllocation) error
 }
 
 type dialer interface {
@@ -53,77 +56,190 @@ func (d *defaultDialer) dial(ctx context.Context, node *pb.Node) (ps client.PSCl
 }
 
 type ecClient struct {
-	d   dialer
-	mbm int
+	d         dialer
+	mbm       int
+	limits    Limits
+	nodes     *nodeLimiters
+	bandwidth *bandwidthLimit
+
+	throughputs *throughputs
+	latencies   *latencies
 }
 
 // NewClient from the given TransportClient and max buffer memory
 func NewClient(identity *provider.FullIdentity, t transport.Client, mbm int) Client {
+	return NewClientWithLimits(identity, t, mbm, Limits{})
+}
+
+// NewClientWithLimits creates a client like NewClient whose transfers of
+// pieces are limited by limits
+func NewClientWithLimits(identity *provider.FullIdentity, t transport.Client, mbm int, limits Limits) Client {
 	d := defaultDialer{identity: identity, t: t}
-	return &ecClient{d: &d, mbm: mbm}
+	return &ecClient{
+		d:         &d,
+		mbm:       mbm,
+		limits:    limits,
+		nodes:     &nodeLimiters{n: limits.NodeConcurrency},
+		bandwidth: newBandwidthLimit(limits.MaxBandwidth),
+
+		throughputs: &throughputs{},
+		latencies:   &latencies{},
+	}
 }
 
+// Put uploads the erasure coded pieces of data to nodes, and returns the
+// roots of the Merkle trees of the pieces, which the pieces are challenged
+// against, nil for the nodes that didn't store their piece
 func (ec *ecClient) Put(ctx context.Context, nodes []*pb.Node, rs eestream.RedundancyStrategy,
-	pieceID client.PieceID, data io.Reader, expiration time.Time) (err error) {
+	pieceID client.PieceID, data io.Reader, expiration time.Time,
+	limits []*pb.PayerBandwidthAllocation) (roots [][]byte, err error) {
 	defer mon.Task()(&ctx)(&err)
 	if len(nodes) != rs.TotalCount() {
-		return Error.New("number of nodes (%d) do not match total count (%d) of erasure scheme",
+		return nil, Error.New("number of nodes (%d) do not match total count (%d) of erasure scheme",
 			len(nodes), rs.TotalCount())
 	}
 	if !unique(nodes) {
-		return Error.New("duplicated nodes are not allowed")
+		return nil, Error.New("duplicated nodes are not allowed")
 	}
 	padded := eestream.PadReader(ioutil.NopCloser(data), rs.DecodedBlockSize())
 	readers, err := eestream.EncodeReader(ctx, padded, rs, ec.mbm)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	errs := make(chan error, len(readers))
-	for i, n := range nodes {
-		go func(i int, n *pb.Node) {
+	// the pieces are encoded in lockstep, so the pieces over the upload
+	// concurrency aren't uploaded at all. the encoder drops them like the
+	// pieces of slow nodes.
+	count := len(readers)
+	if limit := ec.limits.UploadConcurrency; limit > 0 && limit < count {
+		count = limit
+		if count < rs.OptimumThreshold() {
+			count = rs.OptimumThreshold()
+		}
+	}
+	// every piece can be canceled on its own once it isn't needed anymore
+	cancels := make([]context.CancelFunc, count)
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+	start := time.Now()
+	results := make(chan pieceResult, count)
+	for i, n := range nodes[:count] {
+		var pieceCtx context.Context
+		pieceCtx, cancels[i] = context.WithCancel(ctx)
+		go func(pieceCtx context.Context, i int, n *pb.Node) {
 			derivedPieceID, err := pieceID.Derive([]byte(n.GetId()))
 			if err != nil {
 				zap.S().Errorf("Failed deriving piece id for %s: %v", pieceID, err)
-				errs <- err
+				results <- pieceResult{i: i, err: err}
 				return
 			}
-			ps, err := ec.d.dial(ctx, n)
+			limit := ec.nodes.limiter(n.GetId())
+			if err := limit.acquire(pieceCtx); err != nil {
+				results <- pieceResult{i: i, err: err}
+				return
+			}
+			defer limit.release()
+			ps, err := ec.d.dial(pieceCtx, n)
 			if err != nil {
 				zap.S().Errorf("Failed putting piece %s -> %s to node %s: %v",
 					pieceID, derivedPieceID, n.GetId(), err)
-				errs <- err
+				results <- pieceResult{i: i, err: err}
 				return
 			}
-			err = ps.Put(ctx, derivedPieceID, readers[i], expiration, &pb.PayerBandwidthAllocation{})
+			leaves := eestream.NewMerkleHasher()
+			data := &countingReader{r: io.TeeReader(readers[i], leaves)}
+			err = ps.Put(pieceCtx, derivedPieceID, throttle(pieceCtx, data, ec.bandwidth), expiration, orderLimit(limits, i))
 			// normally the bellow call should be deferred, but doing so fails
 			// randomly the unit tests
 			utils.LogClose(ps)
-			if err != nil {
+			took := time.Since(start)
+			switch {
+			case client.IsBusy(err):
+				// the other nodes are expected to make up for busy ones
+				zap.S().Debugf("Node %s is busy, skipped piece %s -> %s",
+					n.GetId(), pieceID, derivedPieceID)
+			case err != nil && pieceCtx.Err() != nil && ctx.Err() == nil:
+				zap.S().Debugf("Canceled putting piece %s -> %s to straggling node %s",
+					pieceID, derivedPieceID, n.GetId())
+				mon.Counter("long_tail_canceled").Inc(1)
+			case err != nil:
 				zap.S().Errorf("Failed putting piece %s -> %s to node %s: %v",
 					pieceID, derivedPieceID, n.GetId(), err)
+			default:
+				ec.throughputs.add(n.GetId(), data.n, took)
 			}
-			errs <- err
-		}(i, n)
+			results <- pieceResult{i: i, err: err, size: data.n, took: took,
+				root: eestream.MerkleRoot(leaves.Leaves())}
+		}(pieceCtx, i, n)
+	}
+
+	var allerrs []error
+	var finished []time.Duration
+	roots = make([][]byte, len(nodes))
+	var cutoff <-chan time.Time
+	pending := make(map[int]bool, count)
+	for i := 0; i < count; i++ {
+		pending[i] = true
+	}
+	for len(pending) > 0 {
+		select {
+		case res := <-results:
+			delete(pending, res.i)
+			if res.err != nil {
+				allerrs = append(allerrs, res.err)
+				continue
+			}
+			roots[res.i] = res.root
+			finished = append(finished, res.took)
+			if ec.limits.LongTailDeviations > 0 && len(finished) == rs.MinimumThreshold() && len(pending) > 0 {
+				cutoff = ec.cutLongTail(nodes, pending, cancels, finished, res.size, start)
+			}
+		case <-cutoff:
+			for i := range pending {
+				cancels[i]()
+			}
+			cutoff = nil
+		}
 	}
-	allerrs := collectErrors(errs, len(readers))
-	sc := len(readers) - len(allerrs)
+	sc := count - len(allerrs)
 	if sc < rs.MinimumThreshold() {
-		return Error.New(
+		return nil, Error.New(
 			"successful puts (%d) less than minimum threshold (%d)",
 			sc, rs.MinimumThreshold())
 	}
-	return nil
+	return roots, nil
+}
+
+// cutLongTail cancels the pending pieces whose nodes aren't expected to
+// finish before the long tail deadline computed from the finished pieces,
+// and returns when to cancel the others
+func (ec *ecClient) cutLongTail(nodes []*pb.Node, pending map[int]bool, cancels []context.CancelFunc,
+	finished []time.Duration, size int64, start time.Time) <-chan time.Time {
+	deadline := longTailDeadline(finished, ec.limits.LongTailDeviations)
+	for i := range pending {
+		expected, ok := ec.throughputs.expected(nodes[i].GetId(), size)
+		if ok && expected > deadline {
+			cancels[i]()
+		}
+	}
+	return time.After(time.Until(start.Add(deadline)))
 }
 
+// Get returns a ranger of the data erasure coded in the pieces on nodes. The
+// pieces with the roots of their Merkle trees in roots are verified against
+// them as they are downloaded.
 func (ec *ecClient) Get(ctx context.Context, nodes []*pb.Node, es eestream.ErasureScheme,
-	pieceID client.PieceID, size int64) (rr ranger.Ranger, err error) {
+	pieceID client.PieceID, size int64,
+	limits []*pb.PayerBandwidthAllocation, roots [][]byte) (rr ranger.Ranger, err error) {
 	defer mon.Task()(&ctx)(&err)
 
 	if len(nodes) != es.TotalCount() {
 		return nil, Error.New("number of nodes (%v) do not match total count (%v) of erasure scheme", len(nodes), es.TotalCount())
 	}
 	paddedSize := calcPadded(size, es.DecodedBlockSize())
-	pieceSize := paddedSize / int64(es.RequiredCount())
+	pieceSize := paddedSize / int64(es.DecodedBlockSize()) * int64(es.EncodedBlockSize())
 	rrs := map[int]ranger.Ranger{}
 	type rangerInfo struct {
 		i   int
@@ -141,11 +257,16 @@ func (ec *ecClient) Get(ctx context.Context, nodes []*pb.Node, es eestream.Erasu
 			}
 
 			rr := &lazyPieceRanger{
-				dialer: ec.d,
-				node:   n,
-				id:     derivedPieceID,
-				size:   pieceSize,
-				pba:    &pb.PayerBandwidthAllocation{},
+				dialer:    ec.d,
+				node:      n,
+				id:        derivedPieceID,
+				size:      pieceSize,
+				pba:       orderLimit(limits, i),
+				limit:     ec.nodes.limiter(n.GetId()),
+				bandwidth: ec.bandwidth,
+			}
+			if i < len(roots) {
+				rr.root = roots[i]
 			}
 
 			ch <- rangerInfo{i: i, rr: rr, err: nil}
@@ -157,6 +278,19 @@ func (ec *ecClient) Get(ctx context.Context, nodes []*pb.Node, es eestream.Erasu
 			rrs[rri.i] = rri.rr
 		}
 	}
+	if limit := ec.limits.DownloadConcurrency; limit > 0 {
+		if limit < es.RequiredCount() {
+			limit = es.RequiredCount()
+		}
+		if ec.limits.HedgePercentile > 0 && limit < len(rrs) {
+			rr, err = newHedgedRanger(rrs, limit, es, ec.mbm, ec.hedgeDelay, ec.latencies)
+			if err != nil {
+				return nil, err
+			}
+			return eestream.Unpad(rr, int(paddedSize-size))
+		}
+		rrs = firstRangers(rrs, limit)
+	}
 	rr, err = eestream.Decode(rrs, es, ec.mbm)
 	if err != nil {
 		return nil, err
@@ -164,17 +298,24 @@ func (ec *ecClient) Get(ctx context.Context, nodes []*pb.Node, es eestream.Erasu
 	return eestream.Unpad(rr, int(paddedSize-size))
 }
 
-func (ec *ecClient) Delete(ctx context.Context, nodes []*pb.Node, pieceID client.PieceID) (err error) {
+func (ec *ecClient) Delete(ctx context.Context, nodes []*pb.Node, pieceID client.PieceID,
+	limits []*pb.PayerBandwidthAllocation) (err error) {
 	defer mon.Task()(&ctx)(&err)
 	errs := make(chan error, len(nodes))
-	for _, n := range nodes {
-		go func(n *pb.Node) {
+	for i, n := range nodes {
+		go func(i int, n *pb.Node) {
 			derivedPieceID, err := pieceID.Derive([]byte(n.GetId()))
 			if err != nil {
 				zap.S().Errorf("Failed deriving piece id for %s: %v", pieceID, err)
 				errs <- err
 				return
 			}
+			limit := ec.nodes.limiter(n.GetId())
+			if err := limit.acquire(ctx); err != nil {
+				errs <- err
+				return
+			}
+			defer limit.release()
 			ps, err := ec.d.dial(ctx, n)
 			if err != nil {
 				zap.S().Errorf("Failed deleting piece %s -> %s from node %s: %v",
@@ -182,7 +323,7 @@ func (ec *ecClient) Delete(ctx context.Context, nodes []*pb.Node, pieceID client
 				errs <- err
 				return
 			}
-			err = ps.Delete(ctx, derivedPieceID)
+			err = ps.Delete(ctx, derivedPieceID, orderLimit(limits, i))
 			// normally the bellow call should be deferred, but doing so fails
 			// randomly the unit tests
 			utils.LogClose(ps)
@@ -191,7 +332,7 @@ func (ec *ecClient) Delete(ctx context.Context, nodes []*pb.Node, pieceID client
 					pieceID, derivedPieceID, n.GetId(), err)
 			}
 			errs <- err
-		}(n)
+		}(i, n)
 	}
 	allerrs := collectErrors(errs, len(nodes))
 	if len(allerrs) > 0 && len(allerrs) == len(nodes) {
@@ -232,6 +373,46 @@ func unique(nodes []*pb.Node) bool {
 	return true
 }
 
+// orderLimit returns the order limit for the i-th node, or an empty one if
+// there is none
+func orderLimit(limits []*pb.PayerBandwidthAllocation, i int) *pb.PayerBandwidthAllocation {
+	if i < len(limits) && limits[i] != nil {
+		return limits[i]
+	}
+	return &pb.PayerBandwidthAllocation{}
+}
+
+// hedgeDelay returns how long a piece download may take to return its first
+// byte before an alternate piece is downloaded, or 0 while too few downloads
+// were made to tell
+func (ec *ecClient) hedgeDelay() time.Duration {
+	delay, ok := ec.latencies.percentile(ec.limits.HedgePercentile)
+	if !ok {
+		return 0
+	}
+	if delay < ec.limits.HedgeMinDelay {
+		delay = ec.limits.HedgeMinDelay
+	}
+	return delay
+}
+
+// firstRangers returns the rangers of the n pieces with the lowest numbers
+func firstRangers(rrs map[int]ranger.Ranger, n int) map[int]ranger.Ranger {
+	if len(rrs) <= n {
+		return rrs
+	}
+	nums := make([]int, 0, len(rrs))
+	for num := range rrs {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	first := make(map[int]ranger.Ranger, n)
+	for _, num := range nums[:n] {
+		first[num] = rrs[num]
+	}
+	return first
+}
+
 func calcPadded(size int64, blockSize int) int64 {
 	mod := size % int64(blockSize)
 	if mod == 0 {
@@ -241,12 +422,17 @@ func calcPadded(size int64, blockSize int) int64 {
 }
 
 type lazyPieceRanger struct {
-	ranger ranger.Ranger
-	dialer dialer
-	node   *pb.Node
-	id     client.PieceID
-	size   int64
-	pba    *pb.PayerBandwidthAllocation
+	ranger    ranger.Ranger
+	dialer    dialer
+	node      *pb.Node
+	id        client.PieceID
+	size      int64
+	pba       *pb.PayerBandwidthAllocation
+	limit     limiter
+	bandwidth *bandwidthLimit
+	// root is the root of the Merkle tree of the piece, nil if unknown
+	root []byte
+	ps   client.PSClient
 }
 
 // Size implements Ranger.Size
@@ -254,8 +440,18 @@ func (lr *lazyPieceRanger) Size() int64 {
 	return lr.size
 }
 
-// Range implements Ranger.Range to be lazily connected
-func (lr *lazyPieceRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+// Range implements Ranger.Range to be lazily connected. The returned reader
+// holds a slot of the concurrency limit of the node until it is closed.
+func (lr *lazyPieceRanger) Range(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	if err := lr.limit.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			lr.limit.release()
+		}
+	}()
+
 	if lr.ranger == nil {
 		ps, err := lr.dialer.dial(ctx, lr.node)
 		if err != nil {
@@ -265,7 +461,19 @@ func (lr *lazyPieceRanger) Range(ctx context.Context, offset, length int64) (io.
 		if err != nil {
 			return nil, err
 		}
-		lr.ranger = ranger
+		lr.ranger, lr.ps = ranger, ps
+	}
+	var r io.ReadCloser
+	if len(lr.root) > 0 && length > 0 {
+		r, err = lr.verifiedRange(ctx, offset, length)
+	} else {
+		r, err = lr.ranger.Range(ctx, offset, length)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if lr.limit == nil && lr.bandwidth == nil {
+		return r, nil
 	}
-	return lr.ranger.Range(ctx, offset, length)
+	return &limitedReadCloser{Reader: throttle(ctx, r, lr.bandwidth), closer: r, limit: lr.limit}, nil
 }
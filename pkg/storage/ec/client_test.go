@@ -11,6 +11,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"testing"
 	"time"
 
@@ -185,12 +186,13 @@ TestLoop:
 		}
 		r := io.LimitReader(rand.Reader, int64(size))
 		ec := ecClient{d: &mockDialer{m: m}, mbm: tt.mbm}
-		err = ec.Put(ctx, tt.nodes, rs, id, r, ttl)
+		roots, err := ec.Put(ctx, tt.nodes, rs, id, r, ttl, nil)
 
 		if tt.errString != "" {
 			assert.EqualError(t, err, tt.errString, errTag)
 		} else {
 			assert.NoError(t, err, errTag)
+			assert.Len(t, roots, len(tt.nodes), errTag)
 		}
 	}
 }
@@ -254,7 +256,7 @@ TestLoop:
 			}
 		}
 		ec := ecClient{d: &mockDialer{m: m}, mbm: tt.mbm}
-		rr, err := ec.Get(ctx, tt.nodes, es, id, int64(size))
+		rr, err := ec.Get(ctx, tt.nodes, es, id, int64(size), nil, nil)
 		if err == nil {
 			_, err := rr.Range(ctx, 0, 0)
 			assert.NoError(t, err, errTag)
@@ -298,8 +300,11 @@ TestLoop:
 			errs[n] = tt.errs[i]
 		}
 
+		// every node gets its own order limit
+		limits := make([]*pb.PayerBandwidthAllocation, len(tt.nodes))
 		m := make(map[*pb.Node]client.PSClient, len(tt.nodes))
-		for _, n := range tt.nodes {
+		for i, n := range tt.nodes {
+			limits[i] = &pb.PayerBandwidthAllocation{Signature: []byte(n.GetId())}
 			if errs[n] != ErrDialFailed {
 				derivedID, err := id.Derive([]byte(n.GetId()))
 				if !assert.NoError(t, err, errTag) {
@@ -307,7 +312,7 @@ TestLoop:
 				}
 				ps := NewMockPSClient(ctrl)
 				gomock.InOrder(
-					ps.EXPECT().Delete(gomock.Any(), derivedID).Return(errs[n]),
+					ps.EXPECT().Delete(gomock.Any(), derivedID, limits[i]).Return(errs[n]),
 					ps.EXPECT().Close().Return(nil),
 				)
 				m[n] = ps
@@ -315,7 +320,7 @@ TestLoop:
 		}
 
 		ec := ecClient{d: &mockDialer{m: m}}
-		err := ec.Delete(ctx, tt.nodes, id)
+		err := ec.Delete(ctx, tt.nodes, id, limits)
 
 		if tt.errString != "" {
 			assert.EqualError(t, err, tt.errString, errTag)
@@ -347,3 +352,156 @@ func TestUnique(t *testing.T) {
 		assert.Equal(t, tt.unique, unique(tt.nodes), errTag)
 	}
 }
+
+func TestPutUploadConcurrency(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	size := 32 * 1024
+	fc, err := infectious.NewFEC(2, 4)
+	if !assert.NoError(t, err) {
+		return
+	}
+	rs, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, size/4), 2, 3)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	id := client.NewPieceID()
+	ttl := time.Now()
+	nodes := []*pb.Node{node0, node1, node2, node3}
+
+	// the concurrency is raised to the success threshold, so the pieces are
+	// uploaded to the first 3 nodes only
+	m := map[*pb.Node]client.PSClient{}
+	for _, n := range nodes[:3] {
+		derivedID, err := id.Derive([]byte(n.GetId()))
+		if !assert.NoError(t, err) {
+			return
+		}
+		ps := NewMockPSClient(ctrl)
+		gomock.InOrder(
+			ps.EXPECT().Put(gomock.Any(), derivedID, gomock.Any(), ttl, gomock.Any()).Return(nil),
+			ps.EXPECT().Close().Return(nil),
+		)
+		m[n] = ps
+	}
+
+	ec := ecClient{
+		d:      &mockDialer{m: m},
+		limits: Limits{UploadConcurrency: 1, NodeConcurrency: 1},
+		nodes:  &nodeLimiters{n: 1},
+	}
+	r := io.LimitReader(rand.Reader, int64(size))
+	_, err = ec.Put(ctx, nodes, rs, id, r, ttl, nil)
+	assert.NoError(t, err)
+}
+
+func TestLimits(t *testing.T) {
+	ctx := context.Background()
+
+	rrs := map[int]ranger.Ranger{}
+	for _, num := range []int{5, 1, 3, 0} {
+		rrs[num] = ranger.ByteRanger(nil)
+	}
+	first := firstRangers(rrs, 2)
+	assert.Len(t, first, 2)
+	assert.Contains(t, first, 0)
+	assert.Contains(t, first, 1)
+	assert.Len(t, firstRangers(rrs, 10), 4)
+
+	nodes := &nodeLimiters{n: 1}
+	assert.Equal(t, nodes.limiter("node-0"), nodes.limiter("node-0"))
+	assert.NotEqual(t, nodes.limiter("node-0"), nodes.limiter("node-1"))
+	assert.Nil(t, (&nodeLimiters{}).limiter("node-0"))
+
+	limit := nodes.limiter("node-0")
+	assert.NoError(t, limit.acquire(ctx))
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.Error(t, limit.acquire(canceled))
+	limit.release()
+
+	// the bandwidth is shared by all readers
+	assert.Nil(t, newBandwidthLimit(0))
+	bandwidth := newBandwidthLimit(100 * 1024)
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		r := throttle(ctx, io.LimitReader(rand.Reader, 10*1024), bandwidth)
+		_, err := io.Copy(ioutil.Discard, r)
+		assert.NoError(t, err)
+	}
+	assert.True(t, time.Since(start) >= 150*time.Millisecond)
+}
+
+func TestPutLongTail(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	size := 32 * 1024
+	fc, err := infectious.NewFEC(2, 4)
+	if !assert.NoError(t, err) {
+		return
+	}
+	rs, err := eestream.NewRedundancyStrategy(eestream.NewRSScheme(fc, size/4), 2, 4)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	id := client.NewPieceID()
+	ttl := time.Now()
+	nodes := []*pb.Node{node0, node1, node2, node3}
+
+	// the first 2 nodes are fast, the others hang until they are canceled
+	m := map[*pb.Node]client.PSClient{}
+	for i, n := range nodes {
+		ps := NewMockPSClient(ctrl)
+		put := ps.EXPECT().Put(gomock.Any(), gomock.Any(), gomock.Any(), ttl, gomock.Any())
+		if i < 2 {
+			put.Return(nil)
+		} else {
+			put.Do(func(ctx context.Context, id client.PieceID, data io.Reader, ttl time.Time, ba *pb.PayerBandwidthAllocation) {
+				<-ctx.Done()
+			}).Return(context.Canceled)
+		}
+		ps.EXPECT().Close().Return(nil)
+		m[n] = ps
+	}
+
+	// node-3 is known to be slow and canceled right away, node-2 at the
+	// deadline
+	throughputs := &throughputs{}
+	throughputs.add(node3.GetId(), 1, time.Hour)
+	ec := ecClient{
+		d:           &mockDialer{m: m},
+		limits:      Limits{LongTailDeviations: 1},
+		throughputs: throughputs,
+	}
+	start := time.Now()
+	r := io.LimitReader(rand.Reader, int64(size))
+	_, err = ec.Put(ctx, nodes, rs, id, r, ttl, nil)
+	assert.NoError(t, err)
+	assert.True(t, time.Since(start) < 5*time.Second)
+
+	// canceled uploads don't count as throughput
+	_, ok := throughputs.expected(node2.GetId(), 1024)
+	assert.False(t, ok)
+}
+
+func TestLongTailDeadline(t *testing.T) {
+	assert.Equal(t, time.Duration(0), longTailDeadline(nil, 1))
+	finished := []time.Duration{time.Second, 3 * time.Second}
+	assert.Equal(t, 2*time.Second, longTailDeadline(finished, 0))
+	assert.Equal(t, 4*time.Second, longTailDeadline(finished, 2))
+
+	throughputs := &throughputs{}
+	throughputs.add("node", 1000, time.Second)
+	expected, ok := throughputs.expected("node", 2000)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Second, expected)
+	throughputs.add("node", 500, time.Second)
+	expected, _ = throughputs.expected("node", 900)
+	assert.Equal(t, time.Second, expected)
+}
@@ -0,0 +1,384 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ecclient
+
+import (
+	"context"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/ranger"
+)
+
+var (
+	// errHedgeUnneeded is returned by the downloads of alternate pieces that
+	// weren't needed
+	errHedgeUnneeded = errs.New("alternate piece not needed")
+	// errHedgeLost is returned by the download of a piece whose rival
+	// returned its first byte first
+	errHedgeLost = errs.New("hedged piece download lost")
+)
+
+const (
+	// latencySamples is how many recent times to first byte are kept
+	latencySamples = 100
+	// minLatencySamples is how many times to first byte are needed before
+	// downloads are hedged for being slow
+	minLatencySamples = 10
+)
+
+// latencies keeps the recent times to first byte of piece downloads. The nil
+// latencies doesn't keep anything.
+type latencies struct {
+	mu      sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+// add accounts a download that returned its first byte after took
+func (l *latencies) add(took time.Duration) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.samples) < latencySamples {
+		l.samples = append(l.samples, took)
+		return
+	}
+	l.samples[l.next] = took
+	l.next = (l.next + 1) % latencySamples
+}
+
+// percentile returns the p-th percentile, between 0 and 100, of the recent
+// times to first byte, or false if there are too few of them
+func (l *latencies) percentile(p float64) (time.Duration, bool) {
+	if l == nil {
+		return 0, false
+	}
+	l.mu.Lock()
+	sorted := append([]time.Duration(nil), l.samples...)
+	l.mu.Unlock()
+	if len(sorted) < minLatencySamples {
+		return 0, false
+	}
+	sort.Slice(sorted, func(i, k int) bool { return sorted[i] < sorted[k] })
+	i := int(p / 100 * float64(len(sorted)))
+	if i >= len(sorted) {
+		i = len(sorted) - 1
+	}
+	return sorted[i], true
+}
+
+// hedgedRanger decodes a segment from its primary pieces, and downloads an
+// alternate piece in place of every primary piece that doesn't return its
+// first byte within the hedge delay or fails. Of a slow piece and its
+// alternate, the one returning its first byte first is used and the other is
+// canceled.
+type hedgedRanger struct {
+	rrs       map[int]ranger.Ranger
+	primaries int
+	es        eestream.ErasureScheme
+	mbm       int
+	size      int64
+	delay     func() time.Duration
+	latencies *latencies
+}
+
+// newHedgedRanger returns a ranger decoding rrs, downloading the pieces with
+// the n lowest numbers first and the others as alternates. delay returns the
+// current hedge delay, 0 to hedge only failed pieces.
+func newHedgedRanger(rrs map[int]ranger.Ranger, n int, es eestream.ErasureScheme, mbm int,
+	delay func() time.Duration, latencies *latencies) (ranger.Ranger, error) {
+	rr, err := eestream.Decode(rrs, es, mbm)
+	if err != nil {
+		return nil, err
+	}
+	return &hedgedRanger{
+		rrs:       rrs,
+		primaries: n,
+		es:        es,
+		mbm:       mbm,
+		size:      rr.Size(),
+		delay:     delay,
+		latencies: latencies,
+	}, nil
+}
+
+func (hr *hedgedRanger) Size() int64 {
+	return hr.size
+}
+
+func (hr *hedgedRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	nums := make([]int, 0, len(hr.rrs))
+	for num := range hr.rrs {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+
+	h := &hedge{latencies: hr.latencies}
+	delay := hr.delay()
+	pieces := make(map[int]ranger.Ranger, len(nums))
+	for i, num := range nums {
+		piece := &hedgedPiece{ranger: hr.rrs[num], hedge: h}
+		if i < hr.primaries {
+			piece.delay = delay
+			h.outstanding++
+		} else {
+			piece.start = make(chan bool, 1)
+			h.waiting = append(h.waiting, piece)
+		}
+		pieces[num] = piece
+	}
+
+	rr, err := eestream.Decode(pieces, hr.es, hr.mbm)
+	if err != nil {
+		return nil, err
+	}
+	return rr.Range(ctx, offset, length)
+}
+
+// hedge hedges the downloads of the pieces of a range of a segment
+type hedge struct {
+	latencies *latencies
+
+	mu sync.Mutex
+	// outstanding counts the primary pieces that neither returned their
+	// first byte nor were hedged
+	outstanding int
+	// waiting are the alternate pieces that weren't started
+	waiting []*hedgedPiece
+}
+
+// firstByte accounts the first byte returned by piece, and cancels its rival
+func (h *hedge) firstByte(piece *hedgedPiece) {
+	h.latencies.add(time.Since(piece.begun))
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	piece.first = true
+	if piece.rival != nil && !piece.rival.first {
+		piece.rival.lose()
+	}
+	if piece.primary() && !piece.resolved {
+		piece.resolve()
+		h.outstanding--
+		h.settle()
+	}
+}
+
+// slow hedges the primary piece that didn't return its first byte within the
+// hedge delay
+func (h *hedge) slow(piece *hedgedPiece) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if piece.resolved {
+		return
+	}
+	piece.resolve()
+	h.outstanding--
+	mon.Counter("hedged_slow_pieces").Inc(1)
+	h.startAlternate(piece)
+	h.settle()
+}
+
+// failed replaces the failed piece with an alternate piece, unless its rival
+// is still downloaded, and returns whether the piece lost against its rival
+func (h *hedge) failed(piece *hedgedPiece) (lost bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if piece.lost || piece.failed {
+		return piece.lost
+	}
+	piece.failed = true
+	if piece.primary() && !piece.resolved {
+		piece.resolve()
+		h.outstanding--
+	}
+	if piece.rival == nil || piece.rival.lost || piece.rival.failed {
+		mon.Counter("hedged_failed_pieces").Inc(1)
+		h.startAlternate(nil)
+	}
+	h.settle()
+	return false
+}
+
+// startAlternate starts the download of an alternate piece racing rival, if
+// not nil
+func (h *hedge) startAlternate(rival *hedgedPiece) {
+	if len(h.waiting) == 0 {
+		return
+	}
+	alternate := h.waiting[0]
+	h.waiting = h.waiting[1:]
+	if rival != nil {
+		alternate.rival, rival.rival = rival, alternate
+	}
+	alternate.start <- true
+}
+
+// settle dismisses the alternate pieces once every primary piece returned its
+// first byte or was hedged, so that the decoder doesn't wait for them
+func (h *hedge) settle() {
+	if h.outstanding > 0 {
+		return
+	}
+	for _, alternate := range h.waiting {
+		alternate.start <- false
+	}
+	h.waiting = nil
+}
+
+// hedgedPiece downloads a piece of a range of a hedgedRanger. It is both the
+// ranger of the piece passed to the decoder and the reader it returns.
+type hedgedPiece struct {
+	ranger ranger.Ranger
+	hedge  *hedge
+	// delay is the hedge delay of a primary piece
+	delay time.Duration
+	// start receives whether an alternate piece is downloaded. It is nil
+	// for primary pieces.
+	start chan bool
+
+	// set by Range
+	offset int64
+	length int64
+	timer  *time.Timer
+	// read by the reading goroutine only
+	r     io.ReadCloser
+	begun time.Time
+	read  bool
+
+	// guarded by the mutex of the hedge
+	ctx      context.Context
+	cancel   func()
+	resolved bool
+	first    bool
+	failed   bool
+	lost     bool
+	rival    *hedgedPiece
+	closed   bool
+}
+
+func (hp *hedgedPiece) Size() int64 {
+	return hp.ranger.Size()
+}
+
+// Range returns the piece itself, which starts its download on its first
+// read, so that the pieces are hedged independently of how long they take to
+// connect
+func (hp *hedgedPiece) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	ctx, cancel := context.WithCancel(ctx)
+
+	hp.hedge.mu.Lock()
+	defer hp.hedge.mu.Unlock()
+	hp.ctx, hp.cancel = ctx, cancel
+	hp.offset, hp.length = offset, length
+	hp.begun = time.Now()
+	if hp.lost {
+		cancel()
+	}
+	if hp.primary() && hp.delay > 0 && !hp.resolved {
+		hp.timer = time.AfterFunc(hp.delay, func() { hp.hedge.slow(hp) })
+	}
+	return hp, nil
+}
+
+// primary returns whether the piece is downloaded from the start
+func (hp *hedgedPiece) primary() bool {
+	return hp.start == nil
+}
+
+// resolve stops the hedge timer of the piece
+func (hp *hedgedPiece) resolve() {
+	hp.resolved = true
+	if hp.timer != nil {
+		hp.timer.Stop()
+	}
+}
+
+// lose cancels the piece, whose rival returned its first byte first
+func (hp *hedgedPiece) lose() {
+	hp.lost = true
+	hp.resolve()
+	if hp.cancel != nil {
+		hp.cancel()
+	}
+}
+
+func (hp *hedgedPiece) Read(p []byte) (n int, err error) {
+	if hp.r == nil {
+		if err := hp.open(); err != nil {
+			return 0, hp.fail(err)
+		}
+	}
+	n, err = hp.r.Read(p)
+	if n > 0 && !hp.read {
+		hp.read = true
+		hp.hedge.firstByte(hp)
+	}
+	if err != nil && err != io.EOF {
+		return n, hp.fail(err)
+	}
+	return n, err
+}
+
+// open starts the download of the piece, once started for alternate pieces
+func (hp *hedgedPiece) open() error {
+	if !hp.primary() {
+		select {
+		case start := <-hp.start:
+			if !start {
+				return errHedgeUnneeded
+			}
+			hp.begun = time.Now()
+		case <-hp.ctx.Done():
+			return hp.ctx.Err()
+		}
+	}
+
+	r, err := hp.ranger.Range(hp.ctx, hp.offset, hp.length)
+	if err != nil {
+		return err
+	}
+	hp.hedge.mu.Lock()
+	defer hp.hedge.mu.Unlock()
+	if hp.closed {
+		_ = r.Close()
+		return io.ErrClosedPipe
+	}
+	hp.r = r
+	return nil
+}
+
+// fail accounts the failure of the piece and returns the error its reads
+// return
+func (hp *hedgedPiece) fail(err error) error {
+	if err == errHedgeUnneeded {
+		return err
+	}
+	if hp.hedge.failed(hp) {
+		return errHedgeLost
+	}
+	return err
+}
+
+func (hp *hedgedPiece) Close() error {
+	hp.hedge.mu.Lock()
+	defer hp.hedge.mu.Unlock()
+	hp.closed = true
+	hp.resolve()
+	if hp.cancel != nil {
+		hp.cancel()
+	}
+	if hp.r == nil {
+		return nil
+	}
+	return hp.r.Close()
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ecclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"errors"
+	"io"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vivint/infectious"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/ranger"
+)
+
+// stuckRanger is a piece whose node never responds
+type stuckRanger struct{ ranger.Ranger }
+
+func (stuckRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// failingRanger is a piece whose node fails
+type failingRanger struct{ ranger.Ranger }
+
+func (failingRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	return nil, errors.New("node failed")
+}
+
+func encodePieces(t *testing.T, data []byte, es eestream.ErasureScheme) map[int]ranger.Ranger {
+	rs, err := eestream.NewRedundancyStrategy(es, 0, 0)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	readers, err := eestream.EncodeReader(context.Background(), bytes.NewReader(data), rs, 0)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	type result struct {
+		i    int
+		data []byte
+		err  error
+	}
+	results := make(chan result, len(readers))
+	for i, r := range readers {
+		go func(i int, r io.Reader) {
+			data, err := ioutil.ReadAll(r)
+			results <- result{i: i, data: data, err: err}
+		}(i, r)
+	}
+	rrs := map[int]ranger.Ranger{}
+	for range readers {
+		res := <-results
+		assert.NoError(t, res.err)
+		rrs[res.i] = ranger.ByteRanger(res.data)
+	}
+	return rrs
+}
+
+func TestLatencies(t *testing.T) {
+	var l *latencies
+	l.add(time.Second)
+	_, ok := l.percentile(50)
+	assert.False(t, ok)
+
+	l = &latencies{}
+	for i := 1; i < minLatencySamples; i++ {
+		l.add(time.Duration(i) * time.Millisecond)
+	}
+	_, ok = l.percentile(50)
+	assert.False(t, ok)
+
+	for i := minLatencySamples; i <= latencySamples+10; i++ {
+		l.add(time.Duration(i) * time.Millisecond)
+	}
+	// only the latest samples, 11ms to 110ms, are kept
+	p, ok := l.percentile(0)
+	assert.True(t, ok)
+	assert.Equal(t, 11*time.Millisecond, p)
+	p, _ = l.percentile(90)
+	assert.Equal(t, 101*time.Millisecond, p)
+	p, _ = l.percentile(100)
+	assert.Equal(t, 110*time.Millisecond, p)
+}
+
+func TestHedgedRanger(t *testing.T) {
+	ctx := context.Background()
+	data := make([]byte, 32*1024)
+	_, err := rand.Read(data)
+	assert.NoError(t, err)
+
+	fc, err := infectious.NewFEC(2, 4)
+	assert.NoError(t, err)
+	es := eestream.NewRSScheme(fc, 1024)
+
+	for _, tt := range []struct {
+		name  string
+		piece func(ranger.Ranger) ranger.Ranger
+		delay time.Duration
+	}{
+		{"fast", func(rr ranger.Ranger) ranger.Ranger { return rr }, time.Millisecond},
+		{"slow", func(rr ranger.Ranger) ranger.Ranger { return stuckRanger{rr} }, 10 * time.Millisecond},
+		{"failed", func(rr ranger.Ranger) ranger.Ranger { return failingRanger{rr} }, 0},
+	} {
+		rrs := encodePieces(t, data, es)
+		rrs[0] = tt.piece(rrs[0])
+		l := &latencies{}
+
+		rr, err := newHedgedRanger(rrs, 2, es, 0, func() time.Duration { return tt.delay }, l)
+		if !assert.NoError(t, err, tt.name) {
+			continue
+		}
+		assert.Equal(t, int64(len(data)), rr.Size(), tt.name)
+
+		r, err := rr.Range(ctx, 0, rr.Size())
+		if !assert.NoError(t, err, tt.name) {
+			continue
+		}
+		decoded, err := ioutil.ReadAll(r)
+		assert.NoError(t, err, tt.name)
+		assert.Equal(t, data, decoded, tt.name)
+		assert.NoError(t, r.Close(), tt.name)
+		assert.NotEmpty(t, l.samples, tt.name)
+	}
+}
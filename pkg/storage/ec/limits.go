@@ -0,0 +1,168 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ecclient
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// Limits limits the transfers of pieces of a client. The zero values don't
+// limit anything.
+type Limits struct {
+	UploadConcurrency   int   `help:"how many pieces of a segment are uploaded at once, at least the success threshold. 0 means all" default:"0"`
+	DownloadConcurrency int   `help:"how many pieces of a segment are downloaded at once, at least the minimum threshold. 0 means all" default:"0"`
+	NodeConcurrency     int   `help:"how many pieces are transferred at once with a single storage node. 0 means no limit" default:"0"`
+	MaxBandwidth        int64 `help:"bandwidth (in bytes per second) shared by all the transfers of pieces. 0 means no limit" default:"0"`
+
+	// LongTailDeviations is the risk taken when cutting the long tail of
+	// uploads: once the minimum threshold of pieces of a segment is uploaded,
+	// the other pieces have until the mean upload duration of the finished
+	// pieces plus this many standard deviations to finish. The pieces whose
+	// node isn't expected to make it from its recent throughput are canceled
+	// right away.
+	LongTailDeviations float64 `help:"once enough pieces of a segment are uploaded, how many standard deviations of the upload durations of the finished pieces to wait for the other pieces. lower values cancel stragglers earlier, at the risk of storing fewer pieces. 0 keeps the fixed cutoff of the encoder" default:"0"`
+
+	// HedgePercentile hedges the downloads of pieces limited by
+	// DownloadConcurrency: a piece that doesn't return its first byte within
+	// this percentile of the recent times to first byte, or at least
+	// HedgeMinDelay, is also downloaded from a node holding another piece,
+	// and the first of the two to respond is used. Failed pieces are replaced
+	// right away.
+	HedgePercentile float64       `help:"percentile (0-100) of the recent times to first byte of piece downloads after which another piece is downloaded in place of a slow one. needs download-concurrency. 0 disables hedging" default:"0"`
+	HedgeMinDelay   time.Duration `help:"minimum time to first byte of a piece download before another piece is downloaded in its place" default:"50ms"`
+}
+
+// limiter limits the number of concurrent transfers. The nil limiter doesn't
+// limit anything.
+type limiter chan struct{}
+
+// acquire starts a transfer, waiting until another one is done if the limit
+// is reached
+func (l limiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release ends a transfer started with acquire
+func (l limiter) release() {
+	if l != nil {
+		<-l
+	}
+}
+
+// nodeLimiters limits the concurrent transfers with every node
+type nodeLimiters struct {
+	n int
+
+	mu     sync.Mutex
+	byNode map[string]limiter
+}
+
+// limiter returns the limiter of nodeID, or nil if transfers with nodes
+// aren't limited
+func (nl *nodeLimiters) limiter(nodeID string) limiter {
+	if nl == nil || nl.n <= 0 {
+		return nil
+	}
+	nl.mu.Lock()
+	defer nl.mu.Unlock()
+	if nl.byNode == nil {
+		nl.byNode = map[string]limiter{}
+	}
+	l, ok := nl.byNode[nodeID]
+	if !ok {
+		l = make(limiter, nl.n)
+		nl.byNode[nodeID] = l
+	}
+	return l
+}
+
+// bandwidthLimit limits the bandwidth shared by concurrent transfers. The
+// nil bandwidthLimit doesn't limit anything.
+type bandwidthLimit struct {
+	rate int64 // bytes per second
+
+	mu  sync.Mutex
+	due time.Time
+}
+
+// newBandwidthLimit returns a limit of rate bytes per second, or nil if rate
+// is 0
+func newBandwidthLimit(rate int64) *bandwidthLimit {
+	if rate <= 0 {
+		return nil
+	}
+	return &bandwidthLimit{rate: rate}
+}
+
+// wait accounts n transferred bytes and blocks until the transfers are within
+// the limit again
+func (b *bandwidthLimit) wait(ctx context.Context, n int) error {
+	if b == nil || n <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	now := time.Now()
+	if b.due.Before(now) {
+		b.due = now
+	}
+	b.due = b.due.Add(time.Duration(float64(n) / float64(b.rate) * float64(time.Second)))
+	delay := b.due.Sub(now)
+	b.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// throttledReader reads from r within a bandwidth limit
+type throttledReader struct {
+	ctx   context.Context
+	r     io.Reader
+	limit *bandwidthLimit
+}
+
+func (t *throttledReader) Read(p []byte) (n int, err error) {
+	n, err = t.r.Read(p)
+	if waitErr := t.limit.wait(t.ctx, n); waitErr != nil && err == nil {
+		err = waitErr
+	}
+	return n, err
+}
+
+// throttle returns r reading within limit, or r itself if there is no limit
+func throttle(ctx context.Context, r io.Reader, limit *bandwidthLimit) io.Reader {
+	if limit == nil {
+		return r
+	}
+	return &throttledReader{ctx: ctx, r: r, limit: limit}
+}
+
+// limitedReadCloser releases a slot of a limiter when closed
+type limitedReadCloser struct {
+	io.Reader
+	closer io.Closer
+	limit  limiter
+	once   sync.Once
+}
+
+func (l *limitedReadCloser) Close() error {
+	l.once.Do(l.limit.release)
+	return l.closer.Close()
+}
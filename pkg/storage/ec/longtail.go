@@ -0,0 +1,98 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ecclient
+
+import (
+	"io"
+	"math"
+	"sync"
+	"time"
+)
+
+// throughputWeight is the weight of the latest upload in the throughput of a
+// node, so the throughput follows the recent uploads
+const throughputWeight = 0.2
+
+// throughputs keeps the recent upload throughput of every node. The nil
+// throughputs doesn't keep anything.
+type throughputs struct {
+	mu     sync.Mutex
+	byNode map[string]float64 // bytes per second
+}
+
+// add accounts the upload of size bytes to nodeID in took
+func (t *throughputs) add(nodeID string, size int64, took time.Duration) {
+	if t == nil || size <= 0 || took <= 0 {
+		return
+	}
+	sample := float64(size) / took.Seconds()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.byNode == nil {
+		t.byNode = map[string]float64{}
+	}
+	if old, ok := t.byNode[nodeID]; ok {
+		sample = (1-throughputWeight)*old + throughputWeight*sample
+	}
+	t.byNode[nodeID] = sample
+}
+
+// expected returns how long an upload of size bytes to nodeID is expected to
+// take, or false if the throughput of the node is unknown
+func (t *throughputs) expected(nodeID string, size int64) (time.Duration, bool) {
+	if t == nil {
+		return 0, false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	throughput, ok := t.byNode[nodeID]
+	if !ok || throughput <= 0 {
+		return 0, false
+	}
+	return time.Duration(float64(size) / throughput * float64(time.Second)), true
+}
+
+// longTailDeadline returns how long after the start of the upload of a
+// segment the pieces still being uploaded are canceled, given the upload
+// durations of the finished pieces: their mean plus deviations times their
+// standard deviation
+func longTailDeadline(finished []time.Duration, deviations float64) time.Duration {
+	if len(finished) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, took := range finished {
+		sum += float64(took)
+	}
+	mean := sum / float64(len(finished))
+	var variance float64
+	for _, took := range finished {
+		variance += (float64(took) - mean) * (float64(took) - mean)
+	}
+	variance /= float64(len(finished))
+	return time.Duration(mean + deviations*math.Sqrt(variance))
+}
+
+// pieceResult is the result of the upload of the i-th piece of a segment
+type pieceResult struct {
+	i    int
+	err  error
+	size int64
+	took time.Duration
+	// root is the root of the Merkle tree of the piece
+	root []byte
+}
+
+// countingReader counts the bytes read from r
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (n int, err error) {
+	n, err = c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
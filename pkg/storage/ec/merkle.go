@@ -0,0 +1,108 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ecclient
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+)
+
+// verifiedRange returns a range of the piece like Range, whose data is
+// verified against the root of the Merkle tree of the piece. The range is
+// downloaded from the first to the last leaf it overlaps, whose hashes the
+// node proves are in the tree.
+func (lr *lazyPieceRanger) verifiedRange(ctx context.Context, offset, length int64) (_ io.ReadCloser, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	first, last := offset/eestream.LeafSize, (offset+length-1)/eestream.LeafSize
+	proof, err := lr.ps.Proof(ctx, lr.id, first, last)
+	if client.IsUnimplemented(err) {
+		// nodes that can't prove their pieces yet are trusted as before
+		zap.S().Debugf("Node %s can't prove piece %s", lr.node.GetId(), lr.id)
+		return lr.ranger.Range(ctx, offset, length)
+	}
+	if err != nil {
+		return nil, err
+	}
+	count := eestream.MerkleLeafCount(lr.size)
+	if !eestream.VerifyMerkleRange(lr.root, count, int(first), proof.GetLeaves(), proof.GetProof()) {
+		mon.Counter("merkle_proof_invalid").Inc(1)
+		return nil, Error.New("invalid Merkle proof of piece %s from node %s", lr.id, lr.node.GetId())
+	}
+
+	start, end := first*eestream.LeafSize, (last+1)*eestream.LeafSize
+	if end > lr.size {
+		end = lr.size
+	}
+	r, err := lr.ranger.Range(ctx, start, end-start)
+	if err != nil {
+		return nil, err
+	}
+	return &verifiedReader{r: r, leaves: proof.GetLeaves(), skip: offset - start, length: length}, nil
+}
+
+// verifiedReader reads whole leaves of a piece from r, verifies them against
+// the hashes of leaves, and returns length bytes of them after skip
+type verifiedReader struct {
+	r      io.ReadCloser
+	leaves [][]byte
+	skip   int64
+	length int64
+
+	leaf []byte
+	buf  []byte
+}
+
+// Read implements io.Reader
+func (vr *verifiedReader) Read(p []byte) (int, error) {
+	for len(vr.buf) == 0 {
+		if vr.length <= 0 {
+			return 0, io.EOF
+		}
+		if len(vr.leaves) == 0 {
+			return 0, io.ErrUnexpectedEOF
+		}
+		if vr.leaf == nil {
+			vr.leaf = make([]byte, eestream.LeafSize)
+		}
+		n, err := io.ReadFull(vr.r, vr.leaf)
+		if err == io.EOF {
+			return 0, io.ErrUnexpectedEOF
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+		// the last leaf of a piece may be shorter
+		leaf := vr.leaf[:n]
+		if !bytes.Equal(eestream.MerkleLeaf(leaf), vr.leaves[0]) {
+			mon.Counter("merkle_leaf_mismatch").Inc(1)
+			return 0, Error.New("piece data doesn't match its Merkle tree")
+		}
+		vr.leaves = vr.leaves[1:]
+
+		skip := vr.skip
+		if skip > int64(len(leaf)) {
+			skip = int64(len(leaf))
+		}
+		leaf, vr.skip = leaf[skip:], vr.skip-skip
+		if int64(len(leaf)) > vr.length {
+			leaf = leaf[:vr.length]
+		}
+		vr.buf, vr.length = leaf, vr.length-int64(len(leaf))
+	}
+	n := copy(p, vr.buf)
+	vr.buf = vr.buf[n:]
+	return n, nil
+}
+
+// Close implements io.Closer
+func (vr *verifiedReader) Close() error {
+	return vr.r.Close()
+}
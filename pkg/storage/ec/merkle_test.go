@@ -0,0 +1,81 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package ecclient
+
+import (
+	"context"
+	"io/ioutil"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/ranger"
+)
+
+func TestVerifiedRange(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	data := make([]byte, 3*eestream.LeafSize+10)
+	for i := range data {
+		data[i] = byte(i*7 + i/eestream.LeafSize)
+	}
+	hasher := eestream.NewMerkleHasher()
+	_, _ = hasher.Write(data)
+	leaves := hasher.Leaves()
+	root := eestream.MerkleRoot(leaves)
+	size := int64(len(data))
+
+	id := client.NewPieceID()
+	proof := func(first, last int) *pb.ProofResponse {
+		return &pb.ProofResponse{Leaves: leaves[first : last+1], Proof: eestream.MerkleRangeProof(leaves, first, last)}
+	}
+	newRanger := func(stored []byte, first, last int64, resp *pb.ProofResponse) *lazyPieceRanger {
+		ps := NewMockPSClient(ctrl)
+		ps.EXPECT().Get(gomock.Any(), id, size, gomock.Any()).Return(ranger.ByteRanger(stored), nil)
+		ps.EXPECT().Proof(gomock.Any(), id, first, last).Return(resp, nil)
+		return &lazyPieceRanger{
+			dialer: &mockDialer{m: map[*pb.Node]client.PSClient{node0: ps}},
+			node:   node0,
+			id:     id,
+			size:   size,
+			root:   root,
+		}
+	}
+
+	// ranges within leaves, and up to the shorter last leaf
+	for _, r := range []struct{ offset, length, first, last int64 }{
+		{eestream.LeafSize + 100, eestream.LeafSize, 1, 2},
+		{0, 1, 0, 0},
+		{size - 20, 20, 2, 3},
+	} {
+		rr := newRanger(data, r.first, r.last, proof(int(r.first), int(r.last)))
+		reader, err := rr.Range(ctx, r.offset, r.length)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		read, err := ioutil.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, data[r.offset:r.offset+r.length], read)
+		assert.NoError(t, reader.Close())
+	}
+
+	// corrupted data fails the download of the leaf
+	corrupted := append([]byte{}, data...)
+	corrupted[2*eestream.LeafSize]++
+	reader, err := newRanger(corrupted, 1, 2, proof(1, 2)).Range(ctx, eestream.LeafSize, 2*eestream.LeafSize)
+	if assert.NoError(t, err) {
+		_, err = ioutil.ReadAll(reader)
+		assert.Error(t, err)
+	}
+
+	// so does a proof of other leaves
+	_, err = newRanger(data, 1, 2, proof(0, 1)).Range(ctx, eestream.LeafSize, 2*eestream.LeafSize)
+	assert.Error(t, err)
+}
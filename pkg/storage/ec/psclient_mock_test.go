@@ -44,6 +44,19 @@ func (m *MockPSClient) EXPECT() *MockPSClientMockRecorder {
 	return m.recorder
 }
 
+// Challenge mocks base method
+func (m *MockPSClient) Challenge(arg0 context.Context, arg1 client.PieceID, arg2 int64) (*pb.ChallengeResponse, error) {
+	ret := m.ctrl.Call(m, "Challenge", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*pb.ChallengeResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Challenge indicates an expected call of Challenge
+func (mr *MockPSClientMockRecorder) Challenge(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Challenge", reflect.TypeOf((*MockPSClient)(nil).Challenge), arg0, arg1, arg2)
+}
+
 // Close mocks base method
 func (m *MockPSClient) Close() error {
 	ret := m.ctrl.Call(m, "Close")
@@ -57,15 +70,15 @@ func (mr *MockPSClientMockRecorder) Close() *gomock.Call {
 }
 
 // Delete mocks base method
-func (m *MockPSClient) Delete(arg0 context.Context, arg1 client.PieceID) error {
-	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+func (m *MockPSClient) Delete(arg0 context.Context, arg1 client.PieceID, arg2 *pb.PayerBandwidthAllocation) error {
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1, arg2)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete
-func (mr *MockPSClientMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPSClient)(nil).Delete), arg0, arg1)
+func (mr *MockPSClientMockRecorder) Delete(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPSClient)(nil).Delete), arg0, arg1, arg2)
 }
 
 // Get mocks base method
@@ -94,6 +107,19 @@ func (mr *MockPSClientMockRecorder) Meta(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Meta", reflect.TypeOf((*MockPSClient)(nil).Meta), arg0, arg1)
 }
 
+// Proof mocks base method
+func (m *MockPSClient) Proof(arg0 context.Context, arg1 client.PieceID, arg2, arg3 int64) (*pb.ProofResponse, error) {
+	ret := m.ctrl.Call(m, "Proof", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(*pb.ProofResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Proof indicates an expected call of Proof
+func (mr *MockPSClientMockRecorder) Proof(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Proof", reflect.TypeOf((*MockPSClient)(nil).Proof), arg0, arg1, arg2, arg3)
+}
+
 // Put mocks base method
 func (m *MockPSClient) Put(arg0 context.Context, arg1 client.PieceID, arg2 io.Reader, arg3 time.Time, arg4 *pb.PayerBandwidthAllocation) error {
 	ret := m.ctrl.Call(m, "Put", arg0, arg1, arg2, arg3, arg4)
@@ -106,6 +132,32 @@ func (mr *MockPSClientMockRecorder) Put(arg0, arg1, arg2, arg3, arg4 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockPSClient)(nil).Put), arg0, arg1, arg2, arg3, arg4)
 }
 
+// Retain mocks base method
+func (m *MockPSClient) Retain(arg0 context.Context, arg1 time.Time, arg2 []byte) (*pb.RetainSummary, error) {
+	ret := m.ctrl.Call(m, "Retain", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*pb.RetainSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Retain indicates an expected call of Retain
+func (mr *MockPSClientMockRecorder) Retain(arg0, arg1, arg2 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Retain", reflect.TypeOf((*MockPSClient)(nil).Retain), arg0, arg1, arg2)
+}
+
+// StatPieces mocks base method
+func (m *MockPSClient) StatPieces(arg0 context.Context, arg1 []client.PieceID) ([]*pb.PieceStat, error) {
+	ret := m.ctrl.Call(m, "StatPieces", arg0, arg1)
+	ret0, _ := ret[0].([]*pb.PieceStat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StatPieces indicates an expected call of StatPieces
+func (mr *MockPSClientMockRecorder) StatPieces(arg0, arg1 interface{}) *gomock.Call {
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StatPieces", reflect.TypeOf((*MockPSClient)(nil).StatPieces), arg0, arg1)
+}
+
 // Stats mocks base method
 func (m *MockPSClient) Stats(arg0 context.Context) error {
 	ret := m.ctrl.Call(m, "Stats", arg0)
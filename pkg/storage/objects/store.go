@@ -51,6 +51,13 @@ type Store interface {
 	List(ctx context.Context, prefix, startAfter, endBefore paths.Path,
 		recursive bool, limit int, metaFlags uint32) (items []ListItem,
 		more bool, err error)
+	PutPart(ctx context.Context, path paths.Path, uploadID string, number int,
+		data io.Reader, expiration time.Time) (part streams.Part, err error)
+	CommitParts(ctx context.Context, path paths.Path, uploadID string,
+		parts []streams.Part, metadata SerializableMeta,
+		expiration time.Time) (meta Meta, err error)
+	DeleteParts(ctx context.Context, path paths.Path, uploadID string,
+		parts []streams.Part) (err error)
 }
 
 type objStore struct {
@@ -139,6 +146,45 @@ func (o *objStore) List(ctx context.Context, prefix, startAfter,
 	return items, more, nil
 }
 
+func (o *objStore) PutPart(ctx context.Context, path paths.Path, uploadID string,
+	number int, data io.Reader, expiration time.Time) (part streams.Part, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return streams.Part{}, NoPathError.New("")
+	}
+
+	return o.s.PutPart(ctx, path, uploadID, number, data, expiration)
+}
+
+func (o *objStore) CommitParts(ctx context.Context, path paths.Path, uploadID string,
+	parts []streams.Part, metadata SerializableMeta, expiration time.Time) (
+	meta Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return Meta{}, NoPathError.New("")
+	}
+
+	b, err := proto.Marshal(&metadata)
+	if err != nil {
+		return Meta{}, err
+	}
+	m, err := o.s.CommitParts(ctx, path, uploadID, parts, b, expiration)
+	return convertMeta(m), err
+}
+
+func (o *objStore) DeleteParts(ctx context.Context, path paths.Path, uploadID string,
+	parts []streams.Part) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if len(path) == 0 {
+		return NoPathError.New("")
+	}
+
+	return o.s.DeleteParts(ctx, path, uploadID, parts)
+}
+
 // convertMeta converts stream metadata to object metadata
 func convertMeta(m streams.Meta) Meta {
 	ser := SerializableMeta{}
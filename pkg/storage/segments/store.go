@@ -5,18 +5,21 @@ package segments
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"io"
 	"time"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
-	"github.com/vivint/infectious"
+	"github.com/zeebo/errs"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	monkit "gopkg.in/spacemonkeygo/monkit.v2"
 
-	"storj.io/storj/pkg/dht"
 	"storj.io/storj/pkg/eestream"
-	"storj.io/storj/pkg/kademlia"
+	"storj.io/storj/pkg/orders"
 	"storj.io/storj/pkg/overlay"
 	"storj.io/storj/pkg/paths"
 	"storj.io/storj/pkg/pb"
@@ -64,6 +67,8 @@ type segmentStore struct {
 	pdb           pdbclient.Client
 	rs            eestream.RedundancyStrategy
 	thresholdSize int
+	shareMACs     bool
+	suite         eestream.SuiteID
 }
 
 // NewSegmentStore creates a new instance of segmentStore
@@ -72,12 +77,30 @@ func NewSegmentStore(oc overlay.Client, ec ecclient.Client,
 	return &segmentStore{oc: oc, ec: ec, pdb: pdb, rs: rs, thresholdSize: t}
 }
 
+// NewSegmentStoreWithShareMACs is like NewSegmentStore, but if shareMACs is
+// set the erasure shares of the uploaded segments are followed by a MAC bound
+// to their segment, stripe and share number, so storage nodes can't replay or
+// swap shares without being caught when they are downloaded or audited
+func NewSegmentStoreWithShareMACs(oc overlay.Client, ec ecclient.Client,
+	pdb pdbclient.Client, rs eestream.RedundancyStrategy, t int, shareMACs bool) Store {
+	return &segmentStore{oc: oc, ec: ec, pdb: pdb, rs: rs, thresholdSize: t, shareMACs: shareMACs}
+}
+
+// NewSegmentStoreWithSuite is like NewSegmentStoreWithShareMACs, but records
+// in the pointers of uploaded segments that they're stored with suite, whose
+// erasure scheme rs must be. Satellites may only allow some suites for new
+// segments.
+func NewSegmentStoreWithSuite(oc overlay.Client, ec ecclient.Client,
+	pdb pdbclient.Client, rs eestream.RedundancyStrategy, t int, shareMACs bool, suite eestream.SuiteID) Store {
+	return &segmentStore{oc: oc, ec: ec, pdb: pdb, rs: rs, thresholdSize: t, shareMACs: shareMACs, suite: suite}
+}
+
 // Meta retrieves the metadata of the segment
 func (s *segmentStore) Meta(ctx context.Context, path paths.Path) (meta Meta,
 	err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	pr, err := s.pdb.Get(ctx, path)
+	pr, _, err := s.pdb.Get(ctx, path)
 	if err != nil {
 		return Meta{}, Error.Wrap(err)
 	}
@@ -109,50 +132,116 @@ func (s *segmentStore) Put(ctx context.Context, path paths.Path, data io.Reader,
 			Size:           int64(len(peekReader.thresholdBuf)),
 			ExpirationDate: exp,
 			Metadata:       metadata,
+			Suite:          int32(s.suite),
 		}
 	} else {
-		// uses overlay client to request a list of nodes
-		nodes, err := s.oc.Choose(ctx, s.rs.TotalCount(), 0)
+		// uses overlay client to request a list of nodes, in the placement
+		// of the bucket, which follows the segment in the path
+		var bucket string
+		if len(path) > 1 {
+			bucket = path[1]
+		}
+		nodes, err := s.oc.Choose(ctx, s.rs.TotalCount(), 0, bucket)
 		if err != nil {
 			return Meta{}, Error.Wrap(err)
 		}
 		pieceID := client.NewPieceID()
 		sizedReader := SizeReader(peekReader)
 
+		var nodeIDs []string
+		for _, node := range nodes {
+			nodeIDs = append(nodeIDs, node.GetId())
+		}
+		limits, err := s.pdb.OrderLimits(ctx, path, pb.PayerBandwidthAllocation_PUT, pieceID, nodeIDs)
+		if err != nil {
+			return Meta{}, Error.Wrap(err)
+		}
+		// the nodes are dialed at the addresses the satellite put in their
+		// order limits
+		nodes, err = orders.Nodes(limits)
+		if err != nil {
+			return Meta{}, Error.Wrap(err)
+		}
+
+		rs := s.rs
+		if s.shareMACs {
+			rs.ErasureScheme = eestream.NewMACScheme(rs.ErasureScheme, eestream.NewShareMAC([]byte(pieceID)))
+		}
+
 		// puts file to ecclient
-		err = s.ec.Put(ctx, nodes, s.rs, pieceID, sizedReader, expiration)
+		roots, err := s.ec.Put(ctx, nodes, rs, pieceID, sizedReader, expiration, limits)
 		if err != nil {
 			return Meta{}, Error.Wrap(err)
 		}
-		p, err = s.makeRemotePointer(nodes, pieceID, sizedReader.Size(), exp, metadata)
+		p, err = s.makeRemotePointer(nodes, roots, pieceID, sizedReader.Size(), exp, metadata)
 		if err != nil {
 			return Meta{}, err
 		}
 	}
 
-	// puts pointer to pointerDB
-	err = s.pdb.Put(ctx, path, p)
+	pr, err := s.commit(ctx, path, p)
 	if err != nil {
-		return Meta{}, Error.Wrap(err)
+		return Meta{}, err
 	}
+	return convertMeta(pr), nil
+}
 
-	// get the metadata for the newly uploaded segment
-	m, err := s.Meta(ctx, path)
-	if err != nil {
-		return Meta{}, Error.Wrap(err)
+// maxCommitAttempts is how many times the commit of a segment is sent when
+// it's unknown whether it was applied
+const maxCommitAttempts = 3
+
+// commit puts pointer p to pointerDB and gets the metadata for the newly
+// uploaded segment in the same round trip. The commit is retried after
+// ambiguous failures with the same idempotency key, so it's applied once and
+// the uploaded pieces aren't orphaned by uploading the segment again.
+func (s *segmentStore) commit(ctx context.Context, path paths.Path, p *pb.Pointer) (pr *pb.Pointer, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	var key [16]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	idempotencyKey := hex.EncodeToString(key[:])
+
+	for attempt := 1; ; attempt++ {
+		responses, err := s.pdb.Batch(ctx,
+			&pb.BatchRequestItem{Put: &pb.PutRequest{Path: path.String(), Pointer: p, IdempotencyKey: idempotencyKey}},
+			&pb.BatchRequestItem{Get: &pb.GetRequest{Path: path.String()}},
+		)
+		if err == nil {
+			pr, _, err = pdbclient.UnmarshalGetResponse(responses[1].GetGet())
+			return pr, Error.Wrap(err)
+		}
+		if attempt >= maxCommitAttempts || ctx.Err() != nil || !isAmbiguous(err) {
+			return nil, Error.Wrap(err)
+		}
+		zap.S().Debugf("Retrying commit of segment %s: %v", path, err)
 	}
-	return m, nil
 }
 
-// makeRemotePointer creates a pointer of type remote
-func (s *segmentStore) makeRemotePointer(nodes []*pb.Node, pieceID client.PieceID, readerSize int64,
+// isAmbiguous returns whether err leaves it unknown whether the request was
+// applied
+func isAmbiguous(err error) bool {
+	return errs.IsFunc(err, func(err error) bool {
+		code := status.Code(err)
+		return code == codes.Unavailable || code == codes.DeadlineExceeded
+	})
+}
+
+// makeRemotePointer creates a pointer of type remote, with the roots of the
+// Merkle trees of the pieces which satellites challenge nodes against
+func (s *segmentStore) makeRemotePointer(nodes []*pb.Node, roots [][]byte, pieceID client.PieceID, readerSize int64,
 	exp *timestamp.Timestamp, metadata []byte) (pointer *pb.Pointer, err error) {
 	var remotePieces []*pb.RemotePiece
 	for i := range nodes {
-		remotePieces = append(remotePieces, &pb.RemotePiece{
+		piece := &pb.RemotePiece{
 			PieceNum: int32(i),
 			NodeId:   nodes[i].Id,
-		})
+		}
+		if i < len(roots) {
+			piece.MerkleRoot = roots[i]
+		}
+		remotePieces = append(remotePieces, piece)
 	}
 
 	pointer = &pb.Pointer{
@@ -165,6 +254,7 @@ func (s *segmentStore) makeRemotePointer(nodes []*pb.Node, pieceID client.PieceI
 				RepairThreshold:  int32(s.rs.Min),
 				SuccessThreshold: int32(s.rs.Opt),
 				ErasureShareSize: int32(s.rs.EncodedBlockSize()),
+				ShareMacs:        s.shareMACs,
 			},
 			PieceId:      string(pieceID),
 			RemotePieces: remotePieces,
@@ -172,16 +262,17 @@ func (s *segmentStore) makeRemotePointer(nodes []*pb.Node, pieceID client.PieceI
 		Size:           readerSize,
 		ExpirationDate: exp,
 		Metadata:       metadata,
+		Suite:          int32(s.suite),
 	}
 	return pointer, nil
 }
 
-// Get retrieves a segment using erasure code, overlay, and pointerdb clients
+// Get retrieves a segment using erasure code and pointerdb clients
 func (s *segmentStore) Get(ctx context.Context, path paths.Path) (
 	rr ranger.Ranger, meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	pr, err := s.pdb.Get(ctx, path)
+	pr, _, err := s.pdb.Get(ctx, path)
 	if err != nil {
 		return nil, Meta{}, Error.Wrap(err)
 	}
@@ -189,17 +280,28 @@ func (s *segmentStore) Get(ctx context.Context, path paths.Path) (
 	if pr.GetType() == pb.Pointer_REMOTE {
 		seg := pr.GetRemote()
 		pid := client.PieceID(seg.PieceId)
-		nodes, err := s.lookupNodes(ctx, seg)
+
+		es, err := makeErasureScheme(eestream.SuiteID(pr.GetSuite()), seg.GetRedundancy(), pid)
 		if err != nil {
-			return nil, Meta{}, Error.Wrap(err)
+			return nil, Meta{}, err
 		}
 
-		es, err := makeErasureScheme(pr.GetRemote().GetRedundancy())
+		limits, err := s.pdb.OrderLimits(ctx, path, pb.PayerBandwidthAllocation_GET, pid, nil)
 		if err != nil {
-			return nil, Meta{}, err
+			return nil, Meta{}, Error.Wrap(err)
+		}
+		nodes, err := orders.Nodes(limits)
+		if err != nil {
+			return nil, Meta{}, Error.Wrap(err)
+		}
+
+		// pieces are verified against the roots recorded at upload
+		var roots [][]byte
+		for _, piece := range seg.GetRemotePieces() {
+			roots = append(roots, piece.GetMerkleRoot())
 		}
 
-		rr, err = s.ec.Get(ctx, nodes, es, pid, pr.GetSize())
+		rr, err = s.ec.Get(ctx, nodes, es, pid, pr.GetSize(), limits, roots)
 		if err != nil {
 			return nil, Meta{}, Error.Wrap(err)
 		}
@@ -210,12 +312,20 @@ func (s *segmentStore) Get(ctx context.Context, path paths.Path) (
 	return rr, convertMeta(pr), nil
 }
 
-func makeErasureScheme(rs *pb.RedundancyScheme) (eestream.ErasureScheme, error) {
-	fc, err := infectious.NewFEC(int(rs.GetMinReq()), int(rs.GetTotal()))
+// makeErasureScheme returns the erasure scheme of the segment with root piece
+// id pieceID stored with suite and rs
+func makeErasureScheme(suiteID eestream.SuiteID, rs *pb.RedundancyScheme, pieceID client.PieceID) (eestream.ErasureScheme, error) {
+	suite, err := eestream.LookupSuite(suiteID)
 	if err != nil {
 		return nil, Error.Wrap(err)
 	}
-	es := eestream.NewRSScheme(fc, int(rs.GetErasureShareSize()))
+	es, err := suite.NewErasure(int(rs.GetMinReq()), int(rs.GetTotal()), int(rs.GetErasureShareSize()))
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if rs.GetShareMacs() {
+		es = eestream.NewMACScheme(es, eestream.NewShareMAC([]byte(pieceID)))
+	}
 	return es, nil
 }
 
@@ -223,7 +333,7 @@ func makeErasureScheme(rs *pb.RedundancyScheme) (eestream.ErasureScheme, error)
 func (s *segmentStore) Delete(ctx context.Context, path paths.Path) (err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	pr, err := s.pdb.Get(ctx, path)
+	pr, _, err := s.pdb.Get(ctx, path)
 	if err != nil {
 		return Error.Wrap(err)
 	}
@@ -231,13 +341,19 @@ func (s *segmentStore) Delete(ctx context.Context, path paths.Path) (err error)
 	if pr.GetType() == pb.Pointer_REMOTE {
 		seg := pr.GetRemote()
 		pid := client.PieceID(seg.PieceId)
-		nodes, err := s.lookupNodes(ctx, seg)
+
+		// piece stores only delete pieces with an order limit
+		limits, err := s.pdb.OrderLimits(ctx, path, pb.PayerBandwidthAllocation_DELETE, pid, nil)
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		nodes, err := orders.Nodes(limits)
 		if err != nil {
 			return Error.Wrap(err)
 		}
 
 		// ecclient sends delete request
-		err = s.ec.Delete(ctx, nodes, pid)
+		err = s.ec.Delete(ctx, nodes, pid, limits)
 		if err != nil {
 			return Error.Wrap(err)
 		}
@@ -247,20 +363,6 @@ func (s *segmentStore) Delete(ctx context.Context, path paths.Path) (err error)
 	return s.pdb.Delete(ctx, path)
 }
 
-// lookupNodes calls Lookup to get node addresses from the overlay
-func (s *segmentStore) lookupNodes(ctx context.Context, seg *pb.RemoteSegment) (nodes []*pb.Node, err error) {
-	pieces := seg.GetRemotePieces()
-	var nodeIds []dht.NodeID
-	for _, p := range pieces {
-		nodeIds = append(nodeIds, kademlia.StringToNodeID(p.GetNodeId()))
-	}
-	nodes, err = s.oc.BulkLookup(ctx, nodeIds)
-	if err != nil {
-		return nil, Error.Wrap(err)
-	}
-	return nodes, nil
-}
-
 // List retrieves paths to segments and their metadata stored in the pointerdb
 func (s *segmentStore) List(ctx context.Context, prefix, startAfter,
 	endBefore paths.Path, recursive bool, limit int, metaFlags uint32) (
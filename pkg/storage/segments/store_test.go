@@ -9,10 +9,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/golang/protobuf/ptypes"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"storj.io/storj/pkg/eestream"
 	mock_eestream "storj.io/storj/pkg/eestream/mocks"
 	mock_overlay "storj.io/storj/pkg/overlay/mocks"
@@ -27,6 +30,16 @@ var (
 	ctx = context.Background()
 )
 
+// orderLimit returns an unsigned order limit for the node at address
+func orderLimit(t *testing.T, nodeID, address string) *pb.PayerBandwidthAllocation {
+	data, err := proto.Marshal(&pb.PayerBandwidthAllocation_Data{
+		StorageNodeId:      []byte(nodeID),
+		StorageNodeAddress: address,
+	})
+	assert.NoError(t, err)
+	return &pb.PayerBandwidthAllocation{Signature: []byte("signature"), Data: data}
+}
+
 func TestNewSegmentStore(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -53,7 +66,7 @@ func TestSegmentStoreMeta(t *testing.T) {
 		ErasureScheme: mock_eestream.NewMockErasureScheme(ctrl),
 	}
 
-	ss := segmentStore{mockOC, mockEC, mockPDB, rs, 10}
+	ss := segmentStore{oc: mockOC, ec: mockEC, pdb: mockPDB, rs: rs, thresholdSize: 10}
 	assert.NotNil(t, ss)
 
 	var mExp time.Time
@@ -72,7 +85,7 @@ func TestSegmentStoreMeta(t *testing.T) {
 		calls := []*gomock.Call{
 			mockPDB.EXPECT().Get(
 				gomock.Any(), gomock.Any(),
-			).Return(tt.returnPointer, nil),
+			).Return(tt.returnPointer, nil, nil),
 		}
 		gomock.InOrder(calls...)
 
@@ -104,31 +117,34 @@ func TestSegmentStorePutRemote(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{oc: mockOC, ec: mockEC, pdb: mockPDB, rs: rs, thresholdSize: tt.thresholdSize}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
 		r := strings.NewReader(tt.readerContent)
+		limits := []*pb.PayerBandwidthAllocation{orderLimit(t, "im-a-node", "127.0.0.1:1")}
+		// the node is dialed at the address in its order limit
+		nodes := []*pb.Node{{Id: "im-a-node", Address: &pb.NodeAddress{Address: "127.0.0.1:1"}}}
 
 		calls := []*gomock.Call{
 			mockES.EXPECT().TotalCount().Return(1),
 			mockOC.EXPECT().Choose(
-				gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
 			).Return([]*pb.Node{
 				{Id: "im-a-node"},
 			}, nil),
+			mockPDB.EXPECT().OrderLimits(
+				gomock.Any(), p, pb.PayerBandwidthAllocation_PUT, gomock.Any(), []string{"im-a-node"},
+			).Return(limits, nil),
 			mockEC.EXPECT().Put(
-				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), nodes, gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), limits,
 			),
 			mockES.EXPECT().RequiredCount().Return(1),
 			mockES.EXPECT().TotalCount().Return(1),
 			mockES.EXPECT().EncodedBlockSize().Return(1),
-			mockPDB.EXPECT().Put(
+			mockPDB.EXPECT().Batch(
 				gomock.Any(), gomock.Any(), gomock.Any(),
-			).Return(nil),
-			mockPDB.EXPECT().Get(
-				gomock.Any(), gomock.Any(),
-			),
+			).Return([]*pb.BatchResponseItem{{Put: &pb.PutResponse{}}, {Get: &pb.GetResponse{}}}, nil),
 		}
 		gomock.InOrder(calls...)
 
@@ -159,19 +175,16 @@ func TestSegmentStorePutInline(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{oc: mockOC, ec: mockEC, pdb: mockPDB, rs: rs, thresholdSize: tt.thresholdSize}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
 		r := strings.NewReader(tt.readerContent)
 
 		calls := []*gomock.Call{
-			mockPDB.EXPECT().Put(
+			mockPDB.EXPECT().Batch(
 				gomock.Any(), gomock.Any(), gomock.Any(),
-			).Return(nil),
-			mockPDB.EXPECT().Get(
-				gomock.Any(), gomock.Any(),
-			),
+			).Return([]*pb.BatchResponseItem{{Put: &pb.PutResponse{}}, {Get: &pb.GetResponse{}}}, nil),
 		}
 		gomock.InOrder(calls...)
 
@@ -180,6 +193,38 @@ func TestSegmentStorePutInline(t *testing.T) {
 	}
 }
 
+func TestSegmentStoreCommitRetry(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockPDB := mock_pointerdb.NewMockClient(ctrl)
+	ss := segmentStore{pdb: mockPDB, thresholdSize: 1000}
+
+	var keys []string
+	recordKey := func(ctx context.Context, items ...*pb.BatchRequestItem) {
+		keys = append(keys, items[0].GetPut().GetIdempotencyKey())
+	}
+	gomock.InOrder(
+		mockPDB.EXPECT().Batch(gomock.Any(), gomock.Any(), gomock.Any()).Do(recordKey).
+			Return(nil, status.Error(codes.Unavailable, "connection reset")),
+		mockPDB.EXPECT().Batch(gomock.Any(), gomock.Any(), gomock.Any()).Do(recordKey).
+			Return([]*pb.BatchResponseItem{{Put: &pb.PutResponse{}}, {Get: &pb.GetResponse{}}}, nil),
+	)
+
+	_, err := ss.Put(ctx, paths.New("path/1"), strings.NewReader("data"), nil, time.Unix(0, 0).UTC())
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 2) {
+		assert.NotEmpty(t, keys[0])
+		assert.Equal(t, keys[0], keys[1], "the retried commit has the same idempotency key")
+	}
+
+	// unambiguous failures aren't retried
+	mockPDB.EXPECT().Batch(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, status.Error(codes.PermissionDenied, "denied"))
+	_, err = ss.Put(ctx, paths.New("path/1"), strings.NewReader("data"), nil, time.Unix(0, 0).UTC())
+	assert.Error(t, err)
+}
+
 func TestSegmentStoreGetInline(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -206,7 +251,7 @@ func TestSegmentStoreGetInline(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{oc: mockOC, ec: mockEC, pdb: mockPDB, rs: rs, thresholdSize: tt.thresholdSize}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
@@ -221,7 +266,7 @@ func TestSegmentStoreGetInline(t *testing.T) {
 				ExpirationDate: someTime,
 				Size:           tt.size,
 				Metadata:       tt.metadata,
-			}, nil),
+			}, nil, nil),
 		}
 		gomock.InOrder(calls...)
 
@@ -255,10 +300,11 @@ func TestSegmentStoreGetRemote(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{oc: mockOC, ec: mockEC, pdb: mockPDB, rs: rs, thresholdSize: tt.thresholdSize}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
+		limits := []*pb.PayerBandwidthAllocation{orderLimit(t, "node1", "127.0.0.1:1")}
 
 		calls := []*gomock.Call{
 			mockPDB.EXPECT().Get(
@@ -280,10 +326,12 @@ func TestSegmentStoreGetRemote(t *testing.T) {
 				ExpirationDate: someTime,
 				Size:           tt.size,
 				Metadata:       tt.metadata,
-			}, nil),
-			mockOC.EXPECT().BulkLookup(gomock.Any(), gomock.Any()),
+			}, nil, nil),
+			mockPDB.EXPECT().OrderLimits(
+				gomock.Any(), p, pb.PayerBandwidthAllocation_GET, gomock.Any(), gomock.Any(),
+			).Return(limits, nil),
 			mockEC.EXPECT().Get(
-				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), limits, gomock.Any(),
 			),
 		}
 		gomock.InOrder(calls...)
@@ -293,6 +341,58 @@ func TestSegmentStoreGetRemote(t *testing.T) {
 	}
 }
 
+func TestSegmentStoreGetRemoteNodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockOC := mock_overlay.NewMockClient(ctrl)
+	mockEC := mock_ecclient.NewMockClient(ctrl)
+	mockPDB := mock_pointerdb.NewMockClient(ctrl)
+	mockES := mock_eestream.NewMockErasureScheme(ctrl)
+	rs := eestream.RedundancyStrategy{
+		ErasureScheme: mockES,
+	}
+
+	ss := segmentStore{oc: mockOC, ec: mockEC, pdb: mockPDB, rs: rs, thresholdSize: 10}
+
+	pointer := &pb.Pointer{
+		Type: pb.Pointer_REMOTE,
+		Remote: &pb.RemoteSegment{
+			Redundancy: &pb.RedundancyScheme{
+				Type:             pb.RedundancyScheme_RS,
+				MinReq:           1,
+				Total:            2,
+				RepairThreshold:  1,
+				SuccessThreshold: 2,
+			},
+			PieceId: "here's my piece id",
+			RemotePieces: []*pb.RemotePiece{
+				{PieceNum: 0, NodeId: "node1"},
+				{PieceNum: 1, NodeId: "node2"},
+			},
+		},
+	}
+	limits := []*pb.PayerBandwidthAllocation{
+		orderLimit(t, "node1", "127.0.0.1:1"),
+		orderLimit(t, "node2", ""),
+	}
+	// the nodes are dialed at the addresses in their order limits, rather
+	// than the addresses pointerdb returned, and the overlay isn't asked
+	nodes := []*pb.Node{
+		{Id: "node1", Address: &pb.NodeAddress{Address: "127.0.0.1:1"}},
+		{Id: "node2"},
+	}
+	calls := []*gomock.Call{
+		mockPDB.EXPECT().Get(gomock.Any(), gomock.Any()).Return(pointer, []*pb.Node{{Id: "node1"}, nil}, nil),
+		mockPDB.EXPECT().OrderLimits(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(limits, nil),
+		mockEC.EXPECT().Get(gomock.Any(), nodes, gomock.Any(), gomock.Any(), gomock.Any(), limits, gomock.Any()),
+	}
+	gomock.InOrder(calls...)
+
+	_, _, err := ss.Get(ctx, paths.New("path/1"))
+	assert.NoError(t, err)
+}
+
 func TestSegmentStoreDeleteInline(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -319,7 +419,7 @@ func TestSegmentStoreDeleteInline(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{oc: mockOC, ec: mockEC, pdb: mockPDB, rs: rs, thresholdSize: tt.thresholdSize}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
@@ -334,7 +434,7 @@ func TestSegmentStoreDeleteInline(t *testing.T) {
 				ExpirationDate: someTime,
 				Size:           tt.size,
 				Metadata:       tt.metadata,
-			}, nil),
+			}, nil, nil),
 			mockPDB.EXPECT().Delete(
 				gomock.Any(), gomock.Any(),
 			),
@@ -371,10 +471,11 @@ func TestSegmentStoreDeleteRemote(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{oc: mockOC, ec: mockEC, pdb: mockPDB, rs: rs, thresholdSize: tt.thresholdSize}
 		assert.NotNil(t, ss)
 
 		p := paths.New(tt.pathInput)
+		limits := []*pb.PayerBandwidthAllocation{orderLimit(t, "node1", "127.0.0.1:1")}
 
 		calls := []*gomock.Call{
 			mockPDB.EXPECT().Get(
@@ -396,10 +497,12 @@ func TestSegmentStoreDeleteRemote(t *testing.T) {
 				ExpirationDate: someTime,
 				Size:           tt.size,
 				Metadata:       tt.metadata,
-			}, nil),
-			mockOC.EXPECT().BulkLookup(gomock.Any(), gomock.Any()),
+			}, nil, nil),
+			mockPDB.EXPECT().OrderLimits(
+				gomock.Any(), p, pb.PayerBandwidthAllocation_DELETE, gomock.Any(), gomock.Any(),
+			).Return(limits, nil),
 			mockEC.EXPECT().Delete(
-				gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any(), limits,
 			),
 			mockPDB.EXPECT().Delete(
 				gomock.Any(), gomock.Any(),
@@ -434,7 +537,7 @@ func TestSegmentStoreList(t *testing.T) {
 			ErasureScheme: mockES,
 		}
 
-		ss := segmentStore{mockOC, mockEC, mockPDB, rs, tt.thresholdSize}
+		ss := segmentStore{oc: mockOC, ec: mockEC, pdb: mockPDB, rs: rs, thresholdSize: tt.thresholdSize}
 		assert.NotNil(t, ss)
 
 		prefix := paths.New(tt.prefixInput)
@@ -0,0 +1,63 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"hash"
+	"io"
+
+	"github.com/zeebo/errs"
+
+	"storj.io/storj/pkg/ranger"
+)
+
+// ErrChecksum is the error of the downloads of streams whose content doesn't
+// match the checksum recorded when they were uploaded
+var ErrChecksum = errs.Class("stream checksum mismatch")
+
+// checksumRanger verifies the checksum of the stream of rr when the whole
+// stream is read. Partial ranges aren't verified.
+type checksumRanger struct {
+	ranger.Ranger
+	checksum []byte
+}
+
+// verifyChecksum returns rr verifying checksum on full reads, or rr itself if
+// the stream has no checksum
+func verifyChecksum(rr ranger.Ranger, checksum []byte) ranger.Ranger {
+	if len(checksum) == 0 {
+		return rr
+	}
+	return &checksumRanger{Ranger: rr, checksum: checksum}
+}
+
+// Range implements ranger.Ranger
+func (rr *checksumRanger) Range(ctx context.Context, offset, length int64) (io.ReadCloser, error) {
+	r, err := rr.Ranger.Range(ctx, offset, length)
+	if err != nil || offset != 0 || length != rr.Size() {
+		return r, err
+	}
+	return &checksumReader{ReadCloser: r, hash: sha256.New(), checksum: rr.checksum}, nil
+}
+
+// checksumReader hashes what is read from a stream and fails at the end of
+// the stream if the hash doesn't match the checksum
+type checksumReader struct {
+	io.ReadCloser
+	hash     hash.Hash
+	checksum []byte
+}
+
+func (r *checksumReader) Read(p []byte) (n int, err error) {
+	n, err = r.ReadCloser.Read(p)
+	_, _ = r.hash.Write(p[:n])
+	if err == io.EOF && !bytes.Equal(r.hash.Sum(nil), r.checksum) {
+		mon.Counter("stream_checksum_mismatch").Inc(1)
+		return n, ErrChecksum.New("content doesn't match the checksum recorded at upload")
+	}
+	return n, err
+}
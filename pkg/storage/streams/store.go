@@ -4,9 +4,13 @@
 package streams
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"sync"
 	"time"
 
 	proto "github.com/gogo/protobuf/proto"
@@ -28,6 +32,8 @@ type Meta struct {
 	Expiration time.Time
 	Size       int64
 	Data       []byte
+	// Checksum is the sha256 hash of the stream, if it was recorded
+	Checksum []byte
 }
 
 // convertMeta converts segment metadata to stream metadata
@@ -38,14 +44,53 @@ func convertMeta(segmentMeta segments.Meta) (Meta, error) {
 		return Meta{}, err
 	}
 
+	size := ((msi.NumberOfSegments - 1) * msi.SegmentsSize) + msi.LastSegmentSize
+	if len(msi.Parts) > 0 {
+		size = 0
+		for _, part := range msi.Parts {
+			size += convertPart(part).Size
+		}
+	}
+
 	return Meta{
 		Modified:   segmentMeta.Modified,
 		Expiration: segmentMeta.Expiration,
-		Size:       ((msi.NumberOfSegments - 1) * msi.SegmentsSize) + msi.LastSegmentSize,
+		Size:       size,
 		Data:       msi.Metadata,
+		Checksum:   msi.Checksum,
 	}, nil
 }
 
+// Part is a part of a multipart upload. Its data is kept in its own segments,
+// which are referenced by the stream the parts are stitched together into.
+type Part struct {
+	Number          int
+	ETag            string
+	Size            int64
+	Segments        int64
+	SegmentsSize    int64
+	LastSegmentSize int64
+}
+
+// convertPart converts the part info of a stitched stream to a Part
+func convertPart(part *pb.MetaStreamPart) Part {
+	return Part{
+		Number:          int(part.Number),
+		ETag:            part.Etag,
+		Size:            ((part.NumberOfSegments - 1) * part.SegmentsSize) + part.LastSegmentSize,
+		Segments:        part.NumberOfSegments,
+		SegmentsSize:    part.SegmentsSize,
+		LastSegmentSize: part.LastSegmentSize,
+	}
+}
+
+// partSegmentPath returns the path of a segment of a multipart upload part.
+// Like the other segment paths, it starts with the segment followed by the
+// bucket.
+func partSegmentPath(path paths.Path, uploadID string, number int, index int64) paths.Path {
+	return path.Prepend(fmt.Sprintf("u%s.p%d.s%d", uploadID, number, index))
+}
+
 // Store interface methods for streams to satisfy to be a store
 type Store interface {
 	Meta(ctx context.Context, path paths.Path) (Meta, error)
@@ -56,50 +101,59 @@ type Store interface {
 	List(ctx context.Context, prefix, startAfter, endBefore paths.Path,
 		recursive bool, limit int, metaFlags uint32) (items []ListItem,
 		more bool, err error)
+	PutPart(ctx context.Context, path paths.Path, uploadID string, number int,
+		data io.Reader, expiration time.Time) (Part, error)
+	CommitParts(ctx context.Context, path paths.Path, uploadID string,
+		parts []Part, metadata []byte, expiration time.Time) (Meta, error)
+	DeleteParts(ctx context.Context, path paths.Path, uploadID string, parts []Part) error
 }
 
 // streamStore is a store for streams
 type streamStore struct {
-	segments    segments.Store
-	segmentSize int64
+	segments         segments.Store
+	segmentSize      int64
+	inflightSegments int
 }
 
-// NewStreamStore stuff
-func NewStreamStore(segments segments.Store, segmentSize int64) (Store, error) {
+// NewStreamStore creates a stream store that uploads up to inflightSegments
+// segments of a stream at once. With more than one, the segments are buffered
+// in memory, so each upload needs up to inflightSegments * segmentSize bytes.
+func NewStreamStore(segments segments.Store, segmentSize int64, inflightSegments int) (Store, error) {
 	if segmentSize <= 0 {
 		return nil, errs.New("segment size must be larger than 0")
 	}
-	return &streamStore{segments: segments, segmentSize: segmentSize}, nil
+	if inflightSegments <= 0 {
+		return nil, errs.New("inflight segments must be larger than 0")
+	}
+	return &streamStore{segments: segments, segmentSize: segmentSize, inflightSegments: inflightSegments}, nil
 }
 
 // Put breaks up data as it comes in into s.segmentSize length pieces, then
 // store the first piece at s0/<path>, second piece at s1/<path>, and the
 // *last* piece at l/<path>. Store the given metadata, along with the number
-// of segments, in a new protobuf, in the metadata of l/<path>.
+// of segments and the checksum of data, in a new protobuf, in the metadata
+// of l/<path>.
 func (s *streamStore) Put(ctx context.Context, path paths.Path, data io.Reader,
 	metadata []byte, expiration time.Time) (m Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
 
-	var totalSegments int64
-	var totalSize int64
-	var lastSegmentSize int64
-
+	checksum := sha256.New()
+	data = io.TeeReader(data, checksum)
 	awareLimitReader := EOFAwareReader(data)
 
-	for !awareLimitReader.isEOF() && !awareLimitReader.hasError() {
-		segmentPath := path.Prepend(fmt.Sprintf("s%d", totalSegments))
-		segmentData := io.LimitReader(awareLimitReader, s.segmentSize)
-
-		putMeta, err := s.segments.Put(ctx, segmentPath, segmentData, nil, expiration)
-		if err != nil {
-			return Meta{}, err
-		}
-		lastSegmentSize = putMeta.Size
-		totalSize = totalSize + putMeta.Size
-		totalSegments = totalSegments + 1
+	sizes, err := s.putAll(ctx, func(index int) paths.Path {
+		return path.Prepend(fmt.Sprintf("s%d", index))
+	}, awareLimitReader, expiration)
+	if err != nil {
+		return Meta{}, err
 	}
-	if awareLimitReader.hasError() {
-		return Meta{}, awareLimitReader.err
+
+	totalSegments := int64(len(sizes))
+	var totalSize int64
+	var lastSegmentSize int64
+	for _, size := range sizes {
+		totalSize += size
+		lastSegmentSize = size
 	}
 
 	lastSegmentPath := path.Prepend("l")
@@ -109,6 +163,8 @@ func (s *streamStore) Put(ctx context.Context, path paths.Path, data io.Reader,
 		SegmentsSize:     s.segmentSize,
 		LastSegmentSize:  lastSegmentSize,
 		Metadata:         metadata,
+		// data was read to the end by putAll
+		Checksum: checksum.Sum(nil),
 	}
 	lastSegmentMetadata, err := proto.Marshal(&md)
 	if err != nil {
@@ -127,14 +183,209 @@ func (s *streamStore) Put(ctx context.Context, path paths.Path, data io.Reader,
 		Expiration: expiration,
 		Size:       totalSize,
 		Data:       metadata,
+		Checksum:   md.Checksum,
 	}
 
 	return resultMeta, nil
 }
 
+// putAll uploads the segments of data to the paths given by segmentPath and
+// returns their sizes
+func (s *streamStore) putAll(ctx context.Context, segmentPath func(index int) paths.Path,
+	data *EOFAwareLimitReader, expiration time.Time) (sizes []int64, err error) {
+	if s.inflightSegments > 1 {
+		return s.putSegmentsPipelined(ctx, segmentPath, data, expiration)
+	}
+	return s.putSegments(ctx, segmentPath, data, expiration)
+}
+
+// putSegments uploads the segments of data one after the other, while data
+// is read, and returns their sizes
+func (s *streamStore) putSegments(ctx context.Context, segmentPath func(index int) paths.Path,
+	data *EOFAwareLimitReader, expiration time.Time) (sizes []int64, err error) {
+	for !data.isEOF() && !data.hasError() {
+		segmentPath := segmentPath(len(sizes))
+		segmentData := io.LimitReader(data, s.segmentSize)
+
+		putMeta, err := s.segments.Put(ctx, segmentPath, segmentData, nil, expiration)
+		if err != nil {
+			return nil, err
+		}
+		sizes = append(sizes, putMeta.Size)
+	}
+	if data.hasError() {
+		return nil, data.err
+	}
+	return sizes, nil
+}
+
+// putSegmentsPipelined reads each segment of data into memory and uploads it
+// in the background, so that the next segment is read and uploaded while the
+// previous ones are finishing. At most s.inflightSegments segments are held
+// in memory at once. It returns the sizes of the segments.
+func (s *streamStore) putSegmentsPipelined(ctx context.Context, segmentPath func(index int) paths.Path,
+	data *EOFAwareLimitReader, expiration time.Time) (sizes []int64, err error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		putErr   error
+		putSizes = map[int]int64{}
+		inflight = make(chan struct{}, s.inflightSegments)
+	)
+	failed := func() error {
+		mu.Lock()
+		defer mu.Unlock()
+		return putErr
+	}
+
+	count := 0
+	for !data.isEOF() && !data.hasError() && failed() == nil {
+		select {
+		case inflight <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		segmentData, err := ioutil.ReadAll(io.LimitReader(data, s.segmentSize))
+		if err != nil {
+			<-inflight
+			break
+		}
+
+		index := count
+		count++
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-inflight }()
+
+			putMeta, err := s.segments.Put(ctx, segmentPath(index), bytes.NewReader(segmentData), nil, expiration)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if putErr == nil {
+					putErr = err
+					cancel()
+				}
+				return
+			}
+			putSizes[index] = putMeta.Size
+		}()
+	}
+	wg.Wait()
+
+	if err := failed(); err != nil {
+		return nil, err
+	}
+	if data.hasError() {
+		return nil, data.err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	sizes = make([]int64, count)
+	for index := range sizes {
+		sizes[index] = putSizes[index]
+	}
+	return sizes, nil
+}
+
+// PutPart uploads the part number of the multipart upload uploadID of path.
+// The parts of an upload can be uploaded in any order, and a part can be
+// uploaded again. Nothing can be downloaded from path until the parts are
+// stitched together with CommitParts.
+func (s *streamStore) PutPart(ctx context.Context, path paths.Path, uploadID string, number int,
+	data io.Reader, expiration time.Time) (part Part, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	sizes, err := s.putAll(ctx, func(index int) paths.Path {
+		return partSegmentPath(path, uploadID, number, int64(index))
+	}, EOFAwareReader(data), expiration)
+	if err != nil {
+		return Part{}, err
+	}
+
+	part = Part{
+		Number:       number,
+		Segments:     int64(len(sizes)),
+		SegmentsSize: s.segmentSize,
+	}
+	for _, size := range sizes {
+		part.Size += size
+		part.LastSegmentSize = size
+	}
+	return part, nil
+}
+
+// CommitParts stitches the given parts of the multipart upload uploadID
+// together, in the given order, into the stream at path. The segments of the
+// parts aren't copied, l/<path> refers to them where they were uploaded.
+func (s *streamStore) CommitParts(ctx context.Context, path paths.Path, uploadID string,
+	parts []Part, metadata []byte, expiration time.Time) (m Meta, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	md := pb.MetaStreamInfo{
+		Metadata: metadata,
+		UploadId: uploadID,
+	}
+	var totalSize int64
+	for _, part := range parts {
+		md.Parts = append(md.Parts, &pb.MetaStreamPart{
+			Number:           int32(part.Number),
+			Etag:             part.ETag,
+			NumberOfSegments: part.Segments,
+			SegmentsSize:     part.SegmentsSize,
+			LastSegmentSize:  part.LastSegmentSize,
+		})
+		totalSize += part.Size
+	}
+	lastSegmentMetadata, err := proto.Marshal(&md)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	putMeta, err := s.segments.Put(ctx, path.Prepend("l"), bytes.NewReader(nil),
+		lastSegmentMetadata, expiration)
+	if err != nil {
+		return Meta{}, err
+	}
+
+	return Meta{
+		Modified:   putMeta.Modified,
+		Expiration: expiration,
+		Size:       totalSize,
+		Data:       metadata,
+	}, nil
+}
+
+// DeleteParts deletes the segments of the given parts of the multipart
+// upload uploadID
+func (s *streamStore) DeleteParts(ctx context.Context, path paths.Path, uploadID string,
+	parts []Part) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	for _, part := range parts {
+		for i := int64(0); i < part.Segments; i++ {
+			err := s.segments.Delete(ctx, partSegmentPath(path, uploadID, part.Number, i))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Get returns a ranger that knows what the overall size is (from l/<path>)
 // and then returns the appropriate data from segments s0/<path>, s1/<path>,
-// ..., l/<path>.
+// ..., l/<path>. Reading the whole stream fails with an ErrChecksum error if
+// it doesn't match its checksum.
 func (s *streamStore) Get(ctx context.Context, path paths.Path) (
 	rr ranger.Ranger, meta Meta, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -157,6 +408,21 @@ func (s *streamStore) Get(ctx context.Context, path paths.Path) (
 
 	var rangers []ranger.Ranger
 
+	for _, part := range msi.Parts {
+		part := convertPart(part)
+		for i := int64(0); i < part.Segments; i++ {
+			size := part.SegmentsSize
+			if i == part.Segments-1 {
+				size = part.LastSegmentSize
+			}
+			rangers = append(rangers, &lazySegmentRanger{
+				segments: s.segments,
+				path:     partSegmentPath(path, msi.UploadId, part.Number, i),
+				size:     size,
+			})
+		}
+	}
+
 	for i := int64(0); i < msi.NumberOfSegments; i++ {
 		currentPath := fmt.Sprintf("s%d", i)
 		size := msi.SegmentsSize
@@ -175,7 +441,7 @@ func (s *streamStore) Get(ctx context.Context, path paths.Path) (
 
 	catRangers := ranger.Concat(rangers...)
 
-	return catRangers, newMeta, nil
+	return verifyChecksum(catRangers, msi.Checksum), newMeta, nil
 }
 
 // Meta implements Store.Meta
@@ -216,6 +482,15 @@ func (s *streamStore) Delete(ctx context.Context, path paths.Path) (err error) {
 		}
 	}
 
+	var parts []Part
+	for _, part := range msi.Parts {
+		parts = append(parts, convertPart(part))
+	}
+	err = s.DeleteParts(ctx, path, msi.UploadId, parts)
+	if err != nil {
+		return err
+	}
+
 	return s.segments.Delete(ctx, path.Prepend("l"))
 }
 
@@ -0,0 +1,271 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package streams
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/segments"
+)
+
+// memorySegments is a segments.Store that keeps the segments in memory and
+// tracks how many puts run at once
+type memorySegments struct {
+	segments.Store
+
+	// release is received from before a put of an s segment returns, if set
+	release chan struct{}
+	fail    string
+
+	mu          sync.Mutex
+	data        map[string][]byte
+	metadata    map[string][]byte
+	inflight    int
+	maxInflight int
+}
+
+func (store *memorySegments) Put(ctx context.Context, path paths.Path, data io.Reader, metadata []byte,
+	expiration time.Time) (segments.Meta, error) {
+	store.mu.Lock()
+	store.inflight++
+	if store.inflight > store.maxInflight {
+		store.maxInflight = store.inflight
+	}
+	store.mu.Unlock()
+	defer func() {
+		store.mu.Lock()
+		store.inflight--
+		store.mu.Unlock()
+	}()
+
+	content, err := ioutil.ReadAll(data)
+	if err != nil {
+		return segments.Meta{}, err
+	}
+	if path.String() == store.fail {
+		return segments.Meta{}, errors.New("put failed")
+	}
+	if store.release != nil && path.String() != "l/bucket/object" {
+		select {
+		case <-store.release:
+		case <-ctx.Done():
+			return segments.Meta{}, ctx.Err()
+		}
+	}
+
+	store.mu.Lock()
+	store.data[path.String()] = content
+	if store.metadata != nil {
+		store.metadata[path.String()] = metadata
+	}
+	store.mu.Unlock()
+	return segments.Meta{Size: int64(len(content)), Data: metadata}, nil
+}
+
+func (store *memorySegments) Meta(ctx context.Context, path paths.Path) (segments.Meta, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	content, ok := store.data[path.String()]
+	if !ok {
+		return segments.Meta{}, errors.New("not found")
+	}
+	return segments.Meta{Size: int64(len(content)), Data: store.metadata[path.String()]}, nil
+}
+
+func (store *memorySegments) Get(ctx context.Context, path paths.Path) (ranger.Ranger, segments.Meta, error) {
+	meta, err := store.Meta(ctx, path)
+	if err != nil {
+		return nil, segments.Meta{}, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return ranger.ByteRanger(store.data[path.String()]), meta, nil
+}
+
+func (store *memorySegments) Delete(ctx context.Context, path paths.Path) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, ok := store.data[path.String()]; !ok {
+		return errors.New("not found")
+	}
+	delete(store.data, path.String())
+	delete(store.metadata, path.String())
+	return nil
+}
+
+func (store *memorySegments) inflightPuts() int {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	return store.inflight
+}
+
+func TestPutPipelined(t *testing.T) {
+	ctx := context.Background()
+	data := bytes.Repeat([]byte("0123456789"), 25)
+
+	for _, inflight := range []int{1, 3} {
+		store := &memorySegments{data: map[string][]byte{}}
+		streams, err := NewStreamStore(store, 100, inflight)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		if inflight > 1 {
+			// the segments only finish once all of them are uploading
+			store.release = make(chan struct{})
+			go func() {
+				for store.inflightPuts() < 3 {
+					time.Sleep(time.Millisecond)
+				}
+				for i := 0; i < 3; i++ {
+					store.release <- struct{}{}
+				}
+			}()
+		}
+
+		meta, err := streams.Put(ctx, paths.New("bucket", "object"), bytes.NewReader(data), nil, time.Time{})
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.EqualValues(t, len(data), meta.Size)
+		assert.Equal(t, inflight, store.maxInflight)
+
+		var uploaded []byte
+		for _, segment := range []string{"s0/bucket/object", "s1/bucket/object", "s2/bucket/object"} {
+			uploaded = append(uploaded, store.data[segment]...)
+		}
+		assert.Equal(t, data, uploaded)
+	}
+}
+
+func TestPutPipelinedError(t *testing.T) {
+	store := &memorySegments{data: map[string][]byte{}, fail: "s1/bucket/object"}
+	streams, err := NewStreamStore(store, 100, 2)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	data := bytes.Repeat([]byte("0123456789"), 50)
+	_, err = streams.Put(context.Background(), paths.New("bucket", "object"), bytes.NewReader(data), nil, time.Time{})
+	assert.EqualError(t, err, "put failed")
+	assert.NotContains(t, store.data, "l/bucket/object")
+}
+
+func TestCommitParts(t *testing.T) {
+	ctx := context.Background()
+	store := &memorySegments{data: map[string][]byte{}, metadata: map[string][]byte{}}
+	streams, err := NewStreamStore(store, 100, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	path := paths.New("bucket", "object")
+	first := bytes.Repeat([]byte("a"), 30)
+	second := bytes.Repeat([]byte("b"), 150)
+
+	// the parts are uploaded out of order
+	part2, err := streams.PutPart(ctx, path, "upload", 2, bytes.NewReader(second), time.Time{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualValues(t, 150, part2.Size)
+	assert.EqualValues(t, 2, part2.Segments)
+	part1, err := streams.PutPart(ctx, path, "upload", 1, bytes.NewReader(first), time.Time{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	part1.ETag, part2.ETag = "etag1", "etag2"
+
+	_, err = streams.Meta(ctx, path)
+	assert.Error(t, err, "parts are not visible before they are committed")
+
+	meta, err := streams.CommitParts(ctx, path, "upload", []Part{part1, part2}, []byte("metadata"), time.Time{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.EqualValues(t, 180, meta.Size)
+
+	meta, err = streams.Meta(ctx, path)
+	if assert.NoError(t, err) {
+		assert.EqualValues(t, 180, meta.Size)
+		assert.Equal(t, []byte("metadata"), meta.Data)
+	}
+
+	rr, _, err := streams.Get(ctx, path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	reader, err := rr.Range(ctx, 0, rr.Size())
+	if !assert.NoError(t, err) {
+		return
+	}
+	downloaded, err := ioutil.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.NoError(t, reader.Close())
+	assert.Equal(t, append(first, second...), downloaded)
+
+	assert.NoError(t, streams.Delete(ctx, path))
+	assert.Empty(t, store.data)
+}
+
+func TestChecksum(t *testing.T) {
+	ctx := context.Background()
+	store := &memorySegments{data: map[string][]byte{}, metadata: map[string][]byte{}}
+	streams, err := NewStreamStore(store, 100, 1)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	path := paths.New("bucket", "object")
+	data := bytes.Repeat([]byte("0123456789"), 25)
+	meta, err := streams.Put(ctx, path, bytes.NewReader(data), nil, time.Time{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	checksum := sha256.Sum256(data)
+	assert.Equal(t, checksum[:], meta.Checksum)
+
+	read := func(offset, length int64) ([]byte, error) {
+		rr, _, err := streams.Get(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		reader, err := rr.Range(ctx, offset, length)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { assert.NoError(t, reader.Close()) }()
+		return ioutil.ReadAll(reader)
+	}
+
+	downloaded, err := read(0, int64(len(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, data, downloaded)
+
+	// corrupt the second segment
+	store.data["s1/bucket/object"][0] = 'x'
+
+	_, err = read(0, int64(len(data)))
+	assert.True(t, ErrChecksum.Has(err))
+
+	// partial downloads aren't verified
+	downloaded, err = read(0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, data[:100], downloaded)
+}
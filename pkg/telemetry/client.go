@@ -5,6 +5,7 @@ package telemetry
 
 import (
 	"context"
+	"net"
 	"os"
 	"time"
 
@@ -53,6 +54,10 @@ type ClientOpts struct {
 	// FloatEncoding is how floats should be encoded on the wire.
 	// Default is float16.
 	FloatEncoding admproto.FloatEncoding
+
+	// Spool keeps the packets that couldn't be sent, until the collector can
+	// be reached again. Without it, they are dropped.
+	Spool *Spool
 }
 
 // Client is a telemetry client for sending UDP packets at a regular interval
@@ -60,6 +65,7 @@ type ClientOpts struct {
 type Client struct {
 	interval time.Duration
 	opts     admmonkit.Options
+	spool    *Spool
 }
 
 // NewClient constructs a telemetry client that sends packets to remoteAddr
@@ -88,6 +94,7 @@ func NewClient(remoteAddr string, opts ClientOpts) (rv *Client, err error) {
 
 	return &Client{
 		interval: opts.Interval,
+		spool:    opts.Spool,
 		opts: admmonkit.Options{
 			Application: opts.Application,
 			InstanceId:  []byte(opts.Instance),
@@ -115,5 +122,78 @@ func (c *Client) Run(ctx context.Context) {
 
 // Report bundles up all the current stats and writes them out as UDP packets
 func (c *Client) Report(ctx context.Context) error {
-	return admmonkit.Send(ctx, c.opts)
+	if c.spool == nil {
+		return admmonkit.Send(ctx, c.opts)
+	}
+
+	packets, err := c.collect(ctx)
+	if err != nil {
+		return err
+	}
+	return c.forward(packets)
+}
+
+// collect bundles up all the current stats into UDP packets, which are sent
+// to a local socket to be kept
+func (c *Client) collect(ctx context.Context) (packets [][]byte, err error) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	received := make(chan [][]byte, 1)
+	go func() {
+		var packets [][]byte
+		buf := make([]byte, 64*1024)
+		for {
+			n, _, err := conn.ReadFrom(buf)
+			if err != nil {
+				received <- packets
+				return
+			}
+			packets = append(packets, append([]byte(nil), buf[:n]...))
+		}
+	}()
+
+	opts := c.opts
+	opts.Address = conn.LocalAddr().String()
+	err = admmonkit.Send(ctx, opts)
+
+	// the packets that were sent are queued on the socket already, so the
+	// reader only has to drain them
+	_ = conn.SetReadDeadline(time.Now().Add(100 * time.Millisecond))
+	packets = <-received
+	if err != nil {
+		return nil, err
+	}
+	return packets, nil
+}
+
+// forward sends packets to the collector, followed by the spooled ones. The
+// packets that can't be sent are spooled.
+func (c *Client) forward(packets [][]byte) error {
+	conn, err := net.Dial("udp", c.opts.Address)
+	if err != nil {
+		zap.S().Warnf("telemetry collector unreachable, spooling %d packets: %v", len(packets), err)
+		return c.spool.Add(packets)
+	}
+	defer func() { _ = conn.Close() }()
+
+	send := func(packet []byte) error {
+		_, err := conn.Write(packet)
+		return err
+	}
+	for i, packet := range packets {
+		if err := send(packet); err != nil {
+			zap.S().Warnf("telemetry collector unreachable, spooling %d packets: %v", len(packets)-i, err)
+			return c.spool.Add(packets[i:])
+		}
+	}
+
+	sent, err := c.spool.Replay(send)
+	if sent > 0 {
+		zap.S().Infof("sent %d spooled telemetry packets", sent)
+	}
+	return err
 }
@@ -0,0 +1,205 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package telemetry
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSpoolSize is the default limit of how many bytes of packets are
+	// kept in a spool
+	DefaultSpoolSize = 10 << 20
+
+	// DefaultSpoolAge is the default limit of how long packets are kept in a
+	// spool
+	DefaultSpoolAge = 24 * time.Hour
+
+	spoolExt = ".spool"
+)
+
+// Spool keeps batches of metric packets on disk that couldn't be sent, so
+// that they can be sent later. The oldest batches are dropped once the spool
+// holds more than MaxSize bytes, and batches older than MaxAge are dropped.
+type Spool struct {
+	dir     string
+	maxSize int64
+	maxAge  time.Duration
+
+	mu sync.Mutex
+}
+
+// NewSpool creates a spool keeping its batches in dir
+func NewSpool(dir string, maxSize int64, maxAge time.Duration) (*Spool, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultSpoolSize
+	}
+	if maxAge <= 0 {
+		maxAge = DefaultSpoolAge
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return &Spool{dir: dir, maxSize: maxSize, maxAge: maxAge}, nil
+}
+
+// Add writes a batch of packets to the spool
+func (s *Spool) Add(packets [][]byte) error {
+	if len(packets) == 0 {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := filepath.Join(s.dir, fmt.Sprintf("%019d%s", time.Now().UnixNano(), spoolExt))
+	if err := ioutil.WriteFile(name, encodeBatch(packets), 0600); err != nil {
+		return Error.Wrap(err)
+	}
+	return s.trim()
+}
+
+// Replay sends the spooled batches, oldest first, with send and removes the
+// packets that were sent. It stops at the first packet send fails on.
+func (s *Spool) Replay(send func(packet []byte) error) (sent int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.trim(); err != nil {
+		return 0, err
+	}
+	batches, err := s.batches()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, batch := range batches {
+		data, err := ioutil.ReadFile(batch.path)
+		if err != nil {
+			return sent, Error.Wrap(err)
+		}
+		packets, err := decodeBatch(data)
+		if err != nil {
+			// a corrupt batch can't ever be sent
+			if err := os.Remove(batch.path); err != nil {
+				return sent, Error.Wrap(err)
+			}
+			continue
+		}
+
+		for i, packet := range packets {
+			if err := send(packet); err != nil {
+				// keep the packets that weren't sent for the next replay
+				if err := ioutil.WriteFile(batch.path, encodeBatch(packets[i:]), 0600); err != nil {
+					return sent, Error.Wrap(err)
+				}
+				return sent, err
+			}
+			sent++
+		}
+		if err := os.Remove(batch.path); err != nil {
+			return sent, Error.Wrap(err)
+		}
+	}
+	return sent, nil
+}
+
+// spooledBatch is a batch file in the spool
+type spooledBatch struct {
+	path    string
+	created time.Time
+	size    int64
+}
+
+// batches returns the batches in the spool, oldest first
+func (s *Spool) batches() ([]spooledBatch, error) {
+	infos, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	var batches []spooledBatch
+	for _, info := range infos {
+		if info.IsDir() || !strings.HasSuffix(info.Name(), spoolExt) {
+			continue
+		}
+		nanos, err := strconv.ParseInt(strings.TrimSuffix(info.Name(), spoolExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		batches = append(batches, spooledBatch{
+			path:    filepath.Join(s.dir, info.Name()),
+			created: time.Unix(0, nanos),
+			size:    info.Size(),
+		})
+	}
+	sort.Slice(batches, func(i, k int) bool {
+		return batches[i].created.Before(batches[k].created)
+	})
+	return batches, nil
+}
+
+// trim drops the batches that are too old, and the oldest batches until the
+// spool is small enough
+func (s *Spool) trim() error {
+	batches, err := s.batches()
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, batch := range batches {
+		total += batch.size
+	}
+	for _, batch := range batches {
+		if total <= s.maxSize && time.Since(batch.created) <= s.maxAge {
+			continue
+		}
+		if err := os.Remove(batch.path); err != nil {
+			return Error.Wrap(err)
+		}
+		total -= batch.size
+	}
+	return nil
+}
+
+// encodeBatch encodes packets, each prefixed by its length
+func encodeBatch(packets [][]byte) []byte {
+	var buf bytes.Buffer
+	var size [4]byte
+	for _, packet := range packets {
+		binary.BigEndian.PutUint32(size[:], uint32(len(packet)))
+		_, _ = buf.Write(size[:])
+		_, _ = buf.Write(packet)
+	}
+	return buf.Bytes()
+}
+
+// decodeBatch decodes the packets of a batch
+func decodeBatch(data []byte) (packets [][]byte, err error) {
+	r := bytes.NewReader(data)
+	for r.Len() > 0 {
+		var size uint32
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		packet := make([]byte, size)
+		if _, err := io.ReadFull(r, packet); err != nil {
+			return nil, Error.Wrap(err)
+		}
+		packets = append(packets, packet)
+	}
+	return packets, nil
+}
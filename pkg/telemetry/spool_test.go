@@ -0,0 +1,130 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+)
+
+func TestSpool(t *testing.T) {
+	dir, err := ioutil.TempDir("", "telemetry-spool")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	spool, err := NewSpool(dir, 0, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, spool.Add([][]byte{[]byte("a"), []byte("b")}))
+	assert.NoError(t, spool.Add([][]byte{[]byte("c")}))
+
+	// the replay stops at the first packet that can't be sent
+	var sent []string
+	count, err := spool.Replay(func(packet []byte) error {
+		if string(packet) == "b" && len(sent) == 1 {
+			return errors.New("unreachable")
+		}
+		sent = append(sent, string(packet))
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, count)
+
+	count, err = spool.Replay(func(packet []byte) error {
+		sent = append(sent, string(packet))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []string{"a", "b", "c"}, sent)
+
+	batches, err := spool.batches()
+	assert.NoError(t, err)
+	assert.Empty(t, batches)
+}
+
+func TestSpoolRetention(t *testing.T) {
+	dir, err := ioutil.TempDir("", "telemetry-spool")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	// every batch takes 4+10 bytes, so only two fit
+	spool, err := NewSpool(dir, 30, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, packet := range []string{"0000000001", "0000000002", "0000000003"} {
+		assert.NoError(t, spool.Add([][]byte{[]byte(packet)}))
+	}
+
+	var sent []string
+	_, err = spool.Replay(func(packet []byte) error {
+		sent = append(sent, string(packet))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0000000002", "0000000003"}, sent)
+}
+
+func TestClientSpools(t *testing.T) {
+	dir, err := ioutil.TempDir("", "telemetry-spool")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	spool, err := NewSpool(dir, 0, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	registry := monkit.NewRegistry()
+	registry.ScopeNamed("test").Counter("counter").Inc(1)
+
+	// the collector can't be resolved, so the packets are spooled
+	unreachable, err := NewClient("collector.invalid:9000", ClientOpts{
+		Application: "testapp",
+		Instance:    "testinst",
+		Registry:    registry,
+		Spool:       spool,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, unreachable.Report(context.Background()))
+	batches, err := spool.batches()
+	assert.NoError(t, err)
+	assert.Len(t, batches, 1)
+
+	s, err := Listen("127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, s.Close()) }()
+
+	client, err := NewClient(s.Addr(), ClientOpts{
+		Application: "testapp",
+		Instance:    "testinst",
+		Registry:    registry,
+		Spool:       spool,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, client.Report(context.Background()))
+	batches, err = spool.batches()
+	assert.NoError(t, err)
+	assert.Empty(t, batches)
+}
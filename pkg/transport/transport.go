@@ -5,8 +5,13 @@ package transport
 
 import (
 	"context"
+	"net"
+	"sync"
+	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/provider"
@@ -14,7 +19,8 @@ import (
 
 // Transport interface structure
 type Transport struct {
-	identity *provider.FullIdentity
+	identity    *provider.FullIdentity
+	dialTimeout time.Duration
 }
 
 // NewClient returns a newly instantiated Transport Client
@@ -22,6 +28,13 @@ func NewClient(identity *provider.FullIdentity) *Transport {
 	return &Transport{identity: identity}
 }
 
+// NewClientWithTimeout returns a Transport Client whose dials fail if they
+// can't connect to the node within dialTimeout. A dialTimeout of 0 doesn't
+// wait for the connection, like NewClient.
+func NewClientWithTimeout(identity *provider.FullIdentity, dialTimeout time.Duration) *Transport {
+	return &Transport{identity: identity, dialTimeout: dialTimeout}
+}
+
 // DialNode using the authenticated mode
 func (o *Transport) DialNode(ctx context.Context, node *pb.Node) (conn *grpc.ClientConn, err error) {
 	defer mon.Task()(&ctx)(&err)
@@ -34,7 +47,71 @@ func (o *Transport) DialNode(ctx context.Context, node *pb.Node) (conn *grpc.Cli
 	if err != nil {
 		return nil, err
 	}
-	return grpc.Dial(node.Address.Address, dialOpt)
+	if o.dialTimeout <= 0 {
+		return grpc.Dial(node.Address.Address, dialOpt)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, o.dialTimeout)
+	defer cancel()
+	conn, err = grpc.DialContext(ctx, node.Address.Address, dialOpt, grpc.WithBlock())
+	if err != nil {
+		return nil, Error.New("dialing node %s: %v", node.GetId(), err)
+	}
+	return conn, nil
+}
+
+// DialNodePeer is like DialNode, but waits for the connection to the node to
+// be established and returns its peer, so that the identity of the node can
+// be checked before any request is sent to it
+func (o *Transport) DialNodePeer(ctx context.Context, node *pb.Node) (conn *grpc.ClientConn, p *peer.Peer, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	if node.Address == nil || node.Address.Address == "" {
+		return nil, nil, Error.New("no address")
+	}
+
+	creds, err := o.identity.TransportCredentials()
+	if err != nil {
+		return nil, nil, err
+	}
+	handshakes := &peerCredentials{TransportCredentials: creds}
+
+	if o.dialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.dialTimeout)
+		defer cancel()
+	}
+	conn, err = grpc.DialContext(ctx, node.Address.Address, grpc.WithTransportCredentials(handshakes), grpc.WithBlock())
+	if err != nil {
+		return nil, nil, Error.New("dialing node %s: %v", node.GetId(), err)
+	}
+	return conn, &peer.Peer{AuthInfo: handshakes.authInfo()}, nil
+}
+
+// peerCredentials are transport credentials that keep the auth info of the
+// last handshake made with them
+type peerCredentials struct {
+	credentials.TransportCredentials
+	mu   sync.Mutex
+	info credentials.AuthInfo
+}
+
+// ClientHandshake implements credentials.TransportCredentials
+func (c *peerCredentials) ClientHandshake(ctx context.Context, authority string, rawConn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	conn, info, err := c.TransportCredentials.ClientHandshake(ctx, authority, rawConn)
+	if err == nil {
+		c.mu.Lock()
+		c.info = info
+		c.mu.Unlock()
+	}
+	return conn, info, err
+}
+
+// authInfo returns the auth info of the last handshake
+func (c *peerCredentials) authInfo() credentials.AuthInfo {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.info
 }
 
 // DialUnauthenticated using unauthenticated mode
@@ -4,9 +4,12 @@ package transport
 
 import (
 	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
 
 	"storj.io/storj/pkg/pb"
 	"storj.io/storj/pkg/provider"
@@ -57,3 +60,44 @@ func TestDialNode(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, conn)
 }
+
+func TestDialNodePeer(t *testing.T) {
+	ca, err := provider.NewCA(ctx, 12, 4)
+	assert.NoError(t, err)
+	identity, err := ca.NewIdentity()
+	assert.NoError(t, err)
+	serverCA, err := provider.NewCA(ctx, 12, 4)
+	assert.NoError(t, err)
+	serverIdentity, err := serverCA.NewIdentity()
+	assert.NoError(t, err)
+
+	serverOpt, err := serverIdentity.ServerOption()
+	assert.NoError(t, err)
+	server := grpc.NewServer(serverOpt)
+	defer server.Stop()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	go func() { _ = server.Serve(lis) }()
+
+	oc := NewClientWithTimeout(identity, 5*time.Second)
+
+	// no address
+	_, _, err = oc.DialNodePeer(ctx, &pb.Node{Id: "DUMMYID1"})
+	assert.Error(t, err)
+
+	// the peer is known before any request is sent
+	conn, p, err := oc.DialNodePeer(ctx, &pb.Node{
+		Id: serverIdentity.ID.String(),
+		Address: &pb.NodeAddress{
+			Transport: pb.NodeTransport_TCP,
+			Address:   lis.Addr().String(),
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer func() { assert.NoError(t, conn.Close()) }()
+	peerIdentity, err := provider.PeerIdentityFromPeer(p)
+	assert.NoError(t, err)
+	assert.Equal(t, serverIdentity.ID, peerIdentity.ID)
+}
@@ -0,0 +1,466 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package verification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/datarepair"
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/vetting"
+	"storj.io/storj/storage"
+)
+
+// maxStatPieces is how many pieces are requested from a node at once, the
+// most storage nodes accept
+const maxStatPieces = 1000
+
+// ErrAuditRunning is returned when an audit is started while another one
+// is running
+var ErrAuditRunning = errs.Class("node audit already running")
+
+// NodeAudit is an audit of nodes for the pieces pointerdb has on them
+type NodeAudit struct {
+	Nodes    []string  `json:"nodes"`
+	Repair   bool      `json:"repair"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	// Error is why the audit failed, if it did
+	Error string `json:"error,omitempty"`
+	// Results contains the result of every node, by node id
+	Results map[string]*NodeAuditResult `json:"results"`
+	// Queued is the number of segments added to the repair queue
+	Queued int `json:"queued"`
+}
+
+// NodeAuditResult is the result of the audit of a node
+type NodeAuditResult struct {
+	// Pieces is the number of pieces the node should have
+	Pieces int `json:"pieces"`
+	// Checked is the number of pieces the node reported on
+	Checked int `json:"checked"`
+	// Challenged is the number of pieces the node had to prove it stores
+	Challenged int `json:"challenged"`
+	// Missing are the paths of the segments whose piece the node lost or
+	// failed to prove it stores
+	Missing []string `json:"missing"`
+	// Error is why the node couldn't report on all its pieces
+	Error string `json:"error,omitempty"`
+	// Contained is whether the node is contained, as it didn't answer its
+	// challenge, or Reverified the outcome of the challenge it was asked
+	// again if it was contained before the audit
+	Contained  bool   `json:"contained,omitempty"`
+	Reverified string `json:"reverified,omitempty"`
+}
+
+// Auditor asks storage nodes whether they still have all the pieces that
+// pointerdb has on them, without downloading them, to find the data lost in
+// incidents like disk failures before the nodes are audited or go offline.
+// Segments with lost pieces can be queued for repair right away.
+type Auditor struct {
+	log         *zap.Logger
+	loop        *metainfo.Loop
+	stater      Stater
+	queue       datarepair.RepairQueue
+	vetting     *vetting.Tracker
+	containment *audit.Containment
+	concurrency int
+	requests    chan auditRequest
+
+	mu      sync.Mutex
+	running *NodeAudit
+	last    *NodeAudit
+}
+
+type auditRequest struct {
+	nodes  []string
+	repair bool
+}
+
+// NewAuditor creates an Auditor of the pointers of loop, asking up to
+// concurrency nodes at a time and adding the segments to repair to queue.
+// The audits that nodes completed are counted toward their vetting in
+// tracker, which may be nil. Nodes that don't answer their challenge are
+// kept in containment and challenged again at their next audit.
+func NewAuditor(log *zap.Logger, loop *metainfo.Loop, stater Stater, queue datarepair.RepairQueue, tracker *vetting.Tracker, containment *audit.Containment, concurrency int) *Auditor {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Auditor{
+		log:         log,
+		loop:        loop,
+		stater:      stater,
+		queue:       queue,
+		vetting:     tracker,
+		containment: containment,
+		concurrency: concurrency,
+		requests:    make(chan auditRequest),
+	}
+}
+
+// Run runs the audits started with Start until ctx is canceled
+func (auditor *Auditor) Run(ctx context.Context) error {
+	for {
+		select {
+		case req := <-auditor.requests:
+			audit, err := auditor.Audit(ctx, req.nodes, req.repair)
+			if err != nil {
+				auditor.log.Error("node audit failed", zap.Error(err))
+				audit.Error = err.Error()
+			}
+			auditor.mu.Lock()
+			auditor.running, auditor.last = nil, audit
+			auditor.mu.Unlock()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Start starts an audit of nodes in the background, unless one is running
+func (auditor *Auditor) Start(nodes []string, repair bool) error {
+	select {
+	case auditor.requests <- auditRequest{nodes: nodes, repair: repair}:
+		return nil
+	default:
+		return ErrAuditRunning.New("")
+	}
+}
+
+// Running returns whether an audit is running
+func (auditor *Auditor) Running() bool {
+	auditor.mu.Lock()
+	defer auditor.mu.Unlock()
+	return auditor.running != nil
+}
+
+// Last returns the most recently finished audit, or nil
+func (auditor *Auditor) Last() *NodeAudit {
+	auditor.mu.Lock()
+	defer auditor.mu.Unlock()
+	return auditor.last
+}
+
+// nodePiece is a piece of a segment on an audited node
+type nodePiece struct {
+	path string
+	num  int32
+	id   client.PieceID
+	// root is the root of the Merkle tree of the piece, nil for pieces
+	// uploaded before they were computed, and size the size of the piece
+	root []byte
+	size int64
+}
+
+// collector collects the pieces of the audited nodes during an iteration of
+// the metainfo loop
+type collector struct {
+	pieces map[string][]nodePiece
+}
+
+// Pointer implements metainfo.Observer
+func (collector *collector) Pointer(ctx context.Context, path storage.Key, pointer *pb.Pointer) error {
+	remote := pointer.GetRemote()
+	if remote == nil {
+		return nil
+	}
+
+	pieceID := client.PieceID(remote.GetPieceId())
+	size := audit.PieceSize(pointer)
+	for _, piece := range remote.GetRemotePieces() {
+		nodeID := piece.GetNodeId()
+		if _, audited := collector.pieces[nodeID]; !audited {
+			continue
+		}
+		derived, err := pieceID.Derive([]byte(nodeID))
+		if err != nil {
+			return Error.Wrap(err)
+		}
+		collector.pieces[nodeID] = append(collector.pieces[nodeID], nodePiece{
+			path: path.String(),
+			num:  piece.GetPieceNum(),
+			id:   derived,
+			root: piece.GetMerkleRoot(),
+			size: size,
+		})
+	}
+	return nil
+}
+
+// Audit asks nodes for the pieces pointerdb has on them and, if repair is
+// set, adds the segments with lost pieces to the repair queue. The audit is
+// returned even if it fails.
+func (auditor *Auditor) Audit(ctx context.Context, nodes []string, repair bool) (audit *NodeAudit, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	audit = &NodeAudit{
+		Nodes:   nodes,
+		Repair:  repair,
+		Started: time.Now(),
+		Results: map[string]*NodeAuditResult{},
+	}
+	auditor.mu.Lock()
+	auditor.running = audit
+	auditor.mu.Unlock()
+	defer func() { audit.Finished = time.Now() }()
+
+	collector := &collector{pieces: map[string][]nodePiece{}}
+	for _, nodeID := range nodes {
+		collector.pieces[nodeID] = nil
+	}
+	if err := auditor.loop.Join(ctx, collector); err != nil {
+		return audit, Error.Wrap(err)
+	}
+
+	// the lost piece numbers of every segment
+	lost := map[string][]int32{}
+
+	limiter := make(chan struct{}, auditor.concurrency)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for nodeID, pieces := range collector.pieces {
+		limiter <- struct{}{}
+		wg.Add(1)
+		go func(nodeID string, pieces []nodePiece) {
+			defer wg.Done()
+			defer func() { <-limiter }()
+
+			result, missing := auditor.auditNode(ctx, nodeID, pieces)
+
+			mu.Lock()
+			defer mu.Unlock()
+			audit.Results[nodeID] = result
+			for _, piece := range missing {
+				lost[piece.path] = append(lost[piece.path], piece.num)
+			}
+		}(nodeID, pieces)
+	}
+	wg.Wait()
+
+	if !repair || len(lost) == 0 {
+		return audit, nil
+	}
+
+	segments := make([]*pb.InjuredSegment, 0, len(lost))
+	for path, nums := range lost {
+		sort.Slice(nums, func(i, k int) bool { return nums[i] < nums[k] })
+		segments = append(segments, &pb.InjuredSegment{Path: path, LostPieces: nums})
+	}
+	sort.Slice(segments, func(i, k int) bool { return segments[i].Path < segments[k].Path })
+	if err := auditor.queue.AddAll(segments); err != nil {
+		return audit, Error.Wrap(err)
+	}
+	audit.Queued = len(segments)
+	auditor.log.Info("queued segments with lost pieces for repair", zap.Int("segments", len(segments)))
+	return audit, nil
+}
+
+// auditNode asks nodeID for pieces in batches, challenges it for one of the
+// pieces it has, and returns the pieces it lost. A contained node first has
+// to answer the challenge it didn't answer before, and isn't audited anew
+// until it does.
+func (auditor *Auditor) auditNode(ctx context.Context, nodeID string, pieces []nodePiece) (result *NodeAuditResult, missing []nodePiece) {
+	result = &NodeAuditResult{Pieces: len(pieces)}
+	if !auditor.reverify(ctx, nodeID, result) {
+		return result, nil
+	}
+
+	var challengeable []nodePiece
+	for len(pieces) > 0 {
+		batch := pieces
+		if len(batch) > maxStatPieces {
+			batch = batch[:maxStatPieces]
+		}
+		pieces = pieces[len(batch):]
+
+		ids := make([]client.PieceID, 0, len(batch))
+		for _, piece := range batch {
+			ids = append(ids, piece.id)
+		}
+		stats, err := auditor.stater.StatPieces(ctx, nodeID, ids)
+		if err != nil {
+			auditor.log.Warn("node audit incomplete", zap.String("node", nodeID), zap.Error(err))
+			result.Error = err.Error()
+			break
+		}
+		for i, stat := range stats {
+			// the hash of a piece is the root of its Merkle tree, which
+			// must be the root the uplink computed
+			hash := stat.GetHash()
+			if !stat.GetExists() || (batch[i].root != nil && hash != nil && !bytes.Equal(hash, batch[i].root)) {
+				missing = append(missing, batch[i])
+				result.Missing = append(result.Missing, batch[i].path)
+				continue
+			}
+			if batch[i].root != nil && batch[i].size > 0 {
+				challengeable = append(challengeable, batch[i])
+			}
+		}
+		result.Checked += len(batch)
+	}
+
+	// the metadata of a piece doesn't prove the node still has its data
+	if result.Error == "" && len(challengeable) > 0 {
+		piece := challengeable[rand.Intn(len(challengeable))]
+		leaf, err := audit.NewChallenge(piece.size)
+		var passed bool
+		if err == nil {
+			passed, err = auditor.challenge(ctx, nodeID, piece, leaf)
+			if err != nil {
+				result.Contained = auditor.contain(ctx, nodeID, piece, leaf)
+			}
+		}
+		if err != nil {
+			auditor.log.Warn("node audit incomplete", zap.String("node", nodeID), zap.Error(err))
+			result.Error = err.Error()
+		} else {
+			result.Challenged++
+			if !passed {
+				missing = append(missing, piece)
+				result.Missing = append(result.Missing, piece.path)
+			}
+		}
+	}
+
+	// nodes that couldn't be asked about all their pieces aren't judged, but
+	// missed the audit
+	if result.Error == "" {
+		if err := auditor.vetting.RecordAudit(ctx, nodeID, len(missing) == 0); err != nil {
+			auditor.log.Warn("could not record audit", zap.String("node", nodeID), zap.Error(err))
+		}
+	} else {
+		if err := auditor.vetting.RecordMissedAudit(ctx, nodeID); err != nil {
+			auditor.log.Warn("could not record missed audit", zap.String("node", nodeID), zap.Error(err))
+		}
+	}
+
+	mon.IntVal("audit_missing_pieces").Observe(int64(len(missing)))
+	if len(missing) > 0 {
+		auditor.log.Warn("node lost pieces", zap.String("node", nodeID), zap.Int("missing", len(missing)))
+	}
+	return result, missing
+}
+
+// reverify challenges a contained node again with the challenge it didn't
+// answer, and returns whether the node can be audited anew. The answer counts
+// as the audit the node missed.
+func (auditor *Auditor) reverify(ctx context.Context, nodeID string, result *NodeAuditResult) bool {
+	if auditor.containment == nil {
+		return true
+	}
+	outcome, err := auditor.containment.ReverifyChallenge(ctx, nodeID, challenger{auditor.stater})
+	if err != nil {
+		auditor.log.Warn("could not reverify node", zap.String("node", nodeID), zap.Error(err))
+		return true
+	}
+
+	switch outcome {
+	case audit.Passed, audit.Failed:
+		result.Reverified = outcome.String()
+		if err := auditor.vetting.RecordAudit(ctx, nodeID, outcome == audit.Passed); err != nil {
+			auditor.log.Warn("could not record audit", zap.String("node", nodeID), zap.Error(err))
+		}
+	case audit.Pending:
+		result.Contained, result.Reverified = true, outcome.String()
+		if err := auditor.vetting.RecordMissedAudit(ctx, nodeID); err != nil {
+			auditor.log.Warn("could not record missed audit", zap.String("node", nodeID), zap.Error(err))
+		}
+		return false
+	}
+	return true
+}
+
+// contain keeps the challenge of the leaf of piece that nodeID didn't
+// answer, and returns whether the node is contained
+func (auditor *Auditor) contain(ctx context.Context, nodeID string, piece nodePiece, leaf int64) bool {
+	if auditor.containment == nil {
+		return false
+	}
+	err := auditor.containment.Contain(ctx, &pb.PendingAudit{
+		NodeId:      nodeID,
+		PieceId:     piece.id.String(),
+		StripeIndex: leaf,
+		MerkleRoot:  piece.root,
+		PieceSize:   piece.size,
+	})
+	if err != nil {
+		auditor.log.Warn("could not contain node", zap.String("node", nodeID), zap.Error(err))
+		return false
+	}
+	return true
+}
+
+// challenge challenges nodeID to prove it stores the data of the leaf of
+// piece, and returns whether it did. An error means the node didn't answer.
+func (auditor *Auditor) challenge(ctx context.Context, nodeID string, piece nodePiece, leaf int64) (passed bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	resp, err := auditor.stater.Challenge(ctx, nodeID, piece.id, leaf)
+	if client.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return audit.VerifyChallenge(piece.root, piece.size, leaf, resp) == audit.Passed, nil
+}
+
+// challenger implements audit.Challenger with a Stater
+type challenger struct {
+	stater Stater
+}
+
+// Challenge implements audit.Challenger
+func (c challenger) Challenge(ctx context.Context, nodeID, pieceID string, leaf int64) (*pb.ChallengeResponse, error) {
+	return c.stater.Challenge(ctx, nodeID, client.PieceID(pieceID), leaf)
+}
+
+// ServeHTTP implements the admin API of the node audits, mounted at
+// /verification/audits:
+//
+//	GET  /verification/audits    returns whether an audit is running and the last audit
+//	POST /verification/audits    starts an audit of {"nodes": [...], "repair": bool}
+func (auditor *Auditor) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(struct {
+			Running bool       `json:"running"`
+			Last    *NodeAudit `json:"last"`
+		}{auditor.Running(), auditor.Last()})
+	case http.MethodPost:
+		var body struct {
+			Nodes  []string `json:"nodes"`
+			Repair bool     `json:"repair"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(body.Nodes) == 0 {
+			http.Error(w, "no nodes to audit", http.StatusBadRequest)
+			return
+		}
+		if err := auditor.Start(body.Nodes, body.Repair); err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package verification
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/eestream"
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/vetting"
+	"storj.io/storj/storage/teststore"
+)
+
+type mockStater struct {
+	missing     map[string]bool
+	unreachable map[string]bool
+	// piece is the data of the challenged pieces, which corrupted nodes
+	// don't have anymore
+	piece     []byte
+	corrupted map[string]bool
+	// hashes are the hashes nodes report for their pieces
+	hashes map[string][]byte
+
+	mu sync.Mutex
+	// drops is how many challenges nodes drop before answering
+	drops map[string]int
+}
+
+func (stater *mockStater) StatPieces(ctx context.Context, nodeID string, ids []client.PieceID) ([]*pb.PieceStat, error) {
+	if stater.unreachable[nodeID] {
+		return nil, errors.New("dial failed")
+	}
+	var pieces []*pb.PieceStat
+	for _, id := range ids {
+		pieces = append(pieces, &pb.PieceStat{Id: id.String(), Exists: !stater.missing[nodeID], Hash: stater.hashes[nodeID]})
+	}
+	return pieces, nil
+}
+
+func (stater *mockStater) Challenge(ctx context.Context, nodeID string, pieceID client.PieceID, leaf int64) (*pb.ChallengeResponse, error) {
+	stater.mu.Lock()
+	defer stater.mu.Unlock()
+	if stater.drops[nodeID] > 0 {
+		stater.drops[nodeID]--
+		return nil, errors.New("connection dropped")
+	}
+	data := stater.piece
+	if stater.corrupted[nodeID] {
+		data = []byte("corrupted")
+	}
+	return &pb.ChallengeResponse{Leaf: data}, nil
+}
+
+type mockQueue struct {
+	mu       sync.Mutex
+	segments []*pb.InjuredSegment
+}
+
+func (queue *mockQueue) Remove(qi *pb.InjuredSegment) error { return nil }
+func (queue *mockQueue) GetNext() pb.InjuredSegment         { return pb.InjuredSegment{} }
+func (queue *mockQueue) GetSize() int                       { return len(queue.segments) }
+
+func (queue *mockQueue) Add(qi *pb.InjuredSegment) error {
+	return queue.AddAll([]*pb.InjuredSegment{qi})
+}
+
+func (queue *mockQueue) AddAll(qis []*pb.InjuredSegment) error {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	queue.segments = append(queue.segments, qis...)
+	return nil
+}
+
+func TestAuditor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	db := teststore.New()
+	putPointer(t, db, "a/one", remotePointer("n1", "n2", "n3"))
+	putPointer(t, db, "a/inline", &pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: []byte("data")})
+	putPointer(t, db, "b/two", remotePointer("n2", "n3", "n4"))
+
+	loop := metainfo.NewLoop(metainfo.Config{}, db)
+	go func() { _ = loop.Run(ctx) }()
+
+	stater := &mockStater{
+		missing:     map[string]bool{"n2": true, "n3": true},
+		unreachable: map[string]bool{"n4": true},
+	}
+	queue := &mockQueue{}
+	auditor := NewAuditor(zap.NewNop(), loop, stater, queue, nil, nil, 2)
+
+	audit, err := auditor.Audit(ctx, []string{"n1", "n2", "n4", "n5"}, false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, &NodeAuditResult{Pieces: 1, Checked: 1}, audit.Results["n1"])
+	assert.Equal(t, &NodeAuditResult{Pieces: 2, Checked: 2, Missing: []string{"a/one", "b/two"}}, audit.Results["n2"])
+	assert.Equal(t, &NodeAuditResult{Pieces: 1, Error: "dial failed"}, audit.Results["n4"])
+	assert.Equal(t, &NodeAuditResult{}, audit.Results["n5"])
+	assert.Equal(t, 0, audit.Queued)
+	assert.Len(t, queue.segments, 0)
+
+	audit, err = auditor.Audit(ctx, []string{"n2", "n3"}, true)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 2, audit.Queued)
+	assert.Equal(t, []*pb.InjuredSegment{
+		{Path: "a/one", LostPieces: []int32{1, 2}},
+		{Path: "b/two", LostPieces: []int32{0, 1}},
+	}, queue.segments)
+}
+
+func TestAuditorChallenge(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// pieces of 2 stripes of 1KiB shares, a single leaf
+	piece := make([]byte, 2048)
+	root := eestream.MerkleRoot([][]byte{eestream.MerkleLeaf(piece)})
+	pointer := remotePointer("n1", "n2", "n3")
+	pointer.Size = 4000
+	pointer.Remote.Redundancy.ErasureShareSize = 1024
+	for _, remote := range pointer.Remote.RemotePieces {
+		remote.MerkleRoot = root
+	}
+
+	db := teststore.New()
+	putPointer(t, db, "a/one", pointer)
+	// pieces uploaded before roots were computed aren't challenged
+	putPointer(t, db, "a/old", remotePointer("n1", "n2", "n3"))
+
+	loop := metainfo.NewLoop(metainfo.Config{}, db)
+	go func() { _ = loop.Run(ctx) }()
+
+	stater := &mockStater{
+		piece:     piece,
+		corrupted: map[string]bool{"n2": true},
+		hashes:    map[string][]byte{"n1": root, "n3": []byte("another root")},
+	}
+	auditor := NewAuditor(zap.NewNop(), loop, stater, &mockQueue{}, nil, nil, 1)
+
+	audit, err := auditor.Audit(ctx, []string{"n1", "n2", "n3"}, false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, &NodeAuditResult{Pieces: 2, Checked: 2, Challenged: 1}, audit.Results["n1"])
+	assert.Equal(t, &NodeAuditResult{Pieces: 2, Checked: 2, Challenged: 1, Missing: []string{"a/one"}}, audit.Results["n2"])
+	// pieces whose hash isn't the root of the uplink are corrupted
+	assert.Equal(t, &NodeAuditResult{Pieces: 2, Checked: 2, Missing: []string{"a/one"}}, audit.Results["n3"])
+}
+
+func TestAuditorContainment(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	piece := make([]byte, 2048)
+	root := eestream.MerkleRoot([][]byte{eestream.MerkleLeaf(piece)})
+	pointer := remotePointer("n1", "n2", "n3")
+	pointer.Size = 4000
+	pointer.Remote.Redundancy.ErasureShareSize = 1024
+	for _, remote := range pointer.Remote.RemotePieces {
+		remote.MerkleRoot = root
+	}
+
+	db := teststore.New()
+	putPointer(t, db, "a/one", pointer)
+
+	loop := metainfo.NewLoop(metainfo.Config{}, db)
+	go func() { _ = loop.Run(ctx) }()
+
+	stater := &mockStater{
+		piece: piece,
+		drops: map[string]int{"n1": 1, "n2": 2},
+	}
+	tracker := vetting.NewTracker(zap.NewNop(), teststore.New(), vetting.Thresholds{}, 0, nil)
+	containment := audit.NewContainment(zap.NewNop(), teststore.New(), 1)
+	auditor := NewAuditor(zap.NewNop(), loop, stater, &mockQueue{}, tracker, containment, 2)
+
+	// nodes dropping their challenge are contained
+	result, err := auditor.Audit(ctx, []string{"n1", "n2"}, false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, nodeID := range []string{"n1", "n2"} {
+		assert.Equal(t, &NodeAuditResult{Pieces: 1, Checked: 1, Error: "connection dropped", Contained: true}, result.Results[nodeID])
+		pending, err := containment.Get(ctx, nodeID)
+		assert.NoError(t, err)
+		assert.NotNil(t, pending)
+	}
+
+	// and challenged again before they are audited anew. n1 answers, but n2
+	// drops its challenge again and fails the audit
+	result, err = auditor.Audit(ctx, []string{"n1", "n2"}, false)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, &NodeAuditResult{Pieces: 1, Checked: 1, Challenged: 1, Reverified: "passed"}, result.Results["n1"])
+	assert.Equal(t, &NodeAuditResult{Pieces: 1, Checked: 1, Challenged: 1, Reverified: "failed"}, result.Results["n2"])
+
+	for nodeID, passed := range map[string]int64{"n1": 2, "n2": 1} {
+		pending, err := containment.Get(ctx, nodeID)
+		assert.NoError(t, err)
+		assert.Nil(t, pending)
+
+		progress, err := tracker.Get(ctx, nodeID)
+		if !assert.NoError(t, err) {
+			continue
+		}
+		assert.Equal(t, int64(2), progress.AuditCount, nodeID)
+		assert.Equal(t, passed, progress.AuditSuccessCount, nodeID)
+		assert.Equal(t, int64(0), progress.MissedAudits, nodeID)
+	}
+}
+
+func TestAuditorServeHTTP(t *testing.T) {
+	auditor := NewAuditor(zap.NewNop(), nil, &mockStater{}, &mockQueue{}, nil, nil, 1)
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		auditor.ServeHTTP(w, httptest.NewRequest(method, "/verification/audits", strings.NewReader(body)))
+		return w
+	}
+
+	w := serve(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"running":false,"last":null}`, w.Body.String())
+
+	w = serve(http.MethodPost, `{"nodes":[]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// the auditor isn't running, so it can't start audits
+	w = serve(http.MethodPost, `{"nodes":["n1"],"repair":true}`)
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
@@ -0,0 +1,146 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package verification
+
+import (
+	"context"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+)
+
+// Checker checks whether storage nodes have pieces
+type Checker interface {
+	// HasPiece returns whether nodeID has the piece with pieceID. An error
+	// means the node couldn't be asked.
+	HasPiece(ctx context.Context, nodeID string, pieceID client.PieceID) (bool, error)
+}
+
+// Stater requests the metadata of many pieces from storage nodes at once,
+// and challenges nodes to prove they still store the data of pieces
+type Stater interface {
+	// StatPieces returns the metadata of the pieces of ids on nodeID, in
+	// the order of ids
+	StatPieces(ctx context.Context, nodeID string, ids []client.PieceID) ([]*pb.PieceStat, error)
+	// Challenge returns the answer of nodeID to the challenge of the leaf
+	// of the piece with pieceID
+	Challenge(ctx context.Context, nodeID string, pieceID client.PieceID, leaf int64) (*pb.ChallengeResponse, error)
+}
+
+// Overlay looks up the address of a storage node
+type Overlay interface {
+	Get(ctx context.Context, nodeID string) (*pb.Node, error)
+}
+
+type nodeChecker struct {
+	identity  *provider.FullIdentity
+	transport transport.Client
+	overlay   Overlay
+}
+
+// NewNodeChecker creates a Checker that dials storage nodes found in the
+// overlay and requests the metadata of pieces, without downloading them
+func NewNodeChecker(identity *provider.FullIdentity, t transport.Client, overlay Overlay) Checker {
+	return &nodeChecker{identity: identity, transport: t, overlay: overlay}
+}
+
+// NewNodeStater creates a Stater that dials storage nodes found in the
+// overlay
+func NewNodeStater(identity *provider.FullIdentity, t transport.Client, overlay Overlay) Stater {
+	return &nodeChecker{identity: identity, transport: t, overlay: overlay}
+}
+
+// HasPiece implements Checker
+func (checker *nodeChecker) HasPiece(ctx context.Context, nodeID string, pieceID client.PieceID) (has bool, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ps, err := checker.dial(ctx, nodeID)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = ps.Close() }()
+
+	pieces, err := ps.StatPieces(ctx, []client.PieceID{pieceID})
+	if err == nil {
+		if len(pieces) != 1 {
+			return false, Error.New("node %s returned %d pieces", nodeID, len(pieces))
+		}
+		return pieces[0].GetExists(), nil
+	}
+	if !client.IsUnimplemented(err) {
+		return false, Error.Wrap(err)
+	}
+
+	// nodes that don't have StatPieces yet are asked for the piece's meta
+	_, err = ps.Meta(ctx, pieceID)
+	if client.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, Error.Wrap(err)
+	}
+	return true, nil
+}
+
+// StatPieces implements Stater
+func (checker *nodeChecker) StatPieces(ctx context.Context, nodeID string, ids []client.PieceID) (pieces []*pb.PieceStat, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ps, err := checker.dial(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = ps.Close() }()
+
+	pieces, err = ps.StatPieces(ctx, ids)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if len(pieces) != len(ids) {
+		return nil, Error.New("node %s returned %d pieces of %d", nodeID, len(pieces), len(ids))
+	}
+	return pieces, nil
+}
+
+// Challenge implements Stater
+func (checker *nodeChecker) Challenge(ctx context.Context, nodeID string, pieceID client.PieceID, leaf int64) (resp *pb.ChallengeResponse, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	ps, err := checker.dial(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = ps.Close() }()
+
+	resp, err = ps.Challenge(ctx, pieceID, leaf)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return resp, nil
+}
+
+// dial connects to the storage node with nodeID
+func (checker *nodeChecker) dial(ctx context.Context, nodeID string) (client.PSClient, error) {
+	node, err := checker.overlay.Get(ctx, nodeID)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if node == nil {
+		return nil, Error.New("node %s not in the overlay", nodeID)
+	}
+
+	conn, err := checker.transport.DialNode(ctx, node)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	ps, err := client.NewPSClient(conn, 0, checker.identity.Key)
+	if err != nil {
+		_ = conn.Close()
+		return nil, Error.Wrap(err)
+	}
+	return ps, nil
+}
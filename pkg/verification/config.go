@@ -0,0 +1,76 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package verification
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/audit"
+	"storj.io/storj/pkg/datarepair"
+	"storj.io/storj/pkg/metainfo"
+	"storj.io/storj/pkg/overlay"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/transport"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/pkg/vetting"
+	"storj.io/storj/storage/boltdb"
+)
+
+// ContainmentBucket is the bolt bucket the pending audits of contained nodes
+// are stored in
+const ContainmentBucket = "containment"
+
+// Config contains everything necessary to start the node audits of a
+// satellite, which are triggered through its admin API
+type Config struct {
+	Concurrency      int    `help:"how many storage nodes are audited in parallel" default:"5"`
+	ContainmentURL   string `help:"the database the challenges that nodes didn't answer are stored in" default:"bolt://$CONFDIR/containment.db"`
+	MaxReverifyCount int    `help:"how many times a contained node may miss its challenge again before it fails the audit" default:"1"`
+}
+
+// Run implements the provider.Responsibility interface. Run assumes the
+// metainfo loop and Overlay responsibilities have been started before this
+// one, and counts the audits toward the vetting of nodes if the vetting
+// responsibility has been started too.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	loop := metainfo.LoadFromContext(ctx)
+	if loop == nil {
+		return Error.New("programmer error: metainfo loop responsibility unstarted")
+	}
+
+	cache := overlay.LoadFromContext(ctx)
+	if cache == nil {
+		return Error.New("programmer error: overlay responsibility unstarted")
+	}
+
+	dburl, err := utils.ParseURL(c.ContainmentURL)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if dburl.Scheme != "bolt" {
+		return Error.New("unsupported db scheme: %s", dburl.Scheme)
+	}
+	db, err := boltdb.New(dburl.Path, ContainmentBucket)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { _ = db.Close() }()
+	containment := audit.NewContainment(zap.L().Named("containment"), db, c.MaxReverifyCount)
+
+	stater := NewNodeStater(server.Identity(), transport.NewClient(server.Identity()), cache)
+	// TODO: the repair queue isn't backed by a database yet
+	auditor := NewAuditor(zap.L().Named("verification"), loop, stater, datarepair.Queue{}, vetting.LoadFromContext(ctx), containment, c.Concurrency)
+	process.HandleDebug("/verification/audits", auditor)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() { _ = auditor.Run(ctx) }()
+
+	return server.Run(ctx)
+}
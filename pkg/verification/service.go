@@ -0,0 +1,222 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package verification
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/storage"
+)
+
+// Health is the health of a segment
+type Health int
+
+const (
+	// Healthy segments have more retrievable pieces than the repair
+	// threshold
+	Healthy Health = iota
+	// AtRisk segments have no more retrievable pieces than the repair
+	// threshold, but can still be reconstructed
+	AtRisk
+	// Lost segments have fewer retrievable pieces than are needed to
+	// reconstruct them
+	Lost
+)
+
+// String returns the name of the health
+func (health Health) String() string {
+	switch health {
+	case Healthy:
+		return "healthy"
+	case AtRisk:
+		return "at risk"
+	case Lost:
+		return "lost"
+	default:
+		return "invalid"
+	}
+}
+
+// Segment is the verification result of a remote segment
+type Segment struct {
+	Path            string
+	Health          Health
+	Required        int
+	RepairThreshold int
+	Total           int
+	// Retrievable is the number of pieces that nodes confirmed they have
+	Retrievable int
+	// Missing are the nodes that don't have their piece
+	Missing []string
+	// Unreachable are the nodes that couldn't be asked. Their pieces are
+	// not counted as retrievable.
+	Unreachable []string
+}
+
+// Report is the result of a verification
+type Report struct {
+	// Checked is the number of remote segments that were verified
+	Checked int
+	// Inline is the number of inline segments, which are always
+	// retrievable
+	Inline int
+	// Unhealthy are the segments that are at risk or lost
+	Unhealthy []Segment
+	// Last is the path of the last segment that was verified, to continue
+	// verification from
+	Last string
+}
+
+// Range selects the segments to verify
+type Range struct {
+	// First is the path of the first segment to verify
+	First string
+	// End is the path the verification stops before, no limit if empty
+	End string
+	// Limit is the maximum number of segments to verify, no limit if 0
+	Limit int
+}
+
+// Service verifies that the pieces of segments are retrievable from the
+// storage nodes. Repair only counts pieces on nodes that are online, so it
+// misses pieces that nodes lost.
+type Service struct {
+	log         *zap.Logger
+	pointers    storage.KeyValueStore
+	checker     Checker
+	concurrency int
+}
+
+// NewService creates a Service that verifies the segments in pointers,
+// asking up to concurrency nodes at a time
+func NewService(log *zap.Logger, pointers storage.KeyValueStore, checker Checker, concurrency int) *Service {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &Service{log: log, pointers: pointers, checker: checker, concurrency: concurrency}
+}
+
+// Verify verifies the segments in r
+func (service *Service) Verify(ctx context.Context, r Range) (report *Report, err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	type remote struct {
+		path    string
+		pointer *pb.Pointer
+	}
+	var segments []remote
+
+	report = &Report{}
+	err = service.pointers.Iterate(storage.IterateOptions{First: storage.Key(r.First), Recurse: true},
+		func(it storage.Iterator) error {
+			var item storage.ListItem
+			for it.Next(&item) {
+				if r.End != "" && bytes.Compare(item.Key, []byte(r.End)) >= 0 {
+					return nil
+				}
+				if r.Limit > 0 && report.Checked+report.Inline >= r.Limit {
+					return nil
+				}
+
+				pointer := &pb.Pointer{}
+				if err := proto.Unmarshal(item.Value, pointer); err != nil {
+					return Error.New("invalid pointer %q: %v", item.Key, err)
+				}
+				report.Last = item.Key.String()
+
+				if pointer.GetRemote() == nil {
+					report.Inline++
+					continue
+				}
+				report.Checked++
+				segments = append(segments, remote{path: item.Key.String(), pointer: pointer})
+			}
+			return nil
+		})
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+
+	// the nodes are asked after iterating, so that the database isn't held
+	// open while waiting for them
+	for _, segment := range segments {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result := service.verifySegment(ctx, segment.path, segment.pointer)
+		if result.Health != Healthy {
+			service.log.Warn("unhealthy segment",
+				zap.String("path", result.Path),
+				zap.Stringer("health", result.Health),
+				zap.Int("retrievable", result.Retrievable),
+				zap.Int("required", result.Required))
+			report.Unhealthy = append(report.Unhealthy, result)
+		}
+	}
+	return report, nil
+}
+
+// verifySegment asks the nodes of all pieces of pointer whether they have
+// their piece
+func (service *Service) verifySegment(ctx context.Context, path string, pointer *pb.Pointer) Segment {
+	remote := pointer.GetRemote()
+	redundancy := remote.GetRedundancy()
+	result := Segment{
+		Path:            path,
+		Required:        int(redundancy.GetMinReq()),
+		RepairThreshold: int(redundancy.GetRepairThreshold()),
+		Total:           len(remote.GetRemotePieces()),
+	}
+
+	pieceID := client.PieceID(remote.GetPieceId())
+	limiter := make(chan struct{}, service.concurrency)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, piece := range remote.GetRemotePieces() {
+		nodeID := piece.GetNodeId()
+		limiter <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-limiter }()
+
+			var has bool
+			derived, err := pieceID.Derive([]byte(nodeID))
+			if err == nil {
+				has, err = service.checker.HasPiece(ctx, nodeID, derived)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err != nil:
+				service.log.Debug("node unreachable", zap.String("node", nodeID), zap.Error(err))
+				result.Unreachable = append(result.Unreachable, nodeID)
+			case !has:
+				result.Missing = append(result.Missing, nodeID)
+			default:
+				result.Retrievable++
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Strings(result.Missing)
+	sort.Strings(result.Unreachable)
+	switch {
+	case result.Retrievable < result.Required:
+		result.Health = Lost
+	case result.Retrievable <= result.RepairThreshold:
+		result.Health = AtRisk
+	}
+	return result
+}
@@ -0,0 +1,101 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package verification
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/pkg/piecestore/rpc/client"
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+)
+
+type mockChecker struct {
+	missing     map[string]bool
+	unreachable map[string]bool
+}
+
+func (checker *mockChecker) HasPiece(ctx context.Context, nodeID string, pieceID client.PieceID) (bool, error) {
+	if checker.unreachable[nodeID] {
+		return false, errors.New("dial failed")
+	}
+	return !checker.missing[nodeID], nil
+}
+
+func putPointer(t *testing.T, db storage.KeyValueStore, path string, pointer *pb.Pointer) {
+	value, err := proto.Marshal(pointer)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	assert.NoError(t, db.Put(storage.Key(path), value))
+}
+
+func remotePointer(nodeIDs ...string) *pb.Pointer {
+	var pieces []*pb.RemotePiece
+	for i, nodeID := range nodeIDs {
+		pieces = append(pieces, &pb.RemotePiece{PieceNum: int32(i), NodeId: nodeID})
+	}
+	return &pb.Pointer{
+		Type: pb.Pointer_REMOTE,
+		Remote: &pb.RemoteSegment{
+			Redundancy:   &pb.RedundancyScheme{MinReq: 2, RepairThreshold: 3, Total: 5},
+			PieceId:      "piece-id-of-the-segment",
+			RemotePieces: pieces,
+		},
+	}
+}
+
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	db := teststore.New()
+	putPointer(t, db, "a/healthy", remotePointer("n1", "n2", "n3", "n4", "n5"))
+	putPointer(t, db, "a/inline", &pb.Pointer{Type: pb.Pointer_INLINE, InlineSegment: []byte("data")})
+	putPointer(t, db, "b/at-risk", remotePointer("n1", "n2", "n6", "n7", "n8"))
+	putPointer(t, db, "c/lost", remotePointer("n1", "n6", "n7", "n8"))
+
+	checker := &mockChecker{
+		missing:     map[string]bool{"n6": true, "n7": true},
+		unreachable: map[string]bool{"n8": true},
+	}
+	service := NewService(zap.NewNop(), db, checker, 2)
+
+	report, err := service.Verify(ctx, Range{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 3, report.Checked)
+	assert.Equal(t, 1, report.Inline)
+	assert.Equal(t, "c/lost", report.Last)
+	assert.Equal(t, []Segment{
+		{
+			Path: "b/at-risk", Health: AtRisk, Required: 2, RepairThreshold: 3, Total: 5, Retrievable: 2,
+			Missing: []string{"n6", "n7"}, Unreachable: []string{"n8"},
+		},
+		{
+			Path: "c/lost", Health: Lost, Required: 2, RepairThreshold: 3, Total: 4, Retrievable: 1,
+			Missing: []string{"n6", "n7"}, Unreachable: []string{"n8"},
+		},
+	}, report.Unhealthy)
+
+	report, err = service.Verify(ctx, Range{First: "a/inline", End: "c", Limit: 5})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, 1, report.Checked)
+	assert.Equal(t, 1, report.Inline)
+	assert.Equal(t, "b/at-risk", report.Last)
+
+	report, err = service.Verify(ctx, Range{Limit: 1})
+	if assert.NoError(t, err) {
+		assert.Equal(t, "a/healthy", report.Last)
+		assert.Len(t, report.Unhealthy, 0)
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package vetting
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/analytics"
+	"storj.io/storj/pkg/process"
+	"storj.io/storj/pkg/provider"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage/boltdb"
+)
+
+// CtxKey Used as vetting tracker key
+type CtxKey int
+
+const (
+	ctxKeyTracker CtxKey = iota
+)
+
+// Bucket is the bolt bucket the vetting progress of nodes is stored in
+const Bucket = "vetting"
+
+// Config contains everything necessary to track the vetting of nodes
+type Config struct {
+	DatabaseURL string `help:"the database the vetting progress of nodes is stored in" default:"bolt://$CONFDIR/vetting.db"`
+	AuditCount  int64  `help:"how many audits a node has to pass to be vetted" default:"100"`
+	UptimeCount int64  `help:"how many uptime checks a node has to pass to be vetted" default:"50"`
+
+	SuccessRatio    float64 `help:"the share of its audits a node has to pass not to be failing" default:"0.6"`
+	MinAudits       int64   `help:"how many audits a node is judged on before it can be failing" default:"10"`
+	MaxMissedAudits int64   `help:"how many audits in a row a node may miss before it is failing" default:"3"`
+
+	MaintenanceBudget time.Duration `help:"the downtime of the maintenance windows announced by each node per calendar month during which failed uptime checks aren't held against it" default:"8h"`
+}
+
+// Run implements the provider.Responsibility interface. The progress is
+// served on the debug endpoints at /vetting/. Responsibilities auditing or
+// checking nodes have to be started after this one, and the analytics
+// responsibility before it for vetted nodes to be emitted.
+func (c Config) Run(ctx context.Context, server *provider.Provider) (err error) {
+	defer mon.Task()(&ctx)(&err)
+
+	dburl, err := utils.ParseURL(c.DatabaseURL)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if dburl.Scheme != "bolt" {
+		return Error.New("unsupported db scheme: %s", dburl.Scheme)
+	}
+	db, err := boltdb.New(dburl.Path, Bucket)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	defer func() { _ = db.Close() }()
+
+	tracker := NewTracker(zap.L().Named("vetting"), db, Thresholds{
+		AuditCount:      c.AuditCount,
+		UptimeCount:     c.UptimeCount,
+		SuccessRatio:    c.SuccessRatio,
+		MinAudits:       c.MinAudits,
+		MaxMissedAudits: c.MaxMissedAudits,
+	}, c.MaintenanceBudget, analytics.LoadFromContext(ctx))
+	process.HandleDebug("/vetting/", tracker)
+
+	return server.Run(context.WithValue(ctx, ctxKeyTracker, tracker))
+}
+
+// LoadFromContext loads the Tracker from the Provider context stack, or nil
+// if vetting isn't tracked
+func LoadFromContext(ctx context.Context) *Tracker {
+	if v, ok := ctx.Value(ctxKeyTracker).(*Tracker); ok {
+		return v
+	}
+	return nil
+}
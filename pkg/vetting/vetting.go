@@ -0,0 +1,350 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package vetting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/analytics"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage"
+)
+
+var (
+	mon = monkit.Package()
+
+	// Error is the default vetting errs class
+	Error = errs.Class("vetting error")
+)
+
+// Thresholds are the successful audits and uptime checks a node needs to
+// pass to be vetted, and the audits it has to keep passing not to be failing:
+// once it was judged on MinAudits, at least SuccessRatio of them, and it may
+// not miss more than MaxMissedAudits in a row
+type Thresholds struct {
+	AuditCount  int64
+	UptimeCount int64
+
+	SuccessRatio    float64
+	MinAudits       int64
+	MaxMissedAudits int64
+}
+
+// Progress is how far a node is toward being vetted
+type Progress struct {
+	NodeID string `json:"node_id"`
+	// AuditCount and UptimeCount count the audits and uptime checks, and
+	// AuditSuccessCount and UptimeSuccessCount those the node passed
+	AuditCount         int64 `json:"audit_count"`
+	AuditSuccessCount  int64 `json:"audit_success_count"`
+	UptimeCount        int64 `json:"uptime_count"`
+	UptimeSuccessCount int64 `json:"uptime_success_count"`
+	// MissedAudits counts the audits in a row the node didn't answer, which
+	// aren't judged
+	MissedAudits int64 `json:"missed_audits"`
+	// VettedAt is when the node reached the thresholds, nil if it didn't
+	VettedAt *time.Time `json:"vetted_at,omitempty"`
+
+	// Maintenance is the latest maintenance window the node announced and
+	// that fit in its budget. MaintenanceUsed is the downtime of the windows
+	// of MaintenanceMonth charged to the budget, and ExcusedCount counts the
+	// uptime checks failed during windows, which aren't held against the node.
+	Maintenance      *Window       `json:"maintenance,omitempty"`
+	MaintenanceMonth string        `json:"maintenance_month,omitempty"`
+	MaintenanceUsed  time.Duration `json:"maintenance_used"`
+	ExcusedCount     int64         `json:"excused_count"`
+}
+
+// Window is a maintenance window announced by a node
+type Window struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// contains returns whether t is within the window
+func (window *Window) contains(t time.Time) bool {
+	return window != nil && !t.Before(window.Start) && t.Before(window.End)
+}
+
+// Vetted returns whether the node reached the thresholds
+func (progress *Progress) Vetted() bool {
+	return progress.VettedAt != nil
+}
+
+// Tracker counts the audits and uptime checks of nodes toward the vetting
+// thresholds. A nil *Tracker tracks nothing, so it can be left unset where
+// vetting isn't tracked.
+type Tracker struct {
+	log        *zap.Logger
+	db         storage.KeyValueStore
+	thresholds Thresholds
+	events     *analytics.Events
+	// budget is the downtime of the maintenance windows of a node per
+	// calendar month that isn't held against it
+	budget time.Duration
+
+	// mu serializes the updates of the progress in db
+	mu sync.Mutex
+}
+
+// NewTracker creates a Tracker storing the progress of nodes in db. The
+// nodes reaching thresholds are emitted to events, which may be nil. The
+// uptime checks failed during the maintenance windows of nodes are excused
+// up to budget of downtime per month.
+func NewTracker(log *zap.Logger, db storage.KeyValueStore, thresholds Thresholds, budget time.Duration, events *analytics.Events) *Tracker {
+	return &Tracker{log: log, db: db, thresholds: thresholds, budget: budget, events: events}
+}
+
+// RecordAudit counts an audit of nodeID, passed if success is true
+func (tracker *Tracker) RecordAudit(ctx context.Context, nodeID string, success bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return tracker.update(nodeID, func(progress *Progress) {
+		progress.AuditCount++
+		progress.MissedAudits = 0
+		if success {
+			progress.AuditSuccessCount++
+		}
+	})
+}
+
+// RecordMissedAudit counts an audit nodeID didn't answer, usually because
+// it was offline or timed out
+func (tracker *Tracker) RecordMissedAudit(ctx context.Context, nodeID string) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	return tracker.update(nodeID, func(progress *Progress) {
+		progress.MissedAudits++
+	})
+}
+
+// RecordUptime counts an uptime check of nodeID, passed if up is true.
+// Failed checks during a maintenance window of the node are excused.
+func (tracker *Tracker) RecordUptime(ctx context.Context, nodeID string, up bool) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	now := time.Now()
+	return tracker.update(nodeID, func(progress *Progress) {
+		if !up && progress.Maintenance.contains(now) {
+			mon.Counter("uptime_excused").Inc(1)
+			progress.ExcusedCount++
+			return
+		}
+		progress.UptimeCount++
+		if up {
+			progress.UptimeSuccessCount++
+		}
+	})
+}
+
+// RecordMaintenance records the maintenance window nodeID announced at
+// check-in, if any. A new window is charged to the budget of the month it
+// starts in, and ignored if it doesn't fit.
+func (tracker *Tracker) RecordMaintenance(ctx context.Context, nodeID string, announced *pb.MaintenanceWindow) (err error) {
+	defer mon.Task()(&ctx)(&err)
+	if announced == nil {
+		return nil
+	}
+	window := &Window{
+		Start: time.Unix(announced.GetStartUnixSec(), 0).UTC(),
+		End:   time.Unix(announced.GetEndUnixSec(), 0).UTC(),
+	}
+	if !window.End.After(window.Start) {
+		return Error.New("invalid maintenance window of node %s", nodeID)
+	}
+	return tracker.update(nodeID, func(progress *Progress) {
+		if progress.Maintenance != nil && progress.Maintenance.Start.Equal(window.Start) {
+			return
+		}
+		month := window.Start.Format("2006-01")
+		if month != progress.MaintenanceMonth {
+			progress.MaintenanceMonth = month
+			progress.MaintenanceUsed = 0
+		}
+		downtime := window.End.Sub(window.Start)
+		if progress.MaintenanceUsed+downtime > tracker.budget {
+			tracker.log.Info("maintenance window exceeds budget", zap.String("node", nodeID),
+				zap.Time("start", window.Start), zap.Duration("downtime", downtime))
+			return
+		}
+		progress.Maintenance = window
+		progress.MaintenanceUsed += downtime
+	})
+}
+
+// update applies fn to the progress of nodeID and vets the node when it
+// reaches the thresholds
+func (tracker *Tracker) update(nodeID string, fn func(progress *Progress)) error {
+	if tracker == nil {
+		return nil
+	}
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	progress, err := tracker.get(nodeID)
+	if err != nil {
+		return err
+	}
+	fn(progress)
+
+	vetted := !progress.Vetted() &&
+		progress.AuditSuccessCount >= tracker.thresholds.AuditCount &&
+		progress.UptimeSuccessCount >= tracker.thresholds.UptimeCount
+	if vetted {
+		now := time.Now().UTC()
+		progress.VettedAt = &now
+	}
+
+	value, err := json.Marshal(progress)
+	if err != nil {
+		return Error.Wrap(err)
+	}
+	if err := tracker.db.Put(storage.Key(nodeID), value); err != nil {
+		return Error.Wrap(err)
+	}
+
+	if vetted {
+		mon.Counter("nodes_vetted").Inc(1)
+		tracker.log.Info("node vetted", zap.String("node", nodeID))
+		tracker.events.Emit(analytics.Event{
+			Name:       analytics.NodeVetted,
+			Time:       *progress.VettedAt,
+			Properties: map[string]string{"node_id": nodeID},
+		})
+	}
+	return nil
+}
+
+// Get returns the progress of nodeID, which is zero for unknown nodes
+func (tracker *Tracker) Get(ctx context.Context, nodeID string) (progress *Progress, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if tracker == nil {
+		return &Progress{NodeID: nodeID}, nil
+	}
+	return tracker.get(nodeID)
+}
+
+func (tracker *Tracker) get(nodeID string) (*Progress, error) {
+	value, err := tracker.db.Get(storage.Key(nodeID))
+	if storage.ErrKeyNotFound.Has(err) {
+		return &Progress{NodeID: nodeID}, nil
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	progress := &Progress{}
+	if err := json.Unmarshal(value, progress); err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return progress, nil
+}
+
+// List returns the progress of all tracked nodes, ordered by node id
+func (tracker *Tracker) List(ctx context.Context) (progresses []*Progress, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if tracker == nil {
+		return nil, nil
+	}
+	err = tracker.db.Iterate(storage.IterateOptions{Recurse: true}, func(it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(&item) {
+			progress := &Progress{}
+			if err := json.Unmarshal(item.Value, progress); err != nil {
+				return err
+			}
+			progresses = append(progresses, progress)
+		}
+		return nil
+	})
+	return progresses, Error.Wrap(err)
+}
+
+// Failing returns whether a node with progress passed fewer of its audits
+// than the thresholds require or missed too many of them in a row
+func (tracker *Tracker) Failing(progress *Progress) bool {
+	if tracker == nil {
+		return false
+	}
+	thresholds := tracker.thresholds
+	if thresholds.MaxMissedAudits > 0 && progress.MissedAudits > thresholds.MaxMissedAudits {
+		return true
+	}
+	if progress.AuditCount == 0 || progress.AuditCount < thresholds.MinAudits {
+		return false
+	}
+	return float64(progress.AuditSuccessCount)/float64(progress.AuditCount) < thresholds.SuccessRatio
+}
+
+// CheckIn returns the stats request the satellite checks in with nodeID:
+// the vetting progress and audit status of the node, or neither if vetting
+// isn't tracked
+func (tracker *Tracker) CheckIn(ctx context.Context, nodeID string) (_ *pb.StatsReq, err error) {
+	defer mon.Task()(&ctx)(&err)
+	if tracker == nil {
+		return &pb.StatsReq{}, nil
+	}
+	progress, err := tracker.Get(ctx, nodeID)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.StatsReq{
+		Vetting: &pb.VettingProgress{
+			AuditCount:      progress.AuditSuccessCount,
+			AuditThreshold:  tracker.thresholds.AuditCount,
+			UptimeCount:     progress.UptimeSuccessCount,
+			UptimeThreshold: tracker.thresholds.UptimeCount,
+			Vetted:          progress.Vetted(),
+		},
+		Audit: &pb.AuditStatus{
+			AuditCount:        progress.AuditCount,
+			AuditSuccessCount: progress.AuditSuccessCount,
+			MissedAudits:      progress.MissedAudits,
+			SuccessThreshold:  tracker.thresholds.SuccessRatio,
+			Failing:           tracker.Failing(progress),
+		},
+	}, nil
+}
+
+// ServeHTTP implements the admin API of the vetting progress, mounted at
+// /vetting/:
+//
+//	GET /vetting/        lists the progress of all tracked nodes
+//	GET /vetting/NODEID  returns the progress of the node NODEID
+func (tracker *Tracker) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx := req.Context()
+	nodeID := strings.Trim(strings.TrimPrefix(req.URL.Path, "/vetting"), "/")
+
+	var result interface{}
+	var err error
+	if nodeID == "" {
+		var progresses []*Progress
+		progresses, err = tracker.List(ctx)
+		if progresses == nil {
+			progresses = []*Progress{}
+		}
+		result = struct {
+			AuditThreshold  int64       `json:"audit_threshold"`
+			UptimeThreshold int64       `json:"uptime_threshold"`
+			Nodes           []*Progress `json:"nodes"`
+		}{tracker.thresholds.AuditCount, tracker.thresholds.UptimeCount, progresses}
+	} else {
+		result, err = tracker.Get(ctx, nodeID)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
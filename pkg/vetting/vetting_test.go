@@ -0,0 +1,177 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package vetting
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/analytics"
+	"storj.io/storj/pkg/pb"
+	"storj.io/storj/storage/teststore"
+)
+
+type sink struct {
+	events []*analytics.Event
+}
+
+func (sink *sink) Write(event *analytics.Event) error {
+	sink.events = append(sink.events, event)
+	return nil
+}
+
+func (sink *sink) Close() error { return nil }
+
+func TestTracker(t *testing.T) {
+	ctx := context.Background()
+	events := &sink{}
+	tracker := NewTracker(zap.NewNop(), teststore.New(), Thresholds{AuditCount: 2, UptimeCount: 1}, time.Hour, analytics.New(events, nil))
+
+	progress, err := tracker.Get(ctx, "node1")
+	assert.NoError(t, err)
+	assert.Equal(t, &Progress{NodeID: "node1"}, progress)
+
+	// failed audits and uptime checks don't count toward vetting
+	assert.NoError(t, tracker.RecordAudit(ctx, "node1", true))
+	assert.NoError(t, tracker.RecordAudit(ctx, "node1", false))
+	assert.NoError(t, tracker.RecordUptime(ctx, "node1", false))
+	assert.NoError(t, tracker.RecordUptime(ctx, "node1", true))
+	progress, err = tracker.Get(ctx, "node1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), progress.AuditCount)
+	assert.Equal(t, int64(1), progress.AuditSuccessCount)
+	assert.Equal(t, int64(2), progress.UptimeCount)
+	assert.Equal(t, int64(1), progress.UptimeSuccessCount)
+	assert.False(t, progress.Vetted())
+	assert.Empty(t, events.events)
+
+	checkIn, err := tracker.CheckIn(ctx, "node1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), checkIn.GetVetting().GetAuditCount())
+	assert.Equal(t, int64(2), checkIn.GetVetting().GetAuditThreshold())
+	assert.False(t, checkIn.GetVetting().GetVetted())
+	assert.Equal(t, int64(2), checkIn.GetAudit().GetAuditCount())
+	assert.Equal(t, int64(1), checkIn.GetAudit().GetAuditSuccessCount())
+
+	// the node is vetted, and emitted, once
+	assert.NoError(t, tracker.RecordAudit(ctx, "node1", true))
+	assert.NoError(t, tracker.RecordAudit(ctx, "node1", true))
+	progress, err = tracker.Get(ctx, "node1")
+	assert.NoError(t, err)
+	assert.True(t, progress.Vetted())
+	if assert.Len(t, events.events, 1) {
+		assert.Equal(t, analytics.NodeVetted, events.events[0].Name)
+		assert.Equal(t, "node1", events.events[0].Properties["node_id"])
+	}
+
+	assert.NoError(t, tracker.RecordUptime(ctx, "node2", true))
+	progresses, err := tracker.List(ctx)
+	assert.NoError(t, err)
+	if assert.Len(t, progresses, 2) {
+		assert.Equal(t, "node1", progresses[0].NodeID)
+		assert.Equal(t, "node2", progresses[1].NodeID)
+	}
+
+	w := httptest.NewRecorder()
+	tracker.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/vetting/node1", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	var served Progress
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&served))
+	assert.True(t, served.Vetted())
+}
+
+func TestMaintenance(t *testing.T) {
+	ctx := context.Background()
+	tracker := NewTracker(zap.NewNop(), teststore.New(), Thresholds{}, 90*time.Minute, nil)
+
+	now := time.Now()
+	window := &pb.MaintenanceWindow{
+		StartUnixSec: now.Add(-time.Minute).Unix(),
+		EndUnixSec:   now.Add(time.Hour).Unix(),
+	}
+	assert.NoError(t, tracker.RecordMaintenance(ctx, "node1", nil))
+	assert.NoError(t, tracker.RecordMaintenance(ctx, "node1", window))
+	// announcing the same window again doesn't charge the budget again
+	assert.NoError(t, tracker.RecordMaintenance(ctx, "node1", window))
+
+	// failed checks during the window are excused
+	assert.NoError(t, tracker.RecordUptime(ctx, "node1", false))
+	assert.NoError(t, tracker.RecordUptime(ctx, "node1", true))
+	progress, err := tracker.Get(ctx, "node1")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), progress.ExcusedCount)
+	assert.Equal(t, int64(1), progress.UptimeCount)
+	assert.Equal(t, int64(1), progress.UptimeSuccessCount)
+	assert.Equal(t, time.Hour+time.Minute, progress.MaintenanceUsed)
+
+	// windows beyond the budget aren't excused
+	assert.NoError(t, tracker.RecordMaintenance(ctx, "node2", &pb.MaintenanceWindow{
+		StartUnixSec: now.Add(-time.Minute).Unix(),
+		EndUnixSec:   now.Add(2 * time.Hour).Unix(),
+	}))
+	assert.NoError(t, tracker.RecordUptime(ctx, "node2", false))
+	progress, err = tracker.Get(ctx, "node2")
+	assert.NoError(t, err)
+	assert.Nil(t, progress.Maintenance)
+	assert.Equal(t, int64(0), progress.ExcusedCount)
+	assert.Equal(t, int64(1), progress.UptimeCount)
+}
+
+func TestNilTracker(t *testing.T) {
+	ctx := context.Background()
+	var tracker *Tracker
+
+	assert.NoError(t, tracker.RecordAudit(ctx, "node1", true))
+	assert.NoError(t, tracker.RecordUptime(ctx, "node1", true))
+	assert.NoError(t, tracker.RecordMissedAudit(ctx, "node1"))
+	checkIn, err := tracker.CheckIn(ctx, "node1")
+	assert.NoError(t, err)
+	assert.Nil(t, checkIn.GetVetting())
+	assert.Nil(t, checkIn.GetAudit())
+}
+
+func TestFailing(t *testing.T) {
+	ctx := context.Background()
+	tracker := NewTracker(zap.NewNop(), teststore.New(), Thresholds{
+		SuccessRatio:    0.6,
+		MinAudits:       3,
+		MaxMissedAudits: 2,
+	}, time.Hour, nil)
+
+	failing := func(nodeID string) bool {
+		checkIn, err := tracker.CheckIn(ctx, nodeID)
+		assert.NoError(t, err)
+		assert.Equal(t, 0.6, checkIn.GetAudit().GetSuccessThreshold())
+		return checkIn.GetAudit().GetFailing()
+	}
+
+	// nodes aren't judged on their first audits
+	assert.NoError(t, tracker.RecordAudit(ctx, "node1", false))
+	assert.NoError(t, tracker.RecordAudit(ctx, "node1", true))
+	assert.False(t, failing("node1"))
+	assert.NoError(t, tracker.RecordAudit(ctx, "node1", false))
+	assert.True(t, failing("node1"))
+	assert.NoError(t, tracker.RecordAudit(ctx, "node1", true))
+	assert.NoError(t, tracker.RecordAudit(ctx, "node1", true))
+	assert.False(t, failing("node1"))
+
+	// missing audits in a row fails the node until it answers one
+	for i := 0; i < 3; i++ {
+		assert.False(t, failing("node2"))
+		assert.NoError(t, tracker.RecordMissedAudit(ctx, "node2"))
+	}
+	assert.True(t, failing("node2"))
+	progress, err := tracker.Get(ctx, "node2")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), progress.MissedAudits)
+	assert.NoError(t, tracker.RecordAudit(ctx, "node2", true))
+	assert.False(t, failing("node2"))
+}
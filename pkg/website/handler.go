@@ -0,0 +1,190 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package website
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/zeebo/errs"
+	"go.uber.org/zap"
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+var (
+	mon = monkit.Package()
+	// Error is the default error class for websites
+	Error = errs.Class("website error")
+)
+
+// Handler serves the buckets with a website configuration as static
+// websites. Buckets are addressed by path, like /BUCKET/KEY, or if a domain
+// is set, by host, like BUCKET.DOMAIN/KEY.
+type Handler struct {
+	log    *zap.Logger
+	bs     buckets.Store
+	domain string
+}
+
+// NewHandler creates a Handler serving the buckets of bs. domain is the
+// domain the buckets are subdomains of, or empty to address them by path.
+func NewHandler(log *zap.Logger, bs buckets.Store, domain string) *Handler {
+	return &Handler{log: log, bs: bs, domain: strings.TrimPrefix(domain, ".")}
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	defer mon.Task()(&ctx)(nil)
+
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	bucket, key, base := h.route(r)
+	if bucket == "" {
+		http.NotFound(w, r)
+		return
+	}
+	if !strings.HasPrefix(r.URL.Path, base) {
+		// the bucket root is redirected, so relative links resolve within
+		// the bucket
+		http.Redirect(w, r, base, http.StatusMovedPermanently)
+		return
+	}
+
+	meta, err := h.bs.Get(ctx, bucket)
+	if err != nil || meta.Defaults.Website == nil {
+		if err != nil && !storage.ErrKeyNotFound.Has(err) {
+			h.serverError(w, err)
+			return
+		}
+		http.NotFound(w, r)
+		return
+	}
+	web := meta.Defaults.Website
+
+	if target, ok := redirect(web.Redirects, key); ok {
+		http.Redirect(w, r, base+target, http.StatusMovedPermanently)
+		return
+	}
+
+	store, err := h.bs.GetObjectStore(ctx, bucket)
+	if err != nil {
+		h.serverError(w, err)
+		return
+	}
+
+	if web.IndexDocument != "" && (key == "" || strings.HasSuffix(key, "/")) {
+		key += web.IndexDocument
+	}
+
+	rr, m, err := store.Get(ctx, paths.New(key))
+	if storage.ErrKeyNotFound.Has(err) && web.IndexDocument != "" && key != "" {
+		// like S3, a path without the trailing slash of a prefix with an
+		// index document is redirected to the prefix
+		if _, err := store.Meta(ctx, paths.New(key, web.IndexDocument)); err == nil {
+			http.Redirect(w, r, base+key+"/", http.StatusFound)
+			return
+		}
+	}
+	if err != nil {
+		if storage.ErrKeyNotFound.Has(err) {
+			h.notFound(ctx, w, r, store, web.ErrorDocument)
+			return
+		}
+		h.serverError(w, err)
+		return
+	}
+
+	if m.ContentType != "" {
+		w.Header().Set("Content-Type", m.ContentType)
+	}
+	if m.Checksum != "" {
+		// the checksum identifies the content for conditional and range
+		// requests
+		w.Header().Set("Etag", `"`+m.Checksum+`"`)
+	}
+	ranger.ServeContent(ctx, w, r, key, m.Modified, rr)
+}
+
+// route returns the bucket and key of a request and the prefix of the paths
+// of the bucket's objects in redirects
+func (h *Handler) route(r *http.Request) (bucket, key, base string) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+
+	if h.domain != "" {
+		host := r.Host
+		if hostname, _, err := net.SplitHostPort(host); err == nil {
+			host = hostname
+		}
+		if strings.HasSuffix(host, "."+h.domain) {
+			return strings.TrimSuffix(host, "."+h.domain), path, "/"
+		}
+	}
+
+	parts := append(strings.SplitN(path, "/", 2), "")
+	return parts[0], parts[1], "/" + parts[0] + "/"
+}
+
+// redirect returns the key that key is redirected to by the redirect with
+// the longest matching prefix, if any
+func redirect(redirects map[string]string, key string) (target string, ok bool) {
+	longest := -1
+	for prefix, replacement := range redirects {
+		if strings.HasPrefix(key, prefix) && len(prefix) > longest {
+			longest = len(prefix)
+			target = replacement + key[len(prefix):]
+		}
+	}
+	return target, longest >= 0
+}
+
+// notFound serves the error document with status 404, or a plain 404 if
+// there's none
+func (h *Handler) notFound(ctx context.Context, w http.ResponseWriter, r *http.Request,
+	store objects.Store, errorDocument string) {
+	if errorDocument == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	rr, m, err := store.Get(ctx, paths.New(errorDocument))
+	if err != nil {
+		h.log.Debug("missing error document", zap.String("key", errorDocument), zap.Error(err))
+		http.NotFound(w, r)
+		return
+	}
+	body, err := rr.Range(ctx, 0, rr.Size())
+	if err != nil {
+		h.serverError(w, err)
+		return
+	}
+	defer utils.LogClose(body)
+
+	if m.ContentType != "" {
+		w.Header().Set("Content-Type", m.ContentType)
+	}
+	w.WriteHeader(http.StatusNotFound)
+	if r.Method != http.MethodHead {
+		_, _ = io.Copy(w, body)
+	}
+}
+
+func (h *Handler) serverError(w http.ResponseWriter, err error) {
+	h.log.Error("serving website failed", zap.Error(err))
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
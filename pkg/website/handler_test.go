@@ -0,0 +1,137 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package website
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"storj.io/storj/pkg/paths"
+	"storj.io/storj/pkg/ranger"
+	"storj.io/storj/pkg/storage/buckets"
+	"storj.io/storj/pkg/storage/objects"
+	"storj.io/storj/storage"
+)
+
+// memoryBuckets serves buckets of objects held in memory. Only the methods
+// the handler uses are implemented.
+type memoryBuckets struct {
+	buckets.Store
+	meta    map[string]buckets.Meta
+	objects map[string]memoryObjects
+}
+
+func (m *memoryBuckets) Get(ctx context.Context, bucket string) (buckets.Meta, error) {
+	meta, ok := m.meta[bucket]
+	if !ok {
+		return meta, storage.ErrKeyNotFound.New(bucket)
+	}
+	return meta, nil
+}
+
+func (m *memoryBuckets) GetObjectStore(ctx context.Context, bucket string) (objects.Store, error) {
+	return m.objects[bucket], nil
+}
+
+type memoryObjects struct {
+	objects.Store
+	data map[string]string
+}
+
+func (m memoryObjects) Meta(ctx context.Context, path paths.Path) (objects.Meta, error) {
+	_, meta, err := m.Get(ctx, path)
+	return meta, err
+}
+
+func (m memoryObjects) Get(ctx context.Context, path paths.Path) (ranger.Ranger, objects.Meta, error) {
+	data, ok := m.data[path.String()]
+	if !ok {
+		return nil, objects.Meta{}, storage.ErrKeyNotFound.New(path.String())
+	}
+	meta := objects.Meta{Size: int64(len(data)), Checksum: fmt.Sprintf("%x", data)}
+	meta.ContentType = "text/html"
+	return ranger.ByteRanger(data), meta, nil
+}
+
+func TestHandler(t *testing.T) {
+	bs := &memoryBuckets{
+		meta: map[string]buckets.Meta{
+			"site": {Defaults: buckets.Defaults{Website: &buckets.Website{
+				IndexDocument: "index.html",
+				ErrorDocument: "404.html",
+				Redirects:     map[string]string{"old/": "new/", "old/keep/": "kept/"},
+			}}},
+			"private": {},
+		},
+		objects: map[string]memoryObjects{
+			"site": {data: map[string]string{
+				"index.html":      "home",
+				"404.html":        "missing",
+				"docs/index.html": "docs",
+				"docs/a.html":     "a",
+			}},
+			"private": {data: map[string]string{"index.html": "secret"}},
+		},
+	}
+	handler := NewHandler(zap.NewNop(), bs, "example.com")
+
+	for _, tt := range []struct {
+		host, path string
+		status     int
+		body       string
+		location   string
+	}{
+		{"localhost", "/site/", http.StatusOK, "home", ""},
+		{"localhost", "/site", http.StatusMovedPermanently, "", "/site/"},
+		{"localhost", "/site/docs/", http.StatusOK, "docs", ""},
+		{"localhost", "/site/docs", http.StatusFound, "", "/site/docs/"},
+		{"localhost", "/site/docs/a.html", http.StatusOK, "a", ""},
+		{"localhost", "/site/nope", http.StatusNotFound, "missing", ""},
+		{"localhost", "/site/old/x.html", http.StatusMovedPermanently, "", "/site/new/x.html"},
+		{"localhost", "/site/old/keep/x.html", http.StatusMovedPermanently, "", "/site/kept/x.html"},
+		{"localhost", "/private/", http.StatusNotFound, "", ""},
+		{"localhost", "/unknown/", http.StatusNotFound, "", ""},
+		{"site.example.com", "/docs/a.html", http.StatusOK, "a", ""},
+		{"site.example.com:8080", "/", http.StatusOK, "home", ""},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "http://"+tt.host+tt.path, nil)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		assert.Equal(t, tt.status, rec.Code, tt.host+tt.path)
+		if tt.body != "" {
+			assert.Equal(t, tt.body, rec.Body.String(), tt.host+tt.path)
+		}
+		assert.Equal(t, tt.location, rec.Header().Get("Location"), tt.host+tt.path)
+	}
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/site/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	// the checksum of an object is its ETag
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/site/docs/a.html", nil))
+	etag := rec.Header().Get("Etag")
+	assert.Equal(t, `"61"`, etag)
+
+	req := httptest.NewRequest(http.MethodGet, "/site/docs/a.html", nil)
+	req.Header.Set("If-None-Match", etag)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/site/", nil)
+	req.Header.Set("Range", "bytes=1-2")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusPartialContent, rec.Code)
+	assert.Equal(t, "om", rec.Body.String())
+}
@@ -106,6 +106,39 @@ func (client *Client) ReverseList(first storage.Key, limit int) (storage.Keys, e
 	return storage.ReverseListKeys(client, first, limit)
 }
 
+// Update calls fn with a transaction of the bucket, which is committed if fn
+// returns nil
+func (client *Client) Update(fn func(storage.Transaction) error) error {
+	return client.update(func(bucket *bolt.Bucket) error {
+		return fn(&transaction{bucket: bucket})
+	})
+}
+
+// transaction is a transaction of a bucket
+type transaction struct {
+	bucket *bolt.Bucket
+}
+
+func (tx *transaction) Get(key storage.Key) (storage.Value, error) {
+	data := tx.bucket.Get([]byte(key))
+	if len(data) == 0 {
+		return nil, storage.ErrKeyNotFound.New(key.String())
+	}
+	return storage.CloneValue(storage.Value(data)), nil
+}
+
+func (tx *transaction) Put(key storage.Key, value storage.Value) error {
+	if len(key) == 0 {
+		return Error.New("invalid key")
+	}
+	// bolt keeps the key and value until the transaction is committed
+	return tx.bucket.Put(storage.CloneKey(key), storage.CloneValue(value))
+}
+
+func (tx *transaction) Delete(key storage.Key) error {
+	return tx.bucket.Delete(key)
+}
+
 // Close closes a BoltDB client
 func (client *Client) Close() error {
 	return client.db.Close()
@@ -63,6 +63,10 @@ type KeyValueStore interface {
 	ReverseList(Key, int) (Keys, error)
 	// Iterate iterates over items based on opts
 	Iterate(opts IterateOptions, fn func(Iterator) error) error
+	// Update calls fn with a transaction, whose writes are committed
+	// atomically if fn returns nil. fn may be called again if the
+	// transaction conflicts with concurrent writes.
+	Update(fn func(Transaction) error) error
 	// Close closes the store
 	Close() error
 }
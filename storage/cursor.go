@@ -0,0 +1,67 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storage
+
+import (
+	"encoding/base64"
+
+	"github.com/zeebo/errs"
+)
+
+// ErrCursor is returned when a listing cursor can't be decoded
+var ErrCursor = errs.Class("invalid cursor")
+
+const (
+	cursorVersion = 1
+
+	cursorAfter  = 'a'
+	cursorBefore = 'b'
+)
+
+// NextCursor returns an opaque cursor that continues a listing with opts,
+// which returned items and more. Cursors refer to the last listed key rather
+// than an offset, so listing with them neither skips nor repeats keys when
+// keys are inserted or deleted between pages. NextCursor returns an empty
+// cursor once the listing is complete.
+func NextCursor(opts ListOptions, items Items, more bool) string {
+	if !more || len(items) == 0 {
+		return ""
+	}
+	if !opts.EndBefore.IsZero() {
+		return encodeCursor(cursorBefore, items[0].Key)
+	}
+	return encodeCursor(cursorAfter, items[len(items)-1].Key)
+}
+
+// ApplyCursor returns opts continuing the listing that returned cursor. An
+// empty cursor doesn't change opts.
+func ApplyCursor(opts ListOptions, cursor string) (ListOptions, error) {
+	if cursor == "" {
+		return opts, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return opts, ErrCursor.Wrap(err)
+	}
+	if len(data) < 2 || data[0] != cursorVersion {
+		return opts, ErrCursor.New("unsupported cursor")
+	}
+
+	key := Key(data[2:])
+	switch data[1] {
+	case cursorAfter:
+		opts.StartAfter, opts.EndBefore = key, nil
+	case cursorBefore:
+		opts.StartAfter, opts.EndBefore = nil, key
+	default:
+		return opts, ErrCursor.New("unsupported cursor direction")
+	}
+	return opts, nil
+}
+
+func encodeCursor(direction byte, key Key) string {
+	data := append([]byte{cursorVersion, direction}, key...)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,145 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package namespace
+
+import (
+	"bytes"
+
+	"storj.io/storj/storage"
+)
+
+// Store scopes a storage.KeyValueStore to the keys starting with a namespace,
+// so that several datasets can share one database. Keys passed to and
+// returned by Store don't include the namespace.
+type Store struct {
+	namespace storage.Key
+	store     storage.KeyValueStore
+}
+
+// New returns store scoped to namespace. Namespaces of stores sharing a
+// database must not be prefixes of each other, which is easiest to ensure by
+// ending them with storage.Delimiter.
+func New(store storage.KeyValueStore, namespace string) *Store {
+	return &Store{namespace: storage.Key(namespace), store: store}
+}
+
+func (store *Store) key(key storage.Key) storage.Key {
+	return append(append(storage.Key{}, store.namespace...), key...)
+}
+
+// Put adds a value to store
+func (store *Store) Put(key storage.Key, value storage.Value) error {
+	if key.IsZero() {
+		return storage.ErrEmptyKey
+	}
+	return store.store.Put(store.key(key), value)
+}
+
+// Get gets a value to store
+func (store *Store) Get(key storage.Key) (storage.Value, error) {
+	if key.IsZero() {
+		return nil, storage.ErrEmptyKey
+	}
+	return store.store.Get(store.key(key))
+}
+
+// GetAll gets all values from the store corresponding to keys
+func (store *Store) GetAll(keys storage.Keys) (storage.Values, error) {
+	if len(keys) > storage.LookupLimit {
+		return nil, storage.ErrLimitExceeded
+	}
+
+	scoped := make(storage.Keys, 0, len(keys))
+	for _, key := range keys {
+		scoped = append(scoped, store.key(key))
+	}
+	return store.store.GetAll(scoped)
+}
+
+// Delete deletes key and the value
+func (store *Store) Delete(key storage.Key) error {
+	if key.IsZero() {
+		return storage.ErrEmptyKey
+	}
+	return store.store.Delete(store.key(key))
+}
+
+// List lists all keys starting from first and upto limit items
+func (store *Store) List(first storage.Key, limit int) (storage.Keys, error) {
+	return storage.ListKeys(store, first, limit)
+}
+
+// ReverseList lists all keys in reverse order, starting from first
+func (store *Store) ReverseList(first storage.Key, limit int) (storage.Keys, error) {
+	return storage.ReverseListKeys(store, first, limit)
+}
+
+// Iterate iterates over items based on opts
+func (store *Store) Iterate(opts storage.IterateOptions, fn func(storage.Iterator) error) error {
+	scoped := opts
+	scoped.Prefix = store.key(opts.Prefix)
+	if !opts.First.IsZero() {
+		scoped.First = store.key(opts.First)
+	}
+
+	return store.store.Iterate(scoped, func(it storage.Iterator) error {
+		return fn(storage.IteratorFunc(func(item *storage.ListItem) bool {
+			if !it.Next(item) {
+				return false
+			}
+			if !bytes.HasPrefix(item.Key, store.namespace) {
+				return false
+			}
+			item.Key = item.Key[len(store.namespace):]
+			return true
+		}))
+	})
+}
+
+// Update calls fn with a transaction of the underlying store, scoped to the
+// namespace
+func (store *Store) Update(fn func(storage.Transaction) error) error {
+	return store.store.Update(func(tx storage.Transaction) error {
+		return fn(store.Scope(tx))
+	})
+}
+
+// Scope scopes tx, a transaction of the underlying store, to the namespace,
+// so that a transaction can span several namespaces of the store
+func (store *Store) Scope(tx storage.Transaction) storage.Transaction {
+	return &transaction{store: store, tx: tx}
+}
+
+// transaction is a transaction scoped to a namespace
+type transaction struct {
+	store *Store
+	tx    storage.Transaction
+}
+
+func (tx *transaction) Get(key storage.Key) (storage.Value, error) {
+	if key.IsZero() {
+		return nil, storage.ErrEmptyKey
+	}
+	return tx.tx.Get(tx.store.key(key))
+}
+
+func (tx *transaction) Put(key storage.Key, value storage.Value) error {
+	if key.IsZero() {
+		return storage.ErrEmptyKey
+	}
+	return tx.tx.Put(tx.store.key(key), value)
+}
+
+func (tx *transaction) Delete(key storage.Key) error {
+	if key.IsZero() {
+		return storage.ErrEmptyKey
+	}
+	return tx.tx.Delete(tx.store.key(key))
+}
+
+// Close does nothing, as the underlying store is shared with other
+// namespaces. The owner of the underlying store closes it.
+func (store *Store) Close() error {
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package namespace
+
+import (
+	"testing"
+
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+	"storj.io/storj/storage/testsuite"
+)
+
+// newStore returns a namespace of a store that has keys before and after the
+// namespace, which the namespace must not see
+func newStore(t testing.TB) *Store {
+	shared := teststore.New()
+	err := storage.PutAll(shared,
+		storage.ListItem{Key: storage.Key("a/before"), Value: storage.Value("1")},
+		storage.ListItem{Key: storage.Key("ns"), Value: storage.Value("2")},
+		storage.ListItem{Key: storage.Key("z/after"), Value: storage.Value("3")},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(shared, "ns/")
+}
+
+func TestSuite(t *testing.T)      { testsuite.RunTests(t, newStore(t)) }
+func BenchmarkSuite(b *testing.B) { testsuite.RunBenchmarks(b, newStore(b)) }
+
+func TestIsolation(t *testing.T) {
+	shared := teststore.New()
+	first, second := New(shared, "first/"), New(shared, "second/")
+
+	if err := first.Put(storage.Key("key"), storage.Value("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.Put(storage.Key("key"), storage.Value("second")); err != nil {
+		t.Fatal(err)
+	}
+
+	value, err := first.Get(storage.Key("key"))
+	if err != nil || string(value) != "first" {
+		t.Fatalf("unexpected value %q: %v", value, err)
+	}
+	if _, err := shared.Get(storage.Key("second/key")); err != nil {
+		t.Fatalf("expected the key to be stored with its namespace: %v", err)
+	}
+
+	if err := first.Delete(storage.Key("key")); err != nil {
+		t.Fatal(err)
+	}
+	keys, err := second.List(nil, 0)
+	if err != nil || len(keys) != 1 || keys[0].String() != "key" {
+		t.Fatalf("unexpected keys %v: %v", keys, err)
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package readcache
+
+import (
+	"container/list"
+	"sync"
+
+	"storj.io/storj/storage"
+)
+
+// LRU is an in-memory Cache of the most recently used values
+type LRU struct {
+	size int
+
+	mu    sync.Mutex
+	order *list.List // of *lruItem, most recently used first
+	items map[string]*list.Element
+}
+
+type lruItem struct {
+	key   string
+	value storage.Value
+}
+
+// NewLRU returns an in-memory cache of up to size values
+func NewLRU(size int) *LRU {
+	return &LRU{
+		size:  size,
+		order: list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+// Get returns the value of key
+func (lru *LRU) Get(key storage.Key) (storage.Value, error) {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	elem, ok := lru.items[string(key)]
+	if !ok {
+		return nil, storage.ErrKeyNotFound.New(key.String())
+	}
+	lru.order.MoveToFront(elem)
+	return storage.CloneValue(elem.Value.(*lruItem).value), nil
+}
+
+// Put caches the value of key, evicting the least recently used value if the
+// cache is full
+func (lru *LRU) Put(key storage.Key, value storage.Value) error {
+	if lru.size <= 0 {
+		return nil
+	}
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	value = storage.CloneValue(value)
+	if elem, ok := lru.items[string(key)]; ok {
+		elem.Value.(*lruItem).value = value
+		lru.order.MoveToFront(elem)
+		return nil
+	}
+	lru.items[string(key)] = lru.order.PushFront(&lruItem{key: string(key), value: value})
+	if lru.order.Len() > lru.size {
+		oldest := lru.order.Back()
+		lru.order.Remove(oldest)
+		delete(lru.items, oldest.Value.(*lruItem).key)
+	}
+	return nil
+}
+
+// Delete evicts key
+func (lru *LRU) Delete(key storage.Key) error {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+
+	if elem, ok := lru.items[string(key)]; ok {
+		lru.order.Remove(elem)
+		delete(lru.items, string(key))
+	}
+	return nil
+}
+
+// Len returns the number of cached values
+func (lru *LRU) Len() int {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	return lru.order.Len()
+}
+
+// Close empties the cache
+func (lru *LRU) Close() error {
+	lru.mu.Lock()
+	defer lru.mu.Unlock()
+	lru.order.Init()
+	lru.items = map[string]*list.Element{}
+	return nil
+}
@@ -0,0 +1,141 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package readcache
+
+import (
+	"sync"
+
+	monkit "gopkg.in/spacemonkeygo/monkit.v2"
+
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+var mon = monkit.Package()
+
+// Cache keeps some of the values of a store, like the LRU or a redis client
+// with a TTL
+type Cache interface {
+	// Get returns the value of key, or storage.ErrKeyNotFound if it isn't
+	// cached
+	Get(key storage.Key) (storage.Value, error)
+	Put(key storage.Key, value storage.Value) error
+	Delete(key storage.Key) error
+	Close() error
+}
+
+// Store is a read-through cache in front of a store, which is the source of
+// truth. Gets that miss the cache are served by the store and fill the
+// cache, and writes evict the key from the cache. Everything else is served
+// by the store.
+type Store struct {
+	storage.KeyValueStore
+	cache Cache
+
+	// mu serializes filling the cache with counting writes, so a value read
+	// before a write is never cached after the write evicted the key. Writes
+	// are counted once the store applied them, as a value read while a write
+	// is pending may still be the old one.
+	mu     sync.Mutex
+	writes uint64
+}
+
+// New returns store with a read-through cache
+func New(cache Cache, store storage.KeyValueStore) *Store {
+	return &Store{KeyValueStore: store, cache: cache}
+}
+
+// Get gets the value of key from the cache, or from the store if it misses
+func (store *Store) Get(key storage.Key) (storage.Value, error) {
+	value, err := store.cache.Get(key)
+	if err == nil {
+		mon.Counter("cache_hit").Inc(1)
+		return value, nil
+	}
+	if !storage.ErrKeyNotFound.Has(err) {
+		// the store can still serve the value
+		mon.Counter("cache_error").Inc(1)
+	}
+	mon.Counter("cache_miss").Inc(1)
+
+	store.mu.Lock()
+	writes := store.writes
+	store.mu.Unlock()
+
+	value, err = store.KeyValueStore.Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if store.writes == writes {
+		if err := store.cache.Put(key, value); err != nil {
+			mon.Counter("cache_error").Inc(1)
+		}
+	}
+	return value, nil
+}
+
+// Put adds a value to the store and evicts key from the cache
+func (store *Store) Put(key storage.Key, value storage.Value) error {
+	err := store.KeyValueStore.Put(key, value)
+	store.wrote()
+	if err != nil {
+		return err
+	}
+	return store.evict(key)
+}
+
+// Delete deletes key from the store and the cache
+func (store *Store) Delete(key storage.Key) error {
+	err := store.KeyValueStore.Delete(key)
+	store.wrote()
+	if err != nil {
+		return err
+	}
+	return store.evict(key)
+}
+
+// Update calls fn with a transaction of the store, and evicts the keys it
+// wrote from the cache once it is committed
+func (store *Store) Update(fn func(storage.Transaction) error) error {
+	var written *storage.WriteBuffer
+	err := store.KeyValueStore.Update(func(tx storage.Transaction) error {
+		written = storage.NewWriteBuffer(tx.Get)
+		if err := fn(written); err != nil {
+			return err
+		}
+		return written.Apply(tx)
+	})
+	store.wrote()
+	if err != nil {
+		return err
+	}
+	return written.Writes(func(key storage.Key, _ storage.Value) error {
+		return store.evict(key)
+	})
+}
+
+// wrote counts a write once the store returned, before the written keys are
+// evicted. A failed write is counted too, as it may have been applied.
+func (store *Store) wrote() {
+	store.mu.Lock()
+	store.writes++
+	store.mu.Unlock()
+}
+
+// evict removes key from the cache
+func (store *Store) evict(key storage.Key) error {
+	err := store.cache.Delete(key)
+	if err != nil && !storage.ErrKeyNotFound.Has(err) {
+		return err
+	}
+	return nil
+}
+
+// Close closes the cache and the store
+func (store *Store) Close() error {
+	return utils.CombineErrors(store.cache.Close(), store.KeyValueStore.Close())
+}
@@ -0,0 +1,142 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package readcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+	"storj.io/storj/storage/testsuite"
+)
+
+func TestSuite(t *testing.T) {
+	testsuite.RunTests(t, New(NewLRU(10), teststore.New()))
+}
+
+func BenchmarkSuite(b *testing.B) {
+	testsuite.RunBenchmarks(b, New(NewLRU(10), teststore.New()))
+}
+
+func TestReadCache(t *testing.T) {
+	backing := teststore.New()
+	store := New(NewLRU(10), backing)
+
+	assert.NoError(t, store.Put(storage.Key("hot"), storage.Value("1")))
+
+	// the first get fills the cache, the next ones are served by it
+	for i := 0; i < 3; i++ {
+		value, err := store.Get(storage.Key("hot"))
+		assert.NoError(t, err)
+		assert.Equal(t, storage.Value("1"), value)
+	}
+	assert.Equal(t, 1, backing.CallCount.Get)
+
+	// writes evict the key
+	assert.NoError(t, store.Put(storage.Key("hot"), storage.Value("2")))
+	value, err := store.Get(storage.Key("hot"))
+	assert.NoError(t, err)
+	assert.Equal(t, storage.Value("2"), value)
+	assert.Equal(t, 2, backing.CallCount.Get)
+
+	assert.NoError(t, store.Delete(storage.Key("hot")))
+	_, err = store.Get(storage.Key("hot"))
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+
+	// missing keys aren't cached
+	assert.NoError(t, backing.Put(storage.Key("hot"), storage.Value("3")))
+	value, err = store.Get(storage.Key("hot"))
+	assert.NoError(t, err)
+	assert.Equal(t, storage.Value("3"), value)
+}
+
+// pausingStore pauses Put before it writes to the store, and Get after it
+// read from it, to interleave them
+type pausingStore struct {
+	*teststore.Client
+	putting, put chan struct{}
+	got, get     chan struct{}
+}
+
+func (store *pausingStore) Put(key storage.Key, value storage.Value) error {
+	close(store.putting)
+	<-store.put
+	return store.Client.Put(key, value)
+}
+
+func (store *pausingStore) Get(key storage.Key) (storage.Value, error) {
+	value, err := store.Client.Get(key)
+	close(store.got)
+	<-store.get
+	return value, err
+}
+
+func TestReadCacheGetDuringPut(t *testing.T) {
+	backing := &pausingStore{
+		Client:  teststore.New(),
+		putting: make(chan struct{}), put: make(chan struct{}),
+		got: make(chan struct{}), get: make(chan struct{}),
+	}
+	cache := NewLRU(10)
+	store := New(cache, backing)
+	assert.NoError(t, backing.Client.Put(storage.Key("hot"), storage.Value("1")))
+
+	// the get reads the old value while the put is pending, and fills the
+	// cache after the put evicted the key
+	putErr := make(chan error, 1)
+	go func() { putErr <- store.Put(storage.Key("hot"), storage.Value("2")) }()
+	<-backing.putting
+
+	type result struct {
+		value storage.Value
+		err   error
+	}
+	got := make(chan result, 1)
+	go func() {
+		value, err := store.Get(storage.Key("hot"))
+		got <- result{value, err}
+	}()
+	<-backing.got
+
+	close(backing.put)
+	assert.NoError(t, <-putErr)
+	close(backing.get)
+	r := <-got
+	assert.NoError(t, r.err)
+	assert.Equal(t, storage.Value("1"), r.value)
+
+	// the old value isn't cached
+	_, err := cache.Get(storage.Key("hot"))
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+}
+
+func TestLRU(t *testing.T) {
+	lru := NewLRU(2)
+	assert.NoError(t, lru.Put(storage.Key("a"), storage.Value("1")))
+	assert.NoError(t, lru.Put(storage.Key("b"), storage.Value("2")))
+
+	// getting a makes b the least recently used
+	_, err := lru.Get(storage.Key("a"))
+	assert.NoError(t, err)
+	assert.NoError(t, lru.Put(storage.Key("c"), storage.Value("3")))
+	assert.Equal(t, 2, lru.Len())
+	_, err = lru.Get(storage.Key("b"))
+	assert.True(t, storage.ErrKeyNotFound.Has(err))
+
+	value, err := lru.Get(storage.Key("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, storage.Value("1"), value)
+
+	// cached values are copies
+	value[0] = 'x'
+	value, err = lru.Get(storage.Key("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, storage.Value("1"), value)
+
+	assert.NoError(t, lru.Delete(storage.Key("a")))
+	assert.NoError(t, lru.Delete(storage.Key("a")))
+	assert.Equal(t, 1, lru.Len())
+}
@@ -4,7 +4,11 @@
 package redis
 
 import (
+	"net/url"
 	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis"
@@ -21,19 +25,57 @@ const defaultNodeExpiration = 61 * time.Minute
 
 // Client is the entrypoint into Redis
 type Client struct {
-	db  *redis.Client
-	TTL time.Duration
+	db redis.UniversalClient
+	// cluster is set if db is a Redis Cluster, whose keys are spread over
+	// several masters
+	cluster *redis.ClusterClient
+	TTL     time.Duration
 }
 
 // NewClient returns a configured Client instance, verifying a successful connection to redis
 func NewClient(address, password string, db int) (*Client, error) {
+	return newClient(redis.NewClient(&redis.Options{
+		Addr:     address,
+		Password: password,
+		DB:       db,
+	}), nil)
+}
+
+// NewClientFrom returns a configured Client instance for a connection URL of
+// the form redis://[:password@]host:port[,host:port...][?db=0], verifying a
+// successful connection to redis. With the query parameter sentinel=<master>
+// the hosts are the Sentinels monitoring the master, which the client fails
+// over with. With cluster=true the hosts are nodes of a Redis Cluster.
+func NewClientFrom(address string) (*Client, error) {
+	opts, err := parseURL(address)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case opts.master != "":
+		return newClient(redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    opts.master,
+			SentinelAddrs: opts.addrs,
+			Password:      opts.password,
+			DB:            opts.db,
+		}), nil)
+	case opts.cluster:
+		cluster := redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    opts.addrs,
+			Password: opts.password,
+		})
+		return newClient(cluster, cluster)
+	default:
+		return NewClient(opts.addrs[0], opts.password, opts.db)
+	}
+}
+
+func newClient(db redis.UniversalClient, cluster *redis.ClusterClient) (*Client, error) {
 	client := &Client{
-		db: redis.NewClient(&redis.Options{
-			Addr:     address,
-			Password: password,
-			DB:       db,
-		}),
-		TTL: defaultNodeExpiration,
+		db:      db,
+		cluster: cluster,
+		TTL:     defaultNodeExpiration,
 	}
 
 	// ping here to verify we are able to connect to redis with the initialized client.
@@ -44,6 +86,58 @@ func NewClient(address, password string, db int) (*Client, error) {
 	return client, nil
 }
 
+// options are the connection options of a redis connection URL
+type options struct {
+	addrs    []string
+	password string
+	db       int
+	master   string
+	cluster  bool
+}
+
+func parseURL(address string) (*options, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if u.Scheme != "redis" {
+		return nil, Error.New("unsupported scheme: %s", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, Error.New("no address specified")
+	}
+
+	opts := &options{addrs: strings.Split(u.Host, ",")}
+	if u.User != nil {
+		opts.password, _ = u.User.Password()
+	}
+
+	query := u.Query()
+	if db := query.Get("db"); db != "" {
+		opts.db, err = strconv.Atoi(db)
+		if err != nil {
+			return nil, Error.New("invalid db: %v", err)
+		}
+	}
+	opts.master = query.Get("sentinel")
+	if cluster := query.Get("cluster"); cluster != "" {
+		opts.cluster, err = strconv.ParseBool(cluster)
+		if err != nil {
+			return nil, Error.New("invalid cluster: %v", err)
+		}
+	}
+
+	switch {
+	case opts.master != "" && opts.cluster:
+		return nil, Error.New("sentinel and cluster cannot be combined")
+	case opts.cluster && opts.db != 0:
+		return nil, Error.New("redis cluster only supports db 0")
+	case opts.master == "" && !opts.cluster && len(opts.addrs) > 1:
+		return nil, Error.New("multiple addresses require sentinel or cluster")
+	}
+	return opts, nil
+}
+
 // Get looks up the provided key from redis returning either an error or the result.
 func (client *Client) Get(key storage.Key) (storage.Value, error) {
 	value, err := client.db.Get(string(key)).Bytes()
@@ -68,6 +162,61 @@ func (client *Client) Put(key storage.Key, value storage.Value) error {
 	return nil
 }
 
+// maxTransactionRetries is how many times a transaction is attempted while
+// it conflicts with concurrent writes
+const maxTransactionRetries = 10
+
+// Update calls fn with a transaction whose writes are applied with
+// MULTI/EXEC. The keys read by the transaction are watched, and fn is called
+// again if they change before the writes are applied. Transactions aren't
+// supported by Redis Cluster clients, as their keys may live on different
+// masters.
+func (client *Client) Update(fn func(storage.Transaction) error) error {
+	if client.cluster != nil {
+		return Error.New("transactions aren't supported on redis cluster")
+	}
+	for i := 0; i < maxTransactionRetries; i++ {
+		err := client.db.Watch(func(tx *redis.Tx) error {
+			buffer := storage.NewWriteBuffer(func(key storage.Key) (storage.Value, error) {
+				if err := tx.Watch(key.String()).Err(); err != nil {
+					return nil, Error.New("watch error: %v", err)
+				}
+				value, err := tx.Get(key.String()).Bytes()
+				if err == redis.Nil {
+					return nil, storage.ErrKeyNotFound.New(key.String())
+				}
+				if err != nil {
+					return nil, Error.New("get error: %v", err)
+				}
+				return value, nil
+			})
+			if err := fn(buffer); err != nil {
+				return err
+			}
+			if buffer.Len() == 0 {
+				return nil
+			}
+
+			_, err := tx.Pipelined(func(pipe redis.Pipeliner) error {
+				return buffer.Writes(func(key storage.Key, value storage.Value) error {
+					if value == nil {
+						return pipe.Del(key.String()).Err()
+					}
+					return pipe.Set(key.String(), []byte(value), client.TTL).Err()
+				})
+			})
+			if err != nil && err != redis.TxFailedErr {
+				return Error.New("transaction error: %v", err)
+			}
+			return err
+		})
+		if err != redis.TxFailedErr {
+			return err
+		}
+	}
+	return Error.New("transaction conflicted %d times", maxTransactionRetries)
+}
+
 // List returns either a list of keys for which boltdb has values or an error.
 func (client *Client) List(first storage.Key, limit int) (storage.Keys, error) {
 	return storage.ListKeys(client, first, limit)
@@ -106,7 +255,30 @@ func (client *Client) GetAll(keys storage.Keys) (storage.Values, error) {
 		keyStrings[i] = v.String()
 	}
 
-	results, err := client.db.MGet(keyStrings...).Result()
+	if client.cluster == nil {
+		return client.mget(keyStrings)
+	}
+
+	// keys of a single MGET must hash to the same slot of a cluster
+	values := make(storage.Values, len(keys))
+	for _, indexes := range groupBySlot(keyStrings) {
+		slotKeys := make([]string, 0, len(indexes))
+		for _, i := range indexes {
+			slotKeys = append(slotKeys, keyStrings[i])
+		}
+		slotValues, err := client.mget(slotKeys)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range indexes {
+			values[i] = slotValues[j]
+		}
+	}
+	return values, nil
+}
+
+func (client *Client) mget(keys []string) (storage.Values, error) {
+	results, err := client.db.MGet(keys...).Result()
 	if err != nil {
 		return nil, err
 	}
@@ -154,9 +326,11 @@ func (client *Client) allPrefixedItems(prefix, first, last storage.Key) (storage
 	seen := map[string]struct{}{}
 
 	match := string(escapeMatch([]byte(prefix))) + "*"
-	it := client.db.Scan(0, match, 0).Iterator()
-	for it.Next() {
-		key := it.Val()
+	keys, err := client.scan(match)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
 		if !first.IsZero() && storage.Key(key).Less(first) {
 			continue
 		}
@@ -185,3 +359,33 @@ func (client *Client) allPrefixedItems(prefix, first, last storage.Key) (storage
 
 	return all, nil
 }
+
+// scan returns the keys matching match. The keys of a cluster are scanned
+// on every master, as each only has the keys of its own slots.
+func (client *Client) scan(match string) (keys []string, err error) {
+	if client.cluster == nil {
+		it := client.db.Scan(0, match, 0).Iterator()
+		for it.Next() {
+			keys = append(keys, it.Val())
+		}
+		return keys, it.Err()
+	}
+
+	var mu sync.Mutex
+	err = client.cluster.ForEachMaster(func(master *redis.Client) error {
+		it := master.Scan(0, match, 0).Iterator()
+		var masterKeys []string
+		for it.Next() {
+			masterKeys = append(masterKeys, it.Val())
+		}
+		if err := it.Err(); err != nil {
+			return err
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, masterKeys...)
+		return nil
+	})
+	return keys, err
+}
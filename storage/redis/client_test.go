@@ -5,6 +5,7 @@ package redis
 
 import (
 	"testing"
+	"time"
 
 	"storj.io/storj/storage/redis/redisserver"
 	"storj.io/storj/storage/testsuite"
@@ -46,3 +47,43 @@ func BenchmarkSuite(b *testing.B) {
 
 	testsuite.RunBenchmarks(b, client)
 }
+
+func TestLease(t *testing.T) {
+	addr, cleanup, err := redisserver.Start()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+
+	client, err := NewClient(addr, "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acquire := func(owner string) bool {
+		acquired, err := client.AcquireLease("chores", owner, time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return acquired
+	}
+
+	if !acquire("a") || !acquire("a") {
+		t.Fatal("expected a to acquire and renew the lease")
+	}
+	if acquire("b") {
+		t.Fatal("expected b not to acquire the lease held by a")
+	}
+	if err := client.ReleaseLease("chores", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if acquire("b") {
+		t.Fatal("expected b not to release the lease held by a")
+	}
+	if err := client.ReleaseLease("chores", "a"); err != nil {
+		t.Fatal(err)
+	}
+	if !acquire("b") {
+		t.Fatal("expected b to acquire the released lease")
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package redis
+
+import (
+	"time"
+
+	"github.com/go-redis/redis"
+)
+
+// leasePrefix is the prefix of the keys of leases, so they don't collide
+// with stored values
+const leasePrefix = "lease/"
+
+// AcquireLease acquires the lease name for owner for ttl, or renews it if
+// owner already holds it. It returns false if another owner holds it.
+func (client *Client) AcquireLease(name, owner string, ttl time.Duration) (acquired bool, err error) {
+	key := leasePrefix + name
+	err = client.db.Watch(func(tx *redis.Tx) error {
+		current, err := tx.Get(key).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if err == nil && current != owner {
+			return nil
+		}
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			return pipe.Set(key, owner, ttl).Err()
+		})
+		acquired = err == nil
+		return err
+	}, key)
+	if err == redis.TxFailedErr {
+		// another owner changed the lease concurrently
+		return false, nil
+	}
+	if err != nil {
+		return false, Error.New("lease error: %v", err)
+	}
+	return acquired, nil
+}
+
+// ReleaseLease releases the lease name if owner holds it
+func (client *Client) ReleaseLease(name, owner string) error {
+	key := leasePrefix + name
+	err := client.db.Watch(func(tx *redis.Tx) error {
+		current, err := tx.Get(key).Result()
+		if err == redis.Nil || (err == nil && current != owner) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Pipelined(func(pipe redis.Pipeliner) error {
+			return pipe.Del(key).Err()
+		})
+		return err
+	}, key)
+	if err != nil && err != redis.TxFailedErr {
+		return Error.New("lease error: %v", err)
+	}
+	return nil
+}
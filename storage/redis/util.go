@@ -3,6 +3,8 @@
 
 package redis
 
+import "strings"
+
 func escapeMatch(match []byte) []byte {
 	start := 0
 	escaped := []byte{}
@@ -20,3 +22,52 @@ func escapeMatch(match []byte) []byte {
 
 	return append(escaped, match[start:]...)
 }
+
+// slotCount is the number of hash slots of a Redis Cluster
+const slotCount = 16384
+
+// slot returns the hash slot of key in a Redis Cluster. If key contains a
+// hash tag, i.e. a non-empty substring between the first { and the next },
+// only the hash tag is hashed, so that keys with the same hash tag are in the
+// same slot.
+func slot(key string) int {
+	if start := strings.IndexByte(key, '{'); start >= 0 {
+		if end := strings.IndexByte(key[start+1:], '}'); end > 0 {
+			key = key[start+1 : start+1+end]
+		}
+	}
+	return int(crc16(key) % slotCount)
+}
+
+// groupBySlot returns the indexes of keys grouped by their hash slot, in the
+// order the slots first appear in keys
+func groupBySlot(keys []string) (groups [][]int) {
+	bySlot := map[int]int{}
+	for i, key := range keys {
+		s := slot(key)
+		group, ok := bySlot[s]
+		if !ok {
+			group = len(groups)
+			bySlot[s] = group
+			groups = append(groups, nil)
+		}
+		groups[group] = append(groups[group], i)
+	}
+	return groups
+}
+
+// crc16 is the CRC-16/XMODEM checksum Redis Cluster hashes keys with
+func crc16(data string) uint16 {
+	var crc uint16
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
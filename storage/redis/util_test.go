@@ -26,3 +26,59 @@ func TestEscapeMatch(t *testing.T) {
 		}
 	}
 }
+
+func TestSlot(t *testing.T) {
+	for _, example := range []struct {
+		key  string
+		slot int
+	}{
+		// slots from the examples of the Redis Cluster specification
+		{"123456789", 0x31c3 % slotCount},
+		{"foo", 12182},
+		{"{user1000}.following", slot("user1000")},
+		{"{user1000}.followers", slot("user1000")},
+		{"foo{}{bar}", slot("foo{}{bar}")},
+		{"foo{{bar}}zap", slot("{bar")},
+		{"foo{bar}{zap}", slot("bar")},
+	} {
+		if got := slot(example.key); got != example.slot {
+			t.Errorf("slot of %q is %d, expected %d", example.key, got, example.slot)
+		}
+	}
+
+	groups := groupBySlot([]string{"{a}1", "{b}1", "{a}2"})
+	if len(groups) != 2 || len(groups[0]) != 2 || groups[0][1] != 2 || groups[1][0] != 1 {
+		t.Errorf("unexpected groups %v", groups)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	opts, err := parseURL("redis://:secret@a:6379,b:6379?db=2&sentinel=primary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts.addrs) != 2 || opts.password != "secret" || opts.db != 2 || opts.master != "primary" {
+		t.Errorf("unexpected sentinel options %+v", opts)
+	}
+
+	opts, err = parseURL("redis://a:6379,b:6379?cluster=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(opts.addrs) != 2 || !opts.cluster {
+		t.Errorf("unexpected cluster options %+v", opts)
+	}
+
+	for _, invalid := range []string{
+		"bolt://a",
+		"redis://?db=1",
+		"redis://a:6379?db=x",
+		"redis://a:6379,b:6379",
+		"redis://a:6379?cluster=true&db=1",
+		"redis://a:6379?cluster=true&sentinel=primary",
+	} {
+		if _, err := parseURL(invalid); err == nil {
+			t.Errorf("expected %q to be invalid", invalid)
+		}
+	}
+}
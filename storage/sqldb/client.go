@@ -0,0 +1,369 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sqldb
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+const (
+	// maxRetries is how many times statements and transactions are run
+	// when they fail transiently
+	maxRetries = 5
+	// iterateBatch is how many items iterators read at once
+	iterateBatch = 256
+)
+
+var tableName = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// Client is a KeyValueStore storing its items in a table of a SQL database
+type Client struct {
+	db      *sql.DB
+	dialect Dialect
+	table   string
+
+	get    string
+	upsert string
+	delete string
+}
+
+// New opens the SQL database of dialect at dsn, and creates the table of
+// items if it doesn't exist
+func New(dialect Dialect, dsn, table string) (*Client, error) {
+	if !tableName.MatchString(table) {
+		return nil, Error.New("invalid table name %q", table)
+	}
+	db, err := sql.Open(dialect.Driver(), dsn)
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	if _, err := db.Exec(dialect.CreateTable(table)); err != nil {
+		return nil, utils.CombineErrors(Error.Wrap(err), db.Close())
+	}
+
+	return &Client{
+		db:      db,
+		dialect: dialect,
+		table:   table,
+		get:     fmt.Sprintf("SELECT item_value FROM %s WHERE item_key = %s", table, dialect.Placeholder(1)),
+		upsert:  dialect.Upsert(table),
+		delete:  fmt.Sprintf("DELETE FROM %s WHERE item_key = %s", table, dialect.Placeholder(1)),
+	}, nil
+}
+
+// execer is either the database or a transaction
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func (client *Client) getFrom(db execer, key storage.Key) (storage.Value, error) {
+	var value []byte
+	err := db.QueryRow(client.get, []byte(key)).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, storage.ErrKeyNotFound.New(key.String())
+	}
+	if err != nil {
+		return nil, Error.Wrap(err)
+	}
+	return storage.Value(value), nil
+}
+
+func (client *Client) putTo(db execer, key storage.Key, value storage.Value) error {
+	if key.IsZero() {
+		return Error.New("invalid key")
+	}
+	// nil would be inserted as NULL
+	if value == nil {
+		value = storage.Value{}
+	}
+	_, err := db.Exec(client.upsert, []byte(key), []byte(value))
+	return Error.Wrap(err)
+}
+
+func (client *Client) deleteFrom(db execer, key storage.Key) error {
+	_, err := db.Exec(client.delete, []byte(key))
+	return Error.Wrap(err)
+}
+
+// retry calls fn again while it fails transiently
+func (client *Client) retry(fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= maxRetries || !client.dialect.Retryable(err) {
+			return err
+		}
+	}
+}
+
+// Put adds a value to the provided key, replacing the value it had
+func (client *Client) Put(key storage.Key, value storage.Value) error {
+	return client.retry(func() error { return client.putTo(client.db, key, value) })
+}
+
+// Get looks up the value of key
+func (client *Client) Get(key storage.Key) (storage.Value, error) {
+	return client.getFrom(client.db, key)
+}
+
+// GetAll looks up the values of up to storage.LookupLimit keys. The value
+// of the keys that aren't found is nil.
+func (client *Client) GetAll(keys storage.Keys) (storage.Values, error) {
+	if len(keys) > storage.LookupLimit {
+		return nil, storage.ErrLimitExceeded
+	}
+	values := make(storage.Values, 0, len(keys))
+	for _, key := range keys {
+		value, err := client.getFrom(client.db, key)
+		if storage.ErrKeyNotFound.Has(err) {
+			values = append(values, nil)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// Delete deletes key and its value
+func (client *Client) Delete(key storage.Key) error {
+	return client.retry(func() error { return client.deleteFrom(client.db, key) })
+}
+
+// List returns up to limit keys starting from first
+func (client *Client) List(first storage.Key, limit int) (storage.Keys, error) {
+	return storage.ListKeys(client, first, limit)
+}
+
+// ReverseList returns up to limit keys starting from first backwards
+func (client *Client) ReverseList(first storage.Key, limit int) (storage.Keys, error) {
+	return storage.ReverseListKeys(client, first, limit)
+}
+
+// Update calls fn with a serializable transaction, which is committed if fn
+// returns nil. The transaction is retried while it conflicts with
+// concurrent transactions.
+func (client *Client) Update(fn func(storage.Transaction) error) error {
+	for attempt := 1; ; attempt++ {
+		retry, err := client.update(fn)
+		if !retry || attempt >= maxRetries {
+			return err
+		}
+	}
+}
+
+// update runs fn in a transaction, and returns whether it failed
+// transiently
+func (client *Client) update(fn func(storage.Transaction) error) (retry bool, err error) {
+	sqlTx, err := client.db.BeginTx(context.Background(), client.dialect.TxOptions())
+	if err != nil {
+		return client.dialect.Retryable(err), Error.Wrap(err)
+	}
+	tx := &transaction{client: client, tx: sqlTx}
+	if err := fn(tx); err != nil {
+		_ = sqlTx.Rollback()
+		return tx.conflict, err
+	}
+	if err := sqlTx.Commit(); err != nil {
+		return client.dialect.Retryable(err), Error.Wrap(err)
+	}
+	return false, nil
+}
+
+// transaction is a transaction of the table
+type transaction struct {
+	client *Client
+	tx     *sql.Tx
+	// conflict is set once a statement failed transiently, after which the
+	// transaction is retried
+	conflict bool
+}
+
+func (tx *transaction) check(err error) error {
+	if err != nil && tx.client.dialect.Retryable(err) {
+		tx.conflict = true
+	}
+	return err
+}
+
+func (tx *transaction) Get(key storage.Key) (storage.Value, error) {
+	value, err := tx.client.getFrom(tx.tx, key)
+	return value, tx.check(err)
+}
+
+func (tx *transaction) Put(key storage.Key, value storage.Value) error {
+	return tx.check(tx.client.putTo(tx.tx, key, value))
+}
+
+func (tx *transaction) Delete(key storage.Key) error {
+	return tx.check(tx.client.deleteFrom(tx.tx, key))
+}
+
+// Close closes the database
+func (client *Client) Close() error {
+	return client.db.Close()
+}
+
+// Iterate iterates over items based on opts. Items are read in batches,
+// which don't see the same snapshot of the table.
+func (client *Client) Iterate(opts storage.IterateOptions, fn func(storage.Iterator) error) error {
+	it := &iterator{client: client, opts: opts}
+	if !opts.Reverse {
+		// from the first item or the prefix, to the end of the prefix
+		it.cursor, it.inclusive = opts.Prefix, true
+		if !opts.First.IsZero() && !opts.First.Less(opts.Prefix) {
+			it.cursor = opts.First
+		}
+		if !opts.Prefix.IsZero() {
+			it.bound = storage.AfterPrefix(opts.Prefix)
+		}
+	} else {
+		// from the first item or the end of the prefix, back to the prefix
+		if !opts.Prefix.IsZero() {
+			it.cursor, it.inclusive = storage.AfterPrefix(opts.Prefix), false
+		}
+		if !opts.First.IsZero() && (opts.Prefix.IsZero() || opts.First.Less(it.cursor)) {
+			it.cursor, it.inclusive = opts.First, true
+		}
+		it.bound = opts.Prefix
+	}
+	if err := fn(it); err != nil {
+		return err
+	}
+	return it.err
+}
+
+// iterator reads the items of an iteration in batches
+type iterator struct {
+	client *Client
+	opts   storage.IterateOptions
+
+	// cursor is where the next batch is read from, if not empty, and bound
+	// where the iteration ends, the end of the prefix or the prefix itself
+	cursor    storage.Key
+	inclusive bool
+	bound     storage.Key
+
+	batch storage.Items
+	done  bool
+	err   error
+}
+
+// query returns the query of the next batch and its arguments
+func (it *iterator) query() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	condition := func(op string, key storage.Key) {
+		args = append(args, []byte(key))
+		conditions = append(conditions, "item_key "+op+" "+it.client.dialect.Placeholder(len(args)))
+	}
+
+	order := "ASC"
+	if !it.opts.Reverse {
+		if !it.cursor.IsZero() {
+			if it.inclusive {
+				condition(">=", it.cursor)
+			} else {
+				condition(">", it.cursor)
+			}
+		}
+		if !it.bound.IsZero() {
+			condition("<", it.bound)
+		}
+	} else {
+		order = "DESC"
+		if !it.cursor.IsZero() {
+			if it.inclusive {
+				condition("<=", it.cursor)
+			} else {
+				condition("<", it.cursor)
+			}
+		}
+		if !it.bound.IsZero() {
+			condition(">=", it.bound)
+		}
+	}
+
+	query := "SELECT item_key, item_value FROM " + it.client.table
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY item_key %s LIMIT %d", order, iterateBatch)
+	return query, args
+}
+
+// fetch reads the next batch
+func (it *iterator) fetch() {
+	query, args := it.query()
+	rows, err := it.client.db.Query(query, args...)
+	if err != nil {
+		it.err = Error.Wrap(err)
+		return
+	}
+	defer func() { it.err = utils.CombineErrors(it.err, Error.Wrap(rows.Close())) }()
+
+	it.batch = it.batch[:0]
+	for rows.Next() {
+		var key, value []byte
+		if err := rows.Scan(&key, &value); err != nil {
+			it.err = Error.Wrap(err)
+			return
+		}
+		it.batch = append(it.batch, storage.ListItem{Key: key, Value: value})
+	}
+	it.err = Error.Wrap(rows.Err())
+	it.done = len(it.batch) < iterateBatch
+}
+
+// Next prepares the next list item
+func (it *iterator) Next(item *storage.ListItem) bool {
+	if len(it.batch) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		it.fetch()
+		if len(it.batch) == 0 || it.err != nil {
+			return false
+		}
+	}
+	next := it.batch[0]
+	it.batch = it.batch[1:]
+	if !bytes.HasPrefix(next.Key, it.opts.Prefix) {
+		return false
+	}
+
+	if !it.opts.Recurse {
+		// collapse the items below a nested prefix, and skip past them
+		if p := bytes.IndexByte(next.Key[len(it.opts.Prefix):], storage.Delimiter); p >= 0 {
+			prefix := storage.CloneKey(next.Key[:len(it.opts.Prefix)+p+1])
+			if !it.opts.Reverse {
+				it.cursor, it.inclusive = storage.AfterPrefix(prefix), true
+			} else {
+				it.cursor, it.inclusive = prefix, false
+			}
+			it.batch, it.done = it.batch[:0], false
+
+			item.Key = append(item.Key[:0], prefix...)
+			item.Value = item.Value[:0]
+			item.IsPrefix = true
+			return true
+		}
+	}
+
+	it.cursor, it.inclusive = next.Key, false
+	item.Key = append(item.Key[:0], next.Key...)
+	item.Value = append(item.Value[:0], next.Value...)
+	item.IsPrefix = false
+	return true
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sqldb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"storj.io/storj/storage/testsuite"
+)
+
+func TestSuite(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "storj-sqldb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = os.RemoveAll(tempdir) }()
+
+	store, err := New(SQLite3, filepath.Join(tempdir, "sqlite.db"), "items")
+	if err != nil {
+		t.Fatalf("failed to create db: %v", err)
+	}
+	defer func() {
+		if err := store.Close(); err != nil {
+			t.Fatalf("failed to close db: %v", err)
+		}
+	}()
+
+	testsuite.RunTests(t, store)
+}
+
+// TestSuiteServers runs the tests against the databases of the
+// STORJ_POSTGRES_TEST and STORJ_MYSQL_TEST data source names, if set
+func TestSuiteServers(t *testing.T) {
+	for _, server := range []struct {
+		dialect Dialect
+		env     string
+	}{
+		{Postgres, "STORJ_POSTGRES_TEST"},
+		{MySQL, "STORJ_MYSQL_TEST"},
+	} {
+		dsn := os.Getenv(server.env)
+		if dsn == "" {
+			continue
+		}
+		t.Run(server.dialect.Driver(), func(t *testing.T) {
+			store, err := New(server.dialect, dsn, "storj_sqldb_test")
+			if err != nil {
+				t.Fatalf("failed to create db: %v", err)
+			}
+			defer func() { _ = store.Close() }()
+
+			testsuite.RunTests(t, store)
+		})
+	}
+}
+
+func TestInvalidTable(t *testing.T) {
+	if _, err := New(SQLite3, ":memory:", "items; DROP TABLE items"); err == nil {
+		t.Fatal("expected invalid table error")
+	}
+}
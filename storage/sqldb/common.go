@@ -0,0 +1,11 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sqldb
+
+import (
+	"github.com/zeebo/errs"
+)
+
+// Error is the default sqldb errs class
+var Error = errs.Class("sqldb error")
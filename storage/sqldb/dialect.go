@@ -0,0 +1,135 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package sqldb
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	sqlite3 "github.com/mattn/go-sqlite3"
+	"github.com/zeebo/errs"
+)
+
+// Dialect is what differs between the SQL databases a Client can store its
+// items in. Keys are compared byte by byte in every dialect, so that items
+// are iterated in the same order as in the other stores.
+type Dialect interface {
+	// Driver returns the name of the database/sql driver
+	Driver() string
+	// Placeholder returns the placeholder of the i-th argument of a
+	// statement, starting from 1
+	Placeholder(i int) string
+	// CreateTable returns the statement creating the table of items if it
+	// doesn't exist
+	CreateTable(table string) string
+	// Upsert returns the statement inserting an item into table, or
+	// replacing the value of the key, with the key and value as arguments
+	Upsert(table string) string
+	// TxOptions returns the options of serializable transactions
+	TxOptions() *sql.TxOptions
+	// Retryable returns whether err is a transient failure, like a
+	// serialization failure or a deadlock, after which the statement or
+	// transaction can be retried
+	Retryable(err error) bool
+}
+
+var (
+	// Postgres is the dialect of PostgreSQL
+	Postgres Dialect = postgres{}
+	// MySQL is the dialect of MySQL, with the InnoDB engine
+	MySQL Dialect = mysqlDialect{}
+	// SQLite3 is the dialect of SQLite, for tests and single satellites
+	SQLite3 Dialect = sqlite{}
+)
+
+type postgres struct{}
+
+func (postgres) Driver() string { return "postgres" }
+
+func (postgres) Placeholder(i int) string { return "$" + strconv.Itoa(i) }
+
+func (postgres) CreateTable(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		item_key BYTEA PRIMARY KEY,
+		item_value BYTEA NOT NULL
+	)`, table)
+}
+
+func (postgres) Upsert(table string) string {
+	return fmt.Sprintf(`INSERT INTO %s (item_key, item_value) VALUES ($1, $2)
+		ON CONFLICT (item_key) DO UPDATE SET item_value = EXCLUDED.item_value`, table)
+}
+
+func (postgres) TxOptions() *sql.TxOptions {
+	return &sql.TxOptions{Isolation: sql.LevelSerializable}
+}
+
+func (postgres) Retryable(err error) bool {
+	return errs.IsFunc(err, func(err error) bool {
+		pqErr, ok := err.(*pq.Error)
+		// serialization_failure and deadlock_detected
+		return ok && (pqErr.Code == "40001" || pqErr.Code == "40P01")
+	})
+}
+
+type mysqlDialect struct{}
+
+func (mysqlDialect) Driver() string { return "mysql" }
+
+func (mysqlDialect) Placeholder(i int) string { return "?" }
+
+func (mysqlDialect) CreateTable(table string) string {
+	// 3072 bytes is the longest key InnoDB indexes
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		item_key VARBINARY(3072) NOT NULL PRIMARY KEY,
+		item_value LONGBLOB NOT NULL
+	)`, table)
+}
+
+func (mysqlDialect) Upsert(table string) string {
+	return fmt.Sprintf(`INSERT INTO %s (item_key, item_value) VALUES (?, ?)
+		ON DUPLICATE KEY UPDATE item_value = VALUES(item_value)`, table)
+}
+
+func (mysqlDialect) TxOptions() *sql.TxOptions {
+	return &sql.TxOptions{Isolation: sql.LevelSerializable}
+}
+
+func (mysqlDialect) Retryable(err error) bool {
+	return errs.IsFunc(err, func(err error) bool {
+		mysqlErr, ok := err.(*mysql.MySQLError)
+		// ER_LOCK_DEADLOCK and ER_LOCK_WAIT_TIMEOUT
+		return ok && (mysqlErr.Number == 1213 || mysqlErr.Number == 1205)
+	})
+}
+
+type sqlite struct{}
+
+func (sqlite) Driver() string { return "sqlite3" }
+
+func (sqlite) Placeholder(i int) string { return "?" }
+
+func (sqlite) CreateTable(table string) string {
+	return fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		item_key BLOB PRIMARY KEY,
+		item_value BLOB NOT NULL
+	)`, table)
+}
+
+func (sqlite) Upsert(table string) string {
+	return fmt.Sprintf(`INSERT OR REPLACE INTO %s (item_key, item_value) VALUES (?, ?)`, table)
+}
+
+// TxOptions returns nil, as the transactions of SQLite are serializable
+func (sqlite) TxOptions() *sql.TxOptions { return nil }
+
+func (sqlite) Retryable(err error) bool {
+	return errs.IsFunc(err, func(err error) bool {
+		sqliteErr, ok := err.(sqlite3.Error)
+		return ok && (sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked)
+	})
+}
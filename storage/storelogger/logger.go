@@ -84,6 +84,37 @@ func (store *Logger) Iterate(opts storage.IterateOptions, fn func(storage.Iterat
 	})
 }
 
+// Update calls fn with a transaction logging its operations
+func (store *Logger) Update(fn func(storage.Transaction) error) error {
+	store.log.Debug("Update")
+	err := store.store.Update(func(tx storage.Transaction) error {
+		return fn(&transaction{log: store.log, tx: tx})
+	})
+	store.log.Debug("Update done", zap.Error(err))
+	return err
+}
+
+// transaction logs the operations of a transaction
+type transaction struct {
+	log *zap.Logger
+	tx  storage.Transaction
+}
+
+func (tx *transaction) Get(key storage.Key) (storage.Value, error) {
+	tx.log.Debug("  Get", zap.String("key", string(key)))
+	return tx.tx.Get(key)
+}
+
+func (tx *transaction) Put(key storage.Key, value storage.Value) error {
+	tx.log.Debug("  Put", zap.String("key", string(key)), zap.Binary("value", []byte(value)))
+	return tx.tx.Put(key, value)
+}
+
+func (tx *transaction) Delete(key storage.Key) error {
+	tx.log.Debug("  Delete", zap.String("key", string(key)))
+	return tx.tx.Delete(key)
+}
+
 // Close closes the store
 func (store *Logger) Close() error {
 	store.log.Debug("Close")
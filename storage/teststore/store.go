@@ -27,6 +27,7 @@ type Client struct {
 		Delete      int
 		Close       int
 		Iterate     int
+		Update      int
 	}
 
 	version int
@@ -67,11 +68,17 @@ func (store *Client) Put(key storage.Key, value storage.Value) error {
 		return storage.ErrEmptyKey
 	}
 
+	store.put(key, value)
+	return nil
+}
+
+// put sets the value of key
+func (store *Client) put(key storage.Key, value storage.Value) {
 	keyIndex, found := store.indexOf(key)
 	if found {
 		kv := &store.Items[keyIndex]
 		kv.Value = storage.CloneValue(value)
-		return nil
+		return
 	}
 
 	store.Items = append(store.Items, storage.ListItem{})
@@ -80,8 +87,6 @@ func (store *Client) Put(key storage.Key, value storage.Value) error {
 		Key:   storage.CloneKey(key),
 		Value: storage.CloneValue(value),
 	}
-
-	return nil
 }
 
 // Get gets a value to store
@@ -132,14 +137,22 @@ func (store *Client) Delete(key storage.Key) error {
 		return errInternal
 	}
 
+	if !store.delete(key) {
+		return storage.ErrKeyNotFound.New(key.String())
+	}
+	return nil
+}
+
+// delete deletes key, and returns whether it was found
+func (store *Client) delete(key storage.Key) bool {
 	keyIndex, found := store.indexOf(key)
 	if !found {
-		return storage.ErrKeyNotFound.New(key.String())
+		return false
 	}
 
 	copy(store.Items[keyIndex:], store.Items[keyIndex+1:])
 	store.Items = store.Items[:len(store.Items)-1]
-	return nil
+	return true
 }
 
 // List lists all keys starting from start and upto limit items
@@ -160,6 +173,36 @@ func (store *Client) ReverseList(first storage.Key, limit int) (storage.Keys, er
 	return storage.ReverseListKeys(store, first, limit)
 }
 
+// Update calls fn with a transaction, whose writes are applied if fn returns
+// nil
+func (store *Client) Update(fn func(storage.Transaction) error) error {
+	store.CallCount.Update++
+	if store.forcedError() {
+		return errInternal
+	}
+
+	buffer := storage.NewWriteBuffer(func(key storage.Key) (storage.Value, error) {
+		keyIndex, found := store.indexOf(key)
+		if !found {
+			return nil, storage.ErrKeyNotFound.New(key.String())
+		}
+		return storage.CloneValue(store.Items[keyIndex].Value), nil
+	})
+	if err := fn(buffer); err != nil {
+		return err
+	}
+
+	store.version++
+	return buffer.Writes(func(key storage.Key, value storage.Value) error {
+		if value == nil {
+			store.delete(key)
+		} else {
+			store.put(key, value)
+		}
+		return nil
+	})
+}
+
 // Close closes the store
 func (store *Client) Close() error {
 	store.CallCount.Close++
@@ -19,9 +19,11 @@ func RunTests(t *testing.T, store storage.KeyValueStore) {
 	t.Run("Iterate", func(t *testing.T) { testIterate(t, store) })
 	t.Run("IterateAll", func(t *testing.T) { testIterateAll(t, store) })
 	t.Run("Prefix", func(t *testing.T) { testPrefix(t, store) })
+	t.Run("Update", func(t *testing.T) { testUpdate(t, store) })
 
 	t.Run("List", func(t *testing.T) { testList(t, store) })
 	t.Run("ListV2", func(t *testing.T) { testListV2(t, store) })
+	t.Run("Cursor", func(t *testing.T) { testCursor(t, store) })
 }
 
 func testConstraints(t *testing.T, store storage.KeyValueStore) {
@@ -0,0 +1,84 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite
+
+import (
+	"testing"
+
+	"storj.io/storj/storage"
+)
+
+func testCursor(t *testing.T, store storage.KeyValueStore) {
+	items := storage.Items{
+		newItem("cursor/a", "", false),
+		newItem("cursor/c", "", false),
+		newItem("cursor/e", "", false),
+		newItem("cursor/g", "", false),
+	}
+	defer cleanupItems(store, items)
+	if err := storage.PutAll(store, items...); err != nil {
+		t.Fatalf("failed to setup: %v", err)
+	}
+
+	listPage := func(opts storage.ListOptions, cursor string) (storage.Items, string) {
+		t.Helper()
+		opts, err := storage.ApplyCursor(opts, cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		page, more, err := storage.ListV2(store, opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return page, storage.NextCursor(opts, page, more)
+	}
+	keys := func(items storage.Items) (keys []string) {
+		for _, item := range items {
+			keys = append(keys, item.Key.String())
+		}
+		return keys
+	}
+
+	opts := storage.ListOptions{Prefix: storage.Key("cursor/"), Recursive: true, Limit: 2}
+	page, cursor := listPage(opts, "")
+	if got := keys(page); len(got) != 2 || got[0] != "a" || got[1] != "c" {
+		t.Fatalf("unexpected first page %v", got)
+	}
+	if cursor == "" {
+		t.Fatal("expected a cursor")
+	}
+
+	// changes before the cursor neither shift nor repeat the next page
+	if err := store.Delete(storage.Key("cursor/a")); err != nil {
+		t.Fatal(err)
+	}
+	inserted := newItem("cursor/b", "", false)
+	defer cleanupItems(store, storage.Items{inserted})
+	if err := store.Put(inserted.Key, inserted.Value); err != nil {
+		t.Fatal(err)
+	}
+
+	page, cursor = listPage(opts, cursor)
+	if got := keys(page); len(got) != 2 || got[0] != "e" || got[1] != "g" {
+		t.Fatalf("unexpected second page %v", got)
+	}
+	if cursor != "" {
+		t.Fatalf("expected the listing to be complete, got cursor %q", cursor)
+	}
+
+	// listing backwards continues before the first listed key
+	opts.EndBefore = storage.Key("g")
+	page, cursor = listPage(opts, "")
+	if got := keys(page); len(got) != 2 || got[0] != "c" || got[1] != "e" {
+		t.Fatalf("unexpected reverse page %v", got)
+	}
+	page, _ = listPage(opts, cursor)
+	if got := keys(page); len(got) != 1 || got[0] != "b" {
+		t.Fatalf("unexpected second reverse page %v", got)
+	}
+
+	if _, err := storage.ApplyCursor(opts, "not a cursor"); !storage.ErrCursor.Has(err) {
+		t.Fatalf("expected invalid cursor error, got %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package testsuite
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"storj.io/storj/storage"
+)
+
+func testUpdate(t *testing.T, store storage.KeyValueStore) {
+	items := storage.Items{
+		newItem("tx/a", "a", false),
+		newItem("tx/b", "b", false),
+		newItem("tx/c", "c", false),
+	}
+	defer cleanupItems(store, items)
+
+	if err := store.Put(items[0].Key, items[0].Value); err != nil {
+		t.Fatalf("failed to put %q: %v", items[0].Key, err)
+	}
+
+	t.Run("Commit", func(t *testing.T) {
+		err := store.Update(func(tx storage.Transaction) error {
+			value, err := tx.Get(items[0].Key)
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(value, items[0].Value) {
+				t.Errorf("invalid value for %q: got %v", items[0].Key, value)
+			}
+			if err := tx.Put(items[1].Key, items[1].Value); err != nil {
+				return err
+			}
+			if err := tx.Delete(items[0].Key); err != nil {
+				return err
+			}
+
+			// the transaction sees its own writes
+			value, err = tx.Get(items[1].Key)
+			if err != nil || !bytes.Equal(value, items[1].Value) {
+				t.Errorf("transaction doesn't see its put of %q: got %v, %v", items[1].Key, value, err)
+			}
+			if _, err := tx.Get(items[0].Key); !storage.ErrKeyNotFound.Has(err) {
+				t.Errorf("transaction doesn't see its delete of %q: got %v", items[0].Key, err)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("failed to update: %v", err)
+		}
+
+		if _, err := store.Get(items[0].Key); !storage.ErrKeyNotFound.Has(err) {
+			t.Fatalf("%q wasn't deleted: %v", items[0].Key, err)
+		}
+		value, err := store.Get(items[1].Key)
+		if err != nil || !bytes.Equal(value, items[1].Value) {
+			t.Fatalf("%q wasn't put: got %v, %v", items[1].Key, value, err)
+		}
+	})
+
+	t.Run("Rollback", func(t *testing.T) {
+		failure := errors.New("failure")
+		err := store.Update(func(tx storage.Transaction) error {
+			if err := tx.Put(items[2].Key, items[2].Value); err != nil {
+				return err
+			}
+			if err := tx.Delete(items[1].Key); err != nil {
+				return err
+			}
+			return failure
+		})
+		if err != failure {
+			t.Fatalf("expected the error of the transaction, got %v", err)
+		}
+
+		if _, err := store.Get(items[2].Key); !storage.ErrKeyNotFound.Has(err) {
+			t.Fatalf("%q of a failed transaction was put: %v", items[2].Key, err)
+		}
+		if _, err := store.Get(items[1].Key); err != nil {
+			t.Fatalf("%q of a failed transaction was deleted: %v", items[1].Key, err)
+		}
+	})
+
+	t.Run("Empty key", func(t *testing.T) {
+		err := store.Update(func(tx storage.Transaction) error {
+			return tx.Put(nil, storage.Value("x"))
+		})
+		if err == nil {
+			t.Fatal("putting empty key should fail")
+		}
+	})
+}
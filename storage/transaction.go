@@ -0,0 +1,92 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package storage
+
+// Transaction reads and writes the keys of a KeyValueStore atomically. Reads
+// see the puts and deletes made earlier in the transaction.
+type Transaction interface {
+	// Get gets the value of key
+	Get(Key) (Value, error)
+	// Put sets the value of key
+	Put(Key, Value) error
+	// Delete deletes key and its value
+	Delete(Key) error
+}
+
+// WriteBuffer is a Transaction buffering its puts and deletes until they are
+// committed, for stores without native transactions and for stores layered
+// over others.
+type WriteBuffer struct {
+	get    func(Key) (Value, error)
+	writes map[string]Value
+	keys   Keys
+}
+
+// NewWriteBuffer returns a WriteBuffer reading the keys it didn't write with
+// get
+func NewWriteBuffer(get func(Key) (Value, error)) *WriteBuffer {
+	return &WriteBuffer{get: get, writes: map[string]Value{}}
+}
+
+// Get gets the value of key
+func (buffer *WriteBuffer) Get(key Key) (Value, error) {
+	if value, ok := buffer.writes[string(key)]; ok {
+		if value == nil {
+			return nil, ErrKeyNotFound.New(key.String())
+		}
+		return CloneValue(value), nil
+	}
+	return buffer.get(key)
+}
+
+// Put sets the value of key
+func (buffer *WriteBuffer) Put(key Key, value Value) error {
+	if key.IsZero() {
+		return ErrEmptyKey
+	}
+	buffer.write(key, append(Value{}, value...))
+	return nil
+}
+
+// Delete deletes key and its value
+func (buffer *WriteBuffer) Delete(key Key) error {
+	if key.IsZero() {
+		return ErrEmptyKey
+	}
+	buffer.write(key, nil)
+	return nil
+}
+
+func (buffer *WriteBuffer) write(key Key, value Value) {
+	if _, ok := buffer.writes[string(key)]; !ok {
+		buffer.keys = append(buffer.keys, CloneKey(key))
+	}
+	buffer.writes[string(key)] = value
+}
+
+// Len returns the number of keys written
+func (buffer *WriteBuffer) Len() int {
+	return len(buffer.keys)
+}
+
+// Writes calls fn with the last value written to every key, in the order the
+// keys were first written, or nil if the key was deleted
+func (buffer *WriteBuffer) Writes(fn func(key Key, value Value) error) error {
+	for _, key := range buffer.keys {
+		if err := fn(key, buffer.writes[string(key)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply commits the writes of buffer to tx
+func (buffer *WriteBuffer) Apply(tx Transaction) error {
+	return buffer.Writes(func(key Key, value Value) error {
+		if value == nil {
+			return tx.Delete(key)
+		}
+		return tx.Put(key, value)
+	})
+}
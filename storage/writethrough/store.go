@@ -0,0 +1,234 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package writethrough
+
+import (
+	"encoding/binary"
+	"time"
+
+	"storj.io/storj/pkg/utils"
+	"storj.io/storj/storage"
+)
+
+// expirySize is the size of the expiration time the durable store keeps
+// before every value
+const expirySize = 8
+
+// Store layers a fast cache, like redis, over a durable store, like boltdb,
+// which is the source of truth. Writes go to the durable store before the
+// cache, and reads that miss the cache are served by the durable store.
+//
+// The cache may expire values, like redis with a TTL. The durable store keeps
+// the time values expire at with them, so that values expired in the cache
+// aren't served by the durable store. Reads don't fill the cache, as that
+// would extend the life of the values.
+type Store struct {
+	cache   storage.KeyValueStore
+	durable storage.KeyValueStore
+	ttl     time.Duration
+	now     func() time.Time
+}
+
+// New returns a store that caches durable in cache. Values expire ttl after
+// they were written, as they do in cache, or never if ttl is 0. If cache is
+// empty, as after a cold start, it is rebuilt from durable.
+func New(cache, durable storage.KeyValueStore, ttl time.Duration) (*Store, error) {
+	store := &Store{cache: cache, durable: durable, ttl: ttl, now: time.Now}
+
+	keys, err := cache.List(nil, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		if err := store.Rebuild(); err != nil {
+			return nil, err
+		}
+	}
+	return store, nil
+}
+
+// encode returns value as kept in the durable store, after the time it
+// expires at
+func (store *Store) encode(value storage.Value) storage.Value {
+	var expires int64
+	if store.ttl > 0 {
+		expires = store.now().Add(store.ttl).UnixNano()
+	}
+	encoded := make(storage.Value, expirySize+len(value))
+	binary.BigEndian.PutUint64(encoded, uint64(expires))
+	copy(encoded[expirySize:], value)
+	return encoded
+}
+
+// decode returns the value kept in the durable store as encoded, and whether
+// it hasn't expired
+func (store *Store) decode(encoded storage.Value) (value storage.Value, live bool) {
+	if len(encoded) < expirySize {
+		return nil, false
+	}
+	expires := int64(binary.BigEndian.Uint64(encoded))
+	if expires != 0 && expires <= store.now().UnixNano() {
+		return nil, false
+	}
+	return encoded[expirySize:], true
+}
+
+// getDurable gets the value of key from the durable store with get, unless it
+// expired
+func (store *Store) getDurable(get func(storage.Key) (storage.Value, error), key storage.Key) (storage.Value, error) {
+	encoded, err := get(key)
+	if err != nil {
+		return nil, err
+	}
+	value, live := store.decode(encoded)
+	if !live {
+		return nil, storage.ErrKeyNotFound.New(key.String())
+	}
+	return value, nil
+}
+
+// Rebuild copies the items of the durable store that haven't expired to the
+// cache. The cache may keep them longer than the durable store, up to its
+// TTL.
+func (store *Store) Rebuild() error {
+	return store.Iterate(storage.IterateOptions{Recurse: true}, func(it storage.Iterator) error {
+		var item storage.ListItem
+		for it.Next(&item) {
+			if err := store.cache.Put(item.Key, item.Value); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Put adds a value to store
+func (store *Store) Put(key storage.Key, value storage.Value) error {
+	if err := store.durable.Put(key, store.encode(value)); err != nil {
+		return err
+	}
+	return store.cache.Put(key, value)
+}
+
+// Get gets a value to store
+func (store *Store) Get(key storage.Key) (storage.Value, error) {
+	value, err := store.cache.Get(key)
+	if err == nil {
+		return value, nil
+	}
+	if !storage.ErrKeyNotFound.Has(err) {
+		return nil, err
+	}
+	return store.getDurable(store.durable.Get, key)
+}
+
+// GetAll gets all values from the store corresponding to keys
+func (store *Store) GetAll(keys storage.Keys) (storage.Values, error) {
+	values, err := store.cache.GetAll(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	var missing storage.Keys
+	var indexes []int
+	for i, value := range values {
+		if value == nil {
+			missing = append(missing, keys[i])
+			indexes = append(indexes, i)
+		}
+	}
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	durable, err := store.durable.GetAll(missing)
+	if err != nil {
+		return nil, err
+	}
+	for j, encoded := range durable {
+		if encoded == nil {
+			continue
+		}
+		if value, live := store.decode(encoded); live {
+			values[indexes[j]] = value
+		}
+	}
+	return values, nil
+}
+
+// Delete deletes key and the value
+func (store *Store) Delete(key storage.Key) error {
+	if err := store.durable.Delete(key); err != nil {
+		return err
+	}
+	return store.cache.Delete(key)
+}
+
+// Update calls fn with a transaction of the durable store, and applies its
+// writes to the cache once it is committed
+func (store *Store) Update(fn func(storage.Transaction) error) error {
+	var written *storage.WriteBuffer
+	err := store.durable.Update(func(tx storage.Transaction) error {
+		written = storage.NewWriteBuffer(func(key storage.Key) (storage.Value, error) {
+			return store.getDurable(tx.Get, key)
+		})
+		if err := fn(written); err != nil {
+			return err
+		}
+		return written.Writes(func(key storage.Key, value storage.Value) error {
+			if value == nil {
+				return tx.Delete(key)
+			}
+			return tx.Put(key, store.encode(value))
+		})
+	})
+	if err != nil {
+		return err
+	}
+	return written.Writes(func(key storage.Key, value storage.Value) error {
+		if value == nil {
+			err := store.cache.Delete(key)
+			if storage.ErrKeyNotFound.Has(err) {
+				return nil
+			}
+			return err
+		}
+		return store.cache.Put(key, value)
+	})
+}
+
+// List lists all keys starting from first and upto limit items. Listings
+// are served by the durable store, as the cache may have evicted keys.
+func (store *Store) List(first storage.Key, limit int) (storage.Keys, error) {
+	return storage.ListKeys(store, first, limit)
+}
+
+// ReverseList lists all keys in reverse order, starting from first
+func (store *Store) ReverseList(first storage.Key, limit int) (storage.Keys, error) {
+	return storage.ReverseListKeys(store, first, limit)
+}
+
+// Iterate iterates over the items of the durable store that haven't expired
+// based on opts
+func (store *Store) Iterate(opts storage.IterateOptions, fn func(storage.Iterator) error) error {
+	return store.durable.Iterate(opts, func(it storage.Iterator) error {
+		return fn(storage.IteratorFunc(func(item *storage.ListItem) bool {
+			for it.Next(item) {
+				if item.IsPrefix {
+					return true
+				}
+				if value, live := store.decode(item.Value); live {
+					item.Value = value
+					return true
+				}
+			}
+			return false
+		}))
+	})
+}
+
+// Close closes both stores
+func (store *Store) Close() error {
+	return utils.CombineErrors(store.cache.Close(), store.durable.Close())
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2018 Storj Labs, Inc.
+// See LICENSE for copying information.
+
+package writethrough
+
+import (
+	"testing"
+	"time"
+
+	"storj.io/storj/storage"
+	"storj.io/storj/storage/teststore"
+	"storj.io/storj/storage/testsuite"
+)
+
+func newStore(t testing.TB, cache, durable storage.KeyValueStore) *Store {
+	store, err := New(cache, durable, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func TestSuite(t *testing.T) {
+	testsuite.RunTests(t, newStore(t, teststore.New(), teststore.New()))
+}
+
+func BenchmarkSuite(b *testing.B) {
+	testsuite.RunBenchmarks(b, newStore(b, teststore.New(), teststore.New()))
+}
+
+func TestWriteThrough(t *testing.T) {
+	cache, durable := teststore.New(), teststore.New()
+	if err := durable.Put(storage.Key("persisted"), (&Store{}).encode(storage.Value("1"))); err != nil {
+		t.Fatal(err)
+	}
+
+	// a cold cache is rebuilt from the durable store
+	store := newStore(t, cache, durable)
+	if value, err := cache.Get(storage.Key("persisted")); err != nil || string(value) != "1" {
+		t.Fatalf("expected the cache to be rebuilt, got %q: %v", value, err)
+	}
+
+	if err := store.Put(storage.Key("written"), storage.Value("2")); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := store.getDurable(durable.Get, storage.Key("written")); err != nil || string(value) != "2" {
+		t.Fatalf("expected write-through, got %q: %v", value, err)
+	}
+
+	// reads that miss the cache, e.g. after eviction, are served by the
+	// durable store
+	if err := cache.Delete(storage.Key("written")); err != nil {
+		t.Fatal(err)
+	}
+	values, err := store.GetAll(storage.Keys{storage.Key("persisted"), storage.Key("written"), storage.Key("missing")})
+	if err != nil || len(values) != 3 || string(values[1]) != "2" || values[2] != nil {
+		t.Fatalf("unexpected values %q: %v", values, err)
+	}
+	if _, err := cache.Get(storage.Key("written")); !storage.ErrKeyNotFound.Has(err) {
+		t.Fatalf("expected the cache not to be filled, got %v", err)
+	}
+
+	if err := store.Delete(storage.Key("persisted")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(storage.Key("persisted")); !storage.ErrKeyNotFound.Has(err) {
+		t.Fatalf("expected key not found, got %v", err)
+	}
+
+	// a warm cache isn't rebuilt
+	if err := durable.Put(storage.Key("uncached"), (&Store{}).encode(storage.Value("3"))); err != nil {
+		t.Fatal(err)
+	}
+	newStore(t, cache, durable)
+	if _, err := cache.Get(storage.Key("uncached")); !storage.ErrKeyNotFound.Has(err) {
+		t.Fatalf("expected a warm cache to be kept, got %v", err)
+	}
+}
+
+func TestWriteThroughExpiry(t *testing.T) {
+	cache, durable := teststore.New(), teststore.New()
+	store, err := New(cache, durable, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	if err := store.Put(storage.Key("node"), storage.Value("1")); err != nil {
+		t.Fatal(err)
+	}
+	// the cache expired the value, e.g. redis with a TTL
+	now = now.Add(2 * time.Hour)
+	if err := cache.Delete(storage.Key("node")); err != nil {
+		t.Fatal(err)
+	}
+
+	// so the durable store doesn't serve it anymore
+	if _, err := store.Get(storage.Key("node")); !storage.ErrKeyNotFound.Has(err) {
+		t.Fatalf("expected key not found, got %v", err)
+	}
+	values, err := store.GetAll(storage.Keys{storage.Key("node")})
+	if err != nil || len(values) != 1 || values[0] != nil {
+		t.Fatalf("unexpected values %q: %v", values, err)
+	}
+	if keys, err := store.List(nil, 0); err != nil || len(keys) != 0 {
+		t.Fatalf("unexpected keys %q: %v", keys, err)
+	}
+	if _, err := cache.Get(storage.Key("node")); !storage.ErrKeyNotFound.Has(err) {
+		t.Fatalf("expected the expired value not to be cached again, got %v", err)
+	}
+
+	// nor rebuilds the cache with it
+	if err := store.Rebuild(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Get(storage.Key("node")); !storage.ErrKeyNotFound.Has(err) {
+		t.Fatalf("expected the expired value not to be rebuilt, got %v", err)
+	}
+
+	// writes renew the value
+	if err := store.Put(storage.Key("node"), storage.Value("2")); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Delete(storage.Key("node")); err != nil {
+		t.Fatal(err)
+	}
+	if value, err := store.Get(storage.Key("node")); err != nil || string(value) != "2" {
+		t.Fatalf("unexpected value %q: %v", value, err)
+	}
+}